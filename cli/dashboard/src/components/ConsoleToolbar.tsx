@@ -41,6 +41,8 @@ export interface ConsoleToolbarProps {
   onAutoScrollChange: (enabled: boolean) => void
   searchTerm: string
   onSearchChange: (term: string) => void
+  /** Searches the full log history for the term (Enter in the search box, local mode only) */
+  onSearchSubmit?: (term: string) => void
   onClearAll: () => void
   onOpenSettings: () => void
   onStartAll: () => void
@@ -77,6 +79,7 @@ export function ConsoleToolbar({
   onAutoScrollChange,
   searchTerm,
   onSearchChange,
+  onSearchSubmit,
   onClearAll,
   onOpenSettings,
   onStartAll,
@@ -184,7 +187,12 @@ export function ConsoleToolbar({
             type="text"
             value={searchTerm}
             onChange={(e) => onSearchChange(e.target.value)}
-            placeholder="Search logs..."
+            onKeyDown={(e) => {
+              if (e.key === 'Enter' && onSearchSubmit && logMode === 'local' && searchTerm.trim()) {
+                onSearchSubmit(searchTerm)
+              }
+            }}
+            placeholder={onSearchSubmit && logMode === 'local' ? 'Search logs... (Enter to search history)' : 'Search logs...'}
             className="w-full pl-9 pr-9 py-1.5 bg-white dark:bg-slate-800/50 border border-slate-300 dark:border-slate-700 rounded-md text-sm text-slate-800 dark:text-slate-200 placeholder:text-slate-400 dark:placeholder:text-slate-500 focus:outline-none focus:ring-2 focus:ring-cyan-500/50 focus:border-cyan-500/50"
           />
           {searchTerm && (
@@ -12,6 +12,7 @@ import { DiagnosticsModal } from './DiagnosticsModal'
 import { AzureSetupGuide } from './AzureSetupGuide'
 import { ConsoleToolbar, type TimeRangePreset } from './ConsoleToolbar'
 import { ConsoleFilters } from './ConsoleFilters'
+import { LogSearchPanel } from './LogSearchPanel'
 import { usePreferences } from '@/hooks/usePreferences'
 import { useToast } from '@/components/ui/toast'
 import type { SetupStep } from './AzureSetupGuide'
@@ -61,6 +62,7 @@ export function ConsoleView({
   const [isFullscreen, setIsFullscreen] = React.useState(false)
   const [isSettingsOpen, setIsSettingsOpen] = React.useState(false)
   const [globalSearchTerm, setGlobalSearchTerm] = React.useState('')
+  const [historySearchQuery, setHistorySearchQuery] = React.useState<string | null>(null)
   const [autoScrollEnabled, setAutoScrollEnabled] = React.useState(true)
   const [clearAllTrigger, setClearAllTrigger] = React.useState(0)
   const [collapsedPanes, setCollapsedPanes] = React.useState<Record<string, boolean>>({})
@@ -352,6 +354,7 @@ export function ConsoleView({
         onAutoScrollChange={setAutoScrollEnabled}
         searchTerm={globalSearchTerm}
         onSearchChange={setGlobalSearchTerm}
+        onSearchSubmit={setHistorySearchQuery}
         onClearAll={handleClearAll}
         onOpenSettings={() => setIsSettingsOpen(true)}
         onStartAll={() => void startAll()}
@@ -413,6 +416,14 @@ export function ConsoleView({
         initialStep={setupGuideInitialStep}
       />
 
+      {/* Log History Search */}
+      <LogSearchPanel
+        isOpen={historySearchQuery !== null}
+        onClose={() => setHistorySearchQuery(null)}
+        initialQuery={historySearchQuery ?? ''}
+        serviceNames={services.map((s) => s.name)}
+      />
+
       {/* Settings Dialog */}
       <SettingsDialog
         isOpen={isSettingsOpen}
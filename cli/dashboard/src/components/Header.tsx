@@ -16,6 +16,7 @@ import { ThemeToggle } from './ThemeToggle'
 import { HealthPill, ConnectionStatus } from './StatusIndicator'
 import { ServiceStatusCard } from './ServiceStatusCard'
 import { EnvironmentBadge } from './EnvironmentBadge'
+import { ProjectSwitcher } from './ProjectSwitcher'
 import type { HealthSummary, Service } from '@/types'
 
 // GitHub brand icon (removed from lucide-react v1.x)
@@ -211,26 +212,29 @@ export function Header({
       )}
     >
       {/* Brand Zone */}
-      <button
-        type="button"
-        onClick={() => onViewChange('console')}
-        className="flex items-center gap-3 min-w-0 hover:opacity-80 transition-opacity cursor-pointer"
-        aria-label="Go to Console"
-      >
-        <div className="w-7 h-7 rounded-lg bg-linear-to-br from-cyan-500 to-cyan-600 flex items-center justify-center shrink-0">
-          <Rocket className="w-4 h-4 text-white" />
-        </div>
-        <h1 className="text-lg font-semibold text-slate-900 dark:text-slate-100 truncate tracking-tight">
-          {projectName || 'Dashboard'}
-        </h1>
-        <EnvironmentBadge environmentName={environmentName} />
-        {connected && (
-          <span className="relative flex h-2 w-2" aria-hidden="true">
-            <span className="animate-ping absolute inline-flex h-full w-full rounded-full bg-emerald-400 opacity-75"></span>
-            <span className="relative inline-flex rounded-full h-2 w-2 bg-emerald-500"></span>
-          </span>
-        )}
-      </button>
+      <div className="flex items-center gap-1 min-w-0">
+        <button
+          type="button"
+          onClick={() => onViewChange('console')}
+          className="flex items-center gap-3 min-w-0 hover:opacity-80 transition-opacity cursor-pointer"
+          aria-label="Go to Console"
+        >
+          <div className="w-7 h-7 rounded-lg bg-linear-to-br from-cyan-500 to-cyan-600 flex items-center justify-center shrink-0">
+            <Rocket className="w-4 h-4 text-white" />
+          </div>
+          <h1 className="text-lg font-semibold text-slate-900 dark:text-slate-100 truncate tracking-tight">
+            {projectName || 'Dashboard'}
+          </h1>
+          <EnvironmentBadge environmentName={environmentName} />
+          {connected && (
+            <span className="relative flex h-2 w-2" aria-hidden="true">
+              <span className="animate-ping absolute inline-flex h-full w-full rounded-full bg-emerald-400 opacity-75"></span>
+              <span className="relative inline-flex rounded-full h-2 w-2 bg-emerald-500"></span>
+            </span>
+          )}
+        </button>
+        <ProjectSwitcher />
+      </div>
 
       {/* Navigation Zone */}
       <nav aria-label="Main navigation" className="hidden md:block">
@@ -0,0 +1,239 @@
+/**
+ * LogSearchPanel - Slide-in panel for searching the full local log history
+ * Searches the in-memory buffers and the persisted log files of every service
+ * with /api/logs/search and shows each match with the lines around it.
+ */
+import * as React from 'react'
+import { X, Search, Loader2, Inbox, CaseSensitive } from 'lucide-react'
+import { cn } from '@/lib/utils'
+import { useEscapeKey } from '@/hooks/useEscapeKey'
+import { useLogSearch } from '@/hooks/useLogSearch'
+import { formatLogTimestamp } from '@/lib/service-utils'
+import { getServiceColor } from '@/lib/log-utils'
+import type { LogSearchMatch } from '@/types'
+
+// =============================================================================
+// Types
+// =============================================================================
+
+export interface LogSearchPanelProps {
+  /** Whether panel is visible */
+  isOpen: boolean
+  /** Close panel callback */
+  onClose: () => void
+  /** Query to search for when the panel opens */
+  initialQuery: string
+  /** Services that can be searched */
+  serviceNames: string[]
+}
+
+// =============================================================================
+// Constants
+// =============================================================================
+
+const PANEL_WIDTH = 560
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+function getMatchColor(match: LogSearchMatch): string {
+  if (match.isStderr || match.level === 2) return 'text-red-500 dark:text-red-400'
+  if (match.level === 1) return 'text-yellow-600 dark:text-yellow-400'
+  return 'text-slate-800 dark:text-slate-200'
+}
+
+// =============================================================================
+// LogSearchPanel Component
+// =============================================================================
+
+export function LogSearchPanel({
+  isOpen,
+  onClose,
+  initialQuery,
+  serviceNames,
+}: Readonly<LogSearchPanelProps>) {
+  const panelRef = React.useRef<HTMLDivElement>(null)
+  const [query, setQuery] = React.useState(initialQuery)
+  const [service, setService] = React.useState('')
+  const [caseSensitive, setCaseSensitive] = React.useState(false)
+  const { matches, truncated, isLoading, error, search, clear } = useLogSearch()
+
+  // Close on Escape
+  useEscapeKey(onClose, isOpen)
+
+  // Search for the toolbar query when the panel opens
+  React.useEffect(() => {
+    if (!isOpen) return
+    setQuery(initialQuery)
+    clear()
+    if (initialQuery.trim()) {
+      void search({ query: initialQuery })
+    }
+  }, [isOpen, initialQuery, search, clear])
+
+  const runSearch = React.useCallback(() => {
+    if (query.trim()) {
+      void search({ query, service, caseSensitive })
+    }
+  }, [query, service, caseSensitive, search])
+
+  if (!isOpen) {
+    return null
+  }
+
+  return (
+    <>
+      {/* Backdrop */}
+      <div
+        className="fixed inset-0 z-40 bg-black/50 dark:bg-black/70 animate-fade-in"
+        onClick={onClose}
+        aria-hidden="true"
+      />
+
+      {/* Panel */}
+      <div
+        ref={panelRef}
+        role="dialog"
+        aria-modal="true"
+        aria-labelledby="log-search-panel-title"
+        style={{ width: PANEL_WIDTH }}
+        className={cn(
+          'fixed right-0 top-0 z-50 h-screen',
+          'bg-white dark:bg-slate-900',
+          'border-l border-slate-200 dark:border-slate-700',
+          'shadow-2xl',
+          'flex flex-col',
+          'animate-slide-in-right',
+        )}
+      >
+        {/* Header */}
+        <div className="flex items-center justify-between gap-3 p-4 border-b border-slate-200 dark:border-slate-700 shrink-0">
+          <div className="flex items-center gap-3 min-w-0">
+            <div className="w-9 h-9 rounded-lg flex items-center justify-center bg-cyan-100 dark:bg-cyan-500/20 shrink-0">
+              <Search className="w-5 h-5 text-cyan-600 dark:text-cyan-400" />
+            </div>
+            <div className="min-w-0">
+              <h2
+                id="log-search-panel-title"
+                className="text-lg font-semibold text-slate-900 dark:text-slate-100 truncate"
+              >
+                Search Log History
+              </h2>
+              <p className="text-sm text-slate-500 dark:text-slate-400 truncate">
+                Includes persisted logs of earlier runs
+              </p>
+            </div>
+          </div>
+          <button
+            type="button"
+            onClick={onClose}
+            className="p-2 rounded-lg text-slate-400 hover:text-slate-600 dark:hover:text-slate-200 hover:bg-slate-100 dark:hover:bg-slate-800 transition-colors"
+            aria-label="Close panel"
+          >
+            <X className="w-5 h-5" />
+          </button>
+        </div>
+
+        {/* Search form */}
+        <form
+          className="flex items-center gap-2 p-4 border-b border-slate-200 dark:border-slate-700 shrink-0"
+          onSubmit={(e) => {
+            e.preventDefault()
+            runSearch()
+          }}
+        >
+          <input
+            type="text"
+            value={query}
+            onChange={(e) => setQuery(e.target.value)}
+            placeholder="Regular expression, e.g. timeout|refused"
+            aria-label="Search pattern"
+            autoFocus
+            className="flex-1 min-w-0 px-3 py-1.5 bg-white dark:bg-slate-800/50 border border-slate-300 dark:border-slate-700 rounded-md text-sm font-mono text-slate-800 dark:text-slate-200 placeholder:text-slate-400 dark:placeholder:text-slate-500 focus:outline-none focus:ring-2 focus:ring-cyan-500/50"
+          />
+          <select
+            value={service}
+            onChange={(e) => setService(e.target.value)}
+            aria-label="Service"
+            className="px-2 py-1.5 bg-white dark:bg-slate-800/50 border border-slate-300 dark:border-slate-700 rounded-md text-sm text-slate-800 dark:text-slate-200"
+          >
+            <option value="">All services</option>
+            {serviceNames.map(name => (
+              <option key={name} value={name}>{name}</option>
+            ))}
+          </select>
+          <button
+            type="button"
+            onClick={() => setCaseSensitive(!caseSensitive)}
+            aria-pressed={caseSensitive}
+            title="Match case"
+            className={cn(
+              'p-1.5 rounded-md border transition-colors',
+              caseSensitive
+                ? 'bg-cyan-500/20 text-cyan-600 dark:text-cyan-400 border-cyan-500/30'
+                : 'text-slate-500 border-transparent hover:bg-slate-100 dark:hover:bg-slate-800'
+            )}
+          >
+            <CaseSensitive className="w-4 h-4" />
+          </button>
+          <button
+            type="submit"
+            disabled={!query.trim() || isLoading}
+            className="px-3 py-1.5 rounded-md text-sm font-medium bg-cyan-600 text-white hover:bg-cyan-700 disabled:opacity-50 disabled:cursor-not-allowed transition-colors"
+          >
+            Search
+          </button>
+        </form>
+
+        {/* Results */}
+        <div className="flex-1 overflow-y-auto p-4 space-y-3 font-mono text-xs">
+          {isLoading && (
+            <div className="flex items-center justify-center gap-2 py-8 text-slate-500">
+              <Loader2 className="w-4 h-4 animate-spin" />
+              Searching...
+            </div>
+          )}
+
+          {!isLoading && error && (
+            <p className="text-sm font-sans text-red-600 dark:text-red-400">{error}</p>
+          )}
+
+          {!isLoading && !error && matches.length === 0 && (
+            <div className="flex flex-col items-center justify-center gap-2 py-8 text-slate-500 font-sans">
+              <Inbox className="w-8 h-8" />
+              <p className="text-sm">{query.trim() ? 'No matching log lines' : 'Enter a pattern to search'}</p>
+            </div>
+          )}
+
+          {!isLoading && matches.map((match, index) => (
+            <div
+              key={`${match.service}-${match.timestamp}-${index}`}
+              className="rounded-md border border-slate-200 dark:border-slate-700 bg-slate-50 dark:bg-slate-800/50 p-2"
+            >
+              <div className="flex items-center gap-2 mb-1 text-slate-500 dark:text-slate-400">
+                <span>{formatLogTimestamp(match.timestamp)}</span>
+                <span className={getServiceColor(match.service)}>[{match.service}]</span>
+              </div>
+              {match.context?.before?.map((line, i) => (
+                <div key={`before-${i}`} className="text-slate-400 dark:text-slate-500 whitespace-pre-wrap break-all">{line}</div>
+              ))}
+              <div className={cn('whitespace-pre-wrap break-all font-semibold', getMatchColor(match))}>
+                {match.message}
+              </div>
+              {match.context?.after?.map((line, i) => (
+                <div key={`after-${i}`} className="text-slate-400 dark:text-slate-500 whitespace-pre-wrap break-all">{line}</div>
+              ))}
+            </div>
+          ))}
+
+          {!isLoading && truncated && (
+            <p className="text-center text-sm font-sans text-slate-500">
+              Showing the {matches.length} most recent matches. Refine the pattern to see older ones.
+            </p>
+          )}
+        </div>
+      </div>
+    </>
+  )
+}
@@ -0,0 +1,139 @@
+/**
+ * ProjectSwitcher - Switches the tab between the dashboards of all projects
+ * with an active azd app run session. Hidden when only this project is running.
+ */
+import * as React from 'react'
+import { Check, ChevronDown, FolderOpen } from 'lucide-react'
+import { cn } from '@/lib/utils'
+import { transformLocalhostUrl } from '@/lib/codespace-utils'
+import { useCodespaceEnv } from '@/hooks/useCodespaceEnv'
+import { useEscapeKey } from '@/hooks/useEscapeKey'
+import { useProjects } from '@/hooks/useProjects'
+
+// =============================================================================
+// Types
+// =============================================================================
+
+export interface ProjectSwitcherProps {
+  /** Additional class names */
+  className?: string
+}
+
+// =============================================================================
+// ProjectSwitcher Component
+// =============================================================================
+
+export function ProjectSwitcher({ className }: ProjectSwitcherProps) {
+  const projects = useProjects()
+  const { config } = useCodespaceEnv()
+  const [isOpen, setIsOpen] = React.useState(false)
+  const menuRef = React.useRef<HTMLDivElement>(null)
+
+  const close = React.useCallback(() => setIsOpen(false), [])
+  useEscapeKey(close, isOpen)
+
+  // Close on click outside
+  React.useEffect(() => {
+    const handleClickOutside = (event: MouseEvent) => {
+      if (menuRef.current && !menuRef.current.contains(event.target as Node)) {
+        setIsOpen(false)
+      }
+    }
+    if (isOpen) {
+      document.addEventListener('mousedown', handleClickOutside)
+    }
+    return () => {
+      document.removeEventListener('mousedown', handleClickOutside)
+    }
+  }, [isOpen])
+
+  if (projects.length < 2) {
+    return null
+  }
+
+  return (
+    <div ref={menuRef} className={cn('relative hidden sm:block', className)}>
+      <button
+        type="button"
+        onClick={() => setIsOpen(!isOpen)}
+        className={cn(
+          'flex items-center gap-1.5 px-2 py-1 rounded-md text-xs font-medium',
+          'text-slate-500 dark:text-slate-400',
+          'hover:bg-slate-100 dark:hover:bg-slate-800',
+          'transition-colors duration-200',
+          'focus:outline-none focus-visible:ring-2 focus-visible:ring-cyan-500',
+        )}
+        aria-expanded={isOpen}
+        aria-haspopup="menu"
+        aria-label={`Switch project (${projects.length} running)`}
+      >
+        <FolderOpen className="w-3.5 h-3.5" />
+        {projects.length}
+        <ChevronDown className={cn('w-3 h-3 transition-transform', isOpen && 'rotate-180')} />
+      </button>
+
+      {isOpen && (
+        <div
+          className={cn(
+            'absolute left-0 top-full mt-1 z-50',
+            'w-72 rounded-md shadow-lg',
+            'bg-white dark:bg-slate-800',
+            'border border-slate-200 dark:border-slate-700',
+            'py-1',
+          )}
+          role="menu"
+        >
+          {projects.map(project => {
+            const content = (
+              <>
+                <Check className={cn('w-4 h-4 shrink-0 mt-0.5 text-cyan-600 dark:text-cyan-400', !project.current && 'invisible')} />
+                <span className="min-w-0">
+                  <span className="block font-medium truncate">{project.name}</span>
+                  <span className="block text-xs text-slate-500 dark:text-slate-400 truncate" title={project.dir}>
+                    {project.dir}
+                  </span>
+                  <span className="block text-xs text-slate-400 dark:text-slate-500">
+                    {project.services.length} {project.services.length === 1 ? 'service' : 'services'}
+                    {project.detached && ' · background'}
+                  </span>
+                </span>
+              </>
+            )
+            const itemClassName = cn(
+              'w-full flex items-start gap-2 px-3 py-2 text-left text-sm',
+              'text-slate-700 dark:text-slate-200',
+            )
+
+            if (project.current || !project.dashboardUrl) {
+              return (
+                <div
+                  key={project.dir}
+                  role="menuitem"
+                  aria-current={project.current ? 'page' : undefined}
+                  aria-disabled={!project.current}
+                  className={cn(itemClassName, !project.current && 'opacity-50')}
+                >
+                  {content}
+                </div>
+              )
+            }
+            return (
+              <a
+                key={project.dir}
+                href={transformLocalhostUrl(project.dashboardUrl, config)}
+                role="menuitem"
+                className={cn(
+                  itemClassName,
+                  'hover:bg-slate-100 dark:hover:bg-slate-700',
+                  'focus:outline-none focus:bg-slate-100 dark:focus:bg-slate-700',
+                )}
+              >
+                {content}
+              </a>
+            )
+          })}
+        </div>
+      )}
+    </div>
+  )
+}
@@ -0,0 +1,94 @@
+/**
+ * ResourceMetrics - Live CPU and memory graphs for a locally running service
+ */
+import type { ReactNode } from 'react'
+import { useServiceMetrics } from '@/hooks/useServiceMetrics'
+import { formatBytes, formatCpuPercent, formatUptime } from '@/lib/service-utils'
+
+const SPARKLINE_WIDTH = 240
+const SPARKLINE_HEIGHT = 40
+
+interface SparklineProps {
+  values: number[]
+  /** Lower bound of the y-axis maximum, so small fluctuations don't fill the graph */
+  minMax: number
+  className: string
+  label: string
+}
+
+function Sparkline({ values, minMax, className, label }: SparklineProps) {
+  if (values.length < 2) {
+    return <div className="h-10 text-xs text-slate-400 dark:text-slate-500 flex items-center">Collecting samples…</div>
+  }
+
+  const max = Math.max(minMax, ...values)
+  const step = SPARKLINE_WIDTH / (values.length - 1)
+  const points = values
+    .map((value, i) => `${(i * step).toFixed(1)},${(SPARKLINE_HEIGHT - (value / max) * SPARKLINE_HEIGHT).toFixed(1)}`)
+    .join(' ')
+
+  return (
+    <svg
+      role="img"
+      aria-label={label}
+      viewBox={`0 0 ${SPARKLINE_WIDTH} ${SPARKLINE_HEIGHT}`}
+      preserveAspectRatio="none"
+      className="w-full h-10"
+    >
+      <polyline points={points} fill="none" strokeWidth="1.5" vectorEffect="non-scaling-stroke" className={className} />
+    </svg>
+  )
+}
+
+interface MetricRowProps {
+  label: string
+  value: string
+  children?: ReactNode
+}
+
+function MetricRow({ label, value, children }: MetricRowProps) {
+  return (
+    <div className="py-2 border-b border-slate-200 dark:border-slate-700 last:border-b-0">
+      <div className="flex justify-between items-center">
+        <span className="text-sm text-slate-500 dark:text-slate-400">{label}</span>
+        <span className="text-sm font-medium text-slate-900 dark:text-slate-100 tabular-nums">{value}</span>
+      </div>
+      {children}
+    </div>
+  )
+}
+
+export interface ResourceMetricsProps {
+  serviceName: string
+}
+
+export function ResourceMetrics({ serviceName }: ResourceMetricsProps) {
+  const { latest, history } = useServiceMetrics(serviceName)
+
+  if (!latest) {
+    return <p className="text-sm text-slate-500 dark:text-slate-400">No resource usage available.</p>
+  }
+
+  return (
+    <div className="space-y-0">
+      <MetricRow label="CPU" value={formatCpuPercent(latest.cpuPercent)}>
+        <Sparkline
+          values={history.map(p => p.cpuPercent)}
+          minMax={10}
+          label={`CPU usage of ${serviceName}`}
+          className="stroke-cyan-500 dark:stroke-cyan-400"
+        />
+      </MetricRow>
+      <MetricRow label="Memory" value={formatBytes(latest.memoryBytes)}>
+        <Sparkline
+          values={history.map(p => p.memoryBytes)}
+          minMax={64 * 1024 * 1024}
+          label={`Memory usage of ${serviceName}`}
+          className="stroke-purple-500 dark:stroke-purple-400"
+        />
+      </MetricRow>
+      <MetricRow label="Processes" value={String(latest.processes)} />
+      <MetricRow label="Uptime" value={formatUptime(latest.uptimeSeconds * 1_000_000_000)} />
+    </div>
+  )
+}
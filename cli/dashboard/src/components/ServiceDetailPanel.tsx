@@ -22,6 +22,7 @@ import { useClipboard } from '@/hooks/useClipboard'
 import { useServiceUrls } from '@/hooks/useServiceUrls'
 import { StatusBadge, type EffectiveStatus } from './StatusIndicator'
 import { ServiceActions } from '@/components/ServiceActions'
+import { ResourceMetrics } from '@/components/ResourceMetrics'
 import { useServiceOperations, type OperationState } from '@/hooks/useServiceOperations'
 import type { Service, HealthCheckResult } from '@/types'
 import { 
@@ -427,6 +428,13 @@ function LocalTab({ service, healthStatus, copiedField, onCopy, operationState }
         </div>
       </SectionCard>
 
+      {/* Resources - live CPU and memory of the service's processes */}
+      {service.local?.pid ? (
+        <SectionCard title="Resources">
+          <ResourceMetrics serviceName={service.name} />
+        </SectionCard>
+      ) : null}
+
       {/* URLs Section - Show all available URLs */}
       {(service.local?.url || service.local?.customUrl) && (
         <SectionCard title="URLs">
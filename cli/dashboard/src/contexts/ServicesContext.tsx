@@ -1,7 +1,11 @@
 import { createContext, useContext, useState, useEffect, useCallback, useMemo, type ReactNode } from 'react'
 import type { Service } from '@/types'
+import { WEBSOCKET_CONSTANTS } from '@/lib/constants'
 
 const API_BASE = ''
+const WS_INITIAL_RETRY_DELAY_MS = WEBSOCKET_CONSTANTS.WS_INITIAL_RETRY_DELAY_MS
+const WS_MAX_RETRY_DELAY_MS = WEBSOCKET_CONSTANTS.WS_MAX_RETRY_DELAY_MS
+const WS_MAX_RETRIES = WEBSOCKET_CONSTANTS.WS_MAX_RETRIES
 
 // Mock data for development when backend isn't running
 const MOCK_SERVICES: Service[] = [
@@ -57,6 +61,21 @@ interface ServicesContextValue {
   getService: (name: string) => Service | undefined
 }
 
+/**
+ * Message received on /api/ws. "services" messages carry a sequence number, and the first one
+ * also carries the reconnect token. "service-route" messages report that the reverse proxy now
+ * reaches a service on another backend port.
+ */
+interface ServicesMessage {
+  type: string
+  service?: Service | string
+  services?: Service[]
+  token?: string
+  seq?: number
+  publicUrl?: string
+  backendPort?: number
+}
+
 const ServicesContext = createContext<ServicesContextValue | null>(null)
 
 interface ServicesProviderProps {
@@ -95,64 +114,105 @@ export function ServicesProvider({ children }: ServicesProviderProps) {
   useEffect(() => {
     void fetchServices()
 
-    // Set up WebSocket connection
-    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:'
-    const ws = new WebSocket(`${protocol}//${window.location.host}/api/ws`)
     let isMounted = true
+    let ws: WebSocket | null = null
+    let retryCount = 0
+    let retryTimeout: ReturnType<typeof setTimeout> | null = null
+    // Reconnect token and last sequence number from the server; sent when reconnecting so
+    // the server replays the service updates missed while disconnected
+    let token: string | null = null
+    let lastSeq = 0
 
-    ws.onopen = () => {
-      if (isMounted) {
-        setConnected(true)
+    // Reconnect with exponential backoff: 1s, 2s, 4s, ... up to 30s
+    const scheduleReconnect = () => {
+      if (retryCount >= WS_MAX_RETRIES) {
+        console.error(`WebSocket: Max retries (${WS_MAX_RETRIES}) exceeded, giving up`)
+        return
       }
+      const delay = Math.min(WS_INITIAL_RETRY_DELAY_MS * Math.pow(2, retryCount), WS_MAX_RETRY_DELAY_MS)
+      retryCount++
+      retryTimeout = setTimeout(() => {
+        if (isMounted) connect()
+      }, delay)
     }
 
-    ws.onmessage = (event: MessageEvent<string>) => {
-      if (!isMounted) return
-      try {
-        const update = JSON.parse(event.data) as { type: string; service?: Service; services?: Service[] }
-        if (update.type === 'services' && update.services) {
-          // Bulk update: replace all services
-          setServices(update.services)
-        } else if ((update.type === 'update' || update.type === 'add') && update.service) {
-          setServices(prev => {
-            const index = prev.findIndex(
-              s => s.name === update.service!.name
-            )
-            if (index >= 0) {
-              const updated = [...prev]
-              updated[index] = update.service!
-              return updated
-            }
-            return [...prev, update.service!]
-          })
-        } else if (update.type === 'remove' && update.service) {
-          setServices(prev =>
-            prev.filter(
-              s => s.name !== update.service!.name
+    // Set up WebSocket connection
+    const connect = () => {
+      const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:'
+      const resume = token ? `?token=${encodeURIComponent(token)}&since=${lastSeq}` : ''
+      const socket = new WebSocket(`${protocol}//${window.location.host}/api/ws${resume}`)
+      ws = socket
+
+      socket.onopen = () => {
+        if (isMounted) {
+          setConnected(true)
+          retryCount = 0
+        }
+      }
+
+      socket.onmessage = (event: MessageEvent<string>) => {
+        if (!isMounted) return
+        try {
+          const update = JSON.parse(event.data) as ServicesMessage
+          if (update.token) token = update.token
+          if (update.type === 'services' && typeof update.seq === 'number') lastSeq = update.seq
+          if (update.type === 'services' && update.services) {
+            // Bulk update: replace all services
+            setServices(update.services)
+          } else if (update.type === 'service-route' && typeof update.service === 'string' && typeof update.backendPort === 'number') {
+            // The service moved to another port; the public URL through the proxy is unchanged
+            const name = update.service
+            const port = update.backendPort
+            setServices(prev => prev.map(s =>
+              s.name === name && s.local ? { ...s, local: { ...s.local, port } } : s
+            ))
+          } else if ((update.type === 'update' || update.type === 'add') && typeof update.service === 'object') {
+            const service = update.service
+            setServices(prev => {
+              const index = prev.findIndex(
+                s => s.name === service.name
+              )
+              if (index >= 0) {
+                const updated = [...prev]
+                updated[index] = service
+                return updated
+              }
+              return [...prev, service]
+            })
+          } else if (update.type === 'remove' && typeof update.service === 'object') {
+            const service = update.service
+            setServices(prev =>
+              prev.filter(
+                s => s.name !== service.name
+              )
             )
-          )
+          }
+        } catch (err) {
+          console.error('Failed to parse WebSocket message:', err)
         }
-      } catch (err) {
-        console.error('Failed to parse WebSocket message:', err)
       }
-    }
 
-    ws.onerror = () => {
-      if (isMounted) {
-        setConnected(false)
-        console.warn('WebSocket not available (this is normal in dev mode)')
+      socket.onerror = () => {
+        if (isMounted) {
+          setConnected(false)
+          console.warn('WebSocket not available (this is normal in dev mode)')
+        }
       }
-    }
 
-    ws.onclose = () => {
-      if (isMounted) {
-        setConnected(false)
+      socket.onclose = () => {
+        if (isMounted) {
+          setConnected(false)
+          scheduleReconnect()
+        }
       }
     }
 
+    connect()
+
     return () => {
       isMounted = false
-      if (ws.readyState === WebSocket.OPEN || ws.readyState === WebSocket.CONNECTING) {
+      if (retryTimeout) clearTimeout(retryTimeout)
+      if (ws && (ws.readyState === WebSocket.OPEN || ws.readyState === WebSocket.CONNECTING)) {
         ws.close(1000, 'Component unmounting')
       }
     }
@@ -0,0 +1,77 @@
+/**
+ * Tests for useLogSearch hook
+ * Validates the search request and error handling
+ */
+import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest'
+import { renderHook, act } from '@testing-library/react'
+import { useLogSearch } from './useLogSearch'
+
+describe('useLogSearch', () => {
+  let originalFetch: typeof globalThis.fetch
+
+  beforeEach(() => {
+    originalFetch = globalThis.fetch
+  })
+
+  afterEach(() => {
+    vi.restoreAllMocks()
+    globalThis.fetch = originalFetch
+  })
+
+  it('should request /api/logs/search with the search params', async () => {
+    const fetchMock = vi.fn().mockResolvedValue({
+      ok: true,
+      json: () => Promise.resolve({
+        query: 'refused',
+        matches: [{ service: 'api', message: 'connection refused', level: 2, timestamp: '2024-01-15T10:30:45Z', isStderr: true }],
+        truncated: true,
+      }),
+    })
+    globalThis.fetch = fetchMock as unknown as typeof globalThis.fetch
+
+    const { result } = renderHook(() => useLogSearch())
+    await act(async () => {
+      await result.current.search({ query: 'refused', service: 'api', caseSensitive: true })
+    })
+
+    expect(fetchMock).toHaveBeenCalledWith('/api/logs/search?q=refused&service=api&caseSensitive=true')
+    expect(result.current.matches).toHaveLength(1)
+    expect(result.current.matches[0].message).toBe('connection refused')
+    expect(result.current.truncated).toBe(true)
+    expect(result.current.error).toBeNull()
+    expect(result.current.isLoading).toBe(false)
+  })
+
+  it('should report the server error of an invalid pattern', async () => {
+    globalThis.fetch = vi.fn().mockResolvedValue({
+      ok: false,
+      status: 400,
+      json: () => Promise.resolve({ error: 'Invalid search pattern: missing closing )' }),
+    }) as unknown as typeof globalThis.fetch
+
+    const { result } = renderHook(() => useLogSearch())
+    await act(async () => {
+      await result.current.search({ query: '(unclosed' })
+    })
+
+    expect(result.current.matches).toHaveLength(0)
+    expect(result.current.error).toBe('Invalid search pattern: missing closing )')
+  })
+
+  it('should clear results', async () => {
+    globalThis.fetch = vi.fn().mockResolvedValue({
+      ok: true,
+      json: () => Promise.resolve({ query: 'x', matches: [{ service: 'api', message: 'x', level: 0, timestamp: '', isStderr: false }], truncated: false }),
+    }) as unknown as typeof globalThis.fetch
+
+    const { result } = renderHook(() => useLogSearch())
+    await act(async () => {
+      await result.current.search({ query: 'x' })
+    })
+    act(() => {
+      result.current.clear()
+    })
+
+    expect(result.current.matches).toHaveLength(0)
+  })
+})
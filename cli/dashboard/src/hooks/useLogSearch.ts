@@ -0,0 +1,78 @@
+import { useCallback, useRef, useState } from 'react'
+import type { LogSearchMatch, LogSearchResponse } from '@/types'
+
+const API_BASE = ''
+
+/** Options of a log search */
+export interface LogSearchParams {
+  /** Regular expression matched against log messages */
+  query: string
+  /** Only search this service; all services when empty */
+  service?: string
+  /** Comma-separated levels, e.g. "warn,error" */
+  level?: string
+  caseSensitive?: boolean
+}
+
+/** Return type for the log search hook */
+export interface UseLogSearchReturn {
+  matches: LogSearchMatch[]
+  truncated: boolean
+  isLoading: boolean
+  error: string | null
+  search: (params: LogSearchParams) => Promise<void>
+  clear: () => void
+}
+
+/**
+ * Hook for searching the in-memory and persisted local logs with /api/logs/search.
+ * Only the result of the latest search is kept.
+ */
+export function useLogSearch(): UseLogSearchReturn {
+  const [matches, setMatches] = useState<LogSearchMatch[]>([])
+  const [truncated, setTruncated] = useState(false)
+  const [isLoading, setIsLoading] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+  const requestIdRef = useRef(0)
+
+  const search = useCallback(async ({ query, service, level, caseSensitive }: LogSearchParams) => {
+    const requestId = ++requestIdRef.current
+    const params = new URLSearchParams({ q: query })
+    if (service) params.set('service', service)
+    if (level) params.set('level', level)
+    if (caseSensitive) params.set('caseSensitive', 'true')
+
+    setIsLoading(true)
+    setError(null)
+    try {
+      const response = await fetch(`${API_BASE}/api/logs/search?${params.toString()}`)
+      if (!response.ok) {
+        const body = await response.json().catch(() => null) as { error?: string } | null
+        throw new Error(body?.error ?? `Search failed (HTTP ${response.status})`)
+      }
+      const data = await response.json() as LogSearchResponse
+      if (requestId !== requestIdRef.current) return
+      setMatches(data.matches ?? [])
+      setTruncated(data.truncated)
+    } catch (err) {
+      if (requestId !== requestIdRef.current) return
+      setMatches([])
+      setTruncated(false)
+      setError(err instanceof Error ? err.message : 'Search failed')
+    } finally {
+      if (requestId === requestIdRef.current) {
+        setIsLoading(false)
+      }
+    }
+  }, [])
+
+  const clear = useCallback(() => {
+    requestIdRef.current++
+    setMatches([])
+    setTruncated(false)
+    setError(null)
+    setIsLoading(false)
+  }, [])
+
+  return { matches, truncated, isLoading, error, search, clear }
+}
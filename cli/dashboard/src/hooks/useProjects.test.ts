@@ -0,0 +1,50 @@
+/**
+ * Tests for useProjects hook
+ * Validates loading the active projects and failure handling
+ */
+import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest'
+import { renderHook, waitFor } from '@testing-library/react'
+import { useProjects } from './useProjects'
+
+describe('useProjects', () => {
+  let originalFetch: typeof globalThis.fetch
+
+  beforeEach(() => {
+    originalFetch = globalThis.fetch
+  })
+
+  afterEach(() => {
+    vi.restoreAllMocks()
+    globalThis.fetch = originalFetch
+  })
+
+  it('should load the projects from /api/v1/projects', async () => {
+    const fetchMock = vi.fn().mockResolvedValue({
+      ok: true,
+      json: () => Promise.resolve({
+        projects: [
+          { name: 'shop', dir: '/src/shop', dashboardUrl: 'http://localhost:41001', services: ['api', 'web'], detached: false, current: true },
+          { name: 'blog', dir: '/src/blog', dashboardUrl: 'http://localhost:41002', services: ['web'], detached: true, current: false },
+        ],
+      }),
+    })
+    globalThis.fetch = fetchMock as unknown as typeof globalThis.fetch
+
+    const { result } = renderHook(() => useProjects())
+
+    await waitFor(() => expect(result.current).toHaveLength(2))
+    expect(fetchMock).toHaveBeenCalledWith('/api/v1/projects')
+    expect(result.current[0].current).toBe(true)
+    expect(result.current[1].dashboardUrl).toBe('http://localhost:41002')
+  })
+
+  it('should return no projects when the request fails', async () => {
+    const fetchMock = vi.fn().mockRejectedValue(new Error('network error'))
+    globalThis.fetch = fetchMock as unknown as typeof globalThis.fetch
+
+    const { result } = renderHook(() => useProjects())
+
+    await waitFor(() => expect(fetchMock).toHaveBeenCalled())
+    expect(result.current).toHaveLength(0)
+  })
+})
@@ -0,0 +1,41 @@
+import { useEffect, useState } from 'react'
+import type { ActiveProject, ProjectsResponse } from '@/types'
+
+const API_BASE = ''
+/** Sessions start and stop in other terminals, so the list is refreshed periodically */
+const REFRESH_INTERVAL_MS = 15_000
+
+/**
+ * Hook for the projects with an active azd app run session, from /api/v1/projects.
+ * Returns an empty list until loaded, or if the backend isn't available.
+ */
+export function useProjects(): ActiveProject[] {
+  const [projects, setProjects] = useState<ActiveProject[]>([])
+
+  useEffect(() => {
+    let isMounted = true
+
+    const fetchProjects = async () => {
+      try {
+        const response = await fetch(`${API_BASE}/api/v1/projects`)
+        if (!response.ok) return
+        const data = await response.json() as ProjectsResponse
+        if (isMounted) {
+          setProjects(data.projects ?? [])
+        }
+      } catch {
+        // Backend not available (dev mode); the switcher stays hidden
+      }
+    }
+
+    void fetchProjects()
+    const interval = setInterval(() => { void fetchProjects() }, REFRESH_INTERVAL_MS)
+
+    return () => {
+      isMounted = false
+      clearInterval(interval)
+    }
+  }, [])
+
+  return projects
+}
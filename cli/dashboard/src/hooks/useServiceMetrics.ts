@@ -0,0 +1,72 @@
+import { useEffect, useState } from 'react'
+import type { MetricsPoint, MetricsResponse, ServiceMetrics } from '@/types'
+
+const API_BASE = ''
+/** Matches metricsHistorySize on the server (5 minutes at a 2 second interval) */
+const MAX_HISTORY_POINTS = 150
+
+/** Return type for the service metrics hook */
+export interface UseServiceMetricsReturn {
+  /** Latest sample, or null if the service isn't running locally */
+  latest: ServiceMetrics | null
+  /** Recent samples, oldest first */
+  history: MetricsPoint[]
+}
+
+/**
+ * Hook for the live CPU and memory usage of a service.
+ * Loads the recent history from /api/metrics, then appends the samples
+ * broadcast as "metrics" messages on /api/ws.
+ */
+export function useServiceMetrics(serviceName: string): UseServiceMetricsReturn {
+  const [latest, setLatest] = useState<ServiceMetrics | null>(null)
+  const [history, setHistory] = useState<MetricsPoint[]>([])
+
+  useEffect(() => {
+    let isMounted = true
+
+    void (async () => {
+      try {
+        const response = await fetch(`${API_BASE}/api/metrics`)
+        if (!response.ok) return
+        const data = await response.json() as MetricsResponse
+        if (!isMounted) return
+        setLatest(data.services.find(m => m.service === serviceName) ?? null)
+        setHistory(data.history[serviceName] ?? [])
+      } catch {
+        // Backend not available (dev mode); the panel stays empty
+      }
+    })()
+
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:'
+    const ws = new WebSocket(`${protocol}//${window.location.host}/api/ws`)
+    ws.onmessage = (event: MessageEvent<string>) => {
+      if (!isMounted) return
+      try {
+        const message = JSON.parse(event.data) as { type: string; metrics?: ServiceMetrics[] }
+        if (message.type !== 'metrics' || !message.metrics) return
+        const sample = message.metrics.find(m => m.service === serviceName) ?? null
+        setLatest(sample)
+        if (!sample) {
+          setHistory([])
+          return
+        }
+        setHistory(prev => [
+          ...prev,
+          { timestamp: sample.timestamp, cpuPercent: sample.cpuPercent, memoryBytes: sample.memoryBytes },
+        ].slice(-MAX_HISTORY_POINTS))
+      } catch (err) {
+        console.error('Failed to parse metrics message:', err)
+      }
+    }
+
+    return () => {
+      isMounted = false
+      if (ws.readyState === WebSocket.OPEN || ws.readyState === WebSocket.CONNECTING) {
+        ws.close(1000, 'Component unmounting')
+      }
+    }
+  }, [serviceName])
+
+  return { latest, history }
+}
@@ -396,6 +396,48 @@ describe('useServicesContext', () => {
     })
   })
 
+  it('should reconnect with the reconnect token and last sequence number', async () => {
+    const mockFetch = vi.fn(() => createMockFetchResponse(mockServices))
+    globalThis.fetch = mockFetch as unknown as typeof fetch
+
+    const sockets: MockWebSocket[] = []
+    class WebSocketMock {
+      url: string
+      onopen: ((event: Event) => void) | null = null
+      onmessage: ((event: MessageEvent) => void) | null = null
+      onerror: ((event: Event) => void) | null = null
+      onclose: ((event: CloseEvent) => void) | null = null
+      close = vi.fn()
+      constructor(url: string) {
+        this.url = url
+        sockets.push(this)
+        setTimeout(() => {
+          this.onopen?.(new Event('open'))
+        }, 0)
+      }
+    }
+    globalThis.WebSocket = WebSocketMock as unknown as typeof WebSocket
+
+    const { result } = renderHook(() => useServicesContext(), { wrapper: TestWrapper })
+
+    await waitFor(() => {
+      expect(result.current.connected).toBe(true)
+    })
+    expect(sockets[0].url).toMatch(/\/api\/ws$/)
+
+    act(() => {
+      sockets[0].onmessage?.(createMockWebSocketMessage({ type: 'services', services: mockServices, token: 'abc', seq: 4 }))
+      sockets[0].onmessage?.(createMockWebSocketMessage({ type: 'services', services: mockServices, seq: 5 }))
+      sockets[0].onclose?.(new CloseEvent('close'))
+    })
+    expect(result.current.connected).toBe(false)
+
+    await waitFor(() => {
+      expect(sockets).toHaveLength(2)
+    }, { timeout: 2000 })
+    expect(sockets[1].url).toMatch(/\/api\/ws\?token=abc&since=5$/)
+  })
+
   it('should close WebSocket on unmount', async () => {
     const mockFetch = vi.fn(() => createMockFetchResponse(mockServices))
     globalThis.fetch = mockFetch as unknown as typeof fetch
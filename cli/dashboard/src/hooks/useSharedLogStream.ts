@@ -28,6 +28,10 @@ interface WebSocketHandlers {
 
 class SharedLogStreamManager {
   protected ws: WebSocket | null = null
+  // Server-Sent Events fallback, used once a WebSocket fails before opening (e.g. a proxy blocks WebSockets)
+  private eventSource: EventSource | null = null
+  private useSSE = false
+  private wsOpened = false
   private readonly wsHandlers = new WeakMap<WebSocket, WebSocketHandlers>()
   private readonly subscribers = new Map<string, Set<(entry: LogEntry) => void>>()
   private readonly stateSubscribers = new Set<StateChangeCallback>()
@@ -202,8 +206,13 @@ class SharedLogStreamManager {
     return `${protocol}//${globalThis.location.host}/api/logs/stream`
   }
 
+  /** Server-Sent Events stream with the same entries as getStreamUrl, or null if there is none */
+  protected getSSEUrl(): string | null {
+    return '/api/logs/sse'
+  }
+
   private connect(): void {
-    if (this.isDestroyed || this.ws || this.isConnecting) return
+    if (this.isDestroyed || this.ws || this.eventSource || this.isConnecting) return
     
     // Check max reconnect attempts
     if (this.reconnectAttempts >= this.config.maxReconnectAttempts) {
@@ -218,8 +227,15 @@ class SharedLogStreamManager {
     this.isConnecting = true
     this.setState('connecting')
     this.reconnectAttempts++
-    
+
+    const sseUrl = this.getSSEUrl()
+    if (this.useSSE && sseUrl) {
+      this.connectSSE(sseUrl)
+      return
+    }
+
     const url = this.getStreamUrl()
+    this.wsOpened = false
 
     try {
       const ws = new WebSocket(url)
@@ -254,9 +270,32 @@ class SharedLogStreamManager {
     }
   }
 
+  // The browser reconnects an EventSource by itself; only a source it gave up on is reconnected here
+  private connectSSE(url: string): void {
+    const source = new EventSource(url)
+    source.onopen = () => this.handleOpen()
+    source.onmessage = (event: MessageEvent) => this.handleMessage(event)
+    source.onerror = () => {
+      if (this.isDestroyed || this.eventSource !== source) return
+      if (source.readyState !== EventSource.CLOSED) {
+        this.setState('connecting')
+        return
+      }
+      source.close()
+      this.eventSource = null
+      this.isConnecting = false
+      this.setState('error')
+      if (this.subscribers.size > 0) {
+        this.scheduleReconnect()
+      }
+    }
+    this.eventSource = source
+  }
+
   private handleOpen(): void {
     if (this.isDestroyed) return
     
+    this.wsOpened = this.ws !== null
     const wasReconnecting = this.reconnectAttempts > 1
     this.isConnecting = false
     this.backoffDelay = this.minBackoff
@@ -265,8 +304,10 @@ class SharedLogStreamManager {
     
     // Send init message if we have pending configs and haven't sent yet
     this.sendInitMessage()
-    
-    this.startHeartbeat()
+
+    if (this.ws) {
+      this.startHeartbeat()
+    }
     
     // Only log initial connection and successful reconnections
     if (wasReconnecting) {
@@ -401,6 +442,14 @@ class SharedLogStreamManager {
     this.ws = null
     this.stopHeartbeat()
 
+    // A WebSocket that never opened may be blocked by a proxy: switch to Server-Sent Events
+    if (!this.wsOpened && !this.useSSE && this.getSSEUrl() && event.code !== 1000 && this.subscribers.size > 0) {
+      console.warn('[SharedLogStream] WebSocket unavailable, falling back to Server-Sent Events')
+      this.useSSE = true
+      this.connect()
+      return
+    }
+
     // Clean close or no subscribers - disconnect
     if (event.code === 1000 || this.subscribers.size === 0 || this.isDestroyed) {
       if (wasConnected && !this.isDestroyed) {
@@ -517,6 +566,11 @@ class SharedLogStreamManager {
       }
     }
 
+    if (this.eventSource) {
+      this.eventSource.close()
+      this.eventSource = null
+    }
+
     this.isConnecting = false
     this.backoffDelay = this.minBackoff
     this.reconnectAttempts = 0
@@ -559,6 +613,9 @@ class SharedLogStreamManager {
 
   // For testing: check if connected
   isConnected(): boolean {
+    if (this.eventSource) {
+      return this.eventSource.readyState === EventSource.OPEN
+    }
     return this.ws !== null && this.ws.readyState === WebSocket.OPEN
   }
 }
@@ -583,6 +640,10 @@ function getAzureLogManager(): SharedLogStreamManager {
         return `${protocol}//${globalThis.location.host}/api/azure/logs/stream?realtime=true`
       }
 
+      protected getSSEUrl(): string | null {
+        return null
+      }
+
       protected sendInitMessage(): void {
         if (!this.ws || this.ws.readyState !== WebSocket.OPEN) return
         if (this.initSent) return
@@ -93,3 +93,28 @@ export function formatUptime(nanos?: number): string {
   const days = hours / 24
   return `${Math.floor(days)}d ${Math.round(hours % 24)}h`
 }
+
+/**
+ * Format a byte count as a human-readable size (e.g. 512 KB, 1.5 GB)
+ */
+export function formatBytes(bytes?: number): string {
+  if (bytes === undefined || bytes < 0) return '-'
+  const units = ['B', 'KB', 'MB', 'GB', 'TB']
+  let value = bytes
+  let unit = 0
+  while (value >= 1024 && unit < units.length - 1) {
+    value /= 1024
+    unit++
+  }
+  return unit === 0 || value >= 100
+    ? `${Math.round(value)} ${units[unit]}`
+    : `${value.toFixed(1)} ${units[unit]}`
+}
+
+/**
+ * Format a CPU usage percentage with one decimal place
+ */
+export function formatCpuPercent(percent?: number): string {
+  if (percent === undefined || percent < 0) return '-'
+  return `${percent.toFixed(1)}%`
+}
@@ -8,6 +8,8 @@ import {
   formatLogTimestamp,
   formatResponseTime,
   formatUptime,
+  formatBytes,
+  formatCpuPercent,
   getCheckTypeDisplay,
   mergeHealthIntoService,
   getLogPaneVisualStatus,
@@ -338,6 +340,34 @@ describe('service-utils', () => {
     })
   })
 
+  describe('formatBytes', () => {
+    it('should return - for undefined', () => {
+      expect(formatBytes()).toBe('-')
+    })
+
+    it('should format bytes', () => {
+      expect(formatBytes(512)).toBe('512 B')
+    })
+
+    it('should format megabytes with one decimal', () => {
+      expect(formatBytes(1.5 * 1024 * 1024)).toBe('1.5 MB')
+    })
+
+    it('should round large values', () => {
+      expect(formatBytes(256 * 1024 * 1024)).toBe('256 MB')
+    })
+  })
+
+  describe('formatCpuPercent', () => {
+    it('should return - for undefined', () => {
+      expect(formatCpuPercent()).toBe('-')
+    })
+
+    it('should format with one decimal', () => {
+      expect(formatCpuPercent(12.345)).toBe('12.3%')
+    })
+  })
+
   describe('getCheckTypeDisplay', () => {
     it('should return HTTP for http', () => {
       expect(getCheckTypeDisplay('http')).toBe('HTTP')
@@ -43,6 +43,8 @@ export {
   formatLogTimestamp,
   formatResponseTime,
   formatUptime,
+  formatBytes,
+  formatCpuPercent,
 } from './service-formatters'
 
 // Re-export from service-display.ts
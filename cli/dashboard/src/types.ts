@@ -264,3 +264,71 @@ export interface HealthDiagnostic {
   suggestedActions: HealthAction[]
   formattedReport: string  // Pre-formatted markdown for copy
 }
+
+// ============================================================================
+// Resource Metrics Types (/api/metrics and "metrics" WebSocket messages)
+// ============================================================================
+
+/** CPU, memory, and uptime of a service's process tree */
+export interface ServiceMetrics {
+  service: string
+  pid: number
+  cpuPercent: number  // Percent of one CPU core; may exceed 100 on multi-core machines
+  memoryBytes: number // Resident set size
+  processes: number
+  uptimeSeconds: number
+  timestamp: string
+}
+
+/** One point of a service's resource usage history */
+export interface MetricsPoint {
+  timestamp: string
+  cpuPercent: number
+  memoryBytes: number
+}
+
+/** Response of GET /api/metrics */
+export interface MetricsResponse {
+  intervalSeconds: number
+  services: ServiceMetrics[]
+  history: Record<string, MetricsPoint[]>
+}
+
+/** A match of GET /api/logs/search with the messages around it */
+export interface LogSearchMatch {
+  service: string
+  message: string
+  /** 0 = info, 1 = warn, 2 = error, 3 = debug */
+  level: number
+  timestamp: string
+  isStderr: boolean
+  context?: {
+    before?: string[]
+    after?: string[]
+  }
+}
+
+/** Response of GET /api/logs/search */
+export interface LogSearchResponse {
+  query: string
+  /** Most recent first */
+  matches: LogSearchMatch[]
+  /** More matches exist beyond the limit */
+  truncated: boolean
+}
+
+/** A project with an active azd app run session, from GET /api/v1/projects */
+export interface ActiveProject {
+  name: string
+  dir: string
+  dashboardUrl?: string
+  services: string[]
+  detached: boolean
+  /** The project of this dashboard */
+  current: boolean
+}
+
+/** Response of GET /api/v1/projects */
+export interface ProjectsResponse {
+  projects: ActiveProject[]
+}
@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/cache"
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-core/cliout"
+
+	"github.com/spf13/cobra"
+)
+
+// Cache scopes selected with --global.
+const (
+	cacheScopeProject = "project"
+	cacheScopeGlobal  = "global"
+)
+
+var cacheGlobal bool
+
+// CacheLocation identifies the cache directory a cache command operates on.
+type CacheLocation struct {
+	Scope string `json:"scope"` // project or global
+	Path  string `json:"path"`
+}
+
+// CacheStatsReport is the output of `azd app cache stats`.
+type CacheStatsReport struct {
+	CacheLocation
+	Entries   []cache.Entry `json:"entries"`
+	Count     int           `json:"count"`
+	TotalSize int64         `json:"totalSize"` // Bytes
+}
+
+// CacheClearResult is the output of `azd app cache clear`.
+type CacheClearResult struct {
+	CacheLocation
+	Removed int `json:"removed"` // Cache files removed
+}
+
+// NewCacheCommand creates the cache command.
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage cached results",
+		Long: `Shows, locates and clears the files azd app caches results in, such as the
+requirement check results of 'azd app reqs'.
+
+Commands operate on the project's cache (.azure/cache next to azure.yaml) by default,
+or on the cache shared by all projects (~/.azd/app/cache) with --global.
+
+Examples:
+  # Show what is cached for the project
+  azd app cache stats
+
+  # Clear the project's cache
+  azd app cache clear
+
+  # Print the global cache directory
+  azd app cache path --global`,
+	}
+
+	cmd.PersistentFlags().BoolVar(&cacheGlobal, "global", false, "Use the cache shared by all projects instead of the project's cache")
+
+	cmd.AddCommand(newCacheClearCmd())
+	cmd.AddCommand(newCacheStatsCmd())
+	cmd.AddCommand(newCachePathCmd())
+
+	return cmd
+}
+
+func newCacheClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "clear",
+		Short:        "Delete the cached results",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliout.CommandHeader("cache clear", "Delete the cached results")
+			location, err := resolveCacheLocation(cacheGlobal)
+			if err != nil {
+				return err
+			}
+
+			removed, err := cache.ClearDir(location.Path)
+			if err != nil {
+				return fmt.Errorf("failed to clear cache: %w", err)
+			}
+
+			if cliout.IsJSON() {
+				return cliout.PrintJSON(CacheClearResult{CacheLocation: location, Removed: removed})
+			}
+			if removed == 0 {
+				cliout.Info("The %s cache is already empty (%s)", location.Scope, location.Path)
+				return nil
+			}
+			cliout.Success("Removed %d cache file(s) from %s", removed, location.Path)
+			return nil
+		},
+	}
+}
+
+func newCacheStatsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "stats",
+		Short:        "Show the cached results and their size and age",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliout.CommandHeader("cache stats", "Show the cached results")
+			location, err := resolveCacheLocation(cacheGlobal)
+			if err != nil {
+				return err
+			}
+
+			entries, err := cache.ListEntries(location.Path)
+			if err != nil {
+				return err
+			}
+			report := newCacheStatsReport(location, entries)
+
+			if cliout.IsJSON() {
+				return cliout.PrintJSON(report)
+			}
+			printCacheStats(report, time.Now())
+			return nil
+		},
+	}
+}
+
+func newCachePathCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "path",
+		Short:        "Print the cache directory",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			location, err := resolveCacheLocation(cacheGlobal)
+			if err != nil {
+				return err
+			}
+
+			if cliout.IsJSON() {
+				return cliout.PrintJSON(location)
+			}
+			// Plain output so the path can be used in scripts, e.g. ls "$(azd app cache path)"
+			fmt.Println(location.Path)
+			return nil
+		},
+	}
+}
+
+// resolveCacheLocation returns the global cache directory, or the cache directory of the
+// project containing the current directory (the directory of azure.yaml, or the current
+// directory when there is none).
+func resolveCacheLocation(global bool) (CacheLocation, error) {
+	if global {
+		dir, err := cache.GlobalDir()
+		if err != nil {
+			return CacheLocation{}, err
+		}
+		return CacheLocation{Scope: cacheScopeGlobal, Path: dir}, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return CacheLocation{}, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	projectDir := cwd
+	azureYamlPath, err := detector.FindAzureYaml(cwd)
+	if err != nil {
+		return CacheLocation{}, fmt.Errorf("error searching for azure.yaml: %w", err)
+	}
+	if azureYamlPath != "" {
+		projectDir = filepath.Dir(azureYamlPath)
+	}
+	return CacheLocation{Scope: cacheScopeProject, Path: cache.ProjectDir(projectDir)}, nil
+}
+
+// newCacheStatsReport totals the entries of a cache directory.
+func newCacheStatsReport(location CacheLocation, entries []cache.Entry) CacheStatsReport {
+	report := CacheStatsReport{CacheLocation: location, Entries: entries, Count: len(entries)}
+	if report.Entries == nil {
+		report.Entries = []cache.Entry{}
+	}
+	for _, entry := range entries {
+		report.TotalSize += entry.Size
+	}
+	return report
+}
+
+// printCacheStats prints the entries of a cache directory as a table.
+func printCacheStats(report CacheStatsReport, now time.Time) {
+	cliout.Label("Cache", fmt.Sprintf("%s (%s)", report.Path, report.Scope))
+	cliout.Newline()
+
+	if report.Count == 0 {
+		cliout.Info("No cached results")
+		return
+	}
+
+	rows := make([]cliout.TableRow, 0, len(report.Entries))
+	for _, entry := range report.Entries {
+		row := cliout.TableRow{
+			"Cache":   entry.Name,
+			"Size":    formatCacheSize(entry.Size),
+			"Age":     "-",
+			"Version": entry.Version,
+		}
+		if entry.Corrupt {
+			row["Age"] = "corrupted"
+		} else if !entry.CachedAt.IsZero() {
+			row["Age"] = formatInfoDuration(now.Sub(entry.CachedAt))
+		}
+		rows = append(rows, row)
+	}
+	cliout.Table([]string{"Cache", "Size", "Age", "Version"}, rows)
+
+	cliout.Newline()
+	cliout.Label("Total", fmt.Sprintf("%d file(s), %s", report.Count, formatCacheSize(report.TotalSize)))
+}
+
+// formatCacheSize formats a size in bytes, e.g. 512 B or 1.5 KB.
+func formatCacheSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size) / unit
+	for _, suffix := range []string{"KB", "MB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f GB", value)
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/cache"
+)
+
+func TestFormatCacheSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{size: 0, want: "0 B"},
+		{size: 512, want: "512 B"},
+		{size: 1536, want: "1.5 KB"},
+		{size: 5 * 1024 * 1024, want: "5.0 MB"},
+		{size: 3 * 1024 * 1024 * 1024, want: "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCacheSize(tt.size); got != tt.want {
+			t.Errorf("formatCacheSize(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestNewCacheStatsReport(t *testing.T) {
+	location := CacheLocation{Scope: cacheScopeProject, Path: "/app/.azure/cache"}
+
+	empty := newCacheStatsReport(location, nil)
+	if empty.Entries == nil || empty.Count != 0 || empty.TotalSize != 0 {
+		t.Errorf("empty report = %+v, want no entries and an empty, non-nil list", empty)
+	}
+
+	report := newCacheStatsReport(location, []cache.Entry{{Name: "reqs_cache", Size: 300}, {Name: "other", Size: 200}})
+	if report.Count != 2 || report.TotalSize != 500 {
+		t.Errorf("report = %+v, want 2 entries totaling 500 bytes", report)
+	}
+}
+
+func TestResolveCacheLocation(t *testing.T) {
+	globalDir := filepath.Join(t.TempDir(), "global")
+	original := cache.GlobalDir
+	cache.GlobalDir = func() (string, error) { return globalDir, nil }
+	defer func() { cache.GlobalDir = original }()
+
+	location, err := resolveCacheLocation(true)
+	if err != nil {
+		t.Fatalf("resolveCacheLocation(true) error = %v", err)
+	}
+	if location.Scope != cacheScopeGlobal || location.Path != globalDir {
+		t.Errorf("resolveCacheLocation(true) = %+v, want global %s", location, globalDir)
+	}
+
+	// The project cache is next to azure.yaml, even from a subdirectory
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, "azure.yaml"), []byte("name: test\n"), 0600); err != nil {
+		t.Fatalf("failed to write azure.yaml: %v", err)
+	}
+	subDir := filepath.Join(projectDir, "src", "api")
+	if err := os.MkdirAll(subDir, 0750); err != nil {
+		t.Fatalf("failed to create subdirectory: %v", err)
+	}
+	t.Chdir(subDir)
+
+	location, err = resolveCacheLocation(false)
+	if err != nil {
+		t.Fatalf("resolveCacheLocation(false) error = %v", err)
+	}
+	want := filepath.Join(projectDir, ".azure", "cache")
+	if resolved, err := filepath.EvalSymlinks(projectDir); err == nil {
+		want = filepath.Join(resolved, ".azure", "cache")
+	}
+	if location.Scope != cacheScopeProject || location.Path != want {
+		t.Errorf("resolveCacheLocation(false) = %+v, want project %s", location, want)
+	}
+}
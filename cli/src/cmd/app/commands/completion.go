@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+var completionNoDescriptions bool
+
+// NewCompletionCommand creates the completion command, which replaces cobra's default one
+// with help for azd app.
+func NewCompletionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion <bash|zsh|fish|powershell>",
+		Short: "Generate shell completion scripts",
+		Long: `Generates a completion script for bash, zsh, fish or PowerShell and prints it to stdout.
+
+Besides commands and flags, the scripts complete the service names of azure.yaml for
+--service and for the service arguments of logs, restart and open.
+
+Examples:
+  # Load completions for the current bash session
+  source <(azd app completion bash)
+
+  # Install completions for fish
+  azd app completion fish > ~/.config/fish/completions/azd-app.fish
+
+  # Load completions in PowerShell
+  azd app completion powershell | Out-String | Invoke-Expression`,
+	}
+
+	cmd.PersistentFlags().BoolVar(&completionNoDescriptions, "no-descriptions", false, "Disable completion descriptions")
+
+	generators := []struct {
+		shell, short string
+		generate     func(root *cobra.Command) error
+	}{
+		{"bash", "Generate the autocompletion script for bash", func(root *cobra.Command) error {
+			return root.GenBashCompletionV2(os.Stdout, !completionNoDescriptions)
+		}},
+		{"zsh", "Generate the autocompletion script for zsh", func(root *cobra.Command) error {
+			if completionNoDescriptions {
+				return root.GenZshCompletionNoDesc(os.Stdout)
+			}
+			return root.GenZshCompletion(os.Stdout)
+		}},
+		{"fish", "Generate the autocompletion script for fish", func(root *cobra.Command) error {
+			return root.GenFishCompletion(os.Stdout, !completionNoDescriptions)
+		}},
+		{"powershell", "Generate the autocompletion script for PowerShell", func(root *cobra.Command) error {
+			if completionNoDescriptions {
+				return root.GenPowerShellCompletion(os.Stdout)
+			}
+			return root.GenPowerShellCompletionWithDesc(os.Stdout)
+		}},
+	}
+	for _, g := range generators {
+		generate := g.generate
+		cmd.AddCommand(&cobra.Command{
+			Use:                   g.shell,
+			Short:                 g.short,
+			Args:                  cobra.NoArgs,
+			DisableFlagsInUseLine: true,
+			ValidArgsFunction:     cobra.NoFileCompletions,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if err := generate(cmd.Root()); err != nil {
+					return fmt.Errorf("failed to generate completion script: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	return cmd
+}
+
+// completeServiceNames completes the service names of azure.yaml for service arguments,
+// leaving out the services already given.
+func completeServiceNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	for _, name := range azureYamlServiceNames(cmd) {
+		if !slices.Contains(args, name) && strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeServiceName completes a single service name argument.
+func completeServiceName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeServiceNames(cmd, args, toComplete)
+}
+
+// completeServiceList completes the service names of azure.yaml for comma-separated --service
+// flags: the name after the last comma, leaving out the names before it.
+func completeServiceList(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	given, partial := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		given, partial = toComplete[:i+1], toComplete[i+1:]
+	}
+	listed := strings.Split(strings.TrimSuffix(given, ","), ",")
+
+	var names []string
+	for _, name := range azureYamlServiceNames(cmd) {
+		if !slices.Contains(listed, name) && strings.HasPrefix(name, partial) {
+			names = append(names, given+name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// registerServiceFlagCompletion completes the service names of azure.yaml for the --service flag of cmd.
+func registerServiceFlagCompletion(cmd *cobra.Command) {
+	_ = cmd.RegisterFlagCompletionFunc("service", completeServiceList)
+}
+
+// azureYamlServiceNames returns the names of the services in the azure.yaml of the working
+// directory, which is the --cwd flag when given, in order. It returns none when there's no
+// azure.yaml or it can't be parsed, since completion has no way to report errors.
+func azureYamlServiceNames(cmd *cobra.Command) []string {
+	dir := "."
+	if flag := cmd.Flag("cwd"); flag != nil && flag.Value.String() != "" {
+		dir = flag.Value.String()
+	}
+	azureYaml, err := service.ParseAzureYaml(dir)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(azureYaml.Services))
+	for name := range azureYaml.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
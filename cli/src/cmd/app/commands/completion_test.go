@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func writeCompletionAzureYaml(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	content := "name: shop\nservices:\n  web:\n    project: ./web\n  api:\n    project: ./api\n  worker:\n    project: ./worker\n"
+	if err := os.WriteFile(filepath.Join(dir, "azure.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestCompleteServiceNames(t *testing.T) {
+	t.Chdir(writeCompletionAzureYaml(t))
+	cmd := &cobra.Command{}
+
+	tests := []struct {
+		args       []string
+		toComplete string
+		want       []string
+	}{
+		{nil, "", []string{"api", "web", "worker"}},
+		{nil, "w", []string{"web", "worker"}},
+		{[]string{"web"}, "w", []string{"worker"}},
+		{nil, "db", nil},
+	}
+	for _, tt := range tests {
+		got, directive := completeServiceNames(cmd, tt.args, tt.toComplete)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("completeServiceNames(%v, %q) = %v, want %v", tt.args, tt.toComplete, got, tt.want)
+		}
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("completeServiceNames() directive = %v, want NoFileComp", directive)
+		}
+	}
+
+	if got, _ := completeServiceName(cmd, []string{"api"}, ""); got != nil {
+		t.Errorf("completeServiceName() after an argument = %v, want none", got)
+	}
+}
+
+func TestCompleteServiceList(t *testing.T) {
+	t.Chdir(writeCompletionAzureYaml(t))
+	cmd := &cobra.Command{}
+
+	tests := []struct {
+		toComplete string
+		want       []string
+	}{
+		{"", []string{"api", "web", "worker"}},
+		{"a", []string{"api"}},
+		{"api,", []string{"api,web", "api,worker"}},
+		{"api,web,w", []string{"api,web,worker"}},
+	}
+	for _, tt := range tests {
+		if got, _ := completeServiceList(cmd, nil, tt.toComplete); !slices.Equal(got, tt.want) {
+			t.Errorf("completeServiceList(%q) = %v, want %v", tt.toComplete, got, tt.want)
+		}
+	}
+}
+
+func TestAzureYamlServiceNames_Cwd(t *testing.T) {
+	dir := writeCompletionAzureYaml(t)
+	t.Chdir(t.TempDir())
+
+	cmd := &cobra.Command{}
+	if got := azureYamlServiceNames(cmd); got != nil {
+		t.Errorf("azureYamlServiceNames() without azure.yaml = %v, want none", got)
+	}
+
+	cmd.Flags().StringP("cwd", "C", "", "")
+	if err := cmd.Flags().Set("cwd", dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := azureYamlServiceNames(cmd); !slices.Equal(got, []string{"api", "web", "worker"}) {
+		t.Errorf("azureYamlServiceNames() with --cwd = %v", got)
+	}
+}
+
+func TestCompletionCommand(t *testing.T) {
+	root := &cobra.Command{Use: "app"}
+	root.AddCommand(NewCompletionCommand(), NewLogsCommand())
+
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		if cmd, _, err := root.Find([]string{"completion", shell}); err != nil || cmd.Name() != shell {
+			t.Errorf("completion has no %s subcommand", shell)
+		}
+	}
+
+	t.Chdir(writeCompletionAzureYaml(t))
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{cobra.ShellCompRequestCmd, "logs", "--service", "api,"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("completion request error = %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "api,web\n") || !strings.Contains(got, "api,worker\n") {
+		t.Errorf("--service completion = %q, want the remaining services", got)
+	}
+}
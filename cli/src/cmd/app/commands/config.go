@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/config"
+	"github.com/jongio/azd-app/cli/src/internal/selfupdate"
+	"github.com/jongio/azd-app/cli/src/internal/telemetry"
+	"github.com/jongio/azd-core/cliout"
+
+	"github.com/spf13/cobra"
+)
+
+// TelemetryReport is the output of `azd app config telemetry`.
+type TelemetryReport struct {
+	telemetry.Status
+	Queued  int  `json:"queued"`  // Events waiting to be uploaded
+	Uploads bool `json:"uploads"` // Whether this build uploads events; without an endpoint they stay queued
+}
+
+// UpdatesReport is the output of `azd app config updates`.
+type UpdatesReport struct {
+	selfupdate.CheckStatus
+	CheckedAt     time.Time `json:"checkedAt,omitzero"`      // When the registry was last read
+	LatestVersion string    `json:"latestVersion,omitempty"` // Latest release found by the last check
+}
+
+// NewConfigCommand creates the config command.
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage azd app settings",
+		Long: `Shows and changes the user settings of azd app, saved in ~/.azd/config.json.
+
+Examples:
+  # Show whether usage telemetry is collected
+  azd app config telemetry
+
+  # Opt in to anonymous usage telemetry
+  azd app config telemetry on
+
+  # Stop checking for newer releases
+  azd app config updates off`,
+	}
+
+	cmd.AddCommand(newConfigTelemetryCmd())
+	cmd.AddCommand(newConfigUpdatesCmd())
+
+	return cmd
+}
+
+func newConfigTelemetryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "telemetry [on|off]",
+		Short: "Show or change the usage telemetry opt-in",
+		Long: `Shows whether anonymous usage telemetry is collected, or opts in or out.
+
+Telemetry is off unless you opt in. Each command records its name, duration, success or
+failure and the frameworks it detected; arguments, paths, service names and error messages
+are never recorded. Events are queued in ~/.azd/app/telemetry and uploaded in batches;
+builds without a telemetry endpoint upload nothing.
+
+The environment takes precedence over the saved setting:
+  AZURE_CORE_COLLECT_TELEMETRY=no  turns telemetry off (azd's setting)
+  DO_NOT_TRACK=1                   turns telemetry off
+  AZD_APP_COLLECT_TELEMETRY=yes|no turns telemetry on or off for the process
+
+Turning telemetry off deletes the queued events.`,
+		Args:         cobra.MaximumNArgs(1),
+		ValidArgs:    []string{"on", "off"},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				enabled, err := parseTelemetrySwitch(args[0])
+				if err != nil {
+					return err
+				}
+				if err := setTelemetry(enabled); err != nil {
+					return err
+				}
+			}
+
+			report, err := newTelemetryReport()
+			if err != nil {
+				return err
+			}
+			if cliout.IsJSON() {
+				return cliout.PrintJSON(report)
+			}
+			printTelemetryReport(report, len(args) == 1)
+			return nil
+		},
+	}
+}
+
+func newConfigUpdatesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "updates [on|off]",
+		Short: "Show or change the check for newer releases",
+		Long: `Shows whether azd app checks for newer releases, or turns the check on or off.
+
+The check is on by default. At most once a day, commands read the extension registry in the
+background and cache the result in ~/.azd/app/update-check.json; when a newer release exists,
+a one-line hint to run 'azd app upgrade' is printed after the command's output.
+
+The check is skipped in CI, and AZD_APP_UPDATE_CHECK=yes|no turns it on or off for the process.`,
+		Args:         cobra.MaximumNArgs(1),
+		ValidArgs:    []string{"on", "off"},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				enabled, err := parseSwitch("updates", args[0])
+				if err != nil {
+					return err
+				}
+				if err := config.SetUpdateCheckEnabled(enabled); err != nil {
+					return fmt.Errorf("failed to save update check setting: %w", err)
+				}
+			}
+
+			report := newUpdatesReport()
+			if cliout.IsJSON() {
+				return cliout.PrintJSON(report)
+			}
+			printUpdatesReport(report, len(args) == 1)
+			return nil
+		},
+	}
+}
+
+// parseTelemetrySwitch parses the on|off argument of `azd app config telemetry`.
+func parseTelemetrySwitch(arg string) (bool, error) {
+	return parseSwitch("telemetry", arg)
+}
+
+// parseSwitch parses the on|off argument of the config setting name.
+func parseSwitch(name, arg string) (bool, error) {
+	switch strings.ToLower(arg) {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%s must be 'on' or 'off', got '%s'", name, arg)
+	}
+}
+
+// setTelemetry saves the opt-in; opting out also deletes the queued events.
+func setTelemetry(enabled bool) error {
+	if err := config.SetTelemetryEnabled(enabled); err != nil {
+		return fmt.Errorf("failed to save telemetry setting: %w", err)
+	}
+	if enabled {
+		return nil
+	}
+	queue, err := telemetry.DefaultQueue()
+	if err != nil {
+		return err
+	}
+	return queue.Clear()
+}
+
+// newTelemetryReport returns the telemetry status and the number of queued events.
+func newTelemetryReport() (TelemetryReport, error) {
+	report := TelemetryReport{Status: telemetry.Current(), Uploads: telemetry.Uploads()}
+	queue, err := telemetry.DefaultQueue()
+	if err != nil {
+		return report, err
+	}
+	events, err := queue.Events()
+	if err != nil {
+		return report, err
+	}
+	report.Queued = len(events)
+	return report, nil
+}
+
+// printTelemetryReport prints the telemetry status; changed is true after on or off.
+func printTelemetryReport(report TelemetryReport, changed bool) {
+	switch {
+	case changed && report.OptedIn:
+		cliout.Success("Opted in to anonymous usage telemetry")
+	case changed:
+		cliout.Success("Opted out of usage telemetry")
+	}
+
+	state := "off"
+	if report.Enabled {
+		state = "on"
+	}
+	cliout.Label("Telemetry", fmt.Sprintf("%s (%s)", state, report.Reason))
+	cliout.Label("Queued", fmt.Sprintf("%d event(s)", report.Queued))
+
+	if changed && report.OptedIn && !report.Uploads {
+		cliout.Info("Nothing is uploaded in this build: it has no telemetry endpoint, so events only stay in the local queue")
+	}
+
+	// The environment overrides the saved setting
+	if changed && report.Enabled != report.OptedIn {
+		cliout.Warning("The environment overrides this setting: %s", report.Reason)
+	}
+}
+
+// newUpdatesReport returns the update check status and the result of the last check.
+func newUpdatesReport() UpdatesReport {
+	state := selfupdate.LoadCheckState()
+	return UpdatesReport{
+		CheckStatus:   selfupdate.CurrentCheck(),
+		CheckedAt:     state.CheckedAt,
+		LatestVersion: state.LatestVersion,
+	}
+}
+
+// printUpdatesReport prints the update check status; changed is true after on or off.
+func printUpdatesReport(report UpdatesReport, changed bool) {
+	switch {
+	case changed && report.Saved:
+		cliout.Success("Turned on the check for newer releases")
+	case changed:
+		cliout.Success("Turned off the check for newer releases")
+	}
+
+	state := "off"
+	if report.Enabled {
+		state = "on"
+	}
+	cliout.Label("Updates", fmt.Sprintf("%s (%s)", state, report.Reason))
+	if !report.CheckedAt.IsZero() {
+		cliout.Label("Checked", report.CheckedAt.Local().Format(time.RFC1123))
+	}
+	if report.LatestVersion != "" {
+		cliout.Label("Latest", report.LatestVersion)
+	}
+
+	// The environment overrides the saved setting
+	if changed && report.Enabled != report.Saved {
+		cliout.Warning("The environment overrides this setting: %s", report.Reason)
+	}
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/config"
+	"github.com/jongio/azd-app/cli/src/internal/selfupdate"
+	"github.com/jongio/azd-app/cli/src/internal/telemetry"
+)
+
+func TestParseTelemetrySwitch(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    bool
+		wantErr bool
+	}{
+		{"on", true, false},
+		{"OFF", false, false},
+		{"yes", false, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTelemetrySwitch(tt.arg)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseTelemetrySwitch(%q) = %v, %v; want %v, error %v", tt.arg, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSetTelemetry(t *testing.T) {
+	t.Setenv(telemetry.EnvCollect, "")
+	t.Setenv(telemetry.EnvAzdCollect, "")
+	t.Setenv(telemetry.EnvDoNotTrack, "")
+	t.Setenv(telemetry.EnvEndpoint, "")
+	dir := t.TempDir()
+
+	originalConfigPath := config.GetConfigPath
+	config.GetConfigPath = func() (string, error) { return filepath.Join(dir, "config.json"), nil }
+	defer func() { config.GetConfigPath = originalConfigPath }()
+	config.ResetGlobal()
+	defer config.ResetGlobal()
+
+	originalQueueDir := telemetry.QueueDir
+	telemetry.QueueDir = func() (string, error) { return dir, nil }
+	defer func() { telemetry.QueueDir = originalQueueDir }()
+
+	if err := setTelemetry(true); err != nil {
+		t.Fatalf("setTelemetry(true) error = %v", err)
+	}
+	queue := &telemetry.Queue{Dir: dir}
+	if err := queue.Add(telemetry.Event{Command: "app run", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	report, err := newTelemetryReport()
+	if err != nil {
+		t.Fatalf("newTelemetryReport() error = %v", err)
+	}
+	if !report.Enabled || !report.OptedIn || report.Queued != 1 {
+		t.Errorf("report after opting in = %+v, want enabled with 1 queued event", report)
+	}
+	if report.Uploads {
+		t.Error("report.Uploads = true, want false without a telemetry endpoint")
+	}
+
+	// Opting out deletes the queued events
+	if err := setTelemetry(false); err != nil {
+		t.Fatalf("setTelemetry(false) error = %v", err)
+	}
+	report, err = newTelemetryReport()
+	if err != nil {
+		t.Fatalf("newTelemetryReport() error = %v", err)
+	}
+	if report.Enabled || report.OptedIn || report.Queued != 0 {
+		t.Errorf("report after opting out = %+v, want disabled with no queued events", report)
+	}
+}
+
+func TestParseSwitch(t *testing.T) {
+	if _, err := parseSwitch("updates", "daily"); err == nil || !strings.Contains(err.Error(), "updates must be") {
+		t.Errorf("parseSwitch() error = %v, want one naming the setting", err)
+	}
+}
+
+func TestNewUpdatesReport(t *testing.T) {
+	t.Setenv(selfupdate.EnvUpdateCheck, "")
+	t.Setenv("CI", "")
+	t.Setenv("GITHUB_ACTIONS", "")
+	t.Setenv("TF_BUILD", "")
+	dir := t.TempDir()
+
+	originalConfigPath := config.GetConfigPath
+	config.GetConfigPath = func() (string, error) { return filepath.Join(dir, "config.json"), nil }
+	defer func() { config.GetConfigPath = originalConfigPath }()
+	config.ResetGlobal()
+	defer config.ResetGlobal()
+
+	originalStatePath := selfupdate.CheckStatePath
+	selfupdate.CheckStatePath = func() (string, error) { return filepath.Join(dir, "update-check.json"), nil }
+	defer func() { selfupdate.CheckStatePath = originalStatePath }()
+
+	if err := selfupdate.SaveCheckState(selfupdate.CheckState{CheckedAt: time.Now(), LatestVersion: "0.13.5"}); err != nil {
+		t.Fatal(err)
+	}
+	report := newUpdatesReport()
+	if !report.Enabled || report.LatestVersion != "0.13.5" {
+		t.Errorf("report = %+v, want enabled with latest 0.13.5", report)
+	}
+
+	if err := config.SetUpdateCheckEnabled(false); err != nil {
+		t.Fatal(err)
+	}
+	if report := newUpdatesReport(); report.Enabled || report.Saved {
+		t.Errorf("report after turning off = %+v, want disabled", report)
+	}
+}
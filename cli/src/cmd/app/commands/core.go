@@ -4,9 +4,11 @@ package commands
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/jongio/azd-app/cli/src/internal/orchestrator"
+	"github.com/jongio/azd-app/cli/src/internal/service"
 	"github.com/jongio/azd-core/cliout"
 )
 
@@ -16,12 +18,29 @@ var cmdOrchestrator *orchestrator.Orchestrator
 // ExecutionContext holds runtime configuration for command execution.
 type ExecutionContext struct {
 	CacheEnabled bool
+	Services     []string // Services whose requirements are checked (empty = all services)
+	Concurrency  int      // Maximum requirement checks running at once (0 = one per CPU)
 }
 
 // ReqsResult represents the JSON output structure for reqs command.
 type ReqsResult struct {
-	Satisfied bool        `json:"satisfied"`
+	Satisfied bool        `json:"satisfied"` // Every required requirement is satisfied
 	Reqs      []ReqResult `json:"reqs"`
+	Summary   ReqsSummary `json:"summary"`
+}
+
+// ReqsSummary counts the requirement results of each severity.
+type ReqsSummary struct {
+	Required    SeverityCounts `json:"required"`
+	Recommended SeverityCounts `json:"recommended"`
+	Optional    SeverityCounts `json:"optional"`
+}
+
+// SeverityCounts counts the requirement results of one severity.
+type SeverityCounts struct {
+	Total       int `json:"total"`
+	Satisfied   int `json:"satisfied"`
+	Unsatisfied int `json:"unsatisfied"`
 }
 
 // DepsResult represents the JSON output structure for deps command.
@@ -65,6 +84,16 @@ func SetCacheEnabled(enabled bool) {
 	execContext.CacheEnabled = enabled
 }
 
+// SetReqsServices limits requirement checks to the top-level reqs and the reqs of the given services.
+func SetReqsServices(services []string) {
+	execContext.Services = services
+}
+
+// SetReqsConcurrency limits how many requirement checks run at once (0 = one per CPU).
+func SetReqsConcurrency(concurrency int) {
+	execContext.Concurrency = concurrency
+}
+
 // init initializes the command orchestrator and registers all commands.
 func init() {
 	cmdOrchestrator = orchestrator.NewOrchestrator()
@@ -116,21 +145,23 @@ func executeReqs() error {
 		return err
 	}
 
-	// Build effective requirements list
-	effectiveReqs := azureYaml.Reqs
-
-	// Auto-inject Docker requirement if container services are detected
-	if azureYaml.hasContainerServices() && !azureYaml.hasDockerReq() {
-		dockerReq := Prerequisite{
-			Name:         "docker",
-			MinVersion:   "20.0.0",
-			CheckRunning: true,
-		}
-		effectiveReqs = append(effectiveReqs, dockerReq)
+	// Build effective requirements list for the selected services
+	effectiveReqs, err := azureYaml.requirementsFor(execContext.Services)
+	if err != nil {
+		return err
 	}
 
+	// Projects pinning pnpm/yarn via the packageManager field need corepack
+	corepackReqs := findServiceCorepackRequirements(filepath.Dir(azureYamlPath), azureYaml, execContext.Services)
+
+	// Projects pinning a Node.js version via .nvmrc, .node-version or volta
+	nodePins := findServiceNodeVersionPins(filepath.Dir(azureYamlPath), azureYaml, execContext.Services)
+
+	// .NET projects pinning their SDK via global.json
+	sdkPins := findServiceGlobalJSONPins(filepath.Dir(azureYamlPath), azureYaml, execContext.Services)
+
 	// If no reqs section exists, skip checks gracefully
-	if len(effectiveReqs) == 0 {
+	if len(effectiveReqs) == 0 && len(corepackReqs) == 0 && len(nodePins) == 0 && len(sdkPins) == 0 {
 		if cliout.IsJSON() {
 			return cliout.PrintJSON(ReqsResult{
 				Satisfied: true,
@@ -144,13 +175,29 @@ func executeReqs() error {
 	cacheManager := createCacheManager(execContext.CacheEnabled)
 
 	// Check requirements (with caching)
-	results, allSatisfied := checkRequirementsWithCache(effectiveReqs, azureYamlPath, cacheManager)
+	results, allSatisfied := checkRequirementsWithCache(effectiveReqs, azureYamlPath, reqsCacheScope(execContext.Services, service.ActiveProfile()), cacheManager)
+
+	// Corepack results depend on package.json rather than azure.yaml, so they are never cached
+	corepackResults, corepackSatisfied := checkCorepackRequirements(corepackReqs)
+	results = append(results, corepackResults...)
+	allSatisfied = allSatisfied && corepackSatisfied
+
+	// SDK pins depend on global.json and the installed SDKs, so like corepack they are never cached
+	sdkResults, sdkSatisfied := checkGlobalJSONPins(sdkPins)
+	results = append(results, sdkResults...)
+	allSatisfied = allSatisfied && sdkSatisfied
+
+	// Pinned Node.js versions are only recommended, so a mismatch warns without failing the check
+	results = append(results, checkNodeVersionPins(nodePins)...)
+
+	summary := summarizeReqs(results)
 
 	// JSON output
 	if cliout.IsJSON() {
 		return cliout.PrintJSON(ReqsResult{
 			Satisfied: allSatisfied,
 			Reqs:      results,
+			Summary:   summary,
 		})
 	}
 
@@ -161,7 +208,17 @@ func executeReqs() error {
 		return fmt.Errorf("requirement check failed")
 	}
 
-	cliout.Success("All reqs satisfied!")
+	if summary.Recommended.Unsatisfied == 0 && summary.Optional.Unsatisfied == 0 {
+		cliout.Success("All reqs satisfied!")
+		return nil
+	}
+	cliout.Success("All required reqs satisfied")
+	if summary.Recommended.Unsatisfied > 0 {
+		cliout.Warning("%d recommended req(s) not satisfied", summary.Recommended.Unsatisfied)
+	}
+	if summary.Optional.Unsatisfied > 0 {
+		cliout.Info("%d optional req(s) not satisfied", summary.Optional.Unsatisfied)
+	}
 	return nil
 }
 
@@ -2,25 +2,48 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/jongio/azd-app/cli/src/internal/cache"
 	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/eta"
 	"github.com/jongio/azd-app/cli/src/internal/installer"
+	"github.com/jongio/azd-app/cli/src/internal/output"
 	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-app/cli/src/internal/teamconfig"
 	"github.com/jongio/azd-app/cli/src/internal/workspace"
 	"github.com/jongio/azd-core/cliout"
 	types "github.com/jongio/azd-core/projecttype"
 )
 
+// depsProjects are the projects deps installs, by ecosystem.
+type depsProjects struct {
+	Node   []types.NodeProject
+	Python []types.PythonProject
+	Dotnet []types.DotnetProject
+	Go     []detector.GoProject
+	Rust   []detector.RustProject
+	Java   []detector.JavaProject
+}
+
+// count returns the number of projects of all ecosystems.
+func (p depsProjects) count() int {
+	return len(p.Node) + len(p.Python) + len(p.Dotnet) + len(p.Go) + len(p.Rust) + len(p.Java)
+}
+
 // DependencyInstaller handles installation of project dependencies.
 type DependencyInstaller struct {
-	searchRoot     string
-	nodeProjects   []types.NodeProject   // Pre-filtered Node.js projects (optional)
-	pythonProjects []types.PythonProject // Pre-filtered Python projects (optional)
-	dotnetProjects []types.DotnetProject // Pre-filtered .NET projects (optional)
+	searchRoot string
+	projects   depsProjects     // Pre-filtered projects (optional)
+	cache      *cache.DepsCache // Skips projects whose lock files haven't changed (optional)
+	force      bool             // Installs even the projects cache reports as up to date
+	timeout    time.Duration    // Cancels an install that takes longer (0 = no limit)
 }
 
 // NewDependencyInstaller creates a new dependency installer.
@@ -32,12 +55,14 @@ func NewDependencyInstaller(searchRoot string) *DependencyInstaller {
 
 // InstallResult represents the result of installing dependencies for a project.
 type InstallResult struct {
-	Type    string `json:"type"`
-	Dir     string `json:"dir,omitempty"`
-	Path    string `json:"path,omitempty"`
-	Manager string `json:"manager,omitempty"`
-	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty"`
+	Type     string `json:"type"`
+	Dir      string `json:"dir,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Manager  string `json:"manager,omitempty"`
+	Success  bool   `json:"success"`
+	UpToDate bool   `json:"upToDate,omitempty"` // Skipped because the lock files haven't changed
+	TimedOut bool   `json:"timedOut,omitempty"` // Canceled because it took longer than --timeout
+	Error    string `json:"error,omitempty"`
 }
 
 // InstallAll installs dependencies for all detected project types.
@@ -67,6 +92,27 @@ func (di *DependencyInstaller) InstallAll() ([]InstallResult, error) {
 	}
 	results = append(results, dotnetResults...)
 
+	// Download Go modules
+	goResults, err := di.installGoProjects()
+	if err != nil {
+		detectionErrors = append(detectionErrors, fmt.Errorf("go detection: %w", err))
+	}
+	results = append(results, goResults...)
+
+	// Fetch Rust crates
+	rustResults, err := di.installRustProjects()
+	if err != nil {
+		detectionErrors = append(detectionErrors, fmt.Errorf("rust detection: %w", err))
+	}
+	results = append(results, rustResults...)
+
+	// Resolve Java dependencies
+	javaResults, err := di.installJavaProjects()
+	if err != nil {
+		detectionErrors = append(detectionErrors, fmt.Errorf("java detection: %w", err))
+	}
+	results = append(results, javaResults...)
+
 	// Return combined detection errors if any occurred
 	if len(detectionErrors) > 0 {
 		errMsgs := make([]string, len(detectionErrors))
@@ -85,23 +131,41 @@ func (di *DependencyInstaller) InstallAllFiltered() ([]InstallResult, error) {
 	var results []InstallResult
 
 	// Install Node.js dependencies from pre-filtered list
-	if len(di.nodeProjects) > 0 {
-		nodeResults := di.installNodeProjectList(di.nodeProjects)
+	if len(di.projects.Node) > 0 {
+		nodeResults := di.installNodeProjectList(di.projects.Node)
 		results = append(results, nodeResults...)
 	}
 
 	// Install Python dependencies from pre-filtered list
-	if len(di.pythonProjects) > 0 {
-		pythonResults := di.installPythonProjectList(di.pythonProjects)
+	if len(di.projects.Python) > 0 {
+		pythonResults := di.installPythonProjectList(di.projects.Python)
 		results = append(results, pythonResults...)
 	}
 
 	// Install .NET dependencies from pre-filtered list
-	if len(di.dotnetProjects) > 0 {
-		dotnetResults := di.installDotnetProjectList(di.dotnetProjects)
+	if len(di.projects.Dotnet) > 0 {
+		dotnetResults := di.installDotnetProjectList(di.projects.Dotnet)
 		results = append(results, dotnetResults...)
 	}
 
+	// Download Go modules from pre-filtered list
+	if len(di.projects.Go) > 0 {
+		goResults := di.installGoProjectList(di.projects.Go)
+		results = append(results, goResults...)
+	}
+
+	// Fetch Rust crates from pre-filtered list
+	if len(di.projects.Rust) > 0 {
+		rustResults := di.installRustProjectList(di.projects.Rust)
+		results = append(results, rustResults...)
+	}
+
+	// Resolve Java dependencies from pre-filtered list
+	if len(di.projects.Java) > 0 {
+		javaResults := di.installJavaProjectList(di.projects.Java)
+		results = append(results, javaResults...)
+	}
+
 	return results, nil
 }
 
@@ -109,8 +173,8 @@ func (di *DependencyInstaller) InstallAllFiltered() ([]InstallResult, error) {
 func (di *DependencyInstaller) installNodeProjectList(nodeProjects []types.NodeProject) []InstallResult {
 	results := make([]InstallResult, 0, len(nodeProjects))
 	for _, nodeProject := range nodeProjects {
-		result := di.installProject("node", nodeProject.Dir, nodeProject.PackageManager, func() error {
-			return installer.InstallNodeDependencies(nodeProject)
+		result := di.installProject("node", nodeProject.Dir, nodeProject.PackageManager, func(ctx context.Context) error {
+			return installer.InstallNodeDependencies(ctx, nodeProject)
 		})
 		results = append(results, result)
 	}
@@ -121,8 +185,8 @@ func (di *DependencyInstaller) installNodeProjectList(nodeProjects []types.NodeP
 func (di *DependencyInstaller) installPythonProjectList(pythonProjects []types.PythonProject) []InstallResult {
 	results := make([]InstallResult, 0, len(pythonProjects))
 	for _, pyProject := range pythonProjects {
-		result := di.installProject("python", pyProject.Dir, pyProject.PackageManager, func() error {
-			return installer.SetupPythonVirtualEnv(pyProject)
+		result := di.installProject("python", pyProject.Dir, pyProject.PackageManager, func(ctx context.Context) error {
+			return installer.SetupPythonVirtualEnv(ctx, pyProject)
 		})
 		results = append(results, result)
 	}
@@ -133,8 +197,8 @@ func (di *DependencyInstaller) installPythonProjectList(pythonProjects []types.P
 func (di *DependencyInstaller) installDotnetProjectList(dotnetProjects []types.DotnetProject) []InstallResult {
 	results := make([]InstallResult, 0, len(dotnetProjects))
 	for _, dotnetProject := range dotnetProjects {
-		result := di.installProject("dotnet", filepath.Dir(dotnetProject.Path), "dotnet", func() error {
-			return installer.RestoreDotnetProject(dotnetProject)
+		result := di.installProject("dotnet", filepath.Dir(dotnetProject.Path), "dotnet", func(ctx context.Context) error {
+			return installer.RestoreDotnetProject(ctx, dotnetProject)
 		})
 		// For dotnet, we use Path instead of Dir in the result
 		result.Path = dotnetProject.Path
@@ -144,6 +208,42 @@ func (di *DependencyInstaller) installDotnetProjectList(dotnetProjects []types.D
 	return results
 }
 
+// installGoProjectList downloads modules for a list of Go projects.
+func (di *DependencyInstaller) installGoProjectList(goProjects []detector.GoProject) []InstallResult {
+	results := make([]InstallResult, 0, len(goProjects))
+	for _, goProject := range goProjects {
+		result := di.installProject("go", goProject.Dir, "go", func(ctx context.Context) error {
+			return installer.DownloadGoModules(ctx, goProject)
+		})
+		results = append(results, result)
+	}
+	return results
+}
+
+// installRustProjectList fetches crates for a list of Rust projects.
+func (di *DependencyInstaller) installRustProjectList(rustProjects []detector.RustProject) []InstallResult {
+	results := make([]InstallResult, 0, len(rustProjects))
+	for _, rustProject := range rustProjects {
+		result := di.installProject("rust", rustProject.Dir, "cargo", func(ctx context.Context) error {
+			return installer.FetchRustDependencies(ctx, rustProject)
+		})
+		results = append(results, result)
+	}
+	return results
+}
+
+// installJavaProjectList resolves dependencies for a list of Java projects.
+func (di *DependencyInstaller) installJavaProjectList(javaProjects []detector.JavaProject) []InstallResult {
+	results := make([]InstallResult, 0, len(javaProjects))
+	for _, javaProject := range javaProjects {
+		result := di.installProject("java", javaProject.Dir, javaProject.BuildTool, func(ctx context.Context) error {
+			return installer.ResolveJavaDependencies(ctx, javaProject)
+		})
+		results = append(results, result)
+	}
+	return results
+}
+
 // installNodeProjects installs dependencies for Node.js projects.
 func (di *DependencyInstaller) installNodeProjects() ([]InstallResult, error) {
 	nodeProjects, err := detector.FindNodeProjects(di.searchRoot)
@@ -151,14 +251,17 @@ func (di *DependencyInstaller) installNodeProjects() ([]InstallResult, error) {
 		return nil, err
 	}
 
+	// Workspace packages are installed by their workspace root
+	nodeProjects = workspace.NewHandler().FilterNodeProjects(nodeProjects)
+
 	if !cliout.IsJSON() {
 		cliout.Step("📦", "Found %s Node.js project(s)", cliout.Count(len(nodeProjects)))
 	}
 
 	results := make([]InstallResult, 0, len(nodeProjects))
 	for _, nodeProject := range nodeProjects {
-		result := di.installProject("node", nodeProject.Dir, nodeProject.PackageManager, func() error {
-			return installer.InstallNodeDependencies(nodeProject)
+		result := di.installProject("node", nodeProject.Dir, nodeProject.PackageManager, func(ctx context.Context) error {
+			return installer.InstallNodeDependencies(ctx, nodeProject)
 		})
 		results = append(results, result)
 	}
@@ -183,8 +286,8 @@ func (di *DependencyInstaller) installPythonProjects() ([]InstallResult, error)
 
 	results := make([]InstallResult, 0, len(pythonProjects))
 	for _, pyProject := range pythonProjects {
-		result := di.installProject("python", pyProject.Dir, pyProject.PackageManager, func() error {
-			return installer.SetupPythonVirtualEnv(pyProject)
+		result := di.installProject("python", pyProject.Dir, pyProject.PackageManager, func(ctx context.Context) error {
+			return installer.SetupPythonVirtualEnv(ctx, pyProject)
 		})
 		results = append(results, result)
 	}
@@ -213,7 +316,7 @@ func (di *DependencyInstaller) installDotnetProjects() ([]InstallResult, error)
 			Type: "dotnet",
 			Path: dotnetProject.Path,
 		}
-		if err := installer.RestoreDotnetProject(dotnetProject); err != nil {
+		if err := installer.RestoreDotnetProject(context.Background(), dotnetProject); err != nil {
 			if !cliout.IsJSON() {
 				cliout.ItemWarning("Failed to restore %s: %v", dotnetProject.Path, err)
 			}
@@ -232,55 +335,125 @@ func (di *DependencyInstaller) installDotnetProjects() ([]InstallResult, error)
 	return results, nil
 }
 
+// installGoProjects downloads modules for Go projects.
+func (di *DependencyInstaller) installGoProjects() ([]InstallResult, error) {
+	goProjects, err := detector.FindGoProjects(di.searchRoot)
+	if err != nil || len(goProjects) == 0 {
+		return nil, err
+	}
+
+	if !cliout.IsJSON() {
+		cliout.Step("🐹", "Found %s Go project(s)", cliout.Count(len(goProjects)))
+	}
+
+	results := di.installGoProjectList(goProjects)
+
+	if !cliout.IsJSON() {
+		cliout.Newline()
+	}
+
+	return results, nil
+}
+
+// installRustProjects fetches crates for Rust projects.
+func (di *DependencyInstaller) installRustProjects() ([]InstallResult, error) {
+	rustProjects, err := detector.FindRustProjects(di.searchRoot)
+	if err != nil || len(rustProjects) == 0 {
+		return nil, err
+	}
+
+	if !cliout.IsJSON() {
+		cliout.Step("🦀", "Found %s Rust project(s)", cliout.Count(len(rustProjects)))
+	}
+
+	results := di.installRustProjectList(rustProjects)
+
+	if !cliout.IsJSON() {
+		cliout.Newline()
+	}
+
+	return results, nil
+}
+
+// installJavaProjects resolves dependencies for Java projects.
+func (di *DependencyInstaller) installJavaProjects() ([]InstallResult, error) {
+	javaProjects, err := detector.FindJavaProjects(di.searchRoot)
+	if err != nil || len(javaProjects) == 0 {
+		return nil, err
+	}
+
+	if !cliout.IsJSON() {
+		cliout.Step("☕", "Found %s Java project(s)", cliout.Count(len(javaProjects)))
+	}
+
+	results := di.installJavaProjectList(javaProjects)
+
+	if !cliout.IsJSON() {
+		cliout.Newline()
+	}
+
+	return results, nil
+}
+
 // installProject installs dependencies for a single project.
-func (di *DependencyInstaller) installProject(projectType, dir, manager string, installFunc func() error) InstallResult {
+func (di *DependencyInstaller) installProject(projectType, dir, manager string, installFunc func(context.Context) error) InstallResult {
 	result := InstallResult{
 		Type:    projectType,
 		Dir:     dir,
 		Manager: manager,
 	}
 
+	relDir := dir
+	if rel, err := filepath.Rel(di.searchRoot, dir); err == nil && rel != "." {
+		relDir = rel
+	}
+
+	key := installer.CacheKey(projectType, dir)
+	if di.cache != nil && !di.force &&
+		di.cache.UpToDate(key, installer.Fingerprint(projectType, dir, manager)) &&
+		installer.DependenciesPresent(projectType, dir, manager) {
+		if !cliout.IsJSON() {
+			cliout.Item("%s (%s): up to date", relDir, manager)
+		}
+		result.Success = true
+		result.UpToDate = true
+		return result
+	}
+
 	// Show which project we're installing
 	if !cliout.IsJSON() {
-		relDir := dir
-		if rel, err := filepath.Rel(di.searchRoot, dir); err == nil && rel != "." {
-			relDir = rel
-		}
 		cliout.Item("Installing %s (%s)", relDir, manager)
 	}
 
-	if err := installFunc(); err != nil {
+	if err := installer.RunWithTimeout(context.Background(), di.timeout, installFunc); err != nil {
 		if !cliout.IsJSON() {
 			cliout.ItemWarning("Failed to install for %s: %v", dir, err)
 		}
 		result.Success = false
+		result.TimedOut = installer.IsTimeout(err)
 		result.Error = err.Error()
+		di.cache.Record(key, "")
 	} else {
 		result.Success = true
+		di.cache.Record(key, installer.Fingerprint(projectType, dir, manager))
 	}
 	return result
 }
 
 // filterProjectsByService filters projects to only include those matching the specified service names.
-func filterProjectsByService(
-	nodeProjects []types.NodeProject,
-	pythonProjects []types.PythonProject,
-	dotnetProjects []types.DotnetProject,
-	services []string,
-	searchRoot string,
-) ([]types.NodeProject, []types.PythonProject, []types.DotnetProject) {
+func filterProjectsByService(projects depsProjects, services []string, searchRoot string) depsProjects {
 	// Build a set of service paths from azure.yaml
 	servicePaths := make(map[string]bool)
 
 	azureYamlPath, err := detector.FindAzureYaml(searchRoot)
 	if err != nil || azureYamlPath == "" {
 		// No azure.yaml found, can't filter by service
-		return nodeProjects, pythonProjects, dotnetProjects
+		return projects
 	}
 
 	azureYaml, err := parseAzureYaml(azureYamlPath)
 	if err != nil {
-		return nodeProjects, pythonProjects, dotnetProjects
+		return projects
 	}
 
 	azureYamlDir := filepath.Dir(azureYamlPath)
@@ -305,64 +478,124 @@ func filterProjectsByService(
 		}
 	}
 
-	// Filter Node.js projects
-	var filteredNode []types.NodeProject
-	for _, p := range nodeProjects {
+	return filterProjectsByPath(projects, servicePaths)
+}
+
+// filterProjectsByPath filters projects to only include those in, or inside, one of the absolute paths.
+func filterProjectsByPath(projects depsProjects, paths map[string]bool) depsProjects {
+	var filtered depsProjects
+
+	// Filter Node.js projects. A workspace root is kept when a filtered path is one of its packages,
+	// since the workspace is installed as a whole.
+	for _, p := range projects.Node {
 		absDir, _ := filepath.Abs(p.Dir)
-		if servicePaths[absDir] || isSubdirectory(absDir, servicePaths) {
-			filteredNode = append(filteredNode, p)
+		if paths[absDir] || isSubdirectory(absDir, paths) || (p.IsWorkspaceRoot && containsAnyPath(absDir, paths)) {
+			filtered.Node = append(filtered.Node, p)
 		}
 	}
 
 	// Filter Python projects
-	var filteredPython []types.PythonProject
-	for _, p := range pythonProjects {
+	for _, p := range projects.Python {
 		absDir, _ := filepath.Abs(p.Dir)
-		if servicePaths[absDir] || isSubdirectory(absDir, servicePaths) {
-			filteredPython = append(filteredPython, p)
+		if paths[absDir] || isSubdirectory(absDir, paths) {
+			filtered.Python = append(filtered.Python, p)
 		}
 	}
 
 	// Filter .NET projects
-	var filteredDotnet []types.DotnetProject
-	for _, p := range dotnetProjects {
+	for _, p := range projects.Dotnet {
 		absPath, _ := filepath.Abs(p.Path)
 		absDir := filepath.Dir(absPath)
-		if servicePaths[absDir] || isSubdirectory(absDir, servicePaths) {
-			filteredDotnet = append(filteredDotnet, p)
+		if paths[absDir] || isSubdirectory(absDir, paths) {
+			filtered.Dotnet = append(filtered.Dotnet, p)
+		}
+	}
+
+	// Filter Go projects
+	for _, p := range projects.Go {
+		absDir, _ := filepath.Abs(p.Dir)
+		if paths[absDir] || isSubdirectory(absDir, paths) {
+			filtered.Go = append(filtered.Go, p)
+		}
+	}
+
+	// Filter Rust projects. A workspace root is kept when a filtered path is one of its crates,
+	// since the workspace is fetched as a whole.
+	for _, p := range projects.Rust {
+		absDir, _ := filepath.Abs(p.Dir)
+		if paths[absDir] || isSubdirectory(absDir, paths) || (p.Workspace && containsAnyPath(absDir, paths)) {
+			filtered.Rust = append(filtered.Rust, p)
+		}
+	}
+
+	// Filter Java projects
+	for _, p := range projects.Java {
+		absDir, _ := filepath.Abs(p.Dir)
+		if paths[absDir] || isSubdirectory(absDir, paths) {
+			filtered.Java = append(filtered.Java, p)
 		}
 	}
 
-	return filteredNode, filteredPython, filteredDotnet
+	return filtered
+}
+
+// depsProjectTypes are the project types accepted by deps --only.
+var depsProjectTypes = []string{"node", "python", "dotnet", "go", "rust", "java"}
+
+// filterProjectsByType filters projects to only include those of the given project types.
+func filterProjectsByType(projects depsProjects, only []string) depsProjects {
+	include := make(map[string]bool, len(only))
+	for _, projectType := range only {
+		include[projectType] = true
+	}
+	if !include["node"] {
+		projects.Node = nil
+	}
+	if !include["python"] {
+		projects.Python = nil
+	}
+	if !include["dotnet"] {
+		projects.Dotnet = nil
+	}
+	if !include["go"] {
+		projects.Go = nil
+	}
+	if !include["rust"] {
+		projects.Rust = nil
+	}
+	if !include["java"] {
+		projects.Java = nil
+	}
+	return projects
 }
 
 // detectProjectsFromAzureYaml reads azure.yaml and detects project types directly from
 // service project paths, without walking the entire directory tree.
 // Returns an error if no azure.yaml is found or no services are defined.
-func detectProjectsFromAzureYaml(searchRoot string) ([]types.NodeProject, []types.PythonProject, []types.DotnetProject, error) {
+func detectProjectsFromAzureYaml(searchRoot string) (depsProjects, error) {
 	azureYamlPath, err := detector.FindAzureYaml(searchRoot)
 	if err != nil || azureYamlPath == "" {
-		return nil, nil, nil, fmt.Errorf("azure.yaml not found - create one with a 'services' section to define your development environment")
+		return depsProjects{}, fmt.Errorf("azure.yaml not found - create one with a 'services' section to define your development environment")
 	}
 
 	azureYaml, err := service.ParseAzureYaml(filepath.Dir(azureYamlPath))
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to parse azure.yaml: %w", err)
+		return depsProjects{}, fmt.Errorf("failed to parse azure.yaml: %w", err)
 	}
 
 	if !service.HasServices(azureYaml) {
-		return nil, nil, nil, fmt.Errorf("no services defined in azure.yaml - add a 'services' section to define your development environment")
+		return depsProjects{}, fmt.Errorf("no services defined in azure.yaml - add a 'services' section to define your development environment")
 	}
 
 	// Resolve the project root to an absolute path for containment checks
 	absSearchRoot, err := filepath.Abs(searchRoot)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to resolve project root: %w", err)
+		return depsProjects{}, fmt.Errorf("failed to resolve project root: %w", err)
 	}
 
-	var nodeProjects []types.NodeProject
-	var pythonProjects []types.PythonProject
-	var dotnetProjects []types.DotnetProject
+	var projects depsProjects
+	workspaceDirs := make(map[string]bool)
+	rustDirs := make(map[string]bool)
 
 	for _, svc := range azureYaml.Services {
 		projectDir := svc.Project
@@ -373,23 +606,40 @@ func detectProjectsFromAzureYaml(searchRoot string) ([]types.NodeProject, []type
 		// Validate the project path stays within the project root (prevent path traversal)
 		absProjectDir, err := filepath.Abs(projectDir)
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to resolve service project path %q: %w", projectDir, err)
+			return depsProjects{}, fmt.Errorf("failed to resolve service project path %q: %w", projectDir, err)
+		}
+		// Services referenced from another project are contained by that project's root instead
+		root := absSearchRoot
+		if svc.RefRoot != "" {
+			root = svc.RefRoot
 		}
-		rel, err := filepath.Rel(absSearchRoot, absProjectDir)
+		rel, err := filepath.Rel(root, absProjectDir)
 		if err != nil || strings.HasPrefix(rel, "..") {
-			return nil, nil, nil, fmt.Errorf("service project path %q resolves outside the project root - check the 'project' path in azure.yaml", projectDir)
+			return depsProjects{}, fmt.Errorf("service project path %q resolves outside the project root - check the 'project' path in azure.yaml", projectDir)
 		}
 
 		// Verify the project directory exists
 		if _, err := os.Stat(projectDir); os.IsNotExist(err) {
-			return nil, nil, nil, fmt.Errorf("service project directory %q does not exist - check the 'project' path in azure.yaml", projectDir)
+			return depsProjects{}, fmt.Errorf("service project directory %q does not exist - check the 'project' path in azure.yaml", projectDir)
 		}
 
-		// Check for Node.js project (package.json)
-		if _, err := os.Stat(filepath.Join(projectDir, "package.json")); err == nil {
+		// Check for Node.js project (package.json), or a Deno project (deno.json). Packages in the
+		// same workspace share one install from the workspace root.
+		if _, err := os.Stat(filepath.Join(projectDir, "package.json")); err == nil || detector.HasDenoConfig(projectDir) {
+			if workspace := detector.FindNodeWorkspace(projectDir, root); workspace != nil && detector.HasPackageJson(projectDir) {
+				if !workspaceDirs[workspace.Root] {
+					workspaceDirs[workspace.Root] = true
+					projects.Node = append(projects.Node, types.NodeProject{
+						Dir:             workspace.Root,
+						PackageManager:  workspace.PackageManager,
+						IsWorkspaceRoot: true,
+					})
+				}
+				continue
+			}
 			pm := detector.DetectNodePackageManager(projectDir)
 			isWorkspaceRoot := detector.HasNpmWorkspaces(projectDir)
-			nodeProjects = append(nodeProjects, types.NodeProject{
+			projects.Node = append(projects.Node, types.NodeProject{
 				Dir:             projectDir,
 				PackageManager:  pm,
 				IsWorkspaceRoot: isWorkspaceRoot,
@@ -398,7 +648,7 @@ func detectProjectsFromAzureYaml(searchRoot string) ([]types.NodeProject, []type
 		}
 
 		// Check for Python project
-		pythonFiles := []string{"requirements.txt", "pyproject.toml", "poetry.lock", "uv.lock", "Pipfile"}
+		pythonFiles := []string{"requirements.txt", "pyproject.toml", "poetry.lock", "uv.lock", "Pipfile", "environment.yml", "environment.yaml"}
 		isPython := false
 		for _, f := range pythonFiles {
 			if _, err := os.Stat(filepath.Join(projectDir, f)); err == nil {
@@ -408,13 +658,37 @@ func detectProjectsFromAzureYaml(searchRoot string) ([]types.NodeProject, []type
 		}
 		if isPython {
 			pm := detector.DetectPythonPackageManager(projectDir)
-			pythonProjects = append(pythonProjects, types.PythonProject{
+			installer.SetPythonInterpreter(projectDir, svc.PythonInterpreter())
+			projects.Python = append(projects.Python, types.PythonProject{
 				Dir:            projectDir,
 				PackageManager: pm,
 			})
 			continue
 		}
 
+		// Check for Go project (go.mod)
+		if detector.HasGoMod(projectDir) {
+			projects.Go = append(projects.Go, detector.GoProject{Dir: projectDir})
+			continue
+		}
+
+		// Check for Rust project (Cargo.toml). Crates in the same workspace share one fetch
+		// from the workspace root.
+		if detector.HasCargoToml(projectDir) {
+			rustDir := detector.CargoWorkspaceRoot(projectDir, root)
+			if !rustDirs[rustDir] {
+				rustDirs[rustDir] = true
+				projects.Rust = append(projects.Rust, detector.RustProject{Dir: rustDir, Workspace: detector.IsCargoWorkspace(rustDir)})
+			}
+			continue
+		}
+
+		// Check for Java project (pom.xml or build.gradle)
+		if buildTool := detector.DetectJavaBuildTool(projectDir); buildTool != "" {
+			projects.Java = append(projects.Java, detector.JavaProject{Dir: projectDir, BuildTool: buildTool})
+			continue
+		}
+
 		// Check for .NET project (*.csproj or *.sln in the directory)
 		entries, err := os.ReadDir(projectDir)
 		if err == nil {
@@ -424,7 +698,7 @@ func detectProjectsFromAzureYaml(searchRoot string) ([]types.NodeProject, []type
 				}
 				ext := filepath.Ext(entry.Name())
 				if ext == ".csproj" || ext == ".sln" {
-					dotnetProjects = append(dotnetProjects, types.DotnetProject{
+					projects.Dotnet = append(projects.Dotnet, types.DotnetProject{
 						Path: filepath.Join(projectDir, entry.Name()),
 					})
 					break
@@ -433,7 +707,7 @@ func detectProjectsFromAzureYaml(searchRoot string) ([]types.NodeProject, []type
 		}
 	}
 
-	return nodeProjects, pythonProjects, dotnetProjects, nil
+	return projects, nil
 }
 
 // isSubdirectory checks if path is a subdirectory of any path in the set.
@@ -461,35 +735,74 @@ func isSubdirectory(path string, parentPaths map[string]bool) bool {
 	return false
 }
 
+// containsAnyPath checks if any path in the set is dir or inside it.
+func containsAnyPath(dir string, paths map[string]bool) bool {
+	for path := range paths {
+		if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
 // runParallelInstallation runs the parallel installer for non-JSON mode.
-func runParallelInstallation(nodeProjects []types.NodeProject, pythonProjects []types.PythonProject, dotnetProjects []types.DotnetProject, verbose bool) error {
+// Install durations are recorded under searchRoot/.azure so later runs can show ETAs.
+func runParallelInstallation(searchRoot string, projects depsProjects) error {
 	parallelInstaller := installer.NewParallelInstaller()
-	parallelInstaller.Verbose = verbose
+	parallelInstaller.Verbose = output.IsVerbose()
+	parallelInstaller.History = eta.Load(searchRoot)
+	opts := GetDepsOptions()
+	parallelInstaller.MaxConcurrency = opts.Concurrency
+	if parallelInstaller.MaxConcurrency == 0 {
+		parallelInstaller.MaxConcurrency = teamconfig.ForProject(searchRoot).Concurrency
+	}
+	parallelInstaller.Timeout = opts.Timeout
+	parallelInstaller.Cache = cache.LoadDepsCache(searchRoot)
+	parallelInstaller.Force = opts.NoCache
 
 	// Handle npm/yarn/pnpm workspace scenarios using workspace handler
 	// When a workspace root exists, only install at the root level to avoid race conditions
 	// on Windows where parallel npm installs compete for the same node_modules directory
 	workspaceHandler := workspace.NewHandler()
-	filteredNodeProjects := workspaceHandler.FilterNodeProjects(nodeProjects)
+	filteredNodeProjects := workspaceHandler.FilterNodeProjects(projects.Node)
 
 	for _, project := range filteredNodeProjects {
 		parallelInstaller.AddNodeProject(project)
 	}
-	for _, project := range pythonProjects {
+	for _, project := range projects.Python {
 		parallelInstaller.AddPythonProject(project)
 	}
-	for _, project := range dotnetProjects {
+	for _, project := range projects.Dotnet {
 		parallelInstaller.AddDotnetProject(project)
 	}
+	for _, project := range projects.Go {
+		parallelInstaller.AddGoProject(project)
+	}
+	for _, project := range projects.Rust {
+		parallelInstaller.AddRustProject(project)
+	}
+	for _, project := range projects.Java {
+		parallelInstaller.AddJavaProject(project)
+	}
 
 	// Run all installations in parallel
 	if err := parallelInstaller.Run(); err != nil {
 		return err
 	}
 
+	if err := parallelInstaller.History.Save(); err != nil {
+		slog.Debug("failed to save install timing history", "error", err)
+	}
+	if err := parallelInstaller.Cache.Save(); err != nil {
+		slog.Debug("failed to save deps cache", "error", err)
+	}
+
 	// Check for failures
 	if parallelInstaller.HasFailures() {
 		failedProjects := parallelInstaller.FailedProjects()
+		if timedOut := parallelInstaller.TimedOutProjects(); len(timedOut) > 0 {
+			return fmt.Errorf("failed to install %d of %d projects: %v (timed out after %s: %v)", len(failedProjects), parallelInstaller.TotalProjects(), failedProjects, opts.Timeout, timedOut)
+		}
 		if len(failedProjects) > 0 {
 			return fmt.Errorf("failed to install %d of %d projects: %v", len(failedProjects), parallelInstaller.TotalProjects(), failedProjects)
 		}
@@ -500,13 +813,18 @@ func runParallelInstallation(nodeProjects []types.NodeProject, pythonProjects []
 }
 
 // runJSONInstallation runs installation in JSON mode with sequential cliout.
-func runJSONInstallation(searchRoot string, nodeProjects []types.NodeProject, pythonProjects []types.PythonProject, dotnetProjects []types.DotnetProject) error {
+func runJSONInstallation(searchRoot string, projects depsProjects) error {
 	depInstaller := NewDependencyInstaller(searchRoot)
-	depInstaller.nodeProjects = nodeProjects
-	depInstaller.pythonProjects = pythonProjects
-	depInstaller.dotnetProjects = dotnetProjects
+	depInstaller.projects = projects
+	opts := GetDepsOptions()
+	depInstaller.cache = cache.LoadDepsCache(searchRoot)
+	depInstaller.force = opts.NoCache
+	depInstaller.timeout = opts.Timeout
 
 	results, err := depInstaller.InstallAllFiltered()
+	if err := depInstaller.cache.Save(); err != nil {
+		slog.Debug("failed to save deps cache", "error", err)
+	}
 	if err != nil {
 		return err
 	}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/jongio/azd-app/cli/src/internal/cache"
@@ -79,13 +82,80 @@ func loadAzureYaml() (string, *AzureYaml, error) {
 	}
 
 	var azureYaml AzureYaml
-	if err := yaml.Unmarshal(data, &azureYaml); err != nil {
+	if err := service.UnmarshalAzureYaml(data, &azureYaml); err != nil {
 		return "", nil, fmt.Errorf("failed to parse azure.yaml: %w", err)
 	}
 
+	// Tool paths are relative to azure.yaml
+	azureYaml.resolveToolPaths(filepath.Dir(azureYamlPath))
+
+	// The selected profile's reqs replace base reqs of the same name
+	azureYaml.applyProfile(service.ActiveProfile())
+
+	// Referenced services bring their own project's requirements
+	mergeReferencedReqs(&azureYaml, filepath.Dir(azureYamlPath), map[string]bool{azureYamlPath: true})
+
 	return azureYamlPath, &azureYaml, nil
 }
 
+// mergeReferencedReqs appends the reqs of projects referenced by `ref` services to those
+// services, so they are only checked when the service is selected. Requirements already
+// declared at the top level or by the service (by name, case-insensitive) are kept as-is.
+// Unreadable referenced projects are skipped; `run` reports ref errors in detail.
+func mergeReferencedReqs(azureYaml *AzureYaml, azureYamlDir string, visited map[string]bool) {
+	for name, svc := range azureYaml.Services {
+		if svc.Ref == "" {
+			continue
+		}
+		ref, err := service.ParseServiceRef(svc.Ref)
+		if err != nil {
+			continue
+		}
+
+		refDir := ref.ProjectDir(azureYamlDir)
+		refYamlPath := filepath.Join(refDir, "azure.yaml")
+		if visited[refYamlPath] {
+			continue
+		}
+
+		// #nosec G304 -- Path is derived from a ref declared in the user's azure.yaml
+		data, err := os.ReadFile(refYamlPath)
+		if err != nil {
+			continue
+		}
+		var refYaml AzureYaml
+		if err := service.UnmarshalAzureYaml(data, &refYaml); err != nil {
+			continue
+		}
+		refYaml.resolveToolPaths(refDir)
+		visited[refYamlPath] = true
+		mergeReferencedReqs(&refYaml, refDir, visited)
+		// Only the current chain of refs is tracked, so every ref into a project gets its reqs
+		delete(visited, refYamlPath)
+		refReqs, err := refYaml.requirementsFor(nil)
+		if err != nil {
+			continue
+		}
+
+		for _, req := range refReqs {
+			if !hasPrerequisite(azureYaml.Reqs, req.Name) && !hasPrerequisite(svc.Reqs, req.Name) {
+				svc.Reqs = append(svc.Reqs, req)
+			}
+		}
+		azureYaml.Services[name] = svc
+	}
+}
+
+// hasPrerequisite reports whether reqs contains a requirement with the given name.
+func hasPrerequisite(reqs []Prerequisite, name string) bool {
+	for _, r := range reqs {
+		if strings.EqualFold(r.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // handleDepsError returns an error with JSON output if in JSON mode.
 func handleDepsError(err error, message string) error {
 	fullErr := fmt.Errorf("%s: %w", message, err)
@@ -124,10 +194,11 @@ func checkAllSuccess(results []InstallResult) bool {
 }
 
 // checkRequirementsWithCache checks requirements with cache support.
-func checkRequirementsWithCache(reqs []Prerequisite, azureYamlPath string, cacheManager *cache.CacheManager) ([]ReqResult, bool) {
+// scope identifies the services the requirements belong to (see reqsCacheScope).
+func checkRequirementsWithCache(reqs []Prerequisite, azureYamlPath, scope string, cacheManager *cache.CacheManager) ([]ReqResult, bool) {
 	// Try cache first if enabled
 	if cacheManager.IsEnabled() {
-		if results, allSatisfied, ok := tryGetCachedResults(azureYamlPath, cacheManager); ok {
+		if results, allSatisfied, ok := tryGetCachedResults(reqs, azureYamlPath, scope, cacheManager); ok {
 			return results, allSatisfied
 		}
 	}
@@ -137,15 +208,28 @@ func checkRequirementsWithCache(reqs []Prerequisite, azureYamlPath string, cache
 
 	// Save to cache if enabled
 	if cacheManager.IsEnabled() {
-		saveToCache(azureYamlPath, results, allSatisfied, cacheManager)
+		saveToCache(azureYamlPath, scope, results, allSatisfied, cacheManager)
 	}
 
 	return results, allSatisfied
 }
 
-// tryGetCachedResults attempts to retrieve and use cached results.
-func tryGetCachedResults(azureYamlPath string, cacheManager *cache.CacheManager) ([]ReqResult, bool, bool) {
-	cachedResults, valid, err := cacheManager.GetCachedResults(azureYamlPath)
+// reqsCacheScope returns the cache scope for requirements checked for the given services
+// with the given profile applied.
+func reqsCacheScope(services []string, profile string) string {
+	sorted := slices.Clone(services)
+	sort.Strings(sorted)
+	scope := strings.Join(sorted, ",")
+	if profile != "" {
+		scope = "profile:" + profile + ";" + scope
+	}
+	return scope
+}
+
+// tryGetCachedResults attempts to retrieve and use cached results. Results whose tool was
+// installed, removed, upgraded or now resolves to another executable on PATH are checked again.
+func tryGetCachedResults(reqs []Prerequisite, azureYamlPath, scope string, cacheManager *cache.CacheManager) ([]ReqResult, bool, bool) {
+	cachedResults, valid, err := cacheManager.GetScopedResults(azureYamlPath, scope)
 	if err != nil {
 		// Log cache read errors in both JSON and non-JSON modes for visibility
 		if !cliout.IsJSON() {
@@ -158,21 +242,57 @@ func tryGetCachedResults(azureYamlPath string, cacheManager *cache.CacheManager)
 		return nil, false, false // Cache miss
 	}
 
+	// Convert cached results
+	results := convertCachedResults(cachedResults.Results)
+
+	rechecked, ok := revalidateCachedResults(NewPrerequisiteChecker(), reqs, results)
+	if !ok {
+		return nil, false, false // Results don't match the requirements, treat as a miss
+	}
+	allSatisfied := cachedResults.AllPassed
+	if rechecked > 0 {
+		allSatisfied = requiredSatisfied(results)
+		saveToCache(azureYamlPath, scope, results, allSatisfied, cacheManager)
+	}
+
 	// Cache hit
 	if !cliout.IsJSON() {
-		cliout.Info("Using cached reqs check results...")
+		if rechecked > 0 {
+			cliout.Info("Using cached reqs check results (%d re-checked after tool changes)...", rechecked)
+		} else {
+			cliout.Info("Using cached reqs check results...")
+		}
 	}
 
-	// Convert cached results
-	results := convertCachedResults(cachedResults.Results)
-
 	// Print cached results
 	if !cliout.IsJSON() {
 		formatter := NewResultFormatter()
 		formatter.PrintAll(results)
 	}
 
-	return results, cachedResults.AllPassed, true
+	return results, allSatisfied, true
+}
+
+// revalidateCachedResults checks again, in place, every cached result whose tool no longer
+// resolves to the same executable with the same modification time. It returns how many results
+// were checked again, and false when the results don't line up with reqs.
+func revalidateCachedResults(checker *PrerequisiteChecker, reqs []Prerequisite, results []ReqResult) (int, bool) {
+	if len(results) != len(reqs) {
+		return 0, false
+	}
+
+	rechecked := 0
+	for i, req := range reqs {
+		if results[i].Name != req.Name {
+			return 0, false
+		}
+		if checker.resolveBinary(req).equal(results[i].binary) {
+			continue
+		}
+		results[i] = checker.Evaluate(req)
+		rechecked++
+	}
+	return rechecked, true
 }
 
 // convertCachedResults converts cached results to ReqResult format.
@@ -188,13 +308,16 @@ func convertCachedResults(cached []cache.CachedReqResult) []ReqResult {
 			Running:    c.Running,
 			CheckedRun: c.CheckedRun,
 			Message:    c.Message,
+			Issues:     c.Issues,
+			Severity:   normalizeSeverity(c.Severity),
+			binary:     toolBinary{Path: c.BinaryPath, ModTime: c.BinaryModTime},
 		}
 	}
 	return results
 }
 
 // saveToCache saves results to cache with error handling.
-func saveToCache(azureYamlPath string, results []ReqResult, allSatisfied bool, cacheManager *cache.CacheManager) {
+func saveToCache(azureYamlPath, scope string, results []ReqResult, allSatisfied bool, cacheManager *cache.CacheManager) {
 	cacheResults := make([]cache.CachedReqResult, len(results))
 	for i, result := range results {
 		cacheResults[i] = cache.CachedReqResult{
@@ -206,29 +329,98 @@ func saveToCache(azureYamlPath string, results []ReqResult, allSatisfied bool, c
 			Running:    result.Running,
 			CheckedRun: result.CheckedRun,
 			Message:    result.Message,
+			Issues:     result.Issues,
+			Severity:   result.Severity,
+			// Lets the next run detect tools that were installed or upgraded since
+			BinaryPath:    result.binary.Path,
+			BinaryModTime: result.binary.ModTime,
 		}
 	}
 
-	if err := cacheManager.SaveResults(azureYamlPath, cacheResults, allSatisfied); err != nil && !cliout.IsJSON() {
+	if err := cacheManager.SaveScopedResults(azureYamlPath, scope, cacheResults, allSatisfied); err != nil && !cliout.IsJSON() {
 		cliout.Warning("Failed to save cache: %v", err)
 	}
 }
 
-// performReqsCheck performs fresh reqs checking.
+// performReqsCheck performs fresh reqs checking. Up to execContext.Concurrency checks run at
+// once (0 = one per CPU); results are printed and returned in the order of reqs. The check
+// passes when every required requirement is satisfied.
 func performReqsCheck(reqs []Prerequisite) ([]ReqResult, bool) {
 	checker := NewPrerequisiteChecker()
-	results := make([]ReqResult, 0, len(reqs))
-	allSatisfied := true
+	results := make([]ReqResult, len(reqs))
+	done := make([]chan struct{}, len(reqs))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	jobs := make(chan int)
+	for w := 0; w < reqsCheckWorkers(len(reqs)); w++ {
+		go func() {
+			for i := range jobs {
+				results[i] = checker.Evaluate(reqs[i])
+				close(done[i])
+			}
+		}()
+	}
+	go func() {
+		for i := range reqs {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	// Print each result once it and all results before it are ready, so the output
+	// order doesn't depend on which check finishes first
+	formatter := NewResultFormatter()
+	for i := range reqs {
+		<-done[i]
+		if !cliout.IsJSON() {
+			formatter.Print(results[i])
+		}
+	}
+
+	return results, requiredSatisfied(results)
+}
 
-	for _, prereq := range reqs {
-		result := checker.Check(prereq)
-		results = append(results, result)
-		if !result.Satisfied {
-			allSatisfied = false
+// requiredSatisfied reports whether every required result is satisfied. Unsatisfied
+// recommended and optional results don't fail the check.
+func requiredSatisfied(results []ReqResult) bool {
+	for _, result := range results {
+		if !result.Satisfied && normalizeSeverity(result.Severity) == SeverityRequired {
+			return false
 		}
 	}
+	return true
+}
 
-	return results, allSatisfied
+// summarizeReqs counts the results of each severity.
+func summarizeReqs(results []ReqResult) ReqsSummary {
+	var summary ReqsSummary
+	for _, result := range results {
+		counts := &summary.Required
+		switch normalizeSeverity(result.Severity) {
+		case SeverityRecommended:
+			counts = &summary.Recommended
+		case SeverityOptional:
+			counts = &summary.Optional
+		}
+		counts.Total++
+		if result.Satisfied {
+			counts.Satisfied++
+		} else {
+			counts.Unsatisfied++
+		}
+	}
+	return summary
+}
+
+// reqsCheckWorkers returns how many requirement checks run at once for count requirements.
+func reqsCheckWorkers(count int) int {
+	workers := execContext.Concurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return max(1, min(workers, count))
 }
 
 // ResultFormatter handles formatting of requirement check results.
@@ -241,17 +433,37 @@ func NewResultFormatter() *ResultFormatter {
 
 // Print formats and prints a single requirement result.
 func (rf *ResultFormatter) Print(result ReqResult) {
+	if !result.Satisfied && normalizeSeverity(result.Severity) != SeverityRequired {
+		rf.printNotRequired(result)
+		return
+	}
+
 	if !result.Installed {
 		cliout.ItemError("%s: NOT INSTALLED (required: %s)", result.Name, result.Required)
+		rf.printInstallURL(result)
 		return
 	}
 
-	if result.Version == "" {
+	switch {
+	case result.IsPodman && result.Name == toolDocker:
+		cliout.ItemSuccess("%s: %s via Podman (version check skipped)", result.Name, result.Version)
+	case result.Version == "":
 		cliout.ItemWarning("%s: INSTALLED (version unknown, required: %s)", result.Name, result.Required)
-	} else if !result.Satisfied && !result.CheckedRun {
+	case result.invalidConstraint:
+		cliout.ItemError("%s: %s", result.Name, result.Message)
+		return
+	case len(result.Issues) > 0:
 		cliout.ItemError("%s: %s (required: %s)", result.Name, result.Version, result.Required)
+		if result.CheckedRun {
+			rf.printRunningStatus(result.Running)
+		}
+		rf.printIssues(result)
 		return
-	} else {
+	case !result.Satisfied && !result.CheckedRun:
+		cliout.ItemError("%s: %s (required: %s)", result.Name, result.Version, result.Required)
+		rf.printInstallURL(result)
+		return
+	default:
 		cliout.ItemSuccess("%s: %s (required: %s)", result.Name, result.Version, result.Required)
 	}
 
@@ -261,6 +473,36 @@ func (rf *ResultFormatter) Print(result ReqResult) {
 	}
 }
 
+// printNotRequired prints an unsatisfied recommended requirement as a warning and an
+// unsatisfied optional one for information, since neither fails the check.
+func (rf *ResultFormatter) printNotRequired(result ReqResult) {
+	status := result.Message
+	if !result.Installed {
+		status = "NOT INSTALLED"
+	}
+	if normalizeSeverity(result.Severity) == SeverityRecommended {
+		cliout.ItemWarning("%s: %s (recommended: %s)", result.Name, status, result.Required)
+	} else {
+		cliout.ItemInfo("%s: %s (optional: %s)", result.Name, status, result.Required)
+	}
+	rf.printIssues(result)
+	rf.printInstallURL(result)
+}
+
+// printIssues prints the failed deeper checks of a requirement.
+func (rf *ResultFormatter) printIssues(result ReqResult) {
+	for _, issue := range result.Issues {
+		cliout.Item("   %s✗%s %s", cliout.Red, cliout.Reset, issue)
+	}
+}
+
+// printInstallURL prints where to install a missing or outdated tool, if known.
+func (rf *ResultFormatter) printInstallURL(result ReqResult) {
+	if result.InstallURL != "" {
+		cliout.Item("   Install: %s", result.InstallURL)
+	}
+}
+
 // printRunningStatus prints the running status indicator.
 func (rf *ResultFormatter) printRunningStatus(isRunning bool) {
 	if isRunning {
@@ -297,8 +539,11 @@ func cleanDependencies(nodeProjects []types.NodeProject, pythonProjects []types.
 
 	// Clean Python projects
 	for _, project := range pythonProjects {
-		venvPath := filepath.Join(project.Dir, ".venv")
-		if err := cleanDirectory(venvPath); err != nil {
+		envDir := ".venv"
+		if project.PackageManager == "conda" {
+			envDir = detector.CondaEnvDir
+		}
+		if err := cleanDirectory(filepath.Join(project.Dir, envDir)); err != nil {
 			errors = append(errors, err)
 		}
 	}
@@ -421,11 +666,11 @@ func parseAzureYaml(azureYamlPath string) (*service.AzureYaml, error) {
 }
 
 // showDryRunSummary displays what would be installed without actually installing.
-func showDryRunSummary(nodeProjects []types.NodeProject, pythonProjects []types.PythonProject, dotnetProjects []types.DotnetProject, searchRoot string) error {
+func showDryRunSummary(projects depsProjects, searchRoot string) error {
 	if cliout.IsJSON() {
 		// Build dry-run results
 		var results []InstallResult
-		for _, p := range nodeProjects {
+		for _, p := range projects.Node {
 			results = append(results, InstallResult{
 				Type:    "node",
 				Dir:     p.Dir,
@@ -433,7 +678,7 @@ func showDryRunSummary(nodeProjects []types.NodeProject, pythonProjects []types.
 				Success: true, // Would succeed (dry-run)
 			})
 		}
-		for _, p := range pythonProjects {
+		for _, p := range projects.Python {
 			results = append(results, InstallResult{
 				Type:    "python",
 				Dir:     p.Dir,
@@ -441,13 +686,37 @@ func showDryRunSummary(nodeProjects []types.NodeProject, pythonProjects []types.
 				Success: true,
 			})
 		}
-		for _, p := range dotnetProjects {
+		for _, p := range projects.Dotnet {
 			results = append(results, InstallResult{
 				Type:    "dotnet",
 				Path:    p.Path,
 				Success: true,
 			})
 		}
+		for _, p := range projects.Go {
+			results = append(results, InstallResult{
+				Type:    "go",
+				Dir:     p.Dir,
+				Manager: "go",
+				Success: true,
+			})
+		}
+		for _, p := range projects.Rust {
+			results = append(results, InstallResult{
+				Type:    "rust",
+				Dir:     p.Dir,
+				Manager: "cargo",
+				Success: true,
+			})
+		}
+		for _, p := range projects.Java {
+			results = append(results, InstallResult{
+				Type:    "java",
+				Dir:     p.Dir,
+				Manager: p.BuildTool,
+				Success: true,
+			})
+		}
 		return cliout.PrintJSON(DepsResult{
 			Success:  true,
 			Projects: results,
@@ -459,9 +728,9 @@ func showDryRunSummary(nodeProjects []types.NodeProject, pythonProjects []types.
 	cliout.Section("📋", "Dry Run - Projects that would be installed")
 	cliout.Newline()
 
-	if len(nodeProjects) > 0 {
-		cliout.Step("📦", "Node.js projects (%d)", len(nodeProjects))
-		for _, p := range nodeProjects {
+	if len(projects.Node) > 0 {
+		cliout.Step("📦", "Node.js projects (%d)", len(projects.Node))
+		for _, p := range projects.Node {
 			relDir := p.Dir
 			if rel, err := filepath.Rel(searchRoot, p.Dir); err == nil && rel != "." {
 				relDir = rel
@@ -471,9 +740,9 @@ func showDryRunSummary(nodeProjects []types.NodeProject, pythonProjects []types.
 		cliout.Newline()
 	}
 
-	if len(pythonProjects) > 0 {
-		cliout.Step("🐍", "Python projects (%d)", len(pythonProjects))
-		for _, p := range pythonProjects {
+	if len(projects.Python) > 0 {
+		cliout.Step("🐍", "Python projects (%d)", len(projects.Python))
+		for _, p := range projects.Python {
 			relDir := p.Dir
 			if rel, err := filepath.Rel(searchRoot, p.Dir); err == nil && rel != "." {
 				relDir = rel
@@ -483,9 +752,9 @@ func showDryRunSummary(nodeProjects []types.NodeProject, pythonProjects []types.
 		cliout.Newline()
 	}
 
-	if len(dotnetProjects) > 0 {
-		cliout.Step("🔷", ".NET projects (%d)", len(dotnetProjects))
-		for _, p := range dotnetProjects {
+	if len(projects.Dotnet) > 0 {
+		cliout.Step("🔷", ".NET projects (%d)", len(projects.Dotnet))
+		for _, p := range projects.Dotnet {
 			relPath := p.Path
 			if rel, err := filepath.Rel(searchRoot, p.Path); err == nil && rel != "." {
 				relPath = rel
@@ -495,7 +764,43 @@ func showDryRunSummary(nodeProjects []types.NodeProject, pythonProjects []types.
 		cliout.Newline()
 	}
 
-	total := len(nodeProjects) + len(pythonProjects) + len(dotnetProjects)
+	if len(projects.Go) > 0 {
+		cliout.Step("🐹", "Go projects (%d)", len(projects.Go))
+		for _, p := range projects.Go {
+			relDir := p.Dir
+			if rel, err := filepath.Rel(searchRoot, p.Dir); err == nil && rel != "." {
+				relDir = rel
+			}
+			cliout.Item("%s (go)", relDir)
+		}
+		cliout.Newline()
+	}
+
+	if len(projects.Rust) > 0 {
+		cliout.Step("🦀", "Rust projects (%d)", len(projects.Rust))
+		for _, p := range projects.Rust {
+			relDir := p.Dir
+			if rel, err := filepath.Rel(searchRoot, p.Dir); err == nil && rel != "." {
+				relDir = rel
+			}
+			cliout.Item("%s (cargo)", relDir)
+		}
+		cliout.Newline()
+	}
+
+	if len(projects.Java) > 0 {
+		cliout.Step("☕", "Java projects (%d)", len(projects.Java))
+		for _, p := range projects.Java {
+			relDir := p.Dir
+			if rel, err := filepath.Rel(searchRoot, p.Dir); err == nil && rel != "." {
+				relDir = rel
+			}
+			cliout.Item("%s (%s)", relDir, p.BuildTool)
+		}
+		cliout.Newline()
+	}
+
+	total := len(projects.Node) + len(projects.Python) + len(projects.Dotnet) + len(projects.Go) + len(projects.Rust) + len(projects.Java)
 	cliout.Info("Total: %d project(s) would be installed", total)
 	cliout.Info("Run without --dry-run to install dependencies")
 
@@ -1,9 +1,13 @@
 package commands
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/cache"
 	"github.com/jongio/azd-core/cliout"
@@ -153,6 +157,7 @@ func TestConvertCachedResults(t *testing.T) {
 			Running:    false,
 			CheckedRun: false,
 			Message:    "test message",
+			Severity:   SeverityRecommended,
 		},
 	}
 
@@ -172,6 +177,76 @@ func TestConvertCachedResults(t *testing.T) {
 	if !r.Satisfied {
 		t.Error("Satisfied should be true")
 	}
+	if r.Severity != SeverityRecommended {
+		t.Errorf("Severity = %q, want %q", r.Severity, SeverityRecommended)
+	}
+}
+
+func TestRevalidateCachedResults(t *testing.T) {
+	if runtime.GOOS == osWindows {
+		t.Skip("fake tool is a shell script")
+	}
+
+	binDir := t.TempDir()
+	t.Setenv("PATH", binDir)
+	tool := filepath.Join(binDir, "azd-app-fake-tool")
+	writeTool := func(version string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(tool, []byte("#!/bin/sh\necho "+version+"\n"), 0o755); err != nil {
+			t.Fatalf("failed to write tool: %v", err)
+		}
+		if err := os.Chtimes(tool, modTime, modTime); err != nil {
+			t.Fatalf("failed to set tool mod time: %v", err)
+		}
+	}
+
+	checker := NewPrerequisiteChecker()
+	reqs := []Prerequisite{{Name: "azd-app-fake-tool", MinVersion: "2.0.0", Command: "azd-app-fake-tool"}}
+
+	// Not installed when the results were cached
+	results := []ReqResult{checker.Evaluate(reqs[0])}
+	if results[0].Installed {
+		t.Fatal("tool should not be installed yet")
+	}
+
+	installed := time.Now().Add(-time.Hour)
+	writeTool("2.1.0", installed)
+	rechecked, ok := revalidateCachedResults(checker, reqs, results)
+	if !ok || rechecked != 1 {
+		t.Fatalf("revalidateCachedResults() = %d, %v after install, want 1, true", rechecked, ok)
+	}
+	if !results[0].Satisfied || results[0].Version != "2.1.0" {
+		t.Errorf("result after install = %+v, want satisfied 2.1.0", results[0])
+	}
+
+	// Unchanged executable: the cached result is kept
+	if rechecked, _ := revalidateCachedResults(checker, reqs, results); rechecked != 0 {
+		t.Errorf("revalidateCachedResults() re-checked %d results of an unchanged tool", rechecked)
+	}
+
+	// Results that went through the cache keep the executable they were checked with
+	roundTrip := convertCachedResults([]cache.CachedReqResult{{
+		Name:          results[0].Name,
+		BinaryPath:    results[0].binary.Path,
+		BinaryModTime: results[0].binary.ModTime,
+	}})
+	if rechecked, _ := revalidateCachedResults(checker, reqs, roundTrip); rechecked != 0 {
+		t.Errorf("revalidateCachedResults() re-checked %d results after a cache round trip", rechecked)
+	}
+
+	// Downgraded in place: the modification time changes
+	writeTool("1.0.0", installed.Add(time.Minute))
+	if rechecked, _ := revalidateCachedResults(checker, reqs, results); rechecked != 1 {
+		t.Errorf("revalidateCachedResults() re-checked %d results after a downgrade, want 1", rechecked)
+	}
+	if results[0].Satisfied {
+		t.Errorf("result after downgrade = %+v, want unsatisfied", results[0])
+	}
+
+	// Results cached for other requirements don't apply
+	if _, ok := revalidateCachedResults(checker, []Prerequisite{{Name: "node"}}, results); ok {
+		t.Error("revalidateCachedResults() should fail for results of other requirements")
+	}
 }
 
 func TestPerformReqsCheck(t *testing.T) {
@@ -201,6 +276,135 @@ func TestPerformReqsCheck(t *testing.T) {
 		results[0].Installed, results[0].Satisfied, allSatisfied)
 }
 
+func TestPerformReqsCheck_Order(t *testing.T) {
+	_ = cliout.SetFormat("json")
+	defer func() { _ = cliout.SetFormat("default") }()
+
+	original := execContext.Concurrency
+	defer func() { execContext.Concurrency = original }()
+	SetReqsConcurrency(3)
+
+	var reqs []Prerequisite
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("azd-app-missing-tool-%d", i)
+		reqs = append(reqs, Prerequisite{Name: name, MinVersion: "1.0.0", Command: name})
+	}
+
+	results, allSatisfied := performReqsCheck(reqs)
+	if len(results) != len(reqs) {
+		t.Fatalf("expected %d results, got %d", len(reqs), len(results))
+	}
+	for i, result := range results {
+		if result.Name != reqs[i].Name {
+			t.Errorf("results[%d].Name = %q, want %q", i, result.Name, reqs[i].Name)
+		}
+		if result.Installed {
+			t.Errorf("%s should not be installed", result.Name)
+		}
+	}
+	if allSatisfied {
+		t.Error("allSatisfied should be false when tools are missing")
+	}
+}
+
+func TestPerformReqsCheck_Severity(t *testing.T) {
+	_ = cliout.SetFormat("json")
+	defer func() { _ = cliout.SetFormat("default") }()
+
+	missing := func(name, severity string) Prerequisite {
+		return Prerequisite{Name: name, MinVersion: "1.0.0", Command: name, Severity: severity}
+	}
+
+	tests := []struct {
+		name string
+		reqs []Prerequisite
+		want bool
+	}{
+		{name: "missing required", reqs: []Prerequisite{missing("azd-app-missing-required", SeverityRequired)}, want: false},
+		{name: "missing default severity", reqs: []Prerequisite{missing("azd-app-missing-default", "")}, want: false},
+		{name: "missing recommended", reqs: []Prerequisite{missing("azd-app-missing-recommended", SeverityRecommended)}, want: true},
+		{name: "missing optional", reqs: []Prerequisite{missing("azd-app-missing-optional", SeverityOptional)}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, allSatisfied := performReqsCheck(tt.reqs)
+			if allSatisfied != tt.want {
+				t.Errorf("allSatisfied = %v, want %v", allSatisfied, tt.want)
+			}
+			if want := tt.reqs[0].severity(); results[0].Severity != want {
+				t.Errorf("Severity = %q, want %q", results[0].Severity, want)
+			}
+		})
+	}
+}
+
+func TestSummarizeReqs(t *testing.T) {
+	results := []ReqResult{
+		{Name: "node", Satisfied: true, Severity: SeverityRequired},
+		{Name: "python", Satisfied: false, Severity: SeverityRequired},
+		{Name: "dotnet", Satisfied: true},
+		{Name: "docker", Satisfied: false, Severity: SeverityRecommended},
+		{Name: "gh", Satisfied: true, Severity: SeverityOptional},
+		{Name: "helm", Satisfied: false, Severity: SeverityOptional},
+	}
+
+	want := ReqsSummary{
+		Required:    SeverityCounts{Total: 3, Satisfied: 2, Unsatisfied: 1},
+		Recommended: SeverityCounts{Total: 1, Satisfied: 0, Unsatisfied: 1},
+		Optional:    SeverityCounts{Total: 2, Satisfied: 1, Unsatisfied: 1},
+	}
+	if got := summarizeReqs(results); got != want {
+		t.Errorf("summarizeReqs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRequiredSatisfied(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []ReqResult
+		want    bool
+	}{
+		{name: "none", want: true},
+		{name: "all satisfied", results: []ReqResult{{Satisfied: true}, {Satisfied: true, Severity: SeverityOptional}}, want: true},
+		{name: "required unsatisfied", results: []ReqResult{{Satisfied: false, Severity: SeverityRequired}}, want: false},
+		{name: "unknown severity unsatisfied", results: []ReqResult{{Satisfied: false, Severity: "must"}}, want: false},
+		{name: "recommended and optional unsatisfied", results: []ReqResult{{Satisfied: true}, {Severity: SeverityRecommended}, {Severity: SeverityOptional}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requiredSatisfied(tt.results); got != tt.want {
+				t.Errorf("requiredSatisfied() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReqsCheckWorkers(t *testing.T) {
+	original := execContext.Concurrency
+	defer func() { execContext.Concurrency = original }()
+
+	tests := []struct {
+		concurrency int
+		count       int
+		expected    int
+	}{
+		{concurrency: 4, count: 10, expected: 4},
+		{concurrency: 4, count: 2, expected: 2},
+		{concurrency: 1, count: 10, expected: 1},
+		{concurrency: 4, count: 0, expected: 1},
+		{concurrency: 0, count: 1, expected: 1},
+	}
+
+	for _, tt := range tests {
+		SetReqsConcurrency(tt.concurrency)
+		if got := reqsCheckWorkers(tt.count); got != tt.expected {
+			t.Errorf("reqsCheckWorkers(%d) with concurrency %d = %d, want %d", tt.count, tt.concurrency, got, tt.expected)
+		}
+	}
+}
+
 func TestNewResultFormatter(t *testing.T) {
 	formatter := NewResultFormatter()
 	if formatter == nil {
@@ -277,6 +481,26 @@ func TestResultFormatterPrint(t *testing.T) {
 				CheckedRun: false,
 			},
 		},
+		{
+			name: "recommended not installed",
+			result: ReqResult{
+				Name:      "docker",
+				Installed: false,
+				Required:  "20.0.0",
+				Severity:  SeverityRecommended,
+			},
+		},
+		{
+			name: "optional not satisfied",
+			result: ReqResult{
+				Name:      "helm",
+				Installed: true,
+				Version:   "3.10.0",
+				Required:  "3.12.0",
+				Message:   "version 3.10.0 is below 3.12.0",
+				Severity:  SeverityOptional,
+			},
+		},
 		{
 			name: "running check - running",
 			result: ReqResult{
@@ -327,7 +551,7 @@ func TestInstallProject(t *testing.T) {
 	di := NewDependencyInstaller("/test")
 
 	// Test successful install
-	result := di.installProject("node", "/test/dir", "npm", func() error {
+	result := di.installProject("node", "/test/dir", "npm", func(context.Context) error {
 		return nil
 	})
 
@@ -348,7 +572,7 @@ func TestInstallProject(t *testing.T) {
 	_ = cliout.SetFormat("json")
 	defer func() { _ = cliout.SetFormat("default") }()
 
-	failResult := di.installProject("python", "/test/dir2", "pip", func() error {
+	failResult := di.installProject("python", "/test/dir2", "pip", func(context.Context) error {
 		return os.ErrNotExist
 	})
 
@@ -375,9 +599,10 @@ func TestSaveToCache(t *testing.T) {
 		t.Fatalf("failed to create test file: %v", err)
 	}
 
-	// Create cache manager
+	// Cache in the temp directory, not the .azure directory of the package
 	cacheManager, err := cache.NewCacheManagerWithOptions(cache.CacheOptions{
-		Enabled: true,
+		Enabled:  true,
+		CacheDir: filepath.Join(tmpDir, ".azure", "cache"),
 	})
 	if err != nil {
 		t.Fatalf("failed to create cache manager: %v", err)
@@ -393,5 +618,5 @@ func TestSaveToCache(t *testing.T) {
 	}
 
 	// Should not panic
-	saveToCache(azureYamlPath, results, true, cacheManager)
+	saveToCache(azureYamlPath, "", results, true, cacheManager)
 }
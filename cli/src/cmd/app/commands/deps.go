@@ -4,9 +4,14 @@ package commands
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/eventstream"
 	"github.com/jongio/azd-core/cliout"
 	types "github.com/jongio/azd-core/projecttype"
 	"github.com/spf13/cobra"
@@ -15,12 +20,15 @@ import (
 // DepsOptions holds the options for the deps command.
 // Using a struct instead of global variables for better testability and concurrency safety.
 type DepsOptions struct {
-	Verbose  bool
-	Clean    bool
-	NoCache  bool
-	Force    bool
-	DryRun   bool     // Show what would be installed without installing
-	Services []string // Filter to specific services by name
+	Clean       bool
+	NoCache     bool
+	Force       bool
+	DryRun      bool          // Show what would be installed without installing
+	Services    []string      // Filter to specific services by name
+	Only        []string      // Filter to project types (node, python, dotnet, go, rust, java)
+	Projects    []string      // Filter to the projects in or under these absolute directories
+	Concurrency int           // Maximum installs running at once (0 = team default, or unlimited)
+	Timeout     time.Duration // Maximum time for each project's install (0 = no limit)
 }
 
 // depsExecutor encapsulates the deps command execution with injectable dependencies.
@@ -60,60 +68,68 @@ func (e *depsExecutor) execute() error {
 	}
 
 	// Detect projects from azure.yaml services only (no tree walk)
-	nodeProjects, pythonProjects, dotnetProjects, err := detectProjectsFromAzureYaml(searchRoot)
+	projects, err := detectProjectsFromAzureYaml(searchRoot)
 	if err != nil {
 		return handleDepsError(err, "failed to detect projects from azure.yaml")
 	}
 
 	// Apply service filter if specified (further restricts to named services)
 	if len(e.opts.Services) > 0 {
-		nodeProjects, pythonProjects, dotnetProjects = e.filterProjectsByService(
-			nodeProjects, pythonProjects, dotnetProjects, searchRoot)
+		projects = e.filterProjectsByService(projects, searchRoot)
 	}
 
-	totalProjects := len(nodeProjects) + len(pythonProjects) + len(dotnetProjects)
+	// Apply the --only and --project filters
+	if len(e.opts.Only) > 0 {
+		projects = filterProjectsByType(projects, e.opts.Only)
+	}
+	if len(e.opts.Projects) > 0 {
+		projectPaths := make(map[string]bool, len(e.opts.Projects))
+		for _, dir := range e.opts.Projects {
+			projectPaths[dir] = true
+		}
+		projects = filterProjectsByPath(projects, projectPaths)
+	}
 
 	// Handle no projects case
-	if totalProjects == 0 {
+	if projects.count() == 0 {
 		return e.handleNoProjectsCase(searchRoot)
 	}
 
 	// Dry-run mode: show what would be installed and exit
 	if e.opts.DryRun {
-		return showDryRunSummary(nodeProjects, pythonProjects, dotnetProjects, searchRoot)
+		return showDryRunSummary(projects, searchRoot)
+	}
+
+	if err := executePredepsHook(searchRoot); err != nil {
+		return err
 	}
 
 	// Clean dependencies if requested
 	if e.opts.Clean {
-		if err := cleanDependencies(nodeProjects, pythonProjects, dotnetProjects); err != nil {
+		if err := cleanDependencies(projects.Node, projects.Python, projects.Dotnet); err != nil {
 			return fmt.Errorf("failed to clean dependencies: %w", err)
 		}
 	}
 
 	// Use parallel installer for concurrent installation with progress bars
 	if !cliout.IsJSON() {
-		return runParallelInstallation(nodeProjects, pythonProjects, dotnetProjects, e.opts.Verbose)
+		return runParallelInstallation(searchRoot, projects)
 	}
 
 	// JSON mode: use sequential installer
-	return runJSONInstallation(searchRoot, nodeProjects, pythonProjects, dotnetProjects)
+	return runJSONInstallation(searchRoot, projects)
 }
 
 // filterProjectsByService filters projects to only those matching the specified services.
-func (e *depsExecutor) filterProjectsByService(
-	nodeProjects []types.NodeProject,
-	pythonProjects []types.PythonProject,
-	dotnetProjects []types.DotnetProject,
-	searchRoot string,
-) ([]types.NodeProject, []types.PythonProject, []types.DotnetProject) {
-	return filterProjectsByService(nodeProjects, pythonProjects, dotnetProjects, e.opts.Services, searchRoot)
+func (e *depsExecutor) filterProjectsByService(projects depsProjects, searchRoot string) depsProjects {
+	return filterProjectsByService(projects, e.opts.Services, searchRoot)
 }
 
 // handleNoProjectsCase handles the case when no projects are detected.
 func (e *depsExecutor) handleNoProjectsCase(searchRoot string) error {
-	// If user specified services but none matched, show a helpful message
-	if len(e.opts.Services) > 0 {
-		msg := fmt.Sprintf("No projects found matching services: %v", e.opts.Services)
+	// If user specified filters but nothing matched, show a helpful message
+	if len(e.opts.Services) > 0 || len(e.opts.Only) > 0 || len(e.opts.Projects) > 0 {
+		msg := "No projects found matching " + describeDepsFilters(e.opts)
 		if cliout.IsJSON() {
 			return cliout.PrintJSON(DepsResult{
 				Success:  true,
@@ -150,11 +166,47 @@ func (e *depsExecutor) handleNoProjectsCase(searchRoot string) error {
 	if hasLogicAppsOnly {
 		cliout.Item("Logic Apps projects detected (no dependency installation needed)")
 	} else {
-		cliout.Item("Supported: Node.js (package.json), Python (requirements.txt/pyproject.toml), .NET (*.csproj)")
+		cliout.Item("Supported: Node.js (package.json), Python (requirements.txt/pyproject.toml), .NET (*.csproj), Go (go.mod), Rust (Cargo.toml), Java (pom.xml/build.gradle)")
+	}
+	return nil
+}
+
+// validateDepsFilters checks the --only project types and resolves the --project directories
+// to absolute paths.
+func validateDepsFilters(opts *DepsOptions) error {
+	for _, projectType := range opts.Only {
+		if !slices.Contains(depsProjectTypes, projectType) {
+			return fmt.Errorf("invalid --only value %q: must be one of %s", projectType, strings.Join(depsProjectTypes, ", "))
+		}
+	}
+	for i, dir := range opts.Projects {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve --project %s: %w", dir, err)
+		}
+		if info, err := os.Stat(absDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("--project %s is not a directory", dir)
+		}
+		opts.Projects[i] = filepath.Clean(absDir)
 	}
 	return nil
 }
 
+// describeDepsFilters describes the filters of the deps command, e.g. "services: [api]".
+func describeDepsFilters(opts *DepsOptions) string {
+	var filters []string
+	if len(opts.Services) > 0 {
+		filters = append(filters, fmt.Sprintf("services: %v", opts.Services))
+	}
+	if len(opts.Only) > 0 {
+		filters = append(filters, fmt.Sprintf("types: %v", opts.Only))
+	}
+	if len(opts.Projects) > 0 {
+		filters = append(filters, fmt.Sprintf("projects: %v", opts.Projects))
+	}
+	return strings.Join(filters, ", ")
+}
+
 // GetDepsOptions is a legacy getter function for backward compatibility.
 //
 // Deprecated: Use executor pattern instead.
@@ -165,14 +217,21 @@ func GetDepsOptions() *DepsOptions {
 	// Return a deep copy to prevent external mutation
 	servicesCopy := make([]string, len(globalDepsOptions.Services))
 	copy(servicesCopy, globalDepsOptions.Services)
+	onlyCopy := make([]string, len(globalDepsOptions.Only))
+	copy(onlyCopy, globalDepsOptions.Only)
+	projectsCopy := make([]string, len(globalDepsOptions.Projects))
+	copy(projectsCopy, globalDepsOptions.Projects)
 
 	return &DepsOptions{
-		Verbose:  globalDepsOptions.Verbose,
-		Clean:    globalDepsOptions.Clean,
-		NoCache:  globalDepsOptions.NoCache,
-		Force:    globalDepsOptions.Force,
-		DryRun:   globalDepsOptions.DryRun,
-		Services: servicesCopy,
+		Clean:       globalDepsOptions.Clean,
+		NoCache:     globalDepsOptions.NoCache,
+		Force:       globalDepsOptions.Force,
+		DryRun:      globalDepsOptions.DryRun,
+		Services:    servicesCopy,
+		Only:        onlyCopy,
+		Projects:    projectsCopy,
+		Concurrency: globalDepsOptions.Concurrency,
+		Timeout:     globalDepsOptions.Timeout,
 	}
 }
 
@@ -197,14 +256,21 @@ func setDepsOptions(opts *DepsOptions) {
 	// Deep copy to prevent mutation
 	servicesCopy := make([]string, len(opts.Services))
 	copy(servicesCopy, opts.Services)
+	onlyCopy := make([]string, len(opts.Only))
+	copy(onlyCopy, opts.Only)
+	projectsCopy := make([]string, len(opts.Projects))
+	copy(projectsCopy, opts.Projects)
 
 	globalDepsOptions = &DepsOptions{
-		Verbose:  opts.Verbose,
-		Clean:    opts.Clean,
-		NoCache:  opts.NoCache,
-		Force:    opts.Force,
-		DryRun:   opts.DryRun,
-		Services: servicesCopy,
+		Clean:       opts.Clean,
+		NoCache:     opts.NoCache,
+		Force:       opts.Force,
+		DryRun:      opts.DryRun,
+		Services:    servicesCopy,
+		Only:        onlyCopy,
+		Projects:    projectsCopy,
+		Concurrency: opts.Concurrency,
+		Timeout:     opts.Timeout,
 	}
 }
 
@@ -216,7 +282,7 @@ func NewDepsCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:          "deps",
 		Short:        "Install dependencies for services defined in azure.yaml",
-		Long:         `Installs dependencies for services defined in azure.yaml. Only service project paths are checked (Node.js, Python, .NET). Requires azure.yaml with a 'services' section.`,
+		Long:         `Installs dependencies for services defined in azure.yaml. Only service project paths are checked (Node.js, Python, .NET, Go, Rust, Java). Requires azure.yaml with a 'services' section.`,
 		SilenceUsage: true,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// Try to get the output flag from parent or self
@@ -226,12 +292,23 @@ func NewDepsCommand() *cobra.Command {
 			} else if flag := cmd.Flags().Lookup("output"); flag != nil {
 				formatValue = flag.Value.String()
 			}
-			if formatValue != "" {
+			// The root command has already set up the ndjson event stream
+			if formatValue != "" && formatValue != eventstream.Format {
 				return cliout.SetFormat(formatValue)
 			}
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Concurrency < 0 {
+				return fmt.Errorf("--concurrency must not be negative, got %d", opts.Concurrency)
+			}
+			if opts.Timeout < 0 {
+				return fmt.Errorf("--timeout must not be negative, got %s", opts.Timeout)
+			}
+			if err := validateDepsFilters(opts); err != nil {
+				return err
+			}
+
 			// Handle --force flag (combines --clean and --no-cache)
 			if opts.Force {
 				opts.Clean = true
@@ -240,6 +317,7 @@ func NewDepsCommand() *cobra.Command {
 
 			// Set global options for backward compatibility with orchestrator
 			setDepsOptions(opts)
+			SetReqsServices(opts.Services)
 
 			// Configure cache based on flag
 			if opts.NoCache {
@@ -250,12 +328,20 @@ func NewDepsCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Show full installation output")
+	supportEventStream(cmd)
+
 	cmd.Flags().BoolVar(&opts.Clean, "clean", false, "Remove existing dependencies before installing (clears node_modules, .venv, etc.)")
 	cmd.Flags().BoolVar(&opts.NoCache, "no-cache", false, "Force fresh dependency installation and bypass cached results")
 	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "Force clean reinstall (combines --clean and --no-cache)")
 	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show what would be installed without actually installing")
+	cmd.Flags().StringSliceVar(&opts.Only, "only", nil, "Install dependencies only for these project types: node, python, dotnet, go, rust, java")
+	cmd.Flags().StringSliceVar(&opts.Projects, "project", nil, "Install dependencies only for the projects in or under this directory (can be specified multiple times)")
+	cmd.Flags().IntVar(&opts.Concurrency, "concurrency", 0, "Maximum number of projects to install at once (0 = the team default, or unlimited)")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 0, "Cancel a project's install after this long, e.g. 5m (0 = no limit)")
 	cmd.Flags().StringSliceVarP(&opts.Services, "service", "s", nil, "Install dependencies only for specific services (can be specified multiple times)")
+	registerServiceFlagCompletion(cmd)
+	_ = cmd.RegisterFlagCompletionFunc("only", cobra.FixedCompletions(depsProjectTypes, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.MarkFlagDirname("project")
 
 	return cmd
 }
@@ -1,12 +1,15 @@
 package commands
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/jongio/azd-app/cli/src/internal/detector"
 	"github.com/jongio/azd-core/cliout"
 	types "github.com/jongio/azd-core/projecttype"
 	"github.com/spf13/cobra"
@@ -22,9 +25,6 @@ func TestDepsOptions_Default(t *testing.T) {
 	}
 
 	// Check defaults
-	if opts.Verbose {
-		t.Error("Verbose should be false by default")
-	}
 	if opts.Clean {
 		t.Error("Clean should be false by default")
 	}
@@ -45,7 +45,6 @@ func TestDepsOptions_Default(t *testing.T) {
 func TestResetDepsOptions(t *testing.T) {
 	// Set some values
 	opts := GetDepsOptions()
-	opts.Verbose = true
 	opts.Clean = true
 	opts.Services = []string{"test"}
 
@@ -54,9 +53,6 @@ func TestResetDepsOptions(t *testing.T) {
 
 	// Verify reset
 	newOpts := GetDepsOptions()
-	if newOpts.Verbose {
-		t.Error("Verbose should be false after reset")
-	}
 	if newOpts.Clean {
 		t.Error("Clean should be false after reset")
 	}
@@ -83,7 +79,7 @@ func TestDepsCommand_Flags(t *testing.T) {
 	}
 
 	// Verify flags exist
-	flags := []string{"verbose", "clean", "no-cache", "force", "dry-run", "service"}
+	flags := []string{"clean", "no-cache", "force", "dry-run", "service", "only", "project", "concurrency", "timeout"}
 	for _, flagName := range flags {
 		if cmd.Flags().Lookup(flagName) == nil {
 			t.Errorf("Flag %q not found", flagName)
@@ -92,7 +88,6 @@ func TestDepsCommand_Flags(t *testing.T) {
 
 	// Verify short flags
 	shortFlags := map[string]string{
-		"verbose": "v",
 		"force":   "f",
 		"service": "s",
 	}
@@ -208,20 +203,105 @@ func TestFilterProjectsByService_NoAzureYaml(t *testing.T) {
 	dotnetProjects := []types.DotnetProject{{Path: "/test/dotnet/project.csproj"}}
 
 	// Use a non-existent path to ensure no azure.yaml is found
-	filteredNode, filteredPython, filteredDotnet := filterProjectsByService(
-		nodeProjects, pythonProjects, dotnetProjects,
+	filtered := filterProjectsByService(
+		depsProjects{Node: nodeProjects, Python: pythonProjects, Dotnet: dotnetProjects},
 		[]string{"api"}, "/nonexistent/path",
 	)
 
 	// Should return original projects when no azure.yaml
-	if len(filteredNode) != len(nodeProjects) {
-		t.Errorf("Node projects filtered incorrectly: got %d, want %d", len(filteredNode), len(nodeProjects))
+	if len(filtered.Node) != len(nodeProjects) {
+		t.Errorf("Node projects filtered incorrectly: got %d, want %d", len(filtered.Node), len(nodeProjects))
+	}
+	if len(filtered.Python) != len(pythonProjects) {
+		t.Errorf("Python projects filtered incorrectly: got %d, want %d", len(filtered.Python), len(pythonProjects))
+	}
+	if len(filtered.Dotnet) != len(dotnetProjects) {
+		t.Errorf("Dotnet projects filtered incorrectly: got %d, want %d", len(filtered.Dotnet), len(dotnetProjects))
+	}
+}
+
+func TestFilterProjectsByType(t *testing.T) {
+	nodeProjects := []types.NodeProject{{Dir: "/repo/web"}}
+	pythonProjects := []types.PythonProject{{Dir: "/repo/api"}}
+	dotnetProjects := []types.DotnetProject{{Path: "/repo/backend/backend.csproj"}}
+	goProjects := []detector.GoProject{{Dir: "/repo/worker"}}
+
+	filtered := filterProjectsByType(
+		depsProjects{Node: nodeProjects, Python: pythonProjects, Dotnet: dotnetProjects, Go: goProjects},
+		[]string{"python", "go"},
+	)
+	if len(filtered.Node) != 0 || len(filtered.Dotnet) != 0 {
+		t.Errorf("filterProjectsByType() kept %d Node.js and %d .NET projects, want none", len(filtered.Node), len(filtered.Dotnet))
+	}
+	if len(filtered.Python) != 1 || len(filtered.Go) != 1 {
+		t.Errorf("filterProjectsByType() kept %d Python and %d Go projects, want 1 each", len(filtered.Python), len(filtered.Go))
+	}
+}
+
+func TestFilterProjectsByPath(t *testing.T) {
+	root := t.TempDir()
+	web := filepath.Join(root, "apps", "web")
+	nodeProjects := []types.NodeProject{
+		{Dir: filepath.Join(root, "apps"), IsWorkspaceRoot: true},
+		{Dir: web},
 	}
-	if len(filteredPython) != len(pythonProjects) {
-		t.Errorf("Python projects filtered incorrectly: got %d, want %d", len(filteredPython), len(pythonProjects))
+	pythonProjects := []types.PythonProject{{Dir: filepath.Join(root, "services", "api")}}
+	dotnetProjects := []types.DotnetProject{{Path: filepath.Join(root, "services", "backend", "backend.csproj")}}
+
+	// A project directory keeps its workspace root, which installs it
+	filtered := filterProjectsByPath(
+		depsProjects{Node: nodeProjects, Python: pythonProjects, Dotnet: dotnetProjects},
+		map[string]bool{web: true},
+	)
+	if len(filtered.Node) != 2 || len(filtered.Python) != 0 || len(filtered.Dotnet) != 0 {
+		t.Errorf("filterProjectsByPath(web) = %d Node.js, %d Python, %d .NET projects, want 2, 0, 0", len(filtered.Node), len(filtered.Python), len(filtered.Dotnet))
 	}
-	if len(filteredDotnet) != len(dotnetProjects) {
-		t.Errorf("Dotnet projects filtered incorrectly: got %d, want %d", len(filteredDotnet), len(dotnetProjects))
+
+	// A parent directory keeps every project under it
+	filtered = filterProjectsByPath(
+		depsProjects{Node: nodeProjects, Python: pythonProjects, Dotnet: dotnetProjects},
+		map[string]bool{filepath.Join(root, "services"): true},
+	)
+	if len(filtered.Node) != 0 || len(filtered.Python) != 1 || len(filtered.Dotnet) != 1 {
+		t.Errorf("filterProjectsByPath(services) = %d Node.js, %d Python, %d .NET projects, want 0, 1, 1", len(filtered.Node), len(filtered.Python), len(filtered.Dotnet))
+	}
+}
+
+func TestValidateDepsFilters(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "package.json")
+	if err := os.WriteFile(file, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    DepsOptions
+		wantErr bool
+	}{
+		{"no filters", DepsOptions{}, false},
+		{"valid types", DepsOptions{Only: []string{"node", "dotnet"}}, false},
+		{"invalid type", DepsOptions{Only: []string{"ruby"}}, true},
+		{"project directory", DepsOptions{Projects: []string{dir}}, false},
+		{"missing project", DepsOptions{Projects: []string{filepath.Join(dir, "missing")}}, true},
+		{"project file", DepsOptions{Projects: []string{file}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateDepsFilters(&tt.opts); (err != nil) != tt.wantErr {
+				t.Errorf("validateDepsFilters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Relative project directories are resolved against the working directory
+	t.Chdir(dir)
+	opts := DepsOptions{Projects: []string{"."}}
+	if err := validateDepsFilters(&opts); err != nil {
+		t.Fatalf("validateDepsFilters() error = %v", err)
+	}
+	if want, _ := filepath.Abs("."); opts.Projects[0] != want {
+		t.Errorf("Projects = %v, want [%s]", opts.Projects, want)
 	}
 }
 
@@ -285,13 +365,13 @@ func TestDependencyInstaller_NewDependencyInstaller(t *testing.T) {
 		t.Errorf("searchRoot = %q, want %q", di.searchRoot, searchRoot)
 	}
 	// Verify filtered project slices are nil by default
-	if di.nodeProjects != nil {
+	if di.projects.Node != nil {
 		t.Error("nodeProjects should be nil by default")
 	}
-	if di.pythonProjects != nil {
+	if di.projects.Python != nil {
 		t.Error("pythonProjects should be nil by default")
 	}
-	if di.dotnetProjects != nil {
+	if di.projects.Dotnet != nil {
 		t.Error("dotnetProjects should be nil by default")
 	}
 }
@@ -331,7 +411,7 @@ func TestGetDepsOptions_ReturnsCopy(t *testing.T) {
 
 	// Set initial values using setDepsOptions
 	setDepsOptions(&DepsOptions{
-		Verbose:  true,
+		Clean:    true,
 		Services: []string{"api", "web"},
 	})
 
@@ -339,14 +419,14 @@ func TestGetDepsOptions_ReturnsCopy(t *testing.T) {
 	opts := GetDepsOptions()
 
 	// Modify the copy
-	opts.Verbose = false
+	opts.Clean = false
 	opts.Services[0] = "modified"
 	opts.Services = append(opts.Services, "new")
 
 	// Get another copy and verify original is unchanged
 	opts2 := GetDepsOptions()
-	if !opts2.Verbose {
-		t.Error("Original Verbose should still be true")
+	if !opts2.Clean {
+		t.Error("Original Clean should still be true")
 	}
 	if opts2.Services[0] != "api" {
 		t.Errorf("Original Services[0] should be 'api', got %q", opts2.Services[0])
@@ -368,7 +448,7 @@ func TestSetDepsOptions_ThreadSafe(t *testing.T) {
 		go func(val int) {
 			defer wg.Done()
 			setDepsOptions(&DepsOptions{
-				Verbose:  val%2 == 0,
+				Clean:    val%2 == 0,
 				Services: []string{string(rune('a' + val%26))},
 			})
 		}(i)
@@ -404,7 +484,7 @@ func TestResetDepsOptions_ThreadSafe(t *testing.T) {
 		}()
 		go func(val int) {
 			defer wg.Done()
-			setDepsOptions(&DepsOptions{Verbose: val%2 == 0})
+			setDepsOptions(&DepsOptions{Clean: val%2 == 0})
 		}(i)
 		go func() {
 			defer wg.Done()
@@ -516,52 +596,52 @@ services:
 	}
 
 	// Test filtering for "api" service only
-	filteredNode, filteredPython, filteredDotnet := filterProjectsByService(
-		nodeProjects, pythonProjects, dotnetProjects,
+	filtered := filterProjectsByService(
+		depsProjects{Node: nodeProjects, Python: pythonProjects, Dotnet: dotnetProjects},
 		[]string{"api"}, tmpDir,
 	)
 
 	// Should filter to only api service (python project)
-	if len(filteredNode) != 0 {
-		t.Errorf("Expected 0 node projects for 'api' filter, got %d", len(filteredNode))
+	if len(filtered.Node) != 0 {
+		t.Errorf("Expected 0 node projects for 'api' filter, got %d", len(filtered.Node))
 	}
-	if len(filteredPython) != 1 {
-		t.Errorf("Expected 1 python project for 'api' filter, got %d", len(filteredPython))
+	if len(filtered.Python) != 1 {
+		t.Errorf("Expected 1 python project for 'api' filter, got %d", len(filtered.Python))
 	}
-	if len(filteredDotnet) != 0 {
-		t.Errorf("Expected 0 dotnet projects for 'api' filter, got %d", len(filteredDotnet))
+	if len(filtered.Dotnet) != 0 {
+		t.Errorf("Expected 0 dotnet projects for 'api' filter, got %d", len(filtered.Dotnet))
 	}
 
 	// Test filtering for "web" service only
-	filteredNode, filteredPython, filteredDotnet = filterProjectsByService(
-		nodeProjects, pythonProjects, dotnetProjects,
+	filtered = filterProjectsByService(
+		depsProjects{Node: nodeProjects, Python: pythonProjects, Dotnet: dotnetProjects},
 		[]string{"web"}, tmpDir,
 	)
 
-	if len(filteredNode) != 1 {
-		t.Errorf("Expected 1 node project for 'web' filter, got %d", len(filteredNode))
+	if len(filtered.Node) != 1 {
+		t.Errorf("Expected 1 node project for 'web' filter, got %d", len(filtered.Node))
 	}
-	if len(filteredPython) != 0 {
-		t.Errorf("Expected 0 python projects for 'web' filter, got %d", len(filteredPython))
+	if len(filtered.Python) != 0 {
+		t.Errorf("Expected 0 python projects for 'web' filter, got %d", len(filtered.Python))
 	}
-	if len(filteredDotnet) != 0 {
-		t.Errorf("Expected 0 dotnet projects for 'web' filter, got %d", len(filteredDotnet))
+	if len(filtered.Dotnet) != 0 {
+		t.Errorf("Expected 0 dotnet projects for 'web' filter, got %d", len(filtered.Dotnet))
 	}
 
 	// Test filtering for multiple services
-	filteredNode, filteredPython, filteredDotnet = filterProjectsByService(
-		nodeProjects, pythonProjects, dotnetProjects,
+	filtered = filterProjectsByService(
+		depsProjects{Node: nodeProjects, Python: pythonProjects, Dotnet: dotnetProjects},
 		[]string{"api", "web", "backend"}, tmpDir,
 	)
 
-	if len(filteredNode) != 1 {
-		t.Errorf("Expected 1 node project for multi-service filter, got %d", len(filteredNode))
+	if len(filtered.Node) != 1 {
+		t.Errorf("Expected 1 node project for multi-service filter, got %d", len(filtered.Node))
 	}
-	if len(filteredPython) != 1 {
-		t.Errorf("Expected 1 python project for multi-service filter, got %d", len(filteredPython))
+	if len(filtered.Python) != 1 {
+		t.Errorf("Expected 1 python project for multi-service filter, got %d", len(filtered.Python))
 	}
-	if len(filteredDotnet) != 1 {
-		t.Errorf("Expected 1 dotnet project for multi-service filter, got %d", len(filteredDotnet))
+	if len(filtered.Dotnet) != 1 {
+		t.Errorf("Expected 1 dotnet project for multi-service filter, got %d", len(filtered.Dotnet))
 	}
 }
 
@@ -581,19 +661,19 @@ func TestFilterProjectsByService_InvalidAzureYaml(t *testing.T) {
 	dotnetProjects := []types.DotnetProject{{Path: filepath.Join(tmpDir, "project.csproj")}}
 
 	// Should return original projects when azure.yaml is invalid
-	filteredNode, filteredPython, filteredDotnet := filterProjectsByService(
-		nodeProjects, pythonProjects, dotnetProjects,
+	filtered := filterProjectsByService(
+		depsProjects{Node: nodeProjects, Python: pythonProjects, Dotnet: dotnetProjects},
 		[]string{"api"}, tmpDir,
 	)
 
-	if len(filteredNode) != len(nodeProjects) {
-		t.Errorf("Expected original node projects on invalid yaml, got %d", len(filteredNode))
+	if len(filtered.Node) != len(nodeProjects) {
+		t.Errorf("Expected original node projects on invalid yaml, got %d", len(filtered.Node))
 	}
-	if len(filteredPython) != len(pythonProjects) {
-		t.Errorf("Expected original python projects on invalid yaml, got %d", len(filteredPython))
+	if len(filtered.Python) != len(pythonProjects) {
+		t.Errorf("Expected original python projects on invalid yaml, got %d", len(filtered.Python))
 	}
-	if len(filteredDotnet) != len(dotnetProjects) {
-		t.Errorf("Expected original dotnet projects on invalid yaml, got %d", len(filteredDotnet))
+	if len(filtered.Dotnet) != len(dotnetProjects) {
+		t.Errorf("Expected original dotnet projects on invalid yaml, got %d", len(filtered.Dotnet))
 	}
 }
 
@@ -615,7 +695,7 @@ func TestShowDryRunSummary_TextMode(t *testing.T) {
 	}
 
 	// showDryRunSummary should not return an error
-	err := showDryRunSummary(nodeProjects, pythonProjects, dotnetProjects, tmpDir)
+	err := showDryRunSummary(depsProjects{Node: nodeProjects, Python: pythonProjects, Dotnet: dotnetProjects}, tmpDir)
 	if err != nil {
 		t.Errorf("showDryRunSummary returned error: %v", err)
 	}
@@ -628,7 +708,7 @@ func TestShowDryRunSummary_EmptyProjects(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Empty projects
-	err := showDryRunSummary(nil, nil, nil, tmpDir)
+	err := showDryRunSummary(depsProjects{}, tmpDir)
 	if err != nil {
 		t.Errorf("showDryRunSummary with empty projects returned error: %v", err)
 	}
@@ -952,14 +1032,14 @@ services:
 		{Dir: apiSubDir, PackageManager: "npm"},
 	}
 
-	filteredNode, _, _ := filterProjectsByService(
-		nodeProjects, nil, nil,
+	filtered := filterProjectsByService(
+		depsProjects{Node: nodeProjects},
 		[]string{"api"}, tmpDir,
 	)
 
 	// Should match because apiSubDir is a subdirectory of apiDir
-	if len(filteredNode) != 1 {
-		t.Errorf("Expected 1 node project for subdirectory match, got %d", len(filteredNode))
+	if len(filtered.Node) != 1 {
+		t.Errorf("Expected 1 node project for subdirectory match, got %d", len(filtered.Node))
 	}
 }
 
@@ -969,9 +1049,6 @@ func TestDepsOptions_AllFlagsDefaultValues(t *testing.T) {
 	opts := GetDepsOptions()
 
 	// Verify all default values
-	if opts.Verbose {
-		t.Error("Verbose should be false by default")
-	}
 	if opts.Clean {
 		t.Error("Clean should be false by default")
 	}
@@ -994,20 +1071,18 @@ func TestDepsOptions_SetAndGet(t *testing.T) {
 
 	// Set custom options
 	customOpts := &DepsOptions{
-		Verbose:  true,
-		Clean:    true,
-		NoCache:  true,
-		Force:    true,
-		DryRun:   true,
-		Services: []string{"api", "web"},
+		Clean:       true,
+		NoCache:     true,
+		Force:       true,
+		DryRun:      true,
+		Services:    []string{"api", "web"},
+		Concurrency: 2,
+		Timeout:     5 * time.Minute,
 	}
 	setDepsOptions(customOpts)
 
 	// Get and verify
 	opts := GetDepsOptions()
-	if !opts.Verbose {
-		t.Error("Verbose should be true")
-	}
 	if !opts.Clean {
 		t.Error("Clean should be true")
 	}
@@ -1026,6 +1101,9 @@ func TestDepsOptions_SetAndGet(t *testing.T) {
 	if opts.Services[0] != "api" || opts.Services[1] != "web" {
 		t.Errorf("Services mismatch: got %v", opts.Services)
 	}
+	if opts.Concurrency != 2 || opts.Timeout != 5*time.Minute {
+		t.Errorf("Concurrency = %d, Timeout = %s, want 2, 5m0s", opts.Concurrency, opts.Timeout)
+	}
 }
 
 func TestNewDependencyInstaller_WithFilteredProjects(t *testing.T) {
@@ -1033,18 +1111,18 @@ func TestNewDependencyInstaller_WithFilteredProjects(t *testing.T) {
 	di := NewDependencyInstaller(searchRoot)
 
 	// Set filtered projects
-	di.nodeProjects = []types.NodeProject{{Dir: "/test/node"}}
-	di.pythonProjects = []types.PythonProject{{Dir: "/test/python"}}
-	di.dotnetProjects = []types.DotnetProject{{Path: "/test/dotnet/project.csproj"}}
+	di.projects.Node = []types.NodeProject{{Dir: "/test/node"}}
+	di.projects.Python = []types.PythonProject{{Dir: "/test/python"}}
+	di.projects.Dotnet = []types.DotnetProject{{Path: "/test/dotnet/project.csproj"}}
 
-	if len(di.nodeProjects) != 1 {
-		t.Errorf("Expected 1 node project, got %d", len(di.nodeProjects))
+	if len(di.projects.Node) != 1 {
+		t.Errorf("Expected 1 node project, got %d", len(di.projects.Node))
 	}
-	if len(di.pythonProjects) != 1 {
-		t.Errorf("Expected 1 python project, got %d", len(di.pythonProjects))
+	if len(di.projects.Python) != 1 {
+		t.Errorf("Expected 1 python project, got %d", len(di.projects.Python))
 	}
-	if len(di.dotnetProjects) != 1 {
-		t.Errorf("Expected 1 dotnet project, got %d", len(di.dotnetProjects))
+	if len(di.projects.Dotnet) != 1 {
+		t.Errorf("Expected 1 dotnet project, got %d", len(di.projects.Dotnet))
 	}
 }
 
@@ -1178,7 +1256,7 @@ func TestShowDryRunSummary_OnlyNodeProjects(t *testing.T) {
 		{Dir: filepath.Join(tmpDir, "web2"), PackageManager: "pnpm"},
 	}
 
-	err := showDryRunSummary(nodeProjects, nil, nil, tmpDir)
+	err := showDryRunSummary(depsProjects{Node: nodeProjects}, tmpDir)
 	if err != nil {
 		t.Errorf("showDryRunSummary returned error: %v", err)
 	}
@@ -1193,7 +1271,7 @@ func TestShowDryRunSummary_OnlyPythonProjects(t *testing.T) {
 		{Dir: filepath.Join(tmpDir, "api2"), PackageManager: "poetry"},
 	}
 
-	err := showDryRunSummary(nil, pythonProjects, nil, tmpDir)
+	err := showDryRunSummary(depsProjects{Python: pythonProjects}, tmpDir)
 	if err != nil {
 		t.Errorf("showDryRunSummary returned error: %v", err)
 	}
@@ -1208,7 +1286,7 @@ func TestShowDryRunSummary_OnlyDotnetProjects(t *testing.T) {
 		{Path: filepath.Join(tmpDir, "backend2", "project2.csproj")},
 	}
 
-	err := showDryRunSummary(nil, nil, dotnetProjects, tmpDir)
+	err := showDryRunSummary(depsProjects{Dotnet: dotnetProjects}, tmpDir)
 	if err != nil {
 		t.Errorf("showDryRunSummary returned error: %v", err)
 	}
@@ -1231,14 +1309,14 @@ services:
 	nodeProjects := []types.NodeProject{{Dir: tmpDir}}
 
 	// Empty services list should return all projects
-	filteredNode, _, _ := filterProjectsByService(
-		nodeProjects, nil, nil,
+	filtered := filterProjectsByService(
+		depsProjects{Node: nodeProjects},
 		[]string{}, tmpDir,
 	)
 
 	// With empty filter, the function should return all projects
-	if len(filteredNode) != 0 {
-		t.Logf("filteredNode: %v", filteredNode)
+	if len(filtered.Node) != 0 {
+		t.Logf("filtered.Node: %v", filtered.Node)
 	}
 }
 
@@ -1265,14 +1343,14 @@ services:
 	nodeProjects := []types.NodeProject{{Dir: apiDir}}
 
 	// Filter for non-existent service
-	filteredNode, _, _ := filterProjectsByService(
-		nodeProjects, nil, nil,
+	filtered := filterProjectsByService(
+		depsProjects{Node: nodeProjects},
 		[]string{"nonexistent"}, tmpDir,
 	)
 
 	// Should return empty since no services match
-	if len(filteredNode) != 0 {
-		t.Errorf("Expected 0 projects for non-matching service, got %d", len(filteredNode))
+	if len(filtered.Node) != 0 {
+		t.Errorf("Expected 0 projects for non-matching service, got %d", len(filtered.Node))
 	}
 }
 
@@ -1537,13 +1615,13 @@ services:
 		{Dir: otherDir, PackageManager: "pip"},
 	}
 
-	_, filteredPython, _ := filterProjectsByService(
-		nil, pythonProjects, nil,
+	filtered := filterProjectsByService(
+		depsProjects{Python: pythonProjects},
 		[]string{"api"}, tmpDir,
 	)
 
-	if len(filteredPython) != 1 {
-		t.Errorf("Expected 1 python project, got %d", len(filteredPython))
+	if len(filtered.Python) != 1 {
+		t.Errorf("Expected 1 python project, got %d", len(filtered.Python))
 	}
 }
 
@@ -1576,13 +1654,41 @@ services:
 		{Path: filepath.Join(otherDir, "other.csproj")},
 	}
 
-	_, _, filteredDotnet := filterProjectsByService(
-		nil, nil, dotnetProjects,
+	filtered := filterProjectsByService(
+		depsProjects{Dotnet: dotnetProjects},
 		[]string{"backend"}, tmpDir,
 	)
 
-	if len(filteredDotnet) != 1 {
-		t.Errorf("Expected 1 dotnet project, got %d", len(filteredDotnet))
+	if len(filtered.Dotnet) != 1 {
+		t.Errorf("Expected 1 dotnet project, got %d", len(filtered.Dotnet))
+	}
+}
+
+func TestFilterProjectsByService_GoProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	azureYamlContent := `name: test-app
+services:
+  worker:
+    project: ./worker
+    language: go
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "azure.yaml"), []byte(azureYamlContent), 0600); err != nil {
+		t.Fatalf("Failed to create azure.yaml: %v", err)
+	}
+
+	goProjects := []detector.GoProject{
+		{Dir: filepath.Join(tmpDir, "worker")},
+		{Dir: filepath.Join(tmpDir, "tools")},
+	}
+
+	filtered := filterProjectsByService(
+		depsProjects{Go: goProjects},
+		[]string{"worker"}, tmpDir,
+	)
+
+	if len(filtered.Go) != 1 || filtered.Go[0].Dir != goProjects[0].Dir {
+		t.Errorf("Expected only the worker go project, got %+v", filtered.Go)
 	}
 }
 
@@ -1834,7 +1940,7 @@ func TestShowDryRunSummary_JSONMode(t *testing.T) {
 	}
 
 	// showDryRunSummary should return nil for JSON output
-	err := showDryRunSummary(nodeProjects, pythonProjects, dotnetProjects, tmpDir)
+	err := showDryRunSummary(depsProjects{Node: nodeProjects, Python: pythonProjects, Dotnet: dotnetProjects}, tmpDir)
 	// In JSON mode it prints JSON and returns nil
 	if err != nil {
 		t.Logf("showDryRunSummary returned: %v (may be expected for JSON output)", err)
@@ -1917,7 +2023,7 @@ func TestInstallAllFiltered_WithNodeProjects(t *testing.T) {
 	}
 
 	di := NewDependencyInstaller(tmpDir)
-	di.nodeProjects = []types.NodeProject{{Dir: nodeDir, PackageManager: "npm"}}
+	di.projects.Node = []types.NodeProject{{Dir: nodeDir, PackageManager: "npm"}}
 
 	// This will try to run npm install, which may fail if npm is not available
 	// but the function should still return results
@@ -1950,7 +2056,7 @@ func TestInstallAllFiltered_WithPythonProjects(t *testing.T) {
 	}
 
 	di := NewDependencyInstaller(tmpDir)
-	di.pythonProjects = []types.PythonProject{{Dir: pythonDir, PackageManager: "pip"}}
+	di.projects.Python = []types.PythonProject{{Dir: pythonDir, PackageManager: "pip"}}
 
 	results, err := di.InstallAllFiltered()
 	if err != nil {
@@ -1982,7 +2088,7 @@ func TestInstallAllFiltered_WithDotnetProjects(t *testing.T) {
 	}
 
 	di := NewDependencyInstaller(tmpDir)
-	di.dotnetProjects = []types.DotnetProject{{Path: csprojPath}}
+	di.projects.Dotnet = []types.DotnetProject{{Path: csprojPath}}
 
 	results, err := di.InstallAllFiltered()
 	if err != nil {
@@ -2020,8 +2126,8 @@ func TestInstallAllFiltered_MixedProjects(t *testing.T) {
 	}
 
 	di := NewDependencyInstaller(tmpDir)
-	di.nodeProjects = []types.NodeProject{{Dir: nodeDir, PackageManager: "npm"}}
-	di.pythonProjects = []types.PythonProject{{Dir: pythonDir, PackageManager: "pip"}}
+	di.projects.Node = []types.NodeProject{{Dir: nodeDir, PackageManager: "npm"}}
+	di.projects.Python = []types.PythonProject{{Dir: pythonDir, PackageManager: "pip"}}
 
 	results, err := di.InstallAllFiltered()
 	if err != nil {
@@ -2304,7 +2410,7 @@ func TestInstallProject_Success(t *testing.T) {
 	di := NewDependencyInstaller("/test")
 
 	// Test with a successful install function
-	result := di.installProject("test", "/test/dir", "test-manager", func() error {
+	result := di.installProject("test", "/test/dir", "test-manager", func(context.Context) error {
 		return nil
 	})
 
@@ -2329,7 +2435,7 @@ func TestInstallProject_Failure(t *testing.T) {
 
 	// Test with a failing install function
 	expectedError := "installation failed"
-	result := di.installProject("test", "/test/dir", "test-manager", func() error {
+	result := di.installProject("test", "/test/dir", "test-manager", func(context.Context) error {
 		return &testError{msg: expectedError}
 	})
 
@@ -2347,7 +2453,7 @@ func TestInstallProject_JSONMode(t *testing.T) {
 
 	di := NewDependencyInstaller("/test")
 
-	result := di.installProject("node", "/test/node", "npm", func() error {
+	result := di.installProject("node", "/test/node", "npm", func(context.Context) error {
 		return nil
 	})
 
@@ -2365,7 +2471,7 @@ func TestInstallProject_RelativePath(t *testing.T) {
 
 	di := NewDependencyInstaller(searchRoot)
 
-	result := di.installProject("python", projectDir, "pip", func() error {
+	result := di.installProject("python", projectDir, "pip", func(context.Context) error {
 		return nil
 	})
 
@@ -2377,6 +2483,25 @@ func TestInstallProject_RelativePath(t *testing.T) {
 	}
 }
 
+func TestInstallProject_Timeout(t *testing.T) {
+	_ = cliout.SetFormat("text")
+
+	di := NewDependencyInstaller("/test")
+	di.timeout = 10 * time.Millisecond
+
+	result := di.installProject("node", "/test/node", "npm", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if result.Success || !result.TimedOut {
+		t.Errorf("installProject() = %+v, want a timed out failure", result)
+	}
+	if result.Error != "timed out after 10ms" {
+		t.Errorf("Error = %q, want %q", result.Error, "timed out after 10ms")
+	}
+}
+
 // Test createCacheManager
 func TestCreateCacheManager_Enabled(t *testing.T) {
 	cm := createCacheManager(true)
@@ -2740,11 +2865,6 @@ func TestNewDepsCommand_AllFlags(t *testing.T) {
 	cmd := NewDepsCommand()
 
 	// Verify all flags exist with correct types
-	verboseFlag := cmd.Flags().Lookup("verbose")
-	if verboseFlag == nil || verboseFlag.Value.Type() != "bool" {
-		t.Error("verbose flag missing or wrong type")
-	}
-
 	cleanFlag := cmd.Flags().Lookup("clean")
 	if cleanFlag == nil || cleanFlag.Value.Type() != "bool" {
 		t.Error("clean flag missing or wrong type")
@@ -2990,7 +3110,7 @@ version = "1.0.0"
 func TestDetectProjectsFromAzureYaml_NoAzureYaml(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	_, _, _, err := detectProjectsFromAzureYaml(tmpDir)
+	_, err := detectProjectsFromAzureYaml(tmpDir)
 	if err == nil {
 		t.Fatal("Expected error when no azure.yaml exists")
 	}
@@ -3007,7 +3127,7 @@ func TestDetectProjectsFromAzureYaml_NoServices(t *testing.T) {
 		t.Fatalf("Failed to create azure.yaml: %v", err)
 	}
 
-	_, _, _, err := detectProjectsFromAzureYaml(tmpDir)
+	_, err := detectProjectsFromAzureYaml(tmpDir)
 	if err == nil {
 		t.Fatal("Expected error when no services defined")
 	}
@@ -3025,7 +3145,7 @@ func TestDetectProjectsFromAzureYaml_MissingProjectDir(t *testing.T) {
 		t.Fatalf("Failed to create azure.yaml: %v", err)
 	}
 
-	_, _, _, err := detectProjectsFromAzureYaml(tmpDir)
+	_, err := detectProjectsFromAzureYaml(tmpDir)
 	if err == nil {
 		t.Fatal("Expected error when project directory does not exist")
 	}
@@ -3050,18 +3170,18 @@ func TestDetectProjectsFromAzureYaml_NodeProject(t *testing.T) {
 		t.Fatalf("Failed to create azure.yaml: %v", err)
 	}
 
-	nodeProjects, pythonProjects, dotnetProjects, err := detectProjectsFromAzureYaml(tmpDir)
+	projects, err := detectProjectsFromAzureYaml(tmpDir)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
-	if len(nodeProjects) != 1 {
-		t.Errorf("Expected 1 node project, got %d", len(nodeProjects))
+	if len(projects.Node) != 1 {
+		t.Errorf("Expected 1 node project, got %d", len(projects.Node))
 	}
-	if len(pythonProjects) != 0 {
-		t.Errorf("Expected 0 python projects, got %d", len(pythonProjects))
+	if len(projects.Python) != 0 {
+		t.Errorf("Expected 0 python projects, got %d", len(projects.Python))
 	}
-	if len(dotnetProjects) != 0 {
-		t.Errorf("Expected 0 dotnet projects, got %d", len(dotnetProjects))
+	if len(projects.Dotnet) != 0 {
+		t.Errorf("Expected 0 dotnet projects, got %d", len(projects.Dotnet))
 	}
 }
 
@@ -3090,12 +3210,12 @@ func TestDetectProjectsFromAzureYaml_IgnoresNonServiceProjects(t *testing.T) {
 		t.Fatalf("Failed to create azure.yaml: %v", err)
 	}
 
-	nodeProjects, _, _, err := detectProjectsFromAzureYaml(tmpDir)
+	projects, err := detectProjectsFromAzureYaml(tmpDir)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
-	if len(nodeProjects) != 1 {
-		t.Errorf("Expected 1 node project, got %d", len(nodeProjects))
+	if len(projects.Node) != 1 {
+		t.Errorf("Expected 1 node project, got %d", len(projects.Node))
 	}
 }
 
@@ -3105,7 +3225,8 @@ func TestDetectProjectsFromAzureYaml_MultipleServices(t *testing.T) {
 	webDir := filepath.Join(tmpDir, "web")
 	apiDir := filepath.Join(tmpDir, "api")
 	backendDir := filepath.Join(tmpDir, "backend")
-	for _, dir := range []string{webDir, apiDir, backendDir} {
+	workerDir := filepath.Join(tmpDir, "worker")
+	for _, dir := range []string{webDir, apiDir, backendDir, workerDir} {
 		if err := os.MkdirAll(dir, 0750); err != nil {
 			t.Fatalf("Failed to create directory: %v", err)
 		}
@@ -3119,6 +3240,9 @@ func TestDetectProjectsFromAzureYaml_MultipleServices(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(backendDir, "app.csproj"), []byte("<Project></Project>"), 0600); err != nil {
 		t.Fatalf("Failed to write: %v", err)
 	}
+	if err := os.WriteFile(filepath.Join(workerDir, "go.mod"), []byte("module example.com/worker\n\ngo 1.22\n"), 0600); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
 
 	content := `name: test-app
 services:
@@ -3131,23 +3255,202 @@ services:
   backend:
     project: ./backend
     host: localhost
+  worker:
+    project: ./worker
+    host: localhost
 `
 	if err := os.WriteFile(filepath.Join(tmpDir, "azure.yaml"), []byte(content), 0600); err != nil {
 		t.Fatalf("Failed to create azure.yaml: %v", err)
 	}
 
-	nodeProjects, pythonProjects, dotnetProjects, err := detectProjectsFromAzureYaml(tmpDir)
+	projects, err := detectProjectsFromAzureYaml(tmpDir)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
-	if len(nodeProjects) != 1 {
-		t.Errorf("Expected 1 node project, got %d", len(nodeProjects))
+	if len(projects.Node) != 1 {
+		t.Errorf("Expected 1 node project, got %d", len(projects.Node))
 	}
-	if len(pythonProjects) != 1 {
-		t.Errorf("Expected 1 python project, got %d", len(pythonProjects))
+	if len(projects.Python) != 1 {
+		t.Errorf("Expected 1 python project, got %d", len(projects.Python))
 	}
-	if len(dotnetProjects) != 1 {
-		t.Errorf("Expected 1 dotnet project, got %d", len(dotnetProjects))
+	if len(projects.Dotnet) != 1 {
+		t.Errorf("Expected 1 dotnet project, got %d", len(projects.Dotnet))
+	}
+	if len(projects.Go) != 1 || filepath.Base(projects.Go[0].Dir) != "worker" {
+		t.Errorf("Expected 1 go project in worker, got %+v", projects.Go)
+	}
+}
+
+func TestDetectProjectsFromAzureYaml_NodeWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"web/package.json":            `{"name": "web", "workspaces": ["apps/*"]}`,
+		"web/turbo.json":              `{}`,
+		"web/yarn.lock":               "",
+		"web/apps/site/package.json":  `{"name": "site"}`,
+		"web/apps/admin/package.json": `{"name": "admin"}`,
+		"standalone/package.json":     `{"name": "standalone"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+	}
+
+	content := `name: test-app
+services:
+  site:
+    project: ./web/apps/site
+  admin:
+    project: ./web/apps/admin
+  standalone:
+    project: ./standalone
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "azure.yaml"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to create azure.yaml: %v", err)
+	}
+
+	projects, err := detectProjectsFromAzureYaml(tmpDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(projects.Node) != 2 {
+		t.Fatalf("Expected the workspace and the standalone package, got %+v", projects.Node)
+	}
+
+	var workspaceRoot *types.NodeProject
+	for i := range projects.Node {
+		if projects.Node[i].IsWorkspaceRoot {
+			workspaceRoot = &projects.Node[i]
+		}
+	}
+	if workspaceRoot == nil || workspaceRoot.Dir != filepath.Join(tmpDir, "web") {
+		t.Fatalf("Expected workspace packages to be installed from the workspace root, got %+v", projects.Node)
+	}
+	if workspaceRoot.PackageManager != "yarn" {
+		t.Errorf("Expected the workspace root's package manager yarn, got %q", workspaceRoot.PackageManager)
+	}
+
+	// Filtering by a package's service keeps the workspace it belongs to
+	filtered := filterProjectsByService(
+		projects,
+		[]string{"admin"}, tmpDir,
+	)
+	if len(filtered.Node) != 1 || filtered.Node[0].Dir != workspaceRoot.Dir {
+		t.Errorf("Expected only the workspace root, got %+v", filtered.Node)
+	}
+}
+
+func TestDetectProjectsFromAzureYaml_RustWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"rs/Cargo.toml":                "[workspace]\nmembers = [\"crates/*\"]\n",
+		"rs/crates/api/Cargo.toml":     "[package]\nname = \"api\"\n",
+		"rs/crates/worker/Cargo.toml":  "[package]\nname = \"worker\"\n",
+		"standalone/Cargo.toml":        "[package]\nname = \"standalone\"\n",
+		"rs/crates/api/src/main.rs":    "fn main() {}",
+		"rs/crates/worker/src/main.rs": "fn main() {}",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+	}
+
+	content := `name: test-app
+services:
+  api:
+    project: ./rs/crates/api
+  worker:
+    project: ./rs/crates/worker
+  standalone:
+    project: ./standalone
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "azure.yaml"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to create azure.yaml: %v", err)
+	}
+
+	projects, err := detectProjectsFromAzureYaml(tmpDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(projects.Rust) != 2 {
+		t.Fatalf("Expected the workspace and the standalone crate, got %+v", projects.Rust)
+	}
+
+	var workspace *detector.RustProject
+	for i := range projects.Rust {
+		if projects.Rust[i].Workspace {
+			workspace = &projects.Rust[i]
+		}
+	}
+	if workspace == nil {
+		t.Fatalf("Expected workspace crates to be fetched from the workspace root, got %+v", projects.Rust)
+	}
+
+	// Filtering by a crate's service keeps the workspace it belongs to
+	filtered := filterProjectsByService(
+		projects,
+		[]string{"worker"}, tmpDir,
+	)
+	if len(filtered.Rust) != 1 || filtered.Rust[0].Dir != workspace.Dir {
+		t.Errorf("Expected only the workspace root, got %+v", filtered.Rust)
+	}
+}
+
+func TestDetectProjectsFromAzureYaml_JavaServices(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"orders/pom.xml":               "<project/>",
+		"orders/mvnw":                  "#!/bin/sh",
+		"billing/build.gradle.kts":     "plugins { java }",
+		"billing/app/build.gradle.kts": "plugins { application }",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+	}
+
+	content := `name: test-app
+services:
+  orders:
+    project: ./orders
+  billing:
+    project: ./billing
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "azure.yaml"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to create azure.yaml: %v", err)
+	}
+
+	projects, err := detectProjectsFromAzureYaml(tmpDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(projects.Java) != 2 {
+		t.Fatalf("Expected 2 java projects, got %+v", projects.Java)
+	}
+	buildTools := make(map[string]string)
+	for _, p := range projects.Java {
+		buildTools[filepath.Base(p.Dir)] = p.BuildTool
+	}
+	if buildTools["orders"] != detector.BuildToolMaven || buildTools["billing"] != detector.BuildToolGradle {
+		t.Errorf("Expected orders to use maven and billing to use gradle, got %v", buildTools)
 	}
 }
 
@@ -3165,7 +3468,7 @@ services:
 		t.Fatalf("Failed to create azure.yaml: %v", err)
 	}
 
-	_, _, _, err := detectProjectsFromAzureYaml(tmpDir)
+	_, err := detectProjectsFromAzureYaml(tmpDir)
 	if err == nil {
 		t.Fatal("Expected error for path traversal, got nil")
 	}
@@ -0,0 +1,220 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jongio/azd-app/cli/src/internal/azure"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffCloudServices           string
+	diffCloudIncludePlatformEnv bool
+	diffCloudFailOnDrift        bool
+)
+
+// diffCloudHosts are the azure.yaml hosts whose deployed configuration can be compared.
+var diffCloudHosts = map[string]bool{
+	"containerapp": true,
+	"appservice":   true,
+	"function":     true,
+}
+
+// NewDiffCloudCommand creates the diff-cloud command.
+func NewDiffCloudCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff-cloud",
+		Short: "Compare local services against their deployed Azure resources",
+		Long: `Compares the services, ports, and environment variable names defined in azure.yaml
+against the Container Apps and App Services provisioned by azd for the current environment,
+and reports drift such as services that are not deployed or env keys only set locally.
+
+Only env variable names are compared, and values are never printed. Azure returns App Service
+and Function app settings with their values (reading them needs the
+Microsoft.Web/sites/config/list/action permission); they are discarded on receipt, except
+WEBSITES_PORT.`,
+		Example: `  # Compare all services
+  azd app diff-cloud
+
+  # Compare specific services and fail when drift is found (CI)
+  azd app diff-cloud --service api,web --fail-on-drift
+
+  # Machine-readable output
+  azd app diff-cloud --output json`,
+		SilenceUsage: true,
+		RunE:         runDiffCloud,
+	}
+
+	cmd.Flags().StringVarP(&diffCloudServices, "service", "s", "", "Compare specific service(s) only (comma-separated)")
+	registerServiceFlagCompletion(cmd)
+	cmd.Flags().BoolVar(&diffCloudIncludePlatformEnv, "include-platform-env", false, "Include Azure-managed settings (e.g. WEBSITES_PORT) in the env comparison")
+	cmd.Flags().BoolVar(&diffCloudFailOnDrift, "fail-on-drift", false, "Exit with an error when drift is detected")
+
+	return cmd
+}
+
+// runDiffCloud executes the diff-cloud command.
+func runDiffCloud(cmd *cobra.Command, args []string) error {
+	cliout.CommandHeader("diff-cloud", "Compare local services against Azure")
+
+	azureYamlPath, err := findAzureYaml()
+	if err != nil {
+		return err
+	}
+	projectDir := filepath.Dir(azureYamlPath)
+
+	azureYaml, err := service.ParseAzureYaml(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to parse azure.yaml: %w", err)
+	}
+
+	filter := make(map[string]bool)
+	for _, name := range parseServiceFilter(diffCloudServices) {
+		if name != "" {
+			filter[azure.NormalizeServiceName(name)] = true
+		}
+	}
+	local := collectLocalServices(azureYaml.Services, filter)
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cred, err := azure.NewAzureCredential()
+	if err != nil {
+		return fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+
+	discovery, err := azure.NewResourceDiscovery(cred, projectDir).Discover(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to discover Azure resources: %w", err)
+	}
+	if discovery.SubscriptionID == "" || discovery.ResourceGroup == "" {
+		return fmt.Errorf("no provisioned Azure environment found - run 'azd provision' or 'azd up' first")
+	}
+
+	reader := azure.NewConfigReader(cred)
+	cloud := make([]azure.CloudService, 0, len(discovery.Resources))
+	for name, resource := range discovery.Resources {
+		if len(filter) > 0 && !filter[azure.NormalizeServiceName(name)] {
+			continue
+		}
+		cloud = append(cloud, reader.Read(ctx, resource))
+	}
+
+	ignore := azure.PlatformEnvKeys
+	if diffCloudIncludePlatformEnv {
+		ignore = nil
+	}
+
+	report := azure.Diff(local, cloud, ignore)
+	report.Environment = discovery.Environment
+
+	if cliout.IsJSON() {
+		if err := cliout.PrintJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printDriftReport(report)
+	}
+
+	if diffCloudFailOnDrift && report.HasDrift() {
+		return fmt.Errorf("drift detected between local services and environment %q", report.Environment)
+	}
+	return nil
+}
+
+// collectLocalServices builds the local view of deployable services from azure.yaml.
+// Services hosted locally or on unsupported hosts are skipped.
+func collectLocalServices(services map[string]service.Service, filter map[string]bool) []azure.LocalService {
+	local := make([]azure.LocalService, 0, len(services))
+	for name, svc := range services {
+		if !diffCloudHosts[strings.ToLower(svc.Host)] {
+			continue
+		}
+		if len(filter) > 0 && !filter[azure.NormalizeServiceName(name)] {
+			continue
+		}
+
+		// The deployed target port corresponds to the port the app listens on (the container port).
+		hostPort, containerPort, _ := svc.GetPrimaryPort()
+		port := containerPort
+		if port == 0 {
+			port = hostPort
+		}
+
+		env := svc.GetEnvironment()
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		local = append(local, azure.LocalService{Name: name, Port: port, EnvKeys: keys})
+	}
+	return local
+}
+
+// printDriftReport prints a drift report as a table followed by per-service details.
+func printDriftReport(report *azure.DriftReport) {
+	if len(report.Services) == 0 {
+		cliout.Info("No deployable services found to compare")
+		return
+	}
+
+	rows := make([]cliout.TableRow, 0, len(report.Services))
+	for _, s := range report.Services {
+		rows = append(rows, cliout.TableRow{
+			"Service":    s.Service,
+			"Status":     string(s.Status),
+			"Local Port": formatDriftPort(s.LocalPort),
+			"Cloud Port": formatDriftPort(s.CloudPort),
+			"Env Drift":  fmt.Sprintf("%d local / %d cloud", len(s.LocalOnlyEnv), len(s.CloudOnlyEnv)),
+		})
+	}
+	cliout.Table([]string{"Service", "Status", "Local Port", "Cloud Port", "Env Drift"}, rows)
+
+	for _, s := range report.Services {
+		switch s.Status {
+		case azure.DriftStatusLocalOnly:
+			cliout.ItemWarning("%s: defined in azure.yaml but not deployed", s.Service)
+		case azure.DriftStatusCloudOnly:
+			cliout.ItemWarning("%s: deployed but not defined in azure.yaml", s.Service)
+		case azure.DriftStatusUnreadable:
+			cliout.ItemError("%s: could not read deployed configuration: %s", s.Service, s.Error)
+		case azure.DriftStatusDrifted:
+			if s.PortDrift() {
+				cliout.ItemWarning("%s: port %d locally, %d in Azure", s.Service, s.LocalPort, s.CloudPort)
+			}
+			if len(s.LocalOnlyEnv) > 0 {
+				cliout.ItemWarning("%s: env only set locally: %s", s.Service, strings.Join(s.LocalOnlyEnv, ", "))
+			}
+			if len(s.CloudOnlyEnv) > 0 {
+				cliout.ItemWarning("%s: env only set in Azure: %s", s.Service, strings.Join(s.CloudOnlyEnv, ", "))
+			}
+		}
+	}
+
+	cliout.Newline()
+	if report.HasDrift() {
+		cliout.Warning("Drift detected against environment %q", report.Environment)
+	} else {
+		cliout.Success("Local services match environment %q", report.Environment)
+	}
+}
+
+func formatDriftPort(port int) string {
+	if port == 0 {
+		return "-"
+	}
+	return strconv.Itoa(port)
+}
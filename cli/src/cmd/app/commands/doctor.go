@@ -0,0 +1,487 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/devenv"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/pathutil"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorFix bool
+
+// DoctorIssue is a problem found by `azd app doctor`.
+type DoctorIssue struct {
+	Message  string `json:"message"`
+	Hint     string `json:"hint,omitempty"`
+	Fixable  bool   `json:"fixable"`
+	Fixed    bool   `json:"fixed,omitempty"`
+	FixError string `json:"fixError,omitempty"`
+
+	fix func() error // Repairs the issue; nil when it has to be fixed by hand
+}
+
+// DoctorCheck is the result of one `azd app doctor` check.
+type DoctorCheck struct {
+	Name    string        `json:"name"`
+	Title   string        `json:"title"`
+	Skipped string        `json:"skipped,omitempty"` // Why the check didn't run
+	Notes   []string      `json:"notes,omitempty"`   // Guidance that isn't a problem
+	Issues  []DoctorIssue `json:"issues"`
+}
+
+// DoctorReport is the output of `azd app doctor`.
+type DoctorReport struct {
+	Project string        `json:"project"`
+	Checks  []DoctorCheck `json:"checks"`
+	Issues  int           `json:"issues"`  // Issues found
+	Fixed   int           `json:"fixed"`   // Issues repaired with --fix
+	Fixable int           `json:"fixable"` // Unresolved issues --fix can repair
+	Healthy bool          `json:"healthy"` // No unresolved issues
+}
+
+// doctorPortManager is the part of the port manager the doctor checks use.
+type doctorPortManager interface {
+	Assignments() []portmanager.PortAssignment
+	ReleasePort(serviceName string) error
+	IsPortAvailable(port int) bool
+	ProcessOnPort(port int) (*portmanager.ProcessInfo, error)
+	KillProcessOnPort(port int) error
+}
+
+// NewDoctorCommand creates the doctor command.
+func NewDoctorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose and repair common problems with the local environment",
+		Long: `Checks the project for common broken states:
+
+  - .azure directory that is not a directory or not writable
+  - Corrupted cache files in .azure
+  - A dashboard registration left behind by an 'azd app run' that didn't exit cleanly
+  - Port assignments for services that are no longer in azure.yaml
+  - Service processes from a previous run still holding their ports
+  - Required tools that are installed but not on PATH
+  - WSL and devcontainer setups that make services slow or unreachable
+
+Use --fix to repair the issues that can be fixed automatically. Orphaned processes
+are only stopped when they are known dev servers (node, python, dotnet, ...); other
+processes are reported and left running.
+
+Examples:
+  # Diagnose problems
+  azd app doctor
+
+  # Diagnose and repair
+  azd app doctor --fix`,
+		SilenceUsage: true,
+		RunE:         runDoctor,
+	}
+
+	cmd.Flags().BoolVar(&doctorFix, "fix", false, "Repair the issues that can be fixed automatically")
+
+	return cmd
+}
+
+// runDoctor executes the doctor command.
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cliout.CommandHeader("doctor", "Diagnose common problems")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	projectDir := cwd
+	azureYamlPath, err := detector.FindAzureYaml(cwd)
+	if err != nil {
+		return fmt.Errorf("error searching for azure.yaml: %w", err)
+	}
+	if azureYamlPath != "" {
+		projectDir = filepath.Dir(azureYamlPath)
+	}
+
+	ctx := context.Background()
+	sessionActive := dashboard.IsDashboardRunning(ctx, projectDir)
+	portMgr := portmanager.GetPortManager(projectDir)
+
+	var services []string
+	hasContainers := false
+	if azureYamlPath != "" {
+		azureYaml, err := parseAzureYaml(azureYamlPath)
+		if err != nil {
+			return fmt.Errorf("failed to parse azure.yaml: %w", err)
+		}
+		for name, svc := range azureYaml.Services {
+			services = append(services, name)
+			hasContainers = hasContainers || svc.IsContainerService() || svc.Compose != "" || svc.Type == service.ServiceTypeAzurite
+		}
+		hasContainers = hasContainers || len(azureYaml.Containers) > 0
+	}
+
+	checks := []DoctorCheck{
+		checkAzureDir(projectDir),
+		checkCacheFiles(projectDir),
+		checkDashboardRegistration(ctx, projectDir, sessionActive),
+		checkPortAssignments(portMgr, services, azureYamlPath != ""),
+		checkOrphanedProcesses(portMgr, sessionActive),
+		checkToolPaths(doctorTools(projectDir, azureYamlPath), exec.LookPath, pathutil.SearchToolInSystemPath),
+		checkDevEnvironment(detectDevEnvironment(), projectDir, hasContainers),
+	}
+
+	if doctorFix {
+		applyDoctorFixes(checks)
+	}
+	report := newDoctorReport(projectDir, checks)
+
+	if cliout.IsJSON() {
+		if err := cliout.PrintJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printDoctorReport(report)
+	}
+
+	if !report.Healthy {
+		return fmt.Errorf("doctor found %d unresolved issue(s)", report.Issues-report.Fixed)
+	}
+	return nil
+}
+
+// checkAzureDir checks that the project's .azure directory, if it exists, is a writable directory.
+func checkAzureDir(projectDir string) DoctorCheck {
+	check := DoctorCheck{Name: "azure-dir", Title: ".azure directory"}
+	dir := filepath.Join(projectDir, ".azure")
+
+	info, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return check // Created on demand
+	}
+	if err != nil {
+		check.Issues = append(check.Issues, DoctorIssue{
+			Message: fmt.Sprintf("Cannot access %s: %v", dir, err),
+			Hint:    "Check the permissions of the project directory",
+		})
+		return check
+	}
+	if !info.IsDir() {
+		check.Issues = append(check.Issues, DoctorIssue{
+			Message: fmt.Sprintf("%s is a file, not a directory", dir),
+			Hint:    "Rename or remove it so azd can create the .azure directory",
+		})
+		return check
+	}
+
+	if err := checkDirWritable(dir); err != nil {
+		check.Issues = append(check.Issues, DoctorIssue{
+			Message: fmt.Sprintf("%s is not writable: %v", dir, err),
+			Hint:    "Logs, caches and environments are stored here; make it writable by your user",
+			fix: func() error {
+				// #nosec G302 -- directory permissions, matching the 0750 used when it is created
+				if err := os.Chmod(dir, info.Mode().Perm()|0o700); err != nil {
+					return err
+				}
+				return checkDirWritable(dir)
+			},
+		})
+	}
+	return check
+}
+
+// checkDirWritable creates and removes a temporary file in dir.
+func checkDirWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	_ = f.Close()
+	return os.Remove(name)
+}
+
+// checkCacheFiles checks that the JSON cache files in .azure can be read.
+// Corrupted files are deleted by --fix; they are rebuilt on the next run.
+func checkCacheFiles(projectDir string) DoctorCheck {
+	check := DoctorCheck{Name: "cache", Title: "Cache files"}
+	azureDir := filepath.Join(projectDir, ".azure")
+
+	files, _ := filepath.Glob(filepath.Join(azureDir, "cache", "*.json"))
+	files = append(files, filepath.Join(azureDir, "timings.json")) // Startup ETA history
+
+	for _, file := range files {
+		// #nosec G304 -- file is a cache file inside the project's .azure directory
+		data, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err == nil && json.Valid(data) {
+			continue
+		}
+
+		message := fmt.Sprintf("%s is corrupted", file)
+		if err != nil {
+			message = fmt.Sprintf("%s cannot be read: %v", file, err)
+		}
+		check.Issues = append(check.Issues, DoctorIssue{
+			Message: message,
+			Hint:    "Delete it; it is rebuilt automatically",
+			fix:     func() error { return os.Remove(file) },
+		})
+	}
+	return check
+}
+
+// checkDashboardRegistration finds a dashboard port registered for the project while no
+// dashboard is running, which makes other commands try to reach a dead session.
+func checkDashboardRegistration(ctx context.Context, projectDir string, sessionActive bool) DoctorCheck {
+	check := DoctorCheck{Name: "dashboard", Title: "Dashboard registration"}
+	if sessionActive {
+		return check
+	}
+
+	if port := dashboard.GetDashboardPort(ctx, projectDir); port > 0 {
+		check.Issues = append(check.Issues, DoctorIssue{
+			Message: fmt.Sprintf("Dashboard is registered on port %d but not responding", port),
+			Hint:    "A previous 'azd app run' didn't exit cleanly",
+			fix:     func() error { return dashboard.ClearDashboardPort(ctx, projectDir) },
+		})
+	}
+	return check
+}
+
+// checkPortAssignments finds saved port assignments for services that are no longer in azure.yaml.
+func checkPortAssignments(portMgr doctorPortManager, services []string, hasAzureYaml bool) DoctorCheck {
+	check := DoctorCheck{Name: "ports", Title: "Port assignments"}
+	if !hasAzureYaml {
+		check.Skipped = "no azure.yaml found"
+		return check
+	}
+
+	for _, assignment := range portMgr.Assignments() {
+		if assignment.ServiceName == constants.DashboardServiceName || slices.Contains(services, assignment.ServiceName) {
+			continue
+		}
+		serviceName := assignment.ServiceName
+		check.Issues = append(check.Issues, DoctorIssue{
+			Message: fmt.Sprintf("Port %d is assigned to '%s', which is not in azure.yaml", assignment.Port, serviceName),
+			Hint:    "Release the assignment so the port can be reused",
+			fix:     func() error { return portMgr.ReleasePort(serviceName) },
+		})
+	}
+	return check
+}
+
+// checkOrphanedProcesses finds processes holding the ports of the project's services while
+// no 'azd app run' session is active, usually left over from a session that crashed.
+func checkOrphanedProcesses(portMgr doctorPortManager, sessionActive bool) DoctorCheck {
+	check := DoctorCheck{Name: "processes", Title: "Orphaned service processes"}
+	if sessionActive {
+		check.Skipped = "an 'azd app run' session is active"
+		return check
+	}
+
+	for _, assignment := range portMgr.Assignments() {
+		if assignment.ServiceName == constants.DashboardServiceName || portMgr.IsPortAvailable(assignment.Port) {
+			continue
+		}
+
+		port := assignment.Port
+		holder := "a process"
+		if info, err := portMgr.ProcessOnPort(port); err == nil {
+			holder = fmt.Sprintf("%s (PID %d)", info.Name, info.PID)
+			if info.Name == "" {
+				holder = fmt.Sprintf("PID %d", info.PID)
+			}
+		}
+		check.Issues = append(check.Issues, DoctorIssue{
+			Message: fmt.Sprintf("Port %d of '%s' is held by %s, but no 'azd app run' session is active", port, assignment.ServiceName, holder),
+			Hint:    "Stop the process left over from a previous run",
+			fix:     func() error { return portMgr.KillProcessOnPort(port) },
+		})
+	}
+	return check
+}
+
+// checkToolPaths finds required tools that are installed but can't be found on PATH.
+func checkToolPaths(tools []string, lookPath func(string) (string, error), search func(string) string) DoctorCheck {
+	check := DoctorCheck{Name: "path", Title: "Tools on PATH"}
+	if len(tools) == 0 {
+		check.Skipped = "no required tools found"
+		return check
+	}
+
+	for _, tool := range tools {
+		if _, err := lookPath(tool); err == nil {
+			continue
+		}
+		if path := search(tool); path != "" {
+			check.Issues = append(check.Issues, DoctorIssue{
+				Message: fmt.Sprintf("%s is installed at %s but not on PATH", tool, path),
+				Hint:    fmt.Sprintf("Add %s to PATH, or restart your terminal if it was just installed", filepath.Dir(path)),
+			})
+			continue
+		}
+		check.Issues = append(check.Issues, DoctorIssue{
+			Message: fmt.Sprintf("%s is not installed", tool),
+			Hint:    "Run 'azd app reqs --install' to install missing tools",
+		})
+	}
+	return check
+}
+
+// checkDevEnvironment reports how services are reached in WSL or a devcontainer, and finds
+// setups that make them slow or unreachable: a project on a Windows drive in WSL, and
+// containers on the host's Docker daemon, which aren't reachable at localhost.
+func checkDevEnvironment(env devenv.Environment, projectDir string, hasContainers bool) DoctorCheck {
+	check := DoctorCheck{Name: "environment", Title: "Development environment"}
+	if env.Name() == "" {
+		check.Skipped = "not running in WSL or a devcontainer"
+		return check
+	}
+
+	check.Notes = append([]string{"Running in " + env.Name()}, env.Hints()...)
+	if env.WSL && devenv.IsWindowsMount(projectDir) {
+		check.Issues = append(check.Issues, DoctorIssue{
+			Message: fmt.Sprintf("The project is on a Windows drive (%s), which is slow to access from WSL", projectDir),
+			Hint:    "Move it into the Linux filesystem (e.g. ~/src) for faster installs, builds and file watching",
+		})
+	}
+	if env.HostDocker && hasContainers {
+		check.Issues = append(check.Issues, DoctorIssue{
+			Message: "Containers run on the host's Docker daemon, so their ports aren't reachable at localhost in this devcontainer",
+			Hint:    "Connect to them at host.docker.internal instead of localhost, or use the docker-in-docker devcontainer feature",
+		})
+	}
+	return check
+}
+
+// doctorTools returns the commands of the project's required tools: the reqs in azure.yaml,
+// or the tools detected from the project files when there is no azure.yaml.
+func doctorTools(projectDir, azureYamlPath string) []string {
+	var reqs []Prerequisite
+	if azureYamlPath != "" {
+		var azureYaml AzureYaml
+		if data, err := readFileSecure(azureYamlPath); err == nil && unmarshalYaml(data, &azureYaml) == nil {
+			reqs, _ = azureYaml.requirementsFor(nil)
+		}
+	} else if detected, err := detectProjectReqs(projectDir); err == nil {
+		for _, req := range detected {
+			reqs = append(reqs, Prerequisite{Name: req.Name})
+		}
+	}
+
+	checker := NewPrerequisiteChecker()
+	var tools []string
+	for _, req := range reqs {
+		if command := checker.getToolConfig(req).Command; command != "" && !slices.Contains(tools, command) {
+			tools = append(tools, command)
+		}
+	}
+	return tools
+}
+
+// applyDoctorFixes runs the fix of every fixable issue.
+func applyDoctorFixes(checks []DoctorCheck) {
+	for i := range checks {
+		for j := range checks[i].Issues {
+			issue := &checks[i].Issues[j]
+			if issue.fix == nil {
+				continue
+			}
+			if err := issue.fix(); err != nil {
+				issue.FixError = err.Error()
+				continue
+			}
+			issue.Fixed = true
+		}
+	}
+}
+
+// newDoctorReport summarizes the checks.
+func newDoctorReport(projectDir string, checks []DoctorCheck) *DoctorReport {
+	report := &DoctorReport{Project: projectDir, Checks: checks}
+	for i := range checks {
+		if checks[i].Issues == nil {
+			checks[i].Issues = []DoctorIssue{}
+		}
+		for j := range checks[i].Issues {
+			issue := &checks[i].Issues[j]
+			issue.Fixable = issue.fix != nil
+			report.Issues++
+			switch {
+			case issue.Fixed:
+				report.Fixed++
+			case issue.Fixable:
+				report.Fixable++
+			}
+		}
+	}
+	report.Healthy = report.Issues == report.Fixed
+	return report
+}
+
+// printDoctorNotes prints the guidance of a check.
+func printDoctorNotes(check DoctorCheck) {
+	for _, note := range check.Notes {
+		cliout.Info("   %s %s", cliout.IconBulb, note)
+	}
+}
+
+// printDoctorReport prints the doctor report in default format.
+func printDoctorReport(report *DoctorReport) {
+	cliout.Section(cliout.IconSearch, fmt.Sprintf("Checking %s", report.Project))
+	for _, check := range report.Checks {
+		switch {
+		case check.Skipped != "":
+			cliout.ItemInfo("%s: skipped (%s)", check.Title, check.Skipped)
+			continue
+		case len(check.Issues) == 0:
+			cliout.ItemSuccess("%s", check.Title)
+			printDoctorNotes(check)
+			continue
+		}
+		printDoctorNotes(check)
+
+		for _, issue := range check.Issues {
+			switch {
+			case issue.Fixed:
+				cliout.ItemSuccess("%s: fixed - %s", check.Title, issue.Message)
+			case issue.FixError != "":
+				cliout.ItemError("%s: %s (fix failed: %s)", check.Title, issue.Message, issue.FixError)
+			default:
+				cliout.ItemWarning("%s: %s", check.Title, issue.Message)
+				if issue.Hint != "" {
+					cliout.Info("   %s %s", cliout.IconBulb, issue.Hint)
+				}
+			}
+		}
+	}
+
+	cliout.Newline()
+	switch {
+	case report.Issues == 0:
+		cliout.Success("No problems found!")
+	case report.Healthy:
+		cliout.Success("Fixed %d issue(s)!", report.Fixed)
+	default:
+		cliout.Warning("Found %d issue(s), %d fixed", report.Issues, report.Fixed)
+		if report.Fixable > 0 && !doctorFix {
+			cliout.Info("%s Run 'azd app doctor --fix' to repair %d of them", cliout.IconBulb, report.Fixable)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-app/cli/src/internal/devenv"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+)
+
+// fakeDoctorPortManager is an in-memory doctorPortManager.
+type fakeDoctorPortManager struct {
+	assignments []portmanager.PortAssignment
+	inUse       map[int]*portmanager.ProcessInfo
+	released    []string
+	killed      []int
+}
+
+func (f *fakeDoctorPortManager) Assignments() []portmanager.PortAssignment {
+	return f.assignments
+}
+
+func (f *fakeDoctorPortManager) ReleasePort(serviceName string) error {
+	f.released = append(f.released, serviceName)
+	return nil
+}
+
+func (f *fakeDoctorPortManager) IsPortAvailable(port int) bool {
+	_, used := f.inUse[port]
+	return !used
+}
+
+func (f *fakeDoctorPortManager) ProcessOnPort(port int) (*portmanager.ProcessInfo, error) {
+	if info, ok := f.inUse[port]; ok {
+		return info, nil
+	}
+	return nil, errors.New("no process")
+}
+
+func (f *fakeDoctorPortManager) KillProcessOnPort(port int) error {
+	f.killed = append(f.killed, port)
+	return nil
+}
+
+func TestCheckAzureDir(t *testing.T) {
+	dir := t.TempDir()
+	if check := checkAzureDir(dir); len(check.Issues) != 0 {
+		t.Errorf("missing .azure should not be an issue, got %+v", check.Issues)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, ".azure"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if check := checkAzureDir(dir); len(check.Issues) != 0 {
+		t.Errorf("writable .azure should not be an issue, got %+v", check.Issues)
+	}
+
+	fileDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(fileDir, ".azure"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	check := checkAzureDir(fileDir)
+	if len(check.Issues) != 1 || check.Issues[0].fix != nil {
+		t.Errorf("a .azure file should be reported as an issue that can't be fixed, got %+v", check.Issues)
+	}
+}
+
+func TestCheckCacheFiles(t *testing.T) {
+	dir := t.TempDir()
+	cacheDir := filepath.Join(dir, ".azure", "cache")
+	if err := os.MkdirAll(cacheDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	valid := filepath.Join(cacheDir, "reqs_cache.json")
+	corrupted := filepath.Join(dir, ".azure", "timings.json")
+	if err := os.WriteFile(valid, []byte(`{"version":"1.1"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(corrupted, []byte(`{"services":`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	check := checkCacheFiles(dir)
+	if len(check.Issues) != 1 {
+		t.Fatalf("checkCacheFiles() found %d issues, want 1", len(check.Issues))
+	}
+	if !strings.Contains(check.Issues[0].Message, "timings.json") {
+		t.Errorf("issue = %q, want it to name timings.json", check.Issues[0].Message)
+	}
+
+	applyDoctorFixes([]DoctorCheck{check})
+	if _, err := os.Stat(corrupted); !os.IsNotExist(err) {
+		t.Error("--fix should delete the corrupted file")
+	}
+	if _, err := os.Stat(valid); err != nil {
+		t.Error("--fix should keep valid cache files")
+	}
+}
+
+func TestCheckPortAssignments(t *testing.T) {
+	portMgr := &fakeDoctorPortManager{assignments: []portmanager.PortAssignment{
+		{ServiceName: "api", Port: 3000},
+		{ServiceName: "old-worker", Port: 3001},
+		{ServiceName: constants.DashboardServiceName, Port: 43000},
+	}}
+
+	if check := checkPortAssignments(portMgr, nil, false); check.Skipped == "" {
+		t.Error("check should be skipped without azure.yaml")
+	}
+
+	check := checkPortAssignments(portMgr, []string{"api"}, true)
+	if len(check.Issues) != 1 || !strings.Contains(check.Issues[0].Message, "old-worker") {
+		t.Fatalf("checkPortAssignments() issues = %+v, want only old-worker", check.Issues)
+	}
+
+	applyDoctorFixes([]DoctorCheck{check})
+	if len(portMgr.released) != 1 || portMgr.released[0] != "old-worker" {
+		t.Errorf("released = %v, want [old-worker]", portMgr.released)
+	}
+}
+
+func TestCheckOrphanedProcesses(t *testing.T) {
+	portMgr := &fakeDoctorPortManager{
+		assignments: []portmanager.PortAssignment{
+			{ServiceName: "api", Port: 3000},
+			{ServiceName: "web", Port: 3001},
+			{ServiceName: constants.DashboardServiceName, Port: 43000},
+		},
+		inUse: map[int]*portmanager.ProcessInfo{
+			3000:  {PID: 4242, Name: "node"},
+			43000: {PID: 1, Name: "other"},
+		},
+	}
+
+	if check := checkOrphanedProcesses(portMgr, true); check.Skipped == "" || len(check.Issues) != 0 {
+		t.Errorf("check should be skipped while a session is active, got %+v", check)
+	}
+
+	check := checkOrphanedProcesses(portMgr, false)
+	if len(check.Issues) != 1 {
+		t.Fatalf("checkOrphanedProcesses() found %d issues, want 1", len(check.Issues))
+	}
+	if msg := check.Issues[0].Message; !strings.Contains(msg, "node (PID 4242)") || !strings.Contains(msg, "'api'") {
+		t.Errorf("issue = %q, want it to name the service and process", msg)
+	}
+
+	applyDoctorFixes([]DoctorCheck{check})
+	if len(portMgr.killed) != 1 || portMgr.killed[0] != 3000 {
+		t.Errorf("killed = %v, want [3000]", portMgr.killed)
+	}
+}
+
+func TestCheckToolPaths(t *testing.T) {
+	lookPath := func(tool string) (string, error) {
+		if tool == "node" {
+			return "/usr/bin/node", nil
+		}
+		return "", errors.New("not found")
+	}
+	search := func(tool string) string {
+		if tool == "go" {
+			return "/usr/local/go/bin/go"
+		}
+		return ""
+	}
+
+	check := checkToolPaths([]string{"node", "go", "dotnet"}, lookPath, search)
+	if len(check.Issues) != 2 {
+		t.Fatalf("checkToolPaths() found %d issues, want 2", len(check.Issues))
+	}
+	if !strings.Contains(check.Issues[0].Message, "not on PATH") || !strings.Contains(check.Issues[0].Hint, "/usr/local/go/bin") {
+		t.Errorf("go issue = %+v, want a hint to add its directory to PATH", check.Issues[0])
+	}
+	if !strings.Contains(check.Issues[1].Message, "dotnet is not installed") {
+		t.Errorf("dotnet issue = %+v, want it reported as not installed", check.Issues[1])
+	}
+
+	if check := checkToolPaths(nil, lookPath, search); check.Skipped == "" {
+		t.Error("check should be skipped without tools")
+	}
+}
+
+func TestCheckDevEnvironment(t *testing.T) {
+	if check := checkDevEnvironment(devenv.Environment{}, "/mnt/c/src/app", true); check.Skipped == "" {
+		t.Error("check should be skipped outside WSL and devcontainers")
+	}
+
+	wsl := devenv.Environment{WSL: true, WSLVersion: 2, WSLDistro: "Ubuntu"}
+	check := checkDevEnvironment(wsl, "/mnt/c/src/app", true)
+	if len(check.Issues) != 1 || !strings.Contains(check.Issues[0].Message, "Windows drive") {
+		t.Errorf("WSL issues = %+v, want the Windows drive reported", check.Issues)
+	}
+	if len(check.Notes) == 0 || !strings.Contains(check.Notes[0], "WSL2 (Ubuntu)") {
+		t.Errorf("WSL notes = %q, want the environment named", check.Notes)
+	}
+	if check := checkDevEnvironment(wsl, "/home/me/src/app", true); len(check.Issues) != 0 {
+		t.Errorf("issues in the Linux filesystem = %+v, want none", check.Issues)
+	}
+
+	dood := devenv.Environment{Devcontainer: true, HostDocker: true}
+	check = checkDevEnvironment(dood, "/workspaces/app", true)
+	if len(check.Issues) != 1 || !strings.Contains(check.Issues[0].Hint, "host.docker.internal") {
+		t.Errorf("devcontainer issues = %+v, want host.docker.internal guidance", check.Issues)
+	}
+	if check := checkDevEnvironment(dood, "/workspaces/app", false); len(check.Issues) != 0 {
+		t.Errorf("issues without containers = %+v, want none", check.Issues)
+	}
+}
+
+func TestNewDoctorReport(t *testing.T) {
+	failing := errors.New("permission denied")
+	checks := []DoctorCheck{
+		{Name: "ok"},
+		{Name: "cache", Issues: []DoctorIssue{
+			{Message: "fixed", fix: func() error { return nil }},
+			{Message: "fix fails", fix: func() error { return failing }},
+		}},
+		{Name: "path", Issues: []DoctorIssue{{Message: "manual"}}},
+	}
+
+	report := newDoctorReport("/project", checks)
+	if report.Issues != 3 || report.Fixed != 0 || report.Fixable != 2 || report.Healthy {
+		t.Errorf("report before fixes = %+v, want 3 issues, 2 fixable", report)
+	}
+	if report.Checks[0].Issues == nil {
+		t.Error("checks without issues should report an empty list")
+	}
+
+	applyDoctorFixes(checks)
+	report = newDoctorReport("/project", checks)
+	if report.Fixed != 1 || report.Fixable != 1 || report.Healthy {
+		t.Errorf("report after fixes = %+v, want 1 fixed and 1 fixable", report)
+	}
+	if checks[1].Issues[1].FixError != failing.Error() {
+		t.Errorf("FixError = %q, want %q", checks[1].Issues[1].FixError, failing.Error())
+	}
+
+	healthy := newDoctorReport("/project", []DoctorCheck{{Name: "ok"}})
+	if !healthy.Healthy {
+		t.Error("a report without issues should be healthy")
+	}
+}
@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jongio/azd-app/cli/src/internal/eventstream"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+
+	"github.com/spf13/cobra"
+)
+
+// eventStreamAnnotation marks the commands that support --output ndjson.
+const eventStreamAnnotation = "azd-app/eventstream"
+
+// supportEventStream marks cmd as emitting events with --output ndjson.
+func supportEventStream(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[eventStreamAnnotation] = "true"
+}
+
+// SetOutputFormat applies the --output flag for cmd.
+// With ndjson, stdout carries only the event stream and the human-readable output moves to stderr.
+func SetOutputFormat(cmd *cobra.Command, format string) error {
+	if format != eventstream.Format {
+		return cliout.SetFormat(format)
+	}
+	if cmd.Annotations[eventStreamAnnotation] == "" {
+		return fmt.Errorf("--output %s is not supported by '%s' (supported by: run, deps)", format, cmd.CommandPath())
+	}
+
+	eventstream.Enable(os.Stdout)
+	os.Stdout = os.Stderr
+	return cliout.SetFormat("default")
+}
+
+// registerEventStream emits the lifecycle events and log lines of the services started in
+// projectDir to the event stream. The returned function unregisters the handlers.
+func registerEventStream(projectDir string, runtimes []*service.ServiceRuntime) func() {
+	if !eventstream.Enabled() {
+		return func() {}
+	}
+
+	schemes := make(map[string]string, len(runtimes))
+	for _, rt := range runtimes {
+		schemes[rt.Name] = rt.URLScheme()
+	}
+
+	var mu sync.Mutex
+	ports := make(map[string]int)
+	subscriptions := make(map[*service.LogBuffer]chan service.LogEntry)
+
+	// A service's port is assigned before it starts, except for compose services, whose port
+	// is only known once their containers publish it
+	emitPort := func(info service.ServiceEventInfo) {
+		mu.Lock()
+		changed := info.Port > 0 && ports[info.Service] != info.Port
+		ports[info.Service] = info.Port
+		mu.Unlock()
+		if !changed {
+			return
+		}
+		scheme := schemes[info.Service]
+		if scheme == "" {
+			scheme = "http"
+		}
+		eventstream.Emit(eventstream.Event{
+			Event:   eventstream.TypePortAssigned,
+			Service: info.Service,
+			Port:    info.Port,
+			URL:     fmt.Sprintf("%s://localhost:%d", scheme, info.Port),
+			Time:    info.Time,
+		})
+	}
+
+	// Subscribe before the service starts so its first log lines aren't missed
+	subscribeLogs := func(name string) {
+		buffer, err := service.GetLogManager(projectDir).CreateBuffer(name, 1000, true)
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if _, ok := subscriptions[buffer]; ok {
+			return
+		}
+		ch := buffer.Subscribe()
+		subscriptions[buffer] = ch
+		go func() {
+			for entry := range ch {
+				eventstream.Emit(logLineEvent(entry))
+			}
+		}()
+	}
+
+	types := map[service.ServiceEvent]eventstream.Type{
+		service.EventServiceStarting: eventstream.TypeServiceStarting,
+		service.EventServiceStarted:  eventstream.TypeServiceStarted,
+		service.EventServiceReady:    eventstream.TypeServiceReady,
+		service.EventServiceStopped:  eventstream.TypeServiceStopped,
+		service.EventServiceCrashed:  eventstream.TypeServiceCrashed,
+	}
+	var unregister []func()
+	for event, eventType := range types {
+		unregister = append(unregister, service.OnServiceEvent(event, func(info service.ServiceEventInfo) {
+			switch event {
+			case service.EventServiceStarting:
+				subscribeLogs(info.Service)
+				emitPort(info)
+			case service.EventServiceStarted:
+				emitPort(info)
+			}
+
+			lifecycle := eventstream.Event{
+				Event:   eventType,
+				Service: info.Service,
+				Port:    info.Port,
+				PID:     info.PID,
+				Time:    info.Time,
+			}
+			if info.Error != nil {
+				lifecycle.Error = info.Error.Error()
+			}
+			eventstream.Emit(lifecycle)
+
+			// A crash is reported as stopped too, so failures are reported once
+			if event == service.EventServiceStopped {
+				eventstream.EmitError(info.Service, info.Error)
+			}
+		}))
+	}
+
+	return func() {
+		for _, fn := range unregister {
+			fn()
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for buffer, ch := range subscriptions {
+			buffer.Unsubscribe(ch)
+		}
+	}
+}
+
+// logLineEvent converts a service log entry to a log-line event.
+func logLineEvent(entry service.LogEntry) eventstream.Event {
+	stream := "stdout"
+	if entry.IsStderr {
+		stream = "stderr"
+	}
+	return eventstream.Event{
+		Event:   eventstream.TypeLogLine,
+		Service: entry.Service,
+		Stream:  stream,
+		Level:   strings.ToLower(entry.Level.String()),
+		Message: entry.Message,
+		Time:    entry.Timestamp,
+	}
+}
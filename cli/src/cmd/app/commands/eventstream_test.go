@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/eventstream"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+
+	"github.com/spf13/cobra"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of the log subscriptions.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) events(t *testing.T) []eventstream.Event {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var events []eventstream.Event
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var event eventstream.Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("line %q is not an event: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestSetOutputFormat(t *testing.T) {
+	stdout := os.Stdout
+	t.Cleanup(func() {
+		os.Stdout = stdout
+		eventstream.Disable()
+		_ = cliout.SetFormat("default")
+	})
+
+	unsupported := &cobra.Command{Use: "status"}
+	if err := SetOutputFormat(unsupported, eventstream.Format); err == nil {
+		t.Error("SetOutputFormat() should reject ndjson for commands without events")
+	}
+	if eventstream.Enabled() {
+		t.Error("SetOutputFormat() enabled the event stream for an unsupported command")
+	}
+	if err := SetOutputFormat(unsupported, "json"); err != nil || !cliout.IsJSON() {
+		t.Errorf("SetOutputFormat(json) error = %v, IsJSON = %v", err, cliout.IsJSON())
+	}
+
+	supported := &cobra.Command{Use: "run"}
+	supportEventStream(supported)
+	if err := SetOutputFormat(supported, eventstream.Format); err != nil {
+		t.Fatalf("SetOutputFormat(ndjson) error = %v", err)
+	}
+	if !eventstream.Enabled() || cliout.IsJSON() {
+		t.Error("SetOutputFormat(ndjson) should enable the event stream with human-readable output")
+	}
+	if os.Stdout != os.Stderr {
+		t.Error("SetOutputFormat(ndjson) should move the human-readable output to stderr")
+	}
+}
+
+func TestRegisterEventStream(t *testing.T) {
+	var out syncBuffer
+	eventstream.Enable(&out)
+	t.Cleanup(eventstream.Disable)
+
+	projectDir := t.TempDir()
+	name := "eventstream-api"
+	unregister := registerEventStream(projectDir, []*service.ServiceRuntime{{Name: name, Port: 8123}})
+
+	service.EmitServiceEvent(service.ServiceEventInfo{Event: service.EventServiceStarting, Service: name, Port: 8123})
+	buffer, ok := service.GetLogManager(projectDir).GetBuffer(name)
+	if !ok {
+		t.Fatal("registerEventStream() didn't create the service's log buffer")
+	}
+	buffer.Add(service.NewLogEntry(name, "listening on 8123", true))
+	service.EmitServiceEvent(service.ServiceEventInfo{Event: service.EventServiceStopped, Service: name, Error: errors.New("exit status 1")})
+
+	// Log lines are forwarded asynchronously
+	deadline := time.Now().Add(2 * time.Second)
+	for len(out.events(t)) < 5 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	unregister()
+
+	got := map[eventstream.Type]eventstream.Event{}
+	for _, event := range out.events(t) {
+		got[event.Event] = event
+	}
+	if e := got[eventstream.TypePortAssigned]; e.Port != 8123 || e.URL != "http://localhost:8123" {
+		t.Errorf("port-assigned = %+v", e)
+	}
+	if e, ok := got[eventstream.TypeServiceStarting]; !ok || e.Service != name {
+		t.Errorf("service-starting = %+v", e)
+	}
+	if e := got[eventstream.TypeLogLine]; e.Message != "listening on 8123" || e.Stream != "stderr" {
+		t.Errorf("log-line = %+v", e)
+	}
+	if e := got[eventstream.TypeServiceStopped]; e.Error != "exit status 1" {
+		t.Errorf("service-stopped = %+v", e)
+	}
+	if e := got[eventstream.TypeError]; e.Service != name || e.Error != "exit status 1" {
+		t.Errorf("error = %+v", e)
+	}
+
+	// Nothing is reported once unregistered
+	before := len(out.events(t))
+	service.EmitServiceEvent(service.ServiceEventInfo{Event: service.EventServiceStarting, Service: name, Port: 8123})
+	if after := len(out.events(t)); after != before {
+		t.Errorf("registerEventStream() still reports events after unregistering: %d, want %d", after, before)
+	}
+}
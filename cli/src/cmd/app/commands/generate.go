@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/service"
 	"github.com/jongio/azd-core/cliout"
 	"github.com/jongio/azd-core/security"
 	"github.com/jongio/azd-core/yamlutil"
@@ -46,7 +47,10 @@ const (
 	langPython = "python"
 	langDotnet = "dotnet"
 	pkgPNPM    = "pnpm"
+	pkgBun     = "bun"
+	pkgDeno    = "deno"
 	pkgPoetry  = "poetry"
+	pkgConda   = "conda"
 )
 
 // runGenerate is the main entry point for the generate command.
@@ -122,18 +126,21 @@ func detectProjectReqs(projectDir string) ([]DetectedRequirement, error) { //nol
 	var requirements []DetectedRequirement
 	foundSources := make(map[string]bool)
 
-	// Detect Node.js projects
-	if hasPackageJSON(projectDir) {
+	// Detect Node.js projects, including Bun projects, and Deno projects
+	if hasPackageJSON(projectDir) || detector.HasDenoConfig(projectDir) {
 		foundSources["Node.js"] = true
+		pkgMgrReq := detectNodePackageManager(projectDir)
 
-		// Add Node.js
-		if req := detectNode(projectDir); req.Name != "" {
-			requirements = append(requirements, req)
+		// Add Node.js (Deno is a standalone runtime and doesn't need it)
+		if pkgMgrReq.Name != pkgDeno {
+			if req := detectNode(projectDir); req.Name != "" {
+				requirements = append(requirements, req)
+			}
 		}
 
 		// Add package manager
-		if req := detectNodePackageManager(projectDir); req.Name != "" {
-			requirements = append(requirements, req)
+		if pkgMgrReq.Name != "" {
+			requirements = append(requirements, pkgMgrReq)
 		}
 	}
 
@@ -152,6 +159,34 @@ func detectProjectReqs(projectDir string) ([]DetectedRequirement, error) { //nol
 		}
 	}
 
+	// Detect Go projects
+	if detector.HasGoMod(projectDir) {
+		foundSources["Go"] = true
+		if req := detectGo(projectDir); req.Name != "" {
+			requirements = append(requirements, req)
+		}
+	}
+
+	// Detect Rust projects
+	if detector.HasCargoToml(projectDir) {
+		foundSources["Rust"] = true
+		for _, req := range detectRust(projectDir) {
+			if req.Name != "" {
+				requirements = append(requirements, req)
+			}
+		}
+	}
+
+	// Detect Java projects
+	if detector.DetectJavaBuildTool(projectDir) != "" {
+		foundSources["Java"] = true
+		for _, req := range detectJavaProject(projectDir) {
+			if req.Name != "" {
+				requirements = append(requirements, req)
+			}
+		}
+	}
+
 	// Detect .NET projects
 	if hasDotnetProject(projectDir) {
 		foundSources[".NET"] = true
@@ -178,6 +213,34 @@ func detectProjectReqs(projectDir string) ([]DetectedRequirement, error) { //nol
 		}
 	}
 
+	// Detect infrastructure as code
+	if terraformDir := detector.TerraformDir(projectDir); terraformDir != "" {
+		foundSources["Terraform"] = true
+		if req := detectTerraform(terraformDir); req.Name != "" {
+			requirements = append(requirements, req)
+		}
+	}
+	if detector.HasBicepFiles(projectDir) {
+		foundSources["Bicep"] = true
+		if req := detectBicep(projectDir); req.Name != "" {
+			requirements = append(requirements, req)
+		}
+	}
+
+	// Detect Kubernetes deployments
+	if detector.HasKubernetesManifests(projectDir) || hasAksService(projectDir) {
+		foundSources["Kubernetes"] = true
+		if req := detectKubectl(projectDir); req.Name != "" {
+			requirements = append(requirements, req)
+		}
+	}
+	if detector.HasHelmChart(projectDir) {
+		foundSources["Helm"] = true
+		if req := detectHelm(projectDir); req.Name != "" {
+			requirements = append(requirements, req)
+		}
+	}
+
 	// Detect Azure Functions projects (all variants including Logic Apps)
 	functionApps, _ := detector.FindFunctionApps(projectDir)
 	if len(functionApps) > 0 {
@@ -245,6 +308,10 @@ func detectProjectReqs(projectDir string) ([]DetectedRequirement, error) { //nol
 					}
 				}
 			case "java":
+				// Java already detected above if pom.xml or build.gradle exists
+				if detector.DetectJavaBuildTool(projectDir) != "" {
+					continue
+				}
 				// Add Java requirements (JDK and Maven/Gradle)
 				if req := detectJava(projectDir); req.Name != "" {
 					requirements = append(requirements, req)
@@ -285,7 +352,7 @@ func hasPackageJSON(dir string) bool {
 }
 
 func hasPythonProject(dir string) bool {
-	files := []string{"requirements.txt", "pyproject.toml", "poetry.lock", "uv.lock", "Pipfile"}
+	files := []string{"requirements.txt", "pyproject.toml", "poetry.lock", "uv.lock", "Pipfile", "environment.yml", "environment.yaml"}
 	for _, file := range files {
 		path := filepath.Join(dir, file)
 		if err := security.ValidatePath(path); err != nil {
@@ -335,6 +402,24 @@ func hasAzureYaml(dir string) bool {
 	return path != ""
 }
 
+// hasAksService reports whether azure.yaml defines a service hosted on Azure Kubernetes Service.
+func hasAksService(dir string) bool {
+	path, _ := detector.FindAzureYaml(dir)
+	if path == "" {
+		return false
+	}
+	azureYaml, err := service.ParseAzureYaml(path)
+	if err != nil {
+		return false
+	}
+	for _, svc := range azureYaml.Services {
+		if svc.Host == "aks" {
+			return true
+		}
+	}
+	return false
+}
+
 func hasGit(dir string) bool {
 	path := filepath.Join(dir, ".git")
 	if err := security.ValidatePath(path); err != nil {
@@ -365,6 +450,27 @@ func detectPythonPackageManager(projectDir string) DetectedRequirement {
 	return detectTool(info.Name, info.Source)
 }
 
+func detectGo(projectDir string) DetectedRequirement {
+	req := detectToolWithSource("go", "go.mod", false)
+	// The go directive is the oldest toolchain that can build the module
+	if version := detector.GoVersionFromGoMod(projectDir); version != "" {
+		req.MinVersion = version
+	}
+	return req
+}
+
+// detectRust returns the rust toolchain and cargo requirements for a Cargo package or workspace.
+func detectRust(projectDir string) []DetectedRequirement {
+	rust := detectToolWithSource("rust", "Cargo.toml", false)
+	cargo := detectToolWithSource("cargo", "Cargo.toml", false)
+	// rust-version is the oldest toolchain the crate supports, and cargo ships with it
+	if version := detector.CargoRustVersion(projectDir); version != "" {
+		rust.MinVersion = version
+		cargo.MinVersion = version
+	}
+	return []DetectedRequirement{rust, cargo}
+}
+
 func detectDotnet(_ string) DetectedRequirement {
 	return detectToolWithSource(langDotnet, ".csproj or .sln", false)
 }
@@ -385,6 +491,22 @@ func detectJava(_ string) DetectedRequirement {
 	return detectToolWithSource("java", "Java Functions project", false)
 }
 
+// detectJavaProject returns the JDK requirement for a Maven or Gradle build, plus mvn or gradle
+// unless the project has a wrapper (mvnw/gradlew) that downloads the build tool itself.
+func detectJavaProject(projectDir string) []DetectedRequirement {
+	buildTool := detector.DetectJavaBuildTool(projectDir)
+	tool, source := "mvn", "pom.xml"
+	if buildTool == detector.BuildToolGradle {
+		tool, source = "gradle", "build.gradle"
+	}
+
+	reqs := []DetectedRequirement{detectToolWithSource("java", source, false)}
+	if detector.JavaWrapper(projectDir, buildTool) == "" {
+		reqs = append(reqs, detectToolWithSource(tool, source, false))
+	}
+	return reqs
+}
+
 func detectJavaBuildTool(projectDir string) DetectedRequirement {
 	// Check for Maven
 	pomPath := filepath.Join(projectDir, "pom.xml")
@@ -409,6 +531,27 @@ func detectJavaBuildTool(projectDir string) DetectedRequirement {
 	return DetectedRequirement{}
 }
 
+func detectTerraform(terraformDir string) DetectedRequirement {
+	req := detectToolWithSource("terraform", "*.tf", false)
+	// required_version is the oldest Terraform the configuration supports
+	if version := detector.TerraformRequiredVersion(terraformDir); version != "" {
+		req.MinVersion = version
+	}
+	return req
+}
+
+func detectBicep(_ string) DetectedRequirement {
+	return detectToolWithSource("bicep", "*.bicep", false)
+}
+
+func detectKubectl(_ string) DetectedRequirement {
+	return detectToolWithSource("kubectl", "Kubernetes manifests or aks service", false)
+}
+
+func detectHelm(_ string) DetectedRequirement {
+	return detectToolWithSource("helm", "Chart.yaml", false)
+}
+
 func detectAzd(_ string) DetectedRequirement {
 	return detectToolWithSource("azd", "azure.yaml", false)
 }
@@ -539,26 +682,31 @@ func normalizeVersion(installedVersion string, toolName string) string {
 	parts := strings.Split(installedVersion, ".")
 
 	switch toolName {
-	case "node", langDotnet, "go", "rust", "docker", "git":
+	case "node", langDotnet, "java", "docker", "git", "func", "helm":
 		// Major version only: "22.3.0" -> "22.0.0"
 		if len(parts) >= 1 {
 			return parts[0] + ".0.0"
 		}
-	case langPython:
-		// Major.Minor version: "3.12.5" -> "3.12.0"
+	case langPython, "go", "rust", "cargo":
+		// Major.Minor version: "3.12.5" -> "3.12.0", "1.22.3" -> "1.22.0"
 		if len(parts) >= 2 {
 			return parts[0] + "." + parts[1] + ".0"
 		}
-	case pkgPNPM, "npm", "yarn", pkgPoetry, "uv", "pip", "pipenv":
+	case pkgPNPM, "npm", "yarn", pkgBun, pkgDeno, pkgPoetry, "uv", "pip", "pipenv", pkgConda, "mvn", "gradle":
 		// Major version for package managers: "9.1.4" -> "9.0.0"
 		if len(parts) >= 1 {
 			return parts[0] + ".0.0"
 		}
-	case "azd", "az", "aspire":
+	case "azd", "az", "aspire", "bicep":
 		// Major.Minor for Azure tools: "1.5.3" -> "1.5.0"
 		if len(parts) >= 2 {
 			return parts[0] + "." + parts[1] + ".0"
 		}
+	case "terraform", "kubectl":
+		// Major.Minor for infrastructure tools, which add features in minor releases: "1.9.5" -> "1.9.0"
+		if len(parts) >= 2 {
+			return parts[0] + "." + parts[1] + ".0"
+		}
 	default:
 		// Default: use as-is
 		return installedVersion
@@ -591,7 +739,7 @@ func displayDetectedDependencies(requirements []DetectedRequirement) {
 			if req.Name == "node" {
 				// Look for package manager in other requirements
 				for _, r := range requirements {
-					if r.Name == pkgPNPM || r.Name == "yarn" || r.Name == "npm" {
+					if r.Name == pkgPNPM || r.Name == "yarn" || r.Name == "npm" || r.Name == pkgBun {
 						pkgMgr = r.Name
 						break
 					}
@@ -600,6 +748,14 @@ func displayDetectedDependencies(requirements []DetectedRequirement) {
 			if req.Name == "node" || req.Name == "npm" || req.Name == pkgPNPM || req.Name == "yarn" {
 				sources[fmt.Sprintf("Node.js project (%s)", pkgMgr)] = true
 			}
+		} else if req.Source == "go.mod" {
+			sources["Go project"] = true
+		} else if req.Source == "Cargo.toml" {
+			sources["Rust project"] = true
+		} else if req.Source == "pom.xml" || strings.HasPrefix(req.Source, "build.gradle") {
+			sources["Java project"] = true
+		} else if strings.Contains(req.Source, "deno.json") {
+			sources["Deno project"] = true
 		} else if strings.Contains(req.Source, "AppHost.cs") {
 			sources[".NET Aspire project"] = true
 		} else if strings.Contains(req.Source, ".csproj") || strings.Contains(req.Source, ".sln") {
@@ -608,17 +764,17 @@ func displayDetectedDependencies(requirements []DetectedRequirement) {
 			}
 		} else if strings.Contains(req.Source, "docker") || strings.Contains(req.Source, "Dockerfile") {
 			sources["Docker configuration"] = true
-		} else if strings.Contains(req.Source, "requirements.txt") || strings.Contains(req.Source, "pyproject.toml") {
+		} else if strings.Contains(req.Source, "requirements.txt") || strings.Contains(req.Source, "pyproject.toml") || strings.Contains(req.Source, "environment.y") {
 			pkgMgr := req.Name
 			if req.Name == langPython {
 				for _, r := range requirements {
-					if r.Name == pkgPoetry || r.Name == "uv" || r.Name == "pip" || r.Name == "pipenv" {
+					if r.Name == pkgPoetry || r.Name == "uv" || r.Name == "pip" || r.Name == "pipenv" || r.Name == pkgConda {
 						pkgMgr = r.Name
 						break
 					}
 				}
 			}
-			if req.Name == langPython || req.Name == "pip" || req.Name == pkgPoetry || req.Name == "uv" || req.Name == "pipenv" {
+			if req.Name == langPython || req.Name == "pip" || req.Name == pkgPoetry || req.Name == "uv" || req.Name == "pipenv" || req.Name == pkgConda {
 				sources[fmt.Sprintf("Python project (%s)", pkgMgr)] = true
 			}
 		}
@@ -661,10 +817,16 @@ func displayDetectedReqs(reqs []DetectedRequirement) {
 				switch req.Name {
 				case pkgPNPM:
 					cliout.Item("     Install: npm install -g pnpm")
+				case pkgBun:
+					cliout.Item("     Install: curl -fsSL https://bun.sh/install | bash")
+				case pkgDeno:
+					cliout.Item("     Install: curl -fsSL https://deno.land/install.sh | sh")
 				case pkgPoetry:
 					cliout.Item("     Install: curl -sSL https://install.python-poetry.org | python3 -")
 				case "uv":
 					cliout.Item("     Install: curl -LsSf https://astral.sh/uv/install.sh | sh")
+				case "rust", "cargo":
+					cliout.Item("     Install: curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh")
 				}
 			}
 		}
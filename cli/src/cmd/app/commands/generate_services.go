@@ -0,0 +1,507 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/security"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Hosts written for generated services.
+const (
+	generatedHostContainerApp = "containerapp"
+	generatedHostFunction     = "function"
+)
+
+var generateDryRun bool
+
+// servicesSectionPattern matches the top-level services key of azure.yaml, which may be an empty flow mapping.
+var servicesSectionPattern = regexp.MustCompile(`^services:\s*(\{\s*\})?\s*(#.*)?$`)
+
+// serviceNameInvalidChars matches the characters that can't be used in generated service names.
+var serviceNameInvalidChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// GeneratedService is a service detected in the project by `azd app generate services`.
+type GeneratedService struct {
+	Name      string `json:"name"`
+	Project   string `json:"project"`             // Relative to azure.yaml, e.g. "./src/api"
+	Language  string `json:"language,omitempty"`  // azure.yaml language, e.g. "ts" or "python"
+	Framework string `json:"framework,omitempty"` // Detected framework, e.g. "Next.js"
+	Host      string `json:"host"`
+	Port      int    `json:"port,omitempty"` // Preferred port of the framework, if it has one
+}
+
+// GenerateServicesResult is the output of `azd app generate services`.
+type GenerateServicesResult struct {
+	AzureYamlPath string             `json:"azureYamlPath"`
+	Created       bool               `json:"created"`  // azure.yaml was (or would be) created
+	DryRun        bool               `json:"dryRun"`   // Nothing was written
+	Services      []GeneratedService `json:"services"` // Services added to azure.yaml
+	Skipped       []string           `json:"skipped"`  // Detected projects azure.yaml already has a service for
+}
+
+// detectedProject is a project found by the detectors, before its service is generated.
+type detectedProject struct {
+	dir      string
+	language string // Language hint for framework detection, "" to detect it
+	host     string
+}
+
+// NewGenerateCommand creates the generate command.
+func NewGenerateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate azure.yaml sections from the project",
+		Long: `Scans the project and writes what it detects into azure.yaml, keeping its comments
+and formatting. Use 'azd app reqs --generate' to generate the reqs section.
+
+Examples:
+  # Add a service for each detected project to azure.yaml
+  azd app generate services
+
+  # Show the services that would be added
+  azd app generate services --dry-run`,
+	}
+
+	cmd.AddCommand(newGenerateServicesCmd())
+
+	return cmd
+}
+
+func newGenerateServicesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "services",
+		Short: "Add a service for each detected project to azure.yaml",
+		Long: `Scans the project for Node.js, Python, .NET, Aspire, Java, Go, Rust and Azure Functions
+projects and adds a service for each to the services section of azure.yaml, with its
+project path, language, host and the preferred port of its framework. The detected
+framework is noted in a comment.
+
+Projects azure.yaml already has a service for are skipped, and the rest of the file,
+including its comments, is left as is. azure.yaml is created if the project has none.
+When the project has an Aspire AppHost, only the AppHost is added: it runs the other
+.NET projects.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
+			return runGenerateServices(cwd, generateDryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&generateDryRun, "dry-run", false, "Show the services that would be added without changing azure.yaml")
+
+	return cmd
+}
+
+// runGenerateServices adds a service for each project detected next to the azure.yaml of
+// workingDir, or in workingDir when there's no azure.yaml yet.
+func runGenerateServices(workingDir string, dryRun bool) error {
+	cliout.CommandHeader("generate services", "Generate services from project")
+
+	azureYamlPath, created := filepath.Join(workingDir, "azure.yaml"), true
+	if existingPath, err := detector.FindAzureYaml(workingDir); err == nil && existingPath != "" {
+		azureYamlPath, created = existingPath, false
+	}
+	if err := security.ValidatePath(azureYamlPath); err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+	rootDir := filepath.Dir(azureYamlPath)
+
+	content := fmt.Sprintf(`# This file was auto-generated by azd app generate services
+# Customize as needed for your project
+
+name: %s
+
+services:
+`, filepath.Base(rootDir))
+	if !created {
+		// #nosec G304 -- Path validated by security.ValidatePath
+		data, err := os.ReadFile(azureYamlPath)
+		if err != nil {
+			return fmt.Errorf("failed to read azure.yaml: %w", err)
+		}
+		content = string(data)
+	}
+
+	if !cliout.IsJSON() {
+		cliout.Section("🔍", "Scanning project for services")
+	}
+	services := detectServices(rootDir)
+
+	newContent, added, skipped, err := mergeServices(content, rootDir, services)
+	if err != nil {
+		return fmt.Errorf("failed to merge services: %w", err)
+	}
+
+	if len(added) > 0 && !dryRun {
+		// #nosec G306 -- azure.yaml is a config file, 0644 is appropriate for team access
+		if err := os.WriteFile(azureYamlPath, []byte(newContent), 0644); err != nil {
+			return fmt.Errorf("failed to write azure.yaml: %w", err)
+		}
+	}
+
+	result := GenerateServicesResult{
+		AzureYamlPath: azureYamlPath,
+		Created:       created && len(added) > 0,
+		DryRun:        dryRun,
+		Services:      added,
+		Skipped:       skipped,
+	}
+	if cliout.IsJSON() {
+		return cliout.PrintJSON(result)
+	}
+	displayGeneratedServices(result, len(services))
+	return nil
+}
+
+// displayGeneratedServices prints the services added to azure.yaml.
+func displayGeneratedServices(result GenerateServicesResult, detected int) {
+	if detected == 0 {
+		cliout.Warning("No projects detected in %s", filepath.Dir(result.AzureYamlPath))
+		cliout.Item("Supported project types: Node.js, Python, .NET, Aspire, Java, Go, Rust and Azure Functions")
+		return
+	}
+
+	for _, svc := range result.Services {
+		details := []string{svc.Project}
+		if svc.Framework != "" {
+			details = append(details, svc.Framework)
+		}
+		if svc.Port > 0 {
+			details = append(details, fmt.Sprintf("port %d", svc.Port))
+		}
+		cliout.Item("%s (%s)", svc.Name, strings.Join(details, ", "))
+	}
+	for _, project := range result.Skipped {
+		cliout.Item("%s already has a service", project)
+	}
+	cliout.Newline()
+
+	switch {
+	case len(result.Services) == 0:
+		cliout.Info("azure.yaml already has a service for each detected project")
+	case result.DryRun:
+		cliout.Info("Would add %d service(s) to %s", len(result.Services), result.AzureYamlPath)
+		cliout.Item("Run without --dry-run to apply changes.")
+	case result.Created:
+		cliout.Success("Created azure.yaml with %d service(s)", len(result.Services))
+	default:
+		cliout.Success("Added %d service(s) to azure.yaml", len(result.Services))
+	}
+	if !result.DryRun && len(result.Services) > 0 {
+		cliout.Label("Path", result.AzureYamlPath)
+		cliout.Item("Run 'azd app run' to start them.")
+	}
+}
+
+// detectServices returns a service for each project in rootDir, ordered by project path.
+// Directories with several kinds of projects get one service, for the first kind detected.
+func detectServices(rootDir string) []GeneratedService {
+	projects := findServiceProjects(rootDir)
+
+	services := make([]GeneratedService, 0, len(projects))
+	usedNames := make(map[string]bool)
+	for _, project := range projects {
+		language, framework, _, err := service.DetectFramework(project.dir, project.language, project.host)
+		if err != nil {
+			continue
+		}
+
+		rel, err := filepath.Rel(rootDir, project.dir)
+		if err != nil {
+			continue
+		}
+		svc := GeneratedService{
+			Name:      uniqueServiceName(serviceNameFromDir(project.dir), usedNames),
+			Project:   azureYamlProjectPath(rel),
+			Language:  azureYamlLanguage(language),
+			Framework: framework,
+			Host:      project.host,
+		}
+		// The port of Functions apps comes from local.settings.json and the AppHost runs the dashboard
+		if project.host != generatedHostFunction && framework != service.FrameworkAspire.Name {
+			svc.Port = service.FrameworkDefaultPort(framework, language)
+		}
+		services = append(services, svc)
+	}
+	return services
+}
+
+// findServiceProjects returns the directories of the projects in rootDir that run as services.
+func findServiceProjects(rootDir string) []detectedProject {
+	var projects []detectedProject
+	seen := make(map[string]bool)
+	add := func(dir, language, host string) {
+		dir = filepath.Clean(dir)
+		if seen[dir] {
+			return
+		}
+		seen[dir] = true
+		projects = append(projects, detectedProject{dir: dir, language: language, host: host})
+	}
+
+	// Functions apps are detected first so their directories get the function host
+	functionApps, _ := detector.FindFunctionApps(rootDir)
+	for _, app := range functionApps {
+		add(app.Dir, "", generatedHostFunction)
+	}
+
+	appHost, _ := detector.FindAppHost(rootDir)
+	if appHost != nil {
+		add(appHost.Dir, langDotnet, generatedHostContainerApp)
+	}
+
+	nodeProjects, _ := detector.FindNodeProjects(rootDir)
+	for _, p := range nodeProjects {
+		// The packages of a workspace are the services, not its root
+		if !p.IsWorkspaceRoot {
+			add(p.Dir, "", generatedHostContainerApp)
+		}
+	}
+	pythonProjects, _ := detector.FindPythonProjects(rootDir)
+	for _, p := range pythonProjects {
+		add(p.Dir, langPython, generatedHostContainerApp)
+	}
+	goProjects, _ := detector.FindGoProjects(rootDir)
+	for _, p := range goProjects {
+		add(p.Dir, "go", generatedHostContainerApp)
+	}
+	rustProjects, _ := detector.FindRustProjects(rootDir)
+	for _, p := range rustProjects {
+		if !p.Workspace {
+			add(p.Dir, "rust", generatedHostContainerApp)
+		}
+	}
+	javaProjects, _ := detector.FindJavaProjects(rootDir)
+	for _, p := range javaProjects {
+		add(p.Dir, "java", generatedHostContainerApp)
+	}
+
+	// The AppHost runs the .NET projects of its app model
+	if appHost == nil {
+		dotnetProjects, _ := detector.FindDotnetProjects(rootDir)
+		for _, p := range dotnetProjects {
+			if isDotnetServiceProject(p.Path) {
+				add(filepath.Dir(p.Path), langDotnet, generatedHostContainerApp)
+			}
+		}
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool { return projects[i].dir < projects[j].dir })
+	return projects
+}
+
+// isDotnetServiceProject reports whether a .NET project file is a project that can run as
+// a service, rather than a solution or a test project.
+func isDotnetServiceProject(path string) bool {
+	if filepath.Ext(path) == ".sln" {
+		return false
+	}
+	name := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	return !strings.HasSuffix(name, "tests") && !strings.HasSuffix(name, ".test")
+}
+
+// serviceNameFromDir returns a service name for the project in dir: its directory name in
+// lowercase, with characters azure.yaml service names can't have replaced by dashes.
+func serviceNameFromDir(dir string) string {
+	name := serviceNameInvalidChars.ReplaceAllString(strings.ToLower(filepath.Base(dir)), "-")
+	if name = strings.Trim(name, "-"); name == "" {
+		return "app"
+	}
+	return name
+}
+
+// uniqueServiceName returns name, or name with a numeric suffix if it's already used, and marks it used.
+func uniqueServiceName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// azureYamlProjectPath returns the project path of a service in the form azure.yaml uses, e.g. "./src/api".
+func azureYamlProjectPath(rel string) string {
+	rel = filepath.ToSlash(rel)
+	if rel == "." {
+		return "."
+	}
+	return "./" + rel
+}
+
+// azureYamlLanguage returns the azure.yaml language of a detected language, or "" if it has none.
+func azureYamlLanguage(language string) string {
+	switch language {
+	case "JavaScript":
+		return "js"
+	case "TypeScript":
+		return "ts"
+	case "Python":
+		return langPython
+	case ".NET":
+		return langDotnet
+	case "Java":
+		return "java"
+	case "Go":
+		return "go"
+	case "Rust":
+		return "rust"
+	case "PHP":
+		return "php"
+	case "Docker":
+		return toolDocker
+	default:
+		return ""
+	}
+}
+
+// mergeServices adds the detected services azure.yaml doesn't have to its services section
+// using text-based manipulation, so no comments or formatting are lost. A detected service
+// is skipped when a service already uses its project directory; one whose name is taken
+// gets a numeric suffix, and one whose preferred port is taken gets the next free port.
+// It returns the new content, the services added and the project paths skipped.
+func mergeServices(content, rootDir string, detected []GeneratedService) (string, []GeneratedService, []string, error) {
+	var azureYaml struct {
+		Services map[string]struct {
+			Project string        `yaml:"project"`
+			Ports   []interface{} `yaml:"ports"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &azureYaml); err != nil {
+		return "", nil, nil, fmt.Errorf("failed to parse azure.yaml: %w", err)
+	}
+
+	usedNames := make(map[string]bool)
+	usedDirs := make(map[string]bool)
+	usedPorts := make(map[int]bool)
+	for name, svc := range azureYaml.Services {
+		usedNames[name] = true
+		if svc.Project != "" {
+			usedDirs[filepath.Clean(filepath.Join(rootDir, svc.Project))] = true
+		}
+		for _, port := range svc.Ports {
+			if hostPort, err := strconv.Atoi(strings.SplitN(fmt.Sprint(port), ":", 2)[0]); err == nil {
+				usedPorts[hostPort] = true
+			}
+		}
+	}
+
+	var added []GeneratedService
+	var skipped []string
+	for _, svc := range detected {
+		if usedDirs[filepath.Clean(filepath.Join(rootDir, svc.Project))] {
+			skipped = append(skipped, svc.Project)
+			continue
+		}
+		svc.Name = uniqueServiceName(svc.Name, usedNames)
+		if svc.Port > 0 {
+			for usedPorts[svc.Port] {
+				svc.Port++
+			}
+			usedPorts[svc.Port] = true
+		}
+		added = append(added, svc)
+	}
+	if len(added) == 0 {
+		return content, nil, skipped, nil
+	}
+
+	newContent, err := appendToServicesSection(content, added)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return newContent, added, skipped, nil
+}
+
+// appendToServicesSection appends services to the end of the services section of azure.yaml,
+// using the indentation of its existing services, or adds the section at the end of the file.
+func appendToServicesSection(content string, services []GeneratedService) (string, error) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	sectionLine := -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "services:") {
+			continue
+		}
+		if !servicesSectionPattern.MatchString(line) {
+			return "", fmt.Errorf("the services section must be a block mapping to add services to it")
+		}
+		sectionLine = i
+		break
+	}
+	if sectionLine < 0 {
+		lines = append(lines, "", "services:")
+		sectionLine = len(lines) - 1
+	}
+	// An empty flow mapping ("services: {}") becomes a block mapping
+	if key, comment, _ := strings.Cut(lines[sectionLine], "#"); strings.Contains(key, "{") {
+		lines[sectionLine] = "services:"
+		if comment != "" {
+			lines[sectionLine] += " #" + comment
+		}
+	}
+
+	// The section ends at the first top-level line after it
+	indent, indentFound := "  ", false
+	insertAt := sectionLine + 1
+	for i := sectionLine + 1; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		trimmed := strings.TrimLeft(line, " \t")
+		if len(trimmed) == len(line) {
+			break
+		}
+		if !indentFound && !strings.HasPrefix(trimmed, "#") {
+			indent, indentFound = line[:len(line)-len(trimmed)], true
+		}
+		insertAt = i + 1
+	}
+
+	var entries []string
+	for _, svc := range services {
+		entries = append(entries, formatServiceEntry(svc, indent)...)
+	}
+
+	merged := make([]string, 0, len(lines)+len(entries))
+	merged = append(merged, lines[:insertAt]...)
+	merged = append(merged, entries...)
+	merged = append(merged, lines[insertAt:]...)
+	return strings.Join(merged, "\n") + "\n", nil
+}
+
+// formatServiceEntry formats a service as azure.yaml lines indented by indent.
+func formatServiceEntry(svc GeneratedService, indent string) []string {
+	key := indent + svc.Name + ":"
+	if svc.Framework != "" {
+		key += " # " + svc.Framework
+	}
+	lines := []string{key, fmt.Sprintf("%s%sproject: %s", indent, indent, svc.Project)}
+	if svc.Language != "" {
+		lines = append(lines, fmt.Sprintf("%s%slanguage: %s", indent, indent, svc.Language))
+	}
+	lines = append(lines, fmt.Sprintf("%s%shost: %s", indent, indent, svc.Host))
+	if svc.Port > 0 {
+		lines = append(lines,
+			fmt.Sprintf("%s%sports:", indent, indent),
+			fmt.Sprintf("%s%s%s- %q", indent, indent, indent, strconv.Itoa(svc.Port)))
+	}
+	return lines
+}
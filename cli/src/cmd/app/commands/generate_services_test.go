@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDetectServices(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"web/package.json":             `{"name": "web", "dependencies": {"next": "14.0.0"}}`,
+		"web/next.config.js":           "module.exports = {}",
+		"api/requirements.txt":         "fastapi\nuvicorn\n",
+		"api/main.py":                  "from fastapi import FastAPI\napp = FastAPI()\n",
+		"worker/go.mod":                "module example.com/worker\n\ngo 1.22\n",
+		"worker/main.go":               "package main\n\nfunc main() {}\n",
+		"Shop.Api/Shop.Api.csproj":     `<Project Sdk="Microsoft.NET.Sdk.Web"></Project>`,
+		"Shop.Tests/Shop.Tests.csproj": `<Project Sdk="Microsoft.NET.Sdk"></Project>`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	services := detectServices(dir)
+	byName := make(map[string]GeneratedService)
+	for _, svc := range services {
+		byName[svc.Name] = svc
+	}
+	if len(services) != 4 {
+		t.Fatalf("detectServices() = %+v, want 4 services", services)
+	}
+
+	tests := []struct {
+		name     string
+		project  string
+		language string
+		port     int
+	}{
+		{"web", "./web", "js", 3000},
+		{"api", "./api", "python", 8000},
+		{"worker", "./worker", "go", 8080},
+		{"shop-api", "./Shop.Api", "dotnet", 5000},
+	}
+	for _, tt := range tests {
+		svc, ok := byName[tt.name]
+		if !ok {
+			t.Errorf("detectServices() has no service %q: %+v", tt.name, services)
+			continue
+		}
+		if svc.Project != tt.project || svc.Language != tt.language || svc.Port != tt.port || svc.Host != generatedHostContainerApp {
+			t.Errorf("service %q = %+v, want project %s, language %s, port %d", tt.name, svc, tt.project, tt.language, tt.port)
+		}
+	}
+}
+
+func TestMergeServices(t *testing.T) {
+	dir := t.TempDir()
+	content := `# Project settings
+name: shop
+
+services:
+  # The API, configured by hand
+  api:
+    project: ./api
+    language: python
+    host: containerapp
+    ports:
+      - "3000"
+
+# Requirements
+reqs:
+  - name: node
+    minVersion: "20.0.0"
+`
+	detected := []GeneratedService{
+		{Name: "api", Project: "./api", Language: "python", Framework: "FastAPI", Host: generatedHostContainerApp, Port: 8000},
+		{Name: "api", Project: "./backend/api", Language: "ts", Framework: "Express", Host: generatedHostContainerApp, Port: 3000},
+		{Name: "web", Project: "./web", Language: "js", Framework: "Next.js", Host: generatedHostContainerApp, Port: 3000},
+	}
+
+	merged, added, skipped, err := mergeServices(content, dir, detected)
+	if err != nil {
+		t.Fatalf("mergeServices() error = %v", err)
+	}
+	if len(skipped) != 1 || skipped[0] != "./api" {
+		t.Errorf("skipped = %v, want the project of the existing api service", skipped)
+	}
+	if len(added) != 2 || added[0].Name != "api-2" || added[0].Port != 3001 || added[1].Name != "web" || added[1].Port != 3002 {
+		t.Errorf("added = %+v, want api-2 on 3001 and web on 3002", added)
+	}
+
+	for _, kept := range []string{"# Project settings", "  # The API, configured by hand", "# Requirements"} {
+		if !strings.Contains(merged, kept) {
+			t.Errorf("merged azure.yaml lost %q:\n%s", kept, merged)
+		}
+	}
+	if !strings.Contains(merged, "  web: # Next.js\n    project: ./web\n    language: js\n    host: containerapp\n    ports:\n      - \"3002\"\n") {
+		t.Errorf("merged azure.yaml is missing the web service:\n%s", merged)
+	}
+	if strings.Index(merged, "api-2:") > strings.Index(merged, "# Requirements") {
+		t.Errorf("services should be added to the services section:\n%s", merged)
+	}
+
+	var parsed struct {
+		Services map[string]interface{} `yaml:"services"`
+		Reqs     []interface{}          `yaml:"reqs"`
+	}
+	if err := yaml.Unmarshal([]byte(merged), &parsed); err != nil {
+		t.Fatalf("merged azure.yaml is invalid: %v\n%s", err, merged)
+	}
+	if len(parsed.Services) != 3 || len(parsed.Reqs) != 1 {
+		t.Errorf("merged azure.yaml has %d services and %d reqs, want 3 and 1", len(parsed.Services), len(parsed.Reqs))
+	}
+}
+
+func TestAppendToServicesSection(t *testing.T) {
+	svc := GeneratedService{Name: "web", Project: ".", Language: "ts", Host: generatedHostContainerApp}
+	want := "web:\n  project: .\n  language: ts\n  host: containerapp\n"
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"no section", "name: app\n", "name: app\n\nservices:\n  " + strings.ReplaceAll(want, "\n  ", "\n    ")},
+		{"empty flow mapping", "name: app\nservices: {} # none yet\n", "name: app\nservices: # none yet\n  " + strings.ReplaceAll(want, "\n  ", "\n    ")},
+		{"four-space indent", "services:\n    api:\n        project: ./api\n", "services:\n    api:\n        project: ./api\n    " + strings.ReplaceAll(want, "\n  ", "\n        ")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := appendToServicesSection(tt.content, []GeneratedService{svc})
+			if err != nil {
+				t.Fatalf("appendToServicesSection() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("appendToServicesSection() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := appendToServicesSection("services: {api: {project: ./api}}\n", []GeneratedService{svc}); err == nil {
+		t.Error("appendToServicesSection() should fail for a non-empty flow mapping")
+	}
+}
+
+func TestServiceNameFromDir(t *testing.T) {
+	tests := map[string]string{
+		"/repo/src/Shop.Api": "shop-api",
+		"/repo/web_app":      "web-app",
+		"/repo/api":          "api",
+		"/repo/__":           "app",
+	}
+	for dir, want := range tests {
+		if got := serviceNameFromDir(filepath.FromSlash(dir)); got != want {
+			t.Errorf("serviceNameFromDir(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
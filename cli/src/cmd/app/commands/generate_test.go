@@ -23,6 +23,11 @@ func TestNormalizeVersion(t *testing.T) {
 		// Python (major.minor)
 		{"python major.minor", "3.12.5", "python", "3.12.0"},
 		{"python 3.13", "3.13.9", "python", "3.13.0"},
+		{"go major.minor", "1.23.4", "go", "1.23.0"},
+		{"rust major.minor", "1.83.0", "rust", "1.83.0"},
+		{"java major", "21.0.2", "java", "21.0.0"},
+		{"mvn major", "3.9.6", "mvn", "3.0.0"},
+		{"cargo major.minor", "1.83.2", "cargo", "1.83.0"},
 
 		// Package managers (major only)
 		{"pnpm major", "10.20.0", "pnpm", "10.0.0"},
@@ -36,6 +41,13 @@ func TestNormalizeVersion(t *testing.T) {
 		{"azd major.minor", "1.20.3", "azd", "1.20.0"},
 		{"az major.minor", "2.70.0", "az", "2.70.0"},
 		{"aspire major.minor", "13.0.1", "aspire", "13.0.0"},
+		{"bicep major.minor", "0.30.23", "bicep", "0.30.0"},
+		{"func major", "4.0.6610", "func", "4.0.0"},
+
+		// Infrastructure tools
+		{"terraform major.minor", "1.9.5", "terraform", "1.9.0"},
+		{"kubectl major.minor", "1.31.2", "kubectl", "1.31.0"},
+		{"helm major", "3.16.1", "helm", "3.0.0"},
 
 		// Edge cases
 		{"two parts", "3.12", "python", "3.12.0"},
@@ -104,6 +116,34 @@ func TestExtractVersionFromOutput(t *testing.T) {
 			field:    2,
 			expected: "2.2.1",
 		},
+		{
+			name:     "terraform version",
+			output:   "Terraform v1.9.5\non linux_amd64",
+			prefix:   "",
+			field:    1,
+			expected: "1.9.5",
+		},
+		{
+			name:     "bicep version",
+			output:   "Bicep CLI version 0.30.23 (ec3612f8ba)",
+			prefix:   "",
+			field:    3,
+			expected: "0.30.23",
+		},
+		{
+			name:     "kubectl version",
+			output:   "Client Version: v1.31.0\nKustomize Version: v5.4.2",
+			prefix:   "",
+			field:    2,
+			expected: "1.31.0",
+		},
+		{
+			name:     "helm version",
+			output:   "v3.16.1+g5a5449d",
+			prefix:   "",
+			field:    0,
+			expected: "3.16.1",
+		},
 		{
 			name:     "simple version",
 			output:   "1.2.3",
@@ -869,6 +909,22 @@ func TestDetectNodePackageManager(t *testing.T) {
 			expectedID:     "yarn",
 			expectedSource: "yarn.lock",
 		},
+		{
+			name: "detects bun from lock file",
+			setup: func(dir string) error {
+				return os.WriteFile(filepath.Join(dir, "bun.lockb"), []byte(""), 0600)
+			},
+			expectedID:     "bun",
+			expectedSource: "bun.lockb",
+		},
+		{
+			name: "detects deno from deno.json",
+			setup: func(dir string) error {
+				return os.WriteFile(filepath.Join(dir, "deno.json"), []byte("{}"), 0600)
+			},
+			expectedID:     "deno",
+			expectedSource: "deno.json",
+		},
 		{
 			name: "detects npm from lock file",
 			setup: func(dir string) error {
@@ -886,8 +942,8 @@ func TestDetectNodePackageManager(t *testing.T) {
 		{
 			name: "unsupported package manager in packageManager field falls back to lock files",
 			setup: func(dir string) error {
-				// Set packageManager to an unsupported manager (e.g., "bun")
-				pkgJSON := `{"name": "test", "packageManager": "bun@1.0.0"}`
+				// Set packageManager to an unsupported manager (e.g., "cnpm")
+				pkgJSON := `{"name": "test", "packageManager": "cnpm@9.0.0"}`
 				if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(pkgJSON), 0600); err != nil {
 					return err
 				}
@@ -901,7 +957,7 @@ func TestDetectNodePackageManager(t *testing.T) {
 			name: "unsupported package manager with no lock files defaults to npm",
 			setup: func(dir string) error {
 				// Set packageManager to an unsupported manager with no lock files
-				pkgJSON := `{"name": "test", "packageManager": "bun@1.0.0"}`
+				pkgJSON := `{"name": "test", "packageManager": "cnpm@9.0.0"}`
 				return os.WriteFile(filepath.Join(dir, "package.json"), []byte(pkgJSON), 0600)
 			},
 			expectedID:     "npm",
@@ -931,6 +987,191 @@ func TestDetectNodePackageManager(t *testing.T) {
 	}
 }
 
+func TestDetectProjectReqs_Deno(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "deno.json"), []byte(`{"tasks": {"dev": "deno run -A main.ts"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs, err := detectProjectReqs(dir)
+	if err != nil {
+		t.Fatalf("detectProjectReqs() error = %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, req := range reqs {
+		names[req.Name] = true
+	}
+	if !names["deno"] {
+		t.Errorf("reqs = %+v, want deno", reqs)
+	}
+	if names["node"] {
+		t.Errorf("reqs = %+v, Deno projects shouldn't require node", reqs)
+	}
+}
+
+func TestDetectProjectReqs_Go(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs, err := detectProjectReqs(dir)
+	if err != nil {
+		t.Fatalf("detectProjectReqs() error = %v", err)
+	}
+
+	for _, req := range reqs {
+		if req.Name != "go" {
+			continue
+		}
+		if req.MinVersion != "1.22.0" || req.Source != "go.mod" {
+			t.Errorf("go req = %+v, want minVersion 1.22.0 from go.mod", req)
+		}
+		return
+	}
+	t.Errorf("reqs = %+v, want go", reqs)
+}
+
+func TestDetectProjectReqs_Rust(t *testing.T) {
+	dir := t.TempDir()
+	cargoToml := "[package]\nname = \"api\"\nrust-version = \"1.74\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "Cargo.toml"), []byte(cargoToml), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs, err := detectProjectReqs(dir)
+	if err != nil {
+		t.Fatalf("detectProjectReqs() error = %v", err)
+	}
+
+	found := make(map[string]DetectedRequirement)
+	for _, req := range reqs {
+		found[req.Name] = req
+	}
+	for _, name := range []string{"rust", "cargo"} {
+		req, ok := found[name]
+		if !ok {
+			t.Errorf("reqs = %+v, want %s", reqs, name)
+			continue
+		}
+		if req.MinVersion != "1.74.0" || req.Source != "Cargo.toml" {
+			t.Errorf("%s req = %+v, want minVersion 1.74.0 from Cargo.toml", name, req)
+		}
+	}
+}
+
+func TestDetectProjectReqs_Java(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     []string
+		wantTools []string
+	}{
+		{name: "maven", files: []string{"pom.xml"}, wantTools: []string{"java", "mvn"}},
+		{name: "gradle", files: []string{"build.gradle.kts"}, wantTools: []string{"java", "gradle"}},
+		{name: "maven wrapper", files: []string{"pom.xml", "mvnw", "mvnw.cmd"}, wantTools: []string{"java"}},
+		{name: "gradle wrapper", files: []string{"settings.gradle", "gradlew", "gradlew.bat"}, wantTools: []string{"java"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, file := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, file), []byte(""), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			reqs, err := detectProjectReqs(dir)
+			if err != nil {
+				t.Fatalf("detectProjectReqs() error = %v", err)
+			}
+
+			var tools []string
+			for _, req := range reqs {
+				if req.Name == "java" || req.Name == "mvn" || req.Name == "gradle" {
+					tools = append(tools, req.Name)
+				}
+			}
+			if strings.Join(tools, ",") != strings.Join(tt.wantTools, ",") {
+				t.Errorf("java reqs = %v, want %v", tools, tt.wantTools)
+			}
+		})
+	}
+}
+
+func TestDetectProjectReqs_Infrastructure(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		wantTools []string
+	}{
+		{name: "terraform", files: map[string]string{"infra/main.tf": ""}, wantTools: []string{"terraform"}},
+		{name: "bicep", files: map[string]string{"infra/main.bicep": ""}, wantTools: []string{"bicep"}},
+		{name: "kubernetes manifests", files: map[string]string{"manifests/deployment.yaml": "kind: Deployment\n"}, wantTools: []string{"kubectl"}},
+		{name: "aks service", files: map[string]string{"azure.yaml": "name: app\nservices:\n  api:\n    host: aks\n"}, wantTools: []string{"kubectl"}},
+		{name: "helm chart", files: map[string]string{"charts/api/Chart.yaml": "name: api\n"}, wantTools: []string{"helm"}},
+		{name: "none", files: map[string]string{"README.md": ""}, wantTools: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for file, content := range tt.files {
+				path := filepath.Join(dir, file)
+				if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			reqs, err := detectProjectReqs(dir)
+			if err != nil {
+				t.Fatalf("detectProjectReqs() error = %v", err)
+			}
+
+			var tools []string
+			for _, req := range reqs {
+				switch req.Name {
+				case "terraform", "bicep", "kubectl", "helm":
+					tools = append(tools, req.Name)
+				}
+			}
+			if strings.Join(tools, ",") != strings.Join(tt.wantTools, ",") {
+				t.Errorf("infrastructure reqs = %v, want %v", tools, tt.wantTools)
+			}
+		})
+	}
+}
+
+func TestDetectProjectReqs_TerraformRequiredVersion(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "infra"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	versions := "terraform {\n  required_version = \">= 1.5, < 2.0\"\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "infra", "versions.tf"), []byte(versions), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs, err := detectProjectReqs(dir)
+	if err != nil {
+		t.Fatalf("detectProjectReqs() error = %v", err)
+	}
+	for _, req := range reqs {
+		if req.Name != "terraform" {
+			continue
+		}
+		if req.MinVersion != "1.5.0" || req.Source != "*.tf" {
+			t.Errorf("terraform req = %+v, want minVersion 1.5.0 from *.tf", req)
+		}
+		return
+	}
+	t.Errorf("reqs = %+v, want terraform", reqs)
+}
+
 func TestDetectPythonPackageManager(t *testing.T) {
 	// Create temporary directory
 	tmpDir := t.TempDir()
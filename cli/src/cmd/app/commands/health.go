@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,6 +13,8 @@ import (
 	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/healthcheck"
+	"github.com/jongio/azd-app/cli/src/internal/output"
+	"github.com/jongio/azd-app/cli/src/internal/teamconfig"
 
 	"github.com/spf13/cobra"
 )
@@ -44,7 +47,6 @@ var (
 	healthEndpoint          string
 	healthTimeout           time.Duration
 	healthAll               bool
-	healthVerbose           bool
 	healthProfile           string
 	healthLogLevel          string
 	healthLogFormat         string
@@ -96,13 +98,13 @@ Examples:
 
 	// Basic flags
 	cmd.Flags().StringVarP(&healthService, "service", "s", "", "Monitor specific service(s) only (comma-separated)")
+	registerServiceFlagCompletion(cmd)
 	cmd.Flags().BoolVar(&healthStream, "stream", false, "Enable streaming mode for real-time updates")
 	cmd.Flags().DurationVarP(&healthInterval, "interval", "i", defaultHealthInterval, "Interval between health checks in streaming mode")
 	cmd.Flags().StringVarP(&healthOutput, "output", "o", "text", "Output format: 'text', 'json', 'table'")
 	cmd.Flags().StringVar(&healthEndpoint, "endpoint", defaultHealthEndpoint, "Default health endpoint path to check")
 	cmd.Flags().DurationVar(&healthTimeout, "timeout", defaultHealthTimeout, "Timeout for each health check")
 	cmd.Flags().BoolVar(&healthAll, "all", false, "Show health for all projects on this machine")
-	cmd.Flags().BoolVarP(&healthVerbose, "verbose", "v", false, "Show detailed health check information")
 
 	// Profile and logging flags
 	cmd.Flags().StringVar(&healthProfile, "profile", "", "Health profile to use (development, production, ci, staging, or custom)")
@@ -160,7 +162,7 @@ func runHealth(cmd *cobra.Command, args []string) error {
 		ProjectDir:             projectDir,
 		DefaultEndpoint:        healthEndpoint,
 		Timeout:                healthTimeout,
-		Verbose:                healthVerbose,
+		Verbose:                output.IsVerbose(),
 		LogLevel:               healthLogLevel,
 		LogFormat:              healthLogFormat,
 		EnableCircuitBreaker:   healthCircuitBreaker,
@@ -172,46 +174,25 @@ func runHealth(cmd *cobra.Command, args []string) error {
 		CacheTTL:               healthCacheTTL,
 	}
 
+	// Fall back to the team default profile from .azdapp/config.yaml
+	profileName := healthProfile
+	if profileName == "" {
+		profileName = teamconfig.ForProject(projectDir).Profile
+	}
+
 	// Apply profile if specified
-	if healthProfile != "" && profiles != nil {
-		profile, profileErr := profiles.GetProfile(healthProfile)
-		if profileErr != nil {
+	if profileName != "" && profiles != nil {
+		profile, profileErr := profiles.GetProfile(profileName)
+		if profileErr != nil && healthProfile != "" {
 			return fmt.Errorf("%w", profileErr)
 		}
-
-		// Apply profile settings (CLI flags take precedence)
-		if !cmd.Flags().Changed("timeout") && profile.Timeout > 0 {
-			config.Timeout = profile.Timeout
-		}
-		if !cmd.Flags().Changed("log-level") && profile.LogLevel != "" {
-			config.LogLevel = profile.LogLevel
-		}
-		if !cmd.Flags().Changed("log-format") && profile.LogFormat != "" {
-			config.LogFormat = profile.LogFormat
-		}
-		if !cmd.Flags().Changed("circuit-breaker") {
-			config.EnableCircuitBreaker = profile.CircuitBreaker
-		}
-		if !cmd.Flags().Changed("circuit-break-count") && profile.CircuitBreakerFailures > 0 {
-			config.CircuitBreakerFailures = profile.CircuitBreakerFailures
-		}
-		if !cmd.Flags().Changed("circuit-break-timeout") && profile.CircuitBreakerTimeout > 0 {
-			config.CircuitBreakerTimeout = profile.CircuitBreakerTimeout
-		}
-		if !cmd.Flags().Changed("rate-limit") && profile.RateLimit > 0 {
-			config.RateLimit = profile.RateLimit
-		}
-		if !cmd.Flags().Changed("metrics") {
-			config.EnableMetrics = profile.Metrics
-		}
-		if !cmd.Flags().Changed("metrics-port") && profile.MetricsPort > 0 {
-			config.MetricsPort = profile.MetricsPort
-		}
-		if !cmd.Flags().Changed("cache-ttl") && profile.CacheTTL > 0 {
-			config.CacheTTL = profile.CacheTTL
+		if profileErr != nil {
+			// A stale team default must not break the command
+			slog.Warn("team default health profile not found, ignoring", "profile", profileName)
+		} else {
+			applyHealthProfile(cmd, &config, profile)
+			fmt.Printf("Using health profile: %s\n", profileName)
 		}
-
-		fmt.Printf("Using health profile: %s\n", healthProfile)
 	}
 
 	// Create health monitor with enriched config
@@ -258,6 +239,40 @@ func runHealth(cmd *cobra.Command, args []string) error {
 	return runStaticMode(ctx, monitor, serviceFilter)
 }
 
+// applyHealthProfile applies profile settings to config. CLI flags take precedence.
+func applyHealthProfile(cmd *cobra.Command, config *healthcheck.MonitorConfig, profile healthcheck.HealthProfile) {
+	if !cmd.Flags().Changed("timeout") && profile.Timeout > 0 {
+		config.Timeout = profile.Timeout
+	}
+	if !cmd.Flags().Changed("log-level") && profile.LogLevel != "" {
+		config.LogLevel = profile.LogLevel
+	}
+	if !cmd.Flags().Changed("log-format") && profile.LogFormat != "" {
+		config.LogFormat = profile.LogFormat
+	}
+	if !cmd.Flags().Changed("circuit-breaker") {
+		config.EnableCircuitBreaker = profile.CircuitBreaker
+	}
+	if !cmd.Flags().Changed("circuit-break-count") && profile.CircuitBreakerFailures > 0 {
+		config.CircuitBreakerFailures = profile.CircuitBreakerFailures
+	}
+	if !cmd.Flags().Changed("circuit-break-timeout") && profile.CircuitBreakerTimeout > 0 {
+		config.CircuitBreakerTimeout = profile.CircuitBreakerTimeout
+	}
+	if !cmd.Flags().Changed("rate-limit") && profile.RateLimit > 0 {
+		config.RateLimit = profile.RateLimit
+	}
+	if !cmd.Flags().Changed("metrics") {
+		config.EnableMetrics = profile.Metrics
+	}
+	if !cmd.Flags().Changed("metrics-port") && profile.MetricsPort > 0 {
+		config.MetricsPort = profile.MetricsPort
+	}
+	if !cmd.Flags().Changed("cache-ttl") && profile.CacheTTL > 0 {
+		config.CacheTTL = profile.CacheTTL
+	}
+}
+
 // validateHealthFlags validates the health command flags
 func validateHealthFlags() error {
 	if healthInterval < minHealthInterval {
@@ -464,7 +479,7 @@ func displayTextReport(report *healthcheck.HealthReport) error {
 		}
 
 		// Show details if verbose or if there are details
-		if healthVerbose && result.Details != nil {
+		if output.IsVerbose() && result.Details != nil {
 			fmt.Println("  Details:")
 			for k, v := range result.Details {
 				fmt.Printf("    - %s: %v\n", k, v)
@@ -36,7 +36,6 @@ func TestHealthCommandFlags(t *testing.T) {
 		{"endpoint flag", "endpoint", "string"},
 		{"timeout flag", "timeout", "duration"},
 		{"all flag", "all", "bool"},
-		{"verbose flag", "verbose", "bool"},
 	}
 
 	for _, tt := range tests {
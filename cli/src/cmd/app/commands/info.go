@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/devenv"
 	"github.com/jongio/azd-app/cli/src/internal/serviceinfo"
 	"github.com/jongio/azd-core/cliout"
 
@@ -15,9 +16,14 @@ import (
 )
 
 var (
-	infoAll bool
+	infoAll    bool
+	infoDeep   bool
+	infoFormat string
 )
 
+// detectDevEnvironment detects WSL and devcontainers. This is a variable to allow test overrides.
+var detectDevEnvironment = devenv.Detect
+
 const (
 	statusUnknown = "unknown"
 	statusRunning = "running"
@@ -28,27 +34,40 @@ const (
 // NewInfoCommand creates the info command.
 func NewInfoCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "info",
-		Short:        "Show information about running services",
-		Long:         `Displays comprehensive information about all running services including URLs, status, health, and metadata`,
+		Use:   "info",
+		Short: "Show information about running services",
+		Long: `Displays comprehensive information about all running services including URLs, status, health, and metadata.
+
+With --deep, prints a machine-readable environment report instead: OS and architecture,
+installed tools with versions and paths, detected projects and frameworks, port assignments
+and an azure.yaml summary. Attach it to bug reports:
+
+  azd app info --deep > env-report.json
+  azd app info --deep --format yaml > env-report.yaml`,
 		SilenceUsage: true,
 		RunE:         runInfo,
 	}
 
 	cmd.Flags().BoolVar(&infoAll, "all", false, "Show services from all projects on this machine")
+	cmd.Flags().BoolVar(&infoDeep, "deep", false, "Print a full environment report (tools, projects, ports, azure.yaml) for bug reports")
+	cmd.Flags().StringVar(&infoFormat, "format", reportFormatJSON, "Report format for --deep: json or yaml")
 
 	return cmd
 }
 
 // runInfo executes the info command.
 func runInfo(cmd *cobra.Command, args []string) error {
-	cliout.CommandHeader("info", "Show information about services")
 	// Get current working directory (may be set by --cwd flag)
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
+	if infoDeep {
+		return runInfoDeep(cwd, infoFormat)
+	}
+	cliout.CommandHeader("info", "Show information about services")
+
 	ctx := context.Background()
 
 	// Try to get services from dashboard API first (live state)
@@ -114,8 +133,9 @@ func printInfoJSON(projectDir string, services []*serviceinfo.ServiceInfo, azure
 	}
 
 	return cliout.PrintJSON(map[string]interface{}{
-		"project":  projectDir,
-		"services": outputServices,
+		"project":     projectDir,
+		"environment": detectDevEnvironment(),
+		"services":    outputServices,
 	})
 }
 
@@ -124,6 +144,14 @@ func printInfoDefault(projectDir string, services []*serviceinfo.ServiceInfo, az
 	// Show project directory header
 	cliout.Section("📦", fmt.Sprintf("Project: %s", projectDir))
 
+	// WSL and devcontainers change how services are reached
+	if env := detectDevEnvironment(); env.Name() != "" {
+		cliout.Label("Environment", env.Name())
+		for _, hint := range env.Hints() {
+			cliout.Info("   %s %s", cliout.IconBulb, hint)
+		}
+	}
+
 	if len(services) == 0 {
 		cliout.Info("No services defined in azure.yaml")
 		cliout.Item("Run 'azd app reqs --generate' to create azure.yaml with service definitions")
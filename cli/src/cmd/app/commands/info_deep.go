@@ -0,0 +1,349 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/devenv"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	internalversion "github.com/jongio/azd-app/cli/src/internal/version"
+	"github.com/jongio/azd-core/cliout"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	reportFormatJSON = "json"
+	reportFormatYAML = "yaml"
+)
+
+// EnvironmentReport describes the machine and project for bug reports (azd app info --deep).
+type EnvironmentReport struct {
+	GeneratedAt   time.Time        `json:"generatedAt" yaml:"generatedAt"`
+	AzdAppVersion string           `json:"azdAppVersion" yaml:"azdAppVersion"`
+	System        SystemReport     `json:"system" yaml:"system"`
+	Tools         []ToolReport     `json:"tools" yaml:"tools"`
+	Projects      []ProjectReport  `json:"projects" yaml:"projects"`
+	Ports         []PortReport     `json:"ports" yaml:"ports"`
+	AzureYaml     *AzureYamlReport `json:"azureYaml,omitempty" yaml:"azureYaml,omitempty"`
+	Errors        []string         `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// SystemReport describes the operating system and runtime.
+type SystemReport struct {
+	OS          string             `json:"os" yaml:"os"`
+	Arch        string             `json:"arch" yaml:"arch"`
+	CPUs        int                `json:"cpus" yaml:"cpus"`
+	GoVersion   string             `json:"goVersion" yaml:"goVersion"`
+	Environment devenv.Environment `json:"environment" yaml:"environment"` // WSL or devcontainer
+}
+
+// ToolReport describes a known tool and whether it is installed.
+type ToolReport struct {
+	Name      string `json:"name" yaml:"name"`
+	Installed bool   `json:"installed" yaml:"installed"`
+	Version   string `json:"version,omitempty" yaml:"version,omitempty"`
+	Path      string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// ProjectReport describes a project detected in the project directory.
+type ProjectReport struct {
+	Type           string `json:"type" yaml:"type"`
+	Path           string `json:"path" yaml:"path"`
+	PackageManager string `json:"packageManager,omitempty" yaml:"packageManager,omitempty"`
+}
+
+// PortReport describes a port assigned to a service.
+type PortReport struct {
+	Service  string    `json:"service" yaml:"service"`
+	Port     int       `json:"port" yaml:"port"`
+	LastUsed time.Time `json:"lastUsed" yaml:"lastUsed"`
+}
+
+// AzureYamlReport summarizes azure.yaml.
+type AzureYamlReport struct {
+	Path      string                 `json:"path" yaml:"path"`
+	Name      string                 `json:"name" yaml:"name"`
+	Services  []ServiceSummaryReport `json:"services" yaml:"services"`
+	Resources []string               `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Reqs      []string               `json:"reqs,omitempty" yaml:"reqs,omitempty"`
+}
+
+// ServiceSummaryReport summarizes a service definition and its detected framework.
+type ServiceSummaryReport struct {
+	Name           string   `json:"name" yaml:"name"`
+	Host           string   `json:"host,omitempty" yaml:"host,omitempty"`
+	Project        string   `json:"project,omitempty" yaml:"project,omitempty"`
+	Image          string   `json:"image,omitempty" yaml:"image,omitempty"`
+	Ref            string   `json:"ref,omitempty" yaml:"ref,omitempty"`
+	Language       string   `json:"language,omitempty" yaml:"language,omitempty"`
+	Framework      string   `json:"framework,omitempty" yaml:"framework,omitempty"`
+	PackageManager string   `json:"packageManager,omitempty" yaml:"packageManager,omitempty"`
+	Ports          []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+	DependsOn      []string `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+}
+
+// runInfoDeep prints the environment report for the project containing cwd.
+func runInfoDeep(cwd, format string) error {
+	if format != reportFormatJSON && format != reportFormatYAML {
+		return fmt.Errorf("invalid --format %q: must be %q or %q", format, reportFormatJSON, reportFormatYAML)
+	}
+	if cliout.IsJSON() {
+		format = reportFormatJSON
+	}
+
+	report := buildEnvironmentReport(cwd)
+	return writeEnvironmentReport(os.Stdout, report, format)
+}
+
+// buildEnvironmentReport collects the environment report. Problems collecting one section
+// are recorded in the report's Errors instead of failing the whole report.
+func buildEnvironmentReport(cwd string) *EnvironmentReport {
+	report := &EnvironmentReport{
+		GeneratedAt:   time.Now().UTC(),
+		AzdAppVersion: internalversion.Version,
+		System: SystemReport{
+			OS:          runtime.GOOS,
+			Arch:        runtime.GOARCH,
+			CPUs:        runtime.NumCPU(),
+			GoVersion:   runtime.Version(),
+			Environment: detectDevEnvironment(),
+		},
+		Tools: collectToolReports(),
+	}
+
+	projectDir := cwd
+	azureYamlPath, err := detector.FindAzureYaml(cwd)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to find azure.yaml: %v", err))
+	}
+	if azureYamlPath != "" {
+		projectDir = filepath.Dir(azureYamlPath)
+		azureYaml, err := summarizeAzureYaml(azureYamlPath)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to read azure.yaml: %v", err))
+		}
+		report.AzureYaml = azureYaml
+	}
+
+	projects, errs := collectProjectReports(projectDir)
+	report.Projects = projects
+	report.Errors = append(report.Errors, errs...)
+
+	report.Ports = make([]PortReport, 0)
+	if azureYamlPath != "" {
+		for _, assignment := range portmanager.GetPortManager(projectDir).Assignments() {
+			report.Ports = append(report.Ports, PortReport{
+				Service:  assignment.ServiceName,
+				Port:     assignment.Port,
+				LastUsed: assignment.LastUsed,
+			})
+		}
+	}
+
+	return report
+}
+
+// collectToolReports checks every tool in the tool registry, in parallel.
+func collectToolReports() []ToolReport {
+	names := make([]string, 0, len(toolRegistry))
+	for name := range toolRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	checker := NewPrerequisiteChecker()
+	reports := make([]ToolReport, len(names))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < reqsCheckWorkers(len(names)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				reports[i] = checkTool(checker, names[i])
+			}
+		}()
+	}
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return reports
+}
+
+// checkTool looks up a tool on PATH and gets its version.
+func checkTool(checker *PrerequisiteChecker, name string) ToolReport {
+	report := ToolReport{Name: name}
+	path, err := exec.LookPath(toolRegistry[name].Command)
+	if err != nil {
+		return report
+	}
+	report.Path = path
+
+	result := checker.Evaluate(Prerequisite{Name: name})
+	report.Installed = result.Installed
+	report.Version = result.Version
+	return report
+}
+
+// collectProjectReports detects Node.js, Python, Go, Rust, Java, .NET, Aspire and Functions projects under projectDir.
+// Paths are relative to projectDir so reports don't expose the user's directory layout.
+func collectProjectReports(projectDir string) ([]ProjectReport, []string) {
+	projects := make([]ProjectReport, 0)
+	var errs []string
+	relPath := func(path string) string {
+		if rel, err := filepath.Rel(projectDir, path); err == nil {
+			return filepath.ToSlash(rel)
+		}
+		return path
+	}
+
+	nodeProjects, err := detector.FindNodeProjects(projectDir)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("failed to detect Node.js projects: %v", err))
+	}
+	for _, p := range nodeProjects {
+		projects = append(projects, ProjectReport{Type: "node", Path: relPath(p.Dir), PackageManager: p.PackageManager})
+	}
+
+	pythonProjects, err := detector.FindPythonProjects(projectDir)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("failed to detect Python projects: %v", err))
+	}
+	for _, p := range pythonProjects {
+		projects = append(projects, ProjectReport{Type: "python", Path: relPath(p.Dir), PackageManager: p.PackageManager})
+	}
+
+	goProjects, err := detector.FindGoProjects(projectDir)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("failed to detect Go projects: %v", err))
+	}
+	for _, p := range goProjects {
+		projects = append(projects, ProjectReport{Type: "go", Path: relPath(p.Dir), PackageManager: "go"})
+	}
+
+	rustProjects, err := detector.FindRustProjects(projectDir)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("failed to detect Rust projects: %v", err))
+	}
+	for _, p := range rustProjects {
+		projects = append(projects, ProjectReport{Type: "rust", Path: relPath(p.Dir), PackageManager: "cargo"})
+	}
+
+	javaProjects, err := detector.FindJavaProjects(projectDir)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("failed to detect Java projects: %v", err))
+	}
+	for _, p := range javaProjects {
+		projects = append(projects, ProjectReport{Type: "java", Path: relPath(p.Dir), PackageManager: p.BuildTool})
+	}
+
+	dotnetProjects, err := detector.FindDotnetProjects(projectDir)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("failed to detect .NET projects: %v", err))
+	}
+	for _, p := range dotnetProjects {
+		projects = append(projects, ProjectReport{Type: "dotnet", Path: relPath(p.Path)})
+	}
+
+	if appHost, err := detector.FindAppHost(projectDir); err == nil && appHost != nil {
+		projects = append(projects, ProjectReport{Type: "aspire", Path: relPath(appHost.ProjectFile)})
+	}
+
+	functionApps, err := detector.FindFunctionApps(projectDir)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("failed to detect Functions apps: %v", err))
+	}
+	for _, p := range functionApps {
+		projects = append(projects, ProjectReport{Type: "functions-" + p.Variant, Path: relPath(p.Dir)})
+	}
+
+	return projects, errs
+}
+
+// summarizeAzureYaml summarizes azure.yaml and detects each local service's framework.
+func summarizeAzureYaml(azureYamlPath string) (*AzureYamlReport, error) {
+	azureYaml, err := parseAzureYaml(azureYamlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	projectDir := filepath.Dir(azureYamlPath)
+	summary := &AzureYamlReport{
+		Path:     "azure.yaml",
+		Name:     azureYaml.Name,
+		Services: make([]ServiceSummaryReport, 0, len(azureYaml.Services)),
+	}
+
+	for name, svc := range azureYaml.Services {
+		serviceSummary := ServiceSummaryReport{
+			Name:      name,
+			Host:      svc.Host,
+			Project:   svc.Project,
+			Image:     svc.Image,
+			Ref:       svc.Ref,
+			Language:  svc.Language,
+			Ports:     svc.Ports,
+			DependsOn: svc.DependsOn,
+		}
+		if svc.Image == "" && svc.Ref == "" && svc.Project != "" {
+			language, framework, packageManager, err := service.DetectFramework(filepath.Join(projectDir, svc.Project), svc.Language, svc.Host)
+			if err == nil {
+				serviceSummary.Language = language
+				serviceSummary.Framework = framework
+				serviceSummary.PackageManager = packageManager
+			}
+		}
+		summary.Services = append(summary.Services, serviceSummary)
+	}
+	sort.Slice(summary.Services, func(i, j int) bool {
+		return summary.Services[i].Name < summary.Services[j].Name
+	})
+
+	for name := range azureYaml.Resources {
+		summary.Resources = append(summary.Resources, name)
+	}
+	sort.Strings(summary.Resources)
+
+	// Reqs aren't part of service.AzureYaml; read them the same way 'azd app reqs' does
+	var reqsYaml AzureYaml
+	if data, err := readFileSecure(azureYamlPath); err == nil && unmarshalYaml(data, &reqsYaml) == nil {
+		for _, req := range reqsYaml.Reqs {
+			if requirement := versionRequirement(req); requirement != "" {
+				summary.Reqs = append(summary.Reqs, fmt.Sprintf("%s %s", req.Name, requirement))
+			} else {
+				summary.Reqs = append(summary.Reqs, req.Name)
+			}
+		}
+	}
+
+	return summary, nil
+}
+
+// writeEnvironmentReport writes the report as JSON or YAML.
+func writeEnvironmentReport(w io.Writer, report *EnvironmentReport, format string) error {
+	if format == reportFormatYAML {
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		return encoder.Close()
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSummarizeAzureYaml(t *testing.T) {
+	dir := t.TempDir()
+	azureYamlPath := filepath.Join(dir, "azure.yaml")
+	content := `name: demo
+reqs:
+  - name: node
+    minVersion: "18.0.0"
+  - name: docker
+services:
+  web:
+    host: containerapp
+    project: ./web
+    ports: ["3000"]
+    dependsOn: [cache]
+  cache:
+    image: redis:7
+resources:
+  db:
+    type: db.postgres
+`
+	if err := os.WriteFile(azureYamlPath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "web"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "web", "package.json"), []byte(`{"dependencies":{"express":"^4.0.0"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := summarizeAzureYaml(azureYamlPath)
+	if err != nil {
+		t.Fatalf("summarizeAzureYaml() error = %v", err)
+	}
+
+	if summary.Name != "demo" {
+		t.Errorf("Name = %q, want %q", summary.Name, "demo")
+	}
+	if len(summary.Services) != 2 || summary.Services[0].Name != "cache" || summary.Services[1].Name != "web" {
+		t.Fatalf("Services = %+v, want cache and web sorted by name", summary.Services)
+	}
+	if cache := summary.Services[0]; cache.Image != "redis:7" || cache.Framework != "" {
+		t.Errorf("cache = %+v, want an image service without framework detection", cache)
+	}
+	web := summary.Services[1]
+	if web.Language == "" || web.Framework == "" {
+		t.Errorf("web = %+v, want a detected language and framework", web)
+	}
+	if len(web.Ports) != 1 || len(web.DependsOn) != 1 {
+		t.Errorf("web = %+v, want ports and dependsOn from azure.yaml", web)
+	}
+	if len(summary.Resources) != 1 || summary.Resources[0] != "db" {
+		t.Errorf("Resources = %v, want [db]", summary.Resources)
+	}
+	if want := []string{"node 18.0.0", "docker"}; strings.Join(summary.Reqs, ",") != strings.Join(want, ",") {
+		t.Errorf("Reqs = %v, want %v", summary.Reqs, want)
+	}
+}
+
+func TestCollectProjectReports(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "api"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api", "requirements.txt"), []byte("flask\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	projects, errs := collectProjectReports(dir)
+	if len(errs) != 0 {
+		t.Fatalf("collectProjectReports() errors = %v", errs)
+	}
+	if len(projects) != 1 {
+		t.Fatalf("collectProjectReports() = %+v, want one python project", projects)
+	}
+	if projects[0].Type != "python" || projects[0].Path != "api" {
+		t.Errorf("project = %+v, want python project at relative path api", projects[0])
+	}
+}
+
+func TestWriteEnvironmentReport(t *testing.T) {
+	report := &EnvironmentReport{
+		AzdAppVersion: "1.2.3",
+		System:        SystemReport{OS: "linux", Arch: "amd64"},
+		Tools:         []ToolReport{{Name: "node", Installed: true, Version: "20.11.0", Path: "/usr/bin/node"}},
+		Ports:         []PortReport{{Service: "web", Port: 3000}},
+	}
+
+	var jsonOut bytes.Buffer
+	if err := writeEnvironmentReport(&jsonOut, report, reportFormatJSON); err != nil {
+		t.Fatalf("writeEnvironmentReport(json) error = %v", err)
+	}
+	var fromJSON EnvironmentReport
+	if err := json.Unmarshal(jsonOut.Bytes(), &fromJSON); err != nil {
+		t.Fatalf("invalid JSON report: %v", err)
+	}
+	if fromJSON.Tools[0].Version != "20.11.0" || fromJSON.Ports[0].Port != 3000 {
+		t.Errorf("JSON report = %+v, want tools and ports preserved", fromJSON)
+	}
+
+	var yamlOut bytes.Buffer
+	if err := writeEnvironmentReport(&yamlOut, report, reportFormatYAML); err != nil {
+		t.Fatalf("writeEnvironmentReport(yaml) error = %v", err)
+	}
+	var fromYAML EnvironmentReport
+	if err := yaml.Unmarshal(yamlOut.Bytes(), &fromYAML); err != nil {
+		t.Fatalf("invalid YAML report: %v", err)
+	}
+	if fromYAML.System.OS != "linux" || fromYAML.AzdAppVersion != "1.2.3" {
+		t.Errorf("YAML report = %+v, want system and version preserved", fromYAML)
+	}
+	if !strings.Contains(yamlOut.String(), "azdAppVersion: 1.2.3") {
+		t.Errorf("YAML report should use camelCase keys, got:\n%s", yamlOut.String())
+	}
+}
+
+func TestRunInfoDeep_InvalidFormat(t *testing.T) {
+	if err := runInfoDeep(t.TempDir(), "xml"); err == nil {
+		t.Error("runInfoDeep() should reject an unknown format")
+	}
+}
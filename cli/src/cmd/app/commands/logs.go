@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"syscall"
@@ -16,8 +16,10 @@ import (
 
 	"github.com/jongio/azd-app/cli/src/internal/azure"
 	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/logexport"
 	"github.com/jongio/azd-app/cli/src/internal/service"
 	"github.com/jongio/azd-app/cli/src/internal/serviceinfo"
+	"github.com/jongio/azd-app/cli/src/internal/teamconfig"
 	"github.com/jongio/azd-core/cliout"
 	"github.com/jongio/azd-core/security"
 	"github.com/spf13/cobra"
@@ -37,14 +39,6 @@ const (
 	// Capped to prevent excessive memory usage (10K lines ≈ 1-2MB).
 	maxTailLines = 10000
 
-	// maxLogLineSize is the maximum size of a single log line (1MB).
-	// This handles extremely long log lines from stack traces or JSON dumps.
-	maxLogLineSize = 1 * 1024 * 1024
-
-	// scannerInitialBufferSize is the initial buffer for the log file scanner.
-	// 64KB handles most log lines without reallocation.
-	scannerInitialBufferSize = 64 * 1024
-
 	// dashboardOperationTimeout is the timeout for dashboard operations.
 	// Set to 5 seconds to prevent hanging on unresponsive dashboard.
 	dashboardOperationTimeout = 5 * time.Second
@@ -135,6 +129,10 @@ type CollectedLogs struct {
 	// Source is the log source that was used ("local", "azure", "all").
 	Source string
 
+	// Persisted indicates the local logs were read from the log files in .azure/logs
+	// because no services are running.
+	Persisted bool
+
 	// Warnings holds non-fatal warning messages (e.g., "Azure logs unavailable").
 	// CLI displays these via cliout.Warning; MCP can include them in responses.
 	Warnings []string
@@ -153,9 +151,13 @@ type logsOptions struct {
 	format       string
 	file         string
 	exclude      string
+	grep         string // Regex that log messages must match
 	noBuiltins   bool
 	contextLines int    // Number of context lines before/after matching entries (0-10)
 	source       string // Log source: "local", "azure", or "all"
+	export       string // One-shot export target: "file" or "otlp"
+	exportURL    string // OTLP endpoint for --export otlp
+	exportFormat string // File format for --export file: "json", "text" or "har"
 }
 
 // logsExecutor encapsulates the logs command execution with injectable dependencies.
@@ -170,6 +172,8 @@ type logsExecutor struct {
 
 	// Configuration options (stored directly to avoid duplication)
 	opts *logsOptions
+
+	grepRe *regexp.Regexp // Compiled opts.grep, see grepFilter
 }
 
 // newLogsExecutor creates a logsExecutor with production dependencies.
@@ -255,6 +259,9 @@ Examples:
   # View logs from the last 5 minutes
   azd app logs --since 5m
 
+  # Follow the last 20 lines of the api service that mention "timeout"
+  azd app logs api -f --tail 20 --grep timeout
+
   # Export logs to a file
   azd app logs --file logs.txt
 
@@ -271,15 +278,27 @@ Examples:
   azd app logs --source all
 
   # Follow Azure logs (polling-based)
-  azd app logs --source azure --follow`,
-		SilenceUsage: true,
+  azd app logs --source azure --follow
+
+  # Export a JSON bundle of the last 1000 lines for a bug report
+  azd app logs --export file --tail 1000 --file bug-1234-logs.json
+
+  # Export the errors of the api service as plain text
+  azd app logs api --export file --export-format text --level error
+
+  # Push logs to an OpenTelemetry collector
+  azd app logs --export otlp --export-endpoint http://localhost:4318`,
+		SilenceUsage:      true,
+		ValidArgsFunction: completeServiceName,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			applyTeamLogDefaults(cmd, opts)
 			return runLogsWithOptions(opts, args)
 		},
 	}
 
 	cmd.Flags().BoolVarP(&opts.follow, "follow", "f", false, "Follow log output (tail -f behavior)")
 	cmd.Flags().StringVarP(&opts.service, "service", "s", "", "Filter by service name(s) (comma-separated)")
+	registerServiceFlagCompletion(cmd)
 	cmd.Flags().IntVarP(&opts.tail, "tail", "n", defaultTailLines, "Number of lines to show from the end")
 	cmd.Flags().StringVar(&opts.since, "since", "", "Show logs since duration (e.g., 5m, 1h)")
 	cmd.Flags().BoolVar(&opts.timestamps, "timestamps", true, "Show timestamps with each log entry")
@@ -288,13 +307,32 @@ Examples:
 	cmd.Flags().StringVar(&opts.format, "format", "text", "Output format (text, json)")
 	cmd.Flags().StringVar(&opts.file, "file", "", "Write logs to file instead of stdout")
 	cmd.Flags().StringVar(&opts.exclude, "exclude", "", "Regex patterns to exclude (comma-separated)")
+	cmd.Flags().StringVar(&opts.grep, "grep", "", "Only show log entries matching this regex pattern")
 	cmd.Flags().BoolVar(&opts.noBuiltins, "no-builtins", false, "Disable built-in filter patterns")
-	cmd.Flags().IntVar(&opts.contextLines, "context", 0, "Number of context lines before/after matching entries (0-10, requires --level)")
+	cmd.Flags().IntVar(&opts.contextLines, "context", 0, "Number of context lines before/after matching entries (0-10, requires --level or --grep)")
 	cmd.Flags().StringVar(&opts.source, "source", "local", "Log source: 'local' (default), 'azure', or 'all'")
+	cmd.Flags().StringVar(&opts.export, "export", "", "Export logs once and exit: 'file' (see --export-format) or 'otlp'")
+	cmd.Flags().StringVar(&opts.exportFormat, "export-format", "", "File format for --export file: 'json' (bundle, default), 'text' or 'har'")
+	cmd.Flags().StringVar(&opts.exportURL, "export-endpoint", "", "OTLP/HTTP endpoint for --export otlp (default: $OTEL_EXPORTER_OTLP_ENDPOINT or http://localhost:4318)")
 
 	return cmd
 }
 
+// applyTeamLogDefaults applies log defaults from .azdapp/config.yaml for flags not set on the command line.
+func applyTeamLogDefaults(cmd *cobra.Command, opts *logsOptions) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	team := teamconfig.ForProject(cwd)
+	if !cmd.Flags().Changed("level") && team.Logs.Level != "" {
+		opts.level = team.Logs.Level
+	}
+	if !cmd.Flags().Changed("tail") && team.Logs.Tail > 0 {
+		opts.tail = team.Logs.Tail
+	}
+}
+
 func runLogsWithOptions(opts *logsOptions, args []string) error {
 	cliout.CommandHeader("logs", "View logs from running services")
 
@@ -316,6 +354,10 @@ func (e *logsExecutor) execute(ctx context.Context, args []string) error {
 		e.opts.source = string(LogSourceLocal)
 	}
 
+	if e.opts.export != "" {
+		return e.exportLogs(ctx, args)
+	}
+
 	collected, err := e.collect(ctx, args)
 	if err != nil {
 		return err
@@ -323,7 +365,9 @@ func (e *logsExecutor) execute(ctx context.Context, args []string) error {
 
 	// CLI-specific: emit informational messages based on collected status
 	if e.opts.source == string(LogSourceLocal) {
-		if !collected.DashboardAvailable || collected.ServiceCount == 0 {
+		if collected.Persisted {
+			cliout.Info("No services are currently running; showing logs from the last session")
+		} else if !collected.DashboardAvailable || collected.ServiceCount == 0 {
 			cliout.Info("No services are currently running")
 			cliout.Item("Run 'azd app run' to start services")
 			return nil
@@ -370,6 +414,10 @@ func (e *logsExecutor) execute(ctx context.Context, args []string) error {
 	}
 
 	// Follow mode - subscribe to live logs
+	if e.opts.follow && collected.Persisted {
+		cliout.Item("Run 'azd app run' to start services and follow their logs")
+		return nil
+	}
 	if e.opts.follow {
 		// Reconstruct needed state for follow mode
 		cwd, err := e.getWorkingDir()
@@ -427,20 +475,16 @@ func (e *logsExecutor) collect(ctx context.Context, args []string) (*CollectedLo
 	defer dashCancel()
 
 	// Get running services via dashboard client (works across processes)
+	// No dashboard = no services running; local logs are then read from the persisted log files
 	dashboardClient, err := e.dashboardClientFactory(dashCtx, cwd)
-	if err != nil && e.opts.source == string(LogSourceLocal) {
-		// Local logs require dashboard; no dashboard = no services running
-		return result, nil
+	if err != nil {
+		dashboardClient = nil
 	}
 
 	var serviceNames []string
 	if dashboardClient != nil {
 		// Check if dashboard is actually responding
 		if pingErr := dashboardClient.Ping(dashCtx); pingErr != nil {
-			if e.opts.source == string(LogSourceLocal) {
-				return result, nil
-			}
-			// For Azure-only flows, continue without dashboard
 			dashboardClient = nil
 		}
 	}
@@ -508,11 +552,17 @@ func (e *logsExecutor) collect(ctx context.Context, args []string) (*CollectedLo
 		}
 	default: // "local"
 		if dashboardClient == nil {
-			return nil, fmt.Errorf("cannot collect local logs: dashboard not running (run 'azd app run')")
-		}
-		logs, err = e.collectLogs(ctx, cwd, targetServices, logManager, sinceTime)
-		if err != nil {
-			return nil, fmt.Errorf("failed to collect logs: %w", err)
+			// Services aren't running; fall back to the logs persisted by the last session
+			logs = collectPersistedLogs(cwd, targetServices, sinceTime)
+			if len(logs) == 0 {
+				return result, nil
+			}
+			result.Persisted = true
+		} else {
+			logs, err = e.collectLogs(ctx, cwd, targetServices, logManager, sinceTime)
+			if err != nil {
+				return nil, fmt.Errorf("failed to collect logs: %w", err)
+			}
 		}
 	}
 
@@ -523,7 +573,7 @@ func (e *logsExecutor) collect(ctx context.Context, args []string) (*CollectedLo
 	logs = service.FilterLogEntries(logs, logFilter)
 
 	// Handle context mode vs regular mode
-	if e.opts.contextLines > 0 && levelFilter != LogLevelAll {
+	if e.opts.contextLines > 0 && (levelFilter != LogLevelAll || e.grepFilter() != nil) {
 		// Context mode: extract matching entries with surrounding context
 		logsWithContext := e.extractLogsWithContext(logs, levelFilter, e.opts.contextLines)
 
@@ -538,8 +588,9 @@ func (e *logsExecutor) collect(ctx context.Context, args []string) (*CollectedLo
 		}
 		result.HasContext = true
 	} else {
-		// Regular mode: filter by level
+		// Regular mode: filter by level and --grep
 		logs = filterLogsByLevel(logs, levelFilter)
+		logs = e.filterLogsByGrep(logs)
 
 		// Apply final tail limit after all filtering
 		if e.opts.tail > 0 && len(logs) > e.opts.tail {
@@ -555,6 +606,81 @@ func (e *logsExecutor) collect(ctx context.Context, args []string) (*CollectedLo
 	return result, nil
 }
 
+// exportLogs collects logs once and exports them to the --export target.
+// When no services are running (e.g., after a failed session), collect reads
+// local logs from the persisted log files instead.
+func (e *logsExecutor) exportLogs(ctx context.Context, args []string) error {
+	cwd, err := e.getWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	collected, err := e.collect(ctx, args)
+	if err != nil {
+		return err
+	}
+	for _, w := range collected.Warnings {
+		cliout.Warning("%s", w)
+	}
+
+	entries := collected.Entries
+
+	if len(entries) == 0 {
+		cliout.Info("No logs to export")
+		return nil
+	}
+
+	bundle := logexport.NewBundle(filepath.Base(cwd), e.opts.source, entries)
+
+	switch e.opts.export {
+	case logexport.TargetOTLP:
+		exporter, err := logexport.NewOTLPExporter(e.opts.exportURL)
+		if err != nil {
+			return err
+		}
+		if err := exporter.Export(ctx, bundle); err != nil {
+			return fmt.Errorf("failed to export logs: %w", err)
+		}
+		cliout.Success("Exported %d log entries from %d service(s) to %s", len(bundle.Entries), len(bundle.Services), exporter.URL)
+	default: // file
+		if e.opts.file == "" {
+			e.opts.file = logexport.DefaultFileName(time.Now(), e.opts.exportFormat)
+		}
+		writer, cleanup, err := e.setupOutputWriter()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		if err := logexport.Write(writer, bundle, e.opts.exportFormat); err != nil {
+			return err
+		}
+		cliout.Success("Exported %d log entries from %d service(s) to %s", len(bundle.Entries), len(bundle.Services), e.opts.file)
+	}
+
+	return nil
+}
+
+// collectPersistedLogs reads logs from .azure/logs for the given services,
+// or for every service with a log file when no services are given.
+func collectPersistedLogs(cwd string, services []string, sinceTime time.Time) []service.LogEntry {
+	if len(services) == 0 {
+		matches, _ := filepath.Glob(filepath.Join(service.LogsDir(cwd), "*.log"))
+		for _, m := range matches {
+			services = append(services, strings.TrimSuffix(filepath.Base(m), ".log"))
+		}
+	}
+
+	var logs []service.LogEntry
+	for _, name := range services {
+		serviceLogs, err := readLogsFromFile(cwd, name, 0, sinceTime)
+		if err != nil {
+			continue // No persisted logs for this service
+		}
+		logs = append(logs, serviceLogs...)
+	}
+	return logs
+}
+
 // collectAllLogsQuiet collects logs from both local and Azure sources,
 // appending warnings to the CollectedLogs result instead of calling cliout.
 func (e *logsExecutor) collectAllLogsQuiet(ctx context.Context, cwd string, dashboardClient DashboardClient, targetServices []string, logManager LogManagerInterface, sinceTime time.Time, result *CollectedLogs) ([]service.LogEntry, error) { //nolint:unparam // return value kept for future use/interface conformance
@@ -793,7 +919,7 @@ func (e *logsExecutor) extractLogsWithContext(logs []service.LogEntry, levelFilt
 	// Find indices of matching entries
 	var matchIndices []int
 	for i, entry := range logs {
-		if entry.Level == levelFilter {
+		if (levelFilter == LogLevelAll || entry.Level == levelFilter) && e.matchesGrep(entry.Message) {
 			matchIndices = append(matchIndices, i)
 		}
 	}
@@ -953,7 +1079,39 @@ func (e *logsExecutor) shouldDisplayEntry(entry service.LogEntry, levelFilter se
 		return false
 	}
 
-	return true
+	return e.matchesGrep(entry.Message)
+}
+
+// grepFilter returns the compiled --grep pattern, or nil when --grep is not set.
+func (e *logsExecutor) grepFilter() *regexp.Regexp {
+	if e.opts.grep == "" {
+		return nil
+	}
+	if e.grepRe == nil {
+		// The pattern is validated by validateLogsOptions; an invalid pattern matches everything
+		e.grepRe, _ = regexp.Compile(e.opts.grep)
+	}
+	return e.grepRe
+}
+
+// matchesGrep reports whether a log message matches the --grep pattern, if any.
+func (e *logsExecutor) matchesGrep(message string) bool {
+	re := e.grepFilter()
+	return re == nil || re.MatchString(message)
+}
+
+// filterLogsByGrep returns the log entries matching the --grep pattern, if any.
+func (e *logsExecutor) filterLogsByGrep(logs []service.LogEntry) []service.LogEntry {
+	if e.grepFilter() == nil {
+		return logs
+	}
+	filtered := make([]service.LogEntry, 0, len(logs))
+	for _, entry := range logs {
+		if e.matchesGrep(entry.Message) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
 }
 
 // followLogs subscribes to live log streams and displays them.
@@ -1474,21 +1632,12 @@ func (e *logsExecutor) followAllLogs(ctx context.Context, projectDir string, _ L
 
 // readLogsFromFile reads logs from the persisted log file for a service.
 // This is used when the in-memory buffer is empty (e.g., when called from a subprocess).
-// It also reads from rotated backup files (.log.1, .log.2) if needed.
+// It also reads from rotated backup files (.log.1, .log.2, ...) if needed.
 func readLogsFromFile(projectDir, serviceName string, tail int, sinceTime time.Time) ([]service.LogEntry, error) {
-	logsDir := filepath.Join(projectDir, ".azure", "logs")
-	baseLogFile := filepath.Join(logsDir, serviceName+".log")
-
 	var allEntries []service.LogEntry
 
-	// Read from rotated files first (oldest to newest: .log.2, .log.1, .log)
-	logFiles := []string{
-		baseLogFile + ".2",
-		baseLogFile + ".1",
-		baseLogFile,
-	}
-
-	for _, logFile := range logFiles {
+	// Read from rotated files first (oldest to newest: ..., .log.2, .log.1, .log)
+	for _, logFile := range service.LogFiles(projectDir, serviceName) {
 		entries, err := readSingleLogFile(logFile, serviceName, sinceTime)
 		if err != nil {
 			continue // File may not exist (rotated files are optional)
@@ -1510,113 +1659,18 @@ func readLogsFromFile(projectDir, serviceName string, tail int, sinceTime time.T
 
 // readSingleLogFile reads log entries from a single log file.
 func readSingleLogFile(logFile, serviceName string, sinceTime time.Time) ([]service.LogEntry, error) {
-	cleanLogFile := filepath.Clean(logFile)
-	file, err := os.Open(cleanLogFile)
-	if err != nil {
-		return nil, err
+	entries, err := service.ReadLogFile(logFile, serviceName)
+	if err != nil || sinceTime.IsZero() {
+		return entries, err
 	}
-	defer func() { _ = file.Close() }()
 
-	var entries []service.LogEntry
-	scanner := bufio.NewScanner(file)
-	// Increase buffer size to handle long log lines (stack traces, JSON dumps)
-	scanner.Buffer(make([]byte, scannerInitialBufferSize), maxLogLineSize)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		entry, err := parseLogLine(line, serviceName)
-		if err != nil {
-			continue // Skip unparseable lines
-		}
-
-		// Apply since filter
-		if !sinceTime.IsZero() && entry.Timestamp.Before(sinceTime) {
-			continue
-		}
-
-		entries = append(entries, entry)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	return entries, nil
-}
-
-// parseLogLine parses a log line from the file format:
-// [2006-01-02 15:04:05.000] [LEVEL] [STREAM] message
-func parseLogLine(line, serviceName string) (service.LogEntry, error) {
-	entry := service.LogEntry{
-		Service: serviceName,
-	}
-
-	// Parse timestamp: [2006-01-02 15:04:05.000]
-	if len(line) < 25 || line[0] != '[' {
-		return entry, fmt.Errorf("invalid log line format")
-	}
-
-	endTimestamp := strings.Index(line[1:], "]")
-	if endTimestamp == -1 {
-		return entry, fmt.Errorf("missing timestamp end bracket")
-	}
-
-	timestampStr := line[1 : endTimestamp+1]
-	timestamp, err := time.Parse("2006-01-02 15:04:05.000", timestampStr)
-	if err != nil {
-		return entry, fmt.Errorf("failed to parse timestamp: %w", err)
-	}
-	entry.Timestamp = timestamp
-
-	// Parse remaining: [LEVEL] [STREAM] message
-	remaining := line[endTimestamp+3:] // Skip "] "
-
-	// Parse level: [LEVEL]
-	if len(remaining) < 3 || remaining[0] != '[' {
-		entry.Message = remaining
-		entry.Level = service.LogLevelInfo
-		return entry, nil
-	}
-
-	endLevel := strings.Index(remaining[1:], "]")
-	if endLevel == -1 {
-		entry.Message = remaining
-		entry.Level = service.LogLevelInfo
-		return entry, nil
-	}
-
-	levelStr := remaining[1 : endLevel+1]
-	entry.Level = parseLogLevelFromString(levelStr)
-	remaining = remaining[endLevel+3:] // Skip "] "
-
-	// Parse stream: [STREAM]
-	if len(remaining) >= 3 && remaining[0] == '[' {
-		endStream := strings.Index(remaining[1:], "]")
-		if endStream != -1 {
-			streamStr := remaining[1 : endStream+1]
-			entry.IsStderr = streamStr == "ERR"
-			remaining = remaining[endStream+3:] // Skip "] "
+	filtered := entries[:0]
+	for _, entry := range entries {
+		if !entry.Timestamp.Before(sinceTime) {
+			filtered = append(filtered, entry)
 		}
 	}
-
-	entry.Message = remaining
-	return entry, nil
-}
-
-// parseLogLevelFromString parses a log level from a string.
-func parseLogLevelFromString(level string) service.LogLevel {
-	switch strings.ToUpper(level) {
-	case "INFO":
-		return service.LogLevelInfo
-	case "WARN", "WARNING":
-		return service.LogLevelWarn
-	case "ERROR":
-		return service.LogLevelError
-	case "DEBUG":
-		return service.LogLevelDebug
-	default:
-		return service.LogLevelInfo
-	}
+	return filtered, nil
 }
 
 // ANSI color constants for log output formatting.
@@ -1866,10 +1920,17 @@ func validateLogsOptions(opts *logsOptions) error {
 		return fmt.Errorf("--level must be one of: info, warn, error, debug, all; got '%s'", opts.level)
 	}
 
-	// Validate context requires level to be set (not "all")
+	// Validate grep is a valid regex
+	if opts.grep != "" {
+		if _, err := regexp.Compile(opts.grep); err != nil {
+			return fmt.Errorf("--grep must be a valid regex pattern, got '%s': %w", opts.grep, err)
+		}
+	}
+
+	// Validate context requires level to be set (not "all") or a grep pattern
 	if opts.contextLines > 0 {
-		if strings.ToLower(opts.level) == "all" {
-			return fmt.Errorf("--context requires --level to be set (info, warn, error, or debug)")
+		if strings.ToLower(opts.level) == "all" && opts.grep == "" {
+			return fmt.Errorf("--context requires --level or --grep to be set")
 		}
 	}
 
@@ -1889,6 +1950,34 @@ func validateLogsOptions(opts *logsOptions) error {
 		}
 	}
 
+	// Validate export
+	switch strings.ToLower(opts.export) {
+	case "":
+	case logexport.TargetFile, logexport.TargetOTLP:
+		opts.export = strings.ToLower(opts.export)
+		if opts.follow {
+			return fmt.Errorf("--export cannot be combined with --follow")
+		}
+		if opts.contextLines > 0 {
+			return fmt.Errorf("--export cannot be combined with --context")
+		}
+	default:
+		return fmt.Errorf("--export must be 'file' or 'otlp', got '%s'", opts.export)
+	}
+	if opts.exportURL != "" && opts.export != logexport.TargetOTLP {
+		return fmt.Errorf("--export-endpoint requires --export otlp")
+	}
+	switch strings.ToLower(opts.exportFormat) {
+	case "":
+	case logexport.FormatJSON, logexport.FormatText, logexport.FormatHAR:
+		opts.exportFormat = strings.ToLower(opts.exportFormat)
+		if opts.export != logexport.TargetFile {
+			return fmt.Errorf("--export-format requires --export file")
+		}
+	default:
+		return fmt.Errorf("--export-format must be 'json', 'text' or 'har', got '%s'", opts.exportFormat)
+	}
+
 	// Validate source
 	switch strings.ToLower(opts.source) {
 	case string(LogSourceLocal), string(LogSourceAzure), string(LogSourceAll):
@@ -211,13 +211,6 @@ func TestLogLevelAllConstant(t *testing.T) {
 	}
 }
 
-func TestMaxLogLineSizeConstant(t *testing.T) {
-	expectedSize := 1 * 1024 * 1024
-	if maxLogLineSize != expectedSize {
-		t.Errorf("maxLogLineSize = %d, expected %d (1MB)", maxLogLineSize, expectedSize)
-	}
-}
-
 func TestLogsCommandStructure(t *testing.T) {
 	cmd := NewLogsCommand()
 
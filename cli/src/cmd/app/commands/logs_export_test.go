@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/logexport"
+)
+
+func TestExportLogs_FileFromPersistedLogs(t *testing.T) {
+	tmpDir := t.TempDir()
+	logsDir := filepath.Join(tmpDir, ".azure", "logs")
+	if err := os.MkdirAll(logsDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"api.log": "[2024-01-15 10:30:45.100] [INFO] [OUT] api started\n[2024-01-15 10:30:45.400] [ERROR] [ERR] api crashed\n",
+		"web.log": "[2024-01-15 10:30:45.200] [WARN] [OUT] web slow\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(logsDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	outFile := filepath.Join(tmpDir, "bundle.json")
+	opts := &logsOptions{tail: 100, level: "all", format: "text", source: "local", export: logexport.TargetFile, file: outFile}
+	executor := newLogsExecutorForTest(
+		func(ctx context.Context, projectDir string) (DashboardClient, error) {
+			return nil, errors.New("dashboard not running")
+		},
+		func(projectDir string) LogManagerInterface { return newMockLogManager() },
+		func() (string, error) { return tmpDir, nil },
+		&bytes.Buffer{},
+		opts,
+	)
+
+	if err := executor.execute(context.Background(), nil); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("bundle not written: %v", err)
+	}
+	var bundle logexport.Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		t.Fatalf("bundle is not valid JSON: %v", err)
+	}
+
+	if len(bundle.Entries) != 3 {
+		t.Fatalf("bundle has %d entries, want 3", len(bundle.Entries))
+	}
+	if bundle.Entries[1].Service != "web" {
+		t.Errorf("entries not sorted by time: second entry service = %q, want web", bundle.Entries[1].Service)
+	}
+	if bundle.Entries[2].Level != "error" || bundle.Entries[2].Stream != "stderr" {
+		t.Errorf("last entry = %+v, want error on stderr", bundle.Entries[2])
+	}
+	if len(bundle.Services) != 2 {
+		t.Errorf("bundle services = %v, want [api web]", bundle.Services)
+	}
+}
+
+func TestValidateLogsOptions_Export(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    logsOptions
+		wantErr bool
+	}{
+		{"file export", logsOptions{export: "file"}, false},
+		{"otlp export with endpoint", logsOptions{export: "OTLP", exportURL: "http://localhost:4318"}, false},
+		{"unknown target", logsOptions{export: "s3"}, true},
+		{"export with follow", logsOptions{export: "file", follow: true}, true},
+		{"endpoint without otlp", logsOptions{export: "file", exportURL: "http://localhost:4318"}, true},
+		{"text format", logsOptions{export: "file", exportFormat: "TEXT"}, false},
+		{"har format", logsOptions{export: "file", exportFormat: "har"}, false},
+		{"format without file export", logsOptions{export: "otlp", exportFormat: "text"}, true},
+		{"unknown format", logsOptions{export: "file", exportFormat: "csv"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.tail, opts.format, opts.level, opts.source = 100, "text", "all", "local"
+			err := validateLogsOptions(&opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateLogsOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
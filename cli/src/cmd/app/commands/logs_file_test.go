@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -235,7 +238,7 @@ malformed again
 
 	t.Run("line exceeds max buffer size causes scanner error", func(t *testing.T) {
 		hugeFile := filepath.Join(tmpDir, "huge.log")
-		hugeMsg := strings.Repeat("x", maxLogLineSize+1000)
+		hugeMsg := strings.Repeat("x", 1024*1024+1000) // Over the 1MB line limit
 		content := fmt.Sprintf("[2024-01-15 10:30:45.123] [INFO] [OUT] %s", hugeMsg)
 		if err := os.WriteFile(hugeFile, []byte(content), 0644); err != nil {
 			t.Fatal(err)
@@ -246,3 +249,69 @@ malformed again
 		}
 	})
 }
+
+func TestCollect_PersistedLogsWhenNotRunning(t *testing.T) {
+	tmpDir := t.TempDir()
+	logsDir := filepath.Join(tmpDir, ".azure", "logs")
+	if err := os.MkdirAll(logsDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"api.log.3": "[2024-01-15 10:30:40.000] [INFO] [OUT] api oldest\n",
+		"api.log":   "[2024-01-15 10:30:45.100] [INFO] [OUT] api started\n[2024-01-15 10:30:45.400] [ERROR] [ERR] api crashed\n",
+		"web.log":   "[2024-01-15 10:30:45.200] [WARN] [OUT] web slow\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(logsDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newExecutor := func(opts *logsOptions) *logsExecutor {
+		return newLogsExecutorForTest(
+			func(ctx context.Context, projectDir string) (DashboardClient, error) {
+				return nil, errors.New("dashboard not running")
+			},
+			func(projectDir string) LogManagerInterface { return newMockLogManager() },
+			func() (string, error) { return tmpDir, nil },
+			&bytes.Buffer{},
+			opts,
+		)
+	}
+
+	t.Run("all services", func(t *testing.T) {
+		collected, err := newExecutor(&logsOptions{tail: 100, level: "all", source: "local"}).collect(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("collect() error = %v", err)
+		}
+		if !collected.Persisted {
+			t.Error("Persisted = false, want true")
+		}
+		if len(collected.Entries) != 4 {
+			t.Fatalf("got %d entries, want 4", len(collected.Entries))
+		}
+		if collected.Entries[0].Message != "api oldest" {
+			t.Errorf("first entry = %q, want the oldest rotated entry", collected.Entries[0].Message)
+		}
+	})
+
+	t.Run("service and level filter", func(t *testing.T) {
+		collected, err := newExecutor(&logsOptions{tail: 100, level: "error", source: "local"}).collect(context.Background(), []string{"api"})
+		if err != nil {
+			t.Fatalf("collect() error = %v", err)
+		}
+		if len(collected.Entries) != 1 || collected.Entries[0].Message != "api crashed" {
+			t.Errorf("entries = %+v, want only 'api crashed'", collected.Entries)
+		}
+	})
+
+	t.Run("no log files", func(t *testing.T) {
+		collected, err := newExecutor(&logsOptions{tail: 100, level: "all", source: "local"}).collect(context.Background(), []string{"worker"})
+		if err != nil {
+			t.Fatalf("collect() error = %v", err)
+		}
+		if collected.Persisted || len(collected.Entries) != 0 {
+			t.Errorf("collect() = %+v, want no persisted entries", collected)
+		}
+	})
+}
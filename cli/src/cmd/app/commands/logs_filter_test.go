@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -256,3 +257,51 @@ func BenchmarkFilterLogsByLevel(b *testing.B) {
 		_ = filterLogsByLevel(logs, service.LogLevelInfo)
 	}
 }
+
+func TestLogsExecutor_Grep(t *testing.T) {
+	logs := []service.LogEntry{
+		{Service: "api", Message: "request started", Level: service.LogLevelInfo},
+		{Service: "api", Message: "upstream Timeout after 30s", Level: service.LogLevelError},
+		{Service: "api", Message: "request finished", Level: service.LogLevelInfo},
+		{Service: "api", Message: "db timeout", Level: service.LogLevelWarn},
+	}
+
+	t.Run("filter", func(t *testing.T) {
+		e := &logsExecutor{opts: &logsOptions{grep: "(?i)timeout"}}
+		got := e.filterLogsByGrep(logs)
+		if len(got) != 2 || got[0].Message != logs[1].Message || got[1].Message != logs[3].Message {
+			t.Errorf("filterLogsByGrep() = %+v, want the two timeout entries", got)
+		}
+		if e.shouldDisplayEntry(logs[0], LogLevelAll, nil) {
+			t.Error("shouldDisplayEntry() = true for an entry not matching --grep")
+		}
+	})
+
+	t.Run("no pattern", func(t *testing.T) {
+		e := &logsExecutor{opts: &logsOptions{}}
+		if got := e.filterLogsByGrep(logs); len(got) != len(logs) {
+			t.Errorf("filterLogsByGrep() returned %d entries, want all %d", len(got), len(logs))
+		}
+	})
+
+	t.Run("context", func(t *testing.T) {
+		e := &logsExecutor{opts: &logsOptions{grep: "Timeout"}}
+		got := e.extractLogsWithContext(logs, LogLevelAll, 1)
+		if len(got) != 1 || got[0].Message != logs[1].Message {
+			t.Fatalf("extractLogsWithContext() = %+v, want only the case-sensitive match", got)
+		}
+		if got[0].Context == nil || len(got[0].Context.Before) != 1 || len(got[0].Context.After) != 1 {
+			t.Errorf("Context = %+v, want one line before and after", got[0].Context)
+		}
+	})
+}
+
+func TestValidateLogsOptions_Grep(t *testing.T) {
+	if err := validateLogsOptions(&logsOptions{format: "text", level: "all", grep: "timeout", contextLines: 2}); err != nil {
+		t.Errorf("--grep with --context: unexpected error %v", err)
+	}
+	err := validateLogsOptions(&logsOptions{format: "text", level: "all", grep: "("})
+	if err == nil || !strings.Contains(err.Error(), "--grep must be a valid regex") {
+		t.Errorf("invalid --grep: error = %v", err)
+	}
+}
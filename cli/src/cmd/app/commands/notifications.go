@@ -74,6 +74,7 @@ func newNotificationsListCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&serviceName, "service", "s", "", "Filter by service name")
+	registerServiceFlagCompletion(cmd)
 	cmd.Flags().BoolVarP(&unreadOnly, "unread", "u", false, "Show only unread notifications")
 	cmd.Flags().IntVarP(&limit, "limit", "n", 50, "Maximum number of notifications to show")
 
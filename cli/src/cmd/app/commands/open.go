@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-app/cli/src/internal/serviceinfo"
+	"github.com/jongio/azd-core/cliout"
+	"github.com/pkg/browser"
+
+	"github.com/spf13/cobra"
+)
+
+// openReadyPollInterval is how often `azd app open` checks whether the target is ready.
+const openReadyPollInterval = 500 * time.Millisecond
+
+var (
+	openPrint   bool
+	openTimeout time.Duration
+)
+
+// openBrowser opens a URL in the default browser. This is a variable to allow test overrides.
+var openBrowser = browser.OpenURL
+
+// NewOpenCommand creates the open command.
+func NewOpenCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open [service]",
+		Short: "Open the dashboard or a service in the browser",
+		Long: `Opens the dashboard of the running 'azd app run' session, or the local URL of a service, in the default browser.
+
+The URL is resolved from the service registry and port assignments of the project, and the
+browser is only opened once the dashboard or service responds to HTTP requests.
+
+Examples:
+  # Open the dashboard
+  azd app open
+
+  # Open the api service
+  azd app open api
+
+  # Print the URL without opening a browser
+  azd app open api --print`,
+		Args:              cobra.MaximumNArgs(1),
+		SilenceUsage:      true,
+		ValidArgsFunction: completeServiceName,
+		RunE:              runOpen,
+	}
+
+	cmd.Flags().BoolVar(&openPrint, "print", false, "Print the URL instead of opening the browser")
+	cmd.Flags().DurationVar(&openTimeout, "timeout", 30*time.Second, "How long to wait for the dashboard or service to become ready")
+
+	return cmd
+}
+
+// runOpen executes the open command.
+func runOpen(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	var name, targetURL string
+	if len(args) == 0 {
+		name = "dashboard"
+		targetURL, err = resolveDashboardURL(ctx, cwd)
+	} else {
+		name, targetURL, err = resolveServiceURL(cwd, args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := waitForURL(ctx, targetURL, openTimeout); err != nil {
+		return fmt.Errorf("%s is not ready at %s: %w", name, targetURL, err)
+	}
+
+	if cliout.IsJSON() {
+		return cliout.PrintJSON(map[string]string{"name": name, "url": targetURL})
+	}
+	if openPrint {
+		cliout.Plain("%s", targetURL)
+		return nil
+	}
+
+	cliout.Info("Opening %s at %s", name, targetURL)
+	if err := openBrowser(targetURL); err != nil {
+		return fmt.Errorf("failed to open browser (open %s manually): %w", targetURL, err)
+	}
+	return nil
+}
+
+// resolveDashboardURL returns the URL of the project's dashboard, using https when azure.yaml enables it.
+func resolveDashboardURL(ctx context.Context, projectDir string) (string, error) {
+	port := dashboard.GetDashboardPort(ctx, projectDir)
+	if port == 0 {
+		return "", fmt.Errorf("the dashboard isn't running for this project; start it with 'azd app run'")
+	}
+
+	scheme := "http"
+	if azureYaml, err := service.ParseAzureYaml(projectDir); err == nil && azureYaml.Dashboard != nil && azureYaml.Dashboard.HTTPS {
+		scheme = service.ProtocolHTTPS
+	}
+	return fmt.Sprintf("%s://localhost:%d", scheme, port), nil
+}
+
+// resolveServiceURL returns the name and local URL of a service. The URL comes from the
+// service registry, or from the service's port assignment when it isn't registered.
+func resolveServiceURL(projectDir, requested string) (string, string, error) {
+	services, err := serviceinfo.GetServiceInfo(projectDir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get service info: %w", err)
+	}
+
+	svc := findServiceInfo(services, requested)
+	if svc == nil {
+		names := make([]string, 0, len(services))
+		for _, s := range services {
+			names = append(names, s.Name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return "", "", fmt.Errorf("service '%s' not found: no services are defined or running", requested)
+		}
+		return "", "", fmt.Errorf("service '%s' not found (available: %s)", requested, strings.Join(names, ", "))
+	}
+
+	if svc.Local == nil || !isServiceActive(svc.Local.Status) {
+		return "", "", fmt.Errorf("service '%s' isn't running; start it with 'azd app run'", svc.Name)
+	}
+	if svc.Local.ServiceType == service.ServiceTypeTCP || svc.Local.ServiceType == service.ServiceTypeProcess {
+		return "", "", fmt.Errorf("service '%s' is a %s service and can't be opened in a browser", svc.Name, svc.Local.ServiceType)
+	}
+
+	if svc.Local.URL != "" {
+		return svc.Name, svc.Local.URL, nil
+	}
+	port := svc.Local.Port
+	if port == 0 {
+		port, _ = portmanager.GetPortManager(projectDir).GetAssignment(svc.Name)
+	}
+	if port == 0 {
+		return "", "", fmt.Errorf("service '%s' has no URL because it doesn't listen on a port", svc.Name)
+	}
+	return svc.Name, fmt.Sprintf("http://localhost:%d", port), nil
+}
+
+// findServiceInfo returns the service with the given name, matched case-insensitively
+// when there is no exact match.
+func findServiceInfo(services []*serviceinfo.ServiceInfo, name string) *serviceinfo.ServiceInfo {
+	for _, svc := range services {
+		if svc.Name == name {
+			return svc
+		}
+	}
+	for _, svc := range services {
+		if strings.EqualFold(svc.Name, name) {
+			return svc
+		}
+	}
+	return nil
+}
+
+// isServiceActive reports whether a registry status means the service is starting or running.
+func isServiceActive(status string) bool {
+	switch status {
+	case constants.StatusStarting, constants.StatusReady, constants.StatusRunning:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitForURL polls the URL until it returns any HTTP response or the timeout expires.
+// Error statuses still count as ready: the server is up, and the page shows the error.
+func waitForURL(ctx context.Context, targetURL string, timeout time.Duration) error {
+	client := &http.Client{
+		Timeout: openReadyPollInterval * 4,
+		Transport: &http.Transport{
+			// The development certificate may be self-signed and not trusted by this process
+			// #nosec G402 -- only used to check that a localhost URL responds
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(openReadyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("no response after %s: %w", timeout, err)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-core/registry"
+)
+
+func TestResolveServiceURL(t *testing.T) {
+	projectDir := t.TempDir()
+	azureYaml := "name: app\nservices:\n  api:\n    project: ./api\n  web:\n    project: ./web\n  db:\n    image: postgres:16\n  worker:\n    project: ./worker\n"
+	if err := os.WriteFile(filepath.Join(projectDir, "azure.yaml"), []byte(azureYaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reg := registry.GetRegistry(projectDir)
+	entries := []*registry.ServiceRegistryEntry{
+		{Name: "api", Port: 5100, URL: "https://localhost:5100", Status: constants.StatusRunning, Type: "http"},
+		{Name: "web", Port: 3000, Status: constants.StatusStarting},
+		{Name: "db", Port: 5432, URL: "http://localhost:5432", Status: constants.StatusRunning, Type: "tcp"},
+		{Name: "worker", Status: constants.StatusStopped},
+	}
+	for _, entry := range entries {
+		if err := reg.Register(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		requested string
+		wantName  string
+		wantURL   string
+		wantErr   string
+	}{
+		{requested: "api", wantName: "api", wantURL: "https://localhost:5100"},
+		{requested: "API", wantName: "api", wantURL: "https://localhost:5100"},
+		{requested: "web", wantName: "web", wantURL: "http://localhost:3000"},
+		{requested: "db", wantErr: "can't be opened in a browser"},
+		{requested: "worker", wantErr: "isn't running"},
+		{requested: "payments", wantErr: "available: api, db, web, worker"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.requested, func(t *testing.T) {
+			name, url, err := resolveServiceURL(projectDir, tt.requested)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("resolveServiceURL() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || name != tt.wantName || url != tt.wantURL {
+				t.Errorf("resolveServiceURL() = %q, %q, %v, want %q, %q", name, url, err, tt.wantName, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestWaitForURL(t *testing.T) {
+	// Any response means the server is up, even an error status
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	if err := waitForURL(context.Background(), server.URL, time.Second); err != nil {
+		t.Errorf("waitForURL() error = %v, want nil", err)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	if err := waitForURL(context.Background(), closed.URL, time.Second); err == nil || !strings.Contains(err.Error(), "no response after 1s") {
+		t.Errorf("waitForURL() error = %v, want a timeout", err)
+	}
+}
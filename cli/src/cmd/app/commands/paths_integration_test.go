@@ -0,0 +1,157 @@
+//go:build integration
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCommands_ProjectPathWithSpacesAndNonASCII runs reqs, deps, run, logs and stop with the
+// built binary in a project directory whose path has a space and non-ASCII characters.
+func TestCommands_ProjectPathWithSpacesAndNonASCII(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node is not installed")
+	}
+
+	projectDir := filepath.Join(t.TempDir(), "proj dir", "ünï")
+	port := freeTCPPort(t)
+	writePathsTestProject(t, projectDir, port)
+
+	binaryPath := buildAzdBinary(t)
+
+	t.Run("reqs", func(t *testing.T) {
+		runAzdAppIn(t, binaryPath, projectDir, "reqs")
+	})
+
+	t.Run("deps", func(t *testing.T) {
+		runAzdAppIn(t, binaryPath, projectDir, "deps")
+	})
+
+	// Stop the detached session once logs has read its output
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, binaryPath, "stop", "--all")
+		cmd.Dir = projectDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Logf("stop --all failed: %v\n%s", err, output)
+		}
+	})
+
+	t.Run("run", func(t *testing.T) {
+		runAzdAppIn(t, binaryPath, projectDir, "run", "--detach")
+		body := waitForHTTP(t, fmt.Sprintf("http://localhost:%d", port), time.Minute)
+		if body != "ok" {
+			t.Errorf("service responded %q, want %q", body, "ok")
+		}
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		output := runAzdAppIn(t, binaryPath, projectDir, "logs")
+		if want := fmt.Sprintf("listening on %d", port); !strings.Contains(output, want) {
+			t.Errorf("logs output does not contain %q:\n%s", want, output)
+		}
+	})
+}
+
+// writePathsTestProject writes a node project whose web service answers "ok" on port.
+func writePathsTestProject(t *testing.T, dir string, port int) {
+	t.Helper()
+
+	files := map[string]string{
+		"azure.yaml": fmt.Sprintf(`name: paths-test
+reqs:
+  - name: node
+    minVersion: 18.0.0
+services:
+  web:
+    language: js
+    project: .
+    ports:
+      - "%d"
+`, port),
+		"package.json": `{
+  "name": "paths-test",
+  "version": "1.0.0",
+  "scripts": {
+    "start": "node server.js"
+  }
+}
+`,
+		"server.js": `const http = require('http')
+const port = process.env.PORT
+http.createServer((req, res) => res.end('ok')).listen(port, () => console.log('listening on ' + port))
+`,
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// runAzdAppIn runs the binary with args in dir and returns its output, failing the test on error.
+func runAzdAppIn(t *testing.T, binaryPath, dir string, args ...string) string {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, binaryPath, args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return string(output)
+}
+
+// freeTCPPort returns a port that was free when checked.
+func freeTCPPort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// waitForHTTP polls url until it responds and returns the response body.
+func waitForHTTP(t *testing.T, url string, timeout time.Duration) string {
+	t.Helper()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		resp, err := client.Get(url)
+		if err == nil {
+			body, readErr := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if readErr == nil {
+				return string(body)
+			}
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	t.Fatalf("%s did not respond within %v", url, timeout)
+	return ""
+}
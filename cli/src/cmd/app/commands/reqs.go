@@ -3,14 +3,20 @@ package commands
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/cache"
+	"github.com/jongio/azd-app/cli/src/internal/service"
 	"github.com/jongio/azd-core/cliout"
 	"github.com/jongio/azd-core/pathutil"
 
@@ -21,7 +27,11 @@ import (
 type Prerequisite struct {
 	Name       string `yaml:"name"`
 	MinVersion string `yaml:"minVersion"`
+	MaxVersion string `yaml:"maxVersion,omitempty"` // Highest allowed version; a partial version includes its range ("20" allows 20.x)
+	Version    string `yaml:"version,omitempty"`    // Semver range, e.g. ">=18 <21", "^3.12" or "~8.0 || ^9"
+	Severity   string `yaml:"severity,omitempty"`   // required (default), recommended or optional
 	// Custom tool configuration (optional)
+	Path          string   `yaml:"path,omitempty"`          // Executable to use instead of the one on PATH, relative to azure.yaml (run uses it too)
 	Command       string   `yaml:"command,omitempty"`       // Override command to execute
 	Args          []string `yaml:"args,omitempty"`          // Override arguments
 	VersionPrefix string   `yaml:"versionPrefix,omitempty"` // Override version prefix to strip
@@ -32,15 +42,29 @@ type Prerequisite struct {
 	RunningCheckArgs     []string `yaml:"runningCheckArgs,omitempty"`     // Arguments for running check command
 	RunningCheckExpected string   `yaml:"runningCheckExpected,omitempty"` // Expected substring in output (optional)
 	RunningCheckExitCode *int     `yaml:"runningCheckExitCode,omitempty"` // Expected exit code (default: 0)
+	// HTTP probe run instead of the running check command (optional)
+	RunningCheckHTTP *RunningCheckHTTP `yaml:"runningCheckHttp,omitempty"`
+	// Deeper checks of the docker requirement: plugins and resources (optional)
+	Docker *DockerChecks `yaml:"docker,omitempty"`
 	// Install URL configuration (optional)
 	InstallURL string `yaml:"installUrl,omitempty"` // URL to installation page (overrides built-in)
 }
 
 // ReqsService represents a minimal service definition for reqs parsing.
-// Only includes fields needed to detect container services.
+// Only includes fields needed to select services and detect container services.
 type ReqsService struct {
-	Image  string            `yaml:"image,omitempty"`
-	Docker *ReqsDockerConfig `yaml:"docker,omitempty"`
+	Ref       string            `yaml:"ref,omitempty"`
+	Project   string            `yaml:"project,omitempty"`
+	Image     string            `yaml:"image,omitempty"`
+	Docker    *ReqsDockerConfig `yaml:"docker,omitempty"`
+	Reqs      []Prerequisite    `yaml:"reqs,omitempty"` // Requirements of this service only
+	Uses      []string          `yaml:"uses,omitempty"`
+	DependsOn []string          `yaml:"dependsOn,omitempty"`
+}
+
+// isContainer returns true if the service runs a container image.
+func (s ReqsService) isContainer() bool {
+	return s.Image != "" || (s.Docker != nil && s.Docker.Image != "")
 }
 
 // ReqsDockerConfig represents minimal Docker configuration for reqs parsing.
@@ -52,48 +76,210 @@ type ReqsDockerConfig struct {
 type AzureYaml struct {
 	Reqs     []Prerequisite         `yaml:"reqs"`
 	Services map[string]ReqsService `yaml:"services,omitempty"`
+	Profiles map[string]ReqsProfile `yaml:"profiles,omitempty"`
+}
+
+// ReqsProfile represents the requirements a profile overrides, at the top level and per service.
+type ReqsProfile struct {
+	Reqs     []Prerequisite                `yaml:"reqs,omitempty"`
+	Services map[string]ReqsProfileService `yaml:"services,omitempty"`
+}
+
+// ReqsProfileService represents the requirements a profile overrides for one service.
+type ReqsProfileService struct {
+	Reqs []Prerequisite `yaml:"reqs,omitempty"`
+}
+
+// applyProfile merges the reqs of the named profile over the base reqs: a profile requirement
+// replaces the base requirement with the same name (case-insensitive) and is added otherwise.
+// Unknown profiles and services are reported by service.ParseAzureYaml, so they are ignored here.
+func (a *AzureYaml) applyProfile(name string) {
+	profile, ok := a.Profiles[name]
+	if !ok {
+		return
+	}
+	a.Reqs = mergeProfileReqs(a.Reqs, profile.Reqs)
+	for serviceName, override := range profile.Services {
+		svc, exists := a.Services[serviceName]
+		if !exists {
+			continue
+		}
+		svc.Reqs = mergeProfileReqs(svc.Reqs, override.Reqs)
+		a.Services[serviceName] = svc
+	}
+}
+
+// mergeProfileReqs returns base with each override replacing the requirement of the same name,
+// or appended when base has none.
+func mergeProfileReqs(base, overrides []Prerequisite) []Prerequisite {
+	if len(overrides) == 0 {
+		return base
+	}
+	merged := slices.Clone(base)
+	for _, req := range overrides {
+		i := slices.IndexFunc(merged, func(existing Prerequisite) bool {
+			return strings.EqualFold(existing.Name, req.Name)
+		})
+		if i >= 0 {
+			merged[i] = req
+		} else {
+			merged = append(merged, req)
+		}
+	}
+	return merged
 }
 
 const (
 	toolDocker = "docker"
+	toolNode   = "node"
+	toolDotnet = "dotnet"
 	osWindows  = "windows"
 )
 
-// hasContainerServices returns true if any service is a container service.
-func (a *AzureYaml) hasContainerServices() bool {
-	for _, svc := range a.Services {
-		if svc.Image != "" {
-			return true
+// Requirement severities. Only unsatisfied required requirements fail the check;
+// recommended ones are reported as warnings and optional ones for information.
+const (
+	SeverityRequired    = "required"
+	SeverityRecommended = "recommended"
+	SeverityOptional    = "optional"
+)
+
+// severity returns the prerequisite's severity, defaulting to required.
+func (p Prerequisite) severity() string {
+	return normalizeSeverity(p.Severity)
+}
+
+// normalizeSeverity returns severity in lowercase, or required when it is empty or unknown,
+// so a typo never hides a missing tool.
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case SeverityRecommended:
+		return SeverityRecommended
+	case SeverityOptional:
+		return SeverityOptional
+	default:
+		return SeverityRequired
+	}
+}
+
+// selectServices returns the sorted names of the given services, or of all services when none
+// are given. Services can be given as glob patterns (e.g. "svc-*"), and the services they
+// depend on are included, since they are started with them. It fails for names and patterns
+// that don't match a service in azure.yaml.
+func (a *AzureYaml) selectServices(services []string) ([]string, error) {
+	names := make([]string, 0, len(a.Services))
+	dependencies := make(map[string][]string, len(a.Services))
+	for name, svc := range a.Services {
+		names = append(names, name)
+		dependencies[name] = append(slices.Clone(svc.Uses), svc.DependsOn...)
+	}
+	if len(services) == 0 {
+		sort.Strings(names)
+		return names, nil
+	}
+
+	matched, err := service.MatchServiceNames(names, services)
+	if err != nil {
+		return nil, err
+	}
+	return service.WithDependencies(matched, dependencies), nil
+}
+
+// resolveToolPaths makes the relative tool paths of all requirements, including those of
+// services and profiles, absolute against the azure.yaml directory.
+func (a *AzureYaml) resolveToolPaths(azureYamlDir string) {
+	resolve := func(reqs []Prerequisite) {
+		for i := range reqs {
+			if reqs[i].Path != "" && !filepath.IsAbs(reqs[i].Path) {
+				reqs[i].Path = filepath.Clean(filepath.Join(azureYamlDir, reqs[i].Path))
+			}
 		}
-		if svc.Docker != nil && svc.Docker.Image != "" {
-			return true
+	}
+	resolve(a.Reqs)
+	for _, svc := range a.Services {
+		resolve(svc.Reqs)
+	}
+	for _, profile := range a.Profiles {
+		resolve(profile.Reqs)
+		for _, svc := range profile.Services {
+			resolve(svc.Reqs)
 		}
 	}
-	return false
 }
 
-// hasDockerReq returns true if Docker is already in the reqs list.
-func (a *AzureYaml) hasDockerReq() bool {
+// requirementsFor returns the requirements to check for the given services (all services
+// when none are given): the top-level reqs, which apply to every service, followed by the
+// reqs declared under each selected service. Docker is added when a selected service runs
+// a container and no requirement covers it. Identical requirements are checked once.
+func (a *AzureYaml) requirementsFor(services []string) ([]Prerequisite, error) {
+	names, err := a.selectServices(services)
+	if err != nil {
+		return nil, err
+	}
+
+	reqs := make([]Prerequisite, 0, len(a.Reqs))
+	add := func(req Prerequisite) {
+		for _, existing := range reqs {
+			if reflect.DeepEqual(existing, req) {
+				return
+			}
+		}
+		reqs = append(reqs, req)
+	}
+
 	for _, req := range a.Reqs {
-		if strings.EqualFold(req.Name, toolDocker) {
-			return true
+		add(req)
+	}
+	needsDocker := false
+	for _, name := range names {
+		svc := a.Services[name]
+		for _, req := range svc.Reqs {
+			add(req)
 		}
+		needsDocker = needsDocker || svc.isContainer()
 	}
-	return false
+
+	// Auto-inject Docker requirement if container services are selected
+	if needsDocker && !hasPrerequisite(reqs, toolDocker) {
+		add(Prerequisite{
+			Name:         toolDocker,
+			MinVersion:   "20.0.0",
+			CheckRunning: true,
+		})
+	}
+	return reqs, nil
 }
 
 // ReqResult represents the result of checking a requirement.
 type ReqResult struct {
-	Name       string `json:"name"`
-	Installed  bool   `json:"installed"`
-	Version    string `json:"version,omitempty"`
-	Required   string `json:"required"`
-	Satisfied  bool   `json:"satisfied"`
-	Running    bool   `json:"running,omitempty"`
-	CheckedRun bool   `json:"checkedRunning,omitempty"`
-	Message    string `json:"message,omitempty"`
-	IsPodman   bool   `json:"isPodman,omitempty"`   // True when Podman is aliased to Docker
-	InstallURL string `json:"installUrl,omitempty"` // URL to installation page
+	Name       string   `json:"name"`
+	Installed  bool     `json:"installed"`
+	Version    string   `json:"version,omitempty"`
+	Required   string   `json:"required"`
+	Satisfied  bool     `json:"satisfied"`
+	Running    bool     `json:"running,omitempty"`
+	CheckedRun bool     `json:"checkedRunning,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	IsPodman   bool     `json:"isPodman,omitempty"`   // True when Podman is aliased to Docker
+	InstallURL string   `json:"installUrl,omitempty"` // URL to installation page
+	Severity   string   `json:"severity"`             // required, recommended or optional
+	Path       string   `json:"path,omitempty"`       // Executable pinned with path in azure.yaml
+	Issues     []string `json:"issues,omitempty"`     // Failed deeper checks (docker), each with how to fix it
+
+	invalidConstraint bool       // The version requirement couldn't be parsed; Message has the error
+	binary            toolBinary // Executable the version check ran
+}
+
+// toolBinary identifies the executable a requirement was checked with, so cached results can
+// be revalidated when the tool is installed, upgraded or resolves to another PATH entry.
+type toolBinary struct {
+	Path    string    // Resolved path of the executable ("" when it isn't on PATH)
+	ModTime time.Time // Modification time of the executable
+}
+
+// equal reports whether both identify the same executable at the same modification time.
+func (b toolBinary) equal(other toolBinary) bool {
+	return b.Path == other.Path && b.ModTime.Equal(other.ModTime)
 }
 
 // ToolConfig defines how to check a specific tool.
@@ -123,6 +309,19 @@ var toolRegistry = map[string]ToolConfig{
 		Command: "yarn",
 		Args:    []string{"--version"},
 	},
+	toolCorepack: {
+		Command: toolCorepack,
+		Args:    []string{"--version"},
+	},
+	"bun": {
+		Command: "bun",
+		Args:    []string{"--version"},
+	},
+	"deno": {
+		Command:      "deno",
+		Args:         []string{"--version"},
+		VersionField: 1, // "deno 2.1.4 (stable, release, ...)" -> take field 1
+	},
 	"python": {
 		Command:      "python",
 		Args:         []string{"--version"},
@@ -146,6 +345,11 @@ var toolRegistry = map[string]ToolConfig{
 		Command: "pipenv",
 		Args:    []string{"--version"},
 	},
+	"conda": {
+		Command:      "conda",
+		Args:         []string{"--version"},
+		VersionField: 1, // "conda 24.11.3" -> take field 1
+	},
 	"dotnet": {
 		Command: "dotnet",
 		Args:    []string{"--version"},
@@ -169,6 +373,16 @@ var toolRegistry = map[string]ToolConfig{
 		Args:         []string{"version"},
 		VersionField: 2, // "go version go1.25.3 windows/amd64" -> take field 2
 	},
+	"rust": {
+		Command:      "rustc",
+		Args:         []string{"--version"},
+		VersionField: 1, // "rustc 1.83.0 (90b35a623 2024-11-26)" -> take field 1
+	},
+	"cargo": {
+		Command:      "cargo",
+		Args:         []string{"--version"},
+		VersionField: 1, // "cargo 1.83.0 (5ffbef321 2024-10-29)" -> take field 1
+	},
 	"azd": {
 		Command: "azd",
 		Args:    []string{"version"},
@@ -201,39 +415,70 @@ var toolRegistry = map[string]ToolConfig{
 		Args:         []string{"--version"},
 		VersionField: 1, // "Gradle 8.5" -> take field 1
 	},
+	"terraform": {
+		Command:      "terraform",
+		Args:         []string{"version"},
+		VersionField: 1, // "Terraform v1.9.5\non linux_amd64" -> take field 1
+	},
+	"bicep": {
+		Command:      "bicep",
+		Args:         []string{"--version"},
+		VersionField: 3, // "Bicep CLI version 0.30.23 (ec3612f8ba)" -> take field 3
+	},
+	"kubectl": {
+		Command:      "kubectl",
+		Args:         []string{"version", "--client"},
+		VersionField: 2, // "Client Version: v1.31.0\nKustomize Version: v5.4.2" -> take field 2
+	},
+	"helm": {
+		Command: "helm",
+		Args:    []string{"version", "--short"}, // "v3.16.1+g5a5449d"
+	},
 }
 
 // toolAliases maps alternative names to canonical tool names.
 var toolAliases = map[string]string{
 	"nodejs":                     "node",
+	"rustc":                      "rust",
 	"azure-cli":                  "az",
 	"azure-functions-core-tools": "func",
+	"bicep-cli":                  "bicep",
 }
 
 // installURLRegistry maps tool names to their installation page URLs.
 var installURLRegistry = map[string]string{
-	"node":     "https://nodejs.org/",
-	"npm":      "https://nodejs.org/",
-	"pnpm":     "https://pnpm.io/installation",
-	"yarn":     "https://yarnpkg.com/getting-started/install",
-	"python":   "https://www.python.org/downloads/",
-	"pip":      "https://www.python.org/downloads/",
-	"poetry":   "https://python-poetry.org/docs/#installation",
-	"uv":       "https://docs.astral.sh/uv/getting-started/installation/",
-	"pipenv":   "https://pipenv.pypa.io/en/latest/installation.html",
-	"dotnet":   "https://dotnet.microsoft.com/download",
-	"aspire":   "https://learn.microsoft.com/dotnet/aspire/fundamentals/setup-tooling",
-	toolDocker: "https://www.docker.com/products/docker-desktop",
-	"git":      "https://git-scm.com/downloads",
-	"go":       "https://go.dev/dl/",
-	"azd":      "https://aka.ms/install-azd",
-	"az":       "https://aka.ms/installazurecli",
-	"air":      "https://github.com/air-verse/air#installation",
-	"func":     "https://learn.microsoft.com/azure/azure-functions/functions-run-local#install-the-azure-functions-core-tools",
-	"java":     "https://adoptium.net/",
-	"mvn":      "https://maven.apache.org/install.html",
-	"gradle":   "https://gradle.org/install/",
-	"gh":       "https://cli.github.com/",
+	"node":      "https://nodejs.org/",
+	"npm":       "https://nodejs.org/",
+	"pnpm":      "https://pnpm.io/installation",
+	"yarn":      "https://yarnpkg.com/getting-started/install",
+	"corepack":  "https://nodejs.org/api/corepack.html",
+	"bun":       "https://bun.sh/docs/installation",
+	"deno":      "https://docs.deno.com/runtime/getting_started/installation/",
+	"python":    "https://www.python.org/downloads/",
+	"pip":       "https://www.python.org/downloads/",
+	"poetry":    "https://python-poetry.org/docs/#installation",
+	"uv":        "https://docs.astral.sh/uv/getting-started/installation/",
+	"pipenv":    "https://pipenv.pypa.io/en/latest/installation.html",
+	"conda":     "https://docs.conda.io/projects/conda/en/stable/user-guide/install/",
+	"dotnet":    "https://dotnet.microsoft.com/download",
+	"aspire":    "https://learn.microsoft.com/dotnet/aspire/fundamentals/setup-tooling",
+	toolDocker:  "https://www.docker.com/products/docker-desktop",
+	"git":       "https://git-scm.com/downloads",
+	"go":        "https://go.dev/dl/",
+	"rust":      "https://rustup.rs/",
+	"cargo":     "https://rustup.rs/",
+	"azd":       "https://aka.ms/install-azd",
+	"az":        "https://aka.ms/installazurecli",
+	"air":       "https://github.com/air-verse/air#installation",
+	"func":      "https://learn.microsoft.com/azure/azure-functions/functions-run-local#install-the-azure-functions-core-tools",
+	"java":      "https://adoptium.net/",
+	"mvn":       "https://maven.apache.org/install.html",
+	"gradle":    "https://gradle.org/install/",
+	"gh":        "https://cli.github.com/",
+	"terraform": "https://developer.hashicorp.com/terraform/install",
+	"bicep":     "https://learn.microsoft.com/azure/azure-resource-manager/bicep/install",
+	"kubectl":   "https://kubernetes.io/docs/tasks/tools/",
+	"helm":      "https://helm.sh/docs/intro/install/",
 }
 
 // NewReqsCommand creates the reqs command.
@@ -243,6 +488,11 @@ func NewReqsCommand() *cobra.Command {
 	var noCache bool
 	var clearCache bool
 	var fixMode bool
+	var installMode bool
+	var yes bool
+	var services []string
+	var profile string
+	var concurrency int
 
 	cmd := &cobra.Command{
 		Use:          "reqs",
@@ -256,6 +506,24 @@ generates the reqs section in azure.yaml based on what's installed on your machi
 
 With --fix, it attempts to resolve PATH issues by refreshing the environment and
 searching for installed tools that aren't accessible in the current session.
+For projects that pin pnpm or yarn with the package.json packageManager field,
+it also runs 'corepack enable' and 'corepack prepare --activate'.
+
+With --install, it installs tools that aren't installed using the platform's
+package manager (winget or choco on Windows, brew on macOS, apt or the official
+install script on Linux). It asks before each install unless --yes is set.
+Combine with --dry-run to print the commands that would be executed.
+
+Requirements can also be declared under individual services in azure.yaml. With
+--service, only the top-level reqs and the reqs of the given services and of the
+services they depend on are checked. Service names may be glob patterns such as 'svc-*'.
+
+With --profile, the reqs of the named azure.yaml profile are merged over the base reqs,
+replacing requirements with the same name.
+
+Requirements are checked in parallel; use --concurrency to limit how many version
+commands run at once (--concurrency 1 checks one at a time). Results are always
+printed in the order they are declared.
 
 The command caches results in .azure/cache/ to improve performance on subsequent runs.
 Use --no-cache to force a fresh check and bypass cached results.`,
@@ -280,6 +548,12 @@ Use --no-cache to force a fresh check and bypass cached results.`,
 
 			// Configure cache based on flag
 			SetCacheEnabled(!noCache)
+			SetReqsServices(services)
+			service.SetActiveProfile(profile)
+			if concurrency < 0 {
+				return fmt.Errorf("--concurrency must not be negative, got %d", concurrency)
+			}
+			SetReqsConcurrency(concurrency)
 
 			if generateMode {
 				// Get current working directory
@@ -301,15 +575,26 @@ Use --no-cache to force a fresh check and bypass cached results.`,
 				return runReqsFix()
 			}
 
+			if installMode {
+				SetCacheEnabled(false)
+				return runReqsInstall(dryRun, yes)
+			}
+
 			return cmdOrchestrator.Run("reqs")
 		},
 	}
 
 	cmd.Flags().BoolVarP(&generateMode, "generate", "g", false, "Generate reqs from detected project dependencies")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview changes without modifying azure.yaml")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview changes without modifying azure.yaml or installing tools")
 	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Force fresh reqs check and bypass cached results")
 	cmd.Flags().BoolVar(&clearCache, "clear-cache", false, "Clear cached reqs results")
 	cmd.Flags().BoolVar(&fixMode, "fix", false, "Attempt to fix PATH issues for missing tools")
+	cmd.Flags().BoolVar(&installMode, "install", false, "Install missing tools with the platform's package manager")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts for --install")
+	cmd.Flags().StringSliceVarP(&services, "service", "s", nil, "Check requirements only for specific services (can be specified multiple times)")
+	registerServiceFlagCompletion(cmd)
+	cmd.Flags().StringVar(&profile, "profile", "", "Merge the reqs of a profile from azure.yaml over the base reqs")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "Maximum number of requirement checks to run at once (0 = one per CPU)")
 
 	return cmd
 }
@@ -333,30 +618,49 @@ func NewPrerequisiteChecker() *PrerequisiteChecker {
 	}
 }
 
-// Check checks a prerequisite and returns structured result.
+// Check checks a prerequisite, prints the result (except in JSON mode) and returns it.
 func (pc *PrerequisiteChecker) Check(prereq Prerequisite) ReqResult {
-	installed, version, isPodman := pc.getInstalledVersion(prereq)
+	result := pc.Evaluate(prereq)
+	if !cliout.IsJSON() {
+		NewResultFormatter().Print(result)
+	}
+	return result
+}
 
-	// Resolve install URL (custom overrides built-in)
-	installURL := pc.getInstallURL(prereq)
+// Evaluate checks a prerequisite without printing anything. It is safe for concurrent use.
+// The deeper docker checks only run once the tool satisfies its version and running checks.
+func (pc *PrerequisiteChecker) Evaluate(prereq Prerequisite) ReqResult {
+	result := pc.evaluate(prereq)
+	if result.Satisfied && prereq.Docker != nil {
+		if result.Issues = checkDocker(*prereq.Docker, pc.getToolConfig(prereq).Command); len(result.Issues) > 0 {
+			result.Satisfied = false
+			result.Message = fmt.Sprintf("%d docker check(s) failed", len(result.Issues))
+		}
+	}
+	return result
+}
+
+// evaluate checks the version of a prerequisite and, if configured, that it's running.
+func (pc *PrerequisiteChecker) evaluate(prereq Prerequisite) ReqResult {
+	installed, version, isPodman := pc.getInstalledVersion(prereq)
 
 	result := ReqResult{
 		Name:       prereq.Name,
 		Installed:  installed,
 		Version:    version,
-		Required:   prereq.MinVersion,
+		Required:   versionRequirement(prereq),
 		Satisfied:  false,
 		IsPodman:   isPodman,
-		InstallURL: installURL,
+		InstallURL: pc.getInstallURL(prereq), // Custom install URL overrides built-in
+		Severity:   prereq.severity(),
+		Path:       prereq.Path,
+		binary:     pc.resolveBinary(prereq),
 	}
 
 	if !installed {
 		result.Message = "Not installed"
-		if !cliout.IsJSON() {
-			cliout.ItemError("%s: NOT INSTALLED (required: %s)", prereq.Name, prereq.MinVersion)
-			if installURL != "" {
-				cliout.Item("   Install: %s", installURL)
-			}
+		if prereq.Path != "" {
+			result.Message = "Not found at " + prereq.Path
 		}
 		return result
 	}
@@ -365,9 +669,6 @@ func (pc *PrerequisiteChecker) Check(prereq Prerequisite) ReqResult {
 	// Podman uses its own versioning (e.g., 5.7.0) which is not comparable to Docker versions (e.g., 20.10.0).
 	if isPodman && prereq.Name == toolDocker {
 		result.Message = "Podman detected (version check skipped)"
-		if !cliout.IsJSON() {
-			cliout.ItemSuccess("%s: %s via Podman (version check skipped)", prereq.Name, version)
-		}
 		// Continue to check if running if needed, otherwise mark satisfied
 		if !prereq.CheckRunning {
 			result.Satisfied = true
@@ -375,44 +676,30 @@ func (pc *PrerequisiteChecker) Check(prereq Prerequisite) ReqResult {
 		}
 	} else if version == "" {
 		result.Message = "Version unknown"
-		if !cliout.IsJSON() {
-			cliout.ItemWarning("%s: INSTALLED (version unknown, required: %s)", prereq.Name, prereq.MinVersion)
-		}
 		// Continue to check if it's running if needed
 	} else {
-		versionOk := compareVersions(version, prereq.MinVersion)
-		if !versionOk {
-			result.Message = fmt.Sprintf("Version %s does not meet minimum %s", version, prereq.MinVersion)
-			if !cliout.IsJSON() {
-				cliout.ItemError("%s: %s (required: %s)", prereq.Name, version, prereq.MinVersion)
-				if installURL != "" {
-					cliout.Item("   Install: %s", installURL)
-				}
-			}
+		versionOk, reason, err := checkVersionRequirement(prereq, version)
+		if err != nil {
+			result.Message = err.Error()
+			result.invalidConstraint = true
 			return result
 		}
-		if !cliout.IsJSON() {
-			cliout.ItemSuccess("%s: %s (required: %s)", prereq.Name, version, prereq.MinVersion)
+		if !versionOk {
+			result.Message = reason
+			return result
 		}
 	}
 
 	// Check if the tool is running (if configured)
 	if prereq.CheckRunning {
 		result.CheckedRun = true
-		isRunning := pc.checkIsRunning(prereq)
-		result.Running = isRunning
-		if !isRunning {
+		result.Running = pc.checkIsRunning(prereq)
+		if !result.Running {
 			result.Message = "Not running"
-			if !cliout.IsJSON() {
-				cliout.Item("- %s✗%s NOT RUNNING", cliout.Red, cliout.Reset)
-			}
 			return result
 		}
 		result.Satisfied = true
 		result.Message = "Running"
-		if !cliout.IsJSON() {
-			cliout.Item("- %s✓%s RUNNING", cliout.Green, cliout.Reset)
-		}
 		return result
 	}
 
@@ -467,8 +754,31 @@ func (pc *PrerequisiteChecker) getInstalledVersion(prereq Prerequisite) (install
 	return true, version, isPodman
 }
 
+// resolveBinary returns the executable the version check of a prerequisite runs.
+func (pc *PrerequisiteChecker) resolveBinary(prereq Prerequisite) toolBinary {
+	path, err := exec.LookPath(pc.getToolConfig(prereq).Command)
+	if err != nil {
+		return toolBinary{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return toolBinary{Path: path}
+	}
+	return toolBinary{Path: path, ModTime: info.ModTime()}
+}
+
 // getToolConfig gets the tool configuration for a prerequisite.
+// A path pinned in the prerequisite replaces the command the version check runs.
 func (pc *PrerequisiteChecker) getToolConfig(prereq Prerequisite) ToolConfig {
+	config := pc.toolConfig(prereq)
+	if prereq.Path != "" {
+		config.Command = prereq.Path
+	}
+	return config
+}
+
+// toolConfig gets the configured or registered tool configuration for a prerequisite.
+func (pc *PrerequisiteChecker) toolConfig(prereq Prerequisite) ToolConfig {
 	// Check if custom configuration is provided in prerequisite
 	if prereq.Command != "" {
 		return ToolConfig{
@@ -501,6 +811,14 @@ func (pc *PrerequisiteChecker) getToolConfig(prereq Prerequisite) ToolConfig {
 
 // checkIsRunning checks if a prerequisite tool is currently running.
 func (pc *PrerequisiteChecker) checkIsRunning(prereq Prerequisite) bool {
+	if prereq.RunningCheckHTTP != nil {
+		if err := probeRunningHTTP(*prereq.RunningCheckHTTP, prereq.RunningCheckExpected); err != nil {
+			slog.Debug("running check failed", "tool", prereq.Name, "error", err)
+			return false
+		}
+		return true
+	}
+
 	// If no custom running check is configured, use defaults based on tool ID
 	command := prereq.RunningCheckCommand
 	args := prereq.RunningCheckArgs
@@ -626,7 +944,9 @@ func extractPodmanVersion(output string) string {
 
 // Compiled regex patterns for version extraction (package-level for performance)
 var (
-	semanticVersionRegex = regexp.MustCompile(`(\d+\.\d+\.\d+)`)
+	// Prerelease suffixes are kept only for well-known labels, so distro package
+	// revisions such as 24.0.7-0ubuntu2 aren't mistaken for prereleases
+	semanticVersionRegex = regexp.MustCompile(`(\d+\.\d+\.\d+(?:-(?:alpha|beta|rc|preview|pre|nightly|canary|dev)[0-9A-Za-z.-]*)?)`)
 	simpleVersionRegex   = regexp.MustCompile(`(\d+\.\d+)`)
 )
 
@@ -651,8 +971,10 @@ func extractFirstVersion(s string) string {
 // Returns true if installed >= required.
 // Missing version parts are treated as 0 (e.g., "1.2" is equivalent to "1.2.0").
 func compareVersions(installed, required string) bool {
-	installedParts := parseVersion(installed)
-	requiredParts := parseVersion(required)
+	installedCore, installedPre := splitPrerelease(installed)
+	requiredCore, requiredPre := splitPrerelease(required)
+	installedParts := parseVersion(installedCore)
+	requiredParts := parseVersion(requiredCore)
 
 	// Get the maximum length to compare all parts
 	maxLen := len(requiredParts)
@@ -681,7 +1003,20 @@ func compareVersions(installed, required string) bool {
 		// Equal, continue to next part
 	}
 
-	return true // All parts equal
+	// All parts equal: a prerelease is lower than its release (1.2.0-rc.1 < 1.2.0)
+	return comparePrerelease(installedPre, requiredPre) >= 0
+}
+
+// splitPrerelease splits a version into its numeric part and its dot-separated
+// prerelease identifiers (e.g. "9.0.100-rc.1" -> "9.0.100", ["rc", "1"]).
+// Build metadata ("+build") is dropped.
+func splitPrerelease(version string) (string, []string) {
+	version, _, _ = strings.Cut(version, "+")
+	core, pre, found := strings.Cut(version, "-")
+	if !found || pre == "" {
+		return core, nil
+	}
+	return core, strings.Split(pre, ".")
 }
 
 // parseVersion parses a version string into numeric parts.
@@ -742,22 +1077,34 @@ func runReqsFix() error {
 	if err != nil {
 		return err
 	}
+	reqs, err := azureYaml.requirementsFor(execContext.Services)
+	if err != nil {
+		return err
+	}
 
-	if len(azureYaml.Reqs) == 0 {
+	// Package managers pinned via packageManager that corepack can provide
+	var corepackReqs []corepackRequirement
+	for _, req := range findServiceCorepackRequirements(filepath.Dir(azureYamlPath), azureYaml, execContext.Services) {
+		if needsCorepackEnable(req) {
+			corepackReqs = append(corepackReqs, req)
+		}
+	}
+
+	if len(reqs) == 0 && len(corepackReqs) == 0 {
 		return fmt.Errorf("no reqs defined in azure.yaml - run 'azd app reqs --generate' to add them")
 	}
 
 	// Step 1: Run initial check to identify issues
 	initialChecker := NewPrerequisiteChecker()
+	initialResults, _ := performReqsCheck(reqs)
 	var failedReqs []Prerequisite
-	for _, prereq := range azureYaml.Reqs {
-		result := initialChecker.Check(prereq)
+	for i, result := range initialResults {
 		if !result.Satisfied {
-			failedReqs = append(failedReqs, prereq)
+			failedReqs = append(failedReqs, reqs[i])
 		}
 	}
 
-	if len(failedReqs) == 0 {
+	if len(failedReqs) == 0 && len(corepackReqs) == 0 {
 		if cliout.IsJSON() {
 			return cliout.PrintJSON(map[string]interface{}{
 				"success": true,
@@ -785,10 +1132,25 @@ func runReqsFix() error {
 		}
 	}
 
-	// Step 3: Try to find and fix each failed requirement
-	fixResults := make([]FixResult, 0, len(failedReqs))
+	fixResults := make([]FixResult, 0, len(failedReqs)+len(corepackReqs))
 	fixedCount := 0
+	totalCount := len(failedReqs) + len(corepackReqs)
 
+	// Step 3: Enable corepack shims for pinned pnpm/yarn versions, which also
+	// fixes failed pnpm/yarn reqs before they are searched for below
+	if len(corepackReqs) > 0 && !cliout.IsJSON() {
+		cliout.Newline()
+		cliout.Step(cliout.IconTool, "Enabling corepack...")
+	}
+	for _, req := range corepackReqs {
+		fixResult := enableCorepack(req)
+		if fixResult.Fixed {
+			fixedCount++
+		}
+		fixResults = append(fixResults, fixResult)
+	}
+
+	// Step 4: Try to find and fix each failed requirement
 	for _, prereq := range failedReqs {
 		if !cliout.IsJSON() {
 			cliout.Newline()
@@ -855,7 +1217,7 @@ func runReqsFix() error {
 		fixResults = append(fixResults, fixResult)
 	}
 
-	// Step 4: Invalidate cache so next check gets fresh results
+	// Step 5: Invalidate cache so next check gets fresh results
 	if fixedCount > 0 {
 		// Use same azure.yaml path for cache clearing
 		cacheDir := filepath.Join(filepath.Dir(azureYamlPath), ".azure", "cache")
@@ -873,30 +1235,20 @@ func runReqsFix() error {
 		}
 	}
 
-	// Step 5: Re-check all requirements
+	// Step 6: Re-check all requirements
 	if !cliout.IsJSON() {
 		cliout.Newline()
 		cliout.Section(cliout.IconCheck, "Re-checking requirements...")
 	}
 
-	checker := NewPrerequisiteChecker()
-	allResults := make([]ReqResult, 0, len(azureYaml.Reqs))
-	allSatisfied := true
-
-	for _, prereq := range azureYaml.Reqs {
-		result := checker.Check(prereq)
-		allResults = append(allResults, result)
-		if !result.Satisfied {
-			allSatisfied = false
-		}
-	}
+	allResults, allSatisfied := performReqsCheck(reqs)
 
 	// JSON output
 	if cliout.IsJSON() {
 		return cliout.PrintJSON(map[string]interface{}{
 			"success":      fixedCount > 0,
 			"fixed":        fixedCount,
-			"total":        len(failedReqs),
+			"total":        totalCount,
 			"allSatisfied": allSatisfied,
 			"fixes":        fixResults,
 			"results":      allResults,
@@ -906,7 +1258,7 @@ func runReqsFix() error {
 	// Default output - summary
 	cliout.Newline()
 	if fixedCount > 0 {
-		cliout.Success("Fixed %d of %d issues!", fixedCount, len(failedReqs))
+		cliout.Success("Fixed %d of %d issues!", fixedCount, totalCount)
 	} else {
 		cliout.Warning("Could not automatically fix any issues")
 	}
@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/installer"
+	"github.com/jongio/azd-core/cliout"
+)
+
+const toolCorepack = "corepack"
+
+// corepackRequirement is a pnpm or yarn version pinned by a package.json packageManager field.
+type corepackRequirement struct {
+	Spec    string // Full packageManager value, e.g. "pnpm@8.15.0"
+	Manager string // "pnpm" or "yarn"
+}
+
+// findCorepackRequirements returns the unique pnpm/yarn packageManager specs of the
+// Node.js projects under projectDir, sorted by spec.
+func findCorepackRequirements(projectDir string) []corepackRequirement {
+	projects, err := detector.FindNodeProjects(projectDir)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var reqs []corepackRequirement
+	for _, project := range projects {
+		spec := detector.GetPackageManagerSpecFromPackageJSON(project.Dir)
+		manager := detector.GetPackageManagerFromPackageJSON(project.Dir)
+		if spec == "" || !installer.IsCorepackManager(manager) || seen[spec] {
+			continue
+		}
+		seen[spec] = true
+		reqs = append(reqs, corepackRequirement{Spec: spec, Manager: manager})
+	}
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].Spec < reqs[j].Spec })
+	return reqs
+}
+
+// findServiceCorepackRequirements returns the corepack requirements of the selected services'
+// projects, or of every project under projectDir when no services are selected.
+func findServiceCorepackRequirements(projectDir string, azureYaml *AzureYaml, services []string) []corepackRequirement {
+	if len(services) == 0 {
+		return findCorepackRequirements(projectDir)
+	}
+
+	// An invalid selection is reported by requirementsFor
+	names, err := azureYaml.selectServices(services)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var reqs []corepackRequirement
+	for _, name := range names {
+		svc, ok := azureYaml.Services[name]
+		if !ok || svc.Project == "" {
+			continue
+		}
+		for _, req := range findCorepackRequirements(filepath.Join(projectDir, svc.Project)) {
+			if !seen[req.Spec] {
+				seen[req.Spec] = true
+				reqs = append(reqs, req)
+			}
+		}
+	}
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].Spec < reqs[j].Spec })
+	return reqs
+}
+
+// needsCorepackEnable reports whether a pinned package manager is missing from PATH
+// but can be provided by running `corepack enable`.
+func needsCorepackEnable(req corepackRequirement) bool {
+	if _, err := exec.LookPath(req.Manager); err == nil {
+		return false
+	}
+	return installer.CorepackAvailable()
+}
+
+// checkCorepackRequirements checks that every pinned package manager can run, either from
+// PATH or through corepack. A manager that is only reachable through corepack is reported
+// as a warning because deps and run fall back to `corepack <pm>` automatically.
+func checkCorepackRequirements(reqs []corepackRequirement) ([]ReqResult, bool) {
+	results := make([]ReqResult, 0, len(reqs))
+	allSatisfied := true
+
+	for _, req := range reqs {
+		result := ReqResult{
+			Name:       toolCorepack,
+			Required:   req.Spec,
+			InstallURL: installURLRegistry[toolCorepack],
+			Severity:   SeverityRequired,
+		}
+
+		if _, err := exec.LookPath(req.Manager); err == nil {
+			result.Installed = true
+			result.Satisfied = true
+			result.Message = fmt.Sprintf("%s available", req.Manager)
+			if !cliout.IsJSON() {
+				cliout.ItemSuccess("%s: %s available (packageManager: %s)", toolCorepack, req.Manager, req.Spec)
+			}
+		} else if installer.CorepackAvailable() {
+			result.Installed = true
+			result.Satisfied = true
+			result.Message = fmt.Sprintf("Not enabled, using 'corepack %s'", req.Manager)
+			if !cliout.IsJSON() {
+				cliout.ItemWarning("%s: not enabled for %s (packageManager: %s), using 'corepack %s'", toolCorepack, req.Manager, req.Spec, req.Manager)
+				cliout.Item("   Fix: azd app reqs --fix (runs 'corepack enable')")
+			}
+		} else {
+			allSatisfied = false
+			result.Message = "Not installed"
+			if !cliout.IsJSON() {
+				cliout.ItemError("%s: NOT INSTALLED (required for packageManager: %s)", toolCorepack, req.Spec)
+				cliout.Item("   Install: %s", result.InstallURL)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, allSatisfied
+}
+
+// enableCorepack runs `corepack enable` and `corepack prepare --activate` for a pinned
+// package manager and reports the outcome as a FixResult.
+func enableCorepack(req corepackRequirement) FixResult {
+	fixResult := FixResult{Name: toolCorepack, Found: true}
+
+	if err := installer.EnableCorepack(context.Background(), req.Spec); err != nil {
+		fixResult.Message = fmt.Sprintf("Failed to enable %s: %v", req.Spec, err)
+		if !cliout.IsJSON() {
+			cliout.ItemError("Failed to enable %s via corepack: %v", req.Spec, err)
+		}
+		return fixResult
+	}
+
+	if path, err := exec.LookPath(req.Manager); err == nil {
+		fixResult.Path = path
+	}
+	fixResult.Fixed = true
+	fixResult.Satisfied = true
+	fixResult.Message = fmt.Sprintf("Enabled %s via corepack", req.Spec)
+	if !cliout.IsJSON() {
+		cliout.ItemSuccess("Enabled %s via corepack", req.Spec)
+	}
+	return fixResult
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindCorepackRequirements(t *testing.T) {
+	root := t.TempDir()
+	packages := map[string]string{
+		"web":    `{"name": "web", "packageManager": "pnpm@8.15.0"}`,
+		"admin":  `{"name": "admin", "packageManager": "pnpm@8.15.0"}`,
+		"api":    `{"name": "api", "packageManager": "yarn@4.1.0"}`,
+		"legacy": `{"name": "legacy", "packageManager": "npm@10.5.0"}`,
+		"plain":  `{"name": "plain"}`,
+	}
+	for dir, content := range packages {
+		path := filepath.Join(root, dir)
+		if err := os.MkdirAll(path, 0o750); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "package.json"), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write package.json: %v", err)
+		}
+	}
+
+	got := findCorepackRequirements(root)
+	want := []corepackRequirement{
+		{Spec: "pnpm@8.15.0", Manager: "pnpm"},
+		{Spec: "yarn@4.1.0", Manager: "yarn"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findCorepackRequirements() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindCorepackRequirements_NoNodeProjects(t *testing.T) {
+	if got := findCorepackRequirements(t.TempDir()); len(got) != 0 {
+		t.Errorf("findCorepackRequirements() = %+v, want none", got)
+	}
+}
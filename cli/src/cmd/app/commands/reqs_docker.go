@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// dockerMemoryMargin is how much less memory than minMemory Docker may report: the Docker
+// Desktop VM reports somewhat less than its memory setting.
+const dockerMemoryMargin = 0.1
+
+// DockerChecks are deeper checks of the docker requirement, run once the daemon satisfies the
+// version (and running) check.
+type DockerChecks struct {
+	Compose   bool   `yaml:"compose,omitempty"`   // Require the Compose v2 plugin (docker compose)
+	Buildx    bool   `yaml:"buildx,omitempty"`    // Require the buildx plugin
+	MinMemory string `yaml:"minMemory,omitempty"` // Minimum memory available to containers, e.g. "4GB"
+	MinCPUs   int    `yaml:"minCpus,omitempty"`   // Minimum CPUs available to containers
+}
+
+// dockerOutput runs docker with args and returns its trimmed output.
+// This is a variable to allow test overrides.
+var dockerOutput = func(docker string, args ...string) (string, error) {
+	// #nosec G204 -- docker is the requirement's command; args are fixed
+	output, err := exec.CommandContext(context.Background(), docker, args...).Output()
+	return strings.TrimSpace(string(output)), err
+}
+
+// checkDocker runs the deeper docker checks with the docker executable and returns a message
+// for each failed check, saying how to fix it.
+func checkDocker(checks DockerChecks, docker string) []string {
+	var issues []string
+
+	if checks.Compose {
+		version, err := dockerOutput(docker, "compose", "version", "--short")
+		switch {
+		case err != nil:
+			issues = append(issues, "Compose v2 plugin not found (docker compose) - update Docker Desktop or install the docker-compose-plugin package")
+		case !compareVersions(strings.TrimPrefix(version, "v"), "2.0.0"):
+			issues = append(issues, fmt.Sprintf("Compose %s found, v2 required - update Docker Desktop or install the docker-compose-plugin package", version))
+		}
+	}
+
+	if checks.Buildx {
+		if _, err := dockerOutput(docker, "buildx", "version"); err != nil {
+			issues = append(issues, "buildx plugin not found (docker buildx) - update Docker Desktop or install the docker-buildx-plugin package")
+		}
+	}
+
+	if checks.MinMemory == "" && checks.MinCPUs == 0 {
+		return issues
+	}
+	minMemory, err := parseByteSize(checks.MinMemory)
+	if err != nil {
+		return append(issues, err.Error())
+	}
+	info, err := dockerOutput(docker, "info", "--format", "{{.MemTotal}} {{.NCPU}}")
+	fields := strings.Fields(info)
+	if err != nil || len(fields) != 2 {
+		return append(issues, "couldn't read the memory and CPUs available to Docker (docker info) - is the daemon running?")
+	}
+	memory, memErr := strconv.ParseInt(fields[0], 10, 64)
+	cpus, cpuErr := strconv.Atoi(fields[1])
+	if memErr != nil || cpuErr != nil {
+		return append(issues, fmt.Sprintf("unexpected docker info output %q", info))
+	}
+
+	if minMemory > 0 && float64(memory) < float64(minMemory)*(1-dockerMemoryMargin) {
+		issues = append(issues, fmt.Sprintf("%s of memory available to Docker, %s required - increase it in Docker Desktop under Settings > Resources",
+			formatByteSize(memory), checks.MinMemory))
+	}
+	if checks.MinCPUs > 0 && cpus < checks.MinCPUs {
+		issues = append(issues, fmt.Sprintf("%d CPUs available to Docker, %d required - increase them in Docker Desktop under Settings > Resources",
+			cpus, checks.MinCPUs))
+	}
+	return issues
+}
+
+// parseByteSize parses a size such as "4GB", "512MiB" or "2g" into bytes. Like Docker, it uses
+// binary units: a GB is 1024 MB. An empty size is 0.
+func parseByteSize(size string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	if s == "" {
+		return 0, nil
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+
+	multiplier := int64(1)
+	for i, unit := range []string{"K", "M", "G", "T"} {
+		if strings.HasSuffix(s, unit) {
+			s = strings.TrimSuffix(s, unit)
+			multiplier = int64(1) << (10 * (i + 1))
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid minMemory %q - use a size such as \"4GB\" or \"512MB\"", size)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// formatByteSize formats bytes in GB (or MB below 1 GB) with one decimal, e.g. "3.8 GB".
+func formatByteSize(bytes int64) string {
+	if bytes < 1<<30 {
+		return fmt.Sprintf("%.0f MB", float64(bytes)/(1<<20))
+	}
+	return fmt.Sprintf("%.1f GB", float64(bytes)/(1<<30))
+}
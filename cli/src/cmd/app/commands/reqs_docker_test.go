@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckDocker(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     DockerChecks
+		outputs    map[string]string // docker subcommand -> output; missing = command fails
+		wantIssues []string          // substrings of the expected issues, in order
+	}{
+		{
+			name:    "all satisfied",
+			checks:  DockerChecks{Compose: true, Buildx: true, MinMemory: "4GB", MinCPUs: 4},
+			outputs: map[string]string{"compose": "2.27.0-desktop.2", "buildx": "github.com/docker/buildx v0.14.1", "info": "8218439680 8"},
+		},
+		{
+			name:       "plugins missing",
+			checks:     DockerChecks{Compose: true, Buildx: true},
+			outputs:    map[string]string{},
+			wantIssues: []string{"Compose v2 plugin not found", "buildx plugin not found"},
+		},
+		{
+			name:       "compose v1",
+			checks:     DockerChecks{Compose: true},
+			outputs:    map[string]string{"compose": "1.29.2"},
+			wantIssues: []string{"Compose 1.29.2 found, v2 required"},
+		},
+		{
+			name:   "memory within the VM margin",
+			checks: DockerChecks{MinMemory: "4GB"},
+			// Docker Desktop set to 4 GB reports a little less
+			outputs: map[string]string{"info": "4108517376 2"},
+		},
+		{
+			name:       "not enough resources",
+			checks:     DockerChecks{MinMemory: "8g", MinCPUs: 4},
+			outputs:    map[string]string{"info": "4108517376 2"},
+			wantIssues: []string{"3.8 GB of memory available to Docker, 8g required", "2 CPUs available to Docker, 4 required"},
+		},
+		{
+			name:       "daemon not running",
+			checks:     DockerChecks{MinCPUs: 2},
+			outputs:    map[string]string{},
+			wantIssues: []string{"is the daemon running?"},
+		},
+		{
+			name:       "invalid minMemory",
+			checks:     DockerChecks{MinMemory: "lots"},
+			outputs:    map[string]string{"info": "4108517376 2"},
+			wantIssues: []string{`invalid minMemory "lots"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := dockerOutput
+			defer func() { dockerOutput = original }()
+			dockerOutput = func(docker string, args ...string) (string, error) {
+				if docker != "/usr/bin/docker" {
+					t.Errorf("ran %q, want the requirement's docker", docker)
+				}
+				output, ok := tt.outputs[args[0]]
+				if !ok {
+					return "", errors.New("exit status 1")
+				}
+				return output, nil
+			}
+
+			issues := checkDocker(tt.checks, "/usr/bin/docker")
+			if len(issues) != len(tt.wantIssues) {
+				t.Fatalf("checkDocker() = %q, want %d issues", issues, len(tt.wantIssues))
+			}
+			for i, want := range tt.wantIssues {
+				if !strings.Contains(issues[i], want) {
+					t.Errorf("issue %d = %q, want it to contain %q", i, issues[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{size: "", want: 0},
+		{size: "1024", want: 1024},
+		{size: "512MB", want: 512 << 20},
+		{size: "512MiB", want: 512 << 20},
+		{size: "4GB", want: 4 << 30},
+		{size: "2g", want: 2 << 30},
+		{size: "1.5 GB", want: 3 << 29},
+		{size: "lots", wantErr: true},
+		{size: "-1GB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := parseByteSize(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseByteSize(%q) error = %v, wantErr %v", tt.size, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseByteSize(%q) = %d, want %d", tt.size, got, tt.want)
+			}
+		})
+	}
+}
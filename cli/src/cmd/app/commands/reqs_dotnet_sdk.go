@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-core/cliout"
+)
+
+// findGlobalJSONPins returns the unique global.json SDK pins of the .NET projects under
+// projectDir, sorted by path.
+func findGlobalJSONPins(projectDir string) []detector.GlobalJSON {
+	projects, err := detector.FindDotnetProjects(projectDir)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var pins []detector.GlobalJSON
+	for _, project := range projects {
+		pin, ok := detector.FindGlobalJSON(filepath.Dir(project.Path))
+		if !ok || seen[pin.Path] {
+			continue
+		}
+		seen[pin.Path] = true
+		pins = append(pins, pin)
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].Path < pins[j].Path })
+	return pins
+}
+
+// findServiceGlobalJSONPins returns the global.json SDK pins of the selected services'
+// projects, or of every project under projectDir when no services are selected.
+func findServiceGlobalJSONPins(projectDir string, azureYaml *AzureYaml, services []string) []detector.GlobalJSON {
+	if len(services) == 0 {
+		return findGlobalJSONPins(projectDir)
+	}
+
+	// An invalid selection is reported by requirementsFor
+	names, err := azureYaml.selectServices(services)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var pins []detector.GlobalJSON
+	for _, name := range names {
+		svc, ok := azureYaml.Services[name]
+		if !ok || svc.Project == "" {
+			continue
+		}
+		for _, pin := range findGlobalJSONPins(filepath.Join(projectDir, svc.Project)) {
+			if !seen[pin.Path] {
+				seen[pin.Path] = true
+				pins = append(pins, pin)
+			}
+		}
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].Path < pins[j].Path })
+	return pins
+}
+
+// checkGlobalJSONPins checks that an installed .NET SDK satisfies every global.json pin under
+// its roll-forward policy. The dotnet command fails to build a project whose pin no SDK
+// satisfies, so an unsatisfied pin is required. An SDK that's only in another .NET
+// installation than the dotnet on PATH is reported with a warning, since run uses it.
+func checkGlobalJSONPins(pins []detector.GlobalJSON) ([]ReqResult, bool) {
+	results := make([]ReqResult, 0, len(pins))
+	allSatisfied := true
+	pathRoot := detector.DotnetPathRoot()
+
+	for _, pin := range pins {
+		result := ReqResult{
+			Name:       toolDotnet,
+			Required:   pin.Requirement(),
+			InstallURL: installURLRegistry[toolDotnet],
+			Severity:   SeverityRequired,
+		}
+
+		root, version, ok := detector.FindDotnetSDK(pin)
+		switch {
+		case ok && root == pathRoot:
+			result.Installed = true
+			result.Satisfied = true
+			result.Version = version
+			result.Message = "Satisfied"
+			if !cliout.IsJSON() {
+				cliout.ItemSuccess("%s: SDK %s (global.json: %s)", toolDotnet, version, pin.Requirement())
+			}
+		case ok:
+			result.Installed = true
+			result.Satisfied = true
+			result.Version = version
+			result.Message = fmt.Sprintf("SDK %s is in %s, not the dotnet on PATH", version, root)
+			if !cliout.IsJSON() {
+				cliout.ItemWarning("%s: %s (global.json: %s), azd app run uses it", toolDotnet, result.Message, pin.Requirement())
+			}
+		default:
+			allSatisfied = false
+			var installed []string
+			for _, root := range detector.DotnetRoots() {
+				installed = append(installed, detector.InstalledDotnetSDKs(root)...)
+			}
+			result.Installed = len(installed) > 0
+			if result.Installed {
+				sort.Strings(installed)
+				result.Message = fmt.Sprintf("SDK %s required by %s, installed: %s", pin.Requirement(), pin.Path, strings.Join(installed, ", "))
+			} else {
+				result.Message = "Not installed"
+			}
+			if !cliout.IsJSON() {
+				if result.Installed {
+					cliout.ItemError("%s: %s", toolDotnet, result.Message)
+					cliout.Item("   Fix: install the SDK, or change the version or rollForward in global.json")
+				} else {
+					cliout.ItemError("%s: NOT INSTALLED (required by %s)", toolDotnet, pin.Path)
+				}
+				cliout.Item("   Install: %s", result.InstallURL)
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, allSatisfied
+}
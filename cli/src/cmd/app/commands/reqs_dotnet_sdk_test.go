@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+)
+
+func TestFindGlobalJSONPins(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"global.json":          `{"sdk": {"version": "8.0.100"}}`,
+		"api/api.csproj":       "<Project />",
+		"worker/worker.csproj": "<Project />",
+		"legacy/global.json":   `{"sdk": {"version": "6.0.400", "rollForward": "latestFeature"}}`,
+		"legacy/legacy.csproj": "<Project />",
+		"tools/tools.csproj":   "<Project />",
+		"tools/global.json":    `{"msbuild-sdks": {}}`,
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pins := findGlobalJSONPins(root)
+	if len(pins) != 2 {
+		t.Fatalf("findGlobalJSONPins() = %+v, want 2 pins", pins)
+	}
+	if pins[0].Path != filepath.Join(root, "global.json") || pins[0].Version != "8.0.100" {
+		t.Errorf("pins[0] = %+v", pins[0])
+	}
+	if pins[1].Path != filepath.Join(root, "legacy", "global.json") || pins[1].RollForward != "latestFeature" {
+		t.Errorf("pins[1] = %+v", pins[1])
+	}
+}
+
+func TestCheckGlobalJSONPins(t *testing.T) {
+	dotnetRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dotnetRoot, "sdk", "8.0.104"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("DOTNET_ROOT", dotnetRoot)
+
+	pins := []detector.GlobalJSON{
+		{Path: "/app/global.json", Version: "8.0.100", RollForward: "patch"},
+		{Path: "/app/legacy/global.json", Version: "99.0.100", RollForward: "patch"},
+	}
+	results, satisfied := checkGlobalJSONPins(pins)
+	if satisfied {
+		t.Error("checkGlobalJSONPins() satisfied = true, want false")
+	}
+	if len(results) != 2 {
+		t.Fatalf("checkGlobalJSONPins() returned %d results, want 2", len(results))
+	}
+	if !results[0].Satisfied || results[0].Version != "8.0.104" || results[0].Severity != SeverityRequired {
+		t.Errorf("results[0] = %+v, want SDK 8.0.104 satisfied", results[0])
+	}
+	if results[1].Satisfied || !results[1].Installed {
+		t.Errorf("results[1] = %+v, want an unsatisfied installed result", results[1])
+	}
+}
@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jongio/azd-app/cli/src/internal/cache"
+	"github.com/jongio/azd-app/cli/src/internal/toolinstaller"
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/pathutil"
+)
+
+// ToolInstallResult represents the result of installing a missing requirement.
+type ToolInstallResult struct {
+	Name      string   `json:"name"`
+	Method    string   `json:"method,omitempty"`
+	Commands  []string `json:"commands,omitempty"`
+	Installed bool     `json:"installed"`
+	Skipped   bool     `json:"skipped,omitempty"`
+	Message   string   `json:"message,omitempty"`
+
+	plan *toolinstaller.Plan
+}
+
+// toolPlanner plans how a tool is installed on this machine.
+type toolPlanner interface {
+	Plan(tool string) (*toolinstaller.Plan, error)
+}
+
+// planMissingTools plans an install for each requirement that is not installed.
+// Requirements that can't be installed automatically are returned with a message
+// pointing to their install page.
+func planMissingTools(planner toolPlanner, results []ReqResult) []ToolInstallResult {
+	installs := make([]ToolInstallResult, 0, len(results))
+	for _, result := range results {
+		if result.Installed {
+			continue
+		}
+
+		install := ToolInstallResult{Name: result.Name}
+		if result.Path != "" {
+			// A package manager installs on PATH, not where the requirement points
+			install.Skipped = true
+			install.Message = fmt.Sprintf("pinned to %s - install it there", result.Path)
+			installs = append(installs, install)
+			continue
+		}
+		plan, err := planner.Plan(result.Name)
+		if err != nil {
+			install.Skipped = true
+			install.Message = err.Error()
+			if result.InstallURL != "" {
+				install.Message = fmt.Sprintf("%s - install manually from %s", err, result.InstallURL)
+			}
+		} else {
+			install.plan = plan
+			install.Method = plan.Method
+			install.Commands = plan.CommandLines()
+		}
+		installs = append(installs, install)
+	}
+	return installs
+}
+
+// runReqsInstall installs requirements that are not installed using the platform's package managers.
+// With dryRun, it only prints the commands that would run. With yes, it doesn't ask before each install.
+func runReqsInstall(dryRun, yes bool) error {
+	if cliout.IsJSON() && !dryRun && !yes {
+		return fmt.Errorf("--install with JSON output requires --yes or --dry-run")
+	}
+
+	cliout.CommandHeader("reqs --install", "Install missing tools")
+
+	azureYamlPath, azureYaml, err := loadAzureYaml()
+	if err != nil {
+		return err
+	}
+	reqs, err := azureYaml.requirementsFor(execContext.Services)
+	if err != nil {
+		return err
+	}
+	if len(reqs) == 0 {
+		return fmt.Errorf("no reqs defined in azure.yaml - run 'azd app reqs --generate' to add them")
+	}
+
+	// Step 1: Find requirements that are not installed
+	initialResults, _ := performReqsCheck(reqs)
+
+	installer := toolinstaller.New()
+	installs := planMissingTools(installer, initialResults)
+	if len(installs) == 0 {
+		if cliout.IsJSON() {
+			return cliout.PrintJSON(map[string]interface{}{
+				"success": true,
+				"message": "All required tools already installed",
+			})
+		}
+		cliout.Success("All required tools already installed!")
+		return nil
+	}
+
+	// Step 2: Show the plan
+	if !cliout.IsJSON() {
+		cliout.Section(cliout.IconTool, "Missing tools:")
+		for _, install := range installs {
+			if install.Skipped {
+				cliout.ItemWarning("%s: %s", install.Name, install.Message)
+				continue
+			}
+			cliout.ItemInfo("%s (%s)", install.Name, install.Method)
+			for _, line := range install.Commands {
+				cliout.Item("    %s$ %s%s", cliout.Dim, line, cliout.Reset)
+			}
+		}
+	}
+
+	if dryRun {
+		if cliout.IsJSON() {
+			return cliout.PrintJSON(map[string]interface{}{
+				"success":  true,
+				"dryRun":   true,
+				"installs": installs,
+			})
+		}
+		cliout.Newline()
+		cliout.Info("Dry run - no tools were installed. Run without --dry-run to install them.")
+		return nil
+	}
+
+	// Step 3: Install each planned tool. Keep stdout clean for JSON output.
+	var stdout io.Writer = os.Stdout
+	if cliout.IsJSON() {
+		stdout = os.Stderr
+	}
+
+	installedCount := 0
+	for i := range installs {
+		install := &installs[i]
+		if install.Skipped {
+			continue
+		}
+
+		if !cliout.IsJSON() {
+			cliout.Newline()
+		}
+		if !yes && !cliout.Confirm(fmt.Sprintf("Install %s with %s?", install.Name, install.Method)) {
+			install.Skipped = true
+			install.Message = "Skipped by user"
+			continue
+		}
+
+		if !cliout.IsJSON() {
+			cliout.Step(cliout.IconTool, "Installing %s with %s...", install.Name, install.Method)
+		}
+		if err := installer.Install(context.Background(), install.plan, stdout, os.Stderr); err != nil {
+			install.Message = err.Error()
+			if !cliout.IsJSON() {
+				cliout.ItemError("%v", err)
+			}
+			continue
+		}
+
+		install.Installed = true
+		installedCount++
+		if !cliout.IsJSON() {
+			cliout.ItemSuccess("Installed %s", install.Name)
+		}
+	}
+
+	// Step 4: Pick up newly installed tools and invalidate cached results
+	if installedCount > 0 {
+		if _, err := pathutil.RefreshPATH(); err != nil && !cliout.IsJSON() {
+			cliout.Warning("Failed to refresh PATH: %v", err)
+		}
+
+		cacheDir := filepath.Join(filepath.Dir(azureYamlPath), ".azure", "cache")
+		cacheManager, err := cache.NewCacheManagerWithOptions(cache.CacheOptions{
+			Enabled:  true,
+			CacheDir: cacheDir,
+		})
+		if err == nil {
+			if err := cacheManager.ClearCache(); err != nil && !cliout.IsJSON() {
+				cliout.Warning("Failed to clear cache: %v", err)
+			}
+		}
+	}
+
+	// Step 5: Re-check all requirements
+	if !cliout.IsJSON() {
+		cliout.Newline()
+		cliout.Section(cliout.IconCheck, "Re-checking requirements...")
+	}
+
+	allResults, allSatisfied := performReqsCheck(reqs)
+
+	if cliout.IsJSON() {
+		return cliout.PrintJSON(map[string]interface{}{
+			"success":      installedCount > 0,
+			"installed":    installedCount,
+			"total":        len(installs),
+			"allSatisfied": allSatisfied,
+			"installs":     installs,
+			"results":      allResults,
+		})
+	}
+
+	cliout.Newline()
+	if installedCount > 0 {
+		cliout.Success("Installed %d of %d missing tools!", installedCount, len(installs))
+	} else {
+		cliout.Warning("No tools were installed")
+	}
+
+	if !allSatisfied {
+		cliout.Newline()
+		cliout.Info("%s Next steps:", cliout.IconBulb)
+		cliout.Item("1. Install the remaining tools from the links above")
+		cliout.Item("2. Restart your terminal to refresh PATH")
+		cliout.Item("3. Run 'azd app reqs' again to verify")
+		return fmt.Errorf("not all requirements satisfied")
+	}
+
+	cliout.Newline()
+	cliout.Success("All requirements now satisfied!")
+	return nil
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/toolinstaller"
+)
+
+// fakePlanner plans installs with brew for the tools it knows.
+type fakePlanner map[string]bool
+
+func (f fakePlanner) Plan(tool string) (*toolinstaller.Plan, error) {
+	if !f[tool] {
+		return nil, fmt.Errorf("%w: %s", toolinstaller.ErrUnsupportedTool, tool)
+	}
+	return &toolinstaller.Plan{Tool: tool, Method: "brew", Commands: [][]string{{"brew", "install", tool}}}, nil
+}
+
+func TestPlanMissingTools(t *testing.T) {
+	results := []ReqResult{
+		{Name: "node", Installed: true, Satisfied: true},
+		{Name: "go", Installed: true, Satisfied: false}, // Too old, not missing
+		{Name: "git", Installed: false},
+		{Name: "my-tool", Installed: false, InstallURL: "https://example.com/install"},
+		{Name: "git", Installed: false, Path: "/repo/tools/git"}, // Pinned, so not installed on PATH
+	}
+
+	installs := planMissingTools(fakePlanner{"git": true}, results)
+	if len(installs) != 3 {
+		t.Fatalf("planMissingTools() returned %d installs, want 3 (missing tools only)", len(installs))
+	}
+
+	git := installs[0]
+	if git.Name != "git" || git.Skipped || git.Method != "brew" {
+		t.Errorf("git install = %+v, want a brew install", git)
+	}
+	if len(git.Commands) != 1 || git.Commands[0] != "brew install git" {
+		t.Errorf("git commands = %v, want [brew install git]", git.Commands)
+	}
+
+	custom := installs[1]
+	if !custom.Skipped {
+		t.Errorf("my-tool install should be skipped, got %+v", custom)
+	}
+	if !strings.Contains(custom.Message, "https://example.com/install") {
+		t.Errorf("my-tool message = %q, want it to point to the install URL", custom.Message)
+	}
+
+	pinned := installs[2]
+	if !pinned.Skipped || !strings.Contains(pinned.Message, "/repo/tools/git") {
+		t.Errorf("pinned git install = %+v, want it skipped with its path", pinned)
+	}
+}
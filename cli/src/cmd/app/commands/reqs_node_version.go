@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-core/cliout"
+)
+
+// findNodeVersionPins returns the unique Node.js versions pinned by .nvmrc, .node-version or
+// volta for the Node.js projects under projectDir, sorted by pinning file. rootDir bounds the
+// search for pinning files in parent directories.
+func findNodeVersionPins(projectDir, rootDir string) []detector.NodeVersionPin {
+	projects, err := detector.FindNodeProjects(projectDir)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var pins []detector.NodeVersionPin
+	for _, project := range projects {
+		pin, ok := detector.FindNodeVersionPin(project.Dir, rootDir)
+		if !ok || seen[pin.File] {
+			continue
+		}
+		seen[pin.File] = true
+		pins = append(pins, pin)
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].File < pins[j].File })
+	return pins
+}
+
+// findServiceNodeVersionPins returns the Node.js version pins of the selected services'
+// projects, or of every project under projectDir when no services are selected.
+func findServiceNodeVersionPins(projectDir string, azureYaml *AzureYaml, services []string) []detector.NodeVersionPin {
+	if len(services) == 0 {
+		return findNodeVersionPins(projectDir, projectDir)
+	}
+
+	// An invalid selection is reported by requirementsFor
+	names, err := azureYaml.selectServices(services)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var pins []detector.NodeVersionPin
+	for _, name := range names {
+		svc, ok := azureYaml.Services[name]
+		if !ok || svc.Project == "" {
+			continue
+		}
+		for _, pin := range findNodeVersionPins(filepath.Join(projectDir, svc.Project), projectDir) {
+			if !seen[pin.File] {
+				seen[pin.File] = true
+				pins = append(pins, pin)
+			}
+		}
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].File < pins[j].File })
+	return pins
+}
+
+// checkNodeVersionPins compares the active Node.js version with every pinned version.
+// A mismatch is recommended rather than required: the services still run with the active
+// version, unless they use the version manager (node.useVersionManager).
+func checkNodeVersionPins(pins []detector.NodeVersionPin) []ReqResult {
+	checker := NewPrerequisiteChecker()
+	results := make([]ReqResult, 0, len(pins))
+
+	for _, pin := range pins {
+		source := pin.Source()
+		if pin.IsAlias() {
+			// Aliases such as lts/* only resolve in the version manager
+			result := ReqResult{
+				Name:      toolNode,
+				Required:  pin.Version,
+				Satisfied: true,
+				Message:   fmt.Sprintf("Alias %s from %s not checked", pin.Version, source),
+				Severity:  SeverityRecommended,
+			}
+			result.Installed, result.Version, _ = checker.getInstalledVersion(Prerequisite{Name: toolNode})
+			if !cliout.IsJSON() {
+				cliout.Item("%s: %s pinned by %s (alias not checked)", toolNode, pin.Version, source)
+			}
+			results = append(results, result)
+			continue
+		}
+
+		result := checker.Evaluate(Prerequisite{Name: toolNode, Version: pin.Version, Severity: SeverityRecommended})
+		switch {
+		case !result.Installed:
+			result.Message = fmt.Sprintf("node %s required by %s but node is not installed", pin.Version, source)
+		case result.Satisfied:
+			result.Message = fmt.Sprintf("Matches %s", source)
+		default:
+			result.Message = fmt.Sprintf("node %s required by %s but %s active", pin.Version, source, result.Version)
+		}
+
+		if !cliout.IsJSON() {
+			if result.Satisfied {
+				cliout.ItemSuccess("%s: %s (pinned by %s: %s)", toolNode, result.Version, source, pin.Version)
+			} else {
+				cliout.ItemWarning("%s", result.Message)
+				cliout.Item("   Fix: %s", nodeVersionFix(pin))
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// nodeVersionFix suggests how to switch to a pinned Node.js version.
+func nodeVersionFix(pin detector.NodeVersionPin) string {
+	switch pin.Manager {
+	case detector.NodeVersionManagerVolta:
+		return "volta install node@" + pin.Version
+	case detector.NodeVersionManagerFnm:
+		return "fnm use --install-if-missing " + pin.Version
+	default:
+		return "nvm install " + pin.Version + " && nvm use " + pin.Version
+	}
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+)
+
+func TestFindNodeVersionPins(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"web/package.json":   `{"name": "web"}`,
+		"web/.nvmrc":         "18",
+		"admin/package.json": `{"name": "admin", "volta": {"node": "20.11.1"}}`,
+		"api/package.json":   `{"name": "api"}`,
+		"docs/package.json":  `{"name": "docs"}`,
+		"docs/.node-version": "20",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pins := findNodeVersionPins(root, root)
+	var got []string
+	for _, pin := range pins {
+		got = append(got, pin.Source()+"="+pin.Version)
+	}
+	want := "package.json (volta)=20.11.1 .node-version=20 .nvmrc=18"
+	if strings.Join(got, " ") != want {
+		t.Errorf("findNodeVersionPins() = %v, want %s", got, want)
+	}
+}
+
+func TestCheckNodeVersionPins(t *testing.T) {
+	if _, err := exec.LookPath(toolNode); err != nil {
+		t.Skip("node not installed")
+	}
+
+	pins := []detector.NodeVersionPin{
+		{Version: "1", File: "/app/.nvmrc", Manager: detector.NodeVersionManagerNvm},
+		{Version: "lts/*", File: "/app/web/.nvmrc", Manager: detector.NodeVersionManagerNvm},
+	}
+	results := checkNodeVersionPins(pins)
+	if len(results) != 2 {
+		t.Fatalf("checkNodeVersionPins() returned %d results, want 2", len(results))
+	}
+
+	mismatch := results[0]
+	if mismatch.Satisfied || mismatch.Severity != SeverityRecommended {
+		t.Errorf("mismatch = %+v, want an unsatisfied recommended result", mismatch)
+	}
+	if want := "node 1 required by .nvmrc but " + mismatch.Version + " active"; mismatch.Message != want {
+		t.Errorf("mismatch message = %q, want %q", mismatch.Message, want)
+	}
+	if alias := results[1]; !alias.Satisfied || !strings.Contains(alias.Message, "lts/*") {
+		t.Errorf("alias = %+v, want a satisfied result naming the alias", alias)
+	}
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultRunningCheckHTTPTimeout bounds an HTTP running check without a timeout.
+const defaultRunningCheckHTTPTimeout = 5 * time.Second
+
+// maxRunningCheckBody is how much of the response an HTTP running check searches for the
+// expected substring.
+const maxRunningCheckBody = 64 * 1024
+
+// RunningCheckHTTP configures an HTTP probe that verifies a tool is running, e.g. that a
+// daemon's API responds or a local container reports healthy.
+type RunningCheckHTTP struct {
+	URL            string `yaml:"url"`
+	ExpectedStatus int    `yaml:"expectedStatus,omitempty"` // Expected status code (default: any 2xx)
+	Timeout        string `yaml:"timeout,omitempty"`        // Request timeout, e.g. "2s" (default: 5s)
+}
+
+// probeRunningHTTP sends a GET request to the probe's URL and checks the response status and,
+// when expected isn't empty, that the response body contains it.
+func probeRunningHTTP(probe RunningCheckHTTP, expected string) error {
+	if probe.URL == "" {
+		return fmt.Errorf("runningCheckHttp has no url")
+	}
+	timeout := defaultRunningCheckHTTPTimeout
+	if probe.Timeout != "" {
+		d, err := time.ParseDuration(probe.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid runningCheckHttp timeout %q", probe.Timeout)
+		}
+		timeout = d
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.URL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid runningCheckHttp url: %w", err)
+	}
+	// #nosec G107 -- URL comes from the running check configuration in azure.yaml
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if probe.ExpectedStatus != 0 && resp.StatusCode != probe.ExpectedStatus {
+		return fmt.Errorf("status %d, want %d", resp.StatusCode, probe.ExpectedStatus)
+	}
+	if probe.ExpectedStatus == 0 && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		return fmt.Errorf("status %d, want 2xx", resp.StatusCode)
+	}
+
+	if expected != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxRunningCheckBody))
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if !strings.Contains(string(body), expected) {
+			return fmt.Errorf("response doesn't contain %q", expected)
+		}
+	}
+	return nil
+}
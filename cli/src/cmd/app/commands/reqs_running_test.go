@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProbeRunningHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthy":
+			fmt.Fprint(w, `{"status":"Healthy"}`)
+		case "/ping":
+			w.WriteHeader(http.StatusNoContent)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		probe    RunningCheckHTTP
+		expected string
+		wantErr  bool
+	}{
+		{name: "2xx", probe: RunningCheckHTTP{URL: server.URL + "/ping"}},
+		{name: "expected status", probe: RunningCheckHTTP{URL: server.URL + "/down", ExpectedStatus: http.StatusServiceUnavailable}},
+		{name: "unexpected status", probe: RunningCheckHTTP{URL: server.URL + "/ping", ExpectedStatus: http.StatusOK}, wantErr: true},
+		{name: "not 2xx", probe: RunningCheckHTTP{URL: server.URL + "/down"}, wantErr: true},
+		{name: "expected body", probe: RunningCheckHTTP{URL: server.URL + "/healthy"}, expected: "Healthy"},
+		{name: "unexpected body", probe: RunningCheckHTTP{URL: server.URL + "/healthy"}, expected: "Degraded", wantErr: true},
+		{name: "timeout", probe: RunningCheckHTTP{URL: server.URL + "/slow", Timeout: "50ms"}, wantErr: true},
+		{name: "invalid timeout", probe: RunningCheckHTTP{URL: server.URL + "/ping", Timeout: "soon"}, wantErr: true},
+		{name: "no url", probe: RunningCheckHTTP{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := probeRunningHTTP(tt.probe, tt.expected)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("probeRunningHTTP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckPrerequisiteWithRunningCheckHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := NewPrerequisiteChecker()
+	prereq := Prerequisite{
+		Name:             "go",
+		CheckRunning:     true,
+		RunningCheckHTTP: &RunningCheckHTTP{URL: server.URL},
+	}
+	if !checker.checkIsRunning(prereq) {
+		t.Error("checkIsRunning() = false, want true for a responding endpoint")
+	}
+
+	server.Close()
+	if checker.checkIsRunning(prereq) {
+		t.Error("checkIsRunning() = true, want false once the endpoint is down")
+	}
+}
@@ -416,6 +416,13 @@ func TestToolAliases(t *testing.T) {
 			},
 			expected: "az",
 		},
+		{
+			name: "rustc alias resolves to rust",
+			prereq: Prerequisite{
+				Name: "rustc",
+			},
+			expected: "rustc",
+		},
 		{
 			name: "node uses node directly",
 			prereq: Prerequisite{
@@ -1478,3 +1485,238 @@ func yamlArgsString(args []string) string {
 	}
 	return "[" + strings.Join(quoted, ", ") + "]"
 }
+
+func TestPrerequisiteSeverity(t *testing.T) {
+	tests := []struct {
+		severity string
+		want     string
+	}{
+		{severity: "", want: SeverityRequired},
+		{severity: "required", want: SeverityRequired},
+		{severity: "recommended", want: SeverityRecommended},
+		{severity: "Optional", want: SeverityOptional},
+		{severity: " optional ", want: SeverityOptional},
+		{severity: "nice-to-have", want: SeverityRequired},
+	}
+
+	for _, tt := range tests {
+		if got := (Prerequisite{Severity: tt.severity}).severity(); got != tt.want {
+			t.Errorf("Prerequisite{Severity: %q}.severity() = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestAzureYaml_RequirementsFor(t *testing.T) {
+	data := `
+reqs:
+  - name: azd
+    minVersion: "1.0.0"
+services:
+  api:
+    project: ./api
+    reqs:
+      - name: python
+        version: "^3.12"
+  web:
+    project: ./web
+    reqs:
+      - name: node
+        minVersion: "20.0.0"
+      - name: azd
+        minVersion: "1.0.0"
+  cache:
+    image: redis:7
+  worker:
+    project: ./worker
+    uses: [cache, storage]
+`
+	var azureYaml AzureYaml
+	if err := yaml.Unmarshal([]byte(data), &azureYaml); err != nil {
+		t.Fatalf("failed to parse azure.yaml: %v", err)
+	}
+
+	names := func(reqs []Prerequisite) []string {
+		result := make([]string, 0, len(reqs))
+		for _, req := range reqs {
+			result = append(result, req.Name)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		services []string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "all services", services: nil, expected: []string{"azd", "python", "node", "docker"}},
+		{name: "one service", services: []string{"api"}, expected: []string{"azd", "python"}},
+		{name: "duplicates checked once", services: []string{"web"}, expected: []string{"azd", "node"}},
+		{name: "container service", services: []string{"cache"}, expected: []string{"azd", "docker"}},
+		{name: "glob pattern", services: []string{"a*"}, expected: []string{"azd", "python"}},
+		{name: "dependencies included", services: []string{"worker"}, expected: []string{"azd", "docker"}},
+		{name: "unknown service", services: []string{"missing"}, wantErr: true},
+		{name: "pattern without match", services: []string{"svc-*"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, err := azureYaml.requirementsFor(tt.services)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("requirementsFor() should fail for an unknown service")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("requirementsFor() error = %v", err)
+			}
+			if got := names(reqs); strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("requirementsFor(%v) = %v, want %v", tt.services, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAzureYaml_ApplyProfile(t *testing.T) {
+	data := `
+reqs:
+  - name: node
+    minVersion: "20.0.0"
+services:
+  api:
+    project: ./api
+    reqs:
+      - name: python
+        minVersion: "3.11.0"
+profiles:
+  test:
+    reqs:
+      - name: Node
+        minVersion: "22.0.0"
+      - name: docker
+        checkRunning: true
+    services:
+      api:
+        reqs:
+          - name: python
+            minVersion: "3.12.0"
+      worker:
+        reqs:
+          - name: go
+`
+	var azureYaml AzureYaml
+	if err := yaml.Unmarshal([]byte(data), &azureYaml); err != nil {
+		t.Fatalf("failed to parse azure.yaml: %v", err)
+	}
+
+	azureYaml.applyProfile("missing")
+	if len(azureYaml.Reqs) != 1 || azureYaml.Reqs[0].MinVersion != "20.0.0" {
+		t.Fatalf("applyProfile(missing) changed reqs: %+v", azureYaml.Reqs)
+	}
+
+	azureYaml.applyProfile("test")
+	reqs, err := azureYaml.requirementsFor(nil)
+	if err != nil {
+		t.Fatalf("requirementsFor() error = %v", err)
+	}
+
+	got := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		got = append(got, req.Name+"@"+req.MinVersion)
+	}
+	want := []string{"Node@22.0.0", "docker@", "python@3.12.0"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("requirementsFor() with profile = %v, want %v", got, want)
+	}
+}
+
+func TestAzureYaml_ResolveToolPaths(t *testing.T) {
+	funcPath := filepath.Join(t.TempDir(), "func")
+	data := fmt.Sprintf(`
+reqs:
+  - name: bicep
+    path: ./tools/bicep
+  - name: node
+services:
+  api:
+    project: ./api
+    reqs:
+      - name: func
+        path: %s
+profiles:
+  ci:
+    reqs:
+      - name: bicep
+        path: ci/bicep
+`, funcPath)
+	var azureYaml AzureYaml
+	if err := yaml.Unmarshal([]byte(data), &azureYaml); err != nil {
+		t.Fatalf("failed to parse azure.yaml: %v", err)
+	}
+
+	root := filepath.Join(string(filepath.Separator), "repo")
+	azureYaml.resolveToolPaths(root)
+
+	if got, want := azureYaml.Reqs[0].Path, filepath.Join(root, "tools", "bicep"); got != want {
+		t.Errorf("bicep path = %q, want %q", got, want)
+	}
+	if got := azureYaml.Reqs[1].Path; got != "" {
+		t.Errorf("node path = %q, want none", got)
+	}
+	if got := azureYaml.Services["api"].Reqs[0].Path; got != funcPath {
+		t.Errorf("func path = %q, want %q (absolute paths are kept)", got, funcPath)
+	}
+	if got, want := azureYaml.Profiles["ci"].Reqs[0].Path, filepath.Join(root, "ci", "bicep"); got != want {
+		t.Errorf("profile bicep path = %q, want %q", got, want)
+	}
+}
+
+func TestCheckPrerequisiteWithPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the tool")
+	}
+
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "mytool")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\necho \"mytool 1.4.2\"\n"), 0o700); err != nil { // #nosec G306 -- test executable
+		t.Fatal(err)
+	}
+
+	checker := NewPrerequisiteChecker()
+	result := checker.Evaluate(Prerequisite{Name: "mytool", Path: tool, MinVersion: "1.2.0"})
+	if !result.Satisfied || result.Version != "1.4.2" || result.Path != tool {
+		t.Errorf("Evaluate() = %+v, want mytool 1.4.2 satisfied from %s", result, tool)
+	}
+
+	missing := filepath.Join(dir, "missing")
+	result = checker.Evaluate(Prerequisite{Name: "mytool", Path: missing})
+	if result.Installed || result.Message != "Not found at "+missing {
+		t.Errorf("Evaluate() = %+v, want not found at %s", result, missing)
+	}
+}
+
+func TestMergeReferencedReqs_SiblingRefsIntoSameProject(t *testing.T) {
+	root := t.TempDir()
+	otherDir := filepath.Join(root, "other")
+	if err := os.MkdirAll(otherDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	other := "name: other\nreqs:\n  - name: python\n    minVersion: \"3.11\"\nservices:\n  api:\n    project: ./api\n  worker:\n    project: ./worker\n"
+	if err := os.WriteFile(filepath.Join(otherDir, "azure.yaml"), []byte(other), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	appDir := filepath.Join(root, "app")
+	azureYaml := AzureYaml{Services: map[string]ReqsService{
+		"orders": {Ref: "../other#api"},
+		"jobs":   {Ref: "../other#worker"},
+	}}
+	mergeReferencedReqs(&azureYaml, appDir, map[string]bool{filepath.Join(appDir, "azure.yaml"): true})
+
+	for _, name := range []string{"orders", "jobs"} {
+		if !hasPrerequisite(azureYaml.Services[name].Reqs, "python") {
+			t.Errorf("service %s reqs = %v, want python from the referenced project", name, azureYaml.Services[name].Reqs)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version. A prerelease (e.g. 9.0.100-rc.1) sorts before its release.
+type semver struct {
+	major, minor, patch int
+	pre                 []string
+}
+
+// comparator is a single version comparison, e.g. ">=18.0.0".
+type comparator struct {
+	op      string // One of "=", ">", ">=", "<", "<="
+	version semver
+}
+
+// versionConstraint is a semver range: comparator sets joined by "||", where every
+// comparator of a set must match (e.g. ">=18 <21 || ^22").
+type versionConstraint struct {
+	sets [][]comparator
+}
+
+// lowestPrerelease makes "<1.3.0-0" exclude 1.3.0 prereleases, as in npm ranges.
+var lowestPrerelease = []string{"0"}
+
+// parseVersionConstraint parses a semver range expression. Supported forms:
+//   - comparators: "=1.2.3", ">1.2", ">=18", "<21", "<=20.x"
+//   - x-ranges and partial versions: "1.2.x", "1.2", "18", "*"
+//   - tilde and caret ranges: "~1.2.3", "^3.12", "^0.2.3"
+//   - hyphen ranges: "1.2 - 2.3.4"
+//
+// Comparators are separated by spaces or commas and sets by "||".
+// Partial upper bounds include the whole range, so "<=20" allows 20.11.0.
+func parseVersionConstraint(expr string) (*versionConstraint, error) {
+	constraint := &versionConstraint{}
+	for _, group := range strings.Split(expr, "||") {
+		set, err := parseComparatorSet(group)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", expr, err)
+		}
+		constraint.sets = append(constraint.sets, set)
+	}
+	return constraint, nil
+}
+
+// check reports whether version satisfies the constraint.
+func (c *versionConstraint) check(version string) bool {
+	v, _, err := parsePartialVersion(version)
+	if err != nil {
+		return false
+	}
+	for _, set := range c.sets {
+		if satisfiesAll(v, set) {
+			return true
+		}
+	}
+	return false
+}
+
+// satisfiesAll reports whether v matches every comparator of a set.
+func satisfiesAll(v semver, set []comparator) bool {
+	for _, comp := range set {
+		cmp := compareSemver(v, comp.version)
+		var ok bool
+		switch comp.op {
+		case "=":
+			ok = cmp == 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// parseComparatorSet parses the comparators of one "||" group.
+func parseComparatorSet(group string) ([]comparator, error) {
+	tokens := strings.FieldsFunc(group, func(r rune) bool { return r == ' ' || r == '\t' || r == ',' })
+
+	// Hyphen range: "<from> - <to>"
+	if len(tokens) == 3 && tokens[1] == "-" {
+		return hyphenRange(tokens[0], tokens[2])
+	}
+
+	var set []comparator
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		// Allow a space between an operator and its version, e.g. ">= 18"
+		if strings.Trim(token, "<>=~^") == "" && i+1 < len(tokens) {
+			i++
+			token += tokens[i]
+		}
+		comps, err := parseComparator(token)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, comps...)
+	}
+	if len(set) == 0 {
+		return nil, fmt.Errorf("empty comparator set")
+	}
+	return set, nil
+}
+
+// parseComparator expands one range token into plain comparators.
+func parseComparator(token string) ([]comparator, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(token, prefix) {
+			op = prefix
+			break
+		}
+	}
+	version := strings.TrimPrefix(token, op)
+	if op != "" && version == "" {
+		return nil, fmt.Errorf("missing version after %q", op)
+	}
+	v, parts, err := parsePartialVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "", "=":
+		if parts == 0 {
+			return []comparator{{">=", semver{}}}, nil
+		}
+		if parts == 3 {
+			return []comparator{{"=", v}}, nil
+		}
+		return []comparator{{">=", v}, {"<", nextVersion(v, parts)}}, nil
+	case ">":
+		if parts == 0 {
+			return []comparator{{"<", semver{pre: lowestPrerelease}}}, nil // Nothing matches
+		}
+		if parts == 3 {
+			return []comparator{{">", v}}, nil
+		}
+		return []comparator{{">=", nextVersion(v, parts)}}, nil
+	case ">=":
+		return []comparator{{">=", v}}, nil
+	case "<":
+		if parts < 3 {
+			v.pre = lowestPrerelease
+		}
+		return []comparator{{"<", v}}, nil
+	case "<=":
+		if parts == 0 {
+			return []comparator{{">=", semver{}}}, nil
+		}
+		if parts == 3 {
+			return []comparator{{"<=", v}}, nil
+		}
+		return []comparator{{"<", nextVersion(v, parts)}}, nil
+	case "~":
+		// ~1.2.3 := >=1.2.3 <1.3.0-0, ~1 := >=1.0.0 <2.0.0-0
+		if parts == 0 {
+			return []comparator{{">=", semver{}}}, nil
+		}
+		upperParts := 2
+		if parts == 1 {
+			upperParts = 1
+		}
+		return []comparator{{">=", v}, {"<", nextVersion(v, upperParts)}}, nil
+	default: // "^"
+		// ^ allows changes that don't modify the left-most non-zero part
+		if parts == 0 {
+			return []comparator{{">=", semver{}}}, nil
+		}
+		upperParts := 1
+		switch {
+		case v.major == 0 && v.minor == 0 && parts == 3:
+			upperParts = 3
+		case v.major == 0 && parts >= 2:
+			upperParts = 2
+		}
+		return []comparator{{">=", v}, {"<", nextVersion(v, upperParts)}}, nil
+	}
+}
+
+// hyphenRange expands "<from> - <to>", where a partial upper bound includes its whole range.
+func hyphenRange(from, to string) ([]comparator, error) {
+	lower, _, err := parsePartialVersion(from)
+	if err != nil {
+		return nil, err
+	}
+	upper, parts, err := parsePartialVersion(to)
+	if err != nil {
+		return nil, err
+	}
+	set := []comparator{{">=", lower}}
+	switch {
+	case parts == 3:
+		set = append(set, comparator{"<=", upper})
+	case parts > 0:
+		set = append(set, comparator{"<", nextVersion(upper, parts)})
+	}
+	return set, nil
+}
+
+// nextVersion returns the lowest version above the range of v's first parts parts,
+// e.g. 1.2 (2 parts) -> 1.3.0-0, excluding that version's prereleases.
+func nextVersion(v semver, parts int) semver {
+	switch parts {
+	case 1:
+		return semver{major: v.major + 1, pre: lowestPrerelease}
+	case 2:
+		return semver{major: v.major, minor: v.minor + 1, pre: lowestPrerelease}
+	default:
+		return semver{major: v.major, minor: v.minor, patch: v.patch + 1, pre: lowestPrerelease}
+	}
+}
+
+// parsePartialVersion parses a version such as "v1.2.3-rc.1", "3.12", "18.x" or "*".
+// It returns the number of numeric parts given; parts after the third are ignored.
+// Build metadata ("+build") is ignored.
+func parsePartialVersion(s string) (semver, int, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, hasPre := strings.Cut(s, "-")
+
+	var v semver
+	parts := 0
+	if core != "" && core != "*" && core != "x" && core != "X" {
+		fields := strings.Split(core, ".")
+		for i, field := range fields {
+			if field == "x" || field == "X" || field == "*" {
+				break
+			}
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 0 {
+				return semver{}, 0, fmt.Errorf("invalid version %q", s)
+			}
+			switch i {
+			case 0:
+				v.major = n
+			case 1:
+				v.minor = n
+			case 2:
+				v.patch = n
+			}
+			if i < 3 {
+				parts = i + 1
+			}
+		}
+	}
+	if hasPre {
+		if parts < 3 || pre == "" {
+			return semver{}, 0, fmt.Errorf("invalid version %q: a prerelease needs a full major.minor.patch version", s)
+		}
+		v.pre = strings.Split(pre, ".")
+	}
+	return v, parts, nil
+}
+
+// compareSemver returns -1, 0 or 1 as a is lower than, equal to or higher than b.
+func compareSemver(a, b semver) int {
+	for _, pair := range [][2]int{{a.major, b.major}, {a.minor, b.minor}, {a.patch, b.patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return comparePrerelease(a.pre, b.pre)
+}
+
+// comparePrerelease orders prerelease identifiers by semver precedence:
+// a release is higher than any prerelease, numeric identifiers compare numerically
+// and are lower than alphanumeric ones, and a longer list wins when all else is equal.
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		an, aErr := strconv.Atoi(a[i])
+		bn, bErr := strconv.Atoi(b[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if cmp := strings.Compare(a[i], b[i]); cmp != 0 {
+				return cmp
+			}
+		}
+	}
+
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// versionRequirement describes all version constraints of a prerequisite for display,
+// e.g. "18.0.0" for a plain minVersion or ">=18.0.0 <=20" when combined with maxVersion.
+func versionRequirement(prereq Prerequisite) string {
+	if prereq.Version == "" && prereq.MaxVersion == "" {
+		return prereq.MinVersion
+	}
+
+	var parts []string
+	if prereq.MinVersion != "" {
+		parts = append(parts, ">="+prereq.MinVersion)
+	}
+	if prereq.Version != "" {
+		parts = append(parts, prereq.Version)
+	}
+	if prereq.MaxVersion != "" {
+		parts = append(parts, "<="+prereq.MaxVersion)
+	}
+	return strings.Join(parts, " ")
+}
+
+// checkVersionRequirement checks an installed version against the prerequisite's minVersion,
+// version range and maxVersion. It returns a description of the first unmet constraint.
+func checkVersionRequirement(prereq Prerequisite, version string) (bool, string, error) {
+	if prereq.MinVersion != "" && !compareVersions(version, prereq.MinVersion) {
+		return false, fmt.Sprintf("Version %s does not meet minimum %s", version, prereq.MinVersion), nil
+	}
+	if prereq.Version != "" {
+		constraint, err := parseVersionConstraint(prereq.Version)
+		if err != nil {
+			return false, "", err
+		}
+		if !constraint.check(version) {
+			return false, fmt.Sprintf("Version %s does not satisfy %s", version, prereq.Version), nil
+		}
+	}
+	if prereq.MaxVersion != "" {
+		constraint, err := parseVersionConstraint("<=" + prereq.MaxVersion)
+		if err != nil {
+			return false, "", fmt.Errorf("invalid maxVersion %q: %w", prereq.MaxVersion, err)
+		}
+		if !constraint.check(version) {
+			return false, fmt.Sprintf("Version %s exceeds maximum %s", version, prereq.MaxVersion), nil
+		}
+	}
+	return true, "", nil
+}
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVersionConstraint_Check(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		// Comparators
+		{">=18 <21", "18.0.0", true},
+		{">=18 <21", "20.11.1", true},
+		{">=18 <21", "21.0.0", false},
+		{">=18 <21", "17.9.0", false},
+		{">=18, <21", "19.0.0", true},
+		{">= 18 < 21", "19.0.0", true},
+		{">1.2", "1.2.9", false},
+		{">1.2", "1.3.0", true},
+		{">1.2.3", "1.2.4", true},
+		{"=1.2.3", "1.2.3", true},
+		{"1.2.3", "1.2.4", false},
+		{"<=20", "20.11.0", true},
+		{"<=20", "21.0.0", false},
+		{"<=1.2.3", "1.2.3", true},
+
+		// X-ranges and partial versions
+		{"18", "18.19.0", true},
+		{"18", "19.0.0", false},
+		{"3.12.x", "3.12.4", true},
+		{"3.12.x", "3.13.0", false},
+		{"*", "0.0.1", true},
+
+		// Tilde ranges
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1.2.3", "1.2.2", false},
+		{"~1", "1.9.0", true},
+		{"~1", "2.0.0", false},
+
+		// Caret ranges
+		{"^3.12", "3.12.0", true},
+		{"^3.12", "3.13.1", true},
+		{"^3.12", "3.11.9", false},
+		{"^3.12", "4.0.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.3", true},
+		{"^0.0.3", "0.0.4", false},
+
+		// Hyphen ranges
+		{"1.2 - 2.3.4", "2.3.4", true},
+		{"1.2 - 2.3.4", "2.3.5", false},
+		{"1.2.3 - 2.3", "2.3.9", true},
+		{"1.2.3 - 2.3", "2.4.0", false},
+
+		// Alternatives
+		{"^8.0 || ^9.0", "9.0.100", true},
+		{"^8.0 || ^9.0", "10.0.100", false},
+
+		// Prereleases sort before their release
+		{">=9.0.100", "9.0.100-rc.1", false},
+		{">=9.0.100-rc.1", "9.0.100-rc.2", true},
+		{">=9.0.100-rc.2", "9.0.100-rc.10", true},
+		{">=9.0.100-rc.1", "9.0.100-preview.7", false},
+		{">=8", "9.0.100-preview.7", true},
+		{"<9", "9.0.0-rc.1", false},
+		{"<9.0.0", "9.0.0-rc.1", true},
+		{"^9", "10.0.0-preview.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			constraint, err := parseVersionConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("parseVersionConstraint(%q) error = %v", tt.constraint, err)
+			}
+			if got := constraint.check(tt.version); got != tt.expected {
+				t.Errorf("%q.check(%q) = %v, want %v", tt.constraint, tt.version, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseVersionConstraint_Invalid(t *testing.T) {
+	for _, expr := range []string{"", ">=abc", "1.2-beta", "^1 ||", ">=18 <"} {
+		if _, err := parseVersionConstraint(expr); err == nil {
+			t.Errorf("parseVersionConstraint(%q) should fail", expr)
+		}
+	}
+}
+
+func TestCheckVersionRequirement(t *testing.T) {
+	tests := []struct {
+		name       string
+		prereq     Prerequisite
+		version    string
+		expected   bool
+		wantReason string
+		wantErr    bool
+	}{
+		{name: "min only", prereq: Prerequisite{MinVersion: "18.0.0"}, version: "20.1.0", expected: true},
+		{name: "below min", prereq: Prerequisite{MinVersion: "18.0.0"}, version: "16.0.0", wantReason: "does not meet minimum 18.0.0"},
+		{name: "max includes its range", prereq: Prerequisite{MinVersion: "18", MaxVersion: "20"}, version: "20.11.0", expected: true},
+		{name: "above max", prereq: Prerequisite{MinVersion: "18", MaxVersion: "20"}, version: "22.0.0", wantReason: "exceeds maximum 20"},
+		{name: "range", prereq: Prerequisite{Version: "^3.12"}, version: "3.13.0", expected: true},
+		{name: "outside range", prereq: Prerequisite{Version: ">=18 <21"}, version: "22.0.0", wantReason: "does not satisfy >=18 <21"},
+		{name: "prerelease below min", prereq: Prerequisite{MinVersion: "9.0.100"}, version: "9.0.100-rc.1", wantReason: "does not meet minimum"},
+		{name: "invalid range", prereq: Prerequisite{Version: ">=1.y"}, version: "1.0.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, reason, err := checkVersionRequirement(tt.prereq, tt.version)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("checkVersionRequirement() should fail for an invalid constraint")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkVersionRequirement() error = %v", err)
+			}
+			if ok != tt.expected {
+				t.Errorf("checkVersionRequirement() = %v, want %v", ok, tt.expected)
+			}
+			if !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("checkVersionRequirement() reason = %q, want it to contain %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestVersionRequirement(t *testing.T) {
+	tests := []struct {
+		prereq   Prerequisite
+		expected string
+	}{
+		{Prerequisite{MinVersion: "18.0.0"}, "18.0.0"},
+		{Prerequisite{MinVersion: "18.0.0", MaxVersion: "20"}, ">=18.0.0 <=20"},
+		{Prerequisite{Version: "^3.12"}, "^3.12"},
+	}
+
+	for _, tt := range tests {
+		if got := versionRequirement(tt.prereq); got != tt.expected {
+			t.Errorf("versionRequirement(%+v) = %q, want %q", tt.prereq, got, tt.expected)
+		}
+	}
+}
+
+func TestExtractFirstVersion_Prerelease(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"10.0.100-preview.7.25380.108", "10.0.100-preview.7.25380.108"},
+		{"v24.0.0-nightly20250101", "24.0.0-nightly20250101"},
+		{"Docker version 24.0.7-0ubuntu2~22.04.1, build 24.0.7", "24.0.7"},
+	}
+
+	for _, tt := range tests {
+		if got := extractFirstVersion(tt.input); got != tt.expected {
+			t.Errorf("extractFirstVersion(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -1,9 +1,17 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/service"
 	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/security"
 
 	"github.com/spf13/cobra"
 )
@@ -17,19 +25,25 @@ var (
 // NewRestartCommand creates the restart command.
 func NewRestartCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "restart",
+		Use:   "restart [service...]",
 		Short: "Restart services",
 		Long: `Restart one or more services.
 
 This command stops and then starts services. It works on both running and
-stopped services. Use --service to restart a specific service, or --all
-to restart all services.
+stopped services. Name the services to restart, or use --all to restart all
+services. Other services keep running.
 
 Services are stopped gracefully before being restarted. If a service
 doesn't respond to graceful shutdown, it will be forcefully terminated.
 
+When an 'azd app run' session is active, the session restarts the service,
+so it keeps its port assignment and its logs in the dashboard and 'azd app logs'.
+
 Examples:
   # Restart a specific service
+  azd app restart api
+
+  # Restart a specific service (flag form)
   azd app restart --service api
 
   # Restart multiple services
@@ -39,12 +53,14 @@ Examples:
   azd app restart --all
 
   # JSON output
-  azd app restart --service api --output json`,
-		SilenceUsage: true,
-		RunE:         runRestart,
+  azd app restart api --output json`,
+		SilenceUsage:      true,
+		ValidArgsFunction: completeServiceNames,
+		RunE:              runRestart,
 	}
 
-	cmd.Flags().StringVarP(&restartService, "service", "s", "", "Service name(s) to restart (comma-separated)")
+	cmd.Flags().StringVarP(&restartService, "service", "s", "", "Service name(s) to restart (comma-separated, same as arguments)")
+	registerServiceFlagCompletion(cmd)
 	cmd.Flags().BoolVar(&restartAll, "all", false, "Restart all services")
 	cmd.Flags().BoolVarP(&restartYes, "yes", "y", false, "Skip confirmation prompt for --all")
 
@@ -54,9 +70,12 @@ Examples:
 func runRestart(cmd *cobra.Command, args []string) error {
 	cliout.CommandHeader("restart", "Restart services")
 
+	// Service names can be given as arguments and with --service
+	serviceList := strings.Join(append(append([]string{}, args...), restartService), ",")
+
 	// Validate flags
-	if restartService == "" && !restartAll {
-		return fmt.Errorf("specify --service <name> or --all to restart services")
+	if strings.Trim(serviceList, ",") == "" && !restartAll {
+		return fmt.Errorf("specify a service name or --all to restart services")
 	}
 
 	// Create controller
@@ -69,10 +88,21 @@ func runRestart(cmd *cobra.Command, args []string) error {
 	ctx, _, cleanup := setupContextWithSignalHandling()
 	defer cleanup()
 
+	// Services started by 'azd app run' are restarted by that session
+	restartOp := ctrl.RestartService
+	listServices := ctrl.GetAllServices
+	if client := runningDashboardClient(ctx, ctrl.projectDir); client != nil {
+		restartOp = ctrl.dashboardOperation(service.OpRestart, client.RestartService)
+		listServices = func() []string { return dashboardServiceNames(ctx, client) }
+	}
+	bulkRestart := func(ctx context.Context, names []string) *BulkServiceControlResult {
+		return ctrl.bulkOperation(ctx, names, service.OpRestart, restartOp)
+	}
+
 	// Determine which services to restart
 	var servicesToRestart []string
 	if restartAll {
-		servicesToRestart = ctrl.GetAllServices()
+		servicesToRestart = listServices()
 		if len(servicesToRestart) == 0 {
 			printNoServicesRegistered()
 			if cliout.IsJSON() {
@@ -85,11 +115,54 @@ func runRestart(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 	} else {
-		servicesToRestart, err = parseServiceList(restartService)
+		servicesToRestart, err = parseServiceList(serviceList)
 		if err != nil {
 			return err
 		}
 	}
 
-	return executeServiceOperation(ctx, servicesToRestart, ctrl.RestartService, ctrl.BulkRestart, "restart")
+	return executeServiceOperation(ctx, servicesToRestart, restartOp, bulkRestart, "restart")
+}
+
+// runningDashboardClient returns a client for the dashboard of the project's 'azd app run'
+// session, or nil if no session is running.
+func runningDashboardClient(ctx context.Context, projectDir string) *dashboard.Client {
+	client, err := dashboard.NewClient(ctx, projectDir)
+	if err != nil {
+		return nil
+	}
+	if err := client.Ping(ctx); err != nil {
+		slog.Debug("dashboard not responding", "error", err)
+		return nil
+	}
+	return client
+}
+
+// dashboardOperation returns an operation that starts or restarts a service through the
+// dashboard with request, so the 'azd app run' session that owns the service runs it.
+func (c *ServiceController) dashboardOperation(op service.OperationType, request func(ctx context.Context, serviceName string) error) func(ctx context.Context, serviceName string) *ServiceControlResult {
+	return func(ctx context.Context, serviceName string) *ServiceControlResult {
+		if err := security.ValidateServiceName(serviceName, false); err != nil {
+			return newErrorResult(serviceName, err.Error())
+		}
+
+		start := time.Now()
+		err := request(ctx, serviceName)
+		opResult := &service.OperationResult{ServiceName: serviceName, Operation: op, Success: err == nil, Error: err, Duration: time.Since(start)}
+		return c.buildResult(serviceName, opResult, string(op), constants.StatusRunning)
+	}
+}
+
+// dashboardServiceNames returns the names of the services known to the dashboard.
+func dashboardServiceNames(ctx context.Context, client *dashboard.Client) []string {
+	services, err := client.GetServices(ctx)
+	if err != nil {
+		slog.Debug("failed to get services from dashboard", "error", err)
+		return nil
+	}
+	names := make([]string, 0, len(services))
+	for _, svc := range services {
+		names = append(names, svc.Name)
+	}
+	return names
 }
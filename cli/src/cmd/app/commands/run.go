@@ -2,29 +2,41 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/jongio/azd-app/cli/src/internal/azdenv"
+	"github.com/jongio/azd-app/cli/src/internal/constants"
 	"github.com/jongio/azd-app/cli/src/internal/dashboard"
 	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/devenv"
+	"github.com/jongio/azd-app/cli/src/internal/eta"
 	"github.com/jongio/azd-app/cli/src/internal/executor"
 	"github.com/jongio/azd-app/cli/src/internal/notifications"
+	"github.com/jongio/azd-app/cli/src/internal/output"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
 	"github.com/jongio/azd-app/cli/src/internal/service"
 	"github.com/jongio/azd-app/cli/src/internal/serviceinfo"
+	"github.com/jongio/azd-app/cli/src/internal/telemetry"
+	"github.com/jongio/azd-app/cli/src/internal/tracing"
 	"github.com/jongio/azd-core/browser"
 	"github.com/jongio/azd-core/cliout"
 	"github.com/jongio/azd-core/registry"
 	"github.com/jongio/azd-core/yamlutil"
 
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -35,12 +47,20 @@ const (
 var (
 	runServiceFilter     string
 	runEnvFile           string
-	runVerbose           bool
+	runAzdEnv            string
 	runDryRun            bool
 	runRuntime           string
 	runWeb               bool
 	runRestartContainers bool
 	runForce             bool
+	runWatch             bool
+	runForceKill         bool
+	runLogFiles          bool
+	runProfile           string
+	runProxy             bool
+	runDetach            bool
+	runPortVisibility    string
+	runWithProvision     string
 )
 
 // NewRunCommand creates the run command.
@@ -55,25 +75,48 @@ func NewRunCommand() *cobra.Command {
 		},
 	}
 
+	supportEventStream(cmd)
+
 	// Add flags for service orchestration
-	cmd.Flags().StringVarP(&runServiceFilter, "service", "s", "", "Run specific service(s) only (comma-separated)")
+	cmd.Flags().StringVarP(&runServiceFilter, "service", "s", "", "Run specific service(s) and their dependencies only (comma-separated names or glob patterns, e.g. 'svc-*')")
+	registerServiceFlagCompletion(cmd)
 	cmd.Flags().StringVar(&runEnvFile, "env-file", "", "Load environment variables from .env file")
-	cmd.Flags().BoolVarP(&runVerbose, "verbose", "v", false, "Enable verbose logging")
+	cmd.Flags().StringVar(&runAzdEnv, "azd-env", "", "Give services the values of this azd environment (.azure/<name>/.env) instead of the current one")
 	cmd.Flags().BoolVar(&runDryRun, "dry-run", false, "Show what would be run without starting services")
 	cmd.Flags().StringVar(&runRuntime, "runtime", runtimeModeAzd, "Runtime mode: 'azd' (azd dashboard) or 'aspire' (native Aspire with dotnet run)")
 	cmd.Flags().BoolVarP(&runWeb, "web", "w", false, "Open dashboard in browser")
 	cmd.Flags().BoolVar(&runRestartContainers, "restart-containers", false, "Restart containers even if they are already running")
 	cmd.Flags().BoolVar(&runForce, "force", false, "Force clean dependency reinstall (passes --force to deps)")
+	cmd.Flags().BoolVar(&runWatch, "watch", false, "Restart a service when its source files change")
+	cmd.Flags().BoolVar(&runForceKill, "force-kill", false, "Allow killing any process on a conflicting port, not just azd-app services and known dev servers")
+	cmd.Flags().BoolVar(&runLogFiles, "log-files", true, "Persist service logs to .azure/logs (overrides logs.persist.enabled in azure.yaml)")
+	cmd.Flags().StringVar(&runProfile, "profile", "", "Merge a profile from azure.yaml (e.g. test, staging) over the base configuration")
+	cmd.Flags().BoolVar(&runProxy, "proxy", false, "Serve all services from one port, routed by path as configured in the proxy section of azure.yaml")
+	cmd.Flags().BoolVarP(&runDetach, "detach", "d", false, "Run in the background; manage the session with status, logs and stop from any terminal")
+	cmd.Flags().StringVar(&runPortVisibility, "port-visibility", "", "In GitHub Codespaces, set the visibility of the forwarded service ports: 'private', 'org' or 'public'")
+	cmd.Flags().StringVar(&runWithProvision, "with-provision", "", "Run azd provision before starting services: 'auto' when the azd environment's outputs are missing or older than the infrastructure (the default without a value), or 'always'")
+	cmd.Flags().Lookup("with-provision").NoOptDefVal = provisionModeAuto
 
 	return cmd
 }
 
 // runWithServices runs services from azure.yaml.
-func runWithServices(ctx context.Context, _ *cobra.Command, _ []string) error {
+func runWithServices(ctx context.Context, cmd *cobra.Command, _ []string) error {
 	cliout.CommandHeader("run", "Run the development environment")
 	if err := validateRuntimeMode(runRuntime); err != nil {
 		return err
 	}
+	if err := validatePortVisibility(runPortVisibility); err != nil {
+		return err
+	}
+	if err := validateWithProvision(runWithProvision); err != nil {
+		return err
+	}
+
+	// The background process runs this command again, with the environment marking it detached
+	if runDetach && !isDetachedSession() {
+		return startDetachedSession(ctx)
+	}
 
 	// Set deps options if --force specified
 	if runForce {
@@ -82,6 +125,19 @@ func runWithServices(ctx context.Context, _ *cobra.Command, _ []string) error {
 		setDepsOptions(opts)
 	}
 
+	portmanager.SetForceKill(runForceKill)
+	if cmd != nil && cmd.Flags().Changed("log-files") {
+		service.SetLogPersistence(runLogFiles)
+	}
+
+	// The profile applies to reqs and to every azure.yaml parse below, including service restarts
+	service.SetActiveProfile(runProfile)
+
+	// Only gate on the requirements of the services being run and their dependencies
+	if runServiceFilter != "" {
+		SetReqsServices(service.ParseServiceFilter(runServiceFilter))
+	}
+
 	// Execute dependencies first (reqs -> deps -> run)
 	// The orchestrator automatically sets orchestrated mode for dependencies
 	if err := cmdOrchestrator.Run("run"); err != nil {
@@ -127,6 +183,11 @@ func findAzureYaml() (string, error) {
 func runServicesFromAzureYaml(ctx context.Context, azureYamlPath string, runtimeMode string) error {
 	azureYamlDir := filepath.Dir(azureYamlPath)
 
+	// Provision first, so the services get the fresh outputs of the azd environment
+	if err := provisionBeforeRun(ctx, azureYamlPath); err != nil {
+		return err
+	}
+
 	// Aspire mode: run AppHost directly
 	if runtimeMode == runtimeModeAspire {
 		return runAspireMode(ctx, azureYamlDir)
@@ -143,11 +204,27 @@ func runAzdMode(ctx context.Context, azureYamlPath, azureYamlDir string) error {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
+	// Values of the azd environment (e.g. outputs of azd provision) are given to services
+	azdEnv, err := loadAzdEnvironment(azureYamlDir)
+	if err != nil {
+		return err
+	}
+
+	// Variables from --env-file and the azd environment can be referenced in azure.yaml as ${VAR}
+	envVars, err := loadEnvironmentVariables(azdEnv)
+	if err != nil {
+		return err
+	}
+	service.SetInterpolationEnv(envVars)
+
 	// Parse azure.yaml
 	azureYaml, err := service.ParseAzureYaml(azureYamlPath)
 	if err != nil {
 		return fmt.Errorf("failed to parse azure.yaml: %w", err)
 	}
+	if profile := service.ActiveProfile(); profile != "" {
+		cliout.Info("Using profile: %s", profile)
+	}
 
 	// REMOVED: initializeAzureLogBuffer call - deprecated v1
 	// Azure logs are now fetched on-demand via /api/azure/logs endpoint
@@ -163,23 +240,90 @@ func runAzdMode(ctx context.Context, azureYamlPath, azureYamlDir string) error {
 	}
 
 	// Filter and detect services
-	services := filterServices(azureYaml)
-	if len(services) == 0 {
-		return fmt.Errorf("no services match filter: %s", runServiceFilter)
+	services, err := filterServices(azureYaml)
+	if err != nil {
+		return err
 	}
 
+	// Hold each assigned port until its service starts, so services starting concurrently
+	// (or other processes) can't take it in the meantime
+	portmanager.SetReservationMode(true)
+	defer portmanager.ReleaseHeldPorts()
+
+	_, span := tracing.Start(ctx, "detect", attribute.Int("azd.app.service_count", len(services)))
 	runtimes, err := detectServiceRuntimes(services, azureYamlDir, runtimeModeAzd)
+	tracing.End(span, err)
 	if err != nil {
 		return err
 	}
 
+	// Give each service the URL and port of the others, and resolve ${services.<name>.url} references
+	endpoints := service.RuntimeEndpoints(runtimes)
+	for _, rt := range runtimes {
+		if err := service.InjectServiceURLs(rt, endpoints); err != nil {
+			return err
+		}
+	}
+
 	// Dry-run mode: show what would be executed
 	if runDryRun {
 		return showDryRun(runtimes)
 	}
 
+	// Processes left running by a crashed session would hold the ports the services need
+	stopOrphanedProcesses(cwd)
+
+	// Give HTTPS services (and the dashboard, if enabled) the development certificate
+	_, span = tracing.Start(ctx, "https")
+	err = configureHTTPS(azureYaml, cwd, runtimes)
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+
+	// Serve Prometheus metrics on the dashboard; enabled before orchestration so start latency is recorded
+	if (azureYaml.Dashboard != nil && azureYaml.Dashboard.Prometheus) || os.Getenv(dashboard.EnvPrometheus) == "true" {
+		dashboard.GetServer(cwd).EnablePrometheus()
+	}
+
 	// Execute and monitor services
-	return executeAndMonitorServices(ctx, runtimes, cwd, azureYaml, azureYamlDir)
+	return executeAndMonitorServices(ctx, runtimes, cwd, azureYaml, azureYamlDir, azdEnv)
+}
+
+// confirmOrphanCleanup asks whether to stop the processes of a previous session.
+// This is a variable to allow test overrides.
+var confirmOrphanCleanup = cliout.Confirm
+
+// stopOrphanedProcesses offers to stop the service processes a previous azd app run session
+// left running, e.g. because it crashed or was killed. Processes are identified by the PIDs
+// and start times recorded when they were started, so unrelated processes that reused a PID
+// are never stopped.
+func stopOrphanedProcesses(projectDir string) {
+	orphans, err := service.FindOrphanedProcesses(projectDir)
+	if err != nil {
+		slog.Debug("failed to check for orphaned service processes", "error", err)
+	}
+	if len(orphans) == 0 {
+		return
+	}
+
+	cliout.Warning("Found %d service process(es) left running by a previous session:", len(orphans))
+	for _, orphan := range orphans {
+		cliout.ItemWarning("%s (PID %d, started %s ago): %s", orphan.Service, orphan.PID,
+			formatInfoDuration(time.Since(orphan.StartedAt)), orphan.Command)
+	}
+	if !confirmOrphanCleanup("Stop them before starting?") {
+		cliout.Info("Leaving them running; services whose ports they hold will get a port conflict")
+		return
+	}
+
+	for _, orphan := range orphans {
+		if err := service.StopOrphanedProcess(projectDir, orphan); err != nil {
+			cliout.ItemError("%v", err)
+			continue
+		}
+		cliout.ItemSuccess("Stopped %s (PID %d)", orphan.Service, orphan.PID)
+	}
 }
 
 // showNoServicesMessage displays a message when no services are defined.
@@ -190,13 +334,21 @@ func showNoServicesMessage() error {
 	return nil
 }
 
-// filterServices applies service filtering based on --service flag.
-func filterServices(azureYaml *service.AzureYaml) map[string]service.Service {
-	if runServiceFilter == "" {
-		return azureYaml.Services
+// filterServices applies service filtering based on the --service flag: the named services and
+// those matching its glob patterns, plus the services they depend on.
+func filterServices(azureYaml *service.AzureYaml) (map[string]service.Service, error) {
+	patterns := service.ParseServiceFilter(runServiceFilter)
+	if len(patterns) == 0 {
+		return azureYaml.Services, nil
+	}
+	services, dependencies, err := service.SelectServices(azureYaml, patterns)
+	if err != nil {
+		return nil, err
 	}
-	filterList := strings.Split(runServiceFilter, ",")
-	return service.FilterServices(azureYaml, filterList)
+	if len(dependencies) > 0 {
+		cliout.Info("Also starting dependencies: %s", strings.Join(dependencies, ", "))
+	}
+	return services, nil
 }
 
 // detectServiceRuntimes detects runtime information for all services.
@@ -220,6 +372,7 @@ func detectServiceRuntimes(services map[string]service.Service, azureYamlDir, ru
 			return nil, fmt.Errorf("failed to detect runtime for service %s: %w", name, err)
 		}
 		usedPorts[runtime.Port] = true
+		telemetry.AddFrameworks(runtime.Framework)
 
 		// If we auto-assigned a port and user wants to save it, update azure.yaml
 		if runtime.ShouldUpdateAzureYaml {
@@ -238,13 +391,31 @@ func detectServiceRuntimes(services map[string]service.Service, azureYamlDir, ru
 }
 
 // executeAndMonitorServices starts services and monitors them until interrupted.
-func executeAndMonitorServices(ctx context.Context, runtimes []*service.ServiceRuntime, cwd string, azureYaml *service.AzureYaml, azureYamlDir string) error {
+func executeAndMonitorServices(ctx context.Context, runtimes []*service.ServiceRuntime, cwd string, azureYaml *service.AzureYaml, azureYamlDir string, azdEnv *azdenv.Environment) error {
 	// Create logger
-	logger := service.NewServiceLogger(runVerbose)
+	logger := service.NewServiceLogger(output.IsVerbose())
 	logger.LogStartup(len(runtimes))
 
 	// Load environment variables
-	envVars, err := loadEnvironmentVariables()
+	envVars, err := loadEnvironmentVariables(azdEnv)
+	if err != nil {
+		return err
+	}
+
+	// Show how long each service usually takes, based on previous runs
+	history := eta.Load(azureYamlDir)
+	showStartupEstimates(logger, history, runtimes)
+
+	// Run the service lifecycle hooks from azure.yaml as services start, stop and change health
+	unregisterHooks := registerServiceEventHooks(azureYaml, azureYamlDir, runtimes)
+	defer unregisterHooks()
+
+	// With --output ndjson, report the services' progress and logs as events
+	unregisterEventStream := registerEventStream(cwd, runtimes)
+	defer unregisterEventStream()
+
+	// Validate the proxy routes before starting anything
+	routingProxy, err := newRunProxy(azureYaml, cwd)
 	if err != nil {
 		return err
 	}
@@ -255,6 +426,9 @@ func executeAndMonitorServices(ctx context.Context, runtimes []*service.ServiceR
 		return fmt.Errorf("service orchestration failed: %w", err)
 	}
 
+	// Measure time-to-healthy in the background to improve future estimates
+	go recordStartupDurations(history, result.Processes)
+
 	// Validate that all services are ready
 	if err := service.ValidateOrchestration(result); err != nil {
 		service.StopAllServices(result.Processes)
@@ -262,11 +436,23 @@ func executeAndMonitorServices(ctx context.Context, runtimes []*service.ServiceR
 	}
 
 	// Display service URLs (local + custom + Azure endpoints/domains)
+	// In a codespace or over SSH, also show how to reach them from the browser
 	serviceSummaries := buildServiceSummaries(cwd, azureYaml, result.Processes)
+	devEnv := detectDevEnvironment()
+	addForwardedURLs(devEnv, serviceSummaries)
 	logger.LogSummary(serviceSummaries)
+	showRemoteForwarding(devEnv, serviceSummaries, runPortVisibility)
 
 	logger.LogReady()
 
+	if routingProxy != nil {
+		if err := startRunProxy(ctx, routingProxy, azureYaml.Proxy, cwd); err != nil {
+			service.StopAllServices(result.Processes)
+			return err
+		}
+		defer stopRunProxy(routingProxy)
+	}
+
 	// Execute postrun hook after all services are ready
 	if err := executePostrunHook(azureYaml, azureYamlDir); err != nil {
 		cliout.Warning("Postrun hook failed but services are running: %v", err)
@@ -284,20 +470,88 @@ func executeAndMonitorServices(ctx context.Context, runtimes []*service.ServiceR
 		}
 	}
 
+	// Services are restarted individually by `azd app restart` and the dashboard; in watch mode
+	// also when their sources change. Services with a healthcheck in azure.yaml are restarted
+	// when they become unhealthy, and services with a restart policy when they exit
+	restarter := newServiceRestarter(result, envVars, logger, azureYamlDir, runWatch)
+
+	// Pick up the values azd provision writes to the azd environment while services run
+	if azdEnv != nil {
+		watchCtx, stopWatch := context.WithCancel(ctx)
+		defer stopWatch()
+		go azdEnv.Watch(watchCtx, azdenv.DefaultPollInterval, func(updated *azdenv.Environment, changed []string) {
+			applyAzdEnvironmentChange(cwd, updated, changed, restarter)
+		})
+	}
+
 	// Start dashboard and wait for shutdown
-	return monitorServicesUntilShutdown(result, cwd)
+	return monitorServicesUntilShutdown(result, cwd, restarter)
+}
+
+// showStartupEstimates prints how long each service usually takes to become ready.
+// Nothing is printed for services without history or with very short startups.
+func showStartupEstimates(logger *service.ServiceLogger, history *eta.History, runtimes []*service.ServiceRuntime) {
+	for _, rt := range runtimes {
+		if hint := history.Hint(eta.KindService, rt.Name); hint != "" {
+			logger.LogService(rt.Name, hint+" to become ready")
+		}
+	}
+}
+
+// recordStartupDurations waits for each started service to pass its health check and
+// records the elapsed time since the process started in the project's timing history.
+// Services without a meaningful readiness signal (health check "none", build/task modes) are skipped.
+func recordStartupDurations(history *eta.History, processes map[string]*service.ServiceProcess) {
+	var wg sync.WaitGroup
+	for name, proc := range processes {
+		if proc == nil || proc.StartTime.IsZero() || proc.Runtime.HealthCheck.Type == "none" {
+			continue
+		}
+		if proc.Runtime.Mode == service.ServiceModeBuild || proc.Runtime.Mode == service.ServiceModeTask {
+			continue
+		}
+
+		wg.Add(1)
+		// Probe a copy so the health check never mutates the live process state
+		go func(serviceName string, probe service.ServiceProcess) {
+			defer wg.Done()
+			if probe.Runtime.HealthCheck.Timeout <= 0 {
+				probe.Runtime.HealthCheck.Timeout = service.DefaultHealthWaitTimeout
+			}
+			if err := service.PerformHealthCheck(&probe); err != nil {
+				slog.Debug("not recording startup duration", "service", serviceName, "error", err)
+				return
+			}
+			history.Record(eta.KindService, serviceName, time.Since(probe.StartTime))
+		}(name, *proc)
+	}
+	wg.Wait()
+
+	if err := history.Save(); err != nil {
+		slog.Debug("failed to save startup timing history", "error", err)
+	}
 }
 
-// loadEnvironmentVariables loads environment variables from --env-file if specified.
-func loadEnvironmentVariables() (map[string]string, error) {
+// loadEnvironmentVariables loads the values of the azd environment, if any, overridden by
+// the environment variables from --env-file if specified.
+func loadEnvironmentVariables(azdEnv *azdenv.Environment) (map[string]string, error) {
+	envVars := make(map[string]string)
+	if azdEnv != nil {
+		for key, value := range azdEnv.Values {
+			envVars[key] = value
+		}
+	}
 	if runEnvFile == "" {
-		return make(map[string]string), nil
+		return envVars, nil
 	}
 
-	envVars, err := service.LoadDotEnv(runEnvFile)
+	fileVars, err := service.LoadDotEnv(runEnvFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load env file: %w", err)
 	}
+	for key, value := range fileVars {
+		envVars[key] = value
+	}
 	return envVars, nil
 }
 
@@ -451,13 +705,31 @@ func extractCustomConfig(azureYaml *service.AzureYaml) map[string]customEndpoint
 //
 // This uses sync.WaitGroup (not errgroup) because we want all goroutines to complete
 // independently rather than failing fast on first error.
-func monitorServicesUntilShutdown(result *service.OrchestrationResult, cwd string) error {
-	// Create context that cancels on SIGINT/SIGTERM only
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+//
+// When restarter is non-nil, it owns the service monitors and restarts services, e.g. when
+// their sources change or the dashboard asks for it; shutdown then stops the latest processes.
+func monitorServicesUntilShutdown(result *service.OrchestrationResult, cwd string, restarter *serviceRestarter) error {
+	// Create context that cancels on SIGINT/SIGTERM/SIGHUP only
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case sig := <-signals:
+			// Services run in their own process groups, so the signals the terminal sends
+			// azd don't reach them; stop them with the signal azd received instead
+			service.SetStopSignal(sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
 
 	var wg sync.WaitGroup
 	dashboardServer := dashboard.GetServer(cwd)
+	// `azd app stop --all` from another terminal shuts down like Ctrl+C
+	dashboardServer.SetShutdownHandler(cancel)
 
 	// Start notification manager for OS notifications on service issues
 	notifMgr, err := notifications.NewNotificationManager(
@@ -471,21 +743,85 @@ func monitorServicesUntilShutdown(result *service.OrchestrationResult, cwd strin
 		// Notifications enabled silently - no need to announce
 	}
 
+	// Start service process monitors
+	processes := result.Processes
+	currentProcesses := func() map[string]*service.ServiceProcess { return processes }
+	if restarter != nil {
+		restarter.start(ctx, &wg)
+		currentProcesses = restarter.snapshot
+		// `azd app start/restart` and the dashboard restart services in this session, which
+		// keeps tracking their processes
+		dashboardServer.SetRestartHandler(func(name string) error {
+			return restarter.restartRequested(ctx, name)
+		})
+		defer dashboardServer.SetRestartHandler(nil)
+	} else {
+		startServiceMonitors(ctx, &wg, processes, cwd)
+	}
+
+	// The session registers itself for 'azd app sessions' once the dashboard is up; a detached
+	// session also records itself in the project for status, logs and stop in other terminals
+	onDashboardStarted := func(dashboardURL string) {
+		registerRunSession(cwd, dashboardURL, currentProcesses())
+		if isDetachedSession() {
+			recordDetachedSession(cwd, dashboardURL, currentProcesses())
+		}
+	}
+	defer service.UnregisterSession(cwd)
+	if isDetachedSession() {
+		defer service.RemoveSession(cwd)
+	}
+
 	// Start dashboard monitoring (passes notifMgr to set URL after dashboard starts)
-	startDashboardMonitor(ctx, &wg, dashboardServer, notifMgr)
+	startDashboardMonitor(ctx, &wg, dashboardServer, notifMgr, onDashboardStarted)
 
-	// Start service process monitors
-	startServiceMonitors(ctx, &wg, result.Processes, cwd)
+	if restarter != nil && service.HasLivenessChecks(processes) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			service.WatchServiceLiveness(ctx, currentProcesses, func(name string, err error) {
+				handleLivenessFailure(ctx, restarter, name, err)
+			})
+		}()
+	}
+
+	// Health is only watched when something (e.g. a servicehealthchanged hook) listens for changes
+	if service.HasServiceEventHandlers(service.EventServiceHealthChanged) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			service.WatchServiceHealth(ctx, service.DefaultHealthWatchInterval, currentProcesses)
+		}()
+	}
 
 	// Wait for signal (context cancellation) or all services to complete
 	wg.Wait()
 
-	// Perform cleanup shutdown
-	return performGracefulShutdown(dashboardServer, result.Processes)
+	if restarter != nil {
+		// Let the monitors return before their processes are stopped, so stopping isn't reported as a crash
+		restarter.wait()
+		processes = restarter.snapshot()
+	}
+
+	// Perform cleanup shutdown; the stopped services are no longer left for the next run to clean up
+	defer service.ForgetProcesses(cwd)
+	return performGracefulShutdown(dashboardServer, processes)
+}
+
+// handleLivenessFailure reports a service that failed its liveness checks and restarts it
+// unless its healthcheck sets restartOnFailure: false.
+func handleLivenessFailure(ctx context.Context, restarter *serviceRestarter, name string, err error) {
+	proc := restarter.snapshot()[name]
+	if proc == nil || !proc.Runtime.HealthCheck.RestartOnFailure {
+		cliout.Warning("%s is unhealthy: %v", name, err)
+		return
+	}
+	_ = restarter.restart(ctx, name, fmt.Sprintf("unhealthy (%v)", err))
 }
 
 // startDashboardMonitor starts the dashboard server in a separate goroutine with panic recovery.
-func startDashboardMonitor(ctx context.Context, wg *sync.WaitGroup, dashboardServer *dashboard.Server, notifMgr *notifications.NotificationManager) {
+// onStarted, if not nil, is called once the dashboard has started, with an empty URL if it is unavailable.
+func startDashboardMonitor(ctx context.Context, wg *sync.WaitGroup, dashboardServer *dashboard.Server, notifMgr *notifications.NotificationManager, onStarted func(dashboardURL string)) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -498,23 +834,35 @@ func startDashboardMonitor(ctx context.Context, wg *sync.WaitGroup, dashboardSer
 		dashboardURL, err := dashboardServer.Start()
 		if err != nil {
 			cliout.Warning("Dashboard unavailable: %v", err)
+			if onStarted != nil {
+				onStarted("")
+			}
 			<-ctx.Done()
 			return
 		}
+		if onStarted != nil {
+			onStarted(dashboardURL)
+		}
 
 		// Set dashboard URL for clickable notifications
 		if notifMgr != nil {
 			notifMgr.SetDashboardURL(dashboardURL)
 		}
 
-		cliout.Plain("  Dashboard  %s", dashboardURL)
+		devEnv := detectDevEnvironment()
+		output.Result("  Dashboard  %s", dashboardDisplayURL(devEnv, dashboardURL))
+		if port := devenv.LocalPort(dashboardURL); port > 0 && devEnv.SSH {
+			cliout.Hint("Forward the dashboard to your machine: " + devEnv.SSHForwardCommand([]int{port}))
+		}
 		cliout.Newline()
 
 		// Launch browser after dashboard is ready (if enabled)
 		browserLaunched := launchDashboardBrowser(dashboardURL)
 
 		// Show compact hints on a single line
-		if browserLaunched {
+		if isDetachedSession() {
+			cliout.Hint("Stop with: azd app stop --all")
+		} else if browserLaunched {
 			cliout.Hint("Press Ctrl+C to stop")
 		} else {
 			cliout.Hint("Press Ctrl+C to stop", "--web to open browser")
@@ -532,7 +880,7 @@ func startServiceMonitors(ctx context.Context, wg *sync.WaitGroup, processes map
 			continue
 		}
 		wg.Add(1)
-		go monitorServiceProcess(ctx, wg, name, process, projectDir)
+		go monitorServiceProcess(ctx, wg, name, process, projectDir, nil)
 	}
 }
 
@@ -552,12 +900,13 @@ func performGracefulShutdown(dashboardServer *dashboard.Server, processes map[st
 	}
 
 	// Stop all services with graceful timeout
-	if stopErr := shutdownAllServices(shutdownCtx, processes); stopErr != nil {
+	shutdownStart := time.Now()
+	results, stopErr := shutdownAllServices(shutdownCtx, processes)
+	if stopErr != nil && !cliout.IsJSON() {
 		cliout.Warning("Some services failed to stop cleanly: %v", stopErr)
 	}
 
-	cliout.Success("All services stopped")
-	cliout.Newline()
+	printShutdownSummary(results, time.Since(shutdownStart))
 
 	// Clean up port assignments on clean shutdown
 	// Note: Port assignments are kept in the file for persistence across runs,
@@ -570,10 +919,14 @@ func performGracefulShutdown(dashboardServer *dashboard.Server, processes map[st
 	return nil
 }
 
+// exitHandler is called when a service process exits on its own (not stopped by request)
+// and reports whether the service will be relaunched, e.g. by its restart policy.
+type exitHandler func(exitCode int) bool
+
 // monitorServiceProcess monitors a single service process for exit or cancellation.
 // This function runs in its own goroutine with panic recovery to ensure one service
-// crash doesn't affect others (process isolation).
-func monitorServiceProcess(ctx context.Context, wg *sync.WaitGroup, serviceName string, proc *service.ServiceProcess, projectDir string) {
+// crash doesn't affect others (process isolation). onExit may be nil.
+func monitorServiceProcess(ctx context.Context, wg *sync.WaitGroup, serviceName string, proc *service.ServiceProcess, projectDir string, onExit exitHandler) {
 	defer wg.Done()
 	defer func() {
 		if r := recover(); r != nil {
@@ -589,7 +942,7 @@ func monitorServiceProcess(ctx context.Context, wg *sync.WaitGroup, serviceName
 	}
 	waitDone := make(chan exitResult, 1)
 	go func() {
-		state, err := proc.Process.Wait()
+		state, err := proc.Wait()
 		if err != nil {
 			waitDone <- exitResult{exitCode: -1, err: fmt.Errorf("service %s exited with error: %w", serviceName, err)}
 			return
@@ -613,6 +966,11 @@ func monitorServiceProcess(ctx context.Context, wg *sync.WaitGroup, serviceName
 			cliout.Warning("Failed to update exit info for %s: %v", serviceName, regErr)
 		}
 
+		info := service.ServiceEventInfo{Event: service.EventServiceStopped, Service: serviceName, Port: proc.Port, Error: result.err}
+		if proc.Process != nil {
+			info.PID = proc.Process.Pid
+		}
+
 		// Get service mode from registry to determine appropriate status
 		entry, _ := reg.GetService(serviceName)
 		mode := ""
@@ -620,7 +978,19 @@ func monitorServiceProcess(ctx context.Context, wg *sync.WaitGroup, serviceName
 			mode = entry.Mode
 		}
 
-		if result.err != nil {
+		if entry != nil && entry.Status == constants.StatusStopping {
+			// Stopped on purpose by `azd app stop/restart` or the dashboard, which update the
+			// registry themselves - the exit code of a terminated process is not a crash
+			slog.Debug("service stopped by request", "service", serviceName, "exitCode", result.exitCode)
+		} else if onExit != nil && onExit(result.exitCode) {
+			// Relaunched by its restart policy, which reports the exit and updates the registry
+			slog.Debug("service exited and will be relaunched", "service", serviceName, "exitCode", result.exitCode)
+			if result.err != nil {
+				emitServiceCrashed(info)
+			}
+		} else if result.err != nil {
+			emitServiceCrashed(info)
+
 			// Update registry to trigger OS notification via state monitor
 			// CRITICAL FIX: Implement retry logic for registry updates
 			maxRetries := 3
@@ -646,11 +1016,11 @@ func monitorServiceProcess(ctx context.Context, wg *sync.WaitGroup, serviceName
 			// Show mode-appropriate error message
 			switch mode {
 			case service.ServiceModeBuild:
-				cliout.Error("Build failed: %s (exit code %d)", serviceName, result.exitCode)
+				output.Error("Build failed: %s (exit code %d)", serviceName, result.exitCode)
 			case service.ServiceModeTask:
-				cliout.Error("Task failed: %s (exit code %d)", serviceName, result.exitCode)
+				output.Error("Task failed: %s (exit code %d)", serviceName, result.exitCode)
 			default:
-				cliout.Error("⚠️  %v", result.err)
+				output.Error("⚠️  %v", result.err)
 				cliout.Warning("Service %s stopped. Other services continue running.", serviceName)
 				cliout.Info("Press Ctrl+C to stop all services")
 			}
@@ -689,6 +1059,8 @@ func monitorServiceProcess(ctx context.Context, wg *sync.WaitGroup, serviceName
 				cliout.Warning("Failed to update registry for %s after %d retries: %v", serviceName, maxRetries, regErr)
 			}
 		}
+
+		service.EmitServiceEvent(info)
 		// Intentionally don't cancel context - other services should continue
 	case <-ctx.Done():
 		// Context canceled by signal - proceed to graceful shutdown
@@ -696,11 +1068,19 @@ func monitorServiceProcess(ctx context.Context, wg *sync.WaitGroup, serviceName
 	}
 }
 
+// emitServiceCrashed reports a service that exited with an error without being stopped.
+func emitServiceCrashed(info service.ServiceEventInfo) {
+	info.Event = service.EventServiceCrashed
+	service.EmitServiceEvent(info)
+}
+
 // shutdownAllServices stops all services with graceful timeout.
 // Runs all shutdowns in parallel goroutines and waits for all to complete.
-// Returns aggregated errors from any services that failed to stop cleanly.
-func shutdownAllServices(ctx context.Context, processes map[string]*service.ServiceProcess) error {
+// Returns per-service results sorted by name, and aggregated errors from any
+// services that failed to stop cleanly.
+func shutdownAllServices(ctx context.Context, processes map[string]*service.ServiceProcess) ([]service.StopResult, error) {
 	var shutdownErrors []error
+	var results []service.StopResult
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
@@ -709,7 +1089,8 @@ func shutdownAllServices(ctx context.Context, processes map[string]*service.Serv
 		go func(serviceName string, proc *service.ServiceProcess) {
 			defer wg.Done()
 
-			if proc.Process == nil {
+			// Containers are kept for the next run; compose projects are taken down
+			if proc.Process == nil && proc.Runtime.Type != service.ServiceTypeCompose {
 				return
 			}
 
@@ -723,49 +1104,104 @@ func shutdownAllServices(ctx context.Context, processes map[string]*service.Serv
 				}
 			}
 
-			if err := service.StopServiceGraceful(proc, timeout); err != nil {
-				mu.Lock()
+			result, err := service.StopServiceWithResult(proc, timeout)
+			result.Service = serviceName
+
+			mu.Lock()
+			results = append(results, result)
+			if err != nil {
 				shutdownErrors = append(shutdownErrors, fmt.Errorf("%s: %w", serviceName, err))
-				mu.Unlock()
 			}
+			mu.Unlock()
 		}(name, process)
 	}
 
 	wg.Wait()
 
+	sort.Slice(results, func(i, j int) bool { return results[i].Service < results[j].Service })
+
 	if len(shutdownErrors) > 0 {
-		return fmt.Errorf("failed to stop %d service(s): %w", len(shutdownErrors), errors.Join(shutdownErrors...))
+		return results, fmt.Errorf("failed to stop %d service(s): %w", len(shutdownErrors), errors.Join(shutdownErrors...))
 	}
-	return nil
+	return results, nil
 }
 
-// runAspireMode runs Aspire AppHost directly using dotnet run.
-func runAspireMode(ctx context.Context, rootDir string) error {
-	// Find Aspire AppHost project
-	aspireProject, err := detector.FindAppHost(rootDir)
-	if err != nil {
-		return fmt.Errorf("failed to search for Aspire AppHost: %w", err)
-	}
+// shutdownEvent is the JSON event emitted when `run` finishes shutting down.
+type shutdownEvent struct {
+	Event      string               `json:"event"`
+	Clean      bool                 `json:"clean"`
+	DurationMs int64                `json:"durationMs"`
+	Services   []service.StopResult `json:"services"`
+}
 
-	if aspireProject == nil {
-		return fmt.Errorf("no Aspire AppHost found - --runtime aspire requires an AppHost.cs or Program.cs file in a .csproj project")
+// printShutdownSummary reports how each service stopped.
+// In JSON mode it emits a single "shutdown" event line so wrappers can detect unclean shutdowns.
+func printShutdownSummary(results []service.StopResult, elapsed time.Duration) {
+	clean := true
+	for _, r := range results {
+		if !r.Clean() {
+			clean = false
+		}
 	}
 
-	cliout.Plain("Running Aspire in native mode")
-	cliout.Item("Directory: %s", aspireProject.Dir)
-	cliout.Item("Project: %s", aspireProject.ProjectFile)
-	cliout.Newline()
-	cliout.Plain("Aspire dashboard will start automatically")
-	cliout.Newline()
+	if cliout.IsJSON() {
+		event := shutdownEvent{
+			Event:      "shutdown",
+			Clean:      clean,
+			DurationMs: elapsed.Milliseconds(),
+			Services:   results,
+		}
+		if event.Services == nil {
+			event.Services = []service.StopResult{}
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			slog.Debug("failed to marshal shutdown event", "error", err)
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
 
-	// Use executor to run dotnet with proper environment inheritance
-	args := []string{"run", "--project", aspireProject.ProjectFile}
+	if len(results) > 0 {
+		rows := make([]cliout.TableRow, 0, len(results))
+		for _, r := range results {
+			exitCode := "-"
+			if r.ExitCode >= 0 {
+				exitCode = strconv.Itoa(r.ExitCode)
+			}
+			rows = append(rows, cliout.TableRow{
+				"Service":  r.Service,
+				"Result":   describeStopOutcome(r.Outcome),
+				"Exit":     exitCode,
+				"Duration": r.Duration.Round(10 * time.Millisecond).String(),
+			})
+		}
+		cliout.Newline()
+		cliout.Table([]string{"Service", "Result", "Exit", "Duration"}, rows)
+		cliout.Newline()
+	}
 
-	cliout.Hint("Press Ctrl+C to stop")
+	if clean {
+		cliout.Success("All services stopped")
+	} else {
+		cliout.Warning("Some services did not stop cleanly")
+	}
 	cliout.Newline()
+}
 
-	// Run dotnet and let it handle everything (inherits all azd env vars)
-	return executor.StartCommand(ctx, "dotnet", args, aspireProject.Dir)
+// describeStopOutcome returns the user-facing label for a stop outcome.
+func describeStopOutcome(outcome string) string {
+	switch outcome {
+	case service.StopOutcomeGraceful:
+		return "stopped gracefully"
+	case service.StopOutcomeForced:
+		return "force-killed"
+	case service.StopOutcomeSkipped:
+		return "skipped"
+	default:
+		return "failed to stop"
+	}
 }
 
 // showDryRun displays what would be executed without starting services.
@@ -780,6 +1216,9 @@ func showDryRun(runtimes []*service.ServiceRuntime) error {
 		cliout.Label("Port", fmt.Sprintf("%d", runtime.Port))
 		cliout.Label("Directory", runtime.WorkingDir)
 		cliout.Label("Command", fmt.Sprintf("%s %v", runtime.Command, runtime.Args))
+		if len(runtime.DependsOn) > 0 {
+			cliout.Label("Depends on", strings.Join(runtime.DependsOn, ", "))
+		}
 	}
 
 	return nil
@@ -790,6 +1229,20 @@ func executePrerunHook(azureYaml *service.AzureYaml, workingDir string) error {
 	return executeHook(azureYaml, azureYaml.Hooks, azureYaml.Hooks.GetPrerun(), "prerun", workingDir)
 }
 
+// executePredepsHook executes the predeps hook of the azure.yaml in projectDir, if configured.
+func executePredepsHook(projectDir string) error {
+	azureYamlPath, err := detector.FindAzureYaml(projectDir)
+	if err != nil || azureYamlPath == "" {
+		return nil // deps reports the missing azure.yaml
+	}
+	azureYaml, err := service.ParseAzureYaml(azureYamlPath)
+	if err != nil {
+		return nil // deps reports the parse error
+	}
+	workingDir := filepath.Dir(azureYamlPath)
+	return executeHook(azureYaml, azureYaml.Hooks, azureYaml.Hooks.GetPredeps(), "predeps", workingDir)
+}
+
 // executePostrunHook executes the postrun hook if configured.
 func executePostrunHook(azureYaml *service.AzureYaml, workingDir string) error {
 	return executeHook(azureYaml, azureYaml.Hooks, azureYaml.Hooks.GetPostrun(), "postrun", workingDir)
@@ -817,6 +1270,96 @@ func executeHook(azureYaml *service.AzureYaml, hooks *service.Hooks, hook *servi
 	return executor.ExecuteHook(context.Background(), hookName, *config, workingDir)
 }
 
+// serviceEvents are the service lifecycle events that can have a hook in azure.yaml.
+var serviceEvents = []service.ServiceEvent{
+	service.EventServiceStarting,
+	service.EventServiceStarted,
+	service.EventServiceStopped,
+	service.EventServiceHealthChanged,
+	service.EventServiceReady,
+	service.EventServiceCrashed,
+}
+
+// registerServiceEventHooks registers an orchestrator handler for each service lifecycle hook
+// configured in azure.yaml, and for each service's own postStop hook.
+// The returned function unregisters the handlers.
+func registerServiceEventHooks(azureYaml *service.AzureYaml, workingDir string, runtimes []*service.ServiceRuntime) func() {
+	var unregister []func()
+	for _, event := range serviceEvents {
+		hook := azureYaml.Hooks.GetServiceHook(event)
+		if hook == nil {
+			continue
+		}
+		unregister = append(unregister, service.OnServiceEvent(event, func(info service.ServiceEventInfo) {
+			if err := executeServiceEventHook(azureYaml, hook, info, workingDir, workingDir); err != nil {
+				cliout.Warning("%v", err)
+			}
+		}))
+	}
+
+	for _, rt := range runtimes {
+		if rt.PostStop == nil {
+			continue
+		}
+		name, hook, serviceDir := rt.Name, rt.PostStop, rt.WorkingDir
+		unregister = append(unregister, service.OnServiceEvent(service.EventServiceStopped, func(info service.ServiceEventInfo) {
+			if info.Service != name {
+				return
+			}
+			info.Event = "postStop"
+			if err := executeServiceEventHook(azureYaml, hook, info, workingDir, serviceDir); err != nil {
+				cliout.Warning("%v", err)
+			}
+		}))
+	}
+
+	return func() {
+		for _, fn := range unregister {
+			fn()
+		}
+	}
+}
+
+// executeServiceEventHook runs a service lifecycle hook in hookDir with the event details in its environment.
+// Hook failures never stop the service lifecycle, so they are returned for the caller to report.
+func executeServiceEventHook(azureYaml *service.AzureYaml, hook *service.Hook, info service.ServiceEventInfo, workingDir, hookDir string) error {
+	config := executor.ResolveHookConfig(convertHook(hook))
+	if config == nil {
+		return nil
+	}
+
+	config.Env = append(buildHookEnvironmentVariables(azureYaml, workingDir), buildServiceEventEnvironmentVariables(info)...)
+
+	hookName := fmt.Sprintf("%s (%s)", info.Event, info.Service)
+	return executor.ExecuteHook(context.Background(), hookName, *config, hookDir)
+}
+
+// buildServiceEventEnvironmentVariables describes a service lifecycle event to its hook.
+func buildServiceEventEnvironmentVariables(info service.ServiceEventInfo) []string {
+	envVars := []string{
+		fmt.Sprintf("%s=%s", executor.EnvEvent, info.Event),
+		fmt.Sprintf("%s=%s", executor.EnvServiceName, info.Service),
+	}
+	if status := info.Status(); status != "" {
+		envVars = append(envVars, fmt.Sprintf("%s=%s", executor.EnvServiceStatus, status))
+	}
+	if info.Port > 0 {
+		envVars = append(envVars, fmt.Sprintf("%s=%d", executor.EnvServicePort, info.Port))
+	}
+	if info.PID > 0 {
+		envVars = append(envVars, fmt.Sprintf("%s=%d", executor.EnvServicePID, info.PID))
+	}
+	if info.Health != "" {
+		envVars = append(envVars,
+			fmt.Sprintf("%s=%s", executor.EnvServiceHealth, info.Health),
+			fmt.Sprintf("%s=%s", executor.EnvServicePreviousHealth, info.PreviousHealth))
+	}
+	if info.Error != nil {
+		envVars = append(envVars, fmt.Sprintf("%s=%s", executor.EnvServiceError, info.Error.Error()))
+	}
+	return envVars
+}
+
 // buildHookEnvironmentVariables builds environment variables to pass to hooks
 // Following the pattern from azure/azure-dev
 func buildHookEnvironmentVariables(azureYaml *service.AzureYaml, workingDir string) []string {
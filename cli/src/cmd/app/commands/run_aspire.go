@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/cmdutil"
+	"github.com/jongio/azd-core/registry"
+)
+
+// aspireStartedMessage is logged by an AppHost once its resources have been started.
+const aspireStartedMessage = "Distributed application started"
+
+// runAspireMode runs the Aspire AppHost directly using dotnet run, with the native Aspire
+// dashboard. The AppHost and the projects, containers and executables of its app model are
+// also shown in the azd app dashboard and in `azd app status` while it runs.
+func runAspireMode(ctx context.Context, rootDir string) error {
+	// Find Aspire AppHost project
+	aspireProject, err := detector.FindAppHost(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to search for Aspire AppHost: %w", err)
+	}
+
+	if aspireProject == nil {
+		return fmt.Errorf("no Aspire AppHost found - --runtime aspire requires an AppHost.cs or Program.cs file in a .csproj project")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	appHost := aspireAppHostName(rootDir, aspireProject.Dir, aspireProject.ProjectFile)
+
+	cliout.Plain("Running Aspire in native mode")
+	cliout.Item("Directory: %s", aspireProject.Dir)
+	cliout.Item("Project: %s", aspireProject.ProjectFile)
+	cliout.Newline()
+	cliout.Plain("Aspire dashboard will start automatically")
+	cliout.Newline()
+
+	// The AppHost gets Ctrl+C from the terminal and stops its resources; azd waits for it to exit
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	// Resources are discovered once the AppHost has started them, and therefore has been built
+	var discover sync.Once
+	onOutput := func(line string) {
+		if !strings.Contains(line, aspireStartedMessage) {
+			return
+		}
+		discover.Do(func() {
+			go func() {
+				if err := service.DiscoverAspireResources(ctx, cwd, appHost, aspireProject.ProjectFile); err != nil {
+					slog.Warn("failed to discover Aspire resources", slog.String("apphost", appHost), slog.String("error", err.Error()))
+				}
+			}()
+		})
+	}
+
+	args := []string{"run", "--project", aspireProject.ProjectFile}
+	cmd, err := cmdutil.StartCommandWithOutputMonitoring(ctx, "dotnet", args, aspireProject.Dir, onOutput)
+	if err != nil {
+		return fmt.Errorf("failed to start Aspire AppHost: %w", err)
+	}
+
+	var interrupted atomic.Bool
+	exited := make(chan struct{})
+	defer close(exited)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				interrupted.Store(true)
+				if sig != os.Interrupt {
+					_ = cmd.Process.Signal(sig)
+				}
+			case <-exited:
+				return
+			}
+		}
+	}()
+
+	reg := registry.GetRegistry(cwd)
+	if err := reg.Register(&registry.ServiceRegistryEntry{
+		Name:       appHost,
+		ProjectDir: cwd,
+		PID:        cmd.Process.Pid,
+		Language:   ".NET",
+		Framework:  service.FrameworkAspire.Name,
+		Status:     constants.StatusRunning,
+		StartTime:  time.Now(),
+		Type:       service.ServiceTypeProcess,
+	}); err != nil {
+		slog.Debug("failed to register Aspire AppHost", "error", err)
+	}
+	defer func() { _ = reg.Unregister(appHost) }()
+	defer service.ForgetAspireResources(appHost)
+
+	dashboardServer := dashboard.GetServer(cwd)
+	if dashboardURL, err := dashboardServer.Start(); err != nil {
+		cliout.Warning("Dashboard unavailable: %v", err)
+	} else {
+		defer func() { _ = dashboardServer.Stop() }()
+		registerRunSession(cwd, dashboardURL, map[string]*service.ServiceProcess{appHost: {Name: appHost}})
+		defer service.UnregisterSession(cwd)
+		cliout.Plain("  Dashboard  %s", dashboardURL)
+	}
+	cliout.Hint("Press Ctrl+C to stop")
+	cliout.Newline()
+
+	// The AppHost exits with an error code when it is interrupted
+	if err := cmd.Wait(); err != nil && !interrupted.Load() {
+		return fmt.Errorf("the Aspire AppHost exited: %w", err)
+	}
+	return nil
+}
+
+// aspireAppHostName returns the name the AppHost is shown with: its service in azure.yaml,
+// or else the name of its project.
+func aspireAppHostName(azureYamlDir, appHostDir, projectFile string) string {
+	if azureYaml, err := service.ParseAzureYaml(azureYamlDir); err == nil {
+		for name, svc := range azureYaml.Services {
+			projectDir := svc.Project
+			if projectDir != "" && !filepath.IsAbs(projectDir) {
+				projectDir = filepath.Join(azureYamlDir, projectDir)
+			}
+			if projectDir != "" && filepath.Clean(projectDir) == filepath.Clean(appHostDir) {
+				return name
+			}
+		}
+	}
+	return strings.ToLower(strings.TrimSuffix(filepath.Base(projectFile), filepath.Ext(projectFile)))
+}
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAspireAppHostName(t *testing.T) {
+	dir := t.TempDir()
+	appHostDir := filepath.Join(dir, "src", "Shop.AppHost")
+	projectFile := filepath.Join(appHostDir, "Shop.AppHost.csproj")
+
+	if got := aspireAppHostName(dir, appHostDir, projectFile); got != "shop.apphost" {
+		t.Errorf("aspireAppHostName() without azure.yaml = %q, want shop.apphost", got)
+	}
+
+	if err := os.MkdirAll(appHostDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	azureYaml := "name: shop\nservices:\n  app:\n    project: ./src/Shop.AppHost\n    language: dotnet\n    host: containerapp\n"
+	if err := os.WriteFile(filepath.Join(dir, "azure.yaml"), []byte(azureYaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := aspireAppHostName(dir, appHostDir, projectFile); got != "app" {
+		t.Errorf("aspireAppHostName() = %q, want the azure.yaml service app", got)
+	}
+}
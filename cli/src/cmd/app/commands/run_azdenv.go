@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/jongio/azd-app/cli/src/internal/azdenv"
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/serviceinfo"
+	"github.com/jongio/azd-core/cliout"
+)
+
+// loadAzdEnvironment loads the azd environment selected with --azd-env, or the current one.
+// Without --azd-env, a project that has no azd environment yet (or hasn't selected one) runs
+// without, so nil is returned.
+func loadAzdEnvironment(projectDir string) (*azdenv.Environment, error) {
+	azdEnv, err := azdenv.Load(projectDir, runAzdEnv)
+	if err != nil {
+		if runAzdEnv == "" {
+			if !errors.Is(err, azdenv.ErrNoEnvironment) {
+				slog.Debug("not loading the azd environment", "error", err)
+			}
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	cliout.Info("Using azd environment: %s", azdEnv.Name)
+	serviceinfo.SetAzdEnvironment(azdEnv.Values)
+	return azdEnv, nil
+}
+
+// applyAzdEnvironmentChange gives restarted services the new values of the azd environment,
+// e.g. after azd provision ran in another terminal, and refreshes the dashboard so it shows
+// the new Azure endpoints. Running services keep their values until they are restarted.
+func applyAzdEnvironmentChange(projectDir string, updated *azdenv.Environment, changed []string, restarter *serviceRestarter) {
+	serviceinfo.SetAzdEnvironment(updated.Values)
+	if restarter != nil {
+		envVars, err := loadEnvironmentVariables(updated)
+		if err != nil {
+			cliout.Warning("Failed to reload environment variables: %v", err)
+		} else {
+			restarter.setEnvVars(envVars)
+		}
+	}
+
+	cliout.Info("azd environment '%s' changed: %s", updated.Name, strings.Join(changed, ", "))
+	cliout.Hint("Restart services to use the new values: azd app restart")
+
+	if err := dashboard.GetServer(projectDir).BroadcastServiceUpdate(projectDir); err != nil {
+		slog.Debug("failed to broadcast azd environment change", "error", err)
+	}
+}
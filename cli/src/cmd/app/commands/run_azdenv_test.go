@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/azdenv"
+	"github.com/jongio/azd-app/cli/src/internal/serviceinfo"
+)
+
+func TestLoadAzdEnvironment(t *testing.T) {
+	projectDir := t.TempDir()
+	t.Setenv(azdenv.EnvName, "")
+	defer serviceinfo.SetAzdEnvironment(nil)
+	defer func() { runAzdEnv = "" }()
+
+	// Without --azd-env, a project without an environment runs without one
+	runAzdEnv = ""
+	azdEnv, err := loadAzdEnvironment(projectDir)
+	if err != nil || azdEnv != nil {
+		t.Fatalf("loadAzdEnvironment() = %v, %v; want no environment", azdEnv, err)
+	}
+
+	// A missing environment selected with --azd-env is an error
+	runAzdEnv = "staging"
+	if _, err := loadAzdEnvironment(projectDir); err == nil {
+		t.Fatal("loadAzdEnvironment() should fail for a missing --azd-env environment")
+	}
+
+	if err := os.MkdirAll(azdenv.Dir(projectDir, "staging"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(azdenv.Path(projectDir, "staging"), []byte("SERVICE_API_URL=\"https://staging.example.com\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	azdEnv, err = loadAzdEnvironment(projectDir)
+	if err != nil {
+		t.Fatalf("loadAzdEnvironment() error = %v", err)
+	}
+	if azdEnv.Name != "staging" || serviceinfo.AzdEnvironment()["SERVICE_API_URL"] != "https://staging.example.com" {
+		t.Errorf("loadAzdEnvironment() = %+v, want staging shared with the dashboard", azdEnv)
+	}
+}
+
+func TestLoadEnvironmentVariablesPrecedence(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), "local.env")
+	if err := os.WriteFile(envFile, []byte("DATABASE_URL=postgres://localhost\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	runEnvFile = envFile
+	defer func() { runEnvFile = "" }()
+
+	azdEnv := &azdenv.Environment{Name: "dev", Values: map[string]string{
+		"DATABASE_URL":    "postgres://azure",
+		"SERVICE_API_URL": "https://api.example.com",
+	}}
+	envVars, err := loadEnvironmentVariables(azdEnv)
+	if err != nil {
+		t.Fatalf("loadEnvironmentVariables() error = %v", err)
+	}
+	if envVars["DATABASE_URL"] != "postgres://localhost" {
+		t.Errorf("DATABASE_URL = %q, want --env-file to override the azd environment", envVars["DATABASE_URL"])
+	}
+	if envVars["SERVICE_API_URL"] != "https://api.example.com" {
+		t.Errorf("SERVICE_API_URL = %q, want the azd environment value", envVars["SERVICE_API_URL"])
+	}
+}
@@ -0,0 +1,213 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/azdconfig"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+)
+
+const (
+	// envDetachedSession marks the background process started by 'azd app run --detach'.
+	envDetachedSession = "AZD_APP_DETACHED"
+
+	// detachedPollInterval is how often the launching process checks whether the session is up.
+	detachedPollInterval = 250 * time.Millisecond
+
+	// detachedLogTailLines is how much of the session output is shown when startup fails.
+	detachedLogTailLines = 20
+
+	// sessionStopTimeout is how long a session process that is stopped with a signal gets
+	// to stop its services before it is killed.
+	sessionStopTimeout = 15 * time.Second
+)
+
+// isDetachedSession reports whether this process is the background session of 'azd app run --detach'.
+func isDetachedSession() bool {
+	return os.Getenv(envDetachedSession) == "1"
+}
+
+// startDetachedSession starts 'azd app run' again as a background process that survives the
+// invoking shell, and waits until its services and dashboard are up. The session records
+// itself in .azure/session.json, which status, logs and stop use from other terminals;
+// its output goes to .azure/run.log.
+func startDetachedSession(ctx context.Context) error {
+	if runDryRun {
+		return fmt.Errorf("--detach can't be combined with --dry-run")
+	}
+	if runRuntime == runtimeModeAspire {
+		return fmt.Errorf("--detach is not supported with --runtime %s", runtimeModeAspire)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if _, err := findAzureYaml(); err != nil {
+		return err
+	}
+
+	existing, err := service.ReadSession(cwd)
+	if err != nil {
+		slog.Debug("failed to read session", "error", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("a detached session is already running for this project (PID %d); stop it with 'azd app stop --all'", existing.PID)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the azd app executable: %w", err)
+	}
+
+	logPath := service.SessionLogPath(cwd)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0750); err != nil {
+		return fmt.Errorf("failed to create .azure directory: %w", err)
+	}
+	// #nosec G304 -- logPath is the session log in the project's .azure directory
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create session log: %w", err)
+	}
+	defer logFile.Close()
+
+	// The session runs the same command line; the environment variable tells it not to detach again
+	// #nosec G204 -- executable is this binary, started with the arguments it was invoked with
+	child := exec.Command(executable, os.Args[1:]...)
+	child.Dir = cwd
+	child.Env = append(os.Environ(), envDetachedSession+"=1")
+	child.Env = append(child.Env, detachedStateEnv(ctx, cwd)...)
+	child.Stdout = logFile
+	child.Stderr = logFile
+	setupDetachedProcess(child)
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("failed to start detached session: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- child.Wait() }()
+
+	if !cliout.IsJSON() {
+		cliout.Info("Starting in the background (PID %d), output in %s", child.Process.Pid, logPath)
+	}
+	session, err := waitForDetachedSession(ctx, cwd, child.Process.Pid, exited)
+	if err != nil {
+		return err
+	}
+
+	if cliout.IsJSON() {
+		return cliout.PrintJSON(session)
+	}
+	cliout.Success("Running in the background (PID %d)", session.PID)
+	if session.DashboardURL != "" {
+		cliout.Plain("  Dashboard  %s", session.DashboardURL)
+	}
+	cliout.Newline()
+	cliout.Hint("azd app status", "azd app logs", "azd app stop --all to stop")
+	return nil
+}
+
+// detachedStateEnv returns the environment that moves the session's state off azd. State kept
+// by azd is read over gRPC from the azd process that started this command, which exits once the
+// session is up; the session would then lose its port assignments. The session uses the file
+// backend instead, seeded with the project's current assignments so services keep their ports.
+func detachedStateEnv(ctx context.Context, projectDir string) []string {
+	if azdconfig.Backend() != azdconfig.BackendAzd {
+		return nil
+	}
+
+	client, err := azdconfig.OpenBackend(ctx, azdconfig.BackendFile)
+	if err != nil {
+		// Ports assigned by the session are still kept for its lifetime
+		slog.Debug("failed to open file state backend for detached session", "error", err)
+		return []string{azdconfig.EnvStateBackend + "=" + azdconfig.BackendMemory}
+	}
+	defer client.Close()
+	if err := portmanager.GetPortManager(projectDir).SaveTo(client); err != nil {
+		slog.Debug("failed to copy port assignments for detached session", "error", err)
+	}
+	return []string{azdconfig.EnvStateBackend + "=" + azdconfig.BackendFile}
+}
+
+// waitForDetachedSession waits until the background process with the given PID records its
+// session, i.e. its services are running and its dashboard is up. It fails with the end of
+// the session output if the process exits first.
+func waitForDetachedSession(ctx context.Context, projectDir string, pid int, exited <-chan error) (*service.Session, error) {
+	ticker := time.NewTicker(detachedPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("stopped waiting for the detached session (PID %d), which keeps starting in the background: %w", pid, ctx.Err())
+		case err := <-exited:
+			logPath := service.SessionLogPath(projectDir)
+			if err == nil {
+				err = fmt.Errorf("exited")
+			}
+			return nil, fmt.Errorf("detached session failed to start: %v\n%s\nFull output: %s", err, readLogTail(logPath, detachedLogTailLines), logPath)
+		case <-ticker.C:
+			session, err := service.ReadSession(projectDir)
+			if err != nil {
+				slog.Debug("failed to read session", "error", err)
+				continue
+			}
+			if session != nil && session.PID == pid {
+				return session, nil
+			}
+		}
+	}
+}
+
+// readLogTail returns the last lines of a log file, or an empty string if it can't be read.
+func readLogTail(path string, lines int) string {
+	// #nosec G304 -- path is the session log in the project's .azure directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	all := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	return strings.Join(all, "\n")
+}
+
+// recordDetachedSession records the session of this background process once its dashboard has
+// started, which also tells the 'azd app run --detach' that launched it that startup is complete.
+func recordDetachedSession(projectDir, dashboardURL string, processes map[string]*service.ServiceProcess) {
+	if err := service.WriteSession(projectDir, dashboardURL, processes); err != nil {
+		cliout.Warning("Failed to record the session; other terminals can't find it: %v", err)
+	}
+}
+
+// stopDetachedSession stops the detached session of a project whose dashboard can't be reached,
+// with a stop signal to its process, and reports whether it was stopped. Nothing happens if no
+// session is running.
+func stopDetachedSession(projectDir string) bool {
+	session, err := service.ReadSession(projectDir)
+	if err != nil {
+		slog.Debug("failed to read session", "error", err)
+		return false
+	}
+	if session == nil {
+		return false
+	}
+	if err := service.StopSession(session, sessionStopTimeout); err != nil {
+		cliout.Warning("%v", err)
+		return false
+	}
+	if !cliout.IsJSON() {
+		cliout.Success("Stopped the background session of %s (PID %d)", projectDir, session.PID)
+	}
+	return true
+}
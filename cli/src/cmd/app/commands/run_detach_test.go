@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/azdconfig"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+func TestWaitForDetachedSession(t *testing.T) {
+	projectDir := t.TempDir()
+	exited := make(chan error, 1)
+
+	go func() {
+		time.Sleep(2 * detachedPollInterval)
+		_ = service.WriteSession(projectDir, "http://localhost:41234", nil)
+	}()
+	defer service.RemoveSession(projectDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// The test process stands in for the background process
+	session, err := waitForDetachedSession(ctx, projectDir, os.Getpid(), exited)
+	if err != nil {
+		t.Fatalf("waitForDetachedSession() error = %v", err)
+	}
+	if session.DashboardURL != "http://localhost:41234" {
+		t.Errorf("DashboardURL = %q, want http://localhost:41234", session.DashboardURL)
+	}
+}
+
+func TestWaitForDetachedSession_Exited(t *testing.T) {
+	projectDir := t.TempDir()
+	logPath := service.SessionLogPath(projectDir)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logPath, []byte("starting\nError: port 3000 in use\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	exited := make(chan error, 1)
+	exited <- errors.New("exit status 1")
+	_, err := waitForDetachedSession(context.Background(), projectDir, os.Getpid(), exited)
+	if err == nil {
+		t.Fatal("waitForDetachedSession() expected error when the process exits")
+	}
+	if !strings.Contains(err.Error(), "exit status 1") || !strings.Contains(err.Error(), "port 3000 in use") {
+		t.Errorf("error = %v, want the exit status and the end of the output", err)
+	}
+}
+
+func TestReadLogTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.log")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readLogTail(path, 2); got != "two\nthree" {
+		t.Errorf("readLogTail() = %q, want %q", got, "two\nthree")
+	}
+	if got := readLogTail(path, 5); got != "one\ntwo\nthree" {
+		t.Errorf("readLogTail() = %q, want all lines", got)
+	}
+	if got := readLogTail(filepath.Join(t.TempDir(), "missing.log"), 2); got != "" {
+		t.Errorf("readLogTail() of a missing file = %q, want empty", got)
+	}
+}
+
+func TestStartDetachedSession_InvalidFlags(t *testing.T) {
+	origDryRun, origRuntime := runDryRun, runRuntime
+	defer func() { runDryRun, runRuntime = origDryRun, origRuntime }()
+
+	runDryRun, runRuntime = true, runtimeModeAzd
+	if err := startDetachedSession(context.Background()); err == nil || !strings.Contains(err.Error(), "--dry-run") {
+		t.Errorf("startDetachedSession() with --dry-run error = %v", err)
+	}
+
+	runDryRun, runRuntime = false, runtimeModeAspire
+	if err := startDetachedSession(context.Background()); err == nil || !strings.Contains(err.Error(), "--runtime") {
+		t.Errorf("startDetachedSession() with --runtime aspire error = %v", err)
+	}
+}
+
+func TestDetachedStateEnv(t *testing.T) {
+	projectDir := t.TempDir()
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	t.Setenv(azdconfig.EnvStatePath, stateFile)
+	defer portmanager.SetTestModeForTesting(func(int) bool { return true })()
+	defer portmanager.InvalidateCache(projectDir)
+
+	pm := portmanager.GetPortManager(projectDir)
+	pm.SetConfigClient(azdconfig.NewInMemoryClient())
+	if _, _, err := pm.AssignPort("api", 4127, true); err != nil {
+		t.Fatalf("AssignPort() failed: %v", err)
+	}
+
+	// State kept by azd moves to the file backend, seeded with the project's ports
+	t.Setenv(azdconfig.EnvStateBackend, "")
+	env := detachedStateEnv(context.Background(), projectDir)
+	if len(env) != 1 || env[0] != azdconfig.EnvStateBackend+"="+azdconfig.BackendFile {
+		t.Fatalf("detachedStateEnv() = %v, want the file backend", env)
+	}
+	client, err := azdconfig.NewFileClient(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolvedDir, err := filepath.EvalSymlinks(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ports, err := client.GetAllServicePorts(azdconfig.ProjectHash(resolvedDir))
+	if err != nil || ports["api"] != 4127 {
+		t.Errorf("seeded ports = %v, %v; want api on 4127", ports, err)
+	}
+
+	// Other backends don't depend on azd
+	t.Setenv(azdconfig.EnvStateBackend, azdconfig.BackendSQLite)
+	if env := detachedStateEnv(context.Background(), projectDir); env != nil {
+		t.Errorf("detachedStateEnv() with sqlite = %v, want none", env)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package commands
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setupDetachedProcess starts the command in a new session, without a controlling terminal,
+// so closing the terminal (SIGHUP) or Ctrl+C in it doesn't reach it.
+func setupDetachedProcess(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+}
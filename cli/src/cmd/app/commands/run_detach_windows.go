@@ -0,0 +1,20 @@
+//go:build windows
+
+package commands
+
+import (
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// setupDetachedProcess starts the command without a console and in its own process group,
+// so closing the console window or Ctrl+C in it doesn't reach it.
+func setupDetachedProcess(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP
+	cmd.SysProcAttr.HideWindow = true
+}
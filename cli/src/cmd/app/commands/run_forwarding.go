@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+
+	"github.com/jongio/azd-app/cli/src/internal/devenv"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+)
+
+// Codespaces port visibilities for --port-visibility.
+const (
+	portVisibilityPrivate = "private"
+	portVisibilityOrg     = "org"
+	portVisibilityPublic  = "public"
+)
+
+// setCodespacePortVisibility sets the visibility of forwarded codespace ports with the GitHub CLI.
+// This is a variable to allow test overrides.
+var setCodespacePortVisibility = func(codespace, visibility string, ports []int) error {
+	args := []string{"codespace", "ports", "visibility"}
+	for _, port := range ports {
+		args = append(args, fmt.Sprintf("%d:%s", port, visibility))
+	}
+	args = append(args, "--codespace", codespace)
+	// #nosec G204 -- args are ports, a validated visibility and the codespace name from CODESPACE_NAME
+	output, err := exec.CommandContext(context.Background(), "gh", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("gh codespace ports visibility: %w: %s", err, output)
+	}
+	return nil
+}
+
+// validatePortVisibility validates the --port-visibility value; "" leaves visibilities as they are.
+func validatePortVisibility(visibility string) error {
+	switch visibility {
+	case "", portVisibilityPrivate, portVisibilityOrg, portVisibilityPublic:
+		return nil
+	default:
+		return fmt.Errorf("invalid --port-visibility value: %s (must be '%s', '%s' or '%s')", visibility, portVisibilityPrivate, portVisibilityOrg, portVisibilityPublic)
+	}
+}
+
+// addForwardedURLs sets the forwarded URL of each service with a local URL in a codespace,
+// so the summary shows URLs that work in the browser.
+func addForwardedURLs(env devenv.Environment, summaries []service.ServiceURLSummary) {
+	for i := range summaries {
+		summaries[i].ForwardedURL = env.ForwardedURL(summaries[i].LocalURL)
+	}
+}
+
+// summaryPorts returns the sorted local ports of the services.
+func summaryPorts(summaries []service.ServiceURLSummary) []int {
+	seen := make(map[int]bool)
+	var ports []int
+	for _, summary := range summaries {
+		if port := devenv.LocalPort(summary.LocalURL); port > 0 && !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+	sort.Ints(ports)
+	return ports
+}
+
+// showRemoteForwarding makes the service ports reachable from the browser in a remote
+// environment: in a codespace it sets their visibility when requested, and over SSH it prints
+// the ssh command that forwards them.
+func showRemoteForwarding(env devenv.Environment, summaries []service.ServiceURLSummary, visibility string) {
+	ports := summaryPorts(summaries)
+	if len(ports) == 0 {
+		return
+	}
+
+	switch {
+	case env.Codespaces:
+		if visibility == "" {
+			return
+		}
+		if env.CodespaceName == "" {
+			cliout.Warning("Cannot set port visibility: CODESPACE_NAME is not set")
+			return
+		}
+		if err := setCodespacePortVisibility(env.CodespaceName, visibility, ports); err != nil {
+			cliout.Warning("Failed to make ports %s: %v", visibility, err)
+			return
+		}
+		cliout.Info("Ports %s are %s", joinPorts(ports), visibility)
+	case env.SSH:
+		if visibility != "" {
+			cliout.Warning("--port-visibility only applies in GitHub Codespaces")
+		}
+		cliout.Hint("Forward the ports to your machine: " + env.SSHForwardCommand(ports))
+	case visibility != "":
+		cliout.Warning("--port-visibility only applies in GitHub Codespaces")
+	}
+}
+
+// dashboardDisplayURL returns the dashboard URL to print: its forwarded URL in a codespace,
+// else the local URL.
+func dashboardDisplayURL(env devenv.Environment, dashboardURL string) string {
+	if forwarded := env.ForwardedURL(dashboardURL); forwarded != "" {
+		return forwarded
+	}
+	return dashboardURL
+}
+
+// joinPorts formats ports as "3000, 5000".
+func joinPorts(ports []int) string {
+	s := ""
+	for i, port := range ports {
+		if i > 0 {
+			s += ", "
+		}
+		s += strconv.Itoa(port)
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/devenv"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+func TestValidatePortVisibility(t *testing.T) {
+	for _, visibility := range []string{"", "private", "org", "public"} {
+		if err := validatePortVisibility(visibility); err != nil {
+			t.Errorf("validatePortVisibility(%q) = %v, want nil", visibility, err)
+		}
+	}
+	if err := validatePortVisibility("internal"); err == nil {
+		t.Error("validatePortVisibility(\"internal\") = nil, want an error")
+	}
+}
+
+func TestAddForwardedURLs(t *testing.T) {
+	env := devenv.Environment{Codespaces: true, CodespaceName: "fuzzy-space", PortForwardingDomain: "app.github.dev"}
+	summaries := []service.ServiceURLSummary{
+		{Name: "web", LocalURL: "http://localhost:3000"},
+		{Name: "worker"},
+	}
+
+	addForwardedURLs(env, summaries)
+
+	if got := summaries[0].ForwardedURL; got != "https://fuzzy-space-3000.app.github.dev" {
+		t.Errorf("web ForwardedURL = %q", got)
+	}
+	if got := summaries[1].ForwardedURL; got != "" {
+		t.Errorf("worker ForwardedURL = %q, want empty", got)
+	}
+}
+
+func TestShowRemoteForwarding(t *testing.T) {
+	summaries := []service.ServiceURLSummary{
+		{Name: "web", LocalURL: "http://localhost:3000"},
+		{Name: "api", LocalURL: "http://localhost:5000"},
+		{Name: "azure", LocalURL: "https://app.azurewebsites.net"},
+	}
+	codespace := devenv.Environment{Codespaces: true, CodespaceName: "fuzzy-space", PortForwardingDomain: "app.github.dev"}
+
+	tests := []struct {
+		name       string
+		env        devenv.Environment
+		visibility string
+		setErr     error
+		wantCalled bool
+	}{
+		{name: "codespace with visibility", env: codespace, visibility: "public", wantCalled: true},
+		{name: "codespace visibility fails", env: codespace, visibility: "org", setErr: errors.New("not authenticated"), wantCalled: true},
+		{name: "codespace without visibility", env: codespace},
+		{name: "codespace without a name", env: devenv.Environment{Codespaces: true}, visibility: "public"},
+		{name: "SSH", env: devenv.Environment{SSH: true, SSHHost: "10.0.0.5"}, visibility: "public"},
+		{name: "local", env: devenv.Environment{}, visibility: "public"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := setCodespacePortVisibility
+			defer func() { setCodespacePortVisibility = original }()
+
+			called := false
+			setCodespacePortVisibility = func(codespace, visibility string, ports []int) error {
+				called = true
+				if codespace != "fuzzy-space" || visibility != tt.visibility {
+					t.Errorf("setCodespacePortVisibility(%q, %q), want (%q, %q)", codespace, visibility, "fuzzy-space", tt.visibility)
+				}
+				if !reflect.DeepEqual(ports, []int{3000, 5000}) {
+					t.Errorf("ports = %v, want [3000 5000]", ports)
+				}
+				return tt.setErr
+			}
+
+			showRemoteForwarding(tt.env, summaries, tt.visibility)
+
+			if called != tt.wantCalled {
+				t.Errorf("setCodespacePortVisibility called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestDashboardDisplayURL(t *testing.T) {
+	codespace := devenv.Environment{Codespaces: true, CodespaceName: "fuzzy-space", PortForwardingDomain: "app.github.dev"}
+	if got := dashboardDisplayURL(codespace, "http://localhost:40123"); got != "https://fuzzy-space-40123.app.github.dev" {
+		t.Errorf("dashboardDisplayURL() in a codespace = %q", got)
+	}
+	if got := dashboardDisplayURL(devenv.Environment{}, "http://localhost:40123"); got != "http://localhost:40123" {
+		t.Errorf("dashboardDisplayURL() locally = %q", got)
+	}
+}
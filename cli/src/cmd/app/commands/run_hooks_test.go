@@ -2,9 +2,11 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -605,3 +607,94 @@ services:
 		t.Errorf("Expected platform-specific hook to succeed, got error: %v", err)
 	}
 }
+
+func TestBuildServiceEventEnvironmentVariables(t *testing.T) {
+	info := service.ServiceEventInfo{
+		Event:          service.EventServiceHealthChanged,
+		Service:        "api",
+		Port:           8080,
+		PID:            1234,
+		Health:         service.ServiceHealthUnhealthy,
+		PreviousHealth: service.ServiceHealthHealthy,
+		Error:          errors.New("connection refused"),
+	}
+
+	envVars := buildServiceEventEnvironmentVariables(info)
+
+	expected := []string{
+		executor.EnvEvent + "=servicehealthchanged",
+		executor.EnvServiceName + "=api",
+		executor.EnvServicePort + "=8080",
+		executor.EnvServicePID + "=1234",
+		executor.EnvServiceHealth + "=unhealthy",
+		executor.EnvServicePreviousHealth + "=healthy",
+		executor.EnvServiceError + "=connection refused",
+	}
+	for _, want := range expected {
+		if !slices.Contains(envVars, want) {
+			t.Errorf("Expected %s in %v", want, envVars)
+		}
+	}
+
+	// Health changes don't change the service's status
+	for _, envVar := range envVars {
+		if strings.HasPrefix(envVar, executor.EnvServiceStatus+"=") {
+			t.Errorf("Expected no status for a health change, got %s", envVar)
+		}
+	}
+
+	// Optional values are omitted when unknown
+	envVars = buildServiceEventEnvironmentVariables(service.ServiceEventInfo{Event: service.EventServiceStarting, Service: "worker"})
+	if len(envVars) != 3 || !slices.Contains(envVars, executor.EnvServiceStatus+"=starting") {
+		t.Errorf("Expected only event, service name and status, got %v", envVars)
+	}
+
+	envVars = buildServiceEventEnvironmentVariables(service.ServiceEventInfo{Event: service.EventServiceCrashed, Service: "worker", Error: errors.New("exit status 1")})
+	for _, want := range []string{executor.EnvEvent + "=servicecrashed", executor.EnvServiceStatus + "=error", executor.EnvServiceError + "=exit status 1"} {
+		if !slices.Contains(envVars, want) {
+			t.Errorf("Expected %s in %v", want, envVars)
+		}
+	}
+}
+
+func TestExecutePredepsHook(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping hook execution test in short mode")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping POSIX shell test on Windows")
+	}
+
+	tmpDir := t.TempDir()
+	azureYamlContent := `name: test-app
+
+hooks:
+  predeps:
+    run: echo "$AZD_APP_PROJECT_NAME" > predeps.txt
+    shell: sh
+
+services:
+  web:
+    language: TypeScript
+    project: .
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "azure.yaml"), []byte(azureYamlContent), 0644); err != nil {
+		t.Fatalf("Failed to create azure.yaml: %v", err)
+	}
+
+	if err := executePredepsHook(tmpDir); err != nil {
+		t.Fatalf("Expected predeps hook to succeed, got error: %v", err)
+	}
+	output, err := os.ReadFile(filepath.Join(tmpDir, "predeps.txt"))
+	if err != nil {
+		t.Fatalf("Expected predeps hook to run in the project directory: %v", err)
+	}
+	if strings.TrimSpace(string(output)) != "test-app" {
+		t.Errorf("Expected project name in hook environment, got %q", output)
+	}
+
+	// Without azure.yaml there is nothing to run
+	if err := executePredepsHook(t.TempDir()); err != nil {
+		t.Errorf("Expected no error without azure.yaml, got: %v", err)
+	}
+}
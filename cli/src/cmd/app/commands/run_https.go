@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/devcert"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+)
+
+// configureHTTPS creates the development certificate when a service uses protocol: https or the
+// dashboard has https: true, then passes it to those services and the dashboard.
+func configureHTTPS(azureYaml *service.AzureYaml, cwd string, runtimes []*service.ServiceRuntime) error {
+	dashboardHTTPS := azureYaml.Dashboard != nil && azureYaml.Dashboard.HTTPS
+
+	var httpsRuntimes []*service.ServiceRuntime
+	for _, rt := range runtimes {
+		if rt.IsHTTPS() {
+			httpsRuntimes = append(httpsRuntimes, rt)
+		}
+	}
+	if len(httpsRuntimes) == 0 && !dashboardHTTPS {
+		return nil
+	}
+
+	cert, err := devcert.EnsureDefault()
+	if err != nil {
+		return fmt.Errorf("failed to create development certificate: %w", err)
+	}
+	if !cert.Trusted() {
+		cliout.Warning("Using a self-signed development certificate; browsers will show a security warning")
+		cliout.Item("Run 'mkcert -install' or 'dotnet dev-certs https --trust', then delete %s to use a trusted certificate", cert.CertFile)
+	}
+
+	for _, rt := range httpsRuntimes {
+		service.InjectCertEnvironment(rt, cert)
+	}
+
+	if dashboardHTTPS {
+		tlsConfig, err := cert.TLSConfig()
+		if err != nil {
+			return err
+		}
+		dashboard.GetServer(cwd).SetTLSConfig(tlsConfig)
+	}
+	return nil
+}
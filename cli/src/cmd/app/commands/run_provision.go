@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/jongio/azd-app/cli/src/internal/azdenv"
+	"github.com/jongio/azd-app/cli/src/internal/tracing"
+	"github.com/jongio/azd-core/cliout"
+
+	"go.opentelemetry.io/otel/attribute"
+	"gopkg.in/yaml.v3"
+)
+
+// Modes of --with-provision.
+const (
+	provisionModeAuto   = "auto"   // Provision when the outputs are missing or older than the infrastructure
+	provisionModeAlways = "always" // Provision on every run
+)
+
+// defaultInfraPath is where azd looks for the infrastructure when azure.yaml doesn't set infra.path.
+const defaultInfraPath = "infra"
+
+// runAzdProvision runs `azd provision` in projectDir for the azd environment envName ("" for
+// the current one), attached to the terminal so prompts and progress are shown.
+// This is a variable to allow test overrides.
+var runAzdProvision = func(ctx context.Context, projectDir, envName string) error {
+	args := []string{"provision"}
+	if envName != "" {
+		args = append(args, "-e", envName)
+	}
+	// #nosec G204 -- args are the provision command and the azd environment name from --azd-env or .azure
+	cmd := exec.CommandContext(ctx, "azd", args...)
+	cmd.Dir = projectDir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("azd provision failed: %w", err)
+	}
+	return nil
+}
+
+// validateWithProvision validates the --with-provision value; "" doesn't provision.
+func validateWithProvision(mode string) error {
+	switch mode {
+	case "", provisionModeAuto, provisionModeAlways:
+		return nil
+	default:
+		return fmt.Errorf("invalid --with-provision value: %s (must be '%s' or '%s')", mode, provisionModeAuto, provisionModeAlways)
+	}
+}
+
+// infraDir returns the infrastructure directory of the project: infra.path in azure.yaml, or infra.
+func infraDir(azureYamlPath string) string {
+	projectDir := filepath.Dir(azureYamlPath)
+	infraPath := defaultInfraPath
+
+	// #nosec G304 -- path is the project's azure.yaml
+	if data, err := os.ReadFile(azureYamlPath); err == nil {
+		var project struct {
+			Infra struct {
+				Path string `yaml:"path"`
+			} `yaml:"infra"`
+		}
+		if yaml.Unmarshal(data, &project) == nil && project.Infra.Path != "" {
+			infraPath = project.Infra.Path
+		}
+	}
+
+	if filepath.IsAbs(infraPath) {
+		return infraPath
+	}
+	return filepath.Join(projectDir, infraPath)
+}
+
+// provisionBeforeRun runs `azd provision` for --with-provision and waits for it, so the services
+// started next get the fresh outputs from the azd environment. In auto mode provisioning is
+// skipped while the outputs are newer than the infrastructure.
+func provisionBeforeRun(ctx context.Context, azureYamlPath string) error {
+	if runWithProvision == "" {
+		return nil
+	}
+	projectDir := filepath.Dir(azureYamlPath)
+
+	envName := runAzdEnv
+	if envName == "" {
+		envName = azdenv.CurrentName(projectDir)
+	}
+
+	reason := "--with-provision=" + provisionModeAlways
+	if runWithProvision == provisionModeAuto && envName != "" {
+		stale, staleReason := azdenv.OutputsStale(projectDir, envName, infraDir(azureYamlPath))
+		if !stale {
+			cliout.Info("Outputs of azd environment '%s' are up to date, skipping azd provision", envName)
+			return nil
+		}
+		reason = staleReason
+	} else if runWithProvision == provisionModeAuto {
+		reason = "no azd environment selected"
+	}
+
+	if runDryRun {
+		cliout.Info("Would run azd provision: %s", reason)
+		return nil
+	}
+
+	cliout.Step("☁️", "Running azd provision (%s)", reason)
+	ctx, span := tracing.Start(ctx, "provision", attribute.String("azd.app.provision_mode", runWithProvision))
+	err := runAzdProvision(ctx, projectDir, envName)
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+	cliout.Success("Provisioning complete")
+	return nil
+}
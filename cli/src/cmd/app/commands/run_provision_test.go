@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/azdenv"
+)
+
+func TestValidateWithProvision(t *testing.T) {
+	for _, mode := range []string{"", provisionModeAuto, provisionModeAlways} {
+		if err := validateWithProvision(mode); err != nil {
+			t.Errorf("validateWithProvision(%q) error = %v", mode, err)
+		}
+	}
+	if err := validateWithProvision("sometimes"); err == nil {
+		t.Error("validateWithProvision(\"sometimes\") should fail")
+	}
+}
+
+func TestInfraDir(t *testing.T) {
+	projectDir := t.TempDir()
+	azureYamlPath := filepath.Join(projectDir, "azure.yaml")
+
+	if err := os.WriteFile(azureYamlPath, []byte("name: app\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := infraDir(azureYamlPath), filepath.Join(projectDir, "infra"); got != want {
+		t.Errorf("infraDir() = %q, want %q", got, want)
+	}
+
+	if err := os.WriteFile(azureYamlPath, []byte("name: app\ninfra:\n  path: deploy/bicep\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := infraDir(azureYamlPath), filepath.Join(projectDir, "deploy", "bicep"); got != want {
+		t.Errorf("infraDir() = %q, want %q", got, want)
+	}
+}
+
+func TestProvisionBeforeRun(t *testing.T) {
+	projectDir := t.TempDir()
+	azureYamlPath := filepath.Join(projectDir, "azure.yaml")
+	if err := os.WriteFile(azureYamlPath, []byte("name: app\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	bicep := filepath.Join(projectDir, "infra", "main.bicep")
+	if err := os.MkdirAll(filepath.Dir(bicep), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bicep, []byte("output SERVICE_API_URL string = api.outputs.uri\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(azdenv.EnvName, "dev")
+
+	var provisioned []string
+	origProvision := runAzdProvision
+	runAzdProvision = func(_ context.Context, dir, envName string) error {
+		provisioned = append(provisioned, envName)
+		if err := os.MkdirAll(azdenv.Dir(dir, envName), 0750); err != nil {
+			return err
+		}
+		return os.WriteFile(azdenv.Path(dir, envName), []byte("SERVICE_API_URL=\"https://api.example.com\"\n"), 0600)
+	}
+	defer func() {
+		runAzdProvision = origProvision
+		runWithProvision = ""
+		runDryRun = false
+	}()
+
+	// Without the flag nothing is provisioned
+	runWithProvision = ""
+	if err := provisionBeforeRun(context.Background(), azureYamlPath); err != nil || len(provisioned) != 0 {
+		t.Fatalf("provisionBeforeRun() = %v, provisioned %v; want nothing", err, provisioned)
+	}
+
+	// Dry run only reports that the never-provisioned environment would be provisioned
+	runWithProvision = provisionModeAuto
+	runDryRun = true
+	if err := provisionBeforeRun(context.Background(), azureYamlPath); err != nil || len(provisioned) != 0 {
+		t.Fatalf("provisionBeforeRun() dry run = %v, provisioned %v; want nothing", err, provisioned)
+	}
+	runDryRun = false
+
+	// Auto provisions an environment that azd env new created but never provisioned
+	if err := os.MkdirAll(azdenv.Dir(projectDir, "dev"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(azdenv.Path(projectDir, "dev"), []byte("AZURE_ENV_NAME=\"dev\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := provisionBeforeRun(context.Background(), azureYamlPath); err != nil {
+		t.Fatalf("provisionBeforeRun() error = %v", err)
+	}
+	if len(provisioned) != 1 || provisioned[0] != "dev" {
+		t.Fatalf("provisioned = %v, want [dev]", provisioned)
+	}
+
+	// Auto skips outputs newer than the infrastructure
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(bicep, past, past); err != nil {
+		t.Fatal(err)
+	}
+	if err := provisionBeforeRun(context.Background(), azureYamlPath); err != nil || len(provisioned) != 1 {
+		t.Fatalf("provisionBeforeRun() = %v, provisioned %v; want up-to-date outputs skipped", err, provisioned)
+	}
+
+	// Always provisions anyway
+	runWithProvision = provisionModeAlways
+	if err := provisionBeforeRun(context.Background(), azureYamlPath); err != nil || len(provisioned) != 2 {
+		t.Fatalf("provisionBeforeRun() = %v, provisioned %v; want provisioned again", err, provisioned)
+	}
+
+	// A failed provision stops the run
+	runAzdProvision = func(context.Context, string, string) error { return errors.New("azd provision failed: exit status 1") }
+	if err := provisionBeforeRun(context.Background(), azureYamlPath); err == nil {
+		t.Fatal("provisionBeforeRun() should fail when azd provision fails")
+	}
+}
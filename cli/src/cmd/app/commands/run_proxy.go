@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/proxy"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/registry"
+)
+
+// proxyShutdownTimeout bounds how long in-flight proxied requests may take at shutdown.
+const proxyShutdownTimeout = 5 * time.Second
+
+// newRunProxy creates the reverse proxy from the proxy section of azure.yaml when --proxy is set.
+// It returns nil without --proxy. Routes must name services defined in azure.yaml.
+func newRunProxy(azureYaml *service.AzureYaml, projectDir string) (*proxy.Proxy, error) {
+	if !runProxy {
+		return nil, nil
+	}
+	if azureYaml.Proxy == nil || len(azureYaml.Proxy.Routes) == 0 {
+		return nil, fmt.Errorf("--proxy requires routes in the proxy section of azure.yaml")
+	}
+
+	routes := make([]proxy.Route, 0, len(azureYaml.Proxy.Routes))
+	for _, route := range azureYaml.Proxy.Routes {
+		if _, exists := azureYaml.Services[route.Service]; !exists {
+			return nil, fmt.Errorf("proxy route '%s' references service '%s', which is not defined in azure.yaml", route.Path, route.Service)
+		}
+		routes = append(routes, proxy.Route{Path: route.Path, Service: route.Service, StripPrefix: route.StripPrefix})
+	}
+
+	reg := registry.GetRegistry(projectDir)
+	return proxy.New(routes, func(name string) (*url.URL, error) {
+		entry, exists := reg.GetService(name)
+		if !exists || entry.URL == "" {
+			return nil, fmt.Errorf("service '%s' is not running", name)
+		}
+		return url.Parse(entry.URL)
+	})
+}
+
+// startRunProxy starts the proxy on the configured port and prints its routes. Until ctx is
+// canceled, services restarted on another port are reported, and dashboard clients notified.
+func startRunProxy(ctx context.Context, p *proxy.Proxy, config *service.ProxyConfig, projectDir string) error {
+	port := config.Port
+	if port == 0 {
+		port = proxy.DefaultPort
+	}
+	proxyURL, err := p.Start(port)
+	if err != nil {
+		return err
+	}
+
+	cliout.Plain("  Proxy      %s", proxyURL)
+	for _, route := range p.Routes() {
+		cliout.Item("%s → %s", route.Path, route.Service)
+	}
+
+	go p.Watch(ctx, proxy.DefaultWatchInterval, func(change proxy.RouteChange) {
+		announceRouteChange(projectDir, proxyURL, change)
+	})
+	return nil
+}
+
+// announceRouteChange reports a service that the proxy now forwards to on another port.
+// Its URL through the proxy is unchanged, so nothing using it has to be restarted.
+func announceRouteChange(projectDir, proxyURL string, change proxy.RouteChange) {
+	publicURL := strings.TrimSuffix(proxyURL, "/") + strings.TrimSuffix(change.Route.Path, "/")
+	cliout.Info("Service '%s' moved to port %s; %s still reaches it", change.Route.Service, change.To.Port(), publicURL)
+
+	backendPort, _ := strconv.Atoi(change.To.Port())
+	if err := dashboard.GetServer(projectDir).BroadcastRouteChange(change.Route.Service, publicURL, backendPort); err != nil {
+		slog.Debug("failed to broadcast proxy route change", "error", err)
+	}
+}
+
+// stopRunProxy shuts the proxy down.
+func stopRunProxy(p *proxy.Proxy) {
+	ctx, cancel := context.WithTimeout(context.Background(), proxyShutdownTimeout)
+	defer cancel()
+	if err := p.Stop(ctx); err != nil {
+		cliout.Warning("Failed to stop proxy: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/proxy"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/registry"
+)
+
+func TestNewRunProxy(t *testing.T) {
+	azureYaml := &service.AzureYaml{
+		Services: map[string]service.Service{"api": {}, "web": {}},
+		Proxy: &service.ProxyConfig{Routes: []service.ProxyRoute{
+			{Path: "/api", Service: "api", StripPrefix: true},
+			{Path: "/", Service: "web"},
+		}},
+	}
+
+	runProxy = false
+	if p, err := newRunProxy(azureYaml, t.TempDir()); p != nil || err != nil {
+		t.Errorf("newRunProxy() without --proxy = %v, %v, want nil", p, err)
+	}
+
+	runProxy = true
+	t.Cleanup(func() { runProxy = false })
+
+	tests := []struct {
+		name    string
+		config  *service.ProxyConfig
+		wantErr string
+	}{
+		{name: "no proxy section", config: nil, wantErr: "requires routes"},
+		{name: "unknown service", config: &service.ProxyConfig{Routes: []service.ProxyRoute{{Path: "/", Service: "frontend"}}}, wantErr: "service 'frontend', which is not defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid := &service.AzureYaml{Services: azureYaml.Services, Proxy: tt.config}
+			if _, err := newRunProxy(invalid, t.TempDir()); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("newRunProxy() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Requests are routed to the URL of the service in the registry
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "api "+r.URL.Path)
+	}))
+	defer backend.Close()
+
+	projectDir := t.TempDir()
+	if err := registry.GetRegistry(projectDir).Register(&registry.ServiceRegistryEntry{Name: "api", URL: backend.URL}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := newRunProxy(azureYaml, projectDir)
+	if err != nil {
+		t.Fatalf("newRunProxy() error = %v", err)
+	}
+
+	for path, want := range map[string]struct {
+		status int
+		body   string
+	}{
+		"/api/orders": {http.StatusOK, "api /orders"},
+		"/":           {http.StatusBadGateway, "service 'web' is not running"},
+	} {
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want.status || !strings.Contains(rec.Body.String(), want.body) {
+			t.Errorf("GET %s = %d %q, want %d %q", path, rec.Code, rec.Body.String(), want.status, want.body)
+		}
+	}
+}
+
+func TestAnnounceRouteChange(t *testing.T) {
+	projectDir := t.TempDir()
+	var events []dashboard.BroadcastEvent
+	unsubscribe := dashboard.GetServer(projectDir).Subscribe(func(e dashboard.BroadcastEvent) {
+		events = append(events, e)
+	})
+	defer unsubscribe()
+
+	from, _ := url.Parse("http://localhost:3100")
+	to, _ := url.Parse("http://localhost:3105")
+	announceRouteChange(projectDir, "http://localhost:9000", proxy.RouteChange{
+		Route: proxy.Route{Path: "/api", Service: "api"},
+		From:  from,
+		To:    to,
+	})
+
+	if len(events) != 1 || events[0].Type != "service-route" {
+		t.Fatalf("events = %+v, want one service-route event", events)
+	}
+	message := events[0].Message
+	if message["service"] != "api" || message["publicUrl"] != "http://localhost:9000/api" || message["backendPort"] != 3105 {
+		t.Errorf("service-route message = %v, want api at http://localhost:9000/api on port 3105", message)
+	}
+}
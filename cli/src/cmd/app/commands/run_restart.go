@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/registry"
+)
+
+// exitHandler returns the handler relaunching a service process according to its restart policy.
+func (r *serviceRestarter) exitHandler(ctx context.Context, name string, proc *service.ServiceProcess) exitHandler {
+	policy := proc.Runtime.Restart
+	if policy.GetPolicy() == service.RestartPolicyNo {
+		return nil
+	}
+
+	return func(exitCode int) bool {
+		// Build and task services are expected to finish, so a clean exit is never relaunched
+		mode := proc.Runtime.Mode
+		if exitCode == 0 && (mode == service.ServiceModeBuild || mode == service.ServiceModeTask) {
+			return false
+		}
+
+		// A process that ran for a while starts a new series of relaunches
+		if !proc.StartTime.IsZero() && time.Since(proc.StartTime) >= service.RestartResetAfter {
+			r.resetRelaunches(name)
+		}
+
+		attempt, ok := r.nextRelaunch(name, policy, exitCode)
+		if !ok {
+			if policy.MaxRetries > 0 && attempt >= policy.MaxRetries {
+				cliout.Error("%s exited with code %d, giving up after %d restarts", name, exitCode, attempt)
+			}
+			return false
+		}
+
+		delay := policy.Delay(attempt)
+		cliout.Warning("%s exited with code %d, restarting in %s (%s)", name, exitCode, delay, describeRelaunch(policy, attempt))
+		if err := registry.GetRegistry(r.projectDir).UpdateStatus(name, constants.StatusStarting); err != nil {
+			cliout.Warning("Failed to update status for %s: %v", name, err)
+		}
+
+		go r.relaunch(ctx, name, proc, policy, exitCode, delay)
+		return true
+	}
+}
+
+// relaunch restarts an exited service after delay, retrying with backoff while the restart
+// fails and the policy allows it. Nothing happens if the service was already restarted
+// (e.g. by a file change) or run is shutting down.
+func (r *serviceRestarter) relaunch(ctx context.Context, name string, exited *service.ServiceProcess, policy *service.RestartPolicy, exitCode int, delay time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		r.mu.Lock()
+		if r.processes[name] != exited {
+			r.mu.Unlock()
+			return
+		}
+		err := r.restartLocked(ctx, name, fmt.Sprintf("exited with code %d", exitCode))
+		r.mu.Unlock()
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+
+		attempt, ok := r.nextRelaunch(name, policy, exitCode)
+		if !ok {
+			cliout.Error("Giving up on %s after %d restarts", name, attempt)
+			return
+		}
+		delay = policy.Delay(attempt)
+		cliout.Info("Retrying %s in %s (%s)", name, delay, describeRelaunch(policy, attempt))
+	}
+}
+
+// nextRelaunch counts a relaunch of a service if its policy allows another one.
+// It returns the number of relaunches before this one.
+func (r *serviceRestarter) nextRelaunch(name string, policy *service.RestartPolicy, exitCode int) (int, bool) {
+	r.relaunchMu.Lock()
+	defer r.relaunchMu.Unlock()
+
+	attempt := r.relaunches[name]
+	if !policy.ShouldRestart(exitCode, attempt) {
+		return attempt, false
+	}
+	r.relaunches[name] = attempt + 1
+	return attempt, true
+}
+
+// resetRelaunches starts a new series of relaunches for a service.
+func (r *serviceRestarter) resetRelaunches(name string) {
+	r.relaunchMu.Lock()
+	defer r.relaunchMu.Unlock()
+	delete(r.relaunches, name)
+}
+
+// describeRelaunch describes a relaunch for log output, e.g. "restart 2 of 5".
+func describeRelaunch(policy *service.RestartPolicy, attempt int) string {
+	if policy.MaxRetries > 0 {
+		return fmt.Sprintf("restart %d of %d", attempt+1, policy.MaxRetries)
+	}
+	return fmt.Sprintf("restart %d", attempt+1)
+}
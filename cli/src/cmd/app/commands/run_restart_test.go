@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+func TestServiceRestarter_ExitHandler(t *testing.T) {
+	projectDir := t.TempDir()
+	policy := &service.RestartPolicy{Policy: service.RestartPolicyOnFailure, MaxRetries: 2}
+	proc := &service.ServiceProcess{
+		Name:      "api",
+		StartTime: time.Now(),
+		Runtime:   service.ServiceRuntime{Name: "api", Restart: policy},
+	}
+	result := &service.OrchestrationResult{Processes: map[string]*service.ServiceProcess{"api": proc}}
+	r := newServiceRestarter(result, nil, nil, projectDir, false)
+
+	// A canceled context keeps the scheduled relaunches from running
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	onExit := r.exitHandler(ctx, "api", proc)
+	if onExit == nil {
+		t.Fatal("exitHandler() = nil, want a handler for an on-failure policy")
+	}
+	if onExit(0) {
+		t.Error("clean exit should not be relaunched with on-failure")
+	}
+	if !onExit(1) || !onExit(1) {
+		t.Error("crashes should be relaunched up to maxRetries")
+	}
+	if onExit(1) {
+		t.Error("crash after maxRetries relaunches should not be relaunched")
+	}
+
+	// A process that ran long enough starts a new series
+	proc.StartTime = time.Now().Add(-service.RestartResetAfter)
+	if !onExit(1) {
+		t.Error("crash after a long run should be relaunched again")
+	}
+
+	noPolicy := &service.ServiceProcess{Name: "web"}
+	if r.exitHandler(ctx, "web", noPolicy) != nil {
+		t.Error("exitHandler() should be nil for services without a restart policy")
+	}
+}
@@ -4,15 +4,18 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"syscall"
 	"testing"
 	"time"
 
+	"github.com/jongio/azd-app/cli/src/internal/constants"
 	"github.com/jongio/azd-app/cli/src/internal/detector"
 	"github.com/jongio/azd-app/cli/src/internal/service"
 	"github.com/jongio/azd-core/browser"
+	"github.com/jongio/azd-core/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -146,11 +149,6 @@ func TestRunCommandFlagDefaults(t *testing.T) {
 		t.Fatal("--service flag not found")
 	}
 
-	verboseFlag := cmd.Flags().Lookup("verbose")
-	if verboseFlag == nil {
-		t.Fatal("--verbose flag not found")
-	}
-
 	dryRunFlag := cmd.Flags().Lookup("dry-run")
 	if dryRunFlag == nil {
 		t.Fatal("--dry-run flag not found")
@@ -261,7 +259,7 @@ func TestMonitorServicesUntilShutdown_StartupTimeout(t *testing.T) {
 	// Run monitoring in goroutine with timeout
 	done := make(chan error, 1)
 	go func() {
-		done <- monitorServicesUntilShutdown(result, tmpDir)
+		done <- monitorServicesUntilShutdown(result, tmpDir, nil)
 	}()
 
 	// Ensure cleanup if test exits early
@@ -345,7 +343,7 @@ func TestMonitorServicesUntilShutdown_SignalHandling(t *testing.T) {
 	}()
 
 	startTime := time.Now()
-	_ = monitorServicesUntilShutdown(result, tmpDir)
+	_ = monitorServicesUntilShutdown(result, tmpDir, nil)
 	elapsed := time.Since(startTime)
 
 	// Should complete reasonably quickly after signal
@@ -404,10 +402,18 @@ func TestMonitorServicesUntilShutdown_MultipleServices(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	err := shutdownAllServices(ctx, result.Processes)
+	results, err := shutdownAllServices(ctx, result.Processes)
 	if err != nil {
 		t.Logf("shutdownAllServices() returned: %v", err)
 	}
+	if len(results) != len(processes) {
+		t.Errorf("shutdownAllServices() returned %d results, want %d", len(results), len(processes))
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i-1].Service > results[i].Service {
+			t.Errorf("shutdownAllServices() results not sorted by service: %v", results)
+		}
+	}
 
 	t.Log("✓ Multiple services started and stopped successfully")
 }
@@ -458,7 +464,7 @@ func TestShutdownAllServices_WithContext(t *testing.T) {
 	defer cancel()
 
 	startTime := time.Now()
-	err := shutdownAllServices(ctx, result.Processes)
+	_, err := shutdownAllServices(ctx, result.Processes)
 	elapsed := time.Since(startTime)
 
 	// Log any shutdown errors for diagnostics
@@ -526,7 +532,7 @@ func TestShutdownAllServices_ContextTimeout(t *testing.T) {
 	defer cancel()
 
 	startTime := time.Now()
-	err = shutdownAllServices(ctx, result.Processes)
+	_, err = shutdownAllServices(ctx, result.Processes)
 	elapsed := time.Since(startTime)
 
 	// Expect errors due to timeout
@@ -600,7 +606,7 @@ func TestMonitorServices_RunsIndefinitely(t *testing.T) {
 	}()
 
 	startTime := time.Now()
-	_ = monitorServicesUntilShutdown(result, tmpDir)
+	_ = monitorServicesUntilShutdown(result, tmpDir, nil)
 	elapsed := time.Since(startTime)
 
 	// Should have run for approximately 5 seconds (not stop at 30 seconds or earlier)
@@ -650,7 +656,7 @@ func TestMonitorServiceProcess_CleanExit(t *testing.T) {
 	wg.Add(1)
 
 	// Should not panic or cause issues
-	monitorServiceProcess(ctx, &wg, runtime.Name, process, tmpDir)
+	monitorServiceProcess(ctx, &wg, runtime.Name, process, tmpDir, nil)
 
 	// Wait should complete without hanging
 	waitDone := make(chan struct{})
@@ -704,7 +710,7 @@ func TestMonitorServiceProcess_CrashExit(t *testing.T) {
 	wg.Add(1)
 
 	// Should handle crash without panic
-	monitorServiceProcess(ctx, &wg, runtime.Name, process, tmpDir)
+	monitorServiceProcess(ctx, &wg, runtime.Name, process, tmpDir, nil)
 
 	waitDone := make(chan struct{})
 	go func() {
@@ -758,7 +764,7 @@ func TestMonitorServiceProcess_ContextCancellation(t *testing.T) {
 	wg.Add(1)
 
 	// Start monitoring
-	go monitorServiceProcess(ctx, &wg, runtime.Name, process, tmpDir)
+	go monitorServiceProcess(ctx, &wg, runtime.Name, process, tmpDir, nil)
 
 	// Cancel context after short delay (simulate Ctrl+C)
 	time.Sleep(500 * time.Millisecond)
@@ -779,6 +785,55 @@ func TestMonitorServiceProcess_ContextCancellation(t *testing.T) {
 	}
 }
 
+// TestMonitorServiceProcess_StoppedByRequest verifies that a service stopped on purpose
+// (e.g. by `azd app restart`) is not reported as crashed when it exits with an error code.
+func TestMonitorServiceProcess_StoppedByRequest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping process test in short mode")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh to exit with an error code")
+	}
+
+	tmpDir := t.TempDir()
+
+	rt := &service.ServiceRuntime{
+		Name:       "stopped-by-request",
+		WorkingDir: tmpDir,
+		Command:    "sh",
+		Args:       []string{"-c", "sleep 0.2; exit 1"},
+		Language:   "shell",
+		Port:       9103,
+	}
+
+	reg := registry.GetRegistry(tmpDir)
+	if err := reg.Register(&registry.ServiceRegistryEntry{Name: rt.Name, ProjectDir: tmpDir, Status: constants.StatusStopping}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	process, err := service.StartService(rt, map[string]string{}, tmpDir, nil)
+	if err != nil {
+		t.Fatalf("StartService() error = %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = service.GetLogManager(tmpDir).RemoveBuffer(rt.Name)
+		_ = reg.Unregister(rt.Name)
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	monitorServiceProcess(context.Background(), &wg, rt.Name, process, tmpDir, nil)
+
+	entry, exists := reg.GetService(rt.Name)
+	if !exists {
+		t.Fatal("expected service to remain registered")
+	}
+	if entry.Status != constants.StatusStopping {
+		t.Errorf("Status = %q, want %q (left for the stopping command to update)", entry.Status, constants.StatusStopping)
+	}
+}
+
 // TestProcessExit_DoesNotStopOtherServices verifies process isolation:
 // When one service crashes or exits, other services should continue running.
 // This is a key feature - individual service failures don't bring down the entire environment.
@@ -840,7 +895,7 @@ func TestProcessExit_DoesNotStopOtherServices(t *testing.T) {
 	// Run monitoring in a goroutine since it waits indefinitely for signals
 	done := make(chan error, 1)
 	go func() {
-		done <- monitorServicesUntilShutdown(result, tmpDir)
+		done <- monitorServicesUntilShutdown(result, tmpDir, nil)
 	}()
 
 	// Ensure cleanup if test exits
@@ -949,3 +1004,17 @@ func TestWebFlagBehavior(t *testing.T) {
 		})
 	}
 }
+
+func TestDescribeStopOutcome(t *testing.T) {
+	tests := map[string]string{
+		service.StopOutcomeGraceful: "stopped gracefully",
+		service.StopOutcomeForced:   "force-killed",
+		service.StopOutcomeSkipped:  "skipped",
+		service.StopOutcomeFailed:   "failed to stop",
+	}
+	for outcome, want := range tests {
+		if got := describeStopOutcome(outcome); got != want {
+			t.Errorf("describeStopOutcome(%q) = %q, want %q", outcome, got, want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-app/cli/src/internal/watcher"
+	"github.com/jongio/azd-core/cliout"
+)
+
+// serviceRestarter restarts individual services when their source files change (--watch),
+// when they fail their liveness checks, when they exit and have a restart policy, or when
+// `azd app restart` or the dashboard asks for it.
+// It owns its own copy of the process map because restarts replace processes while
+// other goroutines (e.g. startup timing) may still be reading the original map.
+type serviceRestarter struct {
+	mu              sync.Mutex
+	processes       map[string]*service.ServiceProcess
+	monitors        map[string]*serviceMonitor
+	envVars         map[string]string
+	logger          *service.ServiceLogger
+	projectDir      string
+	functionsParser *service.FunctionsOutputParser
+	watchFiles      bool
+
+	// relaunchMu guards relaunches. It is separate from mu because exit handlers run on
+	// monitor goroutines, which restart waits for while holding mu.
+	relaunchMu sync.Mutex
+	relaunches map[string]int // Consecutive relaunches of each service by its restart policy
+}
+
+// serviceMonitor tracks the monitor goroutine of a single service so it can be
+// stopped before the service is restarted (otherwise the restart would be reported as a crash).
+type serviceMonitor struct {
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+}
+
+// newServiceRestarter creates a restarter for the services in an orchestration result.
+// When watchFiles is set, services are also restarted when their source files change.
+func newServiceRestarter(result *service.OrchestrationResult, envVars map[string]string, logger *service.ServiceLogger, projectDir string, watchFiles bool) *serviceRestarter {
+	processes := make(map[string]*service.ServiceProcess, len(result.Processes))
+	for name, proc := range result.Processes {
+		processes[name] = proc
+	}
+	return &serviceRestarter{
+		processes:       processes,
+		monitors:        make(map[string]*serviceMonitor),
+		relaunches:      make(map[string]int),
+		envVars:         envVars,
+		logger:          logger,
+		projectDir:      projectDir,
+		functionsParser: result.FunctionsParser,
+		watchFiles:      watchFiles,
+	}
+}
+
+// setEnvVars replaces the environment variables given to restarted services.
+func (r *serviceRestarter) setEnvVars(envVars map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.envVars = envVars
+}
+
+// start starts a process monitor for every service and, when watching files, a file watcher
+// for every native service. Watchers are added to wg and run until ctx is canceled.
+func (r *serviceRestarter) start(ctx context.Context, wg *sync.WaitGroup) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, proc := range r.processes {
+		r.startMonitorLocked(ctx, name, proc)
+	}
+	if !r.watchFiles {
+		return
+	}
+
+	dirs := r.watchDirsLocked()
+	names := make([]string, 0, len(dirs))
+	for name := range dirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		dir := dirs[name]
+		w := watcher.New(dir, watcher.Options{Ignore: r.ignoreMatcher(dir, dirs)})
+		wg.Add(1)
+		go func(serviceName string) {
+			defer wg.Done()
+			defer func() {
+				if rec := recover(); rec != nil {
+					cliout.Error("Watcher panic recovered for %s: %v", serviceName, rec)
+				}
+			}()
+			w.Run(ctx, func(changed []string) {
+				if err := r.restart(ctx, serviceName, describeChangedFiles(changed)+" changed"); err != nil {
+					cliout.Info("Fix the error and save again to retry")
+				}
+			})
+		}(name)
+	}
+
+	if len(names) > 0 {
+		cliout.Info("Watching for changes: %s", strings.Join(names, ", "))
+	}
+}
+
+// watchDirsLocked returns the source directory of each service that can be restarted.
+// Container services are skipped because their sources are baked into the image, and
+// services in watch mode are skipped because their own tooling already reloads them.
+func (r *serviceRestarter) watchDirsLocked() map[string]string {
+	dirs := make(map[string]string)
+	for name, proc := range r.processes {
+		if proc == nil || proc.Runtime.Type == service.ServiceTypeContainer || proc.Runtime.WorkingDir == "" {
+			continue
+		}
+		if proc.Runtime.Mode == service.ServiceModeWatch {
+			continue
+		}
+		dir, err := filepath.Abs(proc.Runtime.WorkingDir)
+		if err != nil {
+			continue
+		}
+		dirs[name] = dir
+	}
+	return dirs
+}
+
+// ignoreMatcher builds the ignore rules for a service directory from its .gitignore, the
+// project's .gitignore, and the directories of other services nested inside it, so that
+// editing a nested service doesn't also restart its parent.
+func (r *serviceRestarter) ignoreMatcher(dir string, dirs map[string]string) *watcher.Matcher {
+	m := watcher.LoadGitignore(dir, r.projectDir)
+	for _, other := range dirs {
+		rel, err := filepath.Rel(dir, other)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		m.AddPatterns("", "/"+filepath.ToSlash(rel)+"/")
+	}
+	return m
+}
+
+// restart stops the monitor of a service, restarts the service and monitors the new process.
+// reason describes why the service is restarted, e.g. which files changed.
+func (r *serviceRestarter) restart(ctx context.Context, name string, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.restartLocked(ctx, name, reason)
+}
+
+// restartLocked is restart for callers holding r.mu.
+func (r *serviceRestarter) restartLocked(ctx context.Context, name string, reason string) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	proc := r.processes[name]
+	if proc == nil {
+		return nil
+	}
+
+	cliout.Info("%s: %s, restarting...", name, reason)
+
+	if m := r.monitors[name]; m != nil {
+		m.cancel()
+		m.wg.Wait()
+		delete(r.monitors, name)
+	}
+
+	newProc, err := service.RestartService(ctx, proc, r.envVars, r.logger, r.projectDir, r.functionsParser)
+	if err != nil {
+		cliout.Error("Failed to restart %s: %v", name, err)
+		return err
+	}
+
+	r.processes[name] = newProc
+	r.startMonitorLocked(ctx, name, newProc)
+	cliout.Success("Restarted %s", name)
+	return nil
+}
+
+// restartRequested restarts a service on request of `azd app start/restart` or the dashboard.
+// Unlike restart, it reports a service this session doesn't run and a failed restart.
+func (r *serviceRestarter) restartRequested(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.processes[name] == nil {
+		return fmt.Errorf("service '%s' is not run by this azd app run session", name)
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("azd app run is shutting down")
+	}
+	return r.restartLocked(ctx, name, "restart requested")
+}
+
+// wait waits for the process monitors to return once the context given to start is canceled.
+func (r *serviceRestarter) wait() {
+	r.mu.Lock()
+	monitors := make([]*serviceMonitor, 0, len(r.monitors))
+	for _, m := range r.monitors {
+		monitors = append(monitors, m)
+	}
+	r.mu.Unlock()
+
+	for _, m := range monitors {
+		m.wg.Wait()
+	}
+}
+
+// startMonitorLocked starts monitorServiceProcess for a service with its own cancelable context.
+func (r *serviceRestarter) startMonitorLocked(ctx context.Context, name string, proc *service.ServiceProcess) {
+	if proc == nil || proc.Process == nil {
+		return
+	}
+	monitorCtx, cancel := context.WithCancel(ctx)
+	m := &serviceMonitor{cancel: cancel, wg: &sync.WaitGroup{}}
+	m.wg.Add(1)
+	go monitorServiceProcess(monitorCtx, m.wg, name, proc, r.projectDir, r.exitHandler(ctx, name, proc))
+	r.monitors[name] = m
+}
+
+// snapshot returns the current processes, including restarted ones.
+func (r *serviceRestarter) snapshot() map[string]*service.ServiceProcess {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	processes := make(map[string]*service.ServiceProcess, len(r.processes))
+	for name, proc := range r.processes {
+		processes[name] = proc
+	}
+	return processes
+}
+
+// describeChangedFiles returns a short description of changed files for log output.
+func describeChangedFiles(changed []string) string {
+	const maxShown = 3
+	if len(changed) <= maxShown {
+		return strings.Join(changed, ", ")
+	}
+	return strings.Join(changed[:maxShown], ", ") + ", ..."
+}
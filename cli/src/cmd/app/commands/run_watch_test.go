@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+func TestServiceRestarter_WatchDirs(t *testing.T) {
+	projectDir := t.TempDir()
+	apiDir := filepath.Join(projectDir, "api")
+	if err := os.MkdirAll(apiDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &service.OrchestrationResult{Processes: map[string]*service.ServiceProcess{
+		"root":  {Name: "root", Runtime: service.ServiceRuntime{Type: service.ServiceTypeProcess, WorkingDir: projectDir}},
+		"api":   {Name: "api", Runtime: service.ServiceRuntime{Type: service.ServiceTypeProcess, WorkingDir: apiDir}},
+		"redis": {Name: "redis", Runtime: service.ServiceRuntime{Type: service.ServiceTypeContainer}},
+		"tsc":   {Name: "tsc", Runtime: service.ServiceRuntime{Type: service.ServiceTypeProcess, Mode: service.ServiceModeWatch, WorkingDir: apiDir}},
+	}}
+	r := newServiceRestarter(result, nil, nil, projectDir, true)
+
+	dirs := r.watchDirsLocked()
+	if len(dirs) != 2 || dirs["root"] != projectDir || dirs["api"] != apiDir {
+		t.Fatalf("watchDirsLocked() = %v, want root and api only", dirs)
+	}
+
+	// Changes inside a nested service belong to that service, not its parent.
+	m := r.ignoreMatcher(projectDir, dirs)
+	if !m.Match("api", true) {
+		t.Error("expected nested service directory to be ignored by the parent watcher")
+	}
+	if m.Match("main.go", false) {
+		t.Error("expected parent sources to be watched")
+	}
+}
+
+func TestDescribeChangedFiles(t *testing.T) {
+	if got := describeChangedFiles([]string{"a.go", "b.go"}); got != "a.go, b.go" {
+		t.Errorf("describeChangedFiles() = %q", got)
+	}
+	if got := describeChangedFiles([]string{"a", "b", "c", "d"}); got != "a, b, c, ..." {
+		t.Errorf("describeChangedFiles() = %q", got)
+	}
+}
@@ -97,7 +97,8 @@ func (c *ServiceController) GetAllServices() []string {
 func (c *ServiceController) filterServices(predicate func(status string) bool) []string {
 	var names []string
 	for _, entry := range c.registry.ListAll() {
-		if predicate(entry.Status) {
+		// Resources of an Aspire AppHost are started and stopped with it
+		if predicate(entry.Status) && !service.IsAspireResource(entry) {
 			names = append(names, entry.Name)
 		}
 	}
@@ -124,6 +125,9 @@ func (c *ServiceController) validateAndGetService(serviceName string) (*registry
 	if !exists {
 		return nil, newErrorResult(serviceName, fmt.Sprintf("service '%s' not found", serviceName))
 	}
+	if service.IsAspireResource(entry) {
+		return nil, newErrorResult(serviceName, fmt.Sprintf("'%s' is a resource of the Aspire AppHost '%s' and runs as long as it does", serviceName, service.MemberOwnerOf(serviceName)))
+	}
 
 	return entry, nil
 }
@@ -158,7 +162,7 @@ func (c *ServiceController) StartService(ctx context.Context, serviceName string
 			return fmt.Errorf("service '%s' is already running", serviceName)
 		}
 
-		return c.performStart(ctx, currentEntry, serviceName)
+		return c.performStart(ctx, serviceName)
 	})
 
 	return c.buildResult(serviceName, opResult, "start", constants.StatusRunning)
@@ -210,14 +214,21 @@ func (c *ServiceController) RestartService(ctx context.Context, serviceName stri
 				return fmt.Errorf("stop phase failed: %w", err)
 			}
 
+			// Give the old process time to free its port so the service keeps its port assignment
+			if currentEntry.Port > 0 && currentEntry.Type != service.ServiceTypeContainer && currentEntry.Type != service.ServiceTypeCompose {
+				pm := portmanager.GetPortManager(c.projectDir)
+				if !pm.WaitForPortRelease(currentEntry.Port, portmanager.PortReleaseTimeout) {
+					slog.Warn("port still in use after stop", "service", serviceName, "port", currentEntry.Port)
+				}
+			}
+
 			// Re-fetch entry after stop to ensure it still exists
-			currentEntry, exists = c.registry.GetService(serviceName)
-			if !exists {
+			if _, exists := c.registry.GetService(serviceName); !exists {
 				return fmt.Errorf("service '%s' was unregistered during stop phase", serviceName)
 			}
 		}
 
-		return c.performStart(ctx, currentEntry, serviceName)
+		return c.performStart(ctx, serviceName)
 	})
 
 	return c.buildResult(serviceName, opResult, "restart", constants.StatusRunning)
@@ -294,10 +305,10 @@ func (c *ServiceController) bulkOperation(
 }
 
 // performStart executes the start logic for a service.
-// It now accepts a context for proper cancellation support and implements
-// comprehensive port cleanup on failures to prevent resource leaks.
-// For container services, uses StartContainerService instead of StartService.
-func (c *ServiceController) performStart(ctx context.Context, entry *registry.ServiceRegistryEntry, serviceName string) error {
+// The service is started the way 'azd app run' starts it, with its assigned port and
+// environment; its held port is released if it fails to start.
+// It accepts a context for proper cancellation support.
+func (c *ServiceController) performStart(ctx context.Context, serviceName string) error {
 	// Check context before starting expensive operations
 	select {
 	case <-ctx.Done():
@@ -305,120 +316,14 @@ func (c *ServiceController) performStart(ctx context.Context, entry *registry.Se
 	default:
 	}
 
-	// Parse azure.yaml to get service configuration
-	azureYaml, err := service.ParseAzureYaml(c.projectDir)
-	if err != nil {
-		return fmt.Errorf("failed to parse azure.yaml: %w", err)
-	}
-
-	svcDef, exists := azureYaml.Services[serviceName]
-	if !exists {
-		return fmt.Errorf("service '%s' not found in azure.yaml", serviceName)
-	}
-
-	// Detect runtime
-	runtime, err := service.DetectServiceRuntime(serviceName, svcDef, map[int]bool{}, c.projectDir, "")
-	if err != nil {
-		return fmt.Errorf("failed to detect service runtime: %w", err)
-	}
-
-	// Track port for cleanup on failure (native services only)
-	assignedPort := runtime.Port
-
 	// Update to starting state
 	_ = c.registry.UpdateStatus(serviceName, constants.StatusStarting)
 
-	// Start the service - use container runner for container services
-	var process *service.ServiceProcess
-	if runtime.Type == service.ServiceTypeContainer {
-		process, err = service.StartContainerService(runtime, c.projectDir, true) // restartContainers=true for restart/start ops
-		if err == nil {
-			// Start container log collection
-			if logErr := service.StartContainerLogCollection(process, c.projectDir); logErr != nil {
-				slog.Warn("failed to start container log collection",
-					"service", serviceName,
-					"error", logErr)
-			}
-		}
-	} else {
-		// Load environment variables for native services
-		envVars := c.loadEnvVars(runtime)
-		functionsParser := service.NewFunctionsOutputParser(false)
-		process, err = service.StartService(runtime, envVars, c.projectDir, functionsParser)
-	}
-
-	if err != nil {
+	if _, err := service.StartConfiguredService(ctx, c.projectDir, serviceName, nil); err != nil {
 		_ = c.registry.UpdateStatus(serviceName, constants.StatusError)
-
-		// CRITICAL FIX: Clean up port assignment on failure to prevent resource leak
-		// Note: Port manager uses service name as key, not port number
-		// Only for native services - containers manage their own ports
-		if runtime.Type != service.ServiceTypeContainer && assignedPort > 0 {
-			pm := portmanager.GetPortManager(c.projectDir)
-			if releaseErr := pm.ReleasePort(serviceName); releaseErr != nil {
-				slog.Warn("failed to release port after start failure",
-					"service", serviceName,
-					"port", assignedPort,
-					"error", releaseErr)
-			}
-		}
-
 		return fmt.Errorf("failed to start service: %w", err)
 	}
-
-	// Validate process was created successfully
-	// Container services have ContainerID instead of Process.Pid
-	if process == nil {
-		_ = c.registry.UpdateStatus(serviceName, constants.StatusError)
-		return fmt.Errorf("service process not created")
-	}
-
-	if runtime.Type == service.ServiceTypeContainer {
-		if process.ContainerID == "" {
-			_ = c.registry.UpdateStatus(serviceName, constants.StatusError)
-			return fmt.Errorf("container not created")
-		}
-	} else {
-		if process.Process == nil {
-			_ = c.registry.UpdateStatus(serviceName, constants.StatusError)
-
-			// CRITICAL FIX: Clean up port assignment on validation failure
-			if assignedPort > 0 {
-				pm := portmanager.GetPortManager(c.projectDir)
-				if releaseErr := pm.ReleasePort(serviceName); releaseErr != nil {
-					slog.Warn("failed to release port after validation failure",
-						"service", serviceName,
-						"port", assignedPort,
-						"error", releaseErr)
-				}
-			}
-
-			return fmt.Errorf("native service process not created")
-		}
-	}
-
-	// Update registry with new process info
-	// Health will be determined dynamically by health checks
-	// Preserve Type and Mode for container services
-	updatedEntry := &registry.ServiceRegistryEntry{
-		Name:        serviceName,
-		ProjectDir:  entry.ProjectDir,
-		Port:        runtime.Port,
-		URL:         entry.URL,
-		AzureURL:    entry.AzureURL,
-		Language:    runtime.Language,
-		Framework:   runtime.Framework,
-		Status:      constants.StatusRunning,
-		StartTime:   time.Now(),
-		LastChecked: time.Now(),
-		Type:        runtime.Type,
-		Mode:        runtime.Mode,
-	}
-	// Set PID only for native processes
-	if process.Process != nil {
-		updatedEntry.PID = process.Process.Pid
-	}
-	return c.registry.Register(updatedEntry)
+	return nil
 }
 
 // performStop executes the stop logic for a service.
@@ -441,6 +346,15 @@ func (c *ServiceController) performStop(ctx context.Context, entry *registry.Ser
 		return c.stopContainerByName(serviceName)
 	}
 
+	// Compose services: take down the compose project the entry belongs to
+	if entry.Type == service.ServiceTypeCompose {
+		stack := service.MemberOwnerOf(serviceName)
+		if err := service.StopComposeProject(stack, service.DefaultStopTimeout); err != nil {
+			slog.Warn("failed to stop compose service", "service", stack, "error", err)
+		}
+		return c.registry.UpdateStatus(stack, constants.StatusStopped)
+	}
+
 	// Native processes: stop by PID and ensure port is freed
 	if entry.PID > 0 {
 		process, err := os.FindProcess(entry.PID)
@@ -492,21 +406,6 @@ func (c *ServiceController) stopContainerByName(serviceName string) error {
 	return c.registry.UpdateStatus(serviceName, constants.StatusStopped)
 }
 
-// loadEnvVars loads environment variables for the service.
-func (c *ServiceController) loadEnvVars(runtime *service.ServiceRuntime) map[string]string {
-	envVars := make(map[string]string)
-	for _, e := range os.Environ() {
-		pair := strings.SplitN(e, "=", 2)
-		if len(pair) == 2 {
-			envVars[pair[0]] = pair[1]
-		}
-	}
-	for k, v := range runtime.Env {
-		envVars[k] = v
-	}
-	return envVars
-}
-
 // printResult prints a single service control result to the console.
 func printResult(result *ServiceControlResult) {
 	if result.Success {
@@ -0,0 +1,288 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/cliout"
+
+	"github.com/spf13/cobra"
+)
+
+// sessionExitPollInterval is how often a stopped session is checked for having exited.
+const sessionExitPollInterval = 100 * time.Millisecond
+
+var (
+	sessionsStopAll bool
+	sessionsStopYes bool
+)
+
+// SessionsReport is the output of `azd app sessions`.
+type SessionsReport struct {
+	Sessions []service.ActiveSession `json:"sessions"`
+}
+
+// SessionStopResult is the outcome of stopping one session with `azd app sessions stop`.
+type SessionStopResult struct {
+	Project string `json:"project"`
+	PID     int    `json:"pid"`
+	Stopped bool   `json:"stopped"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NewSessionsCommand creates the sessions command.
+func NewSessionsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "List and stop the 'azd app run' sessions of all projects",
+		Long: `Lists the active 'azd app run' sessions of every project on this machine, in the
+foreground of another terminal or started with --detach, with their PID, services,
+dashboard URL and uptime.
+
+Each session registers itself in ~/.azd/app/sessions while it runs. Entries of
+sessions that exited without unregistering, e.g. after a crash, are removed when
+the registry is read.
+
+Examples:
+  # List active sessions
+  azd app sessions
+
+  # Stop the session of a project, by directory or directory name
+  azd app sessions stop ~/src/shop
+  azd app sessions stop shop
+
+  # Stop every session
+  azd app sessions stop --all`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runSessionsList,
+	}
+
+	cmd.AddCommand(newSessionsStopCmd())
+	return cmd
+}
+
+func newSessionsStopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop [project...]",
+		Short: "Stop the sessions of the given projects, or all sessions with --all",
+		Long: `Stops 'azd app run' sessions of other projects without switching to their directories.
+
+Projects are given by directory, or by directory name when it is unique. Each session
+is asked to shut down through its dashboard, which stops its services as if Ctrl+C had
+been pressed in it; a session whose dashboard can't be reached is sent a stop signal.`,
+		SilenceUsage: true,
+		RunE:         runSessionsStop,
+	}
+
+	cmd.Flags().BoolVar(&sessionsStopAll, "all", false, "Stop the sessions of all projects")
+	cmd.Flags().BoolVarP(&sessionsStopYes, "yes", "y", false, "Skip confirmation prompt for --all")
+	return cmd
+}
+
+// registerRunSession registers this 'azd app run' session in the session registry once its
+// dashboard has started, so 'azd app sessions' lists it. Failures only affect the listing.
+func registerRunSession(projectDir, dashboardURL string, processes map[string]*service.ServiceProcess) {
+	names := make([]string, 0, len(processes))
+	for name := range processes {
+		names = append(names, name)
+	}
+	if err := service.RegisterSession(projectDir, dashboardURL, names, isDetachedSession()); err != nil {
+		slog.Debug("failed to register session", "error", err)
+	}
+}
+
+func runSessionsList(cmd *cobra.Command, args []string) error {
+	cliout.CommandHeader("sessions", "List active sessions")
+
+	sessions, err := service.ListSessions()
+	if err != nil {
+		return err
+	}
+
+	if cliout.IsJSON() {
+		if sessions == nil {
+			sessions = []service.ActiveSession{}
+		}
+		return cliout.PrintJSON(SessionsReport{Sessions: sessions})
+	}
+	printSessions(sessions, time.Now())
+	return nil
+}
+
+// printSessions prints the active sessions as a table.
+func printSessions(sessions []service.ActiveSession, now time.Time) {
+	if len(sessions) == 0 {
+		cliout.Info("No active 'azd app run' sessions")
+		return
+	}
+
+	rows := make([]cliout.TableRow, 0, len(sessions))
+	for _, s := range sessions {
+		mode := "foreground"
+		if s.Detached {
+			mode = "detached"
+		}
+		row := cliout.TableRow{
+			"Project":   s.Project,
+			"PID":       strconv.Itoa(s.PID),
+			"Mode":      mode,
+			"Services":  strings.Join(s.Services, ", "),
+			"Dashboard": s.DashboardURL,
+			"Uptime":    "-",
+		}
+		if s.DashboardURL == "" {
+			row["Dashboard"] = "-"
+		}
+		if !s.StartedAt.IsZero() {
+			row["Uptime"] = formatInfoDuration(now.Sub(s.StartedAt))
+		}
+		rows = append(rows, row)
+	}
+	cliout.Table([]string{"Project", "PID", "Mode", "Services", "Dashboard", "Uptime"}, rows)
+	cliout.Newline()
+	cliout.Hint("azd app sessions stop <project>", "azd app sessions stop --all")
+}
+
+func runSessionsStop(cmd *cobra.Command, args []string) error {
+	cliout.CommandHeader("sessions stop", "Stop sessions")
+
+	if sessionsStopAll && len(args) > 0 {
+		return fmt.Errorf("specify projects or --all, not both")
+	}
+	if !sessionsStopAll && len(args) == 0 {
+		return fmt.Errorf("specify the projects whose sessions to stop, or --all")
+	}
+
+	sessions, err := service.ListSessions()
+	if err != nil {
+		return err
+	}
+
+	targets := sessions
+	if !sessionsStopAll {
+		if targets, err = selectSessions(sessions, args); err != nil {
+			return err
+		}
+	} else {
+		if len(sessions) == 0 {
+			if cliout.IsJSON() {
+				return cliout.PrintJSON([]SessionStopResult{})
+			}
+			cliout.Info("No active 'azd app run' sessions")
+			return nil
+		}
+		if !sessionsStopYes && !cliout.IsJSON() && !cliout.Confirm(fmt.Sprintf("Stop all %d session(s)?", len(sessions))) {
+			cliout.Info("Operation canceled")
+			return nil
+		}
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	results := make([]SessionStopResult, 0, len(targets))
+	failed := 0
+	for _, s := range targets {
+		result := SessionStopResult{Project: s.Project, PID: s.PID, Stopped: true}
+		if err := stopActiveSession(ctx, s); err != nil {
+			result.Stopped = false
+			result.Error = err.Error()
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if cliout.IsJSON() {
+		if err := cliout.PrintJSON(results); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			if r.Stopped {
+				cliout.ItemSuccess("Stopped %s (PID %d)", r.Project, r.PID)
+			} else {
+				cliout.ItemError("%s: %s", r.Project, r.Error)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to stop %d of %d session(s)", failed, len(results))
+	}
+	return nil
+}
+
+// selectSessions returns the sessions of the given projects, each given by directory or by
+// directory name when only one session's project has that name.
+func selectSessions(sessions []service.ActiveSession, projects []string) ([]service.ActiveSession, error) {
+	var selected []service.ActiveSession
+	for _, project := range projects {
+		var matches []service.ActiveSession
+		absProject, err := filepath.Abs(project)
+		if err != nil {
+			absProject = project
+		}
+		for _, s := range sessions {
+			if strings.EqualFold(filepath.Clean(s.Project), filepath.Clean(absProject)) {
+				matches = []service.ActiveSession{s}
+				break
+			}
+			if filepath.Base(s.Project) == project {
+				matches = append(matches, s)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("no active session for project '%s'", project)
+		case 1:
+			selected = append(selected, matches[0])
+		default:
+			dirs := make([]string, len(matches))
+			for i, m := range matches {
+				dirs[i] = m.Project
+			}
+			return nil, fmt.Errorf("several sessions match '%s', use the project directory: %s", project, strings.Join(dirs, ", "))
+		}
+	}
+	return selected, nil
+}
+
+// stopActiveSession asks a session to shut down through its dashboard, falling back to a stop
+// signal, and waits for its azd process to exit.
+func stopActiveSession(ctx context.Context, s service.ActiveSession) error {
+	if port := s.DashboardPort(); port > 0 {
+		if err := dashboard.NewClientWithPort(port).Shutdown(ctx); err != nil {
+			slog.Debug("failed to shut down session through its dashboard", "project", s.Project, "error", err)
+		} else if waitForSessionExit(ctx, s, sessionStopTimeout) {
+			return nil
+		}
+	}
+	return service.KillSession(s.PID, s.StartedAt, sessionStopTimeout)
+}
+
+// waitForSessionExit reports whether the session's azd process exits within timeout.
+func waitForSessionExit(ctx context.Context, s service.ActiveSession, timeout time.Duration) bool {
+	ticker := time.NewTicker(sessionExitPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for s.Running() {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-deadline:
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
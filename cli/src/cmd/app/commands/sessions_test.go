@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+func TestSelectSessions(t *testing.T) {
+	root := t.TempDir()
+	shop := service.ActiveSession{Project: filepath.Join(root, "shop"), PID: 1}
+	apiA := service.ActiveSession{Project: filepath.Join(root, "a", "api"), PID: 2}
+	apiB := service.ActiveSession{Project: filepath.Join(root, "b", "api"), PID: 3}
+	sessions := []service.ActiveSession{apiA, apiB, shop}
+
+	tests := []struct {
+		name     string
+		projects []string
+		wantPIDs []int
+		wantErr  string
+	}{
+		{name: "directory name", projects: []string{"shop"}, wantPIDs: []int{1}},
+		{name: "directory", projects: []string{filepath.Join(root, "a", "api")}, wantPIDs: []int{2}},
+		{name: "several projects", projects: []string{"shop", filepath.Join(root, "b", "api")}, wantPIDs: []int{1, 3}},
+		{name: "ambiguous name", projects: []string{"api"}, wantErr: "several sessions match 'api'"},
+		{name: "unknown project", projects: []string{"blog"}, wantErr: "no active session for project 'blog'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectSessions(sessions, tt.projects)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("selectSessions() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectSessions() error = %v", err)
+			}
+			if len(got) != len(tt.wantPIDs) {
+				t.Fatalf("selectSessions() = %+v, want PIDs %v", got, tt.wantPIDs)
+			}
+			for i, pid := range tt.wantPIDs {
+				if got[i].PID != pid {
+					t.Errorf("session %d PID = %d, want %d", i, got[i].PID, pid)
+				}
+			}
+		})
+	}
+}
+
+func TestRunSessionsStop_RequiresTarget(t *testing.T) {
+	origAll := sessionsStopAll
+	defer func() { sessionsStopAll = origAll }()
+
+	sessionsStopAll = false
+	if err := runSessionsStop(NewSessionsCommand(), nil); err == nil {
+		t.Error("runSessionsStop() without projects or --all should fail")
+	}
+	sessionsStopAll = true
+	if err := runSessionsStop(NewSessionsCommand(), []string{"shop"}); err == nil {
+		t.Error("runSessionsStop() with projects and --all should fail")
+	}
+}
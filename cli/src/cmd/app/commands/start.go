@@ -1,8 +1,10 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/jongio/azd-app/cli/src/internal/service"
 	"github.com/jongio/azd-core/cliout"
 
 	"github.com/spf13/cobra"
@@ -43,6 +45,7 @@ Examples:
 	}
 
 	cmd.Flags().StringVarP(&startService, "service", "s", "", "Service name(s) to start (comma-separated)")
+	registerServiceFlagCompletion(cmd)
 	cmd.Flags().BoolVar(&startAll, "all", false, "Start all stopped services")
 
 	return cmd
@@ -66,6 +69,15 @@ func runStart(cmd *cobra.Command, args []string) error {
 	ctx, _, cleanup := setupContextWithSignalHandling()
 	defer cleanup()
 
+	// Services of an active 'azd app run' session are started by that session
+	startOp := ctrl.StartService
+	if client := runningDashboardClient(ctx, ctrl.projectDir); client != nil {
+		startOp = ctrl.dashboardOperation(service.OpStart, client.StartService)
+	}
+	bulkStart := func(ctx context.Context, names []string) *BulkServiceControlResult {
+		return ctrl.bulkOperation(ctx, names, service.OpStart, startOp)
+	}
+
 	// Determine which services to start
 	var servicesToStart []string
 	if startAll {
@@ -82,5 +94,5 @@ func runStart(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	return executeServiceOperation(ctx, servicesToStart, ctrl.StartService, ctrl.BulkStart, "start")
+	return executeServiceOperation(ctx, servicesToStart, startOp, bulkStart, "start")
 }
@@ -0,0 +1,268 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-app/cli/src/internal/serviceinfo"
+	"github.com/jongio/azd-core/cliout"
+
+	"github.com/spf13/cobra"
+)
+
+// Health values reported by the status command.
+const (
+	statusHealthHealthy   = "healthy"
+	statusHealthDegraded  = "degraded" // port open, but the HTTP endpoint returned an error
+	statusHealthUnhealthy = "unhealthy"
+)
+
+// ServiceStatus is the status of one service as reported by `azd app status`.
+type ServiceStatus struct {
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	Health        string     `json:"health"`
+	HealthError   string     `json:"healthError,omitempty"`
+	PID           int        `json:"pid,omitempty"`
+	Port          int        `json:"port,omitempty"`
+	URL           string     `json:"url,omitempty"`
+	Language      string     `json:"language,omitempty"`
+	Framework     string     `json:"framework,omitempty"`
+	Type          string     `json:"type,omitempty"`
+	StartTime     *time.Time `json:"startTime,omitempty"`
+	UptimeSeconds int64      `json:"uptimeSeconds,omitempty"`
+}
+
+// StatusReport is the output of `azd app status`.
+type StatusReport struct {
+	Project   string          `json:"project"`
+	Dashboard string          `json:"dashboard,omitempty"` // Dashboard URL when an `azd app run` session is active
+	Detached  *DetachedStatus `json:"detached,omitempty"`  // Set when the session was started with --detach
+	Services  []ServiceStatus `json:"services"`
+}
+
+// DetachedStatus describes the background process of an `azd app run --detach` session.
+type DetachedStatus struct {
+	PID     int    `json:"pid"`
+	LogFile string `json:"logFile"`
+}
+
+// NewStatusCommand creates the status command.
+func NewStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the status and health of the project's services",
+		Long: `Lists the services of the project with their PID, port, framework, uptime and health.
+
+Runtime state comes from the running 'azd app run' session, including one started
+with --detach in another terminal. Ports fall back to the
+project's saved port assignments, and health is probed live: an HTTP request for HTTP
+services, a TCP connection for other services with a port, or a process check.
+
+Examples:
+  # Show service status
+  azd app status
+
+  # JSON output for scripting
+  azd app status --output json`,
+		SilenceUsage: true,
+		RunE:         runStatus,
+	}
+}
+
+// runStatus executes the status command.
+func runStatus(cmd *cobra.Command, args []string) error {
+	cliout.CommandHeader("status", "Show service status")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	report := buildStatusReport(cmd.Context(), cwd)
+
+	if cliout.IsJSON() {
+		return cliout.PrintJSON(report)
+	}
+	printStatusReport(report)
+	return nil
+}
+
+// buildStatusReport collects the services of a project and probes their health.
+func buildStatusReport(ctx context.Context, projectDir string) StatusReport {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	report := StatusReport{Project: projectDir, Services: []ServiceStatus{}}
+	if session, err := service.ReadSession(projectDir); err == nil && session != nil {
+		report.Detached = &DetachedStatus{PID: session.PID, LogFile: session.LogFile}
+	}
+
+	// Live state from the `azd app run` session, otherwise azure.yaml only
+	var services []*serviceinfo.ServiceInfo
+	if client, err := dashboard.NewClient(ctx, projectDir); err == nil {
+		if live, err := client.GetServices(ctx); err == nil {
+			services = live
+			if port := dashboard.GetDashboardPort(ctx, projectDir); port > 0 {
+				report.Dashboard = fmt.Sprintf("http://localhost:%d", port)
+			}
+		}
+	}
+	if services == nil {
+		var err error
+		services, err = serviceinfo.GetServiceInfo(projectDir)
+		if err != nil && !cliout.IsJSON() {
+			cliout.Warning("Failed to get service info: %v", err)
+		}
+	}
+
+	pm := portmanager.GetPortManager(projectDir)
+	for _, svc := range services {
+		status := newServiceStatus(svc)
+		if status.Port == 0 {
+			if port, ok := pm.GetAssignment(svc.Name); ok {
+				status.Port = port
+			}
+		}
+		report.Services = append(report.Services, status)
+	}
+
+	probeServiceStatuses(report.Services)
+
+	sort.Slice(report.Services, func(i, j int) bool { return report.Services[i].Name < report.Services[j].Name })
+	return report
+}
+
+// newServiceStatus converts service info into a status entry without health.
+func newServiceStatus(svc *serviceinfo.ServiceInfo) ServiceStatus {
+	status := ServiceStatus{
+		Name:      svc.Name,
+		Status:    statusUnknown,
+		Health:    statusUnknown,
+		Language:  svc.Language,
+		Framework: svc.Framework,
+	}
+	if svc.Local == nil {
+		return status
+	}
+
+	if svc.Local.Status != "" {
+		status.Status = svc.Local.Status
+	}
+	status.PID = svc.Local.PID
+	status.Port = svc.Local.Port
+	status.URL = svc.Local.URL
+	status.Type = svc.Local.ServiceType
+	if svc.Local.StartTime != nil && !svc.Local.StartTime.IsZero() && svc.Local.Status == statusRunning {
+		status.StartTime = svc.Local.StartTime
+		status.UptimeSeconds = int64(time.Since(*svc.Local.StartTime).Seconds())
+	}
+	return status
+}
+
+// probeServiceStatuses probes the health of every service in parallel.
+// Services that are known to be stopped are not probed.
+func probeServiceStatuses(statuses []ServiceStatus) {
+	var wg sync.WaitGroup
+	for i := range statuses {
+		if statuses[i].Status == statusStopped {
+			continue
+		}
+		wg.Add(1)
+		go func(s *ServiceStatus) {
+			defer wg.Done()
+			health, err := probeStatusHealth(s.Type, s.Port, s.PID)
+			s.Health = health
+			if err != nil {
+				s.HealthError = err.Error()
+			}
+		}(&statuses[i])
+	}
+	wg.Wait()
+}
+
+// probeStatusHealth checks a service once: HTTP for HTTP services (falling back to TCP to
+// tell a failing app from a closed port), TCP for other services with a port, and a process
+// check for services with only a PID.
+func probeStatusHealth(serviceType string, port, pid int) (string, error) {
+	switch {
+	case port > 0 && (serviceType == "" || serviceType == service.ServiceTypeHTTP):
+		httpErr := service.HTTPHealthCheck(port, "/")
+		if httpErr == nil {
+			return statusHealthHealthy, nil
+		}
+		if err := service.PortHealthCheck(port); err != nil {
+			return statusHealthUnhealthy, err
+		}
+		return statusHealthDegraded, httpErr
+	case port > 0:
+		if err := service.PortHealthCheck(port); err != nil {
+			return statusHealthUnhealthy, err
+		}
+		return statusHealthHealthy, nil
+	case pid > 0:
+		process, err := os.FindProcess(pid)
+		if err != nil {
+			return statusHealthUnhealthy, err
+		}
+		if err := service.ProcessHealthCheck(&service.ServiceProcess{Process: process}); err != nil {
+			return statusHealthUnhealthy, err
+		}
+		return statusHealthHealthy, nil
+	default:
+		return statusUnknown, nil
+	}
+}
+
+// printStatusReport prints the status report as a table.
+func printStatusReport(report StatusReport) {
+	cliout.Section("📦", fmt.Sprintf("Project: %s", report.Project))
+
+	if len(report.Services) == 0 {
+		cliout.Info("No services defined in azure.yaml")
+		return
+	}
+
+	rows := make([]cliout.TableRow, 0, len(report.Services))
+	for _, s := range report.Services {
+		row := cliout.TableRow{
+			"Service":   s.Name,
+			"Status":    s.Status,
+			"Health":    s.Health,
+			"PID":       "-",
+			"Port":      "-",
+			"Framework": s.Framework,
+			"Uptime":    "-",
+		}
+		if s.PID > 0 {
+			row["PID"] = strconv.Itoa(s.PID)
+		}
+		if s.Port > 0 {
+			row["Port"] = strconv.Itoa(s.Port)
+		}
+		if s.StartTime != nil {
+			row["Uptime"] = formatDuration(time.Duration(s.UptimeSeconds) * time.Second)
+		}
+		rows = append(rows, row)
+	}
+	cliout.Table([]string{"Service", "Status", "Health", "PID", "Port", "Framework", "Uptime"}, rows)
+
+	cliout.Newline()
+	if report.Dashboard != "" {
+		cliout.Label("Dashboard", report.Dashboard)
+	}
+	if report.Detached != nil {
+		cliout.Label("Session", fmt.Sprintf("detached (PID %d), output in %s", report.Detached.PID, report.Detached.LogFile))
+	}
+	if report.Dashboard == "" && report.Detached == nil {
+		cliout.Hint("No 'azd app run' session is active for this project")
+	}
+}
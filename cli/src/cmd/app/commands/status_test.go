@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/serviceinfo"
+)
+
+// serverPort returns the port of a test server.
+func serverPort(t *testing.T, server *httptest.Server) int {
+	t.Helper()
+	return server.Listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestProbeStatusHealth(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ok.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+
+	tests := []struct {
+		name        string
+		serviceType string
+		port        int
+		pid         int
+		want        string
+	}{
+		{"http healthy", "http", serverPort(t, ok), 0, statusHealthHealthy},
+		{"default type is http", "", serverPort(t, ok), 0, statusHealthHealthy},
+		{"http error with open port", "http", serverPort(t, failing), 0, statusHealthDegraded},
+		{"http closed port", "http", closedPort, 0, statusHealthUnhealthy},
+		{"tcp open port", "tcp", serverPort(t, failing), 0, statusHealthHealthy},
+		{"tcp closed port", "tcp", closedPort, 0, statusHealthUnhealthy},
+		{"process running", "process", 0, os.Getpid(), statusHealthHealthy},
+		{"nothing to probe", "process", 0, 0, statusUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := probeStatusHealth(tt.serviceType, tt.port, tt.pid)
+			if got != tt.want {
+				t.Errorf("probeStatusHealth() = %q (err %v), want %q", got, err, tt.want)
+			}
+			if (tt.want == statusHealthUnhealthy || tt.want == statusHealthDegraded) && err == nil {
+				t.Error("expected an error explaining the failed probe")
+			}
+		})
+	}
+}
+
+func TestNewServiceStatus(t *testing.T) {
+	started := time.Now().Add(-90 * time.Second)
+	svc := &serviceinfo.ServiceInfo{
+		Name:      "api",
+		Language:  "python",
+		Framework: "FastAPI",
+		Local: &serviceinfo.LocalServiceInfo{
+			Status:      statusRunning,
+			Port:        8000,
+			PID:         4242,
+			URL:         "http://localhost:8000",
+			ServiceType: "http",
+			StartTime:   &started,
+		},
+	}
+
+	status := newServiceStatus(svc)
+	if status.Name != "api" || status.Status != statusRunning || status.Port != 8000 || status.PID != 4242 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if status.Health != statusUnknown {
+		t.Errorf("Health = %q before probing, want %q", status.Health, statusUnknown)
+	}
+	if status.UptimeSeconds < 90 {
+		t.Errorf("UptimeSeconds = %d, want at least 90", status.UptimeSeconds)
+	}
+
+	// Without runtime state only the definition is known
+	status = newServiceStatus(&serviceinfo.ServiceInfo{Name: "web", Framework: "Vite"})
+	if status.Status != statusUnknown || status.StartTime != nil || status.Port != 0 {
+		t.Errorf("unexpected status without runtime state: %+v", status)
+	}
+}
+
+func TestProbeServiceStatuses_SkipsStopped(t *testing.T) {
+	statuses := []ServiceStatus{
+		{Name: "stopped", Status: statusStopped, Health: statusUnknown, PID: os.Getpid()},
+		{Name: "running", Status: statusRunning, Health: statusUnknown, Type: "process", PID: os.Getpid()},
+	}
+
+	probeServiceStatuses(statuses)
+
+	if statuses[0].Health != statusUnknown {
+		t.Errorf("stopped service Health = %q, want %q", statuses[0].Health, statusUnknown)
+	}
+	if statuses[1].Health != statusHealthHealthy {
+		t.Errorf("running service Health = %q, want %q", statuses[1].Health, statusHealthHealthy)
+	}
+}
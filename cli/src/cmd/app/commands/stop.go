@@ -1,8 +1,12 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
 	"github.com/jongio/azd-core/cliout"
 
 	"github.com/spf13/cobra"
@@ -27,6 +31,10 @@ Use --service to stop a specific service, or --all to stop all running services.
 Services are stopped gracefully with a timeout. If a service doesn't respond
 to graceful shutdown, it will be forcefully terminated.
 
+With --all, the dashboard of the 'azd app run' session is stopped too (which
+ends that session, including one started with 'azd app run --detach') and the
+services' port assignments are released.
+
 Examples:
   # Stop a specific service
   azd app stop --service api
@@ -44,7 +52,8 @@ Examples:
 	}
 
 	cmd.Flags().StringVarP(&stopService, "service", "s", "", "Service name(s) to stop (comma-separated)")
-	cmd.Flags().BoolVar(&stopAll, "all", false, "Stop all running services")
+	registerServiceFlagCompletion(cmd)
+	cmd.Flags().BoolVar(&stopAll, "all", false, "Stop all running services and the dashboard, and release their ports")
 	cmd.Flags().BoolVarP(&stopYes, "yes", "y", false, "Skip confirmation prompt for --all")
 
 	return cmd
@@ -68,25 +77,67 @@ func runStop(cmd *cobra.Command, args []string) error {
 	ctx, _, cleanup := setupContextWithSignalHandling()
 	defer cleanup()
 
-	// Determine which services to stop
-	var servicesToStop []string
-	if stopAll {
-		servicesToStop = ctrl.GetRunningServices()
-		if len(servicesToStop) == 0 {
-			if handleNoServicesCase(ctrl, "running", "stop") {
-				return nil
-			}
+	if !stopAll {
+		servicesToStop, err := parseServiceList(stopService)
+		if err != nil {
+			return err
 		}
-		if !confirmBulkOperation(len(servicesToStop), "stop", stopYes) {
-			cliout.Info("Operation canceled")
+		return executeServiceOperation(ctx, servicesToStop, ctrl.StopService, ctrl.BulkStop, "stop")
+	}
+
+	servicesToStop := ctrl.GetRunningServices()
+	if len(servicesToStop) == 0 {
+		// The session and its dashboard may still be running even when no service is
+		if stopDashboard(ctx, ctrl.projectDir) {
+			if cliout.IsJSON() {
+				return cliout.PrintJSON(BulkServiceControlResult{
+					Success: true,
+					Message: fmt.Sprintf("Stopped the 'azd app run' session of %s", ctrl.projectDir),
+					Results: []ServiceControlResult{},
+				})
+			}
 			return nil
 		}
-	} else {
-		servicesToStop, err = parseServiceList(stopService)
-		if err != nil {
-			return err
+		handleNoServicesCase(ctrl, "running", "stop")
+		return nil
+	}
+	if !confirmBulkOperation(len(servicesToStop), "stop", stopYes) {
+		cliout.Info("Operation canceled")
+		return nil
+	}
+
+	opErr := executeServiceOperation(ctx, servicesToStop, ctrl.StopService, ctrl.BulkStop, "stop")
+	releaseServicePorts(ctrl.projectDir, servicesToStop)
+	stopDashboard(ctx, ctrl.projectDir)
+	return opErr
+}
+
+// releaseServicePorts removes the port assignments of stopped services so their ports
+// are free for other projects. The next 'azd app run' assigns ports again.
+func releaseServicePorts(projectDir string, serviceNames []string) {
+	pm := portmanager.GetPortManager(projectDir)
+	for _, name := range serviceNames {
+		if err := pm.ReleasePort(name); err != nil {
+			slog.Debug("failed to release port", "service", name, "error", err)
 		}
 	}
+}
 
-	return executeServiceOperation(ctx, servicesToStop, ctrl.StopService, ctrl.BulkStop, "stop")
+// stopDashboard asks the 'azd app run' session that owns the project's dashboard to shut down.
+// A detached session whose dashboard can't be reached is stopped with a signal instead.
+// It reports whether a session was stopped; nothing happens if none is running for the project.
+func stopDashboard(ctx context.Context, projectDir string) bool {
+	client, err := dashboard.NewClient(ctx, projectDir)
+	if err != nil {
+		slog.Debug("no dashboard to stop", "error", err)
+		return stopDetachedSession(projectDir)
+	}
+	if err := client.Shutdown(ctx); err != nil {
+		slog.Debug("failed to stop dashboard", "error", err)
+		return stopDetachedSession(projectDir)
+	}
+	if !cliout.IsJSON() {
+		cliout.Success("Stopped the 'azd app run' session of %s and its dashboard", projectDir)
+	}
+	return true
 }
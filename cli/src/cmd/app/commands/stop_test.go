@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/azdconfig"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+func TestStopDashboard(t *testing.T) {
+	t.Setenv(azdconfig.EnvStateBackend, azdconfig.BackendMemory)
+
+	if stopDashboard(context.Background(), t.TempDir()) {
+		t.Error("stopDashboard() = true without a session, want false")
+	}
+
+	shutdown := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/api/shutdown" {
+			shutdown = true
+		}
+	}))
+	defer ts.Close()
+
+	projectDir := t.TempDir()
+	if err := service.WriteSession(projectDir, ts.URL, nil); err != nil {
+		t.Fatalf("WriteSession() error = %v", err)
+	}
+	defer service.RemoveSession(projectDir)
+
+	if !stopDashboard(context.Background(), projectDir) {
+		t.Error("stopDashboard() = false with a session, want true")
+	}
+	if !shutdown {
+		t.Error("stopDashboard() didn't shut down the session's dashboard")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/jongio/azd-app/cli/src/internal/output"
 	"github.com/jongio/azd-app/cli/src/internal/testing"
 	"github.com/jongio/azd-core/cliout"
 	"github.com/spf13/cobra"
@@ -26,7 +27,6 @@ type TestOptions struct {
 	FailFast        bool
 	Parallel        bool
 	Threshold       int
-	Verbose         bool
 	DryRun          bool
 	OutputFormat    string
 	OutputDir       string
@@ -68,12 +68,12 @@ func NewTestCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&opts.Type, "type", "t", "all", "Test type to run: unit, integration, e2e, or all")
 	cmd.Flags().BoolVarP(&opts.Coverage, "coverage", "c", false, "Generate code coverage reports")
 	cmd.Flags().StringVarP(&opts.ServiceFilter, "service", "s", "", "Run tests for specific service(s) (comma-separated)")
+	registerServiceFlagCompletion(cmd)
 	cmd.Flags().BoolVarP(&opts.Watch, "watch", "w", false, "Watch mode - re-run tests on file changes")
 	cmd.Flags().BoolVarP(&opts.UpdateSnapshots, "update-snapshots", "u", false, "Update test snapshots")
 	cmd.Flags().BoolVar(&opts.FailFast, "fail-fast", false, "Stop on first test failure")
 	cmd.Flags().BoolVarP(&opts.Parallel, "parallel", "p", true, "Run tests for services in parallel")
 	cmd.Flags().IntVar(&opts.Threshold, "threshold", 0, "Minimum coverage threshold (0-100)")
-	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Enable verbose test output")
 	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show what would be tested without running tests")
 	cmd.Flags().StringVar(&opts.OutputFormat, "output-format", "default", "Output format: default, json, junit, github")
 	cmd.Flags().StringVar(&opts.OutputDir, "output-dir", "./test-results", "Directory for test reports and coverage")
@@ -144,7 +144,7 @@ func runTests(opts *TestOptions) error {
 		FailFast:          opts.FailFast,
 		CoverageThreshold: float64(opts.Threshold),
 		OutputDir:         opts.OutputDir,
-		Verbose:           opts.Verbose,
+		Verbose:           output.IsVerbose(),
 		Timeout:           opts.Timeout,
 	}
 
@@ -32,7 +32,6 @@ func TestNewTestCommand(t *testing.T) {
 		"fail-fast",
 		"parallel",
 		"threshold",
-		"verbose",
 		"dry-run",
 		"output-format",
 		"output-dir",
@@ -261,12 +260,6 @@ func TestFlagShortcuts(t *testing.T) {
 		t.Error("Expected -w shortcut for --watch flag")
 	}
 
-	// Check verbose shortcut
-	verboseFlag := cmd.Flags().ShorthandLookup("v")
-	if verboseFlag == nil || verboseFlag.Name != "verbose" {
-		t.Error("Expected -v shortcut for --verbose flag")
-	}
-
 	// Check update-snapshots shortcut
 	snapshotsFlag := cmd.Flags().ShorthandLookup("u")
 	if snapshotsFlag == nil || snapshotsFlag.Name != "update-snapshots" {
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/selfupdate"
+	internalversion "github.com/jongio/azd-app/cli/src/internal/version"
+	"github.com/jongio/azd-core/cliout"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	upgradeCheck   bool
+	upgradeVersion string
+	upgradeForce   bool
+)
+
+// UpgradeResult is the output of `azd app upgrade`.
+type UpgradeResult struct {
+	CurrentVersion  string `json:"currentVersion"`
+	LatestVersion   string `json:"latestVersion"` // Version selected with --version, or the latest release
+	UpdateAvailable bool   `json:"updateAvailable"`
+	Upgraded        bool   `json:"upgraded"`
+	Path            string `json:"path,omitempty"` // Executable that was replaced
+}
+
+// NewUpgradeCommand creates the upgrade command.
+func NewUpgradeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade azd app to the latest release",
+		Long: `Checks the extension registry for the latest release of azd app and, when it is newer
+than the running version, downloads the build for this platform, verifies its checksum and
+replaces the installed executable.
+
+The executable azd installed (under ~/.azd/extensions, or AZD_CONFIG_DIR) is replaced and
+azd's record of the installed version is updated, so 'azd extension list' stays accurate.
+AZD_APP_REGISTRY_URL reads releases from another registry.
+
+Examples:
+  # Check whether a newer version is available
+  azd app upgrade --check
+
+  # Upgrade to the latest release
+  azd app upgrade
+
+  # Install a specific version, even if it is older
+  azd app upgrade --version 0.13.4 --force`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUpgrade(cmd.Context())
+		},
+	}
+
+	cmd.Flags().BoolVar(&upgradeCheck, "check", false, "Only check whether a newer version is available")
+	cmd.Flags().StringVar(&upgradeVersion, "version", "", "Install this version instead of the latest release")
+	cmd.Flags().BoolVar(&upgradeForce, "force", false, "Install even if the version isn't newer, or the running build is a dev build")
+
+	return cmd
+}
+
+// runUpgrade checks for and installs a newer release.
+func runUpgrade(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !cliout.IsJSON() {
+		cliout.CommandHeader("upgrade", "Upgrade azd app")
+	}
+
+	registry, err := selfupdate.FetchRegistry(ctx)
+	if err != nil {
+		return err
+	}
+	var release *selfupdate.Release
+	if upgradeVersion != "" {
+		release, err = registry.Find(upgradeVersion)
+	} else {
+		release, err = registry.Latest(false)
+	}
+	if err != nil {
+		return err
+	}
+	if upgradeVersion == "" {
+		// Refresh the cache of the startup check, so its hint matches what was found here
+		_ = selfupdate.SaveCheckState(selfupdate.CheckState{CheckedAt: time.Now().UTC(), LatestVersion: release.Version})
+	}
+
+	result := UpgradeResult{
+		CurrentVersion:  internalversion.Version,
+		LatestVersion:   release.Version,
+		UpdateAvailable: upgradeAvailable(internalversion.Version, release.Version),
+	}
+
+	if upgradeCheck || (!result.UpdateAvailable && !upgradeForce) {
+		return printUpgradeResult(result)
+	}
+
+	installation, err := selfupdate.FindInstallation()
+	if err != nil {
+		return err
+	}
+	artifact, err := release.Artifact()
+	if err != nil {
+		return err
+	}
+
+	if !cliout.IsJSON() {
+		cliout.Step("⬇️", "Downloading azd app %s for %s", release.Version, selfupdate.Platform())
+	}
+	binary, err := selfupdate.Download(ctx, artifact, filepath.Dir(installation.Path))
+	if err != nil {
+		return err
+	}
+	if err := selfupdate.Replace(installation.Path, binary); err != nil {
+		return err
+	}
+	if err := selfupdate.RecordVersion(release.Version); err != nil {
+		return err
+	}
+
+	result.Upgraded = true
+	result.Path = installation.Path
+	return printUpgradeResult(result)
+}
+
+// upgradeAvailable reports whether latest is newer than current. Dev builds have no
+// comparable version, so they are only upgraded with --force.
+func upgradeAvailable(current, latest string) bool {
+	if selfupdate.IsDevBuild(current) {
+		return false
+	}
+	return selfupdate.CompareVersions(latest, current) > 0
+}
+
+// printUpgradeResult prints the outcome of `azd app upgrade`.
+func printUpgradeResult(result UpgradeResult) error {
+	if cliout.IsJSON() {
+		return cliout.PrintJSON(result)
+	}
+
+	switch {
+	case result.Upgraded:
+		cliout.Success("Upgraded azd app %s → %s", result.CurrentVersion, result.LatestVersion)
+		cliout.Label("Installed", result.Path)
+	case result.UpdateAvailable:
+		cliout.Info("azd app %s is available (running %s)", result.LatestVersion, result.CurrentVersion)
+		cliout.Hint("Run 'azd app upgrade' to install it")
+	case selfupdate.IsDevBuild(result.CurrentVersion):
+		cliout.Info("Running a dev build; the latest release is %s", result.LatestVersion)
+		cliout.Hint("Run 'azd app upgrade --force' to replace it with the release")
+	case selfupdate.CompareVersions(result.LatestVersion, result.CurrentVersion) < 0:
+		cliout.Info("azd app %s is older than the running %s", result.LatestVersion, result.CurrentVersion)
+		cliout.Hint("Run with --force to install it anyway")
+	default:
+		cliout.Success("azd app %s is up to date", result.CurrentVersion)
+	}
+	return nil
+}
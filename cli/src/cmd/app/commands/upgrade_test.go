@@ -0,0 +1,21 @@
+package commands
+
+import "testing"
+
+func TestUpgradeAvailable(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"0.13.4", "0.13.5", true},
+		{"0.13.5", "0.13.5", false},
+		{"0.14.0", "0.13.5", false},
+		{"dev", "0.13.5", false},
+	}
+
+	for _, tt := range tests {
+		if got := upgradeAvailable(tt.current, tt.latest); got != tt.want {
+			t.Errorf("upgradeAvailable(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
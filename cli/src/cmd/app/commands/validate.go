@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/schema"
+	"github.com/jongio/azd-core/cliout"
+	"github.com/jongio/azd-core/security"
+
+	"github.com/spf13/cobra"
+)
+
+var validateStrict bool
+
+// ValidateResult is the output of `azd app validate`.
+type ValidateResult struct {
+	AzureYamlPath string         `json:"azureYamlPath"`
+	SchemaURL     string         `json:"schemaUrl"`
+	Valid         bool           `json:"valid"`
+	Errors        int            `json:"errors"`
+	Warnings      int            `json:"warnings"`
+	Issues        []schema.Issue `json:"issues"`
+}
+
+// NewValidateCommand creates the validate command.
+func NewValidateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [path]",
+		Short: "Validate azure.yaml against the azd app schema",
+		Long: `Validates azure.yaml against the azd app JSON schema, including its reqs, services,
+hooks and profiles, and reports each problem with its line and column.
+
+Values the schema doesn't allow are errors. Fields the schema doesn't describe are errors
+where the schema doesn't allow other fields, and warnings at the top level and in services,
+where azd and its extensions may add fields. Use --strict to fail on warnings too.
+
+azure.yaml is searched for in the current and parent directories unless a path is given.
+
+Examples:
+  # Validate the project's azure.yaml
+  azd app validate
+
+  # Validate a file, failing on unknown fields too
+  azd app validate ./samples/azure.yaml --strict
+
+  # Machine-readable output for editors and CI
+  azd app validate --output json`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return runValidate(path, validateStrict)
+		},
+	}
+
+	cmd.Flags().BoolVar(&validateStrict, "strict", false, "Treat warnings, such as unknown service fields, as errors")
+
+	return cmd
+}
+
+// runValidate validates the azure.yaml at path, or the project's azure.yaml when path is empty.
+func runValidate(path string, strict bool) error {
+	cliout.CommandHeader("validate", "Validate azure.yaml")
+
+	azureYamlPath, err := resolveAzureYamlToValidate(path)
+	if err != nil {
+		return err
+	}
+	// #nosec G304 -- Path validated by security.ValidatePath
+	data, err := os.ReadFile(azureYamlPath)
+	if err != nil {
+		return fmt.Errorf("failed to read azure.yaml: %w", err)
+	}
+
+	issues, err := schema.Validate(data)
+	if err != nil {
+		return err
+	}
+
+	result := ValidateResult{
+		AzureYamlPath: azureYamlPath,
+		SchemaURL:     schema.URL,
+		Issues:        issues,
+	}
+	for _, issue := range issues {
+		if issue.Severity == schema.SeverityError {
+			result.Errors++
+		} else {
+			result.Warnings++
+		}
+	}
+	result.Valid = result.Errors == 0 && (!strict || result.Warnings == 0)
+	if result.Issues == nil {
+		result.Issues = []schema.Issue{}
+	}
+
+	if cliout.IsJSON() {
+		if err := cliout.PrintJSON(result); err != nil {
+			return err
+		}
+	} else {
+		displayValidateResult(result)
+	}
+
+	if !result.Valid {
+		return fmt.Errorf("azure.yaml is invalid: %d error(s), %d warning(s)", result.Errors, result.Warnings)
+	}
+	return nil
+}
+
+// resolveAzureYamlToValidate returns the absolute path of the azure.yaml to validate.
+func resolveAzureYamlToValidate(path string) (string, error) {
+	if path == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+		found, err := detector.FindAzureYaml(cwd)
+		if err != nil || found == "" {
+			return "", fmt.Errorf("no azure.yaml found in %s or its parent directories", cwd)
+		}
+		path = found
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path: %w", err)
+	}
+	if info, err := os.Stat(absPath); err == nil && info.IsDir() {
+		absPath = filepath.Join(absPath, "azure.yaml")
+	}
+	if err := security.ValidatePath(absPath); err != nil {
+		return "", fmt.Errorf("invalid path: %w", err)
+	}
+	return absPath, nil
+}
+
+// displayValidateResult prints the issues as "path:line:column: message", the format editors
+// and CI annotations recognize.
+func displayValidateResult(result ValidateResult) {
+	for _, issue := range result.Issues {
+		location := result.AzureYamlPath
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d:%d", result.AzureYamlPath, issue.Line, issue.Column)
+		}
+		if issue.Severity == schema.SeverityError {
+			cliout.Error("%s: %s", location, issue.Message)
+		} else {
+			cliout.Warning("%s: %s", location, issue.Message)
+		}
+	}
+	if len(result.Issues) > 0 {
+		cliout.Newline()
+	}
+
+	switch {
+	case !result.Valid:
+		cliout.Error("azure.yaml has %d error(s) and %d warning(s)", result.Errors, result.Warnings)
+	case result.Warnings > 0:
+		cliout.Success("azure.yaml is valid, with %d warning(s)", result.Warnings)
+	default:
+		cliout.Success("azure.yaml is valid")
+	}
+}
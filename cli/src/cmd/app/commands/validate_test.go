@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunValidate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "azure.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("name: shop\nservices:\n  api:\n    project: ./api\n    host: containerapp\n    langauge: python\n")
+	if err := runValidate(path, false); err != nil {
+		t.Errorf("runValidate() error = %v, want unknown service fields to be warnings", err)
+	}
+	if err := runValidate(path, true); err == nil {
+		t.Error("runValidate() with strict should fail on warnings")
+	}
+
+	write("name: shop\nservices:\n  api:\n    project: ./api\n    host: containerapp\n    ports: 8000\n")
+	err := runValidate(dir, false)
+	if err == nil || !strings.Contains(err.Error(), "1 error(s)") {
+		t.Errorf("runValidate() error = %v, want one error", err)
+	}
+}
+
+func TestResolveAzureYamlToValidate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "azure.yaml")
+	if err := os.WriteFile(path, []byte("name: shop\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	subDir := filepath.Join(dir, "src")
+	if err := os.MkdirAll(subDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(subDir)
+
+	got, err := resolveAzureYamlToValidate("")
+	if err != nil {
+		t.Fatalf("resolveAzureYamlToValidate() error = %v", err)
+	}
+	if resolved, _ := filepath.EvalSymlinks(got); resolved != mustEvalSymlinks(t, path) {
+		t.Errorf("resolveAzureYamlToValidate() = %q, want %q", got, path)
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
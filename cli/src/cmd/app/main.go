@@ -7,8 +7,13 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/pkg/azdext"
 	"github.com/jongio/azd-app/cli/src/cmd/app/commands"
+	"github.com/jongio/azd-app/cli/src/internal/eventstream"
 	"github.com/jongio/azd-app/cli/src/internal/logging"
+	"github.com/jongio/azd-app/cli/src/internal/output"
+	"github.com/jongio/azd-app/cli/src/internal/selfupdate"
 	"github.com/jongio/azd-app/cli/src/internal/skills"
+	"github.com/jongio/azd-app/cli/src/internal/telemetry"
+	"github.com/jongio/azd-app/cli/src/internal/tracing"
 	internalversion "github.com/jongio/azd-app/cli/src/internal/version"
 	"github.com/jongio/azd-core/cliout"
 	"github.com/jongio/azd-core/env"
@@ -17,6 +22,8 @@ import (
 
 var (
 	structuredLogs bool
+	quiet          bool
+	verbosity      int
 )
 
 func main() {
@@ -34,6 +41,8 @@ func main() {
 
 	// Add app-specific flags not covered by the standard set
 	rootCmd.PersistentFlags().BoolVar(&structuredLogs, "structured-logs", false, "Enable structured JSON logging to stderr")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only show errors and the final service URLs")
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "Show the full output of installers, test runners and services (-vv for debug logs too)")
 
 	// Chain app-specific setup after the standard PersistentPreRunE
 	origPreRun := rootCmd.PersistentPreRunE
@@ -52,13 +61,31 @@ func main() {
 			}
 		}
 
-		// Configure logging
-		if extCtx.Debug {
+		outputLevel, err := output.ParseLevel(quiet, verbosity)
+		if err != nil {
+			return err
+		}
+
+		// Configure logging; -vv shows debug logs like --debug
+		debug := extCtx.Debug || outputLevel >= output.LevelDebug
+		if debug {
 			slog.SetLogLoggerLevel(slog.LevelDebug)
 		}
-		logging.SetupLogger(extCtx.Debug, structuredLogs)
+		logging.SetupLogger(debug, structuredLogs)
+
+		// Trace the command when an OTLP endpoint is configured with the OTEL_ environment variables
+		if err := tracing.Init(cmd.Context(), internalversion.Version); err != nil {
+			slog.Warn("Tracing disabled", "error", err)
+		}
+		cmd.SetContext(tracing.StartCommand(cmd.Context(), "azd "+cmd.CommandPath(), args))
+		// Anonymous usage telemetry is recorded only when the user opted in
+		telemetry.StartCommand(cmd.CommandPath())
+		// Look for a newer release in the background; the hint is printed after the command's output
+		if checksForUpdates(cmd) {
+			selfupdate.StartCheck(internalversion.Version)
+		}
 
-		if extCtx.Debug {
+		if debug {
 			logging.Debug("Starting azd app extension",
 				"version", internalversion.Version,
 				"command", cmd.Name(),
@@ -73,12 +100,15 @@ func main() {
 
 		// Install Copilot skill
 		if err := skills.InstallSkill(); err != nil {
-			if extCtx.Debug {
+			if debug {
 				slog.Debug("Failed to install copilot skill", "error", err)
 			}
 		}
 
-		return cliout.SetFormat(extCtx.OutputFormat)
+		if err := commands.SetOutputFormat(cmd, extCtx.OutputFormat); err != nil {
+			return err
+		}
+		return output.SetLevel(outputLevel)
 	}
 
 	// Register all commands
@@ -96,13 +126,46 @@ func main() {
 		commands.NewMCPCommand(),    // Model Context Protocol server
 		commands.NewStartCommand(),
 		commands.NewStopCommand(),
+		commands.NewStatusCommand(),
+		commands.NewSessionsCommand(),
+		commands.NewOpenCommand(),
 		commands.NewRestartCommand(),
 		commands.NewAddCommand(),
+		commands.NewGenerateCommand(),
+		commands.NewValidateCommand(),
+		commands.NewDiffCloudCommand(),
+		commands.NewDoctorCommand(),
+		commands.NewCacheCommand(),
+		commands.NewConfigCommand(),
+		commands.NewUpgradeCommand(),
+		commands.NewCompletionCommand(),
 		commands.NewMetadataCommand(func() *cobra.Command { return rootCmd }),
 	)
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	eventstream.EmitError("", err)
+	tracing.Finish(err)
+	telemetry.Finish(err)
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	if notice := selfupdate.Notice(); notice != "" && !cliout.IsJSON() && !output.IsQuiet() {
+		fmt.Fprintf(os.Stderr, "\n%s%s%s\n", cliout.Yellow, notice, cliout.Reset)
+	}
+}
+
+// checksForUpdates reports whether cmd shows the upgrade hint. Commands run by azd or other
+// tools, and upgrade itself, don't.
+func checksForUpdates(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Hidden {
+			return false
+		}
+	}
+	switch cmd.Name() {
+	case "upgrade", "completion", "mcp", "listen", "metadata", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return false
+	}
+	return true
 }
@@ -0,0 +1,121 @@
+package azdconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Environment variables that select where the extension keeps its state
+// (port assignments, dashboard ports and preferences).
+const (
+	// EnvStateBackend selects the backend: "azd" (default), "file", "sqlite" or "memory".
+	EnvStateBackend = "AZD_APP_STATE_BACKEND"
+	// EnvStatePath overrides the file or database path for the "file" and "sqlite" backends.
+	EnvStatePath = "AZD_APP_STATE_PATH"
+
+	// envAzdConfigDir is azd's override of its user config directory (~/.azd by default).
+	envAzdConfigDir = "AZD_CONFIG_DIR"
+)
+
+// Supported state backends.
+const (
+	// BackendAzd stores state in azd's user config via gRPC (~/.azd/config.json).
+	BackendAzd = "azd"
+	// BackendFile stores state in a standalone JSON file.
+	BackendFile = "file"
+	// BackendSQLite stores state in a SQLite database, suitable for a path shared by several users.
+	BackendSQLite = "sqlite"
+	// BackendMemory keeps state in memory for the lifetime of the process.
+	BackendMemory = "memory"
+)
+
+var (
+	sharedMemoryClient     *InMemoryClient
+	sharedMemoryClientOnce sync.Once
+)
+
+// Backend returns the state backend selected by AZD_APP_STATE_BACKEND, defaulting to "azd".
+func Backend() string {
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv(EnvStateBackend)))
+	if backend == "" {
+		return BackendAzd
+	}
+	return backend
+}
+
+// Open returns a ConfigClient for the backend selected by AZD_APP_STATE_BACKEND.
+// Callers own the returned client and should Close it when done; the "memory" backend
+// returns a client shared by the whole process, for which Close is a no-op.
+func Open(ctx context.Context) (ConfigClient, error) {
+	return OpenBackend(ctx, Backend())
+}
+
+// OpenBackend returns a ConfigClient for the given backend, regardless of AZD_APP_STATE_BACKEND.
+// AZD_APP_STATE_PATH still applies to the "file" and "sqlite" backends.
+func OpenBackend(ctx context.Context, backend string) (ConfigClient, error) {
+	switch backend {
+	case BackendAzd:
+		return NewClient(ctx)
+	case BackendFile:
+		path, err := statePath("state.json")
+		if err != nil {
+			return nil, err
+		}
+		return NewFileClient(path)
+	case BackendSQLite:
+		path, err := statePath("state.db")
+		if err != nil {
+			return nil, err
+		}
+		return NewSQLiteClient(path)
+	case BackendMemory:
+		sharedMemoryClientOnce.Do(func() {
+			sharedMemoryClient = NewInMemoryClient()
+		})
+		return sharedMemoryClient, nil
+	case "redis":
+		return nil, fmt.Errorf("%s=redis is not supported; use %q with %s on a shared path instead", EnvStateBackend, BackendSQLite, EnvStatePath)
+	default:
+		return nil, fmt.Errorf("unknown %s %q (valid: %s, %s, %s, %s)", EnvStateBackend, backend, BackendAzd, BackendFile, BackendSQLite, BackendMemory)
+	}
+}
+
+// StateFile returns the file the selected backend keeps its state in, or "" for the memory
+// backend. For the azd backend this is azd's config.json (in AZD_CONFIG_DIR, or ~/.azd).
+func StateFile() string {
+	var path string
+	var err error
+	switch Backend() {
+	case BackendAzd:
+		if dir := os.Getenv(envAzdConfigDir); dir != "" {
+			return filepath.Join(dir, "config.json")
+		}
+		var home string
+		home, err = os.UserHomeDir()
+		path = filepath.Join(home, ".azd", "config.json")
+	case BackendFile:
+		path, err = statePath("state.json")
+	case BackendSQLite:
+		path, err = statePath("state.db")
+	}
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// statePath returns AZD_APP_STATE_PATH, or ~/.azd/app/<name> when it is not set.
+func statePath(name string) (string, error) {
+	if path := strings.TrimSpace(os.Getenv(EnvStatePath)); path != "" {
+		return filepath.Abs(path)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".azd", "app", name), nil
+}
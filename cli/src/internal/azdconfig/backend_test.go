@@ -0,0 +1,225 @@
+package azdconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStoreClients(t *testing.T) {
+	dir := t.TempDir()
+	clients := map[string]func() (ConfigClient, error){
+		"file":   func() (ConfigClient, error) { return NewFileClient(filepath.Join(dir, "state.json")) },
+		"sqlite": func() (ConfigClient, error) { return NewSQLiteClient(filepath.Join(dir, "state.db")) },
+	}
+
+	for name, open := range clients {
+		t.Run(name, func(t *testing.T) {
+			client, err := open()
+			if err != nil {
+				t.Fatalf("failed to open client: %v", err)
+			}
+			defer client.Close()
+
+			hash := ProjectHash("/projects/app")
+			if port, err := client.GetServicePort(hash, "api"); err != nil || port != 0 {
+				t.Fatalf("GetServicePort() on empty store = %d, %v; want 0, nil", port, err)
+			}
+
+			if err := client.SetServicePort(hash, "api", 3000); err != nil {
+				t.Fatalf("SetServicePort() failed: %v", err)
+			}
+			if err := client.SetServicePort(hash, "web", 3001); err != nil {
+				t.Fatalf("SetServicePort() failed: %v", err)
+			}
+			if err := client.SetServicePort(ProjectHash("/projects/other"), "api", 4000); err != nil {
+				t.Fatalf("SetServicePort() failed: %v", err)
+			}
+			if err := client.SetDashboardPort(hash, 4280); err != nil {
+				t.Fatalf("SetDashboardPort() failed: %v", err)
+			}
+
+			ports, err := client.GetAllServicePorts(hash)
+			if err != nil {
+				t.Fatalf("GetAllServicePorts() failed: %v", err)
+			}
+			if len(ports) != 2 || ports["api"] != 3000 || ports["web"] != 3001 {
+				t.Errorf("GetAllServicePorts() = %v, want api=3000 web=3001", ports)
+			}
+			if port, _ := client.GetDashboardPort(hash); port != 4280 {
+				t.Errorf("GetDashboardPort() = %d, want 4280", port)
+			}
+
+			if err := client.ClearServicePort(hash, "api"); err != nil {
+				t.Fatalf("ClearServicePort() failed: %v", err)
+			}
+			if port, _ := client.GetServicePort(hash, "api"); port != 0 {
+				t.Errorf("GetServicePort() after clear = %d, want 0", port)
+			}
+
+			if err := client.SetPreference("alwaysKill", "true"); err != nil {
+				t.Fatalf("SetPreference() failed: %v", err)
+			}
+			if v, _ := client.GetPreference("alwaysKill"); v != "true" {
+				t.Errorf("GetPreference() = %q, want true", v)
+			}
+			if err := client.SetPreferenceSection("theme", []byte(`{"mode":"dark"}`)); err != nil {
+				t.Fatalf("SetPreferenceSection() failed: %v", err)
+			}
+			var section bytes.Buffer
+			if v, _ := client.GetPreferenceSection("theme"); json.Compact(&section, v) != nil || section.String() != `{"mode":"dark"}` {
+				t.Errorf("GetPreferenceSection() = %s", v)
+			}
+			if err := client.SetPreferenceSection("bad", []byte("{")); err == nil {
+				t.Error("expected error for invalid JSON section")
+			}
+			if err := client.ClearPreference("alwaysKill"); err != nil {
+				t.Fatalf("ClearPreference() failed: %v", err)
+			}
+			if v, _ := client.GetPreference("alwaysKill"); v != "" {
+				t.Errorf("GetPreference() after clear = %q, want empty", v)
+			}
+		})
+	}
+}
+
+// TestSQLiteClient_PathWithURICharacters verifies that a database path with characters that
+// have a meaning in URIs is opened as-is.
+func TestSQLiteClient_PathWithURICharacters(t *testing.T) {
+	name := "state #1 100%25"
+	if runtime.GOOS != "windows" {
+		name += "?mode=ro"
+	}
+	path := filepath.Join(t.TempDir(), name, "state.db")
+
+	client, err := NewSQLiteClient(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteClient() failed: %v", err)
+	}
+	hash := ProjectHash("/projects/app")
+	if err := client.SetServicePort(hash, "api", 3000); err != nil {
+		t.Fatalf("SetServicePort() failed: %v", err)
+	}
+	client.Close()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("database not created at %s: %v", path, err)
+	}
+	client, err = NewSQLiteClient(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteClient() on reopen failed: %v", err)
+	}
+	defer client.Close()
+	if port, err := client.GetServicePort(hash, "api"); err != nil || port != 3000 {
+		t.Errorf("GetServicePort() after reopen = %d, %v; want 3000, nil", port, err)
+	}
+}
+
+// TestFileClient_SharedAcrossClients verifies that separate clients (as used by separate
+// processes) see each other's writes and don't lose concurrent updates.
+func TestFileClient_SharedAcrossClients(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared", "state.json")
+	hash := ProjectHash("/projects/app")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client, err := NewFileClient(path)
+			if err != nil {
+				t.Errorf("NewFileClient() failed: %v", err)
+				return
+			}
+			defer client.Close()
+			if err := client.SetServicePort(hash, string(rune('a'+i)), 3000+i); err != nil {
+				t.Errorf("SetServicePort() failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	client, err := NewFileClient(path)
+	if err != nil {
+		t.Fatalf("NewFileClient() failed: %v", err)
+	}
+	ports, err := client.GetAllServicePorts(hash)
+	if err != nil {
+		t.Fatalf("GetAllServicePorts() failed: %v", err)
+	}
+	if len(ports) != 10 {
+		t.Errorf("got %d ports, want 10: %v", len(ports), ports)
+	}
+}
+
+func TestOpen_BackendSelection(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Setenv(EnvStateBackend, "")
+	if got := Backend(); got != BackendAzd {
+		t.Errorf("Backend() = %q, want %q by default", got, BackendAzd)
+	}
+
+	t.Setenv(EnvStateBackend, "File")
+	t.Setenv(EnvStatePath, filepath.Join(dir, "state.json"))
+	client, err := Open(context.Background())
+	if err != nil {
+		t.Fatalf("Open(file) failed: %v", err)
+	}
+	if _, ok := client.(*storeClient); !ok {
+		t.Errorf("Open(file) returned %T", client)
+	}
+	client.Close()
+
+	t.Setenv(EnvStateBackend, BackendSQLite)
+	t.Setenv(EnvStatePath, filepath.Join(dir, "state.db"))
+	client, err = Open(context.Background())
+	if err != nil {
+		t.Fatalf("Open(sqlite) failed: %v", err)
+	}
+	client.Close()
+
+	t.Setenv(EnvStateBackend, BackendMemory)
+	first, err := Open(context.Background())
+	if err != nil {
+		t.Fatalf("Open(memory) failed: %v", err)
+	}
+	second, _ := Open(context.Background())
+	if first != second {
+		t.Error("Open(memory) should return a process-wide shared client")
+	}
+
+	for _, backend := range []string{"redis", "etcd"} {
+		t.Setenv(EnvStateBackend, backend)
+		if _, err := Open(context.Background()); err == nil || !strings.Contains(err.Error(), EnvStateBackend) {
+			t.Errorf("Open(%s) error = %v, want unsupported backend error", backend, err)
+		}
+	}
+}
+
+func TestStateFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Setenv(EnvStateBackend, BackendAzd)
+	t.Setenv(envAzdConfigDir, dir)
+	if got, want := StateFile(), filepath.Join(dir, "config.json"); got != want {
+		t.Errorf("StateFile(azd) = %q, want %q", got, want)
+	}
+
+	t.Setenv(EnvStateBackend, BackendFile)
+	t.Setenv(EnvStatePath, filepath.Join(dir, "state.json"))
+	if got, want := StateFile(), filepath.Join(dir, "state.json"); got != want {
+		t.Errorf("StateFile(file) = %q, want %q", got, want)
+	}
+
+	t.Setenv(EnvStateBackend, BackendMemory)
+	if got := StateFile(); got != "" {
+		t.Errorf("StateFile(memory) = %q, want none", got)
+	}
+}
@@ -2,6 +2,9 @@
 // This package wraps the azdext UserConfig and Environment services to store
 // and retrieve configuration for the azd app extension.
 //
+// The storage backend can be switched with AZD_APP_STATE_BACKEND (see Open) to a
+// standalone JSON file, a SQLite database shared by several users, or memory.
+//
 // For unit testing, use NewInMemoryClient() which provides an in-memory
 // implementation that doesn't require a gRPC connection.
 package azdconfig
@@ -21,8 +24,9 @@ import (
 )
 
 // ConfigClient defines the interface for configuration operations.
-// This interface is implemented by both the gRPC client (Client) and the
-// in-memory client (InMemoryClient) for testing.
+// This interface is implemented by the gRPC client (Client), the file and
+// SQLite clients (NewFileClient, NewSQLiteClient), and the in-memory client
+// (InMemoryClient) for testing.
 //
 // Project-scoped settings use a projectHash parameter which is a unique
 // identifier derived from the project directory path. Use ProjectHash()
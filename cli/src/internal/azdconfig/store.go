@@ -0,0 +1,415 @@
+package azdconfig
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// fileLockTimeout is how long a file client waits for another process to release the lock.
+	fileLockTimeout = 5 * time.Second
+	// fileLockStaleAge is the age after which a leftover lock file (from a crashed process) is removed.
+	fileLockStaleAge = 30 * time.Second
+	// sqliteBusyTimeoutMs is how long SQLite waits for a lock held by another process.
+	sqliteBusyTimeoutMs = 5000
+)
+
+// store is a key-value persistence layer. Keys use the same dotted paths as azd's
+// user config (see projectConfigPath and preferencePath) and values are JSON.
+type store interface {
+	get(key string) (json.RawMessage, bool, error)
+	set(key string, value json.RawMessage) error
+	delete(key string) error
+	list(prefix string) (map[string]json.RawMessage, error)
+	close() error
+}
+
+// storeClient implements ConfigClient on top of a store.
+type storeClient struct {
+	store store
+}
+
+// Ensure storeClient implements ConfigClient
+var _ ConfigClient = (*storeClient)(nil)
+
+// NewFileClient creates a ConfigClient that keeps state in a JSON file at path.
+// Writes are atomic and serialized across processes with a lock file, so the same
+// file can be shared by several azd processes.
+func NewFileClient(path string) (ConfigClient, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	return &storeClient{store: &fileStore{path: path}}, nil
+}
+
+// NewSQLiteClient creates a ConfigClient that keeps state in a SQLite database at path.
+// The database can be shared by several users and processes (e.g. on a shared devbox).
+func NewSQLiteClient(path string) (ConfigClient, error) {
+	s, err := openSQLiteStore(path)
+	if err != nil {
+		return nil, err
+	}
+	return &storeClient{store: s}, nil
+}
+
+// Close releases the underlying store.
+func (c *storeClient) Close() {
+	_ = c.store.close()
+}
+
+func (c *storeClient) getInt(key string) (int, error) {
+	raw, ok, err := c.store.get(key)
+	if err != nil || !ok {
+		return 0, err
+	}
+	var v int
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return 0, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return v, nil
+}
+
+func (c *storeClient) setJSON(key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	return c.store.set(key, raw)
+}
+
+// GetDashboardPort retrieves the dashboard port for a project.
+func (c *storeClient) GetDashboardPort(projectHash string) (int, error) {
+	return c.getInt(projectConfigPath(projectHash, "dashboardPort"))
+}
+
+// SetDashboardPort stores the dashboard port for a project.
+func (c *storeClient) SetDashboardPort(projectHash string, port int) error {
+	return c.setJSON(projectConfigPath(projectHash, "dashboardPort"), port)
+}
+
+// ClearDashboardPort removes the dashboard port for a project.
+func (c *storeClient) ClearDashboardPort(projectHash string) error {
+	return c.store.delete(projectConfigPath(projectHash, "dashboardPort"))
+}
+
+// GetServicePort retrieves the assigned port for a service.
+func (c *storeClient) GetServicePort(projectHash, serviceName string) (int, error) {
+	return c.getInt(projectConfigPath(projectHash, fmt.Sprintf("ports.%s", serviceName)))
+}
+
+// SetServicePort stores the assigned port for a service.
+func (c *storeClient) SetServicePort(projectHash, serviceName string, port int) error {
+	return c.setJSON(projectConfigPath(projectHash, fmt.Sprintf("ports.%s", serviceName)), port)
+}
+
+// ClearServicePort removes the assigned port for a service.
+func (c *storeClient) ClearServicePort(projectHash, serviceName string) error {
+	return c.store.delete(projectConfigPath(projectHash, fmt.Sprintf("ports.%s", serviceName)))
+}
+
+// GetAllServicePorts retrieves all port assignments for a project.
+func (c *storeClient) GetAllServicePorts(projectHash string) (map[string]int, error) {
+	prefix := projectConfigPath(projectHash, "ports.")
+	entries, err := c.store.list(prefix)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]int, len(entries))
+	for key, raw := range entries {
+		var port int
+		if err := json.Unmarshal(raw, &port); err != nil {
+			continue
+		}
+		result[strings.TrimPrefix(key, prefix)] = port
+	}
+	return result, nil
+}
+
+// GetPreference retrieves a user preference value as a string.
+func (c *storeClient) GetPreference(key string) (string, error) {
+	raw, ok, err := c.store.get(preferencePath(key))
+	if err != nil || !ok {
+		return "", err
+	}
+	var v string
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", fmt.Errorf("invalid value for preference %s: %w", key, err)
+	}
+	return v, nil
+}
+
+// SetPreference stores a user preference value.
+func (c *storeClient) SetPreference(key, value string) error {
+	return c.setJSON(preferencePath(key), value)
+}
+
+// GetPreferenceSection retrieves a user preference section as JSON bytes.
+func (c *storeClient) GetPreferenceSection(key string) ([]byte, error) {
+	raw, ok, err := c.store.get(preferencePath(key))
+	if err != nil || !ok {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// SetPreferenceSection stores a user preference section from JSON bytes.
+func (c *storeClient) SetPreferenceSection(key string, value []byte) error {
+	if !json.Valid(value) {
+		return fmt.Errorf("preference section %s is not valid JSON", key)
+	}
+	return c.store.set(preferencePath(key), value)
+}
+
+// ClearPreference removes a user preference.
+func (c *storeClient) ClearPreference(key string) error {
+	return c.store.delete(preferencePath(key))
+}
+
+// fileStore keeps all keys in a single JSON object on disk.
+// Every operation re-reads the file so changes from other processes are visible.
+type fileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *fileStore) get(key string) (json.RawMessage, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return nil, false, err
+	}
+	v, ok := data[key]
+	return v, ok, nil
+}
+
+func (s *fileStore) set(key string, value json.RawMessage) error {
+	return s.update(func(data map[string]json.RawMessage) {
+		data[key] = value
+	})
+}
+
+func (s *fileStore) delete(key string) error {
+	return s.update(func(data map[string]json.RawMessage) {
+		delete(data, key)
+	})
+}
+
+func (s *fileStore) list(prefix string) (map[string]json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]json.RawMessage)
+	for k, v := range data {
+		if strings.HasPrefix(k, prefix) {
+			result[k] = v
+		}
+	}
+	return result, nil
+}
+
+func (s *fileStore) close() error {
+	return nil
+}
+
+// read loads the state file. A missing or empty file is an empty state.
+func (s *fileStore) read() (map[string]json.RawMessage, error) {
+	data := make(map[string]json.RawMessage)
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return data, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	if len(strings.TrimSpace(string(content))) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", s.path, err)
+	}
+	return data, nil
+}
+
+// update applies fn to the state under the cross-process lock and writes it back atomically.
+func (s *fileStore) update(fn func(map[string]json.RawMessage)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := lockFile(s.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	fn(data)
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp state file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
+
+// lockFile acquires an exclusive lock by creating path, waiting for other holders.
+// Lock files older than fileLockStaleAge are assumed to belong to a crashed process and removed.
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(fileLockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) // #nosec G304 -- lock file next to the state file
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > fileLockStaleAge {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for state lock %s", path)
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+}
+
+// sqliteStore keeps keys in a single table of a SQLite database.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+// openSQLiteStore opens (or creates) the database at path.
+func openSQLiteStore(path string) (*sqliteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve state database path: %w", err)
+	}
+	db, err := sql.Open("sqlite", sqliteDSN(absPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	schema := `CREATE TABLE IF NOT EXISTS state (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	)`
+	if _, err := db.ExecContext(context.Background(), schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create state table: %w", err)
+	}
+	return &sqliteStore{db: db}, nil
+}
+
+// sqliteDSN returns the URI that opens the database at the absolute path. The path is
+// percent-encoded so characters such as '?', '#' and '%' in it aren't read as the start of the
+// query, a fragment or an escape. A Windows path gets a leading slash, as in file:///C:/....
+func sqliteDSN(path string) string {
+	uriPath := filepath.ToSlash(path)
+	if filepath.VolumeName(path) != "" {
+		uriPath = "/" + uriPath
+	}
+	// WAL lets readers proceed while another process writes; busy_timeout makes
+	// concurrent writers wait instead of failing with SQLITE_BUSY.
+	dsn := url.URL{
+		Scheme:   "file",
+		Path:     uriPath,
+		RawQuery: fmt.Sprintf("_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)", sqliteBusyTimeoutMs),
+	}
+	return dsn.String()
+}
+
+func (s *sqliteStore) get(key string) (json.RawMessage, bool, error) {
+	var value string
+	err := s.db.QueryRowContext(context.Background(), `SELECT value FROM state WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return json.RawMessage(value), true, nil
+}
+
+func (s *sqliteStore) set(key string, value json.RawMessage) error {
+	_, err := s.db.ExecContext(context.Background(),
+		`INSERT INTO state (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		key, string(value))
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) delete(key string) error {
+	if _, err := s.db.ExecContext(context.Background(), `DELETE FROM state WHERE key = ?`, key); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) list(prefix string) (map[string]json.RawMessage, error) {
+	rows, err := s.db.QueryContext(context.Background(),
+		`SELECT key, value FROM state WHERE substr(key, 1, ?) = ?`, len(prefix), prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	result := make(map[string]json.RawMessage)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+		}
+		result[key] = json.RawMessage(value)
+	}
+	return result, rows.Err()
+}
+
+func (s *sqliteStore) close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,174 @@
+// Package azdenv reads the values of an azd environment from the project's .azure directory,
+// so services run locally get the endpoints and connection strings written by `azd provision`
+// without going through azd.
+//
+// An environment named <name> is stored in .azure/<name>/.env. The current environment is
+// AZURE_ENV_NAME when set (azd sets it for extensions and with -e), otherwise the
+// defaultEnvironment of .azure/config.json.
+package azdenv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+// EnvName is the variable azd sets to the name of the selected environment.
+const EnvName = "AZURE_ENV_NAME"
+
+// DefaultPollInterval is how often Watch checks the .env file for changes.
+const DefaultPollInterval = 2 * time.Second
+
+// ErrNoEnvironment is returned when no environment is selected and the project has no default.
+var ErrNoEnvironment = errors.New("no azd environment selected")
+
+// Environment is a loaded azd environment.
+type Environment struct {
+	Name   string
+	Path   string // The .env file
+	Values map[string]string
+}
+
+// Dir returns the directory of the azd environment name in projectDir, .azure/<name>.
+func Dir(projectDir, name string) string {
+	return filepath.Join(projectDir, ".azure", name)
+}
+
+// Path returns the .env file of the azd environment name in projectDir.
+func Path(projectDir, name string) string {
+	return filepath.Join(Dir(projectDir, name), ".env")
+}
+
+// CurrentName returns the name of the selected environment: AZURE_ENV_NAME, or the
+// defaultEnvironment of .azure/config.json. It returns "" when neither is set.
+func CurrentName(projectDir string) string {
+	if name := os.Getenv(EnvName); name != "" {
+		return name
+	}
+
+	// #nosec G304 -- path is azd's config file in the project's .azure directory
+	data, err := os.ReadFile(filepath.Join(projectDir, ".azure", "config.json"))
+	if err != nil {
+		return ""
+	}
+	var config struct {
+		DefaultEnvironment string `json:"defaultEnvironment"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return ""
+	}
+	return config.DefaultEnvironment
+}
+
+// validateName rejects names that would resolve outside the .azure directory.
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid azd environment name %q", name)
+	}
+	return nil
+}
+
+// Load reads the azd environment name in projectDir. An empty name loads the current environment.
+func Load(projectDir, name string) (*Environment, error) {
+	if name == "" {
+		name = CurrentName(projectDir)
+		if name == "" {
+			return nil, ErrNoEnvironment
+		}
+	}
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	envPath := Path(projectDir, name)
+	if _, err := os.Stat(envPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("azd environment '%s' not found: %s does not exist (create it with 'azd env new %s')", name, envPath, name)
+		}
+		return nil, fmt.Errorf("failed to read azd environment '%s': %w", name, err)
+	}
+	values, err := service.LoadDotEnv(envPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read azd environment '%s': %w", name, err)
+	}
+	return &Environment{Name: name, Path: envPath, Values: values}, nil
+}
+
+// ChangedKeys returns the sorted names of the values that were added, changed or removed.
+func ChangedKeys(before, after map[string]string) []string {
+	var changed []string
+	for key, value := range after {
+		if old, ok := before[key]; !ok || old != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// fileState is the part of the .env file's metadata used to detect modifications.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size()}
+}
+
+// Watch polls the environment's .env file every interval until ctx is canceled, e.g. to pick up
+// the outputs `azd provision` writes from another terminal. onChange is called with the new
+// environment and the names of the values that changed; rewrites that change no value are ignored.
+func (e *Environment) Watch(ctx context.Context, interval time.Duration, onChange func(updated *Environment, changed []string)) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	current := e
+	last := statFile(e.Path)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		state := statFile(current.Path)
+		if state.modTime.Equal(last.modTime) && state.size == last.size {
+			continue
+		}
+		last = state
+
+		values, err := service.LoadDotEnv(current.Path)
+		if err != nil {
+			// The file may be mid-rewrite; it is read again on the next change
+			continue
+		}
+		changed := ChangedKeys(current.Values, values)
+		if len(changed) == 0 {
+			continue
+		}
+		current = &Environment{Name: current.Name, Path: current.Path, Values: values}
+		onChange(current, changed)
+	}
+}
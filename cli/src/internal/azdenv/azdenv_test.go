@@ -0,0 +1,136 @@
+package azdenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeEnv writes the .env file of the azd environment name in projectDir.
+func writeEnv(t *testing.T, projectDir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(Dir(projectDir, name), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(Path(projectDir, name), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCurrentName(t *testing.T) {
+	projectDir := t.TempDir()
+	t.Setenv(EnvName, "")
+
+	if got := CurrentName(projectDir); got != "" {
+		t.Errorf("CurrentName() without config = %q, want empty", got)
+	}
+
+	if err := os.MkdirAll(filepath.Join(projectDir, ".azure"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"version":1,"defaultEnvironment":"dev"}`
+	if err := os.WriteFile(filepath.Join(projectDir, ".azure", "config.json"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := CurrentName(projectDir); got != "dev" {
+		t.Errorf("CurrentName() = %q, want dev", got)
+	}
+
+	t.Setenv(EnvName, "staging")
+	if got := CurrentName(projectDir); got != "staging" {
+		t.Errorf("CurrentName() with %s = %q, want staging", EnvName, got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	projectDir := t.TempDir()
+	t.Setenv(EnvName, "dev")
+	writeEnv(t, projectDir, "dev", "AZURE_ENV_NAME=\"dev\"\nSERVICE_API_URL=\"https://api-dev.example.com\"\n")
+	writeEnv(t, projectDir, "staging", "SERVICE_API_URL=\"https://api-staging.example.com\"\n")
+
+	env, err := Load(projectDir, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if env.Name != "dev" || env.Values["SERVICE_API_URL"] != "https://api-dev.example.com" {
+		t.Errorf("Load() = %+v, want the dev environment", env)
+	}
+
+	env, err = Load(projectDir, "staging")
+	if err != nil {
+		t.Fatalf("Load(staging) error = %v", err)
+	}
+	if env.Values["SERVICE_API_URL"] != "https://api-staging.example.com" {
+		t.Errorf("Load(staging) = %+v, want the staging environment", env)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	projectDir := t.TempDir()
+	t.Setenv(EnvName, "")
+
+	if _, err := Load(projectDir, ""); !errors.Is(err, ErrNoEnvironment) {
+		t.Errorf("Load() without an environment error = %v, want ErrNoEnvironment", err)
+	}
+	if _, err := Load(projectDir, "missing"); err == nil || !strings.Contains(err.Error(), "azd env new missing") {
+		t.Errorf("Load(missing) error = %v, want a hint to create it", err)
+	}
+	if _, err := Load(projectDir, "../outside"); err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Errorf("Load(../outside) error = %v, want an invalid name", err)
+	}
+}
+
+func TestChangedKeys(t *testing.T) {
+	before := map[string]string{"A": "1", "B": "2", "C": "3"}
+	after := map[string]string{"A": "1", "B": "changed", "D": "4"}
+
+	want := []string{"B", "C", "D"}
+	if got := ChangedKeys(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedKeys() = %v, want %v", got, want)
+	}
+	if got := ChangedKeys(before, before); len(got) != 0 {
+		t.Errorf("ChangedKeys() of equal maps = %v, want none", got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	projectDir := t.TempDir()
+	writeEnv(t, projectDir, "dev", "SERVICE_API_URL=\"\"\n")
+	env, err := Load(projectDir, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	type update struct {
+		env     *Environment
+		changed []string
+	}
+	updates := make(chan update, 1)
+	go env.Watch(ctx, 20*time.Millisecond, func(updated *Environment, changed []string) {
+		updates <- update{updated, changed}
+	})
+
+	// Simulate azd provision writing its outputs
+	time.Sleep(50 * time.Millisecond)
+	writeEnv(t, projectDir, "dev", "SERVICE_API_URL=\"https://api.example.com\"\nSERVICE_API_NAME=\"api\"\n")
+
+	select {
+	case got := <-updates:
+		if got.env.Values["SERVICE_API_URL"] != "https://api.example.com" {
+			t.Errorf("updated SERVICE_API_URL = %q", got.env.Values["SERVICE_API_URL"])
+		}
+		if want := []string{"SERVICE_API_NAME", "SERVICE_API_URL"}; !reflect.DeepEqual(got.changed, want) {
+			t.Errorf("changed = %v, want %v", got.changed, want)
+		}
+	case <-ctx.Done():
+		t.Fatal("Watch() did not report the change")
+	}
+}
@@ -0,0 +1,128 @@
+package azdenv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+var (
+	// bicepOutput matches an output declaration of a Bicep template, e.g. `output API_URL string = ...`.
+	bicepOutput = regexp.MustCompile(`^\s*output\s+([A-Za-z_][A-Za-z0-9_]*)\s`)
+	// terraformOutput matches an output block of a Terraform module, e.g. `output "API_URL" {`.
+	terraformOutput = regexp.MustCompile(`^\s*output\s+"([^"]+)"`)
+)
+
+// DeclaredOutputs returns the sorted names of the outputs the infrastructure in infraDir declares,
+// which `azd provision` writes to the environment's .env file: those of main.bicep, or of the
+// Terraform files at the root of infraDir. Secure Bicep outputs are skipped; azd doesn't store them.
+func DeclaredOutputs(infraDir string) []string {
+	var outputs []string
+
+	// #nosec G304 -- path is the entry point of the project's infrastructure
+	if data, err := os.ReadFile(filepath.Join(infraDir, "main.bicep")); err == nil {
+		secure := false
+		for _, line := range strings.Split(string(data), "\n") {
+			trimmed := strings.TrimSpace(line)
+			if match := bicepOutput.FindStringSubmatch(line); match != nil && !secure {
+				outputs = append(outputs, match[1])
+			}
+			if trimmed != "" {
+				secure = strings.HasPrefix(trimmed, "@secure()")
+			}
+		}
+	}
+
+	tfFiles, _ := filepath.Glob(filepath.Join(infraDir, "*.tf"))
+	for _, tfFile := range tfFiles {
+		// #nosec G304 -- path is a Terraform file of the project's infrastructure
+		data, err := os.ReadFile(tfFile)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if match := terraformOutput.FindStringSubmatch(line); match != nil {
+				outputs = append(outputs, match[1])
+			}
+		}
+	}
+
+	sort.Strings(outputs)
+	return outputs
+}
+
+// OutputsStale reports whether the environment's provisioning outputs are missing or older than
+// the infrastructure in infraDir, and why.
+//
+// An environment is provisioned when its .env file has every output the infrastructure declares;
+// `azd env new` and `azd env set` write the file too, so its existence alone says nothing. The
+// outputs are older than the infrastructure when a file in infraDir changed after the .env file
+// was last written. Without declared outputs only the latter is checked. A missing infraDir is
+// never stale.
+func OutputsStale(projectDir, name, infraDir string) (bool, string) {
+	envPath := Path(projectDir, name)
+	envInfo, err := os.Stat(envPath)
+	if err != nil {
+		return true, fmt.Sprintf("azd environment '%s' has not been provisioned", name)
+	}
+
+	if outputs := DeclaredOutputs(infraDir); len(outputs) > 0 {
+		values, err := service.LoadDotEnv(envPath)
+		if err != nil {
+			return true, fmt.Sprintf("failed to read azd environment '%s': %v", name, err)
+		}
+		if missing := missingOutputs(outputs, values); len(missing) > 0 {
+			return true, fmt.Sprintf("azd environment '%s' has not been provisioned (no %s)", name, strings.Join(missing, ", "))
+		}
+	}
+
+	newest, newestTime := newestFile(infraDir)
+	if newest == "" || !newestTime.After(envInfo.ModTime()) {
+		return false, ""
+	}
+
+	rel, err := filepath.Rel(projectDir, newest)
+	if err != nil {
+		rel = newest
+	}
+	return true, fmt.Sprintf("%s changed since the last provision", filepath.ToSlash(rel))
+}
+
+// missingOutputs returns the outputs that have no value. azd may change the case of output
+// names, so they are compared case-insensitively.
+func missingOutputs(outputs []string, values map[string]string) []string {
+	present := make(map[string]bool, len(values))
+	for key := range values {
+		present[strings.ToUpper(key)] = true
+	}
+	var missing []string
+	for _, output := range outputs {
+		if !present[strings.ToUpper(output)] {
+			missing = append(missing, output)
+		}
+	}
+	return missing
+}
+
+// newestFile returns the most recently modified file under dir and its modification time.
+func newestFile(dir string) (string, time.Time) {
+	var newest string
+	var newestTime time.Time
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, infoErr := d.Info()
+		if infoErr == nil && info.ModTime().After(newestTime) {
+			newest, newestTime = path, info.ModTime()
+		}
+		return nil
+	})
+	return newest, newestTime
+}
@@ -0,0 +1,87 @@
+package azdenv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testMainBicep = `param location string
+
+output SERVICE_API_URL string = api.outputs.uri
+@secure()
+output STORAGE_KEY string = storage.listKeys().keys[0].value
+output AZURE_LOCATION string = location
+`
+
+// writeInfra writes a file of the infrastructure in infraDir.
+func writeInfra(t *testing.T, infraDir, name, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(infraDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(infraDir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDeclaredOutputs(t *testing.T) {
+	bicepDir := filepath.Join(t.TempDir(), "infra")
+	writeInfra(t, bicepDir, "main.bicep", testMainBicep)
+	if got, want := DeclaredOutputs(bicepDir), []string{"AZURE_LOCATION", "SERVICE_API_URL"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DeclaredOutputs(bicep) = %v, want %v", got, want)
+	}
+
+	tfDir := filepath.Join(t.TempDir(), "infra")
+	writeInfra(t, tfDir, "outputs.tf", "output \"SERVICE_API_URL\" {\n  value = azurerm_linux_web_app.api.default_hostname\n}\n")
+	if got, want := DeclaredOutputs(tfDir), []string{"SERVICE_API_URL"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DeclaredOutputs(terraform) = %v, want %v", got, want)
+	}
+
+	if got := DeclaredOutputs(filepath.Join(t.TempDir(), "missing")); len(got) != 0 {
+		t.Errorf("DeclaredOutputs(missing) = %v, want none", got)
+	}
+}
+
+func TestOutputsStale(t *testing.T) {
+	projectDir := t.TempDir()
+	infraDir := filepath.Join(projectDir, "infra")
+	bicep := writeInfra(t, infraDir, "main.bicep", testMainBicep)
+
+	if stale, reason := OutputsStale(projectDir, "dev", infraDir); !stale || !strings.Contains(reason, "not been provisioned") {
+		t.Errorf("OutputsStale() without an environment = %v, %q; want stale", stale, reason)
+	}
+
+	// azd env new writes .env without provisioning
+	writeEnv(t, projectDir, "dev", "AZURE_ENV_NAME=\"dev\"\nAZURE_LOCATION=\"eastus\"\n")
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(bicep, past, past); err != nil {
+		t.Fatal(err)
+	}
+	if stale, reason := OutputsStale(projectDir, "dev", infraDir); !stale || reason != "azd environment 'dev' has not been provisioned (no SERVICE_API_URL)" {
+		t.Errorf("OutputsStale() for a new environment = %v, %q; want stale naming SERVICE_API_URL", stale, reason)
+	}
+
+	// azd provision writes the outputs; secure outputs aren't stored
+	writeEnv(t, projectDir, "dev", "AZURE_ENV_NAME=\"dev\"\nAZURE_LOCATION=\"eastus\"\nSERVICE_API_URL=\"https://api.example.com\"\n")
+	if stale, reason := OutputsStale(projectDir, "dev", infraDir); stale {
+		t.Errorf("OutputsStale() after provisioning = true, %q; want up to date", reason)
+	}
+
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(bicep, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if stale, reason := OutputsStale(projectDir, "dev", infraDir); !stale || reason != "infra/main.bicep changed since the last provision" {
+		t.Errorf("OutputsStale() after changing infra = %v, %q; want stale naming main.bicep", stale, reason)
+	}
+
+	if stale, _ := OutputsStale(projectDir, "dev", filepath.Join(projectDir, "missing")); stale {
+		t.Error("OutputsStale() without an infra directory should not be stale")
+	}
+}
@@ -0,0 +1,353 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	// containerAppAPIVersion is the Microsoft.App API version used to read Container App configuration.
+	containerAppAPIVersion = "2024-03-01"
+	// webAppAPIVersion is the Microsoft.Web API version used to read App Service and Function app settings.
+	webAppAPIVersion = "2023-12-01"
+)
+
+// PlatformEnvKeys are settings that Azure or azd manage on deployed resources.
+// They are not expected in azure.yaml and are ignored by default when comparing env keys.
+var PlatformEnvKeys = map[string]bool{
+	"APPLICATIONINSIGHTS_CONNECTION_STRING":    true,
+	"AZURE_CLIENT_ID":                          true,
+	"AzureWebJobsStorage":                      true,
+	"ENABLE_ORYX_BUILD":                        true,
+	"FUNCTIONS_EXTENSION_VERSION":              true,
+	"FUNCTIONS_WORKER_RUNTIME":                 true,
+	"PORT":                                     true,
+	"SCM_DO_BUILD_DURING_DEPLOYMENT":           true,
+	"WEBSITE_CONTENTAZUREFILECONNECTIONSTRING": true,
+	"WEBSITE_CONTENTSHARE":                     true,
+	"WEBSITE_RUN_FROM_PACKAGE":                 true,
+	"WEBSITES_ENABLE_APP_SERVICE_STORAGE":      true,
+	"WEBSITES_PORT":                            true,
+}
+
+// DriftStatus describes how a local service compares to its deployed counterpart.
+type DriftStatus string
+
+// DriftStatusInSync and related constants describe the drift state of a service.
+const (
+	DriftStatusInSync     DriftStatus = "in-sync"
+	DriftStatusDrifted    DriftStatus = "drift"
+	DriftStatusLocalOnly  DriftStatus = "local-only"
+	DriftStatusCloudOnly  DriftStatus = "cloud-only"
+	DriftStatusUnreadable DriftStatus = "unreadable"
+)
+
+// LocalService is the local view of a service as defined in azure.yaml.
+type LocalService struct {
+	Name    string
+	Port    int
+	EnvKeys []string
+}
+
+// CloudService is the deployed view of a service read from its Azure resource.
+type CloudService struct {
+	Name         string
+	ResourceType ResourceType
+	ResourceID   string
+	Port         int
+	EnvKeys      []string
+	// Error is set when the resource exists but its configuration could not be read.
+	Error string
+}
+
+// ServiceDrift reports the differences for a single service.
+type ServiceDrift struct {
+	Service      string       `json:"service"`
+	Status       DriftStatus  `json:"status"`
+	ResourceType ResourceType `json:"resourceType,omitempty"`
+	ResourceID   string       `json:"resourceId,omitempty"`
+	LocalPort    int          `json:"localPort,omitempty"`
+	CloudPort    int          `json:"cloudPort,omitempty"`
+	LocalOnlyEnv []string     `json:"localOnlyEnv,omitempty"`
+	CloudOnlyEnv []string     `json:"cloudOnlyEnv,omitempty"`
+	Error        string       `json:"error,omitempty"`
+}
+
+// PortDrift returns true when both ports are known and differ.
+func (d ServiceDrift) PortDrift() bool {
+	return d.LocalPort != 0 && d.CloudPort != 0 && d.LocalPort != d.CloudPort
+}
+
+// DriftReport is the result of comparing local services against deployed resources.
+type DriftReport struct {
+	Environment string         `json:"environment,omitempty"`
+	Services    []ServiceDrift `json:"services"`
+}
+
+// HasDrift returns true if any service is not in sync.
+func (r *DriftReport) HasDrift() bool {
+	for _, s := range r.Services {
+		if s.Status != DriftStatusInSync {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff compares local services against cloud services and returns a report sorted by service name.
+// Service names are matched using the same normalization as resource discovery.
+// Env keys present in ignoreEnv (e.g. platform-managed settings) are excluded from the comparison.
+func Diff(local []LocalService, cloud []CloudService, ignoreEnv map[string]bool) *DriftReport {
+	cloudByName := make(map[string]CloudService, len(cloud))
+	for _, c := range cloud {
+		cloudByName[NormalizeServiceName(c.Name)] = c
+	}
+
+	report := &DriftReport{Services: make([]ServiceDrift, 0, len(local)+len(cloud))}
+	seen := make(map[string]bool, len(local))
+
+	for _, l := range local {
+		key := NormalizeServiceName(l.Name)
+		seen[key] = true
+
+		drift := ServiceDrift{Service: l.Name, LocalPort: l.Port}
+		c, ok := cloudByName[key]
+		if !ok {
+			drift.Status = DriftStatusLocalOnly
+			report.Services = append(report.Services, drift)
+			continue
+		}
+
+		drift.ResourceType = c.ResourceType
+		drift.ResourceID = c.ResourceID
+		if c.Error != "" {
+			drift.Status = DriftStatusUnreadable
+			drift.Error = c.Error
+			report.Services = append(report.Services, drift)
+			continue
+		}
+
+		drift.CloudPort = c.Port
+		drift.LocalOnlyEnv = keysMissingFrom(l.EnvKeys, c.EnvKeys, ignoreEnv)
+		drift.CloudOnlyEnv = keysMissingFrom(c.EnvKeys, l.EnvKeys, ignoreEnv)
+
+		drift.Status = DriftStatusInSync
+		if drift.PortDrift() || len(drift.LocalOnlyEnv) > 0 || len(drift.CloudOnlyEnv) > 0 {
+			drift.Status = DriftStatusDrifted
+		}
+		report.Services = append(report.Services, drift)
+	}
+
+	for _, c := range cloud {
+		if seen[NormalizeServiceName(c.Name)] {
+			continue
+		}
+		report.Services = append(report.Services, ServiceDrift{
+			Service:      c.Name,
+			Status:       DriftStatusCloudOnly,
+			ResourceType: c.ResourceType,
+			ResourceID:   c.ResourceID,
+			CloudPort:    c.Port,
+		})
+	}
+
+	sort.Slice(report.Services, func(i, j int) bool {
+		return report.Services[i].Service < report.Services[j].Service
+	})
+	return report
+}
+
+// NormalizeServiceName converts a service name to the form used for SERVICE_<NAME>_* lookups
+// (lowercase, underscores replaced by hyphens).
+func NormalizeServiceName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+}
+
+// keysMissingFrom returns the sorted keys in a that are not in b, skipping ignored keys.
+func keysMissingFrom(a, b []string, ignore map[string]bool) []string {
+	inB := make(map[string]bool, len(b))
+	for _, k := range b {
+		inB[k] = true
+	}
+	var missing []string
+	for _, k := range a {
+		if !inB[k] && !ignore[k] {
+			missing = append(missing, k)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// ConfigReader reads the deployed configuration (port and env keys) of Azure compute resources.
+type ConfigReader struct {
+	pipeline runtime.Pipeline
+	endpoint string
+}
+
+// NewConfigReader creates a reader that authenticates ARM requests with the given credential.
+func NewConfigReader(credential azcore.TokenCredential) *ConfigReader {
+	pipeline := runtime.NewPipeline("azd-app", "1.0",
+		runtime.PipelineOptions{
+			PerRetry: []policy.Policy{
+				runtime.NewBearerTokenPolicy(credential, []string{"https://management.azure.com/.default"}, nil),
+			},
+		},
+		nil,
+	)
+	return &ConfigReader{pipeline: pipeline, endpoint: "https://management.azure.com"}
+}
+
+// Read returns the deployed view of a discovered resource.
+// Failures are recorded on the returned CloudService rather than returned as errors,
+// so one unreadable resource does not prevent the rest of the comparison.
+func (r *ConfigReader) Read(ctx context.Context, resource *AzureResource) CloudService {
+	svc := CloudService{
+		Name:         resource.ServiceName,
+		ResourceType: resource.ResourceType,
+		ResourceID:   resource.ResourceID,
+	}
+
+	if resource.ResourceID == "" {
+		svc.Error = "resource ID could not be resolved"
+		return svc
+	}
+
+	var err error
+	switch resource.ResourceType {
+	case ResourceTypeContainerApp:
+		svc.Port, svc.EnvKeys, err = r.readContainerApp(ctx, resource.ResourceID)
+	case ResourceTypeAppService, ResourceTypeFunction:
+		svc.Port, svc.EnvKeys, err = r.readWebApp(ctx, resource.ResourceID)
+	default:
+		err = fmt.Errorf("resource type %q is not supported", resource.ResourceType)
+	}
+	if err != nil {
+		svc.Error = err.Error()
+	}
+	return svc
+}
+
+// containerAppResponse is the subset of a Microsoft.App/containerApps resource used for drift detection.
+type containerAppResponse struct {
+	Properties struct {
+		Configuration struct {
+			Ingress *struct {
+				TargetPort int `json:"targetPort"`
+			} `json:"ingress"`
+		} `json:"configuration"`
+		Template struct {
+			Containers []struct {
+				Env []struct {
+					Name string `json:"name"`
+				} `json:"env"`
+			} `json:"containers"`
+		} `json:"template"`
+	} `json:"properties"`
+}
+
+// readContainerApp reads the ingress target port and container env var names of a Container App.
+func (r *ConfigReader) readContainerApp(ctx context.Context, resourceID string) (int, []string, error) {
+	var app containerAppResponse
+	url := fmt.Sprintf("%s%s?api-version=%s", r.endpoint, resourceID, containerAppAPIVersion)
+	if err := r.do(ctx, http.MethodGet, url, &app); err != nil {
+		return 0, nil, err
+	}
+	port, keys := parseContainerApp(&app)
+	return port, keys, nil
+}
+
+// parseContainerApp extracts the port and env keys from a Container App response.
+func parseContainerApp(app *containerAppResponse) (int, []string) {
+	port := 0
+	if app.Properties.Configuration.Ingress != nil {
+		port = app.Properties.Configuration.Ingress.TargetPort
+	}
+
+	keys := make(map[string]bool)
+	for _, c := range app.Properties.Template.Containers {
+		for _, e := range c.Env {
+			keys[e.Name] = true
+		}
+	}
+	return port, sortedKeys(keys)
+}
+
+// appSettingsResponse is the response of the Microsoft.Web config/appsettings/list operation.
+type appSettingsResponse struct {
+	Properties map[string]string `json:"properties"`
+}
+
+// readWebApp reads the app setting names of an App Service or Function app.
+// The port is taken from WEBSITES_PORT when set. Azure has no operation that lists only the
+// names: config/appsettings/list returns the values too, including secrets. They are discarded
+// by parseAppSettings and never stored or printed.
+func (r *ConfigReader) readWebApp(ctx context.Context, resourceID string) (int, []string, error) {
+	var settings appSettingsResponse
+	url := fmt.Sprintf("%s%s/config/appsettings/list?api-version=%s", r.endpoint, resourceID, webAppAPIVersion)
+	if err := r.do(ctx, http.MethodPost, url, &settings); err != nil {
+		return 0, nil, err
+	}
+	return parseAppSettings(&settings)
+}
+
+// parseAppSettings extracts the port and env keys from an app settings response.
+func parseAppSettings(settings *appSettingsResponse) (int, []string, error) {
+	port := 0
+	if v, ok := settings.Properties["WEBSITES_PORT"]; ok {
+		p, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid WEBSITES_PORT %q: %w", v, err)
+		}
+		port = p
+	}
+
+	keys := make(map[string]bool, len(settings.Properties))
+	for k := range settings.Properties {
+		keys[k] = true
+	}
+	return port, sortedKeys(keys), nil
+}
+
+// do executes an ARM request and decodes the JSON response into out.
+func (r *ConfigReader) do(ctx context.Context, method, url string, out interface{}) error {
+	req, err := runtime.NewRequest(ctx, method, url)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := r.pipeline.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("API error %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+package azure
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	local := []LocalService{
+		{Name: "api", Port: 8080, EnvKeys: []string{"DATABASE_URL", "LOG_LEVEL"}},
+		{Name: "web", Port: 3000, EnvKeys: []string{"API_URL"}},
+		{Name: "worker_jobs", Port: 0, EnvKeys: []string{"QUEUE"}},
+		{Name: "admin", Port: 5000},
+	}
+	cloud := []CloudService{
+		{Name: "api", ResourceType: ResourceTypeContainerApp, Port: 8000, EnvKeys: []string{"DATABASE_URL", "AZURE_CLIENT_ID", "FEATURE_FLAG"}},
+		{Name: "web", ResourceType: ResourceTypeAppService, Port: 0, EnvKeys: []string{"API_URL", "WEBSITES_PORT"}},
+		{Name: "worker-jobs", ResourceType: ResourceTypeContainerApp, Error: "API error 403"},
+		{Name: "legacy", ResourceType: ResourceTypeAppService, Port: 80},
+	}
+
+	report := Diff(local, cloud, PlatformEnvKeys)
+
+	got := make(map[string]ServiceDrift, len(report.Services))
+	var order []string
+	for _, s := range report.Services {
+		got[s.Service] = s
+		order = append(order, s.Service)
+	}
+
+	if want := []string{"admin", "api", "legacy", "web", "worker_jobs"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("services = %v, want %v", order, want)
+	}
+
+	api := got["api"]
+	if api.Status != DriftStatusDrifted {
+		t.Errorf("api status = %s, want %s", api.Status, DriftStatusDrifted)
+	}
+	if !api.PortDrift() {
+		t.Error("api should report port drift (8080 vs 8000)")
+	}
+	if !reflect.DeepEqual(api.LocalOnlyEnv, []string{"LOG_LEVEL"}) {
+		t.Errorf("api LocalOnlyEnv = %v, want [LOG_LEVEL]", api.LocalOnlyEnv)
+	}
+	if !reflect.DeepEqual(api.CloudOnlyEnv, []string{"FEATURE_FLAG"}) {
+		t.Errorf("api CloudOnlyEnv = %v, want [FEATURE_FLAG] (platform keys ignored)", api.CloudOnlyEnv)
+	}
+
+	if web := got["web"]; web.Status != DriftStatusInSync {
+		t.Errorf("web status = %s, want %s (unknown cloud port is not drift)", web.Status, DriftStatusInSync)
+	}
+	if w := got["worker_jobs"]; w.Status != DriftStatusUnreadable || w.Error == "" {
+		t.Errorf("worker_jobs = %+v, want unreadable with error (names matched after normalization)", w)
+	}
+	if a := got["admin"]; a.Status != DriftStatusLocalOnly {
+		t.Errorf("admin status = %s, want %s", a.Status, DriftStatusLocalOnly)
+	}
+	if l := got["legacy"]; l.Status != DriftStatusCloudOnly || l.CloudPort != 80 {
+		t.Errorf("legacy = %+v, want cloud-only with port 80", l)
+	}
+	if !report.HasDrift() {
+		t.Error("HasDrift() = false, want true")
+	}
+}
+
+func TestDiff_InSync(t *testing.T) {
+	report := Diff(
+		[]LocalService{{Name: "api", Port: 8080, EnvKeys: []string{"A"}}},
+		[]CloudService{{Name: "api", Port: 8080, EnvKeys: []string{"A", "WEBSITES_PORT"}}},
+		PlatformEnvKeys,
+	)
+	if report.HasDrift() {
+		t.Errorf("HasDrift() = true, want false: %+v", report.Services)
+	}
+
+	// Without the ignore list, platform-managed keys are reported.
+	report = Diff(
+		[]LocalService{{Name: "api", Port: 8080, EnvKeys: []string{"A"}}},
+		[]CloudService{{Name: "api", Port: 8080, EnvKeys: []string{"A", "WEBSITES_PORT"}}},
+		nil,
+	)
+	if !report.HasDrift() {
+		t.Error("HasDrift() = false, want true when platform keys are not ignored")
+	}
+}
+
+func TestParseContainerApp(t *testing.T) {
+	body := `{
+		"properties": {
+			"configuration": {"ingress": {"targetPort": 8080}},
+			"template": {"containers": [
+				{"env": [{"name": "B"}, {"name": "A", "secretRef": "a"}]},
+				{"env": [{"name": "A"}, {"name": "C"}]}
+			]}
+		}
+	}`
+
+	var app containerAppResponse
+	if err := json.Unmarshal([]byte(body), &app); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	port, keys := parseContainerApp(&app)
+	if port != 8080 {
+		t.Errorf("port = %d, want 8080", port)
+	}
+	if want := []string{"A", "B", "C"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+}
+
+func TestParseAppSettings(t *testing.T) {
+	port, keys, err := parseAppSettings(&appSettingsResponse{Properties: map[string]string{
+		"WEBSITES_PORT": "8000",
+		"API_KEY":       "secret",
+	}})
+	if err != nil {
+		t.Fatalf("parseAppSettings() failed: %v", err)
+	}
+	if port != 8000 {
+		t.Errorf("port = %d, want 8000", port)
+	}
+	if want := []string{"API_KEY", "WEBSITES_PORT"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+
+	if _, _, err := parseAppSettings(&appSettingsResponse{Properties: map[string]string{"WEBSITES_PORT": "abc"}}); err == nil {
+		t.Error("expected error for invalid WEBSITES_PORT")
+	}
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	corecache "github.com/jongio/azd-core/cache"
+)
+
+const (
+	// DepsCacheVersion tracks the deps cache schema version.
+	DepsCacheVersion = "1.0"
+	// depsCacheKey is the key used for the deps cache entry
+	depsCacheKey = "deps_cache"
+)
+
+// DepsCache remembers the lock file fingerprint each project's dependencies were last
+// installed from, so azd app deps can skip projects whose lock files haven't changed.
+// A nil DepsCache never reports a project as up to date. All methods are safe for concurrent use.
+type DepsCache struct {
+	manager  *corecache.Manager
+	mu       sync.Mutex
+	projects map[string]string // project key -> fingerprint
+	dirty    bool
+}
+
+// depsCacheData is the cached data of the deps cache.
+type depsCacheData struct {
+	Projects map[string]string `json:"projects"`
+}
+
+// LoadDepsCache loads the deps cache of the project in projectDir, stored in .azure/cache.
+// A missing or unreadable cache is empty; everything is installed again.
+func LoadDepsCache(projectDir string) *DepsCache {
+	c := &DepsCache{
+		manager: corecache.NewManager(corecache.Options{
+			Dir:     ProjectDir(projectDir),
+			Version: DepsCacheVersion,
+		}),
+		projects: make(map[string]string),
+	}
+
+	var data depsCacheData
+	if _, err := c.manager.Get(depsCacheKey, &data); err != nil {
+		slog.Debug("ignoring unreadable deps cache", "error", err)
+		return c
+	}
+	if data.Projects != nil {
+		c.projects = data.Projects
+	}
+	return c
+}
+
+// UpToDate reports whether the project's dependencies were last installed from fingerprint.
+// An empty fingerprint is never up to date.
+func (c *DepsCache) UpToDate(project, fingerprint string) bool {
+	if c == nil || fingerprint == "" {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.projects[project] == fingerprint
+}
+
+// Record stores the fingerprint the project's dependencies were installed from.
+// An empty fingerprint forgets the project, e.g. after a failed install.
+func (c *DepsCache) Record(project, fingerprint string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.projects[project] == fingerprint {
+		return
+	}
+	if fingerprint == "" {
+		delete(c.projects, project)
+	} else {
+		c.projects[project] = fingerprint
+	}
+	c.dirty = true
+}
+
+// Save persists the cache if it changed since it was loaded.
+func (c *DepsCache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	if err := c.manager.Set(depsCacheKey, depsCacheData{Projects: c.projects}); err != nil {
+		return fmt.Errorf("failed to save deps cache: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
@@ -0,0 +1,49 @@
+package cache
+
+import "testing"
+
+func TestDepsCache(t *testing.T) {
+	projectDir := t.TempDir()
+
+	c := LoadDepsCache(projectDir)
+	if c.UpToDate("node:/app/web", "abc") {
+		t.Error("UpToDate() = true for an empty cache")
+	}
+
+	c.Record("node:/app/web", "abc")
+	c.Record("python:/app/api", "def")
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	c = LoadDepsCache(projectDir)
+	if !c.UpToDate("node:/app/web", "abc") {
+		t.Error("UpToDate() = false for the recorded fingerprint")
+	}
+	if c.UpToDate("node:/app/web", "changed") {
+		t.Error("UpToDate() = true for a changed fingerprint")
+	}
+	if c.UpToDate("node:/app/web", "") {
+		t.Error("UpToDate() = true for an empty fingerprint")
+	}
+
+	// An empty fingerprint forgets the project
+	c.Record("python:/app/api", "")
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if LoadDepsCache(projectDir).UpToDate("python:/app/api", "def") {
+		t.Error("UpToDate() = true for a forgotten project")
+	}
+}
+
+func TestDepsCache_Nil(t *testing.T) {
+	var c *DepsCache
+	c.Record("node:/app/web", "abc")
+	if c.UpToDate("node:/app/web", "abc") {
+		t.Error("UpToDate() = true for a nil cache")
+	}
+	if err := c.Save(); err != nil {
+		t.Errorf("Save() error = %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GlobalDir returns the directory of caches shared by all projects, ~/.azd/app/cache.
+// This is a variable to allow test overrides.
+var GlobalDir = func() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".azd", "app", "cache"), nil
+}
+
+// ProjectDir returns the cache directory of the project in projectDir, .azure/cache.
+func ProjectDir(projectDir string) string {
+	return filepath.Join(projectDir, ".azure", "cache")
+}
+
+// Entry describes a file in a cache directory.
+type Entry struct {
+	Name     string    `json:"name"` // Cache key, e.g. reqs_cache
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	CachedAt time.Time `json:"cachedAt,omitzero"` // Zero when the file can't be parsed
+	Version  string    `json:"version,omitempty"` // Cache schema version
+	Corrupt  bool      `json:"corrupt,omitempty"` // The file isn't a valid cache entry
+}
+
+// ListEntries returns the cache files in dir sorted by name. A missing directory has no entries.
+func ListEntries(dir string) ([]Entry, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		entry := Entry{
+			Name: strings.TrimSuffix(file.Name(), ".json"),
+			Path: filepath.Join(dir, file.Name()),
+			Size: info.Size(),
+		}
+		readEntryMetadata(&entry)
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// readEntryMetadata sets when the entry was cached and its version from the file's metadata,
+// or marks it as corrupt when the file can't be parsed.
+func readEntryMetadata(entry *Entry) {
+	// #nosec G304 -- path is a file inside a cache directory
+	data, err := os.ReadFile(entry.Path)
+	if err != nil {
+		entry.Corrupt = true
+		return
+	}
+
+	var envelope struct {
+		Metadata *struct {
+			CachedAt time.Time `json:"cachedAt"`
+			Version  string    `json:"version"`
+		} `json:"_cache"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Metadata == nil {
+		entry.Corrupt = true
+		return
+	}
+	entry.CachedAt = envelope.Metadata.CachedAt
+	entry.Version = envelope.Metadata.Version
+}
+
+// ClearDir removes the cache files in dir and returns how many were removed.
+// Other files and subdirectories are left alone.
+func ClearDir(dir string) (int, error) {
+	entries, err := ListEntries(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if err := os.Remove(entry.Path); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("failed to remove cache file %s: %w", entry.Path, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corecache "github.com/jongio/azd-core/cache"
+)
+
+func TestListEntries(t *testing.T) {
+	dir := t.TempDir()
+
+	m := corecache.NewManager(corecache.Options{Dir: dir, TTL: time.Hour, Version: CacheVersion})
+	if err := m.Set("reqs_cache", ReqsCache{Version: CacheVersion}); err != nil {
+		t.Fatalf("failed to write cache entry: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{not json"), 0600); err != nil {
+		t.Fatalf("failed to write corrupt entry: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a cache file"), 0600); err != nil {
+		t.Fatalf("failed to write other file: %v", err)
+	}
+
+	entries, err := ListEntries(dir)
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ListEntries() returned %d entries, want 2: %+v", len(entries), entries)
+	}
+
+	broken, reqs := entries[0], entries[1]
+	if broken.Name != "broken" || !broken.Corrupt {
+		t.Errorf("entries[0] = %+v, want corrupt entry named broken", broken)
+	}
+	if reqs.Name != "reqs_cache" || reqs.Corrupt {
+		t.Errorf("entries[1] = %+v, want valid entry named reqs_cache", reqs)
+	}
+	if reqs.Version != CacheVersion {
+		t.Errorf("Version = %q, want %q", reqs.Version, CacheVersion)
+	}
+	if time.Since(reqs.CachedAt) > time.Minute {
+		t.Errorf("CachedAt = %v, want about now", reqs.CachedAt)
+	}
+	if reqs.Size == 0 || reqs.Path != filepath.Join(dir, "reqs_cache.json") {
+		t.Errorf("entry = %+v, want size and path of reqs_cache.json", reqs)
+	}
+}
+
+func TestListEntriesMissingDir(t *testing.T) {
+	entries, err := ListEntries(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("ListEntries() = %v, %v, want no entries and no error", entries, err)
+	}
+}
+
+func TestClearDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"reqs_cache.json", "other.json", "keep.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	removed, err := ClearDir(dir)
+	if err != nil {
+		t.Fatalf("ClearDir() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("ClearDir() removed %d files, want 2", removed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "keep.txt")); err != nil {
+		t.Errorf("ClearDir() should leave other files alone: %v", err)
+	}
+
+	if removed, err := ClearDir(filepath.Join(dir, "missing")); err != nil || removed != 0 {
+		t.Errorf("ClearDir() of missing directory = %d, %v, want 0, nil", removed, err)
+	}
+}
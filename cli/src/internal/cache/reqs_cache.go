@@ -5,14 +5,17 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	corecache "github.com/jongio/azd-core/cache"
 )
 
 const (
-	// CacheVersion tracks the cache schema version for invalidation on breaking changes
-	CacheVersion = "1.0"
+	// CacheVersion tracks the cache schema version for invalidation on breaking changes.
+	// 1.1: Required describes the full version constraint (minVersion, version range, maxVersion)
+	// 1.2: Results record the executable they were checked with
+	CacheVersion = "1.2"
 	// DefaultCacheTTL is the default cache time-to-live
 	DefaultCacheTTL = 1 * time.Hour
 	// cacheKey is the key used for the reqs cache entry
@@ -24,20 +27,27 @@ type ReqsCache struct {
 	Version       string            `json:"version"` // Schema version for invalidation
 	Timestamp     time.Time         `json:"timestamp"`
 	AzureYamlHash string            `json:"azureYamlHash"`
+	Scope         string            `json:"scope,omitempty"` // Services the results were checked for (empty = all)
 	Results       []CachedReqResult `json:"results"`
 	AllPassed     bool              `json:"allPassed"`
 }
 
 // CachedReqResult represents a cached req check result.
 type CachedReqResult struct {
-	Name       string `json:"name"`
-	Installed  bool   `json:"installed"`
-	Version    string `json:"version,omitempty"`
-	Required   string `json:"required"`
-	Satisfied  bool   `json:"satisfied"`
-	Running    bool   `json:"running,omitempty"`
-	CheckedRun bool   `json:"checkedRunning,omitempty"`
-	Message    string `json:"message,omitempty"`
+	Name       string   `json:"name"`
+	Installed  bool     `json:"installed"`
+	Version    string   `json:"version,omitempty"`
+	Required   string   `json:"required"`
+	Satisfied  bool     `json:"satisfied"`
+	Running    bool     `json:"running,omitempty"`
+	CheckedRun bool     `json:"checkedRunning,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	Issues     []string `json:"issues,omitempty"`
+	Severity   string   `json:"severity,omitempty"`
+	// BinaryPath and BinaryModTime identify the executable the result was checked with.
+	// A result is stale when the tool now resolves to another path or was modified.
+	BinaryPath    string    `json:"binaryPath,omitempty"`
+	BinaryModTime time.Time `json:"binaryModTime,omitzero"`
 }
 
 // CacheManager handles reqs cache operations.
@@ -54,6 +64,21 @@ type CacheStats struct {
 	Misses int `json:"misses"`
 }
 
+// processStats counts the hits and misses of every cache manager in the process,
+// which outlive the short-lived managers created per command.
+var processStats struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// ProcessStats returns the hit/miss statistics of all cache managers in the process.
+func ProcessStats() CacheStats {
+	return CacheStats{
+		Hits:   int(processStats.hits.Load()),
+		Misses: int(processStats.misses.Load()),
+	}
+}
+
 // CacheOptions configures the cache manager.
 type CacheOptions struct {
 	CacheDir string        // Custom cache directory (optional)
@@ -144,8 +169,14 @@ func findAzureDir(startDir string) string {
 	return ""
 }
 
-// GetCachedResults retrieves cached reqs check results if valid.
+// GetCachedResults retrieves cached reqs check results for all services if valid.
 func (cm *CacheManager) GetCachedResults(azureYamlPath string) (*ReqsCache, bool, error) {
+	return cm.GetScopedResults(azureYamlPath, "")
+}
+
+// GetScopedResults retrieves cached reqs check results if valid for the given scope,
+// which identifies the services the requirements were checked for ("" = all services).
+func (cm *CacheManager) GetScopedResults(azureYamlPath, scope string) (*ReqsCache, bool, error) {
 	// If cache is disabled, return cache miss
 	if !cm.enabled {
 		return nil, false, nil
@@ -173,6 +204,12 @@ func (cm *CacheManager) GetCachedResults(azureYamlPath string) (*ReqsCache, bool
 		return nil, false, nil // azure.yaml has changed - cache is invalid
 	}
 
+	// Results checked for other services don't apply
+	if cache.Scope != scope {
+		cm.recordMiss()
+		return nil, false, nil
+	}
+
 	cm.recordHit()
 	return &cache, true, nil
 }
@@ -182,6 +219,7 @@ func (cm *CacheManager) recordHit() {
 	cm.statsMu.Lock()
 	cm.stats.Hits++
 	cm.statsMu.Unlock()
+	processStats.hits.Add(1)
 }
 
 // recordMiss records a cache miss (helper to avoid duplicate lock code)
@@ -189,11 +227,18 @@ func (cm *CacheManager) recordMiss() {
 	cm.statsMu.Lock()
 	cm.stats.Misses++
 	cm.statsMu.Unlock()
+	processStats.misses.Add(1)
 }
 
-// SaveResults saves reqs check results to cache.
+// SaveResults saves reqs check results for all services to cache.
 // Only caches successful results (allPassed=true) to avoid blocking users with stale failures.
 func (cm *CacheManager) SaveResults(azureYamlPath string, results []CachedReqResult, allPassed bool) error {
+	return cm.SaveScopedResults(azureYamlPath, "", results, allPassed)
+}
+
+// SaveScopedResults saves reqs check results for the given scope to cache, replacing
+// results cached for any other scope.
+func (cm *CacheManager) SaveScopedResults(azureYamlPath, scope string, results []CachedReqResult, allPassed bool) error {
 	// If cache is disabled, skip saving
 	if !cm.enabled {
 		return nil
@@ -216,6 +261,7 @@ func (cm *CacheManager) SaveResults(azureYamlPath string, results []CachedReqRes
 		Version:       CacheVersion,
 		Timestamp:     time.Now(),
 		AzureYamlHash: hash,
+		Scope:         scope,
 		Results:       results,
 		AllPassed:     allPassed,
 	}
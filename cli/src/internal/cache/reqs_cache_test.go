@@ -256,6 +256,38 @@ func TestGetCachedResultsInvalidHash(t *testing.T) {
 	}
 }
 
+func TestGetScopedResults(t *testing.T) {
+	tempDir := t.TempDir()
+	cm, err := NewCacheManagerWithOptions(CacheOptions{
+		CacheDir: tempDir,
+		Enabled:  true,
+		TTL:      time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewCacheManagerWithOptions() failed: %v", err)
+	}
+
+	azureYamlPath := filepath.Join(tempDir, "azure.yaml")
+	if err := os.WriteFile(azureYamlPath, []byte("test: content"), 0600); err != nil {
+		t.Fatalf("failed to create azure.yaml: %v", err)
+	}
+
+	results := []CachedReqResult{{Name: "node", Installed: true, Version: "20.0.0", Required: "18", Satisfied: true}}
+	if err := cm.SaveScopedResults(azureYamlPath, "api", results, true); err != nil {
+		t.Fatalf("SaveScopedResults() error = %v", err)
+	}
+
+	if _, valid, err := cm.GetScopedResults(azureYamlPath, "api"); err != nil || !valid {
+		t.Errorf("GetScopedResults(api) valid = %v, err = %v, want a cache hit", valid, err)
+	}
+	if _, valid, _ := cm.GetScopedResults(azureYamlPath, "web"); valid {
+		t.Error("GetScopedResults(web) should miss results cached for api")
+	}
+	if _, valid, _ := cm.GetCachedResults(azureYamlPath); valid {
+		t.Error("GetCachedResults() should miss results cached for api only")
+	}
+}
+
 func TestGetCachedResultsExpired(t *testing.T) {
 	tempDir := t.TempDir()
 	// Use a very short TTL so cache expires immediately
@@ -647,6 +679,8 @@ func TestCacheStats(t *testing.T) {
 		t.Fatalf("failed to create azure.yaml: %v", err)
 	}
 
+	before := ProcessStats()
+
 	// Initial stats should be zero
 	stats := cm.GetStats()
 	if stats.Hits != 0 || stats.Misses != 0 {
@@ -680,6 +714,12 @@ func TestCacheStats(t *testing.T) {
 	if stats.Hits != 0 || stats.Misses != 0 {
 		t.Errorf("After clear, stats = %+v, want Hits: 0, Misses: 0", stats)
 	}
+
+	// Process-wide stats keep counting across managers and clears
+	after := ProcessStats()
+	if after.Hits-before.Hits != 1 || after.Misses-before.Misses != 1 {
+		t.Errorf("ProcessStats() grew by %d hits and %d misses, want 1 and 1", after.Hits-before.Hits, after.Misses-before.Misses)
+	}
 }
 
 func TestCacheVersionMismatch(t *testing.T) {
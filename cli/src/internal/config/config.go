@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 
 	"github.com/jongio/azd-core/fileutil"
@@ -24,6 +25,8 @@ type Config struct {
 // AppConfig represents app-level configuration.
 type AppConfig struct {
 	Dashboard *DashboardConfig `json:"dashboard,omitempty"`
+	Telemetry *TelemetryConfig `json:"telemetry,omitempty"`
+	Updates   *UpdatesConfig   `json:"updates,omitempty"`
 }
 
 // DashboardConfig represents dashboard-specific configuration.
@@ -31,6 +34,16 @@ type DashboardConfig struct {
 	Browser string `json:"browser,omitempty"` // Browser target: default, system, none
 }
 
+// TelemetryConfig represents the usage telemetry opt-in.
+type TelemetryConfig struct {
+	Enabled bool `json:"enabled"` // Set with azd app config telemetry on|off
+}
+
+// UpdatesConfig represents the check for newer releases.
+type UpdatesConfig struct {
+	Check bool `json:"check"` // Set with azd app config updates on|off
+}
+
 // GetConfigPath returns the path to the azd config file.
 // Returns ~/.azd/config.json (or OS-equivalent).
 // This is a variable to allow test overrides.
@@ -121,7 +134,7 @@ func ResetGlobal() {
 }
 
 // Get retrieves a config value by key path.
-// Supported keys: "app.dashboard.browser"
+// Supported keys: "app.dashboard.browser", "app.telemetry.enabled", "app.updates.check"
 func Get(key string) (string, error) {
 	config := GetGlobal()
 	configMu.RLock()
@@ -133,13 +146,23 @@ func Get(key string) (string, error) {
 			return config.App.Dashboard.Browser, nil
 		}
 		return "", nil
+	case "app.telemetry.enabled":
+		if config.App != nil && config.App.Telemetry != nil {
+			return strconv.FormatBool(config.App.Telemetry.Enabled), nil
+		}
+		return "", nil
+	case "app.updates.check":
+		if config.App != nil && config.App.Updates != nil {
+			return strconv.FormatBool(config.App.Updates.Check), nil
+		}
+		return "", nil
 	default:
 		return "", fmt.Errorf("unknown config key: %s", key)
 	}
 }
 
 // Set sets a config value by key path and saves to disk.
-// Supported keys: "app.dashboard.browser"
+// Supported keys: "app.dashboard.browser", "app.telemetry.enabled", "app.updates.check"
 func Set(key, value string) error {
 	config := GetGlobal()
 	configMu.Lock()
@@ -154,6 +177,24 @@ func Set(key, value string) error {
 			config.App.Dashboard = &DashboardConfig{}
 		}
 		config.App.Dashboard.Browser = value
+	case "app.telemetry.enabled":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %q (want true or false)", key, value)
+		}
+		if config.App == nil {
+			config.App = &AppConfig{}
+		}
+		config.App.Telemetry = &TelemetryConfig{Enabled: enabled}
+	case "app.updates.check":
+		check, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %q (want true or false)", key, value)
+		}
+		if config.App == nil {
+			config.App = &AppConfig{}
+		}
+		config.App.Updates = &UpdatesConfig{Check: check}
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
@@ -162,7 +203,7 @@ func Set(key, value string) error {
 }
 
 // Unset removes a config value by key path and saves to disk.
-// Supported keys: "app.dashboard.browser"
+// Supported keys: "app.dashboard.browser", "app.telemetry.enabled", "app.updates.check"
 func Unset(key string) error {
 	config := GetGlobal()
 	configMu.Lock()
@@ -173,6 +214,14 @@ func Unset(key string) error {
 		if config.App != nil && config.App.Dashboard != nil {
 			config.App.Dashboard.Browser = ""
 		}
+	case "app.telemetry.enabled":
+		if config.App != nil {
+			config.App.Telemetry = nil
+		}
+	case "app.updates.check":
+		if config.App != nil {
+			config.App.Updates = nil
+		}
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
@@ -196,3 +245,27 @@ func SetDashboardBrowser(browser string) error {
 func UnsetDashboardBrowser() error {
 	return Unset("app.dashboard.browser")
 }
+
+// GetTelemetryEnabled reports whether the user opted in to usage telemetry.
+// Returns false if not set.
+func GetTelemetryEnabled() bool {
+	value, _ := Get("app.telemetry.enabled")
+	return value == "true"
+}
+
+// SetTelemetryEnabled saves the usage telemetry opt-in.
+func SetTelemetryEnabled(enabled bool) error {
+	return Set("app.telemetry.enabled", strconv.FormatBool(enabled))
+}
+
+// GetUpdateCheckEnabled reports whether to check for newer releases.
+// Returns true if not set.
+func GetUpdateCheckEnabled() bool {
+	value, _ := Get("app.updates.check")
+	return value != "false"
+}
+
+// SetUpdateCheckEnabled saves whether to check for newer releases.
+func SetUpdateCheckEnabled(enabled bool) error {
+	return Set("app.updates.check", strconv.FormatBool(enabled))
+}
@@ -245,3 +245,77 @@ func endsWithPath(fullPath string, parts ...string) bool {
 func endsWith(s, suffix string) bool {
 	return len(s) >= len(suffix) && s[len(s)-len(suffix):] == suffix
 }
+
+func TestTelemetryHelpers(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".azd", "config.json")
+	originalGetConfigPath := GetConfigPath
+	GetConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+	defer func() {
+		GetConfigPath = originalGetConfigPath
+	}()
+	ResetGlobal()
+	defer ResetGlobal()
+
+	if GetTelemetryEnabled() {
+		t.Error("GetTelemetryEnabled() = true before opting in")
+	}
+
+	if err := SetTelemetryEnabled(true); err != nil {
+		t.Fatalf("SetTelemetryEnabled(true) error = %v", err)
+	}
+	ResetGlobal()
+	if !GetTelemetryEnabled() {
+		t.Error("GetTelemetryEnabled() = false after opting in")
+	}
+
+	if err := Set("app.telemetry.enabled", "sometimes"); err == nil {
+		t.Error("Set() with a non-boolean telemetry value should return error")
+	}
+
+	if err := Unset("app.telemetry.enabled"); err != nil {
+		t.Fatalf("Unset() error = %v", err)
+	}
+	ResetGlobal()
+	if GetTelemetryEnabled() {
+		t.Error("GetTelemetryEnabled() = true after unset")
+	}
+}
+
+func TestUpdateCheckHelpers(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".azd", "config.json")
+	originalGetConfigPath := GetConfigPath
+	GetConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+	defer func() {
+		GetConfigPath = originalGetConfigPath
+	}()
+	ResetGlobal()
+	defer ResetGlobal()
+
+	if !GetUpdateCheckEnabled() {
+		t.Error("GetUpdateCheckEnabled() = false before it is set")
+	}
+
+	if err := SetUpdateCheckEnabled(false); err != nil {
+		t.Fatalf("SetUpdateCheckEnabled(false) error = %v", err)
+	}
+	ResetGlobal()
+	if GetUpdateCheckEnabled() {
+		t.Error("GetUpdateCheckEnabled() = true after turning it off")
+	}
+
+	if err := Set("app.updates.check", "daily"); err == nil {
+		t.Error("Set() with a non-boolean update check value should return error")
+	}
+
+	if err := Unset("app.updates.check"); err != nil {
+		t.Fatalf("Unset() error = %v", err)
+	}
+	ResetGlobal()
+	if !GetUpdateCheckEnabled() {
+		t.Error("GetUpdateCheckEnabled() = false after unset")
+	}
+}
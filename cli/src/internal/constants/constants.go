@@ -19,6 +19,9 @@ const (
 	ServerStartupDelay = 100 * time.Millisecond
 	// DashboardAPITimeout is the timeout for dashboard API HTTP client requests
 	DashboardAPITimeout = 5 * time.Second
+	// DashboardServiceOperationTimeout is the timeout for dashboard API requests that
+	// stop and start a service, which wait for the service to exit and start again
+	DashboardServiceOperationTimeout = 60 * time.Second
 )
 
 // Severity levels
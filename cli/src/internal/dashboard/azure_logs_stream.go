@@ -42,7 +42,7 @@ func (s *Server) handleAzureLogsStream(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Capture rate limiter early to avoid race with Stop()
-	rl := s.rateLimiter
+	rl := s.connectionLimiter()
 
 	// Upgrade connection to WebSocket
 	rawConn, err := acceptWebSocket(w, r, rl)
@@ -1,11 +1,13 @@
 package dashboard
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -26,12 +28,17 @@ type Client struct {
 
 // NewClient creates a new dashboard API client for the given project directory.
 // Returns nil if the dashboard is not running for this project.
-// It first tries azdconfig (gRPC), then falls back to reading ~/.azd/config.json directly.
+// It first tries the session file of a detached 'azd app run', then azdconfig (the selected
+// state backend), then falls back to reading ~/.azd/config.json directly.
 func NewClient(ctx context.Context, projectDir string) (*Client, error) {
+	if port := sessionDashboardPort(projectDir); port > 0 {
+		return NewClientWithPort(port), nil
+	}
+
 	projectHash := azdconfig.ProjectHash(projectDir)
 
 	// Try azdconfig first (works when running as azd extension)
-	configClient, err := azdconfig.NewClient(ctx)
+	configClient, err := azdconfig.Open(ctx)
 	if err == nil {
 		defer configClient.Close()
 		dashboardPort, portErr := configClient.GetDashboardPort(projectHash)
@@ -162,6 +169,73 @@ func (c *Client) StopAllServices(ctx context.Context) error {
 	return nil
 }
 
+// StartService asks the dashboard to start a single stopped service. The service is started
+// by the `azd app run` session that owns it, so the session tracks its process.
+func (c *Client) StartService(ctx context.Context, serviceName string) error {
+	return c.serviceOperation(ctx, "start", serviceName)
+}
+
+// RestartService asks the dashboard to restart a single service. The service is restarted
+// by the `azd app run` session that owns it, so it keeps its port assignment and log buffer.
+func (c *Client) RestartService(ctx context.Context, serviceName string) error {
+	return c.serviceOperation(ctx, "restart", serviceName)
+}
+
+// serviceOperation asks the dashboard to start or restart a single service.
+func (c *Client) serviceOperation(ctx context.Context, operation, serviceName string) error {
+	reqURL := fmt.Sprintf("%s/api/services/%s?service=%s", c.baseURL, operation, url.QueryEscape(serviceName))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, nil)
+	if err != nil {
+		return err
+	}
+
+	// Starting waits for the service to stop and start, which takes longer than a query
+	httpClient := &http.Client{Timeout: constants.DashboardServiceOperationTimeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to %s service: %s", operation, readAPIError(resp))
+	}
+
+	return nil
+}
+
+// readAPIError returns the error message of a failed dashboard API response.
+func readAPIError(resp *http.Response) string {
+	body, _ := io.ReadAll(resp.Body)
+	var apiErr ErrorResponse
+	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Error != "" {
+		return apiErr.Error
+	}
+	return fmt.Sprintf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// Shutdown asks the dashboard to stop the `azd app run` session that owns it,
+// which stops the dashboard and any services it still runs.
+func (c *Client) Shutdown(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/shutdown", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to shut down dashboard: status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
 // IsDashboardRunning checks if a dashboard is running for the given project.
 func IsDashboardRunning(ctx context.Context, projectDir string) bool {
 	client, err := NewClient(ctx, projectDir)
@@ -174,10 +248,14 @@ func IsDashboardRunning(ctx context.Context, projectDir string) bool {
 
 // GetDashboardPort returns the dashboard port for a project, or 0 if not running.
 func GetDashboardPort(ctx context.Context, projectDir string) int {
+	if port := sessionDashboardPort(projectDir); port > 0 {
+		return port
+	}
+
 	projectHash := azdconfig.ProjectHash(projectDir)
 
-	// Try azdconfig first (gRPC)
-	configClient, err := azdconfig.NewClient(ctx)
+	// Try azdconfig first (selected state backend)
+	configClient, err := azdconfig.Open(ctx)
 	if err == nil {
 		defer configClient.Close()
 		port, err := configClient.GetDashboardPort(projectHash)
@@ -191,6 +269,32 @@ func GetDashboardPort(ctx context.Context, projectDir string) int {
 	return port
 }
 
+// sessionDashboardPort returns the dashboard port recorded by a detached 'azd app run' session
+// of the project, or 0 if none is running. A detached session outlives the azd process that
+// started it, so it can't rely on azd's config service being reachable.
+func sessionDashboardPort(projectDir string) int {
+	session, err := service.ReadSession(projectDir)
+	if err != nil || session == nil {
+		return 0
+	}
+	return session.DashboardPort()
+}
+
+// ClearDashboardPort removes the dashboard registration for a project, e.g. one left
+// behind by an 'azd app run' session that didn't exit cleanly.
+func ClearDashboardPort(ctx context.Context, projectDir string) error {
+	configClient, err := azdconfig.Open(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open config: %w", err)
+	}
+	defer configClient.Close()
+
+	if err := configClient.ClearDashboardPort(azdconfig.ProjectHash(projectDir)); err != nil {
+		return fmt.Errorf("failed to clear dashboard port: %w", err)
+	}
+	return nil
+}
+
 // azdConfigPath returns the path to the azd config file.
 // This is a variable to allow test overrides.
 var azdConfigPath = func() (string, error) {
@@ -253,6 +357,8 @@ func (c *Client) GetWebSocketURL() string {
 }
 
 // StreamLogs connects to the dashboard's log stream via WebSocket and sends log entries to the provided channel.
+// When the WebSocket connection can't be established, for example behind a proxy that blocks
+// WebSockets, it falls back to the Server-Sent Events stream.
 // The serviceName parameter filters logs to a specific service (empty string for all services).
 // The function blocks until the context is canceled or an error occurs.
 // The caller is responsible for closing the logs channel after StreamLogs returns.
@@ -272,7 +378,16 @@ func (c *Client) StreamLogs(ctx context.Context, serviceName string, logs chan<-
 		defer resp.Body.Close() //nolint:errcheck // best-effort cleanup
 	}
 	if err != nil {
-		return fmt.Errorf("failed to connect to log stream: %w", err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if sseErr := c.streamLogsSSE(ctx, serviceName, logs); sseErr != nil {
+			if ctx.Err() != nil {
+				return sseErr
+			}
+			return fmt.Errorf("failed to connect to log stream: %w (Server-Sent Events fallback: %v)", err, sseErr)
+		}
+		return nil
 	}
 	defer func() { _ = conn.Close(websocket.StatusNormalClosure, "client closing") }()
 
@@ -307,6 +422,67 @@ func (c *Client) StreamLogs(ctx context.Context, serviceName string, logs chan<-
 	}
 }
 
+// streamLogsSSE streams logs from the dashboard's /api/logs/sse endpoint, which serves the same
+// entries as the WebSocket stream over plain HTTP, and sends them to the provided channel.
+func (c *Client) streamLogsSSE(ctx context.Context, serviceName string, logs chan<- service.LogEntry) error {
+	sseURL := c.baseURL + "/api/logs/sse"
+	if serviceName != "" {
+		sseURL += "?service=" + url.QueryEscape(serviceName)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sseURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream stays open until ctx is canceled, so don't use the client's request timeout
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("dashboard returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024) // Allow long log lines
+	for scanner.Scan() {
+		// Each event is a single "data:" line; skip blank lines and heartbeat comments
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		var entry service.LogEntry
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(data, " ")), &entry); err != nil {
+			return fmt.Errorf("failed to decode log entry: %w", err)
+		}
+
+		// Send to channel (non-blocking with timeout)
+		select {
+		case logs <- entry:
+		case <-time.After(100 * time.Millisecond):
+			// Drop if channel is full/slow
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read log entry: %w", err)
+	}
+	return nil
+}
+
 // GetAzureLogs retrieves Azure logs from the dashboard's /api/azure/logs endpoint.
 // The services parameter filters logs to specific services (nil for all services).
 // The tail parameter limits the number of logs returned.
@@ -1,11 +1,13 @@
 package dashboard
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/jongio/azd-app/cli/src/internal/azdconfig"
+	"github.com/jongio/azd-app/cli/src/internal/service"
 )
 
 func TestReadDashboardPortFromAzdConfig(t *testing.T) {
@@ -116,3 +118,22 @@ func TestReadDashboardPortFromAzdConfig_NoFile(t *testing.T) {
 		t.Errorf("readDashboardPortFromAzdConfig() port = %v, want 0", port)
 	}
 }
+
+func TestGetDashboardPort_DetachedSession(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := service.WriteSession(projectDir, "http://localhost:41234", nil); err != nil {
+		t.Fatalf("WriteSession() error = %v", err)
+	}
+	defer service.RemoveSession(projectDir)
+
+	if port := GetDashboardPort(context.Background(), projectDir); port != 41234 {
+		t.Errorf("GetDashboardPort() = %d, want the port recorded by the session", port)
+	}
+	client, err := NewClient(context.Background(), projectDir)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.baseURL != "http://localhost:41234" {
+		t.Errorf("baseURL = %q, want http://localhost:41234", client.baseURL)
+	}
+}
@@ -1,13 +1,21 @@
 package dashboard
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jongio/azd-app/cli/src/internal/service"
 	"github.com/jongio/azd-core/registry"
 )
 
@@ -544,3 +552,307 @@ func TestBroadcastUpdate_NilServices(t *testing.T) {
 	// Should not panic with nil services
 	srv.BroadcastUpdate(nil)
 }
+
+func TestHandleShutdown(t *testing.T) {
+	srv := GetServer(t.TempDir())
+	defer func() { _ = srv.Stop() }()
+
+	// Without a handler the endpoint is rejected
+	w := httptest.NewRecorder()
+	srv.handleShutdown(w, httptest.NewRequest(http.MethodPost, "/api/shutdown", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status without handler = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	called := make(chan struct{})
+	srv.SetShutdownHandler(func() { close(called) })
+
+	w = httptest.NewRecorder()
+	srv.handleShutdown(w, httptest.NewRequest(http.MethodPost, "/api/shutdown", nil))
+	if w.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+
+	select {
+	case <-called:
+	case <-time.After(2 * time.Second):
+		t.Fatal("shutdown handler was not called")
+	}
+}
+
+func TestClientShutdown(t *testing.T) {
+	srv := GetServer(t.TempDir())
+	defer func() { _ = srv.Stop() }()
+
+	called := make(chan struct{})
+	srv.SetShutdownHandler(func() { close(called) })
+
+	ts := httptest.NewServer(srv.mux)
+	defer ts.Close()
+
+	port, err := strconv.Atoi(ts.URL[strings.LastIndex(ts.URL, ":")+1:])
+	if err != nil {
+		t.Fatalf("failed to parse test server port: %v", err)
+	}
+
+	if err := NewClientWithPort(port).Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	select {
+	case <-called:
+	case <-time.After(2 * time.Second):
+		t.Fatal("shutdown handler was not called")
+	}
+}
+
+func TestClientRestartService(t *testing.T) {
+	var gotService string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/services/restart" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		gotService = r.URL.Query().Get("service")
+		if gotService == "missing" {
+			NotFound(w, "Service 'missing' not found")
+			return
+		}
+		WriteJSONSuccess(w, map[string]interface{}{"success": true})
+	}))
+	defer ts.Close()
+
+	port, err := strconv.Atoi(ts.URL[strings.LastIndex(ts.URL, ":")+1:])
+	if err != nil {
+		t.Fatalf("failed to parse test server port: %v", err)
+	}
+	client := NewClientWithPort(port)
+
+	if err := client.RestartService(context.Background(), "api"); err != nil {
+		t.Fatalf("RestartService() error = %v", err)
+	}
+	if gotService != "api" {
+		t.Errorf("service = %q, want %q", gotService, "api")
+	}
+
+	err = client.RestartService(context.Background(), "missing")
+	if err == nil || !strings.Contains(err.Error(), "Service 'missing' not found") {
+		t.Errorf("RestartService() error = %v, want the dashboard's error message", err)
+	}
+}
+
+func TestHandleServiceActionRouter(t *testing.T) {
+	tempDir := t.TempDir()
+	srv := GetServer(tempDir)
+
+	reg := registry.GetRegistry(tempDir)
+	if err := reg.Register(&registry.ServiceRegistryEntry{
+		Name:       "api",
+		ProjectDir: tempDir,
+		Status:     "stopped",
+	}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"stop already stopped service", http.MethodPost, "/api/services/api/stop", http.StatusConflict},
+		{"stop unknown service", http.MethodPost, "/api/services/missing/stop", http.StatusNotFound},
+		{"restart unknown service", http.MethodPost, "/api/services/missing/restart", http.StatusNotFound},
+		{"unknown action", http.MethodPost, "/api/services/api/delete", http.StatusNotFound},
+		{"missing action", http.MethodPost, "/api/services/api", http.StatusNotFound},
+		{"invalid service name", http.MethodPost, "/api/services/bad$name/stop", http.StatusBadRequest},
+		{"GET not allowed", http.MethodGet, "/api/services/api/stop", http.StatusMethodNotAllowed},
+		{"env of service not started", http.MethodGet, "/api/services/api/env", http.StatusNotFound},
+		{"env of invalid service name", http.MethodGet, "/api/services/bad$name/env", http.StatusBadRequest},
+		{"POST env not allowed", http.MethodPost, "/api/services/api/env", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			srv.mux.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("%s %s status = %d, want %d (body: %s)", tt.method, tt.path, w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleGetLogs_LevelFilter(t *testing.T) {
+	tempDir := t.TempDir()
+	srv := GetServer(tempDir)
+
+	buffer, err := service.GetLogManager(tempDir).CreateBuffer("api", 100, false)
+	if err != nil {
+		t.Fatalf("CreateBuffer() error = %v", err)
+	}
+	for _, line := range []string{"INFO started", "WARN slow request", `{"level":"error","msg":"failed"}`, "DEBUG details"} {
+		buffer.Add(service.NewLogEntry("api", line, false))
+	}
+
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantCount  int
+	}{
+		{"", http.StatusOK, 4},
+		{"?level=error", http.StatusOK, 1},
+		{"?service=api&level=warn,error", http.StatusOK, 2},
+		{"?level=loud", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/logs"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			srv.mux.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var logs []service.LogEntry
+			if err := json.Unmarshal(w.Body.Bytes(), &logs); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(logs) != tt.wantCount {
+				t.Errorf("got %d entries, want %d", len(logs), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestHandleGetProjects(t *testing.T) {
+	registryDir := t.TempDir()
+	original := service.SessionRegistryDir
+	service.SessionRegistryDir = func() (string, error) { return registryDir, nil }
+	t.Cleanup(func() { service.SessionRegistryDir = original })
+
+	currentDir := t.TempDir()
+	otherDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(otherDir, "azure.yaml"), []byte("name: other-app\nservices: {}\n"), 0600); err != nil {
+		t.Fatalf("failed to write azure.yaml: %v", err)
+	}
+	if err := service.RegisterSession(otherDir, "http://localhost:41001", []string{"web", "api"}, true); err != nil {
+		t.Fatalf("RegisterSession() error = %v", err)
+	}
+
+	srv := GetServer(currentDir)
+	getProjects := func() []ActiveProject {
+		t.Helper()
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/projects", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 (body: %s)", w.Code, w.Body.String())
+		}
+		var response struct {
+			Projects []ActiveProject `json:"projects"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return response.Projects
+	}
+
+	// This dashboard's project is listed even before its session registers itself
+	projects := getProjects()
+	if len(projects) != 2 {
+		t.Fatalf("got %d projects, want 2: %+v", len(projects), projects)
+	}
+	byName := make(map[string]ActiveProject)
+	for _, p := range projects {
+		byName[p.Name] = p
+	}
+	other, ok := byName["other-app"]
+	if !ok || other.Current || other.DashboardURL != "http://localhost:41001" || !other.Detached || len(other.Services) != 2 {
+		t.Errorf("other project = %+v", other)
+	}
+	current, ok := byName[filepath.Base(currentDir)]
+	if !ok || !current.Current {
+		t.Errorf("current project = %+v, want it listed as current", current)
+	}
+
+	// Once registered, the current project isn't listed twice
+	if err := service.RegisterSession(currentDir, "http://localhost:41002", nil, false); err != nil {
+		t.Fatalf("RegisterSession() error = %v", err)
+	}
+	projects = getProjects()
+	currentCount := 0
+	for _, p := range projects {
+		if p.Current {
+			currentCount++
+		}
+	}
+	if len(projects) != 2 || currentCount != 1 {
+		t.Errorf("projects = %+v, want 2 with one current", projects)
+	}
+}
+
+func TestHandleServiceActionRouter_RestartSetsPort(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("service command uses POSIX shell syntax")
+	}
+
+	tempDir := t.TempDir()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+
+	azureYaml := fmt.Sprintf(`name: test
+services:
+  api:
+    project: .
+    command: sh -c 'echo "$PORT $AZD_PORT $SERVICE_NAME" > env.txt; exec sleep 30'
+    ports: ["%d"]
+    healthcheck: false
+`, port)
+	if err := os.WriteFile(filepath.Join(tempDir, "azure.yaml"), []byte(azureYaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := GetServer(tempDir)
+	reg := registry.GetRegistry(tempDir)
+	if err := reg.Register(&registry.ServiceRegistryEntry{
+		Name:       "api",
+		ProjectDir: tempDir,
+		Status:     "stopped",
+	}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/services/api/restart", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("restart status = %d, want %d (body: %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	t.Cleanup(func() {
+		srv.mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/services/api/stop", nil))
+	})
+
+	want := fmt.Sprintf("%d %d api", port, port)
+	var got string
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(filepath.Join(tempDir, "env.txt")); err == nil && strings.TrimSpace(string(data)) != "" {
+			got = strings.TrimSpace(string(data))
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if got != want {
+		t.Errorf("restarted service env = %q, want PORT, AZD_PORT and SERVICE_NAME %q", got, want)
+	}
+}
@@ -0,0 +1,167 @@
+package dashboard
+
+import (
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+// Options configures a dashboard handler created with NewHandler.
+type Options struct {
+	// DisableSecurityHeaders skips the default security headers middleware.
+	// Set this when the host application applies its own header policy
+	// (for example, an IDE webview that must allow framing).
+	DisableSecurityHeaders bool
+
+	// OnBroadcast, when non-nil, is registered as a broadcast hook before the
+	// handler is returned, so no events are missed.
+	OnBroadcast BroadcastHook
+}
+
+// BroadcastEvent describes a message pushed to connected dashboard clients.
+type BroadcastEvent struct {
+	// ProjectDir is the absolute project directory of the server that broadcast the event.
+	ProjectDir string
+	// Type is the message type (for example "services").
+	Type string
+	// Message is the exact payload sent to WebSocket clients.
+	Message map[string]interface{}
+}
+
+// BroadcastHook is called synchronously for every broadcast.
+// Hooks must not block; long-running work should be moved to a goroutine.
+type BroadcastHook func(BroadcastEvent)
+
+// broadcastHooks holds subscribers registered via Server.Subscribe.
+type broadcastHooks struct {
+	mu     sync.RWMutex
+	nextID int
+	hooks  map[int]BroadcastHook
+}
+
+// NewHandler creates a dashboard server for projectDir that is not bound to a port.
+// The returned Server implements http.Handler, so it can be mounted into any mux
+// and served with a caller-chosen listener, port, and TLS configuration.
+//
+// Unlike GetServer, handlers created here are not shared: each call returns an
+// independent instance. Call Close when the handler is no longer served.
+// Programs outside this module use it through the pkg/dashboard package.
+func NewHandler(projectDir string, opts Options) *Server {
+	absPath, _ := normalizeProjectPath(projectDir)
+
+	srv := newServer(absPath)
+	srv.embedded = true
+	srv.disableSecurityHeaders = opts.DisableSecurityHeaders
+	if opts.OnBroadcast != nil {
+		srv.Subscribe(opts.OnBroadcast)
+	}
+	return srv
+}
+
+// ServeHTTP serves dashboard API and UI requests.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler().ServeHTTP(w, r)
+}
+
+// handler returns the root HTTP handler with middleware applied.
+func (s *Server) handler() http.Handler {
+	if s.disableSecurityHeaders {
+		return s.mux
+	}
+	return securityHeaders(s.mux)
+}
+
+// Subscribe registers a hook that is invoked for every broadcast sent to
+// dashboard clients. It returns a function that removes the hook.
+func (s *Server) Subscribe(hook BroadcastHook) (unsubscribe func()) {
+	s.hooks.mu.Lock()
+	defer s.hooks.mu.Unlock()
+
+	if s.hooks.hooks == nil {
+		s.hooks.hooks = make(map[int]BroadcastHook)
+	}
+	id := s.hooks.nextID
+	s.hooks.nextID++
+	s.hooks.hooks[id] = hook
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.hooks.mu.Lock()
+			delete(s.hooks.hooks, id)
+			s.hooks.mu.Unlock()
+		})
+	}
+}
+
+// notifyBroadcastHooks invokes all registered hooks with the broadcast message.
+// A panicking hook is recovered so it cannot take down the dashboard.
+func (s *Server) notifyBroadcastHooks(message map[string]interface{}) {
+	s.hooks.mu.RLock()
+	if len(s.hooks.hooks) == 0 {
+		s.hooks.mu.RUnlock()
+		return
+	}
+	hooks := make([]BroadcastHook, 0, len(s.hooks.hooks))
+	for _, hook := range s.hooks.hooks {
+		hooks = append(hooks, hook)
+	}
+	s.hooks.mu.RUnlock()
+
+	msgType, _ := message["type"].(string)
+	event := BroadcastEvent{
+		ProjectDir: s.projectDir,
+		Type:       msgType,
+		Message:    message,
+	}
+
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Dashboard broadcast hook panicked: %v", r)
+				}
+			}()
+			hook(event)
+		}()
+	}
+}
+
+// Close releases resources held by a handler created with NewHandler:
+// open WebSocket connections are closed and background workers are stopped.
+// Safe to call multiple times. Servers obtained from GetServer should use Stop.
+func (s *Server) Close() error {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+
+	s.clientsMu.Lock()
+	for client := range s.clients {
+		_ = client.client.close()
+		delete(s.clients, client)
+	}
+	s.clientsMu.Unlock()
+
+	// The host may still be serving the handler, so the resources are released under the lock
+	s.releaseResources()
+	return nil
+}
+
+// newServer allocates a Server with its routes configured.
+func newServer(absProjectDir string) *Server {
+	srv := &Server{
+		port:        0, // Will be assigned by port manager
+		mux:         http.NewServeMux(),
+		projectDir:  absProjectDir,
+		clients:     make(map[*clientConn]bool),
+		rateLimiter: newConnectionRateLimiter(),
+		stopChan:    make(chan struct{}),
+		currentMode: service.LogModeLocal, // Default to local mode
+		metrics:     newServiceMetrics(),
+		events:      newWSEventLog(),
+	}
+	srv.setupRoutes()
+	return srv
+}
@@ -0,0 +1,195 @@
+package dashboard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/jongio/azd-core/registry"
+)
+
+func TestNewHandler_MountsIntoExternalMux(t *testing.T) {
+	tempDir := t.TempDir()
+
+	handler := NewHandler(tempDir, Options{})
+	defer func() { _ = handler.Close() }()
+
+	mux := http.NewServeMux()
+	mux.Handle("/dashboard/", http.StripPrefix("/dashboard", handler))
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/dashboard/api/ping")
+	if err != nil {
+		t.Fatalf("GET /dashboard/api/ping failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := resp.Header.Get("X-Frame-Options"); got != "DENY" {
+		t.Errorf("X-Frame-Options = %q, want %q", got, "DENY")
+	}
+}
+
+func TestNewHandler_DisableSecurityHeaders(t *testing.T) {
+	handler := NewHandler(t.TempDir(), Options{DisableSecurityHeaders: true})
+	defer func() { _ = handler.Close() }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("X-Frame-Options"); got != "" {
+		t.Errorf("X-Frame-Options = %q, want empty", got)
+	}
+}
+
+func TestNewHandler_IndependentOfGetServer(t *testing.T) {
+	tempDir := t.TempDir()
+
+	shared := GetServer(tempDir)
+	embedded := NewHandler(tempDir, Options{})
+
+	if shared == embedded {
+		t.Fatal("NewHandler returned the shared GetServer instance")
+	}
+
+	// Stopping the embedded handler must not evict the shared instance.
+	_ = embedded.Stop()
+	_ = embedded.Close()
+
+	if GetServer(tempDir) != shared {
+		t.Error("embedded handler Stop evicted the shared server")
+	}
+}
+
+func TestServer_SubscribeReceivesBroadcasts(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []BroadcastEvent
+	)
+	handler := NewHandler(t.TempDir(), Options{
+		OnBroadcast: func(e BroadcastEvent) {
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		},
+	})
+	defer func() { _ = handler.Close() }()
+
+	var second int
+	unsubscribe := handler.Subscribe(func(BroadcastEvent) { second++ })
+
+	handler.BroadcastUpdate([]*registry.ServiceRegistryEntry{{Name: "api"}})
+	unsubscribe()
+	unsubscribe() // idempotent
+	handler.BroadcastUpdate(nil)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 {
+		t.Fatalf("OnBroadcast received %d events, want 2", len(events))
+	}
+	if events[0].Type != "services" {
+		t.Errorf("event type = %q, want %q", events[0].Type, "services")
+	}
+	if events[0].ProjectDir != handler.projectDir {
+		t.Errorf("event project dir = %q, want %q", events[0].ProjectDir, handler.projectDir)
+	}
+	if second != 1 {
+		t.Errorf("unsubscribed hook called %d times, want 1", second)
+	}
+}
+
+func TestServer_SubscribeRecoversFromPanickingHook(t *testing.T) {
+	handler := NewHandler(t.TempDir(), Options{})
+	defer func() { _ = handler.Close() }()
+
+	called := false
+	handler.Subscribe(func(BroadcastEvent) { panic("boom") })
+	handler.Subscribe(func(BroadcastEvent) { called = true })
+
+	handler.BroadcastUpdate(nil)
+
+	if !called {
+		t.Error("hook after panicking hook was not called")
+	}
+}
+
+func TestNewHandler_WebSocketOverCustomListener(t *testing.T) {
+	handler := NewHandler(t.TempDir(), Options{})
+	defer func() { _ = handler.Close() }()
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	wsURL := strings.Replace(ts.URL, "http://", "ws://", 1) + "/api/ws"
+	ws, _, err := websocket.Dial(ctx, wsURL, nil)
+	if err != nil {
+		t.Fatalf("failed to connect WebSocket: %v", err)
+	}
+	defer func() { _ = ws.Close(websocket.StatusNormalClosure, "test complete") }()
+
+	var msg map[string]interface{}
+	if err := wsjson.Read(ctx, ws, &msg); err != nil {
+		t.Fatalf("failed to read initial message: %v", err)
+	}
+	if msg["type"] != "services" {
+		t.Errorf("message type = %v, want %q", msg["type"], "services")
+	}
+}
+
+func TestNewHandler_CloseWhileServing(t *testing.T) {
+	handler := NewHandler(t.TempDir(), Options{})
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	wsURL := strings.Replace(ts.URL, "http://", "ws://", 1) + "/api/ws"
+	dial := func() (*http.Response, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		ws, resp, err := websocket.Dial(ctx, wsURL, nil)
+		if err == nil {
+			_ = ws.Close(websocket.StatusNormalClosure, "test complete")
+		}
+		return resp, err
+	}
+
+	// Connect while the handler is closed; run with -race to catch unsynchronized access
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = dial()
+		}()
+	}
+	if err := handler.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	wg.Wait()
+
+	resp, err := dial()
+	if err == nil {
+		t.Fatal("WebSocket connected after Close, want it refused")
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("WebSocket after Close got response %v, want status %d", resp, http.StatusServiceUnavailable)
+	}
+}
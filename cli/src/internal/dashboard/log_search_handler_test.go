@@ -0,0 +1,133 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+func TestHandleLogSearch(t *testing.T) {
+	projectDir := t.TempDir()
+	srv := GetServer(projectDir)
+
+	logManager := service.GetLogManager(projectDir)
+	t.Cleanup(func() { _ = logManager.Clear() })
+	buffer, err := logManager.CreateBuffer("api", 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i, msg := range []string{"listening on :3000", "GET /users", "Error: connection refused", "retrying", "GET /orders"} {
+		level := service.LogLevelInfo
+		if i == 2 {
+			level = service.LogLevelError
+		}
+		buffer.Add(service.LogEntry{Service: "api", Message: msg, Level: level, Timestamp: now.Add(time.Duration(i) * time.Millisecond)})
+	}
+
+	tests := []struct {
+		name          string
+		params        url.Values
+		wantStatus    int
+		wantMatches   []string
+		wantTruncated bool
+	}{
+		{
+			name:        "regex is case-insensitive by default",
+			params:      url.Values{"q": {"^get /"}},
+			wantStatus:  http.StatusOK,
+			wantMatches: []string{"GET /orders", "GET /users"},
+		},
+		{
+			name:        "case-sensitive",
+			params:      url.Values{"q": {"^get /"}, "caseSensitive": {"true"}},
+			wantStatus:  http.StatusOK,
+			wantMatches: []string{},
+		},
+		{
+			name:        "level and service filters",
+			params:      url.Values{"q": {"refused|GET"}, "level": {"error"}, "service": {"api"}},
+			wantStatus:  http.StatusOK,
+			wantMatches: []string{"Error: connection refused"},
+		},
+		{
+			name:          "limit",
+			params:        url.Values{"q": {"."}, "limit": {"2"}},
+			wantStatus:    http.StatusOK,
+			wantMatches:   []string{"GET /orders", "retrying"},
+			wantTruncated: true,
+		},
+		{name: "missing query", params: url.Values{}, wantStatus: http.StatusBadRequest},
+		{name: "invalid regex", params: url.Values{"q": {"(unclosed"}}, wantStatus: http.StatusBadRequest},
+		{name: "invalid level", params: url.Values{"q": {"x"}, "level": {"loud"}}, wantStatus: http.StatusBadRequest},
+		{name: "unknown service", params: url.Values{"q": {"x"}, "service": {"web"}}, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/logs/search?"+tt.params.Encode(), nil)
+			w := httptest.NewRecorder()
+
+			srv.handleLogSearch(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result logSearchResponse
+			if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(result.Matches) != len(tt.wantMatches) {
+				t.Fatalf("matches = %+v, want %v", result.Matches, tt.wantMatches)
+			}
+			for i, want := range tt.wantMatches {
+				if result.Matches[i].Message != want {
+					t.Errorf("match %d = %q, want %q", i, result.Matches[i].Message, want)
+				}
+			}
+			if result.Truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v", result.Truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestHandleLogSearch_ContextLines(t *testing.T) {
+	projectDir := t.TempDir()
+	srv := GetServer(projectDir)
+
+	logManager := service.GetLogManager(projectDir)
+	t.Cleanup(func() { _ = logManager.Clear() })
+	buffer, err := logManager.CreateBuffer("api", 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"one", "two", "panic: nil map", "three", "four"} {
+		buffer.Add(service.LogEntry{Service: "api", Message: msg, Timestamp: time.Now()})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/logs/search?q=panic&context=1", nil)
+	w := httptest.NewRecorder()
+	srv.handleLogSearch(w, req)
+
+	var result logSearchResponse
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(result.Matches) != 1 {
+		t.Fatalf("matches = %+v, want 1", result.Matches)
+	}
+	context := result.Matches[0].Context
+	if len(context.Before) != 1 || context.Before[0] != "two" || len(context.After) != 1 || context.After[0] != "three" {
+		t.Errorf("context = %+v, want [two] before and [three] after", context)
+	}
+}
@@ -0,0 +1,207 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/security"
+)
+
+// logSSEFlushInterval is how often buffered log events are flushed to SSE clients.
+const logSSEFlushInterval = 50 * time.Millisecond
+
+// logSubscription is a merged, level-filtered stream of service log entries.
+type logSubscription struct {
+	entries <-chan service.LogEntry // Closed when every subscribed buffer is closed or after close
+	close   func()                  // Stops merging and unsubscribes; safe to call multiple times
+}
+
+// subscribeLogs subscribes to the logs of serviceName, or of all services when it is empty,
+// keeping only entries whose level is in levels (all entries when levels is nil).
+// It returns false when serviceName has no log buffer.
+func (s *Server) subscribeLogs(serviceName string, levels map[service.LogLevel]bool) (*logSubscription, bool) {
+	logManager := service.GetLogManager(s.projectDir)
+
+	// Create subscriptions for log streams
+	subscriptions := make(map[string]chan service.LogEntry)
+
+	if serviceName != "" {
+		// Subscribe to specific service
+		buffer, exists := logManager.GetBuffer(serviceName)
+		if !exists {
+			return nil, false
+		}
+		subscriptions[serviceName] = buffer.Subscribe()
+	} else {
+		// Subscribe to all services
+		for name, buffer := range logManager.GetAllBuffers() {
+			subscriptions[name] = buffer.Subscribe()
+		}
+	}
+
+	// Merge all subscription channels with backpressure handling
+	// Use constant for buffer size
+	mergedChan := make(chan service.LogEntry, service.WebSocketLogChannelBuffer)
+	stopMerge := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for _, ch := range subscriptions {
+		wg.Add(1)
+		go func(ch chan service.LogEntry) {
+			defer wg.Done()
+			for {
+				select {
+				case entry, ok := <-ch:
+					if !ok {
+						return
+					}
+					if levels != nil && !levels[entry.Level] {
+						continue
+					}
+					// Try to send with timeout to prevent blocking on slow consumers
+					// CRITICAL: Always include stopMerge in select to prevent goroutine leaks
+					select {
+					case mergedChan <- entry:
+						// Successfully sent
+					case <-stopMerge:
+						return
+					default:
+						// Channel full, try with timeout
+						timer := time.NewTimer(service.WebSocketSlowConsumerTimeout)
+						select {
+						case mergedChan <- entry:
+							// Successfully sent after brief wait
+							timer.Stop()
+						case <-timer.C:
+							// Drop log entry if consumer is too slow
+							log.Printf("Warning: Dropped log entry due to slow consumer")
+						case <-stopMerge:
+							timer.Stop()
+							return
+						}
+					}
+				case <-stopMerge:
+					return
+				}
+			}
+		}(ch)
+	}
+
+	// Close merged channel when all goroutines finish
+	go func() {
+		wg.Wait()
+		close(mergedChan)
+	}()
+
+	var once sync.Once
+	return &logSubscription{
+		entries: mergedChan,
+		close: func() {
+			once.Do(func() {
+				// CRITICAL: Close stopMerge FIRST to signal goroutines, THEN wait for them
+				// Otherwise goroutines may be blocked and won't see the stop signal
+				close(stopMerge)
+				wg.Wait()
+				for svcName, ch := range subscriptions {
+					if buffer, exists := logManager.GetBuffer(svcName); exists {
+						buffer.Unsubscribe(ch)
+					}
+				}
+			})
+		},
+	}, true
+}
+
+// handleLogSSE streams logs as Server-Sent Events, for clients behind proxies that block
+// WebSockets. It takes the same parameters as handleLogStream; each event's data is one
+// log entry, and a comment is sent every heartbeatInterval to keep idle connections open.
+func (s *Server) handleLogSSE(w http.ResponseWriter, r *http.Request) {
+	serviceName := r.URL.Query().Get("service")
+
+	// Validate service name to prevent injection attacks
+	if err := security.ValidateServiceName(serviceName, true); err != nil {
+		BadRequest(w, "Invalid service name", nil)
+		return
+	}
+
+	levels, err := parseLogLevelFilter(r.URL.Query().Get("level"))
+	if err != nil {
+		BadRequest(w, err.Error(), nil)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		InternalError(w, "Streaming not supported", nil)
+		return
+	}
+
+	logs, ok := s.subscribeLogs(serviceName, levels)
+	if !ok {
+		NotFound(w, fmt.Sprintf("Service '%s' not found", serviceName))
+		return
+	}
+	defer logs.close()
+
+	// Set SSE headers
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable nginx buffering
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Entries are written as they arrive and flushed periodically to batch bursts
+	flushTicker := time.NewTicker(logSSEFlushInterval)
+	defer flushTicker.Stop()
+	heartbeatTicker := time.NewTicker(heartbeatInterval)
+	defer heartbeatTicker.Stop()
+	pending := false
+
+	for {
+		select {
+		case entry, ok := <-logs.entries:
+			if !ok {
+				if pending {
+					flusher.Flush()
+				}
+				return
+			}
+			data, err := json.Marshal(entry)
+			if err != nil {
+				log.Printf("Failed to marshal log entry: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+			pending = true
+
+		case <-flushTicker.C:
+			if pending {
+				flusher.Flush()
+				pending = false
+			}
+
+		case <-heartbeatTicker.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+			pending = false
+
+		case <-r.Context().Done():
+			// Client disconnected
+			return
+
+		case <-s.stopChan:
+			// Server stopping
+			return
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package dashboard
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+// addLogsUntil adds the lines to buffer every 50ms until done is closed, so an entry reaches
+// a stream whichever moment it subscribes.
+func addLogsUntil(buffer *service.LogBuffer, lines []string, done <-chan struct{}) {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			for _, line := range lines {
+				buffer.Add(service.NewLogEntry("api", line, false))
+			}
+		}
+	}
+}
+
+func TestHandleLogSSE(t *testing.T) {
+	tempDir := t.TempDir()
+	srv := GetServer(tempDir)
+	buffer, err := service.GetLogManager(tempDir).CreateBuffer("api", 100, false)
+	if err != nil {
+		t.Fatalf("CreateBuffer() error = %v", err)
+	}
+
+	ts := httptest.NewServer(srv.mux)
+	defer ts.Close()
+
+	t.Run("errors", func(t *testing.T) {
+		for query, want := range map[string]int{
+			"?service=missing": http.StatusNotFound,
+			"?level=loud":      http.StatusBadRequest,
+			"?service=a%20b":   http.StatusBadRequest,
+		} {
+			resp, err := http.Get(ts.URL + "/api/v1/logs/sse" + query)
+			if err != nil {
+				t.Fatalf("GET %s: %v", query, err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != want {
+				t.Errorf("GET %s status = %d, want %d", query, resp.StatusCode, want)
+			}
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/v1/logs/sse?service=api&level=error", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /api/v1/logs/sse: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+		}
+
+		done := make(chan struct{})
+		defer close(done)
+		go addLogsUntil(buffer, []string{"INFO started", `{"level":"error","msg":"failed"}`}, done)
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+			var entry service.LogEntry
+			if err := json.Unmarshal([]byte(data), &entry); err != nil {
+				t.Fatalf("invalid event data %q: %v", data, err)
+			}
+			if entry.Level != service.LogLevelError || entry.Service != "api" {
+				t.Errorf("entry = %+v, want only error entries of api", entry)
+			}
+			return
+		}
+		t.Fatalf("stream ended without an event: %v", scanner.Err())
+	})
+}
+
+func TestStreamLogs_SSEFallback(t *testing.T) {
+	tempDir := t.TempDir()
+	srv := GetServer(tempDir)
+	buffer, err := service.GetLogManager(tempDir).CreateBuffer("api", 100, false)
+	if err != nil {
+		t.Fatalf("CreateBuffer() error = %v", err)
+	}
+
+	// A proxy that blocks WebSocket upgrades
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "WebSockets are not allowed", http.StatusForbidden)
+	})
+	mux.Handle("/", srv.mux)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	client := &Client{baseURL: ts.URL, httpClient: ts.Client()}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	logs := make(chan service.LogEntry, 10)
+	errs := make(chan error, 1)
+	go func() { errs <- client.StreamLogs(ctx, "api", logs) }()
+
+	done := make(chan struct{})
+	defer close(done)
+	go addLogsUntil(buffer, []string{"INFO started"}, done)
+
+	select {
+	case entry := <-logs:
+		if entry.Message != "INFO started" {
+			t.Errorf("entry message = %q, want %q", entry.Message, "INFO started")
+		}
+	case err := <-errs:
+		t.Fatalf("StreamLogs() returned before streaming: %v", err)
+	case <-ctx.Done():
+		t.Fatal("no log entry received over Server-Sent Events")
+	}
+
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Errorf("StreamLogs() after cancel = %v, want context.Canceled", err)
+	}
+}
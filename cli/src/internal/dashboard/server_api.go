@@ -0,0 +1,334 @@
+package dashboard
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Dashboard API versioning. Every endpoint is served under /api/v1; the unversioned /api paths
+// are aliases kept for existing clients.
+const (
+	apiPrefix        = "/api"
+	apiVersionPrefix = "/api/v1"
+	apiVersion       = "1.0.0" // Version of the API contract in the OpenAPI spec
+)
+
+// apiRoute is a dashboard endpoint registered on the mux, with the operations it serves.
+type apiRoute struct {
+	pattern     string // Mux pattern of the unversioned path, e.g. "/api/services/"
+	handler     http.HandlerFunc
+	operations  []apiOperation // Operations described in the OpenAPI spec
+	unversioned bool           // Served at pattern only, e.g. the Prometheus /metrics endpoint
+}
+
+// apiOperation describes an HTTP operation of an endpoint for the OpenAPI spec.
+type apiOperation struct {
+	method  string
+	path    string // Path relative to /api/v1, or the full path when unversioned, e.g. "/services/{name}/env"
+	summary string
+	params  []apiParam
+	body    bool   // Takes a JSON request body
+	status  int    // Status of a successful response (0 = 200 OK)
+	id      string // operationId, when the one made of the method and path isn't unique
+}
+
+// apiParam is a query or path parameter of an operation.
+type apiParam struct {
+	name        string
+	in          string // "query" or "path"
+	typ         string // JSON schema type: "string", "integer" or "boolean"
+	required    bool
+	description string
+}
+
+// queryParam returns an optional query parameter.
+func queryParam(name, typ, description string) apiParam {
+	return apiParam{name: name, in: "query", typ: typ, description: description}
+}
+
+// requiredQueryParam returns a required query parameter.
+func requiredQueryParam(name, typ, description string) apiParam {
+	return apiParam{name: name, in: "query", typ: typ, required: true, description: description}
+}
+
+// pathParam returns a path parameter; path parameters are always required.
+func pathParam(name, typ, description string) apiParam {
+	return apiParam{name: name, in: "path", typ: typ, required: true, description: description}
+}
+
+// Parameters shared by several operations.
+var (
+	serviceQueryParam         = queryParam("service", "string", "Service name; all services when omitted")
+	serviceListQueryParam     = queryParam("service", "string", "Comma-separated service names; all services when omitted")
+	logLevelQueryParam        = queryParam("level", "string", "Comma-separated log levels to include, e.g. warn,error")
+	serviceNamePathParam      = pathParam("name", "string", "Service name")
+	requiredServiceQueryParam = requiredQueryParam("service", "string", "Service name")
+)
+
+// apiRoutes returns the dashboard API endpoints.
+func (s *Server) apiRoutes() []apiRoute {
+	return []apiRoute{
+		{pattern: "/api/openapi.json", handler: MethodGuard(s.handleOpenAPI, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/openapi.json", summary: "OpenAPI description of the dashboard API"},
+		}},
+		{pattern: "/api/ping", handler: MethodGuard(s.handlePing, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/ping", summary: "Check that the dashboard is up"},
+		}},
+		{pattern: "/api/shutdown", handler: MethodGuard(s.handleShutdown, http.MethodPost), operations: []apiOperation{
+			{method: http.MethodPost, path: "/shutdown", summary: "Stop the services and the azd app run session that owns the dashboard", status: http.StatusAccepted},
+		}},
+		{pattern: "/api/project", handler: MethodGuard(s.handleGetProject, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/project", summary: "Project name and directory"},
+		}},
+		{pattern: "/api/projects", handler: MethodGuard(s.handleGetProjects, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/projects", summary: "Projects with an active azd app run session, with their dashboard URLs"},
+		}},
+		{pattern: "/api/services", handler: MethodGuard(s.handleGetServices, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/services", summary: "Services with their local and Azure state"},
+		}},
+		{pattern: "/api/services/start", handler: MethodGuard(s.handleStartService, http.MethodPost), operations: []apiOperation{
+			{method: http.MethodPost, path: "/services/start", summary: "Start a service, or all stopped services", params: []apiParam{serviceQueryParam}},
+		}},
+		{pattern: "/api/services/stop", handler: MethodGuard(s.handleStopService, http.MethodPost), operations: []apiOperation{
+			{method: http.MethodPost, path: "/services/stop", summary: "Stop a service, or all running services", params: []apiParam{serviceQueryParam}},
+		}},
+		{pattern: "/api/services/restart", handler: MethodGuard(s.handleRestartService, http.MethodPost), operations: []apiOperation{
+			{method: http.MethodPost, path: "/services/restart", summary: "Restart a service, or all services", params: []apiParam{serviceQueryParam}},
+		}},
+		{pattern: "/api/services/", handler: s.handleServiceActionRouter, operations: []apiOperation{
+			{method: http.MethodPost, path: "/services/{name}/restart", summary: "Restart a service", params: []apiParam{serviceNamePathParam}},
+			{method: http.MethodPost, path: "/services/{name}/stop", summary: "Stop a service", params: []apiParam{serviceNamePathParam}},
+			{method: http.MethodGet, path: "/services/{name}/env", summary: "Command line, working directory and environment (secrets masked) the service was last started with", params: []apiParam{serviceNamePathParam}},
+		}},
+		{pattern: "/api/metrics", handler: MethodGuard(s.handleGetMetrics, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/metrics", summary: "CPU, memory and uptime of each running service, with recent history"},
+		}},
+		{pattern: "/api/logs", handler: MethodGuard(s.handleGetLogs, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/logs", summary: "Recent service logs", params: []apiParam{
+				serviceQueryParam, logLevelQueryParam, queryParam("tail", "integer", "Number of lines (default 500)"),
+			}},
+		}},
+		{pattern: "/api/logs/stream", handler: MethodGuard(s.handleLogStream, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/logs/stream", summary: "Stream service logs (WebSocket)", params: []apiParam{serviceQueryParam, logLevelQueryParam}, status: http.StatusSwitchingProtocols},
+		}},
+		{pattern: "/api/logs/sse", handler: MethodGuard(s.handleLogSSE, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/logs/sse", summary: "Stream service logs (Server-Sent Events), for networks that block WebSockets", params: []apiParam{serviceQueryParam, logLevelQueryParam}},
+		}},
+		{pattern: "/api/logs/search", handler: MethodGuard(s.handleLogSearch, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/logs/search", summary: "Search in-memory and persisted logs with a regular expression", params: []apiParam{
+				requiredQueryParam("q", "string", "Regular expression matched against log messages"),
+				serviceQueryParam,
+				logLevelQueryParam,
+				queryParam("context", "integer", "Lines of context around each match"),
+				queryParam("limit", "integer", "Maximum number of matches"),
+				queryParam("caseSensitive", "boolean", "Match case (default false)"),
+			}},
+		}},
+		{pattern: "/api/logs/classifications", handler: s.handleClassificationsRouter, operations: []apiOperation{
+			{method: http.MethodGet, path: "/logs/classifications", summary: "Log classifications from azure.yaml"},
+			{method: http.MethodPost, path: "/logs/classifications", summary: "Add a log classification to azure.yaml", body: true, status: http.StatusCreated},
+		}},
+		{pattern: "/api/logs/classifications/", handler: s.handleClassificationsRouter, operations: []apiOperation{
+			{method: http.MethodDelete, path: "/logs/classifications/{index}", summary: "Remove a log classification from azure.yaml", params: []apiParam{
+				pathParam("index", "integer", "Index of the classification"),
+			}, status: http.StatusNoContent},
+		}},
+		{pattern: "/api/logs/preferences", handler: s.handlePreferencesRouter, operations: []apiOperation{
+			{method: http.MethodGet, path: "/logs/preferences", summary: "Log view preferences"},
+			{method: http.MethodPost, path: "/logs/preferences", summary: "Save log view preferences", body: true},
+		}},
+		{pattern: "/api/mode", handler: s.handleModeRouter, operations: []apiOperation{
+			{method: http.MethodGet, path: "/mode", summary: "Log source mode: local or azure"},
+			{method: http.MethodPut, path: "/mode", summary: "Change the log source mode", body: true},
+		}},
+		{pattern: "/api/azure/enable", handler: MethodGuard(s.handleEnableAzureLogging, http.MethodPost), operations: []apiOperation{
+			{method: http.MethodPost, path: "/azure/enable", summary: "Enable Azure logging in azure.yaml"},
+		}},
+		{pattern: "/api/azure/services", handler: MethodGuard(s.handleAzureServices, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/azure/services", summary: "Services with Azure logging"},
+		}},
+		{pattern: "/api/azure/logs", handler: MethodGuard(s.handleAzureLogs, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/azure/logs", summary: "Logs of the deployed services from Log Analytics", params: []apiParam{
+				serviceQueryParam,
+				queryParam("since", "string", "How far back to query, e.g. 30m (default 1h)"),
+				queryParam("tail", "integer", "Number of lines (default 500)"),
+			}},
+		}},
+		{pattern: "/api/azure/logs/stream", handler: MethodGuard(s.handleAzureLogsStream, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/azure/logs/stream", summary: "Stream logs of the deployed services (WebSocket)", params: []apiParam{
+				serviceQueryParam,
+				queryParam("realtime", "boolean", "Stream in real time rather than by polling (default from azure.yaml)"),
+			}, status: http.StatusSwitchingProtocols},
+		}},
+		{pattern: "/api/azure/logs/health", handler: MethodGuard(s.handleAzureLogsHealth, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/azure/logs/health", summary: "Diagnostic checks for Azure logs troubleshooting"},
+		}},
+		{pattern: "/api/azure/logs/setup-state", handler: MethodGuard(s.handleAzureSetupState, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/azure/logs/setup-state", summary: "Azure logs setup state for the setup guide"},
+		}},
+		{pattern: "/api/azure/logs/verify", handler: MethodGuard(s.handleAzureLogsVerify, http.MethodPost), operations: []apiOperation{
+			{method: http.MethodPost, path: "/azure/logs/verify", summary: "Verify log connectivity for a service", body: true},
+		}},
+		{pattern: "/api/azure/diagnostic-settings/check", handler: MethodGuard(s.handleAzureDiagnosticSettingsCheck, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/azure/diagnostic-settings/check", summary: "Check the diagnostic settings of all services"},
+		}},
+		{pattern: "/api/azure/diagnostics", handler: MethodGuard(s.handleAzureDiagnostics, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/azure/diagnostics", summary: "Azure logs diagnostics for all services"},
+		}},
+		{pattern: "/api/azure/workspace/verify", handler: MethodGuard(s.handleAzureWorkspaceVerify, http.MethodPost), operations: []apiOperation{
+			{method: http.MethodPost, path: "/azure/workspace/verify", summary: "Verify the Log Analytics workspace connection by querying for recent logs"},
+		}},
+		{pattern: "/api/azure/bicep-template", handler: MethodGuard(s.handleAzureBicepTemplate, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/azure/bicep-template", summary: "Bicep template with diagnostic settings for all detected services"},
+		}},
+		{pattern: "/api/azure/logs/config", handler: s.handleAzureLogConfigRouter, operations: []apiOperation{
+			{method: http.MethodGet, path: "/azure/logs/config", summary: "Azure log configuration of a service", params: []apiParam{requiredServiceQueryParam}},
+			{method: http.MethodPut, path: "/azure/logs/config", summary: "Save the Azure log configuration of a service to azure.yaml", body: true},
+		}},
+		{pattern: "/api/azure/tables", handler: MethodGuard(s.handleAzureTables, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/azure/tables", summary: "Log Analytics tables", params: []apiParam{
+				queryParam("resourceType", "string", "Azure resource type, e.g. containerapp (default)"),
+			}},
+		}},
+		{pattern: "/api/azure/query", handler: s.handleAzureQueryRouter, operations: []apiOperation{
+			{method: http.MethodGet, path: "/azure/query", summary: "KQL query of a service", params: []apiParam{requiredServiceQueryParam}},
+			{method: http.MethodPut, path: "/azure/query", summary: "Save a custom KQL query for a service to azure.yaml", body: true},
+		}},
+		{pattern: "/api/ws", handler: s.handleWebSocket, operations: []apiOperation{
+			{method: http.MethodGet, path: "/ws", summary: "Live service, metrics and log updates (WebSocket)", params: []apiParam{
+				queryParam("token", "string", "Reconnect token from the first services message, to resume after a disconnect"),
+				queryParam("since", "integer", "Sequence number of the last services message received; missed ones are replayed"),
+			}, status: http.StatusSwitchingProtocols},
+		}},
+		{pattern: "/api/health", handler: s.handleHealthCheck, operations: []apiOperation{
+			{method: http.MethodGet, path: "/health", summary: "Check the health of the services", params: []apiParam{
+				serviceListQueryParam,
+				queryParam("timeout", "integer", "Timeout in seconds, 1 to 60"),
+			}},
+		}},
+		{pattern: "/api/health/stream", handler: MethodGuard(s.handleHealthStream, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/health/stream", summary: "Stream health updates (Server-Sent Events)", params: []apiParam{
+				serviceListQueryParam,
+				queryParam("interval", "string", "Check interval, e.g. 5s"),
+			}},
+		}},
+		{pattern: "/api/environment", handler: MethodGuard(s.handleGetEnvironment, http.MethodGet), operations: []apiOperation{
+			{method: http.MethodGet, path: "/environment", summary: "Codespace and azd environment of the dashboard"},
+		}},
+		{pattern: "/metrics", handler: MethodGuard(s.handlePrometheusMetrics, http.MethodGet), unversioned: true, operations: []apiOperation{
+			{method: http.MethodGet, path: "/metrics", id: "getPrometheusMetrics", summary: "Prometheus metrics (opt-in with dashboard.prometheus in azure.yaml)"},
+		}},
+	}
+}
+
+// versionedHandler serves a versioned endpoint with the handler of its unversioned path, so
+// handlers that parse the path see the same path either way.
+func versionedHandler(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r = r.Clone(r.Context())
+		r.URL.Path = apiPrefix + strings.TrimPrefix(r.URL.Path, apiVersionPrefix)
+		r.URL.RawPath = ""
+		handler(w, r)
+	}
+}
+
+// handleOpenAPI handles GET /api/openapi.json with the OpenAPI description of the API.
+func (s *Server) handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	WriteJSONSuccess(w, openAPISpec(s.apiRoutes()))
+}
+
+// openAPISpec returns the OpenAPI 3.0 description of the routes.
+func openAPISpec(routes []apiRoute) map[string]interface{} {
+	paths := make(map[string]interface{})
+	for _, route := range routes {
+		for _, op := range route.operations {
+			path := op.path
+			if !route.unversioned {
+				path = apiVersionPrefix + op.path
+			}
+			item, ok := paths[path].(map[string]interface{})
+			if !ok {
+				item = make(map[string]interface{})
+				paths[path] = item
+			}
+			item[strings.ToLower(op.method)] = openAPIOperation(op)
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "azd app dashboard API",
+			"version":     apiVersion,
+			"description": "API of the dashboard started by azd app run. Every path is also served under /api without the version, for existing clients.",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"error": map[string]string{"type": "string"}},
+				},
+			},
+		},
+	}
+}
+
+// openAPIOperation returns the OpenAPI operation object of an operation.
+func openAPIOperation(op apiOperation) map[string]interface{} {
+	status := op.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	operation := map[string]interface{}{
+		"summary":     op.summary,
+		"operationId": operationID(op),
+		"tags":        []string{strings.Split(strings.TrimPrefix(op.path, "/"), "/")[0]},
+		"responses": map[string]interface{}{
+			strconv.Itoa(status): map[string]string{"description": http.StatusText(status)},
+			"default": map[string]interface{}{
+				"description": "Error",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": map[string]string{"$ref": "#/components/schemas/Error"}},
+				},
+			},
+		},
+	}
+
+	if len(op.params) > 0 {
+		params := make([]map[string]interface{}, 0, len(op.params))
+		for _, p := range op.params {
+			params = append(params, map[string]interface{}{
+				"name":        p.name,
+				"in":          p.in,
+				"required":    p.required,
+				"description": p.description,
+				"schema":      map[string]string{"type": p.typ},
+			})
+		}
+		operation["parameters"] = params
+	}
+	if op.body {
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  map[string]interface{}{"application/json": map[string]interface{}{"schema": map[string]string{"type": "object"}}},
+		}
+	}
+	return operation
+}
+
+// operationID returns a unique operation name: its id, else one made of the method and path,
+// e.g. "getServicesNameEnv" for GET /services/{name}/env.
+func operationID(op apiOperation) string {
+	if op.id != "" {
+		return op.id
+	}
+	id := strings.ToLower(op.method)
+	for _, word := range strings.FieldsFunc(op.path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '.'
+	}) {
+		id += strings.ToUpper(word[:1]) + word[1:]
+	}
+	return id
+}
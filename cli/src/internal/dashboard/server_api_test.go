@@ -0,0 +1,131 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-core/registry"
+)
+
+func TestVersionedRoutes(t *testing.T) {
+	tempDir := t.TempDir()
+	srv := GetServer(tempDir)
+
+	if err := registry.GetRegistry(tempDir).Register(&registry.ServiceRegistryEntry{
+		Name:       "api",
+		ProjectDir: tempDir,
+		Status:     "stopped",
+	}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"ping", http.MethodGet, "/api/v1/ping", http.StatusOK},
+		{"unversioned alias", http.MethodGet, "/api/ping", http.StatusOK},
+		{"method guard", http.MethodPost, "/api/v1/ping", http.StatusMethodNotAllowed},
+		{"path parsed by the handler", http.MethodPost, "/api/v1/services/api/stop", http.StatusConflict},
+		{"unknown service action", http.MethodPost, "/api/v1/services/api/delete", http.StatusNotFound},
+		{"unknown endpoint", http.MethodGet, "/api/v1/unknown", http.StatusNotFound},
+		{"openapi", http.MethodGet, "/api/v1/openapi.json", http.StatusOK},
+		{"openapi alias", http.MethodGet, "/api/openapi.json", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("%s %s status = %d, want %d (body: %s)", tt.method, tt.path, w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusNotFound && !strings.Contains(w.Header().Get("Content-Type"), "application/json") {
+				t.Errorf("%s %s Content-Type = %q, want a JSON error", tt.method, tt.path, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	srv := GetServer(t.TempDir())
+
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Parameters  []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if spec.OpenAPI != "3.0.3" || spec.Info.Version != apiVersion {
+		t.Errorf("openapi = %q, version = %q", spec.OpenAPI, spec.Info.Version)
+	}
+
+	// Every registered operation is described, with a unique operationId
+	ids := make(map[string]string)
+	for _, route := range srv.apiRoutes() {
+		for _, op := range route.operations {
+			path := apiVersionPrefix + op.path
+			if route.unversioned {
+				path = op.path
+			}
+			operation, ok := spec.Paths[path][strings.ToLower(op.method)]
+			if !ok {
+				t.Errorf("%s %s missing from the spec", op.method, path)
+				continue
+			}
+			if other, dup := ids[operation.OperationID]; dup {
+				t.Errorf("operationId %q of %s %s is also used by %s", operation.OperationID, op.method, path, other)
+			}
+			ids[operation.OperationID] = op.method + " " + path
+		}
+	}
+
+	env := spec.Paths["/api/v1/services/{name}/env"]["get"]
+	if env.OperationID != "getServicesNameEnv" || len(env.Parameters) != 1 || env.Parameters[0].In != "path" {
+		t.Errorf("GET /api/v1/services/{name}/env = %+v", env)
+	}
+	if _, ok := spec.Paths["/metrics"]["get"]; !ok {
+		t.Error("Prometheus /metrics missing from the spec")
+	}
+}
+
+func TestAPIRoutesDescribed(t *testing.T) {
+	// Every route is described, so the spec can't fall behind the endpoints
+	srv := GetServer(t.TempDir())
+	for _, route := range srv.apiRoutes() {
+		if len(route.operations) == 0 {
+			t.Errorf("route %s has no operations", route.pattern)
+		}
+		for _, op := range route.operations {
+			prefix := strings.TrimSuffix(strings.TrimPrefix(route.pattern, apiPrefix), "/")
+			if route.unversioned {
+				prefix = route.pattern
+			}
+			if !strings.HasPrefix(op.path, prefix) {
+				t.Errorf("operation %s %s isn't served by route %s", op.method, op.path, route.pattern)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package dashboard
 
 import (
+	"crypto/tls"
 	"fmt"
 	"log"
 	"net/http"
@@ -35,8 +36,23 @@ type Server struct {
 	started      bool       // Track if server was successfully started
 	startedMu    sync.Mutex // Protect started flag
 	configClient azdconfig.ConfigClient
+	resourcesMu  sync.Mutex      // Protect configClient and rateLimiter
 	currentMode  service.LogMode // Current log source mode (local or azure)
 	modeMu       sync.RWMutex    // Protect currentMode
+	hooks        broadcastHooks  // Subscribers notified on every broadcast
+	onShutdown   func()          // Called by POST /api/shutdown (e.g. `azd app stop --all`)
+	shutdownMu   sync.Mutex      // Protect onShutdown
+	tlsConfig    *tls.Config     // Serve HTTPS (and plain HTTP) when set
+	metrics      *serviceMetrics // Resource usage samples served by /api/metrics
+	events       *wsEventLog     // Service-status events replayed to reconnecting /api/ws clients
+	prometheus   http.Handler    // Serves /metrics; nil until EnablePrometheus
+	prometheusMu sync.Mutex      // Protect prometheus
+
+	onRestart func(serviceName string) error // Starts or restarts a service of the `azd app run` session
+	restartMu sync.Mutex                     // Protect onRestart
+
+	embedded               bool // Created via NewHandler; not tracked in servers map
+	disableSecurityHeaders bool // Skip securityHeaders middleware (embedded only)
 }
 
 // GetServer returns the dashboard server instance for the specified project.
@@ -53,16 +69,7 @@ func GetServer(projectDir string) *Server {
 	}
 
 	// Create new server instance for this project
-	srv := &Server{
-		port:        0, // Will be assigned by port manager
-		mux:         http.NewServeMux(),
-		projectDir:  absPath,
-		clients:     make(map[*clientConn]bool),
-		rateLimiter: newConnectionRateLimiter(),
-		stopChan:    make(chan struct{}),
-		currentMode: service.LogModeLocal, // Default to local mode
-	}
-	srv.setupRoutes()
+	srv := newServer(absPath)
 	servers[key] = srv
 
 	return srv
@@ -75,7 +82,33 @@ func (s *Server) GetURL() string {
 	if !s.started || s.port == 0 {
 		return ""
 	}
-	return fmt.Sprintf("http://localhost:%d", s.port)
+	return fmt.Sprintf("%s://localhost:%d", s.scheme(), s.port)
+}
+
+// SetShutdownHandler sets the function called when another process asks the dashboard to
+// shut down through POST /api/shutdown. The handler should stop the `azd app run` session
+// that owns this dashboard; a nil handler makes the endpoint reject requests.
+func (s *Server) SetShutdownHandler(fn func()) {
+	s.shutdownMu.Lock()
+	defer s.shutdownMu.Unlock()
+	s.onShutdown = fn
+}
+
+// SetRestartHandler sets the function that starts or restarts a service for the start and
+// restart endpoints. The handler should restart the service in the `azd app run` session that
+// owns this dashboard, so the session keeps tracking its process. Without a handler, the
+// dashboard starts the service itself.
+func (s *Server) SetRestartHandler(fn func(serviceName string) error) {
+	s.restartMu.Lock()
+	defer s.restartMu.Unlock()
+	s.onRestart = fn
+}
+
+// restartHandler returns the handler set with SetRestartHandler, or nil.
+func (s *Server) restartHandler() func(serviceName string) error {
+	s.restartMu.Lock()
+	defer s.restartMu.Unlock()
+	return s.onRestart
 }
 
 // Start starts the dashboard server on an assigned port.
@@ -107,14 +140,14 @@ func (s *Server) Start() (string, error) {
 	s.port = port
 	s.server = &http.Server{
 		Addr:              fmt.Sprintf("127.0.0.1:%d", port),
-		Handler:           securityHeaders(s.mux),
+		Handler:           s.handler(),
 		ReadHeaderTimeout: 10 * time.Second,
 	}
 
 	// Start server in background
 	errChan := make(chan error, 1)
 	go func() {
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := s.listenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Printf("Dashboard server error: %v", err)
 			errChan <- err
 		}
@@ -155,14 +188,14 @@ func (s *Server) Start() (string, error) {
 
 		// Port binding failed, try to find an alternative port
 		if altPort, retryErr := s.retryWithAlternativePort(portMgr); retryErr == nil {
-			return fmt.Sprintf("http://localhost:%d", altPort), nil
+			return fmt.Sprintf("%s://localhost:%d", s.scheme(), altPort), nil
 		}
 		return "", fmt.Errorf("dashboard server failed to start: %w", err)
 	default:
 		// Server started successfully
 	}
 
-	url := fmt.Sprintf("http://localhost:%d", port)
+	url := fmt.Sprintf("%s://localhost:%d", s.scheme(), port)
 
 	// Store dashboard port in azdconfig for other commands to discover
 	s.registerPortInConfig(port)
@@ -179,11 +212,14 @@ func (s *Server) Stop() error {
 	s.started = false // Mark as stopped
 	s.startedMu.Unlock()
 
-	// Always clean up from servers map, even if never started
-	serversMu.Lock()
-	_, key := normalizeProjectPath(s.projectDir)
-	delete(servers, key)
-	serversMu.Unlock()
+	// Always clean up from servers map, even if never started.
+	// Embedded handlers are never registered, so they must not evict the shared instance.
+	if !s.embedded {
+		serversMu.Lock()
+		_, key := normalizeProjectPath(s.projectDir)
+		delete(servers, key)
+		serversMu.Unlock()
+	}
 
 	if !wasStarted {
 		return nil // Server was never started, nothing more to stop
@@ -223,17 +259,29 @@ func (s *Server) Stop() error {
 		_ = s.server.Close()
 	}
 
-	// Now safe — no more handlers running
-	if s.configClient != nil {
-		s.configClient.Close()
-		s.configClient = nil
-	}
+	s.releaseResources()
+	return nil
+}
 
-	// Shutdown rate limiter
-	if s.rateLimiter != nil {
-		s.rateLimiter.shutdown()
-		s.rateLimiter = nil
+// releaseResources closes the config client and shuts down the rate limiter.
+// WebSocket requests that arrive afterwards are refused.
+func (s *Server) releaseResources() {
+	s.resourcesMu.Lock()
+	configClient, rateLimiter := s.configClient, s.rateLimiter
+	s.configClient, s.rateLimiter = nil, nil
+	s.resourcesMu.Unlock()
+
+	if configClient != nil {
+		configClient.Close()
+	}
+	if rateLimiter != nil {
+		rateLimiter.shutdown()
 	}
+}
 
-	return nil
+// connectionLimiter returns the WebSocket rate limiter, or nil once the server is stopped or closed.
+func (s *Server) connectionLimiter() *connectionRateLimiter {
+	s.resourcesMu.Lock()
+	defer s.resourcesMu.Unlock()
+	return s.rateLimiter
 }
@@ -10,7 +10,11 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"runtime/debug"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,6 +36,25 @@ func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(`{"status":"ok"}`))
 }
 
+// handleShutdown stops the `azd app run` session that owns this dashboard.
+// The response is sent before shutting down so the caller isn't left waiting on a closed connection.
+func (s *Server) handleShutdown(w http.ResponseWriter, r *http.Request) {
+	s.shutdownMu.Lock()
+	onShutdown := s.onShutdown
+	s.shutdownMu.Unlock()
+
+	if onShutdown == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "shutdown is not supported by this dashboard", nil)
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, jsonContentType)
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = w.Write([]byte(`{"status":"shutting down"}`))
+
+	go onShutdown()
+}
+
 // handleGetEnvironment returns environment information for Codespace detection.
 func (s *Server) handleGetEnvironment(w http.ResponseWriter, r *http.Request) {
 
@@ -121,6 +144,67 @@ func (s *Server) handleGetProject(w http.ResponseWriter, r *http.Request) {
 	WriteJSONSuccess(w, response)
 }
 
+// ActiveProject is an azd app run session of a project, listed by /api/projects.
+type ActiveProject struct {
+	Name         string   `json:"name"`
+	Dir          string   `json:"dir"`
+	DashboardURL string   `json:"dashboardUrl,omitempty"`
+	Services     []string `json:"services"`
+	Detached     bool     `json:"detached"`
+	Current      bool     `json:"current"` // The project of this dashboard
+}
+
+// handleGetProjects returns the projects with an active azd app run session, from the session
+// registry, so the dashboard can switch between them. This dashboard's project is always listed.
+func (s *Server) handleGetProjects(w http.ResponseWriter, r *http.Request) {
+	sessions, err := service.ListSessions()
+	if err != nil {
+		InternalError(w, "Failed to list sessions", err)
+		return
+	}
+
+	_, currentKey := normalizeProjectPath(s.projectDir)
+	projects := make([]ActiveProject, 0, len(sessions)+1)
+	foundCurrent := false
+	for _, session := range sessions {
+		_, key := normalizeProjectPath(session.Project)
+		project := ActiveProject{
+			Name:         projectName(session.Project),
+			Dir:          session.Project,
+			DashboardURL: session.DashboardURL,
+			Services:     session.Services,
+			Detached:     session.Detached,
+			Current:      key == currentKey,
+		}
+		if project.Current {
+			foundCurrent = true
+			project.DashboardURL = s.GetURL()
+		}
+		projects = append(projects, project)
+	}
+	// The session registers itself only once its dashboard is up, and a dashboard can run without one
+	if !foundCurrent {
+		projects = append(projects, ActiveProject{
+			Name:         projectName(s.projectDir),
+			Dir:          s.projectDir,
+			DashboardURL: s.GetURL(),
+			Services:     []string{},
+			Current:      true,
+		})
+		sort.Slice(projects, func(i, j int) bool { return projects[i].Dir < projects[j].Dir })
+	}
+
+	WriteJSONSuccess(w, map[string]interface{}{"projects": projects})
+}
+
+// projectName returns the name of a project from its azure.yaml, or its directory name.
+func projectName(projectDir string) string {
+	if azureYaml, err := service.ParseAzureYaml(projectDir); err == nil && azureYaml.Name != "" {
+		return azureYaml.Name
+	}
+	return filepath.Base(projectDir)
+}
+
 // handleGetLogs returns recent logs for services.
 func (s *Server) handleGetLogs(w http.ResponseWriter, r *http.Request) {
 	// Add panic recovery with detailed logging
@@ -140,6 +224,12 @@ func (s *Server) handleGetLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	levels, err := parseLogLevelFilter(r.URL.Query().Get("level"))
+	if err != nil {
+		BadRequest(w, err.Error(), nil)
+		return
+	}
+
 	// Default to 500 lines with bounds checking
 	tail := 500
 	if tailStr != "" {
@@ -177,6 +267,7 @@ func (s *Server) handleGetLogs(w http.ResponseWriter, r *http.Request) {
 		// Get logs from all services
 		logs = logManager.GetAllLogs(tail)
 	}
+	logs = filterLogsByLevels(logs, levels)
 
 	// Enable gzip compression for large responses
 	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
@@ -195,6 +286,141 @@ func (s *Server) handleGetLogs(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+const (
+	// maxLogSearchQueryLength bounds the regular expression of a log search.
+	maxLogSearchQueryLength = 500
+
+	// defaultLogSearchLimit and maxLogSearchLimit bound the number of log search matches.
+	defaultLogSearchLimit = 200
+	maxLogSearchLimit     = 1000
+
+	// defaultLogSearchContext is the number of lines shown before and after a log search match.
+	defaultLogSearchContext = 2
+)
+
+// logSearchResponse is the response of GET /api/logs/search.
+type logSearchResponse struct {
+	Query     string                        `json:"query"`
+	Matches   []service.LogEntryWithContext `json:"matches"`
+	Truncated bool                          `json:"truncated"` // More matches exist beyond the limit
+}
+
+// handleLogSearch handles GET /api/logs/search?q=...&service=...&level=...&context=...&limit=...&caseSensitive=true.
+// q is a regular expression matched against the messages of the in-memory and persisted logs.
+// Matching is case-insensitive unless caseSensitive is true.
+func (s *Server) handleLogSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	q := query.Get("q")
+	if strings.TrimSpace(q) == "" {
+		BadRequest(w, "Missing search query", nil)
+		return
+	}
+	if len(q) > maxLogSearchQueryLength {
+		BadRequest(w, fmt.Sprintf("Search query is too long (max %d characters)", maxLogSearchQueryLength), nil)
+		return
+	}
+	expr := q
+	if query.Get("caseSensitive") != "true" {
+		expr = "(?i)" + q
+	}
+	// Go regular expressions run in linear time, so user-supplied patterns are safe to run
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		BadRequest(w, fmt.Sprintf("Invalid search pattern: %s", err.Error()), nil)
+		return
+	}
+
+	serviceName := query.Get("service")
+	if err := security.ValidateServiceName(serviceName, true); err != nil {
+		BadRequest(w, "Invalid service name", nil)
+		return
+	}
+
+	levels, err := parseLogLevelFilter(query.Get("level"))
+	if err != nil {
+		BadRequest(w, err.Error(), nil)
+		return
+	}
+
+	contextLines := parseBoundedInt(query.Get("context"), defaultLogSearchContext, 0, service.MaxContextLines)
+	limit := parseBoundedInt(query.Get("limit"), defaultLogSearchLimit, 1, maxLogSearchLimit)
+
+	logManager := service.GetLogManager(s.projectDir)
+	if logManager == nil {
+		InternalError(w, "Log manager not initialized", nil)
+		return
+	}
+	if serviceName != "" {
+		if _, exists := logManager.GetBuffer(serviceName); !exists {
+			NotFound(w, fmt.Sprintf("Service '%s' not found", serviceName))
+			return
+		}
+	}
+
+	// Ask for one more match than the limit to tell whether results were truncated
+	matches, err := logManager.SearchLogs(serviceName, service.LogSearchOptions{
+		Pattern:      pattern,
+		Levels:       levels,
+		ContextLines: contextLines,
+		Limit:        limit + 1,
+	})
+	if err != nil {
+		InternalError(w, "Failed to search logs", err)
+		return
+	}
+
+	response := logSearchResponse{Query: q, Matches: matches}
+	if len(matches) > limit {
+		response.Matches = matches[:limit]
+		response.Truncated = true
+	}
+	if response.Matches == nil {
+		response.Matches = []service.LogEntryWithContext{}
+	}
+	WriteJSONSuccess(w, response)
+}
+
+// parseBoundedInt parses an integer query parameter, returning def when it is missing or
+// invalid and clamping it to [lower, upper].
+func parseBoundedInt(value string, def, lower, upper int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return def
+	}
+	return min(max(n, lower), upper)
+}
+
+// parseLogLevelFilter parses the level query parameter of the log endpoints: a comma-separated
+// list of levels (e.g. "warn,error"). Returns nil when no level filter is given.
+func parseLogLevelFilter(value string) (map[service.LogLevel]bool, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	levels := make(map[service.LogLevel]bool)
+	for _, name := range strings.Split(value, ",") {
+		level, ok := service.ParseLogLevel(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid log level '%s' (must be debug, info, warn or error)", strings.TrimSpace(name))
+		}
+		levels[level] = true
+	}
+	return levels, nil
+}
+
+// filterLogsByLevels returns the log entries with one of the given levels, or all entries when levels is nil.
+func filterLogsByLevels(logs []service.LogEntry, levels map[service.LogLevel]bool) []service.LogEntry {
+	if levels == nil {
+		return logs
+	}
+	filtered := make([]service.LogEntry, 0, len(logs))
+	for _, entry := range logs {
+		if levels[entry.Level] {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
 // handleStartService handles POST /api/services/start to start a service or all services.
 func (s *Server) handleStartService(w http.ResponseWriter, r *http.Request) {
 	newServiceOperationHandler(s, opStart).Handle(w, r)
@@ -210,6 +436,56 @@ func (s *Server) handleRestartService(w http.ResponseWriter, r *http.Request) {
 	newServiceOperationHandler(s, opRestart).Handle(w, r)
 }
 
+// serviceActionOperations maps the actions of /api/services/{name}/{action} to operations.
+var serviceActionOperations = map[string]serviceOperation{
+	"restart": opRestart,
+	"stop":    opStop,
+}
+
+// handleServiceActionRouter handles the endpoints of a single service:
+// POST /api/services/{name}/restart, POST /api/services/{name}/stop and GET /api/services/{name}/env.
+func (s *Server) handleServiceActionRouter(w http.ResponseWriter, r *http.Request) {
+	name, action, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/services/"), "/")
+	if !ok || name == "" {
+		NotFound(w, fmt.Sprintf("Unknown service endpoint: %s", r.URL.Path))
+		return
+	}
+
+	if action == "env" {
+		MethodGuard(func(w http.ResponseWriter, r *http.Request) {
+			s.handleGetServiceEnv(w, name)
+		}, http.MethodGet)(w, r)
+		return
+	}
+
+	op, known := serviceActionOperations[action]
+	if !known {
+		NotFound(w, fmt.Sprintf("Unknown service endpoint: %s", r.URL.Path))
+		return
+	}
+	MethodGuard(func(w http.ResponseWriter, r *http.Request) {
+		newServiceOperationHandler(s, op).handleSingleOperation(w, r, name)
+	}, http.MethodPost)(w, r)
+}
+
+// handleGetServiceEnv handles GET /api/services/{name}/env. It returns the command line,
+// working directory and environment (secrets masked) the service was last started with.
+func (s *Server) handleGetServiceEnv(w http.ResponseWriter, serviceName string) {
+	if err := security.ValidateServiceName(serviceName, false); err != nil {
+		BadRequest(w, fmt.Sprintf("Invalid service name: %s", err.Error()), nil)
+		return
+	}
+
+	config, ok := service.GetLaunchConfig(s.projectDir, serviceName)
+	if !ok {
+		NotFound(w, fmt.Sprintf("Service '%s' has not been started by this dashboard's azd app run", serviceName))
+		return
+	}
+	config.Env = service.MaskSecrets(service.Service{}, config.Env)
+
+	WriteJSONSuccess(w, config)
+}
+
 // handleFallback provides a simple HTML page when static files aren't available.
 func (s *Server) handleFallback(w http.ResponseWriter, r *http.Request) {
 	reg := registry.GetRegistry(s.projectDir)
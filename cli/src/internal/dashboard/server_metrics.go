@@ -0,0 +1,168 @@
+package dashboard
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-app/cli/src/internal/metrics"
+	"github.com/jongio/azd-core/registry"
+)
+
+const (
+	// metricsSampleInterval is how often service resource usage is sampled while clients are connected.
+	metricsSampleInterval = 2 * time.Second
+	// metricsHistorySize is the number of samples kept per service (5 minutes at the sample interval).
+	metricsHistorySize = 150
+)
+
+// metricsPoint is one entry of a service's resource usage history.
+type metricsPoint struct {
+	Timestamp   time.Time `json:"timestamp"`
+	CPUPercent  float64   `json:"cpuPercent"`
+	MemoryBytes uint64    `json:"memoryBytes"`
+}
+
+// metricsResponse is the body of GET /api/metrics.
+type metricsResponse struct {
+	IntervalSeconds int                       `json:"intervalSeconds"`
+	Services        []metrics.ServiceMetrics  `json:"services"`
+	History         map[string][]metricsPoint `json:"history"`
+}
+
+// serviceMetrics samples the services of a project and keeps a short history for graphs.
+type serviceMetrics struct {
+	sampler   *metrics.Sampler
+	startOnce sync.Once // Starts the sampling loop on the first WebSocket client
+
+	mu      sync.RWMutex
+	latest  []metrics.ServiceMetrics
+	sampled time.Time
+	history map[string][]metricsPoint
+}
+
+// newServiceMetrics creates an empty serviceMetrics.
+func newServiceMetrics() *serviceMetrics {
+	return &serviceMetrics{
+		sampler: metrics.NewSampler(),
+		latest:  []metrics.ServiceMetrics{},
+		history: make(map[string][]metricsPoint),
+	}
+}
+
+// record stores a sample and appends it to the history of each service.
+// Services missing from the sample are dropped from the history.
+func (m *serviceMetrics) record(sample []metrics.ServiceMetrics, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	history := make(map[string][]metricsPoint, len(sample))
+	for _, svc := range sample {
+		points := append(m.history[svc.Service], metricsPoint{
+			Timestamp:   svc.Timestamp,
+			CPUPercent:  svc.CPUPercent,
+			MemoryBytes: svc.MemoryBytes,
+		})
+		if len(points) > metricsHistorySize {
+			points = points[len(points)-metricsHistorySize:]
+		}
+		history[svc.Service] = points
+	}
+	m.history = history
+	m.latest = sample
+	m.sampled = at
+}
+
+// snapshot returns the latest sample and a copy of the history.
+func (m *serviceMetrics) snapshot() metricsResponse {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := make(map[string][]metricsPoint, len(m.history))
+	for name, points := range m.history {
+		history[name] = append([]metricsPoint(nil), points...)
+	}
+	return metricsResponse{
+		IntervalSeconds: int(metricsSampleInterval / time.Second),
+		Services:        append([]metrics.ServiceMetrics{}, m.latest...),
+		History:         history,
+	}
+}
+
+// isStale reports whether the latest sample is older than the sample interval.
+func (m *serviceMetrics) isStale() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return time.Since(m.sampled) >= metricsSampleInterval
+}
+
+// metricsTargets returns the processes of the project's running services.
+// Stopped and failed services are skipped so a reused PID isn't attributed to them.
+func metricsTargets(entries []*registry.ServiceRegistryEntry) []metrics.Target {
+	targets := make([]metrics.Target, 0, len(entries))
+	for _, entry := range entries {
+		if entry.PID <= 0 {
+			continue
+		}
+		switch entry.Status {
+		case constants.StatusStopped, constants.StatusStopping, constants.StatusError, "built", "completed", "failed":
+			continue
+		}
+		targets = append(targets, metrics.Target{Service: entry.Name, PID: entry.PID, StartTime: entry.StartTime})
+	}
+	return targets
+}
+
+// sampleMetrics samples the project's running services and records the result.
+func (s *Server) sampleMetrics(ctx context.Context) []metrics.ServiceMetrics {
+	targets := metricsTargets(registry.GetRegistry(s.projectDir).ListAll())
+	sample := s.metrics.sampler.Sample(ctx, targets)
+	s.metrics.record(sample, time.Now())
+	return sample
+}
+
+// startMetricsLoop starts sampling resource usage in the background, broadcasting each sample to
+// WebSocket clients as a "metrics" message. Sampling is skipped while no clients are connected.
+// The loop runs until the server stops; calling this again has no effect.
+func (s *Server) startMetricsLoop() {
+	s.metrics.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(metricsSampleInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-s.stopChan:
+					return
+				case <-ticker.C:
+				}
+
+				s.clientsMu.RLock()
+				connected := len(s.clients)
+				s.clientsMu.RUnlock()
+				if connected == 0 {
+					continue
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), metricsSampleInterval)
+				sample := s.sampleMetrics(ctx)
+				cancel()
+				_ = s.broadcast(map[string]interface{}{
+					"type":    "metrics",
+					"metrics": sample,
+				})
+			}
+		}()
+	})
+}
+
+// handleGetMetrics returns the CPU, memory, and uptime of each running service, with the recent
+// history for graphs. A fresh sample is taken when the background loop hasn't run recently.
+func (s *Server) handleGetMetrics(w http.ResponseWriter, r *http.Request) {
+	if s.metrics.isStale() {
+		s.sampleMetrics(r.Context())
+	}
+
+	WriteJSONSuccess(w, s.metrics.snapshot())
+}
@@ -0,0 +1,77 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/metrics"
+	"github.com/jongio/azd-core/registry"
+)
+
+func TestHandleGetMetrics(t *testing.T) {
+	projectDir := t.TempDir()
+	reg := registry.GetRegistry(projectDir)
+	entries := []*registry.ServiceRegistryEntry{
+		{Name: "api", PID: os.Getpid(), Status: "running", StartTime: time.Now().Add(-time.Minute)},
+		{Name: "old", PID: os.Getpid(), Status: "stopped"},
+		{Name: "db", Status: "running"}, // Containers have no local PID
+	}
+	for _, entry := range entries {
+		if err := reg.Register(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() { _ = reg.Clear() }()
+
+	srv := NewHandler(projectDir, Options{})
+	defer func() { _ = srv.Close() }()
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/metrics = %d %s", rec.Code, rec.Body.String())
+	}
+
+	var resp metricsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(resp.Services) != 1 || resp.Services[0].Service != "api" {
+		t.Fatalf("services = %+v, want only api", resp.Services)
+	}
+	if resp.Services[0].MemoryBytes == 0 || resp.Services[0].UptimeSeconds < 59 {
+		t.Errorf("api metrics = %+v, want memory and uptime", resp.Services[0])
+	}
+	if len(resp.History["api"]) != 1 || resp.IntervalSeconds != 2 {
+		t.Errorf("history = %+v, interval = %d, want one point every 2s", resp.History, resp.IntervalSeconds)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /api/metrics = %d, want 405", rec.Code)
+	}
+}
+
+func TestServiceMetrics_HistoryIsBounded(t *testing.T) {
+	m := newServiceMetrics()
+	for i := 0; i < metricsHistorySize+10; i++ {
+		m.record([]metrics.ServiceMetrics{{Service: "api", CPUPercent: float64(i)}}, time.Now())
+	}
+	history := m.snapshot().History["api"]
+	if len(history) != metricsHistorySize {
+		t.Fatalf("history has %d points, want %d", len(history), metricsHistorySize)
+	}
+	if last := history[len(history)-1].CPUPercent; last != float64(metricsHistorySize+9) {
+		t.Errorf("last point CPU = %v, want the newest sample", last)
+	}
+
+	m.record([]metrics.ServiceMetrics{}, time.Now())
+	if _, ok := m.snapshot().History["api"]; ok {
+		t.Error("history of a stopped service should be dropped")
+	}
+}
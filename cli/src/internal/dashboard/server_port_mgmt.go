@@ -19,11 +19,14 @@ import (
 
 // getOrCreateConfigClient returns the cached config client, creating it lazily if needed.
 func (s *Server) getOrCreateConfigClient() azdconfig.ConfigClient {
+	s.resourcesMu.Lock()
+	defer s.resourcesMu.Unlock()
+
 	if s.configClient != nil {
 		return s.configClient
 	}
 
-	client, err := azdconfig.NewClient(context.Background())
+	client, err := azdconfig.Open(context.Background())
 	if err != nil {
 		slog.Debug("failed to create azdconfig client, using in-memory fallback", "error", err)
 		s.configClient = azdconfig.NewInMemoryClient()
@@ -118,7 +121,7 @@ func (s *Server) retryWithAlternativePort(portMgr *portmanager.PortManager) (int
 
 		errChan := make(chan error, 1)
 		go func() {
-			if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			if err := s.listenAndServe(); err != nil && err != http.ErrServerClosed {
 				log.Printf("Dashboard server error on alternative port: %v", err)
 				errChan <- err
 			}
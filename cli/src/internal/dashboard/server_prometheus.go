@@ -0,0 +1,186 @@
+package dashboard
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/cache"
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/registry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// EnvPrometheus enables the /metrics endpoint when set to "true", like dashboard.prometheus in azure.yaml.
+const EnvPrometheus = "AZD_APP_PROMETHEUS"
+
+// Lifecycle metrics are recorded from service events, which are process-wide,
+// so they are shared by every dashboard server in the process.
+var (
+	serviceRestarts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "azd_app_service_restarts_total",
+			Help: "Number of times a service was started again after its first start",
+		},
+		[]string{"service"},
+	)
+
+	serviceStartDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "azd_app_service_start_duration_seconds",
+			Help:    "Time from the orchestrator starting a service until its process or container is running",
+			Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120},
+		},
+		[]string{"service"},
+	)
+
+	lifecycleOnce sync.Once
+	lifecycle     = struct {
+		mu       sync.Mutex
+		starting map[string]time.Time // Services being started, with when they started
+		started  map[string]bool      // Services that have started at least once
+	}{
+		starting: make(map[string]time.Time),
+		started:  make(map[string]bool),
+	}
+)
+
+var (
+	serviceUpDesc = prometheus.NewDesc("azd_app_service_up",
+		"Whether the service is running (1) or not (0)", []string{"service"}, nil)
+	servicePortDesc = prometheus.NewDesc("azd_app_service_port",
+		"Port assigned to the service", []string{"service"}, nil)
+	serviceCPUDesc = prometheus.NewDesc("azd_app_service_cpu_percent",
+		"CPU usage of the service's processes, in percent of one core", []string{"service"}, nil)
+	serviceMemoryDesc = prometheus.NewDesc("azd_app_service_memory_bytes",
+		"Resident memory of the service's processes", []string{"service"}, nil)
+	reqsCacheHitsDesc = prometheus.NewDesc("azd_app_reqs_cache_hits_total",
+		"Requirement checks answered from the reqs cache", nil, nil)
+	reqsCacheMissesDesc = prometheus.NewDesc("azd_app_reqs_cache_misses_total",
+		"Requirement checks that missed the reqs cache", nil, nil)
+	reqsCacheHitRatioDesc = prometheus.NewDesc("azd_app_reqs_cache_hit_ratio",
+		"Fraction of reqs cache lookups that were hits", nil, nil)
+)
+
+// recordLifecycleEvents subscribes the lifecycle metrics to service events. It is only
+// done once per process, however many dashboards enable Prometheus metrics.
+func recordLifecycleEvents() {
+	lifecycleOnce.Do(func() {
+		service.OnServiceStarting(func(info service.ServiceEventInfo) {
+			lifecycle.mu.Lock()
+			defer lifecycle.mu.Unlock()
+			lifecycle.starting[info.Service] = info.Time
+			if lifecycle.started[info.Service] {
+				serviceRestarts.WithLabelValues(info.Service).Inc()
+			}
+		})
+		service.OnServiceStarted(func(info service.ServiceEventInfo) {
+			lifecycle.mu.Lock()
+			defer lifecycle.mu.Unlock()
+			lifecycle.started[info.Service] = true
+			if startedAt, ok := lifecycle.starting[info.Service]; ok {
+				serviceStartDuration.WithLabelValues(info.Service).Observe(info.Time.Sub(startedAt).Seconds())
+				delete(lifecycle.starting, info.Service)
+			}
+		})
+		service.OnServiceStopped(func(info service.ServiceEventInfo) {
+			// A failed start is followed by a stopped event instead of a started one
+			lifecycle.mu.Lock()
+			defer lifecycle.mu.Unlock()
+			delete(lifecycle.starting, info.Service)
+		})
+	})
+}
+
+// prometheusCollector reports the current state of the project's services when scraped.
+type prometheusCollector struct {
+	s *Server
+}
+
+// Describe implements prometheus.Collector.
+func (c *prometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- serviceUpDesc
+	ch <- servicePortDesc
+	ch <- serviceCPUDesc
+	ch <- serviceMemoryDesc
+	ch <- reqsCacheHitsDesc
+	ch <- reqsCacheMissesDesc
+	ch <- reqsCacheHitRatioDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *prometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, entry := range registry.GetRegistry(c.s.projectDir).ListAll() {
+		up := 0.0
+		if isServiceUp(entry.Status) {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(serviceUpDesc, prometheus.GaugeValue, up, entry.Name)
+	}
+
+	for _, assignment := range portmanager.GetPortManager(c.s.projectDir).Assignments() {
+		if assignment.ServiceName == constants.DashboardServiceName {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(servicePortDesc, prometheus.GaugeValue, float64(assignment.Port), assignment.ServiceName)
+	}
+
+	if c.s.metrics.isStale() {
+		ctx, cancel := context.WithTimeout(context.Background(), metricsSampleInterval)
+		c.s.sampleMetrics(ctx)
+		cancel()
+	}
+	for _, m := range c.s.metrics.snapshot().Services {
+		ch <- prometheus.MustNewConstMetric(serviceCPUDesc, prometheus.GaugeValue, m.CPUPercent, m.Service)
+		ch <- prometheus.MustNewConstMetric(serviceMemoryDesc, prometheus.GaugeValue, float64(m.MemoryBytes), m.Service)
+	}
+
+	stats := cache.ProcessStats()
+	ch <- prometheus.MustNewConstMetric(reqsCacheHitsDesc, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(reqsCacheMissesDesc, prometheus.CounterValue, float64(stats.Misses))
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		ch <- prometheus.MustNewConstMetric(reqsCacheHitRatioDesc, prometheus.GaugeValue, float64(stats.Hits)/float64(lookups))
+	}
+}
+
+// isServiceUp reports whether a registry status means the service's process is running.
+func isServiceUp(status string) bool {
+	switch status {
+	case constants.StatusRunning, constants.StatusReady, "watching":
+		return true
+	default:
+		return false
+	}
+}
+
+// EnablePrometheus serves Prometheus metrics of the project's services at /metrics.
+// Call it before services are started so their start latency is recorded.
+func (s *Server) EnablePrometheus() {
+	recordLifecycleEvents()
+
+	s.prometheusMu.Lock()
+	defer s.prometheusMu.Unlock()
+	if s.prometheus != nil {
+		return
+	}
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(&prometheusCollector{s: s}, serviceRestarts, serviceStartDuration)
+	s.prometheus = promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
+
+// handlePrometheusMetrics serves the Prometheus metrics, or 404 when they aren't enabled.
+func (s *Server) handlePrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	s.prometheusMu.Lock()
+	handler := s.prometheus
+	s.prometheusMu.Unlock()
+
+	if handler == nil {
+		writeJSONError(w, http.StatusNotFound, "Prometheus metrics are not enabled; set dashboard.prometheus: true in azure.yaml", nil)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,71 @@
+package dashboard
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-core/registry"
+)
+
+func TestHandlePrometheusMetrics(t *testing.T) {
+	projectDir := t.TempDir()
+	reg := registry.GetRegistry(projectDir)
+	for _, entry := range []*registry.ServiceRegistryEntry{
+		{Name: "promapi", Status: "running"},
+		{Name: "promworker", Status: "stopped"},
+	} {
+		if err := reg.Register(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() { _ = reg.Clear() }()
+
+	srv := NewHandler(projectDir, Options{})
+	defer func() { _ = srv.Close() }()
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("GET /metrics before EnablePrometheus = %d, want 404", rec.Code)
+	}
+
+	srv.EnablePrometheus()
+
+	// Start, restart and start again; the second start counts as a restart
+	start := time.Now()
+	service.EmitServiceEvent(service.ServiceEventInfo{Event: service.EventServiceStarting, Service: "promapi", Time: start})
+	service.EmitServiceEvent(service.ServiceEventInfo{Event: service.EventServiceStarted, Service: "promapi", Time: start.Add(2 * time.Second)})
+	service.EmitServiceEvent(service.ServiceEventInfo{Event: service.EventServiceStarting, Service: "promapi", Time: start.Add(time.Minute)})
+	service.EmitServiceEvent(service.ServiceEventInfo{Event: service.EventServiceStarted, Service: "promapi", Time: start.Add(time.Minute + time.Second)})
+
+	server := httptest.NewServer(srv)
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /metrics = %d %s", resp.StatusCode, body)
+	}
+
+	for _, want := range []string{
+		`azd_app_service_up{service="promapi"} 1`,
+		`azd_app_service_up{service="promworker"} 0`,
+		`azd_app_service_restarts_total{service="promapi"} 1`,
+		`azd_app_service_start_duration_seconds_count{service="promapi"} 2`,
+		`azd_app_service_start_duration_seconds_sum{service="promapi"} 3`,
+		`azd_app_reqs_cache_hits_total`,
+		`azd_app_reqs_cache_misses_total`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
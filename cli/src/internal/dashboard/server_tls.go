@@ -0,0 +1,101 @@
+package dashboard
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"sync"
+)
+
+// tlsRecordHandshake is the first byte of a TLS ClientHello.
+const tlsRecordHandshake = 0x16
+
+// SetTLSConfig serves the dashboard over HTTPS with the given configuration. It must be called
+// before Start. Plain HTTP is still accepted on the same port, so other azd app processes and
+// existing bookmarks keep working.
+func (s *Server) SetTLSConfig(config *tls.Config) {
+	s.startedMu.Lock()
+	defer s.startedMu.Unlock()
+	s.tlsConfig = config
+}
+
+// scheme returns the scheme of the dashboard URL: "https" when TLS is configured, "http" otherwise.
+func (s *Server) scheme() string {
+	if s.tlsConfig != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// listenAndServe binds the server's address and serves requests until the server is closed.
+func (s *Server) listenAndServe() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	if s.tlsConfig != nil {
+		listener = newDualProtocolListener(listener, s.tlsConfig)
+	}
+	return s.server.Serve(listener)
+}
+
+// dualProtocolListener accepts both TLS and plain HTTP connections on one port. Each connection
+// is classified by its first byte when it is first read.
+type dualProtocolListener struct {
+	net.Listener
+	config *tls.Config
+}
+
+func newDualProtocolListener(listener net.Listener, config *tls.Config) net.Listener {
+	return &dualProtocolListener{Listener: listener, config: config}
+}
+
+// Accept waits for the next connection. Classification is deferred to the first Read so a slow
+// client can't block Accept; the server's read deadlines apply to it.
+func (l *dualProtocolListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &sniffConn{Conn: conn, config: l.config}, nil
+}
+
+// sniffConn is a connection that is either TLS or plain, decided by its first byte.
+type sniffConn struct {
+	net.Conn
+	config *tls.Config
+	once   sync.Once
+	inner  net.Conn
+}
+
+func (c *sniffConn) init() {
+	c.once.Do(func() {
+		reader := bufio.NewReader(c.Conn)
+		peeked := &peekedConn{Conn: c.Conn, reader: reader}
+		if first, err := reader.Peek(1); err == nil && first[0] == tlsRecordHandshake {
+			c.inner = tls.Server(peeked, c.config)
+			return
+		}
+		c.inner = peeked
+	})
+}
+
+func (c *sniffConn) Read(b []byte) (int, error) {
+	c.init()
+	return c.inner.Read(b)
+}
+
+func (c *sniffConn) Write(b []byte) (int, error) {
+	c.init()
+	return c.inner.Write(b)
+}
+
+// peekedConn reads through the buffer that holds the peeked byte.
+type peekedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *peekedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
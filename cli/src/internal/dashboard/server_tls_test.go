@@ -0,0 +1,63 @@
+package dashboard
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDualProtocolListener(t *testing.T) {
+	// Reuse the test server's certificate for 127.0.0.1
+	certServer := httptest.NewTLSServer(http.NotFoundHandler())
+	certServer.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "ok")
+		}),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() { _ = server.Serve(newDualProtocolListener(listener, certServer.TLS)) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // #nosec G402 -- test certificate
+		},
+	}
+	addr := listener.Addr().String()
+	for _, url := range []string{"https://" + addr, "http://" + addr} {
+		resp, err := client.Get(url)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", url, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+			t.Errorf("GET %s = %d %q, want 200 \"ok\"", url, resp.StatusCode, body)
+		}
+		if (resp.TLS != nil) != (url[:5] == "https") {
+			t.Errorf("GET %s TLS = %v, want TLS only for https", url, resp.TLS != nil)
+		}
+	}
+}
+
+func TestServer_URLSchemeWithTLS(t *testing.T) {
+	s := &Server{port: 4280, started: true}
+	if got := s.GetURL(); got != "http://localhost:4280" {
+		t.Errorf("GetURL() = %q, want http", got)
+	}
+	s.SetTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12})
+	if got := s.GetURL(); got != "https://localhost:4280" {
+		t.Errorf("GetURL() with TLS = %q, want https", got)
+	}
+}
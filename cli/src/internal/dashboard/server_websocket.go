@@ -26,7 +26,7 @@ type clientConn struct {
 // handleWebSocket handles WebSocket connections for live updates.
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	// Capture rate limiter early to avoid race with Stop()
-	rl := s.rateLimiter
+	rl := s.connectionLimiter()
 	conn, err := acceptWebSocket(w, r, rl)
 	if err != nil {
 		if err != http.ErrAbortHandler {
@@ -40,11 +40,6 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	clientWrapper := &clientConn{client: client}
 	clientIP := getClientIP(r)
 
-	// Register client IMMEDIATELY to ensure rate limiter cleanup happens
-	s.clientsMu.Lock()
-	s.clients[clientWrapper] = true
-	s.clientsMu.Unlock()
-
 	defer func() {
 		s.clientsMu.Lock()
 		delete(s.clients, clientWrapper)
@@ -57,20 +52,12 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	// Send initial service data
-	services, err := serviceinfo.GetServiceInfo(s.projectDir)
-	if err != nil {
-		log.Printf("Warning: Failed to get service info: %v", err)
-		services = []*serviceinfo.ServiceInfo{} // Empty array on error
-	}
-
-	if err := clientWrapper.writeWebSocketJSON(map[string]interface{}{
-		"type":     "services",
-		"services": services,
-	}); err != nil {
+	// Register the client and send the services, or the events it missed when reconnecting
+	if err := s.sendInitialState(clientWrapper, r); err != nil {
 		log.Printf("Failed to send initial services: %v", err)
 		return
 	}
+	s.startMetricsLoop()
 
 	// Start health monitoring
 	monitor := newWSHealthMonitor(client)
@@ -106,70 +93,71 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// BroadcastUpdate sends service updates to all connected WebSocket clients.
-// Broadcasts asynchronously with goroutine limiting to prevent resource exhaustion.
-func (s *Server) BroadcastUpdate(services []*registry.ServiceRegistryEntry) {
-	// Copy client list to avoid holding lock during writes
-	s.clientsMu.RLock()
-	clients := make([]*clientConn, 0, len(s.clients))
-	for client := range s.clients {
-		clients = append(clients, client)
+// sendInitialState registers a /api/ws client and sends it the state it starts from.
+// A client reconnecting with ?token=...&since=N is sent the service-status events it missed.
+// Any other client, or one that can't resume, is sent all services together with the
+// reconnect token and the current sequence number; "resync" is set when resuming failed.
+// The client's write lock is held throughout, so broadcasts racing with the connection are
+// written after the initial state.
+func (s *Server) sendInitialState(conn *clientConn, r *http.Request) error {
+	client := conn.client
+	client.writeMu.Lock()
+	defer client.writeMu.Unlock()
+
+	register := func() {
+		s.clientsMu.Lock()
+		s.clients[conn] = true
+		s.clientsMu.Unlock()
 	}
-	s.clientsMu.RUnlock()
 
-	message := map[string]interface{}{
-		"type":     "services",
-		"services": services,
+	token, since, resume := resumeParams(r)
+	if resume {
+		if missed, ok := s.events.missedSince(token, since, register); ok {
+			for _, message := range missed {
+				data, err := json.Marshal(message)
+				if err != nil {
+					return fmt.Errorf("failed to marshal replayed event: %w", err)
+				}
+				if err := client.writeLocked(data); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	} else {
+		register()
 	}
 
-	// Marshal once before broadcast to avoid repeated CPU work
-	jsonBytes, err := json.Marshal(message)
+	token, seq := s.events.current()
+	services, err := serviceinfo.GetServiceInfo(s.projectDir)
 	if err != nil {
-		log.Printf("Failed to marshal broadcast message: %v", err)
-		return
+		log.Printf("Warning: Failed to get service info: %v", err)
+		services = []*serviceinfo.ServiceInfo{} // Empty array on error
 	}
 
-	// Limit concurrent broadcast goroutines to prevent resource exhaustion
-	sem := make(chan struct{}, service.WebSocketMaxConcurrentBroadcasts)
-	var wg sync.WaitGroup
-
-	for _, clientConn := range clients {
-		// Capture client pointer before spawning goroutine to prevent race
-		client := clientConn.client
-		if client == nil {
-			continue
-		}
-
-		wg.Add(1)
-		go func(wsClient *wsClient, data []byte) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			// Write pre-marshaled JSON
-			wsClient.writeMu.Lock()
-			ctx, cancel := context.WithTimeout(wsClient.ctx, service.DefaultWebSocketWriteTimeout)
-			err := wsClient.conn.Write(ctx, websocket.MessageText, data)
-			cancel()
-			wsClient.writeMu.Unlock()
-
-			if err != nil {
-				if !isExpectedCloseError(err) {
-					log.Printf("WebSocket send error: %v", err)
-					// Track consecutive write failures for backpressure
-					failures := wsClient.recordWriteFailure()
-					if failures >= service.WebSocketMaxWriteFailures {
-						log.Printf("WebSocket client exceeded max write failures (%d), will disconnect", failures)
-						// Signal to close by canceling context - main handler will clean up
-						wsClient.cancel()
-					}
-				}
-			}
-		}(client, jsonBytes)
+	message := map[string]interface{}{
+		"type":     "services",
+		"services": services,
+		"token":    token,
+		"seq":      seq,
+	}
+	if resume {
+		message["resync"] = true
 	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal services: %w", err)
+	}
+	return client.writeLocked(data)
+}
 
-	// Wait for all broadcasts to complete before returning
-	wg.Wait()
+// BroadcastUpdate sends service updates to all connected WebSocket clients.
+// Broadcasts asynchronously with goroutine limiting to prevent resource exhaustion.
+func (s *Server) BroadcastUpdate(services []*registry.ServiceRegistryEntry) {
+	_ = s.broadcast(map[string]interface{}{
+		"type":     "services",
+		"services": services,
+	})
 }
 
 // BroadcastServiceUpdate fetches fresh service info and broadcasts to all connected clients.
@@ -182,6 +170,33 @@ func (s *Server) BroadcastServiceUpdate(projectDir string) error {
 		return fmt.Errorf("failed to get service info: %w", err)
 	}
 
+	return s.broadcast(map[string]interface{}{
+		"type":     "services",
+		"services": services,
+	})
+}
+
+// BroadcastRouteChange tells all connected clients that the proxy now forwards a service's
+// route to another backend port, e.g. after the service restarted on a new port. The service
+// stays reachable at publicURL, the proxy's URL for the route.
+func (s *Server) BroadcastRouteChange(serviceName, publicURL string, backendPort int) error {
+	return s.broadcast(map[string]interface{}{
+		"type":        "service-route",
+		"service":     serviceName,
+		"publicUrl":   publicURL,
+		"backendPort": backendPort,
+	})
+}
+
+// broadcast notifies the broadcast hooks and sends the message to all connected WebSocket clients.
+// It returns once every client has been written to or has timed out.
+func (s *Server) broadcast(message map[string]interface{}) error {
+	// Number service-status events for replay; recorded before the client list is copied so a
+	// reconnecting client either gets the event replayed or receives it here
+	if s.events != nil && message["type"] == "services" {
+		s.events.record(message)
+	}
+
 	// Copy client list to avoid holding lock during writes
 	s.clientsMu.RLock()
 	clients := make([]*clientConn, 0, len(s.clients))
@@ -190,10 +205,7 @@ func (s *Server) BroadcastServiceUpdate(projectDir string) error {
 	}
 	s.clientsMu.RUnlock()
 
-	message := map[string]interface{}{
-		"type":     "services",
-		"services": services,
-	}
+	s.notifyBroadcastHooks(message)
 
 	// Marshal once before broadcast to avoid repeated CPU work
 	jsonBytes, err := json.Marshal(message)
@@ -256,8 +268,14 @@ func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	levels, err := parseLogLevelFilter(r.URL.Query().Get("level"))
+	if err != nil {
+		BadRequest(w, err.Error(), nil)
+		return
+	}
+
 	// Capture rate limiter early to avoid race with Stop()
-	rl := s.rateLimiter
+	rl := s.connectionLimiter()
 
 	// Upgrade connection to WebSocket
 	rawConn, err := acceptWebSocket(w, r, rl)
@@ -268,8 +286,9 @@ func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Wrap connection with mutex for safe concurrent writes
-	// Use request context to properly handle client disconnection
-	client := newWSClientWithContext(r.Context(), rawConn)
+	// Clients don't send on the log stream, so CloseRead only processes control frames (the
+	// health monitor's pongs and close); its context ends when the client disconnects
+	client := newWSClientWithContext(rawConn.CloseRead(r.Context()), rawConn)
 	conn := &clientConn{client: client}
 	clientIP := getClientIP(r)
 	defer func() {
@@ -280,87 +299,18 @@ func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	logManager := service.GetLogManager(s.projectDir)
-
-	// Create subscriptions for log streams
-	subscriptions := make(map[string]chan service.LogEntry)
-
-	if serviceName != "" {
-		// Subscribe to specific service
-		buffer, exists := logManager.GetBuffer(serviceName)
-		if !exists {
-			if err := conn.writeWebSocketJSON(map[string]string{"error": fmt.Sprintf("Service '%s' not found", serviceName)}); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to write error to websocket: %v\n", err)
-			}
-			return
-		}
-		subscriptions[serviceName] = buffer.Subscribe()
-	} else {
-		// Subscribe to all services
-		for name, buffer := range logManager.GetAllBuffers() {
-			subscriptions[name] = buffer.Subscribe()
-		}
-	}
+	monitor := newWSHealthMonitor(client)
+	healthErrors := monitor.start()
+	defer monitor.stop()
 
-	// Cleanup function
-	defer func() {
-		for svcName, ch := range subscriptions {
-			if buffer, exists := logManager.GetBuffer(svcName); exists {
-				buffer.Unsubscribe(ch)
-			}
+	logs, ok := s.subscribeLogs(serviceName, levels)
+	if !ok {
+		if err := conn.writeWebSocketJSON(map[string]string{"error": fmt.Sprintf("Service '%s' not found", serviceName)}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write error to websocket: %v\n", err)
 		}
-	}()
-
-	// Merge all subscription channels with backpressure handling
-	// Use constant for buffer size
-	mergedChan := make(chan service.LogEntry, service.WebSocketLogChannelBuffer)
-	stopMerge := make(chan struct{})
-	var wg sync.WaitGroup
-
-	for _, ch := range subscriptions {
-		wg.Add(1)
-		go func(ch chan service.LogEntry) {
-			defer wg.Done()
-			for {
-				select {
-				case entry, ok := <-ch:
-					if !ok {
-						return
-					}
-					// Try to send with timeout to prevent blocking on slow consumers
-					// CRITICAL: Always include stopMerge in select to prevent goroutine leaks
-					select {
-					case mergedChan <- entry:
-						// Successfully sent
-					case <-stopMerge:
-						return
-					default:
-						// Channel full, try with timeout
-						timer := time.NewTimer(service.WebSocketSlowConsumerTimeout)
-						select {
-						case mergedChan <- entry:
-							// Successfully sent after brief wait
-							timer.Stop()
-						case <-timer.C:
-							// Drop log entry if consumer is too slow
-							log.Printf("Warning: Dropped log entry due to slow consumer")
-						case <-stopMerge:
-							timer.Stop()
-							return
-						}
-					}
-				case <-stopMerge:
-					return
-				}
-			}
-		}(ch)
+		return
 	}
-
-	// Close merged channel when all goroutines finish
-	go func() {
-		wg.Wait()
-		close(mergedChan)
-	}()
+	defer logs.close()
 
 	// Stream logs to WebSocket with batching to improve throughput
 	done := make(chan struct{})
@@ -390,7 +340,7 @@ func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request) {
 
 		for {
 			select {
-			case entry, ok := <-mergedChan:
+			case entry, ok := <-logs.entries:
 				if !ok {
 					// Flush remaining batch before closing
 					if err := flush(); err != nil && !isExpectedCloseError(err) {
@@ -418,14 +368,16 @@ func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request) {
 				}
 			case <-s.stopChan:
 				return
+			case <-client.ctx.Done():
+				// Client disconnected
+				return
+			case <-healthErrors:
+				// Client stopped answering pings
+				return
 			}
 		}
 	}()
 
 	// Keep connection alive until client disconnects or server stops
-	// CRITICAL: Close stopMerge FIRST to signal goroutines, THEN wait for them
-	// Otherwise goroutines may be blocked and won't see the stop signal
 	<-done
-	close(stopMerge) // Signal all merger goroutines to stop
-	wg.Wait()        // Wait for all merger goroutines to finish cleanup
 }
@@ -6,13 +6,12 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"strings"
-	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/constants"
 	"github.com/jongio/azd-app/cli/src/internal/docker"
 	"github.com/jongio/azd-app/cli/src/internal/portmanager"
 	"github.com/jongio/azd-app/cli/src/internal/service"
+	"github.com/jongio/azd-app/cli/src/internal/serviceinfo"
 	"github.com/jongio/azd-core/registry"
 	"github.com/jongio/azd-core/security"
 )
@@ -95,6 +94,10 @@ func (h *serviceOperationHandler) handleSingleOperation(w http.ResponseWriter, r
 		NotFound(w, fmt.Sprintf("Service '%s' not found", serviceName))
 		return
 	}
+	if service.IsAspireResource(entry) {
+		writeJSONError(w, http.StatusConflict, fmt.Sprintf("'%s' is a resource of the Aspire AppHost '%s' and runs as long as it does", serviceName, service.MemberOwnerOf(serviceName)), nil)
+		return
+	}
 
 	// Check if operation is already in progress via operation manager
 	opMgr := service.GetOperationManager()
@@ -115,7 +118,7 @@ func (h *serviceOperationHandler) handleSingleOperation(w http.ResponseWriter, r
 	opType := h.toServiceOperationType()
 
 	result := opMgr.ExecuteOperation(ctx, serviceName, opType, func(ctx context.Context) error {
-		return h.executeServiceOperation(w, entry, serviceName, reg)
+		return h.executeServiceOperation(ctx, w, entry, serviceName, reg)
 	})
 
 	if result.Error != nil {
@@ -124,14 +127,7 @@ func (h *serviceOperationHandler) handleSingleOperation(w http.ResponseWriter, r
 }
 
 // executeServiceOperation performs the actual service operation.
-func (h *serviceOperationHandler) executeServiceOperation(w http.ResponseWriter, entry *registry.ServiceRegistryEntry, serviceName string, reg *registry.ServiceRegistry) error {
-	// For restart, stop the service first and wait for process exit
-	if h.operation == opRestart && entry.Status != constants.StatusStopped && entry.Status != constants.StatusNotRunning {
-		if err := h.stopService(entry, serviceName); err != nil {
-			log.Printf("Warning: error during restart stop phase: %v", err)
-		}
-	}
-
+func (h *serviceOperationHandler) executeServiceOperation(ctx context.Context, w http.ResponseWriter, entry *registry.ServiceRegistryEntry, serviceName string, reg *registry.ServiceRegistry) error {
 	// For stop operation, just stop and return
 	if h.operation == opStop {
 		h.performStop(w, entry, serviceName, reg)
@@ -139,7 +135,7 @@ func (h *serviceOperationHandler) executeServiceOperation(w http.ResponseWriter,
 	}
 
 	// For start/restart, start the service
-	h.performStart(w, entry, serviceName, reg)
+	h.performStart(ctx, w, entry, serviceName, reg)
 	return nil
 }
 
@@ -151,6 +147,10 @@ func (h *serviceOperationHandler) handleBulkOperation(w http.ResponseWriter, r *
 	// Filter services based on operation type
 	var applicableServices []string
 	for _, entry := range allServices {
+		// Resources of an Aspire AppHost are started and stopped with it
+		if service.IsAspireResource(entry) {
+			continue
+		}
 		switch h.operation {
 		case opStart:
 			// Start only stopped/errored services
@@ -191,7 +191,7 @@ func (h *serviceOperationHandler) handleBulkOperation(w http.ResponseWriter, r *
 			if !exists {
 				return fmt.Errorf("service '%s' not found", svcName)
 			}
-			return h.executeBulkServiceOperation(entry, svcName, reg)
+			return h.executeBulkServiceOperation(ctx, entry, svcName, reg)
 		}
 	}
 
@@ -230,21 +230,15 @@ func (h *serviceOperationHandler) handleBulkOperation(w http.ResponseWriter, r *
 
 // executeBulkServiceOperation performs the operation for a single service in bulk mode.
 // Unlike executeServiceOperation, this doesn't write to the response writer.
-func (h *serviceOperationHandler) executeBulkServiceOperation(entry *registry.ServiceRegistryEntry, serviceName string, reg *registry.ServiceRegistry) error {
-	// For restart, stop the service first
-	if h.operation == opRestart && entry.Status != constants.StatusStopped && entry.Status != constants.StatusNotRunning {
-		if err := h.stopService(entry, serviceName); err != nil {
-			log.Printf("Warning: error during restart stop phase for %s: %v", serviceName, err)
-		}
-	}
-
+func (h *serviceOperationHandler) executeBulkServiceOperation(ctx context.Context, entry *registry.ServiceRegistryEntry, serviceName string, reg *registry.ServiceRegistry) error {
 	// For stop operation
 	if h.operation == opStop {
 		return h.performStopBulk(entry, serviceName, reg)
 	}
 
 	// For start/restart
-	return h.performStartBulk(entry, serviceName, reg)
+	_, err := h.startService(ctx, entry, serviceName, reg)
+	return err
 }
 
 // performStopBulk handles the stop operation without writing to HTTP response.
@@ -270,103 +264,41 @@ func (h *serviceOperationHandler) performStopBulk(entry *registry.ServiceRegistr
 	return nil
 }
 
-// performStartBulk handles the start/restart operation without writing to HTTP response.
-func (h *serviceOperationHandler) performStartBulk(entry *registry.ServiceRegistryEntry, serviceName string, reg *registry.ServiceRegistry) error {
-	// Parse azure.yaml to get service configuration
-	azureYaml, err := service.ParseAzureYaml(h.server.projectDir)
-	if err != nil {
-		return fmt.Errorf("failed to parse azure.yaml: %w", err)
-	}
-
-	// Find the service definition
-	svcDef, exists := azureYaml.Services[serviceName]
-	if !exists {
-		return fmt.Errorf("service '%s' not found in azure.yaml", serviceName)
-	}
-
-	// Detect runtime for the service
-	runtime, err := service.DetectServiceRuntime(serviceName, svcDef, map[int]bool{}, h.server.projectDir, "")
-	if err != nil {
-		return fmt.Errorf("failed to detect service runtime: %w", err)
-	}
-
-	// Update registry to starting state
-	if updateErr := reg.UpdateStatus(serviceName, constants.StatusStarting); updateErr != nil {
-		log.Printf("Warning: failed to update status: %v", updateErr)
-	}
-
-	// Start the service - use container runner for container services
-	var process *service.ServiceProcess
-	if runtime.Type == service.ServiceTypeContainer {
-		process, err = service.StartContainerService(runtime, h.server.projectDir, true) // restartContainers=true for restart/start ops
-		if err == nil {
-			// Start container log collection
-			if logErr := service.StartContainerLogCollection(process, h.server.projectDir); logErr != nil {
-				log.Printf("Warning: failed to start container log collection for %s: %v", serviceName, logErr)
-			}
+// startService starts or restarts a service and returns its new registry entry.
+// A service of the `azd app run` session that owns the dashboard is restarted by the session,
+// so the session keeps tracking its process. Otherwise the dashboard starts the service the
+// way run does, with its assigned port and environment.
+func (h *serviceOperationHandler) startService(ctx context.Context, entry *registry.ServiceRegistryEntry, serviceName string, reg *registry.ServiceRegistry) (*registry.ServiceRegistryEntry, error) {
+	if restart := h.server.restartHandler(); restart != nil {
+		if err := restart(serviceName); err != nil {
+			return nil, err
 		}
 	} else {
-		// Load environment variables for native services
-		envVars := h.loadEnvironmentVariables(runtime)
-		functionsParser := service.NewFunctionsOutputParser(false)
-		process, err = service.StartService(runtime, envVars, h.server.projectDir, functionsParser)
-	}
-
-	if err != nil {
-		if regErr := reg.UpdateStatus(serviceName, constants.StatusError); regErr != nil {
-			log.Printf("Warning: failed to update status: %v", regErr)
+		// For restart, stop the service first and wait for process exit
+		if h.operation == opRestart {
+			h.stopForRestart(entry, serviceName, reg)
 		}
-		return fmt.Errorf("failed to start service: %w", err)
-	}
 
-	// Validate process was created successfully
-	// Container services have ContainerID instead of Process.Pid
-	if process == nil {
-		if regErr := reg.UpdateStatus(serviceName, constants.StatusError); regErr != nil {
-			log.Printf("Warning: failed to update status: %v", regErr)
+		// Update registry to starting state
+		if err := reg.UpdateStatus(serviceName, constants.StatusStarting); err != nil {
+			log.Printf("Warning: failed to update status: %v", err)
 		}
-		return fmt.Errorf("service process not created")
-	}
-	if runtime.Type == service.ServiceTypeContainer {
-		if process.ContainerID == "" {
-			if regErr := reg.UpdateStatus(serviceName, constants.StatusError); regErr != nil {
-				log.Printf("Warning: failed to update status: %v", regErr)
-			}
-			return fmt.Errorf("container not created")
-		}
-	} else {
-		if process.Process == nil {
+		h.broadcastStatusChange()
+
+		// Values of the azd environment selected by run, e.g. endpoints written by azd provision
+		if _, err := service.StartConfiguredService(ctx, h.server.projectDir, serviceName, serviceinfo.AzdEnvironment()); err != nil {
 			if regErr := reg.UpdateStatus(serviceName, constants.StatusError); regErr != nil {
 				log.Printf("Warning: failed to update status: %v", regErr)
 			}
-			return fmt.Errorf("native service process not created")
+			return nil, fmt.Errorf("failed to start service: %w", err)
 		}
 	}
 
-	// Create a fresh entry - preserve Type and Mode for container services
-	updatedEntry := &registry.ServiceRegistryEntry{
-		Name:        serviceName,
-		ProjectDir:  entry.ProjectDir,
-		Port:        runtime.Port,
-		URL:         entry.URL,
-		AzureURL:    entry.AzureURL,
-		Language:    runtime.Language,
-		Framework:   runtime.Framework,
-		Status:      constants.StatusRunning,
-		StartTime:   time.Now(),
-		LastChecked: time.Now(),
-		Type:        runtime.Type,
-		Mode:        runtime.Mode,
-	}
-	// Set PID only for native processes
-	if process.Process != nil {
-		updatedEntry.PID = process.Process.Pid
-	}
-	if err := reg.Register(updatedEntry); err != nil {
-		log.Printf("Warning: failed to register service: %v", err)
+	updated, exists := reg.GetService(serviceName)
+	if !exists {
+		return nil, fmt.Errorf("service '%s' was unregistered while starting", serviceName)
 	}
-
-	return nil
+	return updated, nil
 }
 
 // validateState checks if the operation is valid for the current service state.
@@ -386,6 +318,30 @@ func (h *serviceOperationHandler) validateState(entry *registry.ServiceRegistryE
 	return nil
 }
 
+// stopForRestart runs the stop phase of a restart. The service is marked as stopping so
+// the `azd app run` monitor doesn't report the exit as a crash, and its port is given time
+// to be freed so the restarted service keeps its port assignment.
+func (h *serviceOperationHandler) stopForRestart(entry *registry.ServiceRegistryEntry, serviceName string, reg *registry.ServiceRegistry) {
+	if entry.Status == constants.StatusStopped || entry.Status == constants.StatusNotRunning {
+		return
+	}
+
+	if err := reg.UpdateStatus(serviceName, constants.StatusStopping); err != nil {
+		log.Printf("Warning: failed to update status: %v", err)
+	}
+	h.broadcastStatusChange()
+	if err := h.stopService(entry, serviceName); err != nil {
+		log.Printf("Warning: error during restart stop phase for %s: %v", serviceName, err)
+	}
+
+	if entry.Port > 0 && entry.Type != service.ServiceTypeContainer && entry.Type != service.ServiceTypeCompose {
+		pm := portmanager.GetPortManager(h.server.projectDir)
+		if !pm.WaitForPortRelease(entry.Port, portmanager.PortReleaseTimeout) {
+			log.Printf("Warning: port %d of service %s is still in use after stopping it", entry.Port, serviceName)
+		}
+	}
+}
+
 // stopService stops a running service by PID and ensures the port is freed.
 // Returns nil if service was stopped successfully or if there was no process to stop.
 // This function handles the case where the registry PID is stale but a different
@@ -397,6 +353,14 @@ func (h *serviceOperationHandler) stopService(entry *registry.ServiceRegistryEnt
 		return h.stopContainerByName(serviceName)
 	}
 
+	// Compose services: take down the compose project the entry belongs to
+	if entry.Type == service.ServiceTypeCompose {
+		if err := service.StopComposeProject(service.MemberOwnerOf(serviceName), service.DefaultStopTimeout); err != nil {
+			log.Printf("Warning: failed to stop compose service %s: %v", serviceName, err)
+		}
+		return nil
+	}
+
 	// Native processes: stop by PID and ensure port is freed
 	if entry.PID > 0 {
 		process, err := os.FindProcess(entry.PID)
@@ -454,6 +418,7 @@ func (h *serviceOperationHandler) performStop(w http.ResponseWriter, entry *regi
 	if err := reg.UpdateStatus(serviceName, constants.StatusStopping); err != nil {
 		log.Printf("Warning: failed to update status: %v", err)
 	}
+	h.broadcastStatusChange()
 
 	if err := h.stopService(entry, serviceName); err != nil {
 		log.Printf("Warning: %v", err)
@@ -474,140 +439,30 @@ func (h *serviceOperationHandler) performStop(w http.ResponseWriter, entry *regi
 }
 
 // performStart handles the start/restart operation.
-func (h *serviceOperationHandler) performStart(w http.ResponseWriter, entry *registry.ServiceRegistryEntry, serviceName string, reg *registry.ServiceRegistry) {
-	// Parse azure.yaml to get service configuration
-	azureYaml, err := service.ParseAzureYaml(h.server.projectDir)
-	if err != nil {
-		InternalError(w, "Failed to parse azure.yaml", err)
-		return
-	}
-
-	// Find the service definition
-	svcDef, exists := azureYaml.Services[serviceName]
-	if !exists {
-		NotFound(w, fmt.Sprintf("Service '%s' not found in azure.yaml", serviceName))
-		return
-	}
-
-	// Detect runtime for the service
-	runtime, err := service.DetectServiceRuntime(serviceName, svcDef, map[int]bool{}, h.server.projectDir, "")
-	if err != nil {
-		InternalError(w, "Failed to detect service runtime", err)
-		return
-	}
-
-	// Update registry to starting state
-	if statusErr := reg.UpdateStatus(serviceName, constants.StatusStarting); statusErr != nil {
-		log.Printf("Warning: failed to update status: %v", statusErr)
-	}
-
-	// Start the service - use container runner for container services
-	var process *service.ServiceProcess
-	if runtime.Type == service.ServiceTypeContainer {
-		process, err = service.StartContainerService(runtime, h.server.projectDir, true) // restartContainers=true for restart/start ops
-		if err == nil {
-			// Start container log collection
-			if logErr := service.StartContainerLogCollection(process, h.server.projectDir); logErr != nil {
-				log.Printf("Warning: failed to start container log collection for %s: %v", serviceName, logErr)
-			}
-		}
-	} else {
-		// Load environment variables for native services
-		envVars := h.loadEnvironmentVariables(runtime)
-		functionsParser := service.NewFunctionsOutputParser(false)
-		process, err = service.StartService(runtime, envVars, h.server.projectDir, functionsParser)
-	}
-
+func (h *serviceOperationHandler) performStart(ctx context.Context, w http.ResponseWriter, entry *registry.ServiceRegistryEntry, serviceName string, reg *registry.ServiceRegistry) {
+	updatedEntry, err := h.startService(ctx, entry, serviceName, reg)
 	if err != nil {
-		if regErr := reg.UpdateStatus(serviceName, constants.StatusError); regErr != nil {
-			log.Printf("Warning: failed to update status: %v", regErr)
-		}
 		// Broadcast error state to WebSocket clients
-		if broadcastErr := h.server.BroadcastServiceUpdate(h.server.projectDir); broadcastErr != nil {
-			log.Printf("Warning: failed to broadcast error update: %v", broadcastErr)
-		}
+		h.broadcastStatusChange()
 		InternalError(w, fmt.Sprintf("Failed to %s service", h.getOperationVerb()), err)
 		return
 	}
 
-	// Validate process was created successfully
-	// Container services have ContainerID instead of Process.Pid
-	if process == nil {
-		if regErr := reg.UpdateStatus(serviceName, constants.StatusError); regErr != nil {
-			log.Printf("Warning: failed to update status: %v", regErr)
-		}
-		InternalError(w, "Service process not created", nil)
-		return
-	}
-	if runtime.Type == service.ServiceTypeContainer {
-		if process.ContainerID == "" {
-			if regErr := reg.UpdateStatus(serviceName, constants.StatusError); regErr != nil {
-				log.Printf("Warning: failed to update status: %v", regErr)
-			}
-			InternalError(w, "Container not created", nil)
-			return
-		}
-	} else {
-		if process.Process == nil {
-			if regErr := reg.UpdateStatus(serviceName, constants.StatusError); regErr != nil {
-				log.Printf("Warning: failed to update status: %v", regErr)
-			}
-			InternalError(w, "Native service process not created", nil)
-			return
-		}
-	}
-
-	// Create a fresh entry to avoid race conditions with the copy from GetService
-	// Preserve Type and Mode for container services
-	updatedEntry := &registry.ServiceRegistryEntry{
-		Name:        serviceName,
-		ProjectDir:  entry.ProjectDir,
-		Port:        runtime.Port,
-		URL:         entry.URL,
-		AzureURL:    entry.AzureURL,
-		Language:    runtime.Language,
-		Framework:   runtime.Framework,
-		Status:      constants.StatusRunning,
-		StartTime:   time.Now(),
-		LastChecked: time.Now(),
-		Type:        runtime.Type,
-		Mode:        runtime.Mode,
-	}
-	// Set PID only for native processes
-	if process.Process != nil {
-		updatedEntry.PID = process.Process.Pid
-	}
-	if err := reg.Register(updatedEntry); err != nil {
-		log.Printf("Warning: failed to register service: %v", err)
-	}
-
 	h.broadcastAndRespond(w, serviceName, h.getOperationPastTense(), updatedEntry)
 }
 
-// loadEnvironmentVariables loads env vars from OS and merges runtime-specific ones.
-func (h *serviceOperationHandler) loadEnvironmentVariables(runtime *service.ServiceRuntime) map[string]string {
-	envVars := make(map[string]string)
-	for _, e := range os.Environ() {
-		pair := strings.SplitN(e, "=", 2)
-		if len(pair) == 2 {
-			envVars[pair[0]] = pair[1]
-		}
-	}
-
-	// Merge runtime-specific env
-	for k, v := range runtime.Env {
-		envVars[k] = v
+// broadcastStatusChange sends the current service states to WebSocket clients, so the
+// dashboard shows intermediate states such as stopping and starting while an operation runs.
+func (h *serviceOperationHandler) broadcastStatusChange() {
+	if err := h.server.BroadcastServiceUpdate(h.server.projectDir); err != nil {
+		log.Printf("Warning: failed to broadcast update: %v", err)
 	}
-
-	return envVars
 }
 
 // broadcastAndRespond broadcasts update to WebSocket clients and sends HTTP response.
 func (h *serviceOperationHandler) broadcastAndRespond(w http.ResponseWriter, serviceName, action string, entry *registry.ServiceRegistryEntry) {
 	// Broadcast update to WebSocket clients
-	if err := h.server.BroadcastServiceUpdate(h.server.projectDir); err != nil {
-		log.Printf("Warning: failed to broadcast update: %v", err)
-	}
+	h.broadcastStatusChange()
 
 	response := map[string]interface{}{
 		"success": true,
@@ -225,6 +225,12 @@ func acceptWebSocket(w http.ResponseWriter, r *http.Request, rateLimiter *connec
 		return nil, http.ErrAbortHandler
 	}
 
+	// The rate limiter is released when the server is stopped or closed
+	if rateLimiter == nil {
+		http.Error(w, "Dashboard is shutting down", http.StatusServiceUnavailable)
+		return nil, http.ErrAbortHandler
+	}
+
 	// Check rate limit
 	clientIP := getClientIP(r)
 	if !rateLimiter.checkAndIncrement(clientIP) {
@@ -267,11 +273,17 @@ func (c *wsClient) writeJSON(data interface{}) error {
 	c.writeMu.Lock()
 	defer c.writeMu.Unlock()
 
+	return c.writeLocked(jsonBytes)
+}
+
+// writeLocked writes a text message; the caller must hold writeMu.
+// Holding writeMu across several writes keeps concurrent broadcasts from interleaving with them.
+func (c *wsClient) writeLocked(data []byte) error {
 	// Use reasonable timeout for local WebSocket connections
 	ctx, cancel := context.WithTimeout(c.ctx, service.DefaultWebSocketWriteTimeout)
 	defer cancel()
 
-	return c.conn.Write(ctx, websocket.MessageText, jsonBytes)
+	return c.conn.Write(ctx, websocket.MessageText, data)
 }
 
 // close closes the WebSocket connection and decrements the rate limiter.
@@ -300,11 +312,11 @@ func (c *wsClient) recordWriteFailure() int {
 }
 
 // readMessage reads a single message from the WebSocket connection.
+// It has no timeout of its own: dashboard clients rarely send messages, so an idle
+// connection is detected by the health monitor's pings rather than by the reader.
+// Reading also processes the pongs those pings wait for.
 func readMessage(client *wsClient) error {
-	ctx, cancel := context.WithTimeout(client.ctx, service.DefaultWebSocketPongWait)
-	defer cancel()
-
-	_, _, err := client.conn.Read(ctx)
+	_, _, err := client.conn.Read(client.ctx)
 	if err != nil {
 		// Check if it's a normal closure
 		if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
@@ -332,14 +344,15 @@ func newWSHealthMonitor(client *wsClient) *wsHealthMonitor {
 }
 
 // start begins the health monitoring with ping/pong messages.
+// Pings keep idle connections open behind proxies, and a client that doesn't answer a ping
+// within DefaultWebSocketPongWait is treated as gone. The connection must be read concurrently
+// (readMessage or CloseRead) for pongs to be received.
 // Returns an error channel that will receive any fatal errors.
 func (m *wsHealthMonitor) start() <-chan error {
 	errChan := make(chan error, 1)
 
-	// Start ping ticker - coder/websocket handles pings automatically, but we can configure read deadline
 	m.pingTicker = time.NewTicker(service.DefaultWebSocketPingPeriod)
 
-	// Set initial read deadline to detect dead connections
 	m.client.conn.SetReadLimit(10 * 1024 * 1024) // 10MB max message size
 
 	// Start ping loop in goroutine
@@ -351,11 +364,9 @@ func (m *wsHealthMonitor) start() <-chan error {
 			case <-m.stopChan:
 				return
 			case <-m.pingTicker.C:
-				// coder/websocket manages read deadlines internally via context
-				// The ping/pong mechanism combined with read timeouts detects dead connections
-
-				// Send ping to verify connection is alive
-				ctx, cancel := context.WithTimeout(m.client.ctx, service.DefaultWebSocketWriteTimeout)
+				// Ping blocks until the pong arrives, so its deadline is the read deadline
+				// for the client's reply
+				ctx, cancel := context.WithTimeout(m.client.ctx, service.DefaultWebSocketPongWait)
 				err := m.client.conn.Ping(ctx)
 				cancel()
 
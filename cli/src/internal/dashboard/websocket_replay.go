@@ -0,0 +1,102 @@
+package dashboard
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+// wsEventLog numbers the service-status events broadcast to /api/ws clients and keeps the
+// most recent ones, so a client that reconnects can be sent the events it missed.
+// The token identifies this server instance: a client holding another token (for example
+// after the dashboard restarted) can't resume and gets a full snapshot instead.
+type wsEventLog struct {
+	mu     sync.Mutex
+	token  string
+	seq    uint64
+	events []wsEvent // Oldest first, at most service.WebSocketReplayBuffer entries
+}
+
+// wsEvent is a recorded broadcast message and its sequence number.
+type wsEvent struct {
+	seq     uint64
+	message map[string]interface{}
+}
+
+// newWSEventLog creates an event log with a random reconnect token.
+func newWSEventLog() *wsEventLog {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand doesn't fail on supported platforms; an empty token only disables resuming
+		return &wsEventLog{}
+	}
+	return &wsEventLog{token: hex.EncodeToString(b)}
+}
+
+// record assigns the next sequence number to message, stores it as message["seq"] and keeps
+// the message for replay.
+func (l *wsEventLog) record(message map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seq++
+	message["seq"] = l.seq
+	l.events = append(l.events, wsEvent{seq: l.seq, message: message})
+	if len(l.events) > service.WebSocketReplayBuffer {
+		l.events = l.events[len(l.events)-service.WebSocketReplayBuffer:]
+	}
+}
+
+// current returns the reconnect token and the sequence number of the last event.
+func (l *wsEventLog) current() (string, uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.token, l.seq
+}
+
+// missedSince returns the events after seq, oldest first. ok is false when the client can't
+// resume from seq: the token is from another server instance, seq is ahead of this server,
+// or the events after seq are no longer buffered.
+// attach is called while the log is locked, so an event is either returned here or recorded
+// after attach ran; registering the client in attach means it misses nothing in between.
+func (l *wsEventLog) missedSince(token string, seq uint64, attach func()) (missed []map[string]interface{}, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	attach()
+
+	if l.token == "" || token != l.token || seq > l.seq {
+		return nil, false
+	}
+	if seq == l.seq {
+		return nil, true
+	}
+	if len(l.events) == 0 || l.events[0].seq > seq+1 {
+		return nil, false
+	}
+	for _, event := range l.events {
+		if event.seq > seq {
+			missed = append(missed, event.message)
+		}
+	}
+	return missed, true
+}
+
+// resumeParams returns the reconnect token and last seen sequence number sent by a
+// reconnecting client as ?token=...&since=N. resume is false for a new connection.
+func resumeParams(r *http.Request) (token string, since uint64, resume bool) {
+	query := r.URL.Query()
+	token = query.Get("token")
+	if token == "" {
+		return "", 0, false
+	}
+	since, err := strconv.ParseUint(query.Get("since"), 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return token, since, true
+}
@@ -0,0 +1,153 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+func TestWSEventLogMissedSince(t *testing.T) {
+	events := newWSEventLog()
+	if events.token == "" {
+		t.Fatal("newWSEventLog() has no token")
+	}
+	for i := 0; i < 3; i++ {
+		events.record(map[string]interface{}{"type": "services"})
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		since    uint64
+		wantOK   bool
+		wantSeqs []uint64
+	}{
+		{"missed events", events.token, 1, true, []uint64{2, 3}},
+		{"from the start", events.token, 0, true, []uint64{1, 2, 3}},
+		{"up to date", events.token, 3, true, nil},
+		{"other server", "stale", 1, false, nil},
+		{"ahead of the server", events.token, 4, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attached := false
+			missed, ok := events.missedSince(tt.token, tt.since, func() { attached = true })
+			if !attached {
+				t.Error("attach not called")
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if len(missed) != len(tt.wantSeqs) {
+				t.Fatalf("missed %d events, want %d", len(missed), len(tt.wantSeqs))
+			}
+			for i, message := range missed {
+				if message["seq"] != tt.wantSeqs[i] {
+					t.Errorf("missed[%d] seq = %v, want %d", i, message["seq"], tt.wantSeqs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWSEventLogBufferLimit(t *testing.T) {
+	events := newWSEventLog()
+	for i := 0; i < service.WebSocketReplayBuffer+10; i++ {
+		events.record(map[string]interface{}{"type": "services"})
+	}
+
+	if len(events.events) != service.WebSocketReplayBuffer {
+		t.Errorf("buffered %d events, want %d", len(events.events), service.WebSocketReplayBuffer)
+	}
+	// The events after seq 5 are gone, so the client has to resync
+	if _, ok := events.missedSince(events.token, 5, func() {}); ok {
+		t.Error("resumed from an event that is no longer buffered")
+	}
+	if missed, ok := events.missedSince(events.token, 10, func() {}); !ok || len(missed) != service.WebSocketReplayBuffer {
+		t.Errorf("missedSince(10) = %d events, %v", len(missed), ok)
+	}
+}
+
+func TestWebSocketReconnectReplay(t *testing.T) {
+	srv := GetServer(t.TempDir())
+	url, err := srv.Start()
+	if err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	defer func() { _ = srv.Stop() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	wsURL := strings.Replace(url, "http://", "ws://", 1) + "/api/ws"
+
+	// First connection: the snapshot carries the reconnect token and sequence number
+	ws, _, err := websocket.Dial(ctx, wsURL, nil)
+	if err != nil {
+		t.Fatalf("failed to connect WebSocket: %v", err)
+	}
+	var initial struct {
+		Type  string `json:"type"`
+		Token string `json:"token"`
+		Seq   uint64 `json:"seq"`
+	}
+	if err := wsjson.Read(ctx, ws, &initial); err != nil {
+		t.Fatalf("failed to read initial message: %v", err)
+	}
+	_ = ws.Close(websocket.StatusNormalClosure, "reconnecting")
+	if initial.Type != "services" || initial.Token == "" {
+		t.Fatalf("initial message = %+v, want services with a token", initial)
+	}
+
+	// Events broadcast while disconnected
+	_ = srv.broadcast(map[string]interface{}{"type": "services", "services": []string{}})
+	_ = srv.broadcast(map[string]interface{}{"type": "services", "services": []string{}})
+
+	t.Run("resume", func(t *testing.T) {
+		ws, _, err := websocket.Dial(ctx, fmt.Sprintf("%s?token=%s&since=%d", wsURL, initial.Token, initial.Seq), nil)
+		if err != nil {
+			t.Fatalf("failed to reconnect: %v", err)
+		}
+		defer func() { _ = ws.Close(websocket.StatusNormalClosure, "test complete") }()
+
+		for want := initial.Seq + 1; want <= initial.Seq+2; want++ {
+			var msg struct {
+				Type   string `json:"type"`
+				Seq    uint64 `json:"seq"`
+				Resync bool   `json:"resync"`
+			}
+			if err := wsjson.Read(ctx, ws, &msg); err != nil {
+				t.Fatalf("failed to read replayed event: %v", err)
+			}
+			if msg.Type != "services" || msg.Seq != want || msg.Resync {
+				t.Errorf("replayed event = %+v, want services with seq %d", msg, want)
+			}
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		ws, _, err := websocket.Dial(ctx, wsURL+"?token=stale&since=1", nil)
+		if err != nil {
+			t.Fatalf("failed to reconnect: %v", err)
+		}
+		defer func() { _ = ws.Close(websocket.StatusNormalClosure, "test complete") }()
+
+		var msg struct {
+			Token  string `json:"token"`
+			Seq    uint64 `json:"seq"`
+			Resync bool   `json:"resync"`
+		}
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("failed to read snapshot: %v", err)
+		}
+		if !msg.Resync || msg.Token != initial.Token || msg.Seq != initial.Seq+2 {
+			t.Errorf("snapshot = %+v, want a resync at seq %d", msg, initial.Seq+2)
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// GlobalJSON is the .NET SDK pin of a global.json file.
+type GlobalJSON struct {
+	Path            string // Absolute path of the global.json file
+	Version         string // Pinned SDK version, e.g. "8.0.100" ("" = any)
+	RollForward     string // Roll-forward policy, e.g. "patch" or "latestFeature"
+	AllowPrerelease bool   // Whether preview SDKs may be selected
+}
+
+// Requirement describes the pin, e.g. "8.0.100 (rollForward: latestFeature)".
+func (g GlobalJSON) Requirement() string {
+	if g.Version == "" {
+		return "any SDK (rollForward: " + g.RollForward + ")"
+	}
+	return g.Version + " (rollForward: " + g.RollForward + ")"
+}
+
+// FindGlobalJSON returns the global.json that pins the .NET SDK of the project in dir: the
+// nearest one in dir or its parents, as found by the dotnet command.
+// Returns false when there is none, or the nearest one has no sdk section.
+func FindGlobalJSON(dir string) (GlobalJSON, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return GlobalJSON{}, false
+	}
+	for {
+		path := filepath.Join(dir, "global.json")
+		if _, err := os.Stat(path); err == nil {
+			return readGlobalJSON(path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return GlobalJSON{}, false
+		}
+		dir = parent
+	}
+}
+
+// readGlobalJSON reads the sdk section of a global.json file.
+func readGlobalJSON(path string) (GlobalJSON, bool) {
+	if err := security.ValidatePath(path); err != nil {
+		return GlobalJSON{}, false
+	}
+	// #nosec G304 -- Path validated by security.ValidatePath
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return GlobalJSON{}, false
+	}
+
+	var file struct {
+		SDK *struct {
+			Version         string `json:"version"`
+			RollForward     string `json:"rollForward"`
+			AllowPrerelease *bool  `json:"allowPrerelease"`
+		} `json:"sdk"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil || file.SDK == nil {
+		return GlobalJSON{}, false
+	}
+
+	pin := GlobalJSON{
+		Path:            path,
+		Version:         strings.TrimSpace(file.SDK.Version),
+		RollForward:     file.SDK.RollForward,
+		AllowPrerelease: file.SDK.AllowPrerelease == nil || *file.SDK.AllowPrerelease,
+	}
+	// Without a version any SDK will do; with one, dotnet only takes newer patches by default
+	switch {
+	case pin.RollForward != "":
+	case pin.Version == "":
+		pin.RollForward = "latestMajor"
+	default:
+		pin.RollForward = "patch"
+	}
+	return pin, true
+}
+
+// sdkVersion is a parsed .NET SDK version: major.minor.FPP, where F is the feature band and
+// PP the patch, e.g. 8.0.303 is feature band 3, patch 3.
+type sdkVersion struct {
+	major, minor, band, patch int
+	pre                       string
+}
+
+// parseSDKVersion parses an SDK version such as "8.0.100" or "9.0.100-rc.2.24474.11".
+func parseSDKVersion(s string) (sdkVersion, error) {
+	version, pre, _ := strings.Cut(s, "-")
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return sdkVersion{}, fmt.Errorf("invalid SDK version %q", s)
+	}
+	nums := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return sdkVersion{}, fmt.Errorf("invalid SDK version %q", s)
+		}
+		nums[i] = n
+	}
+	return sdkVersion{major: nums[0], minor: nums[1], band: nums[2] / 100, patch: nums[2] % 100, pre: pre}, nil
+}
+
+// compare orders SDK versions; a preview sorts before its release.
+func (v sdkVersion) compare(other sdkVersion) int {
+	for _, d := range []int{v.major - other.major, v.minor - other.minor, v.band - other.band, v.patch - other.patch} {
+		if d != 0 {
+			return d
+		}
+	}
+	switch {
+	case v.pre == other.pre:
+		return 0
+	case v.pre == "":
+		return 1
+	case other.pre == "":
+		return -1
+	default:
+		return strings.Compare(v.pre, other.pre)
+	}
+}
+
+// ResolveSDK returns the installed SDK version the dotnet command selects for the pin,
+// following its roll-forward policy. Returns false when none of the installed SDKs match.
+func (g GlobalJSON) ResolveSDK(installed []string) (string, bool) {
+	var candidates []sdkVersion
+	names := make(map[sdkVersion]string)
+	for _, s := range installed {
+		v, err := parseSDKVersion(s)
+		if err != nil || (v.pre != "" && !g.AllowPrerelease) {
+			continue
+		}
+		candidates = append(candidates, v)
+		names[v] = s
+	}
+
+	if g.Version == "" {
+		best, ok := newestSDK(candidates, func(sdkVersion) bool { return true })
+		return names[best], ok
+	}
+	requested, err := parseSDKVersion(g.Version)
+	if err != nil {
+		return "", false
+	}
+
+	// Only the requested version or newer ones are ever selected
+	atLeast := func(scope func(sdkVersion) bool) func(sdkVersion) bool {
+		return func(v sdkVersion) bool { return v.compare(requested) >= 0 && scope(v) }
+	}
+	sameBand := atLeast(func(v sdkVersion) bool {
+		return v.major == requested.major && v.minor == requested.minor && v.band == requested.band
+	})
+	sameMinor := atLeast(func(v sdkVersion) bool { return v.major == requested.major && v.minor == requested.minor })
+	sameMajor := atLeast(func(v sdkVersion) bool { return v.major == requested.major })
+	anyVersion := atLeast(func(sdkVersion) bool { return true })
+
+	var best sdkVersion
+	var ok bool
+	switch g.RollForward {
+	case "disable":
+		for _, v := range candidates {
+			if v.compare(requested) == 0 {
+				return names[v], true
+			}
+		}
+		return "", false
+	case "patch":
+		for _, v := range candidates {
+			if v.compare(requested) == 0 {
+				return names[v], true
+			}
+		}
+		best, ok = newestSDK(candidates, sameBand)
+	case "latestPatch":
+		best, ok = newestSDK(candidates, sameBand)
+	case "feature", "minor", "major":
+		// The newest patch of the requested feature band, else of the next higher band in scope
+		scope := map[string]func(sdkVersion) bool{"feature": sameMinor, "minor": sameMajor, "major": anyVersion}[g.RollForward]
+		if best, ok = newestSDK(candidates, sameBand); !ok {
+			if next, found := oldestSDK(candidates, scope); found {
+				best, ok = newestSDK(candidates, func(v sdkVersion) bool {
+					return v.major == next.major && v.minor == next.minor && v.band == next.band
+				})
+			}
+		}
+	case "latestFeature":
+		best, ok = newestSDK(candidates, sameMinor)
+	case "latestMinor":
+		best, ok = newestSDK(candidates, sameMajor)
+	case "latestMajor":
+		best, ok = newestSDK(candidates, anyVersion)
+	default:
+		return "", false
+	}
+	return names[best], ok
+}
+
+// newestSDK returns the newest of the versions that match.
+func newestSDK(versions []sdkVersion, match func(sdkVersion) bool) (sdkVersion, bool) {
+	var best sdkVersion
+	found := false
+	for _, v := range versions {
+		if match(v) && (!found || v.compare(best) > 0) {
+			best, found = v, true
+		}
+	}
+	return best, found
+}
+
+// oldestSDK returns the oldest of the versions that match.
+func oldestSDK(versions []sdkVersion, match func(sdkVersion) bool) (sdkVersion, bool) {
+	var best sdkVersion
+	found := false
+	for _, v := range versions {
+		if match(v) && (!found || v.compare(best) < 0) {
+			best, found = v, true
+		}
+	}
+	return best, found
+}
+
+// DotnetPathRoot returns the .NET installation directory of the dotnet command on PATH, or ""
+// when dotnet isn't on PATH.
+func DotnetPathRoot() string {
+	path, err := exec.LookPath("dotnet")
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return filepath.Dir(path)
+}
+
+// DotnetRoots returns the .NET installation directories, the one of the dotnet command on PATH
+// first, then DOTNET_ROOT and the default install locations.
+func DotnetRoots() []string {
+	candidates := []string{DotnetPathRoot(), os.Getenv("DOTNET_ROOT")}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".dotnet"))
+	}
+	switch runtime.GOOS {
+	case "windows":
+		candidates = append(candidates, filepath.Join(os.Getenv("ProgramFiles"), "dotnet"))
+	case "darwin":
+		candidates = append(candidates, "/usr/local/share/dotnet")
+	default:
+		candidates = append(candidates, "/usr/share/dotnet", "/usr/lib/dotnet", "/usr/local/share/dotnet")
+	}
+
+	var roots []string
+	seen := make(map[string]bool)
+	for _, root := range candidates {
+		if root == "" || seen[root] {
+			continue
+		}
+		seen[root] = true
+		if info, err := os.Stat(filepath.Join(root, "sdk")); err == nil && info.IsDir() {
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
+
+// InstalledDotnetSDKs returns the SDK versions installed in a .NET installation directory.
+func InstalledDotnetSDKs(root string) []string {
+	entries, err := os.ReadDir(filepath.Join(root, "sdk"))
+	if err != nil {
+		return nil
+	}
+	var versions []string
+	for _, entry := range entries {
+		if _, err := parseSDKVersion(entry.Name()); entry.IsDir() && err == nil {
+			versions = append(versions, entry.Name())
+		}
+	}
+	return versions
+}
+
+// FindDotnetSDK returns the first .NET installation (see DotnetRoots) with an SDK that matches
+// the pin, and that SDK's version. Returns false when no installation has one.
+func FindDotnetSDK(pin GlobalJSON) (root, version string, ok bool) {
+	for _, root := range DotnetRoots() {
+		if version, ok := pin.ResolveSDK(InstalledDotnetSDKs(root)); ok {
+			return root, version, true
+		}
+	}
+	return "", "", false
+}
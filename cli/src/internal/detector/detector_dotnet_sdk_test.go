@@ -0,0 +1,85 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveSDK(t *testing.T) {
+	installed := []string{"6.0.428", "8.0.100", "8.0.104", "8.0.204", "8.0.303", "9.0.100", "10.0.100-rc.2.25502.107"}
+
+	tests := []struct {
+		name        string
+		version     string
+		rollForward string
+		noPreview   bool
+		want        string // "" = no match
+	}{
+		{name: "patch exact", version: "8.0.100", rollForward: "patch", want: "8.0.100"},
+		{name: "patch newer patch", version: "8.0.102", rollForward: "patch", want: "8.0.104"},
+		{name: "patch other band", version: "8.0.400", rollForward: "patch"},
+		{name: "latestPatch", version: "8.0.100", rollForward: "latestPatch", want: "8.0.104"},
+		{name: "disable exact only", version: "8.0.102", rollForward: "disable"},
+		{name: "feature next band", version: "8.0.105", rollForward: "feature", want: "8.0.204"},
+		{name: "feature no newer band", version: "8.0.400", rollForward: "feature"},
+		{name: "latestFeature", version: "8.0.100", rollForward: "latestFeature", want: "8.0.303"},
+		{name: "minor", version: "6.0.500", rollForward: "minor"},
+		{name: "major", version: "6.0.500", rollForward: "major", want: "8.0.104"},
+		{name: "latestMajor", version: "8.0.100", rollForward: "latestMajor", want: "10.0.100-rc.2.25502.107"},
+		{name: "latestMajor without previews", version: "8.0.100", rollForward: "latestMajor", noPreview: true, want: "9.0.100"},
+		{name: "no version", rollForward: "latestMajor", noPreview: true, want: "9.0.100"},
+		{name: "unknown policy", version: "8.0.100", rollForward: "sideways"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pin := GlobalJSON{Version: tt.version, RollForward: tt.rollForward, AllowPrerelease: !tt.noPreview}
+			got, ok := pin.ResolveSDK(installed)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("ResolveSDK() = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindGlobalJSON(t *testing.T) {
+	root := t.TempDir()
+	projectDir := filepath.Join(root, "src", "api")
+	if err := os.MkdirAll(projectDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "global.json"), []byte(`{"sdk": {"version": "8.0.100"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	pin, ok := FindGlobalJSON(projectDir)
+	if !ok {
+		t.Fatal("FindGlobalJSON() found no pin")
+	}
+	if pin.Path != filepath.Join(root, "global.json") || pin.Version != "8.0.100" || pin.RollForward != "patch" || !pin.AllowPrerelease {
+		t.Errorf("FindGlobalJSON() = %+v", pin)
+	}
+
+	// The nearest global.json wins, even without an sdk section
+	if err := os.WriteFile(filepath.Join(projectDir, "global.json"), []byte(`{"msbuild-sdks": {}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if pin, ok := FindGlobalJSON(projectDir); ok {
+		t.Errorf("FindGlobalJSON() = %+v, want no pin", pin)
+	}
+}
+
+func TestInstalledDotnetSDKs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"8.0.100", "9.0.100-rc.1.24452.12", "NuGetFallbackFolder"} {
+		if err := os.MkdirAll(filepath.Join(root, "sdk", dir), 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := InstalledDotnetSDKs(root)
+	if len(got) != 2 || got[0] != "8.0.100" || got[1] != "9.0.100-rc.1.24452.12" {
+		t.Errorf("InstalledDotnetSDKs() = %v", got)
+	}
+}
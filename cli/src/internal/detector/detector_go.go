@@ -0,0 +1,128 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// GoProject represents a Go module.
+type GoProject struct {
+	Dir string // Directory containing go.mod
+}
+
+// FindGoProjects searches for go.mod files.
+// Only searches within rootDir and does not traverse outside it.
+// vendor and testdata directories are skipped because they never hold modules to download.
+func FindGoProjects(rootDir string) ([]GoProject, error) {
+	var goProjects []GoProject
+
+	// Clean the root directory path
+	rootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return goProjects, err
+	}
+
+	err = filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // file not found is expected, means this detector doesn't match
+		}
+
+		// Ensure we don't traverse outside rootDir
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil //nolint:nilerr // file not found is expected, means this detector doesn't match
+		}
+		relPath, err := filepath.Rel(rootDir, absPath)
+		if err != nil || strings.HasPrefix(relPath, "..") {
+			return filepath.SkipDir
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if name == skipDirNodeModules || name == skipDirGit || name == "vendor" || name == "testdata" {
+				return filepath.SkipDir
+			}
+		}
+
+		if !info.IsDir() && info.Name() == "go.mod" {
+			goProjects = append(goProjects, GoProject{Dir: filepath.Dir(path)})
+		}
+
+		return nil
+	})
+
+	return goProjects, err
+}
+
+// HasGoMod checks if go.mod exists in a directory.
+func HasGoMod(dir string) bool {
+	return fileExistsInDir(dir, "go.mod")
+}
+
+// FindGoMainPackages returns the directories under dir/cmd that contain a main package,
+// relative to dir with forward slashes (e.g., "cmd/api"). Results are sorted by name.
+func FindGoMainPackages(dir string) []string {
+	entries, err := os.ReadDir(filepath.Join(dir, "cmd"))
+	if err != nil {
+		return nil
+	}
+
+	var mains []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if IsGoMainPackage(filepath.Join(dir, "cmd", entry.Name())) {
+			mains = append(mains, "cmd/"+entry.Name())
+		}
+	}
+	return mains
+}
+
+// IsGoMainPackage reports whether a directory has a non-test .go file declaring package main.
+func IsGoMainPackage(dir string) bool {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return false
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		if containsTextInFile(file, "package main") {
+			return true
+		}
+	}
+	return false
+}
+
+// GoVersionFromGoMod returns the go directive in dir/go.mod as a full version
+// (e.g., "go 1.22" -> "1.22.0"), or an empty string if go.mod has none.
+func GoVersionFromGoMod(dir string) string {
+	goModPath := filepath.Join(dir, "go.mod")
+	if err := security.ValidatePath(goModPath); err != nil {
+		return ""
+	}
+
+	// #nosec G304 -- Path validated by security.ValidatePath
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "go" {
+			continue
+		}
+		version := fields[1]
+		if strings.Count(version, ".") == 1 {
+			version += ".0"
+		}
+		return version
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindGoProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFiles(t, tmpDir, map[string]string{
+		"api/go.mod":                 "module example.com/api\n\ngo 1.22",
+		"tools/linter/go.mod":        "module example.com/linter\n\ngo 1.22",
+		"api/vendor/x/go.mod":        "module example.com/x",
+		"api/testdata/sample/go.mod": "module example.com/sample",
+		"node_modules/pkg/go.mod":    "module example.com/pkg",
+		"web/package.json":           "{}",
+	})
+
+	projects, err := FindGoProjects(tmpDir)
+	if err != nil {
+		t.Fatalf("FindGoProjects() error = %v", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("FindGoProjects() found %d projects, want 2: %+v", len(projects), projects)
+	}
+	for _, project := range projects {
+		if strings.Contains(project.Dir, "vendor") || strings.Contains(project.Dir, "testdata") || strings.Contains(project.Dir, "node_modules") {
+			t.Errorf("FindGoProjects() should skip %s", project.Dir)
+		}
+	}
+}
+
+func TestFindGoMainPackages(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFiles(t, tmpDir, map[string]string{
+		"go.mod":                  "module example.com/app\n\ngo 1.22",
+		"cmd/worker/main.go":      "package main\n\nfunc main() {}",
+		"cmd/api/main.go":         "package main\n\nfunc main() {}",
+		"cmd/shared/shared.go":    "package shared",
+		"cmd/testonly/x_test.go":  "package main",
+		"cmd/README.md":           "commands",
+		"internal/server/main.go": "package main",
+	})
+
+	got := FindGoMainPackages(tmpDir)
+	want := []string{"cmd/api", "cmd/worker"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("FindGoMainPackages() = %v, want %v", got, want)
+	}
+
+	if got := FindGoMainPackages(t.TempDir()); len(got) != 0 {
+		t.Errorf("FindGoMainPackages() without cmd/ = %v, want none", got)
+	}
+}
+
+func TestGoVersionFromGoMod(t *testing.T) {
+	tests := []struct {
+		name     string
+		goMod    string
+		expected string
+	}{
+		{name: "major.minor", goMod: "module example.com/app\n\ngo 1.22\n", expected: "1.22.0"},
+		{name: "full version", goMod: "module example.com/app\n\ngo 1.23.4\n\ntoolchain go1.23.5\n", expected: "1.23.4"},
+		{name: "no go directive", goMod: "module example.com/app\n", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeTestFiles(t, tmpDir, map[string]string{"go.mod": tt.goMod})
+			if got := GoVersionFromGoMod(tmpDir); got != tt.expected {
+				t.Errorf("GoVersionFromGoMod() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if got := GoVersionFromGoMod(t.TempDir()); got != "" {
+		t.Errorf("GoVersionFromGoMod() without go.mod = %q, want empty", got)
+	}
+}
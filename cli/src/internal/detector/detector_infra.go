@@ -0,0 +1,118 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// infraDir is the directory azd keeps infrastructure as code in by default.
+const infraDir = "infra"
+
+// kubernetesManifestDirs are the directories Kubernetes manifests are conventionally kept in.
+var kubernetesManifestDirs = []string{"manifests", "k8s", "kubernetes", "deploy/k8s"}
+
+// terraformRequiredVersionRegex matches required_version in a terraform block.
+var terraformRequiredVersionRegex = regexp.MustCompile(`(?m)^\s*required_version\s*=\s*"([^"]*)"`)
+
+// versionNumberRegex matches a version of one to three numbers, e.g. 1, 1.5 or 1.5.7.
+var versionNumberRegex = regexp.MustCompile(`\d+(\.\d+){0,2}`)
+
+// TerraformDir returns the directory holding the project's Terraform configuration (*.tf files):
+// the infra directory azd uses by default, or dir itself. Returns an empty string if there is none.
+func TerraformDir(dir string) string {
+	for _, candidate := range []string{filepath.Join(dir, infraDir), dir} {
+		if len(globInDir(candidate, "*.tf")) > 0 {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// TerraformRequiredVersion returns the lowest Terraform version allowed by required_version in the
+// .tf files in dir as a full version (e.g., ">= 1.5" -> "1.5.0"), or an empty string if none is set.
+func TerraformRequiredVersion(dir string) string {
+	for _, path := range globInDir(dir, "*.tf") {
+		// #nosec G304 -- Path validated by security.ValidatePath in globInDir
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if matches := terraformRequiredVersionRegex.FindSubmatch(data); matches != nil {
+			return minimumVersion(string(matches[1]))
+		}
+	}
+	return ""
+}
+
+// minimumVersion returns the lower bound of a version constraint such as ">= 1.5, < 2.0" or "~> 1.5.0",
+// padded to a full version. Returns an empty string if the constraint has no lower bound.
+func minimumVersion(constraint string) string {
+	for _, part := range strings.Split(constraint, ",") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "<") || strings.HasPrefix(part, "!=") {
+			continue
+		}
+		version := versionNumberRegex.FindString(part)
+		if version == "" {
+			continue
+		}
+		for strings.Count(version, ".") < 2 {
+			version += ".0"
+		}
+		return version
+	}
+	return ""
+}
+
+// HasBicepFiles reports whether dir or its infra directory contains Bicep files.
+func HasBicepFiles(dir string) bool {
+	return len(globInDir(filepath.Join(dir, infraDir), "*.bicep")) > 0 || len(globInDir(dir, "*.bicep")) > 0
+}
+
+// HasKubernetesManifests reports whether dir has a kustomization or Kubernetes manifests
+// in one of the conventional manifest directories (manifests, k8s, kubernetes, deploy/k8s).
+func HasKubernetesManifests(dir string) bool {
+	if fileExistsInDir(dir, "kustomization.yaml") || fileExistsInDir(dir, "kustomization.yml") {
+		return true
+	}
+	for _, manifestDir := range kubernetesManifestDirs {
+		path := filepath.Join(dir, manifestDir)
+		if len(globInDir(path, "*.yaml")) > 0 || len(globInDir(path, "*.yml")) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasHelmChart reports whether dir contains a Helm chart (Chart.yaml) at its root or up to
+// two directories below it, e.g. charts/api/Chart.yaml.
+func HasHelmChart(dir string) bool {
+	for _, pattern := range []string{"Chart.yaml", "*/Chart.yaml", "*/*/Chart.yaml"} {
+		if len(globInDir(dir, pattern)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// globInDir returns the files in dir matching pattern whose paths pass security validation.
+func globInDir(dir, pattern string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return nil
+	}
+	files := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if err := security.ValidatePath(match); err != nil {
+			continue
+		}
+		if info, err := os.Stat(match); err == nil && !info.IsDir() {
+			files = append(files, match)
+		}
+	}
+	return files
+}
@@ -0,0 +1,105 @@
+package detector
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTerraformDir(t *testing.T) {
+	dir := t.TempDir()
+	if got := TerraformDir(dir); got != "" {
+		t.Errorf("TerraformDir() without .tf files = %q, want empty", got)
+	}
+
+	writeTestFiles(t, dir, map[string]string{"main.tf": ""})
+	if got := TerraformDir(dir); got != dir {
+		t.Errorf("TerraformDir() = %q, want %q", got, dir)
+	}
+
+	// azd's infra directory takes precedence
+	writeTestFiles(t, dir, map[string]string{"infra/main.tf": ""})
+	if got, want := TerraformDir(dir), filepath.Join(dir, "infra"); got != want {
+		t.Errorf("TerraformDir() = %q, want %q", got, want)
+	}
+}
+
+func TestTerraformRequiredVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "lower bound", content: `required_version = ">= 1.5.7"`, want: "1.5.7"},
+		{name: "pessimistic", content: `required_version = "~> 1.6"`, want: "1.6.0"},
+		{name: "range", content: `required_version = ">= 1.3, < 2.0.0"`, want: "1.3.0"},
+		{name: "upper bound only", content: `required_version = "< 2.0.0"`, want: ""},
+		{name: "exact", content: `required_version = "1.8.0"`, want: "1.8.0"},
+		{name: "not set", content: `provider "azurerm" {}`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFiles(t, dir, map[string]string{"versions.tf": "terraform {\n  " + tt.content + "\n}\n"})
+			if got := TerraformRequiredVersion(dir); got != tt.want {
+				t.Errorf("TerraformRequiredVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasBicepFiles(t *testing.T) {
+	dir := t.TempDir()
+	if HasBicepFiles(dir) {
+		t.Error("HasBicepFiles() = true for an empty directory")
+	}
+	writeTestFiles(t, dir, map[string]string{"infra/main.bicep": ""})
+	if !HasBicepFiles(dir) {
+		t.Error("HasBicepFiles() = false with infra/main.bicep")
+	}
+}
+
+func TestHasKubernetesManifests(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want bool
+	}{
+		{name: "manifests dir", file: "manifests/deployment.yaml", want: true},
+		{name: "k8s dir", file: "k8s/service.yml", want: true},
+		{name: "kustomization", file: "kustomization.yaml", want: true},
+		{name: "unrelated yaml", file: "config/app.yaml", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFiles(t, dir, map[string]string{tt.file: ""})
+			if got := HasKubernetesManifests(dir); got != tt.want {
+				t.Errorf("HasKubernetesManifests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasHelmChart(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want bool
+	}{
+		{name: "root chart", file: "Chart.yaml", want: true},
+		{name: "charts dir", file: "charts/api/Chart.yaml", want: true},
+		{name: "too deep", file: "deploy/helm/charts/api/Chart.yaml", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFiles(t, dir, map[string]string{tt.file: ""})
+			if got := HasHelmChart(dir); got != tt.want {
+				t.Errorf("HasHelmChart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
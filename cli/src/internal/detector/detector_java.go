@@ -0,0 +1,122 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Java build tools, matching the package manager names the service detector uses.
+const (
+	BuildToolMaven  = "maven"
+	BuildToolGradle = "gradle"
+)
+
+// gradleBuildFiles mark a Gradle build; settings files alone mark a multi-project root.
+var gradleBuildFiles = []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"}
+
+// JavaProject represents a Maven or Gradle build.
+type JavaProject struct {
+	Dir       string // Directory containing pom.xml or build.gradle
+	BuildTool string // "maven" or "gradle"
+}
+
+// FindJavaProjects searches for Maven and Gradle builds.
+// Only searches within rootDir and does not traverse outside it.
+// Modules below a build aren't returned separately: resolving from the root build
+// covers a multi-module Maven reactor or multi-project Gradle build.
+func FindJavaProjects(rootDir string) ([]JavaProject, error) {
+	var javaProjects []JavaProject
+
+	// Clean the root directory path
+	rootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return javaProjects, err
+	}
+
+	err = filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // file not found is expected, means this detector doesn't match
+		}
+
+		// Ensure we don't traverse outside rootDir
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil //nolint:nilerr // file not found is expected, means this detector doesn't match
+		}
+		relPath, err := filepath.Rel(rootDir, absPath)
+		if err != nil || strings.HasPrefix(relPath, "..") {
+			return filepath.SkipDir
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		name := info.Name()
+		if name == skipDirNodeModules || name == skipDirGit || name == skipDirBin || name == skipDirObj ||
+			name == skipDirTarget || name == "build" || name == ".gradle" || name == ".mvn" {
+			return filepath.SkipDir
+		}
+
+		if buildTool := DetectJavaBuildTool(path); buildTool != "" {
+			javaProjects = append(javaProjects, JavaProject{Dir: path, BuildTool: buildTool})
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	return javaProjects, err
+}
+
+// DetectJavaBuildTool returns "gradle" or "maven" for a Java build in dir, or an empty string
+// if there is none. Gradle wins when both are present, as in the service detector.
+func DetectJavaBuildTool(dir string) string {
+	for _, file := range gradleBuildFiles {
+		if fileExistsInDir(dir, file) {
+			return BuildToolGradle
+		}
+	}
+	if fileExistsInDir(dir, "pom.xml") {
+		return BuildToolMaven
+	}
+	return ""
+}
+
+// JavaBuildCommand returns the command and leading args that run a build tool in dir: the project's
+// wrapper script (mvnw or gradlew) when it has one, so the pinned tool version is used, otherwise
+// mvn or gradle. Wrappers committed from Windows often lose their executable bit, so those run through sh.
+func JavaBuildCommand(dir, buildTool string) (string, []string) {
+	if wrapper := JavaWrapper(dir, buildTool); wrapper != "" {
+		if info, err := os.Stat(wrapper); err == nil && runtime.GOOS != "windows" && info.Mode()&0o111 == 0 {
+			return "sh", []string{wrapper}
+		}
+		return wrapper, nil
+	}
+	if buildTool == BuildToolGradle {
+		return "gradle", nil
+	}
+	return "mvn", nil
+}
+
+// JavaWrapper returns the path to the mvnw or gradlew wrapper script in dir,
+// or an empty string if the project doesn't have one.
+func JavaWrapper(dir, buildTool string) string {
+	wrapper := "mvnw"
+	if buildTool == BuildToolGradle {
+		wrapper = "gradlew"
+	}
+	if runtime.GOOS == "windows" {
+		if buildTool == BuildToolGradle {
+			wrapper += ".bat"
+		} else {
+			wrapper += ".cmd"
+		}
+	}
+	if !fileExistsInDir(dir, wrapper) {
+		return ""
+	}
+	return filepath.Join(dir, wrapper)
+}
@@ -0,0 +1,76 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFindJavaProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFiles(t, tmpDir, map[string]string{
+		"orders/pom.xml":                  "<project/>",
+		"orders/core/pom.xml":             "<project/>",
+		"orders/target/classes/pom.xml":   "<project/>",
+		"billing/settings.gradle.kts":     "include(\"app\")",
+		"billing/app/build.gradle.kts":    "plugins { application }",
+		"billing/build/tmp/build.gradle":  "",
+		"node_modules/java-lib/pom.xml":   "<project/>",
+		"web/package.json":                "{}",
+		"shipping/build.gradle":           "plugins { java }",
+		"shipping/pom.xml":                "<project/>",
+		"shipping/.gradle/cache/pom.xml":  "<project/>",
+		"notifications/src/main/App.java": "class App {}",
+	})
+
+	projects, err := FindJavaProjects(tmpDir)
+	if err != nil {
+		t.Fatalf("FindJavaProjects() error = %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, project := range projects {
+		rel, _ := filepath.Rel(tmpDir, project.Dir)
+		got[filepath.ToSlash(rel)] = project.BuildTool
+	}
+	want := map[string]string{"orders": BuildToolMaven, "billing": BuildToolGradle, "shipping": BuildToolGradle}
+	if len(got) != len(want) {
+		t.Fatalf("FindJavaProjects() = %v, want %v", got, want)
+	}
+	for dir, buildTool := range want {
+		if got[dir] != buildTool {
+			t.Errorf("FindJavaProjects()[%s] = %q, want %q", dir, got[dir], buildTool)
+		}
+	}
+}
+
+func TestJavaBuildCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("wrapper scripts are mvnw.cmd and gradlew.bat on Windows")
+	}
+
+	dir := t.TempDir()
+	if command, args := JavaBuildCommand(dir, BuildToolMaven); command != "mvn" || len(args) != 0 {
+		t.Errorf("JavaBuildCommand() without wrapper = %s %v, want mvn", command, args)
+	}
+	if command, _ := JavaBuildCommand(dir, BuildToolGradle); command != "gradle" {
+		t.Errorf("JavaBuildCommand() without wrapper = %s, want gradle", command)
+	}
+
+	gradlew := filepath.Join(dir, "gradlew")
+	if err := os.WriteFile(gradlew, []byte("#!/bin/sh"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if command, args := JavaBuildCommand(dir, BuildToolGradle); command != gradlew || len(args) != 0 {
+		t.Errorf("JavaBuildCommand() with wrapper = %s %v, want %s", command, args, gradlew)
+	}
+
+	mvnw := filepath.Join(dir, "mvnw")
+	if err := os.WriteFile(mvnw, []byte("#!/bin/sh"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if command, args := JavaBuildCommand(dir, BuildToolMaven); command != "sh" || len(args) != 1 || args[0] != mvnw {
+		t.Errorf("JavaBuildCommand() with non-executable wrapper = %s %v, want sh %s", command, args, mvnw)
+	}
+}
@@ -11,11 +11,19 @@ import (
 	"github.com/jongio/azd-core/security"
 )
 
-const pkgNPM = "npm"
+const (
+	pkgNPM  = "npm"
+	pkgBun  = "bun"
+	pkgDeno = "deno"
+)
+
+// denoConfigFiles are the files that mark a Deno project.
+var denoConfigFiles = []string{"deno.json", "deno.jsonc"}
 
-// FindNodeProjects searches for package.json files.
+// FindNodeProjects searches for package.json files, and deno.json/deno.jsonc files for
+// Deno projects without a package.json.
 // Only searches within rootDir and does not traverse outside it.
-// Detects npm/yarn/pnpm workspace configurations and marks workspace relationships.
+// Detects npm/yarn/pnpm workspaces and turbo/nx monorepos, and marks workspace relationships.
 func FindNodeProjects(rootDir string) ([]types.NodeProject, error) {
 	var nodeProjects []types.NodeProject
 	seen := make(map[string]bool)
@@ -53,6 +61,16 @@ func FindNodeProjects(rootDir string) ([]types.NodeProject, error) {
 			}
 		}
 
+		// Deno projects don't need a package.json; one next to deno.json is picked up below
+		if !info.IsDir() && isDenoConfigFile(info.Name()) && !HasPackageJson(filepath.Dir(path)) {
+			dir := filepath.Dir(path)
+			if !seen[dir] {
+				nodeProjects = append(nodeProjects, types.NodeProject{Dir: dir, PackageManager: pkgDeno})
+				seen[dir] = true
+			}
+			return nil
+		}
+
 		if !info.IsDir() && info.Name() == "package.json" {
 			dir := filepath.Dir(path)
 
@@ -66,7 +84,7 @@ func FindNodeProjects(rootDir string) ([]types.NodeProject, error) {
 			}
 
 			packageManager := DetectNodePackageManagerWithBoundary(dir, rootDir)
-			isWorkspaceRoot := HasNpmWorkspaces(dir)
+			isWorkspaceRoot := IsNodeWorkspaceRoot(dir)
 
 			nodeProjects = append(nodeProjects, types.NodeProject{
 				Dir:             dir,
@@ -90,9 +108,8 @@ func FindNodeProjects(rootDir string) ([]types.NodeProject, error) {
 			// Check if this project is within a workspace root
 			projectDir := nodeProjects[i].Dir
 			for workspaceRoot := range workspaceRoots {
-				// Check if projectDir is within workspaceRoot
-				relPath, err := filepath.Rel(workspaceRoot, projectDir)
-				if err == nil && !strings.HasPrefix(relPath, "..") && relPath != "." {
+				// Check if projectDir is a member of the workspace (matches its workspace globs)
+				if projectDir != workspaceRoot && NodeWorkspaceIncludes(workspaceRoot, projectDir) {
 					// This project is a child of the workspace
 					nodeProjects[i].WorkspaceRoot = workspaceRoot
 					break
@@ -104,7 +121,7 @@ func FindNodeProjects(rootDir string) ([]types.NodeProject, error) {
 	return nodeProjects, err
 }
 
-// DetectNodePackageManager determines whether to use pnpm, yarn, or npm.
+// DetectNodePackageManager determines whether to use pnpm, yarn, bun, deno, or npm.
 // Priority: packageManager field in package.json > lock files > npm (default).
 func DetectNodePackageManager(projectDir string) string {
 	// Use unbounded search (for backward compatibility with tests)
@@ -144,7 +161,8 @@ func DetectNodePackageManagerWithBoundaryAndSource(projectDir string, boundaryDi
 	}
 
 	// Fall back to lock file detection
-	// Priority: pnpm-lock.yaml > pnpm-workspace.yaml > yarn.lock > package-lock.json > npm (default)
+	// Priority: pnpm-lock.yaml > pnpm-workspace.yaml > yarn.lock > bun.lockb > bun.lock >
+	// deno.json > deno.jsonc > package-lock.json > npm (default)
 	if _, err := os.Stat(filepath.Join(absDir, "pnpm-lock.yaml")); err == nil {
 		return PackageManagerInfo{Name: "pnpm", Source: "pnpm-lock.yaml"}
 	}
@@ -154,6 +172,16 @@ func DetectNodePackageManagerWithBoundaryAndSource(projectDir string, boundaryDi
 	if _, err := os.Stat(filepath.Join(absDir, "yarn.lock")); err == nil {
 		return PackageManagerInfo{Name: "yarn", Source: "yarn.lock"}
 	}
+	for _, lockFile := range []string{"bun.lockb", "bun.lock"} {
+		if _, err := os.Stat(filepath.Join(absDir, lockFile)); err == nil {
+			return PackageManagerInfo{Name: pkgBun, Source: lockFile}
+		}
+	}
+	for _, configFile := range denoConfigFiles {
+		if _, err := os.Stat(filepath.Join(absDir, configFile)); err == nil {
+			return PackageManagerInfo{Name: pkgDeno, Source: configFile}
+		}
+	}
 	if _, err := os.Stat(filepath.Join(absDir, "package-lock.json")); err == nil {
 		return PackageManagerInfo{Name: pkgNPM, Source: "package-lock.json"}
 	}
@@ -163,9 +191,23 @@ func DetectNodePackageManagerWithBoundaryAndSource(projectDir string, boundaryDi
 }
 
 // GetPackageManagerFromPackageJSON reads package.json and extracts the packageManager field.
-// The packageManager field format is: "name@version" (e.g., "pnpm@8.15.0", "yarn@4.1.0", "npm@10.5.0")
+// The packageManager field format is: "name@version" (e.g., "pnpm@8.15.0", "yarn@4.1.0", "bun@1.1.38")
 // Returns the package manager name (without version) if found, empty string otherwise.
 func GetPackageManagerFromPackageJSON(projectDir string) string {
+	spec := GetPackageManagerSpecFromPackageJSON(projectDir)
+	if spec == "" {
+		return ""
+	}
+
+	// Split by '@' to extract the package manager name from "name@version" format
+	// (e.g., "npm@8.19.2" -> "npm")
+	return strings.Split(spec, "@")[0]
+}
+
+// GetPackageManagerSpecFromPackageJSON reads package.json and returns the full packageManager
+// field (e.g., "pnpm@8.15.0" or "yarn@4.1.0+sha224.abc"), as expected by corepack.
+// Returns an empty string if the field is missing or names an unsupported package manager.
+func GetPackageManagerSpecFromPackageJSON(projectDir string) string {
 	packageJSONPath := filepath.Join(projectDir, "package.json")
 
 	// Validate path before reading
@@ -189,23 +231,15 @@ func GetPackageManagerFromPackageJSON(projectDir string) string {
 		return ""
 	}
 
-	// The packageManager field is in the format "name@version"
-	// We need to extract just the name part
-	if pkg.PackageManager == "" {
+	spec := strings.TrimSpace(pkg.PackageManager)
+	if spec == "" {
 		return ""
 	}
 
-	// Split by '@' to extract the package manager name from "name@version" format
-	// (e.g., "npm@8.19.2" -> "npm")
-	parts := strings.Split(pkg.PackageManager, "@")
-
-	// The package manager name is the first part
-	pkgMgrName := parts[0]
-
 	// Validate it's a supported package manager
-	switch pkgMgrName {
-	case pkgNPM, "yarn", "pnpm":
-		return pkgMgrName
+	switch strings.Split(spec, "@")[0] {
+	case pkgNPM, "yarn", "pnpm", pkgBun:
+		return spec
 	default:
 		// Unsupported package manager, fall back to lock file detection
 		return ""
@@ -218,6 +252,26 @@ func HasPackageJson(dir string) bool {
 	return err == nil
 }
 
+// HasDenoConfig checks if deno.json or deno.jsonc exists in a directory.
+func HasDenoConfig(dir string) bool {
+	for _, configFile := range denoConfigFiles {
+		if _, err := os.Stat(filepath.Join(dir, configFile)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isDenoConfigFile reports whether a file name is a Deno configuration file.
+func isDenoConfigFile(name string) bool {
+	for _, configFile := range denoConfigFiles {
+		if name == configFile {
+			return true
+		}
+	}
+	return false
+}
+
 // HasNpmWorkspaces checks if package.json defines npm/yarn/pnpm workspaces.
 // Returns true if the workspaces field is present and not empty, or if pnpm-workspace.yaml exists.
 func HasNpmWorkspaces(dir string) bool {
@@ -21,6 +21,9 @@ func TestFindNodeProjects(t *testing.T) {
 		"project2/package.json":          `{"name": "test2"}`,
 		"project2/yarn.lock":             "",
 		"project3/package.json":          `{"name": "test3"}`,
+		"project4/package.json":          `{"name": "test4"}`,
+		"project4/bun.lockb":             "",
+		"project5/deno.json":             `{"tasks": {"dev": "deno run -A main.ts"}}`,
 		"node_modules/fake/package.json": `{"name": "should-be-ignored"}`,
 	}
 
@@ -40,9 +43,9 @@ func TestFindNodeProjects(t *testing.T) {
 		t.Fatalf("FindNodeProjects() error = %v", err)
 	}
 
-	// Verify results (should find 3, excluding node_modules)
-	if len(results) != 3 {
-		t.Errorf("FindNodeProjects() found %d projects, want 3", len(results))
+	// Verify results (should find 5, excluding node_modules)
+	if len(results) != 5 {
+		t.Errorf("FindNodeProjects() found %d projects, want 5", len(results))
 	}
 
 	// Check package managers
@@ -51,8 +54,8 @@ func TestFindNodeProjects(t *testing.T) {
 		pkgMgrs[proj.PackageManager]++
 	}
 
-	if pkgMgrs["pnpm"] != 1 || pkgMgrs["yarn"] != 1 || pkgMgrs["npm"] != 1 {
-		t.Errorf("Expected 1 pnpm, 1 yarn, 1 npm project, got: %+v", pkgMgrs)
+	if pkgMgrs["pnpm"] != 1 || pkgMgrs["yarn"] != 1 || pkgMgrs["npm"] != 1 || pkgMgrs["bun"] != 1 || pkgMgrs["deno"] != 1 {
+		t.Errorf("Expected 1 pnpm, 1 yarn, 1 npm, 1 bun, 1 deno project, got: %+v", pkgMgrs)
 	}
 }
 
@@ -267,6 +270,11 @@ func TestGetPackageManagerFromPackageJSON(t *testing.T) {
 			content:  `{"name": "test", "packageManager": "pnpm@8.15.0"}`,
 			expected: "pnpm",
 		},
+		{
+			name:     "packageManager field with bun",
+			content:  `{"name": "test", "packageManager": "bun@1.1.38"}`,
+			expected: "bun",
+		},
 		{
 			name:     "no packageManager field",
 			content:  `{"name": "test", "version": "1.0.0"}`,
@@ -279,7 +287,7 @@ func TestGetPackageManagerFromPackageJSON(t *testing.T) {
 		},
 		{
 			name:     "unsupported package manager",
-			content:  `{"name": "test", "packageManager": "bun@1.0.0"}`,
+			content:  `{"name": "test", "packageManager": "cnpm@9.0.0"}`,
 			expected: "",
 		},
 		{
@@ -318,6 +326,48 @@ func TestGetPackageManagerFromPackageJSON(t *testing.T) {
 	}
 }
 
+func TestGetPackageManagerSpecFromPackageJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "pnpm with version",
+			content:  `{"name": "test", "packageManager": "pnpm@8.15.0"}`,
+			expected: "pnpm@8.15.0",
+		},
+		{
+			name:     "yarn with hash",
+			content:  `{"name": "test", "packageManager": "yarn@4.1.0+sha224.abc123"}`,
+			expected: "yarn@4.1.0+sha224.abc123",
+		},
+		{
+			name:     "no packageManager field",
+			content:  `{"name": "test"}`,
+			expected: "",
+		},
+		{
+			name:     "unsupported package manager",
+			content:  `{"name": "test", "packageManager": "cnpm@9.0.0"}`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(tt.content), 0600); err != nil {
+				t.Fatalf("failed to create package.json: %v", err)
+			}
+
+			if result := GetPackageManagerSpecFromPackageJSON(tmpDir); result != tt.expected {
+				t.Errorf("GetPackageManagerSpecFromPackageJSON() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestDetectNodePackageManagerWithPackageManagerField(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -361,6 +411,36 @@ func TestDetectNodePackageManagerWithPackageManagerField(t *testing.T) {
 			lockFiles:   []string{"yarn.lock"},
 			expected:    "pnpm",
 		},
+		{
+			name:        "packageManager field with bun",
+			packageJson: `{"name": "test", "packageManager": "bun@1.1.38"}`,
+			lockFiles:   []string{"package-lock.json"},
+			expected:    "bun",
+		},
+		{
+			name:        "fallback to binary bun lock file",
+			packageJson: `{"name": "test"}`,
+			lockFiles:   []string{"bun.lockb", "package-lock.json"},
+			expected:    "bun",
+		},
+		{
+			name:        "fallback to text bun lock file",
+			packageJson: `{"name": "test"}`,
+			lockFiles:   []string{"bun.lock"},
+			expected:    "bun",
+		},
+		{
+			name:        "deno.json next to package.json",
+			packageJson: `{"name": "test"}`,
+			lockFiles:   []string{"deno.json"},
+			expected:    "deno",
+		},
+		{
+			name:        "yarn lock file takes priority over bun lock file",
+			packageJson: `{"name": "test"}`,
+			lockFiles:   []string{"yarn.lock", "bun.lockb"},
+			expected:    "yarn",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,128 @@
+package detector
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// Node.js version managers that pin a project's Node.js version.
+const (
+	NodeVersionManagerNvm   = "nvm"
+	NodeVersionManagerFnm   = "fnm"
+	NodeVersionManagerVolta = "volta"
+)
+
+// NodeVersionPin is the Node.js version a project pins for a version manager.
+type NodeVersionPin struct {
+	Version string // Pinned version or alias, e.g. "18", "20.11.1" or "lts/iron"
+	File    string // Absolute path of the file that pins it (.nvmrc, .node-version or package.json)
+	Manager string // Version manager the file belongs to: nvm, fnm or volta
+}
+
+// Source returns the name of the pinning file, e.g. ".nvmrc" or "package.json (volta)".
+func (p NodeVersionPin) Source() string {
+	if p.Manager == NodeVersionManagerVolta {
+		return "package.json (volta)"
+	}
+	return filepath.Base(p.File)
+}
+
+// IsAlias reports whether the pin names an alias such as "lts/*" or "node" rather than a version.
+func (p NodeVersionPin) IsAlias() bool {
+	v := strings.TrimPrefix(p.Version, "v")
+	return v == "" || v[0] < '0' || v[0] > '9'
+}
+
+// FindNodeVersionPin returns the Node.js version pinned for the project in projectDir: a volta
+// node version in package.json, an .nvmrc or a .node-version file, in that order. Like the
+// version managers, it looks in the parent directories too, up to rootDir.
+// Returns false when nothing pins a version.
+func FindNodeVersionPin(projectDir, rootDir string) (NodeVersionPin, bool) {
+	dir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return NodeVersionPin{}, false
+	}
+	root, err := filepath.Abs(rootDir)
+	if err != nil || rootDir == "" {
+		root = dir
+	}
+
+	for {
+		if pin, ok := nodeVersionPinIn(dir); ok {
+			return pin, true
+		}
+		parent := filepath.Dir(dir)
+		if dir == root || parent == dir || !strings.HasPrefix(parent, root) {
+			return NodeVersionPin{}, false
+		}
+		dir = parent
+	}
+}
+
+// nodeVersionPinIn returns the Node.js version pinned by the files in dir.
+func nodeVersionPinIn(dir string) (NodeVersionPin, bool) {
+	if version := voltaNodeVersion(dir); version != "" {
+		return NodeVersionPin{Version: version, File: filepath.Join(dir, "package.json"), Manager: NodeVersionManagerVolta}, true
+	}
+	for _, file := range []struct{ name, manager string }{
+		{".nvmrc", NodeVersionManagerNvm},
+		{".node-version", NodeVersionManagerFnm},
+	} {
+		path := filepath.Join(dir, file.name)
+		if version := readNodeVersionFile(path); version != "" {
+			return NodeVersionPin{Version: version, File: path, Manager: file.manager}, true
+		}
+	}
+	return NodeVersionPin{}, false
+}
+
+// readNodeVersionFile returns the version in an .nvmrc or .node-version file: its first line
+// that isn't blank or a comment.
+func readNodeVersionFile(path string) string {
+	if err := security.ValidatePath(path); err != nil {
+		return ""
+	}
+	// #nosec G304 -- Path validated by security.ValidatePath
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			return strings.TrimPrefix(line, "v")
+		}
+	}
+	return ""
+}
+
+// voltaNodeVersion returns the node version of the volta section of the package.json in dir.
+func voltaNodeVersion(dir string) string {
+	packageJSONPath := filepath.Join(dir, "package.json")
+	if err := security.ValidatePath(packageJSONPath); err != nil {
+		return ""
+	}
+	// #nosec G304 -- Path validated by security.ValidatePath
+	data, err := os.ReadFile(packageJSONPath)
+	if err != nil {
+		return ""
+	}
+
+	var pkg struct {
+		Volta struct {
+			Node string `json:"node"`
+		} `json:"volta"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		slog.Debug("failed to parse package.json", "project", dir, "error", "invalid JSON format")
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(pkg.Volta.Node), "v")
+}
@@ -0,0 +1,94 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindNodeVersionPin(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string // relative to the root; the project is in web
+		wantVersion string
+		wantManager string
+		wantFile    string
+	}{
+		{
+			name:        "nvmrc",
+			files:       map[string]string{"web/.nvmrc": "# LTS\nv18\n"},
+			wantVersion: "18", wantManager: NodeVersionManagerNvm, wantFile: "web/.nvmrc",
+		},
+		{
+			name:        "node-version",
+			files:       map[string]string{"web/.node-version": "20.11.1"},
+			wantVersion: "20.11.1", wantManager: NodeVersionManagerFnm, wantFile: "web/.node-version",
+		},
+		{
+			name: "volta takes priority",
+			files: map[string]string{
+				"web/package.json": `{"name": "web", "volta": {"node": "20.11.1"}}`,
+				"web/.nvmrc":       "18",
+			},
+			wantVersion: "20.11.1", wantManager: NodeVersionManagerVolta, wantFile: "web/package.json",
+		},
+		{
+			name:        "pin in a parent directory",
+			files:       map[string]string{".nvmrc": "lts/iron", "web/package.json": `{"name": "web"}`},
+			wantVersion: "lts/iron", wantManager: NodeVersionManagerNvm, wantFile: ".nvmrc",
+		},
+		{
+			name:  "no pin",
+			files: map[string]string{"web/package.json": `{"name": "web"}`, "web/.nvmrc": "# nothing\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(root, "web"), 0750); err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			pin, ok := FindNodeVersionPin(filepath.Join(root, "web"), root)
+			if ok != (tt.wantVersion != "") {
+				t.Fatalf("FindNodeVersionPin() found = %v, want %v (pin %+v)", ok, tt.wantVersion != "", pin)
+			}
+			if !ok {
+				return
+			}
+			if pin.Version != tt.wantVersion || pin.Manager != tt.wantManager || pin.File != filepath.Join(root, tt.wantFile) {
+				t.Errorf("FindNodeVersionPin() = %+v, want %s from %s (%s)", pin, tt.wantVersion, tt.wantFile, tt.wantManager)
+			}
+		})
+	}
+}
+
+func TestFindNodeVersionPin_StopsAtRoot(t *testing.T) {
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, ".nvmrc"), []byte("18"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	root := filepath.Join(outside, "app")
+	project := filepath.Join(root, "web")
+	if err := os.MkdirAll(project, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	if pin, ok := FindNodeVersionPin(project, root); ok {
+		t.Errorf("FindNodeVersionPin() = %+v, want nothing above the root", pin)
+	}
+}
+
+func TestNodeVersionPin_IsAlias(t *testing.T) {
+	for version, want := range map[string]bool{"18": false, "20.11.1": false, "lts/*": true, "node": true, "": true} {
+		if got := (NodeVersionPin{Version: version}).IsAlias(); got != want {
+			t.Errorf("IsAlias(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
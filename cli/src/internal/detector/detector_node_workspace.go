@@ -0,0 +1,222 @@
+package detector
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+	"gopkg.in/yaml.v3"
+)
+
+// Monorepo tools that run package scripts across a JavaScript workspace.
+const (
+	MonorepoToolTurbo = "turbo"
+	MonorepoToolNx    = "nx"
+)
+
+// NodeWorkspace describes the JavaScript workspace (monorepo) a package belongs to.
+type NodeWorkspace struct {
+	Root           string // Directory of the workspace root package.json
+	PackageManager string // Package manager of the workspace root
+	Tool           string // "turbo", "nx", or empty for a plain package manager workspace
+}
+
+// IsNodeWorkspaceRoot checks if dir is the root of a JavaScript workspace: a package.json declaring
+// workspaces, a pnpm-workspace.yaml, or a turbo.json/nx.json monorepo next to a package.json.
+func IsNodeWorkspaceRoot(dir string) bool {
+	return HasNpmWorkspaces(dir) || (HasPackageJson(dir) && DetectMonorepoTool(dir) != "")
+}
+
+// DetectMonorepoTool returns "turbo" or "nx" when dir has a turbo.json or nx.json,
+// or an empty string otherwise.
+func DetectMonorepoTool(dir string) string {
+	if fileExistsInDir(dir, "turbo.json") {
+		return MonorepoToolTurbo
+	}
+	if fileExistsInDir(dir, "nx.json") {
+		return MonorepoToolNx
+	}
+	return ""
+}
+
+// FindNodeWorkspace returns the workspace that the package in dir belongs to, searching from dir
+// up to stopDir (inclusive), or nil if the package isn't part of a workspace.
+// dir itself is returned as the root when it is a workspace root.
+func FindNodeWorkspace(dir, stopDir string) *NodeWorkspace {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	absStop, err := filepath.Abs(stopDir)
+	if err != nil {
+		return nil
+	}
+
+	for current := absDir; ; current = filepath.Dir(current) {
+		if rel, err := filepath.Rel(absStop, current); err != nil || strings.HasPrefix(rel, "..") {
+			return nil
+		}
+		if IsNodeWorkspaceRoot(current) && NodeWorkspaceIncludes(current, absDir) {
+			return &NodeWorkspace{
+				Root:           current,
+				PackageManager: DetectNodePackageManagerWithBoundary(current, current),
+				Tool:           DetectMonorepoTool(current),
+			}
+		}
+		if current == absStop || current == filepath.Dir(current) {
+			return nil
+		}
+	}
+}
+
+// NodeWorkspaceIncludes checks if the package in dir is a member of the workspace rooted at root,
+// matching the workspace globs from package.json or pnpm-workspace.yaml. A workspace without globs
+// (a turbo or nx monorepo, or an empty pnpm-workspace.yaml) installs from the root, so every
+// package below the root is a member.
+func NodeWorkspaceIncludes(root, dir string) bool {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	if rel == "." {
+		return true
+	}
+	rel = filepath.ToSlash(rel)
+
+	patterns := NodeWorkspacePatterns(root)
+	if len(patterns) == 0 {
+		return true
+	}
+
+	included := false
+	for _, pattern := range patterns {
+		if excluded, ok := strings.CutPrefix(pattern, "!"); ok {
+			if matchWorkspacePattern(excluded, rel) {
+				return false
+			}
+			continue
+		}
+		if matchWorkspacePattern(pattern, rel) {
+			included = true
+		}
+	}
+	return included
+}
+
+// NodeWorkspacePatterns returns the workspace package globs from pnpm-workspace.yaml, or from
+// the workspaces field of package.json (either an array or an object with a packages field).
+func NodeWorkspacePatterns(root string) []string {
+	pnpmWorkspacePath := filepath.Join(root, "pnpm-workspace.yaml")
+	if err := security.ValidatePath(pnpmWorkspacePath); err == nil {
+		// #nosec G304 -- Path validated by security.ValidatePath
+		if data, err := os.ReadFile(pnpmWorkspacePath); err == nil {
+			var pnpmWorkspace struct {
+				Packages []string `yaml:"packages"`
+			}
+			if err := yaml.Unmarshal(data, &pnpmWorkspace); err == nil {
+				return pnpmWorkspace.Packages
+			}
+			return nil
+		}
+	}
+
+	packageJSONPath := filepath.Join(root, "package.json")
+	if err := security.ValidatePath(packageJSONPath); err != nil {
+		return nil
+	}
+	// #nosec G304 -- Path validated by security.ValidatePath
+	data, err := os.ReadFile(packageJSONPath)
+	if err != nil {
+		return nil
+	}
+
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+
+	var patterns []string
+	if err := json.Unmarshal(pkg.Workspaces, &patterns); err == nil {
+		return patterns
+	}
+	var workspaces struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(pkg.Workspaces, &workspaces); err == nil {
+		return workspaces.Packages
+	}
+	return nil
+}
+
+// matchWorkspacePattern matches a slash-separated relative path against a workspace glob,
+// where "**" matches any number of directories.
+func matchWorkspacePattern(pattern, rel string) bool {
+	pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+	return matchPatternSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+// matchPatternSegments matches path segments against glob segments one at a time.
+func matchPatternSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchPatternSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+		return false
+	}
+	return matchPatternSegments(pattern[1:], segments[1:])
+}
+
+// NodePackageName returns the name field of dir/package.json, or an empty string if there is none.
+func NodePackageName(dir string) string {
+	packageJSONPath := filepath.Join(dir, "package.json")
+	if err := security.ValidatePath(packageJSONPath); err != nil {
+		return ""
+	}
+	// #nosec G304 -- Path validated by security.ValidatePath
+	data, err := os.ReadFile(packageJSONPath)
+	if err != nil {
+		return ""
+	}
+
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ""
+	}
+	return pkg.Name
+}
+
+// NxProjectName returns the Nx project name for the package in dir: the name in project.json
+// when there is one, otherwise the package.json name.
+func NxProjectName(dir string) string {
+	projectJSONPath := filepath.Join(dir, "project.json")
+	if err := security.ValidatePath(projectJSONPath); err == nil {
+		// #nosec G304 -- Path validated by security.ValidatePath
+		if data, err := os.ReadFile(projectJSONPath); err == nil {
+			var project struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(data, &project); err == nil && project.Name != "" {
+				return project.Name
+			}
+		}
+	}
+	return NodePackageName(dir)
+}
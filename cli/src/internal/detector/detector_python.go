@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	types "github.com/jongio/azd-core/projecttype"
@@ -23,10 +24,10 @@ import (
 //   - error: Non-nil if directory traversal fails
 //
 // Detection Strategy:
-//   - Searches for requirements.txt, pyproject.toml, poetry.lock, or uv.lock
-//   - Skips common directories: node_modules, .git, bin, obj, venv, .venv, __pycache__
+//   - Searches for requirements.txt, pyproject.toml, poetry.lock, uv.lock, or a conda environment.yml
+//   - Skips common directories: node_modules, .git, bin, obj, venv, .venv, .conda, __pycache__
 //   - Does not traverse outside rootDir (prevents directory traversal)
-//   - Package manager detection order: uv > poetry > pip
+//   - Package manager detection order: uv > poetry > conda > pipenv > pip
 func FindPythonProjects(rootDir string) ([]types.PythonProject, error) {
 	var pythonProjects []types.PythonProject
 	seen := make(map[string]bool)
@@ -59,7 +60,7 @@ func FindPythonProjects(rootDir string) ([]types.PythonProject, error) {
 		if info.IsDir() {
 			name := info.Name()
 			if name == skipDirNodeModules || name == skipDirBin || name == skipDirObj || name == skipDirGit ||
-				name == "venv" || name == ".venv" || name == "__pycache__" || name == ".uv" {
+				name == "venv" || name == ".venv" || name == CondaEnvDir || name == "__pycache__" || name == ".uv" {
 				return filepath.SkipDir
 			}
 		}
@@ -74,7 +75,7 @@ func FindPythonProjects(rootDir string) ([]types.PythonProject, error) {
 
 			// Look for Python project indicators
 			if info.Name() == "requirements.txt" || info.Name() == "pyproject.toml" ||
-				info.Name() == "poetry.lock" || info.Name() == "uv.lock" {
+				info.Name() == "poetry.lock" || info.Name() == "uv.lock" || slices.Contains(condaEnvironmentFiles, info.Name()) {
 				packageManager := DetectPythonPackageManager(dir)
 				pythonProjects = append(pythonProjects, types.PythonProject{
 					Dir:            dir,
@@ -91,14 +92,14 @@ func FindPythonProjects(rootDir string) ([]types.PythonProject, error) {
 }
 
 // DetectPythonPackageManager determines which package manager to use.
-// Priority order: uv > poetry > pip.
+// Priority order: uv > poetry > conda > pipenv > pip.
 func DetectPythonPackageManager(projectDir string) string {
 	info := DetectPythonPackageManagerWithSource(projectDir)
 	return info.Name
 }
 
 // DetectPythonPackageManagerWithSource determines which package manager to use and returns detection source.
-// Priority order: uv > poetry > pipenv > pip.
+// Priority order: uv > poetry > conda > pipenv > pip.
 func DetectPythonPackageManagerWithSource(projectDir string) PackageManagerInfo {
 	// Check for uv (uv.lock)
 	if _, err := os.Stat(filepath.Join(projectDir, "uv.lock")); err == nil {
@@ -126,6 +127,11 @@ func DetectPythonPackageManagerWithSource(projectDir string) PackageManagerInfo
 		}
 	}
 
+	// Check for conda (environment.yml)
+	if file := CondaEnvironmentFile(projectDir); file != "" {
+		return PackageManagerInfo{Name: "conda", Source: file}
+	}
+
 	// Check for pipenv (Pipfile or Pipfile.lock)
 	if _, err := os.Stat(filepath.Join(projectDir, "Pipfile")); err == nil {
 		return PackageManagerInfo{Name: "pipenv", Source: "Pipfile"}
@@ -137,3 +143,30 @@ func DetectPythonPackageManagerWithSource(projectDir string) PackageManagerInfo
 	// Default to pip
 	return PackageManagerInfo{Name: "pip", Source: "requirements.txt"}
 }
+
+// CondaEnvDir is the directory in a project where azd app deps creates its conda environment.
+const CondaEnvDir = ".conda"
+
+// condaEnvironmentFiles are the names of conda environment files, in order of preference.
+var condaEnvironmentFiles = []string{"environment.yml", "environment.yaml"}
+
+// CondaEnvironmentFile returns the name of the conda environment file of the project in
+// projectDir, or "" when it has none.
+func CondaEnvironmentFile(projectDir string) string {
+	for _, name := range condaEnvironmentFiles {
+		if _, err := os.Stat(filepath.Join(projectDir, name)); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// ResolvePythonInterpreter resolves a python.interpreter setting from azure.yaml: a path
+// (e.g., ".venv311/bin/python") is relative to projectDir, anything else is a command on PATH
+// (e.g., "python3.12").
+func ResolvePythonInterpreter(projectDir, interpreter string) string {
+	if interpreter == "" || filepath.IsAbs(interpreter) || !strings.ContainsAny(interpreter, `/\`) {
+		return interpreter
+	}
+	return filepath.Join(projectDir, interpreter)
+}
@@ -40,6 +40,22 @@ func TestDetectPythonPackageManager(t *testing.T) {
 			},
 			expected: "uv",
 		},
+		{
+			name: "conda environment file",
+			files: map[string]string{
+				"environment.yml":  "name: api\ndependencies:\n  - python=3.12",
+				"requirements.txt": "requests==2.28.0",
+			},
+			expected: "conda",
+		},
+		{
+			name: "uv takes priority over conda",
+			files: map[string]string{
+				"uv.lock":          "",
+				"environment.yaml": "name: api",
+			},
+			expected: "uv",
+		},
 		{
 			name: "requirements.txt only",
 			files: map[string]string{
@@ -129,3 +145,23 @@ func TestFindPythonProjects(t *testing.T) {
 		t.Errorf("Expected to find pip, poetry, and uv projects, got: %+v", results)
 	}
 }
+
+func TestResolvePythonInterpreter(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), "api")
+	absPython := filepath.Join(t.TempDir(), "python")
+
+	tests := []struct {
+		interpreter string
+		want        string
+	}{
+		{"", ""},
+		{"python3.12", "python3.12"},
+		{".venv311/bin/python", filepath.Join(projectDir, ".venv311", "bin", "python")},
+		{absPython, absPython},
+	}
+	for _, tt := range tests {
+		if got := ResolvePythonInterpreter(projectDir, tt.interpreter); got != tt.want {
+			t.Errorf("ResolvePythonInterpreter(%q) = %q, want %q", tt.interpreter, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// skipDirTarget is Cargo's build output directory.
+const skipDirTarget = "target"
+
+// RustProject represents a Cargo package or workspace.
+type RustProject struct {
+	Dir       string // Directory containing Cargo.toml
+	Workspace bool   // Cargo.toml declares a [workspace]
+}
+
+// FindRustProjects searches for Cargo.toml files.
+// Only searches within rootDir and does not traverse outside it.
+// Crates inside a workspace aren't returned separately: Cargo resolves a workspace as a whole,
+// so its dependencies are fetched once from the workspace root.
+func FindRustProjects(rootDir string) ([]RustProject, error) {
+	var rustProjects []RustProject
+
+	// Clean the root directory path
+	rootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return rustProjects, err
+	}
+
+	err = filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // file not found is expected, means this detector doesn't match
+		}
+
+		// Ensure we don't traverse outside rootDir
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil //nolint:nilerr // file not found is expected, means this detector doesn't match
+		}
+		relPath, err := filepath.Rel(rootDir, absPath)
+		if err != nil || strings.HasPrefix(relPath, "..") {
+			return filepath.SkipDir
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if name == skipDirNodeModules || name == skipDirGit || name == skipDirTarget {
+				return filepath.SkipDir
+			}
+		}
+
+		if !info.IsDir() && info.Name() == "Cargo.toml" {
+			dir := filepath.Dir(path)
+			rustProjects = append(rustProjects, RustProject{Dir: dir, Workspace: IsCargoWorkspace(dir)})
+		}
+
+		return nil
+	})
+
+	return withoutWorkspaceMembers(rustProjects), err
+}
+
+// withoutWorkspaceMembers drops projects nested inside a workspace project.
+func withoutWorkspaceMembers(projects []RustProject) []RustProject {
+	var result []RustProject
+	for _, project := range projects {
+		member := false
+		for _, other := range projects {
+			if !other.Workspace || other.Dir == project.Dir {
+				continue
+			}
+			if rel, err := filepath.Rel(other.Dir, project.Dir); err == nil && !strings.HasPrefix(rel, "..") {
+				member = true
+				break
+			}
+		}
+		if !member {
+			result = append(result, project)
+		}
+	}
+	return result
+}
+
+// HasCargoToml checks if Cargo.toml exists in a directory.
+func HasCargoToml(dir string) bool {
+	return fileExistsInDir(dir, "Cargo.toml")
+}
+
+// IsCargoWorkspace checks if dir/Cargo.toml declares a [workspace].
+func IsCargoWorkspace(dir string) bool {
+	return containsTextInFile(filepath.Join(dir, "Cargo.toml"), "[workspace]")
+}
+
+// CargoWorkspaceRoot returns the nearest directory from dir up to stopDir (inclusive) whose
+// Cargo.toml declares a [workspace], or dir itself when it isn't part of a workspace.
+func CargoWorkspaceRoot(dir, stopDir string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	absStop, err := filepath.Abs(stopDir)
+	if err != nil {
+		return dir
+	}
+
+	for current := absDir; ; current = filepath.Dir(current) {
+		if rel, err := filepath.Rel(absStop, current); err != nil || strings.HasPrefix(rel, "..") {
+			return dir
+		}
+		if IsCargoWorkspace(current) {
+			return current
+		}
+		if current == absStop || current == filepath.Dir(current) {
+			return dir
+		}
+	}
+}
+
+// CargoPackageName returns the name in the [package] section of dir/Cargo.toml,
+// or an empty string for a virtual workspace manifest (one without a package).
+func CargoPackageName(dir string) string {
+	return cargoPackageValue(dir, "name")
+}
+
+// CargoRustVersion returns the minimum supported Rust version declared by rust-version in
+// dir/Cargo.toml as a full version (e.g., "1.74" -> "1.74.0"), or an empty string if none is set.
+func CargoRustVersion(dir string) string {
+	version := cargoPackageValue(dir, "rust-version")
+	if strings.Count(version, ".") == 1 {
+		version += ".0"
+	}
+	return version
+}
+
+// cargoPackageValue returns a string key from the [package] or [workspace.package] section
+// of dir/Cargo.toml.
+func cargoPackageValue(dir, key string) string {
+	cargoPath := filepath.Join(dir, "Cargo.toml")
+	if err := security.ValidatePath(cargoPath); err != nil {
+		return ""
+	}
+
+	// #nosec G304 -- Path validated by security.ValidatePath
+	data, err := os.ReadFile(cargoPath)
+	if err != nil {
+		return ""
+	}
+
+	inPackage := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inPackage = line == "[package]" || line == "[workspace.package]"
+			continue
+		}
+		k, value, ok := strings.Cut(line, "=")
+		if inPackage && ok && strings.TrimSpace(k) == key {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// FindCargoWorkspacePackages returns the names of the packages in the workspace rooted at dir,
+// excluding the root package itself.
+func FindCargoWorkspacePackages(dir string) []string {
+	var packages []string
+	_ = filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // unreadable directories just aren't searched
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if name == skipDirNodeModules || name == skipDirGit || name == skipDirTarget {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() != "Cargo.toml" || filepath.Dir(path) == dir {
+			return nil
+		}
+		if name := CargoPackageName(filepath.Dir(path)); name != "" {
+			packages = append(packages, name)
+		}
+		return nil
+	})
+	return packages
+}
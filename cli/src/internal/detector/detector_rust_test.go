@@ -0,0 +1,99 @@
+package detector
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFindRustProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFiles(t, tmpDir, map[string]string{
+		"backend/Cargo.toml":              "[workspace]\nmembers = [\"crates/*\"]",
+		"backend/crates/api/Cargo.toml":   "[package]\nname = \"api\"",
+		"backend/crates/core/Cargo.toml":  "[package]\nname = \"core\"",
+		"backend/target/debug/Cargo.toml": "[package]\nname = \"stale\"",
+		"cli/Cargo.toml":                  "[package]\nname = \"cli\"",
+	})
+
+	projects, err := FindRustProjects(tmpDir)
+	if err != nil {
+		t.Fatalf("FindRustProjects() error = %v", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("FindRustProjects() = %+v, want the workspace root and the cli crate", projects)
+	}
+	for _, project := range projects {
+		switch filepath.Base(project.Dir) {
+		case "backend":
+			if !project.Workspace {
+				t.Errorf("backend should be a workspace: %+v", project)
+			}
+		case "cli":
+			if project.Workspace {
+				t.Errorf("cli shouldn't be a workspace: %+v", project)
+			}
+		default:
+			t.Errorf("FindRustProjects() returned workspace member or build output %s", project.Dir)
+		}
+	}
+}
+
+func TestCargoWorkspaceRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFiles(t, tmpDir, map[string]string{
+		"rs/Cargo.toml":            "[workspace]\nmembers = [\"crates/*\"]",
+		"rs/crates/api/Cargo.toml": "[package]\nname = \"api\"",
+		"solo/Cargo.toml":          "[package]\nname = \"solo\"",
+	})
+
+	if got := CargoWorkspaceRoot(filepath.Join(tmpDir, "rs", "crates", "api"), tmpDir); got != filepath.Join(tmpDir, "rs") {
+		t.Errorf("CargoWorkspaceRoot(member) = %q, want the workspace root", got)
+	}
+	solo := filepath.Join(tmpDir, "solo")
+	if got := CargoWorkspaceRoot(solo, tmpDir); got != solo {
+		t.Errorf("CargoWorkspaceRoot(standalone) = %q, want %q", got, solo)
+	}
+	// The workspace is outside stopDir, so it isn't used
+	member := filepath.Join(tmpDir, "rs", "crates", "api")
+	if got := CargoWorkspaceRoot(member, member); got != member {
+		t.Errorf("CargoWorkspaceRoot() above stopDir = %q, want %q", got, member)
+	}
+}
+
+func TestCargoManifestValues(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFiles(t, tmpDir, map[string]string{
+		"Cargo.toml":           "[workspace]\nmembers = [\"api\", \"worker\"]\n\n[workspace.package]\nrust-version = \"1.74\"",
+		"api/Cargo.toml":       "[package]\nname = \"api\"\nrust-version = \"1.80.1\"\n\n[dependencies]\nname = \"not-a-package\"",
+		"worker/Cargo.toml":    "[package]\nname = 'worker'",
+		"worker/nested/x.toml": "",
+	})
+
+	if got := CargoPackageName(filepath.Join(tmpDir, "api")); got != "api" {
+		t.Errorf("CargoPackageName(api) = %q, want %q", got, "api")
+	}
+	if got := CargoPackageName(filepath.Join(tmpDir, "worker")); got != "worker" {
+		t.Errorf("CargoPackageName(worker) = %q, want %q", got, "worker")
+	}
+	if got := CargoPackageName(tmpDir); got != "" {
+		t.Errorf("CargoPackageName(virtual manifest) = %q, want empty", got)
+	}
+
+	if got := CargoRustVersion(tmpDir); got != "1.74.0" {
+		t.Errorf("CargoRustVersion(workspace) = %q, want %q", got, "1.74.0")
+	}
+	if got := CargoRustVersion(filepath.Join(tmpDir, "api")); got != "1.80.1" {
+		t.Errorf("CargoRustVersion(api) = %q, want %q", got, "1.80.1")
+	}
+	if got := CargoRustVersion(filepath.Join(tmpDir, "worker")); got != "" {
+		t.Errorf("CargoRustVersion(worker) = %q, want empty", got)
+	}
+
+	packages := FindCargoWorkspacePackages(tmpDir)
+	sort.Strings(packages)
+	if strings.Join(packages, ",") != "api,worker" {
+		t.Errorf("FindCargoWorkspacePackages() = %v, want [api worker]", packages)
+	}
+}
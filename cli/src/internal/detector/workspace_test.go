@@ -441,3 +441,140 @@ func TestFindNodeProjects_PnpmWorkspaces(t *testing.T) {
 		}
 	}
 }
+
+func TestNodeWorkspaceIncludes(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		dir      string
+		expected bool
+	}{
+		{
+			name:     "matches package.json glob",
+			files:    map[string]string{"package.json": `{"workspaces": ["apps/*", "packages/*"]}`},
+			dir:      "apps/web",
+			expected: true,
+		},
+		{
+			name:     "outside package.json globs",
+			files:    map[string]string{"package.json": `{"workspaces": ["apps/*"]}`},
+			dir:      "tools/scripts",
+			expected: false,
+		},
+		{
+			name:     "nested below single-level glob",
+			files:    map[string]string{"package.json": `{"workspaces": {"packages": ["apps/*"]}}`},
+			dir:      "apps/web/e2e",
+			expected: false,
+		},
+		{
+			name:     "pnpm-workspace.yaml with recursive glob",
+			files:    map[string]string{"package.json": `{}`, "pnpm-workspace.yaml": "packages:\n  - 'services/**'\n"},
+			dir:      "services/api/v2",
+			expected: true,
+		},
+		{
+			name:     "pnpm-workspace.yaml exclusion",
+			files:    map[string]string{"package.json": `{}`, "pnpm-workspace.yaml": "packages:\n  - 'packages/*'\n  - '!packages/legacy'\n"},
+			dir:      "packages/legacy",
+			expected: false,
+		},
+		{
+			name:     "nx monorepo without globs",
+			files:    map[string]string{"package.json": `{}`, "nx.json": `{}`},
+			dir:      "libs/shared",
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeTestFiles(t, tmpDir, tt.files)
+			if got := NodeWorkspaceIncludes(tmpDir, filepath.Join(tmpDir, tt.dir)); got != tt.expected {
+				t.Errorf("NodeWorkspaceIncludes(%s) = %v, want %v", tt.dir, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindNodeWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFiles(t, tmpDir, map[string]string{
+		"repo/package.json":          `{"name": "repo", "packageManager": "pnpm@9.1.0"}`,
+		"repo/pnpm-workspace.yaml":   "packages:\n  - 'apps/*'\n",
+		"repo/turbo.json":            `{}`,
+		"repo/apps/web/package.json": `{"name": "web"}`,
+		"repo/tools/package.json":    `{"name": "tools"}`,
+		"standalone/package.json":    `{"name": "standalone"}`,
+	})
+	repo := filepath.Join(tmpDir, "repo")
+
+	workspace := FindNodeWorkspace(filepath.Join(repo, "apps", "web"), tmpDir)
+	if workspace == nil {
+		t.Fatal("FindNodeWorkspace() = nil, want the repo workspace")
+	}
+	if workspace.Root != repo || workspace.PackageManager != "pnpm" || workspace.Tool != MonorepoToolTurbo {
+		t.Errorf("FindNodeWorkspace() = %+v, want root %s with pnpm and turbo", workspace, repo)
+	}
+
+	if workspace := FindNodeWorkspace(repo, tmpDir); workspace == nil || workspace.Root != repo {
+		t.Errorf("FindNodeWorkspace(root) = %+v, want the root itself", workspace)
+	}
+	if workspace := FindNodeWorkspace(filepath.Join(repo, "tools"), tmpDir); workspace != nil {
+		t.Errorf("FindNodeWorkspace() outside the workspace globs = %+v, want nil", workspace)
+	}
+	if workspace := FindNodeWorkspace(filepath.Join(tmpDir, "standalone"), tmpDir); workspace != nil {
+		t.Errorf("FindNodeWorkspace(standalone) = %+v, want nil", workspace)
+	}
+	// The workspace root is above stopDir, so it isn't used
+	web := filepath.Join(repo, "apps", "web")
+	if workspace := FindNodeWorkspace(web, web); workspace != nil {
+		t.Errorf("FindNodeWorkspace() above stopDir = %+v, want nil", workspace)
+	}
+}
+
+func TestFindNodeProjects_TurboAndNx(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFiles(t, tmpDir, map[string]string{
+		"nx-repo/package.json":                 `{"name": "nx-repo"}`,
+		"nx-repo/nx.json":                      `{}`,
+		"nx-repo/libs/ui/package.json":         `{"name": "@nx-repo/ui"}`,
+		"turbo-repo/package.json":              `{"workspaces": ["apps/*"]}`,
+		"turbo-repo/turbo.json":                `{}`,
+		"turbo-repo/apps/web/package.json":     `{"name": "web"}`,
+		"turbo-repo/scripts/seed/package.json": `{"name": "seed"}`,
+	})
+
+	projects, err := FindNodeProjects(tmpDir)
+	if err != nil {
+		t.Fatalf("FindNodeProjects failed: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, project := range projects {
+		rel, _ := filepath.Rel(tmpDir, project.Dir)
+		switch {
+		case project.IsWorkspaceRoot:
+			got[filepath.ToSlash(rel)] = "root"
+		case project.WorkspaceRoot != "":
+			parent, _ := filepath.Rel(tmpDir, project.WorkspaceRoot)
+			got[filepath.ToSlash(rel)] = "member of " + filepath.ToSlash(parent)
+		default:
+			got[filepath.ToSlash(rel)] = "standalone"
+		}
+	}
+
+	want := map[string]string{
+		"nx-repo":                 "root",
+		"nx-repo/libs/ui":         "member of nx-repo",
+		"turbo-repo":              "root",
+		"turbo-repo/apps/web":     "member of turbo-repo",
+		"turbo-repo/scripts/seed": "standalone",
+	}
+	for dir, relation := range want {
+		if got[dir] != relation {
+			t.Errorf("%s is %q, want %q", dir, got[dir], relation)
+		}
+	}
+}
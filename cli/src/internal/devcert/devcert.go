@@ -0,0 +1,268 @@
+// Package devcert provides a TLS certificate for localhost, used to serve the dashboard and
+// services over HTTPS during local development.
+//
+// The certificate is created once and shared by all projects. A locally-trusted certificate is
+// preferred: one issued by mkcert when its CA is installed, or the trusted ASP.NET Core
+// development certificate from `dotnet dev-certs`. Otherwise a self-signed certificate is
+// generated, which browsers warn about.
+package devcert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sources of the certificate.
+const (
+	SourceMkcert     = "mkcert"
+	SourceDotnet     = "dotnet dev-certs"
+	SourceSelfSigned = "self-signed"
+)
+
+const (
+	certFileName   = "localhost.pem"
+	keyFileName    = "localhost-key.pem"
+	sourceFileName = "source"
+
+	// renewBefore is how long before expiry a certificate is replaced.
+	renewBefore = 7 * 24 * time.Hour
+	// selfSignedValidity is how long a generated self-signed certificate is valid.
+	selfSignedValidity = 365 * 24 * time.Hour
+	// toolTimeout bounds each mkcert or dotnet dev-certs invocation.
+	toolTimeout = 30 * time.Second
+)
+
+// hosts are the names the certificate is valid for.
+var hosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// Cert is a certificate and private key in PEM files.
+type Cert struct {
+	CertFile string // PEM certificate
+	KeyFile  string // PEM private key (unencrypted)
+	Source   string // "mkcert", "dotnet dev-certs" or "self-signed"
+}
+
+// Trusted reports whether the certificate is trusted by the machine, so browsers accept it
+// without a warning. Self-signed certificates are not.
+func (c *Cert) Trusted() bool {
+	return c.Source != SourceSelfSigned
+}
+
+// TLSConfig returns a server TLS configuration that uses the certificate.
+func (c *Cert) TLSConfig() (*tls.Config, error) {
+	pair, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load development certificate: %w", err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{pair},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// DefaultDir returns the directory of the shared certificate, ~/.azd/app/certs.
+// This is a variable to allow test overrides.
+var DefaultDir = func() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".azd", "app", "certs"), nil
+}
+
+// lookPath and runTool find and run mkcert and dotnet. They are variables to allow test overrides.
+var (
+	lookPath = exec.LookPath
+	runTool  = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		// #nosec G204 -- name is mkcert or dotnet; args are fixed flags and paths under the cert directory
+		return exec.CommandContext(ctx, name, args...).Output()
+	}
+)
+
+// ensureMu serializes certificate creation within the process.
+var ensureMu sync.Mutex
+
+// EnsureDefault returns the shared certificate in DefaultDir, creating it if needed.
+func EnsureDefault() (*Cert, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return Ensure(dir)
+}
+
+// Ensure returns the certificate in dir. A new one is created when it is missing, unreadable,
+// not valid for localhost, or about to expire.
+func Ensure(dir string) (*Cert, error) {
+	ensureMu.Lock()
+	defer ensureMu.Unlock()
+
+	cert := &Cert{
+		CertFile: filepath.Join(dir, certFileName),
+		KeyFile:  filepath.Join(dir, keyFileName),
+	}
+	if isUsable(cert) {
+		cert.Source = readSource(dir)
+		return cert, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create certificate directory: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), toolTimeout)
+	defer cancel()
+
+	switch {
+	case createWithMkcert(ctx, cert) == nil:
+		cert.Source = SourceMkcert
+	case createWithDotnet(ctx, cert) == nil:
+		cert.Source = SourceDotnet
+	default:
+		if err := createSelfSigned(cert, time.Now()); err != nil {
+			return nil, err
+		}
+		cert.Source = SourceSelfSigned
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, sourceFileName), []byte(cert.Source), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write certificate source: %w", err)
+	}
+	return cert, nil
+}
+
+// isUsable reports whether the certificate and key load, cover localhost and don't expire soon.
+func isUsable(cert *Cert) bool {
+	pair, err := tls.LoadX509KeyPair(cert.CertFile, cert.KeyFile)
+	if err != nil || len(pair.Certificate) == 0 {
+		return false
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return false
+	}
+	if time.Now().Add(renewBefore).After(leaf.NotAfter) {
+		return false
+	}
+	return leaf.VerifyHostname("localhost") == nil
+}
+
+// readSource returns the recorded source of an existing certificate.
+func readSource(dir string) string {
+	// #nosec G304 -- dir is the certificate directory
+	data, err := os.ReadFile(filepath.Join(dir, sourceFileName))
+	if err != nil {
+		return SourceSelfSigned
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// createWithMkcert issues the certificate with mkcert. It is only used when the mkcert CA has
+// been installed (`mkcert -install`), since otherwise the certificate wouldn't be trusted either.
+func createWithMkcert(ctx context.Context, cert *Cert) error {
+	if _, err := lookPath("mkcert"); err != nil {
+		return err
+	}
+	caRoot, err := runTool(ctx, "mkcert", "-CAROOT")
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(strings.TrimSpace(string(caRoot)), "rootCA.pem")); err != nil {
+		return fmt.Errorf("mkcert CA is not installed: %w", err)
+	}
+
+	args := append([]string{"-cert-file", cert.CertFile, "-key-file", cert.KeyFile}, hosts...)
+	if _, err := runTool(ctx, "mkcert", args...); err != nil {
+		return err
+	}
+	if !isUsable(cert) {
+		return errors.New("mkcert did not create a usable certificate")
+	}
+	return nil
+}
+
+// createWithDotnet exports the ASP.NET Core development certificate when it is trusted.
+// `dotnet dev-certs` writes the key next to the certificate with a .key extension.
+func createWithDotnet(ctx context.Context, cert *Cert) error {
+	if _, err := lookPath("dotnet"); err != nil {
+		return err
+	}
+	if _, err := runTool(ctx, "dotnet", "dev-certs", "https", "--check", "--trust"); err != nil {
+		return fmt.Errorf("no trusted .NET development certificate: %w", err)
+	}
+
+	if _, err := runTool(ctx, "dotnet", "dev-certs", "https", "--export-path", cert.CertFile, "--format", "PEM", "--no-password"); err != nil {
+		return err
+	}
+	exportedKey := strings.TrimSuffix(cert.CertFile, filepath.Ext(cert.CertFile)) + ".key"
+	if err := os.Rename(exportedKey, cert.KeyFile); err != nil {
+		return fmt.Errorf("failed to move exported key: %w", err)
+	}
+	if !isUsable(cert) {
+		return errors.New("dotnet dev-certs did not export a usable certificate")
+	}
+	return nil
+}
+
+// createSelfSigned generates a self-signed ECDSA certificate for localhost.
+func createSelfSigned(cert *Cert, now time.Time) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "localhost", Organization: []string{"azd app development certificate"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode key: %w", err)
+	}
+
+	if err := os.WriteFile(cert.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		return fmt.Errorf("failed to write certificate: %w", err)
+	}
+	if err := os.WriteFile(cert.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		return fmt.Errorf("failed to write key: %w", err)
+	}
+	return nil
+}
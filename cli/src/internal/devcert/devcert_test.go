@@ -0,0 +1,126 @@
+package devcert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// withoutTools makes mkcert and dotnet unavailable for the duration of the test.
+func withoutTools(t *testing.T) {
+	t.Helper()
+	origLookPath := lookPath
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	t.Cleanup(func() { lookPath = origLookPath })
+}
+
+func TestEnsure_SelfSigned(t *testing.T) {
+	withoutTools(t)
+	dir := t.TempDir()
+
+	cert, err := Ensure(dir)
+	if err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	if cert.Source != SourceSelfSigned || cert.Trusted() {
+		t.Errorf("Ensure() source = %q, want an untrusted self-signed certificate", cert.Source)
+	}
+
+	config, err := cert.TLSConfig()
+	if err != nil {
+		t.Fatalf("TLSConfig() error = %v", err)
+	}
+	leaf, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{"localhost", "127.0.0.1", "::1"} {
+		if err := leaf.VerifyHostname(host); err != nil {
+			t.Errorf("certificate not valid for %s: %v", host, err)
+		}
+	}
+	if config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x, want TLS 1.2", config.MinVersion)
+	}
+
+	// The certificate is reused rather than regenerated
+	before, _ := os.ReadFile(cert.CertFile)
+	again, err := Ensure(dir)
+	if err != nil {
+		t.Fatalf("Ensure() second call error = %v", err)
+	}
+	after, _ := os.ReadFile(again.CertFile)
+	if string(before) != string(after) || again.Source != SourceSelfSigned {
+		t.Error("Ensure() regenerated a usable certificate")
+	}
+}
+
+func TestEnsure_RenewsExpiringCertificate(t *testing.T) {
+	withoutTools(t)
+	dir := t.TempDir()
+
+	expiring := &Cert{CertFile: filepath.Join(dir, certFileName), KeyFile: filepath.Join(dir, keyFileName)}
+	if err := createSelfSigned(expiring, time.Now().Add(-selfSignedValidity+24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if isUsable(expiring) {
+		t.Fatal("a certificate expiring tomorrow should not be usable")
+	}
+
+	cert, err := Ensure(dir)
+	if err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	if !isUsable(cert) {
+		t.Error("Ensure() did not replace the expiring certificate")
+	}
+}
+
+func TestEnsure_PrefersTrustedDotnetCertificate(t *testing.T) {
+	dir := t.TempDir()
+
+	origLookPath, origRunTool := lookPath, runTool
+	t.Cleanup(func() { lookPath, runTool = origLookPath, origRunTool })
+	lookPath = func(name string) (string, error) {
+		if name == "dotnet" {
+			return name, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	var calls [][]string
+	runTool = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		calls = append(calls, append([]string{name}, args...))
+		if len(args) > 2 && args[2] == "--export-path" {
+			// Simulate the export: certificate at the path and the key next to it
+			exported := &Cert{CertFile: args[3], KeyFile: filepath.Join(dir, "localhost.key")}
+			return nil, createSelfSigned(exported, time.Now())
+		}
+		return nil, nil
+	}
+
+	cert, err := Ensure(dir)
+	if err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	if cert.Source != SourceDotnet || !cert.Trusted() {
+		t.Errorf("Ensure() source = %q, want %q", cert.Source, SourceDotnet)
+	}
+	if len(calls) != 2 {
+		t.Errorf("dotnet calls = %v, want --check --trust then --export-path", calls)
+	}
+	if _, err := os.Stat(cert.KeyFile); err != nil {
+		t.Errorf("exported key was not moved to %s: %v", cert.KeyFile, err)
+	}
+
+	// The source is remembered when the certificate is reused
+	again, err := Ensure(dir)
+	if err != nil || again.Source != SourceDotnet {
+		t.Errorf("Ensure() reuse = %+v, %v, want source %q", again, err, SourceDotnet)
+	}
+}
@@ -0,0 +1,216 @@
+// Package devenv detects the development environment azd app runs in, WSL, a devcontainer or
+// an SSH session, where services are reached differently than on a local machine.
+package devenv
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment is the development environment azd app runs in.
+type Environment struct {
+	WSL          bool   `json:"wsl" yaml:"wsl"`
+	WSLVersion   int    `json:"wslVersion,omitempty" yaml:"wslVersion,omitempty"` // 1 or 2
+	WSLDistro    string `json:"wslDistro,omitempty" yaml:"wslDistro,omitempty"`
+	Devcontainer bool   `json:"devcontainer" yaml:"devcontainer"`
+	Codespaces   bool   `json:"codespaces" yaml:"codespaces"`
+	// CodespaceName and PortForwardingDomain make up the forwarded URLs of a codespace's ports
+	CodespaceName        string `json:"codespaceName,omitempty" yaml:"codespaceName,omitempty"`
+	PortForwardingDomain string `json:"portForwardingDomain,omitempty" yaml:"portForwardingDomain,omitempty"`
+	// SSH is set in an SSH session; SSHHost and SSHPort are the server address the client connected to
+	SSH     bool   `json:"ssh,omitempty" yaml:"ssh,omitempty"`
+	SSHHost string `json:"sshHost,omitempty" yaml:"sshHost,omitempty"`
+	SSHPort string `json:"sshPort,omitempty" yaml:"sshPort,omitempty"`
+	SSHUser string `json:"-" yaml:"-"`
+	// HostDocker is set when containers run on the host's Docker daemon
+	// (docker-outside-of-docker), so their ports are published on the host, not in the devcontainer.
+	HostDocker bool `json:"hostDocker,omitempty" yaml:"hostDocker,omitempty"`
+}
+
+// Paths read to detect the environment.
+const (
+	osReleasePath     = "/proc/sys/kernel/osrelease"
+	dockerHostSocket  = "/var/run/docker-host.sock" // Mounted by the docker-outside-of-docker feature
+	dockerEnvPath     = "/.dockerenv"
+	containerEnvPath  = "/run/.containerenv"
+	workspacesDirPath = "/workspaces"
+)
+
+// defaultPortForwardingDomain is the domain of forwarded codespace ports when
+// GITHUB_CODESPACES_PORT_FORWARDING_DOMAIN isn't set.
+const defaultPortForwardingDomain = "app.github.dev"
+
+// Detect detects the environment of the current process.
+func Detect() Environment {
+	return detect(os.Getenv, os.ReadFile, func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	})
+}
+
+// detect detects the environment from environment variables and files.
+func detect(getenv func(string) string, readFile func(string) ([]byte, error), exists func(string) bool) Environment {
+	var env Environment
+
+	// WSL kernels identify themselves in their release: "4.4.0-19041-Microsoft" (WSL1) or
+	// "5.15.153.1-microsoft-standard-WSL2"
+	release, _ := readFile(osReleasePath)
+	lower := strings.ToLower(string(release))
+	if getenv("WSL_DISTRO_NAME") != "" || strings.Contains(lower, "microsoft") {
+		env.WSL = true
+		env.WSLDistro = getenv("WSL_DISTRO_NAME")
+		env.WSLVersion = 1
+		if getenv("WSL_INTEROP") != "" || strings.Contains(lower, "wsl2") || strings.Contains(lower, "microsoft-standard") {
+			env.WSLVersion = 2
+		}
+	}
+
+	env.Codespaces = getenv("CODESPACES") == "true"
+	if env.Codespaces {
+		env.CodespaceName = getenv("CODESPACE_NAME")
+		env.PortForwardingDomain = getenv("GITHUB_CODESPACES_PORT_FORWARDING_DOMAIN")
+		if env.PortForwardingDomain == "" {
+			env.PortForwardingDomain = defaultPortForwardingDomain
+		}
+	}
+
+	// SSH_CONNECTION is "<client ip> <client port> <server ip> <server port>"
+	if connection := getenv("SSH_CONNECTION"); connection != "" || getenv("SSH_CLIENT") != "" {
+		env.SSH = true
+		if fields := strings.Fields(connection); len(fields) == 4 {
+			env.SSHHost = fields[2]
+			env.SSHPort = fields[3]
+		}
+		env.SSHUser = getenv("USER")
+	}
+
+	inContainer := exists(dockerEnvPath) || exists(containerEnvPath)
+	env.Devcontainer = env.Codespaces ||
+		getenv("REMOTE_CONTAINERS") == "true" ||
+		getenv("DEVCONTAINER") == "true" ||
+		(inContainer && exists(workspacesDirPath))
+	env.HostDocker = env.Devcontainer && exists(dockerHostSocket)
+	return env
+}
+
+// Name describes the environment, e.g. "WSL2 (Ubuntu)" or "GitHub Codespaces".
+// Returns "" for a local machine.
+func (e Environment) Name() string {
+	switch {
+	case e.Codespaces:
+		return "GitHub Codespaces"
+	case e.Devcontainer:
+		return "devcontainer"
+	case e.WSL && e.WSLDistro != "":
+		return fmt.Sprintf("WSL%d (%s)", e.WSLVersion, e.WSLDistro)
+	case e.WSL:
+		return fmt.Sprintf("WSL%d", e.WSLVersion)
+	case e.SSH:
+		return "SSH session"
+	default:
+		return ""
+	}
+}
+
+// Hints explains how services are reached in the environment: port forwarding to the machine
+// running the browser, and the host name of containers.
+func (e Environment) Hints() []string {
+	var hints []string
+	switch {
+	case e.Codespaces:
+		hints = append(hints, "Service ports are forwarded to https://<codespace>-<port>.app.github.dev; the dashboard links to them")
+	case e.Devcontainer:
+		hints = append(hints, "Service ports are only reachable from your machine once forwarded: VS Code forwards them when they open, or list them in forwardPorts in devcontainer.json")
+	case e.WSL && e.WSLVersion == 1:
+		hints = append(hints, "WSL1 shares the Windows network: services are reachable from Windows at localhost")
+	case e.WSL:
+		hints = append(hints, "Services listening on localhost are forwarded to Windows; if a Windows browser can't reach them, set localhostForwarding=true or networkingMode=mirrored in %UserProfile%\\.wslconfig")
+	case e.SSH:
+		hints = append(hints, "Services listening on localhost aren't reachable from your machine: forward their ports with ssh -L; azd app run prints the command")
+	}
+	if e.HostDocker {
+		hints = append(hints, "Containers run on the host's Docker daemon, so their ports aren't on localhost here: reach them at host.docker.internal")
+	}
+	return hints
+}
+
+// ForwardedURL returns the URL a browser reaches a local URL at in a codespace, e.g.
+// http://localhost:3000/api becomes https://<codespace>-3000.app.github.dev/api.
+// Returns "" outside Codespaces, or when the URL isn't a localhost URL with a port.
+func (e Environment) ForwardedURL(localURL string) string {
+	if !e.Codespaces || e.CodespaceName == "" {
+		return ""
+	}
+	port := LocalPort(localURL)
+	if port == 0 {
+		return ""
+	}
+	u, _ := url.Parse(localURL)
+	forwarded := url.URL{
+		Scheme:   "https",
+		Host:     fmt.Sprintf("%s-%d.%s", e.CodespaceName, port, e.PortForwardingDomain),
+		Path:     u.Path,
+		RawQuery: u.RawQuery,
+		Fragment: u.Fragment,
+	}
+	return forwarded.String()
+}
+
+// SSHForwardCommand returns the ssh command that forwards the ports to the same ports on the
+// SSH client, e.g. "ssh -N -L 3000:localhost:3000 user@10.0.0.5".
+// Returns "" outside an SSH session or without ports.
+func (e Environment) SSHForwardCommand(ports []int) string {
+	if !e.SSH || len(ports) == 0 {
+		return ""
+	}
+	args := []string{"ssh", "-N"}
+	for _, port := range ports {
+		args = append(args, fmt.Sprintf("-L %d:localhost:%d", port, port))
+	}
+	if e.SSHPort != "" && e.SSHPort != "22" {
+		args = append(args, "-p "+e.SSHPort)
+	}
+	host := e.SSHHost
+	if host == "" {
+		host = "<host>"
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if e.SSHUser != "" {
+		host = e.SSHUser + "@" + host
+	}
+	return strings.Join(append(args, host), " ")
+}
+
+// LocalPort returns the port of a URL on this machine (localhost, a loopback address or
+// 0.0.0.0), or 0 for other URLs and URLs without a port.
+func LocalPort(rawURL string) int {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || (!ip.IsLoopback() && !ip.IsUnspecified())) {
+		return 0
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil || port <= 0 {
+		return 0
+	}
+	return port
+}
+
+// IsWindowsMount reports whether path is on a Windows drive mounted in WSL, e.g. /mnt/c/src,
+// where file access is much slower than in the Linux filesystem.
+func IsWindowsMount(path string) bool {
+	rest, ok := strings.CutPrefix(path, "/mnt/")
+	if !ok || rest == "" {
+		return false
+	}
+	drive, _, _ := strings.Cut(rest, "/")
+	return len(drive) == 1 && drive[0] >= 'a' && drive[0] <= 'z'
+}
@@ -0,0 +1,199 @@
+package devenv
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		osRelease string
+		files     []string
+		want      Environment
+		wantName  string
+	}{
+		{name: "local", osRelease: "6.8.0-45-generic", want: Environment{}, wantName: ""},
+		{
+			name:      "WSL2",
+			env:       map[string]string{"WSL_DISTRO_NAME": "Ubuntu", "WSL_INTEROP": "/run/WSL/1_interop"},
+			osRelease: "5.15.153.1-microsoft-standard-WSL2",
+			want:      Environment{WSL: true, WSLVersion: 2, WSLDistro: "Ubuntu"},
+			wantName:  "WSL2 (Ubuntu)",
+		},
+		{
+			name:      "WSL1",
+			osRelease: "4.4.0-19041-Microsoft",
+			want:      Environment{WSL: true, WSLVersion: 1},
+			wantName:  "WSL1",
+		},
+		{
+			name:     "VS Code devcontainer with docker-outside-of-docker",
+			env:      map[string]string{"REMOTE_CONTAINERS": "true"},
+			files:    []string{dockerEnvPath, dockerHostSocket},
+			want:     Environment{Devcontainer: true, HostDocker: true},
+			wantName: "devcontainer",
+		},
+		{
+			name:     "Codespaces",
+			env:      map[string]string{"CODESPACES": "true", "CODESPACE_NAME": "fuzzy-space-abc123"},
+			files:    []string{dockerEnvPath, workspacesDirPath},
+			want:     Environment{Devcontainer: true, Codespaces: true, CodespaceName: "fuzzy-space-abc123", PortForwardingDomain: "app.github.dev"},
+			wantName: "GitHub Codespaces",
+		},
+		{
+			name: "Codespaces with a forwarding domain",
+			env: map[string]string{
+				"CODESPACES":     "true",
+				"CODESPACE_NAME": "fuzzy-space-abc123",
+				"GITHUB_CODESPACES_PORT_FORWARDING_DOMAIN": "preview.app.github.dev",
+			},
+			want:     Environment{Devcontainer: true, Codespaces: true, CodespaceName: "fuzzy-space-abc123", PortForwardingDomain: "preview.app.github.dev"},
+			wantName: "GitHub Codespaces",
+		},
+		{
+			name:      "SSH",
+			env:       map[string]string{"SSH_CONNECTION": "192.168.1.20 51234 10.0.0.5 2222", "USER": "dev"},
+			osRelease: "6.8.0-45-generic",
+			want:      Environment{SSH: true, SSHHost: "10.0.0.5", SSHPort: "2222", SSHUser: "dev"},
+			wantName:  "SSH session",
+		},
+		{
+			name:     "devcontainer CLI",
+			files:    []string{dockerEnvPath, workspacesDirPath},
+			want:     Environment{Devcontainer: true},
+			wantName: "devcontainer",
+		},
+		{
+			name:  "plain container",
+			files: []string{dockerEnvPath, dockerHostSocket},
+			want:  Environment{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			readFile := func(path string) ([]byte, error) {
+				if path == osReleasePath && tt.osRelease != "" {
+					return []byte(tt.osRelease + "\n"), nil
+				}
+				return nil, errors.New("not found")
+			}
+			exists := func(path string) bool {
+				for _, file := range tt.files {
+					if file == path {
+						return true
+					}
+				}
+				return false
+			}
+
+			got := detect(getenv, readFile, exists)
+			if got != tt.want {
+				t.Errorf("detect() = %+v, want %+v", got, tt.want)
+			}
+			if got.Name() != tt.wantName {
+				t.Errorf("Name() = %q, want %q", got.Name(), tt.wantName)
+			}
+			if (len(got.Hints()) > 0) != (tt.wantName != "") {
+				t.Errorf("Hints() = %q", got.Hints())
+			}
+		})
+	}
+}
+
+func TestIsWindowsMount(t *testing.T) {
+	tests := map[string]bool{
+		"/mnt/c/src/app":  true,
+		"/mnt/d":          true,
+		"/mnt/wsl/shared": false,
+		"/home/me/src":    false,
+		"/mnt/":           false,
+	}
+	for path, want := range tests {
+		if got := IsWindowsMount(path); got != want {
+			t.Errorf("IsWindowsMount(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestForwardedURL(t *testing.T) {
+	codespace := Environment{Codespaces: true, CodespaceName: "fuzzy-space", PortForwardingDomain: "app.github.dev"}
+	tests := []struct {
+		name string
+		env  Environment
+		url  string
+		want string
+	}{
+		{name: "localhost", env: codespace, url: "http://localhost:3000", want: "https://fuzzy-space-3000.app.github.dev"},
+		{name: "path and query", env: codespace, url: "http://127.0.0.1:5000/api?x=1", want: "https://fuzzy-space-5000.app.github.dev/api?x=1"},
+		{name: "https", env: codespace, url: "https://localhost:7001/", want: "https://fuzzy-space-7001.app.github.dev/"},
+		{name: "remote host", env: codespace, url: "https://app.azurewebsites.net:443", want: ""},
+		{name: "no port", env: codespace, url: "http://localhost", want: ""},
+		{name: "not a codespace", env: Environment{SSH: true}, url: "http://localhost:3000", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.env.ForwardedURL(tt.url); got != tt.want {
+				t.Errorf("ForwardedURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSSHForwardCommand(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   Environment
+		ports []int
+		want  string
+	}{
+		{
+			name:  "default port",
+			env:   Environment{SSH: true, SSHHost: "10.0.0.5", SSHPort: "22", SSHUser: "dev"},
+			ports: []int{3000, 5000},
+			want:  "ssh -N -L 3000:localhost:3000 -L 5000:localhost:5000 dev@10.0.0.5",
+		},
+		{
+			name:  "custom port and IPv6",
+			env:   Environment{SSH: true, SSHHost: "fe80::1", SSHPort: "2222"},
+			ports: []int{8080},
+			want:  "ssh -N -L 8080:localhost:8080 -p 2222 [fe80::1]",
+		},
+		{
+			name:  "unknown host",
+			env:   Environment{SSH: true},
+			ports: []int{8080},
+			want:  "ssh -N -L 8080:localhost:8080 <host>",
+		},
+		{name: "no ports", env: Environment{SSH: true}, want: ""},
+		{name: "not SSH", env: Environment{}, ports: []int{8080}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.env.SSHForwardCommand(tt.ports); got != tt.want {
+				t.Errorf("SSHForwardCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalPort(t *testing.T) {
+	tests := map[string]int{
+		"http://localhost:3000":     3000,
+		"http://127.0.0.1:5000/api": 5000,
+		"http://0.0.0.0:8080":       8080,
+		"http://[::1]:4000":         4000,
+		"http://localhost":          0,
+		"https://example.com:8443":  0,
+		"http://192.168.1.10:3000":  0,
+		"::not a url":               0,
+	}
+	for url, want := range tests {
+		if got := LocalPort(url); got != want {
+			t.Errorf("LocalPort(%q) = %d, want %d", url, got, want)
+		}
+	}
+}
@@ -12,6 +12,10 @@ type Client interface {
 	// Returns an error if the image cannot be found or downloaded.
 	Pull(image string) error
 
+	// Build builds an image from a Dockerfile.
+	// Build output is written to output when it is non-nil.
+	Build(config BuildConfig, output io.Writer) error
+
 	// Run creates and starts a container with the given configuration.
 	// Returns the container ID on success.
 	Run(config ContainerConfig) (string, error)
@@ -51,4 +55,19 @@ type Client interface {
 	// ExecShell runs a shell command inside a running container using sh -c.
 	// Returns the exit code, command output, and any error.
 	ExecShell(containerName string, shellCommand string) (int, string, error)
+
+	// ComposeUp creates and starts the containers of a compose project and waits until they are healthy.
+	// Progress output is written to output when it is non-nil.
+	ComposeUp(config ComposeConfig, output io.Writer) error
+
+	// ComposeDown stops and removes the containers of a compose project, keeping its volumes.
+	// The timeout is in seconds, per container.
+	ComposeDown(project string, timeoutSeconds int) error
+
+	// ComposePs returns the containers of a compose project with their published ports.
+	ComposePs(project string) ([]ComposeContainer, error)
+
+	// ComposeLogs returns a reader for the combined log stream of a compose project's containers.
+	// The caller is responsible for closing the returned ReadCloser.
+	ComposeLogs(project string) (io.ReadCloser, error)
 }
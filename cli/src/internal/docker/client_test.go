@@ -138,6 +138,14 @@ func TestBuildRunArgs(t *testing.T) {
 			},
 			expected: []string{"run", "-d", "-p", "10000:10000/tcp", "-p", "10001:10001/tcp", "-p", "10002:10002/tcp", "azurite"},
 		},
+		{
+			name: "config with volumes",
+			config: ContainerConfig{
+				Image:   "postgres:16",
+				Volumes: []string{"shop-db-data:/var/lib/postgresql/data", "/src/init:/docker-entrypoint-initdb.d"},
+			},
+			expected: []string{"run", "-d", "-v", "shop-db-data:/var/lib/postgresql/data", "-v", "/src/init:/docker-entrypoint-initdb.d", "postgres:16"},
+		},
 		{
 			name: "config with all options",
 			config: ContainerConfig{
@@ -859,3 +867,169 @@ func TestExecShell_ShellCommandValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildImageArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   BuildConfig
+		expected []string
+	}{
+		{
+			name: "basic build",
+			config: BuildConfig{
+				Image:      "azd-api:local",
+				Dockerfile: "/src/api/Dockerfile",
+				Context:    "/src/api",
+			},
+			expected: []string{"build", "-t", "azd-api:local", "-f", "/src/api/Dockerfile", "/src/api"},
+		},
+		{
+			name: "build with platform and build args",
+			config: BuildConfig{
+				Image:      "azd-api:local",
+				Dockerfile: "/src/api/Dockerfile.dev",
+				Context:    "/src",
+				BuildArgs:  []string{"VERSION=1.0", "DEBUG=true"},
+				Platform:   "linux/amd64",
+			},
+			expected: []string{
+				"build", "-t", "azd-api:local", "-f", "/src/api/Dockerfile.dev",
+				"--platform", "linux/amd64",
+				"--build-arg", "VERSION=1.0", "--build-arg", "DEBUG=true",
+				"/src",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildImageArgs(tt.config)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("buildImageArgs() = %v, want %v", got, tt.expected)
+			}
+			for i, arg := range tt.expected {
+				if got[i] != arg {
+					t.Errorf("buildImageArgs()[%d] = %q, want %q", i, got[i], arg)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildConfigValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  BuildConfig
+		wantErr bool
+	}{
+		{"valid", BuildConfig{Image: "azd-api:local", Dockerfile: "Dockerfile", Context: "."}, false},
+		{"invalid image", BuildConfig{Image: "api; rm -rf /", Dockerfile: "Dockerfile", Context: "."}, true},
+		{"missing dockerfile", BuildConfig{Image: "azd-api:local", Context: "."}, true},
+		{"missing context", BuildConfig{Image: "azd-api:local", Dockerfile: "Dockerfile"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestComposeUpArgs(t *testing.T) {
+	got := composeUpArgs(ComposeConfig{File: "/src/docker-compose.yml", Project: "azd-stack"})
+	expected := []string{"compose", "-f", "/src/docker-compose.yml", "-p", "azd-stack", "up", "-d", "--wait", "--remove-orphans"}
+	if len(got) != len(expected) {
+		t.Fatalf("composeUpArgs() = %v, want %v", got, expected)
+	}
+	for i, arg := range expected {
+		if got[i] != arg {
+			t.Errorf("composeUpArgs()[%d] = %q, want %q", i, got[i], arg)
+		}
+	}
+}
+
+func TestComposeConfigValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ComposeConfig
+		wantErr bool
+	}{
+		{"valid", ComposeConfig{File: "docker-compose.yml", Project: "azd-stack"}, false},
+		{"missing file", ComposeConfig{Project: "azd-stack"}, true},
+		{"missing project", ComposeConfig{File: "docker-compose.yml"}, true},
+		{"uppercase project", ComposeConfig{File: "docker-compose.yml", Project: "azd-Stack"}, true},
+		{"project with spaces", ComposeConfig{File: "docker-compose.yml", Project: "azd stack"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestComposeProjectValidation(t *testing.T) {
+	client := NewClient()
+	if err := client.ComposeDown("", 10); err == nil {
+		t.Error("ComposeDown() with empty project should return error")
+	}
+	if _, err := client.ComposePs("-bad"); err == nil {
+		t.Error("ComposePs() with invalid project should return error")
+	}
+	if _, err := client.ComposeLogs(""); err == nil {
+		t.Error("ComposeLogs() with empty project should return error")
+	}
+}
+
+func TestParseComposePs(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "one object per line",
+			data: `{"Name":"azd-stack-web-1","Service":"web","State":"running","Publishers":[{"URL":"0.0.0.0","TargetPort":80,"PublishedPort":8080,"Protocol":"tcp"},{"URL":"::","TargetPort":80,"PublishedPort":8080,"Protocol":"tcp"}]}
+{"Name":"azd-stack-db-1","Service":"db","State":"running","Publishers":[{"URL":"","TargetPort":5432,"PublishedPort":0,"Protocol":"tcp"}]}
+`,
+		},
+		{
+			name: "array",
+			data: `[{"Name":"azd-stack-web-1","Service":"web","State":"running","Publishers":[{"URL":"0.0.0.0","TargetPort":80,"PublishedPort":8080,"Protocol":"tcp"}]},` +
+				`{"Name":"azd-stack-db-1","Service":"db","State":"running","Publishers":null}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			containers, err := parseComposePs([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("parseComposePs() error = %v", err)
+			}
+			if len(containers) != 2 {
+				t.Fatalf("parseComposePs() returned %d containers, want 2", len(containers))
+			}
+			db, web := containers[0], containers[1]
+			if db.Service != "db" || len(db.Ports) != 0 {
+				t.Errorf("containers[0] = %+v, want service db without published ports", db)
+			}
+			if web.Service != "web" || web.Name != "azd-stack-web-1" || web.State != "running" {
+				t.Errorf("containers[1] = %+v, want running container azd-stack-web-1 of service web", web)
+			}
+			want := PortMapping{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}
+			if len(web.Ports) != 1 || web.Ports[0] != want {
+				t.Errorf("web ports = %+v, want [%+v]", web.Ports, want)
+			}
+		})
+	}
+
+	if containers, err := parseComposePs(nil); err != nil || len(containers) != 0 {
+		t.Errorf("parseComposePs(nil) = %v, %v, want no containers", containers, err)
+	}
+	if _, err := parseComposePs([]byte("{not json")); err == nil {
+		t.Error("parseComposePs() with invalid JSON should return error")
+	}
+}
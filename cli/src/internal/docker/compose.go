@@ -0,0 +1,219 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ComposeConfig holds configuration for running a Docker Compose project.
+type ComposeConfig struct {
+	// File is the path to the compose file
+	File string
+
+	// Project is the compose project name, which also names its containers
+	Project string
+
+	// Environment contains variables for interpolation in the compose file, added to the environment of docker compose
+	Environment map[string]string
+}
+
+// ComposeContainer represents a container of a Docker Compose project.
+type ComposeContainer struct {
+	// Name is the container's name (e.g., "azd-stack-web-1")
+	Name string
+
+	// Service is the compose service the container runs
+	Service string
+
+	// State is the container's current state (e.g., "running", "exited")
+	State string
+
+	// Ports contains the ports published on the host
+	Ports []PortMapping
+}
+
+// composeProjectNameRegex validates compose project names.
+// Pattern: [a-z0-9][a-z0-9_-]* (lowercase, must start with a letter or digit)
+var composeProjectNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateComposeProjectName checks if a compose project name is valid.
+func ValidateComposeProjectName(name string) error {
+	if name == "" {
+		return fmt.Errorf("compose project name cannot be empty")
+	}
+	if !composeProjectNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid compose project name %q: must start with a lowercase letter or digit and contain only [a-z0-9_-]", name)
+	}
+	return nil
+}
+
+// Validate checks that the compose configuration names a file and a valid project.
+func (c ComposeConfig) Validate() error {
+	if c.File == "" {
+		return fmt.Errorf("compose file path cannot be empty")
+	}
+	return ValidateComposeProjectName(c.Project)
+}
+
+// ComposeUp creates and starts the containers of a compose project and waits until they
+// are running and healthy. Progress output is written to output when it is non-nil.
+func (c *ExecClient) ComposeUp(config ComposeConfig, output io.Writer) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// #nosec G204 -- project name is validated; the file path comes from azure.yaml service configuration
+	cmd := exec.CommandContext(context.Background(), "docker", composeUpArgs(config)...)
+	cmd.Env = os.Environ()
+	for key, value := range config.Environment {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	// docker compose reports progress and errors on stderr, so keep it for the error message
+	var stderr bytes.Buffer
+	if output != nil {
+		cmd.Stdout = output
+		cmd.Stderr = io.MultiWriter(output, &stderr)
+	} else {
+		cmd.Stderr = &stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		if tail := lastLines(stderr.String(), buildErrorLines); tail != "" {
+			return fmt.Errorf("failed to start compose project %q: %s: %w", config.Project, tail, err)
+		}
+		return fmt.Errorf("failed to start compose project %q: %w", config.Project, err)
+	}
+
+	return nil
+}
+
+// composeUpArgs constructs the arguments for docker compose up.
+func composeUpArgs(config ComposeConfig) []string {
+	return []string{"compose", "-f", config.File, "-p", config.Project, "up", "-d", "--wait", "--remove-orphans"}
+}
+
+// ComposeDown stops and removes the containers and networks of a compose project.
+// Named volumes are kept. The timeout is in seconds, per container.
+func (c *ExecClient) ComposeDown(project string, timeoutSeconds int) error {
+	if err := ValidateComposeProjectName(project); err != nil {
+		return err
+	}
+
+	args := []string{"compose", "-p", project, "down", "-t", fmt.Sprintf("%d", timeoutSeconds)}
+	cmd := exec.CommandContext(context.Background(), "docker", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		stderrStr := strings.TrimSpace(stderr.String())
+		if stderrStr != "" {
+			return fmt.Errorf("failed to stop compose project %q: %s: %w", project, stderrStr, err)
+		}
+		return fmt.Errorf("failed to stop compose project %q: %w", project, err)
+	}
+
+	return nil
+}
+
+// ComposePs returns the containers of a compose project, ordered by service.
+func (c *ExecClient) ComposePs(project string) ([]ComposeContainer, error) {
+	if err := ValidateComposeProjectName(project); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(context.Background(), "docker", "compose", "-p", project, "ps", "--all", "--format", "json")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		stderrStr := strings.TrimSpace(stderr.String())
+		if stderrStr != "" {
+			return nil, fmt.Errorf("failed to list containers of compose project %q: %s: %w", project, stderrStr, err)
+		}
+		return nil, fmt.Errorf("failed to list containers of compose project %q: %w", project, err)
+	}
+
+	return parseComposePs(stdout.Bytes())
+}
+
+// composePsResult represents a container in the JSON output of docker compose ps.
+type composePsResult struct {
+	Name       string `json:"Name"`
+	Service    string `json:"Service"`
+	State      string `json:"State"`
+	Publishers []struct {
+		URL           string `json:"URL"`
+		TargetPort    int    `json:"TargetPort"`
+		PublishedPort int    `json:"PublishedPort"`
+		Protocol      string `json:"Protocol"`
+	} `json:"Publishers"`
+}
+
+// parseComposePs parses the JSON output of docker compose ps, which is an array in
+// Compose versions before 2.21 and one object per line since.
+func parseComposePs(data []byte) ([]ComposeContainer, error) {
+	var results []composePsResult
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &results); err != nil {
+			return nil, fmt.Errorf("failed to parse docker compose ps output: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var result composePsResult
+			if err := json.Unmarshal(line, &result); err != nil {
+				return nil, fmt.Errorf("failed to parse docker compose ps output: %w", err)
+			}
+			results = append(results, result)
+		}
+	}
+
+	containers := make([]ComposeContainer, 0, len(results))
+	for _, result := range results {
+		container := ComposeContainer{Name: result.Name, Service: result.Service, State: result.State}
+		seen := make(map[PortMapping]bool)
+		for _, publisher := range result.Publishers {
+			if publisher.PublishedPort == 0 {
+				continue // Exposed but not published
+			}
+			// Ports published on all interfaces are listed once for IPv4 and once for IPv6
+			port := PortMapping{HostPort: publisher.PublishedPort, ContainerPort: publisher.TargetPort, Protocol: publisher.Protocol}
+			if seen[port] {
+				continue
+			}
+			seen[port] = true
+			container.Ports = append(container.Ports, port)
+		}
+		containers = append(containers, container)
+	}
+	sort.SliceStable(containers, func(i, j int) bool { return containers[i].Service < containers[j].Service })
+	return containers, nil
+}
+
+// ComposeLogs returns a reader for the combined log stream of the containers of a compose
+// project, each line prefixed with its container's name. The caller is responsible for
+// closing the returned ReadCloser.
+func (c *ExecClient) ComposeLogs(project string) (io.ReadCloser, error) {
+	if err := ValidateComposeProjectName(project); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(context.Background(), "docker", "compose", "-p", project, "logs", "-f", "--no-color")
+	return followOutput(cmd, "docker compose logs")
+}
@@ -50,6 +50,64 @@ func (c *ExecClient) Pull(image string) error {
 	return nil
 }
 
+// Build builds an image from a Dockerfile.
+func (c *ExecClient) Build(config BuildConfig, output io.Writer) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// #nosec G204 -- image name is validated; paths come from azure.yaml service configuration
+	cmd := exec.CommandContext(context.Background(), "docker", buildImageArgs(config)...)
+
+	// docker build reports progress and errors on stderr, so keep it for the error message
+	var stderr bytes.Buffer
+	if output != nil {
+		cmd.Stdout = output
+		cmd.Stderr = io.MultiWriter(output, &stderr)
+	} else {
+		cmd.Stderr = &stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		if tail := lastLines(stderr.String(), buildErrorLines); tail != "" {
+			return fmt.Errorf("failed to build image %q: %s: %w", config.Image, tail, err)
+		}
+		return fmt.Errorf("failed to build image %q: %w", config.Image, err)
+	}
+
+	return nil
+}
+
+// buildErrorLines is the number of trailing docker build output lines included in errors.
+const buildErrorLines = 20
+
+// buildImageArgs constructs the arguments for docker build.
+func buildImageArgs(config BuildConfig) []string {
+	args := []string{"build", "-t", config.Image, "-f", config.Dockerfile}
+
+	if config.Platform != "" {
+		args = append(args, "--platform", config.Platform)
+	}
+
+	for _, arg := range config.BuildArgs {
+		args = append(args, "--build-arg", arg)
+	}
+
+	// Context is always the last argument
+	args = append(args, config.Context)
+
+	return args
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
 // Run creates and starts a container with the given configuration.
 func (c *ExecClient) Run(config ContainerConfig) (string, error) {
 	if err := config.Validate(); err != nil {
@@ -102,6 +160,11 @@ func buildRunArgs(config ContainerConfig) []string {
 		args = append(args, "-e", fmt.Sprintf("%s=%s", key, value))
 	}
 
+	// Add volumes
+	for _, volume := range config.Volumes {
+		args = append(args, "-v", volume)
+	}
+
 	// Add image
 	args = append(args, config.Image)
 
@@ -201,6 +264,12 @@ func (c *ExecClient) Logs(containerID string) (io.ReadCloser, error) {
 	}
 
 	cmd := exec.CommandContext(context.Background(), "docker", "logs", "-f", containerID)
+	return followOutput(cmd, "docker logs")
+}
+
+// followOutput starts cmd and returns a reader of its combined stdout and stderr.
+// Closing the reader terminates the command.
+func followOutput(cmd *exec.Cmd, name string) (io.ReadCloser, error) {
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stdout pipe: %w", err)
@@ -215,7 +284,7 @@ func (c *ExecClient) Logs(containerID string) (io.ReadCloser, error) {
 	if err := cmd.Start(); err != nil {
 		_ = stdout.Close()
 		_ = stderr.Close()
-		return nil, fmt.Errorf("failed to start docker logs: %w", err)
+		return nil, fmt.Errorf("failed to start %s: %w", name, err)
 	}
 
 	// Create pipe to combine stdout and stderr concurrently
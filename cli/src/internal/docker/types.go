@@ -19,6 +19,27 @@ type ContainerConfig struct {
 
 	// Environment contains environment variables to set in the container
 	Environment map[string]string
+
+	// Volumes contains the volumes to mount, in docker run -v form (e.g., "pgdata:/var/lib/postgresql/data")
+	Volumes []string
+}
+
+// BuildConfig holds configuration for building an image from a Dockerfile.
+type BuildConfig struct {
+	// Image is the name and tag given to the built image (e.g., "azd-api:local")
+	Image string
+
+	// Dockerfile is the path to the Dockerfile
+	Dockerfile string
+
+	// Context is the build context directory
+	Context string
+
+	// BuildArgs contains build-time variables in KEY=value form
+	BuildArgs []string
+
+	// Platform is the target platform (e.g., "linux/amd64"), empty for the host platform
+	Platform string
 }
 
 // PortMapping represents a host:container port mapping.
@@ -131,3 +152,17 @@ func (c ContainerConfig) Validate() error {
 	}
 	return nil
 }
+
+// Validate checks that the build configuration is complete and the image name is safe.
+func (c BuildConfig) Validate() error {
+	if err := ValidateImageName(c.Image); err != nil {
+		return err
+	}
+	if c.Dockerfile == "" {
+		return fmt.Errorf("dockerfile path cannot be empty")
+	}
+	if c.Context == "" {
+		return fmt.Errorf("build context cannot be empty")
+	}
+	return nil
+}
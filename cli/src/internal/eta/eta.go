@@ -0,0 +1,181 @@
+// Package eta records how long dependency installs and service startups take
+// for a project and turns that history into "usually takes ~40s" estimates.
+package eta
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+)
+
+// Kind identifies the category of a timed operation.
+type Kind string
+
+const (
+	// KindService is the time from process start until a service is healthy.
+	KindService Kind = "service"
+	// KindDeps is the time taken to install dependencies for a project.
+	KindDeps Kind = "deps"
+)
+
+const (
+	// historyFileName is stored in the project's .azure directory.
+	historyFileName = "timings.json"
+
+	// historyVersion tracks the file schema version.
+	historyVersion = 1
+
+	// maxSamples bounds how many recent durations are kept per entry.
+	// A small window lets estimates follow changes in the stack (new deps, warm caches).
+	maxSamples = 5
+
+	// MinDisplayDuration is the shortest estimate worth showing to users.
+	// Anything faster finishes before the hint would be read.
+	MinDisplayDuration = 3 * time.Second
+)
+
+// historyFile is the on-disk representation of the timing history.
+type historyFile struct {
+	Version int                 `json:"version"`
+	Entries map[string][]Sample `json:"entries"`
+}
+
+// Sample is a single recorded duration.
+type Sample struct {
+	DurationMs int64     `json:"durationMs"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// History holds recorded durations for a single project.
+// All methods are safe for concurrent use.
+type History struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string][]Sample
+	dirty   bool
+}
+
+// Load reads the timing history for projectDir.
+// A missing or unreadable file yields an empty history; timing data is advisory
+// and must never block a command.
+func Load(projectDir string) *History {
+	h := &History{
+		path:    filepath.Join(projectDir, ".azure", historyFileName),
+		entries: make(map[string][]Sample),
+	}
+
+	var data historyFile
+	if err := fileutil.ReadJSON(h.path, &data); err != nil {
+		slog.Debug("ignoring unreadable timing history", "path", h.path, "error", err)
+		return h
+	}
+	if data.Version == historyVersion && data.Entries != nil {
+		h.entries = data.Entries
+	}
+	return h
+}
+
+// Record adds a duration for the named operation.
+// Non-positive durations are ignored.
+func (h *History) Record(kind Kind, name string, d time.Duration) {
+	if d <= 0 || name == "" {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := entryKey(kind, name)
+	samples := append(h.entries[key], Sample{
+		DurationMs: d.Milliseconds(),
+		RecordedAt: time.Now(),
+	})
+	if len(samples) > maxSamples {
+		samples = samples[len(samples)-maxSamples:]
+	}
+	h.entries[key] = samples
+	h.dirty = true
+}
+
+// Estimate returns the median of the recorded durations for the named operation.
+// The second return value is false when no history exists.
+func (h *History) Estimate(kind Kind, name string) (time.Duration, bool) {
+	h.mu.Lock()
+	samples := h.entries[entryKey(kind, name)]
+	values := make([]int64, len(samples))
+	for i, s := range samples {
+		values[i] = s.DurationMs
+	}
+	h.mu.Unlock()
+
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	mid := len(values) / 2
+	median := values[mid]
+	if len(values)%2 == 0 {
+		median = (values[mid-1] + values[mid]) / 2
+	}
+	return time.Duration(median) * time.Millisecond, true
+}
+
+// Save persists the history if it changed since it was loaded.
+func (h *History) Save() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.dirty {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0750); err != nil {
+		return fmt.Errorf("failed to create .azure directory: %w", err)
+	}
+
+	if err := fileutil.AtomicWriteJSON(h.path, historyFile{
+		Version: historyVersion,
+		Entries: h.entries,
+	}); err != nil {
+		return fmt.Errorf("failed to save timing history: %w", err)
+	}
+
+	h.dirty = false
+	return nil
+}
+
+// Hint returns a user-facing hint such as "usually takes ~40s", or an empty
+// string when there is no history or the estimate is too short to be useful.
+func (h *History) Hint(kind Kind, name string) string {
+	d, ok := h.Estimate(kind, name)
+	if !ok || d < MinDisplayDuration {
+		return ""
+	}
+	return "usually takes ~" + FormatDuration(d)
+}
+
+// FormatDuration renders a duration compactly for ETA display ("40s", "2m10s").
+func FormatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Round(time.Second).Seconds()))
+	}
+	d = d.Round(10 * time.Second)
+	minutes := int(d / time.Minute)
+	seconds := int((d % time.Minute) / time.Second)
+	if seconds == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dm%ds", minutes, seconds)
+}
+
+// entryKey builds the map key for an operation.
+func entryKey(kind Kind, name string) string {
+	return string(kind) + ":" + name
+}
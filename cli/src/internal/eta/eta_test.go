@@ -0,0 +1,140 @@
+package eta
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistory_EstimateUsesMedian(t *testing.T) {
+	h := Load(t.TempDir())
+
+	if _, ok := h.Estimate(KindService, "api"); ok {
+		t.Fatal("Estimate() on empty history returned ok=true")
+	}
+
+	h.Record(KindService, "api", 10*time.Second)
+	h.Record(KindService, "api", 40*time.Second)
+	h.Record(KindService, "api", 42*time.Second)
+
+	got, ok := h.Estimate(KindService, "api")
+	if !ok {
+		t.Fatal("Estimate() returned ok=false after recording samples")
+	}
+	if got != 40*time.Second {
+		t.Errorf("Estimate() = %v, want %v", got, 40*time.Second)
+	}
+
+	h.Record(KindService, "api", 50*time.Second)
+	got, _ = h.Estimate(KindService, "api")
+	if got != 41*time.Second {
+		t.Errorf("Estimate() with even samples = %v, want %v", got, 41*time.Second)
+	}
+}
+
+func TestHistory_KindsAreIndependent(t *testing.T) {
+	h := Load(t.TempDir())
+	h.Record(KindDeps, "api", 30*time.Second)
+
+	if _, ok := h.Estimate(KindService, "api"); ok {
+		t.Error("service estimate should not use deps samples")
+	}
+}
+
+func TestHistory_KeepsRecentSamplesOnly(t *testing.T) {
+	h := Load(t.TempDir())
+	for i := 0; i < maxSamples; i++ {
+		h.Record(KindService, "api", time.Second)
+	}
+	for i := 0; i < maxSamples; i++ {
+		h.Record(KindService, "api", time.Minute)
+	}
+
+	got, _ := h.Estimate(KindService, "api")
+	if got != time.Minute {
+		t.Errorf("Estimate() = %v, want old samples to be dropped (%v)", got, time.Minute)
+	}
+}
+
+func TestHistory_IgnoresInvalidSamples(t *testing.T) {
+	h := Load(t.TempDir())
+	h.Record(KindService, "api", 0)
+	h.Record(KindService, "", time.Second)
+
+	if _, ok := h.Estimate(KindService, "api"); ok {
+		t.Error("zero duration should not be recorded")
+	}
+	if h.dirty {
+		t.Error("history should not be dirty after ignored samples")
+	}
+}
+
+func TestHistory_SaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	h := Load(dir)
+	h.Record(KindService, "web", 12*time.Second)
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".azure", historyFileName)); err != nil {
+		t.Fatalf("history file not written: %v", err)
+	}
+
+	reloaded := Load(dir)
+	got, ok := reloaded.Estimate(KindService, "web")
+	if !ok || got != 12*time.Second {
+		t.Errorf("reloaded Estimate() = %v, %v; want %v, true", got, ok, 12*time.Second)
+	}
+}
+
+func TestLoad_CorruptFileYieldsEmptyHistory(t *testing.T) {
+	dir := t.TempDir()
+	azureDir := filepath.Join(dir, ".azure")
+	if err := os.MkdirAll(azureDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(azureDir, historyFileName), []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	h := Load(dir)
+	if _, ok := h.Estimate(KindService, "api"); ok {
+		t.Error("corrupt history should load as empty")
+	}
+}
+
+func TestHistory_Hint(t *testing.T) {
+	h := Load(t.TempDir())
+	h.Record(KindService, "fast", time.Second)
+	h.Record(KindService, "slow", 40*time.Second)
+
+	if got := h.Hint(KindService, "missing"); got != "" {
+		t.Errorf("Hint() without history = %q, want empty", got)
+	}
+	if got := h.Hint(KindService, "fast"); got != "" {
+		t.Errorf("Hint() below threshold = %q, want empty", got)
+	}
+	if got, want := h.Hint(KindService, "slow"), "usually takes ~40s"; got != want {
+		t.Errorf("Hint() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{4 * time.Second, "4s"},
+		{40*time.Second + 400*time.Millisecond, "40s"},
+		{2 * time.Minute, "2m"},
+		{2*time.Minute + 12*time.Second, "2m10s"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.in); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Package eventstream writes the progress of long-running commands as newline-delimited JSON
+// events (--output ndjson), so IDE extensions and wrappers can follow it without parsing
+// the human-readable output.
+package eventstream
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Format is the --output value that enables the event stream.
+const Format = "ndjson"
+
+// Type identifies what an event reports.
+type Type string
+
+// Event types.
+const (
+	TypeServiceStarting Type = "service-starting" // before a service is started
+	TypePortAssigned    Type = "port-assigned"    // when a service's port is known
+	TypeServiceStarted  Type = "service-started"  // after a service has started (not necessarily ready)
+	TypeServiceReady    Type = "service-ready"    // when a started service first passes its health check
+	TypeServiceStopped  Type = "service-stopped"  // after a service was stopped or exited
+	TypeServiceCrashed  Type = "service-crashed"  // when a service exits with an error without being stopped
+	TypeLogLine         Type = "log-line"         // a line a service wrote to stdout or stderr
+	TypeDepsInstalling  Type = "deps-installing"  // before the dependencies of a project are installed
+	TypeDepsInstalled   Type = "deps-installed"   // after the dependencies of a project were installed
+	TypeError           Type = "error"            // a failure, of a service, a project or the command itself
+)
+
+// Event is one line of the event stream. Fields that don't apply to the event type are omitted.
+type Event struct {
+	Event   Type      `json:"event"`
+	Time    time.Time `json:"time"`
+	Service string    `json:"service,omitempty"`
+	Port    int       `json:"port,omitempty"`
+	PID     int       `json:"pid,omitempty"`
+	URL     string    `json:"url,omitempty"`
+
+	// Stream ("stdout" or "stderr"), Level and Message are set for log lines.
+	Stream  string `json:"stream,omitempty"`
+	Level   string `json:"level,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	// Project, Language and PackageManager are set for dependency installation.
+	// UpToDate marks a project that wasn't installed because its lock files haven't changed.
+	Project        string `json:"project,omitempty"`
+	Language       string `json:"language,omitempty"`
+	PackageManager string `json:"packageManager,omitempty"`
+	DurationMs     int64  `json:"durationMs,omitempty"`
+	UpToDate       bool   `json:"upToDate,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+var stream struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Enable writes the events emitted from now on to w.
+func Enable(w io.Writer) {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	stream.w = w
+}
+
+// Disable stops writing events.
+func Disable() {
+	Enable(nil)
+}
+
+// Enabled reports whether events are written.
+func Enabled() bool {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	return stream.w != nil
+}
+
+// Emit writes the event as a single line. It does nothing unless the stream is enabled,
+// so callers don't need to check Enabled first.
+func Emit(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if stream.w == nil {
+		return
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		slog.Debug("failed to marshal event", "event", event.Event, "error", err)
+		return
+	}
+	if _, err := stream.w.Write(append(data, '\n')); err != nil {
+		slog.Debug("failed to write event", "event", event.Event, "error", err)
+	}
+}
+
+// EmitError emits an error event for service, or for the command when service is empty.
+func EmitError(service string, err error) {
+	if err == nil {
+		return
+	}
+	Emit(Event{Event: TypeError, Service: service, Error: err.Error()})
+}
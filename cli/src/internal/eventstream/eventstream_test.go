@@ -0,0 +1,53 @@
+package eventstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEmit(t *testing.T) {
+	var buf bytes.Buffer
+	Enable(&buf)
+	t.Cleanup(Disable)
+
+	Emit(Event{Event: TypePortAssigned, Service: "api", Port: 8000})
+	EmitError("api", errors.New("exit status 1"))
+	EmitError("api", nil)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Emit() wrote %d lines, want 2: %q", len(lines), buf.String())
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("line %q is not JSON: %v", lines[0], err)
+	}
+	if got["event"] != "port-assigned" || got["service"] != "api" || got["port"] != float64(8000) {
+		t.Errorf("event = %v, want port-assigned for api on 8000", got)
+	}
+	if _, ok := got["time"]; !ok {
+		t.Error("event has no time")
+	}
+	if _, ok := got["message"]; ok {
+		t.Error("event has an empty message, want it omitted")
+	}
+
+	if !strings.Contains(lines[1], `"event":"error"`) || !strings.Contains(lines[1], `"error":"exit status 1"`) {
+		t.Errorf("error event = %s", lines[1])
+	}
+}
+
+func TestEmit_Disabled(t *testing.T) {
+	var buf bytes.Buffer
+	Enable(&buf)
+	Disable()
+
+	Emit(Event{Event: TypeServiceStarting, Service: "api"})
+	if Enabled() || buf.Len() != 0 {
+		t.Errorf("Emit() wrote %q while disabled", buf.String())
+	}
+}
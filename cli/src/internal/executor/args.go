@@ -0,0 +1,43 @@
+package executor
+
+import "strings"
+
+// SplitCommand splits a command line into a program and its arguments without invoking a shell.
+// Single or double quotes group text containing spaces, so paths such as
+// "C:\Program Files\nodejs\node.exe" or './my app/server.js' stay a single argument.
+// Backslashes are kept literally (they are path separators on Windows), quotes may appear
+// mid-argument (--dir="my app"), and an explicitly quoted empty string ("") is kept as an argument.
+func SplitCommand(command string) []string {
+	parts := []string{}
+	var current strings.Builder
+	inToken := false
+	quoteChar := rune(0)
+
+	for _, r := range command {
+		switch {
+		case quoteChar != 0:
+			if r == quoteChar {
+				quoteChar = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quoteChar = r
+			inToken = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inToken {
+				parts = append(parts, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if inToken {
+		parts = append(parts, current.String())
+	}
+	return parts
+}
@@ -0,0 +1,32 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "simple", input: "node server.js", want: []string{"node", "server.js"}},
+		{name: "empty", input: "  ", want: []string{}},
+		{name: "double-quoted path with spaces", input: `node "./my app/server.js" --port 3000`, want: []string{"node", "./my app/server.js", "--port", "3000"}},
+		{name: "single-quoted path", input: `python './src dir/main.py'`, want: []string{"python", "./src dir/main.py"}},
+		{name: "windows program files", input: `"C:\Program Files\nodejs\node.exe" .\server.js`, want: []string{`C:\Program Files\nodejs\node.exe`, `.\server.js`}},
+		{name: "non-ascii path", input: `dotnet run --project "./Proyecto Código/Api.csproj"`, want: []string{"dotnet", "run", "--project", "./Proyecto Código/Api.csproj"}},
+		{name: "quote mid-argument", input: `app --dir="my app" -x`, want: []string{"app", "--dir=my app", "-x"}},
+		{name: "empty quoted argument", input: `app --name "" -x`, want: []string{"app", "--name", "", "-x"}},
+		{name: "newlines separate arguments", input: "echo hello\necho world", want: []string{"echo", "hello", "echo", "world"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitCommand(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitCommand(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -10,4 +10,28 @@ const (
 
 	// EnvServiceCount is the number of services defined in azure.yaml
 	EnvServiceCount = "AZD_APP_SERVICE_COUNT"
+
+	// EnvEvent is the service lifecycle event that triggered the hook (e.g. servicestarted)
+	EnvEvent = "AZD_APP_EVENT"
+
+	// EnvServiceName is the name of the service the event is about
+	EnvServiceName = "AZD_APP_SERVICE_NAME"
+
+	// EnvServicePort is the port of the service, if it has one
+	EnvServicePort = "AZD_APP_SERVICE_PORT"
+
+	// EnvServicePID is the process ID of the service, if known
+	EnvServicePID = "AZD_APP_SERVICE_PID"
+
+	// EnvServiceStatus is the status of the service after the event (e.g. running, ready, error)
+	EnvServiceStatus = "AZD_APP_SERVICE_STATUS"
+
+	// EnvServiceHealth is the new health of the service (servicehealthchanged only)
+	EnvServiceHealth = "AZD_APP_SERVICE_HEALTH"
+
+	// EnvServicePreviousHealth is the previous health of the service (servicehealthchanged only)
+	EnvServicePreviousHealth = "AZD_APP_SERVICE_PREVIOUS_HEALTH"
+
+	// EnvServiceError is the start failure, exit error or failed health check, if any
+	EnvServiceError = "AZD_APP_SERVICE_ERROR"
 )
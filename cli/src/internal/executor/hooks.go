@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -65,24 +66,48 @@ func ExecuteHook(ctx context.Context, hookName string, config HookConfig, workin
 // isScriptFilePath checks if the run value appears to be a path to a script file
 // rather than inline commands. This helps determine how to execute the script.
 func isScriptFilePath(script string) bool {
+	_, ok := scriptFilePath(script, "")
+	return ok
+}
+
+// scriptFilePath returns the script file path if the run value is a single path rather than
+// inline commands. Paths containing spaces are recognized when quoted ("./my scripts/setup.sh")
+// or when the unquoted value names an existing file relative to workingDir.
+// The returned path has its quotes removed so it can be passed to the shell as one argument.
+func scriptFilePath(script, workingDir string) (string, bool) {
 	trimmed := strings.TrimSpace(script)
 	if trimmed == "" {
-		return false
-	}
-
-	// Check for common script file patterns (single path, no arguments)
-	// - Starts with ./ or ../ (relative path on POSIX)
-	// - Starts with .\ or ..\ (relative path on Windows)
-	// - Starts with / (absolute path on POSIX)
-	pathPrefixes := []string{"./", "../", "/", ".\\", "..\\"}
-	for _, prefix := range pathPrefixes {
-		if strings.HasPrefix(trimmed, prefix) {
-			// Only treat as file path if it's a single token (no arguments/commands after)
-			parts := strings.Fields(trimmed)
-			return len(parts) == 1
+		return "", false
+	}
+
+	// Only treat as file path if it's a single token (no arguments/commands after)
+	if parts := SplitCommand(trimmed); len(parts) == 1 && hasScriptPathPrefix(parts[0]) {
+		return parts[0], true
+	}
+
+	if !hasScriptPathPrefix(trimmed) || strings.ContainsAny(trimmed, "\r\n") {
+		return "", false
+	}
+	path := trimmed
+	if !filepath.IsAbs(path) && workingDir != "" {
+		path = filepath.Join(workingDir, path)
+	}
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return trimmed, true
+	}
+	return "", false
+}
+
+// hasScriptPathPrefix reports whether value starts like a file path:
+// ./ ../ / (POSIX), .\ ..\ (Windows relative) or a drive letter such as C:\ (Windows absolute).
+func hasScriptPathPrefix(value string) bool {
+	for _, prefix := range []string{"./", "../", "/", ".\\", "..\\"} {
+		if strings.HasPrefix(value, prefix) {
+			return true
 		}
 	}
-	return false
+	return len(value) > 2 && value[1] == ':' && (value[2] == '\\' || value[2] == '/') &&
+		((value[0] >= 'a' && value[0] <= 'z') || (value[0] >= 'A' && value[0] <= 'Z'))
 }
 
 // prepareHookCommand prepares the command based on the shell and script.
@@ -96,15 +121,15 @@ func prepareHookCommand(ctx context.Context, shell, script, workingDir string, e
 
 	// Determine shell arguments based on shell type
 	shellLower := strings.ToLower(shell)
-	isFilePath := isScriptFilePath(script)
+	scriptPath, isFilePath := scriptFilePath(script, workingDir)
 
 	switch {
 	case strings.Contains(shellLower, "pwsh") || strings.Contains(shellLower, "powershell"):
 		// PowerShell handling
-		if isFilePath && strings.HasSuffix(strings.ToLower(strings.TrimSpace(script)), ".ps1") {
+		if isFilePath && strings.HasSuffix(strings.ToLower(scriptPath), ".ps1") {
 			// For .ps1 script files, use -File flag which doesn't require execution policy changes
 			// and handles paths more reliably
-			cmd = exec.CommandContext(ctx, shell, "-File", strings.TrimSpace(script))
+			cmd = exec.CommandContext(ctx, shell, "-File", scriptPath)
 		} else {
 			// For inline commands or non-.ps1 files, use -Command
 			// Set OutputEncoding to UTF-8 to properly display Unicode characters (emojis)
@@ -120,7 +145,7 @@ func prepareHookCommand(ctx context.Context, shell, script, workingDir string, e
 			// For script files, execute directly without -c flag
 			// This allows scripts to run without executable permissions (chmod +x)
 			// e.g., "bash ./scripts/setup.sh" instead of "bash -c './scripts/setup.sh'"
-			cmd = exec.CommandContext(ctx, shell, script)
+			cmd = exec.CommandContext(ctx, shell, scriptPath)
 		} else {
 			// For inline commands, use -c flag
 			cmd = exec.CommandContext(ctx, shell, "-c", script)
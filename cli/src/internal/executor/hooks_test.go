@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -886,3 +887,74 @@ exit 0
 		t.Errorf("Expected script to execute without executable permission, got error: %v", err)
 	}
 }
+
+func TestScriptFilePath_PathsWithSpaces(t *testing.T) {
+	dir := t.TempDir()
+	scriptsDir := filepath.Join(dir, "my scripts")
+	if err := os.MkdirAll(scriptsDir, 0750); err != nil {
+		t.Fatalf("failed to create scripts dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(scriptsDir, "set up.sh"), []byte("exit 0\n"), 0600); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		script   string
+		wantPath string
+		wantOK   bool
+	}{
+		{name: "quoted relative path", script: `"./my scripts/set up.sh"`, wantPath: "./my scripts/set up.sh", wantOK: true},
+		{name: "quoted windows path", script: `'.\my scripts\set up.ps1'`, wantPath: `.\my scripts\set up.ps1`, wantOK: true},
+		{name: "windows drive path", script: `C:\scripts\setup.ps1`, wantPath: `C:\scripts\setup.ps1`, wantOK: true},
+		{name: "unquoted existing path", script: "./my scripts/set up.sh", wantPath: "./my scripts/set up.sh", wantOK: true},
+		{name: "unquoted missing path is a command", script: "./my scripts/missing.sh --flag", wantOK: false},
+		{name: "quoted path with arguments", script: `"./my scripts/set up.sh" --flag`, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := scriptFilePath(tt.script, dir)
+			if ok != tt.wantOK || path != tt.wantPath {
+				t.Errorf("scriptFilePath(%q) = (%q, %v), want (%q, %v)", tt.script, path, ok, tt.wantPath, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestExecuteHook_ScriptInDirectoryWithSpacesAndNonASCII(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shell test")
+	}
+
+	projectDir := filepath.Join(t.TempDir(), "my project", "prøject ü")
+	scriptsDir := filepath.Join(projectDir, "hook scripts")
+	if err := os.MkdirAll(scriptsDir, 0750); err != nil {
+		t.Fatalf("failed to create scripts dir: %v", err)
+	}
+	script := "#!/bin/sh\npwd > marker.txt\n"
+	if err := os.WriteFile(filepath.Join(scriptsDir, "pré run.sh"), []byte(script), 0600); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	for _, run := range []string{`"./hook scripts/pré run.sh"`, "./hook scripts/pré run.sh"} {
+		t.Run(run, func(t *testing.T) {
+			marker := filepath.Join(projectDir, "marker.txt")
+			_ = os.Remove(marker)
+
+			if err := ExecuteHook(context.Background(), "prerun", HookConfig{Run: run, Shell: "sh"}, projectDir); err != nil {
+				t.Fatalf("ExecuteHook() failed: %v", err)
+			}
+
+			data, err := os.ReadFile(marker)
+			if err != nil {
+				t.Fatalf("hook did not run: %v", err)
+			}
+			got, _ := filepath.EvalSymlinks(strings.TrimSpace(string(data)))
+			want, _ := filepath.EvalSymlinks(projectDir)
+			if got != want {
+				t.Errorf("hook ran in %q, want %q", got, want)
+			}
+		})
+	}
+}
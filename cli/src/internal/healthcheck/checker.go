@@ -17,9 +17,11 @@ import (
 
 	"github.com/jongio/azd-app/cli/src/internal/docker"
 	"github.com/jongio/azd-app/cli/src/internal/service" // for GetLogManager (app-specific)
+	"github.com/jongio/azd-app/cli/src/internal/tracing"
 	"github.com/jongio/azd-core/procutil"
 	"github.com/rs/zerolog/log"
 	"github.com/sony/gobreaker"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/time/rate"
 )
 
@@ -137,6 +139,23 @@ func (c *HealthChecker) getOrCreateRateLimiter(serviceName string) *rate.Limiter
 
 // CheckService performs a health check on a single service using cascading strategy.
 func (c *HealthChecker) CheckService(ctx context.Context, svc serviceInfo) HealthCheckResult {
+	ctx, span := tracing.Start(ctx, "health.check", attribute.String("azd.app.service", svc.Name))
+	result := c.checkService(ctx, svc)
+	span.SetAttributes(
+		attribute.String("azd.app.health.status", string(result.Status)),
+		attribute.String("azd.app.health.check_type", string(result.CheckType)),
+	)
+
+	var err error
+	if result.Status == HealthStatusUnhealthy {
+		err = errors.New(result.Error)
+	}
+	tracing.End(span, err)
+	return result
+}
+
+// checkService performs the health check of CheckService.
+func (c *HealthChecker) checkService(ctx context.Context, svc serviceInfo) HealthCheckResult {
 	startTime := time.Now()
 	serviceName := svc.Name
 
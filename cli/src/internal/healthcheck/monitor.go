@@ -370,17 +370,19 @@ func parseHealthCheckConfig(svc service.Service) *healthCheckConfig {
 		}
 	}
 
-	if svc.Healthcheck == nil {
+	// Falls back to the built-in probe for well-known images (redis, postgres, kafka, ...)
+	hc := svc.ResolveHealthcheck()
+	if hc == nil {
 		return nil
 	}
 
 	config := &healthCheckConfig{
 		Retries: 3,
-		Type:    svc.Healthcheck.Type,
-		Pattern: svc.Healthcheck.Pattern,
+		Type:    hc.Type,
+		Pattern: hc.Pattern,
 	}
 
-	switch t := svc.Healthcheck.Test.(type) {
+	switch t := hc.Test.(type) {
 	case string:
 		config.Test = []string{t}
 	case []interface{}:
@@ -393,34 +395,34 @@ func parseHealthCheckConfig(svc service.Service) *healthCheckConfig {
 		config.Test = t
 	}
 
-	if svc.Healthcheck.Type == "none" {
+	if hc.Type == "none" {
 		config.Test = []string{"NONE"}
 	}
 
-	if svc.Healthcheck.Interval != "" {
-		if d, err := time.ParseDuration(svc.Healthcheck.Interval); err == nil {
+	if hc.Interval != "" {
+		if d, err := time.ParseDuration(hc.Interval); err == nil {
 			config.Interval = d
 		}
 	}
 
-	if svc.Healthcheck.Timeout != "" {
-		if d, err := time.ParseDuration(svc.Healthcheck.Timeout); err == nil {
+	if hc.Timeout != "" {
+		if d, err := time.ParseDuration(hc.Timeout); err == nil {
 			config.Timeout = d
 		}
 	}
 
-	if svc.Healthcheck.Retries > 0 {
-		config.Retries = svc.Healthcheck.Retries
+	if hc.Retries > 0 {
+		config.Retries = hc.Retries
 	}
 
-	if svc.Healthcheck.StartPeriod != "" {
-		if d, err := time.ParseDuration(svc.Healthcheck.StartPeriod); err == nil {
+	if hc.StartPeriod != "" {
+		if d, err := time.ParseDuration(hc.StartPeriod); err == nil {
 			config.StartPeriod = d
 		}
 	}
 
-	if svc.Healthcheck.StartInterval != "" {
-		if d, err := time.ParseDuration(svc.Healthcheck.StartInterval); err == nil {
+	if hc.StartInterval != "" {
+		if d, err := time.ParseDuration(hc.StartInterval); err == nil {
 			config.StartInterval = d
 		}
 	}
@@ -824,3 +824,30 @@ func TestTrackFailure_LastSuccessTime(t *testing.T) {
 		t.Error("Expected last success time to be more recent")
 	}
 }
+
+func TestParseHealthCheckConfig_BuiltinProbe(t *testing.T) {
+	svc := service.Service{Image: "bitnami/kafka:3.7"}
+
+	config := parseHealthCheckConfig(svc)
+	if config == nil {
+		t.Fatal("Expected built-in probe config for kafka image")
+	}
+	if len(config.Test) != 2 || config.Test[0] != "CMD-SHELL" {
+		t.Errorf("Expected CMD-SHELL probe, got %v", config.Test)
+	}
+	if config.StartPeriod != 30*time.Second {
+		t.Errorf("Expected start period 30s, got %v", config.StartPeriod)
+	}
+
+	// User-defined healthchecks take precedence over built-in probes
+	svc.Healthcheck = &service.HealthcheckConfig{Test: "http://localhost:8080/health"}
+	config = parseHealthCheckConfig(svc)
+	if config == nil || len(config.Test) != 1 || config.Test[0] != "http://localhost:8080/health" {
+		t.Errorf("Expected user healthcheck to win, got %+v", config)
+	}
+
+	// Unknown images keep the default cascading checks
+	if config := parseHealthCheckConfig(service.Service{Image: "nginx:latest"}); config != nil {
+		t.Errorf("Expected nil config for unknown image, got %+v", config)
+	}
+}
@@ -33,6 +33,9 @@ type ServiceInfo = core.ServiceInfo
 // HealthCheckConfig re-exports the core probe configuration for a single health check.
 type HealthCheckConfig = core.HealthCheckConfig
 
+// HealthProfile re-exports the core named set of health monitoring settings.
+type HealthProfile = core.HealthProfile
+
 // Local aliases for backward compatibility with unexported references.
 type serviceInfo = core.ServiceInfo
 type healthCheckConfig = core.HealthCheckConfig
@@ -0,0 +1,88 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// corepackDownloadPromptEnv disables corepack's interactive "about to download" prompt,
+// which would otherwise hang non-interactive installs the first time a version is fetched.
+const corepackDownloadPromptEnv = "COREPACK_ENABLE_DOWNLOAD_PROMPT=0"
+
+// lookPath is exec.LookPath, replaceable in tests.
+var lookPath = exec.LookPath
+
+// IsCorepackManager reports whether corepack can provide the given package manager.
+func IsCorepackManager(packageManager string) bool {
+	return packageManager == "pnpm" || packageManager == "yarn"
+}
+
+// CorepackAvailable reports whether Node.js and corepack are on PATH.
+func CorepackAvailable() bool {
+	if _, err := lookPath("node"); err != nil {
+		return false
+	}
+	_, err := lookPath("corepack")
+	return err == nil
+}
+
+// UseCorepack reports whether a package manager should be invoked through corepack:
+// it is pnpm or yarn, its global binary is not on PATH, and Node.js with corepack is.
+func UseCorepack(packageManager string) bool {
+	if !IsCorepackManager(packageManager) {
+		return false
+	}
+	if _, err := lookPath(packageManager); err == nil {
+		return false
+	}
+	return CorepackAvailable()
+}
+
+// PackageManagerCommand returns the executable and leading arguments used to invoke a
+// Node.js package manager: ("pnpm", nil) normally, or ("corepack", ["pnpm"]) when the
+// global pnpm binary is missing but corepack can run it.
+func PackageManagerCommand(packageManager string) (string, []string) {
+	if UseCorepack(packageManager) {
+		return "corepack", []string{packageManager}
+	}
+	return packageManager, nil
+}
+
+// EnableCorepack runs `corepack enable <name>` so the package manager's shim is added to
+// PATH, then `corepack prepare <spec> --activate` when spec pins a version (e.g. "pnpm@8.15.0").
+func EnableCorepack(ctx context.Context, spec string) error {
+	name, version, _ := strings.Cut(spec, "@")
+	if !IsCorepackManager(name) {
+		return fmt.Errorf("corepack does not support package manager %q", name)
+	}
+
+	if err := runCorepack(ctx, "enable", name); err != nil {
+		return err
+	}
+	if version == "" {
+		return nil
+	}
+	return runCorepack(ctx, "prepare", spec, "--activate")
+}
+
+// runCorepack runs a corepack subcommand and includes its output in any error.
+func runCorepack(ctx context.Context, args ...string) error {
+	// corepack is a .cmd shim on Windows, so run it through cmd.exe like the package managers.
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", append([]string{"/c", "corepack"}, args...)...)
+	} else {
+		// #nosec G204 -- args are a fixed subcommand plus a package manager validated by IsCorepackManager
+		cmd = exec.CommandContext(ctx, "corepack", args...)
+	}
+	cmd.Env = append(os.Environ(), corepackDownloadPromptEnv)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("corepack %s failed: %w\n%s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package installer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPackageManagerCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		packageManager string
+		onPath         []string
+		wantCommand    string
+		wantArgs       []string
+	}{
+		{
+			name:           "global pnpm",
+			packageManager: "pnpm",
+			onPath:         []string{"node", "corepack", "pnpm"},
+			wantCommand:    "pnpm",
+		},
+		{
+			name:           "pnpm through corepack",
+			packageManager: "pnpm",
+			onPath:         []string{"node", "corepack"},
+			wantCommand:    "corepack",
+			wantArgs:       []string{"pnpm"},
+		},
+		{
+			name:           "yarn through corepack",
+			packageManager: "yarn",
+			onPath:         []string{"node", "corepack"},
+			wantCommand:    "corepack",
+			wantArgs:       []string{"yarn"},
+		},
+		{
+			name:           "no corepack",
+			packageManager: "pnpm",
+			onPath:         []string{"node"},
+			wantCommand:    "pnpm",
+		},
+		{
+			name:           "corepack without node",
+			packageManager: "yarn",
+			onPath:         []string{"corepack"},
+			wantCommand:    "yarn",
+		},
+		{
+			name:           "npm is never routed through corepack",
+			packageManager: "npm",
+			onPath:         []string{"node", "corepack"},
+			wantCommand:    "npm",
+		},
+	}
+
+	original := lookPath
+	defer func() { lookPath = original }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookPath = func(file string) (string, error) {
+				for _, p := range tt.onPath {
+					if p == file {
+						return "/usr/bin/" + file, nil
+					}
+				}
+				return "", errors.New("not found")
+			}
+
+			command, args := PackageManagerCommand(tt.packageManager)
+			if command != tt.wantCommand {
+				t.Errorf("PackageManagerCommand(%q) command = %q, want %q", tt.packageManager, command, tt.wantCommand)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("PackageManagerCommand(%q) args = %v, want %v", tt.packageManager, args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestEnableCorepack_UnsupportedManager(t *testing.T) {
+	if err := EnableCorepack(t.Context(), "npm@10.0.0"); err == nil {
+		t.Error("EnableCorepack() with npm should fail")
+	}
+}
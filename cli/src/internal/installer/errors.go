@@ -1,6 +1,10 @@
 package installer
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // DependencyInstallError represents an error during dependency installation.
 type DependencyInstallError struct {
@@ -39,3 +43,25 @@ func (e *VirtualEnvError) Error() string {
 func (e *VirtualEnvError) Unwrap() error {
 	return e.Err
 }
+
+// TimeoutError represents an installation canceled because it took longer than its timeout.
+type TimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s", e.Timeout)
+}
+
+// Unwrap returns the underlying error.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// IsTimeout reports whether err is an installation that timed out.
+func IsTimeout(err error) bool {
+	var timeoutErr *TimeoutError
+	return errors.As(err, &timeoutErr)
+}
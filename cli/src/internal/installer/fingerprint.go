@@ -0,0 +1,96 @@
+package installer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	corecache "github.com/jongio/azd-core/cache"
+)
+
+// lockFiles lists, per project type, the manifests and lock files that decide which
+// dependencies get installed.
+var lockFiles = map[string][]string{
+	"node":   {"package.json", "package-lock.json", "npm-shrinkwrap.json", "pnpm-lock.yaml", "yarn.lock", "bun.lock", "bun.lockb", "deno.json", "deno.lock"},
+	"python": {"requirements.txt", "pyproject.toml", "poetry.lock", "uv.lock", "Pipfile", "Pipfile.lock", "environment.yml", "environment.yaml"},
+	"dotnet": {"packages.lock.json", "Directory.Packages.props", "Directory.Build.props", "NuGet.config", "nuget.config"},
+	"go":     {"go.mod", "go.sum"},
+	"rust":   {"Cargo.toml", "Cargo.lock"},
+	"java":   {"pom.xml", "build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts", "gradle.lockfile"},
+}
+
+// dotnetProjectExts are the project files whose package references a restore installs.
+var dotnetProjectExts = map[string]bool{".csproj": true, ".fsproj": true, ".vbproj": true, ".sln": true, ".slnx": true}
+
+// Fingerprint returns a hash of the package manager and the manifest and lock files of the
+// project in dir, or "" when the project has none of them and must always be installed.
+func Fingerprint(projectType, dir, manager string) string {
+	files := lockFiles[projectType]
+	if projectType == "dotnet" {
+		if entries, err := os.ReadDir(dir); err == nil {
+			for _, entry := range entries {
+				if !entry.IsDir() && dotnetProjectExts[filepath.Ext(entry.Name())] {
+					files = append(files, entry.Name())
+				}
+			}
+		}
+	}
+
+	h := sha256.New()
+	_, _ = h.Write([]byte(projectType + "\x00" + manager + "\x00"))
+	if projectType == "python" {
+		// A different interpreter needs a new environment
+		if interpreter := pythonInterpreter(dir); interpreter != "" {
+			_, _ = h.Write([]byte("interpreter\x00" + interpreter + "\x00"))
+		}
+	}
+	found := false
+	for _, name := range files {
+		hash, err := corecache.HashFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		found = true
+		_, _ = h.Write([]byte(name + "\x00" + hash + "\x00"))
+	}
+	if !found {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DependenciesPresent reports whether the installed dependencies of the project in dir are
+// still there, so a project whose node_modules or .venv was deleted is installed again.
+func DependenciesPresent(projectType, dir, manager string) bool {
+	var installDir string
+	switch {
+	case projectType == "node":
+		installDir = "node_modules"
+	case projectType == "python" && manager == "conda":
+		installDir = detector.CondaEnvDir
+	case projectType == "python" && manager != "poetry":
+		// Poetry keeps its virtual environments outside the project
+		installDir = ".venv"
+	default:
+		// Go, Cargo, NuGet, Maven and Gradle install into shared caches
+		return true
+	}
+	info, err := os.Stat(filepath.Join(dir, installDir))
+	return err == nil && info.IsDir()
+}
+
+// CacheKey returns the deps cache key of the project in dir.
+// A directory can hold projects of several types, e.g. a Node.js frontend and a Python API.
+func CacheKey(projectType, dir string) string {
+	return projectType + ":" + dir
+}
+
+// taskDir returns the directory of the task's project.
+func taskDir(task ProjectInstallTask) string {
+	if task.Dir == "" && task.Path != "" {
+		return filepath.Dir(task.Path)
+	}
+	return task.Dir
+}
@@ -0,0 +1,99 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	if got := Fingerprint("node", dir, "npm"); got != "" {
+		t.Errorf("Fingerprint() without lock files = %q, want empty", got)
+	}
+
+	writeFile(t, filepath.Join(dir, "package.json"), `{"name":"web"}`)
+	writeFile(t, filepath.Join(dir, "package-lock.json"), `{"lockfileVersion":3}`)
+	first := Fingerprint("node", dir, "npm")
+	if first == "" {
+		t.Fatal("Fingerprint() = empty for a project with lock files")
+	}
+	if got := Fingerprint("node", dir, "npm"); got != first {
+		t.Errorf("Fingerprint() isn't stable: %q, then %q", first, got)
+	}
+	if got := Fingerprint("node", dir, "pnpm"); got == first {
+		t.Error("Fingerprint() didn't change with the package manager")
+	}
+
+	writeFile(t, filepath.Join(dir, "package-lock.json"), `{"lockfileVersion":3,"packages":{}}`)
+	if got := Fingerprint("node", dir, "npm"); got == first {
+		t.Error("Fingerprint() didn't change with the lock file")
+	}
+
+	// .NET projects hash their project files too
+	writeFile(t, filepath.Join(dir, "api.csproj"), "<Project />")
+	dotnet := Fingerprint("dotnet", dir, "dotnet")
+	if dotnet == "" {
+		t.Fatal("Fingerprint() = empty for a .NET project")
+	}
+	writeFile(t, filepath.Join(dir, "api.csproj"), `<Project Sdk="Microsoft.NET.Sdk" />`)
+	if got := Fingerprint("dotnet", dir, "dotnet"); got == dotnet {
+		t.Error("Fingerprint() didn't change with the project file")
+	}
+}
+
+func TestDependenciesPresent(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		projectType, manager string
+		want                 bool
+	}{
+		{"node", "npm", false},
+		{"python", "uv", false},
+		{"python", "poetry", true},
+		{"python", "conda", false},
+		{"go", "go", true},
+	}
+	for _, tt := range tests {
+		if got := DependenciesPresent(tt.projectType, dir, tt.manager); got != tt.want {
+			t.Errorf("DependenciesPresent(%s, %s) = %v, want %v", tt.projectType, tt.manager, got, tt.want)
+		}
+	}
+
+	for _, name := range []string{"node_modules", ".venv", ".conda"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !DependenciesPresent("node", dir, "npm") || !DependenciesPresent("python", dir, "uv") || !DependenciesPresent("python", dir, "conda") {
+		t.Error("DependenciesPresent() = false with node_modules, .venv and .conda")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFingerprint_PythonInterpreter(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "requirements.txt"), "flask\n")
+	t.Cleanup(func() { SetPythonInterpreter(dir, "") })
+
+	before := Fingerprint("python", dir, "pip")
+	SetPythonInterpreter(dir, "python3.12")
+	if got := pythonInterpreter(dir); got != "python3.12" {
+		t.Errorf("pythonInterpreter() = %q, want python3.12", got)
+	}
+	if got := Fingerprint("python", dir, "pip"); got == before {
+		t.Error("Fingerprint() didn't change with the interpreter")
+	}
+
+	SetPythonInterpreter(dir, "")
+	if got := Fingerprint("python", dir, "pip"); got != before {
+		t.Error("Fingerprint() changed after the interpreter was reset")
+	}
+}
@@ -1,4 +1,4 @@
-// Package installer provides dependency installation capabilities for Node.js, Python, and .NET projects.
+// Package installer provides dependency installation capabilities for Node.js, Python, .NET, Go, Rust, and Java projects.
 package installer
 
 import (
@@ -12,9 +12,11 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-app/cli/src/internal/detector"
 	"github.com/jongio/azd-core/cliout"
 	"github.com/jongio/azd-core/pathutil"
 	types "github.com/jongio/azd-core/projecttype"
@@ -22,18 +24,18 @@ import (
 )
 
 // InstallNodeDependencies installs dependencies using the detected package manager.
-func InstallNodeDependencies(project types.NodeProject) error {
-	return installNodeDependenciesWithWriter(project, nil)
+func InstallNodeDependencies(ctx context.Context, project types.NodeProject) error {
+	return installNodeDependenciesWithWriter(ctx, project, nil)
 }
 
 // installNodeDependenciesWithWriter installs dependencies with optional writer for progress tracking.
-func installNodeDependenciesWithWriter(project types.NodeProject, progressWriter io.Writer) error {
+func installNodeDependenciesWithWriter(ctx context.Context, project types.NodeProject, progressWriter io.Writer) error {
 	// Validate inputs
 	if err := security.ValidatePath(project.Dir); err != nil {
 		return fmt.Errorf("invalid project directory: %w", err)
 	}
 
-	if err := security.ValidatePackageManager(project.PackageManager); err != nil {
+	if err := validateNodePackageManager(project.PackageManager); err != nil {
 		return fmt.Errorf("invalid package manager: %w", err)
 	}
 
@@ -74,22 +76,33 @@ func installNodeDependenciesWithWriter(project types.NodeProject, progressWriter
 		}
 	case "pnpm":
 		args = []string{"install", "--prefer-offline"}
-		// If this is a workspace root, use --recursive flag to install all workspace packages
-		if project.IsWorkspaceRoot {
+		// If this is a workspace root, use --recursive flag to install all workspace packages.
+		// An nx monorepo without workspaces installs from the root package.json alone.
+		if project.IsWorkspaceRoot && detector.HasNpmWorkspaces(project.Dir) {
 			args = append(args, "--recursive")
 		}
 	case "yarn":
 		args = []string{"install", "--non-interactive", "--prefer-offline"}
 	default:
+		// bun installs workspace packages from the root without extra flags, and
+		// deno install (Deno 2+) caches the dependencies in deno.json and package.json
 		args = []string{"install"}
 	}
 
+	// Projects pinning pnpm/yarn via the packageManager field often rely on corepack
+	// instead of a global install, so fall back to `corepack <pm>` when the binary is missing.
+	executable, prefixArgs := PackageManagerCommand(project.PackageManager)
+	args = append(prefixArgs, args...)
+	if executable != project.PackageManager && !cliout.IsJSON() && progressWriter == nil {
+		cliout.Item("%s not found on PATH, using corepack %s", project.PackageManager, project.PackageManager)
+	}
+
 	if runtime.GOOS == "windows" {
 		// Use cmd.exe /c to properly invoke .cmd files
-		cmdArgs := append([]string{"/c", project.PackageManager}, args...)
-		cmd = exec.CommandContext(context.Background(), "cmd.exe", cmdArgs...)
+		cmdArgs := append([]string{"/c", executable}, args...)
+		cmd = newCommand(ctx, "cmd.exe", cmdArgs...)
 	} else {
-		cmd = exec.CommandContext(context.Background(), project.PackageManager, args...)
+		cmd = newCommand(ctx, executable, args...)
 	}
 
 	cmd.Dir = project.Dir
@@ -113,6 +126,9 @@ func installNodeDependenciesWithWriter(project types.NodeProject, progressWriter
 	}
 	// Don't set Stdin - we don't want interactive prompts
 	cmd.Env = os.Environ()
+	if executable == "corepack" {
+		cmd.Env = append(cmd.Env, corepackDownloadPromptEnv)
+	}
 
 	// Add NPM_CONFIG_PROGRESS for npm to ensure progress is shown
 	if project.PackageManager == "npm" && progressWriter == nil && !cliout.IsJSON() {
@@ -120,7 +136,7 @@ func installNodeDependenciesWithWriter(project types.NodeProject, progressWriter
 	}
 
 	// Run with retry logic for Windows file locking errors
-	err := runWithRetry(cmd, &stderrBuf, 3)
+	err := runWithRetry(ctx, cmd, &stderrBuf, 3)
 	if err != nil {
 		return formatNodeInstallError(project.PackageManager, project.Dir, cmd, err, stderrBuf.String())
 	}
@@ -132,12 +148,12 @@ func installNodeDependenciesWithWriter(project types.NodeProject, progressWriter
 }
 
 // RestoreDotnetProject runs dotnet restore on a project.
-func RestoreDotnetProject(project types.DotnetProject) error {
-	return restoreDotnetProjectWithWriter(project, nil)
+func RestoreDotnetProject(ctx context.Context, project types.DotnetProject) error {
+	return restoreDotnetProjectWithWriter(ctx, project, nil)
 }
 
 // restoreDotnetProjectWithWriter runs dotnet restore with optional progress writer.
-func restoreDotnetProjectWithWriter(project types.DotnetProject, progressWriter io.Writer) error {
+func restoreDotnetProjectWithWriter(ctx context.Context, project types.DotnetProject, progressWriter io.Writer) error {
 	// Validate path
 	if err := security.ValidatePath(project.Path); err != nil {
 		return fmt.Errorf("invalid project path: %w", err)
@@ -149,7 +165,7 @@ func restoreDotnetProjectWithWriter(project types.DotnetProject, progressWriter
 
 	// Run restore with streaming output
 	dir := filepath.Dir(project.Path)
-	cmd := exec.CommandContext(context.Background(), "dotnet", "restore", project.Path)
+	cmd := newCommand(ctx, "dotnet", "restore", project.Path)
 	cmd.Dir = dir
 
 	// Capture stderr for error reporting
@@ -179,33 +195,180 @@ func restoreDotnetProjectWithWriter(project types.DotnetProject, progressWriter
 	return nil
 }
 
+// DownloadGoModules downloads the modules a Go project depends on.
+func DownloadGoModules(ctx context.Context, project detector.GoProject) error {
+	return downloadGoModulesWithWriter(ctx, project, nil)
+}
+
+// downloadGoModulesWithWriter runs go mod download with optional progress writer.
+func downloadGoModulesWithWriter(ctx context.Context, project detector.GoProject, progressWriter io.Writer) error {
+	if err := security.ValidatePath(project.Dir); err != nil {
+		return fmt.Errorf("invalid project directory: %w", err)
+	}
+
+	if !cliout.IsJSON() && progressWriter == nil {
+		cliout.Item("Downloading modules: %s", project.Dir)
+	}
+
+	// -x lists each download, so progress output isn't silent for large dependency trees
+	cmd := newCommand(ctx, "go", "mod", "download", "-x")
+	cmd.Dir = project.Dir
+
+	// Capture stderr for error reporting
+	var stderrBuf bytes.Buffer
+
+	// Configure output
+	if progressWriter != nil {
+		cmd.Stdout = progressWriter
+		cmd.Stderr = io.MultiWriter(progressWriter, &stderrBuf)
+	} else if cliout.IsJSON() {
+		cmd.Stdout = io.Discard
+		cmd.Stderr = &stderrBuf
+	} else {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = io.MultiWriter(os.Stderr, &stderrBuf)
+	}
+	// Don't set Stdin - we don't want interactive prompts
+	cmd.Env = os.Environ()
+
+	if err := cmd.Run(); err != nil {
+		return formatInstallError("go", project.Dir, cmd, err, stderrBuf.String(), goErrorFormatter(project.Dir))
+	}
+
+	if !cliout.IsJSON() && progressWriter == nil {
+		cliout.ItemSuccess("Downloaded modules")
+	}
+	return nil
+}
+
+// FetchRustDependencies downloads the crates a Rust package or workspace depends on.
+func FetchRustDependencies(ctx context.Context, project detector.RustProject) error {
+	return fetchRustDependenciesWithWriter(ctx, project, nil)
+}
+
+// fetchRustDependenciesWithWriter runs cargo fetch with optional progress writer.
+func fetchRustDependenciesWithWriter(ctx context.Context, project detector.RustProject, progressWriter io.Writer) error {
+	if err := security.ValidatePath(project.Dir); err != nil {
+		return fmt.Errorf("invalid project directory: %w", err)
+	}
+
+	if !cliout.IsJSON() && progressWriter == nil {
+		cliout.Item("Fetching crates: %s", project.Dir)
+	}
+
+	// Run from the package or workspace root so cargo fetch covers every member crate
+	cmd := newCommand(ctx, "cargo", "fetch")
+	cmd.Dir = project.Dir
+
+	// Capture stderr for error reporting
+	var stderrBuf bytes.Buffer
+
+	// Configure output
+	if progressWriter != nil {
+		cmd.Stdout = progressWriter
+		cmd.Stderr = io.MultiWriter(progressWriter, &stderrBuf)
+	} else if cliout.IsJSON() {
+		cmd.Stdout = io.Discard
+		cmd.Stderr = &stderrBuf
+	} else {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = io.MultiWriter(os.Stderr, &stderrBuf)
+	}
+	// Don't set Stdin - we don't want interactive prompts
+	cmd.Env = os.Environ()
+
+	if err := cmd.Run(); err != nil {
+		return formatInstallError("cargo", project.Dir, cmd, err, stderrBuf.String(), rustErrorFormatter(project.Dir))
+	}
+
+	if !cliout.IsJSON() && progressWriter == nil {
+		cliout.ItemSuccess("Fetched crates")
+	}
+	return nil
+}
+
+// ResolveJavaDependencies downloads the dependencies of a Maven or Gradle build.
+func ResolveJavaDependencies(ctx context.Context, project detector.JavaProject) error {
+	return resolveJavaDependenciesWithWriter(ctx, project, nil)
+}
+
+// resolveJavaDependenciesWithWriter runs mvn dependency:resolve or gradle dependencies with optional
+// progress writer, through the project's mvnw/gradlew wrapper when it has one.
+func resolveJavaDependenciesWithWriter(ctx context.Context, project detector.JavaProject, progressWriter io.Writer) error {
+	if err := security.ValidatePath(project.Dir); err != nil {
+		return fmt.Errorf("invalid project directory: %w", err)
+	}
+
+	command, args := detector.JavaBuildCommand(project.Dir, project.BuildTool)
+	if project.BuildTool == detector.BuildToolGradle {
+		args = append(args, "dependencies", "--console=plain")
+	} else {
+		args = append(args, "-B", "dependency:resolve") // -B: batch mode, no interactive prompts or color codes
+	}
+
+	if !cliout.IsJSON() && progressWriter == nil {
+		cliout.Item("Resolving dependencies: %s (%s)", project.Dir, project.BuildTool)
+	}
+
+	cmd := newCommand(ctx, command, args...)
+	cmd.Dir = project.Dir
+
+	// Maven reports build errors on stdout, so capture both streams for error reporting
+	var outputBuf bytes.Buffer
+
+	// Configure output
+	if progressWriter != nil {
+		cmd.Stdout = io.MultiWriter(progressWriter, &outputBuf)
+		cmd.Stderr = io.MultiWriter(progressWriter, &outputBuf)
+	} else if cliout.IsJSON() {
+		cmd.Stdout = &outputBuf
+		cmd.Stderr = &outputBuf
+	} else {
+		cmd.Stdout = io.MultiWriter(os.Stdout, &outputBuf)
+		cmd.Stderr = io.MultiWriter(os.Stderr, &outputBuf)
+	}
+	// Don't set Stdin - we don't want interactive prompts
+	cmd.Env = os.Environ()
+
+	if err := cmd.Run(); err != nil {
+		return formatInstallError(project.BuildTool, project.Dir, cmd, err, outputBuf.String(), javaErrorFormatter(project.Dir, project.BuildTool))
+	}
+
+	if !cliout.IsJSON() && progressWriter == nil {
+		cliout.ItemSuccess("Resolved dependencies")
+	}
+	return nil
+}
+
 // SetupPythonVirtualEnv creates a virtual environment and installs dependencies.
-func SetupPythonVirtualEnv(project types.PythonProject) error {
-	return setupPythonVirtualEnvWithWriter(project, nil)
+func SetupPythonVirtualEnv(ctx context.Context, project types.PythonProject) error {
+	return setupPythonVirtualEnvWithWriter(ctx, project, nil)
 }
 
 // setupPythonVirtualEnvWithWriter creates a virtual environment with optional progress writer.
-func setupPythonVirtualEnvWithWriter(project types.PythonProject, progressWriter io.Writer) error {
+func setupPythonVirtualEnvWithWriter(ctx context.Context, project types.PythonProject, progressWriter io.Writer) error {
 	switch project.PackageManager {
 	case "uv":
-		return setupWithUv(project.Dir, progressWriter)
+		return setupWithUv(ctx, project.Dir, progressWriter)
+	case "conda":
+		return setupWithConda(ctx, project.Dir, progressWriter)
 	case "poetry":
-		return setupWithPoetry(project.Dir, progressWriter)
+		return setupWithPoetry(ctx, project.Dir, progressWriter)
 	case "pip":
-		return setupWithPip(project.Dir, progressWriter)
+		return setupWithPip(ctx, project.Dir, progressWriter)
 	default:
 		return fmt.Errorf("unknown package manager '%s' for Python project in %s", project.PackageManager, project.Dir)
 	}
 }
 
 // setupWithUv sets up a Python project using uv.
-func setupWithUv(projectDir string, progressWriter io.Writer) error {
+func setupWithUv(ctx context.Context, projectDir string, progressWriter io.Writer) error {
 	// Check if uv is installed
 	if _, err := exec.LookPath("uv"); err != nil {
 		if !cliout.IsJSON() && progressWriter == nil {
 			cliout.ItemWarning("uv not found, falling back to pip")
 		}
-		return setupWithPip(projectDir, progressWriter)
+		return setupWithPip(ctx, projectDir, progressWriter)
 	}
 
 	// uv automatically manages virtual environments
@@ -214,7 +377,11 @@ func setupWithUv(projectDir string, progressWriter io.Writer) error {
 		cliout.Item("Installing dependencies into .venv (uv)...")
 	}
 
-	cmd := exec.CommandContext(context.Background(), "uv", "sync", "--no-progress")
+	args := []string{"sync", "--no-progress"}
+	if interpreter := pythonInterpreter(projectDir); interpreter != "" {
+		args = append(args, "--python", interpreter)
+	}
+	cmd := newCommand(ctx, "uv", args...)
 	cmd.Dir = projectDir
 	cmd.Env = os.Environ() // Inherit azd context (AZD_SERVER, AZD_ACCESS_TOKEN, AZURE_*)
 
@@ -237,7 +404,11 @@ func setupWithUv(projectDir string, progressWriter io.Writer) error {
 			if !cliout.IsJSON() && progressWriter == nil {
 				cliout.Item("Creating virtual environment at .venv (uv)...")
 			}
-			venvCmd := exec.CommandContext(context.Background(), "uv", "venv")
+			venvArgs := []string{"venv"}
+			if interpreter := pythonInterpreter(projectDir); interpreter != "" {
+				venvArgs = append(venvArgs, "--python", interpreter)
+			}
+			venvCmd := newCommand(ctx, "uv", venvArgs...)
 			venvCmd.Dir = projectDir
 			venvCmd.Env = os.Environ() // Inherit azd context (AZD_SERVER, AZD_ACCESS_TOKEN, AZURE_*)
 
@@ -261,7 +432,7 @@ func setupWithUv(projectDir string, progressWriter io.Writer) error {
 			if !cliout.IsJSON() && progressWriter == nil {
 				cliout.Item("Installing dependencies into .venv (uv pip)...")
 			}
-			installCmd := exec.CommandContext(context.Background(), "uv", "pip", "install", "-r", "requirements.txt", "--no-progress")
+			installCmd := newCommand(ctx, "uv", "pip", "install", "-r", "requirements.txt", "--no-progress")
 			installCmd.Dir = projectDir
 			installCmd.Env = os.Environ() // Inherit azd context (AZD_SERVER, AZD_ACCESS_TOKEN, AZURE_*)
 
@@ -292,17 +463,27 @@ func setupWithUv(projectDir string, progressWriter io.Writer) error {
 }
 
 // setupWithPoetry sets up a Python project using poetry.
-func setupWithPoetry(projectDir string, progressWriter io.Writer) error {
+func setupWithPoetry(ctx context.Context, projectDir string, progressWriter io.Writer) error {
 	// Check if poetry is installed
 	if _, err := exec.LookPath("poetry"); err != nil {
 		if !cliout.IsJSON() && progressWriter == nil {
 			cliout.ItemWarning("poetry not found, falling back to pip")
 		}
-		return setupWithPip(projectDir, progressWriter)
+		return setupWithPip(ctx, projectDir, progressWriter)
+	}
+
+	// Point poetry at the service's interpreter before it looks for an environment
+	if interpreter := pythonInterpreter(projectDir); interpreter != "" {
+		useCmd := newCommand(ctx, "poetry", "env", "use", interpreter)
+		useCmd.Dir = projectDir
+		useCmd.Env = os.Environ() // Inherit azd context (AZD_SERVER, AZD_ACCESS_TOKEN, AZURE_*)
+		if output, err := useCmd.CombinedOutput(); err != nil {
+			return formatPythonInstallError("poetry env use", projectDir, useCmd, err, string(output))
+		}
 	}
 
 	// Check if virtual environment exists
-	checkCmd := exec.CommandContext(context.Background(), "poetry", "env", "info", "--path")
+	checkCmd := newCommand(ctx, "poetry", "env", "info", "--path")
 	checkCmd.Dir = projectDir
 	checkCmd.Env = os.Environ() // Inherit azd context (AZD_SERVER, AZD_ACCESS_TOKEN, AZURE_*)
 	cmdOutput, err := checkCmd.CombinedOutput()
@@ -320,7 +501,7 @@ func setupWithPoetry(projectDir string, progressWriter io.Writer) error {
 	}
 
 	// Install dependencies (use --no-root to avoid installing the package itself)
-	cmd := exec.CommandContext(context.Background(), "poetry", "install", "--no-root")
+	cmd := newCommand(ctx, "poetry", "install", "--no-root")
 	cmd.Dir = projectDir
 	cmd.Env = os.Environ() // Inherit azd context (AZD_SERVER, AZD_ACCESS_TOKEN, AZURE_*)
 
@@ -347,7 +528,7 @@ func setupWithPoetry(projectDir string, progressWriter io.Writer) error {
 }
 
 // setupWithPip sets up a Python project using pip and venv.
-func setupWithPip(projectDir string, progressWriter io.Writer) error {
+func setupWithPip(ctx context.Context, projectDir string, progressWriter io.Writer) error {
 	venvPath := filepath.Join(projectDir, ".venv")
 
 	// Check if venv already exists, create if not
@@ -357,7 +538,11 @@ func setupWithPip(projectDir string, progressWriter io.Writer) error {
 		}
 
 		// Create virtual environment
-		cmd := exec.CommandContext(context.Background(), "python", "-m", "venv", ".venv")
+		python := pythonInterpreter(projectDir)
+		if python == "" {
+			python = "python"
+		}
+		cmd := newCommand(ctx, python, "-m", "venv", ".venv")
 		cmd.Dir = projectDir
 		cmd.Env = os.Environ() // Inherit azd context (AZD_SERVER, AZD_ACCESS_TOKEN, AZURE_*)
 
@@ -397,7 +582,7 @@ func setupWithPip(projectDir string, progressWriter io.Writer) error {
 		}
 
 		// Run pip install with streaming output and optimizations
-		pipCmd := exec.CommandContext(context.Background(), pipPath, "install", "-r", "requirements.txt", "--disable-pip-version-check", "--prefer-binary")
+		pipCmd := newCommand(ctx, pipPath, "install", "-r", "requirements.txt", "--disable-pip-version-check", "--prefer-binary")
 		pipCmd.Dir = projectDir
 
 		var stderrBuf bytes.Buffer
@@ -426,6 +611,98 @@ func setupWithPip(projectDir string, progressWriter io.Writer) error {
 	return nil
 }
 
+// setupWithConda sets up a Python project from its conda environment file, in a conda
+// environment at .conda in the project.
+func setupWithConda(ctx context.Context, projectDir string, progressWriter io.Writer) error {
+	// Check if conda is installed
+	if _, err := exec.LookPath("conda"); err != nil {
+		if !cliout.IsJSON() && progressWriter == nil {
+			cliout.ItemWarning("conda not found, falling back to pip")
+		}
+		return setupWithPip(ctx, projectDir, progressWriter)
+	}
+
+	envFile := detector.CondaEnvironmentFile(projectDir)
+	if envFile == "" {
+		return fmt.Errorf("no conda environment file (environment.yml) found in %s", projectDir)
+	}
+
+	// Create the environment, or bring an existing one in line with the environment file
+	args := []string{"env", "update", "--prefix", detector.CondaEnvDir, "--file", envFile, "--prune"}
+	tool := "conda env update"
+	if _, err := os.Stat(filepath.Join(projectDir, detector.CondaEnvDir)); err != nil {
+		args = []string{"env", "create", "--prefix", detector.CondaEnvDir, "--file", envFile, "--yes"}
+		tool = "conda env create"
+	}
+	if !cliout.IsJSON() && progressWriter == nil {
+		cliout.Item("Installing dependencies into %s (conda)...", detector.CondaEnvDir)
+	}
+
+	cmd := newCommand(ctx, "conda", args...)
+	cmd.Dir = projectDir
+	cmd.Env = os.Environ() // Inherit azd context (AZD_SERVER, AZD_ACCESS_TOKEN, AZURE_*)
+
+	var stderrBuf bytes.Buffer
+	if progressWriter != nil {
+		cmd.Stdout = progressWriter
+		cmd.Stderr = io.MultiWriter(progressWriter, &stderrBuf)
+	} else if cliout.IsJSON() {
+		cmd.Stdout = io.Discard
+		cmd.Stderr = &stderrBuf
+	} else {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = io.MultiWriter(os.Stderr, &stderrBuf)
+	}
+
+	if err := cmd.Run(); err != nil {
+		return formatPythonInstallError(tool, projectDir, cmd, err, stderrBuf.String())
+	}
+
+	if !cliout.IsJSON() && progressWriter == nil {
+		cliout.ItemSuccess("Environment ready (conda)")
+	}
+	return nil
+}
+
+// pythonInterpreters holds the python.interpreter of each Python project, by absolute directory.
+var pythonInterpreters sync.Map
+
+// SetPythonInterpreter sets the interpreter that creates the virtual environment of the Python
+// project in dir, as set by python.interpreter in azure.yaml. An empty interpreter restores
+// the default: python on PATH, or whichever interpreter uv and poetry pick.
+func SetPythonInterpreter(dir, interpreter string) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		absDir = dir
+	}
+	if interpreter == "" {
+		pythonInterpreters.Delete(absDir)
+		return
+	}
+	pythonInterpreters.Store(absDir, detector.ResolvePythonInterpreter(absDir, interpreter))
+}
+
+// pythonInterpreter returns the interpreter set for the Python project in dir, or "" for the default.
+func pythonInterpreter(dir string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		absDir = dir
+	}
+	if interpreter, ok := pythonInterpreters.Load(absDir); ok {
+		return interpreter.(string)
+	}
+	return ""
+}
+
+// validateNodePackageManager validates a Node.js package manager. bun and deno aren't in the
+// shared allow list because they are runtimes as well as package managers.
+func validateNodePackageManager(packageManager string) error {
+	if packageManager == "bun" || packageManager == "deno" {
+		return nil
+	}
+	return security.ValidatePackageManager(packageManager)
+}
+
 // isDependenciesUpToDate checks if node_modules is up-to-date with the lock file
 func isDependenciesUpToDate(projectDir string, packageManager string) bool {
 	nodeModulesPath := filepath.Join(projectDir, "node_modules")
@@ -445,6 +722,13 @@ func isDependenciesUpToDate(projectDir string, packageManager string) bool {
 		lockFile = "yarn.lock"
 		// Yarn doesn't use an internal lock file in node_modules
 		internalLockFile = ""
+	case "bun":
+		// Bun 1.2+ writes a text bun.lock; older versions write the binary bun.lockb
+		lockFile = "bun.lock"
+		if _, err := os.Stat(filepath.Join(projectDir, "bun.lockb")); err == nil {
+			lockFile = "bun.lockb"
+		}
+		internalLockFile = ""
 	default:
 		return false
 	}
@@ -597,6 +881,97 @@ func dotnetErrorFormatter(projectPath, dir string) errorFormatter {
 	}
 }
 
+// goErrorFormatter provides Go-specific error formatting
+func goErrorFormatter(dir string) errorFormatter {
+	return errorFormatter{
+		baseMessage: func(tool string) string {
+			return "failed to download Go modules"
+		},
+		exitCodeContext: func(tool string, exitCode int) string {
+			if exitCode == 127 {
+				return " (go not found - please install Go)"
+			} else if exitCode != 0 {
+				return fmt.Sprintf(" (exit code %d)", exitCode)
+			}
+			return ""
+		},
+		suggestion: func(tool string, exitCode int, stderr string) string {
+			if strings.Contains(stderr, "missing go.sum entry") {
+				return "Run 'go mod tidy' to update go.sum"
+			}
+			return ""
+		},
+		contextFields: func() string {
+			return fmt.Sprintf("\n   Directory: %s", dir)
+		},
+	}
+}
+
+func rustErrorFormatter(dir string) errorFormatter {
+	return errorFormatter{
+		baseMessage: func(tool string) string {
+			return "failed to fetch Rust crates"
+		},
+		exitCodeContext: func(tool string, exitCode int) string {
+			if exitCode == 127 {
+				return " (cargo not found - please install Rust from https://rustup.rs/)"
+			} else if exitCode != 0 {
+				return fmt.Sprintf(" (exit code %d)", exitCode)
+			}
+			return ""
+		},
+		suggestion: func(tool string, exitCode int, stderr string) string {
+			switch {
+			case strings.Contains(stderr, "failed to select a version"):
+				return "Check the dependency versions in Cargo.toml, or run 'cargo update' to refresh Cargo.lock"
+			case strings.Contains(stderr, "failed to load manifest for workspace member"):
+				return "Check the members listed in the [workspace] section of Cargo.toml"
+			}
+			return ""
+		},
+		contextFields: func() string {
+			return fmt.Sprintf("\n   Directory: %s", dir)
+		},
+	}
+}
+
+func javaErrorFormatter(dir, buildTool string) errorFormatter {
+	return errorFormatter{
+		baseMessage: func(tool string) string {
+			return fmt.Sprintf("failed to resolve %s dependencies", tool)
+		},
+		exitCodeContext: func(tool string, exitCode int) string {
+			if exitCode == 127 {
+				command, _ := detector.JavaBuildCommand(dir, buildTool)
+				return fmt.Sprintf(" (%s not found - install it, or add the %s wrapper to the project)", command, wrapperName(buildTool))
+			} else if exitCode != 0 {
+				return fmt.Sprintf(" (exit code %d)", exitCode)
+			}
+			return ""
+		},
+		suggestion: func(tool string, exitCode int, stderr string) string {
+			switch {
+			case strings.Contains(stderr, "JAVA_HOME"):
+				return "Set JAVA_HOME to a JDK installation"
+			case strings.Contains(stderr, "Could not resolve dependencies"), strings.Contains(stderr, "Could not resolve all"):
+				return "Check your network connection and repository settings, then retry"
+			}
+			return ""
+		},
+		contextFields: func() string {
+			return fmt.Sprintf("\n   Directory: %s", dir)
+		},
+	}
+}
+
+// wrapperName returns the wrapper script name for a Java build tool.
+func wrapperName(buildTool string) string {
+	if buildTool == detector.BuildToolGradle {
+		return "gradlew"
+	}
+	return "mvnw"
+}
+
 // formatDotnetRestoreError creates a detailed error message for dotnet restore failures
 func formatDotnetRestoreError(projectPath, dir string, cmd *exec.Cmd, cmdErr error, stderr string) error {
 	return formatInstallError("dotnet", dir, cmd, cmdErr, stderr, dotnetErrorFormatter(projectPath, dir))
@@ -757,6 +1132,8 @@ func getPythonSuggestion(tool string, exitCode int, stderr string) string {
 		toolName := tool
 		if strings.Contains(tool, "uv ") {
 			toolName = "uv"
+		} else if strings.Contains(tool, "conda ") {
+			toolName = "conda"
 		} else if strings.Contains(tool, "poetry ") {
 			toolName = "poetry"
 		} else if strings.Contains(tool, "python ") || strings.Contains(tool, "-m venv") {
@@ -785,10 +1162,21 @@ func getPythonSuggestion(tool string, exitCode int, stderr string) string {
 	return ""
 }
 
+// commandWaitDelay bounds how long a canceled install waits for its output to be closed,
+// since package managers may leave child processes behind that still hold it.
+const commandWaitDelay = 5 * time.Second
+
+// newCommand returns a command that is killed when ctx is canceled, e.g. when its install times out.
+func newCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = commandWaitDelay
+	return cmd
+}
+
 // runWithRetry executes a command with retry logic for Windows file locking errors.
 // This is a safety net for race conditions in npm workspaces on Windows where
 // concurrent npm processes may compete for the same files.
-func runWithRetry(cmd *exec.Cmd, stderrBuf *bytes.Buffer, maxRetries int) error {
+func runWithRetry(ctx context.Context, cmd *exec.Cmd, stderrBuf *bytes.Buffer, maxRetries int) error {
 	var lastErr error
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
@@ -818,13 +1206,17 @@ func runWithRetry(cmd *exec.Cmd, stderrBuf *bytes.Buffer, maxRetries int) error
 			if !cliout.IsJSON() {
 				cliout.ItemWarning("File locking error detected, retrying in %v... (attempt %d/%d)", delay, attempt, maxRetries)
 			}
-			time.Sleep(delay)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 
 			// Reset stderr buffer for next attempt
 			stderrBuf.Reset()
 
 			// Recreate the command for the next attempt (exec.Cmd can only be run once)
-			newCmd := exec.CommandContext(context.Background(), cmd.Path, cmd.Args[1:]...)
+			newCmd := newCommand(ctx, cmd.Path, cmd.Args[1:]...)
 			newCmd.Dir = cmd.Dir
 			newCmd.Env = cmd.Env
 			newCmd.Stdout = cmd.Stdout
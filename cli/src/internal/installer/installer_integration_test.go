@@ -3,6 +3,7 @@
 package installer
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -91,7 +92,7 @@ func TestInstallNodeDependenciesIntegration(t *testing.T) {
 			tempDir := t.TempDir()
 			tt.setupFunc(t, tempDir)
 
-			err := InstallNodeDependencies(types.NodeProject{
+			err := InstallNodeDependencies(context.Background(), types.NodeProject{
 				Dir:            tempDir,
 				PackageManager: tt.packageManager,
 			})
@@ -128,7 +129,7 @@ func TestRestoreDotnetProjectIntegration(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err := RestoreDotnetProject(types.DotnetProject{
+	err := RestoreDotnetProject(context.Background(), types.DotnetProject{
 		Path: csprojPath,
 	})
 	if err != nil {
@@ -217,7 +218,7 @@ requests = "^2.31.0"
 			tempDir := t.TempDir()
 			tt.setupFunc(t, tempDir)
 
-			err := SetupPythonVirtualEnv(types.PythonProject{
+			err := SetupPythonVirtualEnv(context.Background(), types.PythonProject{
 				Dir:            tempDir,
 				PackageManager: tt.packageManager,
 			})
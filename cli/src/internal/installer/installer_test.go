@@ -3,12 +3,15 @@ package installer
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/jongio/azd-app/cli/src/internal/detector"
 	types "github.com/jongio/azd-core/projecttype"
 )
 
@@ -73,7 +76,7 @@ func TestInstallNodeDependencies(t *testing.T) {
 				t.Skip("Skipping actual package manager execution in unit tests")
 			}
 
-			err := InstallNodeDependencies(tt.project)
+			err := InstallNodeDependencies(context.Background(), tt.project)
 			if tt.expectError && err == nil {
 				t.Error("expected error but got nil")
 			}
@@ -205,7 +208,7 @@ func TestSetupPythonVirtualEnv(t *testing.T) {
 			if tt.skipRealInstall {
 				// For unknown package manager, we want to test the error path
 				if tt.project.PackageManager == "unknown" {
-					err := SetupPythonVirtualEnv(tt.project)
+					err := SetupPythonVirtualEnv(context.Background(), tt.project)
 					if err == nil {
 						t.Error("expected error for unknown package manager")
 					}
@@ -215,7 +218,7 @@ func TestSetupPythonVirtualEnv(t *testing.T) {
 				t.Skip("Skipping actual Python environment setup in unit tests")
 			}
 
-			err = SetupPythonVirtualEnv(tt.project)
+			err = SetupPythonVirtualEnv(context.Background(), tt.project)
 			if tt.expectError && err == nil {
 				t.Error("expected error but got nil")
 			}
@@ -297,7 +300,7 @@ func TestInstallNodeDependencies_InvalidPath(t *testing.T) {
 		PackageManager: "npm",
 	}
 
-	err := InstallNodeDependencies(project)
+	err := InstallNodeDependencies(context.Background(), project)
 	if err == nil {
 		t.Error("expected error for invalid path")
 	}
@@ -311,18 +314,29 @@ func TestInstallNodeDependencies_InvalidPackageManager(t *testing.T) {
 		PackageManager: "invalid-pm; rm -rf /",
 	}
 
-	err := InstallNodeDependencies(project)
+	err := InstallNodeDependencies(context.Background(), project)
 	if err == nil {
 		t.Error("expected error for invalid package manager")
 	}
 }
 
+func TestValidateNodePackageManager(t *testing.T) {
+	for _, pm := range []string{"npm", "pnpm", "yarn", "bun", "deno"} {
+		if err := validateNodePackageManager(pm); err != nil {
+			t.Errorf("validateNodePackageManager(%q) error = %v", pm, err)
+		}
+	}
+	if err := validateNodePackageManager("bun; rm -rf /"); err == nil {
+		t.Error("validateNodePackageManager() should reject an unknown package manager")
+	}
+}
+
 func TestRestoreDotnetProject_InvalidPath(t *testing.T) {
 	project := types.DotnetProject{
 		Path: "../../../invalid/path.csproj",
 	}
 
-	err := RestoreDotnetProject(project)
+	err := RestoreDotnetProject(context.Background(), project)
 	if err == nil {
 		t.Error("expected error for invalid path")
 	}
@@ -336,7 +350,7 @@ func TestSetupPythonVirtualEnv_UnknownPackageManager(t *testing.T) {
 		PackageManager: "unknown-manager",
 	}
 
-	err := SetupPythonVirtualEnv(project)
+	err := SetupPythonVirtualEnv(context.Background(), project)
 	if err == nil {
 		t.Error("expected error for unknown package manager")
 	}
@@ -357,7 +371,7 @@ func TestSetupWithPip_ExistingVenv(t *testing.T) {
 	}
 
 	// Should return nil when venv exists
-	err := setupWithPip(tmpDir, nil)
+	err := setupWithPip(context.Background(), tmpDir, nil)
 	if err != nil {
 		t.Errorf("setupWithPip() with existing venv should not error: %v", err)
 	}
@@ -372,7 +386,7 @@ func TestSetupWithPip_NoRequirementsTxt(t *testing.T) {
 
 	// Try to create venv without requirements.txt
 	// This will succeed if python is available
-	err := setupWithPip(tmpDir, nil)
+	err := setupWithPip(context.Background(), tmpDir, nil)
 
 	// We don't assert success/failure as it depends on python availability
 	// Just verify it doesn't panic
@@ -388,7 +402,7 @@ func TestSetupWithPoetry_EnvExists(t *testing.T) {
 
 	// This tests the path where poetry env info succeeds
 	// In practice, this requires poetry to be installed
-	err := setupWithPoetry(tmpDir, nil)
+	err := setupWithPoetry(context.Background(), tmpDir, nil)
 
 	// We expect this to either succeed or fallback to pip
 	// Just verify it doesn't panic
@@ -409,7 +423,7 @@ func TestSetupWithUv_NoUvInstalled(t *testing.T) {
 	}
 
 	// This will fallback to pip if uv is not installed
-	err := setupWithUv(tmpDir, nil)
+	err := setupWithUv(context.Background(), tmpDir, nil)
 
 	// We don't assert success/failure as it depends on tool availability
 	// Just verify it doesn't panic
@@ -479,6 +493,36 @@ func TestIsDependenciesUpToDate(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name:           "bun_binary_lock_up_to_date",
+			packageManager: "bun",
+			setupFunc: func(tmpDir string) error {
+				if err := os.WriteFile(filepath.Join(tmpDir, "bun.lockb"), []byte(""), 0600); err != nil {
+					return err
+				}
+				return os.MkdirAll(filepath.Join(tmpDir, "node_modules"), 0750)
+			},
+			want: true,
+		},
+		{
+			name:           "bun_text_lock_up_to_date",
+			packageManager: "bun",
+			setupFunc: func(tmpDir string) error {
+				if err := os.WriteFile(filepath.Join(tmpDir, "bun.lock"), []byte("{}"), 0600); err != nil {
+					return err
+				}
+				return os.MkdirAll(filepath.Join(tmpDir, "node_modules"), 0750)
+			},
+			want: true,
+		},
+		{
+			name:           "deno_always_installs",
+			packageManager: "deno",
+			setupFunc: func(tmpDir string) error {
+				return os.MkdirAll(filepath.Join(tmpDir, "node_modules"), 0750)
+			},
+			want: false,
+		},
 		{
 			name:           "unknown_package_manager",
 			packageManager: "unknown",
@@ -762,6 +806,53 @@ func TestFormatDotnetRestoreError(t *testing.T) {
 	}
 }
 
+func TestResolveJavaDependencies_Wrapper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script wrapper")
+	}
+
+	// A wrapper without its executable bit, as when checked out from Windows, runs through sh
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args.txt")
+	if err := os.WriteFile(filepath.Join(dir, "pom.xml"), []byte("<project/>"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	wrapper := "echo \"$@\" > " + argsFile + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "mvnw"), []byte(wrapper), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	project := detector.JavaProject{Dir: dir, BuildTool: detector.BuildToolMaven}
+	if err := resolveJavaDependenciesWithWriter(context.Background(), project, io.Discard); err != nil {
+		t.Fatalf("resolveJavaDependenciesWithWriter() error = %v", err)
+	}
+
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("mvnw wasn't run: %v", err)
+	}
+	if got := strings.TrimSpace(string(args)); got != "-B dependency:resolve" {
+		t.Errorf("mvnw args = %q, want %q", got, "-B dependency:resolve")
+	}
+}
+
+func TestJavaErrorFormatter(t *testing.T) {
+	cmd := exec.CommandContext(context.Background(), "mvn", "-B", "dependency:resolve")
+	output := "[ERROR] Failed to execute goal: Could not resolve dependencies for project com.example:api"
+
+	err := formatInstallError("maven", "/test/api", cmd, fmt.Errorf("exit status 1"), output, javaErrorFormatter("/test/api", detector.BuildToolMaven))
+	for _, want := range []string{"failed to resolve maven dependencies", "exit code 1", "Check your network connection", "Directory: /test/api"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q:\n%s", want, err.Error())
+		}
+	}
+
+	err = formatInstallError("gradle", "/test/api", cmd, fmt.Errorf("exit status 127"), "", javaErrorFormatter("/test/api", detector.BuildToolGradle))
+	if !strings.Contains(err.Error(), "gradle not found") || !strings.Contains(err.Error(), "gradlew wrapper") {
+		t.Errorf("error should explain gradle is missing:\n%s", err.Error())
+	}
+}
+
 func TestInstallNodeDependencies_UpToDate(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -792,7 +883,7 @@ func TestInstallNodeDependencies_UpToDate(t *testing.T) {
 	}
 
 	// Should skip install since dependencies are up-to-date
-	err := InstallNodeDependencies(project)
+	err := InstallNodeDependencies(context.Background(), project)
 	if err != nil {
 		t.Errorf("InstallNodeDependencies() with up-to-date deps failed: %v", err)
 	}
@@ -853,7 +944,7 @@ func TestInstallNodeDependencies_Workspace(t *testing.T) {
 			// Test that the function constructs the right arguments
 			// Since we can't easily test command execution without the actual tool,
 			// we'll just verify it doesn't panic with invalid input
-			err := InstallNodeDependencies(project)
+			err := InstallNodeDependencies(context.Background(), project)
 			// Expect error since package manager likely not installed or will fail
 			// The key is it doesn't panic
 			t.Logf("%s workspace install result: %v", tt.packageManager, err)
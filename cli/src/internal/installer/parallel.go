@@ -2,12 +2,18 @@ package installer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
+	"github.com/jongio/azd-app/cli/src/internal/cache"
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/eta"
+	"github.com/jongio/azd-app/cli/src/internal/eventstream"
 	"github.com/jongio/azd-core/cliout"
 	"github.com/jongio/azd-core/progress"
 	types "github.com/jongio/azd-core/projecttype"
@@ -31,15 +37,26 @@ type ParallelInstaller struct {
 	mu          sync.Mutex
 	results     []ProjectInstallResult
 	statusLines []progress.StatusLine
-	Verbose     bool            // Show full installation output
-	ctx         context.Context // Context for cancellation
+	Verbose     bool         // Show full installation output
+	History     *eta.History // Optional install duration history for ETA hints
+	// MaxConcurrency limits how many non-pnpm installs run at once (0 = unlimited).
+	MaxConcurrency int
+	// Cache skips the projects whose lock files haven't changed since their last install (optional).
+	Cache *cache.DepsCache
+	// Force installs every project, even the ones Cache reports as up to date.
+	Force bool
+	// Timeout cancels an install that takes longer (0 = no limit).
+	Timeout time.Duration
+	ctx     context.Context // Context for cancellation
 }
 
 // ProjectInstallResult represents the result of a project installation.
 type ProjectInstallResult struct {
-	Task    ProjectInstallTask
-	Success bool
-	Error   error
+	Task     ProjectInstallTask
+	Success  bool
+	UpToDate bool // Installation was skipped because the lock files haven't changed
+	TimedOut bool // Installation was canceled after Timeout
+	Error    error
 }
 
 // NewParallelInstaller creates a new parallel installer.
@@ -111,33 +128,163 @@ func (pi *ParallelInstaller) AddDotnetProject(project types.DotnetProject) {
 	pi.AddTask(task)
 }
 
+// AddGoProject adds a Go module download task.
+func (pi *ParallelInstaller) AddGoProject(project detector.GoProject) {
+	projectName := getProjectName(project.Dir)
+	task := ProjectInstallTask{
+		ID:          project.Dir,
+		Description: projectName + " (go)",
+		Type:        "go",
+		Dir:         project.Dir,
+		Manager:     "go",
+		Project:     project,
+	}
+	pi.AddTask(task)
+}
+
+// AddRustProject adds a Cargo fetch task.
+func (pi *ParallelInstaller) AddRustProject(project detector.RustProject) {
+	projectName := getProjectName(project.Dir)
+	task := ProjectInstallTask{
+		ID:          project.Dir,
+		Description: projectName + " (cargo)",
+		Type:        "rust",
+		Dir:         project.Dir,
+		Manager:     "cargo",
+		Project:     project,
+	}
+	pi.AddTask(task)
+}
+
+// AddJavaProject adds a Maven or Gradle dependency resolution task.
+func (pi *ParallelInstaller) AddJavaProject(project detector.JavaProject) {
+	projectName := getProjectName(project.Dir)
+	task := ProjectInstallTask{
+		ID:          project.Dir,
+		Description: projectName + " (" + project.BuildTool + ")",
+		Type:        "java",
+		Dir:         project.Dir,
+		Manager:     project.BuildTool,
+		Project:     project,
+	}
+	pi.AddTask(task)
+}
+
 // executeTask is the unified task execution logic.
 // It handles all project types and writes output to the provided writer.
-func (pi *ParallelInstaller) executeTask(task ProjectInstallTask, writer io.Writer) error {
+func (pi *ParallelInstaller) executeTask(ctx context.Context, task ProjectInstallTask, writer io.Writer) error {
 	// Check for cancellation before starting
 	select {
-	case <-pi.ctx.Done():
-		return pi.ctx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
 	default:
 	}
 
 	switch task.Type {
 	case "node":
 		if project, ok := task.Project.(types.NodeProject); ok {
-			return installNodeDependenciesWithWriter(project, writer)
+			return installNodeDependenciesWithWriter(ctx, project, writer)
 		}
 	case "python":
 		if project, ok := task.Project.(types.PythonProject); ok {
-			return setupPythonVirtualEnvWithWriter(project, writer)
+			return setupPythonVirtualEnvWithWriter(ctx, project, writer)
 		}
 	case "dotnet":
 		if project, ok := task.Project.(types.DotnetProject); ok {
-			return restoreDotnetProjectWithWriter(project, writer)
+			return restoreDotnetProjectWithWriter(ctx, project, writer)
+		}
+	case "go":
+		if project, ok := task.Project.(detector.GoProject); ok {
+			return downloadGoModulesWithWriter(ctx, project, writer)
+		}
+	case "rust":
+		if project, ok := task.Project.(detector.RustProject); ok {
+			return fetchRustDependenciesWithWriter(ctx, project, writer)
+		}
+	case "java":
+		if project, ok := task.Project.(detector.JavaProject); ok {
+			return resolveJavaDependenciesWithWriter(ctx, project, writer)
 		}
 	}
 	return fmt.Errorf("unknown task type: %s", task.Type)
 }
 
+// runTask executes a task, records how long it took and reports it to the event stream.
+// It reports whether the task was skipped because its project is up to date.
+func (pi *ParallelInstaller) runTask(task ProjectInstallTask, writer io.Writer) (bool, error) {
+	event := eventstream.Event{
+		Event:          eventstream.TypeDepsInstalling,
+		Project:        task.Dir,
+		Language:       task.Type,
+		PackageManager: task.Manager,
+	}
+	if pi.upToDate(task) {
+		event.Event = eventstream.TypeDepsInstalled
+		event.UpToDate = true
+		eventstream.Emit(event)
+		return true, nil
+	}
+	eventstream.Emit(event)
+
+	start := time.Now()
+	err := RunWithTimeout(pi.ctx, pi.Timeout, func(ctx context.Context) error {
+		return pi.executeTask(ctx, task, writer)
+	})
+	elapsed := time.Since(start)
+	if err != nil {
+		event.Event = eventstream.TypeError
+		event.Error = err.Error()
+	} else {
+		pi.recordDuration(task, elapsed)
+		event.Event = eventstream.TypeDepsInstalled
+		event.DurationMs = elapsed.Milliseconds()
+	}
+	pi.recordInstall(task, err)
+	eventstream.Emit(event)
+	return false, err
+}
+
+// RunWithTimeout runs install with a context that is canceled after timeout (0 = no limit),
+// which kills the package manager. An install canceled by the timeout returns a *TimeoutError.
+func RunWithTimeout(ctx context.Context, timeout time.Duration, install func(context.Context) error) error {
+	if timeout <= 0 {
+		return install(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := install(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{Timeout: timeout, Err: err}
+	}
+	return err
+}
+
+// upToDate reports whether the task's project was last installed from its current lock files
+// and its dependencies are still there.
+func (pi *ParallelInstaller) upToDate(task ProjectInstallTask) bool {
+	if pi.Cache == nil || pi.Force {
+		return false
+	}
+	dir := taskDir(task)
+	return pi.Cache.UpToDate(CacheKey(task.Type, dir), Fingerprint(task.Type, dir, task.Manager)) &&
+		DependenciesPresent(task.Type, dir, task.Manager)
+}
+
+// recordInstall stores the lock files the task's project was installed from, if a cache is configured.
+// The lock files are hashed after the install, which may have updated them.
+// A failed install is forgotten so the next run installs again.
+func (pi *ParallelInstaller) recordInstall(task ProjectInstallTask, err error) {
+	if pi.Cache == nil {
+		return
+	}
+	dir := taskDir(task)
+	fingerprint := ""
+	if err == nil {
+		fingerprint = Fingerprint(task.Type, dir, task.Manager)
+	}
+	pi.Cache.Record(CacheKey(task.Type, dir), fingerprint)
+}
+
 // addResult safely adds a result to the results slice.
 func (pi *ParallelInstaller) addResult(result ProjectInstallResult) {
 	pi.mu.Lock()
@@ -181,7 +328,7 @@ func (pi *ParallelInstaller) Run() error {
 
 	// Add all tasks to the progress display first
 	for _, task := range pi.tasks {
-		pi.multiProg.AddBar(task.ID, task.Description)
+		pi.multiProg.AddBar(task.ID, pi.describeTask(task))
 	}
 
 	// Start rendering progress bars (mpb handles space automatically)
@@ -194,10 +341,13 @@ func (pi *ParallelInstaller) Run() error {
 	var wg sync.WaitGroup
 
 	// Run non-pnpm tasks in parallel
+	slots := pi.newSlots()
 	for _, task := range parallelTasks {
 		wg.Add(1)
 		go func(t ProjectInstallTask) {
 			defer wg.Done()
+			release := acquireSlot(slots)
+			defer release()
 			defer func() {
 				if r := recover(); r != nil {
 					pi.addResult(ProjectInstallResult{
@@ -245,6 +395,24 @@ func (pi *ParallelInstaller) Run() error {
 	return nil
 }
 
+// newSlots returns a semaphore channel sized to MaxConcurrency, or nil when unlimited.
+func (pi *ParallelInstaller) newSlots() chan struct{} {
+	if pi.MaxConcurrency <= 0 {
+		return nil
+	}
+	return make(chan struct{}, pi.MaxConcurrency)
+}
+
+// acquireSlot blocks until a slot is free and returns a function that releases it.
+// A nil slots channel means unlimited concurrency.
+func acquireSlot(slots chan struct{}) func() {
+	if slots == nil {
+		return func() {}
+	}
+	slots <- struct{}{}
+	return func() { <-slots }
+}
+
 // separateTasksByManager separates pnpm tasks from other tasks.
 func (pi *ParallelInstaller) separateTasksByManager() (pnpmTasks, parallelTasks []ProjectInstallTask) {
 	for _, task := range pi.tasks {
@@ -269,18 +437,22 @@ func (pi *ParallelInstaller) runTaskWithProgress(task ProjectInstallTask) {
 		writer = os.Stdout
 	}
 
-	err := pi.executeTask(task, writer)
-
-	if err != nil {
+	upToDate, err := pi.runTask(task, writer)
+	switch {
+	case err != nil:
 		bar.Fail(err.Error())
-	} else {
+	case upToDate:
+		bar.Skip()
+	default:
 		bar.Complete()
 	}
 
 	pi.addResult(ProjectInstallResult{
-		Task:    task,
-		Success: err == nil,
-		Error:   err,
+		Task:     task,
+		Success:  err == nil,
+		UpToDate: upToDate,
+		TimedOut: IsTimeout(err),
+		Error:    err,
 	})
 }
 
@@ -291,10 +463,13 @@ func (pi *ParallelInstaller) runVerbose() error {
 	var wg sync.WaitGroup
 
 	// Run non-pnpm tasks in parallel
+	slots := pi.newSlots()
 	for _, task := range parallelTasks {
 		wg.Add(1)
 		go func(t ProjectInstallTask) {
 			defer wg.Done()
+			release := acquireSlot(slots)
+			defer release()
 			defer func() {
 				if r := recover(); r != nil {
 					pi.addResult(ProjectInstallResult{
@@ -325,7 +500,7 @@ func (pi *ParallelInstaller) runVerbose() error {
 				default:
 				}
 				// Print task header for clarity
-				_, _ = fmt.Fprintf(os.Stdout, "\n=== Installing: %s ===\n", task.Description)
+				_, _ = fmt.Fprintf(os.Stdout, "\n=== Installing: %s ===\n", pi.describeTask(task))
 				pi.runTaskVerbose(task)
 			}
 		}()
@@ -339,21 +514,46 @@ func (pi *ParallelInstaller) runVerbose() error {
 
 // runTaskVerbose executes a single task with verbose output.
 func (pi *ParallelInstaller) runTaskVerbose(task ProjectInstallTask) {
-	err := pi.executeTask(task, os.Stdout)
+	upToDate, err := pi.runTask(task, os.Stdout)
 	pi.addResult(ProjectInstallResult{
-		Task:    task,
-		Success: err == nil,
-		Error:   err,
+		Task:     task,
+		Success:  err == nil,
+		UpToDate: upToDate,
+		TimedOut: IsTimeout(err),
+		Error:    err,
 	})
 }
 
+// describeTask returns the task description with an ETA hint when history is available.
+func (pi *ParallelInstaller) describeTask(task ProjectInstallTask) string {
+	if pi.History == nil {
+		return task.Description
+	}
+	if hint := pi.History.Hint(eta.KindDeps, task.ID); hint != "" {
+		return task.Description + " - " + hint
+	}
+	return task.Description
+}
+
+// recordDuration stores a successful install duration in the history, if configured.
+// Failed installs are not recorded since they usually abort early and would skew estimates.
+func (pi *ParallelInstaller) recordDuration(task ProjectInstallTask, d time.Duration) {
+	if pi.History != nil {
+		pi.History.Record(eta.KindDeps, task.ID, d)
+	}
+}
+
 // printSummary prints the overall installation summary.
 func (pi *ParallelInstaller) printSummary() {
 	totalCount := len(pi.results)
 	successCount := 0
 	var failedTasks []string
 
+	cliout.Newline()
 	for _, result := range pi.results {
+		if result.UpToDate {
+			cliout.Item("%s: up to date", result.Task.Description)
+		}
 		if result.Success {
 			successCount++
 		} else {
@@ -365,7 +565,6 @@ func (pi *ParallelInstaller) printSummary() {
 		}
 	}
 
-	cliout.Newline()
 	progress.PrintSummary(totalCount, successCount, failedTasks)
 }
 
@@ -395,6 +594,17 @@ func (pi *ParallelInstaller) FailedProjects() []string {
 	return failed
 }
 
+// TimedOutProjects returns a list of project descriptions whose installation timed out.
+func (pi *ParallelInstaller) TimedOutProjects() []string {
+	var timedOut []string
+	for _, result := range pi.results {
+		if result.TimedOut {
+			timedOut = append(timedOut, result.Task.Description)
+		}
+	}
+	return timedOut
+}
+
 // TotalProjects returns the total number of projects that were processed.
 func (pi *ParallelInstaller) TotalProjects() int {
 	return len(pi.results)
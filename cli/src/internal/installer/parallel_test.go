@@ -2,11 +2,16 @@ package installer
 
 import (
 	"context"
+	"errors"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/jongio/azd-app/cli/src/internal/cache"
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/eta"
 	types "github.com/jongio/azd-core/projecttype"
 )
 
@@ -132,6 +137,96 @@ func TestAddTask(t *testing.T) {
 	}
 }
 
+func TestDescribeTask_WithHistory(t *testing.T) {
+	pi := NewParallelInstaller()
+	task := ProjectInstallTask{ID: "web", Description: "web (npm)"}
+
+	if got := pi.describeTask(task); got != "web (npm)" {
+		t.Errorf("describeTask() without history = %q, want %q", got, "web (npm)")
+	}
+
+	pi.History = eta.Load(t.TempDir())
+	if got := pi.describeTask(task); got != "web (npm)" {
+		t.Errorf("describeTask() with empty history = %q, want %q", got, "web (npm)")
+	}
+
+	pi.recordDuration(task, 40*time.Second)
+	if got, want := pi.describeTask(task), "web (npm) - usually takes ~40s"; got != want {
+		t.Errorf("describeTask() = %q, want %q", got, want)
+	}
+}
+
+func TestRunTask_SkipsUpToDateProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/api\n")
+	// The task has no project, so running it fails; only a skipped task succeeds
+	task := ProjectInstallTask{ID: dir, Type: "go", Dir: dir, Manager: "go"}
+
+	pi := NewParallelInstaller()
+	pi.Cache = cache.LoadDepsCache(t.TempDir())
+	if upToDate, err := pi.runTask(task, nil); upToDate || err == nil {
+		t.Fatalf("runTask() = %v, %v before the first install", upToDate, err)
+	}
+
+	pi.Cache.Record(CacheKey("go", dir), Fingerprint("go", dir, "go"))
+	if upToDate, err := pi.runTask(task, nil); !upToDate || err != nil {
+		t.Errorf("runTask() = %v, %v, want the unchanged project skipped", upToDate, err)
+	}
+
+	pi.Force = true
+	if upToDate, _ := pi.runTask(task, nil); upToDate {
+		t.Error("runTask() skipped the project with Force")
+	}
+
+	// A failed install is forgotten
+	pi.Force = false
+	if upToDate, _ := pi.runTask(task, nil); upToDate {
+		t.Error("runTask() skipped the project after a failed install")
+	}
+
+	pi.Cache.Record(CacheKey("go", dir), Fingerprint("go", dir, "go"))
+	writeFile(t, filepath.Join(dir, "go.sum"), "example.com/dep v1.0.0 h1:abc=\n")
+	if upToDate, _ := pi.runTask(task, nil); upToDate {
+		t.Error("runTask() skipped the project after its lock file changed")
+	}
+}
+
+func TestRunWithTimeout(t *testing.T) {
+	block := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	err := RunWithTimeout(context.Background(), 10*time.Millisecond, block)
+	if !IsTimeout(err) || err.Error() != "timed out after 10ms" {
+		t.Errorf("RunWithTimeout() error = %v, want a timeout", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RunWithTimeout() error = %v, want it to wrap the install's error", err)
+	}
+
+	// Without a timeout, or when canceled by the caller, the install's error is returned as is
+	if err := RunWithTimeout(context.Background(), 0, func(context.Context) error { return nil }); err != nil {
+		t.Errorf("RunWithTimeout() without timeout error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := RunWithTimeout(ctx, time.Minute, block); IsTimeout(err) || !errors.Is(err, context.Canceled) {
+		t.Errorf("RunWithTimeout() canceled error = %v, want context.Canceled", err)
+	}
+}
+
+func TestTimedOutProjects(t *testing.T) {
+	pi := NewParallelInstaller()
+	pi.addResult(ProjectInstallResult{Task: ProjectInstallTask{Description: "web (npm)"}, Success: true})
+	pi.addResult(ProjectInstallResult{Task: ProjectInstallTask{Description: "api (uv)"}, Error: errors.New("exit status 1")})
+	pi.addResult(ProjectInstallResult{Task: ProjectInstallTask{Description: "jobs (maven)"}, TimedOut: true, Error: &TimeoutError{Timeout: time.Minute}})
+
+	if got := pi.TimedOutProjects(); len(got) != 1 || got[0] != "jobs (maven)" {
+		t.Errorf("TimedOutProjects() = %v, want [jobs (maven)]", got)
+	}
+}
+
 func TestAddNodeProject(t *testing.T) {
 	pi := NewParallelInstaller()
 
@@ -209,6 +304,27 @@ func TestAddDotnetProject(t *testing.T) {
 	}
 }
 
+func TestAddJavaProject(t *testing.T) {
+	pi := NewParallelInstaller()
+
+	pi.AddJavaProject(detector.JavaProject{Dir: "/path/to/java/api", BuildTool: detector.BuildToolGradle})
+
+	if len(pi.tasks) != 1 {
+		t.Fatalf("Expected 1 task, got %d", len(pi.tasks))
+	}
+
+	task := pi.tasks[0]
+	if task.Type != "java" {
+		t.Errorf("Expected type 'java', got %q", task.Type)
+	}
+	if task.Manager != "gradle" {
+		t.Errorf("Expected manager 'gradle', got %q", task.Manager)
+	}
+	if task.Description != "api (gradle)" {
+		t.Errorf("Expected description 'api (gradle)', got %q", task.Description)
+	}
+}
+
 func TestAddMultipleProjects(t *testing.T) {
 	pi := NewParallelInstaller()
 
@@ -0,0 +1,109 @@
+package logexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/version"
+)
+
+// harVersion is the HAR specification version whose envelope the archive follows.
+const harVersion = "1.2"
+
+// harArchive is a HAR-like archive of service logs. It keeps the HTTP Archive envelope
+// (log.version, log.creator, log.pages and log.entries) so tools that read HAR can open it:
+// each service is a page and each log entry an entry referencing its page. Log fields are
+// custom fields, which HAR prefixes with "_"; entries have no request or response.
+type harArchive struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version    string     `json:"version"`
+	Creator    harCreator `json:"creator"`
+	Pages      []harPage  `json:"pages"`
+	Entries    []harEntry `json:"entries"`
+	Project    string     `json:"_project,omitempty"`
+	Source     string     `json:"_source,omitempty"`
+	ExportedAt time.Time  `json:"_exportedAt"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harPage struct {
+	StartedDateTime time.Time      `json:"startedDateTime"`
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	PageTimings     harPageTimings `json:"pageTimings"`
+	Entries         int            `json:"_entries"`
+}
+
+// harPageTimings is required by HAR; log pages have no load timings.
+type harPageTimings struct{}
+
+type harEntry struct {
+	PageRef         string    `json:"pageref"`
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Time            float64   `json:"time"`
+	Service         string    `json:"_service"`
+	Level           string    `json:"_level"`
+	Stream          string    `json:"_stream"`
+	Source          string    `json:"_source,omitempty"`
+	Message         string    `json:"_message"`
+}
+
+// newHARArchive converts a bundle to a HAR-like archive.
+// Pages follow b.Services and start at their service's first entry.
+func newHARArchive(b *Bundle) *harArchive {
+	pages := make([]harPage, len(b.Services))
+	index := make(map[string]int, len(b.Services))
+	for i, name := range b.Services {
+		pages[i] = harPage{ID: name, Title: name}
+		index[name] = i
+	}
+
+	entries := make([]harEntry, 0, len(b.Entries))
+	for _, e := range b.Entries {
+		if i, ok := index[e.Service]; ok {
+			// Entries are sorted by timestamp, so the first one starts the page
+			if pages[i].Entries == 0 {
+				pages[i].StartedDateTime = e.Timestamp
+			}
+			pages[i].Entries++
+		}
+		entries = append(entries, harEntry{
+			PageRef:         e.Service,
+			StartedDateTime: e.Timestamp,
+			Service:         e.Service,
+			Level:           e.Level,
+			Stream:          e.Stream,
+			Source:          e.Source,
+			Message:         e.Message,
+		})
+	}
+
+	return &harArchive{Log: harLog{
+		Version:    harVersion,
+		Creator:    harCreator{Name: "azd-app", Version: version.Version},
+		Pages:      pages,
+		Entries:    entries,
+		Project:    b.Project,
+		Source:     b.Source,
+		ExportedAt: b.ExportedAt,
+	}}
+}
+
+// WriteHAR writes the bundle as an indented HAR-like archive.
+func WriteHAR(w io.Writer, b *Bundle) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(newHARArchive(b)); err != nil {
+		return fmt.Errorf("failed to encode HAR archive: %w", err)
+	}
+	return nil
+}
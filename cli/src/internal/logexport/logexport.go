@@ -0,0 +1,153 @@
+// Package logexport converts service logs into portable formats: a normalized
+// JSON bundle, plain text or a HAR-like archive for attaching to bug reports,
+// and OTLP for observability tooling.
+package logexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+// BundleVersion is the schema version of the JSON bundle.
+const BundleVersion = 1
+
+// Export targets.
+const (
+	// TargetFile writes a normalized JSON bundle to disk.
+	TargetFile = "file"
+	// TargetOTLP pushes logs to an OTLP/HTTP endpoint.
+	TargetOTLP = "otlp"
+)
+
+// File formats for TargetFile.
+const (
+	// FormatJSON is the JSON bundle (the default).
+	FormatJSON = "json"
+	// FormatText is one line per entry with its timestamp, service, level and stream.
+	FormatText = "text"
+	// FormatHAR is a HAR-like archive with a page per service.
+	FormatHAR = "har"
+)
+
+// Bundle is a self-describing snapshot of service logs.
+type Bundle struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Project    string    `json:"project,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	Services   []string  `json:"services"`
+	Entries    []Entry   `json:"entries"`
+}
+
+// Entry is a normalized log record.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Service   string    `json:"service"`
+	Level     string    `json:"level"`
+	Stream    string    `json:"stream"`
+	Source    string    `json:"source,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// NewBundle builds a bundle from collected log entries.
+// Entries are sorted by timestamp and the service list is derived from them.
+func NewBundle(project, source string, logs []service.LogEntry) *Bundle {
+	entries := make([]Entry, 0, len(logs))
+	seen := make(map[string]bool)
+	services := []string{}
+
+	for _, l := range logs {
+		stream := "stdout"
+		if l.IsStderr {
+			stream = "stderr"
+		}
+		entries = append(entries, Entry{
+			Timestamp: l.Timestamp.UTC(),
+			Service:   l.Service,
+			Level:     strings.ToLower(l.Level.String()),
+			Stream:    stream,
+			Source:    l.Source,
+			Message:   l.Message,
+		})
+		if !seen[l.Service] {
+			seen[l.Service] = true
+			services = append(services, l.Service)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	sort.Strings(services)
+
+	return &Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Project:    project,
+		Source:     source,
+		Services:   services,
+		Entries:    entries,
+	}
+}
+
+// WriteBundle writes the bundle as indented JSON.
+func WriteBundle(w io.Writer, b *Bundle) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(b); err != nil {
+		return fmt.Errorf("failed to encode log bundle: %w", err)
+	}
+	return nil
+}
+
+// textTimestampFormat is the timestamp format of WriteText, in UTC with milliseconds.
+const textTimestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// WriteText writes one line per entry, e.g.
+// "[2024-01-15T10:30:45.100Z] [api] [ERROR] [ERR] connection refused".
+// The stream is OUT or ERR, as in the persisted log files.
+func WriteText(w io.Writer, b *Bundle) error {
+	for _, e := range b.Entries {
+		stream := "OUT"
+		if e.Stream == "stderr" {
+			stream = "ERR"
+		}
+		if _, err := fmt.Fprintf(w, "[%s] [%s] [%s] [%s] %s\n",
+			e.Timestamp.Format(textTimestampFormat), e.Service, strings.ToUpper(e.Level), stream, e.Message); err != nil {
+			return fmt.Errorf("failed to write log entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// Write writes the bundle in format: FormatJSON (or ""), FormatText or FormatHAR.
+func Write(w io.Writer, b *Bundle, format string) error {
+	switch format {
+	case "", FormatJSON:
+		return WriteBundle(w, b)
+	case FormatText:
+		return WriteText(w, b)
+	case FormatHAR:
+		return WriteHAR(w, b)
+	default:
+		return fmt.Errorf("unknown export format: %s", format)
+	}
+}
+
+// DefaultFileName returns a timestamped file name for an export in format.
+func DefaultFileName(now time.Time, format string) string {
+	ext := "json"
+	switch format {
+	case FormatText:
+		ext = "log"
+	case FormatHAR:
+		ext = "har"
+	}
+	return fmt.Sprintf("azd-app-logs-%s.%s", now.Format("20060102-150405"), ext)
+}
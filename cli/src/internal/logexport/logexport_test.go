@@ -0,0 +1,227 @@
+package logexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/service"
+)
+
+func sampleLogs() []service.LogEntry {
+	base := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	return []service.LogEntry{
+		{Service: "web", Message: "listening", Level: service.LogLevelInfo, Timestamp: base.Add(2 * time.Second)},
+		{Service: "api", Message: "boom", Level: service.LogLevelError, Timestamp: base.Add(3 * time.Second), IsStderr: true},
+		{Service: "api", Message: "starting", Level: service.LogLevelDebug, Timestamp: base},
+	}
+}
+
+func TestNewBundle(t *testing.T) {
+	b := NewBundle("shop", "local", sampleLogs())
+
+	if b.Version != BundleVersion || b.Project != "shop" || b.Source != "local" {
+		t.Errorf("unexpected bundle metadata: %+v", b)
+	}
+	if len(b.Services) != 2 || b.Services[0] != "api" || b.Services[1] != "web" {
+		t.Errorf("Services = %v, want [api web]", b.Services)
+	}
+	if b.Entries[0].Message != "starting" || b.Entries[2].Message != "boom" {
+		t.Errorf("entries not sorted by timestamp: %+v", b.Entries)
+	}
+	if b.Entries[2].Level != "error" || b.Entries[2].Stream != "stderr" {
+		t.Errorf("entry = %+v, want level error on stderr", b.Entries[2])
+	}
+}
+
+func TestWriteBundle_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, NewBundle("shop", "local", sampleLogs())); err != nil {
+		t.Fatalf("WriteBundle() error = %v", err)
+	}
+
+	var decoded Bundle
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("bundle is not valid JSON: %v", err)
+	}
+	if len(decoded.Entries) != 3 {
+		t.Errorf("decoded %d entries, want 3", len(decoded.Entries))
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteText(&buf, NewBundle("shop", "local", sampleLogs())); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+
+	want := "[2024-01-15T10:30:00.000Z] [api] [DEBUG] [OUT] starting\n" +
+		"[2024-01-15T10:30:02.000Z] [web] [INFO] [OUT] listening\n" +
+		"[2024-01-15T10:30:03.000Z] [api] [ERROR] [ERR] boom\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteHAR(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHAR(&buf, NewBundle("shop", "local", sampleLogs())); err != nil {
+		t.Fatalf("WriteHAR() error = %v", err)
+	}
+
+	var decoded harArchive
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("archive is not valid JSON: %v", err)
+	}
+	har := decoded.Log
+	if har.Version != harVersion || har.Creator.Name != "azd-app" || har.Project != "shop" {
+		t.Errorf("unexpected archive metadata: %+v", har)
+	}
+	if len(har.Pages) != 2 || har.Pages[0].ID != "api" || har.Pages[0].Entries != 2 || har.Pages[1].Entries != 1 {
+		t.Fatalf("Pages = %+v, want api with 2 entries and web with 1", har.Pages)
+	}
+	if want := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC); !har.Pages[0].StartedDateTime.Equal(want) {
+		t.Errorf("api page started at %v, want %v", har.Pages[0].StartedDateTime, want)
+	}
+	if len(har.Entries) != 3 {
+		t.Fatalf("archive has %d entries, want 3", len(har.Entries))
+	}
+	if e := har.Entries[2]; e.PageRef != "api" || e.Level != "error" || e.Stream != "stderr" || e.Message != "boom" {
+		t.Errorf("entry = %+v, want the api error on stderr", e)
+	}
+}
+
+func TestWrite_Formats(t *testing.T) {
+	b := NewBundle("shop", "local", sampleLogs())
+	now := time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC)
+
+	tests := []struct {
+		format   string
+		wantFile string
+		wantErr  bool
+	}{
+		{"", "azd-app-logs-20240115-103100.json", false},
+		{FormatJSON, "azd-app-logs-20240115-103100.json", false},
+		{FormatText, "azd-app-logs-20240115-103100.log", false},
+		{FormatHAR, "azd-app-logs-20240115-103100.har", false},
+		{"csv", "azd-app-logs-20240115-103100.json", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := DefaultFileName(now, tt.format); got != tt.wantFile {
+				t.Errorf("DefaultFileName() = %q, want %q", got, tt.wantFile)
+			}
+			var buf bytes.Buffer
+			err := Write(&buf, b, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && buf.Len() == 0 {
+				t.Error("Write() wrote nothing")
+			}
+		})
+	}
+}
+
+func TestResolveLogsURL(t *testing.T) {
+	t.Setenv(envOTLPLogsEndpoint, "")
+	t.Setenv(envOTLPEndpoint, "")
+
+	tests := []struct {
+		name     string
+		endpoint string
+		env      map[string]string
+		want     string
+		wantErr  bool
+	}{
+		{name: "default", want: "http://localhost:4318/v1/logs"},
+		{name: "base endpoint", endpoint: "https://otel.example.com", want: "https://otel.example.com/v1/logs"},
+		{name: "full path kept", endpoint: "http://collector:4318/v1/logs", want: "http://collector:4318/v1/logs"},
+		{name: "generic env", env: map[string]string{envOTLPEndpoint: "http://collector:4318/"}, want: "http://collector:4318/v1/logs"},
+		{name: "signal env used as-is", env: map[string]string{envOTLPLogsEndpoint: "http://collector:4318/custom"}, want: "http://collector:4318/custom"},
+		{name: "bad scheme", endpoint: "grpc://collector:4317", wantErr: true},
+		{name: "missing host", endpoint: "http://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			got, err := resolveLogsURL(tt.endpoint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveLogsURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveLogsURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	got := parseHeaders("api-key=secret, x-tenant = a%20b ,invalid,=empty")
+	if len(got) != 2 || got["api-key"] != "secret" || got["x-tenant"] != "a b" {
+		t.Errorf("parseHeaders() = %v", got)
+	}
+}
+
+func TestOTLPExporter_Export(t *testing.T) {
+	var received otlpLogsRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("invalid OTLP payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter, err := NewOTLPExporter(server.URL)
+	if err != nil {
+		t.Fatalf("NewOTLPExporter() error = %v", err)
+	}
+	exporter.Headers = map[string]string{"Authorization": "Bearer token"}
+
+	if err := exporter.Export(context.Background(), NewBundle("shop", "local", sampleLogs())); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if auth != "Bearer token" {
+		t.Errorf("Authorization header = %q, want %q", auth, "Bearer token")
+	}
+	if len(received.ResourceLogs) != 2 {
+		t.Fatalf("resourceLogs = %d, want one per service (2)", len(received.ResourceLogs))
+	}
+	api := received.ResourceLogs[0]
+	if api.Resource.Attributes[0].Value.StringValue != "api" {
+		t.Errorf("first resource service.name = %q, want api", api.Resource.Attributes[0].Value.StringValue)
+	}
+	records := api.ScopeLogs[0].LogRecords
+	if len(records) != 2 || records[1].SeverityNumber != 17 || records[1].SeverityText != "ERROR" {
+		t.Errorf("api records = %+v", records)
+	}
+}
+
+func TestOTLPExporter_ExportErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	exporter, err := NewOTLPExporter(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := exporter.Export(context.Background(), NewBundle("shop", "local", sampleLogs())); err == nil {
+		t.Error("Export() expected error for non-2xx response")
+	}
+}
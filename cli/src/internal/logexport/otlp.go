@@ -0,0 +1,257 @@
+package logexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOTLPEndpoint is the standard local OTLP/HTTP collector address.
+	DefaultOTLPEndpoint = "http://localhost:4318"
+
+	// otlpLogsPath is appended to base endpoints per the OTLP/HTTP specification.
+	otlpLogsPath = "/v1/logs"
+
+	// otlpBatchSize bounds the number of log records per request.
+	otlpBatchSize = 1000
+
+	// otlpTimeout is the per-request timeout.
+	otlpTimeout = 30 * time.Second
+
+	// instrumentationScope identifies the exporter in OTLP payloads.
+	instrumentationScope = "azd-app"
+)
+
+// Environment variables defined by the OpenTelemetry exporter specification.
+const (
+	envOTLPLogsEndpoint = "OTEL_EXPORTER_OTLP_LOGS_ENDPOINT"
+	envOTLPEndpoint     = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envOTLPHeaders      = "OTEL_EXPORTER_OTLP_HEADERS"
+)
+
+// OTLPExporter sends log bundles to an OTLP/HTTP endpoint using the JSON encoding.
+type OTLPExporter struct {
+	// URL is the full logs endpoint, e.g. http://localhost:4318/v1/logs.
+	URL string
+	// Headers are added to every request (e.g. authentication).
+	Headers map[string]string
+	// Client performs the requests.
+	Client *http.Client
+}
+
+// NewOTLPExporter creates an exporter for endpoint.
+// When endpoint is empty, the standard OTEL_EXPORTER_OTLP_* environment variables
+// are used, falling back to DefaultOTLPEndpoint.
+func NewOTLPExporter(endpoint string) (*OTLPExporter, error) {
+	logsURL, err := resolveLogsURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OTLPExporter{
+		URL:     logsURL,
+		Headers: parseHeaders(os.Getenv(envOTLPHeaders)),
+		Client:  &http.Client{Timeout: otlpTimeout},
+	}, nil
+}
+
+// Export sends all bundle entries, batching large bundles into several requests.
+func (x *OTLPExporter) Export(ctx context.Context, b *Bundle) error {
+	for start := 0; start < len(b.Entries); start += otlpBatchSize {
+		end := start + otlpBatchSize
+		if end > len(b.Entries) {
+			end = len(b.Entries)
+		}
+		if err := x.send(ctx, buildLogsRequest(b, b.Entries[start:end])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send posts a single OTLP request.
+func (x *OTLPExporter) send(ctx context.Context, payload otlpLogsRequest) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode OTLP payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, x.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range x.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := x.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send logs to %s: %w", x.URL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("OTLP endpoint %s returned %s: %s", x.URL, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// resolveLogsURL determines the logs URL from the flag value or environment.
+// Signal-specific endpoints are used as-is; base endpoints get /v1/logs appended.
+func resolveLogsURL(endpoint string) (string, error) {
+	appendPath := true
+	if endpoint == "" {
+		if v := os.Getenv(envOTLPLogsEndpoint); v != "" {
+			endpoint = v
+			appendPath = false
+		} else if v := os.Getenv(envOTLPEndpoint); v != "" {
+			endpoint = v
+		} else {
+			endpoint = DefaultOTLPEndpoint
+		}
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid OTLP endpoint %q: %w", endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid OTLP endpoint %q: scheme must be http or https", endpoint)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid OTLP endpoint %q: missing host", endpoint)
+	}
+
+	if appendPath && !strings.HasSuffix(u.Path, otlpLogsPath) {
+		u.Path = strings.TrimSuffix(u.Path, "/") + otlpLogsPath
+	}
+	return u.String(), nil
+}
+
+// parseHeaders parses the OTEL_EXPORTER_OTLP_HEADERS format: "key1=value1,key2=value2".
+func parseHeaders(raw string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if decoded, err := url.QueryUnescape(strings.TrimSpace(value)); err == nil {
+			value = decoded
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// OTLP/HTTP JSON payload types (subset of opentelemetry-proto logs/v1).
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string         `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber"`
+	SeverityText   string         `json:"severityText"`
+	Body           otlpAnyValue   `json:"body"`
+	Attributes     []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// buildLogsRequest groups entries by service into OTLP resource logs.
+func buildLogsRequest(b *Bundle, entries []Entry) otlpLogsRequest {
+	byService := make(map[string][]otlpLogRecord)
+	var order []string
+
+	for _, e := range entries {
+		if _, ok := byService[e.Service]; !ok {
+			order = append(order, e.Service)
+		}
+		attrs := []otlpKeyValue{stringAttr("log.iostream", e.Stream)}
+		if e.Source != "" {
+			attrs = append(attrs, stringAttr("azd.app.log.source", e.Source))
+		}
+		byService[e.Service] = append(byService[e.Service], otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(e.Timestamp.UnixNano(), 10),
+			SeverityNumber: severityNumber(e.Level),
+			SeverityText:   strings.ToUpper(e.Level),
+			Body:           otlpAnyValue{StringValue: e.Message},
+			Attributes:     attrs,
+		})
+	}
+
+	req := otlpLogsRequest{ResourceLogs: make([]otlpResourceLogs, 0, len(order))}
+	for _, name := range order {
+		resourceAttrs := []otlpKeyValue{stringAttr("service.name", name)}
+		if b.Project != "" {
+			resourceAttrs = append(resourceAttrs, stringAttr("service.namespace", b.Project))
+		}
+		req.ResourceLogs = append(req.ResourceLogs, otlpResourceLogs{
+			Resource: otlpResource{Attributes: resourceAttrs},
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: instrumentationScope},
+				LogRecords: byService[name],
+			}},
+		})
+	}
+	return req
+}
+
+// severityNumber maps a level name to the OTLP severity number.
+func severityNumber(level string) int {
+	switch level {
+	case "debug":
+		return 5
+	case "warn":
+		return 13
+	case "error":
+		return 17
+	default:
+		return 9 // info
+	}
+}
+
+func stringAttr(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: value}}
+}
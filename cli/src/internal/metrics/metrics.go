@@ -0,0 +1,159 @@
+// Package metrics samples the CPU, memory, and uptime of locally running services.
+// Each service is measured as its whole process tree, so a service started through a
+// wrapper (npm, dotnet run, a shell) reports the resources of the processes doing the work.
+package metrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// Target identifies the root process of a service to sample.
+type Target struct {
+	Service   string
+	PID       int
+	StartTime time.Time // When the service started; zero if unknown
+}
+
+// ServiceMetrics is one resource sample of a service's process tree.
+type ServiceMetrics struct {
+	Service       string    `json:"service"`
+	PID           int       `json:"pid"`
+	CPUPercent    float64   `json:"cpuPercent"`    // Percent of one CPU core, so may exceed 100 on multi-core machines
+	MemoryBytes   uint64    `json:"memoryBytes"`   // Resident set size
+	Processes     int       `json:"processes"`     // Number of processes in the tree
+	UptimeSeconds int64     `json:"uptimeSeconds"` // Seconds since the service started
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// cpuSample is the CPU time a service had consumed at a point in time.
+type cpuSample struct {
+	pid        int
+	cpuSeconds float64
+	at         time.Time
+}
+
+// Sampler measures services and computes CPU usage from the CPU time consumed between
+// consecutive samples. It is safe for concurrent use.
+type Sampler struct {
+	mu   sync.Mutex
+	prev map[string]cpuSample // Keyed by service name
+}
+
+// NewSampler creates a Sampler.
+func NewSampler() *Sampler {
+	return &Sampler{prev: make(map[string]cpuSample)}
+}
+
+// Sample measures each target and returns the metrics sorted by service name.
+// Targets whose process no longer exists are left out.
+//
+// The first sample of a service has no previous CPU time to compare against, so its
+// CPU usage is the average since the service started.
+func (s *Sampler) Sample(ctx context.Context, targets []Target) []ServiceMetrics {
+	if len(targets) == 0 {
+		return []ServiceMetrics{}
+	}
+
+	children := childrenByParent(ctx)
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]ServiceMetrics, 0, len(targets))
+	seen := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		if target.PID <= 0 {
+			continue
+		}
+
+		var cpuSeconds float64
+		m := ServiceMetrics{Service: target.Service, PID: target.PID, Timestamp: now}
+		for _, pid := range processTree(int32(target.PID), children) {
+			proc, err := process.NewProcessWithContext(ctx, pid)
+			if err != nil {
+				continue // Exited since the process list was read
+			}
+			if times, err := proc.TimesWithContext(ctx); err == nil {
+				cpuSeconds += times.User + times.System
+			}
+			if mem, err := proc.MemoryInfoWithContext(ctx); err == nil {
+				m.MemoryBytes += mem.RSS
+			}
+			m.Processes++
+		}
+		if m.Processes == 0 {
+			continue
+		}
+
+		if !target.StartTime.IsZero() {
+			m.UptimeSeconds = int64(now.Sub(target.StartTime).Seconds())
+		}
+
+		prev, ok := s.prev[target.Service]
+		switch {
+		case ok && prev.pid == target.PID && now.After(prev.at):
+			m.CPUPercent = (cpuSeconds - prev.cpuSeconds) / now.Sub(prev.at).Seconds() * 100
+		case m.UptimeSeconds > 0:
+			m.CPUPercent = cpuSeconds / now.Sub(target.StartTime).Seconds() * 100
+		}
+		// Child processes that exited take their CPU time with them
+		if m.CPUPercent < 0 {
+			m.CPUPercent = 0
+		}
+
+		s.prev[target.Service] = cpuSample{pid: target.PID, cpuSeconds: cpuSeconds, at: now}
+		seen[target.Service] = true
+		results = append(results, m)
+	}
+
+	// Forget services that stopped so a restart doesn't compare against stale CPU time
+	for name := range s.prev {
+		if !seen[name] {
+			delete(s.prev, name)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Service < results[j].Service
+	})
+	return results
+}
+
+// childrenByParent reads the process list once and maps each parent PID to its children.
+// Building the map up front keeps the cost of a sample independent of how deep the trees are.
+func childrenByParent(ctx context.Context) map[int32][]int32 {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil
+	}
+	children := make(map[int32][]int32, len(procs))
+	for _, proc := range procs {
+		ppid, err := proc.PpidWithContext(ctx)
+		if err != nil || ppid == proc.Pid {
+			continue
+		}
+		children[ppid] = append(children[ppid], proc.Pid)
+	}
+	return children
+}
+
+// processTree returns root and all of its descendants.
+func processTree(root int32, children map[int32][]int32) []int32 {
+	tree := []int32{root}
+	visited := map[int32]bool{root: true}
+	for i := 0; i < len(tree); i++ {
+		for _, child := range children[tree[i]] {
+			if !visited[child] {
+				visited[child] = true
+				tree = append(tree, child)
+			}
+		}
+	}
+	return tree
+}
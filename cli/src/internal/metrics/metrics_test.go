@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSampler_Sample(t *testing.T) {
+	sampler := NewSampler()
+	ctx := context.Background()
+	targets := []Target{
+		{Service: "self", PID: os.Getpid(), StartTime: time.Now().Add(-time.Minute)},
+		{Service: "gone", PID: 0},
+	}
+
+	first := sampler.Sample(ctx, targets)
+	if len(first) != 1 {
+		t.Fatalf("Sample() returned %d services, want 1: %+v", len(first), first)
+	}
+	m := first[0]
+	if m.Service != "self" || m.PID != os.Getpid() {
+		t.Errorf("Sample() = %+v, want the test process", m)
+	}
+	if m.MemoryBytes == 0 || m.Processes < 1 {
+		t.Errorf("MemoryBytes = %d, Processes = %d, want both > 0", m.MemoryBytes, m.Processes)
+	}
+	if m.UptimeSeconds < 59 {
+		t.Errorf("UptimeSeconds = %d, want about 60", m.UptimeSeconds)
+	}
+
+	// Burn some CPU so the delta between samples is measurable
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for x := 0; time.Now().Before(deadline); x++ {
+		runtime.Gosched()
+	}
+	second := sampler.Sample(ctx, targets)
+	if len(second) != 1 || second[0].CPUPercent <= 0 {
+		t.Errorf("second Sample() = %+v, want CPU usage > 0", second)
+	}
+}
+
+func TestSampler_IncludesChildProcesses(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	cmd := exec.Command("sleep", "5")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("sleep not available: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	got := NewSampler().Sample(context.Background(), []Target{{Service: "self", PID: os.Getpid()}})
+	if len(got) != 1 || got[0].Processes < 2 {
+		t.Errorf("Sample() = %+v, want the child process counted", got)
+	}
+}
+
+func TestProcessTree(t *testing.T) {
+	children := map[int32][]int32{
+		1: {2, 3},
+		3: {4},
+		4: {1}, // A cycle must not loop forever
+		9: {10},
+	}
+	got := processTree(1, children)
+	if len(got) != 4 {
+		t.Errorf("processTree() = %v, want 1, 2, 3 and 4", got)
+	}
+}
@@ -0,0 +1,31 @@
+// Package netinfo finds the process listening on a local TCP port by reading the operating
+// system's socket tables directly, instead of running lsof, netstat or PowerShell: /proc/net/tcp
+// on Linux, libproc on macOS and GetExtendedTcpTable on Windows.
+package netinfo
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoListener is returned when no process is listening on the port.
+	ErrNoListener = errors.New("no process listening on port")
+
+	// ErrOwnerUnknown is returned when a socket is listening on the port but the process that
+	// owns it can't be determined, e.g. because it belongs to another user.
+	ErrOwnerUnknown = errors.New("process listening on port can't be determined")
+)
+
+// ListenerPID returns the PID of the process listening on the local TCP port, on any address.
+// Returns an error wrapping ErrNoListener when nothing is listening on it.
+func ListenerPID(port int) (int, error) {
+	if port <= 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid port number: %d (must be 1-65535)", port)
+	}
+	pid, err := listenerPID(uint16(port))
+	if err != nil {
+		return 0, fmt.Errorf("port %d: %w", port, err)
+	}
+	return pid, nil
+}
@@ -0,0 +1,148 @@
+//go:build darwin
+
+package netinfo
+
+import (
+	"encoding/binary"
+	"syscall"
+	"unsafe"
+)
+
+// libproc constants and struct offsets from <sys/proc_info.h>.
+const (
+	procPidListFDs       = 1 // PROC_PIDLISTFDS: the process's file descriptors as proc_fdinfo
+	procPidFDSocketInfo  = 3 // PROC_PIDFDSOCKETINFO: a socket descriptor as socket_fdinfo
+	proxFDTypeSocket     = 2 // PROX_FDTYPE_SOCKET
+	sockInfoTCP          = 2 // SOCKINFO_TCP
+	tcpStateListen       = 1 // TSI_S_LISTEN
+	procFDInfoSize       = 8 // struct proc_fdinfo: int32 fd, uint32 type
+	socketFDInfoSize     = 792
+	socketKindOffset     = 256 // socket_fdinfo.psi.soi_kind
+	socketLocalPort      = 268 // socket_fdinfo.psi.soi_proto.pri_tcp.tcpsi_ini.insi_lport
+	socketTCPStateOffset = 344 // socket_fdinfo.psi.soi_proto.pri_tcp.tcpsi_state
+)
+
+// listenerPID asks libproc for the socket descriptors of every process and returns the first
+// process with a TCP socket listening on port. Processes of other users can't be inspected
+// unless azd runs as root.
+func listenerPID(port uint16) (int, error) {
+	pids, err := listAllPIDs()
+	if err != nil {
+		return 0, err
+	}
+	for _, pid := range pids {
+		if pid <= 0 {
+			continue
+		}
+		if listensOn(pid, port) {
+			return int(pid), nil
+		}
+	}
+	return 0, ErrNoListener
+}
+
+// listAllPIDs returns the PIDs of all processes.
+func listAllPIDs() ([]int32, error) {
+	n, err := procListAllPIDs(nil)
+	if err != nil {
+		return nil, err
+	}
+	// Leave room for processes started between the calls
+	pids := make([]int32, n+64)
+	n, err = procListAllPIDs(pids)
+	if err != nil {
+		return nil, err
+	}
+	return pids[:min(n, len(pids))], nil
+}
+
+// listensOn reports whether the process has a TCP socket listening on port.
+func listensOn(pid int32, port uint16) bool {
+	size, err := procPidInfo(pid, procPidListFDs, nil)
+	if err != nil || size <= 0 {
+		return false
+	}
+	fds := make([]byte, size)
+	size, err = procPidInfo(pid, procPidListFDs, fds)
+	if err != nil {
+		return false
+	}
+
+	info := make([]byte, socketFDInfoSize)
+	for off := 0; off+procFDInfoSize <= size; off += procFDInfoSize {
+		fd := int32(binary.LittleEndian.Uint32(fds[off:]))
+		if binary.LittleEndian.Uint32(fds[off+4:]) != proxFDTypeSocket {
+			continue
+		}
+		n, err := procPidFDInfo(pid, fd, procPidFDSocketInfo, info)
+		if err != nil || n < socketTCPStateOffset+4 {
+			continue
+		}
+		if binary.LittleEndian.Uint32(info[socketKindOffset:]) != sockInfoTCP ||
+			binary.LittleEndian.Uint32(info[socketTCPStateOffset:]) != tcpStateListen {
+			continue
+		}
+		// insi_lport holds the port in network byte order
+		if binary.BigEndian.Uint16(info[socketLocalPort:]) == port {
+			return true
+		}
+	}
+	return false
+}
+
+// procListAllPIDs calls proc_listallpids, which returns the number of PIDs.
+func procListAllPIDs(buf []int32) (int, error) {
+	var ptr unsafe.Pointer
+	if len(buf) > 0 {
+		ptr = unsafe.Pointer(&buf[0])
+	}
+	r, _, errno := syscall_syscall(libc_proc_listallpids_trampoline_addr, uintptr(ptr), uintptr(len(buf)*4), 0)
+	if int32(r) < 0 {
+		return 0, errno
+	}
+	return int(int32(r)), nil
+}
+
+// procPidInfo calls proc_pidinfo, which returns the number of bytes written to buf, or the
+// size needed when buf is empty.
+func procPidInfo(pid int32, flavor int, buf []byte) (int, error) {
+	var ptr unsafe.Pointer
+	if len(buf) > 0 {
+		ptr = unsafe.Pointer(&buf[0])
+	}
+	r, _, errno := syscall_syscall6(libc_proc_pidinfo_trampoline_addr, uintptr(pid), uintptr(flavor), 0, uintptr(ptr), uintptr(len(buf)), 0)
+	if int32(r) <= 0 {
+		return 0, errno
+	}
+	return int(int32(r)), nil
+}
+
+// procPidFDInfo calls proc_pidfdinfo, which returns the number of bytes written to buf.
+func procPidFDInfo(pid, fd int32, flavor int, buf []byte) (int, error) {
+	r, _, errno := syscall_syscall6(libc_proc_pidfdinfo_trampoline_addr, uintptr(pid), uintptr(fd), uintptr(flavor), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), 0)
+	if int32(r) <= 0 {
+		return 0, errno
+	}
+	return int(int32(r)), nil
+}
+
+// The libproc functions are called through assembly trampolines in libSystem, the way
+// golang.org/x/sys/unix calls libc on macOS, so no cgo is needed.
+
+//go:linkname syscall_syscall syscall.syscall
+func syscall_syscall(fn, a1, a2, a3 uintptr) (r1, r2 uintptr, err syscall.Errno)
+
+//go:linkname syscall_syscall6 syscall.syscall6
+func syscall_syscall6(fn, a1, a2, a3, a4, a5, a6 uintptr) (r1, r2 uintptr, err syscall.Errno)
+
+var libc_proc_listallpids_trampoline_addr uintptr
+
+//go:cgo_import_dynamic libc_proc_listallpids proc_listallpids "/usr/lib/libSystem.B.dylib"
+
+var libc_proc_pidinfo_trampoline_addr uintptr
+
+//go:cgo_import_dynamic libc_proc_pidinfo proc_pidinfo "/usr/lib/libSystem.B.dylib"
+
+var libc_proc_pidfdinfo_trampoline_addr uintptr
+
+//go:cgo_import_dynamic libc_proc_pidfdinfo proc_pidfdinfo "/usr/lib/libSystem.B.dylib"
@@ -0,0 +1,98 @@
+//go:build linux
+
+package netinfo
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tcpListenState is the st column of a listening socket in /proc/net/tcp.
+const tcpListenState = "0A"
+
+// procRoot is the mount point of procfs. This is a variable to allow test overrides.
+var procRoot = "/proc"
+
+// listenerPID finds the inodes of the sockets listening on port in /proc/net/tcp and
+// /proc/net/tcp6, then the process with a file descriptor for one of them.
+func listenerPID(port uint16) (int, error) {
+	inodes := make(map[string]bool)
+	for _, table := range []string{"tcp", "tcp6"} {
+		f, err := os.Open(filepath.Join(procRoot, "net", table))
+		if err != nil {
+			continue // tcp6 is missing when IPv6 is disabled
+		}
+		for _, inode := range parseListeningInodes(f, port) {
+			inodes[inode] = true
+		}
+		_ = f.Close()
+	}
+	if len(inodes) == 0 {
+		return 0, ErrNoListener
+	}
+
+	if pid, ok := findSocketOwner(inodes); ok {
+		return pid, nil
+	}
+	return 0, ErrOwnerUnknown
+}
+
+// parseListeningInodes returns the inodes of the sockets in a /proc/net/tcp table that
+// listen on port.
+func parseListeningInodes(r io.Reader, port uint16) []string {
+	var inodes []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpListenState {
+			continue
+		}
+		_, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		local, err := strconv.ParseUint(hexPort, 16, 16)
+		if err != nil || uint16(local) != port {
+			continue
+		}
+		if fields[9] != "0" {
+			inodes = append(inodes, fields[9])
+		}
+	}
+	return inodes
+}
+
+// findSocketOwner returns the first process with a file descriptor for one of the socket
+// inodes. Processes whose file descriptors can't be read (other users' processes) are skipped.
+func findSocketOwner(inodes map[string]bool) (int, bool) {
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return 0, false
+	}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		fdDir := filepath.Join(procRoot, entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil {
+				continue
+			}
+			if inode, ok := strings.CutPrefix(link, "socket:["); ok && inodes[strings.TrimSuffix(inode, "]")] {
+				return pid, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,59 @@
+//go:build linux
+
+package netinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleProcNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 41234 1 0000000000000000 100 0 0 10 0
+   1: 00000000:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 41235 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:1F90 0100007F:C350 01 00000000:00000000 00:00000000 00000000  1000        0 41236 1 0000000000000000 20 4 30 10 -1
+   3: 0100007F:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 0 1 0000000000000000 100 0 0 10 0
+`
+
+func TestParseListeningInodes(t *testing.T) {
+	tests := []struct {
+		name string
+		port uint16
+		want []string
+	}{
+		{name: "listening socket", port: 8080, want: []string{"41234"}},
+		{name: "socket without inode skipped", port: 3000, want: []string{"41235"}},
+		{name: "no listener", port: 5000, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseListeningInodes(strings.NewReader(sampleProcNetTCP), tt.port)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseListeningInodes(%d) = %v, want %v", tt.port, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListenerPID_OwnerUnknown(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "net"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "net", "tcp"), []byte(sampleProcNetTCP), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := procRoot
+	procRoot = root
+	t.Cleanup(func() { procRoot = old })
+
+	if _, err := listenerPID(8080); err != ErrOwnerUnknown {
+		t.Errorf("listenerPID() error = %v, want ErrOwnerUnknown", err)
+	}
+	if _, err := listenerPID(5000); err != ErrNoListener {
+		t.Errorf("listenerPID() error = %v, want ErrNoListener", err)
+	}
+}
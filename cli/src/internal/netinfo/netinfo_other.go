@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+
+package netinfo
+
+import "errors"
+
+// listenerPID is not supported on this platform.
+func listenerPID(_ uint16) (int, error) {
+	return 0, errors.ErrUnsupported
+}
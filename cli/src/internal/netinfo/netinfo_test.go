@@ -0,0 +1,54 @@
+package netinfo
+
+import (
+	"errors"
+	"net"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestListenerPID_FindsListener(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" && runtime.GOOS != "windows" {
+		t.Skipf("not supported on %s", runtime.GOOS)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	pid, err := ListenerPID(port)
+	if err != nil {
+		t.Fatalf("ListenerPID(%d) error = %v", port, err)
+	}
+	if pid != os.Getpid() {
+		t.Errorf("ListenerPID(%d) = %d, want %d", port, pid, os.Getpid())
+	}
+}
+
+func TestListenerPID_NoListener(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" && runtime.GOOS != "windows" {
+		t.Skipf("not supported on %s", runtime.GOOS)
+	}
+	// Take a free port and release it again
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+
+	if _, err := ListenerPID(port); !errors.Is(err, ErrNoListener) {
+		t.Errorf("ListenerPID(%d) error = %v, want ErrNoListener", port, err)
+	}
+}
+
+func TestListenerPID_InvalidPort(t *testing.T) {
+	for _, port := range []int{0, -1, 65536} {
+		if _, err := ListenerPID(port); err == nil {
+			t.Errorf("ListenerPID(%d) expected error", port)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+//go:build windows
+
+package netinfo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// tcpTableOwnerPIDListener is TCP_TABLE_OWNER_PID_LISTENER: listening sockets with their owner.
+	tcpTableOwnerPIDListener = 3
+
+	// tcpRowSize is the size of MIB_TCPROW_OWNER_PID: state, local address and port,
+	// remote address and port, and owning PID, each a DWORD.
+	tcpRowSize = 24
+
+	// tcp6RowSize is the size of MIB_TCP6ROW_OWNER_PID: local address (16 bytes), scope ID
+	// and port, remote address (16 bytes), scope ID and port, state and owning PID.
+	tcp6RowSize = 56
+)
+
+var procGetExtendedTcpTable = windows.NewLazySystemDLL("iphlpapi.dll").NewProc("GetExtendedTcpTable")
+
+// listenerPID looks up port in the IPv4 and IPv6 tables of listening TCP sockets.
+func listenerPID(port uint16) (int, error) {
+	tables := []struct {
+		family     uint32
+		rowSize    int
+		portOffset int
+		pidOffset  int
+	}{
+		{family: windows.AF_INET, rowSize: tcpRowSize, portOffset: 8, pidOffset: 20},
+		{family: windows.AF_INET6, rowSize: tcp6RowSize, portOffset: 20, pidOffset: 52},
+	}
+	for _, table := range tables {
+		data, err := extendedTCPTable(table.family)
+		if err != nil {
+			return 0, err
+		}
+		if pid, ok := findListener(data, table.rowSize, table.portOffset, table.pidOffset, port); ok {
+			return pid, nil
+		}
+	}
+	return 0, ErrNoListener
+}
+
+// extendedTCPTable returns the table of listening TCP sockets of an address family.
+func extendedTCPTable(family uint32) ([]byte, error) {
+	if err := procGetExtendedTcpTable.Find(); err != nil {
+		return nil, fmt.Errorf("GetExtendedTcpTable unavailable: %w", err)
+	}
+
+	size := uint32(4096)
+	for {
+		buf := make([]byte, size)
+		r, _, _ := procGetExtendedTcpTable.Call(
+			uintptr(unsafe.Pointer(&buf[0])),
+			uintptr(unsafe.Pointer(&size)),
+			0, // unsorted
+			uintptr(family),
+			tcpTableOwnerPIDListener,
+			0,
+		)
+		switch errno := windows.Errno(r); {
+		case r == 0:
+			return buf[:size], nil
+		case errors.Is(errno, windows.ERROR_INSUFFICIENT_BUFFER):
+			// The table grew between calls; size now holds the required size
+			continue
+		default:
+			return nil, fmt.Errorf("GetExtendedTcpTable failed: %w", errno)
+		}
+	}
+}
+
+// findListener returns the owning PID of the row of a MIB_TCPTABLE_OWNER_PID or
+// MIB_TCP6TABLE_OWNER_PID table whose local port is port. Ports are stored in network
+// byte order in the low 16 bits of a DWORD.
+func findListener(data []byte, rowSize, portOffset, pidOffset int, port uint16) (int, bool) {
+	if len(data) < 4 {
+		return 0, false
+	}
+	count := int(binary.LittleEndian.Uint32(data))
+	// Rows are DWORD-aligned and follow the entry count
+	for i := range count {
+		row := 4 + i*rowSize
+		if row+rowSize > len(data) {
+			break
+		}
+		if binary.BigEndian.Uint16(data[row+portOffset:]) != port {
+			continue
+		}
+		return int(binary.LittleEndian.Uint32(data[row+pidOffset:])), true
+	}
+	return 0, false
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/jongio/azd-app/cli/src/internal/tracing"
 	"github.com/jongio/azd-core/cliout"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // CommandFunc represents a command execution function.
@@ -103,7 +105,13 @@ func (o *Orchestrator) runLocked(commandName string, visiting map[string]bool, i
 	}
 
 	// Execute the command
-	if err := cmd.Execute(); err != nil {
+	_, span := tracing.Start(tracing.Context(), commandName,
+		attribute.String("azd.app.command", commandName),
+		attribute.Bool("azd.app.dependency", isDependency),
+	)
+	err := cmd.Execute()
+	tracing.End(span, err)
+	if err != nil {
 		return fmt.Errorf("command %s failed: %w", commandName, err)
 	}
 
@@ -0,0 +1,115 @@
+// Package output holds how much azd app prints: -q/--quiet for only errors and the final
+// service URLs, -v for the full output of the tools it runs, and -vv for debug logs too.
+// Commands ask it instead of keeping verbose flags of their own.
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/jongio/azd-core/cliout"
+)
+
+// Level is the amount of output.
+type Level int
+
+// Output levels, from least to most output.
+const (
+	LevelQuiet   Level = iota - 1 // -q: only errors and the final service URLs
+	LevelNormal                   // the default
+	LevelVerbose                  // -v: the full output of installers, test runners and services
+	LevelDebug                    // -vv: debug logs too
+)
+
+var state = struct {
+	mu      sync.RWMutex
+	level   Level
+	results io.Writer // where quiet mode still prints results
+}{
+	level: LevelNormal,
+}
+
+// ParseLevel returns the level for the --quiet flag and the number of -v flags.
+func ParseLevel(quiet bool, verbosity int) (Level, error) {
+	switch {
+	case quiet && verbosity > 0:
+		return LevelNormal, fmt.Errorf("--quiet and --verbose can't be used together")
+	case quiet:
+		return LevelQuiet, nil
+	case verbosity >= 2:
+		return LevelDebug, nil
+	case verbosity == 1:
+		return LevelVerbose, nil
+	default:
+		return LevelNormal, nil
+	}
+}
+
+// SetLevel sets the output level. In quiet mode, everything printed to stdout is discarded
+// except the results printed with Result and the errors printed with Error; JSON output is
+// never discarded.
+func SetLevel(level Level) error {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.level = level
+	if level != LevelQuiet || cliout.IsJSON() || state.results != nil {
+		return nil
+	}
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", os.DevNull, err)
+	}
+	state.results = os.Stdout
+	os.Stdout = devNull
+	return nil
+}
+
+// GetLevel returns the output level.
+func GetLevel() Level {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	return state.level
+}
+
+// IsQuiet reports whether only errors and results are shown.
+func IsQuiet() bool {
+	return GetLevel() == LevelQuiet
+}
+
+// IsVerbose reports whether the full output of tools is shown (-v or -vv).
+func IsVerbose() bool {
+	return GetLevel() >= LevelVerbose
+}
+
+// IsDebug reports whether debug logs are shown (-vv).
+func IsDebug() bool {
+	return GetLevel() >= LevelDebug
+}
+
+// Results returns the writer for results that quiet mode still shows, such as service URLs.
+func Results() io.Writer {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	if state.results != nil {
+		return state.results
+	}
+	return os.Stdout
+}
+
+// Result prints a line of results, which quiet mode still shows.
+func Result(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(Results(), format+"\n", args...)
+}
+
+// Error prints an error, which quiet mode still shows on stderr.
+func Error(format string, args ...interface{}) {
+	if IsQuiet() {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
+		return
+	}
+	cliout.Error(format, args...)
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		quiet     bool
+		verbosity int
+		want      Level
+		wantErr   bool
+	}{
+		{false, 0, LevelNormal, false},
+		{true, 0, LevelQuiet, false},
+		{false, 1, LevelVerbose, false},
+		{false, 2, LevelDebug, false},
+		{false, 3, LevelDebug, false},
+		{true, 1, LevelNormal, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.quiet, tt.verbosity)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLevel(%v, %d) = %v, %v, want %v (error: %v)", tt.quiet, tt.verbosity, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLevels(t *testing.T) {
+	t.Cleanup(func() { state.level = LevelNormal })
+
+	tests := []struct {
+		level                     Level
+		quiet, verbose, debugLogs bool
+	}{
+		{LevelQuiet, true, false, false},
+		{LevelNormal, false, false, false},
+		{LevelVerbose, false, true, false},
+		{LevelDebug, false, true, true},
+	}
+	for _, tt := range tests {
+		state.level = tt.level
+		if IsQuiet() != tt.quiet || IsVerbose() != tt.verbose || IsDebug() != tt.debugLogs {
+			t.Errorf("level %d: IsQuiet() = %v, IsVerbose() = %v, IsDebug() = %v", tt.level, IsQuiet(), IsVerbose(), IsDebug())
+		}
+	}
+}
+
+func TestSetLevel_Quiet(t *testing.T) {
+	stdout := os.Stdout
+	path := filepath.Join(t.TempDir(), "stdout")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = file
+	t.Cleanup(func() {
+		os.Stdout = stdout
+		state.level = LevelNormal
+		state.results = nil
+		_ = file.Close()
+	})
+
+	if err := SetLevel(LevelQuiet); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	_, _ = os.Stdout.WriteString("progress\n")
+	Result("  Dashboard  %s", "http://localhost:5050")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "  Dashboard  http://localhost:5050\n" {
+		t.Errorf("quiet output = %q, want only the result", got)
+	}
+}
@@ -3,21 +3,66 @@ package portmanager
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"log/slog"
 	"math/big"
 	"net"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-app/cli/src/internal/teamconfig"
 )
 
-// IsPortAvailable checks if a port is available for binding.
+// IsPortAvailable checks if a TCP port is available for binding.
 // This is a public wrapper around the internal port checking logic.
 func (pm *PortManager) IsPortAvailable(port int) bool {
 	return pm.isPortAvailable(port)
 }
 
+// IsPortAvailableOn checks if a port is available for binding with the given protocol.
+func (pm *PortManager) IsPortAvailableOn(port int, protocol Protocol) bool {
+	return pm.isPortAvailableOn(port, protocol)
+}
+
+// IsSocketAvailable checks if a unix socket path is free for a service to listen on: nothing
+// exists at the path, or only a stale socket file that nothing accepts connections on.
+func IsSocketAvailable(path string) bool {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true
+	}
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return false
+	}
+	conn, err := net.DialTimeout("unix", path, socketDialTimeout)
+	if err != nil {
+		slog.Debug("unix socket is stale", "path", path, "error", err)
+		return true
+	}
+	_ = conn.Close()
+	slog.Debug("unix socket in use", "path", path)
+	return false
+}
+
+// WaitForPortRelease waits until a port is available for binding again, e.g. after the
+// service holding it was stopped. Returns false if the port is still in use after timeout.
+func (pm *PortManager) WaitForPortRelease(port int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if pm.isPortAvailable(port) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(portReleasePollInterval)
+	}
+}
+
 // ReservePort attempts to reserve a port by binding to it.
 // This eliminates TOCTOU race conditions by holding the port open until
 // the caller is ready to bind their service.
@@ -38,10 +83,10 @@ func (pm *PortManager) IsPortAvailable(port int) bool {
 //   - *PortReservation: Holds the port open. Call Release() before binding.
 //   - error: Non-nil if port cannot be reserved
 func (pm *PortManager) ReservePort(port int) (*PortReservation, error) {
-	// First check if any process is listening on this port via netstat/lsof
+	// First check if any process is listening on this port in the OS socket table
 	// This catches processes that use SO_REUSEADDR which would allow our bind
 	// but would still cause listen conflicts for the actual service
-	if _, err := pm.getProcessOnPort(port); err == nil {
+	if pm.hasListener(port) {
 		return nil, fmt.Errorf("port %d is in use by another process", port)
 	}
 
@@ -153,14 +198,28 @@ func (pm *PortManager) FindAndReservePort(serviceName string, preferredPort int)
 // This is a fundamental limitation of port allocation and cannot be fully eliminated
 // without holding the port open, which would prevent the caller from using it.
 func (pm *PortManager) isPortAvailable(port int) bool {
+	return pm.isPortAvailableOn(port, ProtocolTCP)
+}
+
+// isPortAvailableOn checks if a port is available for the given protocol (TCP when empty).
+func (pm *PortManager) isPortAvailableOn(port int, protocol Protocol) bool {
 	if pm.portChecker != nil {
 		return pm.portChecker(port)
 	}
+	if protocol == ProtocolUDP {
+		return defaultIsUDPPortAvailable(port)
+	}
 	return pm.defaultIsPortAvailable(port)
 }
 
+// isServicePortAvailable checks if a port is available for the protocol the service listens on.
+// Must be called with pm.mu held.
+func (pm *PortManager) isServicePortAvailable(serviceName string, port int) bool {
+	return pm.isPortAvailableOn(port, pm.protocols[serviceName])
+}
+
 // defaultIsPortAvailable is the default implementation that checks port availability.
-// It first checks if any process is listening on the port (via netstat/lsof),
+// It first checks if any process is listening on the port (in the OS socket table),
 // then verifies with a bind test. This dual approach catches both:
 // - Processes with SO_REUSEADDR that allow bind but will cause listen conflicts
 // - Processes with exclusive address use that will fail bind
@@ -168,9 +227,7 @@ func (pm *PortManager) defaultIsPortAvailable(port int) bool {
 	// First, check if any process is listening on this port
 	// This catches processes that use SO_REUSEADDR which would allow our bind
 	// but would still cause listen conflicts for the actual service
-	_, err := pm.getProcessOnPort(port)
-	if err == nil {
-		// A process is listening on this port
+	if pm.hasListener(port) {
 		slog.Debug("port in use by another process", "port", port)
 		return false
 	}
@@ -193,16 +250,31 @@ func (pm *PortManager) defaultIsPortAvailable(port int) bool {
 	return true
 }
 
-// verifyPortCleanup verifies that a port is available after cleanup, with retries.
+// defaultIsUDPPortAvailable checks if a UDP port is available with a bind test. UDP sockets
+// don't listen, so the bind test is all there is to check.
+func defaultIsUDPPortAvailable(port int) bool {
+	lc := net.ListenConfig{}
+	conn, err := lc.ListenPacket(context.Background(), "udp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		slog.Debug("udp port bind test failed", "port", port, "error", err)
+		return false
+	}
+	if err := conn.Close(); err != nil {
+		slog.Debug("failed to close udp socket during availability check", "port", port, "error", err)
+	}
+	return true
+}
+
+// verifyPortCleanup verifies that a service's port is available after cleanup, with retries.
 // This is necessary on Windows where port release can take longer, especially for system processes.
-func (pm *PortManager) verifyPortCleanup(port int) bool {
+func (pm *PortManager) verifyPortCleanup(serviceName string, port int) bool {
 	for attempt := 0; attempt < portCleanupRetries; attempt++ {
 		if attempt > 0 {
 			slog.Debug("retrying port cleanup verification", "port", port, "attempt", attempt+1)
 			time.Sleep(portCleanupRetryWait)
 		}
 
-		if pm.isPortAvailable(port) {
+		if pm.isServicePortAvailable(serviceName, port) {
 			if attempt > 0 {
 				slog.Debug("port became available after retry", "port", port, "attempts", attempt+1)
 			}
@@ -214,12 +286,15 @@ func (pm *PortManager) verifyPortCleanup(port int) bool {
 	return false
 }
 
-// findAvailablePort finds an available port in the port range.
+// findAvailablePort finds an available port in window for a service, skipping denied ports.
 // Uses cryptographically secure randomized starting point with bounded attempts to:
 // 1. Reduce collision probability when multiple services start simultaneously
 // 2. Avoid exhaustive scanning of the entire port range
 // 3. Prevent predictable port allocation patterns
-func (pm *PortManager) findAvailablePort() (int, error) {
+//
+// With the stable strategy, the search starts at a port derived from the project and
+// service names instead, so the service gets the same port on every machine.
+func (pm *PortManager) findAvailablePort(serviceName string, window portWindow) (int, error) {
 	// Build map of assigned ports to avoid duplicates
 	assignedPorts := make(map[int]bool)
 	for _, assignment := range pm.assignments {
@@ -227,33 +302,48 @@ func (pm *PortManager) findAvailablePort() (int, error) {
 	}
 
 	// Calculate port range size
-	rangeSize := pm.portRange.end - pm.portRange.start + 1
+	rangeSize := window.end - window.start + 1
 	if rangeSize <= 0 {
-		return 0, fmt.Errorf("invalid port range: %d-%d", pm.portRange.start, pm.portRange.end)
+		return 0, fmt.Errorf("invalid port range: %d-%d", window.start, window.end)
 	}
 
-	// Randomize starting point using crypto/rand for security
-	// This prevents predictable port allocation patterns that could be exploited
-	nBig, err := rand.Int(rand.Reader, big.NewInt(int64(rangeSize)))
-	if err != nil {
-		// Fallback to sequential search from start if crypto/rand fails
-		slog.Warn("failed to generate secure random offset, using sequential search", "error", err)
-		nBig = big.NewInt(0)
+	var startOffset int
+	if pm.strategy == teamconfig.StrategyStable {
+		startOffset = stablePortOffset(pm.projectDir, serviceName, rangeSize)
+	} else {
+		// Randomize starting point using crypto/rand for security
+		// This prevents predictable port allocation patterns that could be exploited
+		nBig, err := rand.Int(rand.Reader, big.NewInt(int64(rangeSize)))
+		if err != nil {
+			// Fallback to sequential search from start if crypto/rand fails
+			slog.Warn("failed to generate secure random offset, using sequential search", "error", err)
+			nBig = big.NewInt(0)
+		}
+		startOffset = int(nBig.Int64())
 	}
-	startOffset := int(nBig.Int64())
 
 	// Try maxPortScanAttempts ports starting from random position
 	for attempt := 0; attempt < maxPortScanAttempts && attempt < rangeSize; attempt++ {
 		// Wrap around the range using modulo arithmetic
-		port := pm.portRange.start + ((startOffset + attempt) % rangeSize)
+		port := window.start + ((startOffset + attempt) % rangeSize)
 
-		if assignedPorts[port] {
+		if assignedPorts[port] || pm.deniedPorts[port] {
 			continue
 		}
-		if pm.isPortAvailable(port) {
+		if pm.isServicePortAvailable(serviceName, port) {
 			return port, nil
 		}
 	}
 
-	return 0, fmt.Errorf("no available ports found after %d attempts in range %d-%d", maxPortScanAttempts, pm.portRange.start, pm.portRange.end)
+	return 0, fmt.Errorf("no available ports found after %d attempts in range %d-%d", maxPortScanAttempts, window.start, window.end)
+}
+
+// stablePortOffset returns the offset in a range of rangeSize ports that the stable strategy
+// starts the search for a service's port at. The project is identified by the name of its
+// directory rather than its full path, so teammates who clone it to different locations
+// get the same offset.
+func stablePortOffset(projectDir, serviceName string, rangeSize int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(filepath.Base(projectDir) + "/" + serviceName))
+	return int(h.Sum32() % uint32(rangeSize))
 }
@@ -22,6 +22,13 @@ const (
 	// ProcessKillTimeout is the maximum time to wait for a process kill command.
 	ProcessKillTimeout = 5 * time.Second
 
+	// PortReleaseTimeout is the maximum time to wait for a stopped service's port
+	// to be freed before the service is started again on it.
+	PortReleaseTimeout = 5 * time.Second
+
+	// portReleasePollInterval is how often WaitForPortRelease checks the port.
+	portReleasePollInterval = 100 * time.Millisecond
+
 	// StalePortCleanupAge is the age threshold for cleaning up stale port assignments.
 	// Assignments older than this are removed during cleanup.
 	StalePortCleanupAge = 7 * 24 * time.Hour
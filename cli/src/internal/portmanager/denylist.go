@@ -0,0 +1,58 @@
+package portmanager
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/jongio/azd-app/cli/src/internal/teamconfig"
+)
+
+// envPortDenylist adds ports and ranges, separated by commas, to the denylist.
+const envPortDenylist = "AZD_PORT_DENYLIST"
+
+// defaultDeniedPorts are ports commonly taken by other tools. Automatic assignment never
+// picks them; ports set for a service in azure.yaml and detected framework defaults can
+// still use them.
+var defaultDeniedPorts = []int{
+	3306,  // MySQL
+	5000,  // macOS AirPlay Receiver
+	5432,  // PostgreSQL
+	5672,  // RabbitMQ
+	6379,  // Redis
+	7000,  // macOS AirPlay Receiver
+	7071,  // Azure Functions host
+	8081,  // Azure Cosmos DB emulator
+	9200,  // Elasticsearch
+	10000, // Azurite Blob
+	10001, // Azurite Queue
+	10002, // Azurite Table
+	11211, // Memcached
+	27017, // MongoDB
+}
+
+// deniedPorts returns the ports automatic assignment skips: the defaults, the ports in
+// AZD_PORT_DENYLIST and the team's ports.denylist.
+func deniedPorts(team *teamconfig.Defaults) map[int]bool {
+	denied := make(map[int]bool, len(defaultDeniedPorts))
+	for _, port := range defaultDeniedPorts {
+		denied[port] = true
+	}
+
+	if val := os.Getenv(envPortDenylist); val != "" {
+		ports, err := teamconfig.ParsePortList(strings.Split(val, ","))
+		if err != nil {
+			slog.Warn("invalid port denylist, ignoring", "value", val, "error", err)
+		}
+		for _, port := range ports {
+			denied[port] = true
+		}
+	}
+
+	// Team defaults are validated when loaded
+	ports, _ := teamconfig.ParsePortList(team.Ports.Denylist)
+	for _, port := range ports {
+		denied[port] = true
+	}
+	return denied
+}
@@ -0,0 +1,59 @@
+package portmanager
+
+import (
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/teamconfig"
+)
+
+func TestDeniedPorts(t *testing.T) {
+	t.Setenv(envPortDenylist, "4100, 4200-4202")
+	team := &teamconfig.Defaults{Ports: teamconfig.PortDefaults{Denylist: []string{"4300"}}}
+
+	denied := deniedPorts(team)
+	for _, port := range []int{5000, 6379, 7071, 4100, 4200, 4201, 4202, 4300} {
+		if !denied[port] {
+			t.Errorf("port %d should be denied", port)
+		}
+	}
+	if denied[4203] || denied[3000] {
+		t.Error("ports outside the denylist should not be denied")
+	}
+
+	// An invalid environment variable keeps the other denied ports
+	t.Setenv(envPortDenylist, "not-a-port")
+	denied = deniedPorts(team)
+	if !denied[5000] || !denied[4300] || denied[4100] {
+		t.Errorf("deniedPorts() with an invalid %s = %v", envPortDenylist, denied)
+	}
+}
+
+func TestFindAvailablePort_SkipsDeniedPorts(t *testing.T) {
+	pm := setupTestManager(t.TempDir(), nil)
+
+	for i := 0; i < 10; i++ {
+		port, err := pm.findAvailablePort("api", portWindow{start: 4999, end: 5001})
+		if err != nil {
+			t.Fatalf("findAvailablePort() error = %v", err)
+		}
+		if port == 5000 {
+			t.Fatal("findAvailablePort() picked denied port 5000")
+		}
+	}
+
+	if port, err := pm.findAvailablePort("api", portWindow{start: 5000, end: 5000}); err == nil {
+		t.Errorf("findAvailablePort() = %d, want an error when every port is denied", port)
+	}
+}
+
+func TestAssignPort_DeniedPortsAllowedWhenSet(t *testing.T) {
+	pm := setupTestManager(t.TempDir(), nil)
+
+	// Explicit ports and preferred ports such as framework defaults aren't restricted
+	if port, _, err := pm.AssignPort("airplay", 5000, true); err != nil || port != 5000 {
+		t.Errorf("AssignPort(explicit 5000) = %d, %v, want 5000", port, err)
+	}
+	if port, _, err := pm.AssignPort("functions", 7071, false); err != nil || port != 7071 {
+		t.Errorf("AssignPort(preferred 7071) = %d, %v, want 7071", port, err)
+	}
+}
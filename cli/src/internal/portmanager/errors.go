@@ -20,6 +20,25 @@ func (e *PortInUseError) Error() string {
 		e.Port, e.ServiceName, e.PID)
 }
 
+// KillRefusedError is returned when the process on a port is not killed because it was
+// not started by azd-app, is not a known dev server, and --force-kill was not given.
+type KillRefusedError struct {
+	Port        int
+	PID         int
+	ProcessName string
+	CommandLine string
+}
+
+// Error implements the error interface.
+func (e *KillRefusedError) Error() string {
+	process := fmt.Sprintf("PID %d", e.PID)
+	if e.ProcessName != "" {
+		process = fmt.Sprintf("%s (PID %d)", e.ProcessName, e.PID)
+	}
+	return fmt.Sprintf("refusing to kill %s on port %d: it was not started by azd-app and is not a known dev server (use --force-kill to override)",
+		process, e.Port)
+}
+
 // PortRangeExhaustedError represents an error when no ports are available in the range.
 type PortRangeExhaustedError struct {
 	StartPort int
@@ -90,3 +90,14 @@ func TestInvalidPortError_Implementation(t *testing.T) {
 	// Verify it implements error interface
 	var _ error = &InvalidPortError{}
 }
+
+func TestKillRefusedError_Error(t *testing.T) {
+	err := &KillRefusedError{Port: 5432, PID: 99, ProcessName: "postgres"}
+
+	errMsg := err.Error()
+	for _, want := range []string{"5432", "99", "postgres", "--force-kill"} {
+		if !strings.Contains(errMsg, want) {
+			t.Errorf("Error() = %q, should contain %q", errMsg, want)
+		}
+	}
+}
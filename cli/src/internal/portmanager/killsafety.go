@@ -0,0 +1,207 @@
+package portmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// killAuditDir is the directory in <project>/.azure of the kill audit log. It is kept apart from
+// .azure/logs, whose files are service logs that 'azd app logs' shows and retention prunes.
+const killAuditDir = "audit"
+
+// killAuditFile is the file in killAuditDir that records every kill decision.
+const killAuditFile = "port-kills.log"
+
+// maxAncestorDepth limits how far up the process tree ownership is checked, so a dev
+// server spawned by a service's `npm run dev` still counts as started by azd-app.
+const maxAncestorDepth = 5
+
+// devServerProcessNames are processes that are safe to kill on a port conflict: language
+// runtimes, package managers and dev servers that commonly hold a port left over from a
+// previous run. Names match exactly, or with a version suffix (e.g. python3.12).
+var devServerProcessNames = []string{
+	"node", "npm", "npx", "pnpm", "yarn", "bun", "deno", "tsx", "ts-node", "nodemon",
+	"next-server", "vite", "esbuild", "webpack",
+	"python", "py", "uvicorn", "gunicorn", "hypercorn", "flask", "streamlit",
+	"dotnet", "java", "go", "air", "func", "php", "ruby", "rails", "puma", "cargo",
+	"azurite",
+}
+
+// forceKill allows killing any process on a port (--force-kill).
+var forceKill atomic.Bool
+
+// SetForceKill allows killing processes on a port that were not started by azd-app
+// and are not known dev servers. It applies to every port manager in this process.
+func SetForceKill(enabled bool) {
+	forceKill.Store(enabled)
+}
+
+// RecordProcess records that azd-app started the process with the given PID for a service,
+// which makes it (and its children) safe to kill when its port is needed again.
+func (pm *PortManager) RecordProcess(serviceName string, pid int) {
+	if pid <= 0 {
+		return
+	}
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if pm.ownedPIDs == nil {
+		pm.ownedPIDs = make(map[int]string)
+	}
+	for existing, name := range pm.ownedPIDs {
+		if name == serviceName {
+			delete(pm.ownedPIDs, existing)
+		}
+	}
+	pm.ownedPIDs[pid] = serviceName
+}
+
+// isDevServerProcess reports whether a process name is in the dev server allowlist.
+func isDevServerProcess(name string) bool {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return false
+	}
+	// ps may report a full path (macOS) and tasklist a .exe suffix
+	base := strings.ToLower(filepath.Base(strings.ReplaceAll(fields[0], `\`, "/")))
+	base = strings.TrimSuffix(base, ".exe")
+
+	for _, allowed := range devServerProcessNames {
+		if base == allowed {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(base, allowed); ok && strings.Trim(suffix, "0123456789.") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// killReason returns why the process may be killed, or "" if it must not be killed.
+// owned maps PIDs started by azd-app to their service names.
+func (pm *PortManager) killReason(pid int, name string, owned map[int]string) string {
+	current := pid
+	for depth := 0; depth <= maxAncestorDepth && current > 1; depth++ {
+		if serviceName, ok := owned[current]; ok {
+			return fmt.Sprintf("started by azd-app for service '%s'", serviceName)
+		}
+		current = pm.getParentPID(current)
+	}
+	if isDevServerProcess(name) {
+		return "dev server allowlist"
+	}
+	if forceKill.Load() {
+		return "--force-kill"
+	}
+	return ""
+}
+
+// buildGetProcessCommandLineCommand returns the command and args to get the full command line of a process.
+func buildGetProcessCommandLineCommand(pid int) (cmd string, args []string) {
+	if runtime.GOOS == osWindows {
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command",
+			fmt.Sprintf("(Get-CimInstance Win32_Process -Filter 'ProcessId=%d').CommandLine", pid)}
+	}
+	return "ps", []string{"-p", strconv.Itoa(pid), "-o", "args="}
+}
+
+// buildGetParentPIDCommand returns the command and args to get the parent PID of a process.
+func buildGetParentPIDCommand(pid int) (cmd string, args []string) {
+	if runtime.GOOS == osWindows {
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command",
+			fmt.Sprintf("(Get-CimInstance Win32_Process -Filter 'ProcessId=%d').ParentProcessId", pid)}
+	}
+	return "ps", []string{"-p", strconv.Itoa(pid), "-o", "ppid="}
+}
+
+// getProcessCommandLine returns the full command line of a process, or "" if it can't be read.
+func (pm *PortManager) getProcessCommandLine(pid int) string {
+	cmd, args := buildGetProcessCommandLineCommand(pid)
+	output, err := runProcessQuery(cmd, args)
+	if err != nil {
+		slog.Debug("failed to get process command line", "pid", pid, "error", err)
+		return ""
+	}
+	return output
+}
+
+// getParentPID returns the parent PID of a process, or 0 if it can't be determined.
+func (pm *PortManager) getParentPID(pid int) int {
+	cmd, args := buildGetParentPIDCommand(pid)
+	output, err := runProcessQuery(cmd, args)
+	if err != nil {
+		return 0
+	}
+	ppid, err := strconv.Atoi(output)
+	if err != nil {
+		return 0
+	}
+	return ppid
+}
+
+// runProcessQuery runs a process inspection command with a timeout and returns its trimmed output.
+func runProcessQuery(cmd string, args []string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	// #nosec G204 -- cmd is a hard-coded binary, args only contain a validated int PID
+	execCmd := exec.CommandContext(ctx, cmd, args...)
+	// Don't inherit stdin - prevents blocking in non-interactive environments
+	execCmd.Stdin = nil
+	output, err := execCmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// killAuditEntry is one line of the kill audit log.
+type killAuditEntry struct {
+	Time        time.Time `json:"time"`
+	Port        int       `json:"port"`
+	PID         int       `json:"pid"`
+	ProcessName string    `json:"processName,omitempty"`
+	CommandLine string    `json:"commandLine,omitempty"`
+	Action      string    `json:"action"` // "killed", "refused" or "failed"
+	Reason      string    `json:"reason,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// writeKillAudit appends an entry to <project>/.azure/audit/port-kills.log.
+// Audit failures are logged but never block the kill itself.
+func (pm *PortManager) writeKillAudit(entry killAuditEntry) {
+	entry.Time = time.Now().UTC()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	auditDir := filepath.Join(pm.projectDir, ".azure", killAuditDir)
+	if err := os.MkdirAll(auditDir, 0o750); err != nil {
+		slog.Warn("failed to create kill audit log directory", "dir", auditDir, "error", err)
+		return
+	}
+
+	path := filepath.Join(auditDir, killAuditFile)
+	// #nosec G304 -- path is built from the project directory and a constant file name
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		slog.Warn("failed to open kill audit log", "path", path, "error", err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		slog.Warn("failed to write kill audit log", "path", path, "error", err)
+	}
+}
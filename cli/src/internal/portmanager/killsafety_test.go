@@ -0,0 +1,154 @@
+package portmanager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestIsDevServerProcess(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"node", true},
+		{"node.exe", true},
+		{"Python.exe", true},
+		{"python3", true},
+		{"python3.12", true},
+		{"/usr/local/bin/node", true},
+		{`C:\tools\dotnet\dotnet.exe`, true},
+		{"uvicorn main:app --port 8000", true},
+		{"nodejs-helper", false},
+		{"postgres", false},
+		{"sshd", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDevServerProcess(tt.name); got != tt.want {
+				t.Errorf("isDevServerProcess(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKillReason(t *testing.T) {
+	pm := setupTestManager(t.TempDir(), nil)
+	t.Cleanup(func() { SetForceKill(false) })
+
+	// A PID that is very unlikely to exist, so the ancestor walk stops immediately
+	const unknownPID = 999999
+
+	if reason := pm.killReason(os.Getpid(), "postgres", map[int]string{os.Getpid(): "api"}); !strings.Contains(reason, "api") {
+		t.Errorf("owned process: reason = %q, want it to name service 'api'", reason)
+	}
+
+	if reason := pm.killReason(unknownPID, "node", nil); reason != "dev server allowlist" {
+		t.Errorf("dev server: reason = %q, want %q", reason, "dev server allowlist")
+	}
+
+	if reason := pm.killReason(unknownPID, "postgres", nil); reason != "" {
+		t.Errorf("unknown process: reason = %q, want refusal", reason)
+	}
+
+	SetForceKill(true)
+	if reason := pm.killReason(unknownPID, "postgres", nil); reason != "--force-kill" {
+		t.Errorf("unknown process with force-kill: reason = %q, want %q", reason, "--force-kill")
+	}
+}
+
+func TestKillReason_OwnedAncestor(t *testing.T) {
+	if runtime.GOOS == osWindows {
+		t.Skip("parent PID lookup uses PowerShell on Windows")
+	}
+	pm := setupTestManager(t.TempDir(), nil)
+
+	// The test process is a child of the owned process, like `node` under `npm run dev`
+	reason := pm.killReason(os.Getpid(), "postgres", map[int]string{os.Getppid(): "web"})
+	if !strings.Contains(reason, "web") {
+		t.Errorf("reason = %q, want it to name service 'web'", reason)
+	}
+}
+
+func TestRecordProcess_ReplacesPreviousPID(t *testing.T) {
+	pm := setupTestManager(t.TempDir(), nil)
+
+	pm.RecordProcess("api", 100)
+	pm.RecordProcess("api", 200)
+	pm.RecordProcess("web", 0)
+
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	if _, ok := pm.ownedPIDs[100]; ok {
+		t.Error("expected old PID of restarted service to be forgotten")
+	}
+	if pm.ownedPIDs[200] != "api" {
+		t.Errorf("ownedPIDs[200] = %q, want %q", pm.ownedPIDs[200], "api")
+	}
+	if len(pm.ownedPIDs) != 1 {
+		t.Errorf("expected 1 owned PID, got %d", len(pm.ownedPIDs))
+	}
+}
+
+func TestWriteKillAudit(t *testing.T) {
+	tempDir := t.TempDir()
+	pm := setupTestManager(tempDir, nil)
+
+	pm.writeKillAudit(killAuditEntry{Port: 3000, PID: 42, ProcessName: "node", CommandLine: "node server.js", Action: "killed", Reason: "dev server allowlist"})
+	pm.writeKillAudit(killAuditEntry{Port: 5432, PID: 43, ProcessName: "postgres", Action: "refused"})
+
+	data, err := os.ReadFile(filepath.Join(tempDir, ".azure", killAuditDir, killAuditFile))
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d: %q", len(lines), data)
+	}
+
+	var entry killAuditEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("failed to parse audit entry: %v", err)
+	}
+	if entry.Port != 3000 || entry.PID != 42 || entry.Action != "killed" || entry.CommandLine != "node server.js" {
+		t.Errorf("unexpected audit entry: %+v", entry)
+	}
+	if entry.Time.IsZero() {
+		t.Error("expected audit entry to have a timestamp")
+	}
+
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("failed to parse audit entry: %v", err)
+	}
+	if entry.Action != "refused" {
+		t.Errorf("Action = %q, want %q", entry.Action, "refused")
+	}
+}
+
+func TestBuildProcessQueryCommands(t *testing.T) {
+	cmd, args := buildGetProcessCommandLineCommand(1234)
+	parentCmd, parentArgs := buildGetParentPIDCommand(1234)
+
+	if runtime.GOOS == osWindows {
+		if cmd != "powershell" || !strings.Contains(strings.Join(args, " "), "ProcessId=1234") {
+			t.Errorf("command line query = %s %v", cmd, args)
+		}
+		if parentCmd != "powershell" || !strings.Contains(strings.Join(parentArgs, " "), "ParentProcessId") {
+			t.Errorf("parent PID query = %s %v", parentCmd, parentArgs)
+		}
+		return
+	}
+
+	if cmd != "ps" || strings.Join(args, " ") != "-p 1234 -o args=" {
+		t.Errorf("command line query = %s %v", cmd, args)
+	}
+	if parentCmd != "ps" || strings.Join(parentArgs, " ") != "-p 1234 -o ppid=" {
+		t.Errorf("parent PID query = %s %v", parentCmd, parentArgs)
+	}
+}
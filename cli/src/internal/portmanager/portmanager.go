@@ -3,15 +3,19 @@ package portmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/azdconfig"
+	"github.com/jongio/azd-app/cli/src/internal/teamconfig"
 )
 
 // PortManager manages port assignments for services.
@@ -20,10 +24,15 @@ type PortManager struct {
 	assignments map[string]*PortAssignment // key: serviceName
 	projectDir  string                     // absolute path to project directory
 	projectHash string                     // hash of projectDir for config keys
-	portRange   struct {
-		start int
-		end   int
-	}
+	portRange   portWindow
+	// strategy is how the search for a free port picks its starting point (see teamconfig)
+	strategy string
+	// deniedPorts are never picked by automatic assignment (see denylist.go)
+	deniedPorts map[int]bool
+	// protocols holds the protocol of services that don't listen on TCP
+	protocols map[string]Protocol
+	// serviceRanges restricts the ports automatically assigned to some services (portRange in azure.yaml)
+	serviceRanges map[string]portWindow
 	// portChecker is a function that checks if a port is available
 	// This can be overridden in tests to avoid network binding
 	portChecker func(port int) bool
@@ -32,12 +41,34 @@ type PortManager struct {
 	// sessionAlwaysKill tracks if user selected "always kill" during this session.
 	// This ensures the preference is honored immediately without waiting for config reload.
 	sessionAlwaysKill bool
+	// conflictPolicy is the team default for port conflicts (see teamconfig).
+	// Empty means prompt the user.
+	conflictPolicy string
+	// ownedPIDs maps PIDs of processes started by azd-app in this session to their service
+	// names. Killing them on a port conflict is always allowed (see killReason).
+	ownedPIDs map[int]string
+}
+
+// portWindow is an inclusive range of ports.
+type portWindow struct {
+	start int
+	end   int
+}
+
+// contains reports whether port is in the window.
+func (w portWindow) contains(port int) bool {
+	return port >= w.start && port <= w.end
 }
 
-// cacheEntry holds a port manager with LRU tracking
+// cacheEntry holds a port manager with LRU tracking.
+// Each entry has its own lock so creating or refreshing one project's manager
+// (which may call azd over gRPC) does not block lookups for other projects.
 type cacheEntry struct {
-	manager  *PortManager
-	lastUsed time.Time
+	lastUsed time.Time // guarded by managerCacheMu
+
+	mu      sync.Mutex
+	manager *PortManager
+	stamp   stateStamp // project and port state when assignments were loaded
 }
 
 // preferenceAlwaysKillPortConflicts is the config key for the always-kill preference.
@@ -66,10 +97,72 @@ var (
 // Caching: Port managers are cached per absolute path with an LRU policy (max 50 entries).
 // Multiple calls with the same projectDir (after normalization) return the same instance.
 // The cache is per-process; different azd processes do not share cached instances.
+// The global cache lock only guards the map; creating and refreshing a manager is done
+// under a per-project lock, so a slow project never serializes unrelated projects.
+//
+// Refresh: when the project's .azure directory is deleted or recreated, or the file the state
+// backend keeps port assignments in is modified (e.g. by another process), the cached manager
+// reloads its assignments on the next call. Other writes to .azure (logs, session files) don't.
+// Use InvalidateCache to drop a project's manager explicitly.
 //
 // Note: The cache helps with performance in long-running processes but does not provide
 // cross-process synchronization. File-based persistence handles multi-process coordination.
 func GetPortManager(projectDir string) *PortManager {
+	absPath := normalizeProjectDir(projectDir)
+	slog.Debug("getting port manager", "path", projectDir, "normalized", absPath)
+
+	managerCacheMu.Lock()
+	entry, exists := managerCache[absPath]
+	if !exists {
+		// Evict oldest entry if cache is full
+		if len(managerCache) >= maxCacheSize {
+			evictOldestCacheEntry()
+		}
+		entry = &cacheEntry{}
+		managerCache[absPath] = entry
+	}
+	entry.lastUsed = time.Now()
+	portChecker := globalTestPortChecker
+	managerCacheMu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	stamp := currentStateStamp(absPath)
+	if entry.manager == nil {
+		entry.manager = newPortManager(absPath, portChecker)
+		entry.stamp = stamp
+		return entry.manager
+	}
+
+	if !stamp.equal(entry.stamp) {
+		slog.Debug("project state changed, reloading port assignments", "path", absPath)
+		if err := entry.manager.reload(); err != nil {
+			slog.Warn("failed to reload port assignments from config", "error", err)
+		}
+		entry.stamp = stamp
+	} else {
+		slog.Debug("returning cached port manager", "path", absPath)
+	}
+	return entry.manager
+}
+
+// InvalidateCache drops the cached port manager for projectDir.
+// The next GetPortManager call for the project creates a fresh manager that reloads
+// assignments and team defaults. Managers already handed out keep working.
+func InvalidateCache(projectDir string) {
+	absPath := normalizeProjectDir(projectDir)
+
+	managerCacheMu.Lock()
+	defer managerCacheMu.Unlock()
+	if _, exists := managerCache[absPath]; exists {
+		slog.Debug("invalidating cached port manager", "path", absPath)
+		delete(managerCache, absPath)
+	}
+}
+
+// normalizeProjectDir resolves projectDir to the absolute, symlink-free path used as cache key.
+func normalizeProjectDir(projectDir string) string {
 	if projectDir == "" {
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -93,40 +186,63 @@ func GetPortManager(projectDir string) *PortManager {
 	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
 		absPath = resolved
 	}
+	return absPath
+}
 
-	slog.Debug("getting port manager", "path", projectDir, "normalized", absPath)
-
-	managerCacheMu.Lock()
-	defer managerCacheMu.Unlock()
+// stateStamp identifies the state a cached manager's assignments were loaded from.
+type stateStamp struct {
+	azureDir   os.FileInfo // The project's .azure directory, nil if it does not exist
+	stateMtime time.Time   // Modification time of the state backend's file, zero if none
+}
 
-	// Check cache and update last used time
-	if entry, exists := managerCache[absPath]; exists {
-		entry.lastUsed = time.Now()
-		slog.Debug("returning cached port manager", "path", absPath)
-		return entry.manager
+// currentStateStamp returns the stamp of the project's .azure directory and the state file.
+func currentStateStamp(projectDir string) stateStamp {
+	var stamp stateStamp
+	if info, err := os.Stat(filepath.Join(projectDir, ".azure")); err == nil {
+		stamp.azureDir = info
+	}
+	if stateFile := azdconfig.StateFile(); stateFile != "" {
+		if info, err := os.Stat(stateFile); err == nil {
+			stamp.stateMtime = info.ModTime()
+		}
 	}
+	return stamp
+}
 
-	// Evict oldest entry if cache is full
-	if len(managerCache) >= maxCacheSize {
-		evictOldestCacheEntry()
+// equal reports whether both stamps are of the same .azure directory (not a deleted and
+// recreated one) and the same version of the state file.
+func (s stateStamp) equal(other stateStamp) bool {
+	if (s.azureDir == nil) != (other.azureDir == nil) {
+		return false
+	}
+	if s.azureDir != nil && !os.SameFile(s.azureDir, other.azureDir) {
+		return false
 	}
+	return s.stateMtime.Equal(other.stateMtime)
+}
 
+// newPortManager creates a port manager for absPath and loads its assignments.
+func newPortManager(absPath string, portChecker func(int) bool) *PortManager {
 	slog.Debug("creating new port manager", "path", absPath)
 
 	manager := &PortManager{
-		assignments: make(map[string]*PortAssignment),
-		projectDir:  absPath,
-		projectHash: azdconfig.ProjectHash(absPath),
+		assignments:   make(map[string]*PortAssignment),
+		ownedPIDs:     make(map[int]string),
+		serviceRanges: make(map[string]portWindow),
+		projectDir:    absPath,
+		projectHash:   azdconfig.ProjectHash(absPath),
 	}
 
 	// Configure port range from environment or use defaults
 	manager.portRange.start = getPortRangeStart()
 	manager.portRange.end = getPortRangeEnd()
+	manager.strategy = getPortStrategy()
+	manager.applyTeamDefaults(teamconfig.ForProject(absPath))
 	slog.Debug("port range configured", "start", manager.portRange.start, "end", manager.portRange.end)
 
 	// Set port checker - use global test checker if set, otherwise default
-	if globalTestPortChecker != nil {
-		manager.portChecker = globalTestPortChecker
+	if portChecker != nil {
+		manager.portChecker = portChecker
 	} else {
 		manager.portChecker = manager.defaultIsPortAvailable
 	}
@@ -135,11 +251,6 @@ func GetPortManager(projectDir string) *PortManager {
 	if err := manager.load(); err != nil {
 		slog.Warn("failed to load port assignments from config", "error", err)
 	}
-
-	managerCache[absPath] = &cacheEntry{
-		manager:  manager,
-		lastUsed: time.Now(),
-	}
 	return manager
 }
 
@@ -216,6 +327,63 @@ func getPortRangeEnd() int {
 	return 65535 // Default: maximum valid port
 }
 
+// getPortStrategy returns the port allocation strategy set in the environment, or "" when unset.
+func getPortStrategy() string {
+	val := strings.ToLower(os.Getenv(envPortStrategy))
+	switch val {
+	case "", teamconfig.StrategyRandom, teamconfig.StrategyStable:
+		return val
+	}
+	slog.Warn("invalid port strategy, using default", "value", val)
+	return ""
+}
+
+// applyTeamDefaults applies committed team defaults for settings the user has not
+// configured. Environment variables always take precedence over team defaults.
+func (pm *PortManager) applyTeamDefaults(team *teamconfig.Defaults) {
+	if os.Getenv(envPortRangeStart) == "" && team.Ports.RangeStart > 0 {
+		pm.portRange.start = team.Ports.RangeStart
+	}
+	if os.Getenv(envPortRangeEnd) == "" && team.Ports.RangeEnd > 0 {
+		pm.portRange.end = team.Ports.RangeEnd
+	}
+	pm.conflictPolicy = team.ConflictPolicy()
+	if pm.strategy == "" {
+		pm.strategy = team.PortStrategy()
+	}
+	pm.deniedPorts = deniedPorts(team)
+}
+
+// SetServicePortRange restricts the ports automatically assigned to a service to start-end
+// (portRange in azure.yaml) instead of the global range. An end of 0 removes the restriction.
+// Explicit ports are not restricted.
+func (pm *PortManager) SetServicePortRange(serviceName string, start, end int) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if end == 0 {
+		delete(pm.serviceRanges, serviceName)
+		return nil
+	}
+	if start < 1 || end > 65535 || start > end {
+		return fmt.Errorf("invalid port range %d-%d for service '%s'", start, end, serviceName)
+	}
+	if pm.serviceRanges == nil {
+		pm.serviceRanges = make(map[string]portWindow)
+	}
+	pm.serviceRanges[serviceName] = portWindow{start: start, end: end}
+	return nil
+}
+
+// rangeFor returns the range ports are automatically assigned from for a service.
+// Must be called with pm.mu held.
+func (pm *PortManager) rangeFor(serviceName string) portWindow {
+	if window, ok := pm.serviceRanges[serviceName]; ok {
+		return window
+	}
+	return pm.portRange
+}
+
 // AssignPort assigns or retrieves a port for a service.
 //
 // Parameters:
@@ -226,7 +394,8 @@ func getPortRangeEnd() int {
 //     existing process or choose a different port.
 //
 // Returns:
-//   - port: The assigned port number (guaranteed to be in the valid range 3000-65535)
+//   - port: The assigned port number (guaranteed to be in the valid range 3000-65535, or in the
+//     service's range when set with SetServicePortRange)
 //   - wasAutoAssigned: True if the user was prompted and chose to auto-assign a different port.
 //     This signals that azure.yaml should be updated with the new port.
 //   - error: Non-nil if the assignment failed (validation error, user canceled, no ports available)
@@ -253,7 +422,15 @@ func getPortRangeEnd() int {
 // There is a Time-Of-Check-Time-Of-Use race between checking port availability and the
 // caller binding to it. Another process could bind to the port in the interim. Callers
 // MUST handle port binding failures gracefully and may retry by calling AssignPort again.
+// In reservation mode (see SetReservationMode) the assigned port is held open until the
+// caller releases it with ReleaseHeldPort, which closes that window.
 func (pm *PortManager) AssignPort(serviceName string, preferredPort int, isExplicit bool) (int, bool, error) {
+	return pm.AssignPortWithProtocol(serviceName, preferredPort, isExplicit, ProtocolTCP)
+}
+
+// AssignPortWithProtocol is AssignPort for a service that listens on the given protocol,
+// such as a QUIC/HTTP3 dev server on UDP. Port availability is checked with that protocol.
+func (pm *PortManager) AssignPortWithProtocol(serviceName string, preferredPort int, isExplicit bool, protocol Protocol) (int, bool, error) {
 	// Validate inputs
 	if serviceName == "" {
 		return 0, false, fmt.Errorf("serviceName cannot be empty")
@@ -261,17 +438,51 @@ func (pm *PortManager) AssignPort(serviceName string, preferredPort int, isExpli
 	if isExplicit && (preferredPort <= 0 || preferredPort > 65535) {
 		return 0, false, fmt.Errorf("explicit port must be between 1-65535, got %d", preferredPort)
 	}
+	if protocol != "" && protocol != ProtocolTCP && protocol != ProtocolUDP {
+		return 0, false, fmt.Errorf("unsupported protocol %q for service '%s'", protocol, serviceName)
+	}
 
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
-	// EXPLICIT PORT MODE: Port from azure.yaml - MUST be used, prompt if in use
+	if protocol == ProtocolUDP {
+		if pm.protocols == nil {
+			pm.protocols = make(map[string]Protocol)
+		}
+		pm.protocols[serviceName] = protocol
+	} else {
+		delete(pm.protocols, serviceName)
+	}
+
+	// A port still held for the service since its last assignment remains its port
+	if port, held := pm.heldPortOf(serviceName); held {
+		if !isExplicit || port == preferredPort {
+			return port, false, nil
+		}
+		ReleaseHeldPort(port)
+	}
+
+	var port int
+	var shouldUpdate bool
+	var err error
 	if isExplicit {
-		return pm.assignExplicitPort(serviceName, preferredPort)
+		// EXPLICIT PORT MODE: Port from azure.yaml - MUST be used, prompt if in use
+		port, shouldUpdate, err = pm.assignExplicitPort(serviceName, preferredPort)
+	} else {
+		// FLEXIBLE PORT MODE: Port can be changed if needed, prompt user when conflicts detected
+		port, shouldUpdate, err = pm.assignFlexiblePort(serviceName, preferredPort)
+	}
+	if err != nil {
+		return 0, false, err
 	}
 
-	// FLEXIBLE PORT MODE: Port can be changed if needed, prompt user when conflicts detected
-	return pm.assignFlexiblePort(serviceName, preferredPort)
+	if reservationModeEnabled() {
+		// Not fatal: the port is checked again when the service starts
+		if holdErr := pm.HoldPort(serviceName, port); holdErr != nil {
+			slog.Debug("failed to hold assigned port", "service", serviceName, "port", port, "error", holdErr)
+		}
+	}
+	return port, shouldUpdate, nil
 }
 
 // assignExplicitPort handles port assignment when the port is explicit (from azure.yaml).
@@ -283,8 +494,12 @@ func (pm *PortManager) assignExplicitPort(serviceName string, port int) (int, bo
 			port, serviceName, pm.portRange.start, pm.portRange.end)
 	}
 
+	if owner, held := pm.heldForOtherService(serviceName, port); held {
+		return 0, false, fmt.Errorf("explicit port %d for service '%s' is reserved for service '%s'", port, serviceName, owner)
+	}
+
 	// Check if port is available
-	if pm.isPortAvailable(port) {
+	if pm.isServicePortAvailable(serviceName, port) {
 		return pm.saveAssignment(serviceName, port, false)
 	}
 
@@ -296,13 +511,26 @@ func (pm *PortManager) assignExplicitPort(serviceName string, port int) (int, bo
 // assignFlexiblePort handles port assignment when the port is flexible (can be changed).
 // Must be called with pm.mu held. May temporarily release the lock for user input.
 func (pm *PortManager) assignFlexiblePort(serviceName string, preferredPort int) (int, bool, error) {
+	window := pm.rangeFor(serviceName)
+
 	// Check if we already have an assignment
 	if assignment, exists := pm.assignments[serviceName]; exists {
+		// An assignment made before the service's range changed is replaced
+		if !window.contains(assignment.Port) {
+			slog.Debug("assigned port is outside the service's port range", "service", serviceName, "port", assignment.Port, "start", window.start, "end", window.end)
+			return pm.autoAssignPort(serviceName)
+		}
+
 		assignment.LastUsed = time.Now()
 		slog.Debug("checking assigned port", "service", serviceName, "port", assignment.Port)
 
+		// Ports held for other services are never offered for killing
+		if _, held := pm.heldForOtherService(serviceName, assignment.Port); held {
+			return pm.autoAssignPort(serviceName)
+		}
+
 		// Check if assigned port is available
-		if pm.isPortAvailable(assignment.Port) {
+		if pm.isServicePortAvailable(serviceName, assignment.Port) {
 			slog.Debug("assigned port is available", "service", serviceName, "port", assignment.Port)
 			if err := pm.save(); err != nil {
 				return 0, false, fmt.Errorf("failed to save port assignment: %w", err)
@@ -317,10 +545,14 @@ func (pm *PortManager) assignFlexiblePort(serviceName string, preferredPort int)
 	}
 
 	// Try preferred port first (if provided and in range)
-	if preferredPort >= pm.portRange.start && preferredPort <= pm.portRange.end {
+	if window.contains(preferredPort) {
 		slog.Debug("checking preferred port", "service", serviceName, "port", preferredPort)
 
-		if pm.isPortAvailable(preferredPort) {
+		if _, held := pm.heldForOtherService(serviceName, preferredPort); held {
+			return pm.autoAssignPort(serviceName)
+		}
+
+		if pm.isServicePortAvailable(serviceName, preferredPort) {
 			slog.Debug("preferred port is available", "service", serviceName, "port", preferredPort)
 			return pm.saveAssignment(serviceName, preferredPort, false)
 		}
@@ -353,7 +585,8 @@ func (pm *PortManager) handleConflictAndAssign(serviceName string, port int, pro
 		return pm.killAndAssign(serviceName, port)
 
 	case ActionReassign:
-		return pm.reassignPort(serviceName, port, isExplicit)
+		// Reassignments driven by team policy are non-interactive, so skip the azure.yaml update prompt
+		return pm.reassignPort(serviceName, port, isExplicit && pm.conflictPolicy != teamconfig.ConflictReassign)
 
 	case ActionAlwaysKill:
 		// Set session-level flag immediately so subsequent conflicts in this run are auto-killed
@@ -373,7 +606,7 @@ func (pm *PortManager) handleConflictAndAssign(serviceName string, port int, pro
 // Must be called with pm.mu held.
 func (pm *PortManager) killAndAssign(serviceName string, port int) (int, bool, error) {
 	// Re-validate port state after re-acquiring lock (state may have changed during user input)
-	if pm.isPortAvailable(port) {
+	if pm.isServicePortAvailable(serviceName, port) {
 		// Port became available while waiting for user input - use it directly
 		result, _, err := pm.saveAssignment(serviceName, port, false)
 		if err != nil {
@@ -386,12 +619,17 @@ func (pm *PortManager) killAndAssign(serviceName string, port int) (int, bool, e
 	// Kill process
 	if err := pm.killProcessOnPort(port); err != nil {
 		fmt.Fprintf(os.Stderr, "\n⚠️  %v\n", err)
-		printKillFailedTip()
+		var refused *KillRefusedError
+		if errors.As(err, &refused) {
+			printKillRefusedTip(refused.CommandLine)
+		} else {
+			printKillFailedTip()
+		}
 		return 0, false, fmt.Errorf("failed to free port %d: %w", port, err)
 	}
 
 	// Verify port is now available with retries
-	if !pm.verifyPortCleanup(port) {
+	if !pm.verifyPortCleanup(serviceName, port) {
 		printPortStillInUseMessage(port)
 		printKillFailedTip()
 		return 0, false, fmt.Errorf("port %d is still in use after cleanup attempt", port)
@@ -410,7 +648,7 @@ func (pm *PortManager) killAndAssign(serviceName string, port int) (int, bool, e
 func (pm *PortManager) reassignPort(serviceName string, _ int, isExplicit bool) (int, bool, error) {
 	printFindingPortMessage(serviceName)
 
-	port, err := pm.findAvailablePort()
+	port, err := pm.findAvailablePort(serviceName, pm.rangeFor(serviceName))
 	if err != nil {
 		return 0, false, err
 	}
@@ -440,7 +678,7 @@ func (pm *PortManager) reassignPort(serviceName string, _ int, isExplicit bool)
 // autoAssignPort finds and assigns an available port automatically.
 // Must be called with pm.mu held.
 func (pm *PortManager) autoAssignPort(serviceName string) (int, bool, error) {
-	port, err := pm.findAvailablePort()
+	port, err := pm.findAvailablePort(serviceName, pm.rangeFor(serviceName))
 	if err != nil {
 		return 0, false, err
 	}
@@ -488,6 +726,21 @@ func (pm *PortManager) GetAssignment(serviceName string) (int, bool) {
 	return 0, false
 }
 
+// Assignments returns a copy of all port assignments, sorted by service name.
+func (pm *PortManager) Assignments() []PortAssignment {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	assignments := make([]PortAssignment, 0, len(pm.assignments))
+	for _, assignment := range pm.assignments {
+		assignments = append(assignments, *assignment)
+	}
+	sort.Slice(assignments, func(i, j int) bool {
+		return assignments[i].ServiceName < assignments[j].ServiceName
+	})
+	return assignments
+}
+
 // CleanStalePorts removes port assignments older than the stale threshold.
 // Assignments are considered stale if they haven't been used in 7 days.
 func (pm *PortManager) CleanStalePorts() error {
@@ -510,20 +763,20 @@ func (pm *PortManager) CleanStalePorts() error {
 	return nil
 }
 
-// getConfigClient returns the azdconfig client, creating it lazily if needed.
-// If no gRPC connection is available (e.g., during tests), falls back to a shared
-// in-memory storage that persists across port manager instances.
+// getConfigClient returns the azdconfig client for the selected state backend, creating it
+// lazily if needed. If the backend is unavailable (e.g., no gRPC connection during tests),
+// falls back to a shared in-memory storage that persists across port manager instances.
 func (pm *PortManager) getConfigClient() (azdconfig.ConfigClient, error) { //nolint:unparam // return value kept for future use/interface conformance
 	if pm.configClient != nil {
 		return pm.configClient, nil
 	}
 
-	client, err := azdconfig.NewClient(context.Background())
+	client, err := azdconfig.Open(context.Background())
 	if err != nil {
-		// Fall back to shared in-memory client when gRPC is not available.
+		// Fall back to shared in-memory client when the backend is not available.
 		// Using a shared client ensures port assignments are visible across
 		// all port manager instances within the same process (important for tests).
-		slog.Debug("gRPC connection not available, using shared in-memory port storage", "error", err)
+		slog.Debug("state backend not available, using shared in-memory port storage", "backend", azdconfig.Backend(), "error", err)
 		sharedInMemoryClientOnce.Do(func() {
 			sharedInMemoryClient = azdconfig.NewInMemoryClient()
 		})
@@ -606,7 +859,13 @@ func (pm *PortManager) load() error { //nolint:unparam // return value kept for
 		ports, _ = pm.configClient.GetAllServicePorts(pm.projectHash)
 	}
 
-	// Convert map[string]int to map[string]*PortAssignment
+	pm.setAssignments(ports)
+	slog.Debug("loaded port assignments from config", "count", len(pm.assignments))
+	return nil
+}
+
+// setAssignments adds the persisted ports, keyed by service name, to the assignments.
+func (pm *PortManager) setAssignments(ports map[string]int) {
 	for serviceName, port := range ports {
 		pm.assignments[serviceName] = &PortAssignment{
 			ServiceName: serviceName,
@@ -614,8 +873,38 @@ func (pm *PortManager) load() error { //nolint:unparam // return value kept for
 			LastUsed:    time.Now(), // We don't persist LastUsed anymore
 		}
 	}
+}
 
-	slog.Debug("loaded port assignments from config", "count", len(pm.assignments))
+// reload replaces the in-memory assignments with those currently persisted in config.
+// When the backend can't be read (e.g. the azd process that served it has exited), the
+// assignments loaded last are kept, so running services keep their ports.
+func (pm *PortManager) reload() error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	client, err := pm.getConfigClient()
+	if err != nil {
+		return err
+	}
+	ports, err := client.GetAllServicePorts(pm.projectHash)
+	if err != nil {
+		return fmt.Errorf("failed to read port assignments, keeping the current ones: %w", err)
+	}
+	pm.assignments = make(map[string]*PortAssignment, len(ports))
+	pm.setAssignments(ports)
+	return nil
+}
+
+// SaveTo writes the port assignments to client, e.g. to carry them over to another state backend.
+func (pm *PortManager) SaveTo(client azdconfig.ConfigClient) error {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	for serviceName, assignment := range pm.assignments {
+		if err := client.SetServicePort(pm.projectHash, serviceName, assignment.Port); err != nil {
+			return fmt.Errorf("failed to save port for service %s: %w", serviceName, err)
+		}
+	}
 	return nil
 }
 
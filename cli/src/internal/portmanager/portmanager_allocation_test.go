@@ -1,11 +1,13 @@
 package portmanager
 
 import (
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/azdconfig"
+	"github.com/jongio/azd-app/cli/src/internal/teamconfig"
 )
 
 // mockPortChecker returns a port checker that simulates port availability without network binding.
@@ -125,6 +127,34 @@ func TestAssignPort_Persistence(t *testing.T) {
 	}
 }
 
+func TestAssignments(t *testing.T) {
+	tempDir := t.TempDir()
+	pm := setupTestManager(tempDir, nil)
+
+	for _, svc := range []struct {
+		name string
+		port int
+	}{{"web", 9882}, {"api", 9881}} {
+		if _, _, err := pm.AssignPort(svc.name, svc.port, true); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	assignments := pm.Assignments()
+	if len(assignments) != 2 {
+		t.Fatalf("Expected 2 assignments, got %d", len(assignments))
+	}
+	if assignments[0].ServiceName != "api" || assignments[0].Port != 9881 || assignments[1].ServiceName != "web" {
+		t.Errorf("Expected assignments sorted by service name, got %+v", assignments)
+	}
+
+	// The returned slice is a copy
+	assignments[0].Port = 1
+	if port, _ := pm.GetAssignment("api"); port != 9881 {
+		t.Errorf("Expected assignment to be unchanged, got port %d", port)
+	}
+}
+
 func TestAssignPort_SameServiceTwice(t *testing.T) {
 	tempDir := t.TempDir()
 	pm := setupTestManager(tempDir, nil)
@@ -207,11 +237,36 @@ func TestIsPortAvailable(t *testing.T) {
 	}
 }
 
+func TestWaitForPortRelease(t *testing.T) {
+	var mu sync.Mutex
+	inUse := true
+	pm := setupTestManager(t.TempDir(), nil)
+	pm.portChecker = func(int) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return !inUse
+	}
+
+	if pm.WaitForPortRelease(8080, 150*time.Millisecond) {
+		t.Error("Expected timeout while port is still in use")
+	}
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		mu.Lock()
+		inUse = false
+		mu.Unlock()
+	}()
+	if !pm.WaitForPortRelease(8080, 2*time.Second) {
+		t.Error("Expected port to be released")
+	}
+}
+
 func TestFindAvailablePort(t *testing.T) {
 	tempDir := t.TempDir()
 	pm := setupTestManager(tempDir, nil)
 
-	port, err := pm.findAvailablePort()
+	port, err := pm.findAvailablePort("", pm.portRange)
 	if err != nil {
 		t.Fatalf("Expected to find available port, got error: %v", err)
 	}
@@ -357,6 +412,152 @@ func TestAssignPort_PreferredPortOutOfRange(t *testing.T) {
 	}
 }
 
+func TestAssignPort_ServicePortRange(t *testing.T) {
+	tempDir := t.TempDir()
+	pm := setupTestManager(tempDir, map[int]bool{4001: true})
+
+	if err := pm.SetServicePortRange("api", 4000, 4003); err != nil {
+		t.Fatalf("SetServicePortRange() error = %v", err)
+	}
+
+	// A preferred port outside the range isn't used
+	port, _, err := pm.AssignPort("api", 3000, false)
+	if err != nil {
+		t.Fatalf("AssignPort() error = %v", err)
+	}
+	if port < 4000 || port > 4003 || port == 4001 {
+		t.Errorf("AssignPort() = %d, want an available port in 4000-4003", port)
+	}
+
+	// Other services still use the global range
+	if port, _, err := pm.AssignPort("web", 3000, false); err != nil || port != 3000 {
+		t.Errorf("AssignPort(web) = %d, %v, want 3000", port, err)
+	}
+
+	// Explicit ports aren't restricted
+	if port, _, err := pm.AssignPort("api", 8080, true); err != nil || port != 8080 {
+		t.Errorf("AssignPort(api, explicit) = %d, %v, want 8080", port, err)
+	}
+}
+
+func TestAssignPort_ServicePortRangeReplacesAssignment(t *testing.T) {
+	tempDir := t.TempDir()
+	pm := setupTestManager(tempDir, nil)
+
+	if port, _, err := pm.AssignPort("api", 3000, false); err != nil || port != 3000 {
+		t.Fatalf("AssignPort() = %d, %v, want 3000", port, err)
+	}
+
+	if err := pm.SetServicePortRange("api", 4000, 4099); err != nil {
+		t.Fatalf("SetServicePortRange() error = %v", err)
+	}
+	port, _, err := pm.AssignPort("api", 3000, false)
+	if err != nil {
+		t.Fatalf("AssignPort() error = %v", err)
+	}
+	if port < 4000 || port > 4099 {
+		t.Errorf("AssignPort() = %d, want the assignment moved into 4000-4099", port)
+	}
+
+	// Removing the range keeps the assignment
+	if err := pm.SetServicePortRange("api", 0, 0); err != nil {
+		t.Fatalf("SetServicePortRange(0, 0) error = %v", err)
+	}
+	if again, _, err := pm.AssignPort("api", 3000, false); err != nil || again != port {
+		t.Errorf("AssignPort() = %d, %v, want %d", again, err, port)
+	}
+}
+
+func TestAssignPort_ServicePortRangeExhausted(t *testing.T) {
+	tempDir := t.TempDir()
+	pm := setupTestManager(tempDir, map[int]bool{4000: true, 4001: true})
+
+	if err := pm.SetServicePortRange("api", 4000, 4001); err != nil {
+		t.Fatalf("SetServicePortRange() error = %v", err)
+	}
+	if port, _, err := pm.AssignPort("api", 0, false); err == nil {
+		t.Errorf("AssignPort() = %d, want an error when every port in the range is in use", port)
+	}
+}
+
+func TestSetServicePortRange_Invalid(t *testing.T) {
+	tempDir := t.TempDir()
+	pm := setupTestManager(tempDir, nil)
+
+	for _, r := range [][2]int{{0, 100}, {4100, 4000}, {60000, 70000}} {
+		if err := pm.SetServicePortRange("api", r[0], r[1]); err == nil {
+			t.Errorf("SetServicePortRange(%d, %d) should fail", r[0], r[1])
+		}
+	}
+}
+
+func TestAssignPort_StableStrategy(t *testing.T) {
+	// Teammates clone the project to different locations
+	alice := filepath.Join(t.TempDir(), "alice", "shop")
+	bob := filepath.Join(t.TempDir(), "bob", "shop")
+
+	assign := func(projectDir, serviceName string) int {
+		t.Helper()
+		pm := setupTestManager(projectDir, nil)
+		pm.strategy = teamconfig.StrategyStable
+		port, _, err := pm.AssignPort(serviceName, 0, false)
+		if err != nil {
+			t.Fatalf("AssignPort(%s) error = %v", serviceName, err)
+		}
+		return port
+	}
+
+	api := assign(alice, "api")
+	if got := assign(bob, "api"); got != api {
+		t.Errorf("stable strategy assigned %d and %d to the same service on different machines", api, got)
+	}
+	if web := assign(alice, "web"); web == api {
+		t.Errorf("stable strategy assigned %d to both api and web", web)
+	}
+}
+
+func TestFindAvailablePort_StableStrategySkipsUsedPorts(t *testing.T) {
+	window := portWindow{start: 4000, end: 4099}
+	stable := window.start + stablePortOffset("shop", "api", 100)
+
+	pm := setupTestManager(t.TempDir(), map[int]bool{stable: true})
+	pm.strategy = teamconfig.StrategyStable
+	pm.projectDir = "shop"
+
+	port, err := pm.findAvailablePort("api", window)
+	if err != nil {
+		t.Fatalf("findAvailablePort() error = %v", err)
+	}
+	if want := window.start + (stable-window.start+1)%100; port != want {
+		t.Errorf("findAvailablePort() = %d, want the next port after %d (%d)", port, stable, want)
+	}
+}
+
+func TestAssignPortWithProtocol(t *testing.T) {
+	tempDir := t.TempDir()
+	pm := setupTestManager(tempDir, nil)
+
+	port, _, err := pm.AssignPortWithProtocol("quic", 4433, false, ProtocolUDP)
+	if err != nil || port != 4433 {
+		t.Fatalf("AssignPortWithProtocol(udp) = %d, %v, want 4433", port, err)
+	}
+	if pm.protocols["quic"] != ProtocolUDP {
+		t.Errorf("protocol of quic = %q, want %q", pm.protocols["quic"], ProtocolUDP)
+	}
+
+	// Assigning with TCP again forgets the protocol
+	if _, _, err := pm.AssignPort("quic", 4433, false); err != nil {
+		t.Fatalf("AssignPort() error = %v", err)
+	}
+	if _, ok := pm.protocols["quic"]; ok {
+		t.Error("AssignPort() should check the service's port with tcp")
+	}
+
+	if _, _, err := pm.AssignPortWithProtocol("api", 8080, false, "sctp"); err == nil {
+		t.Error("AssignPortWithProtocol() should reject unsupported protocols")
+	}
+}
+
 func TestIsPortAvailableEdgeCases(t *testing.T) {
 	tempDir := t.TempDir()
 	unavailable := map[int]bool{8000: true, 0: true}
@@ -1,10 +1,14 @@
 package portmanager
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/azdconfig"
+	"github.com/jongio/azd-app/cli/src/internal/teamconfig"
 )
 
 func TestPortManagerCaching(t *testing.T) {
@@ -76,7 +80,7 @@ func TestFindAvailablePort_Exhaustion(t *testing.T) {
 	tempDir := t.TempDir()
 	pm := setupTestManager(tempDir, nil)
 
-	port, err := pm.findAvailablePort()
+	port, err := pm.findAvailablePort("", pm.portRange)
 	if err != nil {
 		t.Fatalf("Expected to find available port, got: %v", err)
 	}
@@ -457,3 +461,249 @@ func TestManagerCacheEviction(t *testing.T) {
 		t.Error("Expected newest entry to be in cache")
 	}
 }
+
+func TestInvalidateCache(t *testing.T) {
+	tempDir := t.TempDir()
+
+	pm1 := setupTestManager(tempDir, nil)
+	InvalidateCache(tempDir)
+	pm2 := setupTestManager(tempDir, nil)
+
+	if pm1 == pm2 {
+		t.Error("Expected a new manager instance after InvalidateCache")
+	}
+
+	// Invalidating an unknown project is a no-op
+	InvalidateCache(t.TempDir())
+}
+
+func TestGetPortManager_ReloadsWhenAzureDirChanges(t *testing.T) {
+	tempDir := t.TempDir()
+
+	pm := setupTestManager(tempDir, nil)
+	client := pm.configClient
+
+	// Another process records an assignment; the cached manager does not see it yet
+	if err := client.SetServicePort(pm.projectHash, "api", 4123); err != nil {
+		t.Fatalf("SetServicePort() failed: %v", err)
+	}
+	if _, exists := GetPortManager(tempDir).GetAssignment("api"); exists {
+		t.Fatal("Expected assignment to stay cached while .azure is unchanged")
+	}
+
+	// Creating .azure changes the project state stamp and triggers a reload
+	if err := os.Mkdir(filepath.Join(tempDir, ".azure"), 0750); err != nil {
+		t.Fatalf("failed to create .azure: %v", err)
+	}
+	reloaded := GetPortManager(tempDir)
+	if reloaded != pm {
+		t.Error("Expected the cached manager to be refreshed in place")
+	}
+	if port, exists := reloaded.GetAssignment("api"); !exists || port != 4123 {
+		t.Errorf("GetAssignment(api) = %d, %v; want 4123, true", port, exists)
+	}
+}
+
+func TestGetPortManager_IgnoresOtherWritesToAzureDir(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tempDir, ".azure"), 0750); err != nil {
+		t.Fatalf("failed to create .azure: %v", err)
+	}
+
+	pm := setupTestManager(tempDir, nil)
+	if err := pm.configClient.SetServicePort(pm.projectHash, "api", 4123); err != nil {
+		t.Fatalf("SetServicePort() failed: %v", err)
+	}
+
+	// Session and log files written to .azure don't touch the port state
+	future := time.Now().Add(time.Hour)
+	if err := os.WriteFile(filepath.Join(tempDir, ".azure", "session.json"), []byte("{}"), 0600); err != nil {
+		t.Fatalf("failed to write session.json: %v", err)
+	}
+	if err := os.Chtimes(filepath.Join(tempDir, ".azure"), future, future); err != nil {
+		t.Fatalf("failed to touch .azure: %v", err)
+	}
+	if _, exists := GetPortManager(tempDir).GetAssignment("api"); exists {
+		t.Error("Expected no reload for writes to .azure that aren't port state")
+	}
+
+	// Deleting .azure is a reload
+	if err := os.RemoveAll(filepath.Join(tempDir, ".azure")); err != nil {
+		t.Fatalf("failed to remove .azure: %v", err)
+	}
+	if port, exists := GetPortManager(tempDir).GetAssignment("api"); !exists || port != 4123 {
+		t.Errorf("GetAssignment(api) = %d, %v; want 4123, true after .azure was deleted", port, exists)
+	}
+}
+
+func TestGetPortManager_ReloadsWhenStateFileChanges(t *testing.T) {
+	tempDir := t.TempDir()
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	t.Setenv(azdconfig.EnvStateBackend, azdconfig.BackendFile)
+	t.Setenv(azdconfig.EnvStatePath, stateFile)
+
+	pm := GetPortManager(tempDir)
+	if _, exists := pm.GetAssignment("api"); exists {
+		t.Fatal("Expected no assignment in a new state file")
+	}
+
+	// Another process records an assignment in the state file
+	other, err := azdconfig.NewFileClient(stateFile)
+	if err != nil {
+		t.Fatalf("NewFileClient() failed: %v", err)
+	}
+	if err := other.SetServicePort(pm.projectHash, "api", 4124); err != nil {
+		t.Fatalf("SetServicePort() failed: %v", err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(stateFile, future, future); err != nil {
+		t.Fatalf("failed to touch state file: %v", err)
+	}
+
+	if port, exists := GetPortManager(tempDir).GetAssignment("api"); !exists || port != 4124 {
+		t.Errorf("GetAssignment(api) = %d, %v; want 4124, true", port, exists)
+	}
+}
+
+func TestGetPortManager_PerEntryLocking(t *testing.T) {
+	busyDir := t.TempDir()
+	_ = setupTestManager(busyDir, nil)
+
+	managerCacheMu.RLock()
+	busy := managerCache[normalizeProjectDir(busyDir)]
+	managerCacheMu.RUnlock()
+
+	// Hold the busy project's lock, as a slow load or refresh would
+	busy.mu.Lock()
+	defer busy.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		_ = GetPortManager(t.TempDir())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetPortManager for an unrelated project blocked on another project's lock")
+	}
+}
+
+func TestApplyTeamDefaults(t *testing.T) {
+	team := &teamconfig.Defaults{
+		Ports: teamconfig.PortDefaults{RangeStart: 4000, RangeEnd: 4999, ConflictPolicy: "Reassign", Strategy: "stable"},
+	}
+
+	pm := &PortManager{}
+	pm.portRange.start, pm.portRange.end = PortRangeStart, PortRangeEnd
+	pm.applyTeamDefaults(team)
+
+	if pm.portRange.start != 4000 || pm.portRange.end != 4999 {
+		t.Errorf("port range = %d-%d, want 4000-4999", pm.portRange.start, pm.portRange.end)
+	}
+	if pm.conflictPolicy != teamconfig.ConflictReassign {
+		t.Errorf("conflictPolicy = %q, want %q", pm.conflictPolicy, teamconfig.ConflictReassign)
+	}
+	if pm.strategy != teamconfig.StrategyStable {
+		t.Errorf("strategy = %q, want %q", pm.strategy, teamconfig.StrategyStable)
+	}
+
+	// Environment variables take precedence over team defaults
+	t.Setenv(envPortRangeStart, "5000")
+	t.Setenv(envPortStrategy, "Random")
+	pm = &PortManager{}
+	pm.portRange.start = getPortRangeStart()
+	pm.strategy = getPortStrategy()
+	pm.applyTeamDefaults(team)
+	if pm.portRange.start != 5000 {
+		t.Errorf("port range start = %d, want env value 5000", pm.portRange.start)
+	}
+	if pm.strategy != teamconfig.StrategyRandom {
+		t.Errorf("strategy = %q, want env value %q", pm.strategy, teamconfig.StrategyRandom)
+	}
+}
+
+func TestHandlePortConflict_TeamPolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		want    PortConflictAction
+		wantErr bool
+	}{
+		{teamconfig.ConflictReassign, ActionReassign, false},
+		{teamconfig.ConflictFail, ActionCancel, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			pm := setupTestManager(t.TempDir(), nil)
+			pm.conflictPolicy = tt.policy
+
+			got, err := handlePortConflict(pm, 3000, "api", "", false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handlePortConflict() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("handlePortConflict() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortManager_ProjectDirWithSpacesAndNonASCII(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), "my project", "prøjekt ü")
+	if err := os.MkdirAll(projectDir, 0750); err != nil {
+		t.Fatalf("failed to create project dir: %v", err)
+	}
+
+	pm := setupTestManager(projectDir, nil)
+	if other := GetPortManager(projectDir + string(filepath.Separator)); other != pm {
+		t.Error("Expected same port manager instance for equivalent paths")
+	}
+
+	if _, _, err := pm.AssignPort("wéb", 9910, false); err != nil {
+		t.Fatalf("failed to assign port: %v", err)
+	}
+	if port, exists := pm.GetAssignment("wéb"); !exists || port != 9910 {
+		t.Errorf("Expected port 9910, got %d (exists: %v)", port, exists)
+	}
+}
+
+// unreachableClient fails to read port assignments, like azd's gRPC server after azd exited.
+type unreachableClient struct {
+	*azdconfig.InMemoryClient
+}
+
+func (unreachableClient) GetAllServicePorts(string) (map[string]int, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestReload_KeepsAssignmentsWhenBackendFails(t *testing.T) {
+	pm := setupTestManager(t.TempDir(), nil)
+	if _, _, err := pm.AssignPort("api", 4125, true); err != nil {
+		t.Fatalf("AssignPort() failed: %v", err)
+	}
+
+	pm.SetConfigClient(unreachableClient{azdconfig.NewInMemoryClient()})
+	if err := pm.reload(); err == nil {
+		t.Error("reload() should report the unreachable backend")
+	}
+	if port, exists := pm.GetAssignment("api"); !exists || port != 4125 {
+		t.Errorf("GetAssignment(api) = %d, %v; want 4125, true kept after a failed reload", port, exists)
+	}
+}
+
+func TestSaveTo(t *testing.T) {
+	pm := setupTestManager(t.TempDir(), nil)
+	if _, _, err := pm.AssignPort("api", 4126, true); err != nil {
+		t.Fatalf("AssignPort() failed: %v", err)
+	}
+
+	client := azdconfig.NewInMemoryClient()
+	if err := pm.SaveTo(client); err != nil {
+		t.Fatalf("SaveTo() failed: %v", err)
+	}
+	if port, _ := client.GetServicePort(pm.projectHash, "api"); port != 4126 {
+		t.Errorf("saved port = %d, want 4126", port)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+
+	"github.com/jongio/azd-app/cli/src/internal/teamconfig"
 )
 
 // PortConflictAction represents the user's chosen action for handling a port conflict.
@@ -47,6 +49,17 @@ func handlePortConflict(pm *PortManager, port int, serviceName string, processIn
 		return ActionKill, nil
 	}
 
+	// Apply the team conflict policy, if any. The user's always-kill preference wins; team
+	// defaults can't make a port conflict kill without asking (see teamconfig.ConflictPolicy).
+	switch pm.conflictPolicy {
+	case teamconfig.ConflictReassign:
+		slog.Info("reassigning port due to team conflict policy", "port", port, "service", serviceName)
+		printTeamPolicyMessage(serviceName, port, processInfo, "assigning a different port")
+		return ActionReassign, nil
+	case teamconfig.ConflictFail:
+		return ActionCancel, fmt.Errorf("port %d for service '%s' is already in use%s (team conflict policy: fail)", port, serviceName, processInfo)
+	}
+
 	// Print the conflict message
 	printConflictMessage(serviceName, port, processInfo, isExplicit)
 
@@ -88,6 +101,11 @@ func printAutoKillMessage(serviceName string, port int, processInfo string, isEx
 	}
 }
 
+// printTeamPolicyMessage prints the message shown when a team conflict policy resolves a conflict.
+func printTeamPolicyMessage(serviceName string, port int, processInfo string, action string) {
+	fmt.Fprintf(os.Stderr, "\n⚠️  Service '%s' port %d is in use%s - %s (team conflict policy)\n", serviceName, port, processInfo, action)
+}
+
 // printConflictMessage prints the initial conflict message before showing options.
 func printConflictMessage(serviceName string, port int, processInfo string, isExplicit bool) {
 	if isExplicit {
@@ -131,6 +149,22 @@ func getProcessInfoString(pm *PortManager, port int) string {
 	return fmt.Sprintf(" by PID %d", info.PID)
 }
 
+// printKillingProcessMessage shows the full command line of a process before it is killed.
+func printKillingProcessMessage(port int, pid int, processName string, commandLine string, reason string) {
+	if commandLine == "" {
+		commandLine = processName
+	}
+	fmt.Fprintf(os.Stderr, "Killing PID %d on port %d (%s): %s\n", pid, port, reason, commandLine)
+}
+
+// printKillRefusedTip explains how to proceed when killing a process was refused.
+func printKillRefusedTip(commandLine string) {
+	if commandLine != "" {
+		fmt.Fprintf(os.Stderr, "   Command: %s\n", commandLine)
+	}
+	fmt.Fprintf(os.Stderr, "\nTip: Stop the process yourself, choose a different port, or rerun with --force-kill\n\n")
+}
+
 // printPortFreedMessage prints a message when a port is successfully freed.
 func printPortFreedMessage(serviceName string, port int) {
 	fmt.Fprintf(os.Stderr, "✓ Port %d freed and ready for service '%s'\n\n", port, serviceName)
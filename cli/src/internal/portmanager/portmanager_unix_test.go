@@ -3,7 +3,9 @@
 package portmanager
 
 import (
+	"net"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -38,14 +40,14 @@ func TestUnixKillProcessOnPort_MacOSCompatibility(t *testing.T) {
 	// Get the PID - should be our process
 	pid, err := pm.getProcessOnPort(port)
 	if err != nil {
-		// In CI environments, lsof may not be able to detect processes properly
+		// In CI environments, the socket owner may not be visible to the test process
 		t.Skipf("Skipping test - process detection not available in this environment: %v", err)
 	}
 
 	expectedPID := os.Getpid()
 	if pid != expectedPID {
 		t.Logf("Warning: PID mismatch. Expected %d (our process), got %d", expectedPID, pid)
-		// This is acceptable in containers or when lsof returns parent process
+		// This is acceptable in containers where the listener is owned by a parent process
 	}
 
 	t.Logf("Process listening on port %d with PID %d", port, pid)
@@ -101,7 +103,7 @@ func TestUnixGetProcessOnPort_NoBSDExtensions(t *testing.T) {
 	// Get process info
 	pid, err := pm.getProcessOnPort(port)
 	if err != nil {
-		// In CI environments, lsof may not be able to detect processes properly
+		// In CI environments, the socket owner may not be visible to the test process
 		t.Skipf("Skipping test - process detection not available in this environment: %v", err)
 	}
 
@@ -184,7 +186,7 @@ func TestMacOSPortConflictResolution(t *testing.T) {
 	// 2. Get process info (this should work on macOS)
 	info, err := pm.getProcessInfoOnPort(port)
 	if err != nil {
-		// In CI environments, lsof may not be able to detect processes properly
+		// In CI environments, the socket owner may not be visible to the test process
 		t.Skipf("Skipping test - process detection not available in this environment: %v", err)
 	}
 
@@ -202,3 +204,60 @@ func TestMacOSPortConflictResolution(t *testing.T) {
 		t.Logf("Port %d still showing as in use after closing listener", port)
 	}
 }
+
+func TestIsPortAvailableOn_UDP(t *testing.T) {
+	pm := setupTestManager(t.TempDir(), nil)
+	pm.portChecker = nil
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to bind a udp port: %v", err)
+	}
+	defer conn.Close()
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+
+	if pm.IsPortAvailableOn(port, ProtocolUDP) {
+		t.Errorf("IsPortAvailableOn(%d, udp) = true while a udp socket is bound to it", port)
+	}
+	_ = conn.Close()
+	if !pm.IsPortAvailableOn(port, ProtocolUDP) {
+		t.Errorf("IsPortAvailableOn(%d, udp) = false after the udp socket was closed", port)
+	}
+}
+
+func TestIsSocketAvailable(t *testing.T) {
+	// Unix socket paths are limited to about 100 bytes, which t.TempDir() can exceed on macOS
+	dir, err := os.MkdirTemp("", "sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "api.sock")
+
+	if !IsSocketAvailable(path) {
+		t.Error("IsSocketAvailable() = false for a path that doesn't exist")
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen on %s: %v", path, err)
+	}
+	if IsSocketAvailable(path) {
+		t.Error("IsSocketAvailable() = true while a service listens on the socket")
+	}
+
+	// A socket file left behind by a process that exited is stale
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = listener.Close()
+	if !IsSocketAvailable(path) {
+		t.Error("IsSocketAvailable() = false for a stale socket file")
+	}
+
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if IsSocketAvailable(file) {
+		t.Error("IsSocketAvailable() = true for a regular file")
+	}
+}
@@ -2,6 +2,7 @@ package portmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os/exec"
@@ -9,84 +10,80 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/netinfo"
 )
 
-// commandTimeout is the maximum time to wait for process detection commands.
-// In containerized environments (e.g., Codespaces), lsof/ss can be slow.
+// commandTimeout is the maximum time to wait for process inspection commands.
+// In containerized environments (e.g., Codespaces), ps can be slow.
 const commandTimeout = 5 * time.Second
 
 // osWindows is the GOOS value for Windows.
 const osWindows = "windows"
 
-// buildGetProcessOnPortCommand returns the command and args to find a process listening on a port.
-// On Unix/Linux, uses lsof -t -i:port which is reliable in Codespaces/containers.
-func buildGetProcessOnPortCommand(port int) (cmd string, args []string) {
+// buildGetProcessNameCommand returns the command and args to get a process name by PID.
+// On Windows the CSV output of tasklist is parsed by parseTasklistName.
+func buildGetProcessNameCommand(pid int) (cmd string, args []string) {
 	if runtime.GOOS == osWindows {
-		psScript := fmt.Sprintf(`
-			$connections = netstat -ano | Select-String ":%d " | Select-String "LISTENING"
-			foreach ($line in $connections) {
-				$parts = $line -split '\s+' | Where-Object { $_ }
-				$procId = $parts[-1]
-				if ($procId -match '^\d+$') {
-					Write-Output $procId
-					exit 0
-				}
-			}
-		`, port)
-		return "powershell", []string{"-Command", psScript}
+		return "tasklist", []string{"/FI", fmt.Sprintf("PID eq %d", pid), "/FO", "CSV", "/NH"}
 	}
-	// Unix: Use lsof -t -i:port which is proven to work reliably in Codespaces
-	// The -t flag outputs only PIDs (terse mode)
-	script := fmt.Sprintf(`lsof -t -i:%d 2>/dev/null | head -n 1`, port)
-	return "sh", []string{"-c", script}
+	return "ps", []string{"-p", strconv.Itoa(pid), "-o", "comm="}
 }
 
-// buildGetProcessNameCommand returns the command and args to get a process name by PID.
-func buildGetProcessNameCommand(pid int) (cmd string, args []string) {
+// buildKillProcessCommand returns the command and args to kill a process and its children by PID.
+// On Windows, taskkill /T terminates the whole process tree.
+// On Unix, children (found with pgrep -P, see findChildPIDs) are passed to kill -9 before the parent.
+func buildKillProcessCommand(pid int, children []int) (cmd string, args []string) {
 	if runtime.GOOS == osWindows {
-		psScript := fmt.Sprintf(`
-			$proc = Get-Process -Id %d -ErrorAction SilentlyContinue
-			if ($proc) {
-				Write-Output $proc.ProcessName
-			}
-		`, pid)
-		return "powershell", []string{"-Command", psScript}
+		return "taskkill", []string{"/F", "/T", "/PID", strconv.Itoa(pid)}
 	}
-	return "sh", []string{"-c", fmt.Sprintf("ps -p %d -o comm=", pid)}
+	args = []string{"-9"}
+	for _, child := range children {
+		args = append(args, strconv.Itoa(child))
+	}
+	return "kill", append(args, strconv.Itoa(pid))
 }
 
-// buildKillProcessCommand returns the command and args to kill a process and its children by PID.
-// On Windows, uses Get-CimInstance Win32_Process to find child processes by ParentProcessId.
-// On Unix, uses kill -9 directly which is reliable in Codespaces/containers.
-// In both cases, children are killed first (recursively), then the parent.
-func buildKillProcessCommand(pid int) (cmd string, args []string) {
+// parseTasklistName returns the process name (without .exe) from `tasklist /FO CSV /NH` output.
+// tasklist prints an informational message instead of CSV when no process matches.
+func parseTasklistName(output string) string {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(output), "\n", 2)[0])
+	if !strings.HasPrefix(line, `"`) {
+		return ""
+	}
+	name := strings.Trim(strings.SplitN(line, ",", 2)[0], `"`)
+	if strings.HasSuffix(strings.ToLower(name), ".exe") {
+		name = name[:len(name)-len(".exe")]
+	}
+	return name
+}
+
+// findChildPIDs returns the direct children of pid using pgrep -P.
+// Returns nil on Windows (taskkill /T handles the tree) or when pgrep is unavailable.
+func findChildPIDs(ctx context.Context, pid int) []int {
 	if runtime.GOOS == osWindows {
-		// PowerShell script that recursively kills child processes first, then the parent.
-		// Uses Get-CimInstance Win32_Process to find children by ParentProcessId.
-		psScript := fmt.Sprintf(`
-			function Kill-ProcessTree {
-				param([int]$ParentId)
-				$children = Get-CimInstance Win32_Process -Filter "ParentProcessId = $ParentId" -ErrorAction SilentlyContinue
-				foreach ($child in $children) {
-					Kill-ProcessTree -ParentId $child.ProcessId
-				}
-				Stop-Process -Id $ParentId -Force -ErrorAction SilentlyContinue
-			}
-			Kill-ProcessTree -ParentId %d
-		`, pid)
-		return "powershell", []string{"-Command", psScript}
+		return nil
+	}
+	// #nosec G204 -- fixed binary, pid is a validated int
+	cmd := exec.CommandContext(ctx, "pgrep", "-P", strconv.Itoa(pid))
+	cmd.Stdin = nil
+	output, err := cmd.Output()
+	if err != nil {
+		// pgrep exits 1 when there are no children
+		return nil
 	}
-	// Unix: Use kill -9 directly - proven to work reliably in Codespaces
-	// First try to kill children, then force kill the parent
-	script := fmt.Sprintf(`
-# Kill child processes first (if pgrep available)
-for child in $(pgrep -P %d 2>/dev/null); do
-    kill -9 "$child" 2>/dev/null
-done
-# Force kill the main process
-kill -9 %d 2>/dev/null || true
-`, pid, pid)
-	return "sh", []string{"-c", script}
+	var children []int
+	for _, field := range strings.Fields(string(output)) {
+		if child, err := strconv.Atoi(field); err == nil {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// ProcessOnPort returns the PID and name of the process listening on the specified port.
+func (pm *PortManager) ProcessOnPort(port int) (*ProcessInfo, error) {
+	return pm.getProcessInfoOnPort(port)
 }
 
 // getProcessInfoOnPort retrieves the PID and name of the process listening on the specified port.
@@ -101,43 +98,26 @@ func (pm *PortManager) getProcessInfoOnPort(port int) (*ProcessInfo, error) {
 }
 
 // getProcessOnPort retrieves the PID of the process listening on the specified port.
+// The operating system's socket table is read directly (see netinfo), so this works
+// without lsof or netstat and doesn't need a timeout.
 func (pm *PortManager) getProcessOnPort(port int) (int, error) {
-	if port <= 0 || port > 65535 {
-		return 0, fmt.Errorf("invalid port number: %d (must be 1-65535)", port)
-	}
-
-	cmd, args := buildGetProcessOnPortCommand(port)
-
-	// Execute command with timeout to prevent hangs in containerized environments
-	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
-	defer cancel()
-
-	// #nosec G204 -- cmd is either "powershell" or "sh" (hard-coded), port is validated int
-	execCmd := exec.CommandContext(ctx, cmd, args...)
-	// Don't inherit stdin - prevents blocking in non-interactive environments
-	execCmd.Stdin = nil
-	output, err := execCmd.Output()
+	pid, err := netinfo.ListenerPID(port)
 	if err != nil {
-		// Check if it was a timeout
-		if ctx.Err() == context.DeadlineExceeded {
-			return 0, fmt.Errorf("timed out getting process on port %d (this can happen in Codespaces)", port)
+		if errors.Is(err, netinfo.ErrNoListener) {
+			return 0, fmt.Errorf("no process found on port %d", port)
 		}
 		return 0, fmt.Errorf("failed to get process on port %d: %w", port, err)
 	}
-
-	pidStr := strings.TrimSpace(string(output))
-	if pidStr == "" {
-		return 0, fmt.Errorf("no process found on port %d", port)
-	}
-
-	pid, err := strconv.Atoi(pidStr)
-	if err != nil {
-		return 0, fmt.Errorf("invalid PID '%s': %w", pidStr, err)
-	}
-
 	return pid, nil
 }
 
+// hasListener reports whether a socket is listening on the port, including sockets of
+// processes that can't be inspected, e.g. because they belong to another user.
+func (pm *PortManager) hasListener(port int) bool {
+	_, err := pm.getProcessOnPort(port)
+	return err == nil || errors.Is(err, netinfo.ErrOwnerUnknown)
+}
+
 // getProcessName retrieves the process name for a given PID.
 func (pm *PortManager) getProcessName(pid int) (string, error) {
 	cmd, args := buildGetProcessNameCommand(pid)
@@ -146,7 +126,7 @@ func (pm *PortManager) getProcessName(pid int) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
 	defer cancel()
 
-	// #nosec G204 -- cmd is either "powershell" or "sh" (hard-coded), pid is validated int
+	// #nosec G204 -- cmd is a hard-coded binary, pid is validated int
 	execCmd := exec.CommandContext(ctx, cmd, args...)
 	// Don't inherit stdin - prevents blocking in non-interactive environments
 	execCmd.Stdin = nil
@@ -159,6 +139,9 @@ func (pm *PortManager) getProcessName(pid int) (string, error) {
 	}
 
 	name := strings.TrimSpace(string(output))
+	if runtime.GOOS == osWindows {
+		name = parseTasklistName(name)
+	}
 	if name == "" {
 		return "", fmt.Errorf("no process name found for PID %d", pid)
 	}
@@ -166,10 +149,32 @@ func (pm *PortManager) getProcessName(pid int) (string, error) {
 	return name, nil
 }
 
-// KillProcessOnPort kills any process listening on the specified port.
+// KillProcessOnPort kills the process listening on the specified port.
+// Only processes started by azd-app or matching the dev server allowlist are killed unless
+// force-kill is enabled (see SetForceKill); others are refused with a *KillRefusedError.
+// Every decision is written to the kill audit log in <project>/.azure/logs.
 // Returns nil if no process was using the port or if the process was successfully killed.
-// Returns an error only if the process could not be terminated (e.g., protected system process).
+// Returns an error if the kill was refused or the process could not be terminated
+// (e.g., protected system process).
 func (pm *PortManager) KillProcessOnPort(port int) error {
+	pm.mu.RLock()
+	owned := make(map[int]string, len(pm.ownedPIDs))
+	for pid, serviceName := range pm.ownedPIDs {
+		owned[pid] = serviceName
+	}
+	pm.mu.RUnlock()
+
+	return pm.killProcess(port, owned)
+}
+
+// killProcessOnPort is KillProcessOnPort for callers that already hold pm.mu.
+func (pm *PortManager) killProcessOnPort(port int) error {
+	return pm.killProcess(port, pm.ownedPIDs)
+}
+
+// killProcess kills the process on a port after checking it is safe to do so.
+// owned maps PIDs started by azd-app to their service names.
+func (pm *PortManager) killProcess(port int, owned map[int]string) error {
 	// Get the PID first so we can provide feedback
 	pid, err := pm.getProcessOnPort(port)
 	if err != nil {
@@ -178,16 +183,22 @@ func (pm *PortManager) KillProcessOnPort(port int) error {
 		return nil
 	}
 
-	// Get process name for diagnostics
+	// Get process name and command line for the safety check, display and audit log
 	processName, _ := pm.getProcessName(pid)
+	commandLine := pm.getProcessCommandLine(pid)
+	audit := killAuditEntry{Port: port, PID: pid, ProcessName: processName, CommandLine: commandLine}
+
+	reason := pm.killReason(pid, processName, owned)
+	if reason == "" {
+		audit.Action = "refused"
+		pm.writeKillAudit(audit)
+		slog.Warn("refusing to kill process on port", "port", port, "pid", pid, "processName", processName)
+		return &KillRefusedError{Port: port, PID: pid, ProcessName: processName, CommandLine: commandLine}
+	}
+	audit.Reason = reason
 
-	// Log without exposing too much system info to prevent information disclosure
-	slog.Info("terminating process on port", "port", port, "pid", pid, "processName", processName)
-
-	cmd, args := buildKillProcessCommand(pid)
-
-	// Log the kill command for debugging (useful in CI/Codespaces)
-	slog.Debug("executing kill command", "cmd", cmd, "args", args, "pid", pid)
+	printKillingProcessMessage(port, pid, processName, commandLine, reason)
+	slog.Info("terminating process on port", "port", port, "pid", pid, "processName", processName, "reason", reason)
 
 	// Execute the kill command with timeout and without stdin inheritance
 	// Using exec.CommandContext directly instead of executor.RunCommand to avoid
@@ -195,7 +206,12 @@ func (pm *PortManager) KillProcessOnPort(port int) error {
 	ctx, cancel := context.WithTimeout(context.Background(), killProcessTimeout)
 	defer cancel()
 
-	// #nosec G204 -- Command injection safe: cmd is hard-coded ("powershell" or "sh"),
+	cmd, args := buildKillProcessCommand(pid, findChildPIDs(ctx, pid))
+
+	// Log the kill command for debugging (useful in CI/Codespaces)
+	slog.Debug("executing kill command", "cmd", cmd, "args", args, "pid", pid)
+
+	// #nosec G204 -- Command injection safe: cmd is a hard-coded binary,
 	// and PID is validated integer from strconv.Atoi in getProcessOnPort (no user input)
 	execCmd := exec.CommandContext(ctx, cmd, args...)
 	execCmd.Stdin = nil // Don't inherit stdin - prevents blocking
@@ -247,15 +263,16 @@ func (pm *PortManager) KillProcessOnPort(port int) error {
 			"killCmdStderr", strings.TrimSpace(stderr.String()),
 			"killCmdError", execErr)
 
-		return fmt.Errorf("process %d (%s) could not be terminated - it may be a protected system process or require administrator privileges",
+		err := fmt.Errorf("process %d (%s) could not be terminated - it may be a protected system process or require administrator privileges",
 			pid, currentProcessName)
+		audit.Action = "failed"
+		audit.Error = err.Error()
+		pm.writeKillAudit(audit)
+		return err
 	}
 
+	audit.Action = "killed"
+	pm.writeKillAudit(audit)
 	slog.Debug("process terminated successfully", "port", port, "pid", pid, "processName", processName)
 	return nil
 }
-
-// killProcessOnPort is an alias for KillProcessOnPort for internal use and test compatibility.
-func (pm *PortManager) killProcessOnPort(port int) error {
-	return pm.KillProcessOnPort(port)
-}
@@ -2,6 +2,7 @@ package portmanager
 
 import (
 	"context"
+	"net"
 	"os"
 	"os/exec"
 	"runtime"
@@ -14,7 +15,7 @@ import (
 
 // TestGetProcessOnPort_DoesNotHang verifies that getProcessOnPort returns within
 // the timeout period and doesn't hang indefinitely. This is a regression test
-// for Codespaces environments where lsof can hang.
+// for Codespaces environments where the lsof-based lookup used to hang.
 func TestGetProcessOnPort_DoesNotHang(t *testing.T) {
 	tempDir := t.TempDir()
 	pm := GetPortManager(tempDir)
@@ -63,7 +64,7 @@ func TestGetProcessOnPort_WithActivePort(t *testing.T) {
 	}
 
 	if err != nil {
-		// In CI environments, lsof may not be able to detect processes properly
+		// In CI environments, the socket owner may not be visible to the test process
 		t.Skipf("Skipping test - process detection not available in this environment: %v", err)
 	}
 
@@ -131,70 +132,40 @@ func TestGetProcessName_DoesNotHang(t *testing.T) {
 	}
 }
 
-// TestBuildGetProcessOnPortCommand_HasTimeout verifies that the Unix command
-// uses lsof correctly for process detection.
-// Note: Timeout protection is provided at the Go level via context.WithTimeout,
-// not via shell timeout command.
-func TestBuildGetProcessOnPortCommand_HasTimeout(t *testing.T) {
+// TestBuildKillProcessCommand_KillsChildrenFirst verifies that the Unix kill command
+// runs kill -9 directly with child PIDs listed before the parent.
+func TestBuildKillProcessCommand_KillsChildrenFirst(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("Unix-specific test")
 	}
 
-	cmd, args := buildGetProcessOnPortCommand(8080)
+	cmd, args := buildKillProcessCommand(12345, []int{200, 201})
 
-	if cmd != "sh" {
-		t.Errorf("Expected 'sh' command, got %s", cmd)
+	if cmd != "kill" {
+		t.Errorf("Expected 'kill' command, got %s", cmd)
 	}
 
-	if len(args) < 2 {
-		t.Fatalf("Expected at least 2 args, got %d", len(args))
+	want := []string{"-9", "200", "201", "12345"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("args = %v, want %v", args, want)
 	}
-
-	script := args[1]
-
-	// Verify the script uses lsof with the terse (-t) flag for PID-only output
-	// This is proven to work reliably in Codespaces/containers
-	hasLsofTerse := strings.Contains(script, "lsof -t")
-	hasPortSpec := strings.Contains(script, ":8080")
-
-	if !hasLsofTerse {
-		t.Errorf("Unix command should use 'lsof -t' for terse PID output, got: %s", script)
-	}
-
-	if !hasPortSpec {
-		t.Errorf("Unix command should include port specification :8080, got: %s", script)
-	}
-
-	t.Logf("Command script: %s", script)
 }
 
-// TestBuildKillProcessCommand_UsesPgrep verifies that the Unix kill command
-// uses pgrep (more portable) instead of pkill with flags that may not exist everywhere.
-func TestBuildKillProcessCommand_UsesPgrep(t *testing.T) {
-	if runtime.GOOS == "windows" {
-		t.Skip("Unix-specific test")
-	}
-
-	cmd, args := buildKillProcessCommand(12345)
-
-	if cmd != "sh" {
-		t.Errorf("Expected 'sh' command, got %s", cmd)
+func TestParseTasklistName(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{output: `"node.exe","5300","Console","1","45,120 K"`, want: "node"},
+		{output: `"Código App.EXE","42","Console","1","1,000 K"`, want: "Código App"},
+		{output: "INFO: No tasks are running which match the specified criteria.", want: ""},
+		{output: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := parseTasklistName(tt.output); got != tt.want {
+			t.Errorf("parseTasklistName(%q) = %q, want %q", tt.output, got, tt.want)
+		}
 	}
-
-	if len(args) < 2 {
-		t.Fatalf("Expected at least 2 args, got %d", len(args))
-	}
-
-	script := args[1]
-
-	// Verify the script uses kill -9 (like the user's working script)
-	hasKill9 := strings.Contains(script, "kill -9") || strings.Contains(script, "kill -KILL")
-
-	if !hasKill9 {
-		t.Error("Unix kill command should use kill -9 for reliable process termination")
-	}
-
-	t.Logf("Kill command script: %s", script)
 }
 
 // TestCommandsDoNotInheritStdin verifies that process commands don't inherit stdin,
@@ -257,3 +228,27 @@ func TestContextTimeoutIsRespected(t *testing.T) {
 
 	t.Logf("Timed-out command completed in %v", elapsed)
 }
+
+// TestGetProcessOnPort_FindsListener runs the real detection against a listener
+// owned by the test process.
+func TestGetProcessOnPort_FindsListener(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	pm := GetPortManager(t.TempDir())
+	pid, err := pm.getProcessOnPort(port)
+	if err != nil {
+		t.Fatalf("getProcessOnPort(%d) failed: %v", port, err)
+	}
+	if pid != os.Getpid() {
+		t.Errorf("getProcessOnPort(%d) = %d, want %d", port, pid, os.Getpid())
+	}
+
+	if name, err := pm.getProcessName(pid); err != nil || name == "" {
+		t.Errorf("getProcessName(%d) = %q, %v; want a name", pid, name, err)
+	}
+}
@@ -0,0 +1,152 @@
+package portmanager
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// heldPort is a port kept open in reservation mode until its service starts.
+type heldPort struct {
+	projectDir  string
+	serviceName string
+	reservation *PortReservation
+	extra       bool // A further port of the service (see ClaimPort), not its assigned one
+}
+
+// heldPorts tracks the ports held in reservation mode.
+// It is shared by all port managers because ports are global to the machine, and a service
+// may be started through a port manager of a different directory than the one that assigned it.
+var heldPorts = struct {
+	mu      sync.Mutex
+	enabled bool
+	byPort  map[int]*heldPort
+}{byPort: make(map[int]*heldPort)}
+
+// SetReservationMode turns reservation mode on or off for this process.
+//
+// In reservation mode, AssignPort keeps a listener open on every port it assigns, so that
+// no other service or process can bind the port between assignment and service start.
+// The port is freed with ReleaseHeldPort immediately before the service binds it.
+// Turning the mode off doesn't free ports that are already held; use ReleaseHeldPorts.
+func SetReservationMode(enabled bool) {
+	heldPorts.mu.Lock()
+	defer heldPorts.mu.Unlock()
+	heldPorts.enabled = enabled
+}
+
+// reservationModeEnabled reports whether AssignPort holds the ports it assigns.
+func reservationModeEnabled() bool {
+	heldPorts.mu.Lock()
+	defer heldPorts.mu.Unlock()
+	return heldPorts.enabled
+}
+
+// HoldPort reserves a port for a service by keeping a listener open on it until
+// ReleaseHeldPort is called. Holding a port the service already holds is a no-op.
+// Ports <= 0 (services without a port) are ignored.
+func (pm *PortManager) HoldPort(serviceName string, port int) error {
+	return pm.holdPort(serviceName, port, false)
+}
+
+// ClaimPort checks that a further port a service listens on, besides its assigned one, is
+// free, e.g. the Queue and Table ports of Azurite. In reservation mode the port is held like
+// an assigned port until ReleaseHeldPort. Claimed ports are not saved as assignments.
+func (pm *PortManager) ClaimPort(serviceName string, port int) error {
+	if owner, held := pm.heldForOtherService(serviceName, port); held {
+		return fmt.Errorf("port %d is reserved for service '%s'", port, owner)
+	}
+	if reservationModeEnabled() {
+		return pm.holdPort(serviceName, port, true)
+	}
+	if !pm.isPortAvailable(port) {
+		return fmt.Errorf("port %d is in use", port)
+	}
+	return nil
+}
+
+// holdPort holds a port for a service; extra marks a port claimed with ClaimPort.
+func (pm *PortManager) holdPort(serviceName string, port int, extra bool) error {
+	if port <= 0 {
+		return nil
+	}
+
+	heldPorts.mu.Lock()
+	defer heldPorts.mu.Unlock()
+
+	if held, exists := heldPorts.byPort[port]; exists {
+		if held.projectDir == pm.projectDir && held.serviceName == serviceName {
+			return nil
+		}
+		return fmt.Errorf("port %d is reserved for service '%s'", port, held.serviceName)
+	}
+
+	reservation, err := pm.ReservePort(port)
+	if err != nil {
+		return err
+	}
+	heldPorts.byPort[port] = &heldPort{
+		projectDir:  pm.projectDir,
+		serviceName: serviceName,
+		reservation: reservation,
+		extra:       extra,
+	}
+	slog.Debug("holding port until service starts", "service", serviceName, "port", port)
+	return nil
+}
+
+// ReleaseHeldPort frees a port held with HoldPort, if any.
+// Call it immediately before the service binds the port.
+func ReleaseHeldPort(port int) {
+	heldPorts.mu.Lock()
+	held, exists := heldPorts.byPort[port]
+	delete(heldPorts.byPort, port)
+	heldPorts.mu.Unlock()
+
+	if !exists {
+		return
+	}
+	if err := held.reservation.Release(); err != nil {
+		slog.Debug("failed to release held port", "service", held.serviceName, "port", port, "error", err)
+	}
+}
+
+// ReleaseHeldPorts frees every held port, e.g. when services are not started after all.
+func ReleaseHeldPorts() {
+	heldPorts.mu.Lock()
+	ports := make([]int, 0, len(heldPorts.byPort))
+	for port := range heldPorts.byPort {
+		ports = append(ports, port)
+	}
+	heldPorts.mu.Unlock()
+
+	for _, port := range ports {
+		ReleaseHeldPort(port)
+	}
+}
+
+// heldPortOf returns the assigned port held for a service of this port manager's project, if any.
+func (pm *PortManager) heldPortOf(serviceName string) (int, bool) {
+	heldPorts.mu.Lock()
+	defer heldPorts.mu.Unlock()
+
+	for port, held := range heldPorts.byPort {
+		if held.projectDir == pm.projectDir && held.serviceName == serviceName && !held.extra {
+			return port, true
+		}
+	}
+	return 0, false
+}
+
+// heldForOtherService returns the service a port is held for, if it is held for any
+// service other than serviceName of this port manager's project.
+func (pm *PortManager) heldForOtherService(serviceName string, port int) (string, bool) {
+	heldPorts.mu.Lock()
+	defer heldPorts.mu.Unlock()
+
+	held, exists := heldPorts.byPort[port]
+	if !exists || (held.projectDir == pm.projectDir && held.serviceName == serviceName) {
+		return "", false
+	}
+	return held.serviceName, true
+}
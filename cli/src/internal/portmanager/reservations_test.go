@@ -0,0 +1,135 @@
+package portmanager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+)
+
+// freePort returns a port that nothing is listening on.
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+	return port
+}
+
+// canBind reports whether the port can be bound right now.
+func canBind(port int) bool {
+	lc := net.ListenConfig{}
+	listener, err := lc.Listen(context.Background(), "tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	_ = listener.Close()
+	return true
+}
+
+func TestHoldPort(t *testing.T) {
+	pm := setupTestManager(t.TempDir(), nil)
+	port := freePort(t)
+	t.Cleanup(func() { ReleaseHeldPort(port) })
+
+	if err := pm.HoldPort("api", port); err != nil {
+		t.Fatalf("HoldPort() error = %v", err)
+	}
+	if err := pm.HoldPort("api", port); err != nil {
+		t.Errorf("HoldPort() for the same service should be a no-op, got %v", err)
+	}
+	if err := pm.HoldPort("web", port); err == nil {
+		t.Error("HoldPort() for another service should fail")
+	}
+	if owner, held := pm.heldForOtherService("web", port); !held || owner != "api" {
+		t.Errorf("heldForOtherService() = %q, %v, want api, true", owner, held)
+	}
+	if canBind(port) {
+		t.Error("held port should not be bindable")
+	}
+
+	ReleaseHeldPort(port)
+	if !canBind(port) {
+		t.Error("port should be bindable after ReleaseHeldPort")
+	}
+	if _, held := pm.heldPortOf("api"); held {
+		t.Error("port should no longer be held")
+	}
+}
+
+func TestAssignPort_ReservationMode(t *testing.T) {
+	SetReservationMode(true)
+	t.Cleanup(func() {
+		SetReservationMode(false)
+		ReleaseHeldPorts()
+	})
+
+	pm := setupTestManager(t.TempDir(), nil)
+	port := freePort(t)
+
+	assigned, _, err := pm.AssignPort("api", port, false)
+	if err != nil {
+		t.Fatalf("AssignPort() error = %v", err)
+	}
+	if assigned != port {
+		t.Fatalf("AssignPort() = %d, want %d", assigned, port)
+	}
+	if held, ok := pm.heldPortOf("api"); !ok || held != port {
+		t.Errorf("heldPortOf(api) = %d, %v, want %d, true", held, ok, port)
+	}
+
+	// Assigning again returns the held port instead of reporting a conflict with it
+	again, _, err := pm.AssignPort("api", port, false)
+	if err != nil || again != port {
+		t.Errorf("AssignPort() again = %d, %v, want %d", again, err, port)
+	}
+
+	// Another service preferring the same port gets a different one
+	other, _, err := pm.AssignPort("web", port, false)
+	if err != nil {
+		t.Fatalf("AssignPort(web) error = %v", err)
+	}
+	if other == port {
+		t.Errorf("AssignPort(web) = %d, want a port other than the one held for api", other)
+	}
+
+	// An explicit port held for another service is an error, not a kill prompt
+	if _, _, err := pm.AssignPort("worker", port, true); err == nil {
+		t.Error("AssignPort() with an explicit port held for another service should fail")
+	}
+}
+
+func TestClaimPort(t *testing.T) {
+	SetReservationMode(true)
+	t.Cleanup(func() {
+		SetReservationMode(false)
+		ReleaseHeldPorts()
+	})
+
+	pm := setupTestManager(t.TempDir(), nil)
+	port, extra := freePort(t), freePort(t)
+
+	if _, _, err := pm.AssignPort("storage", port, false); err != nil {
+		t.Fatalf("AssignPort() error = %v", err)
+	}
+	if err := pm.ClaimPort("storage", extra); err != nil {
+		t.Fatalf("ClaimPort() error = %v", err)
+	}
+	if canBind(extra) {
+		t.Error("claimed port should be held in reservation mode")
+	}
+	if err := pm.ClaimPort("web", extra); err == nil {
+		t.Error("ClaimPort() of a port claimed for another service should fail")
+	}
+
+	// The claimed port is not taken for the service's assigned port
+	if held, ok := pm.heldPortOf("storage"); !ok || held != port {
+		t.Errorf("heldPortOf(storage) = %d, %v, want %d, true", held, ok, port)
+	}
+	if again, _, err := pm.AssignPort("storage", port, false); err != nil || again != port {
+		t.Errorf("AssignPort() again = %d, %v, want %d", again, err, port)
+	}
+}
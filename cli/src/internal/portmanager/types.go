@@ -31,6 +31,9 @@ const (
 	// portCleanupRetryWait is the additional wait time between retry attempts.
 	portCleanupRetryWait = 500 * time.Millisecond
 
+	// socketDialTimeout bounds the connection attempt that checks whether a unix socket is in use.
+	socketDialTimeout = 500 * time.Millisecond
+
 	// Cache limits
 	// maxCacheSize prevents unbounded memory growth in long-running processes.
 	// 50 projects × ~1KB each = ~50KB max overhead, which is negligible.
@@ -40,11 +43,22 @@ const (
 	// Environment variables for configuration
 	envPortRangeStart = "AZD_PORT_RANGE_START"
 	envPortRangeEnd   = "AZD_PORT_RANGE_END"
+	envPortStrategy   = "AZD_PORT_STRATEGY"
 
 	// staleThreshold defines how old an assignment must be to be considered stale.
 	staleThreshold = 7 * 24 * time.Hour // 7 days
 )
 
+// Protocol is the transport protocol a service listens on, which decides how a port's
+// availability is checked.
+type Protocol string
+
+// Protocols.
+const (
+	ProtocolTCP Protocol = "tcp"
+	ProtocolUDP Protocol = "udp"
+)
+
 // PortAssignment represents a port assignment for a service.
 type PortAssignment struct {
 	ServiceName string    `json:"serviceName"`
@@ -0,0 +1,217 @@
+// Package proxy provides a reverse proxy that serves all local services from one port,
+// routing each request to a service by its path prefix.
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultPort is the port the proxy listens on when none is configured.
+const DefaultPort = 9000
+
+// DefaultWatchInterval is how often Watch resolves the routes' services.
+const DefaultWatchInterval = 2 * time.Second
+
+// Route sends requests whose path starts with Path to Service.
+type Route struct {
+	Path        string // Path prefix, e.g. "/api" or "/"
+	Service     string // Name of the service that handles the requests
+	StripPrefix bool   // Remove Path before forwarding (/api/users → /users)
+}
+
+// matches reports whether the request path is under the route's prefix.
+func (r Route) matches(path string) bool {
+	if r.Path == "/" {
+		return true
+	}
+	return path == r.Path || strings.HasPrefix(path, r.Path+"/")
+}
+
+// Resolver returns the base URL of a running service, or an error if it isn't running.
+type Resolver func(service string) (*url.URL, error)
+
+// Proxy routes requests to services by path prefix. Targets are resolved on every request,
+// so a service restarted on another port is picked up without restarting the proxy.
+type Proxy struct {
+	routes    []Route // Longest prefix first
+	resolve   Resolver
+	transport http.RoundTripper
+	server    *http.Server
+}
+
+// New creates a proxy for the routes. Paths are normalized to a leading slash without a
+// trailing one; an empty path or service, or the same path twice, is an error.
+func New(routes []Route, resolve Resolver) (*Proxy, error) {
+	if len(routes) == 0 {
+		return nil, errors.New("no proxy routes configured")
+	}
+
+	normalized := make([]Route, 0, len(routes))
+	seen := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		if route.Service == "" {
+			return nil, fmt.Errorf("proxy route '%s' has no service", route.Path)
+		}
+		path := strings.TrimSpace(route.Path)
+		if path == "" {
+			return nil, fmt.Errorf("proxy route for service '%s' has no path", route.Service)
+		}
+		path = "/" + strings.Trim(path, "/")
+		if seen[path] {
+			return nil, fmt.Errorf("proxy route '%s' is defined more than once", path)
+		}
+		seen[path] = true
+		route.Path = path
+		normalized = append(normalized, route)
+	}
+	sort.SliceStable(normalized, func(i, j int) bool {
+		return len(normalized[i].Path) > len(normalized[j].Path)
+	})
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// HTTPS services use the local development certificate, which may be self-signed
+	// #nosec G402 -- the proxy only forwards to services on localhost
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12}
+
+	return &Proxy{routes: normalized, resolve: resolve, transport: transport}, nil
+}
+
+// Routes returns the routes, longest prefix first.
+func (p *Proxy) Routes() []Route {
+	return p.routes
+}
+
+// match returns the route with the longest prefix that matches the path.
+func (p *Proxy) match(path string) (Route, bool) {
+	for _, route := range p.routes {
+		if route.matches(path) {
+			return route, true
+		}
+	}
+	return Route{}, false
+}
+
+// ServeHTTP forwards the request to the service of the matching route. Requests that match no
+// route get 404; requests for a service that isn't running get 502.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, ok := p.match(r.URL.Path)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no proxy route matches %s", r.URL.Path), http.StatusNotFound)
+		return
+	}
+	target, err := p.resolve(route.Service)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	reverseProxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			if route.StripPrefix && route.Path != "/" {
+				pr.Out.URL.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(pr.In.URL.Path, route.Path), "/")
+				pr.Out.URL.RawPath = ""
+			}
+			pr.SetURL(target)
+			pr.SetXForwarded()
+		},
+		Transport: p.transport,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			slog.Debug("proxy request failed", "service", route.Service, "path", r.URL.Path, "error", err)
+			http.Error(w, fmt.Sprintf("service '%s' is not reachable: %v", route.Service, err), http.StatusBadGateway)
+		},
+	}
+	reverseProxy.ServeHTTP(w, r)
+}
+
+// Start listens on localhost at port and serves requests in the background.
+// It returns the proxy's URL.
+func (p *Proxy) Start(port int) (string, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return "", fmt.Errorf("proxy port %d is not available (set proxy.port in azure.yaml to use another): %w", port, err)
+	}
+	p.server = &http.Server{
+		Handler:           p,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := p.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			slog.Warn("proxy server error", "error", err)
+		}
+	}()
+	return fmt.Sprintf("http://localhost:%d", port), nil
+}
+
+// RouteChange is a route whose service moved to another backend, e.g. restarted on a new port.
+// Requests keep using the proxy's URL; only the backend they are forwarded to changed.
+type RouteChange struct {
+	Route Route
+	From  *url.URL
+	To    *url.URL
+}
+
+// Watch resolves the routes' services every interval until ctx is canceled and calls onChange
+// for each route whose backend changed. A service that stops, or starts again on the same URL,
+// is not a change; requests already follow the backend, as targets are resolved per request.
+func (p *Proxy) Watch(ctx context.Context, interval time.Duration, onChange func(RouteChange)) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	targets := p.resolveTargets(nil)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := p.resolveTargets(targets)
+		for _, route := range p.routes {
+			from, to := targets[route.Service], current[route.Service]
+			if from != nil && to != nil && from.String() != to.String() {
+				onChange(RouteChange{Route: route, From: from, To: to})
+			}
+		}
+		targets = current
+	}
+}
+
+// resolveTargets returns the backend of each routed service. A service that can't be resolved
+// keeps its previous backend, so a restart on a new port is reported against the old one.
+func (p *Proxy) resolveTargets(previous map[string]*url.URL) map[string]*url.URL {
+	targets := make(map[string]*url.URL, len(p.routes))
+	for _, route := range p.routes {
+		if _, done := targets[route.Service]; done {
+			continue
+		}
+		if target, err := p.resolve(route.Service); err == nil {
+			targets[route.Service] = target
+		} else if target := previous[route.Service]; target != nil {
+			targets[route.Service] = target
+		}
+	}
+	return targets
+}
+
+// Stop shuts the proxy down, waiting for in-flight requests until ctx is done.
+func (p *Proxy) Stop(ctx context.Context) error {
+	if p.server == nil {
+		return nil
+	}
+	return p.server.Shutdown(ctx)
+}
@@ -0,0 +1,197 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// echoServer responds with its name and the request path.
+func echoServer(t *testing.T, name string) *url.URL {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", name, r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return target
+}
+
+func TestProxy_Routing(t *testing.T) {
+	targets := map[string]*url.URL{
+		"web":   echoServer(t, "web"),
+		"api":   echoServer(t, "api"),
+		"admin": echoServer(t, "admin"),
+	}
+	resolve := func(service string) (*url.URL, error) {
+		if target, ok := targets[service]; ok {
+			return target, nil
+		}
+		return nil, fmt.Errorf("service '%s' is not running", service)
+	}
+
+	p, err := New([]Route{
+		{Path: "/", Service: "web"},
+		{Path: "api/", Service: "api", StripPrefix: true},
+		{Path: "/api/admin", Service: "admin"},
+		{Path: "/reports", Service: "reports"},
+	}, resolve)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{path: "/", wantStatus: http.StatusOK, wantBody: "web /"},
+		{path: "/about", wantStatus: http.StatusOK, wantBody: "web /about"},
+		{path: "/api", wantStatus: http.StatusOK, wantBody: "api /"},
+		{path: "/api/users/1", wantStatus: http.StatusOK, wantBody: "api /users/1"},
+		{path: "/apiary", wantStatus: http.StatusOK, wantBody: "web /apiary"},
+		{path: "/api/admin/stats", wantStatus: http.StatusOK, wantBody: "admin /api/admin/stats"},
+		{path: "/reports/q1", wantStatus: http.StatusBadGateway, wantBody: "service 'reports' is not running"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := http.Get(server.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.wantStatus || !strings.Contains(string(body), tt.wantBody) {
+				t.Errorf("GET %s = %d %q, want %d %q", tt.path, resp.StatusCode, body, tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestProxy_NoMatchingRoute(t *testing.T) {
+	p, err := New([]Route{{Path: "/api", Service: "api"}}, func(string) (*url.URL, error) {
+		return nil, fmt.Errorf("unexpected resolve")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestProxy_UnreachableService(t *testing.T) {
+	backend := httptest.NewServer(http.NotFoundHandler())
+	down, _ := url.Parse(backend.URL)
+	backend.Close()
+
+	p, err := New([]Route{{Path: "/", Service: "web"}}, func(string) (*url.URL, error) { return down, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusBadGateway || !strings.Contains(rec.Body.String(), "service 'web' is not reachable") {
+		t.Errorf("response = %d %q, want 502 naming the service", rec.Code, rec.Body.String())
+	}
+}
+
+func TestNew_InvalidRoutes(t *testing.T) {
+	resolve := func(string) (*url.URL, error) { return nil, nil }
+	tests := []struct {
+		name    string
+		routes  []Route
+		wantErr string
+	}{
+		{name: "none", routes: nil, wantErr: "no proxy routes"},
+		{name: "no service", routes: []Route{{Path: "/api"}}, wantErr: "has no service"},
+		{name: "no path", routes: []Route{{Service: "api"}}, wantErr: "has no path"},
+		{name: "duplicate", routes: []Route{{Path: "/api", Service: "a"}, {Path: "/api/", Service: "b"}}, wantErr: "more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.routes, resolve); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProxy_WatchReportsBackendChanges(t *testing.T) {
+	first, second := echoServer(t, "api-old"), echoServer(t, "api-new")
+
+	var mu sync.Mutex
+	current := first
+	resolve := func(service string) (*url.URL, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if service != "api" || current == nil {
+			return nil, fmt.Errorf("service '%s' is not running", service)
+		}
+		return current, nil
+	}
+	setBackend := func(target *url.URL) {
+		mu.Lock()
+		defer mu.Unlock()
+		current = target
+	}
+
+	p, err := New([]Route{{Path: "/api", Service: "api"}, {Path: "/", Service: "web"}}, resolve)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	changes := make(chan RouteChange, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Watch(ctx, 10*time.Millisecond, func(change RouteChange) { changes <- change })
+	time.Sleep(30 * time.Millisecond)
+
+	// The service stops, then restarts on another port
+	setBackend(nil)
+	time.Sleep(30 * time.Millisecond)
+	setBackend(second)
+
+	select {
+	case change := <-changes:
+		if change.Route.Path != "/api" || change.From.String() != first.String() || change.To.String() != second.String() {
+			t.Errorf("RouteChange = %+v, want /api moved from %s to %s", change, first, second)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() did not report the backend change")
+	}
+
+	// The proxy URL stays the same and reaches the new backend
+	resp, err := http.Get(server.URL + "/api/orders")
+	if err != nil {
+		t.Fatalf("GET via proxy failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "api-new /api/orders" {
+		t.Errorf("body = %q, want the new backend", body)
+	}
+
+	select {
+	case change := <-changes:
+		t.Errorf("unexpected second RouteChange %+v", change)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,529 @@
+// Package schema validates azure.yaml against the azd app JSON schema.
+//
+// azure.yaml.json is a copy of schemas/v1.1/azure.yaml.json at the root of the repository,
+// embedded so validation works offline; TestSchemaMatchesPublished keeps the two in sync.
+package schema
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dlclark/regexp2"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+)
+
+// URL is the published location of the schema, which azure.yaml files reference.
+const URL = "https://raw.githubusercontent.com/jongio/azd-app/main/schemas/v1.1/azure.yaml.json"
+
+// Issue severities. Errors are schema violations; warnings are fields the schema doesn't
+// describe in places where it allows them, which are usually typos.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+//go:embed azure.yaml.json
+var schemaJSON []byte
+
+// Issue is a problem found in azure.yaml.
+type Issue struct {
+	Severity string `json:"severity"`        // error or warning
+	Path     string `json:"path"`            // Location of the value, e.g. "services.api.host"
+	Line     int    `json:"line"`            // 1-based; 0 when unknown
+	Column   int    `json:"column"`          // 1-based; 0 when unknown
+	Field    string `json:"field,omitempty"` // The field, for unknown fields
+	Message  string `json:"message"`
+}
+
+// String formats the issue as "line:column: message".
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", i.Line, i.Column, i.Message)
+}
+
+var (
+	compileOnce sync.Once
+	compiled    *jsonschema.Schema
+	compileErr  error
+	knownFields map[string]map[string]bool // Fields of the objects that allow others, by "root" or "service"
+)
+
+// offlineLoader resolves the schemas azure.yaml.json references on other sites, such as the
+// schema of AI agent services, to an empty schema, so validation never goes to the network.
+type offlineLoader struct{}
+
+func (offlineLoader) Load(string) (any, error) {
+	return map[string]any{}, nil
+}
+
+// ecmaRegexp compiles the patterns of the schema as ECMAScript regular expressions, as JSON
+// Schema specifies; Go's regexp lacks lookarounds, which e.g. the customDomain pattern uses.
+func ecmaRegexp(pattern string) (jsonschema.Regexp, error) {
+	re, err := regexp2.Compile(pattern, regexp2.ECMAScript)
+	if err != nil {
+		return nil, err
+	}
+	return ecmaPattern{re}, nil
+}
+
+// ecmaPattern is a compiled ECMAScript regular expression.
+type ecmaPattern struct {
+	re *regexp2.Regexp
+}
+
+func (p ecmaPattern) MatchString(s string) bool {
+	matched, err := p.re.MatchString(s)
+	return err == nil && matched
+}
+
+func (p ecmaPattern) String() string {
+	return p.re.String()
+}
+
+// load compiles the embedded schema once.
+func load() (*jsonschema.Schema, error) {
+	compileOnce.Do(func() {
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+		if err != nil {
+			compileErr = fmt.Errorf("failed to parse azure.yaml schema: %w", err)
+			return
+		}
+		compiler := jsonschema.NewCompiler()
+		compiler.UseLoader(offlineLoader{})
+		compiler.UseRegexpEngine(ecmaRegexp)
+		if err := compiler.AddResource(URL, doc); err != nil {
+			compileErr = fmt.Errorf("failed to load azure.yaml schema: %w", err)
+			return
+		}
+		if compiled, err = compiler.Compile(URL); err != nil {
+			compileErr = fmt.Errorf("failed to compile azure.yaml schema: %w", err)
+			return
+		}
+
+		var raw struct {
+			Properties  map[string]json.RawMessage `json:"properties"`
+			Definitions map[string]json.RawMessage `json:"definitions"`
+		}
+		if err := json.Unmarshal(schemaJSON, &raw); err != nil {
+			compileErr = fmt.Errorf("failed to parse azure.yaml schema: %w", err)
+			return
+		}
+		knownFields = map[string]map[string]bool{
+			"root":    fieldSet(raw.Properties),
+			"service": declaredFields(raw.Definitions["service"]),
+		}
+	})
+	return compiled, compileErr
+}
+
+// declaredFields returns the properties a schema object declares, including in its
+// allOf, anyOf, oneOf and if/then/else branches.
+func declaredFields(data json.RawMessage) map[string]bool {
+	var node struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		AllOf      []json.RawMessage          `json:"allOf"`
+		AnyOf      []json.RawMessage          `json:"anyOf"`
+		OneOf      []json.RawMessage          `json:"oneOf"`
+		Then       json.RawMessage            `json:"then"`
+		Else       json.RawMessage            `json:"else"`
+	}
+	if len(data) == 0 || json.Unmarshal(data, &node) != nil {
+		return map[string]bool{}
+	}
+	fields := fieldSet(node.Properties)
+	branches := append(append(append([]json.RawMessage{}, node.AllOf...), node.AnyOf...), node.OneOf...)
+	branches = append(branches, node.Then, node.Else)
+	for _, branch := range branches {
+		for field := range declaredFields(branch) {
+			fields[field] = true
+		}
+	}
+	return fields
+}
+
+// fieldSet returns the names of properties.
+func fieldSet(properties map[string]json.RawMessage) map[string]bool {
+	fields := make(map[string]bool, len(properties))
+	for name := range properties {
+		fields[name] = true
+	}
+	return fields
+}
+
+// Validate validates the content of an azure.yaml file against the schema and returns the
+// issues found, ordered by position. YAML syntax errors are returned as an issue too.
+func Validate(data []byte) ([]Issue, error) {
+	sch, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []Issue{syntaxIssue(err)}, nil
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return []Issue{{Severity: SeverityError, Message: "azure.yaml is empty"}}, nil
+	}
+
+	positions := make(map[string]position)
+	value, nodeErr := nodeValue(doc.Content[0], "", positions)
+	if nodeErr != nil {
+		return []Issue{{Severity: SeverityError, Line: nodeErr.line, Column: nodeErr.column, Message: nodeErr.message}}, nil
+	}
+
+	var issues []Issue
+	if err := sch.Validate(value); err != nil {
+		validationErr, ok := err.(*jsonschema.ValidationError)
+		if !ok {
+			return nil, fmt.Errorf("failed to validate azure.yaml: %w", err)
+		}
+		issues = append(issues, schemaIssues(validationErr, positions)...)
+	}
+	issues = append(issues, unknownFieldIssues(value, positions)...)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues, nil
+}
+
+// position is where a value, or the key of a field, starts in azure.yaml.
+type position struct {
+	line, column       int
+	keyLine, keyColumn int  // Of the key, for values of mapping fields
+	collection         bool // The value is a mapping or sequence
+}
+
+// at returns where issues about the value are reported: at the value for scalars, and at
+// its key for mappings and sequences, whose first line is the one of their first item.
+func (p position) at() (int, int) {
+	if p.collection && p.keyLine > 0 {
+		return p.keyLine, p.keyColumn
+	}
+	return p.line, p.column
+}
+
+// nodeError is a YAML construct that has no JSON equivalent.
+type nodeError struct {
+	line, column int
+	message      string
+}
+
+// nodeValue converts a YAML node to the value the schema is validated against, recording the
+// position of each value by its JSON pointer.
+func nodeValue(node *yaml.Node, pointer string, positions map[string]position) (any, *nodeError) {
+	pos := positions[pointer]
+	pos.line, pos.column = node.Line, node.Column
+	pos.collection = node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode
+	positions[pointer] = pos
+
+	switch node.Kind {
+	case yaml.AliasNode:
+		value, err := nodeValue(node.Alias, pointer, positions)
+		positions[pointer] = pos
+		return value, err
+	case yaml.MappingNode:
+		obj := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, valueNode := node.Content[i], node.Content[i+1]
+			if key.Tag == "!!merge" {
+				// Merge keys (<<: *anchor) add the fields of the anchored mapping
+				merged, err := nodeValue(valueNode, pointer, positions)
+				if err != nil {
+					return nil, err
+				}
+				if fields, ok := merged.(map[string]any); ok {
+					for name, value := range fields {
+						if _, exists := obj[name]; !exists {
+							obj[name] = value
+						}
+					}
+				}
+				positions[pointer] = pos
+				continue
+			}
+			if key.Kind != yaml.ScalarNode {
+				return nil, &nodeError{key.Line, key.Column, "keys must be strings"}
+			}
+			childPointer := pointer + "/" + escapePointer(key.Value)
+			positions[childPointer] = position{keyLine: key.Line, keyColumn: key.Column}
+			value, err := nodeValue(valueNode, childPointer, positions)
+			if err != nil {
+				return nil, err
+			}
+			obj[key.Value] = value
+		}
+		return obj, nil
+	case yaml.SequenceNode:
+		arr := make([]any, 0, len(node.Content))
+		for i, item := range node.Content {
+			value, err := nodeValue(item, pointer+"/"+strconv.Itoa(i), positions)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		return arr, nil
+	default:
+		switch node.Tag {
+		case "!!null":
+			return nil, nil
+		case "!!bool", "!!int", "!!float":
+			var value any
+			if err := node.Decode(&value); err == nil {
+				return value, nil
+			}
+		}
+		// Strings, and scalars such as timestamps that JSON only has as strings
+		return node.Value, nil
+	}
+}
+
+// schemaIssues returns an issue for each failed schema keyword of a validation error.
+func schemaIssues(err *jsonschema.ValidationError, positions map[string]position) []Issue {
+	printer := message.NewPrinter(language.English)
+	seen := make(map[string]bool)
+	var issues []Issue
+	add := func(issue Issue) {
+		if !seen[issue.Path+issue.Message] {
+			seen[issue.Path+issue.Message] = true
+			issues = append(issues, issue)
+		}
+	}
+	valueIssue := func(location []string, message string) Issue {
+		line, column := positions[joinPointer(location)].at()
+		return Issue{
+			Severity: SeverityError,
+			Path:     displayPath(location),
+			Line:     line,
+			Column:   column,
+			Message:  fmt.Sprintf("%s: %s", displayPath(location), message),
+		}
+	}
+
+	var collect func(e *jsonschema.ValidationError)
+	collect = func(e *jsonschema.ValidationError) {
+		causes := relevantCauses(e)
+		if alternatives := alternativeMessages(e, causes, printer); alternatives != "" {
+			add(valueIssue(e.InstanceLocation, alternatives))
+			return
+		}
+		if len(causes) > 0 {
+			for _, cause := range causes {
+				collect(cause)
+			}
+			return
+		}
+
+		// Fields the schema doesn't allow are reported one by one at their keys
+		if additional, ok := e.ErrorKind.(*kind.AdditionalProperties); ok {
+			for _, field := range additional.Properties {
+				add(fieldIssue(SeverityError, joinPointer(append(e.InstanceLocation, field)), field, positions))
+			}
+			return
+		}
+		add(valueIssue(e.InstanceLocation, e.ErrorKind.LocalizedString(printer)))
+	}
+	collect(err)
+	return issues
+}
+
+// alternativeMessages returns the failures of the alternatives of an anyOf or oneOf as one
+// message, e.g. "got string, want object or got string, want array", when each failed on the
+// value itself; or "" when the failures are to be reported one by one.
+func alternativeMessages(e *jsonschema.ValidationError, causes []*jsonschema.ValidationError, printer *message.Printer) string {
+	switch e.ErrorKind.(type) {
+	case *kind.AnyOf, *kind.OneOf:
+	default:
+		return ""
+	}
+	if len(causes) < 2 {
+		return ""
+	}
+	messages := make([]string, 0, len(causes))
+	for _, cause := range causes {
+		leaf := cause
+		for len(leaf.Causes) == 1 {
+			leaf = leaf.Causes[0]
+		}
+		if len(leaf.Causes) > 0 || joinPointer(leaf.InstanceLocation) != joinPointer(e.InstanceLocation) {
+			return ""
+		}
+		if _, ok := leaf.ErrorKind.(*kind.AdditionalProperties); ok {
+			return ""
+		}
+		messages = append(messages, leaf.ErrorKind.LocalizedString(printer))
+	}
+	return strings.Join(messages, " or ")
+}
+
+// relevantCauses returns the causes of a validation error. For anyOf and oneOf, the
+// alternatives of another type than the value are left out when some have its type, so a
+// hook object with a typo isn't also reported for not being an array of hooks.
+func relevantCauses(e *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	switch e.ErrorKind.(type) {
+	case *kind.AnyOf, *kind.OneOf:
+	default:
+		return e.Causes
+	}
+	var matching []*jsonschema.ValidationError
+	for _, cause := range e.Causes {
+		if !isTypeMismatch(cause) {
+			matching = append(matching, cause)
+		}
+	}
+	if len(matching) == 0 {
+		return e.Causes
+	}
+	return matching
+}
+
+// isTypeMismatch reports whether a validation error is only that the value has another type.
+func isTypeMismatch(e *jsonschema.ValidationError) bool {
+	if len(e.Causes) == 0 {
+		_, ok := e.ErrorKind.(*kind.Type)
+		return ok
+	}
+	for _, cause := range e.Causes {
+		if !isTypeMismatch(cause) {
+			return false
+		}
+	}
+	return true
+}
+
+// unknownFieldIssues warns about the fields of the top level and of services that the schema
+// doesn't describe. The schema allows them, since azd and its extensions add fields, but
+// they're usually misspelled fields that would otherwise be ignored without notice.
+func unknownFieldIssues(value any, positions map[string]position) []Issue {
+	root, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var issues []Issue
+	for _, field := range sortedKeys(root) {
+		if !knownFields["root"][field] {
+			issues = append(issues, fieldIssue(SeverityWarning, "/"+escapePointer(field), field, positions))
+		}
+	}
+
+	services, _ := root["services"].(map[string]any)
+	for _, name := range sortedKeys(services) {
+		svc, ok := services[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, field := range sortedKeys(svc) {
+			if !knownFields["service"][field] {
+				pointer := "/services/" + escapePointer(name) + "/" + escapePointer(field)
+				issues = append(issues, fieldIssue(SeverityWarning, pointer, field, positions))
+			}
+		}
+	}
+	return issues
+}
+
+// fieldIssue returns an unknown field issue positioned at the field's key.
+func fieldIssue(severity, pointer, field string, positions map[string]position) Issue {
+	pos := positions[pointer]
+	path := displayPath(splitPointer(pointer))
+	message := fmt.Sprintf("%s: unknown field %q", path, field)
+	if severity == SeverityError {
+		message += " is not allowed"
+	}
+	return Issue{
+		Severity: severity,
+		Path:     path,
+		Line:     pos.keyLine,
+		Column:   pos.keyColumn,
+		Field:    field,
+		Message:  message,
+	}
+}
+
+// syntaxIssue converts a YAML syntax error, such as "yaml: line 3: mapping values are not
+// allowed in this context", to an issue on its line.
+func syntaxIssue(err error) Issue {
+	issue := Issue{Severity: SeverityError, Message: err.Error()}
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	if rest, ok := strings.CutPrefix(msg, "line "); ok {
+		if lineText, detail, found := strings.Cut(rest, ": "); found {
+			if line, convErr := strconv.Atoi(lineText); convErr == nil {
+				issue.Line, issue.Column, issue.Message = line, 1, detail
+			}
+		}
+	}
+	return issue
+}
+
+// displayPath formats an instance location as a dotted path, e.g. "services.api.ports[0]".
+func displayPath(location []string) string {
+	if len(location) == 0 {
+		return "(root)"
+	}
+	var sb strings.Builder
+	for _, segment := range location {
+		if _, err := strconv.Atoi(segment); err == nil && sb.Len() > 0 {
+			sb.WriteString("[" + segment + "]")
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(segment)
+	}
+	return sb.String()
+}
+
+// joinPointer returns the JSON pointer of an instance location.
+func joinPointer(location []string) string {
+	var sb strings.Builder
+	for _, segment := range location {
+		sb.WriteString("/" + escapePointer(segment))
+	}
+	return sb.String()
+}
+
+// splitPointer returns the segments of a JSON pointer.
+func splitPointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+	}
+	return segments
+}
+
+// escapePointer escapes a segment of a JSON pointer.
+func escapePointer(segment string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(segment)
+}
+
+// sortedKeys returns the keys of a map in order.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSchemaMatchesPublished(t *testing.T) {
+	published, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "schemas", "v1.1", "azure.yaml.json"))
+	if err != nil {
+		t.Skipf("published schema not available: %v", err)
+	}
+	if !bytes.Equal(published, schemaJSON) {
+		t.Error("azure.yaml.json differs from schemas/v1.1/azure.yaml.json; copy the published schema here")
+	}
+}
+
+func TestValidate_Valid(t *testing.T) {
+	content := `name: shop
+reqs:
+  - name: node
+    minVersion: "20.0.0"
+services:
+  api:
+    project: ./api
+    language: python
+    host: containerapp
+    ports:
+      - "8000"
+hooks:
+  prerun:
+    run: echo starting
+profiles:
+  ci:
+    services:
+      api:
+        ports:
+          - "9000"
+`
+	issues, err := Validate([]byte(content))
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Validate() = %v, want no issues", issues)
+	}
+}
+
+func TestValidate_Issues(t *testing.T) {
+	content := `name: shop
+reqs:
+  - minVersion: "20.0.0"
+services:
+  api:
+    project: ./api
+    host: containerapp
+    prot: 8000
+hooks:
+  prerun:
+    run: echo starting
+    shel: sh
+`
+	issues, err := Validate([]byte(content))
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	want := []struct {
+		severity     string
+		line, column int
+		field        string
+	}{
+		{SeverityError, 3, 5, ""},       // reqs[0] is missing name
+		{SeverityWarning, 8, 5, "prot"}, // services allow other fields
+		{SeverityError, 12, 5, "shel"},  // hooks don't
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Validate() = %v, want %d issues", issues, len(want))
+	}
+	for i, w := range want {
+		got := issues[i]
+		if got.Severity != w.severity || got.Line != w.line || got.Column != w.column || got.Field != w.field {
+			t.Errorf("issue %d = %+v, want %s at %d:%d for field %q", i, got, w.severity, w.line, w.column, w.field)
+		}
+	}
+	if issues[0].Path != "reqs[0]" {
+		t.Errorf("issue path = %q, want reqs[0]", issues[0].Path)
+	}
+}
+
+// TestValidate_TestProjects checks that the azure.yaml files of the test projects, which are
+// run by azd app without a host, are valid against the schema.
+func TestValidate_TestProjects(t *testing.T) {
+	root := filepath.Join("..", "..", "..", "tests", "projects")
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.Name() != "azure.yaml" {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		issues, err := Validate(data)
+		if err != nil {
+			t.Errorf("Validate(%s) error = %v", path, err)
+		}
+		for _, issue := range issues {
+			t.Errorf("%s: %s", path, issue)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk test projects: %v", err)
+	}
+}
+
+func TestValidate_SyntaxError(t *testing.T) {
+	issues, err := Validate([]byte("name: shop\nservices:\n  api: [\n"))
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Severity != SeverityError || issues[0].Line == 0 {
+		t.Errorf("Validate() = %+v, want one syntax error with its line", issues)
+	}
+}
+
+func TestDisplayPath(t *testing.T) {
+	tests := []struct {
+		location []string
+		want     string
+	}{
+		{nil, "(root)"},
+		{[]string{"services", "api", "ports", "0"}, "services.api.ports[0]"},
+		{[]string{"reqs", "1", "name"}, "reqs[1].name"},
+	}
+	for _, tt := range tests {
+		if got := displayPath(tt.location); got != tt.want {
+			t.Errorf("displayPath(%v) = %q, want %q", tt.location, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package selfupdate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/config"
+	"github.com/jongio/azd-core/fileutil"
+)
+
+// EnvUpdateCheck turns the check for newer releases on or off for the process, overriding
+// the saved setting.
+const EnvUpdateCheck = "AZD_APP_UPDATE_CHECK"
+
+const (
+	// CheckInterval is how long the result of a check is reused before the registry is read again.
+	CheckInterval = 24 * time.Hour
+	// checkTimeout bounds reading the registry in the background.
+	checkTimeout = 3 * time.Second
+	// noticeWait is how long exiting waits for a background check that hasn't finished.
+	noticeWait = 500 * time.Millisecond
+)
+
+// ciEnvVars are set by CI systems, where upgrade hints are noise.
+var ciEnvVars = []string{"CI", "GITHUB_ACTIONS", "TF_BUILD", "GITLAB_CI", "JENKINS_URL", "BUILDKITE", "CODEBUILD_BUILD_ID"}
+
+// CheckStatePath returns the file the last check is cached in, ~/.azd/app/update-check.json.
+// This is a variable to allow test overrides.
+var CheckStatePath = func() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".azd", "app", "update-check.json"), nil
+}
+
+// CheckState is the cached result of the last check.
+type CheckState struct {
+	CheckedAt     time.Time `json:"checkedAt"`
+	LatestVersion string    `json:"latestVersion,omitempty"` // Empty when the registry couldn't be read
+}
+
+// Due reports whether the cached result is older than CheckInterval.
+func (s CheckState) Due(now time.Time) bool {
+	return now.Sub(s.CheckedAt) >= CheckInterval
+}
+
+// LoadCheckState reads the cached result. A missing or unreadable file is a zero state,
+// which is due.
+func LoadCheckState() CheckState {
+	var state CheckState
+	statePath, err := CheckStatePath()
+	if err != nil {
+		return state
+	}
+	// #nosec G304 -- path is the update check cache file
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return state
+	}
+	_ = json.Unmarshal(data, &state)
+	return state
+}
+
+// SaveCheckState caches the result of a check.
+func SaveCheckState(state CheckState) error {
+	statePath, err := CheckStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(statePath), 0750); err != nil {
+		return fmt.Errorf("failed to create update check directory: %w", err)
+	}
+	if err := fileutil.AtomicWriteJSON(statePath, state); err != nil {
+		return fmt.Errorf("failed to write update check cache: %w", err)
+	}
+	return nil
+}
+
+// CheckStatus is whether newer releases are checked for and why.
+type CheckStatus struct {
+	Enabled bool   `json:"enabled"`
+	Saved   bool   `json:"saved"` // The saved setting
+	Reason  string `json:"reason"`
+}
+
+// ResolveCheck determines whether to check for newer releases from the environment and the
+// saved setting.
+func ResolveCheck(saved bool) CheckStatus {
+	status := CheckStatus{Saved: saved}
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvUpdateCheck))) {
+	case "1", "true", "yes", "on":
+		status.Enabled = true
+		status.Reason = EnvUpdateCheck + " turns the check on"
+		return status
+	case "0", "false", "no", "off":
+		status.Reason = EnvUpdateCheck + " turns the check off"
+		return status
+	}
+	for _, name := range ciEnvVars {
+		if os.Getenv(name) != "" {
+			status.Reason = "running in CI (" + name + ")"
+			return status
+		}
+	}
+	status.Enabled = saved
+	if saved {
+		status.Reason = "checked at most once a day"
+	} else {
+		status.Reason = "turned off with 'azd app config updates off'"
+	}
+	return status
+}
+
+// CurrentCheck resolves the check status with the setting saved in ~/.azd/config.json.
+func CurrentCheck() CheckStatus {
+	return ResolveCheck(config.GetUpdateCheckEnabled())
+}
+
+// check is the background check started by StartCheck.
+var check = struct {
+	mu      sync.Mutex
+	current string
+	latest  string
+	done    chan struct{}
+}{}
+
+// StartCheck looks for a newer release than current without blocking. The cached result is
+// used when it is less than CheckInterval old; otherwise the registry is read in the background
+// and the result cached. It does nothing when the check is turned off or current is a dev build.
+func StartCheck(current string) {
+	if IsDevBuild(current) || !CurrentCheck().Enabled {
+		return
+	}
+
+	done := make(chan struct{})
+	check.mu.Lock()
+	check.current, check.latest, check.done = current, "", done
+	check.mu.Unlock()
+
+	state := LoadCheckState()
+	if !state.Due(time.Now()) {
+		setLatest(state.LatestVersion)
+		close(done)
+		return
+	}
+
+	go func() {
+		defer close(done)
+		ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+		defer cancel()
+
+		state := CheckState{CheckedAt: time.Now().UTC()}
+		registry, err := FetchRegistry(ctx)
+		if err == nil {
+			var release *Release
+			if release, err = registry.Latest(false); err == nil {
+				state.LatestVersion = release.Version
+			}
+		}
+		if err != nil {
+			// Cache the failure too, so an offline machine isn't checked on every command
+			slog.Debug("update check failed", "error", err)
+		}
+		setLatest(state.LatestVersion)
+		if err := SaveCheckState(state); err != nil {
+			slog.Debug("failed to cache update check", "error", err)
+		}
+	}()
+}
+
+func setLatest(version string) {
+	check.mu.Lock()
+	defer check.mu.Unlock()
+	check.latest = version
+}
+
+// Notice returns a one-line upgrade hint when the check started by StartCheck found a newer
+// release, or "" otherwise. It waits briefly for a check still in progress.
+func Notice() string {
+	check.mu.Lock()
+	done := check.done
+	check.mu.Unlock()
+	if done == nil {
+		return ""
+	}
+
+	select {
+	case <-done:
+	case <-time.After(noticeWait):
+		return ""
+	}
+
+	check.mu.Lock()
+	defer check.mu.Unlock()
+	return noticeFor(check.current, check.latest)
+}
+
+// noticeFor returns the upgrade hint for latest, or "" when it isn't newer than current.
+func noticeFor(current, latest string) string {
+	if latest == "" || CompareVersions(latest, current) <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("A new version of azd app is available: %s → %s. Run 'azd app upgrade' to install it.", current, latest)
+}
@@ -0,0 +1,149 @@
+package selfupdate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearCheckEnv unsets the variables that turn the update check off.
+func clearCheckEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv(EnvUpdateCheck, "")
+	for _, name := range ciEnvVars {
+		t.Setenv(name, "")
+	}
+}
+
+// withCheckStatePath points CheckStatePath at a temp file.
+func withCheckStatePath(t *testing.T) {
+	t.Helper()
+	statePath := filepath.Join(t.TempDir(), "update-check.json")
+	original := CheckStatePath
+	CheckStatePath = func() (string, error) { return statePath, nil }
+	t.Cleanup(func() { CheckStatePath = original })
+}
+
+func TestResolveCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		saved bool
+		want  bool
+	}{
+		{"default on", nil, true, true},
+		{"turned off", nil, false, false},
+		{"CI", map[string]string{"GITHUB_ACTIONS": "true"}, true, false},
+		{"env off", map[string]string{EnvUpdateCheck: "no"}, true, false},
+		{"env on beats CI", map[string]string{EnvUpdateCheck: "yes", "CI": "1"}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCheckEnv(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			status := ResolveCheck(tt.saved)
+			if status.Enabled != tt.want || status.Reason == "" {
+				t.Errorf("ResolveCheck(%v) = %+v, want enabled %v with a reason", tt.saved, status, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckStateDue(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	if !(CheckState{}).Due(now) {
+		t.Error("a zero state should be due")
+	}
+	if (CheckState{CheckedAt: now.Add(-time.Hour)}).Due(now) {
+		t.Error("a state checked an hour ago should not be due")
+	}
+	if !(CheckState{CheckedAt: now.Add(-CheckInterval)}).Due(now) {
+		t.Error("a state checked a day ago should be due")
+	}
+}
+
+func TestCheckStateRoundTrip(t *testing.T) {
+	withCheckStatePath(t)
+
+	if state := LoadCheckState(); !state.CheckedAt.IsZero() {
+		t.Errorf("LoadCheckState() without a cache = %+v, want zero", state)
+	}
+	want := CheckState{CheckedAt: time.Now().UTC().Truncate(time.Second), LatestVersion: "0.13.5"}
+	if err := SaveCheckState(want); err != nil {
+		t.Fatalf("SaveCheckState() error = %v", err)
+	}
+	if got := LoadCheckState(); !got.CheckedAt.Equal(want.CheckedAt) || got.LatestVersion != want.LatestVersion {
+		t.Errorf("LoadCheckState() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNoticeFor(t *testing.T) {
+	if notice := noticeFor("0.13.4", "0.13.5"); !strings.Contains(notice, "0.13.4 → 0.13.5") || !strings.Contains(notice, "azd app upgrade") {
+		t.Errorf("noticeFor() = %q, want an upgrade hint", notice)
+	}
+	for _, latest := range []string{"", "0.13.4", "0.13.3"} {
+		if notice := noticeFor("0.13.4", latest); notice != "" {
+			t.Errorf("noticeFor(0.13.4, %q) = %q, want no hint", latest, notice)
+		}
+	}
+}
+
+func TestStartCheckUsesCache(t *testing.T) {
+	clearCheckEnv(t)
+	t.Setenv(EnvUpdateCheck, "yes")
+	withCheckStatePath(t)
+	t.Setenv(EnvRegistryURL, "http://127.0.0.1:0/unreachable")
+
+	if err := SaveCheckState(CheckState{CheckedAt: time.Now(), LatestVersion: "0.14.0"}); err != nil {
+		t.Fatal(err)
+	}
+	StartCheck("0.13.5")
+	if notice := Notice(); !strings.Contains(notice, "0.14.0") {
+		t.Errorf("Notice() = %q, want a hint for the cached 0.14.0", notice)
+	}
+}
+
+func TestStartCheckReadsRegistry(t *testing.T) {
+	clearCheckEnv(t)
+	t.Setenv(EnvUpdateCheck, "yes")
+	withCheckStatePath(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(testRegistry())
+	}))
+	defer ts.Close()
+	t.Setenv(EnvRegistryURL, ts.URL)
+
+	StartCheck("0.13.5")
+	if notice := Notice(); !strings.Contains(notice, "0.13.10") {
+		t.Errorf("Notice() = %q, want a hint for 0.13.10", notice)
+	}
+	if state := LoadCheckState(); state.LatestVersion != "0.13.10" {
+		t.Errorf("cached latest version = %q, want 0.13.10", state.LatestVersion)
+	}
+}
+
+func TestStartCheckSkipsDevBuilds(t *testing.T) {
+	clearCheckEnv(t)
+	t.Setenv(EnvUpdateCheck, "yes")
+	withCheckStatePath(t)
+	if err := SaveCheckState(CheckState{CheckedAt: time.Now(), LatestVersion: "0.14.0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	check.mu.Lock()
+	check.done = nil
+	check.mu.Unlock()
+
+	StartCheck("dev")
+	if notice := Notice(); notice != "" {
+		t.Errorf("Notice() for a dev build = %q, want none", notice)
+	}
+}
@@ -0,0 +1,290 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jongio/azd-core/fileutil"
+)
+
+// EnvAzdConfigDir is azd's override of its user config directory (~/.azd by default).
+const EnvAzdConfigDir = "AZD_CONFIG_DIR"
+
+// maxArtifactSize bounds the archive download.
+const maxArtifactSize = 200 << 20
+
+// AzdConfigDir returns azd's user config directory, where azd installs extensions.
+// This is a variable to allow test overrides.
+var AzdConfigDir = func() (string, error) {
+	if dir := os.Getenv(EnvAzdConfigDir); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".azd"), nil
+}
+
+// Installation is where the extension is installed.
+type Installation struct {
+	Path    string `json:"path"`              // Executable to replace
+	Version string `json:"version,omitempty"` // Version azd recorded, empty when not installed by azd
+	ByAzd   bool   `json:"byAzd"`             // Installed with azd extension install
+}
+
+// azdConfigPath returns the path of azd's config.json.
+func azdConfigPath() (string, error) {
+	dir, err := AzdConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// readAzdConfig reads azd's config.json as a generic document so unrelated settings are kept
+// when it is written back. A missing file is an empty document.
+func readAzdConfig() (map[string]any, error) {
+	configPath, err := azdConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G304 -- path is azd's config file
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]any{}, nil
+		}
+		return nil, fmt.Errorf("failed to read azd config: %w", err)
+	}
+	doc := map[string]any{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse azd config: %w", err)
+	}
+	return doc, nil
+}
+
+// installedEntry returns the extension.installed entry azd recorded for the extension.
+func installedEntry(doc map[string]any) map[string]any {
+	extension, _ := doc["extension"].(map[string]any)
+	installed, _ := extension["installed"].(map[string]any)
+	entry, _ := installed[ExtensionID].(map[string]any)
+	return entry
+}
+
+// FindInstallation returns the executable to replace: the one azd installed when azd's config
+// records it, otherwise the running executable.
+func FindInstallation() (Installation, error) {
+	doc, err := readAzdConfig()
+	if err != nil {
+		return Installation{}, err
+	}
+	if entry := installedEntry(doc); entry != nil {
+		if entryPath, _ := entry["path"].(string); entryPath != "" {
+			if !filepath.IsAbs(entryPath) {
+				dir, err := AzdConfigDir()
+				if err != nil {
+					return Installation{}, err
+				}
+				entryPath = filepath.Join(dir, entryPath)
+			}
+			version, _ := entry["version"].(string)
+			return Installation{Path: entryPath, Version: version, ByAzd: true}, nil
+		}
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return Installation{}, fmt.Errorf("failed to locate the azd app executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return Installation{Path: exe}, nil
+}
+
+// RecordVersion updates the version azd recorded for the installed extension, so
+// `azd extension list` shows the upgraded version. It does nothing when azd didn't install it.
+func RecordVersion(version string) error {
+	doc, err := readAzdConfig()
+	if err != nil {
+		return err
+	}
+	entry := installedEntry(doc)
+	if entry == nil {
+		return nil
+	}
+	entry["version"] = version
+
+	configPath, err := azdConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := fileutil.AtomicWriteJSON(configPath, doc); err != nil {
+		return fmt.Errorf("failed to update azd config: %w", err)
+	}
+	return nil
+}
+
+// Download downloads the artifact, verifies its checksum and extracts its executable into dir.
+// It returns the path of the extracted executable.
+func Download(ctx context.Context, artifact Artifact, dir string) (string, error) {
+	newHash, err := checksumHash(artifact.Checksum.Algorithm)
+	if err != nil {
+		return "", err
+	}
+	if artifact.Checksum.Value == "" {
+		return "", fmt.Errorf("artifact %s has no checksum to verify", artifact.URL)
+	}
+
+	archive, err := os.CreateTemp(dir, ".azd-app-download-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create download file: %w", err)
+	}
+	defer func() {
+		_ = archive.Close()
+		_ = os.Remove(archive.Name())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, artifact.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid artifact URL %s: %w", artifact.URL, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", artifact.URL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: %s", artifact.URL, resp.Status)
+	}
+
+	digest := newHash()
+	written, err := io.Copy(io.MultiWriter(archive, digest), io.LimitReader(resp.Body, maxArtifactSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", artifact.URL, err)
+	}
+	if written > maxArtifactSize {
+		return "", fmt.Errorf("artifact %s is larger than %d MB", artifact.URL, maxArtifactSize>>20)
+	}
+	if got := hex.EncodeToString(digest.Sum(nil)); !strings.EqualFold(got, artifact.Checksum.Value) {
+		return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", artifact.URL, artifact.Checksum.Value, got)
+	}
+
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to read download: %w", err)
+	}
+	target := filepath.Join(dir, ".azd-app-"+path.Base(artifact.EntryPoint))
+	if strings.HasSuffix(strings.ToLower(artifact.URL), ".zip") {
+		err = extractZip(archive, written, artifact.EntryPoint, target)
+	} else {
+		err = extractTarGz(archive, artifact.EntryPoint, target)
+	}
+	if err != nil {
+		_ = os.Remove(target)
+		return "", err
+	}
+	return target, nil
+}
+
+// checksumHash returns the hash of a registry checksum algorithm.
+func checksumHash(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToLower(algorithm) {
+	case "sha256":
+		return sha256.New, nil
+	case "sha512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+	}
+}
+
+// extractZip writes the entry point of a zip archive to target.
+func extractZip(archive *os.File, size int64, entryPoint, target string) error {
+	reader, err := zip.NewReader(archive, size)
+	if err != nil {
+		return fmt.Errorf("failed to open zip archive: %w", err)
+	}
+	for _, file := range reader.File {
+		if path.Base(file.Name) != entryPoint {
+			continue
+		}
+		src, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", entryPoint, err)
+		}
+		defer func() { _ = src.Close() }()
+		return writeExecutable(src, target)
+	}
+	return fmt.Errorf("archive does not contain %s", entryPoint)
+}
+
+// extractTarGz writes the entry point of a .tar.gz archive to target.
+func extractTarGz(archive io.Reader, entryPoint, target string) error {
+	gz, err := gzip.NewReader(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open tar.gz archive: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("archive does not contain %s", entryPoint)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar.gz archive: %w", err)
+		}
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == entryPoint {
+			return writeExecutable(reader, target)
+		}
+	}
+}
+
+// writeExecutable writes src to target with executable permissions.
+func writeExecutable(src io.Reader, target string) error {
+	// #nosec G302,G304 -- the extension executable must be executable
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	if _, err := io.Copy(file, io.LimitReader(src, maxArtifactSize)); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return file.Close()
+}
+
+// Replace moves newBinary over target. The current executable is renamed to target.old first,
+// since Windows can't overwrite a running executable, and restored if the move fails.
+// The .old file is removed when possible, or at the next upgrade.
+func Replace(target, newBinary string) error {
+	backup := target + ".old"
+	_ = os.Remove(backup)
+
+	if err := os.Rename(target, backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to move %s aside: %w", target, err)
+	}
+	if err := os.Rename(newBinary, target); err != nil {
+		_ = os.Rename(backup, target)
+		return fmt.Errorf("failed to install %s: %w", target, err)
+	}
+	_ = os.Remove(backup)
+	return nil
+}
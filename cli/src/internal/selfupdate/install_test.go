@@ -0,0 +1,234 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const entryPoint = "jongio-azd-app-test"
+
+var binaryContent = []byte("new azd app binary")
+
+func tarGzArchive(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: entryPoint, Mode: 0755, Size: int64(len(binaryContent)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(binaryContent); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(entryPoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(binaryContent); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownload(t *testing.T) {
+	archives := map[string][]byte{
+		"/app.tar.gz": tarGzArchive(t),
+		"/app.zip":    zipArchive(t),
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archives[r.URL.Path])
+	}))
+	defer ts.Close()
+
+	for name, archive := range archives {
+		t.Run(name, func(t *testing.T) {
+			artifact := Artifact{
+				URL:        ts.URL + name,
+				EntryPoint: entryPoint,
+				Checksum:   Checksum{Algorithm: "sha256", Value: sha256Hex(archive)},
+			}
+			binary, err := Download(context.Background(), artifact, t.TempDir())
+			if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			got, err := os.ReadFile(binary)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, binaryContent) {
+				t.Errorf("extracted %q, want %q", got, binaryContent)
+			}
+		})
+	}
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	archive := tarGzArchive(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	artifact := Artifact{
+		URL:        ts.URL + "/app.tar.gz",
+		EntryPoint: entryPoint,
+		Checksum:   Checksum{Algorithm: "sha256", Value: strings.Repeat("0", 64)},
+	}
+	_, err := Download(context.Background(), artifact, dir)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Download() error = %v, want checksum mismatch", err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("Download() left %d file(s) behind", len(files))
+	}
+}
+
+func TestDownloadMissingEntryPoint(t *testing.T) {
+	archive := zipArchive(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	defer ts.Close()
+
+	artifact := Artifact{
+		URL:        ts.URL + "/app.zip",
+		EntryPoint: "other.exe",
+		Checksum:   Checksum{Algorithm: "sha256", Value: sha256Hex(archive)},
+	}
+	if _, err := Download(context.Background(), artifact, t.TempDir()); err == nil {
+		t.Error("Download() should fail when the archive lacks the entry point")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "app")
+	newBinary := filepath.Join(dir, ".azd-app-new")
+	if err := os.WriteFile(target, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(newBinary, []byte("new"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replace(target, newBinary); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if got, _ := os.ReadFile(target); string(got) != "new" {
+		t.Errorf("target = %q, want new", got)
+	}
+	if _, err := os.Stat(target + ".old"); !os.IsNotExist(err) {
+		t.Error("Replace() should remove the backup")
+	}
+}
+
+// withAzdConfig points AzdConfigDir at a temp directory holding config.
+func withAzdConfig(t *testing.T, config map[string]any) string {
+	t.Helper()
+	dir := t.TempDir()
+	original := AzdConfigDir
+	AzdConfigDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { AzdConfigDir = original })
+
+	if config != nil {
+		data, err := json.Marshal(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestFindInstallationFromAzdConfig(t *testing.T) {
+	dir := withAzdConfig(t, map[string]any{
+		"extension": map[string]any{"installed": map[string]any{
+			ExtensionID: map[string]any{"version": "0.13.4", "path": "extensions/jongio.azd.app/app"},
+		}},
+	})
+
+	installation, err := FindInstallation()
+	if err != nil {
+		t.Fatalf("FindInstallation() error = %v", err)
+	}
+	want := filepath.Join(dir, "extensions", "jongio.azd.app", "app")
+	if installation.Path != want || !installation.ByAzd || installation.Version != "0.13.4" {
+		t.Errorf("FindInstallation() = %+v, want path %s installed by azd at 0.13.4", installation, want)
+	}
+}
+
+func TestFindInstallationFallsBackToExecutable(t *testing.T) {
+	withAzdConfig(t, nil)
+
+	installation, err := FindInstallation()
+	if err != nil {
+		t.Fatalf("FindInstallation() error = %v", err)
+	}
+	if installation.ByAzd || installation.Path == "" {
+		t.Errorf("FindInstallation() = %+v, want the running executable", installation)
+	}
+}
+
+func TestRecordVersion(t *testing.T) {
+	dir := withAzdConfig(t, map[string]any{
+		"defaults": map[string]any{"location": "eastus"},
+		"extension": map[string]any{"installed": map[string]any{
+			ExtensionID: map[string]any{"version": "0.13.4", "path": "extensions/jongio.azd.app/app"},
+		}},
+	})
+
+	if err := RecordVersion("0.13.5"); err != nil {
+		t.Fatalf("RecordVersion() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got := installedEntry(doc)["version"]; got != "0.13.5" {
+		t.Errorf("recorded version = %v, want 0.13.5", got)
+	}
+	if _, ok := doc["defaults"]; !ok {
+		t.Error("RecordVersion() dropped unrelated settings")
+	}
+}
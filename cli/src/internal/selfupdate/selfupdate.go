@@ -0,0 +1,242 @@
+// Package selfupdate finds the latest release of the extension in its azd extension registry,
+// downloads the artifact for the current platform, verifies its checksum and replaces the
+// installed executable.
+//
+// The executable replaced is the one azd installed (recorded in azd's config.json under
+// extension.installed), so `azd extension list` keeps pointing at it; azd's record of the
+// installed version is updated too.
+//
+// StartCheck and Notice tell the user about newer releases: at most once a day the registry is
+// read in the background, and the result is cached in ~/.azd/app/update-check.json.
+package selfupdate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExtensionID is the id of the extension in the registry and in azd's config.
+const ExtensionID = "jongio.azd.app"
+
+// EnvRegistryURL overrides the registry the latest release is read from.
+const EnvRegistryURL = "AZD_APP_REGISTRY_URL"
+
+// RegistryURL is the extension registry published with each release.
+const RegistryURL = "https://raw.githubusercontent.com/jongio/azd-app/main/registry.json"
+
+// registryTimeout bounds reading the registry.
+const registryTimeout = 15 * time.Second
+
+// Registry is an azd extension registry.
+type Registry struct {
+	Extensions []RegistryExtension `json:"extensions"`
+}
+
+// RegistryExtension is an extension and its published versions.
+type RegistryExtension struct {
+	ID       string    `json:"id"`
+	Versions []Release `json:"versions"`
+}
+
+// Release is a published version of the extension.
+type Release struct {
+	Version   string              `json:"version"`
+	Artifacts map[string]Artifact `json:"artifacts"` // Keyed by "os/arch", e.g. "linux/amd64"
+}
+
+// Artifact is the archive of a release for one platform.
+type Artifact struct {
+	URL        string   `json:"url"`
+	EntryPoint string   `json:"entryPoint"` // Executable inside the archive
+	Checksum   Checksum `json:"checksum"`
+}
+
+// Checksum is the expected digest of an artifact archive.
+type Checksum struct {
+	Algorithm string `json:"algorithm"` // sha256 or sha512
+	Value     string `json:"value"`     // Hex encoded
+}
+
+// Platform returns the registry artifact key of the current platform, e.g. "linux/amd64".
+func Platform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// registryURL returns the registry to read, honoring EnvRegistryURL.
+func registryURL() string {
+	if url := os.Getenv(EnvRegistryURL); url != "" {
+		return url
+	}
+	return RegistryURL
+}
+
+// FetchRegistry downloads and parses the extension registry.
+func FetchRegistry(ctx context.Context) (*Registry, error) {
+	ctx, cancel := context.WithTimeout(ctx, registryTimeout)
+	defer cancel()
+
+	url := registryURL()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry URL %s: %w", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read extension registry: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read extension registry %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read extension registry: %w", err)
+	}
+	var registry Registry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("failed to parse extension registry: %w", err)
+	}
+	return &registry, nil
+}
+
+// Latest returns the newest release of the extension. Prereleases are skipped unless
+// includePrerelease is set.
+func (r *Registry) Latest(includePrerelease bool) (*Release, error) {
+	var latest *Release
+	for _, ext := range r.Extensions {
+		if ext.ID != ExtensionID {
+			continue
+		}
+		for i := range ext.Versions {
+			release := &ext.Versions[i]
+			if !includePrerelease && strings.Contains(release.Version, "-") {
+				continue
+			}
+			if latest == nil || CompareVersions(release.Version, latest.Version) > 0 {
+				latest = release
+			}
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("extension %s has no releases in the registry", ExtensionID)
+	}
+	return latest, nil
+}
+
+// Find returns the release with the given version.
+func (r *Registry) Find(version string) (*Release, error) {
+	version = strings.TrimPrefix(version, "v")
+	for _, ext := range r.Extensions {
+		if ext.ID != ExtensionID {
+			continue
+		}
+		for i := range ext.Versions {
+			if ext.Versions[i].Version == version {
+				return &ext.Versions[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("version %s of %s is not in the registry", version, ExtensionID)
+}
+
+// Artifact returns the release's artifact for the current platform.
+func (r *Release) Artifact() (Artifact, error) {
+	artifact, ok := r.Artifacts[Platform()]
+	if !ok {
+		return Artifact{}, fmt.Errorf("version %s has no build for %s", r.Version, Platform())
+	}
+	return artifact, nil
+}
+
+// IsDevBuild reports whether version is a local build without a release version.
+func IsDevBuild(version string) bool {
+	return version == "" || version == "dev" || !strings.ContainsAny(version, "0123456789")
+}
+
+// CompareVersions compares two release versions such as "0.13.5" or "v1.0.0-beta.2",
+// returning -1, 0 or 1. A prerelease is lower than its release.
+func CompareVersions(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+
+	for i := 0; i < len(aCore) || i < len(bCore); i++ {
+		var x, y int
+		if i < len(aCore) {
+			x = aCore[i]
+		}
+		if i < len(bCore) {
+			y = bCore[i]
+		}
+		if x != y {
+			return compareInts(x, y)
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return comparePrerelease(strings.Split(aPre, "."), strings.Split(bPre, "."))
+}
+
+// splitVersion splits a version into its numeric parts and prerelease. Build metadata is dropped.
+func splitVersion(version string) ([]int, string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "+")
+	core, pre, _ := strings.Cut(version, "-")
+
+	var parts []int
+	for _, part := range strings.Split(core, ".") {
+		n, _ := strconv.Atoi(part)
+		parts = append(parts, n)
+	}
+	return parts, pre
+}
+
+// comparePrerelease compares dot-separated prerelease identifiers: numeric identifiers
+// compare numerically and are lower than alphanumeric ones.
+func comparePrerelease(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		x, xErr := strconv.Atoi(a[i])
+		y, yErr := strconv.Atoi(b[i])
+		switch {
+		case xErr == nil && yErr == nil:
+			if x != y {
+				return compareInts(x, y)
+			}
+		case xErr == nil:
+			return -1
+		case yErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(a), len(b))
+}
+
+func compareInts(x, y int) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,128 @@
+package selfupdate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.13.5", "0.13.5", 0},
+		{"0.13.10", "0.13.9", 1},
+		{"v1.0.0", "0.99.0", 1},
+		{"1.2", "1.2.0", 0},
+		{"1.0.0-beta.1", "1.0.0", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.10", -1},
+		{"1.0.0-rc.1", "1.0.0-beta.1", 1},
+		{"1.0.0+build.5", "1.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestIsDevBuild(t *testing.T) {
+	for version, want := range map[string]bool{"dev": true, "": true, "0.13.5": false} {
+		if got := IsDevBuild(version); got != want {
+			t.Errorf("IsDevBuild(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func testRegistry() *Registry {
+	return &Registry{Extensions: []RegistryExtension{
+		{ID: "other.extension", Versions: []Release{{Version: "9.0.0"}}},
+		{ID: ExtensionID, Versions: []Release{
+			{Version: "0.13.4"},
+			{Version: "0.13.10"},
+			{Version: "0.14.0-beta.1"},
+			{Version: "0.13.9"},
+		}},
+	}}
+}
+
+func TestRegistryLatest(t *testing.T) {
+	registry := testRegistry()
+
+	latest, err := registry.Latest(false)
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if latest.Version != "0.13.10" {
+		t.Errorf("Latest(false) = %s, want 0.13.10", latest.Version)
+	}
+
+	latest, err = registry.Latest(true)
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if latest.Version != "0.14.0-beta.1" {
+		t.Errorf("Latest(true) = %s, want 0.14.0-beta.1", latest.Version)
+	}
+
+	if _, err := (&Registry{}).Latest(false); err == nil {
+		t.Error("Latest() of an empty registry should fail")
+	}
+}
+
+func TestRegistryFind(t *testing.T) {
+	registry := testRegistry()
+
+	release, err := registry.Find("v0.13.4")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if release.Version != "0.13.4" {
+		t.Errorf("Find() = %s, want 0.13.4", release.Version)
+	}
+	if _, err := registry.Find("9.0.0"); err == nil {
+		t.Error("Find() should not return another extension's version")
+	}
+}
+
+func TestReleaseArtifact(t *testing.T) {
+	release := &Release{Version: "1.0.0", Artifacts: map[string]Artifact{Platform(): {URL: "https://example.com/app.zip"}}}
+	if _, err := release.Artifact(); err != nil {
+		t.Errorf("Artifact() error = %v", err)
+	}
+
+	release.Artifacts = map[string]Artifact{"plan9/mips": {}}
+	if _, err := release.Artifact(); err == nil {
+		t.Error("Artifact() should fail when there is no build for the platform")
+	}
+}
+
+func TestFetchRegistry(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(testRegistry())
+	}))
+	defer ts.Close()
+	t.Setenv(EnvRegistryURL, ts.URL)
+
+	registry, err := FetchRegistry(context.Background())
+	if err != nil {
+		t.Fatalf("FetchRegistry() error = %v", err)
+	}
+	if len(registry.Extensions) != 2 {
+		t.Errorf("FetchRegistry() returned %d extensions, want 2", len(registry.Extensions))
+	}
+}
+
+func TestFetchRegistryHTTPError(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+	t.Setenv(EnvRegistryURL, ts.URL)
+
+	if _, err := FetchRegistry(context.Background()); err == nil {
+		t.Error("FetchRegistry() should fail on a 404")
+	}
+}
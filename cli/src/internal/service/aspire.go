@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-core/registry"
+	"github.com/jongio/azd-core/security"
+)
+
+const (
+	// aspireManifestFile is where the manifest of an AppHost is written, in its obj directory
+	// so the relative project paths in it resolve against the AppHost project.
+	aspireManifestFile = "azd-app-manifest.json"
+
+	// aspireManifestTimeout bounds how long the AppHost may take to write its manifest.
+	aspireManifestTimeout = 2 * time.Minute
+)
+
+// aspireManifest is the manifest an Aspire AppHost writes with --publisher manifest.
+type aspireManifest struct {
+	Resources map[string]aspireResource `json:"resources"`
+}
+
+// aspireResource is a resource of an Aspire app model, e.g. a project or a container.
+type aspireResource struct {
+	Type     string                   `json:"type"`  // e.g. "project.v0", "container.v0", "parameter.v0"
+	Path     string                   `json:"path"`  // Project file of project resources, relative to the manifest
+	Image    string                   `json:"image"` // Image of container resources
+	Bindings map[string]aspireBinding `json:"bindings"`
+}
+
+// aspireBinding is an endpoint of an Aspire resource.
+type aspireBinding struct {
+	Scheme     string `json:"scheme"`
+	Port       int    `json:"port"`       // Host port, when the app model fixes it
+	TargetPort int    `json:"targetPort"` // Port the resource listens on
+}
+
+// aspireMember is a resource of an Aspire AppHost with its endpoint on the host, if known.
+type aspireMember struct {
+	name     string // Registry name, e.g. "apphost-api"
+	resource string // Resource kind: project, container or executable
+	port     int
+	scheme   string
+}
+
+// IsAspireAppHost reports whether a runtime runs an Aspire AppHost.
+func (rt *ServiceRuntime) IsAspireAppHost() bool {
+	return rt.Framework == FrameworkAspire.Name
+}
+
+// AspireProjectFile returns the project file of an AppHost in dir, or "" if it has none.
+func AspireProjectFile(dir string) string {
+	csprojFiles, _ := filepath.Glob(filepath.Join(dir, "*.csproj"))
+	if len(csprojFiles) == 0 {
+		return ""
+	}
+	return csprojFiles[0]
+}
+
+// DiscoverAspireResources reads the app model of an Aspire AppHost from its manifest and
+// registers its projects, containers and executables as "<apphost>-<resource>", so they show
+// up in the dashboard and in `azd app status` next to the AppHost. The AppHost must have been
+// built. The entries are replaced on each discovery and removed by ForgetAspireResources.
+func DiscoverAspireResources(ctx context.Context, projectDir, appHost, projectFile string) error {
+	manifestPath, err := generateAspireManifest(ctx, projectFile)
+	if err != nil {
+		return err
+	}
+
+	// #nosec G304 -- manifestPath is in the obj directory of the AppHost project
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read Aspire manifest: %w", err)
+	}
+	manifest, err := parseAspireManifest(data)
+	if err != nil {
+		return err
+	}
+
+	members := aspireMembers(appHost, manifest, filepath.Dir(manifestPath))
+	registerAspireMembers(projectDir, appHost, members)
+	slog.Debug("discovered Aspire resources", slog.String("apphost", appHost), slog.Int("resources", len(members)))
+	return nil
+}
+
+// ForgetAspireResources removes the registry entries of the resources of an Aspire AppHost.
+func ForgetAspireResources(appHost string) {
+	unregisterMembers(appHost)
+}
+
+// generateAspireManifest runs an AppHost with the manifest publisher, which writes its app
+// model and exits, and returns the path of the manifest. The AppHost isn't rebuilt, so this
+// doesn't interfere with a running instance.
+func generateAspireManifest(ctx context.Context, projectFile string) (string, error) {
+	if err := security.ValidatePath(projectFile); err != nil {
+		return "", fmt.Errorf("invalid AppHost project: %w", err)
+	}
+	projectDir := filepath.Dir(projectFile)
+	manifestPath := filepath.Join(projectDir, "obj", aspireManifestFile)
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0750); err != nil {
+		return "", fmt.Errorf("failed to create obj directory: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, aspireManifestTimeout)
+	defer cancel()
+
+	args := []string{"run", "--project", projectFile, "--no-build", "--", "--publisher", "manifest", "--output-path", manifestPath}
+	// #nosec G204 -- the project file is the AppHost found in the project directory
+	cmd := exec.CommandContext(ctx, langDotnet, args...)
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate Aspire manifest: %s: %w", lastOutputLines(string(output), 5), err)
+	}
+	return manifestPath, nil
+}
+
+// lastOutputLines returns the last n non-empty lines of command output.
+func lastOutputLines(output string, n int) string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "; ")
+}
+
+// parseAspireManifest parses the manifest of an Aspire AppHost.
+func parseAspireManifest(data []byte) (*aspireManifest, error) {
+	var manifest aspireManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse Aspire manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// aspireResourceKind returns the kind of a resource type that runs locally (project, container
+// or executable), or "" for parameters, connection strings, values and Azure resources.
+func aspireResourceKind(resourceType string) string {
+	kind, _, _ := strings.Cut(resourceType, ".")
+	switch kind {
+	case "project", "container", "executable":
+		return kind
+	case "dockerfile":
+		return "container"
+	default:
+		return ""
+	}
+}
+
+// aspireMembers returns the resources of an AppHost that run locally, ordered by name. The
+// endpoint of a resource is its HTTP endpoint, else its first endpoint with a known host port:
+// one the app model fixes or, for projects, the one in their launch profile.
+func aspireMembers(appHost string, manifest *aspireManifest, manifestDir string) []aspireMember {
+	names := make([]string, 0, len(manifest.Resources))
+	for name := range manifest.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var members []aspireMember
+	for _, name := range names {
+		resource := manifest.Resources[name]
+		kind := aspireResourceKind(resource.Type)
+		if kind == "" {
+			continue
+		}
+		member := aspireMember{name: appHost + "-" + name, resource: kind}
+
+		var launchPorts map[string]int
+		if kind == "project" && resource.Path != "" {
+			launchPorts = launchProfilePorts(filepath.Join(manifestDir, resource.Path))
+		}
+		for _, bindingName := range sortedBindingNames(resource.Bindings) {
+			binding := resource.Bindings[bindingName]
+			port := binding.Port
+			if port == 0 {
+				port = launchPorts[binding.Scheme]
+			}
+			if port == 0 {
+				continue
+			}
+			// HTTP endpoints are preferred: they get a URL and an HTTP health probe
+			if member.port == 0 || (member.scheme != ServiceTypeHTTP && binding.Scheme == ServiceTypeHTTP) {
+				member.port = port
+				member.scheme = binding.Scheme
+			}
+		}
+		members = append(members, member)
+	}
+	return members
+}
+
+// sortedBindingNames returns the names of the endpoints of a resource in order.
+func sortedBindingNames(bindings map[string]aspireBinding) []string {
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// launchProfilePorts returns the ports of the first launch profile of a project that sets
+// applicationUrl, keyed by scheme. Aspire serves the endpoints of projects on these ports.
+func launchProfilePorts(projectFile string) map[string]int {
+	// #nosec G304 -- launchSettings.json of a project referenced by the AppHost
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(projectFile), "Properties", "launchSettings.json"))
+	if err != nil {
+		return nil
+	}
+	var settings struct {
+		Profiles map[string]struct {
+			CommandName    string `json:"commandName"`
+			ApplicationURL string `json:"applicationUrl"`
+		} `json:"profiles"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		slog.Debug("failed to parse launchSettings.json", "project", projectFile, "error", err)
+		return nil
+	}
+
+	names := make([]string, 0, len(settings.Profiles))
+	for name := range settings.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		profile := settings.Profiles[name]
+		if profile.CommandName != "Project" || profile.ApplicationURL == "" {
+			continue
+		}
+		ports := make(map[string]int)
+		for _, rawURL := range strings.Split(profile.ApplicationURL, ";") {
+			u, err := url.Parse(strings.TrimSpace(rawURL))
+			if err != nil {
+				continue
+			}
+			if port, err := strconv.Atoi(u.Port()); err == nil && ports[u.Scheme] == 0 {
+				ports[u.Scheme] = port
+			}
+		}
+		return ports
+	}
+	return nil
+}
+
+// registerAspireMembers registers the resources of an Aspire AppHost.
+func registerAspireMembers(projectDir, appHost string, members []aspireMember) {
+	entries := make([]*registry.ServiceRegistryEntry, 0, len(members))
+	for _, member := range members {
+		entry := &registry.ServiceRegistryEntry{
+			Name:       member.name,
+			ProjectDir: projectDir,
+			Port:       member.port,
+			Framework:  FrameworkAspire.Name,
+			Status:     constants.StatusRunning,
+			StartTime:  time.Now(),
+		}
+		switch member.resource {
+		case "project":
+			entry.Language = langNameDotNet
+		case "container":
+			entry.Language = "container"
+		}
+		switch member.scheme {
+		case ServiceTypeHTTP, ProtocolHTTPS:
+			entry.Type = ServiceTypeHTTP
+			entry.URL = fmt.Sprintf("%s://localhost:%d", member.scheme, member.port)
+		case "":
+			// No known endpoint; health is unknown rather than checked
+		default:
+			entry.Type = ServiceTypeTCP
+		}
+		entries = append(entries, entry)
+	}
+	registerMembers(projectDir, appHost, entries)
+}
+
+// watchAspireResources discovers the resources of an AppHost started by azd app run once it
+// is ready, and removes them when it exits.
+func watchAspireResources(ctx context.Context, process *ServiceProcess, projectDir string) {
+	projectFile := AspireProjectFile(process.Runtime.WorkingDir)
+	if projectFile == "" || process.Process == nil {
+		return
+	}
+
+	// The AppHost is built once it's ready; probe a copy, orchestration adjusts the original's timeout
+	probe := *process
+	if err := PerformHealthCheck(&probe); err != nil {
+		slog.Debug("Aspire AppHost not ready, skipping resource discovery", "service", process.Name, "error", err)
+		return
+	}
+	if err := DiscoverAspireResources(ctx, projectDir, process.Name, projectFile); err != nil {
+		slog.Warn("failed to discover Aspire resources", slog.String("service", process.Name), slog.String("error", err.Error()))
+		return
+	}
+
+	_, _ = process.Wait()
+	ForgetAspireResources(process.Name)
+}
+
+// IsAspireResource reports whether a registry entry is a resource of an Aspire AppHost, which
+// is started and stopped with its AppHost rather than on its own.
+func IsAspireResource(entry *registry.ServiceRegistryEntry) bool {
+	return entry.Framework == FrameworkAspire.Name && MemberOwnerOf(entry.Name) != entry.Name
+}
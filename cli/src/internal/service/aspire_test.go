@@ -0,0 +1,145 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jongio/azd-core/registry"
+)
+
+const testAspireManifest = `{
+  "$schema": "https://json.schemastore.org/aspire-8.0.json",
+  "resources": {
+    "cache": {
+      "type": "container.v0",
+      "image": "docker.io/library/redis:7.4",
+      "bindings": {
+        "tcp": {"scheme": "tcp", "protocol": "tcp", "transport": "tcp", "port": 6380, "targetPort": 6379}
+      }
+    },
+    "api": {
+      "type": "project.v0",
+      "path": "../../Shop.Api/Shop.Api.csproj",
+      "bindings": {
+        "http": {"scheme": "http", "protocol": "tcp", "transport": "http"},
+        "https": {"scheme": "https", "protocol": "tcp", "transport": "http"}
+      }
+    },
+    "worker": {
+      "type": "project.v0",
+      "path": "../../Shop.Worker/Shop.Worker.csproj"
+    },
+    "password": {
+      "type": "parameter.v0",
+      "value": "{password.inputs.value}"
+    },
+    "db": {
+      "type": "value.v0",
+      "connectionString": "Host=localhost"
+    }
+  }
+}`
+
+func TestAspireMembers(t *testing.T) {
+	dir := t.TempDir()
+	manifestDir := filepath.Join(dir, "Shop.AppHost", "obj")
+	apiProperties := filepath.Join(dir, "Shop.Api", "Properties")
+	for _, d := range []string{manifestDir, apiProperties} {
+		if err := os.MkdirAll(d, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	launchSettings := `{"profiles": {
+  "https": {"commandName": "Project", "applicationUrl": "https://localhost:7001;http://localhost:5001"},
+  "IIS Express": {"commandName": "IISExpress"}
+}}`
+	if err := os.WriteFile(filepath.Join(apiProperties, "launchSettings.json"), []byte(launchSettings), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := parseAspireManifest([]byte(testAspireManifest))
+	if err != nil {
+		t.Fatalf("parseAspireManifest() error = %v", err)
+	}
+
+	members := aspireMembers("apphost", manifest, manifestDir)
+	want := []aspireMember{
+		{name: "apphost-api", resource: "project", port: 5001, scheme: "http"},
+		{name: "apphost-cache", resource: "container", port: 6380, scheme: "tcp"},
+		{name: "apphost-worker", resource: "project"},
+	}
+	if len(members) != len(want) {
+		t.Fatalf("aspireMembers() = %+v, want %+v", members, want)
+	}
+	for i := range want {
+		if members[i] != want[i] {
+			t.Errorf("aspireMembers()[%d] = %+v, want %+v", i, members[i], want[i])
+		}
+	}
+}
+
+func TestParseAspireManifest_Invalid(t *testing.T) {
+	if _, err := parseAspireManifest([]byte("Building...")); err == nil {
+		t.Error("parseAspireManifest() should fail for output that isn't a manifest")
+	}
+}
+
+func TestAspireResourceKind(t *testing.T) {
+	tests := map[string]string{
+		"project.v0":       "project",
+		"project.v1":       "project",
+		"container.v1":     "container",
+		"dockerfile.v0":    "container",
+		"executable.v0":    "executable",
+		"parameter.v0":     "",
+		"value.v0":         "",
+		"azure.bicep.v0":   "",
+		"azure.storage.v0": "",
+	}
+	for resourceType, want := range tests {
+		if got := aspireResourceKind(resourceType); got != want {
+			t.Errorf("aspireResourceKind(%q) = %q, want %q", resourceType, got, want)
+		}
+	}
+}
+
+func TestRegisterAspireMembers(t *testing.T) {
+	dir := t.TempDir()
+	reg := registry.GetRegistry(dir)
+
+	registerAspireMembers(dir, "apphost", []aspireMember{
+		{name: "apphost-api", resource: "project", port: 5001, scheme: "http"},
+		{name: "apphost-cache", resource: "container", port: 6380, scheme: "tcp"},
+		{name: "apphost-worker", resource: "project"},
+	})
+	defer ForgetAspireResources("apphost")
+
+	api, ok := reg.GetService("apphost-api")
+	if !ok {
+		t.Fatal("project resource should be registered")
+	}
+	if api.URL != "http://localhost:5001" || api.Type != ServiceTypeHTTP || api.Language != langNameDotNet || api.Framework != FrameworkAspire.Name {
+		t.Errorf("api entry = %+v, want .NET HTTP entry on port 5001", api)
+	}
+	cache, _ := reg.GetService("apphost-cache")
+	if cache == nil || cache.URL != "" || cache.Type != ServiceTypeTCP || cache.Port != 6380 {
+		t.Errorf("cache entry = %+v, want TCP entry on port 6380 without URL", cache)
+	}
+	worker, _ := reg.GetService("apphost-worker")
+	if worker == nil || worker.Port != 0 || worker.Type != "" {
+		t.Errorf("worker entry = %+v, want entry without endpoint", worker)
+	}
+
+	if !IsAspireResource(api) {
+		t.Error("IsAspireResource() = false for a resource of the AppHost")
+	}
+	if IsAspireResource(&registry.ServiceRegistryEntry{Name: "apphost", Framework: FrameworkAspire.Name}) {
+		t.Error("IsAspireResource() = true for the AppHost itself")
+	}
+
+	ForgetAspireResources("apphost")
+	if _, ok := reg.GetService("apphost-api"); ok {
+		t.Error("resources should be unregistered")
+	}
+}
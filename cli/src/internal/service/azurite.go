@@ -0,0 +1,196 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/docker"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+)
+
+const (
+	// frameworkAzurite identifies the runtime of a type: azurite service.
+	frameworkAzurite = "Azurite"
+
+	// azuriteImage is run with Docker when npx isn't available.
+	azuriteImage = "mcr.microsoft.com/azure-storage/azurite:latest"
+
+	// Default ports of the Blob, Queue and Table endpoints. The Queue and Table endpoints
+	// always follow the Blob endpoint, so a moved Blob port moves them too.
+	azuriteBlobPort  = 10000
+	azuriteQueuePort = azuriteBlobPort + 1
+	azuriteTablePort = azuriteBlobPort + 2
+
+	// azuriteAccountName and azuriteAccountKey are Azurite's well-known development account.
+	azuriteAccountName = "devstoreaccount1"
+	azuriteAccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw==" // #nosec G101 -- public development key of the storage emulator
+
+	// maxAzuritePortAttempts bounds how often the block of three ports is moved.
+	maxAzuritePortAttempts = 10
+
+	// EnvAzureStorageConnectionString is set for services that use an Azurite service.
+	EnvAzureStorageConnectionString = "AZURE_STORAGE_CONNECTION_STRING"
+
+	// envAzureWebJobsStorage is the storage account setting of Azure Functions.
+	envAzureWebJobsStorage = "AzureWebJobsStorage"
+)
+
+// azuriteLookPath finds the tools Azurite can be started with. This is a variable to allow test overrides.
+var azuriteLookPath = exec.LookPath
+
+// dockerAvailable reports whether Docker is installed and running. This is a variable to allow test overrides.
+var dockerAvailable = func() bool {
+	return docker.NewClient().IsAvailable()
+}
+
+// AzuriteConnectionString returns the connection string of an Azurite instance whose Blob endpoint
+// listens on blobPort, with its Queue and Table endpoints on the next two ports.
+func AzuriteConnectionString(blobPort int) string {
+	return fmt.Sprintf("DefaultEndpointsProtocol=http;AccountName=%[1]s;AccountKey=%[2]s;"+
+		"BlobEndpoint=http://127.0.0.1:%[3]d/%[1]s;QueueEndpoint=http://127.0.0.1:%[4]d/%[1]s;TableEndpoint=http://127.0.0.1:%[5]d/%[1]s;",
+		azuriteAccountName, azuriteAccountKey, blobPort, blobPort+1, blobPort+2)
+}
+
+// azuriteConnection returns how services connect to an Azurite instance whose Blob endpoint listens on blobPort.
+func azuriteConnection(blobPort int) *ServiceConnection {
+	connectionString := AzuriteConnectionString(blobPort)
+	return &ServiceConnection{String: connectionString, Env: map[string]string{
+		EnvAzureStorageConnectionString: connectionString,
+		envAzureWebJobsStorage:          connectionString,
+	}}
+}
+
+// detectAzuriteRuntime creates a ServiceRuntime for a type: azurite service: the Azure Storage
+// emulator, started with npx or, without Node.js or when the service sets an image, with Docker.
+// Its port is the Blob endpoint, which is also health-checked.
+func detectAzuriteRuntime(serviceName string, service Service, usedPorts map[int]bool, azureYamlDir string) (*ServiceRuntime, error) {
+	preferredPort := azuriteBlobPort
+	isExplicit := false
+	if hostPort, _, explicit := service.GetPrimaryPort(); hostPort > 0 {
+		preferredPort = hostPort
+		isExplicit = explicit
+	}
+	port, shouldUpdate, err := assignAzuritePorts(serviceName, service, usedPorts, azureYamlDir, preferredPort, isExplicit)
+	if err != nil {
+		return nil, err
+	}
+	for p := port; p <= port+2; p++ {
+		usedPorts[p] = true
+	}
+
+	healthCheckType := ServiceTypeTCP
+	if service.IsHealthcheckDisabled() {
+		healthCheckType = watchModeNone
+	}
+	runtime := &ServiceRuntime{
+		Name:                  serviceName,
+		Framework:             frameworkAzurite,
+		WorkingDir:            azureYamlDir,
+		Port:                  port,
+		Protocol:              "tcp",
+		Env:                   make(map[string]string),
+		ShouldUpdateAzureYaml: shouldUpdate,
+		Connection:            azuriteConnection(port),
+		HealthCheck: HealthCheckConfig{
+			Type:     healthCheckType,
+			Port:     port,
+			Timeout:  60 * time.Second,
+			Interval: 2 * time.Second,
+		},
+	}
+
+	image := service.GetContainerImage()
+	_, npxErr := azuriteLookPath("npx")
+	switch {
+	case image == "" && npxErr == nil:
+		// Data is kept with the project's other local state, across runs
+		runtime.Type = ServiceTypeTCP
+		runtime.Language = "JavaScript"
+		runtime.PackageManager = "npx"
+		runtime.Command = "npx"
+		runtime.ExtraPorts = []PortMapping{{HostPort: port + 1}, {HostPort: port + 2}}
+		runtime.Args = []string{
+			"--yes", "azurite",
+			"--silent",
+			"--skipApiVersionCheck",
+			"--location", filepath.Join(azureYamlDir, ".azure", "azurite"),
+			"--blobHost", "127.0.0.1", "--blobPort", strconv.Itoa(port),
+			"--queueHost", "127.0.0.1", "--queuePort", strconv.Itoa(port + 1),
+			"--tableHost", "127.0.0.1", "--tablePort", strconv.Itoa(port + 2),
+		}
+	case image != "" || dockerAvailable():
+		if image == "" {
+			image = azuriteImage
+		}
+		runtime.Type = ServiceTypeContainer
+		runtime.Language = "container"
+		runtime.PackageManager = packageMgrDocker
+		runtime.Command = image
+		runtime.ContainerPort = azuriteBlobPort
+		runtime.ExtraPorts = []PortMapping{
+			{HostPort: port + 1, ContainerPort: azuriteQueuePort},
+			{HostPort: port + 2, ContainerPort: azuriteTablePort},
+		}
+	default:
+		return nil, fmt.Errorf("service %s: azurite needs Node.js (npx) or a running Docker to start", serviceName)
+	}
+
+	applyHealthcheck(&runtime.HealthCheck, service.Healthcheck)
+	return runtime, nil
+}
+
+// assignAzuritePorts assigns the Blob port of an Azurite service and claims the Queue and Table
+// ports that follow it. When either is taken, the whole block of three moves, unless the port
+// is explicit.
+func assignAzuritePorts(serviceName string, service Service, usedPorts map[int]bool, azureYamlDir string, preferredPort int, isExplicit bool) (int, bool, error) {
+	portMgr := portmanager.GetPortManager(azureYamlDir)
+	for attempt := 0; attempt < maxAzuritePortAttempts; attempt++ {
+		for !isExplicit && preferredPort+2 <= portmanager.PortRangeEnd &&
+			(usedPorts[preferredPort] || usedPorts[preferredPort+1] || usedPorts[preferredPort+2] ||
+				!portMgr.IsPortAvailable(preferredPort+1) || !portMgr.IsPortAvailable(preferredPort+2)) {
+			preferredPort += 3
+		}
+
+		port, shouldUpdate, err := assignServicePort(azureYamlDir, serviceName, service, preferredPort, isExplicit)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to assign port for azurite: %w", err)
+		}
+		err = claimAzuritePorts(portMgr, serviceName, port)
+		if err == nil {
+			return port, shouldUpdate, nil
+		}
+		if isExplicit {
+			return 0, false, fmt.Errorf("azurite service %s uses ports %d-%d for its Blob, Queue and Table endpoints: %w", serviceName, port, port+2, err)
+		}
+
+		// The Queue or Table port is taken, e.g. after the Blob port assigned in a previous run; move the whole block
+		slog.Debug("azurite queue or table port is taken, moving its ports", "service", serviceName, "port", port, "error", err)
+		portmanager.ReleaseHeldPort(port)
+		if err := portMgr.ReleasePort(serviceName); err != nil {
+			return 0, false, fmt.Errorf("failed to release port for azurite: %w", err)
+		}
+		preferredPort = port + 3
+	}
+	return 0, false, fmt.Errorf("failed to find 3 free consecutive ports for azurite service %s", serviceName)
+}
+
+// claimAzuritePorts claims the Queue and Table ports following blobPort for the service.
+// If either can't be claimed, neither stays claimed.
+func claimAzuritePorts(portMgr *portmanager.PortManager, serviceName string, blobPort int) error {
+	for port := blobPort + 1; port <= blobPort+2; port++ {
+		if port > portmanager.PortRangeEnd {
+			return fmt.Errorf("port %d is out of range", port)
+		}
+		if err := portMgr.ClaimPort(serviceName, port); err != nil {
+			for claimed := blobPort + 1; claimed < port; claimed++ {
+				portmanager.ReleaseHeldPort(claimed)
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+)
+
+func TestAzuriteConnectionString(t *testing.T) {
+	got := AzuriteConnectionString(10010)
+	for _, want := range []string{
+		"AccountName=devstoreaccount1;",
+		"BlobEndpoint=http://127.0.0.1:10010/devstoreaccount1;",
+		"QueueEndpoint=http://127.0.0.1:10011/devstoreaccount1;",
+		"TableEndpoint=http://127.0.0.1:10012/devstoreaccount1;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("AzuriteConnectionString(10010) = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestDetectAzuriteRuntime(t *testing.T) {
+	tests := []struct {
+		name        string
+		service     Service
+		npx         bool
+		docker      bool
+		wantType    string
+		wantCommand string
+		wantPort    int
+		wantCheck   string
+		wantErr     bool
+	}{
+		{name: "npx", service: Service{Type: ServiceTypeAzurite}, npx: true, docker: true, wantType: ServiceTypeTCP, wantCommand: "npx", wantPort: 10000, wantCheck: "tcp"},
+		{name: "docker without npx", service: Service{Type: ServiceTypeAzurite}, docker: true, wantType: ServiceTypeContainer, wantCommand: azuriteImage, wantPort: 10000, wantCheck: "tcp"},
+		{name: "image runs with docker", service: Service{Type: ServiceTypeAzurite, Image: "mcr.microsoft.com/azure-storage/azurite:3.31.0"}, npx: true, wantType: ServiceTypeContainer, wantCommand: "mcr.microsoft.com/azure-storage/azurite:3.31.0", wantPort: 10000, wantCheck: "tcp"},
+		{name: "explicit port", service: Service{Type: ServiceTypeAzurite, Ports: []string{"10100"}}, npx: true, wantType: ServiceTypeTCP, wantCommand: "npx", wantPort: 10100, wantCheck: "tcp"},
+		{name: "healthcheck disabled", service: Service{Type: ServiceTypeAzurite, Healthcheck: &HealthcheckConfig{Disable: true}}, npx: true, wantType: ServiceTypeTCP, wantCommand: "npx", wantPort: 10000, wantCheck: "none"},
+		{name: "neither npx nor docker", service: Service{Type: ServiceTypeAzurite}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origLookPath, origDocker := azuriteLookPath, dockerAvailable
+			defer func() { azuriteLookPath, dockerAvailable = origLookPath, origDocker }()
+			azuriteLookPath = func(file string) (string, error) {
+				if tt.npx {
+					return "/usr/bin/" + file, nil
+				}
+				return "", errors.New("not found")
+			}
+			dockerAvailable = func() bool { return tt.docker }
+
+			dir := t.TempDir()
+			rt, err := DetectServiceRuntime("storage", tt.service, map[int]bool{}, dir, "")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("DetectServiceRuntime() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DetectServiceRuntime() error = %v", err)
+			}
+			if rt.Type != tt.wantType || rt.Command != tt.wantCommand {
+				t.Errorf("runtime = %s %q, want %s %q", rt.Type, rt.Command, tt.wantType, tt.wantCommand)
+			}
+			if rt.Port != tt.wantPort || rt.Framework != frameworkAzurite {
+				t.Errorf("runtime port = %d, framework = %q, want %d, %q", rt.Port, rt.Framework, tt.wantPort, frameworkAzurite)
+			}
+			if rt.HealthCheck.Type != tt.wantCheck {
+				t.Errorf("HealthCheck.Type = %q, want %q", rt.HealthCheck.Type, tt.wantCheck)
+			}
+
+			if rt.Type == ServiceTypeContainer {
+				mappings := buildContainerPortMappings(rt)
+				if len(mappings) != 3 || mappings[0].ContainerPort != 10000 || mappings[2].HostPort != tt.wantPort+2 || mappings[2].ContainerPort != 10002 {
+					t.Errorf("container port mappings = %+v, want the blob, queue and table ports", mappings)
+				}
+			} else {
+				args := strings.Join(rt.Args, " ")
+				for _, want := range []string{"azurite", "--blobPort " + strconv.Itoa(tt.wantPort), "--queuePort " + strconv.Itoa(tt.wantPort+1), "--tablePort " + strconv.Itoa(tt.wantPort+2)} {
+					if !strings.Contains(args, want) {
+						t.Errorf("args = %q, want %q", args, want)
+					}
+				}
+				if !slices.Contains(rt.Args, "--location") {
+					t.Errorf("args = %q, want a data location", args)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectAzuriteRuntime_QueueOrTablePortTaken(t *testing.T) {
+	origLookPath := azuriteLookPath
+	defer func() { azuriteLookPath = origLookPath }()
+	azuriteLookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+
+	// The Queue port of the default block is taken
+	cleanup := portmanager.SetTestModeForTesting(func(port int) bool { return port != 10001 })
+	defer cleanup()
+
+	rt, err := DetectServiceRuntime("storage", Service{Type: ServiceTypeAzurite}, map[int]bool{}, t.TempDir(), "")
+	if err != nil {
+		t.Fatalf("DetectServiceRuntime() error = %v", err)
+	}
+	if rt.Port != 10003 {
+		t.Errorf("Port = %d, want the whole block moved to 10003", rt.Port)
+	}
+	args := strings.Join(rt.Args, " ")
+	if !strings.Contains(args, "--queuePort 10004") || !strings.Contains(args, "--tablePort 10005") {
+		t.Errorf("args = %q, want the queue and table ports after 10003", args)
+	}
+
+	// An explicit port isn't moved
+	_, err = DetectServiceRuntime("storage", Service{Type: ServiceTypeAzurite, Ports: []string{"10000"}}, map[int]bool{}, t.TempDir(), "")
+	if err == nil || !strings.Contains(err.Error(), "10001") {
+		t.Errorf("DetectServiceRuntime() with an explicit port error = %v, want the taken queue port", err)
+	}
+}
+
+func TestDetectAzuriteRuntime_MovesAssignedBlockWhenTableTaken(t *testing.T) {
+	origLookPath := azuriteLookPath
+	defer func() { azuriteLookPath = origLookPath }()
+	azuriteLookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+
+	// The Blob port was assigned in a previous run; its Table port has been taken since
+	dir := t.TempDir()
+	if _, _, err := portmanager.GetPortManager(dir).AssignPort("storage", 10000, false); err != nil {
+		t.Fatalf("AssignPort() error = %v", err)
+	}
+	cleanup := portmanager.SetTestModeForTesting(func(port int) bool { return port != 10002 })
+	defer cleanup()
+	portmanager.ClearCacheForTesting()
+
+	rt, err := DetectServiceRuntime("storage", Service{Type: ServiceTypeAzurite}, map[int]bool{}, dir, "")
+	if err != nil {
+		t.Fatalf("DetectServiceRuntime() error = %v", err)
+	}
+	if rt.Port != 10003 {
+		t.Errorf("Port = %d, want the whole block moved to 10003", rt.Port)
+	}
+	if port, _ := portmanager.GetPortManager(dir).GetAssignment("storage"); port != 10003 {
+		t.Errorf("assignment = %d, want 10003", port)
+	}
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+	"github.com/jongio/azd-app/cli/src/internal/docker"
+	"github.com/jongio/azd-core/registry"
+	"github.com/jongio/azd-core/security"
+)
+
+// frameworkCompose identifies the runtime of a compose service.
+const frameworkCompose = "Docker Compose"
+
+// composeMember is a compose service of a compose project that publishes a port.
+type composeMember struct {
+	name string // Registry name, e.g. "stack-db"
+	port int    // First published host port
+}
+
+// ComposeProjectName returns the name of the compose project of a compose service, e.g. "azd-stack".
+func ComposeProjectName(serviceName string) string {
+	return "azd-" + strings.ToLower(serviceName)
+}
+
+// detectComposeRuntime creates a ServiceRuntime for a compose service: a compose file run with
+// docker compose. Its port is the one set in ports, or else the first port its containers publish.
+func detectComposeRuntime(serviceName string, service Service, usedPorts map[int]bool, azureYamlDir string) (*ServiceRuntime, error) {
+	baseDir := azureYamlDir
+	if service.RefRoot != "" {
+		baseDir = service.RefRoot
+	}
+	composeFile := service.Compose
+	if !filepath.IsAbs(composeFile) {
+		composeFile = filepath.Join(baseDir, composeFile)
+	}
+	composeFile = filepath.Clean(composeFile)
+	if err := security.ValidatePath(composeFile); err != nil {
+		return nil, fmt.Errorf("invalid compose file: %w", err)
+	}
+	if _, err := os.Stat(composeFile); err != nil {
+		return nil, fmt.Errorf("compose file of service %s not found: %s", serviceName, composeFile)
+	}
+
+	// docker compose up --wait already waits for the containers; the port check covers the published port
+	defaultHealthCheckType := "tcp"
+	if service.IsHealthcheckDisabled() {
+		defaultHealthCheckType = watchModeNone
+	} else if service.Healthcheck != nil && service.Healthcheck.Type != "" {
+		defaultHealthCheckType = service.Healthcheck.Type
+	}
+
+	runtime := &ServiceRuntime{
+		Name:           serviceName,
+		Language:       "container",
+		Framework:      frameworkCompose,
+		PackageManager: packageMgrDocker,
+		WorkingDir:     filepath.Dir(composeFile),
+		Protocol:       "tcp",
+		Env:            make(map[string]string),
+		Type:           ServiceTypeCompose,
+		ComposeFile:    composeFile,
+		HealthCheck: HealthCheckConfig{
+			Type:     defaultHealthCheckType,
+			Timeout:  60 * time.Second,
+			Interval: 2 * time.Second,
+		},
+	}
+
+	// The compose file publishes the ports; a port set in azure.yaml picks the service's own
+	if hostPort, _, _ := service.GetPrimaryPort(); hostPort > 0 {
+		runtime.Port = hostPort
+		runtime.HealthCheck.Port = hostPort
+		usedPorts[hostPort] = true
+	}
+
+	applyHealthcheck(&runtime.HealthCheck, service.Healthcheck)
+	return runtime, nil
+}
+
+// StartComposeService starts the compose project of a compose service with docker compose up,
+// waiting until its containers are running and healthy. The service's environment is available
+// for interpolation in the compose file. The containers of other compose services that publish
+// ports are registered as "<service>-<compose service>".
+func StartComposeService(runtime *ServiceRuntime, projectDir string) (*ServiceProcess, error) {
+	if err := validateServiceNameForContainer(runtime.Name); err != nil {
+		return nil, fmt.Errorf("invalid service name: %w", err)
+	}
+
+	client := docker.NewClient()
+	if !client.IsAvailable() {
+		return nil, fmt.Errorf("docker is not available - please ensure Docker Desktop or Docker daemon is running")
+	}
+
+	project := ComposeProjectName(runtime.Name)
+	slog.Debug("starting compose service",
+		slog.String("service", runtime.Name),
+		slog.String("project", project),
+		slog.String("file", runtime.ComposeFile))
+
+	// Pull and startup progress shows up in `azd app logs` and the dashboard
+	output, flush := serviceLogWriter(projectDir, runtime.Name)
+	err := client.ComposeUp(docker.ComposeConfig{
+		File:        runtime.ComposeFile,
+		Project:     project,
+		Environment: runtime.Env,
+	}, output)
+	flush()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start compose service %s: %w", runtime.Name, err)
+	}
+
+	containers, err := client.ComposePs(project)
+	if err != nil {
+		slog.Warn("failed to list compose containers",
+			slog.String("service", runtime.Name),
+			slog.String("error", err.Error()))
+	}
+
+	process := &ServiceProcess{
+		Name:    runtime.Name,
+		Runtime: *runtime,
+		Port:    runtime.Port,
+		Env:     runtime.Env,
+	}
+	if process.Port == 0 {
+		process.Port = composePrimaryPort(containers)
+		process.Runtime.Port = process.Port
+		process.Runtime.HealthCheck.Port = process.Port
+	}
+	if process.Port == 0 && process.Runtime.HealthCheck.Type == "tcp" {
+		// Nothing is published, so readiness is up to docker compose up --wait
+		process.Runtime.HealthCheck.Type = watchModeNone
+	}
+
+	registerComposeMembers(projectDir, runtime.Name, composeMembers(runtime.Name, containers, process.Port))
+	recordLaunch(projectDir, &process.Runtime, runtime.Env)
+	return process, nil
+}
+
+// composePrimaryPort returns the first port published by the containers of a compose project, or 0.
+func composePrimaryPort(containers []docker.ComposeContainer) int {
+	for _, container := range containers {
+		if len(container.Ports) > 0 {
+			return container.Ports[0].HostPort
+		}
+	}
+	return 0
+}
+
+// composeMembers returns the compose services of a compose project that publish ports, except
+// the one whose port is the compose service's own.
+func composeMembers(serviceName string, containers []docker.ComposeContainer, port int) []composeMember {
+	var members []composeMember
+	seen := make(map[string]bool)
+	for _, container := range containers {
+		ownPort := slices.ContainsFunc(container.Ports, func(p docker.PortMapping) bool { return p.HostPort == port })
+		if len(container.Ports) == 0 || ownPort || seen[container.Service] {
+			continue
+		}
+		// Replicas of a compose service share its entry
+		seen[container.Service] = true
+		members = append(members, composeMember{
+			name: serviceName + "-" + container.Service,
+			port: container.Ports[0].HostPort,
+		})
+	}
+	return members
+}
+
+// registerComposeMembers registers the containers of a compose service that publish ports,
+// so they show up with their URLs and health in the dashboard.
+func registerComposeMembers(projectDir, serviceName string, members []composeMember) {
+	entries := make([]*registry.ServiceRegistryEntry, 0, len(members))
+	for _, member := range members {
+		entries = append(entries, &registry.ServiceRegistryEntry{
+			Name:       member.name,
+			ProjectDir: projectDir,
+			Port:       member.port,
+			URL:        fmt.Sprintf("http://localhost:%d", member.port),
+			Language:   "container",
+			Framework:  frameworkCompose,
+			Status:     constants.StatusRunning,
+			StartTime:  time.Now(),
+			Type:       ServiceTypeCompose,
+		})
+	}
+	registerMembers(projectDir, serviceName, entries)
+}
+
+// StopComposeService stops the compose project of a compose service.
+func StopComposeService(process *ServiceProcess, timeout time.Duration) error {
+	if process == nil {
+		return fmt.Errorf("process is nil")
+	}
+
+	err := StopComposeProject(process.Name, timeout)
+	EmitServiceEvent(newServiceEventInfo(EventServiceStopped, process))
+	return err
+}
+
+// StopComposeProject runs docker compose down for the project of a compose service, which
+// removes its containers but keeps its volumes, and unregisters the entries of its containers.
+func StopComposeProject(serviceName string, timeout time.Duration) error {
+	timeoutSeconds := int(timeout.Seconds())
+	if timeoutSeconds < 1 {
+		timeoutSeconds = 10
+	}
+
+	slog.Debug("stopping compose service", slog.String("service", serviceName))
+	unregisterMembers(serviceName)
+	return docker.NewClient().ComposeDown(ComposeProjectName(serviceName), timeoutSeconds)
+}
+
+// StartComposeLogCollection starts collecting the logs of the containers of a compose service
+// into its log buffer. Each line is prefixed with the name of its container.
+func StartComposeLogCollection(process *ServiceProcess, projectDir string) error {
+	logReader, err := docker.NewClient().ComposeLogs(ComposeProjectName(process.Name))
+	if err != nil {
+		return fmt.Errorf("failed to get compose logs: %w", err)
+	}
+
+	buffer, err := GetLogManager(projectDir).CreateBuffer(process.Name, 1000, true)
+	if err != nil {
+		_ = logReader.Close()
+		return fmt.Errorf("failed to create log buffer: %w", err)
+	}
+
+	go collectContainerLogs(logReader, process.Name, buffer)
+	return nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jongio/azd-app/cli/src/internal/docker"
+	"github.com/jongio/azd-core/registry"
+)
+
+func TestDetectServiceRuntime_Compose(t *testing.T) {
+	dir := t.TempDir()
+	composeFile := filepath.Join(dir, "stack", "docker-compose.yml")
+	if err := os.MkdirAll(filepath.Dir(composeFile), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(composeFile, []byte("services:\n  web:\n    image: nginx\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		svc             Service
+		wantPort        int
+		wantHealthCheck string
+	}{
+		{
+			name:            "port from compose file",
+			svc:             Service{Compose: "./stack/docker-compose.yml"},
+			wantHealthCheck: "tcp",
+		},
+		{
+			name:            "port set in azure.yaml",
+			svc:             Service{Compose: "./stack/docker-compose.yml", Ports: []string{"8080"}},
+			wantPort:        8080,
+			wantHealthCheck: "tcp",
+		},
+		{
+			name:            "healthcheck disabled",
+			svc:             Service{Compose: composeFile, HealthcheckEnabled: boolPtr(false)},
+			wantHealthCheck: watchModeNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usedPorts := map[int]bool{}
+			rt, err := DetectServiceRuntime("stack", tt.svc, usedPorts, dir, "")
+			if err != nil {
+				t.Fatalf("DetectServiceRuntime() error = %v", err)
+			}
+			if rt.Type != ServiceTypeCompose || rt.Framework != frameworkCompose {
+				t.Errorf("type = %q (framework %q), want compose", rt.Type, rt.Framework)
+			}
+			if rt.ComposeFile != composeFile {
+				t.Errorf("compose file = %q, want %q", rt.ComposeFile, composeFile)
+			}
+			if rt.WorkingDir != filepath.Dir(composeFile) {
+				t.Errorf("working dir = %q, want %q", rt.WorkingDir, filepath.Dir(composeFile))
+			}
+			if rt.Port != tt.wantPort || rt.HealthCheck.Port != tt.wantPort {
+				t.Errorf("port = %d (health check %d), want %d", rt.Port, rt.HealthCheck.Port, tt.wantPort)
+			}
+			if tt.wantPort > 0 && !usedPorts[tt.wantPort] {
+				t.Errorf("port %d should be marked as used", tt.wantPort)
+			}
+			if rt.HealthCheck.Type != tt.wantHealthCheck {
+				t.Errorf("health check type = %q, want %q", rt.HealthCheck.Type, tt.wantHealthCheck)
+			}
+		})
+	}
+
+	if _, err := DetectServiceRuntime("stack", Service{Compose: "./missing.yml"}, map[int]bool{}, dir, ""); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("DetectServiceRuntime() with a missing compose file error = %v, want not found", err)
+	}
+}
+
+func TestServiceGetServiceType_Compose(t *testing.T) {
+	svc := Service{Compose: "./docker-compose.yml", Image: "nginx"}
+	if got := svc.GetServiceType(); got != ServiceTypeCompose {
+		t.Errorf("GetServiceType() = %q, want %q", got, ServiceTypeCompose)
+	}
+}
+
+func TestComposeProjectName(t *testing.T) {
+	if got := ComposeProjectName("Stack"); got != "azd-stack" {
+		t.Errorf("ComposeProjectName() = %q, want azd-stack", got)
+	}
+	if err := docker.ValidateComposeProjectName(ComposeProjectName("My_Stack-2")); err != nil {
+		t.Errorf("ComposeProjectName() is not a valid project name: %v", err)
+	}
+}
+
+func TestComposeMembers(t *testing.T) {
+	containers := []docker.ComposeContainer{
+		{Service: "api", Ports: []docker.PortMapping{{HostPort: 8080, ContainerPort: 80}}},
+		{Service: "cache"},
+		{Service: "db", Ports: []docker.PortMapping{{HostPort: 5433, ContainerPort: 5432}}},
+		{Service: "worker", Ports: []docker.PortMapping{{HostPort: 9001, ContainerPort: 9000}}},
+		{Service: "worker", Ports: []docker.PortMapping{{HostPort: 9002, ContainerPort: 9000}}},
+	}
+
+	if got := composePrimaryPort(containers); got != 8080 {
+		t.Errorf("composePrimaryPort() = %d, want 8080", got)
+	}
+	if got := composePrimaryPort(containers[1:2]); got != 0 {
+		t.Errorf("composePrimaryPort() without published ports = %d, want 0", got)
+	}
+
+	members := composeMembers("stack", containers, 8080)
+	want := []composeMember{{name: "stack-db", port: 5433}, {name: "stack-worker", port: 9001}}
+	if len(members) != len(want) {
+		t.Fatalf("composeMembers() = %+v, want %+v", members, want)
+	}
+	for i := range want {
+		if members[i] != want[i] {
+			t.Errorf("composeMembers()[%d] = %+v, want %+v", i, members[i], want[i])
+		}
+	}
+}
+
+func TestRegisterComposeMembers(t *testing.T) {
+	dir := t.TempDir()
+	reg := registry.GetRegistry(dir)
+
+	registerComposeMembers(dir, "stack", []composeMember{{name: "stack-db", port: 5433}})
+	entry, ok := reg.GetService("stack-db")
+	if !ok {
+		t.Fatal("compose container should be registered")
+	}
+	if entry.Port != 5433 || entry.Type != ServiceTypeCompose || entry.URL != "http://localhost:5433" {
+		t.Errorf("entry = %+v, want compose entry on port 5433", entry)
+	}
+	if got := MemberOwnerOf("stack-db"); got != "stack" {
+		t.Errorf("MemberOwnerOf(stack-db) = %q, want stack", got)
+	}
+	if got := MemberOwnerOf("stack"); got != "stack" {
+		t.Errorf("MemberOwnerOf(stack) = %q, want stack", got)
+	}
+
+	unregisterMembers("stack")
+	if _, ok := reg.GetService("stack-db"); ok {
+		t.Error("compose container should be unregistered")
+	}
+	if got := MemberOwnerOf("stack-db"); got != "stack-db" {
+		t.Errorf("MemberOwnerOf(stack-db) after stop = %q, want stack-db", got)
+	}
+}
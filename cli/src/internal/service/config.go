@@ -45,6 +45,11 @@ func ValidateServiceConfig(serviceName string, svc *Service) error {
 		}
 	}
 
+	// Validate protocol if present
+	if svc.Protocol != "" && svc.Protocol != ServiceTypeHTTP && svc.Protocol != ProtocolHTTPS {
+		return fmt.Errorf("invalid protocol for service '%s': must be 'http' or 'https', got '%s'", serviceName, svc.Protocol)
+	}
+
 	return nil
 }
 
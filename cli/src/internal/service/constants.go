@@ -25,11 +25,13 @@ const (
 	// DefaultLogSubscriberTimeout is the timeout for sending log entries to slow subscribers.
 	DefaultLogSubscriberTimeout = 10 * time.Millisecond
 
-	// DefaultWebSocketPongWait is the timeout for receiving pong messages from WebSocket clients.
-	DefaultWebSocketPongWait = 60 * time.Second
+	// DefaultWebSocketPongWait is how long a ping waits for the client's pong before the
+	// connection is considered dead.
+	DefaultWebSocketPongWait = 10 * time.Second
 
-	// DefaultWebSocketPingPeriod is the period for sending ping messages (must be less than PongWait).
-	DefaultWebSocketPingPeriod = 54 * time.Second // (PongWait * 9) / 10
+	// DefaultWebSocketPingPeriod is the period for sending ping messages.
+	// Kept below the 30-60s idle timeouts of common proxies so idle connections stay open.
+	DefaultWebSocketPingPeriod = 25 * time.Second
 
 	// DefaultWebSocketWriteTimeout is the timeout for WebSocket write operations.
 	// Set to 2s for local connections - balances detecting slow clients with system scheduling delays
@@ -47,6 +49,10 @@ const (
 	// WebSocketMaxConcurrentBroadcasts is the maximum number of concurrent broadcast goroutines
 	WebSocketMaxConcurrentBroadcasts = 20
 
+	// WebSocketReplayBuffer is the number of service-status events kept for replay to
+	// reconnecting dashboard clients
+	WebSocketReplayBuffer = 256
+
 	// WebSocketSlowConsumerTimeout is the timeout for sending to slow consumer channels
 	WebSocketSlowConsumerTimeout = 500 * time.Millisecond
 
@@ -48,6 +48,15 @@ func validateServiceNameForContainer(name string) error {
 //   - restartContainers: If true, always restart containers even if already running.
 //     If false, skip starting if container is already running and healthy.
 func StartContainerService(runtime *ServiceRuntime, projectDir string, restartContainers bool) (*ServiceProcess, error) {
+	process, err := startContainerService(runtime, projectDir, restartContainers)
+	if err == nil {
+		recordLaunch(projectDir, runtime, runtime.Env)
+	}
+	return process, err
+}
+
+// startContainerService starts or reuses the container of a service.
+func startContainerService(runtime *ServiceRuntime, projectDir string, restartContainers bool) (*ServiceProcess, error) {
 	// Validate service name before using it in container names
 	if err := validateServiceNameForContainer(runtime.Name); err != nil {
 		return nil, fmt.Errorf("invalid service name: %w", err)
@@ -72,15 +81,27 @@ func StartContainerService(runtime *ServiceRuntime, projectDir string, restartCo
 		slog.String("image", image),
 		slog.Int("port", runtime.Port))
 
-	// Pull image if needed (will be cached if already present)
-	slog.Debug("pulling container image", slog.String("image", image))
-	if err := client.Pull(image); err != nil {
-		// Don't fail if pull fails - image might be cached locally
-		slog.Warn("failed to pull image (continuing with cached version if available)",
-			slog.String("image", image),
-			slog.String("error", err.Error()))
+	if runtime.Build != nil {
+		// Build the image from the service's Dockerfile (Docker's layer cache keeps rebuilds fast)
+		if err := buildContainerImage(client, runtime, image, projectDir); err != nil {
+			return nil, err
+		}
+		// A rebuilt image only takes effect in a new container, so never reuse an existing one
+		restartContainers = true
+	} else {
+		// Pull image if needed (will be cached if already present)
+		slog.Debug("pulling container image", slog.String("image", image))
+		if err := client.Pull(image); err != nil {
+			// Don't fail if pull fails - image might be cached locally
+			slog.Warn("failed to pull image (continuing with cached version if available)",
+				slog.String("image", image),
+				slog.String("error", err.Error()))
+		}
 	}
 
+	// Free the host ports held since port assignment so Docker can publish them
+	releaseHeldPorts(runtime)
+
 	// Check if container already exists
 	containerName := fmt.Sprintf("azd-%s", runtime.Name)
 	if !restartContainers {
@@ -139,6 +160,7 @@ func StartContainerService(runtime *ServiceRuntime, projectDir string, restartCo
 		Image:       image,
 		Ports:       buildContainerPortMappings(runtime),
 		Environment: runtime.Env,
+		Volumes:     runtime.Volumes,
 	}
 
 	// Run container
@@ -185,22 +207,79 @@ func StartContainerService(runtime *ServiceRuntime, projectDir string, restartCo
 	return process, nil
 }
 
+// buildContainerImage builds the image of a Dockerfile-based service, streaming the build
+// output into the service's log buffer so it shows up in `azd app logs` and the dashboard.
+func buildContainerImage(client *docker.ExecClient, runtime *ServiceRuntime, image, projectDir string) error {
+	config := docker.BuildConfig{
+		Image:      image,
+		Dockerfile: runtime.Build.Dockerfile,
+		Context:    runtime.Build.Context,
+		BuildArgs:  runtime.Build.BuildArgs,
+		Platform:   runtime.Build.Platform,
+	}
+
+	slog.Debug("building container image",
+		slog.String("service", runtime.Name),
+		slog.String("image", image),
+		slog.String("dockerfile", config.Dockerfile))
+
+	output, flush := serviceLogWriter(projectDir, runtime.Name)
+	defer flush()
+
+	if err := client.Build(config, output); err != nil {
+		return fmt.Errorf("failed to build image for %s: %w", runtime.Name, err)
+	}
+	return nil
+}
+
+// serviceLogWriter returns a writer whose lines are added to the log buffer of a service and a
+// function that flushes it, which must be called once writing is done. The writer is nil when
+// the log buffer can't be created.
+func serviceLogWriter(projectDir, serviceName string) (io.Writer, func()) {
+	buffer, err := GetLogManager(projectDir).CreateBuffer(serviceName, 1000, true)
+	if err != nil {
+		slog.Warn("failed to create log buffer",
+			slog.String("service", serviceName),
+			slog.String("error", err.Error()))
+		return nil, func() {}
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		collectContainerLogs(pr, serviceName, buffer)
+	}()
+	return pw, func() {
+		_ = pw.Close()
+		<-done
+	}
+}
+
 // buildContainerPortMappings converts ServiceRuntime port to Docker port mappings.
 func buildContainerPortMappings(runtime *ServiceRuntime) []docker.PortMapping {
 	var mappings []docker.PortMapping
 
 	// If runtime has a port, map it
 	if runtime.Port > 0 {
+		containerPort := runtime.ContainerPort
+		if containerPort == 0 {
+			containerPort = runtime.Port
+		}
 		mappings = append(mappings, docker.PortMapping{
 			HostPort:      runtime.Port,
-			ContainerPort: runtime.Port, // Assume same port for now
+			ContainerPort: containerPort,
 			Protocol:      "tcp",
 		})
 	}
 
-	// TODO(#1001): Parse additional ports from runtime if needed
-	// Currently only maps the primary port from runtime.Port. Need to support multiple port mappings
-	// for services that expose additional ports (e.g., debug ports, metrics endpoints).
+	for _, extra := range runtime.ExtraPorts {
+		mappings = append(mappings, docker.PortMapping{
+			HostPort:      extra.HostPort,
+			ContainerPort: extra.ContainerPort,
+			Protocol:      "tcp",
+		})
+	}
 
 	return mappings
 }
@@ -249,6 +328,7 @@ func StopContainerService(process *ServiceProcess, timeout time.Duration) error
 	slog.Debug("container stopped",
 		slog.String("service", process.Name))
 
+	EmitServiceEvent(newServiceEventInfo(EventServiceStopped, process))
 	return nil
 }
 
@@ -289,14 +369,7 @@ func collectContainerLogs(reader io.ReadCloser, serviceName string, buffer *LogB
 
 	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
-		entry := LogEntry{
-			Service:   serviceName,
-			Message:   scanner.Text(),
-			Timestamp: time.Now(),
-			IsStderr:  false, // Docker logs combine stdout/stderr
-			Level:     inferLogLevel(scanner.Text()),
-		}
-		buffer.Add(entry)
+		buffer.Add(NewLogEntry(serviceName, scanner.Text(), false)) // Docker logs combine stdout/stderr
 	}
 }
 
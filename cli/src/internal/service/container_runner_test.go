@@ -135,6 +135,21 @@ func TestBuildContainerPortMappings(t *testing.T) {
 			},
 			expected: []docker.PortMapping{},
 		},
+		{
+			name: "runtime with separate container port",
+			runtime: &ServiceRuntime{
+				Name:          "test",
+				Port:          45123,
+				ContainerPort: 80,
+			},
+			expected: []docker.PortMapping{
+				{
+					HostPort:      45123,
+					ContainerPort: 80,
+					Protocol:      "tcp",
+				},
+			},
+		},
 		{
 			name: "runtime with different port",
 			runtime: &ServiceRuntime{
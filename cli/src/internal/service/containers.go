@@ -0,0 +1,225 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Container is a local container dependency declared in the containers section of azure.yaml,
+// such as a database or a message broker. It runs as a container service that services can
+// use; well-known images get a default port, credentials, a named data volume and connection
+// variables for the services that use them.
+//
+// A container is either an image name (`db: postgres:16`) or an object with the container
+// service fields image, ports, environment, volumes and healthcheck.
+type Container struct {
+	Image              string
+	Ports              []string
+	Environment        Environment
+	Volumes            []string
+	Healthcheck        *HealthcheckConfig
+	HealthcheckEnabled *bool
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for the image name shorthand.
+func (c *Container) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var image string
+	if err := unmarshal(&image); err == nil {
+		c.Image = image
+		return nil
+	}
+
+	// Parsed as a service to share its handling of environment formats and healthcheck: false
+	var svc Service
+	if err := unmarshal(&svc); err != nil {
+		return err
+	}
+	if svc.Project != "" || svc.Run != "" || svc.Ref != "" {
+		return fmt.Errorf("containers only run images; declare services with a project in the services section")
+	}
+	c.Image = svc.Image
+	c.Ports = svc.Ports
+	c.Environment = svc.GetEnvironment()
+	c.Volumes = svc.Volumes
+	c.Healthcheck = svc.Healthcheck
+	c.HealthcheckEnabled = svc.HealthcheckEnabled
+	return nil
+}
+
+// containerKind holds the defaults of a well-known container dependency.
+type containerKind struct {
+	port    int                                                      // Port the server listens on in the container
+	dataDir string                                                   // Directory the server keeps its data in, persisted in a named volume
+	env     map[string]string                                        // Default environment, e.g. development credentials
+	connect func(env map[string]string, port int) *ServiceConnection // Connection of services that use it
+}
+
+// containerKinds holds the defaults of well-known images, keyed by probe name (see probeImages).
+//
+// SECURITY NOTE: the credentials are well-known defaults for local development only.
+var containerKinds = map[string]containerKind{
+	"postgres": {
+		port:    5432,
+		dataDir: "/var/lib/postgresql/data",
+		env:     map[string]string{"POSTGRES_USER": "postgres", "POSTGRES_PASSWORD": "postgres", "POSTGRES_DB": "app"}, // #nosec G101 -- local development defaults
+		connect: func(env map[string]string, port int) *ServiceConnection {
+			user, password, db := env["POSTGRES_USER"], env["POSTGRES_PASSWORD"], env["POSTGRES_DB"]
+			url := fmt.Sprintf("postgresql://%s:%s@localhost:%d/%s?sslmode=disable", user, password, port, db)
+			return &ServiceConnection{String: url, Env: map[string]string{
+				"DATABASE_URL": url,
+				"PGHOST":       "localhost",
+				"PGPORT":       strconv.Itoa(port),
+				"PGUSER":       user,
+				"PGPASSWORD":   password,
+				"PGDATABASE":   db,
+			}}
+		},
+	},
+	"mysql": {
+		port:    3306,
+		dataDir: "/var/lib/mysql",
+		env:     map[string]string{"MYSQL_ROOT_PASSWORD": "mysql", "MYSQL_DATABASE": "app"}, // #nosec G101 -- local development defaults
+		connect: func(env map[string]string, port int) *ServiceConnection {
+			url := fmt.Sprintf("mysql://root:%s@localhost:%d/%s", env["MYSQL_ROOT_PASSWORD"], port, env["MYSQL_DATABASE"])
+			return &ServiceConnection{String: url, Env: map[string]string{"DATABASE_URL": url}}
+		},
+	},
+	"redis": {
+		port:    6379,
+		dataDir: "/data",
+		connect: func(_ map[string]string, port int) *ServiceConnection {
+			url := fmt.Sprintf("redis://localhost:%d", port)
+			return &ServiceConnection{String: url, Env: map[string]string{
+				"REDIS_URL":  url,
+				"REDIS_HOST": "localhost",
+				"REDIS_PORT": strconv.Itoa(port),
+			}}
+		},
+	},
+	"mongo": {
+		port:    27017,
+		dataDir: "/data/db",
+		connect: func(_ map[string]string, port int) *ServiceConnection {
+			url := fmt.Sprintf("mongodb://localhost:%d", port)
+			return &ServiceConnection{String: url, Env: map[string]string{"MONGODB_URI": url}}
+		},
+	},
+	"rabbitmq": {
+		port:    5672,
+		dataDir: "/var/lib/rabbitmq",
+		env:     map[string]string{"RABBITMQ_DEFAULT_USER": "guest", "RABBITMQ_DEFAULT_PASS": "guest"}, // #nosec G101 -- local development defaults
+		connect: func(env map[string]string, port int) *ServiceConnection {
+			url := fmt.Sprintf("amqp://%s:%s@localhost:%d/", env["RABBITMQ_DEFAULT_USER"], env["RABBITMQ_DEFAULT_PASS"], port)
+			return &ServiceConnection{String: url, Env: map[string]string{"RABBITMQ_URL": url}}
+		},
+	},
+}
+
+// ServiceConnection is how services connect to a service they use, such as a database.
+type ServiceConnection struct {
+	String string            // Connection string or URL, also given as SERVICE_<NAME>_CONNECTION_STRING
+	Env    map[string]string // Conventional variables, e.g. DATABASE_URL
+}
+
+// serviceConnection returns how services that use svc connect to it when it listens on port,
+// or nil if it isn't a storage emulator or a well-known container dependency.
+func serviceConnection(svc Service, port int) *ServiceConnection {
+	if port <= 0 {
+		return nil
+	}
+	if svc.Type == ServiceTypeAzurite {
+		return azuriteConnection(port)
+	}
+	if !svc.IsContainerService() {
+		return nil
+	}
+	kind, ok := containerKinds[probeImages[imageRepositoryName(svc.GetContainerImage())]]
+	if !ok {
+		return nil
+	}
+	return kind.connect(svc.GetEnvironment(), port)
+}
+
+// containerServices returns the containers of azure.yaml as container services. Well-known
+// images get their default port (on a free host port), environment and data volume, named
+// after the project so data survives across runs.
+func containerServices(azureYaml *AzureYaml) (map[string]Service, error) {
+	services := make(map[string]Service, len(azureYaml.Containers))
+	for _, name := range sortedContainerNames(azureYaml.Containers) {
+		c := azureYaml.Containers[name]
+		if c.Image == "" {
+			return nil, fmt.Errorf("container '%s' has no image", name)
+		}
+		if _, exists := azureYaml.Services[name]; exists {
+			return nil, fmt.Errorf("container '%s' has the name of a service", name)
+		}
+
+		svc := Service{
+			Host:               "local",
+			Image:              c.Image,
+			Type:               ServiceTypeContainer,
+			Ports:              c.Ports,
+			Volumes:            c.Volumes,
+			Environment:        make(Environment),
+			Healthcheck:        c.Healthcheck,
+			HealthcheckEnabled: c.HealthcheckEnabled,
+		}
+		if kind, ok := containerKinds[probeImages[imageRepositoryName(c.Image)]]; ok {
+			for key, value := range kind.env {
+				svc.Environment[key] = value
+			}
+			if len(svc.Ports) == 0 {
+				// Host port 0 is assigned by the port manager, preferring the container port
+				svc.Ports = []string{fmt.Sprintf("0:%d", kind.port)}
+			}
+			if svc.Volumes == nil {
+				svc.Volumes = []string{containerVolumeName(azureYaml.Name, name) + ":" + kind.dataDir}
+			}
+		}
+		for key, value := range c.Environment {
+			svc.Environment[key] = value
+		}
+		services[name] = svc
+	}
+	return services, nil
+}
+
+// volumeNameInvalidChars matches characters Docker doesn't allow in volume names.
+var volumeNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// containerVolumeName returns the name of the data volume of a container, e.g. "shop-db-data".
+func containerVolumeName(project, container string) string {
+	name := volumeNameInvalidChars.ReplaceAllString(strings.ToLower(project+"-"+container+"-data"), "-")
+	return strings.TrimLeft(name, "-_.")
+}
+
+// containerVolumes returns the volumes of a container service in docker run -v form, with
+// relative host paths resolved against the azure.yaml directory. Named volumes are kept as-is.
+func containerVolumes(volumes []string, azureYamlDir string) []string {
+	if len(volumes) == 0 {
+		return nil
+	}
+	resolved := make([]string, 0, len(volumes))
+	for _, volume := range volumes {
+		source, target, found := strings.Cut(volume, ":")
+		if found && (source == "." || source == ".." || strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")) {
+			volume = filepath.Join(azureYamlDir, source) + ":" + target
+		}
+		resolved = append(resolved, volume)
+	}
+	return resolved
+}
+
+// sortedContainerNames returns the names of the containers of azure.yaml in order.
+func sortedContainerNames(containers map[string]Container) []string {
+	names := make([]string, 0, len(containers))
+	for name := range containers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
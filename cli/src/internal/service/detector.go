@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jongio/azd-app/cli/src/internal/detector"
 	"github.com/jongio/azd-app/cli/src/internal/portmanager"
 	"github.com/jongio/azd-core/security"
 )
@@ -26,7 +27,51 @@ const (
 )
 
 // DetectServiceRuntime determines how to run a service based on its configuration and project structure.
+// The runtime also records the service's dependencies (uses and dependsOn) for startup ordering.
 func DetectServiceRuntime(serviceName string, service Service, usedPorts map[int]bool, azureYamlDir string, runtimeMode string) (*ServiceRuntime, error) {
+	runtime, err := detectServiceRuntime(serviceName, service, usedPorts, azureYamlDir, runtimeMode)
+	if err != nil {
+		return nil, err
+	}
+	applyToolPaths(runtime, service.ToolPaths)
+	runtime.DependsOn = service.Dependencies()
+	runtime.Restart = service.Restart
+	runtime.BuildCommand = service.Build
+	runtime.PreRun = service.PreRun
+	runtime.PostStop = service.PostStop
+	if runtime.StopGracePeriod, err = service.GetStopGracePeriod(); err != nil {
+		return nil, fmt.Errorf("service %s: %w", serviceName, err)
+	}
+	if service.Protocol == ProtocolHTTPS {
+		runtime.Protocol = ProtocolHTTPS
+	}
+
+	// Service env (envFile, environment, env) overrides detected defaults
+	env, err := LoadServiceEnvironment(service, azureYamlDir)
+	if err != nil {
+		return nil, fmt.Errorf("service %s: %w", serviceName, err)
+	}
+	if runtime.Env == nil {
+		runtime.Env = make(map[string]string, len(env))
+	}
+	for key, value := range env {
+		runtime.Env[key] = value
+	}
+	return runtime, nil
+}
+
+// detectServiceRuntime builds the runtime for a compose, container, Functions, or native service.
+func detectServiceRuntime(serviceName string, service Service, usedPorts map[int]bool, azureYamlDir string, runtimeMode string) (*ServiceRuntime, error) {
+	// Managed services don't have a project; an Azurite service may also set the image to run
+	if service.Type == ServiceTypeAzurite {
+		return detectAzuriteRuntime(serviceName, service, usedPorts, azureYamlDir)
+	}
+
+	// Compose services run a compose file rather than a project
+	if service.Compose != "" {
+		return detectComposeRuntime(serviceName, service, usedPorts, azureYamlDir)
+	}
+
 	// Check for container services first (identified by image field)
 	if service.IsContainerService() {
 		return detectContainerRuntime(serviceName, service, usedPorts, azureYamlDir)
@@ -71,32 +116,39 @@ func DetectServiceRuntime(serviceName string, service Service, usedPorts map[int
 		},
 	}
 
-	// Apply custom health check path and pattern if configured
-	if service.Healthcheck != nil {
-		if service.Healthcheck.Path != "" {
-			runtime.HealthCheck.Path = service.Healthcheck.Path
+	entrypoint, command := service.RunCommand()
+
+	// Special handling for Azure Functions (all variants including Logic Apps). Functions projects
+	// are also recognized by their files when neither host: function nor a language is set.
+	if service.Host == "function" || (service.Language == "" && isFunctionsProject(projectDir)) {
+		functionsRuntime, err := buildFunctionsRuntime(serviceName, service, projectDir, usedPorts, azureYamlDir)
+		if err != nil || !service.HasExplicitCommand() {
+			return functionsRuntime, err
 		}
-		if service.Healthcheck.Pattern != "" {
-			runtime.HealthCheck.LogMatch = service.Healthcheck.Pattern
+		// An explicit command replaces func start
+		if err := buildRunCommand(functionsRuntime, projectDir, entrypoint, command, runtimeMode); err != nil {
+			return nil, fmt.Errorf("failed to build run command: %w", err)
 		}
+		return functionsRuntime, nil
 	}
 
-	// Special handling for Azure Functions (all variants including Logic Apps)
-	if service.Host == "function" {
-		return buildFunctionsRuntime(serviceName, service, projectDir, usedPorts, azureYamlDir)
-	}
-
-	// Detect language (use explicit language if provided)
+	// Detect language (use explicit language if provided). A service with an explicit command
+	// doesn't need a recognizable project: the command is run as-is.
 	language := service.Language
 	if language == "" {
 		detectedLang, err := detectLanguage(projectDir, service.Host)
-		if err != nil {
+		if err != nil && !service.HasExplicitCommand() {
 			return nil, fmt.Errorf("failed to detect language: %w", err)
 		}
 		language = detectedLang
 	}
 	runtime.Language = normalizeLanguage(language)
 
+	// A service with only a Dockerfile (and no command of its own) is built and run as a container
+	if runtime.Language == frameworkDocker && !service.HasExplicitCommand() {
+		return detectDockerfileRuntime(serviceName, service, projectDir, usedPorts, azureYamlDir)
+	}
+
 	// Detect framework and package manager
 	framework, packageManager, err := detectFrameworkAndPackageManager(projectDir, runtime.Language)
 	if err != nil {
@@ -105,14 +157,23 @@ func DetectServiceRuntime(serviceName string, service Service, usedPorts map[int
 	runtime.Framework = framework
 	runtime.PackageManager = packageManager
 
+	// A package inside a JavaScript workspace uses the workspace's package manager, and its
+	// scripts are run through turbo or nx when the monorepo uses them
+	if (runtime.Language == langNameJavaScript || runtime.Language == langTypeScript) && packageManager != packageMgrDeno {
+		absProjectDir, _ := filepath.Abs(projectDir)
+		if workspace := detector.FindNodeWorkspace(projectDir, azureYamlDir); workspace != nil && workspace.Root != absProjectDir {
+			runtime.Workspace = workspace
+			runtime.PackageManager = workspace.PackageManager
+		}
+	}
+
 	// Port assignment: skip for services that don't need a port (e.g., build/watch services)
 	if service.NeedsPort() {
 		// Detect preferred port from config (and whether it's explicitly set in azure.yaml)
 		preferredPort, isExplicit, _ := DetectPort(serviceName, service, projectDir, framework, usedPorts)
 
 		// Use port manager from azure.yaml directory (not service project dir) so all services share port assignments
-		portMgr := portmanager.GetPortManager(azureYamlDir)
-		port, shouldUpdateAzureYaml, err := portMgr.AssignPort(serviceName, preferredPort, isExplicit)
+		port, shouldUpdateAzureYaml, err := assignServicePort(azureYamlDir, serviceName, service, preferredPort, isExplicit)
 		if err != nil {
 			return nil, fmt.Errorf("failed to assign port: %w", err)
 		}
@@ -130,13 +191,24 @@ func DetectServiceRuntime(serviceName string, service Service, usedPorts map[int
 
 	// Build command and args based on framework (AFTER port assignment)
 	// Docker Compose style: entrypoint is executable, command is args
-	if err := buildRunCommand(runtime, projectDir, service.Entrypoint, service.Command, runtimeMode); err != nil {
+	runtime.PythonInterpreter = service.PythonInterpreter()
+	if err := buildRunCommand(runtime, projectDir, entrypoint, command, runtimeMode); err != nil {
 		return nil, fmt.Errorf("failed to build run command: %w", err)
 	}
 
-	// Set health check configuration based on framework (only if not explicitly disabled)
+	// Run Node.js services with the version their project pins, when asked to
+	isNode := runtime.Language == langNameJavaScript || runtime.Language == langTypeScript
+	if isNode && service.UsesNodeVersionManager() && runtime.PackageManager != packageMgrDeno && runtime.PackageManager != packageMgrBun {
+		if err := applyNodeVersionManager(runtime, projectDir, azureYamlDir); err != nil {
+			return nil, fmt.Errorf("node.useVersionManager: %w", err)
+		}
+	}
+
+	// Set health check configuration based on framework (only if not explicitly disabled),
+	// then apply the healthcheck configured in azure.yaml over it
 	if !service.IsHealthcheckDisabled() {
 		configureHealthCheck(runtime)
+		applyHealthcheck(&runtime.HealthCheck, service.Healthcheck)
 	}
 
 	// Detect and set service type and mode
@@ -149,6 +221,25 @@ func DetectServiceRuntime(serviceName string, service Service, usedPorts map[int
 	return runtime, nil
 }
 
+// assignServicePort assigns a port to the service with the port manager of the azure.yaml
+// directory, taking automatically assigned ports from the service's portRange when it sets one.
+// Ports of services whose primary port is UDP (e.g. ports: ["4433/udp"]) are checked with UDP.
+func assignServicePort(azureYamlDir, serviceName string, service Service, preferredPort int, isExplicit bool) (int, bool, error) {
+	start, end, err := service.GetPortRange()
+	if err != nil {
+		return 0, false, err
+	}
+	portMgr := portmanager.GetPortManager(azureYamlDir)
+	if err := portMgr.SetServicePortRange(serviceName, start, end); err != nil {
+		return 0, false, err
+	}
+	protocol := portmanager.ProtocolTCP
+	if mappings, _ := service.GetPortMappings(); len(mappings) > 0 && mappings[0].Protocol == string(portmanager.ProtocolUDP) {
+		protocol = portmanager.ProtocolUDP
+	}
+	return portMgr.AssignPortWithProtocol(serviceName, preferredPort, isExplicit, protocol)
+}
+
 // detectContainerRuntime creates a ServiceRuntime for a Docker container service.
 // Container services are identified by having an `image` field set.
 func detectContainerRuntime(serviceName string, service Service, usedPorts map[int]bool, azureYamlDir string) (*ServiceRuntime, error) {
@@ -178,6 +269,16 @@ func detectContainerRuntime(serviceName string, service Service, usedPorts map[i
 		},
 	}
 
+	// Use a built-in readiness probe for well-known infrastructure images unless the user configured one
+	if service.Healthcheck == nil && defaultHealthCheckType == "tcp" {
+		if probe := BuiltinHealthcheck(image); probe != nil {
+			runtime.HealthCheck.Probe, _ = probe.Test.([]string)
+			if d, err := time.ParseDuration(probe.StartPeriod); err == nil {
+				runtime.HealthCheck.Timeout += d
+			}
+		}
+	}
+
 	// Store container image in the runtime (using Command field for now)
 	// TODO(#1002): Add dedicated Image field to ServiceRuntime
 	// Currently storing container image in Command field which is semantically incorrect.
@@ -186,11 +287,6 @@ func detectContainerRuntime(serviceName string, service Service, usedPorts map[i
 	runtime.Language = "container"
 	runtime.Framework = packageMgrDocker
 
-	// Copy environment variables from service config
-	for key, value := range service.GetEnvironment() {
-		runtime.Env[key] = value
-	}
-
 	// Handle port assignment for container services
 	if service.NeedsPort() {
 		// Get port mappings from service config
@@ -202,8 +298,7 @@ func detectContainerRuntime(serviceName string, service Service, usedPorts map[i
 
 			if hostPort == 0 {
 				// Auto-assign host port using port manager
-				portMgr := portmanager.GetPortManager(azureYamlDir)
-				assignedPort, shouldUpdate, err := portMgr.AssignPort(serviceName, containerPort, isExplicit)
+				assignedPort, shouldUpdate, err := assignServicePort(azureYamlDir, serviceName, service, containerPort, isExplicit)
 				if err != nil {
 					return nil, fmt.Errorf("failed to assign port for container: %w", err)
 				}
@@ -213,12 +308,21 @@ func detectContainerRuntime(serviceName string, service Service, usedPorts map[i
 				runtime.Port = hostPort
 			}
 
+			if containerPort > 0 {
+				runtime.ContainerPort = containerPort
+			}
+
 			// Update health check port
 			runtime.HealthCheck.Port = runtime.Port
 			usedPorts[runtime.Port] = true
 		}
 	}
 
+	runtime.Volumes = containerVolumes(service.Volumes, azureYamlDir)
+	runtime.Connection = serviceConnection(service, runtime.Port)
+
+	applyHealthcheck(&runtime.HealthCheck, service.Healthcheck)
+
 	return runtime, nil
 }
 
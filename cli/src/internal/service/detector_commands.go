@@ -4,18 +4,25 @@ package service
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/executor"
+	"github.com/jongio/azd-app/cli/src/internal/installer"
 	"github.com/jongio/azd-core/security"
 )
 
 const frameworkSpringBoot = "Spring Boot"
 
 // buildRunCommand builds the command and arguments to run the service.
+// An explicit command is authoritative: it's used as-is, without framework defaults.
 //
 // Priority:
-//  1. command: Full shell command (e.g., "uvicorn main:app --reload") - PRIMARY
+//  1. command: Full shell command (e.g., "uvicorn main:app --reload") - PRIMARY.
+//     A service's run field is passed here as the command (see Service.RunCommand).
 //  2. entrypoint + command: Advanced Docker Compose style (rarely needed)
 //  3. Neither: Auto-detect based on framework
 func buildRunCommand(runtime *ServiceRuntime, projectDir, entrypoint, command, runtimeMode string) error {
@@ -29,7 +36,7 @@ func buildRunCommand(runtime *ServiceRuntime, projectDir, entrypoint, command, r
 		if command != "" {
 			// Both provided: entrypoint is executable, command is args
 			runtime.Command = entrypoint
-			runtime.Args = strings.Fields(command)
+			runtime.Args = executor.SplitCommand(command)
 		} else {
 			// Only entrypoint: split it as full command
 			return parseShellCommand(runtime, entrypoint)
@@ -43,8 +50,9 @@ func buildRunCommand(runtime *ServiceRuntime, projectDir, entrypoint, command, r
 
 // parseShellCommand parses a user-provided shell command into command and args.
 // Handles both simple commands ("node server.js") and complex ones ("uvicorn main:app --reload").
+// Quoted arguments are kept together, so paths with spaces can be written as "./my app/server.js".
 func parseShellCommand(runtime *ServiceRuntime, command string) error {
-	parts := strings.Fields(command)
+	parts := executor.SplitCommand(command)
 	if len(parts) == 0 {
 		return fmt.Errorf("empty command")
 	}
@@ -57,6 +65,75 @@ func parseShellCommand(runtime *ServiceRuntime, command string) error {
 	return nil
 }
 
+// setNodeScriptCommand sets the command to run a package.json script with the service's
+// package manager, going through corepack when the global pnpm/yarn binary is missing.
+// Deno runs the script as a task, which covers both deno.json tasks and package.json scripts.
+// In a turbo or nx monorepo the script is run through the monorepo tool, so the tasks it
+// depends on (such as building shared packages) run first.
+func setNodeScriptCommand(runtime *ServiceRuntime, script string) {
+	if runtime.PackageManager == packageMgrDeno {
+		runtime.Command = packageMgrDeno
+		runtime.Args = []string{"task", script}
+		return
+	}
+	if setMonorepoScriptCommand(runtime, script) {
+		return
+	}
+	command, prefixArgs := installer.PackageManagerCommand(runtime.PackageManager)
+	runtime.Command = command
+	runtime.Args = append(prefixArgs, "run", script)
+}
+
+// setMonorepoScriptCommand sets the command to run a script through the turbo or nx binary
+// installed in the workspace: `turbo run <script> --filter=<package>` or `nx run <project>:<script>`.
+// Returns false when the service isn't in a turbo or nx workspace or its package has no name.
+func setMonorepoScriptCommand(runtime *ServiceRuntime, script string) bool {
+	if runtime.Workspace == nil {
+		return false
+	}
+
+	var toolArgs []string
+	switch runtime.Workspace.Tool {
+	case detector.MonorepoToolTurbo:
+		name := detector.NodePackageName(runtime.WorkingDir)
+		if name == "" {
+			return false
+		}
+		toolArgs = []string{detector.MonorepoToolTurbo, "run", script, "--filter=" + name}
+	case detector.MonorepoToolNx:
+		name := detector.NxProjectName(runtime.WorkingDir)
+		if name == "" {
+			return false
+		}
+		toolArgs = []string{detector.MonorepoToolNx, "run", name + ":" + script}
+	default:
+		return false
+	}
+
+	command, prefixArgs := packageManagerExec(runtime.PackageManager)
+	runtime.Command = command
+	runtime.Args = append(prefixArgs, toolArgs...)
+	return true
+}
+
+// packageManagerExec returns the command and leading args that run a binary installed in
+// node_modules with the given package manager.
+func packageManagerExec(packageManager string) (string, []string) {
+	switch packageManager {
+	case packageMgrBun:
+		return "bunx", nil
+	case packageMgrPnpm:
+		command, prefixArgs := installer.PackageManagerCommand(packageManager)
+		return command, append(prefixArgs, "exec")
+	case packageMgrYarn:
+		// yarn runs binaries of installed packages directly
+		return installer.PackageManagerCommand(packageManager)
+	default:
+		// --no fails instead of prompting to download a missing package
+		return "npx", []string{"--no"}
+	}
+}
+
 // buildFrameworkCommand builds framework-specific commands using intelligent defaults.
 func buildFrameworkCommand(runtime *ServiceRuntime, projectDir, runtimeMode string) error {
 	// Handle Python frameworks with venv support
@@ -66,33 +143,36 @@ func buildFrameworkCommand(runtime *ServiceRuntime, projectDir, runtimeMode stri
 	}
 
 	if _, isPython := pythonFrameworks[runtime.Framework]; isPython {
-		pythonCmd := "python"
-		if venvPython := getPythonVenvPath(projectDir); venvPython != "" {
-			pythonCmd = venvPython
+		if runtime.PackageManager == packageMgrUv {
+			if _, err := uvLookPath(packageMgrUv); err == nil {
+				return buildUvRunCommand(runtime, projectDir)
+			}
 		}
-		return buildPythonDefaultCommand(runtime, projectDir, pythonCmd)
+		return buildPythonDefaultCommand(runtime, projectDir, resolvePythonCommand(runtime, projectDir))
 	}
 
 	switch runtime.Framework {
 	case "Next.js", "React", "Vue", "Svelte", "SvelteKit", "Remix", "Astro", "Nuxt":
-		runtime.Command = runtime.PackageManager
-		runtime.Args = []string{"run", "dev"}
+		setNodeScriptCommand(runtime, "dev")
 
 	case "Angular":
 		runtime.Command = "ng"
 		runtime.Args = []string{"serve", "--port", fmt.Sprintf("%d", runtime.Port)}
 
 	case "NestJS":
-		runtime.Command = runtime.PackageManager
-		runtime.Args = []string{"run", "start:dev"}
+		setNodeScriptCommand(runtime, "start:dev")
 
 	case "Express", "Node.js":
-		runtime.Command = runtime.PackageManager
 		// Try dev first, fall back to start
-		if hasScript(projectDir, "dev") {
-			runtime.Args = []string{"run", "dev"}
-		} else {
-			runtime.Args = []string{"run", "start"}
+		switch {
+		case hasScript(projectDir, "dev"):
+			setNodeScriptCommand(runtime, "dev")
+		case runtime.PackageManager == packageMgrDeno && !hasScript(projectDir, "start"):
+			// Deno projects often have no tasks; run the entry point directly
+			runtime.Command = packageMgrDeno
+			runtime.Args = []string{"run", "--allow-all", findDenoEntrypoint(projectDir)}
+		default:
+			setNodeScriptCommand(runtime, "start")
 		}
 
 	case "Logic Apps Standard":
@@ -106,20 +186,18 @@ func buildFrameworkCommand(runtime *ServiceRuntime, projectDir, runtimeMode stri
 		return buildDotNetCommand(runtime, projectDir, runtimeMode, false)
 
 	case frameworkSpringBoot:
-		buildJavaCommand(runtime, true)
+		buildJavaCommand(runtime, projectDir, true)
 		return nil
 
 	case langNameJava:
-		buildJavaCommand(runtime, false)
+		buildJavaCommand(runtime, projectDir, false)
 		return nil
 
 	case "Go":
-		runtime.Command = "go"
-		runtime.Args = []string{"run", "."}
+		return buildGoCommand(runtime, projectDir)
 
 	case langNameRust:
-		runtime.Command = "cargo"
-		runtime.Args = []string{"run"}
+		buildRustCommand(runtime, projectDir)
 
 	case "Laravel":
 		runtime.Command = "php"
@@ -136,7 +214,7 @@ func buildFrameworkCommand(runtime *ServiceRuntime, projectDir, runtimeMode stri
 	return nil
 }
 
-// buildDotNetCommand configures a .NET service runtime command.
+// buildDotNetCommand configures a .NET service runtime command, run with the SDK global.json pins.
 func buildDotNetCommand(runtime *ServiceRuntime, projectDir, runtimeMode string, isAspire bool) error {
 	runtime.Command = langDotnet
 
@@ -154,28 +232,151 @@ func buildDotNetCommand(runtime *ServiceRuntime, projectDir, runtimeMode string,
 	} else {
 		runtime.Args = []string{"run"}
 	}
-	return nil
+	return applyDotnetSDK(runtime, projectDir)
+}
+
+// buildGoCommand configures a Go service runtime command. When the module root isn't a main
+// package, it runs the main package under cmd/ named after the service, or the only one there.
+func buildGoCommand(runtime *ServiceRuntime, projectDir string) error {
+	runtime.Command = "go"
+	runtime.Args = []string{"run", "."}
+	if detector.IsGoMainPackage(projectDir) {
+		return nil
+	}
+
+	mains := detector.FindGoMainPackages(projectDir)
+	switch {
+	case len(mains) == 0:
+		return nil
+	case len(mains) == 1:
+		runtime.Args = []string{"run", "./" + mains[0]}
+		return nil
+	case slices.Contains(mains, "cmd/"+runtime.Name):
+		runtime.Args = []string{"run", "./cmd/" + runtime.Name}
+		return nil
+	default:
+		return fmt.Errorf("found multiple main packages (%s) - set 'command' in azure.yaml, e.g. \"go run ./%s\"",
+			strings.Join(mains, ", "), mains[0])
+	}
+}
+
+// buildRustCommand configures a Rust service runtime command. A virtual workspace manifest
+// runs the member package named after the service, or the only one in the workspace.
+// cargo run has no port flag, so Rocket apps get their port through ROCKET_PORT.
+func buildRustCommand(runtime *ServiceRuntime, projectDir string) {
+	runtime.Command = "cargo"
+	runtime.Args = []string{"run"}
+
+	if detector.IsCargoWorkspace(projectDir) && detector.CargoPackageName(projectDir) == "" {
+		packages := detector.FindCargoWorkspacePackages(projectDir)
+		switch {
+		case slices.Contains(packages, runtime.Name):
+			runtime.Args = []string{"run", "-p", runtime.Name}
+		case len(packages) == 1:
+			runtime.Args = []string{"run", "-p", packages[0]}
+		}
+	}
+
+	if runtime.Port > 0 && containsText(filepath.Join(projectDir, "Cargo.toml"), "rocket") {
+		runtime.Env["ROCKET_PORT"] = fmt.Sprintf("%d", runtime.Port)
+	}
 }
 
-// buildJavaCommand configures a Java service runtime command.
-func buildJavaCommand(runtime *ServiceRuntime, isSpringBoot bool) {
-	if runtime.PackageManager == "maven" {
-		runtime.Command = "mvn"
+// buildJavaCommand configures a Java service runtime command, using the project's mvnw or gradlew
+// wrapper when it has one.
+func buildJavaCommand(runtime *ServiceRuntime, projectDir string, isSpringBoot bool) {
+	command, args := detector.JavaBuildCommand(projectDir, runtime.PackageManager)
+	runtime.Command = command
+	if runtime.PackageManager == detector.BuildToolMaven {
 		if isSpringBoot {
-			runtime.Args = []string{"spring-boot:run"}
+			runtime.Args = append(args, "spring-boot:run")
 		} else {
-			runtime.Args = []string{"exec:java"}
+			runtime.Args = append(args, "exec:java")
 		}
 	} else {
-		runtime.Command = "gradle"
 		if isSpringBoot {
-			runtime.Args = []string{"bootRun"}
+			runtime.Args = append(args, "bootRun")
 		} else {
-			runtime.Args = []string{"run"}
+			runtime.Args = append(args, "run")
 		}
 	}
 }
 
+// uvLookPath finds uv, which runs the services of uv projects. This is a variable to allow test overrides.
+var uvLookPath = exec.LookPath
+
+// buildUvRunCommand runs the framework's default Python command with uv run, which syncs the
+// project's environment and runs the command in it, so the service needs no venv handling.
+func buildUvRunCommand(runtime *ServiceRuntime, projectDir string) error {
+	if err := buildPythonDefaultCommand(runtime, projectDir, "python"); err != nil {
+		return err
+	}
+	args := []string{"run"}
+	if runtime.PythonInterpreter != "" {
+		args = append(args, "--python", detector.ResolvePythonInterpreter(projectDir, runtime.PythonInterpreter))
+	}
+	runtime.Args = append(append(args, runtime.Command), runtime.Args...)
+	runtime.Command = packageMgrUv
+	return nil
+}
+
+// resolvePythonCommand returns the Python interpreter that runs the service: its
+// python.interpreter, else the interpreter of the project's virtual or conda environment, else
+// python on PATH. The environment of the interpreter is activated for the service, so the
+// tools it installed (e.g., uvicorn) are found first on PATH.
+func resolvePythonCommand(runtime *ServiceRuntime, projectDir string) string {
+	if runtime.Env == nil {
+		runtime.Env = make(map[string]string)
+	}
+
+	if runtime.PythonInterpreter != "" {
+		python := detector.ResolvePythonInterpreter(projectDir, runtime.PythonInterpreter)
+		if strings.ContainsAny(runtime.PythonInterpreter, `/\`) {
+			// An interpreter given by path runs with its own directory first on PATH
+			prependPath(runtime, filepath.Dir(python))
+		}
+		return python
+	}
+
+	if venvPython := getPythonVenvPath(projectDir); venvPython != "" {
+		// .venv/bin/python -> .venv
+		runtime.Env["VIRTUAL_ENV"] = filepath.Dir(filepath.Dir(venvPython))
+		prependPath(runtime, filepath.Dir(venvPython))
+		return venvPython
+	}
+
+	if condaPython := getPythonCondaPath(projectDir); condaPython != "" {
+		prefix := filepath.Join(projectDir, detector.CondaEnvDir)
+		runtime.Env["CONDA_PREFIX"] = prefix
+		if filepath.Dir(condaPython) == prefix {
+			// Windows conda environments keep python.exe in the prefix and their tools in Scripts
+			prependPath(runtime, prefix, filepath.Join(prefix, "Library", "bin"), filepath.Join(prefix, venvBinDirWindows))
+		} else {
+			prependPath(runtime, filepath.Dir(condaPython))
+		}
+		return condaPython
+	}
+
+	return "python"
+}
+
+// prependPath puts dirs before the inherited PATH of the service.
+func prependPath(runtime *ServiceRuntime, dirs ...string) {
+	// Windows spells it Path; keep the inherited name so the service doesn't get two
+	key := "PATH"
+	for _, entry := range os.Environ() {
+		if name, _, ok := strings.Cut(entry, "="); ok && strings.EqualFold(name, "PATH") {
+			key = name
+			break
+		}
+	}
+	current, ok := runtime.Env[key]
+	if !ok {
+		current = os.Getenv(key)
+	}
+	runtime.Env[key] = strings.Join(append(dirs, current), string(os.PathListSeparator))
+}
+
 // getPythonVenvPath returns the path to the Python interpreter in the virtual environment.
 // Returns empty string if no venv is found.
 func getPythonVenvPath(projectDir string) string {
@@ -196,6 +397,24 @@ func getPythonVenvPath(projectDir string) string {
 	return ""
 }
 
+// getPythonCondaPath returns the path to the Python interpreter in the conda environment that
+// azd app deps creates from environment.yml. Returns empty string if there is none.
+func getPythonCondaPath(projectDir string) string {
+	prefix := filepath.Join(projectDir, detector.CondaEnvDir)
+	condaPaths := []string{
+		filepath.Join(prefix, pythonExeWindows),              // Windows
+		filepath.Join(prefix, venvBinDirUnix, pythonExeUnix), // Linux/macOS
+	}
+
+	for _, path := range condaPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return ""
+}
+
 // resolvePythonEntrypoint resolves and validates the Python entrypoint file.
 // Returns the auto-detected entrypoint filename.
 func resolvePythonEntrypoint(projectDir, _ string) (string, error) {
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestBuildFrameworkCommand_Uv(t *testing.T) {
+	tests := []struct {
+		name        string
+		uvInstalled bool
+		interpreter string
+		wantCommand string
+		wantArgs    []string // leading arguments
+	}{
+		{"uv run", true, "", "uv", []string{"run", "python", "-m", "uvicorn", "main:app"}},
+		{"uv run with interpreter", true, "python3.12", "uv", []string{"run", "--python", "python3.12", "python", "-m", "uvicorn"}},
+		{"uv not installed", false, "", "python", []string{"-m", "uvicorn", "main:app"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origLookPath := uvLookPath
+			defer func() { uvLookPath = origLookPath }()
+			uvLookPath = func(file string) (string, error) {
+				if tt.uvInstalled {
+					return "/usr/bin/" + file, nil
+				}
+				return "", errors.New("not found")
+			}
+
+			projectDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(projectDir, "main.py"), []byte("from fastapi import FastAPI\napp = FastAPI()"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			runtime := &ServiceRuntime{
+				Framework:         "FastAPI",
+				PackageManager:    packageMgrUv,
+				Port:              8000,
+				Env:               make(map[string]string),
+				PythonInterpreter: tt.interpreter,
+			}
+
+			if err := buildFrameworkCommand(runtime, projectDir, ""); err != nil {
+				t.Fatalf("buildFrameworkCommand() error = %v", err)
+			}
+			if runtime.Command != tt.wantCommand || len(runtime.Args) < len(tt.wantArgs) || !slices.Equal(runtime.Args[:len(tt.wantArgs)], tt.wantArgs) {
+				t.Errorf("command = %s %v, want %s %v...", runtime.Command, runtime.Args, tt.wantCommand, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-core/security"
+)
+
+// defaultDockerfile is the Dockerfile name used when docker.path is not set.
+const defaultDockerfile = "Dockerfile"
+
+// detectDockerfileRuntime creates a ServiceRuntime for a service that is built from a Dockerfile
+// (typically host: containerapp with no language-specific project files). The image is built
+// locally and then run like any other container service.
+func detectDockerfileRuntime(serviceName string, service Service, projectDir string, usedPorts map[int]bool, azureYamlDir string) (*ServiceRuntime, error) {
+	build, err := resolveContainerBuild(service, projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("service %s: %w", serviceName, err)
+	}
+
+	defaultHealthCheckType := "tcp"
+	if service.IsHealthcheckDisabled() {
+		defaultHealthCheckType = watchModeNone
+	} else if service.Healthcheck != nil && service.Healthcheck.Type != "" {
+		defaultHealthCheckType = service.Healthcheck.Type
+	}
+
+	runtime := &ServiceRuntime{
+		Name:       serviceName,
+		WorkingDir: projectDir,
+		Protocol:   "tcp",
+		Env:        make(map[string]string),
+		Type:       ServiceTypeContainer,
+		Build:      build,
+		HealthCheck: HealthCheckConfig{
+			Type:     defaultHealthCheckType,
+			Path:     "/",
+			Timeout:  60 * time.Second,
+			Interval: 2 * time.Second,
+		},
+	}
+
+	// The built image is stored in Command, like the image of other container services
+	runtime.Command = localImageName(serviceName)
+	runtime.Language = "container"
+	runtime.Framework = packageMgrDocker
+
+	// Container port: first configured port, otherwise the Dockerfile's first EXPOSE
+	hostPort, containerPort, isExplicit := 0, 0, false
+	if service.NeedsPort() {
+		hostPort, containerPort, isExplicit = service.GetPrimaryPort()
+	} else {
+		containerPort = dockerfileExposedPort(build.Dockerfile)
+	}
+
+	if containerPort == 0 {
+		// Nothing to connect to, so only the container's own state can be checked
+		if runtime.HealthCheck.Type == "tcp" || runtime.HealthCheck.Type == ServiceTypeHTTP {
+			runtime.HealthCheck.Type = watchModeNone
+		}
+		if runtime.HealthCheck.Type != watchModeNone {
+			applyHealthcheck(&runtime.HealthCheck, service.Healthcheck)
+		}
+		return runtime, nil
+	}
+
+	if hostPort == 0 {
+		assignedPort, shouldUpdate, err := assignServicePort(azureYamlDir, serviceName, service, containerPort, isExplicit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to assign port for container: %w", err)
+		}
+		hostPort = assignedPort
+		runtime.ShouldUpdateAzureYaml = shouldUpdate
+	}
+
+	runtime.Port = hostPort
+	runtime.ContainerPort = containerPort
+	runtime.HealthCheck.Port = hostPort
+	usedPorts[hostPort] = true
+	applyHealthcheck(&runtime.HealthCheck, service.Healthcheck)
+
+	// Tell the app which port to listen on unless the service sets it explicitly
+	if _, ok := runtime.Env["PORT"]; !ok {
+		runtime.Env["PORT"] = strconv.Itoa(containerPort)
+	}
+
+	return runtime, nil
+}
+
+// resolveContainerBuild resolves the Dockerfile and build context of a service.
+// docker.path and docker.context are relative to the service project directory, as in azd.
+func resolveContainerBuild(service Service, projectDir string) (*ContainerBuild, error) {
+	build := &ContainerBuild{
+		Dockerfile: filepath.Join(projectDir, defaultDockerfile),
+		Context:    projectDir,
+	}
+
+	if service.Docker != nil {
+		if service.Docker.Path != "" {
+			build.Dockerfile = resolveServicePath(projectDir, service.Docker.Path)
+		}
+		if service.Docker.Context != "" {
+			build.Context = resolveServicePath(projectDir, service.Docker.Context)
+		}
+		build.BuildArgs = service.Docker.BuildArgs
+		build.Platform = service.Docker.Platform
+	}
+
+	if err := security.ValidatePath(build.Dockerfile); err != nil {
+		return nil, fmt.Errorf("invalid Dockerfile path: %w", err)
+	}
+	if err := security.ValidatePath(build.Context); err != nil {
+		return nil, fmt.Errorf("invalid docker build context: %w", err)
+	}
+	if _, err := os.Stat(build.Dockerfile); err != nil {
+		return nil, fmt.Errorf("dockerfile not found at %s", build.Dockerfile)
+	}
+
+	return build, nil
+}
+
+// resolveServicePath resolves a path from the service configuration against the project directory.
+func resolveServicePath(projectDir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(projectDir, path)
+}
+
+// localImageName returns the tag used for locally built service images.
+// Image names must be lowercase, while service names may contain uppercase letters.
+func localImageName(serviceName string) string {
+	return fmt.Sprintf("azd-%s:local", strings.ToLower(serviceName))
+}
+
+// dockerfileExposedPort returns the first TCP port declared by an EXPOSE instruction,
+// or 0 if there is none. Ports given as build variables (e.g. EXPOSE $PORT) are skipped.
+func dockerfileExposedPort(dockerfilePath string) int {
+	// #nosec G304 -- Path validated by resolveContainerBuild
+	file, err := os.Open(dockerfilePath)
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "EXPOSE") {
+			continue
+		}
+		for _, spec := range fields[1:] {
+			portStr, protocol, _ := strings.Cut(spec, "/")
+			if protocol != "" && !strings.EqualFold(protocol, "tcp") {
+				continue
+			}
+			if port, err := strconv.Atoi(portStr); err == nil && port > 0 && port <= 65535 {
+				return port
+			}
+		}
+	}
+	return 0
+}
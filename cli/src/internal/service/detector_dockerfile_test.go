@@ -0,0 +1,95 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDockerfileExposedPort(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		expected   int
+	}{
+		{"single port", "FROM nginx\nEXPOSE 80\n", 80},
+		{"port with protocol", "FROM node:20\nexpose 3000/tcp\n", 3000},
+		{"udp port skipped", "FROM alpine\nEXPOSE 53/udp 8080\n", 8080},
+		{"variable port skipped", "FROM alpine\nARG PORT\nEXPOSE $PORT\n", 0},
+		{"no expose", "FROM alpine\nCMD [\"sleep\", \"infinity\"]\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Dockerfile")
+			if err := os.WriteFile(path, []byte(tt.dockerfile), 0600); err != nil {
+				t.Fatalf("failed to write Dockerfile: %v", err)
+			}
+			if got := dockerfileExposedPort(path); got != tt.expected {
+				t.Errorf("dockerfileExposedPort() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetectServiceRuntime_DockerfileOnly(t *testing.T) {
+	t.Setenv("AZD_APP_STATE_BACKEND", "memory")
+
+	tmpDir := t.TempDir()
+	apiDir := filepath.Join(tmpDir, "api")
+	if err := os.MkdirAll(apiDir, 0750); err != nil {
+		t.Fatalf("failed to create service dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(apiDir, "Dockerfile.dev"), []byte("FROM nginx\nEXPOSE 80\n"), 0600); err != nil {
+		t.Fatalf("failed to write Dockerfile: %v", err)
+	}
+
+	svc := Service{
+		Host:    "containerapp",
+		Project: "./api",
+		Docker: &DockerConfig{
+			Path:      "Dockerfile.dev",
+			Context:   "..",
+			BuildArgs: []string{"VERSION=1"},
+		},
+	}
+
+	runtime, err := DetectServiceRuntime("Api", svc, map[int]bool{}, tmpDir, "azd")
+	if err != nil {
+		t.Fatalf("DetectServiceRuntime() error = %v", err)
+	}
+
+	if runtime.Type != ServiceTypeContainer {
+		t.Errorf("Type = %q, want %q", runtime.Type, ServiceTypeContainer)
+	}
+	if runtime.Command != "azd-api:local" {
+		t.Errorf("Command (image) = %q, want %q", runtime.Command, "azd-api:local")
+	}
+	if runtime.Build == nil {
+		t.Fatal("Build is nil, want Dockerfile build settings")
+	}
+	if runtime.Build.Dockerfile != filepath.Join(apiDir, "Dockerfile.dev") {
+		t.Errorf("Build.Dockerfile = %q, want %q", runtime.Build.Dockerfile, filepath.Join(apiDir, "Dockerfile.dev"))
+	}
+	if runtime.Build.Context != tmpDir {
+		t.Errorf("Build.Context = %q, want %q", runtime.Build.Context, tmpDir)
+	}
+	if runtime.ContainerPort != 80 {
+		t.Errorf("ContainerPort = %d, want 80", runtime.ContainerPort)
+	}
+	if runtime.Port == 0 {
+		t.Error("Port = 0, want an assigned host port")
+	}
+	if runtime.Env["PORT"] != "80" {
+		t.Errorf("Env[PORT] = %q, want %q", runtime.Env["PORT"], "80")
+	}
+}
+
+func TestDetectServiceRuntime_DockerfileMissing(t *testing.T) {
+	tmpDir := t.TempDir()
+	svc := Service{Host: "containerapp", Project: ".", Language: "docker"}
+
+	if _, err := DetectServiceRuntime("api", svc, map[int]bool{}, tmpDir, "azd"); err == nil {
+		t.Error("DetectServiceRuntime() error = nil, want missing Dockerfile error")
+	}
+}
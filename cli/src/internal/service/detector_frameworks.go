@@ -10,6 +10,11 @@ import (
 const (
 	frameworkDocker    = "Docker"
 	packageMgrDocker   = "docker"
+	packageMgrDeno     = "deno"
+	packageMgrBun      = "bun"
+	packageMgrPnpm     = "pnpm"
+	packageMgrYarn     = "yarn"
+	packageMgrUv       = "uv"
 	langNameJavaScript = "JavaScript"
 	langTypeScript     = "TypeScript"
 	langNamePython     = "Python"
@@ -34,11 +39,16 @@ func detectLanguage(projectDir string, host string) (string, error) {
 		{langNameJavaScript, func() bool {
 			return fileExists(projectDir, "package.json")
 		}},
+		{langTypeScript, func() bool {
+			// Deno projects without package.json run TypeScript natively
+			return detector.HasDenoConfig(projectDir)
+		}},
 		{langNamePython, func() bool {
 			return fileExists(projectDir, "requirements.txt") ||
 				fileExists(projectDir, "pyproject.toml") ||
 				fileExists(projectDir, "poetry.lock") ||
-				fileExists(projectDir, "uv.lock")
+				fileExists(projectDir, "uv.lock") ||
+				detector.CondaEnvironmentFile(projectDir) != ""
 		}},
 		{langNameDotNet, func() bool {
 			return hasFileWithExt(projectDir, ".csproj") ||
@@ -73,6 +83,21 @@ func detectLanguage(projectDir string, host string) (string, error) {
 	return "", errCouldNotDetectLanguage(projectDir)
 }
 
+// DetectFramework detects the language, framework and package manager of the project in projectDir
+// without assigning ports or building a full runtime. language may be empty to detect it.
+func DetectFramework(projectDir, language, host string) (string, string, string, error) {
+	if language == "" {
+		detected, err := detectLanguage(projectDir, host)
+		if err != nil {
+			return "", "", "", err
+		}
+		language = detected
+	}
+	language = normalizeLanguage(language)
+	framework, packageManager, err := detectFrameworkAndPackageManager(projectDir, language)
+	return language, framework, packageManager, err
+}
+
 // detectFrameworkAndPackageManager detects the specific framework and package manager.
 func detectFrameworkAndPackageManager(projectDir string, language string) (string, string, error) {
 	switch language {
@@ -191,9 +216,9 @@ func detectDotNetFramework(projectDir string) (string, string, error) {
 
 // detectJavaFramework detects Java framework.
 func detectJavaFramework(projectDir string) (string, string, error) {
-	packageManager := "maven"
-	if fileExists(projectDir, "build.gradle") || fileExists(projectDir, "build.gradle.kts") {
-		packageManager = "gradle"
+	packageManager := detector.BuildToolMaven
+	if detector.DetectJavaBuildTool(projectDir) == detector.BuildToolGradle {
+		packageManager = detector.BuildToolGradle
 	}
 
 	// Check for Spring Boot in pom.xml
@@ -66,9 +66,24 @@ func detectFrameworkFromPackageJSON(projectDir string) string {
 	return ""
 }
 
+// hasScript checks package.json scripts, and deno.json/deno.jsonc tasks for Deno projects.
 func hasScript(projectDir string, scriptName string) bool {
-	packageJSONPath := filepath.Join(projectDir, "package.json")
-	return containsText(packageJSONPath, fmt.Sprintf(`"%s"`, scriptName))
+	for _, filename := range []string{"package.json", "deno.json", "deno.jsonc"} {
+		if containsText(filepath.Join(projectDir, filename), fmt.Sprintf(`"%s"`, scriptName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// findDenoEntrypoint returns the first common Deno entry point in projectDir, or "main.ts".
+func findDenoEntrypoint(projectDir string) string {
+	for _, filename := range []string{"main.ts", "main.js", "mod.ts", "server.ts", "src/main.ts"} {
+		if fileExists(projectDir, filename) {
+			return filename
+		}
+	}
+	return "main.ts"
 }
 
 func findPythonAppFile(projectDir string) string {
@@ -3,6 +3,7 @@ package service_test
 import (
 	"os"
 	"path/filepath"
+	goruntime "runtime"
 	"strings"
 	"testing"
 
@@ -649,6 +650,35 @@ func TestGoServiceDetection(t *testing.T) {
 			expectedArgs:    []string{"run", "./cmd/worker"},
 			expectError:     false,
 		},
+		{
+			name: "Go service with single cmd main package",
+			projectFiles: map[string]string{
+				"go.mod":              "module example.com/app\n\ngo 1.21",
+				"cmd/server/main.go":  "package main\n\nfunc main() {}",
+				"internal/lib/lib.go": "package lib",
+			},
+			expectedCommand: "go",
+			expectedArgs:    []string{"run", "./cmd/server"},
+		},
+		{
+			name: "Go service picks cmd main package named after service",
+			projectFiles: map[string]string{
+				"go.mod":             "module example.com/app\n\ngo 1.21",
+				"cmd/api/main.go":    "package main\n\nfunc main() {}",
+				"cmd/worker/main.go": "package main\n\nfunc main() {}",
+			},
+			expectedCommand: "go",
+			expectedArgs:    []string{"run", "./cmd/api"},
+		},
+		{
+			name: "Go service with ambiguous cmd main packages",
+			projectFiles: map[string]string{
+				"go.mod":              "module example.com/app\n\ngo 1.21",
+				"cmd/migrate/main.go": "package main\n\nfunc main() {}",
+				"cmd/worker/main.go":  "package main\n\nfunc main() {}",
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -731,6 +761,431 @@ services:
 	}
 }
 
+func TestRustServiceDetection(t *testing.T) {
+	tests := []struct {
+		name           string
+		projectFiles   map[string]string
+		expectedArgs   []string
+		wantRocketPort bool
+	}{
+		{
+			name: "single crate",
+			projectFiles: map[string]string{
+				"Cargo.toml":  "[package]\nname = \"api\"\n\n[dependencies]\naxum = \"0.7\"",
+				"src/main.rs": "fn main() {}",
+			},
+			expectedArgs: []string{"run"},
+		},
+		{
+			name: "virtual workspace runs package named after service",
+			projectFiles: map[string]string{
+				"Cargo.toml":               "[workspace]\nmembers = [\"crates/*\"]",
+				"crates/api/Cargo.toml":    "[package]\nname = \"api\"",
+				"crates/worker/Cargo.toml": "[package]\nname = \"worker\"",
+				"target/debug/Cargo.toml":  "[package]\nname = \"stale\"",
+			},
+			expectedArgs: []string{"run", "-p", "api"},
+		},
+		{
+			name: "virtual workspace with one package",
+			projectFiles: map[string]string{
+				"Cargo.toml":            "[workspace]\nmembers = [\"server\"]",
+				"server/Cargo.toml":     "[package]\nname = \"server\"",
+				"server/src/main.rs":    "fn main() {}",
+				"server/src/routes.rs":  "",
+				"server/tests/basic.rs": "",
+			},
+			expectedArgs: []string{"run", "-p", "server"},
+		},
+		{
+			name: "rocket gets ROCKET_PORT",
+			projectFiles: map[string]string{
+				"Cargo.toml": "[package]\nname = \"api\"\n\n[dependencies]\nrocket = \"0.5\"",
+			},
+			expectedArgs:   []string{"run"},
+			wantRocketPort: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for filename, content := range tt.projectFiles {
+				filePath := filepath.Join(tmpDir, filename)
+				if err := os.MkdirAll(filepath.Dir(filePath), 0750); err != nil {
+					t.Fatalf("Failed to create directory for %s: %v", filename, err)
+				}
+				if err := os.WriteFile(filePath, []byte(content), 0600); err != nil {
+					t.Fatalf("Failed to create file %s: %v", filename, err)
+				}
+			}
+
+			svc := service.Service{Project: tmpDir, Language: "rust", Ports: []string{"8080"}}
+			runtime, err := service.DetectServiceRuntime("api", svc, map[int]bool{}, tmpDir, "azd")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if runtime.Command != "cargo" || strings.Join(runtime.Args, " ") != strings.Join(tt.expectedArgs, " ") {
+				t.Errorf("command = %s %v, want cargo %v", runtime.Command, runtime.Args, tt.expectedArgs)
+			}
+			if port, ok := runtime.Env["ROCKET_PORT"]; ok != tt.wantRocketPort || (ok && port != "8080") {
+				t.Errorf("ROCKET_PORT = %q (set: %v), want set: %v", port, ok, tt.wantRocketPort)
+			}
+		})
+	}
+}
+
+func TestNodeWorkspaceServiceDetection(t *testing.T) {
+	tests := []struct {
+		name                   string
+		projectFiles           map[string]string
+		project                string
+		expectedPackageManager string
+		expectedCommand        string
+		expectedArgs           []string
+	}{
+		{
+			name: "turbo monorepo runs script through turbo",
+			projectFiles: map[string]string{
+				"package.json":          `{"workspaces": ["apps/*"]}`,
+				"package-lock.json":     "{}",
+				"turbo.json":            `{"tasks": {"dev": {"dependsOn": ["^build"]}}}`,
+				"apps/web/package.json": `{"name": "web", "scripts": {"dev": "node server.js"}}`,
+			},
+			project:                "apps/web",
+			expectedPackageManager: "npm",
+			expectedCommand:        "npx",
+			expectedArgs:           []string{"--no", "turbo", "run", "dev", "--filter=web"},
+		},
+		{
+			name: "nx monorepo runs project target",
+			projectFiles: map[string]string{
+				"package.json":          `{"name": "repo"}`,
+				"bun.lockb":             "",
+				"nx.json":               "{}",
+				"apps/api/package.json": `{"name": "@repo/api", "scripts": {"dev": "node server.js"}}`,
+				"apps/api/project.json": `{"name": "api"}`,
+			},
+			project:                "apps/api",
+			expectedPackageManager: "bun",
+			expectedCommand:        "bunx",
+			expectedArgs:           []string{"nx", "run", "api:dev"},
+		},
+		{
+			name: "workspace package uses root package manager",
+			projectFiles: map[string]string{
+				"package.json":              `{"workspaces": ["packages/*"]}`,
+				"bun.lockb":                 "",
+				"packages/web/package.json": `{"name": "web", "scripts": {"dev": "bun --watch index.ts"}}`,
+			},
+			project:                "packages/web",
+			expectedPackageManager: "bun",
+			expectedCommand:        "bun",
+			expectedArgs:           []string{"run", "dev"},
+		},
+		{
+			name: "package outside workspace globs is standalone",
+			projectFiles: map[string]string{
+				"package.json":           `{"workspaces": ["packages/*"]}`,
+				"bun.lockb":              "",
+				"turbo.json":             "{}",
+				"tools/web/package.json": `{"name": "web", "scripts": {"dev": "node server.js"}}`,
+			},
+			project:                "tools/web",
+			expectedPackageManager: "npm",
+			expectedCommand:        "npm",
+			expectedArgs:           []string{"run", "dev"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for filename, content := range tt.projectFiles {
+				filePath := filepath.Join(tmpDir, filename)
+				if err := os.MkdirAll(filepath.Dir(filePath), 0750); err != nil {
+					t.Fatalf("Failed to create directory for %s: %v", filename, err)
+				}
+				if err := os.WriteFile(filePath, []byte(content), 0600); err != nil {
+					t.Fatalf("Failed to create file %s: %v", filename, err)
+				}
+			}
+
+			svc := service.Service{Project: tt.project, Language: "js", Ports: []string{"3000"}}
+			runtime, err := service.DetectServiceRuntime("web", svc, map[int]bool{}, tmpDir, "azd")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if runtime.PackageManager != tt.expectedPackageManager {
+				t.Errorf("Expected package manager %q, got %q", tt.expectedPackageManager, runtime.PackageManager)
+			}
+			if runtime.Command != tt.expectedCommand || strings.Join(runtime.Args, " ") != strings.Join(tt.expectedArgs, " ") {
+				t.Errorf("command = %s %v, want %s %v", runtime.Command, runtime.Args, tt.expectedCommand, tt.expectedArgs)
+			}
+			if runtime.WorkingDir != filepath.Join(tmpDir, tt.project) {
+				t.Errorf("WorkingDir = %s, want the package directory", runtime.WorkingDir)
+			}
+		})
+	}
+}
+
+func TestJavaServiceDetection(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("wrapper scripts are mvnw.cmd and gradlew.bat on Windows")
+	}
+
+	tests := []struct {
+		name            string
+		projectFiles    map[string]string
+		executable      string
+		expectedCommand string
+		expectedArgs    []string
+	}{
+		{
+			name:            "spring boot with maven",
+			projectFiles:    map[string]string{"pom.xml": "<artifactId>spring-boot-starter-web</artifactId>"},
+			expectedCommand: "mvn",
+			expectedArgs:    []string{"spring-boot:run"},
+		},
+		{
+			name:            "spring boot with maven wrapper",
+			projectFiles:    map[string]string{"pom.xml": "<artifactId>spring-boot-starter-web</artifactId>", "mvnw": "#!/bin/sh"},
+			executable:      "mvnw",
+			expectedCommand: "mvnw",
+			expectedArgs:    []string{"spring-boot:run"},
+		},
+		{
+			name:            "gradle wrapper without executable bit",
+			projectFiles:    map[string]string{"settings.gradle": "rootProject.name = 'api'", "gradlew": "#!/bin/sh"},
+			expectedCommand: "sh",
+			expectedArgs:    []string{"gradlew", "run"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for filename, content := range tt.projectFiles {
+				if err := os.WriteFile(filepath.Join(tmpDir, filename), []byte(content), 0600); err != nil {
+					t.Fatalf("Failed to create file %s: %v", filename, err)
+				}
+			}
+			if tt.executable != "" {
+				if err := os.Chmod(filepath.Join(tmpDir, tt.executable), 0700); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			svc := service.Service{Project: tmpDir, Language: "java", Ports: []string{"8080"}}
+			runtime, err := service.DetectServiceRuntime("api", svc, map[int]bool{}, tmpDir, "azd")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// Wrappers run by absolute path; compare base names
+			args := make([]string, len(runtime.Args))
+			for i, arg := range runtime.Args {
+				args[i] = filepath.Base(arg)
+			}
+			if filepath.Base(runtime.Command) != tt.expectedCommand || strings.Join(args, " ") != strings.Join(tt.expectedArgs, " ") {
+				t.Errorf("command = %s %v, want %s %v", runtime.Command, runtime.Args, tt.expectedCommand, tt.expectedArgs)
+			}
+		})
+	}
+}
+
+func TestBunAndDenoServiceDetection(t *testing.T) {
+	tests := []struct {
+		name                   string
+		projectFiles           map[string]string
+		expectedPackageManager string
+		expectedCommand        string
+		expectedArgs           []string
+	}{
+		{
+			name: "Bun project with bun.lockb",
+			projectFiles: map[string]string{
+				"package.json": `{"scripts": {"dev": "bun --watch index.ts"}}`,
+				"bun.lockb":    "",
+			},
+			expectedPackageManager: "bun",
+			expectedCommand:        "bun",
+			expectedArgs:           []string{"run", "dev"},
+		},
+		{
+			name: "Bun project from packageManager field",
+			projectFiles: map[string]string{
+				"package.json": `{"packageManager": "bun@1.1.38", "scripts": {"start": "bun index.ts"}}`,
+			},
+			expectedPackageManager: "bun",
+			expectedCommand:        "bun",
+			expectedArgs:           []string{"run", "start"},
+		},
+		{
+			name: "Deno project with dev task",
+			projectFiles: map[string]string{
+				"deno.json": `{"tasks": {"dev": "deno run --watch -A main.ts"}}`,
+				"main.ts":   "Deno.serve(() => new Response('ok'))",
+			},
+			expectedPackageManager: "deno",
+			expectedCommand:        "deno",
+			expectedArgs:           []string{"task", "dev"},
+		},
+		{
+			name: "Deno project without tasks",
+			projectFiles: map[string]string{
+				"deno.jsonc": `{"imports": {}}`,
+				"server.ts":  "Deno.serve(() => new Response('ok'))",
+			},
+			expectedPackageManager: "deno",
+			expectedCommand:        "deno",
+			expectedArgs:           []string{"run", "--allow-all", "server.ts"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for filename, content := range tt.projectFiles {
+				if err := os.WriteFile(filepath.Join(tmpDir, filename), []byte(content), 0600); err != nil {
+					t.Fatalf("Failed to create file %s: %v", filename, err)
+				}
+			}
+
+			azureYamlContent := `name: test-runtime-app
+services:
+  api:
+    project: .
+    ports:
+      - "8080"`
+			azureYamlPath := filepath.Join(tmpDir, "azure.yaml")
+			if err := os.WriteFile(azureYamlPath, []byte(azureYamlContent), 0600); err != nil {
+				t.Fatalf("Failed to create azure.yaml: %v", err)
+			}
+
+			azureYaml, err := service.ParseAzureYaml(azureYamlPath)
+			if err != nil {
+				t.Fatalf("Failed to parse azure.yaml: %v", err)
+			}
+
+			runtime, err := service.DetectServiceRuntime("api", azureYaml.Services["api"], map[int]bool{}, tmpDir, "azd")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if runtime.PackageManager != tt.expectedPackageManager {
+				t.Errorf("Expected package manager %q, got %q", tt.expectedPackageManager, runtime.PackageManager)
+			}
+			if runtime.Command != tt.expectedCommand {
+				t.Errorf("Expected command %q, got %q", tt.expectedCommand, runtime.Command)
+			}
+			if strings.Join(runtime.Args, " ") != strings.Join(tt.expectedArgs, " ") {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, runtime.Args)
+			}
+		})
+	}
+}
+
+func TestServiceEnvMergedIntoRuntime(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"api/go.mod":     "module example.com/api\n\ngo 1.21",
+		"api/main.go":    "package main\n\nfunc main() {}",
+		"config/api.env": "FROM_FILE=file\nLOG_LEVEL=file\nFEATURE=file\n",
+		"azure.yaml": `name: test-env
+services:
+  api:
+    project: ./api
+    language: go
+    envFile: ./config/api.env
+    environment:
+      LOG_LEVEL: info
+      FEATURE: environment
+    env:
+      FEATURE: env
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to create file %s: %v", name, err)
+		}
+	}
+
+	azureYaml, err := service.ParseAzureYaml(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to parse azure.yaml: %v", err)
+	}
+
+	runtime, err := service.DetectServiceRuntime("api", azureYaml.Services["api"], map[int]bool{}, tmpDir, "azd")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := map[string]string{"FROM_FILE": "file", "LOG_LEVEL": "info", "FEATURE": "env"}
+	for key, value := range want {
+		if runtime.Env[key] != value {
+			t.Errorf("Env[%s] = %q, want %q", key, runtime.Env[key], value)
+		}
+	}
+
+	// A missing envFile is an error rather than silently starting without it
+	svc := azureYaml.Services["api"]
+	svc.EnvFile = filepath.Join(tmpDir, "missing.env")
+	if _, err := service.DetectServiceRuntime("api", svc, map[int]bool{}, tmpDir, "azd"); err == nil {
+		t.Error("Expected error for missing envFile")
+	}
+}
+
+func TestExplicitRunCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	// A Go module would otherwise be detected and run with `go run .`
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/api\n\ngo 1.21"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyDir := t.TempDir()
+
+	tests := []struct {
+		name            string
+		svc             service.Service
+		expectedCommand string
+		expectedArgs    []string
+	}{
+		{
+			name:            "run overrides detected command",
+			svc:             service.Service{Project: tmpDir, Language: "go", Run: "air -c .air.toml", Command: "ignored", Entrypoint: "ignored"},
+			expectedCommand: "air",
+			expectedArgs:    []string{"-c", ".air.toml"},
+		},
+		{
+			name:            "run without a detectable language",
+			svc:             service.Service{Project: emptyDir, Run: "npm run dev:api", Build: "npm run build", PreRun: &service.Hook{Run: "npm run migrate"}},
+			expectedCommand: "npm",
+			expectedArgs:    []string{"run", "dev:api"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime, err := service.DetectServiceRuntime("api", tt.svc, map[int]bool{}, tt.svc.Project, "azd")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if runtime.Command != tt.expectedCommand || strings.Join(runtime.Args, " ") != strings.Join(tt.expectedArgs, " ") {
+				t.Errorf("command = %s %v, want %s %v", runtime.Command, runtime.Args, tt.expectedCommand, tt.expectedArgs)
+			}
+			if runtime.BuildCommand != tt.svc.Build || runtime.PreRun != tt.svc.PreRun {
+				t.Errorf("build = %q, preRun = %+v, want %q, %+v", runtime.BuildCommand, runtime.PreRun, tt.svc.Build, tt.svc.PreRun)
+			}
+		})
+	}
+}
+
 func TestGoWorkerServiceWithProcessHealthcheck(t *testing.T) {
 	// Create temporary project directory
 	tmpDir := t.TempDir()
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+)
+
+// applyDotnetSDK makes a .NET service run with the SDK its global.json pins. When the dotnet on
+// PATH has no SDK that satisfies the pin, but another .NET installation does, the service runs
+// with that installation's dotnet instead.
+func applyDotnetSDK(rt *ServiceRuntime, projectDir string) error {
+	pin, ok := detector.FindGlobalJSON(projectDir)
+	if !ok {
+		return nil
+	}
+	root, _, ok := detector.FindDotnetSDK(pin)
+	if !ok {
+		return fmt.Errorf("no installed .NET SDK matches %s required by %s", pin.Requirement(), pin.Path)
+	}
+	if root == detector.DotnetPathRoot() {
+		return nil
+	}
+
+	executable := langDotnet
+	if runtime.GOOS == "windows" {
+		executable += ".exe"
+	}
+	rt.Command = filepath.Join(root, executable)
+	if rt.Env == nil {
+		rt.Env = make(map[string]string)
+	}
+	rt.Env["DOTNET_ROOT"] = root
+	return nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestApplyDotnetSDK(t *testing.T) {
+	dotnetRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dotnetRoot, "sdk", "8.0.303"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("DOTNET_ROOT", dotnetRoot)
+
+	tests := []struct {
+		name        string
+		globalJSON  string // "" = no global.json
+		wantCommand string
+		wantErr     bool
+	}{
+		{name: "no global.json", wantCommand: langDotnet},
+		{name: "SDK in DOTNET_ROOT", globalJSON: `{"sdk": {"version": "8.0.100", "rollForward": "latestFeature"}}`, wantCommand: "root"},
+		{name: "no matching SDK", globalJSON: `{"sdk": {"version": "99.0.100"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			if tt.globalJSON != "" {
+				if err := os.WriteFile(filepath.Join(projectDir, "global.json"), []byte(tt.globalJSON), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			rt := &ServiceRuntime{Command: langDotnet, Args: []string{"run"}}
+			err := applyDotnetSDK(rt, projectDir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyDotnetSDK() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			wantCommand := tt.wantCommand
+			if wantCommand == "root" {
+				wantCommand = filepath.Join(dotnetRoot, langDotnet)
+				if runtime.GOOS == "windows" {
+					wantCommand += ".exe"
+				}
+				if rt.Env["DOTNET_ROOT"] != dotnetRoot {
+					t.Errorf("DOTNET_ROOT = %q, want %q", rt.Env["DOTNET_ROOT"], dotnetRoot)
+				}
+			}
+			if rt.Command != wantCommand {
+				t.Errorf("Command = %q, want %q", rt.Command, wantCommand)
+			}
+		})
+	}
+}
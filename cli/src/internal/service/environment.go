@@ -4,7 +4,6 @@ package service
 import (
 	"bufio"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -200,7 +199,7 @@ func InjectFunctionsWorkerRuntime(env map[string]string, runtime *ServiceRuntime
 	}
 
 	// Check if there's a local.settings.json to read settings from
-	localSettingsPath := filepath.Join(runtime.WorkingDir, "local.settings.json")
+	localSettingsPath := filepath.Join(runtime.WorkingDir, localSettingsFileName)
 	if settings := loadLocalSettings(localSettingsPath); settings != nil {
 		// Inject missing settings from local.settings.json
 		for key, value := range settings {
@@ -228,21 +227,11 @@ func InjectFunctionsWorkerRuntime(env map[string]string, runtime *ServiceRuntime
 
 // loadLocalSettings reads all Values from local.settings.json.
 func loadLocalSettings(path string) map[string]string {
-	// #nosec G304 -- Path is constructed from validated runtime.WorkingDir
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil
-	}
-
-	var config struct {
-		Values map[string]string `json:"Values"`
-	}
-
-	if err := json.Unmarshal(data, &config); err != nil {
+	settings := readLocalSettings(path)
+	if settings == nil {
 		return nil
 	}
-
-	return config.Values
+	return settings.values()
 }
 
 // GenerateServiceURLs creates auto-generated environment variables for service URLs.
@@ -270,6 +259,30 @@ func GenerateServiceURLs(processes map[string]*ServiceProcess) map[string]string
 	return urls
 }
 
+// LoadServiceEnvironment returns the environment variables azure.yaml defines for a service:
+// the variables of its envFile, overridden by environment, overridden by env.
+// A relative envFile is resolved against azureYamlDir.
+func LoadServiceEnvironment(service Service, azureYamlDir string) (map[string]string, error) {
+	env := make(map[string]string)
+	if service.EnvFile != "" {
+		envFile := service.EnvFile
+		if !filepath.IsAbs(envFile) {
+			envFile = filepath.Join(azureYamlDir, envFile)
+		}
+		fileEnv, err := LoadDotEnv(envFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load envFile: %w", err)
+		}
+		for k, v := range fileEnv {
+			env[k] = v
+		}
+	}
+	for k, v := range service.GetEnvironment() {
+		env[k] = v
+	}
+	return env, nil
+}
+
 // LoadDotEnv loads environment variables from a .env file.
 func LoadDotEnv(path string) (map[string]string, error) {
 	if err := security.ValidatePath(path); err != nil {
@@ -344,14 +357,19 @@ func isValidEnvVarName(name string) bool {
 }
 
 // substituteEnvVars performs variable substitution in a string.
-// Supports ${VAR} and $VAR syntax.
+// Supports ${VAR}, ${VAR:-default} and $VAR syntax.
 func substituteEnvVars(value string, env map[string]string) string {
 	// Replace ${VAR} syntax
 	result := os.Expand(value, func(key string) string {
-		if val, exists := env[key]; exists {
-			return val
+		key, def, hasDefault := strings.Cut(key, interpolationDefaultSep)
+		val, exists := env[key]
+		if !exists {
+			val = os.Getenv(key)
+		}
+		if val == "" && hasDefault {
+			return def
 		}
-		return os.Getenv(key)
+		return val
 	})
 
 	return result
@@ -372,6 +390,8 @@ func MaskSecrets(service Service, env map[string]string) map[string]string {
 		if strings.Contains(keyUpper, "SECRET") ||
 			strings.Contains(keyUpper, "PASSWORD") ||
 			strings.Contains(keyUpper, "TOKEN") ||
+			strings.Contains(keyUpper, "CONNECTION_STRING") ||
+			strings.Contains(keyUpper, "CONNECTIONSTRING") ||
 			(strings.Contains(keyUpper, "KEY") && !strings.Contains(keyUpper, "PUBLIC")) {
 			secrets[key] = true
 		}
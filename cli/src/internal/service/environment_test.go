@@ -368,6 +368,24 @@ func TestGetEnvironment(t *testing.T) {
 				"LOG_LEVEL": "warn",
 			},
 		},
+		{
+			name: "env overrides environment",
+			service: Service{
+				Environment: Environment{
+					"PORT":      "8080",
+					"LOG_LEVEL": "warn",
+				},
+				Env: Environment{
+					"LOG_LEVEL": "debug",
+					"FEATURE_X": "on",
+				},
+			},
+			want: map[string]string{
+				"PORT":      "8080",
+				"LOG_LEVEL": "debug",
+				"FEATURE_X": "on",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -709,6 +727,16 @@ func TestSubstituteEnvVars(t *testing.T) {
 			value: "app-${NAME}-service",
 			want:  "app-myapp-service",
 		},
+		{
+			name:  "default for undefined variable",
+			value: "${UNDEFINED:-fallback}",
+			want:  "fallback",
+		},
+		{
+			name:  "default ignored for defined variable",
+			value: "${NAME:-fallback}",
+			want:  "myapp",
+		},
 		{
 			name:  "empty string",
 			value: "",
@@ -747,12 +775,14 @@ func TestMaskSecrets(t *testing.T) {
 		"AUTH_TOKEN":  "authtoken",
 		"NORMAL_VAR":  "normal",
 		"PUBLIC_KEY":  "pubkey123", // Should NOT be masked (has PUBLIC)
+
+		"AZURE_STORAGE_CONNECTION_STRING": "AccountKey=abc",
 	}
 
 	masked := MaskSecrets(service, env)
 
 	// Variables with secret-like patterns should be masked
-	secretKeys := []string{"API_KEY", "PASSWORD", "DB_PASSWORD", "TOKEN", "SECRET", "AUTH_TOKEN"}
+	secretKeys := []string{"API_KEY", "PASSWORD", "DB_PASSWORD", "TOKEN", "SECRET", "AUTH_TOKEN", "AZURE_STORAGE_CONNECTION_STRING"}
 	for _, key := range secretKeys {
 		if masked[key] != "***" {
 			t.Errorf("MaskSecrets()[%q] = %q, want ***", key, masked[key])
@@ -931,6 +961,37 @@ func TestLoadLocalSettings(t *testing.T) {
 		}
 	})
 
+	t.Run("non-string values are formatted", func(t *testing.T) {
+		settingsFile := filepath.Join(t.TempDir(), "local.settings.json")
+		content := `{"Values": {"FUNCTIONS_WORKER_RUNTIME": "node", "RETRIES": 3, "ENABLED": true, "EMPTY": null}}`
+		if err := os.WriteFile(settingsFile, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		result := loadLocalSettings(settingsFile)
+		want := map[string]string{"FUNCTIONS_WORKER_RUNTIME": "node", "RETRIES": "3", "ENABLED": "true"}
+		if len(result) != len(want) {
+			t.Fatalf("got %v, want %v", result, want)
+		}
+		for key, value := range want {
+			if result[key] != value {
+				t.Errorf("For key %q: got %q, want %q", key, result[key], value)
+			}
+		}
+	})
+
+	t.Run("encrypted values are skipped", func(t *testing.T) {
+		settingsFile := filepath.Join(t.TempDir(), "local.settings.json")
+		content := `{"IsEncrypted": true, "Values": {"AzureWebJobsStorage": "CfDJ8..."}}`
+		if err := os.WriteFile(settingsFile, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		if result := loadLocalSettings(settingsFile); result != nil {
+			t.Errorf("Expected nil for encrypted settings, got %v", result)
+		}
+	})
+
 	t.Run("invalid JSON returns nil", func(t *testing.T) {
 		tempDir := t.TempDir()
 		settingsFile := filepath.Join(tempDir, "local.settings.json")
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/constants"
+)
+
+// ServiceEvent identifies a point in a service's lifecycle.
+type ServiceEvent string
+
+// Lifecycle events emitted by the orchestrator.
+const (
+	EventServiceStarting      ServiceEvent = "servicestarting"      // before the service process or container is started
+	EventServiceStarted       ServiceEvent = "servicestarted"       // after the service has started (not necessarily healthy)
+	EventServiceStopped       ServiceEvent = "servicestopped"       // after the service was stopped or exited
+	EventServiceHealthChanged ServiceEvent = "servicehealthchanged" // when a health check result differs from the previous one
+	EventServiceReady         ServiceEvent = "serviceready"         // when a started service first passes its health check
+	EventServiceCrashed       ServiceEvent = "servicecrashed"       // when a service exits with an error without being stopped
+)
+
+// Health states reported by EventServiceHealthChanged.
+const (
+	ServiceHealthHealthy   = "healthy"
+	ServiceHealthUnhealthy = "unhealthy"
+)
+
+// DefaultHealthWatchInterval is how often WatchServiceHealth checks each service.
+const DefaultHealthWatchInterval = 10 * time.Second
+
+// ServiceEventInfo describes a lifecycle event for a single service.
+type ServiceEventInfo struct {
+	Event   ServiceEvent
+	Service string
+	Port    int
+	PID     int
+	Time    time.Time
+	// Health and PreviousHealth are set for EventServiceHealthChanged.
+	Health         string
+	PreviousHealth string
+	// Error is the start failure, exit error or failed health check, if any.
+	Error error
+}
+
+// Status returns the registry status of the service after the event, e.g. "running" after
+// EventServiceStarted, or "" when the event doesn't change it.
+func (info ServiceEventInfo) Status() string {
+	switch info.Event {
+	case EventServiceStarting:
+		return constants.StatusStarting
+	case EventServiceStarted:
+		return constants.StatusRunning
+	case EventServiceReady:
+		return constants.StatusReady
+	case EventServiceCrashed:
+		return constants.StatusError
+	case EventServiceStopped:
+		if info.Error != nil {
+			return constants.StatusError
+		}
+		return constants.StatusStopped
+	default:
+		return ""
+	}
+}
+
+// ServiceEventHandler is called synchronously when a lifecycle event occurs.
+// Handlers that do slow work delay the lifecycle step that emitted the event.
+type ServiceEventHandler func(info ServiceEventInfo)
+
+type serviceEventHandler struct {
+	id int
+	fn ServiceEventHandler
+}
+
+var serviceEvents = struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[ServiceEvent][]serviceEventHandler
+	stopped  map[string]bool // services whose last event was EventServiceStopped
+}{
+	handlers: make(map[ServiceEvent][]serviceEventHandler),
+	stopped:  make(map[string]bool),
+}
+
+// OnServiceStarting registers a handler called before a service is started.
+// The returned function unregisters the handler.
+func OnServiceStarting(handler ServiceEventHandler) func() {
+	return OnServiceEvent(EventServiceStarting, handler)
+}
+
+// OnServiceStarted registers a handler called after a service has started.
+// The returned function unregisters the handler.
+func OnServiceStarted(handler ServiceEventHandler) func() {
+	return OnServiceEvent(EventServiceStarted, handler)
+}
+
+// OnServiceStopped registers a handler called after a service was stopped or exited.
+// The returned function unregisters the handler.
+func OnServiceStopped(handler ServiceEventHandler) func() {
+	return OnServiceEvent(EventServiceStopped, handler)
+}
+
+// OnServiceHealthChanged registers a handler called when a service's health changes.
+// Health is only watched while WatchServiceHealth runs (azd app run does this when a
+// handler is registered). The returned function unregisters the handler.
+func OnServiceHealthChanged(handler ServiceEventHandler) func() {
+	return OnServiceEvent(EventServiceHealthChanged, handler)
+}
+
+// OnServiceReady registers a handler called when a started service first passes its health check.
+// Readiness is only watched while a handler is registered. The returned function unregisters the handler.
+func OnServiceReady(handler ServiceEventHandler) func() {
+	return OnServiceEvent(EventServiceReady, handler)
+}
+
+// OnServiceCrashed registers a handler called when a service exits with an error without being stopped.
+// The returned function unregisters the handler.
+func OnServiceCrashed(handler ServiceEventHandler) func() {
+	return OnServiceEvent(EventServiceCrashed, handler)
+}
+
+// HasServiceEventHandlers reports whether any handler is registered for the event.
+func HasServiceEventHandlers(event ServiceEvent) bool {
+	serviceEvents.mu.RLock()
+	defer serviceEvents.mu.RUnlock()
+	return len(serviceEvents.handlers[event]) > 0
+}
+
+// OnServiceEvent registers a handler for any lifecycle event.
+// The returned function unregisters the handler.
+func OnServiceEvent(event ServiceEvent, handler ServiceEventHandler) func() {
+	serviceEvents.mu.Lock()
+	defer serviceEvents.mu.Unlock()
+
+	serviceEvents.nextID++
+	id := serviceEvents.nextID
+	serviceEvents.handlers[event] = append(serviceEvents.handlers[event], serviceEventHandler{id: id, fn: handler})
+
+	return func() {
+		serviceEvents.mu.Lock()
+		defer serviceEvents.mu.Unlock()
+
+		handlers := serviceEvents.handlers[event]
+		for i, h := range handlers {
+			if h.id == id {
+				serviceEvents.handlers[event] = append(handlers[:i:i], handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// EmitServiceEvent calls the handlers registered for info.Event.
+// A service that is stopped by azd-app and then exits is only reported as stopped once.
+// Handler panics are recovered so a faulty handler can't take down the orchestrator.
+func EmitServiceEvent(info ServiceEventInfo) {
+	if info.Time.IsZero() {
+		info.Time = time.Now()
+	}
+
+	serviceEvents.mu.Lock()
+	switch info.Event {
+	case EventServiceStopped:
+		if serviceEvents.stopped[info.Service] {
+			serviceEvents.mu.Unlock()
+			return
+		}
+		serviceEvents.stopped[info.Service] = true
+	case EventServiceStarting, EventServiceStarted:
+		delete(serviceEvents.stopped, info.Service)
+	}
+	handlers := append([]serviceEventHandler(nil), serviceEvents.handlers[info.Event]...)
+	serviceEvents.mu.Unlock()
+
+	for _, h := range handlers {
+		callServiceEventHandler(h.fn, info)
+	}
+}
+
+// callServiceEventHandler calls a handler and recovers from a panic in it.
+func callServiceEventHandler(handler ServiceEventHandler, info ServiceEventInfo) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("service event handler panicked",
+				slog.String("event", string(info.Event)),
+				slog.String("service", info.Service),
+				slog.Any("panic", r))
+		}
+	}()
+	handler(info)
+}
+
+// newServiceEventInfo creates the event info for a service process.
+func newServiceEventInfo(event ServiceEvent, process *ServiceProcess) ServiceEventInfo {
+	info := ServiceEventInfo{Event: event, Service: process.Name, Port: process.Port}
+	if process.Process != nil {
+		info.PID = process.Process.Pid
+	}
+	return info
+}
+
+// watchServiceReady emits EventServiceReady once a started service passes its health check
+// SuccessThreshold times in a row. It gives up when ctx is canceled or the service isn't
+// healthy within timeout (or its own health check timeout, if longer).
+func watchServiceReady(ctx context.Context, process *ServiceProcess, timeout time.Duration) {
+	config := process.Runtime.HealthCheck
+	interval := config.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	deadline := time.Now().Add(max(timeout, config.Timeout))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	successes := 0
+	for {
+		if err := probeServiceHealth(process); err != nil {
+			successes = 0
+		} else {
+			successes++
+			if successes >= max(config.SuccessThreshold, 1) {
+				EmitServiceEvent(newServiceEventInfo(EventServiceReady, process))
+				return
+			}
+		}
+		if time.Now().After(deadline) {
+			slog.Debug("service did not become ready", slog.String("service", process.Name))
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// WatchServiceHealth checks the health of the services returned by processes every interval
+// and emits EventServiceHealthChanged when a service becomes healthy or unhealthy.
+// The first result for each service is its baseline and does not emit an event.
+// It blocks until ctx is canceled.
+func WatchServiceHealth(ctx context.Context, interval time.Duration, processes func() map[string]*ServiceProcess) {
+	if interval <= 0 {
+		interval = DefaultHealthWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	previous := make(map[string]string)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkServiceHealthChanges(processes(), previous)
+		}
+	}
+}
+
+// checkServiceHealthChanges probes each service once and emits an event for every service
+// whose health differs from previous, which is updated in place.
+func checkServiceHealthChanges(processes map[string]*ServiceProcess, previous map[string]string) {
+	for name, process := range processes {
+		if process == nil || process.Runtime.HealthCheck.Type == watchModeNone {
+			continue
+		}
+
+		err := probeServiceHealth(process)
+		health := ServiceHealthHealthy
+		if err != nil {
+			health = ServiceHealthUnhealthy
+		}
+
+		prev, seen := previous[name]
+		previous[name] = health
+		if !seen || prev == health {
+			continue
+		}
+
+		info := newServiceEventInfo(EventServiceHealthChanged, process)
+		info.Health = health
+		info.PreviousHealth = prev
+		info.Error = err
+		EmitServiceEvent(info)
+	}
+}
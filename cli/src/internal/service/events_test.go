@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestServiceEvents_RegisterEmitUnregister(t *testing.T) {
+	var got []ServiceEventInfo
+	unregister := OnServiceStarted(func(info ServiceEventInfo) {
+		got = append(got, info)
+	})
+
+	EmitServiceEvent(ServiceEventInfo{Event: EventServiceStarted, Service: "events-api", Port: 3000, PID: 42})
+	EmitServiceEvent(ServiceEventInfo{Event: EventServiceStarting, Service: "events-api"})
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(got))
+	}
+	if got[0].Service != "events-api" || got[0].Port != 3000 || got[0].PID != 42 {
+		t.Errorf("unexpected event: %+v", got[0])
+	}
+	if got[0].Time.IsZero() {
+		t.Error("expected event time to be set")
+	}
+	if !HasServiceEventHandlers(EventServiceStarted) {
+		t.Error("expected a registered handler")
+	}
+
+	unregister()
+	EmitServiceEvent(ServiceEventInfo{Event: EventServiceStarted, Service: "events-api"})
+	if len(got) != 1 {
+		t.Errorf("handler called after unregister")
+	}
+	if HasServiceEventHandlers(EventServiceStarted) {
+		t.Error("expected no registered handlers after unregister")
+	}
+}
+
+func TestServiceEvents_StoppedReportedOnce(t *testing.T) {
+	count := 0
+	unregister := OnServiceStopped(func(info ServiceEventInfo) {
+		count++
+	})
+	defer unregister()
+
+	// Stopped by azd-app, then the monitor sees the process exit
+	EmitServiceEvent(ServiceEventInfo{Event: EventServiceStopped, Service: "events-worker"})
+	EmitServiceEvent(ServiceEventInfo{Event: EventServiceStopped, Service: "events-worker", Error: errors.New("exit 1")})
+	if count != 1 {
+		t.Errorf("expected 1 stopped event, got %d", count)
+	}
+
+	// After a restart, the next stop is reported again
+	EmitServiceEvent(ServiceEventInfo{Event: EventServiceStarting, Service: "events-worker"})
+	EmitServiceEvent(ServiceEventInfo{Event: EventServiceStopped, Service: "events-worker"})
+	if count != 2 {
+		t.Errorf("expected 2 stopped events after restart, got %d", count)
+	}
+}
+
+func TestServiceEvents_HandlerPanicRecovered(t *testing.T) {
+	called := false
+	unregisterPanic := OnServiceStarting(func(info ServiceEventInfo) {
+		panic("boom")
+	})
+	defer unregisterPanic()
+	unregister := OnServiceStarting(func(info ServiceEventInfo) {
+		called = true
+	})
+	defer unregister()
+
+	EmitServiceEvent(ServiceEventInfo{Event: EventServiceStarting, Service: "events-panic"})
+
+	if !called {
+		t.Error("expected handlers after a panicking handler to run")
+	}
+}
+
+func TestCheckServiceHealthChanges(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	var got []ServiceEventInfo
+	unregister := OnServiceHealthChanged(func(info ServiceEventInfo) {
+		got = append(got, info)
+	})
+	defer unregister()
+
+	processes := map[string]*ServiceProcess{
+		"events-db": {
+			Name:    "events-db",
+			Port:    port,
+			Runtime: ServiceRuntime{HealthCheck: HealthCheckConfig{Type: "tcp"}},
+		},
+		"events-none": {
+			Name:    "events-none",
+			Runtime: ServiceRuntime{HealthCheck: HealthCheckConfig{Type: "none"}},
+		},
+	}
+	previous := make(map[string]string)
+
+	// First check is the baseline
+	checkServiceHealthChanges(processes, previous)
+	if len(got) != 0 {
+		t.Fatalf("expected no event for baseline, got %+v", got)
+	}
+
+	_ = listener.Close()
+	checkServiceHealthChanges(processes, previous)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 health change, got %d", len(got))
+	}
+	if got[0].Service != "events-db" || got[0].Health != ServiceHealthUnhealthy || got[0].PreviousHealth != ServiceHealthHealthy {
+		t.Errorf("unexpected health change: %+v", got[0])
+	}
+	if got[0].Error == nil {
+		t.Error("expected the failed health check error")
+	}
+
+	// No change, no event
+	checkServiceHealthChanges(processes, previous)
+	if len(got) != 1 {
+		t.Errorf("expected no event without a change, got %d events", len(got))
+	}
+}
+
+func TestServiceEventInfo_Status(t *testing.T) {
+	tests := []struct {
+		info ServiceEventInfo
+		want string
+	}{
+		{ServiceEventInfo{Event: EventServiceStarting}, "starting"},
+		{ServiceEventInfo{Event: EventServiceStarted}, "running"},
+		{ServiceEventInfo{Event: EventServiceReady}, "ready"},
+		{ServiceEventInfo{Event: EventServiceCrashed, Error: errors.New("exit 1")}, "error"},
+		{ServiceEventInfo{Event: EventServiceStopped}, "stopped"},
+		{ServiceEventInfo{Event: EventServiceStopped, Error: errors.New("exit 1")}, "error"},
+		{ServiceEventInfo{Event: EventServiceHealthChanged, Health: ServiceHealthHealthy}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.info.Status(); got != tt.want {
+			t.Errorf("Status() of %s (error %v) = %q, want %q", tt.info.Event, tt.info.Error, got, tt.want)
+		}
+	}
+}
+
+func TestWatchServiceReady(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	var got []ServiceEventInfo
+	unregister := OnServiceReady(func(info ServiceEventInfo) {
+		got = append(got, info)
+	})
+	defer unregister()
+
+	ready := &ServiceProcess{
+		Name:    "events-ready",
+		Port:    port,
+		Runtime: ServiceRuntime{HealthCheck: HealthCheckConfig{Type: "tcp", Interval: 10 * time.Millisecond, SuccessThreshold: 2}},
+	}
+	watchServiceReady(context.Background(), ready, time.Second)
+	if len(got) != 1 || got[0].Service != "events-ready" || got[0].Port != port {
+		t.Fatalf("expected a ready event for events-ready, got %+v", got)
+	}
+
+	// A service that never becomes healthy is given up on after the timeout
+	_ = listener.Close()
+	unhealthy := &ServiceProcess{
+		Name:    "events-unready",
+		Port:    port,
+		Runtime: ServiceRuntime{HealthCheck: HealthCheckConfig{Type: "tcp", Interval: 10 * time.Millisecond}},
+	}
+	watchServiceReady(context.Background(), unhealthy, 50*time.Millisecond)
+	if len(got) != 1 {
+		t.Errorf("expected no ready event for an unhealthy service, got %+v", got[1:])
+	}
+}
@@ -11,10 +11,12 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/constants"
 	"github.com/jongio/azd-app/cli/src/internal/executor"
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
 )
 
 // StartService starts a service and returns the process handle.
@@ -38,6 +40,9 @@ func StartService(runtime *ServiceRuntime, env map[string]string, projectDir str
 		return nil, err
 	}
 
+	// Free the ports held for the service as late as possible, so they can't be taken before the process binds them
+	releaseHeldPorts(runtime)
+
 	// Start process
 	if err := cmd.Start(); err != nil {
 		return nil, fmt.Errorf("failed to start service %s: %w", runtime.Name, err)
@@ -45,6 +50,17 @@ func StartService(runtime *ServiceRuntime, env map[string]string, projectDir str
 
 	process.Process = cmd.Process
 	process.Port = runtime.Port
+	if tree, err := newProcessTree(cmd.Process); err != nil {
+		// Stopping falls back to taskkill /T, which misses descendants whose parent exited
+		slog.Debug("failed to track service process tree",
+			slog.String("service", runtime.Name),
+			slog.String("error", err.Error()))
+	} else {
+		process.tree = tree
+	}
+	recordLaunch(projectDir, runtime, env)
+	recordProcess(projectDir, runtime, cmd.Process.Pid)
+	process.exit = &exitWaiter{done: make(chan struct{})}
 
 	// Start log collection
 	StartLogCollection(process, projectDir, parser)
@@ -52,11 +68,21 @@ func StartService(runtime *ServiceRuntime, env map[string]string, projectDir str
 	return process, nil
 }
 
+// releaseHeldPorts frees the ports held for a service since port assignment: its port and
+// any further ports it listens on.
+func releaseHeldPorts(runtime *ServiceRuntime) {
+	portmanager.ReleaseHeldPort(runtime.Port)
+	for _, extra := range runtime.ExtraPorts {
+		portmanager.ReleaseHeldPort(extra.HostPort)
+	}
+}
+
 // createServiceCommand creates an exec.Cmd for the service.
 func createServiceCommand(runtime *ServiceRuntime, env map[string]string) (*exec.Cmd, error) { //nolint:unparam // return value kept for future use/interface conformance
 	// #nosec G204 -- Command and args come from azure.yaml service configuration, validated by service package
 	cmd := exec.CommandContext(context.Background(), runtime.Command, runtime.Args...)
 	cmd.Dir = runtime.WorkingDir
+	configureProcessTree(cmd)
 
 	// Build environment variable list ensuring azd context is preserved.
 	// Start with os.Environ() which includes all azd context variables
@@ -95,23 +121,111 @@ func setupProcessPipes(cmd *exec.Cmd, process *ServiceProcess) error {
 	return nil
 }
 
+// Stop outcomes reported in StopResult.
+const (
+	StopOutcomeGraceful = "graceful" // exited after the interrupt signal
+	StopOutcomeForced   = "forced"   // killed after the signal failed or the timeout expired
+	StopOutcomeSkipped  = "skipped"  // not stopped (protected dashboard process)
+	StopOutcomeFailed   = "failed"   // could not be stopped
+)
+
+// stopSignal is the signal sent to services to stop them gracefully on Unix.
+var stopSignal = struct {
+	mu  sync.RWMutex
+	sig os.Signal
+}{sig: os.Interrupt}
+
+// SetStopSignal sets the signal services are sent to stop them gracefully on Unix.
+// Services run in their own process groups and don't receive the signals sent to azd by
+// the terminal, so azd forwards the signal it was stopped with. A nil sig restores SIGINT.
+func SetStopSignal(sig os.Signal) {
+	if sig == nil {
+		sig = os.Interrupt
+	}
+	stopSignal.mu.Lock()
+	defer stopSignal.mu.Unlock()
+	stopSignal.sig = sig
+}
+
+// currentStopSignal returns the signal set with SetStopSignal.
+func currentStopSignal() os.Signal {
+	stopSignal.mu.RLock()
+	defer stopSignal.mu.RUnlock()
+	return stopSignal.sig
+}
+
+// StopResult describes how a service stopped.
+type StopResult struct {
+	Service    string        `json:"service"`
+	Outcome    string        `json:"outcome"`
+	ExitCode   int           `json:"exitCode"` // -1 when terminated by a signal or unknown
+	Duration   time.Duration `json:"-"`
+	DurationMs int64         `json:"durationMs"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// Clean reports whether the service stopped without being force-killed or failing.
+func (r StopResult) Clean() bool {
+	return r.Outcome == StopOutcomeGraceful || r.Outcome == StopOutcomeSkipped
+}
+
 // StopServiceGraceful stops a service with graceful shutdown timeout.
-// Sends SIGINT, waits for timeout, then force kills if still running.
+// Sends the stop signal (SIGINT by default) to the service's process group, waits for
+// timeout, then force kills the group if still running. A stopGracePeriod configured for the
+// service replaces timeout.
 // Returns nil if process stops successfully within timeout.
 // Note: The dashboard service is protected and will never be killed.
 func StopServiceGraceful(process *ServiceProcess, timeout time.Duration) error {
+	_, err := StopServiceWithResult(process, timeout)
+	return err
+}
+
+// StopServiceWithResult stops a service like StopServiceGraceful and reports
+// whether it stopped gracefully or was force-killed, its exit code, and how long it took.
+// Compose services are stopped with docker compose down.
+func StopServiceWithResult(process *ServiceProcess, timeout time.Duration) (StopResult, error) {
 	if process == nil {
-		return errors.New("process is nil")
+		return StopResult{Outcome: StopOutcomeFailed, ExitCode: -1}, errors.New("process is nil")
 	}
+
+	if process.Runtime.StopGracePeriod > 0 {
+		timeout = process.Runtime.StopGracePeriod
+	}
+
+	start := time.Now()
+	result := StopResult{Service: process.Name, ExitCode: -1}
+	finish := func(outcome string, state *os.ProcessState, err error) (StopResult, error) {
+		result.Outcome = outcome
+		if state != nil {
+			result.ExitCode = state.ExitCode()
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		result.Duration = time.Since(start)
+		result.DurationMs = result.Duration.Milliseconds()
+		if outcome == StopOutcomeGraceful || outcome == StopOutcomeForced {
+			EmitServiceEvent(newServiceEventInfo(EventServiceStopped, process))
+		}
+		return result, err
+	}
+
+	if process.Runtime.Type == ServiceTypeCompose {
+		if err := StopComposeProject(process.Name, timeout); err != nil {
+			return finish(StopOutcomeFailed, nil, err)
+		}
+		return finish(StopOutcomeGraceful, nil, nil)
+	}
+
 	if process.Process == nil {
-		return errors.New("process not started")
+		return finish(StopOutcomeFailed, nil, errors.New("process not started"))
 	}
 
 	// Never kill the dashboard process - it must remain running to manage other services
 	if process.Name == constants.DashboardServiceName {
 		slog.Debug("skipping stop for dashboard service - dashboard is protected",
 			slog.String("service", process.Name))
-		return nil
+		return finish(StopOutcomeSkipped, nil, nil)
 	}
 
 	slog.Info("stopping service",
@@ -120,30 +234,23 @@ func StopServiceGraceful(process *ServiceProcess, timeout time.Duration) error {
 		slog.Int("port", process.Port),
 		slog.Duration("timeout", timeout))
 
-	// On Windows, we use taskkill with /T flag to kill the entire process tree.
-	// This is critical because services often spawn child processes (e.g., npm -> node,
-	// electron -> node) that hold ports. Using process.Kill() only kills the parent,
-	// leaving child processes running and holding ports, causing port conflicts on restart.
+	// On Windows, the entire process tree is force-killed. This is critical because services
+	// often spawn child processes (e.g., npm -> node, electron -> node) that hold ports. Using
+	// process.Kill() only kills the parent, leaving child processes running and holding ports,
+	// causing port conflicts on restart.
 	if runtime.GOOS == "windows" {
-		slog.Debug("using taskkill /T for Windows process tree termination",
+		slog.Debug("killing Windows process tree",
 			slog.String("service", process.Name),
-			slog.Int("pid", process.Process.Pid))
-
-		// Use taskkill /F /T to force kill entire process tree
-		// /F = Force termination
-		// /T = Kill child processes (tree kill)
-		// /PID = Target process ID
-		// #nosec G204 -- PID is from os.Process which is a validated integer
-		cmd := exec.CommandContext(context.Background(), "taskkill", "/F", "/T", "/PID", fmt.Sprintf("%d", process.Process.Pid))
-		if err := cmd.Run(); err != nil {
-			// taskkill returns error if process already exited, which is fine
-			slog.Debug("taskkill completed with error (process may have already exited)",
+			slog.Int("pid", process.Process.Pid),
+			slog.Bool("job_object", process.tree != nil))
+		if err := killProcessTree(process); err != nil {
+			slog.Debug("failed to kill service process tree",
 				slog.String("service", process.Name),
 				slog.String("error", err.Error()))
 		}
 
 		// Wait for process to exit - this may fail if taskkill already cleaned up
-		_, waitErr := process.Process.Wait()
+		state, waitErr := process.Wait()
 		// Ignore "Access is denied" - process already exited on Windows
 		if waitErr != nil && !isAccessDeniedError(waitErr) {
 			slog.Debug("wait completed with error (expected if taskkill succeeded)",
@@ -151,51 +258,72 @@ func StopServiceGraceful(process *ServiceProcess, timeout time.Duration) error {
 		}
 		slog.Info("service stopped",
 			slog.String("service", process.Name))
-		return nil
+		return finish(StopOutcomeForced, state, nil)
 	}
 
-	// On Unix/Linux/macOS, try graceful shutdown with SIGINT first
-	if err := process.Process.Signal(os.Interrupt); err != nil {
+	// On Unix/Linux/macOS, try graceful shutdown first: the stop signal (SIGINT unless azd
+	// itself was stopped with another signal) goes to the service's whole process group, so
+	// processes started by shells and launchers such as `npm run dev` are stopped too.
+	if err := signalProcessTree(process, currentStopSignal()); err != nil {
 		slog.Warn("graceful shutdown signal failed, forcing kill",
 			slog.String("service", process.Name),
 			slog.String("error", err.Error()))
 		// If signal fails (process already dead or doesn't support signals), try kill
-		if killErr := process.Process.Kill(); killErr != nil {
-			return fmt.Errorf("failed to kill process: %w", killErr)
+		if killErr := killProcessTree(process); killErr != nil {
+			if errors.Is(killErr, os.ErrProcessDone) {
+				// Process had already exited on its own
+				state, _ := process.Wait()
+				return finish(StopOutcomeGraceful, state, nil)
+			}
+			return finish(StopOutcomeFailed, nil, fmt.Errorf("failed to kill process: %w", killErr))
 		}
 		// Wait for process to exit
-		_, _ = process.Process.Wait()
+		state, _ := process.Wait()
 		slog.Info("service stopped (forced)",
 			slog.String("service", process.Name))
-		return nil
+		return finish(StopOutcomeForced, state, nil)
 	}
 
 	// Wait for graceful shutdown with timeout
-	done := make(chan error, 1)
+	type waitResult struct {
+		state *os.ProcessState
+		err   error
+	}
+	done := make(chan waitResult, 1)
 	go func() {
-		_, err := process.Process.Wait()
-		done <- err
+		state, err := process.Wait()
+		done <- waitResult{state, err}
 	}()
 
 	select {
-	case err := <-done:
-		// Process exited within timeout
+	case res := <-done:
+		// Process exited within timeout; give the rest of its process group what is left of it
+		if !waitProcessTree(process, start.Add(timeout)) {
+			slog.Warn("service child processes still running after timeout, forcing kill",
+				slog.String("service", process.Name),
+				slog.Duration("timeout", timeout))
+			if err := killProcessTree(process); err != nil {
+				slog.Debug("failed to kill service process tree",
+					slog.String("service", process.Name),
+					slog.String("error", err.Error()))
+			}
+		}
 		slog.Info("service stopped gracefully",
 			slog.String("service", process.Name))
-		return err
+		return finish(StopOutcomeGraceful, res.state, res.err)
 	case <-time.After(timeout):
 		// Timeout expired, force kill
 		slog.Warn("graceful shutdown timeout, forcing kill",
 			slog.String("service", process.Name),
 			slog.Duration("timeout", timeout))
-		if err := process.Process.Kill(); err != nil {
-			return fmt.Errorf("failed to force kill process after timeout: %w", err)
+		if err := killProcessTree(process); err != nil {
+			return finish(StopOutcomeFailed, nil, fmt.Errorf("failed to force kill process after timeout: %w", err))
 		}
 		// Wait for kill to complete
-		_, waitErr := process.Process.Wait()
+		res := <-done
 		slog.Info("service stopped (forced after timeout)",
 			slog.String("service", process.Name))
-		return waitErr
+		return finish(StopOutcomeForced, res.state, res.err)
 	}
 }
 
@@ -318,14 +446,7 @@ func StartLogCollection(process *ServiceProcess, projectDir string, parser *Func
 func collectStreamLogs(reader io.ReadCloser, serviceName string, buffer *LogBuffer, isStderr bool) {
 	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
-		entry := LogEntry{
-			Service:   serviceName,
-			Message:   scanner.Text(),
-			Timestamp: time.Now(),
-			IsStderr:  isStderr,
-			Level:     inferLogLevel(scanner.Text()),
-		}
-		buffer.Add(entry)
+		buffer.Add(NewLogEntry(serviceName, scanner.Text(), isStderr))
 	}
 }
 
@@ -336,14 +457,7 @@ func collectFunctionsStreamLogs(reader io.ReadCloser, serviceName string, buffer
 		line := scanner.Text()
 
 		// Add to log buffer
-		entry := LogEntry{
-			Service:   serviceName,
-			Message:   line,
-			Timestamp: time.Now(),
-			IsStderr:  isStderr,
-			Level:     inferLogLevel(line),
-		}
-		buffer.Add(entry)
+		buffer.Add(NewLogEntry(serviceName, line, isStderr))
 
 		// Also parse for function endpoints
 		parser.ParseLine(serviceName, line)
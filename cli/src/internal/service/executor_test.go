@@ -2,10 +2,13 @@ package service
 
 import (
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestValidateRuntime(t *testing.T) {
@@ -170,6 +173,14 @@ func TestStartService_Success(t *testing.T) {
 		t.Error("Process.Process is nil")
 	}
 
+	launch, ok := GetLaunchConfig(tmpDir, "test-echo")
+	if !ok {
+		t.Fatal("GetLaunchConfig() found no launch configuration for the started service")
+	}
+	if launch.Command != "timeout" || len(launch.Args) != 1 || launch.WorkingDir != tmpDir || launch.Env["TEST_VAR"] != "value" {
+		t.Errorf("GetLaunchConfig() = %+v, want the command, working dir and environment the service was started with", launch)
+	}
+
 	// Clean up - remove log buffer to release file handles
 	logMgr := GetLogManager(tmpDir)
 	_ = logMgr.RemoveBuffer(runtime.Name)
@@ -405,3 +416,99 @@ func TestReadServiceOutput(t *testing.T) {
 		}
 	}
 }
+
+func TestStartService_CommandWithSpacesAndNonASCIIPaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shell test")
+	}
+
+	projectDir := filepath.Join(t.TempDir(), "my project", "sérvice ü")
+	scriptDir := filepath.Join(projectDir, "run scripts")
+	if err := os.MkdirAll(scriptDir, 0750); err != nil {
+		t.Fatalf("failed to create script dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(scriptDir, "stårt.sh"), []byte("echo \"$1\" > \"$2\"\n"), 0600); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	svcRuntime := &ServiceRuntime{Name: "api", WorkingDir: projectDir, Language: "shell"}
+	if err := buildRunCommand(svcRuntime, projectDir, "", `sh "./run scripts/stårt.sh" "hello wörld" 'out file.txt'`, ""); err != nil {
+		t.Fatalf("buildRunCommand() failed: %v", err)
+	}
+	if want := []string{"./run scripts/stårt.sh", "hello wörld", "out file.txt"}; strings.Join(svcRuntime.Args, "|") != strings.Join(want, "|") {
+		t.Fatalf("Args = %q, want %q", svcRuntime.Args, want)
+	}
+
+	process, err := StartService(svcRuntime, nil, projectDir, nil)
+	if err != nil {
+		t.Fatalf("StartService() error = %v", err)
+	}
+	t.Cleanup(func() { _ = GetLogManager(projectDir).RemoveBuffer(svcRuntime.Name) })
+
+	if _, err := process.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectDir, "out file.txt"))
+	if err != nil {
+		t.Fatalf("service did not write output: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "hello wörld" {
+		t.Errorf("output = %q, want %q", got, "hello wörld")
+	}
+}
+
+func TestService_GetStopGracePeriod(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "not set", yaml: "", want: 0},
+		{name: "seconds", yaml: "stopGracePeriod: 10s", want: 10 * time.Second},
+		{name: "minutes", yaml: "stopGracePeriod: 1m30s", want: 90 * time.Second},
+		{name: "zero", yaml: "stopGracePeriod: 0s", want: 0},
+		{name: "no unit", yaml: "stopGracePeriod: 10", wantErr: true},
+		{name: "negative", yaml: "stopGracePeriod: -5s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var service Service
+			err := yaml.Unmarshal([]byte("project: ./api\n"+tt.yaml), &service)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Unmarshal() expected error, got stopGracePeriod %q", service.StopGracePeriod)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			got, err := service.GetStopGracePeriod()
+			if err != nil {
+				t.Fatalf("GetStopGracePeriod() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetStopGracePeriod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetStopSignal(t *testing.T) {
+	t.Cleanup(func() { SetStopSignal(nil) })
+
+	if got := currentStopSignal(); got != os.Interrupt {
+		t.Errorf("default stop signal = %v, want %v", got, os.Interrupt)
+	}
+	SetStopSignal(os.Kill)
+	if got := currentStopSignal(); got != os.Kill {
+		t.Errorf("stop signal = %v, want %v", got, os.Kill)
+	}
+	SetStopSignal(nil)
+	if got := currentStopSignal(); got != os.Interrupt {
+		t.Errorf("stop signal after reset = %v, want %v", got, os.Interrupt)
+	}
+}
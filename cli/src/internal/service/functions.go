@@ -6,10 +6,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/jongio/azd-app/cli/src/internal/portmanager"
 	"github.com/jongio/azd-core/security"
 )
 
@@ -29,8 +29,14 @@ const (
 	FunctionsVariantDotNet
 	// FunctionsVariantJava represents Java Functions (Maven or Gradle)
 	FunctionsVariantJava
+	// FunctionsVariantGeneric represents other Functions projects, e.g. PowerShell or custom handlers,
+	// recognized by function.json files or the worker runtime in local.settings.json
+	FunctionsVariantGeneric
 )
 
+// localSettingsFileName holds the app settings of a Functions project for local runs.
+const localSettingsFileName = "local.settings.json"
+
 // String returns the framework name for the FunctionsVariant.
 func (v FunctionsVariant) String() string {
 	switch v {
@@ -44,6 +50,8 @@ func (v FunctionsVariant) String() string {
 		return ".NET Functions"
 	case FunctionsVariantJava:
 		return "Java Functions"
+	case FunctionsVariantGeneric:
+		return "Azure Functions"
 	default:
 		return "Unknown"
 	}
@@ -270,9 +278,104 @@ func detectFunctionsVariant(projectDir string) FunctionsVariant {
 		return FunctionsVariantJava
 	}
 
+	if hasFunctionJSON(projectDir) || localSettingsWorkerRuntime(projectDir) != "" {
+		return FunctionsVariantGeneric
+	}
+
 	return FunctionsVariantUnknown
 }
 
+// isFunctionsProject reports whether the directory is an Azure Functions project: it has a
+// host.json and function definitions, a local.settings.json, or a recognized Functions variant.
+// Used to run such projects with Core Tools when azure.yaml doesn't set host: function.
+func isFunctionsProject(projectDir string) bool {
+	if !fileExists(projectDir, "host.json") {
+		return false
+	}
+	if hasFunctionJSON(projectDir) || fileExists(projectDir, localSettingsFileName) {
+		return true
+	}
+	return detectFunctionsVariant(projectDir) != FunctionsVariantUnknown
+}
+
+// Local Settings Functions
+
+// localSettings is the content of local.settings.json.
+type localSettings struct {
+	IsEncrypted bool           `json:"IsEncrypted"`
+	Values      map[string]any `json:"Values"`
+	Host        struct {
+		LocalHTTPPort any `json:"LocalHttpPort"`
+	} `json:"Host"`
+}
+
+// readLocalSettings reads and parses a local.settings.json file, returning nil if it
+// doesn't exist or isn't valid JSON.
+func readLocalSettings(path string) *localSettings {
+	// #nosec G304 -- Path is constructed from the validated project directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var settings localSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil
+	}
+	return &settings
+}
+
+// values returns the app settings as environment variables. Non-string values are
+// formatted as the Functions host reads them; encrypted values can't be used and are skipped.
+func (s *localSettings) values() map[string]string {
+	if s.IsEncrypted || s.Values == nil {
+		return nil
+	}
+	values := make(map[string]string, len(s.Values))
+	for key, value := range s.Values {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			values[key] = v
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		case float64:
+			values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			values[key] = string(data)
+		}
+	}
+	return values
+}
+
+// localHTTPPort returns the Host.LocalHttpPort setting, or 0 if it isn't set.
+func (s *localSettings) localHTTPPort() int {
+	var port int
+	switch v := s.Host.LocalHTTPPort.(type) {
+	case float64:
+		port = int(v)
+	case string:
+		port, _ = strconv.Atoi(v)
+	}
+	if port <= 0 || port > 65535 {
+		return 0
+	}
+	return port
+}
+
+// localSettingsWorkerRuntime returns FUNCTIONS_WORKER_RUNTIME from the project's local.settings.json.
+func localSettingsWorkerRuntime(projectDir string) string {
+	settings := readLocalSettings(filepath.Join(projectDir, localSettingsFileName))
+	if settings == nil {
+		return ""
+	}
+	return strings.TrimSpace(settings.values()["FUNCTIONS_WORKER_RUNTIME"])
+}
+
 // Language Detection Function
 
 // detectFunctionsLanguage detects the programming language for the Functions variant.
@@ -297,6 +400,24 @@ func detectFunctionsLanguage(variant FunctionsVariant, projectDir string) (strin
 	case FunctionsVariantJava:
 		return "Java", nil
 
+	case FunctionsVariantGeneric:
+		switch strings.ToLower(localSettingsWorkerRuntime(projectDir)) {
+		case "powershell":
+			return "PowerShell", nil
+		case "custom":
+			return "Custom Handler", nil
+		case "node":
+			return "JavaScript", nil
+		case "python":
+			return "Python", nil
+		case "dotnet", "dotnet-isolated":
+			return "C#", nil
+		case "java":
+			return "Java", nil
+		default:
+			return "Functions", nil
+		}
+
 	default:
 		return "", fmt.Errorf("could not detect language in %s", projectDir)
 	}
@@ -340,7 +461,7 @@ func buildFunctionsRuntime(serviceName string, service Service, projectDir strin
 	}
 
 	// Assign port
-	port, shouldUpdateAzureYaml, err := assignFunctionsPort(serviceName, service, variant, usedPorts, azureYamlDir)
+	port, shouldUpdateAzureYaml, err := assignFunctionsPort(serviceName, service, variant, projectDir, usedPorts, azureYamlDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to assign port: %w", err)
 	}
@@ -351,13 +472,22 @@ func buildFunctionsRuntime(serviceName string, service Service, projectDir strin
 	// Build command args: func start --port <port>
 	runtime.Args = []string{"start", "--port", fmt.Sprintf("%d", port)}
 
-	// Configure health check
+	// Configure health check: the host reports its status once the functions are loaded, which
+	// takes longer for projects that are built first. A healthcheck in azure.yaml is applied over it.
 	runtime.HealthCheck = HealthCheckConfig{
 		Type:     "http",
 		Path:     variant.HealthCheckPath(),
 		Timeout:  60 * time.Second,
 		Interval: 2 * time.Second,
 	}
+	if variant == FunctionsVariantDotNet || variant == FunctionsVariantJava {
+		runtime.HealthCheck.Timeout = 120 * time.Second
+	}
+	if service.IsHealthcheckDisabled() {
+		runtime.HealthCheck.Type = watchModeNone
+	} else {
+		applyHealthcheck(&runtime.HealthCheck, service.Healthcheck)
+	}
 
 	return runtime, nil
 }
@@ -366,9 +496,14 @@ func buildFunctionsRuntime(serviceName string, service Service, projectDir strin
 
 // assignFunctionsPort assigns a port for an Azure Functions service.
 // Returns the assigned port, whether azure.yaml should be updated, and any error.
-func assignFunctionsPort(serviceName string, service Service, variant FunctionsVariant, usedPorts map[int]bool, azureYamlDir string) (int, bool, error) {
-	// Get variant default port
+func assignFunctionsPort(serviceName string, service Service, variant FunctionsVariant, projectDir string, usedPorts map[int]bool, azureYamlDir string) (int, bool, error) {
+	// Get variant default port, or the port configured for func start in local.settings.json
 	preferredPort := variant.DefaultPort()
+	if settings := readLocalSettings(filepath.Join(projectDir, localSettingsFileName)); settings != nil {
+		if port := settings.localHTTPPort(); port > 0 {
+			preferredPort = port
+		}
+	}
 	isExplicit := false
 
 	// Check for explicit port in azure.yaml
@@ -391,8 +526,7 @@ func assignFunctionsPort(serviceName string, service Service, variant FunctionsV
 	}
 
 	// Use port manager from azure.yaml directory (shared across all services)
-	portMgr := portmanager.GetPortManager(azureYamlDir)
-	port, shouldUpdateAzureYaml, err := portMgr.AssignPort(serviceName, preferredPort, isExplicit)
+	port, shouldUpdateAzureYaml, err := assignServicePort(azureYamlDir, serviceName, service, preferredPort, isExplicit)
 	if err != nil {
 		return 0, false, fmt.Errorf("failed to assign port: %w", err)
 	}
@@ -592,6 +592,90 @@ func TestBuildFunctionsRuntime_Command(t *testing.T) {
 	}
 }
 
+// TestDetectServiceRuntime_FunctionsWithoutHost tests that Functions projects are recognized by their files
+func TestDetectServiceRuntime_FunctionsWithoutHost(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// PowerShell Functions project: host.json, function.json and local.settings.json
+	files := map[string]string{
+		"host.json":                 `{"version": "2.0"}`,
+		"HttpTrigger/function.json": `{"bindings": [{"type": "httpTrigger", "direction": "in", "name": "Request"}]}`,
+		"local.settings.json": `{
+			"IsEncrypted": false,
+			"Values": {"FUNCTIONS_WORKER_RUNTIME": "powershell"},
+			"Host": {"LocalHttpPort": 7075}
+		}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	svc := service.Service{Project: tmpDir}
+	runtime, err := service.DetectServiceRuntime("test-service", svc, make(map[int]bool), tmpDir, "")
+	if err != nil {
+		t.Fatalf("Expected successful detection, got error: %v", err)
+	}
+	if runtime.Command != "func" {
+		t.Errorf("Expected command 'func', got %q", runtime.Command)
+	}
+	if runtime.Framework != "Azure Functions" {
+		t.Errorf("Expected framework 'Azure Functions', got %q", runtime.Framework)
+	}
+	if runtime.Language != "PowerShell" {
+		t.Errorf("Expected language 'PowerShell', got %q", runtime.Language)
+	}
+	if runtime.Port != 7075 {
+		t.Errorf("Expected LocalHttpPort 7075 from local.settings.json, got %d", runtime.Port)
+	}
+	if runtime.HealthCheck.Type != "http" || runtime.HealthCheck.Path != "/admin/host/status" {
+		t.Errorf("Expected http health check on /admin/host/status, got %s %s", runtime.HealthCheck.Type, runtime.HealthCheck.Path)
+	}
+}
+
+// TestBuildFunctionsRuntime_Healthcheck tests that the healthcheck from azure.yaml is applied
+func TestBuildFunctionsRuntime_Healthcheck(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "host.json"), []byte(`{"version": "2.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write host.json: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "function_app.py"), []byte("import azure.functions as func\n"), 0644); err != nil {
+		t.Fatalf("Failed to write function_app.py: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		hc       *service.HealthcheckConfig
+		wantType string
+		wantPath string
+	}{
+		{name: "default", hc: nil, wantType: "http", wantPath: "/admin/host/status"},
+		{name: "custom path", hc: &service.HealthcheckConfig{Path: "/api/health"}, wantType: "http", wantPath: "/api/health"},
+		{name: "disabled", hc: &service.HealthcheckConfig{Disable: true}, wantType: "none", wantPath: "/admin/host/status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.Service{Project: tmpDir, Host: "function", Healthcheck: tt.hc}
+			runtime, err := service.DetectServiceRuntime("test-service", svc, make(map[int]bool), tmpDir, "")
+			if err != nil {
+				t.Fatalf("Expected successful detection, got error: %v", err)
+			}
+			if runtime.HealthCheck.Type != tt.wantType {
+				t.Errorf("HealthCheck.Type = %q, want %q", runtime.HealthCheck.Type, tt.wantType)
+			}
+			if runtime.HealthCheck.Path != tt.wantPath {
+				t.Errorf("HealthCheck.Path = %q, want %q", runtime.HealthCheck.Path, tt.wantPath)
+			}
+		})
+	}
+}
+
 // Helper function
 func containsString(s, substr string) bool {
 	return len(s) > 0 && len(substr) > 0 && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || containsSubstring(s, substr)))
@@ -1,6 +1,7 @@
 package service
 
 import (
+	goruntime "runtime"
 	"strings"
 	"testing"
 	"time"
@@ -177,6 +178,108 @@ func TestStopServiceGraceful_AlreadyExited(t *testing.T) {
 	}
 }
 
+func TestStopServiceWithResult(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping stop result test in short mode")
+	}
+	if goruntime.GOOS == "windows" {
+		t.Skip("graceful interrupt is not used on Windows")
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		timeout time.Duration
+		want    string
+	}{
+		{name: "graceful", command: "sleep", args: []string{"30"}, timeout: constants.TestServiceTimeout, want: StopOutcomeGraceful},
+		{name: "ignores interrupt", command: "sh", args: []string{"-c", "trap '' INT; while true; do sleep 0.1; done"}, timeout: 300 * time.Millisecond, want: StopOutcomeForced},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			runtime := &ServiceRuntime{
+				Name:       "test-stop-result",
+				WorkingDir: tmpDir,
+				Command:    tt.command,
+				Args:       tt.args,
+				Language:   "shell",
+			}
+
+			process, err := StartService(runtime, map[string]string{}, tmpDir, nil)
+			if err != nil {
+				t.Fatalf("StartService() error = %v", err)
+			}
+			t.Cleanup(func() {
+				_ = GetLogManager(tmpDir).RemoveBuffer(runtime.Name)
+			})
+			// Let the shell install its trap before signalling
+			time.Sleep(200 * time.Millisecond)
+
+			result, err := StopServiceWithResult(process, tt.timeout)
+			if err != nil {
+				t.Logf("StopServiceWithResult() returned: %v", err)
+			}
+			if result.Service != runtime.Name {
+				t.Errorf("Service = %q, want %q", result.Service, runtime.Name)
+			}
+			if result.Outcome != tt.want {
+				t.Errorf("Outcome = %q, want %q", result.Outcome, tt.want)
+			}
+			if result.Duration <= 0 || result.DurationMs != result.Duration.Milliseconds() {
+				t.Errorf("Duration = %v (%dms), want positive and consistent", result.Duration, result.DurationMs)
+			}
+			if result.Clean() != (tt.want == StopOutcomeGraceful) {
+				t.Errorf("Clean() = %v for outcome %q", result.Clean(), result.Outcome)
+			}
+		})
+	}
+}
+
+func TestServiceProcess_WaitIsShared(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping process wait test in short mode")
+	}
+	if goruntime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	tmpDir := t.TempDir()
+	runtime := &ServiceRuntime{
+		Name:       "test-shared-wait",
+		WorkingDir: tmpDir,
+		Command:    "sh",
+		Args:       []string{"-c", "exit 3"},
+		Language:   "shell",
+	}
+	process, err := StartService(runtime, map[string]string{}, tmpDir, nil)
+	if err != nil {
+		t.Fatalf("StartService() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = GetLogManager(tmpDir).RemoveBuffer(runtime.Name)
+	})
+
+	codes := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			state, err := process.Wait()
+			if err != nil || state == nil {
+				codes <- -100
+				return
+			}
+			codes <- state.ExitCode()
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if code := <-codes; code != 3 {
+			t.Errorf("Wait() exit code = %d, want 3 for every caller", code)
+		}
+	}
+}
+
 func TestStopServiceGraceful_MultipleTimeouts(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping multiple timeout test in short mode")
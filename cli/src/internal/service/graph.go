@@ -4,6 +4,7 @@ package service
 import (
 	"fmt"
 	"sort"
+	"strings"
 )
 
 // BuildDependencyGraph creates a dependency graph from services and resources.
@@ -13,16 +14,17 @@ func BuildDependencyGraph(services map[string]Service, resources map[string]Reso
 		Edges: make(map[string][]string),
 	}
 
-	// Add service nodes
+	// Add service nodes (uses and dependsOn both order startup)
 	for name, svc := range services {
+		deps := svc.Dependencies()
 		node := &DependencyNode{
 			Name:         name,
 			Service:      &svc,
 			IsResource:   false,
-			Dependencies: svc.Uses,
+			Dependencies: deps,
 		}
 		graph.Nodes[name] = node
-		graph.Edges[name] = svc.Uses
+		graph.Edges[name] = deps
 	}
 
 	// Add resource nodes (for dependency tracking, but won't be started)
@@ -39,12 +41,24 @@ func BuildDependencyGraph(services map[string]Service, resources map[string]Reso
 	// Validate all dependencies exist
 	for name, deps := range graph.Edges {
 		for _, dep := range deps {
+			if dep == name {
+				return nil, fmt.Errorf("service or resource '%s' depends on itself", name)
+			}
 			if _, exists := graph.Nodes[dep]; !exists {
 				return nil, fmt.Errorf("service or resource '%s' depends on '%s' which does not exist", name, dep)
 			}
 		}
 	}
 
+	// dependsOn waits for a health check, so it may only reference services
+	for name, svc := range services {
+		for _, dep := range svc.DependsOn {
+			if graph.Nodes[dep].IsResource {
+				return nil, fmt.Errorf("service '%s' has dependsOn '%s' which is a resource, not a service (use 'uses' for resources)", name, dep)
+			}
+		}
+	}
+
 	// Detect cycles
 	if err := DetectCycles(graph); err != nil {
 		return nil, err
@@ -59,14 +73,22 @@ func BuildDependencyGraph(services map[string]Service, resources map[string]Reso
 }
 
 // DetectCycles checks for circular dependencies in the graph.
+// The error names the services forming the cycle, e.g. "api -> worker -> api".
 func DetectCycles(graph *DependencyGraph) error {
 	visited := make(map[string]bool)
 	recStack := make(map[string]bool)
 
-	for node := range graph.Nodes {
+	// Visit nodes in sorted order so the reported cycle is deterministic
+	names := make([]string, 0, len(graph.Nodes))
+	for name := range graph.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, node := range names {
 		if !visited[node] {
-			if hasCycle(node, graph, visited, recStack) {
-				return fmt.Errorf("circular dependency detected involving: %s", node)
+			if cycle := findCycle(node, graph, visited, recStack, nil); cycle != nil {
+				return fmt.Errorf("circular dependency detected involving: %s", strings.Join(cycle, " -> "))
 			}
 		}
 	}
@@ -74,23 +96,29 @@ func DetectCycles(graph *DependencyGraph) error {
 	return nil
 }
 
-// hasCycle performs DFS to detect cycles.
-func hasCycle(node string, graph *DependencyGraph, visited map[string]bool, recStack map[string]bool) bool {
+// findCycle performs DFS to detect cycles and returns the cycle path, or nil if there is none.
+func findCycle(node string, graph *DependencyGraph, visited map[string]bool, recStack map[string]bool, path []string) []string {
 	visited[node] = true
 	recStack[node] = true
+	path = append(path, node)
 
 	for _, dep := range graph.Edges[node] {
 		if !visited[dep] {
-			if hasCycle(dep, graph, visited, recStack) {
-				return true
+			if cycle := findCycle(dep, graph, visited, recStack, path); cycle != nil {
+				return cycle
 			}
 		} else if recStack[dep] {
-			return true
+			// Trim the path to the start of the cycle and close it
+			for i, name := range path {
+				if name == dep {
+					return append(append([]string{}, path[i:]...), dep)
+				}
+			}
 		}
 	}
 
 	recStack[node] = false
-	return false
+	return nil
 }
 
 // calculateLevels assigns topological levels to nodes.
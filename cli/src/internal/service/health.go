@@ -3,14 +3,20 @@ package service
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/jongio/azd-app/cli/src/internal/docker"
 )
 
 // Backoff configuration constants
@@ -34,8 +40,11 @@ const (
 // - "http": Check an HTTP endpoint (default)
 // - "tcp": Check if a TCP port is listening
 // - "process": Check if the process is running
-// - "output": Monitor stdout for a pattern match (requires LogMatch to be set)
+// - "output" (or "log"): Monitor stdout for a pattern match (requires LogMatch to be set)
+// - "exec": Run a command; exit code 0 means healthy
 // - "none": Skip health checks (service is immediately considered ready)
+//
+// The service is ready after SuccessThreshold consecutive successful checks.
 func PerformHealthCheck(process *ServiceProcess) error {
 	config := process.Runtime.HealthCheck
 
@@ -51,41 +60,118 @@ func PerformHealthCheck(process *ServiceProcess) error {
 	b.MaxInterval = HealthCheckMaxInterval
 	b.Multiplier = BackoffMultiplier
 
+	successes := 0
 	operation := func() error {
-		var err error
-
-		switch config.Type {
-		case ServiceTypeHTTP:
-			err = HTTPHealthCheck(process.Port, config.Path)
-		case "tcp":
-			err = PortHealthCheck(process.Port)
-		case "process":
-			err = ProcessHealthCheck(process)
-		case "output":
-			// Output-based health check: check if the pattern has been matched in logs
-			err = OutputHealthCheck(process, config.LogMatch)
-		case "none":
-			// Already handled above, but include for completeness
-			process.Ready = true
-			return nil
-		default:
-			// Default to HTTP health check if port is available, otherwise process check
-			if process.Port > 0 {
-				err = HTTPHealthCheck(process.Port, config.Path)
-			} else {
-				err = ProcessHealthCheck(process)
-			}
+		err := probeServiceHealth(process)
+		if err != nil {
+			successes = 0
+			return err
 		}
-
-		if err == nil {
-			// Health check succeeded
-			process.Ready = true
+		successes++
+		if successes < config.SuccessThreshold {
+			// Check again right away; the backoff only grows on failures
+			b.Reset()
+			return fmt.Errorf("%d of %d consecutive health checks passed", successes, config.SuccessThreshold)
 		}
+		// Health check succeeded
+		process.Ready = true
+		return nil
+	}
+
+	return backoff.Retry(operation, b)
+}
+
+// probeServiceHealth runs a single health check of the service's configured type.
+// Services with health check type "none" are always healthy.
+func probeServiceHealth(process *ServiceProcess) error {
+	config := process.Runtime.HealthCheck
+
+	// A port configured for the health check takes precedence over the service port
+	port := process.Port
+	if config.Port > 0 {
+		port = config.Port
+	}
+	httpTimeout, dialTimeout := HTTPClientTimeout, ConnectionTimeout
+	if config.ProbeTimeout > 0 {
+		httpTimeout, dialTimeout = config.ProbeTimeout, config.ProbeTimeout
+	}
 
+	switch config.Type {
+	case ServiceTypeHTTP:
+		return httpHealthCheck(process.Runtime.URLScheme(), port, config.Path, httpTimeout)
+	case "tcp":
+		err := portHealthCheck(port, dialTimeout)
+		if err == nil && len(config.Probe) > 0 && process.ContainerID != "" {
+			// The port opens before brokers and databases accept work
+			err = ContainerProbeHealthCheck(process.ContainerID, config.Probe)
+		}
 		return err
+	case "process":
+		return ProcessHealthCheck(process)
+	case "output", "log":
+		// Output-based health check: check if the pattern has been matched in logs
+		return OutputHealthCheck(process, config.LogMatch)
+	case "exec":
+		if process.ContainerID != "" {
+			return ContainerProbeHealthCheck(process.ContainerID, config.Command)
+		}
+		return ExecHealthCheck(process, config.Command, config.ProbeTimeout)
+	case "none":
+		return nil
+	default:
+		// Default to HTTP health check if port is available, otherwise process check
+		if port > 0 {
+			return httpHealthCheck(process.Runtime.URLScheme(), port, config.Path, httpTimeout)
+		}
+		return ProcessHealthCheck(process)
 	}
+}
 
-	return backoff.Retry(operation, b)
+// DefaultExecHealthCheckTimeout is the maximum duration of an exec health check without a configured timeout.
+const DefaultExecHealthCheckTimeout = 30 * time.Second
+
+// ExecHealthCheck runs a health check command on the host in the service's working directory
+// and environment. The command uses the docker-compose test format: ["CMD", args...] or
+// ["CMD-SHELL", command]. Exit code 0 means healthy.
+func ExecHealthCheck(process *ServiceProcess, command []string, timeout time.Duration) error {
+	if len(command) < 2 {
+		return fmt.Errorf("invalid health check command %v", command)
+	}
+	if timeout <= 0 {
+		timeout = DefaultExecHealthCheckTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch command[0] {
+	case "CMD":
+		cmd = exec.CommandContext(ctx, command[1], command[2:]...)
+	case "CMD-SHELL":
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", command[1])
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", command[1])
+		}
+	default:
+		return fmt.Errorf("unsupported health check command format %q", command[0])
+	}
+
+	cmd.Dir = process.Runtime.WorkingDir
+	cmd.Env = os.Environ()
+	for key, value := range process.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("health check command timed out after %s", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("health check command failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
 }
 
 // OutputHealthCheck checks if a specific pattern has been matched in the process output.
@@ -118,19 +204,59 @@ func OutputHealthCheck(process *ServiceProcess, pattern string) error {
 	return fmt.Errorf("pattern %q not found in output", pattern)
 }
 
+// ContainerProbeHealthCheck runs a readiness probe inside a container.
+// The probe uses the docker-compose test format: ["CMD", args...] or ["CMD-SHELL", command].
+func ContainerProbeHealthCheck(containerID string, probe []string) error {
+	if len(probe) < 2 {
+		return fmt.Errorf("invalid container probe %v", probe)
+	}
+
+	client := docker.NewClient()
+	var exitCode int
+	var output string
+	var err error
+	switch probe[0] {
+	case "CMD":
+		exitCode, output, err = client.Exec(containerID, probe[1:])
+	case "CMD-SHELL":
+		exitCode, output, err = client.ExecShell(containerID, probe[1])
+	default:
+		return fmt.Errorf("unsupported container probe format %q", probe[0])
+	}
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("readiness probe exited with code %d: %s", exitCode, output)
+	}
+	return nil
+}
+
 // HTTPHealthCheck attempts HTTP requests to verify service is ready.
 func HTTPHealthCheck(port int, path string) error {
+	return httpHealthCheck(ServiceTypeHTTP, port, path, HTTPClientTimeout)
+}
+
+// httpHealthCheck is HTTPHealthCheck with a URL scheme ("http" or "https") and a custom request timeout.
+func httpHealthCheck(scheme string, port int, path string, timeout time.Duration) error {
 	// Build URL
-	url := fmt.Sprintf("http://localhost:%d%s", port, path)
+	url := fmt.Sprintf("%s://localhost:%d%s", scheme, port, path)
 
 	// Create HTTP client with timeout
 	client := &http.Client{
-		Timeout: HTTPClientTimeout,
+		Timeout: timeout,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			// Don't follow redirects
 			return http.ErrUseLastResponse
 		},
 	}
+	if scheme == ProtocolHTTPS {
+		// The development certificate may be self-signed and not trusted by this process
+		client.Transport = &http.Transport{
+			// #nosec G402 -- health checks only connect to localhost
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12},
+		}
+	}
 
 	ctx := context.Background()
 
@@ -169,8 +295,13 @@ func HTTPHealthCheck(port int, path string) error {
 
 // PortHealthCheck verifies that a port is listening.
 func PortHealthCheck(port int) error {
+	return portHealthCheck(port, ConnectionTimeout)
+}
+
+// portHealthCheck is PortHealthCheck with a custom connection timeout.
+func portHealthCheck(port int, timeout time.Duration) error {
 	address := fmt.Sprintf("localhost:%d", port)
-	dialer := net.Dialer{Timeout: ConnectionTimeout}
+	dialer := net.Dialer{Timeout: timeout}
 	conn, err := dialer.DialContext(context.Background(), "tcp", address)
 	if err != nil {
 		return fmt.Errorf("port %d not listening: %w", port, err)
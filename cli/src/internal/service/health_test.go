@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -370,3 +371,65 @@ func TestPerformHealthCheck_ProcessType(t *testing.T) {
 		t.Error("PerformHealthCheck() process.Ready = false, want true")
 	}
 }
+
+func TestPerformHealthCheck_SuccessThreshold(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping health check test in short mode")
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	process := &ServiceProcess{
+		Name: "test-service",
+		Runtime: ServiceRuntime{
+			Name: "test-service",
+			HealthCheck: HealthCheckConfig{
+				Type:             "http",
+				Path:             "/",
+				Port:             server.Listener.Addr().(*net.TCPAddr).Port, // Takes precedence over the service port
+				Timeout:          constants.TestServiceTimeout,
+				Interval:         100 * time.Millisecond,
+				SuccessThreshold: 3,
+			},
+		},
+		Port: 64998,
+	}
+
+	if err := PerformHealthCheck(process); err != nil {
+		t.Fatalf("PerformHealthCheck() error = %v, want nil", err)
+	}
+	if !process.Ready {
+		t.Error("PerformHealthCheck() process.Ready = false, want true")
+	}
+	// HEAD succeeds, so each check makes a single request
+	if got := requests.Load(); got != 3 {
+		t.Errorf("health check requests = %d, want 3", got)
+	}
+}
+
+func TestExecHealthCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh commands")
+	}
+
+	process := &ServiceProcess{
+		Name:    "test-service",
+		Runtime: ServiceRuntime{WorkingDir: t.TempDir()},
+		Env:     map[string]string{"HEALTH_EXIT": "3"},
+	}
+
+	if err := ExecHealthCheck(process, []string{"CMD", "true"}, 0); err != nil {
+		t.Errorf("ExecHealthCheck(true) error = %v, want nil", err)
+	}
+	if err := ExecHealthCheck(process, []string{"CMD-SHELL", "exit $HEALTH_EXIT"}, 0); err == nil {
+		t.Error("ExecHealthCheck() expected error for non-zero exit code")
+	}
+	if err := ExecHealthCheck(process, []string{"CMD", "sleep", "5"}, 100*time.Millisecond); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("ExecHealthCheck() error = %v, want timeout error", err)
+	}
+}
@@ -2,7 +2,9 @@
 package service
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -336,3 +338,122 @@ func TestPerformHealthCheck_NoneType(t *testing.T) {
 		t.Error("PerformHealthCheck() did not set process.Ready to true for type 'none'")
 	}
 }
+
+func TestService_UnmarshalYAML_HealthcheckProbes(t *testing.T) {
+	yamlContent := `
+project: ./api
+healthCheck:
+  type: log
+  pattern: Listening on
+  port: 9090
+  interval: 15s
+  timeout: 3s
+  start_period: 30s
+  retries: 5
+  successThreshold: 2
+  restartOnFailure: false
+`
+	var service Service
+	if err := yaml.Unmarshal([]byte(yamlContent), &service); err != nil {
+		t.Fatalf("Failed to unmarshal YAML: %v", err)
+	}
+	if service.Healthcheck == nil {
+		t.Fatal("healthCheck should be parsed like healthcheck")
+	}
+
+	check := HealthCheckConfig{Type: "log", Path: "/", Timeout: 60 * time.Second, Interval: 2 * time.Second}
+	applyHealthcheck(&check, service.Healthcheck)
+
+	want := HealthCheckConfig{
+		Type:             "output",
+		Path:             "/",
+		Port:             9090,
+		Timeout:          90 * time.Second,
+		Interval:         2 * time.Second,
+		LogMatch:         "Listening on",
+		ProbeTimeout:     3 * time.Second,
+		SuccessThreshold: 2,
+		LivenessInterval: 15 * time.Second,
+		FailureThreshold: 5,
+		RestartOnFailure: false,
+	}
+	if !reflect.DeepEqual(check, want) {
+		t.Errorf("applyHealthcheck() = %+v, want %+v", check, want)
+	}
+}
+
+func TestApplyHealthcheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *HealthcheckConfig
+		want   HealthCheckConfig
+	}{
+		{
+			name: "no healthcheck",
+			want: HealthCheckConfig{Type: "http", Path: "/"},
+		},
+		{
+			name:   "disabled",
+			config: &HealthcheckConfig{Disable: true},
+			want:   HealthCheckConfig{Type: "http", Path: "/"},
+		},
+		{
+			name:   "liveness defaults",
+			config: &HealthcheckConfig{Path: "/healthz"},
+			want: HealthCheckConfig{
+				Type:             "http",
+				Path:             "/healthz",
+				LivenessInterval: DefaultLivenessInterval,
+				FailureThreshold: DefaultLivenessFailureThreshold,
+				RestartOnFailure: true,
+			},
+		},
+		{
+			name:   "test command runs as exec",
+			config: &HealthcheckConfig{Test: []any{"CMD", "pg_isready"}, StartInterval: "1s"},
+			want: HealthCheckConfig{
+				Type:             "exec",
+				Path:             "/",
+				Interval:         time.Second,
+				Command:          []string{"CMD", "pg_isready"},
+				LivenessInterval: DefaultLivenessInterval,
+				FailureThreshold: DefaultLivenessFailureThreshold,
+				RestartOnFailure: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := HealthCheckConfig{Type: "http", Path: "/"}
+			applyHealthcheck(&check, tt.config)
+			if !reflect.DeepEqual(check, tt.want) {
+				t.Errorf("applyHealthcheck() = %+v, want %+v", check, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealthcheckConfig_GetCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		test any
+		want []string
+	}{
+		{name: "none", want: nil},
+		{name: "http URL", test: "http://localhost:8080/health", want: nil},
+		{name: "shell string", test: "curl -f localhost", want: []string{"CMD-SHELL", "curl -f localhost"}},
+		{name: "CMD array", test: []any{"CMD", "redis-cli", "ping"}, want: []string{"CMD", "redis-cli", "ping"}},
+		{name: "CMD-SHELL array", test: []string{"CMD-SHELL", "exit 0"}, want: []string{"CMD-SHELL", "exit 0"}},
+		{name: "NONE", test: []any{"NONE"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := &HealthcheckConfig{Test: tt.test}
+			if got := hc.GetCommand(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -511,3 +511,134 @@ services:
 		t.Errorf("Expected POSIX Shell='zsh', got: %s", prerun.Posix.Shell)
 	}
 }
+
+func TestParseAzureYaml_WithServiceEventHooks(t *testing.T) {
+	yamlContent := `name: test-app
+
+hooks:
+  servicestarting:
+    run: ./scripts/clear-cache.sh
+  servicehealthchanged:
+    run: echo "$AZD_APP_SERVICE_NAME is $AZD_APP_SERVICE_HEALTH"
+    continueOnError: true
+  serviceready:
+    run: ./scripts/seed.sh
+  servicecrashed:
+    run: ./scripts/notify.sh
+  predeps:
+    run: ./scripts/generate-clients.sh
+
+services:
+  web:
+    language: TypeScript
+    project: ./frontend
+`
+
+	tmpDir := t.TempDir()
+	yamlPath := filepath.Join(tmpDir, "azure.yaml")
+	if err := os.WriteFile(yamlPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	azureYaml, err := ParseAzureYaml(yamlPath)
+	if err != nil {
+		t.Fatalf("Failed to parse azure.yaml: %v", err)
+	}
+
+	if hook := azureYaml.Hooks.GetServiceHook(EventServiceStarting); hook == nil || hook.Run != "./scripts/clear-cache.sh" {
+		t.Errorf("servicestarting hook = %+v, want run ./scripts/clear-cache.sh", hook)
+	}
+	if hook := azureYaml.Hooks.GetServiceHook(EventServiceHealthChanged); hook == nil || !hook.ContinueOnError {
+		t.Errorf("servicehealthchanged hook = %+v, want continueOnError", hook)
+	}
+	if hook := azureYaml.Hooks.GetServiceHook(EventServiceStopped); hook != nil {
+		t.Errorf("servicestopped hook = %+v, want nil", hook)
+	}
+	if hook := azureYaml.Hooks.GetServiceHook(EventServiceReady); hook == nil || hook.Run != "./scripts/seed.sh" {
+		t.Errorf("serviceready hook = %+v, want run ./scripts/seed.sh", hook)
+	}
+	if hook := azureYaml.Hooks.GetServiceHook(EventServiceCrashed); hook == nil || hook.Run != "./scripts/notify.sh" {
+		t.Errorf("servicecrashed hook = %+v, want run ./scripts/notify.sh", hook)
+	}
+	if hook := azureYaml.Hooks.GetPredeps(); hook == nil || hook.Run != "./scripts/generate-clients.sh" {
+		t.Errorf("predeps hook = %+v, want run ./scripts/generate-clients.sh", hook)
+	}
+
+	var nilHooks *Hooks
+	if hook := nilHooks.GetServiceHook(EventServiceStarted); hook != nil {
+		t.Errorf("nil hooks returned %+v", hook)
+	}
+	if hook := nilHooks.GetPredeps(); hook != nil {
+		t.Errorf("nil hooks returned predeps %+v", hook)
+	}
+}
+
+func TestParseAzureYaml_WithServiceCommands(t *testing.T) {
+	yamlContent := `name: test-app
+
+services:
+  api:
+    project: ./api
+    run: npm run dev:api
+    command: ignored
+    build: npm run build
+    preRun: npm run db:migrate
+    postStop:
+      run: docker compose down
+      continueOnError: true
+  web:
+    project: ./web
+    language: TypeScript
+`
+
+	tmpDir := t.TempDir()
+	yamlPath := filepath.Join(tmpDir, "azure.yaml")
+	if err := os.WriteFile(yamlPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	azureYaml, err := ParseAzureYaml(yamlPath)
+	if err != nil {
+		t.Fatalf("Failed to parse azure.yaml: %v", err)
+	}
+
+	api := azureYaml.Services["api"]
+	if entrypoint, command := api.RunCommand(); entrypoint != "" || command != "npm run dev:api" {
+		t.Errorf("RunCommand() = %q, %q, want run to win", entrypoint, command)
+	}
+	if api.Build != "npm run build" {
+		t.Errorf("Expected build='npm run build', got: %s", api.Build)
+	}
+	if api.PreRun == nil || api.PreRun.Run != "npm run db:migrate" {
+		t.Errorf("preRun = %+v, want run npm run db:migrate", api.PreRun)
+	}
+	if api.PostStop == nil || api.PostStop.Run != "docker compose down" || !api.PostStop.ContinueOnError {
+		t.Errorf("postStop = %+v, want run docker compose down with continueOnError", api.PostStop)
+	}
+
+	web := azureYaml.Services["web"]
+	if web.HasExplicitCommand() || web.PreRun != nil || web.PostStop != nil {
+		t.Errorf("web = %+v, want no explicit commands", web)
+	}
+}
+
+func TestParseAzureYaml_InvalidServiceHook(t *testing.T) {
+	yamlContent := `name: test-app
+
+services:
+  api:
+    project: ./api
+    preRun: [npm, run, migrate]
+`
+
+	tmpDir := t.TempDir()
+	yamlPath := filepath.Join(tmpDir, "azure.yaml")
+	if err := os.WriteFile(yamlPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	_, err := ParseAzureYaml(yamlPath)
+	if err == nil || !strings.Contains(err.Error(), "preRun") {
+		t.Errorf("ParseAzureYaml() error = %v, want invalid preRun hook", err)
+	}
+}
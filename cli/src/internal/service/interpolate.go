@@ -0,0 +1,219 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// interpolationDefaultSep separates a variable name from its default value: ${VAR:-default}.
+const interpolationDefaultSep = ":-"
+
+// interpolationEnv holds the variables from --env-file used to resolve ${VAR} references in azure.yaml.
+var interpolationEnv = struct {
+	mu   sync.RWMutex
+	vars map[string]string
+}{}
+
+// SetInterpolationEnv sets extra variables (e.g. from --env-file) used to resolve ${VAR}
+// references in azure.yaml. They take precedence over the process environment and apply to
+// every azure.yaml parsed afterwards in this process.
+func SetInterpolationEnv(vars map[string]string) {
+	interpolationEnv.mu.Lock()
+	defer interpolationEnv.mu.Unlock()
+
+	interpolationEnv.vars = make(map[string]string, len(vars))
+	for k, v := range vars {
+		interpolationEnv.vars[k] = v
+	}
+}
+
+// lookupInterpolationVar resolves a variable from the --env-file variables, then the process environment.
+func lookupInterpolationVar(name string) (string, bool) {
+	interpolationEnv.mu.RLock()
+	value, ok := interpolationEnv.vars[name]
+	interpolationEnv.mu.RUnlock()
+	if ok {
+		return value, true
+	}
+	return os.LookupEnv(name)
+}
+
+// UnresolvedVariable is a ${VAR} reference in azure.yaml to a variable that is not set.
+type UnresolvedVariable struct {
+	Name string // Variable name
+	Path string // Location in azure.yaml, e.g. services.api.project
+	Line int    // Line in azure.yaml
+}
+
+// UnresolvedVariablesError is returned when azure.yaml references variables that are not set
+// and have no default.
+type UnresolvedVariablesError struct {
+	Variables []UnresolvedVariable
+}
+
+func (e *UnresolvedVariablesError) Error() string {
+	refs := make([]string, 0, len(e.Variables))
+	for _, v := range e.Variables {
+		refs = append(refs, fmt.Sprintf("${%s} (%s, line %d)", v.Name, v.Path, v.Line))
+	}
+	return fmt.Sprintf("references to unset variables: %s - set them in the environment or --env-file, or add a default with ${VAR:-default}",
+		strings.Join(refs, ", "))
+}
+
+// UnmarshalAzureYaml parses azure.yaml content into out after replacing ${VAR} and
+// ${VAR:-default} references with values from --env-file and the process environment.
+func UnmarshalAzureYaml(data []byte, out interface{}) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if err := InterpolateYaml(&root, lookupInterpolationVar); err != nil {
+		return err
+	}
+	return root.Decode(out)
+}
+
+// InterpolateYaml replaces ${VAR} and ${VAR:-default} references in the string values of a
+// YAML document. $${VAR} is kept as a literal ${VAR}. References that aren't valid variable
+// names (e.g. shell syntax like ${@}) are left unchanged.
+//
+// In environment blocks, a reference to another variable of the same block that isn't set
+// is left in place; it is resolved when the service starts.
+// Returns an *UnresolvedVariablesError listing every other variable that isn't set.
+func InterpolateYaml(root *yaml.Node, lookup func(name string) (string, bool)) error {
+	var unresolved []UnresolvedVariable
+	interpolateNode(root, "", nil, lookup, &unresolved)
+	if len(unresolved) > 0 {
+		return &UnresolvedVariablesError{Variables: unresolved}
+	}
+	return nil
+}
+
+// interpolateNode interpolates a node and its children. envNames contains the variables
+// defined by the enclosing environment block, if any.
+func interpolateNode(node *yaml.Node, path string, envNames map[string]bool, lookup func(string) (string, bool), unresolved *[]UnresolvedVariable) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for i, child := range node.Content {
+			childPath := path
+			if node.Kind == yaml.SequenceNode {
+				childPath = path + "[" + strconv.Itoa(i) + "]"
+			}
+			interpolateNode(child, childPath, envNames, lookup, unresolved)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			childEnvNames := envNames
+			if key == "environment" || key == "env" {
+				childEnvNames = environmentNames(value)
+			}
+			interpolateNode(value, childPath, childEnvNames, lookup, unresolved)
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return
+		}
+		value, missing := expandVariables(node.Value, lookup, envNames)
+		node.Value = value
+		if node.Style == 0 && node.Tag == "!!str" {
+			// Re-resolve plain scalars so `port: ${PORT}` decodes as a number
+			node.Tag = ""
+		}
+		for _, name := range missing {
+			*unresolved = append(*unresolved, UnresolvedVariable{Name: name, Path: path, Line: node.Line})
+		}
+	}
+}
+
+// environmentNames returns the variable names defined by an environment block in any of
+// its formats: a map, "KEY=value" strings, or {name: KEY, value: ...} objects.
+func environmentNames(node *yaml.Node) map[string]bool {
+	names := make(map[string]bool)
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			names[node.Content[i].Value] = true
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			switch item.Kind {
+			case yaml.ScalarNode:
+				name, _, _ := strings.Cut(item.Value, "=")
+				names[name] = true
+			case yaml.MappingNode:
+				for i := 0; i+1 < len(item.Content); i += 2 {
+					if item.Content[i].Value == "name" {
+						names[item.Content[i+1].Value] = true
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// expandVariables replaces ${VAR} and ${VAR:-default} references in s.
+// Unset variables without a default are left in place and returned, except when they are
+// in keep (variables of the enclosing environment block), which are only left in place.
+func expandVariables(s string, lookup func(string) (string, bool), keep map[string]bool) (string, []string) {
+	var b strings.Builder
+	var missing []string
+
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			b.WriteString(s)
+			break
+		}
+
+		// $${VAR} escapes a literal ${VAR}
+		if start > 0 && s[start-1] == '$' {
+			b.WriteString(s[:start-1])
+			b.WriteString("${")
+			s = s[start+2:]
+			continue
+		}
+
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			b.WriteString(s)
+			break
+		}
+		end += start
+
+		b.WriteString(s[:start])
+		ref := s[start : end+1]
+		name, def, hasDefault := strings.Cut(s[start+2:end], interpolationDefaultSep)
+		s = s[end+1:]
+
+		if !isValidEnvVarName(name) {
+			b.WriteString(ref)
+			continue
+		}
+
+		value, ok := lookup(name)
+		switch {
+		case ok && (value != "" || !hasDefault):
+			b.WriteString(value)
+		case hasDefault:
+			b.WriteString(def)
+		default:
+			b.WriteString(ref)
+			if !keep[name] {
+				missing = append(missing, name)
+			}
+		}
+	}
+
+	return b.String(), missing
+}
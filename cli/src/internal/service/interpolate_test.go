@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExpandVariables(t *testing.T) {
+	vars := map[string]string{"HOST": "db.local", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+
+	tests := []struct {
+		name        string
+		value       string
+		keep        map[string]bool
+		want        string
+		wantMissing []string
+	}{
+		{name: "set variable", value: "postgres://${HOST}:5432", want: "postgres://db.local:5432"},
+		{name: "default for unset variable", value: "${PORT:-8080}", want: "8080"},
+		{name: "default for empty variable", value: "${EMPTY:-fallback}", want: "fallback"},
+		{name: "empty variable without default", value: "[${EMPTY}]", want: "[]"},
+		{name: "default ignored for set variable", value: "${HOST:-localhost}", want: "db.local"},
+		{name: "empty default", value: "${PORT:-}", want: ""},
+		{name: "unset variable", value: "x-${MISSING}-y", want: "x-${MISSING}-y", wantMissing: []string{"MISSING"}},
+		{name: "unset variable of the environment block", value: "${SIBLING}", keep: map[string]bool{"SIBLING": true}, want: "${SIBLING}"},
+		{name: "escaped reference", value: "echo $${HOST}", want: "echo ${HOST}"},
+		{name: "shell syntax is left alone", value: "echo ${@} ${#ARR}", want: "echo ${@} ${#ARR}"},
+		{name: "unterminated reference", value: "${HOST", want: "${HOST"},
+		{name: "several references", value: "${HOST}/${MISSING}/${OTHER}", want: "db.local/${MISSING}/${OTHER}", wantMissing: []string{"MISSING", "OTHER"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, missing := expandVariables(tt.value, lookup, tt.keep)
+			if got != tt.want {
+				t.Errorf("expandVariables(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+			if strings.Join(missing, ",") != strings.Join(tt.wantMissing, ",") {
+				t.Errorf("expandVariables(%q) missing = %v, want %v", tt.value, missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestInterpolateYaml_UnresolvedVariables(t *testing.T) {
+	content := `name: app
+services:
+  api:
+    project: ./${API_DIR}
+    environment:
+      DB_HOST: localhost
+      DB_URL: postgres://${DB_HOST}:${DB_PORT}
+`
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		t.Fatalf("failed to parse YAML: %v", err)
+	}
+
+	err := InterpolateYaml(&root, func(string) (string, bool) { return "", false })
+
+	var unresolvedErr *UnresolvedVariablesError
+	if !errors.As(err, &unresolvedErr) {
+		t.Fatalf("InterpolateYaml() error = %v, want *UnresolvedVariablesError", err)
+	}
+	// DB_HOST is defined by the environment block itself and resolved when the service starts
+	want := []UnresolvedVariable{
+		{Name: "API_DIR", Path: "services.api.project", Line: 4},
+		{Name: "DB_PORT", Path: "services.api.environment.DB_URL", Line: 7},
+	}
+	if len(unresolvedErr.Variables) != len(want) {
+		t.Fatalf("unresolved = %+v, want %+v", unresolvedErr.Variables, want)
+	}
+	for i := range want {
+		if unresolvedErr.Variables[i] != want[i] {
+			t.Errorf("unresolved[%d] = %+v, want %+v", i, unresolvedErr.Variables[i], want[i])
+		}
+	}
+	if !strings.Contains(err.Error(), "${API_DIR} (services.api.project, line 4)") {
+		t.Errorf("error message %q should name the variable and its location", err.Error())
+	}
+}
+
+func TestEnvironmentNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"map", "A: 1\nB: x"},
+		{"strings", "- A=1\n- B"},
+		{"objects", "- name: A\n  value: 1\n- name: B\n  secret: x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var root yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.content), &root); err != nil {
+				t.Fatalf("failed to parse YAML: %v", err)
+			}
+			names := environmentNames(root.Content[0])
+			if len(names) != 2 || !names["A"] || !names["B"] {
+				t.Errorf("environmentNames() = %v, want A and B", names)
+			}
+		})
+	}
+}
+
+func TestParseAzureYaml_Interpolation(t *testing.T) {
+	t.Setenv("AZD_APP_TEST_API_DIR", "backend")
+	SetInterpolationEnv(map[string]string{"AZD_APP_TEST_PORT": "7000"})
+	t.Cleanup(func() { SetInterpolationEnv(nil) })
+
+	content := `name: ${AZD_APP_TEST_NAME:-app}
+services:
+  api:
+    language: python
+    project: ./${AZD_APP_TEST_API_DIR}
+    ports:
+      - "${AZD_APP_TEST_PORT}"
+    environment:
+      LOG_LEVEL: ${AZD_APP_TEST_LOG_LEVEL:-info}
+      GREETING: hello
+      MESSAGE: ${GREETING} world
+`
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "azure.yaml"), []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write azure.yaml: %v", err)
+	}
+
+	azureYaml, err := ParseAzureYaml(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseAzureYaml() error = %v", err)
+	}
+
+	api := azureYaml.Services["api"]
+	if want := filepath.Join(tmpDir, "backend"); api.Project != want {
+		t.Errorf("Project = %q, want %q", api.Project, want)
+	}
+	if len(api.Ports) != 1 || api.Ports[0] != "7000" {
+		t.Errorf("Ports = %v, want [7000] from the env file variables", api.Ports)
+	}
+	env := api.GetEnvironment()
+	if env["LOG_LEVEL"] != "info" {
+		t.Errorf("LOG_LEVEL = %q, want default %q", env["LOG_LEVEL"], "info")
+	}
+	if env["MESSAGE"] != "${GREETING} world" {
+		t.Errorf("MESSAGE = %q, want the reference kept for start-time substitution", env["MESSAGE"])
+	}
+	if azureYaml.Name != "app" {
+		t.Errorf("Name = %q, want default %q", azureYaml.Name, "app")
+	}
+}
+
+func TestParseAzureYaml_UnresolvedVariable(t *testing.T) {
+	content := `name: app
+services:
+  api:
+    language: python
+    project: ./${AZD_APP_TEST_UNSET_DIR}
+`
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "azure.yaml"), []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write azure.yaml: %v", err)
+	}
+
+	_, err := ParseAzureYaml(tmpDir)
+	var unresolvedErr *UnresolvedVariablesError
+	if !errors.As(err, &unresolvedErr) {
+		t.Fatalf("ParseAzureYaml() error = %v, want *UnresolvedVariablesError", err)
+	}
+}
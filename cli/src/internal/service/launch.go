@@ -0,0 +1,71 @@
+package service
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LaunchConfig is the configuration a service was last started with in this process:
+// the resolved command line and the effective environment of the process.
+type LaunchConfig struct {
+	Service    string            `json:"service"`
+	Type       string            `json:"type,omitempty"`
+	Language   string            `json:"language,omitempty"`
+	Framework  string            `json:"framework,omitempty"`
+	Command    string            `json:"command"` // Executable, or the image of container services
+	Args       []string          `json:"args"`
+	WorkingDir string            `json:"workingDir,omitempty"`
+	Port       int               `json:"port,omitempty"`
+	Env        map[string]string `json:"env"`
+	StartedAt  time.Time         `json:"startedAt"`
+}
+
+// launchConfigs holds the last launch configuration of each service, keyed by launchKey.
+var launchConfigs = struct {
+	mu      sync.RWMutex
+	configs map[string]LaunchConfig
+}{configs: make(map[string]LaunchConfig)}
+
+// launchKey identifies a service of a project.
+func launchKey(projectDir, serviceName string) string {
+	if abs, err := filepath.Abs(projectDir); err == nil {
+		projectDir = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(projectDir); err == nil {
+		projectDir = resolved
+	}
+	return projectDir + "\x00" + serviceName
+}
+
+// recordLaunch remembers how a service was started, for GetLaunchConfig.
+func recordLaunch(projectDir string, runtime *ServiceRuntime, env map[string]string) {
+	config := LaunchConfig{
+		Service:    runtime.Name,
+		Type:       runtime.Type,
+		Language:   runtime.Language,
+		Framework:  runtime.Framework,
+		Command:    runtime.Command,
+		Args:       append([]string{}, runtime.Args...),
+		WorkingDir: runtime.WorkingDir,
+		Port:       runtime.Port,
+		Env:        make(map[string]string, len(env)),
+		StartedAt:  time.Now(),
+	}
+	for k, v := range env {
+		config.Env[k] = v
+	}
+
+	launchConfigs.mu.Lock()
+	defer launchConfigs.mu.Unlock()
+	launchConfigs.configs[launchKey(projectDir, runtime.Name)] = config
+}
+
+// GetLaunchConfig returns the configuration a service was last started with by this process.
+// The environment is returned as is; mask it with MaskSecrets before displaying it.
+func GetLaunchConfig(projectDir, serviceName string) (LaunchConfig, bool) {
+	launchConfigs.mu.RLock()
+	defer launchConfigs.mu.RUnlock()
+	config, ok := launchConfigs.configs[launchKey(projectDir, serviceName)]
+	return config, ok
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// livenessTick is how often WatchServiceLiveness looks for services that are due for a check.
+const livenessTick = time.Second
+
+// HasLivenessChecks reports whether any of the processes is checked once ready.
+func HasLivenessChecks(processes map[string]*ServiceProcess) bool {
+	for _, process := range processes {
+		if hasLivenessCheck(process) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLivenessCheck reports whether a process is checked once ready. Build and task services
+// are expected to exit, so only long-running services are checked.
+func hasLivenessCheck(process *ServiceProcess) bool {
+	if process == nil || process.Runtime.HealthCheck.LivenessInterval <= 0 || process.Runtime.HealthCheck.Type == watchModeNone {
+		return false
+	}
+	return process.Runtime.Mode != ServiceModeBuild && process.Runtime.Mode != ServiceModeTask
+}
+
+// livenessState tracks the liveness checks of one service process.
+type livenessState struct {
+	process   *ServiceProcess
+	nextCheck time.Time
+	live      bool // A check has passed, so failures count towards the threshold
+	failures  int
+}
+
+// WatchServiceLiveness checks the services returned by processes every LivenessInterval of
+// their health check and calls onFailure when FailureThreshold consecutive checks fail.
+// Failures only count once a check has passed, so a slow start is left to the readiness check.
+// A new process for a service (e.g. after a restart) starts over.
+// It blocks until ctx is canceled.
+func WatchServiceLiveness(ctx context.Context, processes func() map[string]*ServiceProcess, onFailure func(name string, err error)) {
+	ticker := time.NewTicker(livenessTick)
+	defer ticker.Stop()
+
+	states := make(map[string]*livenessState)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			checkServiceLiveness(now, processes(), states, onFailure)
+		}
+	}
+}
+
+// checkServiceLiveness checks every service that is due and calls onFailure for each service
+// that reached its failure threshold. states is updated in place.
+func checkServiceLiveness(now time.Time, processes map[string]*ServiceProcess, states map[string]*livenessState, onFailure func(name string, err error)) {
+	for name, state := range states {
+		if processes[name] != state.process {
+			delete(states, name)
+		}
+	}
+
+	for name, process := range processes {
+		if !hasLivenessCheck(process) {
+			continue
+		}
+		check := process.Runtime.HealthCheck
+
+		state, ok := states[name]
+		if !ok {
+			state = &livenessState{process: process, nextCheck: now.Add(check.LivenessInterval)}
+			states[name] = state
+			continue
+		}
+		if now.Before(state.nextCheck) {
+			continue
+		}
+		state.nextCheck = now.Add(check.LivenessInterval)
+
+		err := probeServiceHealth(process)
+		if err == nil {
+			state.live = true
+			state.failures = 0
+			continue
+		}
+		if !state.live {
+			continue
+		}
+
+		state.failures++
+		slog.Debug("liveness check failed",
+			slog.String("service", name),
+			slog.Int("failures", state.failures),
+			slog.String("error", err.Error()))
+		if state.failures >= check.FailureThreshold {
+			state.failures = 0
+			state.live = false
+			onFailure(name, err)
+		}
+	}
+}
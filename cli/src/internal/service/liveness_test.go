@@ -0,0 +1,98 @@
+package service
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckServiceLiveness(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	process := &ServiceProcess{
+		Name: "api",
+		Port: server.Listener.Addr().(*net.TCPAddr).Port,
+		Runtime: ServiceRuntime{
+			Name: "api",
+			HealthCheck: HealthCheckConfig{
+				Type:             "http",
+				Path:             "/",
+				LivenessInterval: time.Second,
+				FailureThreshold: 2,
+			},
+		},
+	}
+	processes := map[string]*ServiceProcess{"api": process}
+
+	var failures []string
+	onFailure := func(name string, err error) { failures = append(failures, name) }
+
+	states := make(map[string]*livenessState)
+	now := time.Now()
+	tick := func() {
+		now = now.Add(time.Second)
+		checkServiceLiveness(now, processes, states, onFailure)
+	}
+
+	tick() // Schedules the first check
+	tick() // Passes, so the service is live
+	healthy.Store(false)
+	tick()
+	if len(failures) != 0 {
+		t.Fatalf("onFailure called after 1 failed check, threshold is 2")
+	}
+	tick()
+	if len(failures) != 1 || failures[0] != "api" {
+		t.Fatalf("onFailure calls = %v, want [api]", failures)
+	}
+
+	// Failures don't count again until a check passes
+	tick()
+	tick()
+	if len(failures) != 1 {
+		t.Errorf("onFailure calls = %v, want no further calls before the service is live again", failures)
+	}
+
+	// A restarted process starts over
+	restarted := *process
+	processes["api"] = &restarted
+	tick()
+	if states["api"].process != &restarted {
+		t.Error("liveness state should track the restarted process")
+	}
+}
+
+func TestHasLivenessChecks(t *testing.T) {
+	live := HealthCheckConfig{Type: "http", LivenessInterval: time.Second}
+
+	tests := []struct {
+		name    string
+		process *ServiceProcess
+		want    bool
+	}{
+		{name: "nil", want: false},
+		{name: "no liveness", process: &ServiceProcess{Runtime: ServiceRuntime{HealthCheck: HealthCheckConfig{Type: "http"}}}, want: false},
+		{name: "liveness", process: &ServiceProcess{Runtime: ServiceRuntime{HealthCheck: live}}, want: true},
+		{name: "build mode", process: &ServiceProcess{Runtime: ServiceRuntime{Mode: ServiceModeBuild, HealthCheck: live}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasLivenessChecks(map[string]*ServiceProcess{"api": tt.process}); got != tt.want {
+				t.Errorf("HasLivenessChecks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
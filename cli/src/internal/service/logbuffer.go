@@ -14,9 +14,9 @@ import (
 )
 
 const (
-	// MaxLogFileSize is the maximum size of a log file before rotation (1MB)
+	// MaxLogFileSize is the default size of a log file before rotation (1MB)
 	MaxLogFileSize = 1 * 1024 * 1024
-	// MaxLogFileBackups is the number of backup log files to keep
+	// MaxLogFileBackups is the default number of backup log files to keep
 	MaxLogFileBackups = 2
 )
 
@@ -34,6 +34,8 @@ type LogBuffer struct {
 	fileMu          sync.Mutex
 	logFilter       *LogFilter // Optional filter for noisy log messages
 	currentFileSize int64      // Track current file size for rotation
+	maxFileSize     int64      // Size at which the log file is rotated
+	maxFileBackups  int        // Number of rotated log files to keep
 }
 
 // NewLogBuffer creates a new log buffer for a service.
@@ -43,17 +45,26 @@ func NewLogBuffer(serviceName string, maxSize int, enableFileLogging bool, proje
 
 // NewLogBufferWithFilter creates a new log buffer with optional log filtering.
 func NewLogBufferWithFilter(serviceName string, maxSize int, enableFileLogging bool, projectDir string, filter *LogFilter) (*LogBuffer, error) {
+	fileOpts := defaultLogFileOptions()
+	fileOpts.enabled = enableFileLogging
+	return newLogBuffer(serviceName, maxSize, projectDir, filter, fileOpts)
+}
+
+// newLogBuffer creates a log buffer that persists to a log file according to fileOpts.
+func newLogBuffer(serviceName string, maxSize int, projectDir string, filter *LogFilter, fileOpts logFileOptions) (*LogBuffer, error) {
 	lb := &LogBuffer{
-		serviceName: serviceName,
-		entries:     make([]LogEntry, 0, maxSize),
-		maxSize:     maxSize,
-		subscribers: make(map[chan LogEntry]bool),
-		logFilter:   filter,
+		serviceName:    serviceName,
+		entries:        make([]LogEntry, 0, maxSize),
+		maxSize:        maxSize,
+		subscribers:    make(map[chan LogEntry]bool),
+		logFilter:      filter,
+		maxFileSize:    fileOpts.maxSize,
+		maxFileBackups: fileOpts.maxBackups,
 	}
 
 	// Setup file logging if enabled
-	if enableFileLogging {
-		logsDir := filepath.Join(projectDir, ".azure", "logs")
+	if fileOpts.enabled {
+		logsDir := LogsDir(projectDir)
 		// Use 0700 for directory permissions to match file privacy intent (0600)
 		// This ensures only the owner can access log files
 		if err := os.MkdirAll(logsDir, 0700); err != nil {
@@ -61,6 +72,7 @@ func NewLogBufferWithFilter(serviceName string, maxSize int, enableFileLogging b
 		}
 
 		lb.filePath = filepath.Join(logsDir, fmt.Sprintf("%s.log", serviceName))
+		removeExcessBackups(lb.filePath, lb.maxFileBackups)
 		file, err := os.OpenFile(lb.filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open log file: %w", err)
@@ -110,11 +122,11 @@ func (lb *LogBuffer) Add(entry LogEntry) {
 // writeToFile writes a log entry to the file (must be called with fileMu locked).
 func (lb *LogBuffer) writeToFile(entry LogEntry) {
 	// Check if rotation is needed
-	if lb.currentFileSize >= MaxLogFileSize {
+	if lb.currentFileSize >= lb.maxFileSize {
 		lb.rotateLogFile()
 	}
 
-	timestamp := entry.Timestamp.Format("2006-01-02 15:04:05.000")
+	timestamp := entry.Timestamp.Format(logFileTimestampLayout)
 	level := entry.Level.String()
 	stream := "OUT"
 	if entry.IsStderr {
@@ -145,19 +157,21 @@ func (lb *LogBuffer) rotateLogFile() {
 	}
 
 	// Rotate existing backup files (delete oldest, shift others)
-	for i := MaxLogFileBackups - 1; i >= 1; i-- {
+	for i := lb.maxFileBackups - 1; i >= 1; i-- {
 		oldPath := fmt.Sprintf("%s.%d", lb.filePath, i)
 		newPath := fmt.Sprintf("%s.%d", lb.filePath, i+1)
 		_ = os.Rename(oldPath, newPath) // Ignore errors - file may not exist
 	}
 
-	// Delete the oldest backup if it exceeds MaxLogFileBackups
-	oldestBackup := fmt.Sprintf("%s.%d", lb.filePath, MaxLogFileBackups+1)
+	// Delete the oldest backup if it exceeds maxFileBackups
+	oldestBackup := fmt.Sprintf("%s.%d", lb.filePath, lb.maxFileBackups+1)
 	_ = os.Remove(oldestBackup) // Ignore errors
 
-	// Rename current file to .1
-	if err := os.Rename(lb.filePath, lb.filePath+".1"); err != nil {
-		slog.Debug("failed to rotate log file", "error", err)
+	// Rename current file to .1, or drop it when no backups are kept
+	if lb.maxFileBackups > 0 {
+		if err := os.Rename(lb.filePath, lb.filePath+".1"); err != nil {
+			slog.Debug("failed to rotate log file", "error", err)
+		}
 	}
 
 	// Open new file
@@ -187,6 +201,11 @@ func (lb *LogBuffer) GetRecent(n int) []LogEntry {
 	return result
 }
 
+// persisted reports whether the buffer writes its entries to a log file.
+func (lb *LogBuffer) persisted() bool {
+	return lb.filePath != ""
+}
+
 // ContainsPattern checks if any log entry matches the given regex pattern.
 // Returns true if the pattern is found in any log message.
 func (lb *LogBuffer) ContainsPattern(pattern string) bool {
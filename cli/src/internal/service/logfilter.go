@@ -138,6 +138,8 @@ type LogsConfig struct {
 	Classifications []LogClassification `yaml:"classifications,omitempty" json:"classifications,omitempty"`
 	// Analytics is the global Azure Log Analytics configuration (workspace, polling, timespan)
 	Analytics *AnalyticsConfigGlobal `yaml:"analytics,omitempty" json:"analytics,omitempty"`
+	// Persist configures the log files written to .azure/logs (rotation, retention)
+	Persist *LogPersistConfig `yaml:"persist,omitempty" json:"persist,omitempty"`
 }
 
 // ServiceLogsConfig represents service-level logs configuration in azure.yaml.
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/output"
 )
 
 // ServiceLogger handles multiplexed log output from multiple services.
@@ -41,6 +43,7 @@ type ServiceURLSummary struct {
 	Name              string
 	LocalURL          string
 	LocalCustomURL    string
+	ForwardedURL      string // Where a browser reaches LocalURL in a codespace
 	AzureURL          string
 	AzureCustomURL    string
 	AzureCustomDomain string
@@ -177,7 +180,7 @@ func (l *ServiceLogger) LogStartup(serviceCount int) {
 }
 
 // LogSummary logs the service URLs after startup.
-// Displays each service once with all known URLs/domains.
+// Displays each service once with all known URLs/domains; quiet mode still shows them.
 func (l *ServiceLogger) LogSummary(summaries []ServiceURLSummary) {
 	if len(summaries) == 0 {
 		return
@@ -187,25 +190,27 @@ func (l *ServiceLogger) LogSummary(summaries []ServiceURLSummary) {
 		return summaries[i].Name < summaries[j].Name
 	})
 
-	fmt.Println()
+	w := output.Results()
+	_, _ = fmt.Fprintln(w)
 
 	for _, summary := range summaries {
-		fmt.Printf("  \033[32m✓\033[0m %s\n", summary.Name)
+		_, _ = fmt.Fprintf(w, "  \033[32m✓\033[0m %s\n", summary.Name)
 
 		printURL := func(label, value string) {
 			if strings.TrimSpace(value) == "" {
 				return
 			}
-			fmt.Printf("    %s %s\n", label, value)
+			_, _ = fmt.Fprintf(w, "    %s %s\n", label, value)
 		}
 
 		printURL("local:", summary.LocalURL)
 		printURL("custom:", summary.LocalCustomURL)
+		printURL("forwarded:", summary.ForwardedURL)
 		printURL("azure:", summary.AzureURL)
 		printURL("azure (custom):", summary.AzureCustomURL)
 		printURL("domain:", summary.AzureCustomDomain)
 
-		fmt.Println()
+		_, _ = fmt.Fprintln(w)
 	}
 }
 
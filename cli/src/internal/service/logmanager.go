@@ -4,6 +4,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
@@ -16,6 +17,7 @@ type LogManager struct {
 	projectDir string
 	buffers    map[string]*LogBuffer // key: serviceName
 	logFilter  *LogFilter            // Optional log filter for all buffers
+	fileOpts   logFileOptions        // Log file persistence settings for all buffers
 	mu         sync.RWMutex
 }
 
@@ -48,24 +50,32 @@ func GetLogManager(projectDir string) *LogManager {
 		return lm
 	}
 
+	logFilter, fileOpts := loadLogConfigForProject(absPath)
 	lm := &LogManager{
 		projectDir: absPath,
 		buffers:    make(map[string]*LogBuffer),
-		logFilter:  loadLogFilterForProject(absPath),
+		logFilter:  logFilter,
+		fileOpts:   fileOpts,
 	}
 	logManagers[absPath] = lm
 
+	if fileOpts.enabled {
+		if err := PruneLogFiles(absPath, fileOpts.retention); err != nil {
+			slog.Debug("failed to prune expired log files", "error", err)
+		}
+	}
+
 	return lm
 }
 
-// loadLogFilterForProject loads the log filter configuration from azure.yaml.
-func loadLogFilterForProject(projectDir string) *LogFilter {
+// loadLogConfigForProject loads the log filter and log file configuration from azure.yaml.
+func loadLogConfigForProject(projectDir string) (*LogFilter, logFileOptions) {
 	azureYamlPath := filepath.Join(projectDir, "azure.yaml")
 	azureYaml, err := ParseAzureYaml(azureYamlPath)
 	if err != nil {
-		// No azure.yaml or parse error - use built-in filters only
+		// No azure.yaml or parse error - use built-in filters and default log files only
 		filter, _ := NewLogFilterWithBuiltins(nil)
-		return filter
+		return filter, resolveLogFileOptions(nil)
 	}
 
 	// Get filter config from azure.yaml
@@ -78,7 +88,7 @@ func loadLogFilterForProject(projectDir string) *LogFilter {
 
 	// Always include built-in patterns per schema
 	filter, _ := NewLogFilterWithBuiltins(customPatterns)
-	return filter
+	return filter, resolveLogFileOptions(azureYaml.Logs.GetPersist())
 }
 
 // CreateBuffer creates a log buffer for a service.
@@ -91,8 +101,10 @@ func (lm *LogManager) CreateBuffer(serviceName string, maxSize int, enableFileLo
 		return buffer, nil
 	}
 
-	// Create new buffer with the log filter
-	buffer, err := NewLogBufferWithFilter(serviceName, maxSize, enableFileLogging, lm.projectDir, lm.logFilter)
+	// Create new buffer with the log filter; file logging also requires persistence to be enabled
+	fileOpts := lm.fileOpts
+	fileOpts.enabled = fileOpts.enabled && enableFileLogging
+	buffer, err := newLogBuffer(serviceName, maxSize, lm.projectDir, lm.logFilter, fileOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create log buffer for %s: %w", serviceName, err)
 	}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// jsonLevelKeys are the fields JSON loggers store the level in, in order of preference
+// (e.g. pino/bunyan "level", Google Cloud "severity", Serilog "@l", Python "levelname").
+var jsonLevelKeys = []string{"level", "lvl", "severity", "log.level", "loglevel", "levelname", "@l"}
+
+// levelPrefixPattern matches a level at the start of a line, optionally preceded by up to three
+// timestamp-like or bracketed tokens: "ERROR ...", "[WARN] ...", "info: ...", "WARNING:root:...",
+// "2024-01-15 10:30:45,123 DEBUG ...".
+var levelPrefixPattern = regexp.MustCompile(`^\s*(?:(?:\[[^\]]*\]|\S*\d\S*)\s+){0,3}[\[(<]?(?i:(trace|trce|verbose|debug|dbug|info|information|notice|warn|warning|error|err|fail|fatal|critical|crit|panic))[\])>]?(?::|\s|$)`)
+
+// logfmtLevelPattern matches the level of logfmt lines: `time=... level=error msg=...`.
+var logfmtLevelPattern = regexp.MustCompile(`(?:^|\s)(?:level|lvl)="?([A-Za-z]+)`)
+
+// ParseLogMessage detects the level of a line written by a service and, when the line is a
+// JSON object, returns its fields. The level comes from, in order: the level field of a JSON
+// line, a level prefix ("ERROR", "[WARN]", "info:"), a logfmt level=... pair, and finally
+// keywords anywhere in the message.
+func ParseLogMessage(message string) (LogLevel, map[string]interface{}) {
+	if fields := parseJSONLogFields(message); fields != nil {
+		if level, ok := jsonLogLevel(fields); ok {
+			return level, fields
+		}
+		return inferLogLevel(message), fields
+	}
+
+	if match := levelPrefixPattern.FindStringSubmatch(message); match != nil {
+		if level, ok := ParseLogLevel(match[1]); ok {
+			return level, nil
+		}
+	}
+
+	if match := logfmtLevelPattern.FindStringSubmatch(message); match != nil {
+		if level, ok := ParseLogLevel(match[1]); ok {
+			return level, nil
+		}
+	}
+
+	return inferLogLevel(message), nil
+}
+
+// NewLogEntry creates the log entry of a line written by a service, tagged with the level
+// and fields detected by ParseLogMessage.
+func NewLogEntry(serviceName, line string, isStderr bool) LogEntry {
+	level, fields := ParseLogMessage(line)
+	return LogEntry{
+		Service:   serviceName,
+		Message:   line,
+		Level:     level,
+		Fields:    fields,
+		Timestamp: time.Now(),
+		IsStderr:  isStderr,
+	}
+}
+
+// ParseLogLevel parses a level name as used by common loggers (case-insensitive).
+// Trace and verbose levels map to debug; fatal, critical and panic levels map to error.
+func ParseLogLevel(name string) (LogLevel, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "trace", "trce", "verbose", "debug", "dbug":
+		return LogLevelDebug, true
+	case "info", "information", "informational", "notice":
+		return LogLevelInfo, true
+	case "warn", "warning":
+		return LogLevelWarn, true
+	case "error", "err", "fail", "fatal", "critical", "crit", "panic", "alert", "emergency":
+		return LogLevelError, true
+	default:
+		return LogLevelInfo, false
+	}
+}
+
+// parseJSONLogFields returns the fields of a line that is a JSON object, or nil.
+func parseJSONLogFields(line string) map[string]interface{} {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") || !strings.HasSuffix(trimmed, "}") {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(trimmed), &fields); err != nil || len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// jsonLogLevel returns the level stored in the fields of a JSON log line.
+// Numeric levels follow the pino/bunyan convention (10 trace ... 60 fatal).
+func jsonLogLevel(fields map[string]interface{}) (LogLevel, bool) {
+	for _, key := range jsonLevelKeys {
+		switch value := fields[key].(type) {
+		case string:
+			if level, ok := ParseLogLevel(value); ok {
+				return level, true
+			}
+		case float64:
+			switch {
+			case value >= 50:
+				return LogLevelError, true
+			case value >= 40:
+				return LogLevelWarn, true
+			case value >= 30:
+				return LogLevelInfo, true
+			case value > 0:
+				return LogLevelDebug, true
+			}
+		}
+	}
+	return LogLevelInfo, false
+}
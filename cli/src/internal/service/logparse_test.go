@@ -0,0 +1,51 @@
+package service
+
+import "testing"
+
+func TestParseLogMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		message    string
+		wantLevel  LogLevel
+		wantFields bool
+	}{
+		{"plain info", "Server listening on port 3000", LogLevelInfo, false},
+		{"level prefix", "ERROR could not connect", LogLevelError, false},
+		{"bracketed prefix", "[WARN] cache disabled", LogLevelWarn, false},
+		{".NET prefix", "fail: Microsoft.AspNetCore.Server[1]", LogLevelError, false},
+		{".NET info prefix mentioning errors", "info: retrying after 2 errors", LogLevelInfo, false},
+		{"python logging", "WARNING:root:deprecated setting", LogLevelWarn, false},
+		{"timestamp before level", "2024-01-15 10:30:45,123 DEBUG loading config", LogLevelDebug, false},
+		{"bracketed timestamp before level", "[2024-01-15T10:30:45Z] [main] INFO ready", LogLevelInfo, false},
+		{"logfmt", `time=2024-01-15T10:30:45Z level=warn msg="disk almost full"`, LogLevelWarn, false},
+		{"keyword fallback", "Unhandled exception in worker", LogLevelError, false},
+		{"word starting with a level", "Failed to bind, retrying", LogLevelInfo, false},
+		{"json level", `{"level":"error","msg":"boom","requestId":"abc"}`, LogLevelError, true},
+		{"json numeric level", `{"level":40,"time":1700000000,"msg":"slow"}`, LogLevelWarn, true},
+		{"json severity", `{"severity":"CRITICAL","message":"down"}`, LogLevelError, true},
+		{"json without level", `{"msg":"request done","status":200}`, LogLevelInfo, true},
+		{"json-looking text", "{not json}", LogLevelInfo, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, fields := ParseLogMessage(tt.message)
+			if level != tt.wantLevel {
+				t.Errorf("ParseLogMessage(%q) level = %v, want %v", tt.message, level, tt.wantLevel)
+			}
+			if (fields != nil) != tt.wantFields {
+				t.Errorf("ParseLogMessage(%q) fields = %v, want fields: %v", tt.message, fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestNewLogEntry_Fields(t *testing.T) {
+	entry := NewLogEntry("api", `{"level":"info","msg":"ok","requestId":"abc"}`, true)
+	if entry.Service != "api" || !entry.IsStderr || entry.Timestamp.IsZero() {
+		t.Errorf("NewLogEntry() = %+v", entry)
+	}
+	if entry.Fields["requestId"] != "abc" {
+		t.Errorf("Fields[requestId] = %v, want abc", entry.Fields["requestId"])
+	}
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogPersistConfig configures how service logs are persisted to .azure/logs/<service>.log.
+type LogPersistConfig struct {
+	// Enabled turns log files on or off (default: true)
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	// MaxSizeMB is the size in megabytes at which a log file is rotated (default: 1)
+	MaxSizeMB int `yaml:"maxSizeMB,omitempty" json:"maxSizeMB,omitempty"`
+	// MaxBackups is the number of rotated files kept per service (default: 2)
+	MaxBackups *int `yaml:"maxBackups,omitempty" json:"maxBackups,omitempty"`
+	// RetentionDays deletes log files not written to for this many days (default: 0, keep them)
+	RetentionDays int `yaml:"retentionDays,omitempty" json:"retentionDays,omitempty"`
+}
+
+// GetPersist returns the log persistence config, or nil if not set.
+func (c *LogsConfig) GetPersist() *LogPersistConfig {
+	if c == nil {
+		return nil
+	}
+	return c.Persist
+}
+
+// logFileOptions are the resolved settings for writing log files.
+type logFileOptions struct {
+	enabled    bool
+	maxSize    int64
+	maxBackups int
+	retention  time.Duration
+}
+
+// defaultLogFileOptions returns the settings used when azure.yaml doesn't configure persistence.
+func defaultLogFileOptions() logFileOptions {
+	return logFileOptions{
+		enabled:    true,
+		maxSize:    MaxLogFileSize,
+		maxBackups: MaxLogFileBackups,
+	}
+}
+
+// resolveLogFileOptions applies a persistence config over the defaults.
+func resolveLogFileOptions(config *LogPersistConfig) logFileOptions {
+	opts := defaultLogFileOptions()
+	if config != nil {
+		if config.Enabled != nil {
+			opts.enabled = *config.Enabled
+		}
+		if config.MaxSizeMB > 0 {
+			opts.maxSize = int64(config.MaxSizeMB) * 1024 * 1024
+		}
+		if config.MaxBackups != nil && *config.MaxBackups >= 0 {
+			opts.maxBackups = *config.MaxBackups
+		}
+		if config.RetentionDays > 0 {
+			opts.retention = time.Duration(config.RetentionDays) * 24 * time.Hour
+		}
+	}
+
+	if override, ok := logPersistenceOverride(); ok {
+		opts.enabled = override
+	}
+	return opts
+}
+
+// logPersistence holds the process-wide override of the azure.yaml enabled setting (e.g. from --log-files).
+var logPersistence = struct {
+	mu      sync.RWMutex
+	set     bool
+	enabled bool
+}{}
+
+// SetLogPersistence turns log files on or off for this process, overriding azure.yaml.
+// It applies to log managers created afterwards.
+func SetLogPersistence(enabled bool) {
+	logPersistence.mu.Lock()
+	defer logPersistence.mu.Unlock()
+	logPersistence.set = true
+	logPersistence.enabled = enabled
+}
+
+// ResetLogPersistence removes the override set with SetLogPersistence.
+func ResetLogPersistence() {
+	logPersistence.mu.Lock()
+	defer logPersistence.mu.Unlock()
+	logPersistence.set = false
+	logPersistence.enabled = false
+}
+
+// logPersistenceOverride returns the override set with SetLogPersistence, if any.
+func logPersistenceOverride() (bool, bool) {
+	logPersistence.mu.RLock()
+	defer logPersistence.mu.RUnlock()
+	return logPersistence.enabled, logPersistence.set
+}
+
+const (
+	// logFileTimestampLayout is the timestamp format of log file lines.
+	logFileTimestampLayout = "2006-01-02 15:04:05.000"
+
+	// maxLogFileLineSize is the maximum size of a single log file line (1MB), to allow for
+	// stack traces and JSON dumps.
+	maxLogFileLineSize = 1 * 1024 * 1024
+
+	// logFileScanBufferSize is the initial buffer for reading log files.
+	// 64KB handles most log lines without reallocation.
+	logFileScanBufferSize = 64 * 1024
+)
+
+// LogsDir returns the directory service logs are persisted to.
+func LogsDir(projectDir string) string {
+	return filepath.Join(projectDir, ".azure", "logs")
+}
+
+// LogFiles returns the persisted log files of a service, oldest first:
+// the rotated backups (<service>.log.N, highest N first) followed by <service>.log.
+// Files that don't exist are omitted.
+func LogFiles(projectDir, serviceName string) []string {
+	base := filepath.Join(LogsDir(projectDir), serviceName+".log")
+
+	backups := rotatedLogFiles(base)
+	sort.Slice(backups, func(i, j int) bool { return backups[i].index > backups[j].index })
+
+	files := make([]string, 0, len(backups)+1)
+	for _, backup := range backups {
+		files = append(files, backup.path)
+	}
+	if _, err := os.Stat(base); err == nil {
+		files = append(files, base)
+	}
+	return files
+}
+
+// rotatedLogFile is a backup created by log rotation, e.g. api.log.2.
+type rotatedLogFile struct {
+	path  string
+	index int
+}
+
+// rotatedLogFiles returns the backups of a log file, in no particular order.
+func rotatedLogFiles(base string) []rotatedLogFile {
+	matches, _ := filepath.Glob(base + ".*")
+	backups := make([]rotatedLogFile, 0, len(matches))
+	for _, match := range matches {
+		index, err := strconv.Atoi(strings.TrimPrefix(match, base+"."))
+		if err != nil || index < 1 {
+			continue
+		}
+		backups = append(backups, rotatedLogFile{path: match, index: index})
+	}
+	return backups
+}
+
+// removeExcessBackups deletes the backups of a log file beyond maxBackups,
+// e.g. after maxBackups was lowered in azure.yaml.
+func removeExcessBackups(base string, maxBackups int) {
+	for _, backup := range rotatedLogFiles(base) {
+		if backup.index > maxBackups {
+			_ = os.Remove(backup.path) // Ignore errors - file may already be gone
+		}
+	}
+}
+
+// PruneLogFiles deletes persisted log files that haven't been written to within the retention period.
+// A retention of zero or less keeps every file.
+func PruneLogFiles(projectDir string, retention time.Duration) error {
+	if retention <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(LogsDir(projectDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read logs directory: %w", err)
+	}
+
+	cutoff := time.Now().Add(-retention)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.Contains(entry.Name(), ".log") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		path := filepath.Join(LogsDir(projectDir), entry.Name())
+		if err := os.Remove(path); err != nil {
+			slog.Debug("failed to remove expired log file", "path", path, "error", err)
+		}
+	}
+	return nil
+}
+
+// ReadLogFile reads the entries of a persisted log file. Lines that can't be parsed are skipped.
+func ReadLogFile(path, serviceName string) ([]LogEntry, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries []LogEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, logFileScanBufferSize), maxLogFileLineSize)
+	for scanner.Scan() {
+		entry, err := ParseLogFileLine(scanner.Text(), serviceName)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ReadLogFiles reads the entries of the persisted log files of a service, oldest first,
+// including the rotated backups.
+func ReadLogFiles(projectDir, serviceName string) []LogEntry {
+	var entries []LogEntry
+	for _, path := range LogFiles(projectDir, serviceName) {
+		fileEntries, err := ReadLogFile(path, serviceName)
+		if err != nil {
+			slog.Debug("failed to read log file", slog.String("path", path), slog.String("error", err.Error()))
+			continue
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries
+}
+
+// ParseLogFileLine parses a line written to a log file by LogBuffer:
+// [2006-01-02 15:04:05.000] [LEVEL] [STREAM] message
+func ParseLogFileLine(line, serviceName string) (LogEntry, error) {
+	entry := LogEntry{
+		Service: serviceName,
+	}
+
+	// Parse timestamp: [2006-01-02 15:04:05.000]
+	if len(line) < 25 || line[0] != '[' {
+		return entry, fmt.Errorf("invalid log line format")
+	}
+
+	endTimestamp := strings.Index(line[1:], "]")
+	if endTimestamp == -1 {
+		return entry, fmt.Errorf("missing timestamp end bracket")
+	}
+
+	// Timestamps are written in local time
+	timestamp, err := time.ParseInLocation(logFileTimestampLayout, line[1:endTimestamp+1], time.Local)
+	if err != nil {
+		return entry, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+	entry.Timestamp = timestamp
+
+	// Parse remaining: [LEVEL] [STREAM] message
+	remaining := line[endTimestamp+3:] // Skip "] "
+
+	// Parse level: [LEVEL]
+	if len(remaining) < 3 || remaining[0] != '[' {
+		entry.Message = remaining
+		entry.Level = LogLevelInfo
+		return entry, nil
+	}
+
+	endLevel := strings.Index(remaining[1:], "]")
+	if endLevel == -1 {
+		entry.Message = remaining
+		entry.Level = LogLevelInfo
+		return entry, nil
+	}
+
+	entry.Level = parseLogFileLevel(remaining[1 : endLevel+1])
+	remaining = remaining[endLevel+3:] // Skip "] "
+
+	// Parse stream: [STREAM]
+	if len(remaining) >= 3 && remaining[0] == '[' {
+		endStream := strings.Index(remaining[1:], "]")
+		if endStream != -1 {
+			entry.IsStderr = remaining[1:endStream+1] == "ERR"
+			remaining = remaining[endStream+3:] // Skip "] "
+		}
+	}
+
+	entry.Message = remaining
+	// The file keeps the detected level; restore the fields of JSON log lines
+	_, entry.Fields = ParseLogMessage(remaining)
+	return entry, nil
+}
+
+// parseLogFileLevel parses the level written by LogLevel.String, defaulting to info.
+func parseLogFileLevel(level string) LogLevel {
+	switch strings.ToUpper(level) {
+	case "INFO":
+		return LogLevelInfo
+	case "WARN", "WARNING":
+		return LogLevelWarn
+	case "ERROR":
+		return LogLevelError
+	case "DEBUG":
+		return LogLevelDebug
+	default:
+		return LogLevelInfo
+	}
+}
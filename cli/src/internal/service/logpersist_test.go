@@ -0,0 +1,389 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveLogFileOptions(t *testing.T) {
+	disabled := false
+	noBackups := 0
+
+	tests := []struct {
+		name     string
+		config   *LogPersistConfig
+		override *bool
+		want     logFileOptions
+	}{
+		{name: "defaults", want: logFileOptions{enabled: true, maxSize: MaxLogFileSize, maxBackups: MaxLogFileBackups}},
+		{
+			name:   "configured",
+			config: &LogPersistConfig{MaxSizeMB: 5, MaxBackups: &noBackups, RetentionDays: 7},
+			want:   logFileOptions{enabled: true, maxSize: 5 * 1024 * 1024, maxBackups: 0, retention: 7 * 24 * time.Hour},
+		},
+		{name: "disabled", config: &LogPersistConfig{Enabled: &disabled}, want: logFileOptions{enabled: false, maxSize: MaxLogFileSize, maxBackups: MaxLogFileBackups}},
+		{name: "override", config: &LogPersistConfig{Enabled: &disabled}, override: boolPtr(true), want: logFileOptions{enabled: true, maxSize: MaxLogFileSize, maxBackups: MaxLogFileBackups}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.override != nil {
+				SetLogPersistence(*tt.override)
+				t.Cleanup(ResetLogPersistence)
+			}
+			if got := resolveLogFileOptions(tt.config); got != tt.want {
+				t.Errorf("resolveLogFileOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogBuffer_RotationSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	logsDir := LogsDir(tmpDir)
+	if err := os.MkdirAll(logsDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	// A backup left over from a session that kept more backups
+	if err := os.WriteFile(filepath.Join(logsDir, "api.log.5"), []byte("stale\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer, err := newLogBuffer("api", 10, tmpDir, nil, logFileOptions{enabled: true, maxSize: 100, maxBackups: 1})
+	if err != nil {
+		t.Fatalf("newLogBuffer() error = %v", err)
+	}
+	defer func() { _ = buffer.Close() }()
+
+	for i := 0; i < 10; i++ {
+		buffer.Add(LogEntry{Message: strings.Repeat("x", 40), Level: LogLevelInfo, Timestamp: time.Now()})
+	}
+
+	files := LogFiles(tmpDir, "api")
+	want := []string{filepath.Join(logsDir, "api.log.1"), filepath.Join(logsDir, "api.log")}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("LogFiles() = %v, want %v", files, want)
+	}
+}
+
+func TestLogFiles_Order(t *testing.T) {
+	tmpDir := t.TempDir()
+	logsDir := LogsDir(tmpDir)
+	if err := os.MkdirAll(logsDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"api.log", "api.log.1", "api.log.10", "api.log.2", "api.log.bak", "apiv2.log.1"} {
+		if err := os.WriteFile(filepath.Join(logsDir, name), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	for _, file := range LogFiles(tmpDir, "api") {
+		got = append(got, filepath.Base(file))
+	}
+	want := "api.log.10,api.log.2,api.log.1,api.log"
+	if strings.Join(got, ",") != want {
+		t.Errorf("LogFiles() = %v, want %s", got, want)
+	}
+}
+
+func TestPruneLogFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	logsDir := LogsDir(tmpDir)
+	if err := os.MkdirAll(logsDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	for _, name := range []string{"old.log", "old.log.1", "new.log"} {
+		path := filepath.Join(logsDir, name)
+		if err := os.WriteFile(path, []byte("entry\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(name, "old") {
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := PruneLogFiles(tmpDir, 7*24*time.Hour); err != nil {
+		t.Fatalf("PruneLogFiles() error = %v", err)
+	}
+
+	for name, wantExists := range map[string]bool{"old.log": false, "old.log.1": false, "new.log": true} {
+		_, err := os.Stat(filepath.Join(logsDir, name))
+		if exists := err == nil; exists != wantExists {
+			t.Errorf("%s exists = %v, want %v", name, exists, wantExists)
+		}
+	}
+}
+
+func TestParseLogFileLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		serviceName string
+		wantErr     bool
+		wantLevel   LogLevel
+		wantStderr  bool
+		wantMsg     string
+	}{
+		{
+			name:        "valid info log",
+			line:        "[2024-01-15 10:30:45.123] [INFO] [OUT] Server started on port 3000",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelInfo,
+			wantStderr:  false,
+			wantMsg:     "Server started on port 3000",
+		},
+		{
+			name:        "valid error log with stderr",
+			line:        "[2024-01-15 10:30:45.123] [ERROR] [ERR] Connection failed",
+			serviceName: "db",
+			wantErr:     false,
+			wantLevel:   LogLevelError,
+			wantStderr:  true,
+			wantMsg:     "Connection failed",
+		},
+		{
+			name:        "valid warn log",
+			line:        "[2024-01-15 10:30:45.123] [WARN] [OUT] Deprecated function called",
+			serviceName: "web",
+			wantErr:     false,
+			wantLevel:   LogLevelWarn,
+			wantStderr:  false,
+			wantMsg:     "Deprecated function called",
+		},
+		{
+			name:        "valid debug log",
+			line:        "[2024-01-15 10:30:45.123] [DEBUG] [OUT] Processing request id=123",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelDebug,
+			wantStderr:  false,
+			wantMsg:     "Processing request id=123",
+		},
+		{
+			name:        "invalid - no timestamp bracket",
+			line:        "2024-01-15 10:30:45.123] [INFO] [OUT] Message",
+			serviceName: "api",
+			wantErr:     true,
+		},
+		{
+			name:        "invalid - too short",
+			line:        "[2024-01-15]",
+			serviceName: "api",
+			wantErr:     true,
+		},
+		{
+			name:        "invalid - empty line",
+			line:        "",
+			serviceName: "api",
+			wantErr:     true,
+		},
+		{
+			name:        "partial format - only timestamp",
+			line:        "[2024-01-15 10:30:45.123] Some message without level",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelInfo,
+			wantMsg:     "Some message without level",
+		},
+		{
+			name:        "message with special characters",
+			line:        "[2024-01-15 10:30:45.123] [INFO] [OUT] JSON: {\"key\": \"value\", \"count\": 42}",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelInfo,
+			wantMsg:     "JSON: {\"key\": \"value\", \"count\": 42}",
+		},
+		{
+			name:        "message with brackets",
+			line:        "[2024-01-15 10:30:45.123] [INFO] [OUT] Array: [1, 2, 3]",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelInfo,
+			wantMsg:     "Array: [1, 2, 3]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := ParseLogFileLine(tt.line, tt.serviceName)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseLogFileLine() expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("ParseLogFileLine() unexpected error: %v", err)
+				return
+			}
+
+			if entry.Service != tt.serviceName {
+				t.Errorf("Service = %q, want %q", entry.Service, tt.serviceName)
+			}
+
+			if entry.Level != tt.wantLevel {
+				t.Errorf("Level = %v, want %v", entry.Level, tt.wantLevel)
+			}
+
+			if entry.IsStderr != tt.wantStderr {
+				t.Errorf("IsStderr = %v, want %v", entry.IsStderr, tt.wantStderr)
+			}
+
+			if entry.Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", entry.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestParseLogFileLine_EdgeCases(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		serviceName string
+		wantErr     bool
+		wantLevel   LogLevel
+		wantMsg     string
+	}{
+		{
+			name:        "missing stream marker",
+			line:        "[2024-01-15 10:30:45.123] [INFO] Message without stream",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelInfo,
+			wantMsg:     "Message without stream",
+		},
+		{
+			name:        "unknown level defaults to info",
+			line:        "[2024-01-15 10:30:45.123] [TRACE] [OUT] Trace message",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelInfo,
+			wantMsg:     "Trace message",
+		},
+		{
+			name:        "very short valid line",
+			line:        "[2024-01-15 10:30:45.123] X",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelInfo,
+			wantMsg:     "X",
+		},
+		{
+			name:        "line with only timestamp and space",
+			line:        "[2024-01-15 10:30:45.123] ",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelInfo,
+			wantMsg:     "",
+		},
+		{
+			name:        "unicode in message",
+			line:        "[2024-01-15 10:30:45.123] [INFO] [OUT] 你好世界 🎉 emoji test",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelInfo,
+			wantMsg:     "你好世界 🎉 emoji test",
+		},
+		{
+			name:        "invalid timestamp format",
+			line:        "[not-a-valid-timestamp] [INFO] [OUT] Message",
+			serviceName: "api",
+			wantErr:     true,
+		},
+		{
+			name:        "missing closing bracket on timestamp",
+			line:        "[2024-01-15 10:30:45.123 no closing bracket",
+			serviceName: "api",
+			wantErr:     true,
+		},
+		{
+			name:        "level without closing bracket",
+			line:        "[2024-01-15 10:30:45.123] [INFO no closing level bracket",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelInfo,
+			wantMsg:     "[INFO no closing level bracket",
+		},
+		{
+			name:        "remaining text shorter than 3 chars",
+			line:        "[2024-01-15 10:30:45.123] ab",
+			serviceName: "api",
+			wantErr:     false,
+			wantLevel:   LogLevelInfo,
+			wantMsg:     "ab",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := ParseLogFileLine(tt.line, tt.serviceName)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			if entry.Level != tt.wantLevel {
+				t.Errorf("Level = %v, want %v", entry.Level, tt.wantLevel)
+			}
+			if entry.Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", entry.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestMaxLogFileLineSize(t *testing.T) {
+	if maxLogFileLineSize != 1024*1024 {
+		t.Errorf("maxLogFileLineSize = %d, expected 1MB", maxLogFileLineSize)
+	}
+}
+
+func TestParseLogFileLevel(t *testing.T) {
+	tests := []struct {
+		input string
+		want  LogLevel
+	}{
+		{"INFO", LogLevelInfo},
+		{"info", LogLevelInfo},
+		{"WARN", LogLevelWarn},
+		{"WARNING", LogLevelWarn},
+		{"ERROR", LogLevelError},
+		{"DEBUG", LogLevelDebug},
+		{"", LogLevelInfo},
+		{"unknown", LogLevelInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := parseLogFileLevel(tt.input)
+			if got != tt.want {
+				t.Errorf("parseLogFileLevel(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkParseLogFileLine(b *testing.B) {
+	line := "[2024-01-15 10:30:45.123] [INFO] [OUT] Server started on port 3000 with configuration loaded"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseLogFileLine(line, "api")
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// LogSearchOptions configures a search of service logs.
+type LogSearchOptions struct {
+	Pattern      *regexp.Regexp    // Matched against the message of each entry
+	Levels       map[LogLevel]bool // Only entries with one of these levels match; nil matches every level
+	ContextLines int               // Lines before and after each match (0-MaxContextLines)
+	Limit        int               // Maximum number of matches (0 = no limit)
+}
+
+// matches reports whether an entry is a search match.
+func (o LogSearchOptions) matches(entry LogEntry) bool {
+	if o.Levels != nil && !o.Levels[entry.Level] {
+		return false
+	}
+	return o.Pattern.MatchString(entry.Message)
+}
+
+// SearchLogEntries returns the entries whose message matches the search pattern, most recent
+// first, each with the messages of the surrounding entries as context.
+// The entries must be in chronological order.
+func SearchLogEntries(entries []LogEntry, opts LogSearchOptions) []LogEntryWithContext {
+	contextLines := min(max(opts.ContextLines, 0), MaxContextLines)
+
+	var result []LogEntryWithContext
+	for i := len(entries) - 1; i >= 0; i-- {
+		if opts.Limit > 0 && len(result) >= opts.Limit {
+			break
+		}
+		entry := entries[i]
+		if !opts.matches(entry) {
+			continue
+		}
+
+		match := LogEntryWithContext{
+			Service:   entry.Service,
+			Message:   entry.Message,
+			Level:     entry.Level,
+			Timestamp: entry.Timestamp,
+			IsStderr:  entry.IsStderr,
+			Count:     1,
+		}
+		if contextLines > 0 {
+			for _, before := range entries[max(i-contextLines, 0):i] {
+				match.Context.Before = append(match.Context.Before, before.Message)
+			}
+			for _, after := range entries[i+1 : min(i+1+contextLines, len(entries))] {
+				match.Context.After = append(match.Context.After, after.Message)
+			}
+		}
+		result = append(result, match)
+	}
+	return result
+}
+
+// SearchLogs searches the logs of a service, or of all services when serviceName is empty.
+// Services that persist their logs are searched on disk, which includes the rotated log files
+// and earlier sessions; the others are searched in their in-memory buffer.
+// Matches are returned most recent first.
+func (lm *LogManager) SearchLogs(serviceName string, opts LogSearchOptions) ([]LogEntryWithContext, error) {
+	lm.mu.RLock()
+	buffers := make(map[string]*LogBuffer, len(lm.buffers))
+	for name, buffer := range lm.buffers {
+		if serviceName == "" || name == serviceName {
+			buffers[name] = buffer
+		}
+	}
+	lm.mu.RUnlock()
+
+	if serviceName != "" && len(buffers) == 0 {
+		return nil, fmt.Errorf("no log buffer found for service: %s", serviceName)
+	}
+
+	var matches []LogEntryWithContext
+	for name, buffer := range buffers {
+		matches = append(matches, SearchLogEntries(lm.searchableLogs(name, buffer), opts)...)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Timestamp.After(matches[j].Timestamp)
+	})
+	if opts.Limit > 0 && len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+	return matches, nil
+}
+
+// searchableLogs returns the logs of a service to search, in chronological order.
+// Every entry added to a buffer that persists its logs is also in its log file.
+func (lm *LogManager) searchableLogs(serviceName string, buffer *LogBuffer) []LogEntry {
+	if buffer.persisted() {
+		if entries := ReadLogFiles(lm.projectDir, serviceName); len(entries) > 0 {
+			return entries
+		}
+	}
+	return buffer.GetRecent(0)
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestSearchLogEntries(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	var entries []LogEntry
+	for i, msg := range []string{"starting", "GET /health 200", "db timeout", "retrying", "GET /users 500", "done"} {
+		level := LogLevelInfo
+		if msg == "db timeout" || msg == "GET /users 500" {
+			level = LogLevelError
+		}
+		entries = append(entries, LogEntry{Service: "api", Message: msg, Level: level, Timestamp: start.Add(time.Duration(i) * time.Second)})
+	}
+
+	tests := []struct {
+		name        string
+		opts        LogSearchOptions
+		wantMsgs    []string
+		wantContext []LogContext
+	}{
+		{
+			name:     "most recent first",
+			opts:     LogSearchOptions{Pattern: regexp.MustCompile(`^GET`)},
+			wantMsgs: []string{"GET /users 500", "GET /health 200"},
+		},
+		{
+			name:     "level filter",
+			opts:     LogSearchOptions{Pattern: regexp.MustCompile(`0`), Levels: map[LogLevel]bool{LogLevelError: true}},
+			wantMsgs: []string{"GET /users 500"},
+		},
+		{
+			name:     "limit keeps the most recent",
+			opts:     LogSearchOptions{Pattern: regexp.MustCompile(`.`), Limit: 2},
+			wantMsgs: []string{"done", "GET /users 500"},
+		},
+		{
+			name:     "context lines",
+			opts:     LogSearchOptions{Pattern: regexp.MustCompile(`timeout|done`), ContextLines: 2},
+			wantMsgs: []string{"done", "db timeout"},
+			wantContext: []LogContext{
+				{Before: []string{"retrying", "GET /users 500"}},
+				{Before: []string{"starting", "GET /health 200"}, After: []string{"retrying", "GET /users 500"}},
+			},
+		},
+		{
+			name:     "no match",
+			opts:     LogSearchOptions{Pattern: regexp.MustCompile(`panic`)},
+			wantMsgs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SearchLogEntries(entries, tt.opts)
+			var msgs []string
+			for _, match := range got {
+				msgs = append(msgs, match.Message)
+			}
+			if !reflect.DeepEqual(msgs, tt.wantMsgs) {
+				t.Fatalf("SearchLogEntries() = %v, want %v", msgs, tt.wantMsgs)
+			}
+			for i, want := range tt.wantContext {
+				if !reflect.DeepEqual(got[i].Context, want) {
+					t.Errorf("match %d context = %+v, want %+v", i, got[i].Context, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLogManagerSearchLogs(t *testing.T) {
+	projectDir := t.TempDir()
+	lm := GetLogManager(projectDir)
+	t.Cleanup(func() { _ = lm.Clear() })
+
+	// A previous session left a log file for api, which is searched with the new entries
+	if err := os.MkdirAll(LogsDir(projectDir), 0700); err != nil {
+		t.Fatal(err)
+	}
+	previous := "[2024-01-15 10:30:45.100] [ERROR] [ERR] connection refused (previous session)\n"
+	if err := os.WriteFile(filepath.Join(LogsDir(projectDir), "api.log"), []byte(previous), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	api, err := lm.CreateBuffer("api", 100, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	web, err := lm.CreateBuffer("web", 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api.Add(LogEntry{Service: "api", Message: "connection refused", Level: LogLevelError, Timestamp: time.Now()})
+	web.Add(LogEntry{Service: "web", Message: "connection refused by api", Level: LogLevelWarn, Timestamp: time.Now().Add(time.Second)})
+	web.Add(LogEntry{Service: "web", Message: "ready", Level: LogLevelInfo, Timestamp: time.Now().Add(2 * time.Second)})
+
+	pattern := regexp.MustCompile(`connection refused`)
+	matches, err := lm.SearchLogs("", LogSearchOptions{Pattern: pattern})
+	if err != nil {
+		t.Fatalf("SearchLogs() error = %v", err)
+	}
+	var got []string
+	for _, match := range matches {
+		got = append(got, match.Service+": "+match.Message)
+	}
+	want := []string{"web: connection refused by api", "api: connection refused", "api: connection refused (previous session)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchLogs() = %v, want %v", got, want)
+	}
+
+	matches, err = lm.SearchLogs("web", LogSearchOptions{Pattern: pattern})
+	if err != nil {
+		t.Fatalf("SearchLogs(web) error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].Service != "web" {
+		t.Errorf("SearchLogs(web) = %+v, want the web match only", matches)
+	}
+
+	if _, err := lm.SearchLogs("missing", LogSearchOptions{Pattern: pattern}); err == nil {
+		t.Error("SearchLogs() expected error for unknown service")
+	}
+}
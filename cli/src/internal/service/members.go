@@ -0,0 +1,77 @@
+package service
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/jongio/azd-core/registry"
+)
+
+// serviceMembers holds the registry entries a service registers for what it runs besides its
+// own process, such as the containers of a compose project or the resources of an Aspire
+// AppHost, keyed by the name of the service.
+var serviceMembers = struct {
+	mu     sync.Mutex
+	groups map[string]memberGroup
+}{groups: make(map[string]memberGroup)}
+
+// memberGroup is the registry entries registered by a running service.
+type memberGroup struct {
+	projectDir string
+	members    []string
+}
+
+// MemberOwnerOf returns the service that runs the registry entry name: the service itself,
+// or the one that registered the entry for one of its containers or resources.
+func MemberOwnerOf(name string) string {
+	serviceMembers.mu.Lock()
+	defer serviceMembers.mu.Unlock()
+	for owner, group := range serviceMembers.groups {
+		if slices.Contains(group.members, name) {
+			return owner
+		}
+	}
+	return name
+}
+
+// registerMembers registers the entries of a service's containers or resources, replacing
+// the ones it registered before, so they show up with their URLs and health in the dashboard.
+func registerMembers(projectDir, owner string, entries []*registry.ServiceRegistryEntry) {
+	unregisterMembers(owner)
+
+	reg := registry.GetRegistry(projectDir)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if err := reg.Register(entry); err != nil {
+			slog.Warn("failed to register service member",
+				slog.String("service", owner),
+				slog.String("member", entry.Name),
+				slog.String("error", err.Error()))
+			continue
+		}
+		names = append(names, entry.Name)
+	}
+
+	serviceMembers.mu.Lock()
+	defer serviceMembers.mu.Unlock()
+	serviceMembers.groups[owner] = memberGroup{projectDir: projectDir, members: names}
+}
+
+// unregisterMembers removes the registry entries registered by a service.
+func unregisterMembers(owner string) {
+	serviceMembers.mu.Lock()
+	group, ok := serviceMembers.groups[owner]
+	delete(serviceMembers.groups, owner)
+	serviceMembers.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	reg := registry.GetRegistry(group.projectDir)
+	for _, name := range group.members {
+		if err := reg.Unregister(name); err != nil {
+			slog.Debug("failed to unregister service member", "service", owner, "member", name, "error", err)
+		}
+	}
+}
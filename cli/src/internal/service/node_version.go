@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jongio/azd-app/cli/src/internal/detector"
+)
+
+// nodeVersionManagerLookPath finds volta and fnm. This is a variable to allow test overrides.
+var nodeVersionManagerLookPath = exec.LookPath
+
+// nodeVersionManagerOrder is the order version managers are tried in for each kind of pin:
+// the manager the pinning file belongs to first, then the others that can read the version.
+var nodeVersionManagerOrder = map[string][]string{
+	detector.NodeVersionManagerVolta: {detector.NodeVersionManagerVolta, detector.NodeVersionManagerFnm, detector.NodeVersionManagerNvm},
+	detector.NodeVersionManagerNvm:   {detector.NodeVersionManagerNvm, detector.NodeVersionManagerFnm, detector.NodeVersionManagerVolta},
+	detector.NodeVersionManagerFnm:   {detector.NodeVersionManagerFnm, detector.NodeVersionManagerNvm, detector.NodeVersionManagerVolta},
+}
+
+// applyNodeVersionManager makes a Node.js service run with the Node.js version its project pins:
+// volta and fnm run the command with it, and an nvm install of it comes first on PATH.
+// rootDir bounds the search for pinning files in parent directories.
+func applyNodeVersionManager(runtime *ServiceRuntime, projectDir, rootDir string) error {
+	pin, ok := detector.FindNodeVersionPin(projectDir, rootDir)
+	if !ok {
+		return fmt.Errorf("no Node.js version is pinned in .nvmrc, .node-version or package.json (volta)")
+	}
+	if runtime.Env == nil {
+		runtime.Env = make(map[string]string)
+	}
+
+	for _, manager := range nodeVersionManagerOrder[pin.Manager] {
+		switch manager {
+		case detector.NodeVersionManagerVolta:
+			if _, err := nodeVersionManagerLookPath(manager); err == nil {
+				runtime.Args = append([]string{"run", "--node", pin.Version, runtime.Command}, runtime.Args...)
+				runtime.Command = manager
+				return nil
+			}
+		case detector.NodeVersionManagerFnm:
+			if _, err := nodeVersionManagerLookPath(manager); err == nil {
+				runtime.Args = append([]string{"exec", "--using=" + pin.Version, runtime.Command}, runtime.Args...)
+				runtime.Command = manager
+				return nil
+			}
+		case detector.NodeVersionManagerNvm:
+			// nvm is a shell function rather than a command, so its install goes on PATH instead
+			if binDir := nvmNodeBinDir(pin.Version); binDir != "" {
+				prependPath(runtime, binDir)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("node %s is pinned by %s, but neither volta, fnm nor an nvm install of it was found", pin.Version, pin.Source())
+}
+
+// nvmNodeBinDir returns the directory of the node executable of the newest nvm (NVM_DIR) or
+// nvm-windows (NVM_HOME) install of the version, or "" when it isn't installed.
+func nvmNodeBinDir(version string) string {
+	type installs struct{ dir, bin string }
+	var locations []installs
+	if nvmDir := os.Getenv("NVM_DIR"); nvmDir != "" {
+		locations = append(locations, installs{filepath.Join(nvmDir, "versions", "node"), "bin"})
+	}
+	if nvmHome := os.Getenv("NVM_HOME"); nvmHome != "" {
+		locations = append(locations, installs{nvmHome, ""})
+	}
+
+	version = "v" + strings.TrimPrefix(version, "v")
+	for _, location := range locations {
+		entries, err := os.ReadDir(location.dir)
+		if err != nil {
+			continue
+		}
+		best := ""
+		for _, entry := range entries {
+			name := entry.Name()
+			if !entry.IsDir() || (name != version && !strings.HasPrefix(name, version+".")) {
+				continue
+			}
+			if best == "" || nodeVersionLess(best, name) {
+				best = name
+			}
+		}
+		if best != "" {
+			return filepath.Join(location.dir, best, location.bin)
+		}
+	}
+	return ""
+}
+
+// nodeVersionLess reports whether Node.js version a (e.g., "v18.9.1") is older than b.
+func nodeVersionLess(a, b string) bool {
+	aParts := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bParts := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		x, _ := strconv.Atoi(aParts[i])
+		y, _ := strconv.Atoi(bParts[i])
+		if x != y {
+			return x < y
+		}
+	}
+	return len(aParts) < len(bParts)
+}
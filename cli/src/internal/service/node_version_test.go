@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestApplyNodeVersionManager(t *testing.T) {
+	tests := []struct {
+		name        string
+		pinFile     string // file and content that pin the version
+		pin         string
+		installed   []string // version managers on PATH
+		nvmVersions []string // nvm installs under NVM_DIR
+		wantCommand string
+		wantArgs    []string
+		wantPath    string // nvm version directory expected first on PATH
+		wantErr     bool
+	}{
+		{
+			name: "volta", pinFile: "package.json", pin: `{"name": "web", "volta": {"node": "20.11.1"}}`,
+			installed: []string{"volta", "fnm"}, wantCommand: "volta", wantArgs: []string{"run", "--node", "20.11.1", "npm", "run", "dev"},
+		},
+		{
+			name: "fnm", pinFile: ".node-version", pin: "20",
+			installed: []string{"volta", "fnm"}, wantCommand: "fnm", wantArgs: []string{"exec", "--using=20", "npm", "run", "dev"},
+		},
+		{
+			name: "nvm install on PATH", pinFile: ".nvmrc", pin: "18",
+			installed: []string{"fnm"}, nvmVersions: []string{"v16.20.2", "v18.9.1", "v18.19.0", "v20.11.1"},
+			wantCommand: "npm", wantArgs: []string{"run", "dev"}, wantPath: "v18.19.0",
+		},
+		{
+			name: "fnm when nvm doesn't have the version", pinFile: ".nvmrc", pin: "18",
+			installed: []string{"fnm"}, nvmVersions: []string{"v20.11.1"},
+			wantCommand: "fnm", wantArgs: []string{"exec", "--using=18", "npm", "run", "dev"},
+		},
+		{
+			name: "no version manager", pinFile: ".nvmrc", pin: "18",
+			wantErr: true,
+		},
+		{
+			name:    "no pin",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origLookPath := nodeVersionManagerLookPath
+			defer func() { nodeVersionManagerLookPath = origLookPath }()
+			nodeVersionManagerLookPath = func(file string) (string, error) {
+				if slices.Contains(tt.installed, file) {
+					return "/usr/bin/" + file, nil
+				}
+				return "", errors.New("not found")
+			}
+
+			nvmDir := t.TempDir()
+			t.Setenv("NVM_DIR", nvmDir)
+			t.Setenv("NVM_HOME", "")
+			for _, version := range tt.nvmVersions {
+				if err := os.MkdirAll(filepath.Join(nvmDir, "versions", "node", version, "bin"), 0750); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			projectDir := t.TempDir()
+			if tt.pinFile != "" {
+				if err := os.WriteFile(filepath.Join(projectDir, tt.pinFile), []byte(tt.pin), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			runtime := &ServiceRuntime{Command: "npm", Args: []string{"run", "dev"}}
+
+			err := applyNodeVersionManager(runtime, projectDir, projectDir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyNodeVersionManager() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if runtime.Command != tt.wantCommand || !slices.Equal(runtime.Args, tt.wantArgs) {
+				t.Errorf("command = %s %v, want %s %v", runtime.Command, runtime.Args, tt.wantCommand, tt.wantArgs)
+			}
+			if tt.wantPath != "" {
+				want := filepath.Join(nvmDir, "versions", "node", tt.wantPath, "bin")
+				if path := runtime.Env["PATH"] + runtime.Env["Path"]; !strings.HasPrefix(path, want+string(os.PathListSeparator)) {
+					t.Errorf("PATH = %q, want it to start with %s", path, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNodeVersionLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"v18.9.1", "v18.19.0", true},
+		{"v18.19.0", "v18.9.1", false},
+		{"v20.0.0", "v20.0.0", false},
+		{"v18", "v18.1.0", true},
+	}
+	for _, tt := range tests {
+		if got := nodeVersionLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("nodeVersionLess(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
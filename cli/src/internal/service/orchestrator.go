@@ -13,8 +13,10 @@ import (
 
 	"github.com/jongio/azd-app/cli/src/internal/constants"
 	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+	"github.com/jongio/azd-app/cli/src/internal/tracing"
 	"github.com/jongio/azd-core/cliout"
 	"github.com/jongio/azd-core/registry"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // OrchestrationResult contains the results of service orchestration.
@@ -60,8 +62,11 @@ const DefaultHealthWaitTimeout = 2 * time.Minute
 //
 // Process Isolation:
 // Each service runs in a separate goroutine with panic recovery to prevent cascading failures.
-func OrchestrateServices(ctx context.Context, runtimes []*ServiceRuntime, services map[string]Service, envVars map[string]string, logger *ServiceLogger, restartContainers bool) (*OrchestrationResult, error) {
-	result := &OrchestrationResult{
+func OrchestrateServices(ctx context.Context, runtimes []*ServiceRuntime, services map[string]Service, envVars map[string]string, logger *ServiceLogger, restartContainers bool) (result *OrchestrationResult, err error) {
+	ctx, span := tracing.Start(ctx, "orchestrate", attribute.Int("azd.app.service_count", len(runtimes)))
+	defer func() { tracing.End(span, err) }()
+
+	result = &OrchestrationResult{
 		Processes: make(map[string]*ServiceProcess),
 		Errors:    make(map[string]error),
 		StartTime: time.Now(),
@@ -119,7 +124,9 @@ func OrchestrateServices(ctx context.Context, runtimes []*ServiceRuntime, servic
 			go func(rt *ServiceRuntime) {
 				defer wg.Done()
 
-				process, startErr := startSingleService(ctx, rt, envVars, reg, logger, projectDir, restartContainers, functionsParser)
+				startCtx, startSpan := tracing.Start(ctx, "service.start", attribute.String("azd.app.service", rt.Name))
+				process, startErr := startSingleService(startCtx, rt, envVars, reg, logger, projectDir, restartContainers, functionsParser)
+				tracing.End(startSpan, startErr)
 
 				mu.Lock()
 				if startErr != nil {
@@ -168,8 +175,19 @@ func OrchestrateServices(ctx context.Context, runtimes []*ServiceRuntime, servic
 					continue
 				}
 
-				if err := waitForServiceHealthy(serviceName, process, &svc, DefaultHealthWaitTimeout); err != nil {
+				dependents := dependentsInRuntimes(serviceName, graph, runtimeMap)
+				if logger != nil && len(dependents) > 0 {
+					logger.LogVerbose(serviceName, fmt.Sprintf("waiting for health check before starting %s", strings.Join(dependents, ", ")))
+				}
+
+				_, waitSpan := tracing.Start(ctx, "service.wait_healthy", attribute.String("azd.app.service", serviceName))
+				err := waitForServiceHealthy(serviceName, process, &svc, DefaultHealthWaitTimeout)
+				tracing.End(waitSpan, err)
+				if err != nil {
 					StopAllServices(result.Processes)
+					if len(dependents) > 0 {
+						return result, fmt.Errorf("service %s failed health check (required by %s): %w", serviceName, strings.Join(dependents, ", "), err)
+					}
 					return result, fmt.Errorf("service %s failed health check: %w", serviceName, err)
 				}
 			}
@@ -187,6 +205,17 @@ func OrchestrateServices(ctx context.Context, runtimes []*ServiceRuntime, servic
 	return result, nil
 }
 
+// dependentsInRuntimes returns the services being started that depend directly on serviceName.
+func dependentsInRuntimes(serviceName string, graph *DependencyGraph, runtimeMap map[string]*ServiceRuntime) []string {
+	var dependents []string
+	for _, name := range GetDependents(serviceName, graph) {
+		if _, ok := runtimeMap[name]; ok {
+			dependents = append(dependents, name)
+		}
+	}
+	return dependents
+}
+
 // startSingleService starts a single service and returns the process.
 // This is extracted from the original OrchestrateServices to be reused for level-based startup.
 func startSingleService(ctx context.Context, rt *ServiceRuntime, envVars map[string]string, reg *registry.ServiceRegistry, logger *ServiceLogger, projectDir string, restartContainers bool, functionsParser *FunctionsOutputParser) (*ServiceProcess, error) {
@@ -202,7 +231,7 @@ func startSingleService(ctx context.Context, rt *ServiceRuntime, envVars map[str
 	// Only set URL if port is assigned (port > 0)
 	serviceURL := ""
 	if rt.Port > 0 {
-		serviceURL = fmt.Sprintf("http://localhost:%d", rt.Port)
+		serviceURL = fmt.Sprintf("%s://localhost:%d", rt.URLScheme(), rt.Port)
 	}
 	if err := reg.Register(&registry.ServiceRegistryEntry{
 		Name:       rt.Name,
@@ -260,13 +289,15 @@ func startSingleService(ctx context.Context, rt *ServiceRuntime, envVars map[str
 	}
 	serviceEnv["SERVICE_NAME"] = rt.Name
 
-	// For container services, skip port reservation - the container may already
-	// be running on that port, and StartContainerService handles reuse logic.
-	// For native services, reserve port to prevent TOCTOU race condition.
-	if rt.Type != ServiceTypeContainer {
+	EmitServiceEvent(ServiceEventInfo{Event: EventServiceStarting, Service: rt.Name, Port: rt.Port})
+
+	// For container and compose services, skip port reservation - the container may already
+	// be running on that port, and Docker publishes it.
+	// For native services, hold the port to prevent TOCTOU race condition (a no-op if it has
+	// been held since port assignment). StartService frees it right before the process starts.
+	if !rt.RunsInDocker() {
 		portMgr := portmanager.GetPortManager(projectDir)
-		reservation, portErr := portMgr.ReservePort(rt.Port)
-		if portErr != nil {
+		if portErr := portMgr.HoldPort(rt.Name, rt.Port); portErr != nil {
 			err := fmt.Errorf("port %d is no longer available (taken by another process): %w", rt.Port, portErr)
 			if regErr := reg.UpdateStatus(rt.Name, constants.StatusError); regErr != nil {
 				logger.LogService(rt.Name, fmt.Sprintf("Warning: failed to update status: %v", regErr))
@@ -274,18 +305,35 @@ func startSingleService(ctx context.Context, rt *ServiceRuntime, envVars map[str
 			logger.LogService(rt.Name, fmt.Sprintf("❌ Port %d conflict detected", rt.Port))
 			return nil, err
 		}
+	}
 
-		// Release port reservation immediately before starting service
-		// The service must bind quickly after this to avoid a new race
-		if err := reservation.Release(); err != nil {
-			slog.Debug("failed to release port reservation", "port", rt.Port, "error", err)
+	// Run the service's build command and preRun hook before it starts
+	if err := runPreStartCommands(ctx, rt, serviceEnv); err != nil {
+		// The service won't bind its ports, so free them for other services and processes
+		releaseHeldPorts(rt)
+		if regErr := reg.UpdateStatus(rt.Name, constants.StatusError); regErr != nil {
+			logger.LogService(rt.Name, fmt.Sprintf("Warning: failed to update status: %v", regErr))
 		}
+		logger.LogService(rt.Name, fmt.Sprintf("Failed to start: %v", err))
+		EmitServiceEvent(ServiceEventInfo{Event: EventServiceStopped, Service: rt.Name, Port: rt.Port, Error: err})
+		return nil, err
 	}
 
 	// Start service - use container runner for container services
 	var process *ServiceProcess
 	var err error
-	if rt.Type == ServiceTypeContainer {
+	startedAt := time.Now()
+	switch rt.Type {
+	case ServiceTypeCompose:
+		process, err = StartComposeService(rt, projectDir)
+		if err == nil {
+			if logErr := StartComposeLogCollection(process, projectDir); logErr != nil {
+				slog.Warn("failed to start compose log collection",
+					slog.String("service", rt.Name),
+					slog.String("error", logErr.Error()))
+			}
+		}
+	case ServiceTypeContainer:
 		process, err = StartContainerService(rt, projectDir, restartContainers)
 		if err == nil {
 			// Start container log collection
@@ -295,10 +343,12 @@ func startSingleService(ctx context.Context, rt *ServiceRuntime, envVars map[str
 					slog.String("error", logErr.Error()))
 			}
 		}
-	} else {
+	default:
 		process, err = StartService(rt, serviceEnv, projectDir, functionsParser)
 	}
 	if err != nil {
+		// Starting may have failed before the held ports were freed
+		releaseHeldPorts(rt)
 		slog.Error("failed to start service",
 			slog.String("service", rt.Name),
 			slog.Int("port", rt.Port),
@@ -307,9 +357,14 @@ func startSingleService(ctx context.Context, rt *ServiceRuntime, envVars map[str
 			logger.LogService(rt.Name, fmt.Sprintf("Warning: failed to update status: %v", regErr))
 		}
 		logger.LogService(rt.Name, fmt.Sprintf("Failed to start: %v", err))
+		EmitServiceEvent(ServiceEventInfo{Event: EventServiceStopped, Service: rt.Name, Port: rt.Port, Error: err})
 		return nil, err
 	}
 
+	if process.StartTime.IsZero() {
+		process.StartTime = startedAt
+	}
+
 	pid := 0
 	if process.Process != nil {
 		pid = process.Process.Pid
@@ -321,11 +376,21 @@ func startSingleService(ctx context.Context, rt *ServiceRuntime, envVars map[str
 		slog.String("language", rt.Language),
 		slog.String("framework", rt.Framework))
 
+	// Remember the PID so the port manager may kill it (and its children) on a later port conflict
+	if !rt.RunsInDocker() {
+		portmanager.GetPortManager(projectDir).RecordProcess(rt.Name, pid)
+	}
+
 	// Update registry with PID
 	if entry, exists := reg.GetService(rt.Name); exists {
 		if process.Process != nil {
 			entry.PID = process.Process.Pid
 		}
+		if entry.Port == 0 && process.Port > 0 {
+			// Compose services get the port their containers publish
+			entry.Port = process.Port
+			entry.URL = fmt.Sprintf("%s://localhost:%d", rt.URLScheme(), process.Port)
+		}
 		if regErr := reg.Register(entry); regErr != nil {
 			logger.LogService(rt.Name, fmt.Sprintf("Warning: failed to update registry with PID: %v", regErr))
 		}
@@ -338,9 +403,96 @@ func startSingleService(ctx context.Context, rt *ServiceRuntime, envVars map[str
 	}
 	process.Ready = true
 
+	EmitServiceEvent(newServiceEventInfo(EventServiceStarted, process))
+	if rt.IsAspireAppHost() {
+		// Show the projects and containers of the app model next to the AppHost
+		go watchAspireResources(ctx, process, projectDir)
+	}
+	if HasServiceEventHandlers(EventServiceReady) {
+		// Probe a copy: orchestration adjusts the original's health check timeout while it waits
+		probe := *process
+		go watchServiceReady(ctx, &probe, DefaultHealthWaitTimeout)
+	}
 	return process, nil
 }
 
+// RestartService stops a service and starts it again with the same runtime configuration.
+// It is used by watch mode and liveness checks to restart a single service without touching the others.
+// A process that has already exited (e.g. crashed) is simply started again.
+// Container services are recreated from their image, and compose services with docker compose.
+func RestartService(ctx context.Context, process *ServiceProcess, envVars map[string]string, logger *ServiceLogger, projectDir string, functionsParser *FunctionsOutputParser) (*ServiceProcess, error) {
+	if process == nil {
+		return nil, fmt.Errorf("process is nil")
+	}
+
+	reg := registry.GetRegistry(projectDir)
+	if process.Runtime.Type == ServiceTypeCompose {
+		if err := reg.UpdateStatus(process.Name, constants.StatusStopping); err != nil {
+			slog.Debug("failed to update status before restart", "service", process.Name, "error", err)
+		}
+		if err := StopComposeService(process, DefaultStopTimeout); err != nil {
+			slog.Debug("error stopping compose service for restart", "service", process.Name, "error", err)
+		}
+		rt := process.Runtime
+		return startSingleService(ctx, &rt, envVars, reg, logger, projectDir, true, nil)
+	}
+	if process.Runtime.Type == ServiceTypeContainer {
+		if err := reg.UpdateStatus(process.Name, constants.StatusStopping); err != nil {
+			slog.Debug("failed to update status before restart", "service", process.Name, "error", err)
+		}
+		if process.ContainerID != "" {
+			if err := StopContainerService(process, DefaultStopTimeout); err != nil {
+				slog.Debug("error stopping container for restart", "service", process.Name, "error", err)
+			}
+		}
+		rt := process.Runtime
+		return startSingleService(ctx, &rt, envVars, reg, logger, projectDir, true, nil)
+	}
+
+	if process.Process != nil {
+		if err := reg.UpdateStatus(process.Name, constants.StatusStopping); err != nil {
+			slog.Debug("failed to update status before restart", "service", process.Name, "error", err)
+		}
+		if err := StopServiceGraceful(process, DefaultStopTimeout); err != nil {
+			slog.Debug("error stopping service for restart", "service", process.Name, "error", err)
+		}
+	}
+
+	rt := process.Runtime
+	return startSingleService(ctx, &rt, envVars, reg, logger, projectDir, false, functionsParser)
+}
+
+// StartConfiguredService starts a service defined in the project's azure.yaml on its own,
+// as `azd app start` and the dashboard do when no `azd app run` session manages the service.
+// The service is started like in `azd app run`: with its assigned port in PORT and AZD_PORT,
+// its port held until it starts, and its build command and preRun hook run first.
+// envVars are added to the inherited environment, e.g. the values of the azd environment.
+// Containers are recreated from their image.
+func StartConfiguredService(ctx context.Context, projectDir, serviceName string, envVars map[string]string) (*ServiceProcess, error) {
+	azureYaml, err := ParseAzureYaml(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse azure.yaml: %w", err)
+	}
+	svc, exists := azureYaml.Services[serviceName]
+	if !exists {
+		return nil, fmt.Errorf("service '%s' not found in azure.yaml", serviceName)
+	}
+
+	rt, err := DetectServiceRuntime(serviceName, svc, map[int]bool{}, projectDir, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect service runtime: %w", err)
+	}
+	reg := registry.GetRegistry(projectDir)
+	if err := InjectServiceURLs(rt, RegistryEndpoints(reg, azureYaml.Services)); err != nil {
+		return nil, err
+	}
+	if err := ConfigureHTTPS(rt); err != nil {
+		return nil, err
+	}
+
+	return startSingleService(ctx, rt, envVars, reg, NewServiceLogger(false), projectDir, true, NewFunctionsOutputParser(false))
+}
+
 // waitForServiceHealthy waits for a service to become healthy before proceeding.
 // This is used to ensure dependencies are healthy before starting dependent services.
 func waitForServiceHealthy(name string, process *ServiceProcess, svc *Service, timeout time.Duration) error {
@@ -352,9 +504,9 @@ func waitForServiceHealthy(name string, process *ServiceProcess, svc *Service, t
 	}
 
 	// Use existing PerformHealthCheck which handles all health check types
-	// with exponential backoff
+	// with exponential backoff. A longer timeout from the service's start period is kept.
 	originalTimeout := process.Runtime.HealthCheck.Timeout
-	if timeout > 0 {
+	if timeout > originalTimeout {
 		process.Runtime.HealthCheck.Timeout = timeout
 	}
 
@@ -410,9 +562,12 @@ func StopAllServices(processes map[string]*ServiceProcess) {
 
 			// Stop service - use container runner for container services
 			var stopErr error
-			if proc.Runtime.Type == ServiceTypeContainer {
+			switch proc.Runtime.Type {
+			case ServiceTypeCompose:
+				stopErr = StopComposeService(proc, DefaultStopTimeout)
+			case ServiceTypeContainer:
 				stopErr = StopContainerService(proc, DefaultStopTimeout)
-			} else {
+			default:
 				stopErr = StopServiceGraceful(proc, DefaultStopTimeout)
 			}
 			if stopErr != nil {
@@ -438,7 +593,7 @@ func GetServiceURLs(processes map[string]*ServiceProcess) map[string]string {
 	for name, process := range processes {
 		// Only include services with assigned ports (port > 0)
 		if process.Ready && process.Port > 0 {
-			urls[name] = fmt.Sprintf("http://localhost:%d", process.Port)
+			urls[name] = fmt.Sprintf("%s://localhost:%d", process.Runtime.URLScheme(), process.Port)
 		}
 	}
 
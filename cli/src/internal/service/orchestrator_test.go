@@ -1,9 +1,17 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/portmanager"
+	"github.com/jongio/azd-core/registry"
 )
 
 func TestOrchestrationResult(t *testing.T) {
@@ -193,6 +201,109 @@ func TestTopologicalSort_LinearDependency(t *testing.T) {
 	}
 }
 
+func TestTopologicalSort_DependsOn(t *testing.T) {
+	// dependsOn orders startup like uses, and both can be combined
+	services := map[string]Service{
+		"web":    {Host: "containerapp", DependsOn: []string{"api"}},
+		"api":    {Host: "containerapp", Uses: []string{"db"}, DependsOn: []string{"db", "worker"}},
+		"worker": {Host: "containerapp"},
+		"db":     {Host: "containerapp"},
+	}
+
+	graph, err := BuildDependencyGraph(services, nil)
+	if err != nil {
+		t.Fatalf("Failed to build graph: %v", err)
+	}
+
+	if deps := GetServiceDependencies("api", graph); !reflect.DeepEqual(deps, []string{"db", "worker"}) {
+		t.Errorf("api dependencies = %v, want [db worker] (deduplicated)", deps)
+	}
+
+	levels := TopologicalSort(graph)
+	want := [][]string{{"db", "worker"}, {"api"}, {"web"}}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("levels = %v, want %v", levels, want)
+	}
+}
+
+func TestParseAzureYaml_DependsOn(t *testing.T) {
+	dir := t.TempDir()
+	writeAzureYaml(t, dir, `name: app
+services:
+  web:
+    project: ./web
+    dependsOn: [api]
+  api:
+    project: ./api
+    uses: [db]
+    dependsOn:
+      - db
+      - worker
+  worker:
+    project: ./worker
+  db:
+    image: postgres
+`)
+
+	azureYaml, err := ParseAzureYaml(dir)
+	if err != nil {
+		t.Fatalf("ParseAzureYaml() failed: %v", err)
+	}
+
+	web := azureYaml.Services["web"]
+	if !reflect.DeepEqual(web.DependsOn, []string{"api"}) {
+		t.Errorf("web.DependsOn = %v, want [api]", web.DependsOn)
+	}
+	api := azureYaml.Services["api"]
+	if got := api.Dependencies(); !reflect.DeepEqual(got, []string{"db", "worker"}) {
+		t.Errorf("api.Dependencies() = %v, want [db worker]", got)
+	}
+}
+
+func TestBuildDependencyGraph_DependsOnErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		services  map[string]Service
+		resources map[string]Resource
+		wantErr   string
+	}{
+		{
+			name:     "unknown service",
+			services: map[string]Service{"web": {DependsOn: []string{"missing"}}},
+			wantErr:  "'web' depends on 'missing' which does not exist",
+		},
+		{
+			name:     "self dependency",
+			services: map[string]Service{"web": {DependsOn: []string{"web"}}},
+			wantErr:  "'web' depends on itself",
+		},
+		{
+			name:      "resource in dependsOn",
+			services:  map[string]Service{"api": {DependsOn: []string{"db"}}},
+			resources: map[string]Resource{"db": {Type: "db.postgres"}},
+			wantErr:   "use 'uses' for resources",
+		},
+		{
+			name: "cycle",
+			services: map[string]Service{
+				"api":    {DependsOn: []string{"worker"}},
+				"worker": {DependsOn: []string{"cache"}},
+				"cache":  {Uses: []string{"api"}},
+			},
+			wantErr: "api -> worker -> cache -> api",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildDependencyGraph(tt.services, tt.resources)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("BuildDependencyGraph() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestTopologicalSort_DiamondDependency(t *testing.T) {
 	// Diamond pattern:
 	//     frontend
@@ -464,3 +575,45 @@ func TestFilterGraphByServices(t *testing.T) {
 		t.Error("worker should not be in filtered graph")
 	}
 }
+
+func TestStartSingleService_ReleasesHeldPortOnFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("build command uses POSIX shell syntax")
+	}
+
+	tests := []struct {
+		name string
+		rt   ServiceRuntime
+	}{
+		{name: "build fails", rt: ServiceRuntime{Command: "node", BuildCommand: "exit 1"}},
+		{name: "start fails", rt: ServiceRuntime{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			port := listener.Addr().(*net.TCPAddr).Port
+			_ = listener.Close()
+			t.Cleanup(func() { portmanager.ReleaseHeldPort(port) })
+
+			rt := tt.rt
+			rt.Name = "api"
+			rt.WorkingDir = dir
+			rt.Port = port
+			if _, err := startSingleService(context.Background(), &rt, nil, registry.GetRegistry(dir), NewServiceLogger(false), dir, false, nil); err == nil {
+				t.Fatal("startSingleService() error = nil, want error")
+			}
+
+			lc := net.ListenConfig{}
+			bound, err := lc.Listen(context.Background(), "tcp", fmt.Sprintf(":%d", port))
+			if err != nil {
+				t.Fatalf("port %d is still held after the service failed to start: %v", port, err)
+			}
+			_ = bound.Close()
+		})
+	}
+}
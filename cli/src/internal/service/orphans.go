@@ -0,0 +1,188 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+)
+
+const (
+	// processRecordFileName is stored in the project's .azure directory.
+	processRecordFileName = "processes.json"
+
+	// processRecordVersion tracks the file schema version.
+	processRecordVersion = 1
+
+	// startTimeTolerance allows for the one second resolution of process start times on Unix.
+	startTimeTolerance = time.Second
+)
+
+// RecordedProcess is a service process started by an azd app run session, recorded in
+// .azure/processes.json so it can be found if the session exits without stopping it.
+type RecordedProcess struct {
+	Service        string    `json:"service"`
+	PID            int       `json:"pid"`
+	StartedAt      time.Time `json:"startedAt"` // Start time reported by the OS, to detect PID reuse
+	Command        string    `json:"command,omitempty"`
+	OwnerPID       int       `json:"ownerPid"`       // PID of the azd process that started it
+	OwnerStartedAt time.Time `json:"ownerStartedAt"` // Start time of the azd process that started it
+}
+
+// processRecordFile is the on-disk representation of the recorded processes.
+type processRecordFile struct {
+	Version   int               `json:"version"`
+	Processes []RecordedProcess `json:"processes"`
+}
+
+// processRecordMu serializes updates of the process record files of this azd process.
+var processRecordMu sync.Mutex
+
+// ownerStart is the start time of this azd process, recorded with the processes it starts.
+var ownerStart = sync.OnceValue(func() time.Time {
+	started, err := processStartTime(os.Getpid())
+	if err != nil {
+		slog.Debug("failed to get azd process start time", slog.String("error", err.Error()))
+	}
+	return started
+})
+
+// processRecordPath returns the path of the process record file of a project.
+func processRecordPath(projectDir string) string {
+	return filepath.Join(projectDir, ".azure", processRecordFileName)
+}
+
+// updateProcessRecord loads the recorded processes of a project, applies update and saves the result.
+func updateProcessRecord(projectDir string, update func([]RecordedProcess) []RecordedProcess) error {
+	processRecordMu.Lock()
+	defer processRecordMu.Unlock()
+
+	path := processRecordPath(projectDir)
+	var data processRecordFile
+	if err := fileutil.ReadJSON(path, &data); err != nil || data.Version != processRecordVersion {
+		data = processRecordFile{}
+	}
+
+	processes := update(data.Processes)
+	if len(processes) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove process record: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create .azure directory: %w", err)
+	}
+	if err := fileutil.AtomicWriteJSON(path, processRecordFile{Version: processRecordVersion, Processes: processes}); err != nil {
+		return fmt.Errorf("failed to save process record: %w", err)
+	}
+	return nil
+}
+
+// recordProcess records a started service process, replacing the previous process of the service.
+// Failures are logged; the record only helps clean up after a crashed session.
+func recordProcess(projectDir string, runtime *ServiceRuntime, pid int) {
+	started, err := processStartTime(pid)
+	if err != nil {
+		slog.Debug("not recording service process", slog.String("service", runtime.Name), slog.String("error", err.Error()))
+		return
+	}
+	entry := RecordedProcess{
+		Service:        runtime.Name,
+		PID:            pid,
+		StartedAt:      started,
+		Command:        strings.TrimSpace(runtime.Command + " " + strings.Join(runtime.Args, " ")),
+		OwnerPID:       os.Getpid(),
+		OwnerStartedAt: ownerStart(),
+	}
+
+	err = updateProcessRecord(projectDir, func(processes []RecordedProcess) []RecordedProcess {
+		kept := processes[:0]
+		for _, p := range processes {
+			if p.Service != entry.Service || p.OwnerPID != entry.OwnerPID {
+				kept = append(kept, p)
+			}
+		}
+		return append(kept, entry)
+	})
+	if err != nil {
+		slog.Debug("failed to record service process", slog.String("service", runtime.Name), slog.String("error", err.Error()))
+	}
+}
+
+// ForgetProcesses removes the processes recorded by this azd process, once it has stopped them.
+func ForgetProcesses(projectDir string) {
+	err := updateProcessRecord(projectDir, func(processes []RecordedProcess) []RecordedProcess {
+		kept := processes[:0]
+		for _, p := range processes {
+			if p.OwnerPID != os.Getpid() {
+				kept = append(kept, p)
+			}
+		}
+		return kept
+	})
+	if err != nil {
+		slog.Debug("failed to forget service processes", slog.String("error", err.Error()))
+	}
+}
+
+// isRunningSince reports whether the process with the given PID is running and started at
+// startedAt, i.e. is the recorded process and not a new process that reused its PID.
+func isRunningSince(pid int, startedAt time.Time) bool {
+	if pid <= 0 || startedAt.IsZero() {
+		return false
+	}
+	started, err := processStartTime(pid)
+	if err != nil {
+		return false
+	}
+	diff := started.Sub(startedAt)
+	return diff <= startTimeTolerance && diff >= -startTimeTolerance
+}
+
+// FindOrphanedProcesses returns the recorded service processes of the project that are still
+// running although the azd process that started them has exited, e.g. after a crash.
+// Records of processes that are gone are removed.
+func FindOrphanedProcesses(projectDir string) ([]RecordedProcess, error) {
+	var orphans []RecordedProcess
+	err := updateProcessRecord(projectDir, func(processes []RecordedProcess) []RecordedProcess {
+		kept := processes[:0]
+		for _, p := range processes {
+			if !isRunningSince(p.PID, p.StartedAt) {
+				continue
+			}
+			kept = append(kept, p)
+			if !isRunningSince(p.OwnerPID, p.OwnerStartedAt) {
+				orphans = append(orphans, p)
+			}
+		}
+		return kept
+	})
+	return orphans, err
+}
+
+// StopOrphanedProcess force-stops an orphaned service process and the processes it started,
+// and removes its record. The process is only stopped if it is still the recorded process.
+func StopOrphanedProcess(projectDir string, orphan RecordedProcess) error {
+	if isRunningSince(orphan.PID, orphan.StartedAt) {
+		if err := killOrphanedProcess(orphan.PID, DefaultStopTimeout); err != nil {
+			return fmt.Errorf("failed to stop %s (PID %d): %w", orphan.Service, orphan.PID, err)
+		}
+	}
+
+	return updateProcessRecord(projectDir, func(processes []RecordedProcess) []RecordedProcess {
+		kept := processes[:0]
+		for _, p := range processes {
+			if p.PID != orphan.PID {
+				kept = append(kept, p)
+			}
+		}
+		return kept
+	})
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+)
+
+// readProcessRecord returns the processes recorded for projectDir.
+func readProcessRecord(t *testing.T, projectDir string) []RecordedProcess {
+	t.Helper()
+	var data processRecordFile
+	if err := fileutil.ReadJSON(processRecordPath(projectDir), &data); err != nil {
+		t.Fatalf("failed to read process record: %v", err)
+	}
+	return data.Processes
+}
+
+func TestRecordProcess(t *testing.T) {
+	projectDir := t.TempDir()
+	runtime := &ServiceRuntime{Name: "api", Command: "node", Args: []string{"server.js"}}
+
+	recordProcess(projectDir, runtime, os.Getpid())
+	recordProcess(projectDir, runtime, os.Getpid())
+	recordProcess(projectDir, &ServiceRuntime{Name: "web", Command: "npm"}, os.Getpid())
+
+	processes := readProcessRecord(t, projectDir)
+	if len(processes) != 2 {
+		t.Fatalf("recorded %d processes, want 2 (one per service): %+v", len(processes), processes)
+	}
+	api := processes[0]
+	if api.Service != "api" || api.PID != os.Getpid() || api.Command != "node server.js" {
+		t.Errorf("unexpected record %+v", api)
+	}
+	if api.OwnerPID != os.Getpid() || api.StartedAt.IsZero() || api.OwnerStartedAt.IsZero() {
+		t.Errorf("record is missing start times or owner: %+v", api)
+	}
+
+	ForgetProcesses(projectDir)
+	if _, err := os.Stat(processRecordPath(projectDir)); !os.IsNotExist(err) {
+		t.Errorf("process record still exists after ForgetProcesses, stat error = %v", err)
+	}
+}
+
+func TestFindOrphanedProcesses(t *testing.T) {
+	projectDir := t.TempDir()
+	started, err := processStartTime(os.Getpid())
+	if err != nil {
+		t.Fatalf("processStartTime() error = %v", err)
+	}
+
+	// The test process stands in for the service processes
+	record := []RecordedProcess{
+		// The azd process that started it is gone
+		{Service: "orphan", PID: os.Getpid(), StartedAt: started, OwnerPID: 0},
+		// The azd process that started it is still running
+		{Service: "owned", PID: os.Getpid(), StartedAt: started, OwnerPID: os.Getpid(), OwnerStartedAt: started},
+		// The PID was reused by another process
+		{Service: "reused", PID: os.Getpid(), StartedAt: started.Add(-time.Hour), OwnerPID: 0},
+	}
+	if err := os.MkdirAll(filepath.Dir(processRecordPath(projectDir)), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := fileutil.AtomicWriteJSON(processRecordPath(projectDir), processRecordFile{Version: processRecordVersion, Processes: record}); err != nil {
+		t.Fatal(err)
+	}
+
+	orphans, err := FindOrphanedProcesses(projectDir)
+	if err != nil {
+		t.Fatalf("FindOrphanedProcesses() error = %v", err)
+	}
+	if len(orphans) != 1 || orphans[0].Service != "orphan" {
+		t.Errorf("FindOrphanedProcesses() = %+v, want only the orphan", orphans)
+	}
+
+	var services []string
+	for _, p := range readProcessRecord(t, projectDir) {
+		services = append(services, p.Service)
+	}
+	if len(services) != 2 || services[0] != "orphan" || services[1] != "owned" {
+		t.Errorf("records after scan = %v, want [orphan owned] (reused PID removed)", services)
+	}
+}
+
+func TestFindOrphanedProcesses_NoRecord(t *testing.T) {
+	orphans, err := FindOrphanedProcesses(t.TempDir())
+	if err != nil {
+		t.Fatalf("FindOrphanedProcesses() error = %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Errorf("FindOrphanedProcesses() = %+v, want none", orphans)
+	}
+}
+
+func TestStopOrphanedProcess(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	projectDir := t.TempDir()
+
+	cmd := exec.CommandContext(context.Background(), "sleep", "60")
+	configureProcessTree(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start process: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+	recordProcess(projectDir, &ServiceRuntime{Name: "api", Command: "sleep"}, cmd.Process.Pid)
+	orphan := readProcessRecord(t, projectDir)[0]
+
+	if err := StopOrphanedProcess(projectDir, orphan); err != nil {
+		t.Fatalf("StopOrphanedProcess() error = %v", err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("orphaned process is still running")
+	}
+	if _, err := os.Stat(processRecordPath(projectDir)); !os.IsNotExist(err) {
+		t.Errorf("process record still exists after stopping the only process, stat error = %v", err)
+	}
+}
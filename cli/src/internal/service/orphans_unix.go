@@ -0,0 +1,65 @@
+//go:build !windows
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// psStartTimeLayout is the format of the lstart column of ps in the C locale.
+const psStartTimeLayout = "Mon Jan _2 15:04:05 2006"
+
+// processStartTime returns when the process with the given PID started, to the second.
+func processStartTime(pid int) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// #nosec G204 -- ps is a hard-coded binary and the PID an integer
+	cmd := exec.CommandContext(ctx, "ps", "-o", "lstart=", "-p", strconv.Itoa(pid))
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	output, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("process %d not running: %w", pid, err)
+	}
+	started, err := time.ParseInLocation(psStartTimeLayout, strings.Join(strings.Fields(string(output)), " "), time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse start time of process %d: %w", pid, err)
+	}
+	return started, nil
+}
+
+// killOrphanedProcess stops a process that isn't a child of azd, and the processes in its
+// process group when it leads one: it is sent SIGTERM, then SIGKILL if it is still running
+// after timeout.
+func killOrphanedProcess(pid int, timeout time.Duration) error {
+	target := pid
+	if pgid, err := syscall.Getpgid(pid); err == nil && pgid == pid {
+		target = -pid
+	}
+
+	if err := syscall.Kill(target, syscall.SIGTERM); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+		return err
+	}
+	deadline := time.Now().Add(timeout)
+	for syscall.Kill(target, 0) == nil {
+		if time.Now().After(deadline) {
+			if err := syscall.Kill(target, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+				return err
+			}
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return nil
+}
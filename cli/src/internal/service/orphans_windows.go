@@ -0,0 +1,56 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code GetExitCodeProcess reports for a running process.
+const stillActive = 259
+
+// processStartTime returns when the process with the given PID started.
+func processStartTime(pid int) (time.Time, error) {
+	if pid < 0 || pid > 0x7FFFFFFF {
+		return time.Time{}, fmt.Errorf("invalid PID %d for Windows process handle", pid)
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("process %d not running: %w", pid, err)
+	}
+	defer windows.CloseHandle(handle) //nolint:errcheck
+
+	var exitCode uint32
+	if err := windows.GetExitCodeProcess(handle, &exitCode); err != nil {
+		return time.Time{}, fmt.Errorf("failed to query process %d: %w", pid, err)
+	}
+	if exitCode != stillActive {
+		return time.Time{}, fmt.Errorf("process %d not running", pid)
+	}
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(handle, &creation, &exit, &kernel, &user); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get start time of process %d: %w", pid, err)
+	}
+	return time.Unix(0, creation.Nanoseconds()), nil
+}
+
+// killOrphanedProcess force-kills a process that isn't a child of azd and its descendants.
+// Windows console processes can't be sent a graceful stop signal, so timeout is not used.
+func killOrphanedProcess(pid int, _ time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// #nosec G204 -- PID is an integer from the process record
+	cmd := exec.CommandContext(ctx, "taskkill", "/F", "/T", "/PID", strconv.Itoa(pid))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("taskkill failed: %w: %s", err, output)
+	}
+	return nil
+}
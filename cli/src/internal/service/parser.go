@@ -8,12 +8,18 @@ import (
 
 	"github.com/jongio/azd-app/cli/src/internal/detector"
 	"github.com/jongio/azd-core/security"
-
-	"gopkg.in/yaml.v3"
 )
 
 // ParseAzureYaml reads and parses the azure.yaml file.
+// Services declared with `ref` are resolved from the referenced project's azure.yaml,
+// and the profile selected with SetActiveProfile is merged over the services.
 func ParseAzureYaml(workingDir string) (*AzureYaml, error) {
+	return parseAzureYaml(workingDir, ActiveProfile(), make(map[string]bool))
+}
+
+// parseAzureYaml parses azure.yaml with the named profile applied (none if empty),
+// tracking visited files to detect circular refs.
+func parseAzureYaml(workingDir, profile string, visited map[string]bool) (*AzureYaml, error) {
 	// Find azure.yaml using existing detector logic
 	azureYamlPath, err := detector.FindAzureYaml(workingDir)
 	if err != nil {
@@ -35,14 +41,33 @@ func ParseAzureYaml(workingDir string) (*AzureYaml, error) {
 		return nil, fmt.Errorf("failed to read azure.yaml: %w", err)
 	}
 
-	// Parse YAML
+	// Parse YAML, resolving ${VAR} references
 	var azureYaml AzureYaml
-	if err := yaml.Unmarshal(data, &azureYaml); err != nil {
+	if err := UnmarshalAzureYaml(data, &azureYaml); err != nil {
 		return nil, fmt.Errorf("failed to parse azure.yaml: %w", err)
 	}
 
+	// Containers run as container services, which services can use
+	containers, err := containerServices(&azureYaml)
+	if err != nil {
+		return nil, fmt.Errorf("invalid containers in azure.yaml: %w", err)
+	}
+	if len(containers) > 0 && azureYaml.Services == nil {
+		azureYaml.Services = make(map[string]Service, len(containers))
+	}
+	for name, svc := range containers {
+		azureYaml.Services[name] = svc
+	}
+
 	// Resolve relative paths in service projects
 	azureYamlDir := filepath.Dir(azureYamlPath)
+	visited[azureYamlPath] = true
+	if err := resolveServiceRefs(&azureYaml, azureYamlDir, visited); err != nil {
+		return nil, err
+	}
+	if err := azureYaml.ApplyProfile(profile); err != nil {
+		return nil, err
+	}
 	for name, svc := range azureYaml.Services {
 		if svc.Project != "" {
 			// Convert relative path to absolute
@@ -53,6 +78,13 @@ func ParseAzureYaml(workingDir string) (*AzureYaml, error) {
 				azureYaml.Services[name] = svc
 			}
 		}
+		if svc.EnvFile != "" && !filepath.IsAbs(svc.EnvFile) {
+			svc.EnvFile = filepath.Clean(filepath.Join(azureYamlDir, svc.EnvFile))
+		}
+		// Referenced services already have the tool paths of their own project
+		if svc.RefRoot == "" {
+			svc.ToolPaths = toolPaths(azureYamlDir, azureYaml.Reqs, svc.Reqs)
+		}
 
 		// Validate service configuration
 		if err := ValidateServiceConfig(name, &svc); err != nil {
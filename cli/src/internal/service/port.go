@@ -242,7 +242,7 @@ func DetectPort(serviceName string, service Service, projectDir string, framewor
 	}
 
 	// Priority 4: Framework defaults
-	if port := getFrameworkDefaultPort(framework, service.Language); port > 0 {
+	if port := FrameworkDefaultPort(framework, service.Language); port > 0 {
 		// Check if port is already in use
 		if !usedPorts[port] {
 			return port, false, nil // isExplicit = false
@@ -432,8 +432,8 @@ func detectPortFromEnv(serviceName string) int {
 	return 0
 }
 
-// getFrameworkDefaultPort returns the default port for a framework or language.
-func getFrameworkDefaultPort(framework string, language string) int {
+// FrameworkDefaultPort returns the default port for a framework or language.
+func FrameworkDefaultPort(framework string, language string) int {
 	// Check framework-specific defaults first
 	frameworkDefaults := map[string]int{
 		"Next.js":      3000,
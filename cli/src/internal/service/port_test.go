@@ -212,7 +212,7 @@ func TestDetectPort_UsedPortsRespected(t *testing.T) {
 	}
 }
 
-func TestGetFrameworkDefaultPort(t *testing.T) {
+func TestFrameworkDefaultPort(t *testing.T) {
 	tests := []struct {
 		framework    string
 		language     string
@@ -229,7 +229,7 @@ func TestGetFrameworkDefaultPort(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		port := getFrameworkDefaultPort(tt.framework, tt.language)
+		port := FrameworkDefaultPort(tt.framework, tt.language)
 		if port != tt.expectedPort {
 			t.Errorf("Framework %s: expected port %d, got %d", tt.framework, tt.expectedPort, port)
 		}
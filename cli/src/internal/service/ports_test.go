@@ -2,6 +2,8 @@ package service
 
 import (
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestParsePortSpec(t *testing.T) {
@@ -295,6 +297,48 @@ func TestServiceGetPrimaryPort(t *testing.T) {
 	}
 }
 
+func TestService_GetPortRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		yaml      string
+		wantStart int
+		wantEnd   int
+		wantErr   bool
+	}{
+		{name: "not set", yaml: ""},
+		{name: "range", yaml: "portRange: 4000-4099", wantStart: 4000, wantEnd: 4099},
+		{name: "single port", yaml: "portRange: 4000-4000", wantStart: 4000, wantEnd: 4000},
+		{name: "spaces", yaml: "portRange: 4000 - 4099", wantStart: 4000, wantEnd: 4099},
+		{name: "no end", yaml: "portRange: \"4000\"", wantErr: true},
+		{name: "reversed", yaml: "portRange: 4099-4000", wantErr: true},
+		{name: "too high", yaml: "portRange: 65000-70000", wantErr: true},
+		{name: "not a number", yaml: "portRange: a-b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var service Service
+			err := yaml.Unmarshal([]byte("project: ./api\n"+tt.yaml), &service)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Unmarshal() expected error, got portRange %q", service.PortRange)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			start, end, err := service.GetPortRange()
+			if err != nil {
+				t.Fatalf("GetPortRange() error = %v", err)
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("GetPortRange() = %d-%d, want %d-%d", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
 func TestDetectPortWithPortsArray(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"strings"
+	"time"
+)
+
+// builtinProbes holds readiness probes for common infrastructure images.
+// Probes run inside the container (docker exec), so they only rely on tools
+// shipped with the official images. Keys are probe names; see probeImages.
+var builtinProbes = map[string]HealthcheckConfig{
+	"redis": {
+		Test:     []string{"CMD", "redis-cli", "ping"},
+		Interval: "5s",
+		Timeout:  "5s",
+		Retries:  3,
+	},
+	"postgres": {
+		Test:        []string{"CMD-SHELL", "pg_isready -h 127.0.0.1 -U \"${POSTGRES_USER:-postgres}\""},
+		Interval:    "5s",
+		Timeout:     "5s",
+		Retries:     5,
+		StartPeriod: "10s",
+	},
+	"mysql": {
+		Test:        []string{"CMD-SHELL", "mysqladmin ping -h 127.0.0.1 --silent || mariadb-admin ping -h 127.0.0.1 --silent"},
+		Interval:    "5s",
+		Timeout:     "5s",
+		Retries:     5,
+		StartPeriod: "20s",
+	},
+	"mongo": {
+		Test:        []string{"CMD-SHELL", "mongosh --quiet --eval \"db.adminCommand('ping').ok\" || mongo --quiet --eval \"db.adminCommand('ping').ok\""},
+		Interval:    "5s",
+		Timeout:     "10s",
+		Retries:     5,
+		StartPeriod: "10s",
+	},
+	"rabbitmq": {
+		Test:        []string{"CMD", "rabbitmq-diagnostics", "-q", "ping"},
+		Interval:    "10s",
+		Timeout:     "10s",
+		Retries:     5,
+		StartPeriod: "20s",
+	},
+	"kafka": {
+		// The CLI script lives in different places across the Apache, Bitnami and Confluent images
+		Test:        []string{"CMD-SHELL", "kafka-topics.sh --bootstrap-server localhost:9092 --list || /opt/kafka/bin/kafka-topics.sh --bootstrap-server localhost:9092 --list || kafka-topics --bootstrap-server localhost:9092 --list"},
+		Interval:    "10s",
+		Timeout:     "15s",
+		Retries:     5,
+		StartPeriod: "30s",
+	},
+}
+
+// probeImages maps image repository names (without registry, namespace or tag) to probe names.
+var probeImages = map[string]string{
+	"redis":                    "redis",
+	"redis-stack":              "redis",
+	"redis-stack-server":       "redis",
+	"postgres":                 "postgres",
+	"postgresql":               "postgres",
+	"postgis":                  "postgres",
+	"pgvector":                 "postgres",
+	"mysql":                    "mysql",
+	"mysql-server":             "mysql",
+	"mariadb":                  "mysql",
+	"mongo":                    "mongo",
+	"mongodb":                  "mongo",
+	"mongodb-community-server": "mongo",
+	"rabbitmq":                 "rabbitmq",
+	"kafka":                    "kafka",
+	"cp-kafka":                 "kafka",
+}
+
+// BuiltinHealthcheck returns the built-in readiness probe for a container image,
+// or nil if the image is not a recognized infrastructure service.
+// The returned config is a copy and may be modified by the caller.
+func BuiltinHealthcheck(image string) *HealthcheckConfig {
+	name, ok := probeImages[imageRepositoryName(image)]
+	if !ok {
+		return nil
+	}
+	probe := builtinProbes[name]
+	if test, ok := probe.Test.([]string); ok {
+		probe.Test = append([]string(nil), test...)
+	}
+	return &probe
+}
+
+// ResolveHealthcheck returns the health check for a service: its own healthcheck
+// when configured, otherwise the built-in probe for its container image (if any).
+func (s *Service) ResolveHealthcheck() *HealthcheckConfig {
+	if s.Healthcheck != nil {
+		return s.Healthcheck
+	}
+	if s.IsContainerService() {
+		return BuiltinHealthcheck(s.GetContainerImage())
+	}
+	return nil
+}
+
+// Liveness defaults for services with a healthcheck in azure.yaml.
+const (
+	// DefaultLivenessInterval is how often a ready service is checked when no interval is configured.
+	DefaultLivenessInterval = 10 * time.Second
+	// DefaultLivenessFailureThreshold is the number of consecutive failed checks, when no retries
+	// are configured, before a ready service is considered unhealthy.
+	DefaultLivenessFailureThreshold = 3
+)
+
+// applyHealthcheck applies a service's healthcheck from azure.yaml over the detected health check.
+// Besides the check itself (type, path, port, pattern, command), it configures readiness
+// (start_period, start_interval, successThreshold) and liveness (interval, retries, restartOnFailure):
+// services with a healthcheck keep being checked once ready.
+func applyHealthcheck(check *HealthCheckConfig, hc *HealthcheckConfig) {
+	if hc == nil || hc.IsDisabled() {
+		return
+	}
+
+	command := hc.GetCommand()
+	switch {
+	case hc.Type == "log":
+		check.Type = "output"
+	case hc.Type == "" && command != nil:
+		// Like docker compose, a test command without a type is run as the check
+		check.Type = "exec"
+	}
+
+	if hc.Path != "" {
+		check.Path = hc.Path
+	}
+	if hc.Pattern != "" {
+		check.LogMatch = hc.Pattern
+	}
+	if hc.Port > 0 {
+		check.Port = hc.Port
+	}
+	check.Command = command
+
+	if d, err := time.ParseDuration(hc.Timeout); err == nil && d > 0 {
+		check.ProbeTimeout = d
+	}
+	if d, err := time.ParseDuration(hc.StartInterval); err == nil && d > 0 {
+		check.Interval = d
+	}
+	if d, err := time.ParseDuration(hc.StartPeriod); err == nil && d > 0 {
+		check.Timeout += d
+	}
+	check.SuccessThreshold = hc.SuccessThreshold
+
+	check.LivenessInterval = DefaultLivenessInterval
+	if d, err := time.ParseDuration(hc.Interval); err == nil && d > 0 {
+		check.LivenessInterval = d
+	}
+	check.FailureThreshold = DefaultLivenessFailureThreshold
+	if hc.Retries > 0 {
+		check.FailureThreshold = hc.Retries
+	}
+	check.RestartOnFailure = hc.RestartOnFailure == nil || *hc.RestartOnFailure
+}
+
+// imageRepositoryName extracts the bare repository name from an image reference,
+// e.g. "docker.io/bitnami/kafka:3.7@sha256:..." -> "kafka".
+func imageRepositoryName(image string) string {
+	ref := strings.ToLower(strings.TrimSpace(image))
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	if i := strings.Index(ref, ":"); i >= 0 {
+		ref = ref[:i]
+	}
+	return ref
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestImageRepositoryName(t *testing.T) {
+	tests := map[string]string{
+		"redis":                               "redis",
+		"redis:7-alpine":                      "redis",
+		"docker.io/bitnami/kafka:3.7":         "kafka",
+		"confluentinc/cp-kafka:7.6.0":         "cp-kafka",
+		"localhost:5000/postgres":             "postgres",
+		"mcr.microsoft.com/mssql/server:2022": "server",
+		"mongo@sha256:abcdef":                 "mongo",
+		"  RabbitMQ:3-management ":            "rabbitmq",
+	}
+	for image, want := range tests {
+		if got := imageRepositoryName(image); got != want {
+			t.Errorf("imageRepositoryName(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestBuiltinHealthcheck(t *testing.T) {
+	tests := []struct {
+		image     string
+		wantProbe string // first arg after CMD/CMD-SHELL marker, empty for no probe
+	}{
+		{"redis:7-alpine", "redis-cli"},
+		{"postgres:16", "pg_isready -h 127.0.0.1 -U \"${POSTGRES_USER:-postgres}\""},
+		{"pgvector/pgvector:pg16", "pg_isready -h 127.0.0.1 -U \"${POSTGRES_USER:-postgres}\""},
+		{"mariadb:11", "mysqladmin ping -h 127.0.0.1 --silent || mariadb-admin ping -h 127.0.0.1 --silent"},
+		{"rabbitmq:3-management", "rabbitmq-diagnostics"},
+		{"nginx:latest", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			hc := BuiltinHealthcheck(tt.image)
+			if tt.wantProbe == "" {
+				if hc != nil {
+					t.Errorf("BuiltinHealthcheck(%q) = %+v, want nil", tt.image, hc)
+				}
+				return
+			}
+			if hc == nil {
+				t.Fatalf("BuiltinHealthcheck(%q) = nil, want probe", tt.image)
+			}
+			test, ok := hc.Test.([]string)
+			if !ok || len(test) < 2 || test[1] != tt.wantProbe {
+				t.Errorf("BuiltinHealthcheck(%q).Test = %v, want probe %q", tt.image, hc.Test, tt.wantProbe)
+			}
+		})
+	}
+}
+
+func TestBuiltinHealthcheck_ReturnsCopy(t *testing.T) {
+	hc := BuiltinHealthcheck("redis")
+	hc.Test.([]string)[1] = "changed"
+	hc.Retries = 99
+
+	again := BuiltinHealthcheck("redis")
+	if again.Test.([]string)[1] != "redis-cli" || again.Retries == 99 {
+		t.Errorf("BuiltinHealthcheck() shares state between calls: %+v", again)
+	}
+}
+
+func TestResolveHealthcheck(t *testing.T) {
+	custom := &HealthcheckConfig{Test: "http://localhost:6379/"}
+
+	tests := []struct {
+		name    string
+		svc     Service
+		wantNil bool
+		want    *HealthcheckConfig
+	}{
+		{name: "custom wins", svc: Service{Image: "redis", Healthcheck: custom}, want: custom},
+		{name: "builtin for image", svc: Service{Docker: &DockerConfig{Image: "mongo:7"}}},
+		{name: "no probe for project service", svc: Service{Project: "./redis"}, wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.svc.ResolveHealthcheck()
+			switch {
+			case tt.wantNil:
+				if got != nil {
+					t.Errorf("ResolveHealthcheck() = %+v, want nil", got)
+				}
+			case tt.want != nil:
+				if got != tt.want {
+					t.Errorf("ResolveHealthcheck() = %+v, want %+v", got, tt.want)
+				}
+			default:
+				if got == nil {
+					t.Error("ResolveHealthcheck() = nil, want built-in probe")
+				}
+			}
+		})
+	}
+}
+
+func TestDetectContainerRuntime_BuiltinProbe(t *testing.T) {
+	dir := t.TempDir()
+
+	runtime, err := DetectServiceRuntime("queue", Service{Image: "rabbitmq:3"}, map[int]bool{}, dir, "azd")
+	if err != nil {
+		t.Fatalf("DetectServiceRuntime() failed: %v", err)
+	}
+	if len(runtime.HealthCheck.Probe) == 0 || runtime.HealthCheck.Probe[1] != "rabbitmq-diagnostics" {
+		t.Errorf("HealthCheck.Probe = %v, want rabbitmq probe", runtime.HealthCheck.Probe)
+	}
+	if runtime.HealthCheck.Timeout != 80*time.Second {
+		t.Errorf("HealthCheck.Timeout = %v, want default plus start period (80s)", runtime.HealthCheck.Timeout)
+	}
+
+	custom := Service{Image: "rabbitmq:3", Healthcheck: &HealthcheckConfig{Type: "tcp"}}
+	runtime, err = DetectServiceRuntime("queue", custom, map[int]bool{}, dir, "azd")
+	if err != nil {
+		t.Fatalf("DetectServiceRuntime() failed: %v", err)
+	}
+	if len(runtime.HealthCheck.Probe) != 0 {
+		t.Errorf("HealthCheck.Probe = %v, want none when healthcheck is configured", runtime.HealthCheck.Probe)
+	}
+}
@@ -0,0 +1,93 @@
+//go:build !windows
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// processTree is the process group a service process leads. Services start in their own
+// group, so shells and launchers such as `npm run dev` are stopped together with the
+// processes they start.
+type processTree struct {
+	pgid int
+}
+
+// configureProcessTree makes the command start in a new process group led by the process.
+func configureProcessTree(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// newProcessTree returns the process group of a process started with configureProcessTree.
+func newProcessTree(process *os.Process) (*processTree, error) {
+	pgid, err := syscall.Getpgid(process.Pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get process group of process %d: %w", process.Pid, err)
+	}
+	if pgid != process.Pid {
+		return nil, fmt.Errorf("process %d does not lead its process group %d", process.Pid, pgid)
+	}
+	return &processTree{pgid: pgid}, nil
+}
+
+// signal sends sig to every process in the group.
+func (t *processTree) signal(sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return fmt.Errorf("unsupported signal %v", sig)
+	}
+	if err := syscall.Kill(-t.pgid, s); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("failed to signal process group %d: %w", t.pgid, err)
+	}
+	return nil
+}
+
+// running reports whether any process in the group is still running.
+func (t *processTree) running() bool {
+	return syscall.Kill(-t.pgid, 0) == nil
+}
+
+// signalProcessTree sends sig to the service's process group, or to the service process
+// when it doesn't lead a group.
+func signalProcessTree(process *ServiceProcess, sig os.Signal) error {
+	if process.tree == nil {
+		return process.Process.Signal(sig)
+	}
+	return process.tree.signal(sig)
+}
+
+// waitProcessTree waits until the processes left in the service's process group after the
+// service process exited are gone, or until deadline. It reports whether they are gone.
+func waitProcessTree(process *ServiceProcess, deadline time.Time) bool {
+	if process.tree == nil {
+		return true
+	}
+	for process.tree.running() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return true
+}
+
+// killProcessTree force-kills the service process and every process in its group.
+func killProcessTree(process *ServiceProcess) error {
+	if process.tree != nil {
+		if err := process.tree.signal(syscall.SIGKILL); err != nil {
+			return err
+		}
+	}
+	if err := process.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+//go:build !windows
+
+package service
+
+import (
+	"bufio"
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// startProcessGroup starts script with sh in its own process group, like StartService does.
+// The script's first line of output is returned, e.g. the PID of a background process.
+func startProcessGroup(t *testing.T, script string) (*ServiceProcess, string) {
+	t.Helper()
+	cmd := exec.CommandContext(context.Background(), "sh", "-c", script)
+	configureProcessTree(cmd)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("failed to create stdout pipe: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start process: %v", err)
+	}
+	tree, err := newProcessTree(cmd.Process)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		t.Fatalf("newProcessTree() error = %v", err)
+	}
+	process := &ServiceProcess{Name: "tree-test", Process: cmd.Process, tree: tree, exit: &exitWaiter{done: make(chan struct{})}}
+	t.Cleanup(func() { _ = killProcessTree(process) })
+
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read process output: %v", err)
+	}
+	return process, strings.TrimSpace(line)
+}
+
+// waitForProcessExit waits for the process with the given PID to exit. Zombies count as exited,
+// since they are only waiting for their parent to reap them.
+func waitForProcessExit(t *testing.T, pid int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		out, err := exec.CommandContext(context.Background(), "ps", "-o", "stat=", "-p", strconv.Itoa(pid)).Output()
+		state := strings.TrimSpace(string(out))
+		if err != nil || state == "" || strings.HasPrefix(state, "Z") {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("process %d is still running (state %s)", pid, state)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestNewProcessTree_LeadsGroup(t *testing.T) {
+	process, _ := startProcessGroup(t, "echo ready; sleep 60")
+
+	pgid, err := syscall.Getpgid(process.Process.Pid)
+	if err != nil {
+		t.Fatalf("Getpgid() error = %v", err)
+	}
+	if pgid != process.Process.Pid {
+		t.Errorf("process group = %d, want %d", pgid, process.Process.Pid)
+	}
+	if process.tree.pgid != pgid {
+		t.Errorf("tree.pgid = %d, want %d", process.tree.pgid, pgid)
+	}
+}
+
+func TestStopService_StopsProcessGroup(t *testing.T) {
+	// Background commands of sh ignore SIGINT, so the sleep outlives the shell like a
+	// server started by `npm run dev` can, and is killed when the timeout expires
+	process, output := startProcessGroup(t, "sleep 60 & echo $!; wait")
+	childPID, err := strconv.Atoi(output)
+	if err != nil {
+		t.Fatalf("unexpected output %q", output)
+	}
+
+	start := time.Now()
+	result, err := StopServiceWithResult(process, time.Second)
+	if err != nil {
+		t.Fatalf("StopServiceWithResult() error = %v", err)
+	}
+	if result.Outcome != StopOutcomeGraceful {
+		t.Errorf("Outcome = %q, want %q", result.Outcome, StopOutcomeGraceful)
+	}
+	waitForProcessExit(t, childPID)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("stopping took %v, want about 1s", elapsed)
+	}
+}
+
+func TestStopService_StopGracePeriod(t *testing.T) {
+	process, _ := startProcessGroup(t, "trap '' INT; echo ready; sleep 60")
+	process.Runtime.StopGracePeriod = 200 * time.Millisecond
+
+	start := time.Now()
+	result, err := StopServiceWithResult(process, 30*time.Second)
+	if err != nil {
+		t.Fatalf("StopServiceWithResult() error = %v", err)
+	}
+	if result.Outcome != StopOutcomeForced {
+		t.Errorf("Outcome = %q, want %q", result.Outcome, StopOutcomeForced)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("stopping took %v, want the 200ms grace period instead of the 30s timeout", elapsed)
+	}
+}
+
+func TestStopService_ForwardsStopSignal(t *testing.T) {
+	SetStopSignal(syscall.SIGTERM)
+	t.Cleanup(func() { SetStopSignal(nil) })
+
+	process, _ := startProcessGroup(t, "trap '' INT; trap 'exit 3' TERM; echo ready; while :; do sleep 0.1; done")
+
+	result, err := StopServiceWithResult(process, 5*time.Second)
+	if err != nil {
+		t.Fatalf("StopServiceWithResult() error = %v", err)
+	}
+	if result.Outcome != StopOutcomeGraceful {
+		t.Errorf("Outcome = %q, want %q", result.Outcome, StopOutcomeGraceful)
+	}
+	if result.ExitCode != 3 {
+		t.Errorf("ExitCode = %d, want 3 (exited from the TERM trap)", result.ExitCode)
+	}
+}
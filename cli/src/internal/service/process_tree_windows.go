@@ -0,0 +1,102 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// processTree is the job object a service process runs in. Processes started by a process in
+// a job are in the job too, so terminating the job stops every descendant of the service, even
+// ones whose parent already exited (e.g. node started by an npm shim), which taskkill /T misses
+// because it follows parent process IDs.
+type processTree struct {
+	mu  sync.Mutex
+	job windows.Handle
+}
+
+// configureProcessTree does nothing on Windows; the process is assigned to a job object
+// once it has started.
+func configureProcessTree(_ *exec.Cmd) {}
+
+// newProcessTree creates a job object and assigns the started process to it.
+// Processes the service started before it was assigned are not in the job; killProcessTree
+// runs taskkill /T as well to stop those.
+func newProcessTree(process *os.Process) (*processTree, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job object: %w", err)
+	}
+
+	if process.Pid < 0 || process.Pid > 0x7FFFFFFF {
+		_ = windows.CloseHandle(job)
+		return nil, fmt.Errorf("invalid PID %d for Windows process handle", process.Pid)
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(process.Pid))
+	if err != nil {
+		_ = windows.CloseHandle(job)
+		return nil, fmt.Errorf("failed to open process %d: %w", process.Pid, err)
+	}
+	defer windows.CloseHandle(handle) //nolint:errcheck
+
+	if err := windows.AssignProcessToJobObject(job, handle); err != nil {
+		_ = windows.CloseHandle(job)
+		return nil, fmt.Errorf("failed to assign process %d to job object: %w", process.Pid, err)
+	}
+	return &processTree{job: job}, nil
+}
+
+// kill terminates every process in the job and releases it. It is a no-op once released.
+func (t *processTree) kill() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.job == 0 {
+		return nil
+	}
+
+	err := windows.TerminateJobObject(t.job, 1)
+	_ = windows.CloseHandle(t.job)
+	t.job = 0
+	if err != nil {
+		return fmt.Errorf("failed to terminate job object: %w", err)
+	}
+	return nil
+}
+
+// killProcessTree force-kills a service process and all of its descendants: the processes
+// in its job object, and the processes taskkill /T finds by parent process ID.
+func killProcessTree(process *ServiceProcess) error {
+	// taskkill returns an error if the process already exited, which is fine
+	// #nosec G204 -- PID is from os.Process which is a validated integer
+	cmd := exec.CommandContext(context.Background(), "taskkill", "/F", "/T", "/PID", fmt.Sprintf("%d", process.Process.Pid))
+	if err := cmd.Run(); err != nil {
+		slog.Debug("taskkill completed with error (process may have already exited)",
+			slog.String("service", process.Name),
+			slog.String("error", err.Error()))
+	}
+
+	if process.tree == nil {
+		return nil
+	}
+	return process.tree.kill()
+}
+
+// signalProcessTree sends sig to the service process. Windows services are stopped with
+// killProcessTree instead, since console processes can't be sent signals.
+func signalProcessTree(process *ServiceProcess, sig os.Signal) error {
+	return process.Process.Signal(sig)
+}
+
+// waitProcessTree reports that nothing is left to wait for; killProcessTree stops the
+// remaining processes of the job.
+func waitProcessTree(_ *ServiceProcess, _ time.Time) bool {
+	return true
+}
@@ -0,0 +1,69 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessTree_KillsDescendants(t *testing.T) {
+	// cmd starts a ping in the background and exits, leaving an orphaned grandchild
+	// that taskkill /T can't find by parent process ID
+	cmd := exec.CommandContext(context.Background(), "cmd", "/c", "start", "/b", "ping", "-n", "60", "127.0.0.1")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start process: %v", err)
+	}
+	tree, err := newProcessTree(cmd.Process)
+	if err != nil {
+		t.Fatalf("newProcessTree() error = %v", err)
+	}
+	process := &ServiceProcess{Name: "tree-test", Process: cmd.Process, tree: tree}
+	_, _ = process.Wait()
+
+	var pid int
+	deadline := time.Now().Add(5 * time.Second)
+	for pid == 0 && time.Now().Before(deadline) {
+		pid = findProcessByName(t, "PING.EXE")
+		time.Sleep(100 * time.Millisecond)
+	}
+	if pid == 0 {
+		t.Skip("background ping did not start")
+	}
+
+	if err := killProcessTree(process); err != nil {
+		t.Errorf("killProcessTree() error = %v", err)
+	}
+
+	deadline = time.Now().Add(5 * time.Second)
+	for processIsRunning(pid) == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("grandchild process %d is still running after killProcessTree", pid)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// Killing again is a no-op
+	if err := tree.kill(); err != nil {
+		t.Errorf("second kill() error = %v", err)
+	}
+}
+
+// findProcessByName returns the PID of a running process with the given image name, or 0.
+func findProcessByName(t *testing.T, name string) int {
+	t.Helper()
+	out, err := exec.CommandContext(context.Background(), "tasklist", "/FI", "IMAGENAME eq "+name, "/FO", "CSV", "/NH").Output()
+	if err != nil {
+		return 0
+	}
+	var image string
+	var pid int
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(out)), "%q,\"%d\"", &image, &pid); err != nil {
+		return 0
+	}
+	return pid
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Profile is a named set of overrides in azure.yaml (e.g. dev, test, staging), merged over the
+// base configuration when selected with --profile.
+type Profile struct {
+	Services map[string]ProfileService `yaml:"services,omitempty"`
+}
+
+// ProfileService overrides parts of a service's configuration.
+// Commands and ports replace the base values; environment and env are merged over them.
+type ProfileService struct {
+	Run         string      `yaml:"run,omitempty"`
+	Command     string      `yaml:"command,omitempty"`
+	Entrypoint  string      `yaml:"entrypoint,omitempty"`
+	Build       string      `yaml:"build,omitempty"`
+	Ports       []string    `yaml:"ports,omitempty"`
+	Environment Environment `yaml:"environment,omitempty"`
+	Env         Environment `yaml:"env,omitempty"`
+}
+
+// activeProfile holds the profile selected with --profile.
+var activeProfile = struct {
+	mu   sync.RWMutex
+	name string
+}{}
+
+// SetActiveProfile selects the profile applied to every azure.yaml parsed afterwards in this
+// process. An empty name uses the base configuration.
+func SetActiveProfile(name string) {
+	activeProfile.mu.Lock()
+	defer activeProfile.mu.Unlock()
+	activeProfile.name = strings.TrimSpace(name)
+}
+
+// ActiveProfile returns the profile selected with SetActiveProfile, or an empty string.
+func ActiveProfile() string {
+	activeProfile.mu.RLock()
+	defer activeProfile.mu.RUnlock()
+	return activeProfile.name
+}
+
+// ProfileNames returns the names of the profiles defined in azure.yaml, sorted.
+func (a *AzureYaml) ProfileNames() []string {
+	names := make([]string, 0, len(a.Profiles))
+	for name := range a.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile merges the named profile over the services. It fails if the profile isn't
+// defined or overrides a service that doesn't exist. An empty name leaves the services unchanged.
+func (a *AzureYaml) ApplyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+	profile, ok := a.Profiles[name]
+	if !ok {
+		if len(a.Profiles) == 0 {
+			return fmt.Errorf("profile '%s' not found: azure.yaml doesn't define any profiles", name)
+		}
+		return fmt.Errorf("profile '%s' not found in azure.yaml (available: %s)", name, strings.Join(a.ProfileNames(), ", "))
+	}
+
+	for serviceName, override := range profile.Services {
+		svc, exists := a.Services[serviceName]
+		if !exists {
+			return fmt.Errorf("profile '%s': service '%s' not found in azure.yaml", name, serviceName)
+		}
+		a.Services[serviceName] = mergeProfileService(svc, override)
+	}
+	return nil
+}
+
+// mergeProfileService applies a profile's overrides to a service. Setting any of run, command
+// or entrypoint replaces all three, so the profile's command isn't combined with the base one.
+func mergeProfileService(svc Service, override ProfileService) Service {
+	if override.Run != "" || override.Command != "" || override.Entrypoint != "" {
+		svc.Run = override.Run
+		svc.Command = override.Command
+		svc.Entrypoint = override.Entrypoint
+	}
+	if override.Build != "" {
+		svc.Build = override.Build
+	}
+	if len(override.Ports) > 0 {
+		svc.Ports = override.Ports
+	}
+	svc.Environment = mergeEnvironment(svc.Environment, override.Environment)
+	svc.Env = mergeEnvironment(svc.Env, override.Env)
+	return svc
+}
+
+// mergeEnvironment returns base with overrides merged over it, without modifying either.
+func mergeEnvironment(base, overrides Environment) Environment {
+	if len(overrides) == 0 {
+		return base
+	}
+	merged := make(Environment, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
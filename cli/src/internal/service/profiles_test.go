@@ -0,0 +1,112 @@
+package service
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const profilesAzureYaml = `name: app
+services:
+  api:
+    project: ./api
+    language: python
+    command: uvicorn main:app --reload
+    ports: ["8000"]
+    environment:
+      LOG_LEVEL: debug
+      DATABASE_URL: postgres://localhost/dev
+  web:
+    project: ./web
+    language: ts
+    ports: ["3000"]
+profiles:
+  test:
+    services:
+      api:
+        run: pytest --live-server
+        ports: ["8100"]
+        environment:
+          DATABASE_URL: postgres://localhost/test
+  broken:
+    services:
+      worker:
+        command: python worker.py
+`
+
+func TestParseAzureYaml_AppliesActiveProfile(t *testing.T) {
+	dir := t.TempDir()
+	writeAzureYaml(t, dir, profilesAzureYaml)
+
+	base, err := ParseAzureYaml(dir)
+	if err != nil {
+		t.Fatalf("ParseAzureYaml() failed: %v", err)
+	}
+	if api := base.Services["api"]; api.Command != "uvicorn main:app --reload" || api.Ports[0] != "8000" {
+		t.Errorf("base api = %+v, want the base command and port", api)
+	}
+	if names := base.ProfileNames(); strings.Join(names, ",") != "broken,test" {
+		t.Errorf("ProfileNames() = %v, want [broken test]", names)
+	}
+
+	SetActiveProfile("test")
+	t.Cleanup(func() { SetActiveProfile("") })
+
+	azureYaml, err := ParseAzureYaml(dir)
+	if err != nil {
+		t.Fatalf("ParseAzureYaml() with profile failed: %v", err)
+	}
+
+	api := azureYaml.Services["api"]
+	if entrypoint, command := api.RunCommand(); entrypoint != "" || command != "pytest --live-server" {
+		t.Errorf("api command = %q %q, want the profile's run", entrypoint, command)
+	}
+	if api.Command != "" {
+		t.Errorf("api.Command = %q, want it replaced by the profile's run", api.Command)
+	}
+	if len(api.Ports) != 1 || api.Ports[0] != "8100" {
+		t.Errorf("api.Ports = %v, want [8100]", api.Ports)
+	}
+	if api.Environment["DATABASE_URL"] != "postgres://localhost/test" || api.Environment["LOG_LEVEL"] != "debug" {
+		t.Errorf("api.Environment = %v, want the profile merged over the base", api.Environment)
+	}
+	if api.Project != filepath.Join(dir, "api") {
+		t.Errorf("api.Project = %q, want the base project", api.Project)
+	}
+	if web := azureYaml.Services["web"]; web.Ports[0] != "3000" {
+		t.Errorf("web.Ports = %v, want the base ports", web.Ports)
+	}
+}
+
+func TestAzureYaml_ApplyProfileErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeAzureYaml(t, dir, profilesAzureYaml)
+
+	tests := []struct {
+		profile string
+		wantErr string
+	}{
+		{profile: "staging", wantErr: "available: broken, test"},
+		{profile: "broken", wantErr: "service 'worker' not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			SetActiveProfile(tt.profile)
+			t.Cleanup(func() { SetActiveProfile("") })
+
+			_, err := ParseAzureYaml(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseAzureYaml() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	var noProfiles AzureYaml
+	if err := noProfiles.ApplyProfile("test"); err == nil || !strings.Contains(err.Error(), "doesn't define any profiles") {
+		t.Errorf("ApplyProfile() without profiles error = %v", err)
+	}
+	if err := noProfiles.ApplyProfile(""); err != nil {
+		t.Errorf("ApplyProfile(\"\") error = %v, want nil", err)
+	}
+}
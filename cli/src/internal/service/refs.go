@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ServiceRef identifies a service defined in another azd project.
+// It is written in azure.yaml as `ref: <path>#<service>`, where path is the
+// referenced project directory (or its azure.yaml) relative to the current azure.yaml.
+type ServiceRef struct {
+	Path    string
+	Service string
+}
+
+// ParseServiceRef parses a `<path>#<service>` reference.
+func ParseServiceRef(ref string) (ServiceRef, error) {
+	path, name, ok := strings.Cut(strings.TrimSpace(ref), "#")
+	if !ok || strings.TrimSpace(path) == "" || strings.TrimSpace(name) == "" {
+		return ServiceRef{}, fmt.Errorf("invalid service ref %q: expected '<path>#<service>'", ref)
+	}
+	return ServiceRef{Path: strings.TrimSpace(path), Service: strings.TrimSpace(name)}, nil
+}
+
+// ProjectDir returns the directory of the referenced project. Relative paths are resolved
+// against azureYamlDir, the directory of the azure.yaml declaring the ref.
+func (r ServiceRef) ProjectDir(azureYamlDir string) string {
+	refDir := r.Path
+	if !filepath.IsAbs(refDir) {
+		refDir = filepath.Join(azureYamlDir, refDir)
+	}
+	refDir = filepath.Clean(refDir)
+	if strings.EqualFold(filepath.Ext(refDir), ".yaml") || strings.EqualFold(filepath.Ext(refDir), ".yml") {
+		refDir = filepath.Dir(refDir)
+	}
+	return refDir
+}
+
+// resolveServiceRefs replaces services declared with `ref` by the referenced service definition.
+//
+// The referenced service keeps its own project path, language, command and health checks.
+// Fields set on the referencing entry override it: ports, environment and env (merged),
+// envFile, mode and uses. The referenced service's own `uses` are dropped because they name services of the
+// other project.
+func resolveServiceRefs(azureYaml *AzureYaml, azureYamlDir string, visited map[string]bool) error {
+	for name, local := range azureYaml.Services {
+		if local.Ref == "" {
+			continue
+		}
+
+		ref, err := ParseServiceRef(local.Ref)
+		if err != nil {
+			return fmt.Errorf("service '%s': %w", name, err)
+		}
+
+		refDir := ref.ProjectDir(azureYamlDir)
+		refYamlPath := filepath.Join(refDir, "azure.yaml")
+		if _, err := os.Stat(refYamlPath); err != nil {
+			return fmt.Errorf("service '%s': referenced project %s has no azure.yaml", name, refDir)
+		}
+		if visited[refYamlPath] {
+			return fmt.Errorf("service '%s': circular service reference to %s", name, refYamlPath)
+		}
+
+		// Profiles are selected per project, so the referenced project is loaded without one
+		refYaml, err := parseAzureYaml(refDir, "", visited)
+		// visited holds the projects on the current chain of refs only, so sibling refs
+		// into the same project aren't mistaken for a cycle
+		delete(visited, refYamlPath)
+		if err != nil {
+			return fmt.Errorf("service '%s': failed to load referenced project %s: %w", name, refDir, err)
+		}
+
+		target, exists := refYaml.Services[ref.Service]
+		if !exists {
+			return fmt.Errorf("service '%s': service '%s' not found in %s", name, ref.Service, refYamlPath)
+		}
+
+		resolved := mergeReferencedService(target, local)
+		if resolved.Project == "" {
+			// Services without a project run from their own project root
+			resolved.Project = refDir
+		}
+		if resolved.RefRoot == "" {
+			resolved.RefRoot = refDir
+		}
+
+		slog.Debug("resolved service reference", "service", name, "ref", local.Ref, "project", resolved.Project)
+		azureYaml.Services[name] = resolved
+	}
+	return nil
+}
+
+// mergeReferencedService applies overrides from the referencing entry to the referenced service.
+func mergeReferencedService(target, local Service) Service {
+	resolved := target
+	resolved.Ref = local.Ref
+	resolved.Uses = local.Uses
+	resolved.DependsOn = local.DependsOn
+
+	if len(local.Ports) > 0 {
+		resolved.Ports = local.Ports
+	}
+	if local.Mode != "" {
+		resolved.Mode = local.Mode
+	}
+	if len(local.Environment) > 0 {
+		env := make(Environment, len(target.Environment)+len(local.Environment))
+		for k, v := range target.Environment {
+			env[k] = v
+		}
+		for k, v := range local.Environment {
+			env[k] = v
+		}
+		resolved.Environment = env
+	}
+	if len(local.Env) > 0 {
+		env := make(Environment, len(target.Env)+len(local.Env))
+		for k, v := range target.Env {
+			env[k] = v
+		}
+		for k, v := range local.Env {
+			env[k] = v
+		}
+		resolved.Env = env
+	}
+	if local.EnvFile != "" {
+		resolved.EnvFile = local.EnvFile
+	}
+	if local.Restart != nil {
+		resolved.Restart = local.Restart
+	}
+	if local.StopGracePeriod != "" {
+		resolved.StopGracePeriod = local.StopGracePeriod
+	}
+	return resolved
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeAzureYaml(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		t.Fatalf("failed to create %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "azure.yaml"), []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write azure.yaml: %v", err)
+	}
+}
+
+func TestParseServiceRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    ServiceRef
+		wantErr bool
+	}{
+		{ref: "../other#api", want: ServiceRef{Path: "../other", Service: "api"}},
+		{ref: " ../other/azure.yaml # api ", want: ServiceRef{Path: "../other/azure.yaml", Service: "api"}},
+		{ref: "../other", wantErr: true},
+		{ref: "#api", wantErr: true},
+		{ref: "../other#", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := ParseServiceRef(tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseServiceRef(%q) expected error", tt.ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseServiceRef(%q) failed: %v", tt.ref, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseServiceRef(%q) = %+v, want %+v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAzureYaml_ResolvesServiceRef(t *testing.T) {
+	root := t.TempDir()
+	otherDir := filepath.Join(root, "other")
+	appDir := filepath.Join(root, "app")
+
+	writeAzureYaml(t, otherDir, `name: other
+services:
+  api:
+    project: ./src/api
+    language: python
+    host: containerapp
+    uses:
+      - db
+    environment:
+      LOG_LEVEL: info
+      REGION: west
+  db:
+    image: postgres
+`)
+	writeAzureYaml(t, appDir, `name: app
+services:
+  web:
+    project: ./web
+    language: js
+    uses:
+      - orders
+  orders:
+    ref: ../other#api
+    ports:
+      - "5100"
+    environment:
+      LOG_LEVEL: debug
+`)
+
+	azureYaml, err := ParseAzureYaml(appDir)
+	if err != nil {
+		t.Fatalf("ParseAzureYaml() failed: %v", err)
+	}
+
+	orders, exists := azureYaml.Services["orders"]
+	if !exists {
+		t.Fatal("referenced service 'orders' missing")
+	}
+	if want := filepath.Join(otherDir, "src", "api"); orders.Project != want {
+		t.Errorf("Project = %q, want %q", orders.Project, want)
+	}
+	if orders.Language != "python" {
+		t.Errorf("Language = %q, want python", orders.Language)
+	}
+	if orders.RefRoot != otherDir {
+		t.Errorf("RefRoot = %q, want %q", orders.RefRoot, otherDir)
+	}
+	if len(orders.Uses) != 0 {
+		t.Errorf("Uses = %v, want referenced project's uses to be dropped", orders.Uses)
+	}
+	if len(orders.Ports) != 1 || orders.Ports[0] != "5100" {
+		t.Errorf("Ports = %v, want local override [5100]", orders.Ports)
+	}
+	if orders.Environment["LOG_LEVEL"] != "debug" || orders.Environment["REGION"] != "west" {
+		t.Errorf("Environment = %v, want merged with local override", orders.Environment)
+	}
+}
+
+func TestParseAzureYaml_SiblingRefsIntoSameProject(t *testing.T) {
+	root := t.TempDir()
+	otherDir := filepath.Join(root, "other")
+	appDir := filepath.Join(root, "app")
+
+	writeAzureYaml(t, otherDir, `name: other
+services:
+  api:
+    project: ./api
+    language: python
+  worker:
+    project: ./worker
+    language: python
+`)
+	writeAzureYaml(t, appDir, `name: app
+services:
+  orders:
+    ref: ../other#api
+  jobs:
+    ref: ../other#worker
+`)
+
+	azureYaml, err := ParseAzureYaml(appDir)
+	if err != nil {
+		t.Fatalf("ParseAzureYaml() failed: %v", err)
+	}
+	if want := filepath.Join(otherDir, "api"); azureYaml.Services["orders"].Project != want {
+		t.Errorf("orders.Project = %q, want %q", azureYaml.Services["orders"].Project, want)
+	}
+	if want := filepath.Join(otherDir, "worker"); azureYaml.Services["jobs"].Project != want {
+		t.Errorf("jobs.Project = %q, want %q", azureYaml.Services["jobs"].Project, want)
+	}
+}
+
+func TestParseAzureYaml_ServiceRefErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		other   string
+		ref     string
+		wantErr string
+	}{
+		{
+			name:    "missing project",
+			ref:     "../missing#api",
+			wantErr: "has no azure.yaml",
+		},
+		{
+			name:    "missing service",
+			other:   "name: other\nservices:\n  web:\n    project: ./web\n",
+			ref:     "../other#api",
+			wantErr: "service 'api' not found",
+		},
+		{
+			name:    "circular reference",
+			other:   "name: other\nservices:\n  api:\n    ref: ../app#orders\n",
+			ref:     "../other#api",
+			wantErr: "circular service reference",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if tt.other != "" {
+				writeAzureYaml(t, filepath.Join(root, "other"), tt.other)
+			}
+			appDir := filepath.Join(root, "app")
+			writeAzureYaml(t, appDir, "name: app\nservices:\n  orders:\n    ref: "+tt.ref+"\n")
+
+			_, err := ParseAzureYaml(appDir)
+			if err == nil {
+				t.Fatal("ParseAzureYaml() expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Restart policies for services whose process exits.
+const (
+	RestartPolicyNo        = "no"         // Never relaunch (default)
+	RestartPolicyOnFailure = "on-failure" // Relaunch when the process exits with a non-zero code
+	RestartPolicyAlways    = "always"     // Relaunch whenever the process exits
+)
+
+// Restart backoff defaults.
+const (
+	// DefaultRestartBackoff is the delay before the first relaunch; it doubles with each consecutive relaunch.
+	DefaultRestartBackoff = time.Second
+	// DefaultRestartMaxBackoff caps the delay between relaunches.
+	DefaultRestartMaxBackoff = 30 * time.Second
+	// RestartResetAfter is how long a process must run for the next exit to count as the first again.
+	RestartResetAfter = 10 * time.Second
+)
+
+// RestartPolicy configures whether a service is relaunched when its process exits.
+// In azure.yaml it is either a policy string ("no", "on-failure", "on-failure:5", "always")
+// or an object with the policy, maxRetries and backoff.
+type RestartPolicy struct {
+	// Policy is "no", "on-failure" or "always".
+	Policy string `yaml:"policy,omitempty"`
+	// MaxRetries is the number of consecutive relaunches before giving up (0 = unlimited).
+	MaxRetries int `yaml:"maxRetries,omitempty"`
+	// Backoff is the delay before the first relaunch, doubled for each consecutive relaunch (e.g., "1s").
+	Backoff string `yaml:"backoff,omitempty"`
+	// MaxBackoff caps the delay between relaunches (e.g., "30s").
+	MaxBackoff string `yaml:"maxBackoff,omitempty"`
+}
+
+// UnmarshalYAML accepts a policy string (Docker Compose style, e.g. "on-failure:3") or an object.
+func (p *RestartPolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var policy string
+	if err := unmarshal(&policy); err == nil {
+		parsed, err := ParseRestartPolicy(policy)
+		if err != nil {
+			return err
+		}
+		*p = parsed
+		return nil
+	}
+
+	type restartPolicyObject RestartPolicy
+	var obj restartPolicyObject
+	if err := unmarshal(&obj); err != nil {
+		return err
+	}
+	*p = RestartPolicy(obj)
+	return p.Validate()
+}
+
+// ParseRestartPolicy parses a policy string: "no", "on-failure", "on-failure:<max-retries>" or "always".
+// "unless-stopped" is accepted as "always", since services are only stopped on purpose.
+func ParseRestartPolicy(value string) (RestartPolicy, error) {
+	name, retries, hasRetries := strings.Cut(strings.TrimSpace(value), ":")
+	policy := RestartPolicy{Policy: name}
+	if name == "unless-stopped" {
+		policy.Policy = RestartPolicyAlways
+	}
+	if hasRetries {
+		if policy.Policy != RestartPolicyOnFailure {
+			return RestartPolicy{}, fmt.Errorf("invalid restart policy %q: max retries only apply to on-failure", value)
+		}
+		n, err := strconv.Atoi(retries)
+		if err != nil || n < 0 {
+			return RestartPolicy{}, fmt.Errorf("invalid restart policy %q: max retries must be a non-negative number", value)
+		}
+		policy.MaxRetries = n
+	}
+	return policy, policy.Validate()
+}
+
+// Validate checks the policy name and durations.
+func (p *RestartPolicy) Validate() error {
+	switch p.Policy {
+	case "", RestartPolicyNo, RestartPolicyOnFailure, RestartPolicyAlways:
+	default:
+		return fmt.Errorf("invalid restart policy %q: must be no, on-failure or always", p.Policy)
+	}
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("invalid restart maxRetries %d: must not be negative", p.MaxRetries)
+	}
+	for _, d := range []string{p.Backoff, p.MaxBackoff} {
+		if d == "" {
+			continue
+		}
+		if parsed, err := time.ParseDuration(d); err != nil || parsed < 0 {
+			return fmt.Errorf("invalid restart backoff %q: must be a duration such as 1s", d)
+		}
+	}
+	return nil
+}
+
+// GetPolicy returns the restart policy, with "no" as the default.
+func (p *RestartPolicy) GetPolicy() string {
+	if p == nil || p.Policy == "" {
+		return RestartPolicyNo
+	}
+	return p.Policy
+}
+
+// ShouldRestart reports whether a process that exited with exitCode is relaunched,
+// given the number of consecutive relaunches so far.
+func (p *RestartPolicy) ShouldRestart(exitCode int, restarts int) bool {
+	switch p.GetPolicy() {
+	case RestartPolicyAlways:
+	case RestartPolicyOnFailure:
+		if exitCode == 0 {
+			return false
+		}
+	default:
+		return false
+	}
+	return p.MaxRetries == 0 || restarts < p.MaxRetries
+}
+
+// Delay returns how long to wait before relaunch number restart (0 for the first relaunch).
+func (p *RestartPolicy) Delay(restart int) time.Duration {
+	backoff, maxBackoff := DefaultRestartBackoff, DefaultRestartMaxBackoff
+	if p != nil {
+		if d, err := time.ParseDuration(p.Backoff); err == nil && d >= 0 {
+			backoff = d
+		}
+		if d, err := time.ParseDuration(p.MaxBackoff); err == nil && d > 0 {
+			maxBackoff = d
+		}
+	}
+
+	delay := backoff
+	for i := 0; i < restart && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// HasRestartPolicies reports whether any of the processes is relaunched when it exits.
+func HasRestartPolicies(processes map[string]*ServiceProcess) bool {
+	for _, process := range processes {
+		if process != nil && process.Process != nil && process.Runtime.Restart.GetPolicy() != RestartPolicyNo {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRestartPolicy_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    RestartPolicy
+		wantErr bool
+	}{
+		{name: "no", yaml: "restart: no", want: RestartPolicy{Policy: RestartPolicyNo}},
+		{name: "always", yaml: "restart: always", want: RestartPolicy{Policy: RestartPolicyAlways}},
+		{name: "unless-stopped", yaml: "restart: unless-stopped", want: RestartPolicy{Policy: RestartPolicyAlways}},
+		{name: "on-failure with retries", yaml: "restart: on-failure:5", want: RestartPolicy{Policy: RestartPolicyOnFailure, MaxRetries: 5}},
+		{
+			name: "object",
+			yaml: "restart:\n  policy: on-failure\n  maxRetries: 3\n  backoff: 2s\n  maxBackoff: 1m",
+			want: RestartPolicy{Policy: RestartPolicyOnFailure, MaxRetries: 3, Backoff: "2s", MaxBackoff: "1m"},
+		},
+		{name: "unknown policy", yaml: "restart: sometimes", wantErr: true},
+		{name: "retries on always", yaml: "restart: always:3", wantErr: true},
+		{name: "invalid backoff", yaml: "restart:\n  policy: always\n  backoff: soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var service Service
+			err := yaml.Unmarshal([]byte("project: ./api\n"+tt.yaml), &service)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Unmarshal() expected error, got restart %+v", service.Restart)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if service.Restart == nil || *service.Restart != tt.want {
+				t.Errorf("Restart = %+v, want %+v", service.Restart, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestartPolicy_ShouldRestart(t *testing.T) {
+	var none *RestartPolicy
+	onFailure := &RestartPolicy{Policy: RestartPolicyOnFailure, MaxRetries: 2}
+	always := &RestartPolicy{Policy: RestartPolicyAlways}
+
+	tests := []struct {
+		name     string
+		policy   *RestartPolicy
+		exitCode int
+		restarts int
+		want     bool
+	}{
+		{name: "no policy", policy: none, exitCode: 1, want: false},
+		{name: "on-failure crash", policy: onFailure, exitCode: 1, restarts: 1, want: true},
+		{name: "on-failure clean exit", policy: onFailure, exitCode: 0, want: false},
+		{name: "on-failure retries exhausted", policy: onFailure, exitCode: 1, restarts: 2, want: false},
+		{name: "always clean exit", policy: always, exitCode: 0, restarts: 100, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.ShouldRestart(tt.exitCode, tt.restarts); got != tt.want {
+				t.Errorf("ShouldRestart(%d, %d) = %v, want %v", tt.exitCode, tt.restarts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestartPolicy_Delay(t *testing.T) {
+	policy := &RestartPolicy{Policy: RestartPolicyAlways, Backoff: "500ms", MaxBackoff: "3s"}
+	want := []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+	for restart, d := range want {
+		if got := policy.Delay(restart); got != d {
+			t.Errorf("Delay(%d) = %s, want %s", restart, got, d)
+		}
+	}
+
+	var defaults *RestartPolicy
+	if got := defaults.Delay(0); got != DefaultRestartBackoff {
+		t.Errorf("Delay(0) with defaults = %s, want %s", got, DefaultRestartBackoff)
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ParseServiceFilter splits a comma-separated --service value into names and glob patterns.
+func ParseServiceFilter(filter string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(filter, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// isGlobPattern reports whether a service filter entry uses wildcards (*, ? or [...]).
+func isGlobPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// MatchServiceNames returns the sorted names matched by the patterns: service names, or glob
+// patterns such as "svc-*" (path.Match syntax). Every pattern must match at least one of the
+// available services, so typos are reported instead of silently selecting nothing.
+func MatchServiceNames(available, patterns []string) ([]string, error) {
+	sorted := slices.Sorted(slices.Values(available))
+	var names []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		if !isGlobPattern(pattern) {
+			if !slices.Contains(sorted, pattern) {
+				return nil, fmt.Errorf("service '%s' not found in azure.yaml (available: %s)", pattern, strings.Join(sorted, ", "))
+			}
+			names = append(names, pattern)
+			continue
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid service pattern '%s': %w", pattern, err)
+		}
+		matched := false
+		for _, name := range sorted {
+			if ok, _ := path.Match(pattern, name); ok {
+				names = append(names, name)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no service matches '%s' (available: %s)", pattern, strings.Join(sorted, ", "))
+		}
+	}
+
+	sort.Strings(names)
+	return slices.Compact(names), nil
+}
+
+// WithDependencies returns the sorted names of the selected services and of every service they
+// depend on, directly or through other services. dependencies maps each service to the names it
+// depends on; names that aren't services (e.g. resources in uses) are ignored.
+func WithDependencies(selected []string, dependencies map[string][]string) []string {
+	included := make(map[string]bool, len(selected))
+	queue := slices.Clone(selected)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if included[name] {
+			continue
+		}
+		included[name] = true
+		for _, dep := range dependencies[name] {
+			if _, isService := dependencies[dep]; isService && !included[dep] {
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(included))
+}
+
+// SelectServices returns the services matched by the --service patterns (see MatchServiceNames),
+// together with the services they depend on, so the selection can start on its own.
+// The names of the services added as dependencies are returned sorted.
+// All services are returned when no patterns are given.
+func SelectServices(azureYaml *AzureYaml, patterns []string) (map[string]Service, []string, error) {
+	if azureYaml == nil || len(azureYaml.Services) == 0 {
+		return make(map[string]Service), nil, nil
+	}
+	if len(patterns) == 0 {
+		return azureYaml.Services, nil, nil
+	}
+
+	available := make([]string, 0, len(azureYaml.Services))
+	dependencies := make(map[string][]string, len(azureYaml.Services))
+	for name, svc := range azureYaml.Services {
+		available = append(available, name)
+		dependencies[name] = svc.Dependencies()
+	}
+
+	matched, err := MatchServiceNames(available, patterns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selected := make(map[string]Service)
+	var added []string
+	for _, name := range WithDependencies(matched, dependencies) {
+		selected[name] = azureYaml.Services[name]
+		if !slices.Contains(matched, name) {
+			added = append(added, name)
+		}
+	}
+	return selected, added, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseServiceFilter(t *testing.T) {
+	got := ParseServiceFilter(" api, web ,,svc-*")
+	want := []string{"api", "web", "svc-*"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseServiceFilter() = %v, want %v", got, want)
+	}
+	if got := ParseServiceFilter(""); got != nil {
+		t.Errorf("ParseServiceFilter(\"\") = %v, want nil", got)
+	}
+}
+
+func TestMatchServiceNames(t *testing.T) {
+	available := []string{"web", "svc-orders", "svc-users", "api"}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+		wantErr  string
+	}{
+		{name: "names", patterns: []string{"web", "api"}, want: []string{"api", "web"}},
+		{name: "glob", patterns: []string{"svc-*"}, want: []string{"svc-orders", "svc-users"}},
+		{name: "character class", patterns: []string{"svc-[u]*"}, want: []string{"svc-users"}},
+		{name: "overlapping patterns", patterns: []string{"svc-users", "svc-*", " web "}, want: []string{"svc-orders", "svc-users", "web"}},
+		{name: "unknown name", patterns: []string{"worker"}, wantErr: "service 'worker' not found in azure.yaml (available: api, svc-orders, svc-users, web)"},
+		{name: "glob without match", patterns: []string{"job-*"}, wantErr: "no service matches 'job-*'"},
+		{name: "invalid glob", patterns: []string{"svc-["}, wantErr: "invalid service pattern 'svc-['"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchServiceNames(available, tt.patterns)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("MatchServiceNames() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MatchServiceNames() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchServiceNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectServices(t *testing.T) {
+	azureYaml := &AzureYaml{
+		Services: map[string]Service{
+			"web":        {Uses: []string{"api"}},
+			"api":        {Uses: []string{"db", "storage"}, DependsOn: []string{"auth"}},
+			"auth":       {},
+			"db":         {},
+			"svc-orders": {DependsOn: []string{"db"}},
+			"svc-users":  {},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		patterns  []string
+		want      []string
+		wantAdded []string
+	}{
+		{name: "all services", patterns: nil, want: []string{"api", "auth", "db", "svc-orders", "svc-users", "web"}},
+		{name: "transitive dependencies", patterns: []string{"web"}, want: []string{"api", "auth", "db", "web"}, wantAdded: []string{"api", "auth", "db"}},
+		{name: "glob with dependencies", patterns: []string{"svc-*"}, want: []string{"db", "svc-orders", "svc-users"}, wantAdded: []string{"db"}},
+		{name: "dependency also selected", patterns: []string{"svc-orders", "db"}, want: []string{"db", "svc-orders"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services, added, err := SelectServices(azureYaml, tt.patterns)
+			if err != nil {
+				t.Fatalf("SelectServices() error = %v", err)
+			}
+			var names []string
+			for name := range services {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("SelectServices() services = %v, want %v", names, tt.want)
+			}
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("SelectServices() added = %v, want %v", added, tt.wantAdded)
+			}
+		})
+	}
+
+	if _, _, err := SelectServices(azureYaml, []string{"worker"}); err == nil {
+		t.Error("SelectServices() expected error for an unknown service")
+	}
+}
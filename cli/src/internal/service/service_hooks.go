@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jongio/azd-app/cli/src/internal/executor"
+)
+
+// runPreStartCommands runs the service's build command and then its preRun hook in the service's
+// working directory, with the service's environment. A failure stops the service from starting,
+// unless the preRun hook sets continueOnError.
+func runPreStartCommands(ctx context.Context, rt *ServiceRuntime, env map[string]string) error {
+	hookEnv := envMapToSlice(env)
+
+	if rt.BuildCommand != "" {
+		config := executor.HookConfig{Run: rt.BuildCommand, Env: hookEnv}
+		if err := executor.ExecuteHook(ctx, fmt.Sprintf("build (%s)", rt.Name), config, rt.WorkingDir); err != nil {
+			return err
+		}
+	}
+
+	if config := resolveHookConfig(rt.PreRun); config != nil {
+		config.Env = hookEnv
+		if err := executor.ExecuteHook(ctx, fmt.Sprintf("preRun (%s)", rt.Name), *config, rt.WorkingDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveHookConfig converts a hook from azure.yaml into the executor's configuration for the
+// current platform. Returns nil if hook is nil.
+func resolveHookConfig(hook *Hook) *executor.HookConfig {
+	if hook == nil {
+		return nil
+	}
+	return executor.ResolveHookConfig(executor.NewHook(
+		hook.Run,
+		hook.Shell,
+		hook.ContinueOnError,
+		hook.Interactive,
+		resolvePlatformHook(hook.Windows),
+		resolvePlatformHook(hook.Posix),
+	))
+}
+
+// resolvePlatformHook converts a platform-specific hook override.
+func resolvePlatformHook(hook *PlatformHook) *executor.PlatformHook {
+	if hook == nil {
+		return nil
+	}
+	return executor.NewPlatformHook(hook.Run, hook.Shell, hook.ContinueOnError, hook.Interactive)
+}
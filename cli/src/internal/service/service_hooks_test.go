@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunPreStartCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands use POSIX shell syntax")
+	}
+
+	tests := []struct {
+		name      string
+		rt        ServiceRuntime
+		wantErr   bool
+		wantSteps string
+	}{
+		{
+			name:      "build runs before preRun",
+			rt:        ServiceRuntime{BuildCommand: "echo build >> steps.txt", PreRun: &Hook{Run: "echo preRun:$SERVICE_NAME >> steps.txt"}},
+			wantSteps: "build\npreRun:api\n",
+		},
+		{
+			name:      "failed build skips preRun",
+			rt:        ServiceRuntime{BuildCommand: "exit 1", PreRun: &Hook{Run: "echo preRun >> steps.txt"}},
+			wantErr:   true,
+			wantSteps: "",
+		},
+		{
+			name:      "preRun continueOnError",
+			rt:        ServiceRuntime{PreRun: &Hook{Run: "echo preRun >> steps.txt; exit 1", ContinueOnError: true}},
+			wantSteps: "preRun\n",
+		},
+		{
+			name: "nothing to run",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			rt := tt.rt
+			rt.Name = "api"
+			rt.WorkingDir = tmpDir
+
+			err := runPreStartCommands(context.Background(), &rt, map[string]string{"SERVICE_NAME": "api"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runPreStartCommands() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			data, _ := os.ReadFile(filepath.Join(tmpDir, "steps.txt"))
+			if got := strings.ReplaceAll(string(data), "\r\n", "\n"); got != tt.wantSteps {
+				t.Errorf("steps = %q, want %q", got, tt.wantSteps)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/jongio/azd-app/cli/src/internal/devcert"
+)
+
+// ProtocolHTTPS is the protocol of services that serve HTTPS with the development certificate.
+const ProtocolHTTPS = "https"
+
+// Environment variables with the paths of the development certificate and its private key.
+const (
+	EnvCertFile = "AZD_APP_CERT_FILE"
+	EnvKeyFile  = "AZD_APP_KEY_FILE"
+)
+
+// IsHTTPS reports whether the service serves HTTPS.
+func (rt *ServiceRuntime) IsHTTPS() bool {
+	return rt.Protocol == ProtocolHTTPS
+}
+
+// URLScheme returns the scheme of the service's local URL: "https" or "http".
+func (rt *ServiceRuntime) URLScheme() string {
+	if rt.IsHTTPS() {
+		return ProtocolHTTPS
+	}
+	return ServiceTypeHTTP
+}
+
+// ConfigureHTTPS gives an HTTPS service the development certificate, creating it if needed.
+// It does nothing for other services.
+func ConfigureHTTPS(rt *ServiceRuntime) error {
+	if !rt.IsHTTPS() {
+		return nil
+	}
+	cert, err := devcert.EnsureDefault()
+	if err != nil {
+		return fmt.Errorf("service '%s': failed to create development certificate: %w", rt.Name, err)
+	}
+	InjectCertEnvironment(rt, cert)
+	return nil
+}
+
+// InjectCertEnvironment points rt at the development certificate with AZD_APP_CERT_FILE and
+// AZD_APP_KEY_FILE, plus the variables its framework reads: SSL_CRT_FILE, SSL_KEY_FILE and HTTPS
+// for Node.js dev servers, and the Kestrel default certificate and HTTPS URL for ASP.NET Core.
+// Values already present in the runtime's environment are not overwritten.
+func InjectCertEnvironment(rt *ServiceRuntime, cert *devcert.Cert) {
+	if rt.Env == nil {
+		rt.Env = make(map[string]string)
+	}
+	env := map[string]string{
+		EnvCertFile: cert.CertFile,
+		EnvKeyFile:  cert.KeyFile,
+	}
+
+	switch rt.Language {
+	case langNameJavaScript, langTypeScript:
+		env["SSL_CRT_FILE"] = cert.CertFile
+		env["SSL_KEY_FILE"] = cert.KeyFile
+		env["HTTPS"] = "true"
+	case langNameDotNet:
+		env["ASPNETCORE_Kestrel__Certificates__Default__Path"] = cert.CertFile
+		env["ASPNETCORE_Kestrel__Certificates__Default__KeyPath"] = cert.KeyFile
+		if rt.Port > 0 {
+			env["ASPNETCORE_URLS"] = fmt.Sprintf("https://localhost:%d", rt.Port)
+		}
+	}
+
+	for key, value := range env {
+		if _, exists := rt.Env[key]; !exists {
+			rt.Env[key] = value
+		}
+	}
+}
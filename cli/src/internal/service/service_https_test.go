@@ -0,0 +1,143 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/devcert"
+)
+
+func TestInjectCertEnvironment(t *testing.T) {
+	cert := &devcert.Cert{CertFile: "/certs/localhost.pem", KeyFile: "/certs/localhost-key.pem", Source: devcert.SourceSelfSigned}
+
+	tests := []struct {
+		name    string
+		rt      *ServiceRuntime
+		want    map[string]string
+		notWant []string
+	}{
+		{
+			name: "node",
+			rt:   &ServiceRuntime{Language: langTypeScript, Port: 3000},
+			want: map[string]string{
+				EnvCertFile:    cert.CertFile,
+				EnvKeyFile:     cert.KeyFile,
+				"SSL_CRT_FILE": cert.CertFile,
+				"SSL_KEY_FILE": cert.KeyFile,
+				"HTTPS":        "true",
+			},
+			notWant: []string{"ASPNETCORE_URLS"},
+		},
+		{
+			name: "dotnet",
+			rt:   &ServiceRuntime{Language: langNameDotNet, Port: 5001},
+			want: map[string]string{
+				EnvCertFile: cert.CertFile,
+				"ASPNETCORE_Kestrel__Certificates__Default__Path":    cert.CertFile,
+				"ASPNETCORE_Kestrel__Certificates__Default__KeyPath": cert.KeyFile,
+				"ASPNETCORE_URLS": "https://localhost:5001",
+			},
+			notWant: []string{"HTTPS"},
+		},
+		{
+			name:    "existing values kept",
+			rt:      &ServiceRuntime{Language: langNamePython, Env: map[string]string{EnvCertFile: "/custom.pem"}},
+			want:    map[string]string{EnvCertFile: "/custom.pem", EnvKeyFile: cert.KeyFile},
+			notWant: []string{"SSL_CRT_FILE", "ASPNETCORE_URLS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			InjectCertEnvironment(tt.rt, cert)
+			for key, value := range tt.want {
+				if tt.rt.Env[key] != value {
+					t.Errorf("%s = %q, want %q", key, tt.rt.Env[key], value)
+				}
+			}
+			for _, key := range tt.notWant {
+				if _, exists := tt.rt.Env[key]; exists {
+					t.Errorf("%s should not be set", key)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceProtocol(t *testing.T) {
+	dir := t.TempDir()
+	writeAzureYaml(t, dir, `name: app
+services:
+  api:
+    project: ./api
+    language: python
+    command: python app.py
+    protocol: https
+    ports: ["8443"]
+  web:
+    project: ./web
+    language: python
+    command: python web.py
+    ports: ["3000"]
+`)
+
+	azureYaml, err := ParseAzureYaml(dir)
+	if err != nil {
+		t.Fatalf("ParseAzureYaml() error = %v", err)
+	}
+	var runtimes []*ServiceRuntime
+	for _, name := range []string{"api", "web"} {
+		rt, err := DetectServiceRuntime(name, azureYaml.Services[name], map[int]bool{}, dir, "")
+		if err != nil {
+			t.Fatalf("DetectServiceRuntime(%s) error = %v", name, err)
+		}
+		runtimes = append(runtimes, rt)
+	}
+	if !runtimes[0].IsHTTPS() || runtimes[1].IsHTTPS() {
+		t.Fatalf("IsHTTPS() = %v, %v, want only api", runtimes[0].IsHTTPS(), runtimes[1].IsHTTPS())
+	}
+
+	web := runtimes[1]
+	if err := InjectServiceURLs(web, RuntimeEndpoints(runtimes)); err != nil {
+		t.Fatal(err)
+	}
+	if got := web.Env["SERVICE_API_URL"]; got != "https://localhost:8443" {
+		t.Errorf("SERVICE_API_URL = %q, want the https URL", got)
+	}
+
+	invalid := Service{Protocol: "ftp"}
+	if err := ValidateServiceConfig("api", &invalid); err == nil || !strings.Contains(err.Error(), "must be 'http' or 'https'") {
+		t.Errorf("ValidateServiceConfig() error = %v, want invalid protocol", err)
+	}
+}
+
+func TestProbeServiceHealth_HTTPS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	port, err := strconv.Atoi(server.URL[strings.LastIndex(server.URL, ":")+1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	process := &ServiceProcess{
+		Port: port,
+		Runtime: ServiceRuntime{
+			Protocol:    ProtocolHTTPS,
+			HealthCheck: HealthCheckConfig{Type: ServiceTypeHTTP, Path: "/", ProbeTimeout: 5 * time.Second},
+		},
+	}
+	if err := probeServiceHealth(process); err != nil {
+		t.Errorf("probeServiceHealth() error = %v, want healthy over https", err)
+	}
+
+	process.Runtime.Protocol = ServiceTypeHTTP
+	if err := probeServiceHealth(process); err == nil {
+		t.Error("probeServiceHealth() over http to an https server should fail")
+	}
+}
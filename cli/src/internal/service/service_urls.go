@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jongio/azd-core/registry"
+)
+
+// EnvServicePortSuffix is the suffix appended to service port environment variables (for example, SERVICE_WEB_PORT).
+const EnvServicePortSuffix = "_PORT"
+
+// envServiceConnectionStringSuffix is the suffix of the connection string variables of databases
+// and other dependencies (for example, SERVICE_DB_CONNECTION_STRING).
+const envServiceConnectionStringSuffix = "_CONNECTION_STRING"
+
+// serviceReferencePattern matches ${services.<name>.<property>} references in environment values.
+var serviceReferencePattern = regexp.MustCompile(`\$\{services\.([^.}]+)\.([^}]+)\}`)
+
+// ServiceEndpoint is the local address of a service that other services can call.
+type ServiceEndpoint struct {
+	Name       string
+	Port       int
+	Scheme     string             // "http" (default) or "https"
+	Connection *ServiceConnection // How services that use it connect to it, e.g. a database (nil = only by URL)
+}
+
+// URL returns the local URL of the service.
+func (e ServiceEndpoint) URL() string {
+	scheme := e.Scheme
+	if scheme == "" {
+		scheme = ServiceTypeHTTP
+	}
+	return fmt.Sprintf("%s://localhost:%d", scheme, e.Port)
+}
+
+// RuntimeEndpoints returns the endpoints of the services being started that listen on a port.
+func RuntimeEndpoints(runtimes []*ServiceRuntime) []ServiceEndpoint {
+	endpoints := make([]ServiceEndpoint, 0, len(runtimes))
+	for _, rt := range runtimes {
+		if rt.Port > 0 {
+			endpoints = append(endpoints, ServiceEndpoint{Name: rt.Name, Port: rt.Port, Scheme: rt.URLScheme(), Connection: rt.Connection})
+		}
+	}
+	return endpoints
+}
+
+// RegistryEndpoints returns the endpoints of the services in the registry that listen on a port,
+// so a service restarted on its own sees the same sibling addresses as when all services started.
+// The services of azure.yaml provide the connections of databases and other dependencies.
+func RegistryEndpoints(reg *registry.ServiceRegistry, services map[string]Service) []ServiceEndpoint {
+	var endpoints []ServiceEndpoint
+	for _, entry := range reg.ListAll() {
+		if entry.Port > 0 {
+			endpoint := ServiceEndpoint{Name: entry.Name, Port: entry.Port}
+			if svc, ok := services[entry.Name]; ok {
+				endpoint.Connection = serviceConnection(svc, entry.Port)
+			}
+			if strings.HasPrefix(entry.URL, ProtocolHTTPS+"://") {
+				endpoint.Scheme = ProtocolHTTPS
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+// InjectServiceURLs exposes every other service to rt as SERVICE_<NAME>_URL and SERVICE_<NAME>_PORT,
+// and resolves ${services.<name>.url}, ${services.<name>.port} and ${services.<name>.connectionString}
+// references in its environment. A service that uses a service with a connection, such as a database
+// or Azurite, also gets its connection string as SERVICE_<NAME>_CONNECTION_STRING and its conventional
+// variables, e.g. DATABASE_URL or AZURE_STORAGE_CONNECTION_STRING.
+// Values already present in the runtime's environment, such as those set in azure.yaml, are not overwritten.
+// References to services that aren't running or have no port are an error.
+func InjectServiceURLs(rt *ServiceRuntime, endpoints []ServiceEndpoint) error {
+	if rt.Env == nil {
+		rt.Env = make(map[string]string)
+	}
+
+	byName := make(map[string]ServiceEndpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		byName[endpoint.Name] = endpoint
+	}
+
+	for key, value := range rt.Env {
+		resolved, err := expandServiceReferences(value, byName)
+		if err != nil {
+			return fmt.Errorf("service '%s': environment variable %s: %w", rt.Name, key, err)
+		}
+		rt.Env[key] = resolved
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.Name == rt.Name {
+			continue
+		}
+		prefix := EnvServiceURLPrefix + strings.ToUpper(strings.ReplaceAll(endpoint.Name, "-", "_"))
+		if _, exists := rt.Env[prefix+EnvServiceURLSuffix]; !exists {
+			rt.Env[prefix+EnvServiceURLSuffix] = endpoint.URL()
+		}
+		if _, exists := rt.Env[prefix+EnvServicePortSuffix]; !exists {
+			rt.Env[prefix+EnvServicePortSuffix] = strconv.Itoa(endpoint.Port)
+		}
+		if endpoint.Connection != nil && slices.Contains(rt.DependsOn, endpoint.Name) {
+			if _, exists := rt.Env[prefix+envServiceConnectionStringSuffix]; !exists {
+				rt.Env[prefix+envServiceConnectionStringSuffix] = endpoint.Connection.String
+			}
+			for key, value := range endpoint.Connection.Env {
+				if _, exists := rt.Env[key]; !exists {
+					rt.Env[key] = value
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// expandServiceReferences replaces ${services.<name>.url}, ${services.<name>.port} and
+// ${services.<name>.connectionString} in value.
+func expandServiceReferences(value string, endpoints map[string]ServiceEndpoint) (string, error) {
+	if !strings.Contains(value, "${services.") {
+		return value, nil
+	}
+
+	var refErr error
+	resolved := serviceReferencePattern.ReplaceAllStringFunc(value, func(ref string) string {
+		match := serviceReferencePattern.FindStringSubmatch(ref)
+		name, property := match[1], match[2]
+
+		endpoint, ok := endpoints[name]
+		if !ok {
+			if refErr == nil {
+				refErr = fmt.Errorf("%s references service '%s', which isn't running or has no port", ref, name)
+			}
+			return ref
+		}
+		switch property {
+		case "url":
+			return endpoint.URL()
+		case "port":
+			return strconv.Itoa(endpoint.Port)
+		case "connectionString":
+			if endpoint.Connection != nil {
+				return endpoint.Connection.String
+			}
+			if refErr == nil {
+				refErr = fmt.Errorf("%s: service '%s' has no connection string", ref, name)
+			}
+			return ref
+		default:
+			if refErr == nil {
+				refErr = fmt.Errorf("%s: unknown property '%s' (expected url, port or connectionString)", ref, property)
+			}
+			return ref
+		}
+	})
+	return resolved, refErr
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInjectServiceURLs(t *testing.T) {
+	runtimes := []*ServiceRuntime{
+		{Name: "web", Port: 3000, Env: map[string]string{
+			"API_BASE":    "${services.orders-api.url}/v1",
+			"API_ADDRESS": "localhost:${services.orders-api.port}",
+			"SELF":        "${services.web.port}",
+		}},
+		{Name: "orders-api", Port: 5100},
+		{Name: "worker", Port: 0, Env: map[string]string{"SERVICE_ORDERS_API_URL": "http://custom"}},
+	}
+
+	endpoints := RuntimeEndpoints(runtimes)
+	if len(endpoints) != 2 {
+		t.Fatalf("RuntimeEndpoints() = %+v, want the services with a port", endpoints)
+	}
+	for _, rt := range runtimes {
+		if err := InjectServiceURLs(rt, endpoints); err != nil {
+			t.Fatalf("InjectServiceURLs(%s) error = %v", rt.Name, err)
+		}
+	}
+
+	web, api, worker := runtimes[0].Env, runtimes[1].Env, runtimes[2].Env
+	want := map[string]string{
+		"SERVICE_ORDERS_API_URL":  "http://localhost:5100",
+		"SERVICE_ORDERS_API_PORT": "5100",
+		"API_BASE":                "http://localhost:5100/v1",
+		"API_ADDRESS":             "localhost:5100",
+		"SELF":                    "3000",
+	}
+	for key, value := range want {
+		if web[key] != value {
+			t.Errorf("web %s = %q, want %q", key, web[key], value)
+		}
+	}
+	if api["SERVICE_WEB_URL"] != "http://localhost:3000" || api["SERVICE_WEB_PORT"] != "3000" {
+		t.Errorf("orders-api env = %v, want the web URL and port", api)
+	}
+	if _, exists := api["SERVICE_ORDERS_API_URL"]; exists {
+		t.Error("a service should not receive its own URL")
+	}
+	if worker["SERVICE_ORDERS_API_URL"] != "http://custom" {
+		t.Errorf("worker SERVICE_ORDERS_API_URL = %q, want existing value kept", worker["SERVICE_ORDERS_API_URL"])
+	}
+	if _, exists := web["SERVICE_WORKER_URL"]; exists {
+		t.Error("services without a port should not be injected")
+	}
+}
+
+func TestInjectServiceURLs_Azurite(t *testing.T) {
+	endpoints := []ServiceEndpoint{{Name: "storage", Port: 10010, Connection: azuriteConnection(10010)}, {Name: "api", Port: 8000}}
+	connectionString := AzuriteConnectionString(10010)
+
+	api := &ServiceRuntime{Name: "api", DependsOn: []string{"storage"}, Env: map[string]string{"AzureWebJobsStorage": "custom"}}
+	web := &ServiceRuntime{Name: "web", DependsOn: []string{"api"}, Env: map[string]string{"STORAGE": "${services.storage.connectionString}"}}
+	for _, rt := range []*ServiceRuntime{api, web} {
+		if err := InjectServiceURLs(rt, endpoints); err != nil {
+			t.Fatalf("InjectServiceURLs(%s) error = %v", rt.Name, err)
+		}
+	}
+
+	if api.Env[EnvAzureStorageConnectionString] != connectionString || api.Env["SERVICE_STORAGE_CONNECTION_STRING"] != connectionString {
+		t.Errorf("api env = %v, want the azurite connection string", api.Env)
+	}
+	if api.Env["AzureWebJobsStorage"] != "custom" {
+		t.Errorf("api AzureWebJobsStorage = %q, want existing value kept", api.Env["AzureWebJobsStorage"])
+	}
+	if _, exists := web.Env[EnvAzureStorageConnectionString]; exists {
+		t.Error("a service that doesn't use azurite should not receive its connection string")
+	}
+	if web.Env["STORAGE"] != connectionString {
+		t.Errorf("web STORAGE = %q, want the resolved connection string", web.Env["STORAGE"])
+	}
+}
+
+func TestInjectServiceURLs_InvalidReferences(t *testing.T) {
+	endpoints := []ServiceEndpoint{{Name: "api", Port: 8000}}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{name: "unknown service", value: "${services.payments.url}", wantErr: "service 'payments'"},
+		{name: "unknown property", value: "${services.api.host}", wantErr: "unknown property 'host'"},
+		{name: "no connection string", value: "${services.api.connectionString}", wantErr: "has no connection string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &ServiceRuntime{Name: "web", Env: map[string]string{"TARGET": tt.value}}
+			err := InjectServiceURLs(rt, endpoints)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("InjectServiceURLs() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseAzureYaml_KeepsServiceReferences(t *testing.T) {
+	dir := t.TempDir()
+	writeAzureYaml(t, dir, `name: app
+services:
+  web:
+    project: ./web
+    environment:
+      API_URL: ${services.api.url}
+  api:
+    project: ./api
+`)
+
+	azureYaml, err := ParseAzureYaml(dir)
+	if err != nil {
+		t.Fatalf("ParseAzureYaml() error = %v", err)
+	}
+	if got := azureYaml.Services["web"].Environment["API_URL"]; got != "${services.api.url}" {
+		t.Errorf("API_URL = %q, want the reference resolved when services start", got)
+	}
+}
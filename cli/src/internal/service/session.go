@@ -0,0 +1,136 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+)
+
+const (
+	// sessionFileName is stored in the project's .azure directory.
+	sessionFileName = "session.json"
+
+	// sessionLogFileName receives the output of a detached session, in the project's .azure directory.
+	sessionLogFileName = "run.log"
+
+	// sessionVersion tracks the file schema version.
+	sessionVersion = 1
+)
+
+// Session is an azd app run session running in the background (--detach), recorded in
+// .azure/session.json so commands in other terminals can find, inspect and stop it.
+type Session struct {
+	Version      int              `json:"version"`
+	PID          int              `json:"pid"`
+	StartedAt    time.Time        `json:"startedAt"` // Start time reported by the OS, to detect PID reuse
+	DashboardURL string           `json:"dashboardUrl,omitempty"`
+	LogFile      string           `json:"logFile"`
+	Services     []SessionService `json:"services"`
+}
+
+// SessionService is a service started by a detached session.
+type SessionService struct {
+	Name string `json:"name"`
+	PID  int    `json:"pid,omitempty"`
+	Port int    `json:"port,omitempty"`
+}
+
+// SessionPath returns the path of the session file of a project.
+func SessionPath(projectDir string) string {
+	return filepath.Join(projectDir, ".azure", sessionFileName)
+}
+
+// SessionLogPath returns the path of the file that receives the output of a detached session.
+func SessionLogPath(projectDir string) string {
+	return filepath.Join(projectDir, ".azure", sessionLogFileName)
+}
+
+// DashboardPort returns the port of the session's dashboard, or 0 if it has none.
+func (s *Session) DashboardPort() int {
+	return dashboardURLPort(s.DashboardURL)
+}
+
+// dashboardURLPort returns the port of a dashboard URL, or 0 if there is none.
+func dashboardURLPort(dashboardURL string) int {
+	if dashboardURL == "" {
+		return 0
+	}
+	u, err := url.Parse(dashboardURL)
+	if err != nil {
+		return 0
+	}
+	port, _ := strconv.Atoi(u.Port())
+	return port
+}
+
+// WriteSession records this azd process as the detached session of a project, with its
+// dashboard URL and the services it started.
+func WriteSession(projectDir, dashboardURL string, processes map[string]*ServiceProcess) error {
+	session := Session{
+		Version:      sessionVersion,
+		PID:          os.Getpid(),
+		StartedAt:    ownerStart(),
+		DashboardURL: dashboardURL,
+		LogFile:      SessionLogPath(projectDir),
+		Services:     make([]SessionService, 0, len(processes)),
+	}
+	for name, proc := range processes {
+		session.Services = append(session.Services, SessionService{Name: name, PID: proc.PID, Port: proc.Port})
+	}
+	sort.Slice(session.Services, func(i, j int) bool { return session.Services[i].Name < session.Services[j].Name })
+
+	path := SessionPath(projectDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create .azure directory: %w", err)
+	}
+	if err := fileutil.AtomicWriteJSON(path, session); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// ReadSession returns the detached session of a project, or nil if none is running.
+// The record of a session whose process has exited, e.g. after a crash, is removed.
+func ReadSession(projectDir string) (*Session, error) {
+	path := SessionPath(projectDir)
+	var session Session
+	if err := fileutil.ReadJSON(path, &session); err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	if session.PID == 0 {
+		return nil, nil
+	}
+	if session.Version != sessionVersion || !isRunningSince(session.PID, session.StartedAt) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			slog.Debug("failed to remove stale session", slog.String("error", err.Error()))
+		}
+		return nil, nil
+	}
+	return &session, nil
+}
+
+// RemoveSession removes the session record of a project if this azd process wrote it.
+func RemoveSession(projectDir string) {
+	path := SessionPath(projectDir)
+	var session Session
+	if err := fileutil.ReadJSON(path, &session); err != nil || session.PID != os.Getpid() {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		slog.Debug("failed to remove session", slog.String("error", err.Error()))
+	}
+}
+
+// StopSession stops the process of a detached session, for when it can't be asked to shut
+// down through its dashboard. On Unix it gets a graceful stop signal first, so it can stop its
+// services, and is killed if it is still running after timeout.
+func StopSession(session *Session, timeout time.Duration) error {
+	return KillSession(session.PID, session.StartedAt, timeout)
+}
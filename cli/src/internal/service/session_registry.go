@@ -0,0 +1,148 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/azdconfig"
+	"github.com/jongio/azd-core/fileutil"
+)
+
+// SessionRegistryDir returns the directory of the registry of active azd app run sessions
+// of all projects, ~/.azd/app/sessions. Each session is a file named after its project.
+// This is a variable to allow test overrides.
+var SessionRegistryDir = func() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".azd", "app", "sessions"), nil
+}
+
+// ActiveSession is an azd app run session recorded in the session registry.
+type ActiveSession struct {
+	Project      string    `json:"project"` // Project directory
+	PID          int       `json:"pid"`
+	StartedAt    time.Time `json:"startedAt"` // Start time reported by the OS, to detect PID reuse
+	DashboardURL string    `json:"dashboardUrl,omitempty"`
+	Detached     bool      `json:"detached"`
+	Services     []string  `json:"services"`
+}
+
+// sessionRegistryPath returns the registry file of a project's session.
+func sessionRegistryPath(projectDir string) (string, error) {
+	dir, err := SessionRegistryDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, azdconfig.ProjectHash(projectDir)+".json"), nil
+}
+
+// RegisterSession records this azd process as the active session of a project in the session
+// registry, so it can be listed and stopped from any directory.
+func RegisterSession(projectDir, dashboardURL string, serviceNames []string, detached bool) error {
+	path, err := sessionRegistryPath(projectDir)
+	if err != nil {
+		return err
+	}
+	absDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+
+	services := append([]string{}, serviceNames...)
+	sort.Strings(services)
+	session := ActiveSession{
+		Project:      absDir,
+		PID:          os.Getpid(),
+		StartedAt:    ownerStart(),
+		DashboardURL: dashboardURL,
+		Detached:     detached,
+		Services:     services,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create session registry directory: %w", err)
+	}
+	if err := fileutil.AtomicWriteJSON(path, session); err != nil {
+		return fmt.Errorf("failed to register session: %w", err)
+	}
+	return nil
+}
+
+// UnregisterSession removes the registry entry of a project's session if this azd process wrote it.
+func UnregisterSession(projectDir string) {
+	path, err := sessionRegistryPath(projectDir)
+	if err != nil {
+		return
+	}
+	var session ActiveSession
+	if err := fileutil.ReadJSON(path, &session); err != nil || session.PID != os.Getpid() {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		slog.Debug("failed to unregister session", slog.String("error", err.Error()))
+	}
+}
+
+// ListSessions returns the active sessions of all projects sorted by project directory.
+// Entries of sessions whose process has exited, e.g. after a crash, are removed.
+func ListSessions() ([]ActiveSession, error) {
+	dir, err := SessionRegistryDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read session registry: %w", err)
+	}
+
+	var sessions []ActiveSession
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		var session ActiveSession
+		if err := fileutil.ReadJSON(path, &session); err != nil || !isRunningSince(session.PID, session.StartedAt) {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				slog.Debug("failed to remove stale session entry", slog.String("path", path), slog.String("error", err.Error()))
+			}
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Project < sessions[j].Project })
+	return sessions, nil
+}
+
+// DashboardPort returns the port of the session's dashboard, or 0 if it has none.
+func (s ActiveSession) DashboardPort() int {
+	return dashboardURLPort(s.DashboardURL)
+}
+
+// Running reports whether the session's azd process is still running.
+func (s ActiveSession) Running() bool {
+	return isRunningSince(s.PID, s.StartedAt)
+}
+
+// KillSession stops the azd process of a session with a signal, for when it can't be asked to
+// shut down through its dashboard. On Unix it gets a graceful stop signal first, so it can stop
+// its services, and is killed if it is still running after timeout.
+func KillSession(pid int, startedAt time.Time, timeout time.Duration) error {
+	if !isRunningSince(pid, startedAt) {
+		return nil
+	}
+	if err := killOrphanedProcess(pid, timeout); err != nil {
+		return fmt.Errorf("failed to stop session (PID %d): %w", pid, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+)
+
+// useTempSessionRegistry points the session registry at a temporary directory.
+func useTempSessionRegistry(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	original := SessionRegistryDir
+	SessionRegistryDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { SessionRegistryDir = original })
+	return dir
+}
+
+func TestRegisterSession(t *testing.T) {
+	useTempSessionRegistry(t)
+	projectDir := t.TempDir()
+
+	if err := RegisterSession(projectDir, "http://localhost:41234", []string{"web", "api"}, true); err != nil {
+		t.Fatalf("RegisterSession() error = %v", err)
+	}
+
+	sessions, err := ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("ListSessions() = %+v, want 1 session", sessions)
+	}
+	s := sessions[0]
+	if s.Project != projectDir || s.PID != os.Getpid() || !s.Detached || s.DashboardPort() != 41234 {
+		t.Errorf("unexpected session %+v", s)
+	}
+	if len(s.Services) != 2 || s.Services[0] != "api" {
+		t.Errorf("services = %v, want sorted [api web]", s.Services)
+	}
+	if !s.Running() {
+		t.Error("Running() = false for this process")
+	}
+
+	UnregisterSession(projectDir)
+	if sessions, _ := ListSessions(); len(sessions) != 0 {
+		t.Errorf("ListSessions() after UnregisterSession = %+v, want none", sessions)
+	}
+}
+
+func TestListSessions_RemovesStaleEntries(t *testing.T) {
+	dir := useTempSessionRegistry(t)
+
+	// This process, but with another start time: the session exited and its PID was reused
+	stale := ActiveSession{Project: "/src/old", PID: os.Getpid(), StartedAt: time.Now().Add(-24 * time.Hour)}
+	stalePath := filepath.Join(dir, "stale.json")
+	if err := fileutil.AtomicWriteJSON(stalePath, stale); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("ListSessions() = %+v, want none", sessions)
+	}
+	if _, err := os.Stat(stalePath); !os.IsNotExist(err) {
+		t.Errorf("stale entry was not removed, stat error = %v", err)
+	}
+}
+
+func TestListSessions_NoRegistry(t *testing.T) {
+	original := SessionRegistryDir
+	SessionRegistryDir = func() (string, error) { return filepath.Join(t.TempDir(), "missing"), nil }
+	defer func() { SessionRegistryDir = original }()
+
+	sessions, err := ListSessions()
+	if err != nil || sessions != nil {
+		t.Errorf("ListSessions() = %+v, %v, want nil, nil", sessions, err)
+	}
+}
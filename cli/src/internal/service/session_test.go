@@ -0,0 +1,86 @@
+package service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-core/fileutil"
+)
+
+func TestWriteReadSession(t *testing.T) {
+	projectDir := t.TempDir()
+	processes := map[string]*ServiceProcess{
+		"web": {PID: 200, Port: 3000},
+		"api": {PID: 100, Port: 8080},
+	}
+
+	if err := WriteSession(projectDir, "http://localhost:41234", processes); err != nil {
+		t.Fatalf("WriteSession() error = %v", err)
+	}
+
+	session, err := ReadSession(projectDir)
+	if err != nil {
+		t.Fatalf("ReadSession() error = %v", err)
+	}
+	if session == nil {
+		t.Fatal("ReadSession() = nil, want the session of this process")
+	}
+	if session.PID != os.Getpid() || session.StartedAt.IsZero() || session.LogFile != SessionLogPath(projectDir) {
+		t.Errorf("unexpected session %+v", session)
+	}
+	if got := session.DashboardPort(); got != 41234 {
+		t.Errorf("DashboardPort() = %d, want 41234", got)
+	}
+	if len(session.Services) != 2 || session.Services[0].Name != "api" || session.Services[0].PID != 100 || session.Services[1].Port != 3000 {
+		t.Errorf("services = %+v, want api and web sorted by name", session.Services)
+	}
+
+	RemoveSession(projectDir)
+	if _, err := os.Stat(SessionPath(projectDir)); !os.IsNotExist(err) {
+		t.Errorf("session file still exists after RemoveSession, stat error = %v", err)
+	}
+}
+
+func TestReadSession_NoSession(t *testing.T) {
+	session, err := ReadSession(t.TempDir())
+	if err != nil || session != nil {
+		t.Errorf("ReadSession() = %+v, %v, want nil, nil", session, err)
+	}
+}
+
+func TestReadSession_RemovesStaleSession(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(projectDir+"/.azure", 0750); err != nil {
+		t.Fatal(err)
+	}
+	// This process, but with another start time: the recorded session exited and its PID was reused
+	stale := Session{Version: sessionVersion, PID: os.Getpid(), StartedAt: time.Now().Add(-24 * time.Hour)}
+	if err := fileutil.AtomicWriteJSON(SessionPath(projectDir), stale); err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := ReadSession(projectDir)
+	if err != nil || session != nil {
+		t.Errorf("ReadSession() = %+v, %v, want nil, nil", session, err)
+	}
+	if _, err := os.Stat(SessionPath(projectDir)); !os.IsNotExist(err) {
+		t.Errorf("stale session file was not removed, stat error = %v", err)
+	}
+}
+
+func TestRemoveSession_KeepsOtherSession(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(projectDir+"/.azure", 0750); err != nil {
+		t.Fatal(err)
+	}
+	other := Session{Version: sessionVersion, PID: os.Getpid() + 1}
+	if err := fileutil.AtomicWriteJSON(SessionPath(projectDir), other); err != nil {
+		t.Fatal(err)
+	}
+
+	RemoveSession(projectDir)
+	if _, err := os.Stat(SessionPath(projectDir)); err != nil {
+		t.Errorf("session of another process was removed: %v", err)
+	}
+}
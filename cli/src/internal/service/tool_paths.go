@@ -0,0 +1,61 @@
+package service
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// toolPaths returns the executables of the tools pinned with path in the top-level and service
+// reqs, by lowercase tool name and executable name, so a requirement named "nodejs" with the
+// path ./tools/node also pins node. Service reqs take precedence. Relative paths are resolved
+// against azureYamlDir. Returns nil when no tool is pinned.
+func toolPaths(azureYamlDir string, reqs ...[]ToolRequirement) map[string]string {
+	var paths map[string]string
+	for _, list := range reqs {
+		for _, req := range list {
+			if req.Path == "" {
+				continue
+			}
+			path := req.Path
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(azureYamlDir, path)
+			}
+			path = filepath.Clean(path)
+
+			if paths == nil {
+				paths = make(map[string]string)
+			}
+			paths[strings.ToLower(req.Name)] = path
+			executable := filepath.Base(path)
+			paths[strings.ToLower(strings.TrimSuffix(executable, filepath.Ext(executable)))] = path
+		}
+	}
+	return paths
+}
+
+// applyToolPaths runs a service with the executables its reqs pin: a run command naming a
+// pinned tool runs its executable, and the executables' directories come first on PATH, so
+// the tools the command starts (e.g., node under npm) resolve to them too.
+func applyToolPaths(runtime *ServiceRuntime, paths map[string]string) {
+	if len(paths) == 0 {
+		return
+	}
+	if path, ok := paths[strings.ToLower(runtime.Command)]; ok {
+		runtime.Command = path
+	}
+
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, path := range paths {
+		if dir := filepath.Dir(path); !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	if runtime.Env == nil {
+		runtime.Env = make(map[string]string)
+	}
+	prependPath(runtime, dirs...)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToolPaths(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "repo")
+	paths := toolPaths(root,
+		[]ToolRequirement{{Name: "nodejs", Path: "./tools/node"}, {Name: "go"}, {Name: "func", Path: "./tools/func"}},
+		[]ToolRequirement{{Name: "func", Path: "./api/bin/func.exe"}},
+	)
+
+	want := map[string]string{
+		"nodejs": filepath.Join(root, "tools", "node"),
+		"node":   filepath.Join(root, "tools", "node"),
+		"func":   filepath.Join(root, "api", "bin", "func.exe"), // Service reqs take precedence
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("toolPaths() = %v, want %v", paths, want)
+	}
+	for name, path := range want {
+		if paths[name] != path {
+			t.Errorf("toolPaths()[%q] = %q, want %q", name, paths[name], path)
+		}
+	}
+
+	if paths := toolPaths(root, []ToolRequirement{{Name: "node"}}); paths != nil {
+		t.Errorf("toolPaths() without paths = %v, want nil", paths)
+	}
+}
+
+func TestApplyToolPaths(t *testing.T) {
+	node := filepath.Join(t.TempDir(), "node")
+	runtime := &ServiceRuntime{Command: "node", Args: []string{"server.js"}}
+	applyToolPaths(runtime, map[string]string{"node": node})
+
+	if runtime.Command != node {
+		t.Errorf("Command = %q, want %q", runtime.Command, node)
+	}
+	path := ""
+	for key, value := range runtime.Env {
+		if strings.EqualFold(key, "PATH") {
+			path = value
+		}
+	}
+	if !strings.HasPrefix(path, filepath.Dir(node)+string(os.PathListSeparator)) {
+		t.Errorf("PATH = %q, want it to start with %s", path, filepath.Dir(node))
+	}
+
+	// Commands that don't name a pinned tool still find the pinned tools on PATH
+	runtime = &ServiceRuntime{Command: "npm", Args: []string{"start"}}
+	applyToolPaths(runtime, map[string]string{"node": node})
+	if runtime.Command != "npm" {
+		t.Errorf("Command = %q, want npm", runtime.Command)
+	}
+}
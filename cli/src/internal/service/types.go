@@ -2,12 +2,17 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/jongio/azd-app/cli/src/internal/detector"
 	"gopkg.in/yaml.v3"
 )
 
@@ -29,6 +34,14 @@ const (
 	// Health checks use TCP port connectivity by default.
 	// Container services are started via Docker rather than native processes.
 	ServiceTypeContainer = "container"
+
+	// ServiceTypeAzurite indicates a managed Azure Storage emulator, started with npx or Docker.
+	// Services that use it get its connection string. Health checks use its Blob endpoint port.
+	ServiceTypeAzurite = "azurite"
+
+	// ServiceTypeCompose indicates a Docker Compose project, started with docker compose up.
+	// Health checks use the first port published by its containers, if any.
+	ServiceTypeCompose = "compose"
 )
 
 // Service mode constants define the lifecycle behavior of process-type services.
@@ -60,18 +73,44 @@ const (
 
 // AzureYaml represents the parsed azure.yaml file.
 type AzureYaml struct {
-	Name      string              `yaml:"name"`
-	Services  map[string]Service  `yaml:"services"`
-	Resources map[string]Resource `yaml:"resources"`
-	Metadata  map[string]any      `yaml:"metadata,omitempty"`
-	Hooks     *Hooks              `yaml:"hooks,omitempty"`
-	Dashboard *DashboardConfig    `yaml:"dashboard,omitempty"`
-	Logs      *LogsConfig         `yaml:"logs,omitempty"` // Project-level logging configuration
+	Name       string               `yaml:"name"`
+	Services   map[string]Service   `yaml:"services"`
+	Resources  map[string]Resource  `yaml:"resources"`
+	Containers map[string]Container `yaml:"containers,omitempty"` // Local container dependencies, run as container services
+	Metadata   map[string]any       `yaml:"metadata,omitempty"`
+	Hooks      *Hooks               `yaml:"hooks,omitempty"`
+	Dashboard  *DashboardConfig     `yaml:"dashboard,omitempty"`
+	Logs       *LogsConfig          `yaml:"logs,omitempty"`     // Project-level logging configuration
+	Profiles   map[string]Profile   `yaml:"profiles,omitempty"` // Named overrides selected with --profile
+	Proxy      *ProxyConfig         `yaml:"proxy,omitempty"`    // Reverse proxy started with --proxy
+	Reqs       []ToolRequirement    `yaml:"reqs,omitempty"`     // Requirements; run only uses their tool paths
+}
+
+// ToolRequirement is the part of a reqs entry that run uses: the executable a tool is pinned to.
+type ToolRequirement struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path,omitempty"` // Executable to use instead of the one on PATH, relative to azure.yaml
 }
 
 // DashboardConfig represents dashboard configuration in azure.yaml.
 type DashboardConfig struct {
-	Browser string `yaml:"browser,omitempty"` // Browser target: default, system, none
+	Browser    string `yaml:"browser,omitempty"`    // Browser target: default, system, none
+	HTTPS      bool   `yaml:"https,omitempty"`      // Serve the dashboard over HTTPS with the development certificate
+	Prometheus bool   `yaml:"prometheus,omitempty"` // Serve Prometheus metrics at /metrics
+}
+
+// ProxyConfig represents the reverse proxy configuration in azure.yaml.
+// The proxy serves all services from one port, routing requests by path prefix.
+type ProxyConfig struct {
+	Port   int          `yaml:"port,omitempty"` // Port the proxy listens on (default 9000)
+	Routes []ProxyRoute `yaml:"routes,omitempty"`
+}
+
+// ProxyRoute sends requests under a path prefix to a service.
+type ProxyRoute struct {
+	Path        string `yaml:"path"`                  // Path prefix, e.g. "/api" or "/"
+	Service     string `yaml:"service"`               // Service that handles the requests
+	StripPrefix bool   `yaml:"stripPrefix,omitempty"` // Remove the prefix before forwarding
 }
 
 // Service represents a service definition in azure.yaml.
@@ -79,21 +118,40 @@ type Service struct {
 	Host               string              `yaml:"host"`
 	Language           string              `yaml:"language,omitempty"`
 	Project            string              `yaml:"project,omitempty"`
+	Run                string              `yaml:"run,omitempty"`        // Explicit command that starts the service (e.g., "npm run dev:api"). Authoritative: overrides command, entrypoint and auto-detection.
 	Command            string              `yaml:"command,omitempty"`    // Full command to run (e.g., "uvicorn main:app --reload"). Primary way to override.
 	Entrypoint         string              `yaml:"entrypoint,omitempty"` // Advanced: executable only, use with command for args. Rarely needed.
+	Build              string              `yaml:"build,omitempty"`      // Shell command run in the project directory before the service starts (e.g., "npm run build")
+	PreRun             *Hook               `yaml:"preRun,omitempty"`     // Hook run before the service starts, after build
+	PostStop           *Hook               `yaml:"postStop,omitempty"`   // Hook run after the service stops or exits
 	Image              string              `yaml:"image,omitempty"`
 	Docker             *DockerConfig       `yaml:"docker,omitempty"`
+	Compose            string              `yaml:"compose,omitempty"`     // Compose file started with docker compose, relative to azure.yaml (e.g., "./docker-compose.yml")
 	Ports              []string            `yaml:"ports,omitempty"`       // Docker Compose style: ["8080"] or ["3000:8080"]
+	PortRange          string              `yaml:"portRange,omitempty"`   // Ports automatically assigned to the service are taken from this range (e.g., "4000-4099")
+	Volumes            []string            `yaml:"volumes,omitempty"`     // Docker Compose style, for container services: ["data:/var/lib/data"] or ["./init:/docker-entrypoint-initdb.d"]
+	Protocol           string              `yaml:"protocol,omitempty"`    // Local protocol: "http" (default) or "https" (served with the development certificate)
 	Environment        Environment         `yaml:"environment,omitempty"` // Docker Compose style: supports map, array of strings, or array of objects
+	Env                Environment         `yaml:"env,omitempty"`         // Local overrides merged over environment
+	EnvFile            string              `yaml:"envFile,omitempty"`     // .env file loaded for this service only (lowest priority)
 	Uses               []string            `yaml:"uses,omitempty"`
-	Logs               *ServiceLogsConfig  `yaml:"logs,omitempty"`        // Service-level logging configuration
-	Healthcheck        *HealthcheckConfig  `yaml:"healthcheck,omitempty"` // Docker Compose-compatible health check configuration
-	HealthcheckEnabled *bool               `yaml:"-"`                     // Internal flag: nil = use default, false = explicitly disabled, true = explicitly enabled
-	Type               string              `yaml:"type,omitempty"`        // Service type: "http", "tcp", "process". Default: "http" if ports defined, "process" otherwise.
-	Mode               string              `yaml:"mode,omitempty"`        // Run mode (for type=process): "watch", "build", "daemon", "task". Default: "daemon".
-	Local              *LocalServiceConfig `yaml:"local,omitempty"`       // Local development configuration
-	Azure              *AzureServiceConfig `yaml:"azure,omitempty"`       // Azure deployment configuration
-	URL                string              `yaml:"url,omitempty"`         // DEPRECATED: Use azure.customUrl instead. Custom URL for accessing the service.
+	DependsOn          []string            `yaml:"dependsOn,omitempty"`       // Services that must be healthy before this one starts locally (not used by azd deploy)
+	Logs               *ServiceLogsConfig  `yaml:"logs,omitempty"`            // Service-level logging configuration
+	Healthcheck        *HealthcheckConfig  `yaml:"healthcheck,omitempty"`     // Docker Compose-compatible health check configuration
+	HealthcheckEnabled *bool               `yaml:"-"`                         // Internal flag: nil = use default, false = explicitly disabled, true = explicitly enabled
+	Type               string              `yaml:"type,omitempty"`            // Service type: "http", "tcp", "process". Default: "http" if ports defined, "process" otherwise.
+	Mode               string              `yaml:"mode,omitempty"`            // Run mode (for type=process): "watch", "build", "daemon", "task". Default: "daemon".
+	Restart            *RestartPolicy      `yaml:"restart,omitempty"`         // Relaunch policy when the process exits: "no", "on-failure", "always"
+	StopGracePeriod    string              `yaml:"stopGracePeriod,omitempty"` // Time allowed to stop gracefully before being force-killed (e.g., "10s")
+	Local              *LocalServiceConfig `yaml:"local,omitempty"`           // Local development configuration
+	Python             *PythonConfig       `yaml:"python,omitempty"`          // Python interpreter of Python services
+	Node               *NodeConfig         `yaml:"node,omitempty"`            // Node.js version of Node.js services
+	Azure              *AzureServiceConfig `yaml:"azure,omitempty"`           // Azure deployment configuration
+	URL                string              `yaml:"url,omitempty"`             // DEPRECATED: Use azure.customUrl instead. Custom URL for accessing the service.
+	Ref                string              `yaml:"ref,omitempty"`             // Reference to a service in another azd project: "<path>#<service>"
+	RefRoot            string              `yaml:"-"`                         // Internal: directory of the referenced project's azure.yaml (set when Ref is resolved)
+	Reqs               []ToolRequirement   `yaml:"reqs,omitempty"`            // Requirements of this service; run only uses their tool paths
+	ToolPaths          map[string]string   `yaml:"-"`                         // Internal: executables of the tools pinned with path in reqs, by tool name
 }
 
 // LocalServiceConfig represents local development configuration for a service.
@@ -101,6 +159,20 @@ type LocalServiceConfig struct {
 	CustomURL string `yaml:"customUrl,omitempty" json:"customUrl,omitempty"` // User-configured custom local URL (e.g., https://myapp.ngrok.io)
 }
 
+// PythonConfig represents the Python settings of a service.
+type PythonConfig struct {
+	// Interpreter runs the service and creates its virtual environment: a command on PATH
+	// (e.g., "python3.12") or a path to a Python executable, relative to the project directory.
+	Interpreter string `yaml:"interpreter,omitempty" json:"interpreter,omitempty"`
+}
+
+// NodeConfig represents the Node.js settings of a service.
+type NodeConfig struct {
+	// UseVersionManager runs the service with the Node.js version its project pins in .nvmrc,
+	// .node-version or package.json (volta), through volta, fnm or nvm.
+	UseVersionManager bool `yaml:"useVersionManager,omitempty" json:"useVersionManager,omitempty"`
+}
+
 // AzureServiceConfig represents Azure deployment configuration for a service.
 type AzureServiceConfig struct {
 	CustomURL          string `yaml:"customUrl,omitempty" json:"customUrl,omitempty"`       // User-configured custom Azure URL (e.g., https://api.mycompany.com)
@@ -111,23 +183,40 @@ type AzureServiceConfig struct {
 // serviceRaw is used to handle both boolean and object healthcheck values.
 // It duplicates all fields from Service except Healthcheck to avoid infinite recursion.
 type serviceRaw struct {
-	Host        string              `yaml:"host"`
-	Language    string              `yaml:"language,omitempty"`
-	Project     string              `yaml:"project,omitempty"`
-	Entrypoint  string              `yaml:"entrypoint,omitempty"`
-	Command     string              `yaml:"command,omitempty"`
-	Image       string              `yaml:"image,omitempty"`
-	Docker      *DockerConfig       `yaml:"docker,omitempty"`
-	Ports       []string            `yaml:"ports,omitempty"`
-	Environment Environment         `yaml:"environment,omitempty"`
-	Uses        []string            `yaml:"uses,omitempty"`
-	Logs        *ServiceLogsConfig  `yaml:"logs,omitempty"`
-	Healthcheck any                 `yaml:"healthcheck,omitempty"`
-	Type        string              `yaml:"type,omitempty"`
-	Mode        string              `yaml:"mode,omitempty"`
-	Local       *LocalServiceConfig `yaml:"local,omitempty"`
-	Azure       *AzureServiceConfig `yaml:"azure,omitempty"`
-	URL         string              `yaml:"url,omitempty"`
+	Host            string              `yaml:"host"`
+	Language        string              `yaml:"language,omitempty"`
+	Project         string              `yaml:"project,omitempty"`
+	Entrypoint      string              `yaml:"entrypoint,omitempty"`
+	Command         string              `yaml:"command,omitempty"`
+	Run             string              `yaml:"run,omitempty"`
+	Build           string              `yaml:"build,omitempty"`
+	PreRun          any                 `yaml:"preRun,omitempty"`   // string shorthand for run, or a hook object
+	PostStop        any                 `yaml:"postStop,omitempty"` // string shorthand for run, or a hook object
+	Image           string              `yaml:"image,omitempty"`
+	Docker          *DockerConfig       `yaml:"docker,omitempty"`
+	Compose         string              `yaml:"compose,omitempty"`
+	Ports           []string            `yaml:"ports,omitempty"`
+	PortRange       string              `yaml:"portRange,omitempty"`
+	Volumes         []string            `yaml:"volumes,omitempty"`
+	Protocol        string              `yaml:"protocol,omitempty"`
+	Environment     Environment         `yaml:"environment,omitempty"`
+	Env             Environment         `yaml:"env,omitempty"`
+	EnvFile         string              `yaml:"envFile,omitempty"`
+	Uses            []string            `yaml:"uses,omitempty"`
+	DependsOn       []string            `yaml:"dependsOn,omitempty"`
+	Logs            *ServiceLogsConfig  `yaml:"logs,omitempty"`
+	Healthcheck     any                 `yaml:"healthcheck,omitempty"`
+	HealthCheck     any                 `yaml:"healthCheck,omitempty"` // camelCase alias of healthcheck
+	Type            string              `yaml:"type,omitempty"`
+	Mode            string              `yaml:"mode,omitempty"`
+	Restart         *RestartPolicy      `yaml:"restart,omitempty"`
+	StopGracePeriod string              `yaml:"stopGracePeriod,omitempty"`
+	Local           *LocalServiceConfig `yaml:"local,omitempty"`
+	Python          *PythonConfig       `yaml:"python,omitempty"`
+	Node            *NodeConfig         `yaml:"node,omitempty"`
+	Azure           *AzureServiceConfig `yaml:"azure,omitempty"`
+	URL             string              `yaml:"url,omitempty"`
+	Ref             string              `yaml:"ref,omitempty"`
 }
 
 // UnmarshalYAML implements custom YAML unmarshaling to handle healthcheck: false.
@@ -143,17 +232,31 @@ func (s *Service) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	s.Project = raw.Project
 	s.Entrypoint = raw.Entrypoint
 	s.Command = raw.Command
+	s.Run = raw.Run
+	s.Build = raw.Build
 	s.Image = raw.Image
 	s.Docker = raw.Docker
+	s.Compose = raw.Compose
 	s.Ports = raw.Ports
+	s.PortRange = raw.PortRange
+	s.Volumes = raw.Volumes
+	s.Protocol = raw.Protocol
 	s.Environment = raw.Environment
+	s.Env = raw.Env
+	s.EnvFile = raw.EnvFile
 	s.Uses = raw.Uses
+	s.DependsOn = raw.DependsOn
 	s.Logs = raw.Logs
 	s.Type = raw.Type
 	s.Mode = raw.Mode
+	s.Restart = raw.Restart
+	s.StopGracePeriod = raw.StopGracePeriod
 	s.Local = raw.Local
+	s.Python = raw.Python
+	s.Node = raw.Node
 	s.Azure = raw.Azure
 	s.URL = raw.URL
+	s.Ref = raw.Ref
 
 	// Handle backward compatibility: root-level URL migrates to azure.customUrl
 	if s.URL != "" {
@@ -171,8 +274,28 @@ func (s *Service) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		s.Azure.CustomDomainSource = "user"
 	}
 
-	// Handle healthcheck field
-	switch v := raw.Healthcheck.(type) {
+	if _, err := s.GetStopGracePeriod(); err != nil {
+		return err
+	}
+	if _, _, err := s.GetPortRange(); err != nil {
+		return err
+	}
+
+	// Service hooks accept a command string as shorthand for { run: <command> }
+	var err error
+	if s.PreRun, err = parseServiceHook(raw.PreRun); err != nil {
+		return fmt.Errorf("invalid preRun hook: %w", err)
+	}
+	if s.PostStop, err = parseServiceHook(raw.PostStop); err != nil {
+		return fmt.Errorf("invalid postStop hook: %w", err)
+	}
+
+	// Handle healthcheck field (healthcheck takes precedence over its healthCheck alias)
+	healthcheck := raw.Healthcheck
+	if healthcheck == nil {
+		healthcheck = raw.HealthCheck
+	}
+	switch v := healthcheck.(type) {
 	case bool:
 		// healthcheck: false or healthcheck: true
 		s.HealthcheckEnabled = &v
@@ -201,6 +324,47 @@ func (s *Service) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// parseServiceHook converts a preRun/postStop value, either a command string or a hook object, into a Hook.
+func parseServiceHook(value any) (*Hook, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil, nil
+		}
+		return &Hook{Run: v}, nil
+	case map[string]any:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		var hook Hook
+		if err := yaml.Unmarshal(data, &hook); err != nil {
+			return nil, err
+		}
+		return &hook, nil
+	default:
+		return nil, fmt.Errorf("expected a command or a hook object, got %T", value)
+	}
+}
+
+// RunCommand returns the entrypoint and command that start the service.
+// run is authoritative: when it is set, command and entrypoint are ignored.
+func (s *Service) RunCommand() (entrypoint, command string) {
+	if s.Run != "" {
+		return "", s.Run
+	}
+	return s.Entrypoint, s.Command
+}
+
+// HasExplicitCommand reports whether azure.yaml sets the command that starts the service
+// (run, command or entrypoint), so it doesn't need to be auto-detected.
+func (s *Service) HasExplicitCommand() bool {
+	entrypoint, command := s.RunCommand()
+	return entrypoint != "" || command != ""
+}
+
 // IsHealthcheckDisabled returns true if health checks should be skipped for this service.
 // This can be triggered by:
 // - healthcheck: false (boolean)
@@ -228,14 +392,17 @@ func (s *Service) NeedsPort() bool {
 }
 
 // GetServiceType returns the service type, inferring from configuration if not explicitly set.
-// Returns: "container" (if image is defined), "http" (default if ports defined), "tcp", or "process" (default if no ports).
+// Returns: "compose" (if a compose file is set), "container" (if image is defined), "http" (default if ports defined), "tcp", or "process" (default if no ports).
 func (s *Service) GetServiceType() string {
 	// If explicitly set, use that
 	if s.Type != "" {
 		return s.Type
 	}
 
-	// Container services have priority - they have an image field
+	// Compose and container services have priority - they have a compose file or an image field
+	if s.Compose != "" {
+		return ServiceTypeCompose
+	}
 	if s.IsContainerService() {
 		return ServiceTypeContainer
 	}
@@ -271,6 +438,64 @@ func (s *Service) IsProcessService() bool {
 	return s.GetServiceType() == ServiceTypeProcess
 }
 
+// Dependencies returns the services and resources this service must wait for when
+// running locally: the entries of `uses` followed by those of `dependsOn`, without duplicates.
+func (s *Service) Dependencies() []string {
+	if len(s.DependsOn) == 0 {
+		return s.Uses
+	}
+	seen := make(map[string]bool, len(s.Uses)+len(s.DependsOn))
+	deps := make([]string, 0, len(s.Uses)+len(s.DependsOn))
+	for _, dep := range append(append([]string{}, s.Uses...), s.DependsOn...) {
+		if !seen[dep] {
+			seen[dep] = true
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// GetStopGracePeriod returns how long the service is given to stop gracefully before it is
+// force-killed, or 0 when stopGracePeriod isn't set.
+func (s *Service) GetStopGracePeriod() (time.Duration, error) {
+	if s.StopGracePeriod == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s.StopGracePeriod)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid stopGracePeriod %q: must be a duration such as 10s", s.StopGracePeriod)
+	}
+	return d, nil
+}
+
+// PythonInterpreter returns the Python interpreter set for the service, or "" for the default.
+func (s *Service) PythonInterpreter() string {
+	if s.Python == nil {
+		return ""
+	}
+	return s.Python.Interpreter
+}
+
+// UsesNodeVersionManager reports whether the service runs with its pinned Node.js version.
+func (s *Service) UsesNodeVersionManager() bool {
+	return s.Node != nil && s.Node.UseVersionManager
+}
+
+// GetPortRange returns the range that ports automatically assigned to the service are taken
+// from, or 0, 0 when portRange isn't set.
+func (s *Service) GetPortRange() (start, end int, err error) {
+	if s.PortRange == "" {
+		return 0, 0, nil
+	}
+	from, to, ok := strings.Cut(s.PortRange, "-")
+	start, startErr := strconv.Atoi(strings.TrimSpace(from))
+	end, endErr := strconv.Atoi(strings.TrimSpace(to))
+	if !ok || startErr != nil || endErr != nil || start < 1 || end > 65535 || start > end {
+		return 0, 0, fmt.Errorf("invalid portRange %q: must be a range of ports such as 4000-4099", s.PortRange)
+	}
+	return start, end, nil
+}
+
 // IsContainerService returns true if this service should run as a Docker container.
 // A service is a container service when it has an `image` field (direct image reference)
 // or a `docker.image` field (Docker config with image).
@@ -323,11 +548,12 @@ type HealthcheckConfig struct {
 	//   - ["NONE"] (disable health check)
 	Test any `yaml:"test,omitempty"`
 
-	// Type specifies the health check method: "http", "tcp", "process", "output", or "none".
+	// Type specifies the health check method: "http", "tcp", "process", "output", "log", "exec", or "none".
 	// - "http": Check an HTTP endpoint (default)
 	// - "tcp": Check if a port is listening
 	// - "process": Check if the process is running
-	// - "output": Monitor stdout for a pattern match
+	// - "output" (or "log"): Monitor stdout for a pattern match
+	// - "exec": Run the test command; exit code 0 means healthy
 	// - "none": Disable health checks (service is always considered healthy)
 	Type string `yaml:"type,omitempty"`
 
@@ -335,6 +561,10 @@ type HealthcheckConfig struct {
 	// Defaults to "/health".
 	Path string `yaml:"path,omitempty"`
 
+	// Port is the port to probe (when type=http or tcp).
+	// Defaults to the service's port.
+	Port int `yaml:"port,omitempty"`
+
 	// Pattern is a regex pattern to match in stdout (when type=output).
 	// Service is considered healthy when this pattern is matched.
 	// Examples: "Found 0 errors", "Server started", "Listening on port"
@@ -349,6 +579,14 @@ type HealthcheckConfig struct {
 	// Retries is the number of consecutive failures before marking unhealthy.
 	Retries int `yaml:"retries,omitempty"`
 
+	// SuccessThreshold is the number of consecutive successful checks before the service is ready.
+	// Defaults to 1.
+	SuccessThreshold int `yaml:"successThreshold,omitempty"`
+
+	// RestartOnFailure restarts the service when it becomes unhealthy after being ready.
+	// Defaults to true when a healthcheck is configured.
+	RestartOnFailure *bool `yaml:"restartOnFailure,omitempty"`
+
 	// StartPeriod is the grace period for container initialization (e.g., "0s", "40s").
 	StartPeriod string `yaml:"start_period,omitempty"`
 
@@ -408,6 +646,38 @@ func (h *HealthcheckConfig) GetType() string {
 	return "http"
 }
 
+// GetCommand returns the test command in CMD or CMD-SHELL form, or nil if the test is
+// not a command. A plain string that isn't an HTTP URL is run with the shell.
+func (h *HealthcheckConfig) GetCommand() []string {
+	if h == nil {
+		return nil
+	}
+
+	var test []string
+	switch t := h.Test.(type) {
+	case string:
+		if t == "" || strings.HasPrefix(t, "http://") || strings.HasPrefix(t, "https://") {
+			return nil
+		}
+		return []string{"CMD-SHELL", t}
+	case []string:
+		test = t
+	case []any:
+		for _, arg := range t {
+			str, ok := arg.(string)
+			if !ok {
+				return nil
+			}
+			test = append(test, str)
+		}
+	}
+
+	if len(test) < 2 || (test[0] != "CMD" && test[0] != "CMD-SHELL") {
+		return nil
+	}
+	return test
+}
+
 // DockerConfig represents Docker build configuration.
 type DockerConfig struct {
 	Path        string   `yaml:"path,omitempty"`
@@ -498,11 +768,23 @@ type Resource struct {
 }
 
 // GetEnvironment returns the environment variables for the service.
+// Variables in env override those in environment.
 func (s *Service) GetEnvironment() map[string]string {
-	if s.Environment == nil {
-		return make(map[string]string)
+	if len(s.Env) == 0 {
+		if s.Environment == nil {
+			return make(map[string]string)
+		}
+		return s.Environment
+	}
+
+	env := make(map[string]string, len(s.Environment)+len(s.Env))
+	for k, v := range s.Environment {
+		env[k] = v
+	}
+	for k, v := range s.Env {
+		env[k] = v
 	}
-	return s.Environment
+	return env
 }
 
 // ServiceRuntime contains the detected runtime information for a service.
@@ -515,12 +797,40 @@ type ServiceRuntime struct {
 	Args                  []string
 	WorkingDir            string
 	Port                  int
-	Protocol              string
+	Protocol              string // "http", "https" (development certificate) or "tcp"
 	Env                   map[string]string
 	HealthCheck           HealthCheckConfig
-	ShouldUpdateAzureYaml bool   // True if user wants port added to azure.yaml
-	Type                  string // Service type: "http", "tcp", "process"
-	Mode                  string // Run mode (for type=process): "watch", "build", "daemon", "task"
+	ShouldUpdateAzureYaml bool                    // True if user wants port added to azure.yaml
+	Type                  string                  // Service type: "http", "tcp", "process"
+	Mode                  string                  // Run mode (for type=process): "watch", "build", "daemon", "task"
+	DependsOn             []string                // Services (from uses and dependsOn) that must be healthy before this one starts
+	ContainerPort         int                     // Port inside the container (container services); 0 means same as Port
+	ExtraPorts            []PortMapping           // Further ports the service listens on, besides Port; container services publish them
+	Volumes               []string                // Volumes mounted into container services, in docker run -v form
+	Connection            *ServiceConnection      // How services that use this one connect to it (nil = only by URL)
+	Build                 *ContainerBuild         // Image build settings for container services built from a Dockerfile
+	ComposeFile           string                  // Absolute path to the compose file of compose services
+	Restart               *RestartPolicy          // Relaunch policy when the process exits (nil = never)
+	Workspace             *detector.NodeWorkspace // JavaScript workspace the service's package belongs to (nil = standalone)
+	BuildCommand          string                  // Command run in WorkingDir before the service starts (empty = none)
+	PreRun                *Hook                   // Hook run before the service starts, after BuildCommand
+	PostStop              *Hook                   // Hook run after the service stops or exits
+	StopGracePeriod       time.Duration           // Time allowed to stop gracefully before force-killing (0 = caller's timeout)
+	PythonInterpreter     string                  // python.interpreter of Python services (empty = the project's environment, or python on PATH)
+}
+
+// RunsInDocker reports whether the service runs in Docker (container and compose services),
+// where Docker rather than a host process holds its ports.
+func (rt *ServiceRuntime) RunsInDocker() bool {
+	return rt.Type == ServiceTypeContainer || rt.Type == ServiceTypeCompose
+}
+
+// ContainerBuild describes how to build the image of a Dockerfile-based container service.
+type ContainerBuild struct {
+	Dockerfile string   // Absolute path to the Dockerfile
+	Context    string   // Absolute path to the build context
+	BuildArgs  []string // Build arguments in KEY=value form
+	Platform   string   // Target platform (e.g., "linux/amd64"), empty for the host platform
 }
 
 // PortMapping represents a port mapping (Docker Compose style).
@@ -539,6 +849,14 @@ type HealthCheckConfig struct {
 	Timeout  time.Duration // How long to wait for service to be ready
 	Interval time.Duration // How often to retry
 	LogMatch string        // For log-based checks (e.g., "Server started")
+	Probe    []string      // For container services: built-in readiness probe run via docker exec (CMD or CMD-SHELL form)
+	Command  []string      // For exec checks: command run on the host, or in the container of container services (CMD or CMD-SHELL form)
+
+	ProbeTimeout     time.Duration // Maximum duration of a single check (0 = default of the check type)
+	SuccessThreshold int           // Consecutive successful checks before the service is ready (0 or 1 = first success)
+	LivenessInterval time.Duration // How often a ready service is checked (0 = no liveness checks)
+	FailureThreshold int           // Consecutive failed liveness checks before the service is unhealthy
+	RestartOnFailure bool          // Restart the service when it becomes unhealthy
 }
 
 // ServiceProcess represents a running service process.
@@ -556,6 +874,40 @@ type ServiceProcess struct {
 	HealthCheck chan error
 	Env         map[string]string
 	ContainerID string // Container ID for container services (Type=container)
+
+	// exit lets monitors and stop logic share a single Wait on the process.
+	exit *exitWaiter
+	// tree is the Windows job object holding the process and its descendants; nil elsewhere
+	// or when the process couldn't be assigned to one.
+	tree *processTree
+}
+
+// exitWaiter reaps a process once and shares the result with every caller.
+type exitWaiter struct {
+	once  sync.Once
+	done  chan struct{}
+	state *os.ProcessState
+	err   error
+}
+
+// Wait waits for the process to exit and returns its state.
+// Unlike os.Process.Wait, it is safe to call from multiple goroutines for processes
+// started by StartService: all callers receive the same exit state.
+func (p *ServiceProcess) Wait() (*os.ProcessState, error) {
+	if p.Process == nil {
+		return nil, errors.New("process not started")
+	}
+	if p.exit == nil {
+		return p.Process.Wait()
+	}
+	p.exit.once.Do(func() {
+		go func() {
+			p.exit.state, p.exit.err = p.Process.Wait()
+			close(p.exit.done)
+		}()
+	})
+	<-p.exit.done
+	return p.exit.state, p.exit.err
 }
 
 // DependencyGraph represents service dependencies.
@@ -593,6 +945,9 @@ type LogEntry struct {
 	Timestamp time.Time `json:"timestamp"`
 	IsStderr  bool      `json:"isStderr"`
 
+	// Fields holds the fields of JSON log lines (e.g. {"level":"error","msg":"...","requestId":"..."})
+	Fields map[string]interface{} `json:"fields,omitempty"`
+
 	// Source indicates where the log came from: "local" or "azure"
 	Source string `json:"source,omitempty"`
 
@@ -872,6 +1227,17 @@ func (s *Service) GetPrimaryPort() (int, int, bool) {
 type Hooks struct {
 	Prerun  *Hook `yaml:"prerun,omitempty"`
 	Postrun *Hook `yaml:"postrun,omitempty"`
+
+	// Service lifecycle hooks run once per service event (see ServiceEvent).
+	ServiceStarting      *Hook `yaml:"servicestarting,omitempty"`
+	ServiceStarted       *Hook `yaml:"servicestarted,omitempty"`
+	ServiceStopped       *Hook `yaml:"servicestopped,omitempty"`
+	ServiceHealthChanged *Hook `yaml:"servicehealthchanged,omitempty"`
+	ServiceReady         *Hook `yaml:"serviceready,omitempty"`
+	ServiceCrashed       *Hook `yaml:"servicecrashed,omitempty"`
+
+	// Predeps runs before dependencies are installed, by azd app deps or as part of azd app run.
+	Predeps *Hook `yaml:"predeps,omitempty"`
 }
 
 // GetPrerun safely retrieves the prerun hook, returning nil if not configured.
@@ -890,6 +1256,37 @@ func (h *Hooks) GetPostrun() *Hook {
 	return h.Postrun
 }
 
+// GetPredeps safely retrieves the predeps hook, returning nil if not configured.
+func (h *Hooks) GetPredeps() *Hook {
+	if h == nil {
+		return nil
+	}
+	return h.Predeps
+}
+
+// GetServiceHook safely retrieves the hook for a service lifecycle event, returning nil if not configured.
+func (h *Hooks) GetServiceHook(event ServiceEvent) *Hook {
+	if h == nil {
+		return nil
+	}
+	switch event {
+	case EventServiceStarting:
+		return h.ServiceStarting
+	case EventServiceStarted:
+		return h.ServiceStarted
+	case EventServiceStopped:
+		return h.ServiceStopped
+	case EventServiceHealthChanged:
+		return h.ServiceHealthChanged
+	case EventServiceReady:
+		return h.ServiceReady
+	case EventServiceCrashed:
+		return h.ServiceCrashed
+	default:
+		return nil
+	}
+}
+
 // Hook represents a lifecycle hook configuration.
 type Hook struct {
 	Run             string        `yaml:"run"`                       // Script or command to execute
@@ -167,6 +167,10 @@ services:
 						t.Errorf("Expected path to contain venv directory %q, got %q", tt.venvName, runtimeInfo.Command)
 					}
 				}
+				// The venv is activated for the service
+				if venv := runtimeInfo.Env["VIRTUAL_ENV"]; filepath.Base(venv) != tt.venvName {
+					t.Errorf("Expected VIRTUAL_ENV to be the %s directory, got %q", tt.venvName, venv)
+				}
 			} else {
 				// Should use system python
 				if runtimeInfo.Command != "python" {
@@ -183,3 +187,121 @@ services:
 		})
 	}
 }
+
+func TestPythonCondaEnvironment(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"environment.yml": "name: api\ndependencies:\n  - python=3.12\n  - fastapi\n  - uvicorn",
+		"main.py":         "from fastapi import FastAPI\napp = FastAPI()",
+		"azure.yaml":      "name: test-app\nservices:\n  api:\n    project: .\n    language: python\n",
+	}
+	for filename, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, filename), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to create file %s: %v", filename, err)
+		}
+	}
+	condaPython := filepath.Join(tmpDir, ".conda", "bin", "python")
+	if runtime.GOOS == "windows" {
+		condaPython = filepath.Join(tmpDir, ".conda", "python.exe")
+	}
+	if err := os.MkdirAll(filepath.Dir(condaPython), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(condaPython, []byte("#!/usr/bin/env python"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	azureYaml, err := service.ParseAzureYaml(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to parse azure.yaml: %v", err)
+	}
+	usedPorts := map[int]bool{3000: true, 5000: true, 8000: true, 8080: true}
+	runtimeInfo, err := service.DetectServiceRuntime("api", azureYaml.Services["api"], usedPorts, tmpDir, "azd")
+	if err != nil {
+		t.Fatalf("Failed to detect runtime: %v", err)
+	}
+
+	if !strings.HasSuffix(runtimeInfo.Command, strings.TrimPrefix(condaPython, tmpDir)) {
+		t.Errorf("Expected the conda environment's python, got %q", runtimeInfo.Command)
+	}
+	if prefix := runtimeInfo.Env["CONDA_PREFIX"]; filepath.Base(prefix) != ".conda" {
+		t.Errorf("Expected CONDA_PREFIX to be the .conda directory, got %q", prefix)
+	}
+	if path := pathEnv(runtimeInfo.Env); !strings.HasPrefix(path, filepath.Dir(runtimeInfo.Command)) {
+		t.Errorf("Expected PATH to start with the conda environment, got %q", path)
+	}
+}
+
+func TestPythonInterpreterOverride(t *testing.T) {
+	tests := []struct {
+		name        string
+		interpreter string
+		wantCommand string // relative to the project directory when wantPath is set
+		wantPath    bool
+	}{
+		{name: "command on PATH", interpreter: "python3.12", wantCommand: "python3.12"},
+		{name: "path in the project", interpreter: ".venv311/bin/python", wantCommand: filepath.Join(".venv311", "bin", "python"), wantPath: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			files := map[string]string{
+				"requirements.txt": "fastapi\nuvicorn",
+				"main.py":          "from fastapi import FastAPI\napp = FastAPI()",
+				"azure.yaml":       "name: test-app\nservices:\n  api:\n    project: .\n    language: python\n    python:\n      interpreter: " + tt.interpreter + "\n",
+			}
+			for filename, content := range files {
+				if err := os.WriteFile(filepath.Join(tmpDir, filename), []byte(content), 0600); err != nil {
+					t.Fatalf("Failed to create file %s: %v", filename, err)
+				}
+			}
+			// The interpreter wins over the project's .venv
+			venvPython := filepath.Join(tmpDir, ".venv", "bin", "python")
+			if err := os.MkdirAll(filepath.Dir(venvPython), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(venvPython, []byte("#!/usr/bin/env python"), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			azureYaml, err := service.ParseAzureYaml(tmpDir)
+			if err != nil {
+				t.Fatalf("Failed to parse azure.yaml: %v", err)
+			}
+			svc := azureYaml.Services["api"]
+			if got := svc.PythonInterpreter(); got != tt.interpreter {
+				t.Fatalf("PythonInterpreter() = %q, want %q", got, tt.interpreter)
+			}
+			usedPorts := map[int]bool{3000: true, 5000: true, 8000: true, 8080: true}
+			runtimeInfo, err := service.DetectServiceRuntime("api", svc, usedPorts, tmpDir, "azd")
+			if err != nil {
+				t.Fatalf("Failed to detect runtime: %v", err)
+			}
+
+			if tt.wantPath {
+				if !filepath.IsAbs(runtimeInfo.Command) || !strings.HasSuffix(runtimeInfo.Command, tt.wantCommand) {
+					t.Errorf("Command = %q, want the project's %s", runtimeInfo.Command, tt.wantCommand)
+				}
+				if path := pathEnv(runtimeInfo.Env); !strings.HasPrefix(path, filepath.Dir(runtimeInfo.Command)) {
+					t.Errorf("Expected PATH to start with the interpreter's directory, got %q", path)
+				}
+			} else if runtimeInfo.Command != tt.wantCommand {
+				t.Errorf("Command = %q, want %q", runtimeInfo.Command, tt.wantCommand)
+			}
+			if _, ok := runtimeInfo.Env["VIRTUAL_ENV"]; ok {
+				t.Error("Expected the project's .venv not to be activated")
+			}
+		})
+	}
+}
+
+// pathEnv returns the PATH of a service environment, whatever its case.
+func pathEnv(env map[string]string) string {
+	for key, value := range env {
+		if strings.EqualFold(key, "PATH") {
+			return value
+		}
+	}
+	return ""
+}
@@ -384,3 +384,28 @@ func splitEnv(env string) []string {
 	}
 	return []string{env}
 }
+
+func TestSetAzdEnvironment(t *testing.T) {
+	defer SetAzdEnvironment(nil)
+
+	environmentCacheMu.Lock()
+	environmentCache = map[string]string{"SERVICE_API_URL": "https://cached.example.com"}
+	environmentCacheMu.Unlock()
+	defer func() {
+		environmentCacheMu.Lock()
+		environmentCache = make(map[string]string)
+		environmentCacheMu.Unlock()
+	}()
+
+	values := map[string]string{"SERVICE_API_URL": "https://staging.example.com"}
+	SetAzdEnvironment(values)
+	values["SERVICE_API_URL"] = "modified"
+
+	if got := AzdEnvironment()["SERVICE_API_URL"]; got != "https://staging.example.com" {
+		t.Errorf("AzdEnvironment()[SERVICE_API_URL] = %q, want a copy of the values set", got)
+	}
+	// The selected azd environment wins over cached values
+	if got := getAzureEnvironmentValues()["SERVICE_API_URL"]; got != "https://staging.example.com" {
+		t.Errorf("getAzureEnvironmentValues()[SERVICE_API_URL] = %q, want the azd environment value", got)
+	}
+}
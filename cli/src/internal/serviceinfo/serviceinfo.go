@@ -19,6 +19,10 @@ var (
 	// This cache is refreshed when azd fires environment update events (e.g., after provision)
 	environmentCache   map[string]string
 	environmentCacheMu sync.RWMutex
+
+	// azdEnvironment stores the values of the azd environment selected by `azd app run`
+	// (--azd-env), read from .azure/<env>/.env. It is guarded by environmentCacheMu.
+	azdEnvironment map[string]string
 )
 
 func init() {
@@ -63,6 +67,32 @@ func RefreshEnvironmentFromEvent(bicepOutputs map[string]interface{}) {
 	}
 }
 
+// SetAzdEnvironment replaces the values of the azd environment selected by `azd app run`.
+// They take precedence over the process environment, so the dashboard shows the Azure
+// endpoints of the selected environment.
+func SetAzdEnvironment(values map[string]string) {
+	newValues := make(map[string]string, len(values))
+	for key, value := range values {
+		newValues[key] = value
+	}
+
+	environmentCacheMu.Lock()
+	azdEnvironment = newValues
+	environmentCacheMu.Unlock()
+}
+
+// AzdEnvironment returns a copy of the values set with SetAzdEnvironment.
+func AzdEnvironment() map[string]string {
+	environmentCacheMu.RLock()
+	defer environmentCacheMu.RUnlock()
+
+	values := make(map[string]string, len(azdEnvironment))
+	for key, value := range azdEnvironment {
+		values[key] = value
+	}
+	return values
+}
+
 // ServiceInfo contains comprehensive information about a service.
 type ServiceInfo struct {
 	Name string `json:"name"`
@@ -167,6 +197,10 @@ func getAzureEnvironmentValues() map[string]string {
 	for key, value := range environmentCache {
 		envVars[key] = value
 	}
+	// The azd environment selected with --azd-env wins over the one azd injected
+	for key, value := range azdEnvironment {
+		envVars[key] = value
+	}
 	environmentCacheMu.RUnlock()
 
 	return envVars
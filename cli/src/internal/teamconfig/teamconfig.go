@@ -0,0 +1,290 @@
+// Package teamconfig loads shared team defaults from a .azdapp/config.yaml file
+// committed to a repository.
+//
+// Team defaults sit below every user-controlled setting. The precedence is:
+//
+//	command-line flags > environment variables > user config > team defaults > built-in defaults
+//
+// The file only contains plain values (numbers, names, policies). Nothing in it is
+// executed, so a cloned repository cannot run commands through it.
+package teamconfig
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DirName is the directory holding team defaults, relative to the repository root.
+	DirName = ".azdapp"
+	// FileName is the team defaults file inside DirName.
+	FileName = "config.yaml"
+
+	// maxFileSize bounds how much of the file is read.
+	maxFileSize = 1 << 20 // 1MB
+)
+
+// Port conflict policies.
+const (
+	// ConflictPrompt asks the user what to do (default).
+	ConflictPrompt = "prompt"
+	// ConflictKill stops the process holding the port. It is accepted in the file but treated as
+	// prompt: a cloned repository can't grant itself killing processes (see ConflictPolicy).
+	ConflictKill = "kill"
+	// ConflictReassign picks a different free port.
+	ConflictReassign = "reassign"
+	// ConflictFail aborts startup with an error.
+	ConflictFail = "fail"
+)
+
+// Port allocation strategies.
+const (
+	// StrategyRandom starts the search for a free port at a random port in the range (default).
+	StrategyRandom = "random"
+	// StrategyStable starts the search at a port derived from the project and service names,
+	// so everyone working on the project gets the same ports.
+	StrategyStable = "stable"
+)
+
+// Defaults holds team-wide defaults. Zero values mean "not set".
+type Defaults struct {
+	// Concurrency limits how many dependency installs run at once.
+	Concurrency int `yaml:"concurrency,omitempty"`
+	// Ports configures automatic port assignment.
+	Ports PortDefaults `yaml:"ports,omitempty"`
+	// Profile is the default health profile name (see azd app health --profile).
+	Profile string `yaml:"profile,omitempty"`
+	// Logs configures defaults for azd app logs.
+	Logs LogDefaults `yaml:"logs,omitempty"`
+
+	// path is the file the defaults were loaded from; empty when no file was found.
+	path string
+}
+
+// PortDefaults configures port assignment defaults.
+type PortDefaults struct {
+	RangeStart     int    `yaml:"rangeStart,omitempty"`
+	RangeEnd       int    `yaml:"rangeEnd,omitempty"`
+	ConflictPolicy string `yaml:"conflictPolicy,omitempty"`
+	Strategy       string `yaml:"strategy,omitempty"`
+	// Denylist holds ports and ranges (e.g. "6000-6010") that automatic assignment never picks.
+	Denylist []string `yaml:"denylist,omitempty"`
+}
+
+// LogDefaults configures log viewing defaults.
+type LogDefaults struct {
+	Level string `yaml:"level,omitempty"`
+	Tail  int    `yaml:"tail,omitempty"`
+}
+
+var (
+	cache   = make(map[string]*Defaults)
+	cacheMu sync.Mutex
+)
+
+// Path returns the file the defaults were loaded from, or "" if none was found.
+func (d *Defaults) Path() string {
+	if d == nil {
+		return ""
+	}
+	return d.path
+}
+
+// Find walks up from startDir looking for .azdapp/config.yaml.
+// The search stops at the repository root (a directory containing .git) or the
+// filesystem root. Returns "" if no file is found.
+func Find(startDir string) string {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		candidate := filepath.Join(dir, DirName, FileName)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Load reads and validates the team defaults that apply to startDir.
+// Returns empty defaults (not an error) when no file exists.
+func Load(startDir string) (*Defaults, error) {
+	path := Find(startDir)
+	if path == "" {
+		return &Defaults{}, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if info.Size() > maxFileSize {
+		return nil, fmt.Errorf("%s exceeds maximum size of %d bytes", path, maxFileSize)
+	}
+
+	// #nosec G304 -- path is the fixed .azdapp/config.yaml name under a project directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var defaults Defaults
+	if err := yaml.Unmarshal(data, &defaults); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := defaults.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid team defaults in %s: %w", path, err)
+	}
+
+	defaults.path = path
+	return &defaults, nil
+}
+
+// ForProject returns the team defaults for projectDir, caching the result per directory.
+// Errors are logged and yield empty defaults; a broken team file must not block commands.
+func ForProject(projectDir string) *Defaults {
+	absDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		absDir = projectDir
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if d, ok := cache[absDir]; ok {
+		return d
+	}
+
+	d, err := Load(absDir)
+	if err != nil {
+		slog.Warn("ignoring team defaults", "error", err)
+		d = &Defaults{}
+	} else if d.path != "" {
+		slog.Debug("loaded team defaults", "path", d.path)
+	}
+
+	cache[absDir] = d
+	return d
+}
+
+// ResetCache clears cached defaults. This is primarily for testing.
+func ResetCache() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cache = make(map[string]*Defaults)
+}
+
+// Validate checks that all set values are within range.
+func (d *Defaults) Validate() error {
+	if d.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative, got %d", d.Concurrency)
+	}
+	if err := validatePort("ports.rangeStart", d.Ports.RangeStart); err != nil {
+		return err
+	}
+	if err := validatePort("ports.rangeEnd", d.Ports.RangeEnd); err != nil {
+		return err
+	}
+	if d.Ports.RangeStart > 0 && d.Ports.RangeEnd > 0 && d.Ports.RangeStart > d.Ports.RangeEnd {
+		return fmt.Errorf("ports.rangeStart (%d) must not exceed ports.rangeEnd (%d)", d.Ports.RangeStart, d.Ports.RangeEnd)
+	}
+
+	switch strings.ToLower(d.Ports.ConflictPolicy) {
+	case "", ConflictPrompt, ConflictKill, ConflictReassign, ConflictFail:
+	default:
+		return fmt.Errorf("ports.conflictPolicy must be one of prompt, kill, reassign, fail; got %q", d.Ports.ConflictPolicy)
+	}
+
+	switch strings.ToLower(d.Ports.Strategy) {
+	case "", StrategyRandom, StrategyStable:
+	default:
+		return fmt.Errorf("ports.strategy must be one of random, stable; got %q", d.Ports.Strategy)
+	}
+	if _, err := ParsePortList(d.Ports.Denylist); err != nil {
+		return fmt.Errorf("ports.denylist: %w", err)
+	}
+
+	switch strings.ToLower(d.Logs.Level) {
+	case "", "all", "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("logs.level must be one of all, debug, info, warn, error; got %q", d.Logs.Level)
+	}
+	if d.Logs.Tail < 0 {
+		return fmt.Errorf("logs.tail must not be negative, got %d", d.Logs.Tail)
+	}
+
+	return nil
+}
+
+// ConflictPolicy returns the normalized port conflict policy, defaulting to prompt.
+// Kill is downgraded to prompt: killing whatever holds a port without asking has to be
+// opted into by the user (the "always kill" preference), not by a committed file.
+func (d *Defaults) ConflictPolicy() string {
+	if d == nil || d.Ports.ConflictPolicy == "" {
+		return ConflictPrompt
+	}
+	policy := strings.ToLower(d.Ports.ConflictPolicy)
+	if policy == ConflictKill {
+		slog.Warn("ignoring ports.conflictPolicy kill from team defaults; choose 'always kill' at a port conflict prompt to opt in", "path", d.path)
+		return ConflictPrompt
+	}
+	return policy
+}
+
+// PortStrategy returns the normalized port allocation strategy, defaulting to random.
+func (d *Defaults) PortStrategy() string {
+	if d == nil || d.Ports.Strategy == "" {
+		return StrategyRandom
+	}
+	return strings.ToLower(d.Ports.Strategy)
+}
+
+// ParsePortList parses a list of ports and port ranges such as "5000" and "6000-6010".
+func ParsePortList(entries []string) ([]int, error) {
+	var ports []int
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(entry, "-")
+		if !isRange {
+			to = from
+		}
+		start, startErr := strconv.Atoi(strings.TrimSpace(from))
+		end, endErr := strconv.Atoi(strings.TrimSpace(to))
+		if startErr != nil || endErr != nil || start < 1 || end > 65535 || start > end {
+			return nil, fmt.Errorf("invalid port or port range %q", entry)
+		}
+		for port := start; port <= end; port++ {
+			ports = append(ports, port)
+		}
+	}
+	return ports, nil
+}
+
+// validatePort checks an optional port value.
+func validatePort(field string, port int) error {
+	if port < 0 || port > 65535 {
+		return fmt.Errorf("%s must be between 1 and 65535, got %d", field, port)
+	}
+	return nil
+}
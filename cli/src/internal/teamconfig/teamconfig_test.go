@@ -0,0 +1,194 @@
+package teamconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTeamConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	configDir := filepath.Join(dir, DirName)
+	if err := os.MkdirAll(configDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(configDir, FileName)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_NoFile(t *testing.T) {
+	d, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if d.Path() != "" || d.Concurrency != 0 {
+		t.Errorf("Load() without file = %+v, want empty defaults", d)
+	}
+	if d.ConflictPolicy() != ConflictPrompt {
+		t.Errorf("ConflictPolicy() = %q, want %q", d.ConflictPolicy(), ConflictPrompt)
+	}
+	if d.PortStrategy() != StrategyRandom {
+		t.Errorf("PortStrategy() = %q, want %q", d.PortStrategy(), StrategyRandom)
+	}
+}
+
+func TestLoad_FindsFileInParentDirectory(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	path := writeTeamConfig(t, root, `
+concurrency: 2
+ports:
+  rangeStart: 4000
+  rangeEnd: 4999
+  conflictPolicy: reassign
+  strategy: Stable
+profile: development
+logs:
+  level: warn
+  tail: 200
+`)
+
+	projectDir := filepath.Join(root, "apps", "web")
+	if err := os.MkdirAll(projectDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if d.Path() != path {
+		t.Errorf("Path() = %q, want %q", d.Path(), path)
+	}
+	if d.Concurrency != 2 || d.Ports.RangeStart != 4000 || d.Ports.RangeEnd != 4999 {
+		t.Errorf("unexpected values: %+v", d)
+	}
+	if d.ConflictPolicy() != ConflictReassign {
+		t.Errorf("ConflictPolicy() = %q, want %q", d.ConflictPolicy(), ConflictReassign)
+	}
+	if d.PortStrategy() != StrategyStable {
+		t.Errorf("PortStrategy() = %q, want %q", d.PortStrategy(), StrategyStable)
+	}
+	if d.Profile != "development" || d.Logs.Level != "warn" || d.Logs.Tail != 200 {
+		t.Errorf("unexpected values: %+v", d)
+	}
+}
+
+func TestFind_StopsAtRepositoryRoot(t *testing.T) {
+	outer := t.TempDir()
+	writeTeamConfig(t, outer, "concurrency: 1\n")
+
+	repo := filepath.Join(outer, "repo")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := Find(repo); got != "" {
+		t.Errorf("Find() = %q, want search to stop at the repository root", got)
+	}
+}
+
+func TestLoad_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"negative concurrency", "concurrency: -1\n", "concurrency"},
+		{"port out of range", "ports:\n  rangeStart: 70000\n", "ports.rangeStart"},
+		{"inverted range", "ports:\n  rangeStart: 5000\n  rangeEnd: 4000\n", "must not exceed"},
+		{"unknown policy", "ports:\n  conflictPolicy: ignore\n", "conflictPolicy"},
+		{"unknown strategy", "ports:\n  strategy: sequential\n", "ports.strategy"},
+		{"invalid denylist", "ports:\n  denylist: [\"5000-4000\"]\n", "ports.denylist"},
+		{"unknown log level", "logs:\n  level: verbose\n", "logs.level"},
+		{"malformed yaml", "concurrency: [\n", "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTeamConfig(t, dir, tt.content)
+
+			_, err := Load(dir)
+			if err == nil {
+				t.Fatal("Load() expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestForProject_IgnoresInvalidFile(t *testing.T) {
+	ResetCache()
+	t.Cleanup(ResetCache)
+
+	dir := t.TempDir()
+	writeTeamConfig(t, dir, "concurrency: -1\n")
+
+	d := ForProject(dir)
+	if d == nil || d.Concurrency != 0 {
+		t.Errorf("ForProject() = %+v, want empty defaults for an invalid file", d)
+	}
+}
+
+func TestConflictPolicy(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   string
+	}{
+		{"", ConflictPrompt},
+		{"Reassign", ConflictReassign},
+		{"fail", ConflictFail},
+		// A committed file can't make port conflicts kill without asking
+		{"kill", ConflictPrompt},
+		{"KILL", ConflictPrompt},
+	}
+
+	for _, tt := range tests {
+		d := &Defaults{Ports: PortDefaults{ConflictPolicy: tt.policy}}
+		if got := d.ConflictPolicy(); got != tt.want {
+			t.Errorf("ConflictPolicy() with %q = %q, want %q", tt.policy, got, tt.want)
+		}
+	}
+	if got := (*Defaults)(nil).ConflictPolicy(); got != ConflictPrompt {
+		t.Errorf("ConflictPolicy() without defaults = %q, want %q", got, ConflictPrompt)
+	}
+}
+
+func TestParsePortList(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    []int
+		wantErr bool
+	}{
+		{"empty", nil, nil, false},
+		{"ports", []string{"5000", " 6379 "}, []int{5000, 6379}, false},
+		{"range", []string{"10000-10002"}, []int{10000, 10001, 10002}, false},
+		{"blank entries", []string{"", "5000"}, []int{5000}, false},
+		{"not a number", []string{"redis"}, nil, true},
+		{"reversed range", []string{"5000-4000"}, nil, true},
+		{"out of range", []string{"70000"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePortList(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePortList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ParsePortList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
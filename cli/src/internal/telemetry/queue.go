@@ -0,0 +1,209 @@
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/version"
+)
+
+const (
+	// BatchSize is how many events are queued before they're uploaded, and the most sent per request.
+	BatchSize = 20
+	// maxBatchAge uploads a smaller batch once its oldest event is this old.
+	maxBatchAge = 24 * time.Hour
+	// maxQueuedEvents bounds the queue when uploads fail or no endpoint is configured;
+	// the oldest events are dropped.
+	maxQueuedEvents = 500
+	// queueFileName is the queue file in the queue directory, one JSON event per line.
+	queueFileName = "queue.jsonl"
+)
+
+// QueueDir returns the directory of the telemetry queue, ~/.azd/app/telemetry.
+// This is a variable to allow test overrides.
+var QueueDir = func() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".azd", "app", "telemetry"), nil
+}
+
+// Queue is a file of events waiting to be uploaded. Events are appended by each command,
+// so concurrent azd app processes can share it.
+type Queue struct {
+	Dir string
+}
+
+// DefaultQueue returns the queue in QueueDir.
+func DefaultQueue() (*Queue, error) {
+	dir, err := QueueDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Queue{Dir: dir}, nil
+}
+
+func (q *Queue) path() string {
+	return filepath.Join(q.Dir, queueFileName)
+}
+
+// Add appends an event to the queue.
+func (q *Queue) Add(event Event) error {
+	return appendEvents(q.path(), []Event{event})
+}
+
+// Events returns the queued events, oldest first. Lines that can't be parsed are skipped.
+func (q *Queue) Events() ([]Event, error) {
+	return readEvents(q.path())
+}
+
+// FlushDue reports whether a batch is ready to upload: BatchSize events are queued or the
+// oldest is older than maxBatchAge.
+func (q *Queue) FlushDue(now time.Time) bool {
+	events, err := q.Events()
+	if err != nil || len(events) == 0 {
+		return false
+	}
+	return len(events) >= BatchSize || now.Sub(events[0].Timestamp) >= maxBatchAge
+}
+
+// Flush uploads the queued events to endpoint in batches of BatchSize. Events that aren't
+// sent are put back in the queue, keeping the newest maxQueuedEvents; with no endpoint the
+// queue is only trimmed.
+func (q *Queue) Flush(ctx context.Context, endpoint string) error {
+	// Take the queue so events added while uploading go to a new file
+	sending := filepath.Join(q.Dir, fmt.Sprintf("sending-%d.jsonl", os.Getpid()))
+	if err := os.Rename(q.path(), sending); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to take telemetry queue: %w", err)
+	}
+	events, err := readEvents(sending)
+	_ = os.Remove(sending)
+	if err != nil {
+		return err
+	}
+
+	var uploadErr error
+	if endpoint != "" {
+		client := &http.Client{Timeout: uploadTimeout}
+		for len(events) > 0 {
+			n := min(BatchSize, len(events))
+			if uploadErr = upload(ctx, client, endpoint, events[:n]); uploadErr != nil {
+				break
+			}
+			events = events[n:]
+		}
+	}
+
+	if len(events) > maxQueuedEvents {
+		events = events[len(events)-maxQueuedEvents:]
+	}
+	if len(events) > 0 {
+		if err := appendEvents(q.path(), events); err != nil {
+			return errors.Join(uploadErr, err)
+		}
+	}
+	return uploadErr
+}
+
+// Clear deletes the queued events.
+func (q *Queue) Clear() error {
+	if err := os.Remove(q.path()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete telemetry queue: %w", err)
+	}
+	return nil
+}
+
+// upload posts a batch of events as {"events": [...]}.
+func upload(ctx context.Context, client *http.Client, endpoint string, events []Event) error {
+	body, err := json.Marshal(struct {
+		Events []Event `json:"events"`
+	}{events})
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "azd-app/"+version.Version)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry to %s: %w", endpoint, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("telemetry endpoint %s returned %s: %s", endpoint, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// appendEvents appends events to the queue file at path, one JSON object per line.
+// Each event is a single write, so lines from concurrent processes don't interleave.
+func appendEvents(path string, events []Event) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+	// #nosec G304 -- path is the queue file in the telemetry directory
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open telemetry queue: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode telemetry event: %w", err)
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write telemetry queue: %w", err)
+		}
+	}
+	return nil
+}
+
+// readEvents reads the queue file at path. A missing file has no events.
+func readEvents(path string) ([]Event, error) {
+	// #nosec G304 -- path is a queue file in the telemetry directory
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read telemetry queue: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read telemetry queue: %w", err)
+	}
+	return events, nil
+}
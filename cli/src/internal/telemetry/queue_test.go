@@ -0,0 +1,120 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fillQueue adds n events to queue, one second apart starting at start.
+func fillQueue(t *testing.T, queue *Queue, n int, start time.Time) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := queue.Add(Event{Command: "app run", Success: true, Timestamp: start.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+}
+
+func TestQueueFlushDue(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		events int
+		start  time.Time
+		want   bool
+	}{
+		{"empty", 0, now, false},
+		{"small recent batch", 3, now.Add(-time.Hour), false},
+		{"full batch", BatchSize, now.Add(-time.Hour), true},
+		{"old batch", 1, now.Add(-maxBatchAge), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := &Queue{Dir: t.TempDir()}
+			fillQueue(t, queue, tt.events, tt.start)
+			if got := queue.FlushDue(now); got != tt.want {
+				t.Errorf("FlushDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueueFlush(t *testing.T) {
+	var requests, received atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Events []Event `json:"events"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		requests.Add(1)
+		received.Add(int32(len(payload.Events)))
+	}))
+	defer ts.Close()
+
+	queue := &Queue{Dir: t.TempDir()}
+	fillQueue(t, queue, BatchSize+5, time.Now())
+
+	if err := queue.Flush(context.Background(), ts.URL); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if requests.Load() != 2 || received.Load() != BatchSize+5 {
+		t.Errorf("sent %d events in %d requests, want %d in 2", received.Load(), requests.Load(), BatchSize+5)
+	}
+	if events, _ := queue.Events(); len(events) != 0 {
+		t.Errorf("%d events left after Flush(), want 0", len(events))
+	}
+}
+
+func TestQueueFlush_KeepsUnsentEvents(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	queue := &Queue{Dir: t.TempDir()}
+	fillQueue(t, queue, 3, time.Now())
+
+	if err := queue.Flush(context.Background(), ts.URL); err == nil {
+		t.Error("Flush() error = nil, want the endpoint's error")
+	}
+	if events, _ := queue.Events(); len(events) != 3 {
+		t.Errorf("%d events left after a failed Flush(), want 3", len(events))
+	}
+}
+
+func TestQueueFlush_TrimsWithoutEndpoint(t *testing.T) {
+	queue := &Queue{Dir: t.TempDir()}
+	start := time.Now()
+	fillQueue(t, queue, maxQueuedEvents+10, start)
+
+	if err := queue.Flush(context.Background(), ""); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	events, err := queue.Events()
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+	if len(events) != maxQueuedEvents {
+		t.Fatalf("%d events left, want %d", len(events), maxQueuedEvents)
+	}
+	// The oldest events are dropped
+	if want := start.Add(10 * time.Second); !events[0].Timestamp.Equal(want) {
+		t.Errorf("oldest event at %v, want %v", events[0].Timestamp, want)
+	}
+
+	if err := queue.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if events, _ := queue.Events(); len(events) != 0 {
+		t.Errorf("%d events left after Clear(), want 0", len(events))
+	}
+}
@@ -0,0 +1,212 @@
+// Package telemetry records anonymous usage events: the command that ran, how long it took,
+// whether it succeeded and the frameworks it detected. Arguments, paths, service names and
+// error messages are never recorded.
+//
+// Telemetry is off unless the user opts in with `azd app config telemetry on`. The environment
+// takes precedence over the saved setting: AZURE_CORE_COLLECT_TELEMETRY=no (azd's setting) and
+// DO_NOT_TRACK=1 turn it off, and AZD_APP_COLLECT_TELEMETRY=yes|no turns it on or off for the
+// process. Events are queued in ~/.azd/app/telemetry and uploaded in batches when an endpoint
+// is configured.
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/config"
+	"github.com/jongio/azd-app/cli/src/internal/version"
+)
+
+// Environment variables that control telemetry.
+const (
+	// EnvCollect turns telemetry on or off for the process, overriding the saved setting.
+	EnvCollect = "AZD_APP_COLLECT_TELEMETRY"
+	// EnvAzdCollect is azd's telemetry setting; "no" turns azd app telemetry off too.
+	EnvAzdCollect = "AZURE_CORE_COLLECT_TELEMETRY"
+	// EnvDoNotTrack is the cross-tool opt-out (https://consoledonottrack.com).
+	EnvDoNotTrack = "DO_NOT_TRACK"
+	// EnvEndpoint overrides the endpoint queued events are uploaded to.
+	EnvEndpoint = "AZD_APP_TELEMETRY_ENDPOINT"
+)
+
+// Endpoint is the URL queued events are uploaded to, set at build time with
+// -ldflags "-X github.com/jongio/azd-app/cli/src/internal/telemetry.Endpoint=...".
+// When neither it nor EnvEndpoint is set, events stay in the local queue.
+var Endpoint = ""
+
+// uploadTimeout bounds how long exiting waits for a batch upload.
+const uploadTimeout = 3 * time.Second
+
+// Event is one command execution.
+type Event struct {
+	Command    string    `json:"command"` // Command path, e.g. "app run"
+	DurationMs int64     `json:"durationMs"`
+	Success    bool      `json:"success"`
+	Frameworks []string  `json:"frameworks,omitempty"` // Detected frameworks, e.g. "Next.js"
+	Version    string    `json:"version"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Status is whether telemetry is collected and why.
+type Status struct {
+	Enabled bool   `json:"enabled"`
+	OptedIn bool   `json:"optedIn"` // The saved setting
+	Reason  string `json:"reason"`
+}
+
+// Resolve determines whether telemetry is collected from the environment and the saved
+// opt-in setting.
+func Resolve(optedIn bool) Status {
+	status := Status{OptedIn: optedIn}
+	if collect, ok := parseSwitch(os.Getenv(EnvAzdCollect)); ok && !collect {
+		status.Reason = EnvAzdCollect + " turns telemetry off"
+		return status
+	}
+	if doNotTrack, ok := parseSwitch(os.Getenv(EnvDoNotTrack)); ok && doNotTrack {
+		status.Reason = EnvDoNotTrack + " turns telemetry off"
+		return status
+	}
+	if collect, ok := parseSwitch(os.Getenv(EnvCollect)); ok {
+		status.Enabled = collect
+		if collect {
+			status.Reason = EnvCollect + " turns telemetry on"
+		} else {
+			status.Reason = EnvCollect + " turns telemetry off"
+		}
+		return status
+	}
+	status.Enabled = optedIn
+	if optedIn {
+		status.Reason = "opted in with 'azd app config telemetry on'"
+	} else {
+		status.Reason = "not opted in"
+	}
+	return status
+}
+
+// Current resolves the telemetry status with the setting saved in ~/.azd/config.json.
+func Current() Status {
+	return Resolve(config.GetTelemetryEnabled())
+}
+
+// parseSwitch parses an on/off environment value. ok is false when value is empty or
+// not recognized.
+func parseSwitch(value string) (on bool, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "on":
+		return true, true
+	case "0", "false", "no", "off":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// uploadEndpoint returns the endpoint to upload to, or "" when none is configured.
+func uploadEndpoint() string {
+	if endpoint := os.Getenv(EnvEndpoint); endpoint != "" {
+		return endpoint
+	}
+	return Endpoint
+}
+
+// Uploads reports whether queued events are uploaded: an endpoint is set in this build or with
+// EnvEndpoint. Without one, events only stay in the local queue.
+func Uploads() bool {
+	return uploadEndpoint() != ""
+}
+
+// state is the command being executed and what it detected.
+var state = struct {
+	mu         sync.Mutex
+	command    string
+	start      time.Time
+	frameworks map[string]bool
+}{}
+
+// StartCommand records the start of the command at commandPath, e.g. "app run".
+func StartCommand(commandPath string) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.command = commandPath
+	state.start = time.Now()
+	state.frameworks = make(map[string]bool)
+}
+
+// AddFrameworks records frameworks detected by the running command. Empty names are ignored.
+func AddFrameworks(names ...string) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.frameworks == nil {
+		return
+	}
+	for _, name := range names {
+		if name != "" {
+			state.frameworks[name] = true
+		}
+	}
+}
+
+// Finish queues the event of the running command when telemetry is enabled, and uploads the
+// queue when a batch is due and an endpoint is configured. Failures are logged at debug level and never affect the command.
+func Finish(err error) {
+	state.mu.Lock()
+	command, start, detected := state.command, state.start, state.frameworks
+	state.command, state.frameworks = "", nil
+	state.mu.Unlock()
+
+	if command == "" || !Current().Enabled {
+		return
+	}
+
+	frameworks := make([]string, 0, len(detected))
+	for name := range detected {
+		frameworks = append(frameworks, name)
+	}
+	sort.Strings(frameworks)
+
+	queue, queueErr := DefaultQueue()
+	if queueErr != nil {
+		slog.Debug("telemetry queue unavailable", "error", queueErr)
+		return
+	}
+	endpoint := uploadEndpoint()
+	if endpoint == "" {
+		// Nothing is uploaded, so the queue is never flushed; stop adding once it is full
+		// instead of rewriting it on every command
+		if queued, _ := queue.Events(); len(queued) >= maxQueuedEvents {
+			return
+		}
+	}
+	event := Event{
+		Command:    command,
+		DurationMs: time.Since(start).Milliseconds(),
+		Success:    err == nil,
+		Frameworks: frameworks,
+		Version:    version.Version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Timestamp:  time.Now().UTC(),
+	}
+	if addErr := queue.Add(event); addErr != nil {
+		slog.Debug("failed to queue telemetry event", "error", addErr)
+		return
+	}
+
+	if endpoint == "" || !queue.FlushDue(time.Now()) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+	if flushErr := queue.Flush(ctx, endpoint); flushErr != nil {
+		slog.Debug("failed to upload telemetry", "error", flushErr)
+	}
+}
@@ -0,0 +1,154 @@
+package telemetry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jongio/azd-app/cli/src/internal/config"
+)
+
+// clearTelemetryEnv unsets the environment variables that control telemetry.
+func clearTelemetryEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{EnvCollect, EnvAzdCollect, EnvDoNotTrack, EnvEndpoint} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		optedIn bool
+		want    bool
+	}{
+		{"not opted in", nil, false, false},
+		{"opted in", nil, true, true},
+		{"azd telemetry off", map[string]string{EnvAzdCollect: "no"}, true, false},
+		{"azd telemetry on is not an opt-in", map[string]string{EnvAzdCollect: "yes"}, false, false},
+		{"do not track", map[string]string{EnvDoNotTrack: "1"}, true, false},
+		{"env opt-in", map[string]string{EnvCollect: "yes"}, false, true},
+		{"env opt-out", map[string]string{EnvCollect: "false"}, true, false},
+		{"azd off beats env opt-in", map[string]string{EnvAzdCollect: "false", EnvCollect: "yes"}, true, false},
+		{"do not track beats env opt-in", map[string]string{EnvDoNotTrack: "true", EnvCollect: "on"}, false, false},
+		{"unrecognized values are ignored", map[string]string{EnvCollect: "maybe", EnvDoNotTrack: "maybe"}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTelemetryEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			status := Resolve(tt.optedIn)
+			if status.Enabled != tt.want {
+				t.Errorf("Resolve(%v).Enabled = %v, want %v (%s)", tt.optedIn, status.Enabled, tt.want, status.Reason)
+			}
+			if status.OptedIn != tt.optedIn || status.Reason == "" {
+				t.Errorf("Resolve(%v) = %+v, want the opt-in and a reason", tt.optedIn, status)
+			}
+		})
+	}
+}
+
+func TestFinish(t *testing.T) {
+	clearTelemetryEnv(t)
+	dir := t.TempDir()
+	originalQueueDir := QueueDir
+	QueueDir = func() (string, error) { return dir, nil }
+	defer func() { QueueDir = originalQueueDir }()
+
+	originalConfigPath := config.GetConfigPath
+	config.GetConfigPath = func() (string, error) { return filepath.Join(dir, "config.json"), nil }
+	defer func() { config.GetConfigPath = originalConfigPath }()
+	config.ResetGlobal()
+	defer config.ResetGlobal()
+
+	queue := &Queue{Dir: dir}
+
+	// Without an opt-in nothing is recorded
+	StartCommand("app run")
+	Finish(nil)
+	if events, _ := queue.Events(); len(events) != 0 {
+		t.Fatalf("queued %d events without an opt-in", len(events))
+	}
+
+	t.Setenv(EnvCollect, "yes")
+	StartCommand("app run")
+	AddFrameworks("Next.js", "", "Flask", "Next.js")
+	Finish(nil)
+
+	events, err := queue.Events()
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("queued %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event.Command != "app run" || !event.Success || event.OS == "" || event.Timestamp.IsZero() {
+		t.Errorf("event = %+v, want a successful app run", event)
+	}
+	if len(event.Frameworks) != 2 || event.Frameworks[0] != "Flask" || event.Frameworks[1] != "Next.js" {
+		t.Errorf("Frameworks = %v, want [Flask Next.js]", event.Frameworks)
+	}
+
+	// Finish without a started command records nothing
+	Finish(nil)
+	if events, _ := queue.Events(); len(events) != 1 {
+		t.Errorf("queued %d events after a second Finish, want 1", len(events))
+	}
+}
+
+func TestFinish_WithoutEndpoint(t *testing.T) {
+	clearTelemetryEnv(t)
+	t.Setenv(EnvCollect, "yes")
+	dir := t.TempDir()
+	originalQueueDir := QueueDir
+	QueueDir = func() (string, error) { return dir, nil }
+	defer func() { QueueDir = originalQueueDir }()
+
+	if Uploads() {
+		t.Fatal("Uploads() = true without an endpoint")
+	}
+
+	// A batch is due, but there is nowhere to upload it: the queue is appended to, not rewritten
+	queue := &Queue{Dir: dir}
+	old := time.Now().Add(-2 * maxBatchAge)
+	for range BatchSize {
+		if err := queue.Add(Event{Command: "app deps", Timestamp: old}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before, err := os.Stat(queue.path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	StartCommand("app run")
+	Finish(nil)
+	after, err := os.Stat(queue.path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(before, after) {
+		t.Error("queue was rewritten without an endpoint")
+	}
+	if events, _ := queue.Events(); len(events) != BatchSize+1 {
+		t.Errorf("queued %d events, want %d", len(events), BatchSize+1)
+	}
+
+	// A full queue isn't added to
+	for range maxQueuedEvents - BatchSize - 1 {
+		if err := queue.Add(Event{Command: "app deps", Timestamp: old}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	StartCommand("app run")
+	Finish(nil)
+	if events, _ := queue.Events(); len(events) != maxQueuedEvents {
+		t.Errorf("queued %d events, want at most %d", len(events), maxQueuedEvents)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/jongio/azd-app/cli/src/internal/detector"
+	"github.com/jongio/azd-app/cli/src/internal/executor"
 	"github.com/jongio/azd-app/cli/src/internal/logging"
 	"github.com/jongio/azd-core/security"
 	"gopkg.in/yaml.v3"
@@ -844,44 +845,7 @@ func runCommand(dir, cmd string) error {
 }
 
 // parseCommandString parses a command string into command and arguments.
-// Handles quoted strings and basic shell-style arguments.
+// Quoted arguments (e.g. paths with spaces) are kept together; see executor.SplitCommand.
 func parseCommandString(cmd string) []string {
-	var parts []string
-	var current strings.Builder
-	inQuote := false
-	quoteChar := rune(0)
-
-	for _, r := range cmd {
-		switch r {
-		case '"', '\'':
-			if inQuote && r == quoteChar {
-				// End of quoted section
-				inQuote = false
-				quoteChar = 0
-			} else if !inQuote {
-				// Start of quoted section
-				inQuote = true
-				quoteChar = r
-			} else {
-				// Different quote inside - treat as literal
-				current.WriteRune(r)
-			}
-		case ' ', '\t':
-			if inQuote {
-				current.WriteRune(r)
-			} else if current.Len() > 0 {
-				parts = append(parts, current.String())
-				current.Reset()
-			}
-		default:
-			current.WriteRune(r)
-		}
-	}
-
-	// Add the last part if any
-	if current.Len() > 0 {
-		parts = append(parts, current.String())
-	}
-
-	return parts
+	return executor.SplitCommand(cmd)
 }
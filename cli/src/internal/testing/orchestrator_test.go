@@ -3,6 +3,8 @@ package testing
 import (
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -1564,3 +1566,29 @@ type testError struct {
 func (e *testError) Error() string {
 	return e.msg
 }
+
+func TestRunCommand_PathsWithSpacesAndNonASCII(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shell test")
+	}
+
+	dir := filepath.Join(t.TempDir(), "my tests", "prüfung ü")
+	if err := os.MkdirAll(filepath.Join(dir, "setup scripts"), 0750); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "setup scripts", "séed.sh"), []byte("echo \"$1\" > \"$2\"\n"), 0600); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	if err := runCommand(dir, `sh "./setup scripts/séed.sh" "dätä set" 'seed out.txt'`); err != nil {
+		t.Fatalf("runCommand() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "seed out.txt"))
+	if err != nil {
+		t.Fatalf("command did not run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "dätä set" {
+		t.Errorf("output = %q, want %q", got, "dätä set")
+	}
+}
@@ -0,0 +1,204 @@
+package toolinstaller
+
+// Platforms recipes are registered for (runtime.GOOS values).
+const (
+	osWindows = "windows"
+	osDarwin  = "darwin"
+	osLinux   = "linux"
+)
+
+// toolRecipes lists the ways to install a tool on each platform, in order of preference.
+type toolRecipes map[string][]Recipe
+
+// registry maps canonical tool names (as used by azd app reqs) to their install recipes.
+var registry = map[string]toolRecipes{
+	"node": {
+		osWindows: {winget("OpenJS.NodeJS.LTS"), choco("nodejs-lts")},
+		osDarwin:  {brew("node")},
+		osLinux:   {apt("nodejs", "npm")},
+	},
+	"pnpm": {
+		osWindows: {winget("pnpm.pnpm"), npm("pnpm")},
+		osDarwin:  {brew("pnpm"), npm("pnpm")},
+		osLinux:   {npm("pnpm"), shellScript("curl -fsSL https://get.pnpm.io/install.sh | sh -", "curl")},
+	},
+	"yarn": {
+		osWindows: {winget("Yarn.Yarn"), npm("yarn")},
+		osDarwin:  {brew("yarn"), npm("yarn")},
+		osLinux:   {npm("yarn")},
+	},
+	"bun": {
+		osWindows: {winget("Oven-sh.Bun"), powershellScript("irm https://bun.sh/install.ps1 | iex")},
+		osDarwin:  {brew("oven-sh/bun/bun"), shellScript("curl -fsSL https://bun.sh/install | bash", "curl")},
+		osLinux:   {shellScript("curl -fsSL https://bun.sh/install | bash", "curl")},
+	},
+	"deno": {
+		osWindows: {winget("DenoLand.Deno"), powershellScript("irm https://deno.land/install.ps1 | iex")},
+		osDarwin:  {brew("deno"), shellScript("curl -fsSL https://deno.land/install.sh | sh", "curl")},
+		osLinux:   {shellScript("curl -fsSL https://deno.land/install.sh | sh", "curl")},
+	},
+	"python": {
+		osWindows: {winget("Python.Python.3.12"), choco("python")},
+		osDarwin:  {brew("python")},
+		osLinux:   {apt("python3", "python3-pip", "python-is-python3")},
+	},
+	"poetry": {
+		osWindows: {powershellScript("(Invoke-WebRequest -Uri https://install.python-poetry.org -UseBasicParsing).Content | py -")},
+		osDarwin:  {brew("poetry"), shellScript("curl -sSL https://install.python-poetry.org | python3 -", "curl")},
+		osLinux:   {shellScript("curl -sSL https://install.python-poetry.org | python3 -", "curl")},
+	},
+	"uv": {
+		osWindows: {winget("astral-sh.uv"), powershellScript("irm https://astral.sh/uv/install.ps1 | iex")},
+		osDarwin:  {brew("uv"), shellScript("curl -LsSf https://astral.sh/uv/install.sh | sh", "curl")},
+		osLinux:   {shellScript("curl -LsSf https://astral.sh/uv/install.sh | sh", "curl")},
+	},
+	"pipenv": {
+		osWindows: {pip("pipenv")},
+		osDarwin:  {brew("pipenv"), pip("pipenv")},
+		osLinux:   {pip("pipenv")},
+	},
+	"dotnet": {
+		osWindows: {winget("Microsoft.DotNet.SDK.9"), choco("dotnet-sdk")},
+		osDarwin:  {brewCask("dotnet-sdk")},
+		osLinux:   {shellScript("curl -sSL https://dot.net/v1/dotnet-install.sh | bash /dev/stdin --channel LTS", "curl")},
+	},
+	"aspire": {
+		osWindows: {dotnetTool("Aspire.Cli")},
+		osDarwin:  {dotnetTool("Aspire.Cli")},
+		osLinux:   {dotnetTool("Aspire.Cli")},
+	},
+	"docker": {
+		osWindows: {winget("Docker.DockerDesktop"), choco("docker-desktop")},
+		osDarwin:  {brewCask("docker")},
+		osLinux:   {apt("docker.io")},
+	},
+	"git": {
+		osWindows: {winget("Git.Git"), choco("git")},
+		osDarwin:  {brew("git")},
+		osLinux:   {apt("git")},
+	},
+	"go": {
+		osWindows: {winget("GoLang.Go"), choco("golang")},
+		osDarwin:  {brew("go")},
+		osLinux:   {apt("golang-go")},
+	},
+	"rust": {
+		osWindows: {winget("Rustlang.Rustup")},
+		osDarwin:  {brew("rustup"), shellScript("curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh -s -- -y", "curl")},
+		osLinux:   {shellScript("curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh -s -- -y", "curl")},
+	},
+	"azd": {
+		osWindows: {winget("Microsoft.Azd"), choco("azd")},
+		osDarwin:  {brew("azure/azd/azd")},
+		osLinux:   {shellScript("curl -fsSL https://aka.ms/install-azd.sh | bash", "curl")},
+	},
+	"az": {
+		osWindows: {winget("Microsoft.AzureCLI"), choco("azure-cli")},
+		osDarwin:  {brew("azure-cli")},
+		osLinux:   {shellScript("curl -sL https://aka.ms/InstallAzureCLIDeb | sudo bash", "curl")},
+	},
+	"air": {
+		osWindows: {goInstall("github.com/air-verse/air@latest")},
+		osDarwin:  {goInstall("github.com/air-verse/air@latest")},
+		osLinux:   {goInstall("github.com/air-verse/air@latest")},
+	},
+	"func": {
+		osWindows: {winget("Microsoft.Azure.FunctionsCoreTools"), choco("azure-functions-core-tools"), npm("azure-functions-core-tools@4")},
+		osDarwin:  {brew("azure/functions/azure-functions-core-tools@4"), npm("azure-functions-core-tools@4")},
+		osLinux:   {npm("azure-functions-core-tools@4")},
+	},
+	"java": {
+		osWindows: {winget("Microsoft.OpenJDK.21"), choco("openjdk")},
+		osDarwin:  {brew("openjdk")},
+		osLinux:   {apt("default-jdk")},
+	},
+	"mvn": {
+		osWindows: {choco("maven")},
+		osDarwin:  {brew("maven")},
+		osLinux:   {apt("maven")},
+	},
+	"gradle": {
+		osWindows: {choco("gradle")},
+		osDarwin:  {brew("gradle")},
+		osLinux:   {apt("gradle")},
+	},
+	"gh": {
+		osWindows: {winget("GitHub.cli"), choco("gh")},
+		osDarwin:  {brew("gh")},
+		osLinux:   {apt("gh")},
+	},
+}
+
+// aliases maps tools installed together with another tool, and alternative names, to a registry entry.
+var aliases = map[string]string{
+	"npm":                        "node",
+	"corepack":                   "node",
+	"nodejs":                     "node",
+	"pip":                        "python",
+	"cargo":                      "rust",
+	"rustc":                      "rust",
+	"azure-cli":                  "az",
+	"azure-functions-core-tools": "func",
+}
+
+// winget installs a package by its winget ID.
+func winget(id string) Recipe {
+	return Recipe{Method: "winget", Requires: "winget", Commands: [][]string{
+		{"winget", "install", "--id", id, "--exact", "--source", "winget", "--accept-package-agreements", "--accept-source-agreements"},
+	}}
+}
+
+// choco installs a Chocolatey package.
+func choco(pkg string) Recipe {
+	return Recipe{Method: "choco", Requires: "choco", Commands: [][]string{{"choco", "install", pkg, "-y"}}}
+}
+
+// brew installs a Homebrew formula.
+func brew(formula string) Recipe {
+	return Recipe{Method: "brew", Requires: "brew", Commands: [][]string{{"brew", "install", formula}}}
+}
+
+// brewCask installs a Homebrew cask.
+func brewCask(cask string) Recipe {
+	return Recipe{Method: "brew", Requires: "brew", Commands: [][]string{{"brew", "install", "--cask", cask}}}
+}
+
+// apt installs Debian/Ubuntu packages. It runs as root through sudo.
+func apt(pkgs ...string) Recipe {
+	return Recipe{Method: "apt", Requires: "apt-get", Sudo: true, Commands: [][]string{
+		{"apt-get", "update"},
+		append([]string{"apt-get", "install", "-y"}, pkgs...),
+	}}
+}
+
+// npm installs a global npm package.
+func npm(pkg string) Recipe {
+	return Recipe{Method: "npm", Requires: "npm", Commands: [][]string{{"npm", "install", "--global", pkg}}}
+}
+
+// pip installs a Python package for the current user.
+func pip(pkg string) Recipe {
+	return Recipe{Method: "pip", Requires: "pip", Commands: [][]string{{"pip", "install", "--user", pkg}}}
+}
+
+// goInstall installs a Go program.
+func goInstall(pkg string) Recipe {
+	return Recipe{Method: "go", Requires: "go", Commands: [][]string{{"go", "install", pkg}}}
+}
+
+// dotnetTool installs a global .NET tool.
+func dotnetTool(pkg string) Recipe {
+	return Recipe{Method: "dotnet", Requires: "dotnet", Commands: [][]string{{"dotnet", "tool", "install", "--global", pkg}}}
+}
+
+// shellScript runs an official install script with sh. requires is the downloader it uses.
+func shellScript(script, requires string) Recipe {
+	return Recipe{Method: "script", Requires: requires, Commands: [][]string{{"sh", "-c", script}}}
+}
+
+// powershellScript runs an official install script with PowerShell.
+func powershellScript(script string) Recipe {
+	return Recipe{Method: "script", Requires: "powershell", Commands: [][]string{
+		{"powershell", "-NoProfile", "-ExecutionPolicy", "Bypass", "-Command", script},
+	}}
+}
@@ -0,0 +1,148 @@
+// Package toolinstaller installs missing developer tools (node, python, dotnet, docker, ...)
+// with the platform's package managers: winget or choco on Windows, brew on macOS, and apt
+// or official install scripts on Linux. Plans can be printed before anything is run.
+package toolinstaller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var (
+	// ErrUnsupportedTool is returned for tools without install recipes.
+	ErrUnsupportedTool = errors.New("no installer available for tool")
+	// ErrNoPackageManager is returned when none of a tool's install methods is available on this machine.
+	ErrNoPackageManager = errors.New("no supported package manager found")
+)
+
+// Recipe is one way to install a tool on a platform.
+type Recipe struct {
+	// Method names the package manager or install method, e.g. "winget", "brew" or "script".
+	Method string
+	// Requires is the executable that must be on PATH for the recipe to be used.
+	Requires string
+	// Sudo runs the commands through sudo unless already running as root.
+	Sudo bool
+	// Commands are run in order; each is a program followed by its arguments.
+	Commands [][]string
+}
+
+// Plan is what would be executed to install a tool.
+type Plan struct {
+	Tool     string     `json:"tool"`
+	Method   string     `json:"method"`
+	Commands [][]string `json:"commands"`
+}
+
+// CommandLines returns the plan's commands formatted for display.
+func (p *Plan) CommandLines() []string {
+	lines := make([]string, 0, len(p.Commands))
+	for _, command := range p.Commands {
+		lines = append(lines, formatCommand(command))
+	}
+	return lines
+}
+
+// Installer plans and runs tool installs for the current platform.
+type Installer struct {
+	goos     string
+	isRoot   bool
+	lookPath func(file string) (string, error)
+	run      func(ctx context.Context, command []string, stdout, stderr io.Writer) error
+}
+
+// New creates an Installer for the current platform.
+func New() *Installer {
+	return &Installer{
+		goos:     runtime.GOOS,
+		isRoot:   os.Geteuid() == 0,
+		lookPath: exec.LookPath,
+		run:      runCommand,
+	}
+}
+
+// Canonical returns the registry name for a tool, resolving aliases such as npm -> node.
+func Canonical(tool string) string {
+	name := strings.ToLower(strings.TrimSpace(tool))
+	if alias, ok := aliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// Supported reports whether the tool has install recipes on the current platform.
+func Supported(tool string) bool {
+	return len(registry[Canonical(tool)][runtime.GOOS]) > 0
+}
+
+// Plan picks the first recipe for the tool whose package manager is available.
+func (i *Installer) Plan(tool string) (*Plan, error) {
+	name := Canonical(tool)
+	recipes := registry[name][i.goos]
+	if len(recipes) == 0 {
+		return nil, fmt.Errorf("%w: %s on %s", ErrUnsupportedTool, tool, i.goos)
+	}
+
+	methods := make([]string, 0, len(recipes))
+	for _, recipe := range recipes {
+		methods = append(methods, recipe.Method)
+		if _, err := i.lookPath(recipe.Requires); err != nil {
+			continue
+		}
+		sudo := recipe.Sudo && !i.isRoot
+		if sudo {
+			if _, err := i.lookPath("sudo"); err != nil {
+				continue
+			}
+		}
+
+		plan := &Plan{Tool: name, Method: recipe.Method, Commands: make([][]string, 0, len(recipe.Commands))}
+		for _, command := range recipe.Commands {
+			if sudo {
+				command = append([]string{"sudo"}, command...)
+			}
+			plan.Commands = append(plan.Commands, append([]string(nil), command...))
+		}
+		return plan, nil
+	}
+	return nil, fmt.Errorf("%w to install %s (tried %s)", ErrNoPackageManager, tool, strings.Join(methods, ", "))
+}
+
+// Install runs the plan's commands in order, stopping at the first failure.
+// Command output is written to stdout and stderr.
+func (i *Installer) Install(ctx context.Context, plan *Plan, stdout, stderr io.Writer) error {
+	for _, command := range plan.Commands {
+		if err := i.run(ctx, command, stdout, stderr); err != nil {
+			return fmt.Errorf("failed to install %s: %s: %w", plan.Tool, formatCommand(command), err)
+		}
+	}
+	return nil
+}
+
+// runCommand runs a command with the user's terminal as stdin, so package managers can prompt (e.g. sudo).
+func runCommand(ctx context.Context, command []string, stdout, stderr io.Writer) error {
+	// #nosec G204 -- commands come from the built-in recipe registry
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// formatCommand joins a command for display, quoting arguments that contain spaces.
+func formatCommand(command []string) string {
+	parts := make([]string, 0, len(command))
+	for _, arg := range command {
+		if strings.ContainsAny(arg, " \t|&;") {
+			arg = fmt.Sprintf("%q", arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
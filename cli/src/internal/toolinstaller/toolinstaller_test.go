@@ -0,0 +1,196 @@
+package toolinstaller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// fakeLookPath finds only the given executables.
+func fakeLookPath(available ...string) func(string) (string, error) {
+	return func(file string) (string, error) {
+		for _, name := range available {
+			if name == file {
+				return "/usr/bin/" + file, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestInstaller_Plan(t *testing.T) {
+	tests := []struct {
+		name       string
+		goos       string
+		isRoot     bool
+		available  []string
+		tool       string
+		wantMethod string
+		wantFirst  []string
+		wantErr    error
+	}{
+		{
+			name:       "winget preferred on windows",
+			goos:       osWindows,
+			available:  []string{"winget", "choco"},
+			tool:       "node",
+			wantMethod: "winget",
+			wantFirst:  []string{"winget", "install", "--id", "OpenJS.NodeJS.LTS", "--exact", "--source", "winget", "--accept-package-agreements", "--accept-source-agreements"},
+		},
+		{
+			name:       "choco fallback on windows",
+			goos:       osWindows,
+			available:  []string{"choco"},
+			tool:       "git",
+			wantMethod: "choco",
+			wantFirst:  []string{"choco", "install", "git", "-y"},
+		},
+		{
+			name:       "brew on macos",
+			goos:       osDarwin,
+			available:  []string{"brew"},
+			tool:       "go",
+			wantMethod: "brew",
+			wantFirst:  []string{"brew", "install", "go"},
+		},
+		{
+			name:       "apt with sudo",
+			goos:       osLinux,
+			available:  []string{"apt-get", "sudo"},
+			tool:       "git",
+			wantMethod: "apt",
+			wantFirst:  []string{"sudo", "apt-get", "update"},
+		},
+		{
+			name:       "apt as root",
+			goos:       osLinux,
+			isRoot:     true,
+			available:  []string{"apt-get"},
+			tool:       "git",
+			wantMethod: "apt",
+			wantFirst:  []string{"apt-get", "update"},
+		},
+		{
+			name:       "script on linux",
+			goos:       osLinux,
+			available:  []string{"curl"},
+			tool:       "azd",
+			wantMethod: "script",
+			wantFirst:  []string{"sh", "-c", "curl -fsSL https://aka.ms/install-azd.sh | bash"},
+		},
+		{
+			name:       "bun script on linux",
+			goos:       osLinux,
+			available:  []string{"curl"},
+			tool:       "bun",
+			wantMethod: "script",
+			wantFirst:  []string{"sh", "-c", "curl -fsSL https://bun.sh/install | bash"},
+		},
+		{
+			name:       "cargo alias installs rustup",
+			goos:       osWindows,
+			available:  []string{"winget"},
+			tool:       "cargo",
+			wantMethod: "winget",
+			wantFirst:  []string{"winget", "install", "--id", "Rustlang.Rustup", "--exact", "--source", "winget", "--accept-package-agreements", "--accept-source-agreements"},
+		},
+		{
+			name:       "alias",
+			goos:       osDarwin,
+			available:  []string{"brew"},
+			tool:       "azure-cli",
+			wantMethod: "brew",
+			wantFirst:  []string{"brew", "install", "azure-cli"},
+		},
+		{
+			name:      "apt without sudo",
+			goos:      osLinux,
+			available: []string{"apt-get"},
+			tool:      "git",
+			wantErr:   ErrNoPackageManager,
+		},
+		{
+			name:    "no package manager",
+			goos:    osDarwin,
+			tool:    "node",
+			wantErr: ErrNoPackageManager,
+		},
+		{
+			name:      "unknown tool",
+			goos:      osLinux,
+			available: []string{"apt-get"},
+			tool:      "my-tool",
+			wantErr:   ErrUnsupportedTool,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installer := &Installer{goos: tt.goos, isRoot: tt.isRoot, lookPath: fakeLookPath(tt.available...)}
+			plan, err := installer.Plan(tt.tool)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Plan() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if plan.Method != tt.wantMethod {
+				t.Errorf("Plan().Method = %q, want %q", plan.Method, tt.wantMethod)
+			}
+			if !reflect.DeepEqual(plan.Commands[0], tt.wantFirst) {
+				t.Errorf("Plan().Commands[0] = %v, want %v", plan.Commands[0], tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestInstaller_PlanDoesNotModifyRegistry(t *testing.T) {
+	installer := &Installer{goos: osLinux, lookPath: fakeLookPath("apt-get", "sudo")}
+	if _, err := installer.Plan("git"); err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if got := registry["git"][osLinux][0].Commands[0][0]; got != "apt-get" {
+		t.Errorf("registry command = %q, want apt-get", got)
+	}
+}
+
+func TestInstaller_Install(t *testing.T) {
+	var ran [][]string
+	installer := &Installer{
+		run: func(ctx context.Context, command []string, stdout, stderr io.Writer) error {
+			ran = append(ran, command)
+			if command[0] == "fail" {
+				return errors.New("exit status 1")
+			}
+			return nil
+		},
+	}
+
+	plan := &Plan{Tool: "git", Method: "apt", Commands: [][]string{{"apt-get", "update"}, {"fail"}, {"never"}}}
+	err := installer.Install(context.Background(), plan, io.Discard, io.Discard)
+	if err == nil {
+		t.Fatal("Install() should fail when a command fails")
+	}
+	if len(ran) != 2 {
+		t.Errorf("Install() ran %v, want it to stop at the failed command", ran)
+	}
+}
+
+func TestPlan_CommandLines(t *testing.T) {
+	plan := &Plan{Commands: [][]string{
+		{"brew", "install", "go"},
+		{"sh", "-c", "curl -fsSL https://example.com/install.sh | bash"},
+	}}
+	want := []string{
+		"brew install go",
+		`sh -c "curl -fsSL https://example.com/install.sh | bash"`,
+	}
+	if got := plan.CommandLines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CommandLines() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+// Package tracing records OpenTelemetry spans for command execution, such as requirement
+// checks, dependency installs, service orchestration and health checks.
+//
+// Tracing is off unless an OTLP endpoint is configured with the standard environment
+// variables (OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT). The exporter
+// uses OTLP over HTTP and honors the other OTEL_ variables, e.g. OTEL_EXPORTER_OTLP_HEADERS,
+// OTEL_SERVICE_NAME, OTEL_RESOURCE_ATTRIBUTES and OTEL_TRACES_SAMPLER. When azd passes a trace
+// context in TRACEPARENT, command spans join azd's trace.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans created by azd app.
+const instrumentationName = "github.com/jongio/azd-app/cli"
+
+// shutdownTimeout bounds how long exiting waits for buffered spans to be exported.
+const shutdownTimeout = 5 * time.Second
+
+// state is the tracer provider and the span of the running command.
+var state = struct {
+	mu         sync.Mutex
+	provider   *sdktrace.TracerProvider
+	commandCtx context.Context
+	command    trace.Span
+}{}
+
+// Enabled reports whether the environment configures an OTLP endpoint for traces.
+// OTEL_SDK_DISABLED=true turns tracing off regardless.
+func Enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	if strings.EqualFold(os.Getenv("OTEL_TRACES_EXPORTER"), "none") {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Init installs the OTLP tracer provider when tracing is enabled; otherwise spans are no-ops.
+// version is recorded as the service.version resource attribute.
+func Init(ctx context.Context, version string) error {
+	if !Enabled() {
+		return nil
+	}
+	if protocol := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"); protocol != "" && protocol != "http/protobuf" {
+		slog.Warn("unsupported OTLP protocol, using http/protobuf", "protocol", protocol)
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	// Attributes from OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", "azd-app"),
+			attribute.String("service.version", version),
+		),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithFromEnv(),
+	)
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	state.mu.Lock()
+	state.provider = provider
+	state.mu.Unlock()
+	return nil
+}
+
+// Start creates a span as a child of the span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// StartCommand starts the span of the command being executed. Code that has no context of its
+// own (e.g. commands run as dependencies of others) creates its spans under it through Context.
+func StartCommand(ctx context.Context, name string, args []string) context.Context {
+	ctx, span := Start(ctx, name, attribute.StringSlice("azd.app.args", args))
+
+	state.mu.Lock()
+	state.commandCtx = ctx
+	state.command = span
+	state.mu.Unlock()
+	return ctx
+}
+
+// Context returns the context of the running command's span, or context.Background when no
+// command has started.
+func Context() context.Context {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.commandCtx == nil {
+		return context.Background()
+	}
+	return state.commandCtx
+}
+
+// Finish ends the command span with the command's result and exports the buffered spans.
+func Finish(err error) {
+	state.mu.Lock()
+	span, provider := state.command, state.provider
+	state.command, state.commandCtx, state.provider = nil, nil, nil
+	state.mu.Unlock()
+
+	if span != nil {
+		End(span, err)
+	}
+	if provider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if shutdownErr := provider.Shutdown(ctx); shutdownErr != nil {
+		slog.Debug("failed to export traces", "error", shutdownErr)
+	}
+}
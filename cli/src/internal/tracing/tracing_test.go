@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "no endpoint", want: false},
+		{name: "endpoint", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318"}, want: true},
+		{name: "traces endpoint", env: map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://localhost:4318/v1/traces"}, want: true},
+		{name: "sdk disabled", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318", "OTEL_SDK_DISABLED": "true"}, want: false},
+		{name: "exporter none", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318", "OTEL_TRACES_EXPORTER": "none"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_SDK_DISABLED", "OTEL_TRACES_EXPORTER"} {
+				t.Setenv(key, tt.env[key])
+			}
+			if got := Enabled(); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInit_Disabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	if err := Init(context.Background(), "1.0.0"); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.provider != nil {
+		t.Error("Init() should not install a provider without an endpoint")
+	}
+}
+
+func TestCommandSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	StartCommand(context.Background(), "azd app run", []string{"--service", "api"})
+
+	// Spans without a context of their own are children of the command span
+	_, reqs := Start(Context(), "reqs")
+	End(reqs, nil)
+	_, deps := Start(Context(), "deps")
+	End(deps, errors.New("npm install failed"))
+
+	Finish(errors.New("command failed"))
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("recorded %d spans, want 3", len(spans))
+	}
+	reqsSpan, depsSpan, command := spans[0], spans[1], spans[2]
+
+	if command.Name() != "azd app run" || command.Parent().IsValid() {
+		t.Errorf("command span = %q with parent %v, want a root span named azd app run", command.Name(), command.Parent())
+	}
+	for _, span := range []sdktrace.ReadOnlySpan{reqsSpan, depsSpan} {
+		if span.Parent().SpanID() != command.SpanContext().SpanID() {
+			t.Errorf("span %q is not a child of the command span", span.Name())
+		}
+	}
+
+	if reqsSpan.Status().Code != codes.Unset {
+		t.Errorf("reqs status = %v, want unset", reqsSpan.Status().Code)
+	}
+	if depsSpan.Status().Code != codes.Error || depsSpan.Status().Description != "npm install failed" {
+		t.Errorf("deps status = %+v, want the error", depsSpan.Status())
+	}
+	if command.Status().Code != codes.Error {
+		t.Errorf("command status = %v, want error", command.Status().Code)
+	}
+
+	if Context() != context.Background() {
+		t.Error("Context() after Finish should be the background context")
+	}
+}
@@ -0,0 +1,230 @@
+package watcher
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultIgnorePatterns are always ignored, in .gitignore syntax. They cover VCS metadata,
+// dependency folders and build output that tools rewrite while a service is running,
+// which would otherwise cause restart loops.
+var DefaultIgnorePatterns = []string{
+	".git/",
+	".azure/",
+	"node_modules/",
+	".venv/",
+	"venv/",
+	"__pycache__/",
+	"*.pyc",
+	"bin/",
+	"obj/",
+	"dist/",
+	"build/",
+	"target/",
+	".next/",
+	".nuxt/",
+	".svelte-kit/",
+	"coverage/",
+	"*.log",
+	"*.swp",
+	"*~",
+	".DS_Store",
+}
+
+// ignoreRule is a single compiled .gitignore pattern.
+type ignoreRule struct {
+	base    string // directory the pattern is relative to (slash-separated, "" for root)
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// Matcher decides whether a path is ignored using .gitignore semantics:
+// the last matching rule wins and "!" re-includes a previously ignored path.
+type Matcher struct {
+	rules []ignoreRule
+}
+
+// NewMatcher creates a matcher with the default patterns followed by the given extra patterns.
+func NewMatcher(patterns ...string) *Matcher {
+	m := &Matcher{}
+	m.AddPatterns("", DefaultIgnorePatterns...)
+	m.AddPatterns("", patterns...)
+	return m
+}
+
+// LoadGitignore creates a matcher rooted at root that applies the default patterns and
+// the .gitignore files found in root and in each of the given parent directories
+// (typically the project root containing azure.yaml). Missing files are skipped.
+func LoadGitignore(root string, parents ...string) *Matcher {
+	m := NewMatcher()
+	for _, dir := range parents {
+		rel, err := filepath.Rel(dir, root)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		// Patterns from a parent .gitignore are relative to the parent, so express
+		// them relative to root by stripping the root's offset within the parent.
+		m.addGitignoreFile(filepath.Join(dir, ".gitignore"), filepath.ToSlash(rel))
+	}
+	m.addGitignoreFile(filepath.Join(root, ".gitignore"), "")
+	return m
+}
+
+// addGitignoreFile reads a .gitignore file. offset is the matcher root relative to the
+// directory containing the file ("" when the file is in the root itself).
+func (m *Matcher) addGitignoreFile(path, offset string) {
+	f, err := os.Open(path) // #nosec G304 -- path is a .gitignore inside the project
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	m.addPatternsWithOffset(offset, patterns)
+}
+
+// AddPatterns adds .gitignore-style patterns relative to base (a slash-separated
+// path below the matcher root, or "" for the root).
+func (m *Matcher) AddPatterns(base string, patterns ...string) {
+	for _, p := range patterns {
+		if rule, ok := compileRule(base, p); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+}
+
+// addPatternsWithOffset adds patterns from a parent directory. Unanchored patterns apply
+// at any depth and are added as-is; anchored patterns only apply when they point inside
+// the matcher root, in which case the offset is stripped from them.
+func (m *Matcher) addPatternsWithOffset(offset string, patterns []string) {
+	if offset == "" {
+		m.AddPatterns("", patterns...)
+		return
+	}
+	for _, p := range patterns {
+		line := strings.TrimRight(p, " \t\r")
+		negate := strings.HasPrefix(line, "!")
+		body := strings.TrimPrefix(line, "!")
+		trimmed := strings.TrimSuffix(body, "/")
+		if !strings.Contains(trimmed, "/") {
+			m.AddPatterns("", p)
+			continue
+		}
+		anchored := strings.TrimPrefix(body, "/")
+		if !strings.HasPrefix(anchored, offset+"/") {
+			continue
+		}
+		rebased := "/" + strings.TrimPrefix(anchored, offset+"/")
+		if negate {
+			rebased = "!" + rebased
+		}
+		m.AddPatterns("", rebased)
+	}
+}
+
+// Match reports whether rel (relative to the matcher root) is ignored.
+func (m *Matcher) Match(rel string, isDir bool) bool {
+	rel = strings.Trim(filepath.ToSlash(rel), "/")
+	if rel == "" || rel == "." {
+		return false
+	}
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		p := rel
+		if r.base != "" {
+			if !strings.HasPrefix(rel, r.base+"/") {
+				continue
+			}
+			p = strings.TrimPrefix(rel, r.base+"/")
+		}
+		if r.re.MatchString(p) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// compileRule converts one .gitignore line into a rule. Returns false for blank lines and comments.
+func compileRule(base, line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: strings.Trim(base, "/")}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:] // escaped leading "#" or "!"
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	// A slash anywhere but the end anchors the pattern to its base directory;
+	// otherwise it matches a name at any depth.
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	prefix := `^(?:.*/)?`
+	if anchored {
+		prefix = `^`
+	}
+	re, err := regexp.Compile(prefix + globToRegexp(line) + `$`)
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// globToRegexp translates gitignore glob syntax (*, ?, [...], **) to a regular expression.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString(`(?:.*/)?`)
+			i += 2
+		case c == '*' && strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(`.*`)
+			i++
+		case c == '*':
+			b.WriteString(`[^/]*`)
+		case c == '?':
+			b.WriteString(`[^/]`)
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	m := NewMatcher(
+		"# comment",
+		"*.tmp",
+		"/generated",
+		"docs/**/*.md",
+		"!keep.log",
+		"cache/",
+		"file[0-9].txt",
+	)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"main.go", false, false},
+		{"node_modules", true, true},
+		{"src/node_modules", true, true},
+		{"app.log", false, true},
+		{"keep.log", false, false},
+		{"sub/keep.log", false, false},
+		{"a/b/c.tmp", false, true},
+		{"generated", true, true},
+		{"src/generated", true, false},
+		{"docs/guide/intro.md", false, true},
+		{"docs/intro.md", false, true},
+		{"intro.md", false, false},
+		{"cache", true, true},
+		{"cache", false, false},
+		{"file1.txt", false, true},
+		{"fileA.txt", false, false},
+		{".git", true, true},
+		{"bin", true, true},
+		{"bin", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := m.Match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestLoadGitignore(t *testing.T) {
+	project := t.TempDir()
+	svcDir := filepath.Join(project, "src", "api")
+	if err := os.MkdirAll(svcDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	projectIgnore := "*.secret\n/src/api/fixtures/\n/src/web/\n"
+	if err := os.WriteFile(filepath.Join(project, ".gitignore"), []byte(projectIgnore), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(svcDir, ".gitignore"), []byte("tmp/\n!important.secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := LoadGitignore(svcDir, project)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"db.secret", false, true},
+		{"important.secret", false, false},
+		{"fixtures", true, true},
+		{"tmp", true, true},
+		{"web", true, false},
+		{"server.py", false, false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Package watcher detects source changes in service directories so that
+// `azd app run --watch` can restart only the service whose files changed.
+//
+// It polls file modification times instead of relying on OS notifications,
+// which keeps it dependency-free and reliable on network drives, WSL mounts
+// and containers where inotify/FSEvents are unavailable or unreliable.
+package watcher
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultInterval is how often the tree is scanned for changes.
+	DefaultInterval = 500 * time.Millisecond
+	// DefaultDebounce is how long the tree must be quiet before changes are reported,
+	// so saving several files (or a formatter rewriting them) triggers a single restart.
+	DefaultDebounce = 300 * time.Millisecond
+)
+
+// Options configures a Watcher. Zero values use the defaults.
+type Options struct {
+	Interval time.Duration
+	Debounce time.Duration
+	// Ignore decides which paths are skipped. Defaults to LoadGitignore(root).
+	Ignore *Matcher
+}
+
+// fileState is the part of a file's metadata used to detect modifications.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher polls a directory tree and reports changed files.
+type Watcher struct {
+	root     string
+	interval time.Duration
+	debounce time.Duration
+	ignore   *Matcher
+}
+
+// New creates a watcher for root.
+func New(root string, opts Options) *Watcher {
+	w := &Watcher{
+		root:     root,
+		interval: opts.Interval,
+		debounce: opts.Debounce,
+		ignore:   opts.Ignore,
+	}
+	if w.interval <= 0 {
+		w.interval = DefaultInterval
+	}
+	if w.debounce <= 0 {
+		w.debounce = DefaultDebounce
+	}
+	if w.ignore == nil {
+		w.ignore = LoadGitignore(root)
+	}
+	return w
+}
+
+// Root returns the watched directory.
+func (w *Watcher) Root() string {
+	return w.root
+}
+
+// Run watches until ctx is canceled. onChange is called with the sorted paths
+// (relative to the root) that were created, modified or removed, once the tree has
+// been quiet for the debounce period. onChange runs on the watcher goroutine; changes
+// made while it runs (e.g. build output written during a restart) are not reported.
+func (w *Watcher) Run(ctx context.Context, onChange func(changed []string)) {
+	snapshot := w.scan()
+	pending := make(map[string]bool)
+	var lastChange time.Time
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := w.scan()
+		if changed := diff(snapshot, current); len(changed) > 0 {
+			for _, p := range changed {
+				pending[p] = true
+			}
+			lastChange = time.Now()
+		}
+		snapshot = current
+
+		if len(pending) == 0 || time.Since(lastChange) < w.debounce {
+			continue
+		}
+
+		paths := make([]string, 0, len(pending))
+		for p := range pending {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		pending = make(map[string]bool)
+
+		onChange(paths)
+		if ctx.Err() != nil {
+			return
+		}
+		snapshot = w.scan()
+	}
+}
+
+// scan walks the tree and records the state of every non-ignored file.
+func (w *Watcher) scan() map[string]fileState {
+	files := make(map[string]fileState)
+	err := filepath.WalkDir(w.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files can disappear mid-walk; skip anything we can't read.
+			if d != nil && d.IsDir() && path != w.root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, relErr := filepath.Rel(w.root, path)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+		if w.ignore.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || d.Type()&os.ModeSymlink != 0 {
+			return nil
+		}
+		info, infoErr := d.Info()
+		if infoErr != nil {
+			return nil
+		}
+		files[filepath.ToSlash(rel)] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	if err != nil {
+		slog.Debug("watcher scan failed", "root", w.root, "error", err)
+	}
+	return files
+}
+
+// diff returns the paths that were added, removed or modified between two scans.
+func diff(before, after map[string]fileState) []string {
+	var changed []string
+	for p, a := range after {
+		if b, ok := before[p]; !ok || !b.modTime.Equal(a.modTime) || b.size != a.size {
+			changed = append(changed, p)
+		}
+	}
+	for p := range before {
+		if _, ok := after[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,89 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatcher_ReportsChangesAfterDebounce(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.go"), "package main")
+	if err := os.MkdirAll(filepath.Join(dir, "node_modules"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(dir, Options{Interval: 20 * time.Millisecond, Debounce: 60 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var batches [][]string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Run(ctx, func(changed []string) {
+			mu.Lock()
+			batches = append(batches, changed)
+			mu.Unlock()
+		})
+	}()
+
+	// Let the watcher take its initial snapshot.
+	time.Sleep(60 * time.Millisecond)
+
+	writeFile(t, filepath.Join(dir, "main.go"), "package main // edited")
+	writeFile(t, filepath.Join(dir, "util.go"), "package main")
+	writeFile(t, filepath.Join(dir, "node_modules", "dep.js"), "ignored")
+	writeFile(t, filepath.Join(dir, "debug.log"), "ignored")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(batches)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	// Wait a little longer to make sure the edits were not split across batches.
+	time.Sleep(150 * time.Millisecond)
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 1 {
+		t.Fatalf("got %d change batches, want 1: %v", len(batches), batches)
+	}
+	if want := []string{"main.go", "util.go"}; !reflect.DeepEqual(batches[0], want) {
+		t.Errorf("changed = %v, want %v", batches[0], want)
+	}
+}
+
+func TestDiff_DetectsRemovedFiles(t *testing.T) {
+	now := time.Now()
+	before := map[string]fileState{
+		"a.go": {modTime: now, size: 1},
+		"b.go": {modTime: now, size: 1},
+	}
+	after := map[string]fileState{
+		"a.go": {modTime: now, size: 1},
+	}
+	if got := diff(before, after); !reflect.DeepEqual(got, []string{"b.go"}) {
+		t.Errorf("diff() = %v, want [b.go]", got)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
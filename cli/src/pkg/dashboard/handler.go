@@ -0,0 +1,51 @@
+// Package dashboard serves the azd app dashboard from another program, such as an IDE plugin
+// that shows it in a webview. The handler is mounted into the program's own mux and served on
+// the listener, port and TLS configuration the program chooses.
+package dashboard
+
+import (
+	"net/http"
+
+	"github.com/jongio/azd-app/cli/src/internal/dashboard"
+)
+
+// Options configures a handler created with NewHandler.
+type Options = dashboard.Options
+
+// BroadcastEvent describes a message pushed to connected dashboard clients.
+type BroadcastEvent = dashboard.BroadcastEvent
+
+// BroadcastHook is called synchronously for every broadcast.
+// Hooks must not block; long-running work should be moved to a goroutine.
+type BroadcastHook = dashboard.BroadcastHook
+
+// Handler serves the dashboard UI, API and WebSocket of a project.
+type Handler struct {
+	server *dashboard.Server
+}
+
+// Ensure Handler implements http.Handler
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler creates a dashboard handler for projectDir. Each call returns an independent
+// handler; call Close when it's no longer served.
+func NewHandler(projectDir string, opts Options) *Handler {
+	return &Handler{server: dashboard.NewHandler(projectDir, opts)}
+}
+
+// ServeHTTP serves dashboard API and UI requests.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.server.ServeHTTP(w, r)
+}
+
+// Subscribe registers a hook that is invoked for every broadcast sent to
+// dashboard clients. It returns a function that removes the hook.
+func (h *Handler) Subscribe(hook BroadcastHook) (unsubscribe func()) {
+	return h.server.Subscribe(hook)
+}
+
+// Close closes open WebSocket connections and stops background workers.
+// Safe to call multiple times.
+func (h *Handler) Close() error {
+	return h.server.Close()
+}
@@ -0,0 +1,34 @@
+package dashboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewHandler_MountsIntoExternalMux(t *testing.T) {
+	handler := NewHandler(t.TempDir(), Options{DisableSecurityHeaders: true})
+	defer func() { _ = handler.Close() }()
+
+	unsubscribe := handler.Subscribe(func(BroadcastEvent) {})
+	defer unsubscribe()
+
+	mux := http.NewServeMux()
+	mux.Handle("/dashboard/", http.StripPrefix("/dashboard", handler))
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/dashboard/api/ping")
+	if err != nil {
+		t.Fatalf("GET /dashboard/api/ping failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := resp.Header.Get("X-Frame-Options"); got != "" {
+		t.Errorf("X-Frame-Options = %q, want empty", got)
+	}
+}